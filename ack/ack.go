@@ -0,0 +1,530 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ack implements a garbage-collected and snapshottable store of
+// alert group acknowledgements. An acknowledged group keeps being shown and
+// notified about changes, but is not notified again only because its repeat
+// interval passed.
+package ack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	uuid "github.com/gofrs/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/alertmanager/cluster"
+)
+
+// ErrNotFound is returned if an acknowledgement does not exist.
+var ErrNotFound = errors.New("acknowledgement not found")
+
+// Ack is the acknowledgement of an alert group by a user.
+type Ack struct {
+	// A unique identifier of the acknowledgement.
+	ID string `json:"id"`
+	// The key of the acknowledged aggregation group.
+	GroupKey string `json:"groupKey"`
+	// Who acknowledged the group and why.
+	AckedBy string `json:"ackedBy"`
+	Comment string `json:"comment,omitempty"`
+	// The time range for which the acknowledgement is active.
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	// The last time the acknowledgement was modified.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Active returns true if the acknowledgement is active at the given time.
+func (a *Ack) Active(now time.Time) bool {
+	return !now.Before(a.StartsAt) && now.Before(a.EndsAt)
+}
+
+// Acks holds the acknowledgements of alert groups. There is at most one
+// acknowledgement per group.
+type Acks struct {
+	logger    log.Logger
+	metrics   *metrics
+	now       func() time.Time
+	retention time.Duration
+
+	mtx       sync.RWMutex
+	st        state
+	broadcast func([]byte)
+}
+
+// MaintenanceFunc represents the function to run as part of the periodic
+// maintenance for acknowledgements. It returns the size of the snapshot taken
+// or an error if it failed.
+type MaintenanceFunc func() (int64, error)
+
+type metrics struct {
+	active                  prometheus.GaugeFunc
+	gcDuration              prometheus.Summary
+	snapshotDuration        prometheus.Summary
+	snapshotSize            prometheus.Gauge
+	propagatedMessagesTotal prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer, a *Acks) *metrics {
+	m := &metrics{}
+
+	m.active = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "alertmanager_acks",
+		Help: "Number of active alert group acknowledgements.",
+	}, func() float64 {
+		return float64(len(a.List()))
+	})
+	m.gcDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "alertmanager_acks_gc_duration_seconds",
+		Help:       "Duration of the last acknowledgement garbage collection cycle.",
+		Objectives: map[float64]float64{},
+	})
+	m.snapshotDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "alertmanager_acks_snapshot_duration_seconds",
+		Help:       "Duration of the last acknowledgement snapshot.",
+		Objectives: map[float64]float64{},
+	})
+	m.snapshotSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "alertmanager_acks_snapshot_size_bytes",
+		Help: "Size of the last acknowledgement snapshot in bytes.",
+	})
+	m.propagatedMessagesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "alertmanager_acks_gossip_messages_propagated_total",
+		Help: "Number of received gossip messages that have been further gossiped.",
+	})
+
+	if r != nil {
+		r.MustRegister(
+			m.active,
+			m.gcDuration,
+			m.snapshotDuration,
+			m.snapshotSize,
+			m.propagatedMessagesTotal,
+		)
+	}
+	return m
+}
+
+// Options exposes configuration options for creating a new Acks object.
+type Options struct {
+	// A snapshot file or reader from which the initial state is loaded.
+	// None or only one of them must be set.
+	SnapshotFile   string
+	SnapshotReader io.Reader
+
+	// Retention time for expired acknowledgements. They are garbage
+	// collected once they have been expired for the given duration.
+	Retention time.Duration
+
+	// A logger used by background processing.
+	Logger  log.Logger
+	Metrics prometheus.Registerer
+}
+
+func (o *Options) validate() error {
+	if o.SnapshotFile != "" && o.SnapshotReader != nil {
+		return fmt.Errorf("only one of SnapshotFile and SnapshotReader must be set")
+	}
+	return nil
+}
+
+// New returns a new Acks object with the given configuration.
+func New(o Options) (*Acks, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if o.SnapshotFile != "" {
+		if r, err := os.Open(o.SnapshotFile); err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		} else {
+			defer r.Close()
+			o.SnapshotReader = r
+		}
+	}
+	a := &Acks{
+		logger:    log.NewNopLogger(),
+		now:       utcNow,
+		retention: o.Retention,
+		st:        state{},
+		broadcast: func([]byte) {},
+	}
+	a.metrics = newMetrics(o.Metrics, a)
+
+	if o.Logger != nil {
+		a.logger = o.Logger
+	}
+	if o.SnapshotReader != nil {
+		if err := a.loadSnapshot(o.SnapshotReader); err != nil {
+			return a, err
+		}
+	}
+	return a, nil
+}
+
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+func cloneAck(a *Ack) *Ack {
+	c := *a
+	return &c
+}
+
+// Set acknowledges the alert group of the given acknowledgement until its
+// end time, replacing any previous acknowledgement of the group. The ID and
+// start time are set by Set. It returns the stored acknowledgement.
+func (a *Acks) Set(ack *Ack) (*Ack, error) {
+	if ack.GroupKey == "" {
+		return nil, errors.New("group key must be set")
+	}
+	if ack.AckedBy == "" {
+		return nil, errors.New("acknowledging user must be set")
+	}
+	uid, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("generate uuid: %w", err)
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	now := a.now()
+	if !ack.EndsAt.After(now) {
+		return nil, errors.New("end time must be in the future")
+	}
+	ack = cloneAck(ack)
+	ack.ID = uid.String()
+	ack.StartsAt = now
+	ack.UpdatedAt = now
+
+	if err := a.setAck(ack); err != nil {
+		return nil, err
+	}
+	return cloneAck(ack), nil
+}
+
+// setAck stores the acknowledgement and gossips it. The caller must hold the
+// lock.
+func (a *Acks) setAck(ack *Ack) error {
+	b, err := marshalState(state{ack.GroupKey: ack})
+	if err != nil {
+		return err
+	}
+	a.st.merge(ack)
+	a.broadcast(b)
+	return nil
+}
+
+// Get returns the active acknowledgement of the alert group with the given
+// key.
+func (a *Acks) Get(groupKey string) (*Ack, bool) {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	ack, ok := a.st[groupKey]
+	if !ok || !ack.Active(a.now()) {
+		return nil, false
+	}
+	return cloneAck(ack), true
+}
+
+// Acknowledged returns true if the alert group with the given key is
+// currently acknowledged.
+func (a *Acks) Acknowledged(groupKey string) bool {
+	_, ok := a.Get(groupKey)
+	return ok
+}
+
+// List returns all active acknowledgements, sorted by their start time.
+func (a *Acks) List() []*Ack {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	now := a.now()
+	res := make([]*Ack, 0, len(a.st))
+	for _, ack := range a.st {
+		if ack.Active(now) {
+			res = append(res, cloneAck(ack))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].StartsAt.Equal(res[j].StartsAt) {
+			return res[i].ID < res[j].ID
+		}
+		return res[i].StartsAt.Before(res[j].StartsAt)
+	})
+	return res
+}
+
+// Expire ends the active acknowledgement with the given ID immediately.
+func (a *Acks) Expire(id string) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	now := a.now()
+	for _, ack := range a.st {
+		if ack.ID != id || !ack.Active(now) {
+			continue
+		}
+		ack = cloneAck(ack)
+		ack.EndsAt = now
+		ack.UpdatedAt = now
+		return a.setAck(ack)
+	}
+	return ErrNotFound
+}
+
+// GC removes acknowledgements that have been expired for longer than the
+// retention time. It returns the number of removed acknowledgements.
+func (a *Acks) GC() (int, error) {
+	start := time.Now()
+	defer func() { a.metrics.gcDuration.Observe(time.Since(start).Seconds()) }()
+
+	now := a.now()
+	var n int
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	for gk, ack := range a.st {
+		if !ack.EndsAt.Add(a.retention).After(now) {
+			delete(a.st, gk)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Maintenance garbage collects the acknowledgements at the given interval. If
+// the snapshot file is set, a snapshot is written to it afterwards.
+// Terminates on receiving from stopc.
+// If not nil, the last argument is an override for what to do as part of the maintenance - for advanced usage.
+func (a *Acks) Maintenance(interval time.Duration, snapf string, stopc <-chan struct{}, override MaintenanceFunc) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	doMaintenance := func() (int64, error) {
+		var size int64
+
+		if _, err := a.GC(); err != nil {
+			return size, err
+		}
+		if snapf == "" {
+			return size, nil
+		}
+		f, err := openReplace(snapf)
+		if err != nil {
+			return size, err
+		}
+		if size, err = a.Snapshot(f); err != nil {
+			return size, err
+		}
+		return size, f.Close()
+	}
+
+	if override != nil {
+		doMaintenance = override
+	}
+
+	runMaintenance := func(do MaintenanceFunc) error {
+		start := a.now()
+		level.Debug(a.logger).Log("msg", "Running maintenance")
+		size, err := do()
+		level.Debug(a.logger).Log("msg", "Maintenance done", "duration", a.now().Sub(start), "size", size)
+		a.metrics.snapshotSize.Set(float64(size))
+		return err
+	}
+
+Loop:
+	for {
+		select {
+		case <-stopc:
+			break Loop
+		case <-t.C:
+			if err := runMaintenance(doMaintenance); err != nil {
+				level.Info(a.logger).Log("msg", "Running maintenance failed", "err", err)
+			}
+		}
+	}
+	// No need for final maintenance if we don't want to snapshot.
+	if snapf == "" {
+		return
+	}
+	if err := runMaintenance(doMaintenance); err != nil {
+		level.Info(a.logger).Log("msg", "Creating shutdown snapshot failed", "err", err)
+	}
+}
+
+// loadSnapshot loads a snapshot generated by Snapshot() into the state.
+func (a *Acks) loadSnapshot(r io.Reader) error {
+	st, err := decodeState(r)
+	if err != nil {
+		return err
+	}
+	a.mtx.Lock()
+	a.st = st
+	a.mtx.Unlock()
+
+	return nil
+}
+
+// Snapshot writes the full internal state into the writer and returns the number of bytes
+// written.
+func (a *Acks) Snapshot(w io.Writer) (int64, error) {
+	start := time.Now()
+	defer func() { a.metrics.snapshotDuration.Observe(time.Since(start).Seconds()) }()
+
+	b, err := a.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// MarshalBinary serializes all acknowledgements.
+func (a *Acks) MarshalBinary() ([]byte, error) {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	return marshalState(a.st)
+}
+
+// Merge merges acknowledgement state received from the cluster with the local
+// state.
+func (a *Acks) Merge(b []byte) error {
+	st, err := decodeState(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	now := a.now()
+
+	for _, ack := range st {
+		// Acknowledgements past their retention are only of interest if they
+		// end one we still hold.
+		if _, ok := a.st[ack.GroupKey]; !ok && !ack.EndsAt.Add(a.retention).After(now) {
+			continue
+		}
+		if merged := a.st.merge(ack); merged && !cluster.OversizedMessage(b) {
+			// If this is the first we've seen the message and it's
+			// not oversized, gossip it to other nodes. We don't
+			// propagate oversized messages because they're sent to
+			// all nodes already.
+			a.broadcast(b)
+			a.metrics.propagatedMessagesTotal.Inc()
+			level.Debug(a.logger).Log("msg", "Gossiping new acknowledgement", "ack", ack.ID)
+		}
+	}
+	return nil
+}
+
+// SetBroadcast sets the provided function as the one creating data to be
+// broadcast.
+func (a *Acks) SetBroadcast(f func([]byte)) {
+	a.mtx.Lock()
+	a.broadcast = f
+	a.mtx.Unlock()
+}
+
+type state map[string]*Ack
+
+// merge stores the acknowledgement if it is more recent than the one held
+// for the same group.
+func (s state) merge(ack *Ack) bool {
+	prev, ok := s[ack.GroupKey]
+	if !ok || prev.UpdatedAt.Before(ack.UpdatedAt) {
+		s[ack.GroupKey] = ack
+		return true
+	}
+	return false
+}
+
+func marshalState(s state) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	for _, ack := range s {
+		if err := enc.Encode(ack); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeState(r io.Reader) (state, error) {
+	st := state{}
+
+	dec := json.NewDecoder(r)
+	for {
+		var ack Ack
+		if err := dec.Decode(&ack); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if ack.ID == "" || ack.GroupKey == "" {
+			return nil, errors.New("invalid acknowledgement: missing ID or group key")
+		}
+		st.merge(&ack)
+	}
+	return st, nil
+}
+
+type replaceFile struct {
+	*os.File
+	filename string
+}
+
+func (f *replaceFile) Close() error {
+	if err := f.File.Sync(); err != nil {
+		return err
+	}
+	if err := f.File.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.File.Name(), f.filename)
+}
+
+// openReplace opens a new temporary file that is moved to filename on closing.
+func openReplace(filename string) (*replaceFile, error) {
+	tmpFilename := fmt.Sprintf("%s.%x", filename, uint64(rand.Int63()))
+
+	f, err := os.Create(tmpFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &replaceFile{
+		File:     f,
+		filename: filename,
+	}
+	return rf, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ack
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+const testGroupKey = "{}:{alertname=\"test\"}"
+
+func newTestAcks(t *testing.T, o Options) (*Acks, *time.Time) {
+	t.Helper()
+
+	o.Metrics = prometheus.NewRegistry()
+	a, err := New(o)
+	require.NoError(t, err)
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	return a, &now
+}
+
+func TestOptionsValidate(t *testing.T) {
+	require.NoError(t, (&Options{SnapshotFile: "test.bkp"}).validate())
+	require.EqualError(t,
+		(&Options{SnapshotFile: "test.bkp", SnapshotReader: &bytes.Buffer{}}).validate(),
+		"only one of SnapshotFile and SnapshotReader must be set",
+	)
+}
+
+func TestAcksSet(t *testing.T) {
+	a, now := newTestAcks(t, Options{})
+
+	_, err := a.Set(&Ack{AckedBy: "alice", EndsAt: now.Add(time.Hour)})
+	require.EqualError(t, err, "group key must be set")
+	_, err = a.Set(&Ack{GroupKey: testGroupKey, EndsAt: now.Add(time.Hour)})
+	require.EqualError(t, err, "acknowledging user must be set")
+	_, err = a.Set(&Ack{GroupKey: testGroupKey, AckedBy: "alice", EndsAt: *now})
+	require.EqualError(t, err, "end time must be in the future")
+
+	require.False(t, a.Acknowledged(testGroupKey))
+
+	ack, err := a.Set(&Ack{GroupKey: testGroupKey, AckedBy: "alice", Comment: "on it", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	require.NotEmpty(t, ack.ID)
+	require.Equal(t, *now, ack.StartsAt)
+	require.Equal(t, *now, ack.UpdatedAt)
+	require.True(t, a.Acknowledged(testGroupKey))
+
+	got, ok := a.Get(testGroupKey)
+	require.True(t, ok)
+	require.Equal(t, ack, got)
+
+	// A new acknowledgement replaces the previous one of the group.
+	*now = now.Add(time.Minute)
+	ack2, err := a.Set(&Ack{GroupKey: testGroupKey, AckedBy: "bob", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	require.NotEqual(t, ack.ID, ack2.ID)
+	require.Equal(t, []*Ack{ack2}, a.List())
+
+	// The acknowledgement ends at its end time.
+	*now = now.Add(time.Hour)
+	require.False(t, a.Acknowledged(testGroupKey))
+	require.Empty(t, a.List())
+}
+
+func TestAcksExpire(t *testing.T) {
+	a, now := newTestAcks(t, Options{})
+
+	require.Equal(t, ErrNotFound, a.Expire("unknown"))
+
+	ack, err := a.Set(&Ack{GroupKey: testGroupKey, AckedBy: "alice", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+
+	*now = now.Add(time.Minute)
+	require.NoError(t, a.Expire(ack.ID))
+	require.False(t, a.Acknowledged(testGroupKey))
+	require.Equal(t, ErrNotFound, a.Expire(ack.ID))
+}
+
+func TestAcksGC(t *testing.T) {
+	a, now := newTestAcks(t, Options{Retention: time.Hour})
+
+	_, err := a.Set(&Ack{GroupKey: testGroupKey, AckedBy: "alice", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+
+	*now = now.Add(90 * time.Minute)
+	n, err := a.GC()
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	*now = now.Add(30 * time.Minute)
+	n, err = a.GC()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+func TestAcksSnapshot(t *testing.T) {
+	a, now := newTestAcks(t, Options{})
+
+	ack, err := a.Set(&Ack{GroupKey: testGroupKey, AckedBy: "alice", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = a.Snapshot(&buf)
+	require.NoError(t, err)
+
+	a2, _ := newTestAcks(t, Options{SnapshotReader: &buf})
+	got, ok := a2.Get(testGroupKey)
+	require.True(t, ok)
+	require.Equal(t, ack, got)
+}
+
+func TestAcksMerge(t *testing.T) {
+	a1, now := newTestAcks(t, Options{})
+	a2, _ := newTestAcks(t, Options{})
+	a2.now = a1.now
+
+	var broadcasts [][]byte
+	a1.SetBroadcast(func(b []byte) { broadcasts = append(broadcasts, b) })
+
+	ack, err := a1.Set(&Ack{GroupKey: testGroupKey, AckedBy: "alice", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, broadcasts, 1)
+
+	require.NoError(t, a2.Merge(broadcasts[0]))
+	got, ok := a2.Get(testGroupKey)
+	require.True(t, ok)
+	require.Equal(t, ack, got)
+
+	// Expiring the acknowledgement is gossiped as well.
+	*now = now.Add(time.Minute)
+	require.NoError(t, a1.Expire(ack.ID))
+	require.Len(t, broadcasts, 2)
+	require.NoError(t, a2.Merge(broadcasts[1]))
+	require.False(t, a2.Acknowledged(testGroupKey))
+
+	// Older state is ignored.
+	require.NoError(t, a2.Merge(broadcasts[0]))
+	require.False(t, a2.Acknowledged(testGroupKey))
+}
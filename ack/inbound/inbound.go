@@ -0,0 +1,318 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package inbound implements endpoints receiving webhook callbacks from
+// on-call providers. Acknowledge events are turned into alert group
+// acknowledgements and close events into silences, so that the state in
+// the Alertmanager follows the state in the provider.
+package inbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/route"
+
+	"github.com/prometheus/alertmanager/ack"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+// maxBodySize limits the size of accepted webhook payloads.
+const maxBodySize = 1 << 20
+
+// action is the change requested by a provider for an alert group.
+type action int
+
+const (
+	actionNone action = iota
+	actionAck
+	actionUnack
+	actionClose
+)
+
+// event is a provider callback about an alert group.
+type event struct {
+	// The key the Alertmanager sent the group to the provider with.
+	key    string
+	action action
+	// The provider user triggering the event.
+	user     string
+	provider string
+}
+
+// Handler serves the webhook endpoints of the on-call providers.
+type Handler struct {
+	acks     *ack.Acks
+	silences *silence.Silences
+	groups   func() dispatch.AlertGroups
+	logger   log.Logger
+	now      func() time.Time
+
+	mtx  sync.RWMutex
+	conf *config.AckWebhooksConfig
+}
+
+// New returns a new Handler. The groups function returns the current alert
+// groups, which provider events are matched against.
+func New(acks *ack.Acks, silences *silence.Silences, groups func() dispatch.AlertGroups, l log.Logger) *Handler {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	return &Handler{
+		acks:     acks,
+		silences: silences,
+		groups:   groups,
+		logger:   l,
+		now:      time.Now,
+	}
+}
+
+// Update sets the configuration of the endpoints. They are disabled if the
+// configuration is nil.
+func (h *Handler) Update(conf *config.AckWebhooksConfig) {
+	h.mtx.Lock()
+	h.conf = conf
+	h.mtx.Unlock()
+}
+
+func (h *Handler) config() *config.AckWebhooksConfig {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+	return h.conf
+}
+
+// Register registers the endpoints with the router.
+func (h *Handler) Register(r *route.Router) {
+	r.Post("/hooks/pagerduty", h.servePagerDuty)
+	r.Post("/hooks/opsgenie", h.serveOpsGenie)
+}
+
+// pagerDutyWebhook is the relevant part of a PagerDuty V3 webhook payload.
+type pagerDutyWebhook struct {
+	Event struct {
+		EventType string `json:"event_type"`
+		Agent     *struct {
+			Summary string `json:"summary"`
+		} `json:"agent"`
+		Data struct {
+			IncidentKey string `json:"incident_key"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+func (h *Handler) servePagerDuty(w http.ResponseWriter, req *http.Request) {
+	conf := h.config()
+	if conf == nil {
+		http.NotFound(w, req)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if secret := string(conf.PagerDutySigningSecret); secret != "" {
+		if !validPagerDutySignature(req.Header.Get("X-PagerDuty-Signature"), secret, body) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var msg pagerDutyWebhook
+	if err := json.Unmarshal(body, &msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e := event{
+		key:      msg.Event.Data.IncidentKey,
+		provider: "PagerDuty",
+	}
+	switch msg.Event.EventType {
+	case "incident.acknowledged":
+		e.action = actionAck
+	case "incident.unacknowledged", "incident.reopened":
+		e.action = actionUnack
+	case "incident.resolved":
+		e.action = actionClose
+	}
+	if msg.Event.Agent != nil {
+		e.user = msg.Event.Agent.Summary
+	}
+	h.handle(w, e, conf)
+}
+
+// validPagerDutySignature checks the signature header, which holds one or
+// more comma-separated signatures in the form v1=<hex encoded HMAC-SHA256>.
+func validPagerDutySignature(header, secret string, body []byte) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	// hash.Hash.Write never returns an error.
+	//nolint: errcheck
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	for _, sig := range strings.Split(header, ",") {
+		sig = strings.TrimSpace(sig)
+		if !strings.HasPrefix(sig, "v1=") {
+			continue
+		}
+		b, err := hex.DecodeString(strings.TrimPrefix(sig, "v1="))
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(b, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// opsGenieWebhook is the relevant part of an OpsGenie outgoing webhook payload.
+type opsGenieWebhook struct {
+	Action string `json:"action"`
+	Alert  struct {
+		Alias    string `json:"alias"`
+		Username string `json:"username"`
+	} `json:"alert"`
+}
+
+func (h *Handler) serveOpsGenie(w http.ResponseWriter, req *http.Request) {
+	conf := h.config()
+	if conf == nil {
+		http.NotFound(w, req)
+		return
+	}
+	if token := string(conf.OpsGenieToken); token != "" {
+		if subtle.ConstantTimeCompare([]byte(req.URL.Query().Get("token")), []byte(token)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var msg opsGenieWebhook
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxBodySize)).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e := event{
+		key:      msg.Alert.Alias,
+		user:     msg.Alert.Username,
+		provider: "OpsGenie",
+	}
+	switch msg.Action {
+	case "Acknowledge":
+		e.action = actionAck
+	case "UnAcknowledge":
+		e.action = actionUnack
+	case "Close":
+		e.action = actionClose
+	}
+	h.handle(w, e, conf)
+}
+
+// handle applies the event to the alert group it refers to. Events about
+// unknown groups are ignored without an error, as the provider retrying them
+// would not succeed either.
+func (h *Handler) handle(w http.ResponseWriter, e event, conf *config.AckWebhooksConfig) {
+	logger := log.With(h.logger, "provider", e.provider, "key", e.key)
+
+	if e.action == actionNone || e.key == "" {
+		level.Debug(logger).Log("msg", "Ignoring webhook event")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	ag := h.findGroup(e.key)
+	if ag == nil {
+		level.Debug(logger).Log("msg", "Ignoring webhook event for unknown alert group")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if e.user == "" {
+		e.user = e.provider
+	}
+
+	var err error
+	switch e.action {
+	case actionAck:
+		_, err = h.acks.Set(&ack.Ack{
+			GroupKey: ag.GroupKey,
+			AckedBy:  e.user,
+			Comment:  fmt.Sprintf("Acknowledged in %s", e.provider),
+			EndsAt:   h.now().Add(time.Duration(conf.AckDuration)),
+		})
+	case actionUnack:
+		if a, ok := h.acks.Get(ag.GroupKey); ok {
+			err = h.acks.Expire(a.ID)
+		}
+	case actionClose:
+		err = h.silence(ag, e, conf)
+	}
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to apply webhook event", "group", ag.GroupKey, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	level.Info(logger).Log("msg", "Applied webhook event", "group", ag.GroupKey, "user", e.user)
+	w.WriteHeader(http.StatusOK)
+}
+
+// silence silences the alerts of the group by its group labels.
+func (h *Handler) silence(ag *dispatch.AlertGroup, e event, conf *config.AckWebhooksConfig) error {
+	if len(ag.Labels) == 0 {
+		return fmt.Errorf("cannot silence alert group without group labels")
+	}
+	now := h.now()
+	sil := &silencepb.Silence{
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Duration(conf.SilenceDuration)),
+		CreatedBy: e.user,
+		Comment:   fmt.Sprintf("Closed in %s", e.provider),
+	}
+	for ln, lv := range ag.Labels {
+		sil.Matchers = append(sil.Matchers, &silencepb.Matcher{
+			Type:    silencepb.Matcher_EQUAL,
+			Name:    string(ln),
+			Pattern: string(lv),
+		})
+	}
+	sort.Slice(sil.Matchers, func(i, j int) bool {
+		return sil.Matchers[i].Name < sil.Matchers[j].Name
+	})
+	_, err := h.silences.Set(sil)
+	return err
+}
+
+// findGroup returns the alert group the given provider key was derived from.
+func (h *Handler) findGroup(key string) *dispatch.AlertGroup {
+	for _, ag := range h.groups() {
+		if notify.Key(ag.GroupKey).Hash() == key {
+			return ag
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/route"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/ack"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const testGroupKey = `{}:{alertname="test"}`
+
+func newTestHandler(t *testing.T, conf *config.AckWebhooksConfig) (*Handler, *ack.Acks, *silence.Silences, *route.Router) {
+	t.Helper()
+
+	acks, err := ack.New(ack.Options{})
+	require.NoError(t, err)
+	silences, err := silence.New(silence.Options{})
+	require.NoError(t, err)
+
+	groups := func() dispatch.AlertGroups {
+		return dispatch.AlertGroups{
+			{
+				Labels:   model.LabelSet{"alertname": "test"},
+				Receiver: "pager",
+				GroupKey: testGroupKey,
+			},
+		}
+	}
+	h := New(acks, silences, groups, nil)
+	h.Update(conf)
+
+	r := route.New()
+	h.Register(r)
+	return h, acks, silences, r
+}
+
+func post(r http.Handler, url, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func pagerDutyEvent(eventType string) string {
+	return fmt.Sprintf(`{"event":{"event_type":%q,"agent":{"summary":"Jane Doe"},"data":{"incident_key":%q}}}`,
+		eventType, notify.Key(testGroupKey).Hash())
+}
+
+func TestPagerDutyWebhook(t *testing.T) {
+	conf := config.DefaultAckWebhooksConfig
+	_, acks, silences, r := newTestHandler(t, &conf)
+
+	w := post(r, "/hooks/pagerduty", pagerDutyEvent("incident.acknowledged"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	a, ok := acks.Get(testGroupKey)
+	require.True(t, ok)
+	require.Equal(t, "Jane Doe", a.AckedBy)
+	require.Equal(t, "Acknowledged in PagerDuty", a.Comment)
+
+	w = post(r, "/hooks/pagerduty", pagerDutyEvent("incident.unacknowledged"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.False(t, acks.Acknowledged(testGroupKey))
+
+	w = post(r, "/hooks/pagerduty", pagerDutyEvent("incident.resolved"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	sils, _, err := silences.Query(silence.QState(types.SilenceStateActive))
+	require.NoError(t, err)
+	require.Len(t, sils, 1)
+	require.Equal(t, "Jane Doe", sils[0].CreatedBy)
+	require.Equal(t, "alertname", sils[0].Matchers[0].Name)
+	require.Equal(t, "test", sils[0].Matchers[0].Pattern)
+
+	// Unknown groups and events are ignored.
+	w = post(r, "/hooks/pagerduty", `{"event":{"event_type":"incident.acknowledged","data":{"incident_key":"unknown"}}}`, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = post(r, "/hooks/pagerduty", pagerDutyEvent("incident.priority_updated"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = post(r, "/hooks/pagerduty", "{", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPagerDutyWebhookSignature(t *testing.T) {
+	conf := config.DefaultAckWebhooksConfig
+	conf.PagerDutySigningSecret = "secret"
+	_, acks, _, r := newTestHandler(t, &conf)
+
+	body := pagerDutyEvent("incident.acknowledged")
+
+	w := post(r, "/hooks/pagerduty", body, http.Header{"X-Pagerduty-Signature": {"v1=abcd"}})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.False(t, acks.Acknowledged(testGroupKey))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(body))
+	sig := "v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	w = post(r, "/hooks/pagerduty", body, http.Header{"X-Pagerduty-Signature": {"v1=abcd, " + sig}})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, acks.Acknowledged(testGroupKey))
+}
+
+func TestOpsGenieWebhook(t *testing.T) {
+	conf := config.DefaultAckWebhooksConfig
+	conf.OpsGenieToken = "token"
+	_, acks, silences, r := newTestHandler(t, &conf)
+
+	event := func(action string) string {
+		return fmt.Sprintf(`{"action":%q,"alert":{"alias":%q,"username":"jane@example.com"}}`,
+			action, notify.Key(testGroupKey).Hash())
+	}
+
+	w := post(r, "/hooks/opsgenie", event("Acknowledge"), nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = post(r, "/hooks/opsgenie?token=token", event("Acknowledge"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	a, ok := acks.Get(testGroupKey)
+	require.True(t, ok)
+	require.Equal(t, "jane@example.com", a.AckedBy)
+
+	w = post(r, "/hooks/opsgenie?token=token", event("Close"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	sils, _, err := silences.Query(silence.QState(types.SilenceStateActive))
+	require.NoError(t, err)
+	require.Len(t, sils, 1)
+}
+
+func TestWebhooksDisabled(t *testing.T) {
+	_, _, _, r := newTestHandler(t, nil)
+
+	w := post(r, "/hooks/pagerduty", pagerDutyEvent("incident.acknowledged"), nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = post(r, "/hooks/opsgenie", "{}", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,439 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package alertmanager embeds the routing of alerts of the Alertmanager in a
+// Go program, without running a separate process.
+//
+// An Alertmanager returned by New keeps its alerts, silences, notification
+// log and dead letters in memory. Alerts are put with PutAlerts, or received
+// by the ingesters of the configuration, within its ingestion limits. They
+// are routed, grouped, inhibited and silenced according to the configuration,
+// and notified to its receivers. The configuration can be replaced with
+// ApplyConfig.
+//
+// The routing of alerts and their ingestion are shared with the Alertmanager
+// binary. An embedded Alertmanager has no web server, so the configurations
+// using the features of its API, e.g. authorization or tenancy, are
+// rejected, as are those configuring tracing, which is global to the
+// process.
+//
+// The API of this package follows semantic versioning: exported identifiers
+// are not removed or changed incompatibly within a major version. The other
+// packages of the module back the Alertmanager binary and may change between
+// minor versions.
+package alertmanager
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/alertmanager/analytics"
+	"github.com/prometheus/alertmanager/api/ingestion"
+	"github.com/prometheus/alertmanager/api/metrics"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/deadletter"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/heartbeat"
+	"github.com/prometheus/alertmanager/ingest"
+	"github.com/prometheus/alertmanager/lifecycle"
+	"github.com/prometheus/alertmanager/nflog"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/router"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/storage"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/alertmanager/watchdog"
+)
+
+const (
+	defaultRetention    = 120 * time.Hour
+	maintenanceInterval = 15 * time.Minute
+	alertGCInterval     = 30 * time.Minute
+)
+
+// Option customizes an Alertmanager.
+type Option func(*Alertmanager) error
+
+// WithLogger sets the logger of the Alertmanager. It defaults to a no-op
+// logger.
+func WithLogger(l log.Logger) Option {
+	return func(am *Alertmanager) error {
+		am.logger = l
+		return nil
+	}
+}
+
+// WithRegisterer registers the metrics of the Alertmanager with r. They are
+// registered with a new registry by default, so that several Alertmanagers
+// can be embedded in the same program.
+func WithRegisterer(r prometheus.Registerer) Option {
+	return func(am *Alertmanager) error {
+		am.registerer = r
+		return nil
+	}
+}
+
+// WithExternalURL sets the URL of the Alertmanager rendered by the templates
+// of the notifications.
+func WithExternalURL(u *url.URL) Option {
+	return func(am *Alertmanager) error {
+		am.externalURL = u
+		return nil
+	}
+}
+
+// WithRetention sets how long resolved alerts, expired silences and the
+// entries of the notification log are kept. It defaults to 120h.
+func WithRetention(d time.Duration) Option {
+	return func(am *Alertmanager) error {
+		if d <= 0 {
+			return errors.New("retention must be positive")
+		}
+		am.retention = d
+		return nil
+	}
+}
+
+// WithTransport wraps the round tripper of the HTTP clients of the
+// notifiers, e.g. to instrument or stub their requests.
+func WithTransport(w notify.TransportWrapper) Option {
+	return func(am *Alertmanager) error {
+		am.transport = w
+		return nil
+	}
+}
+
+// WithClock sets the clock of the notification pipeline. It defaults to
+// time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(am *Alertmanager) error {
+		am.now = now
+		return nil
+	}
+}
+
+// Alertmanager routes the alerts put into it to the receivers of its
+// configuration.
+type Alertmanager struct {
+	logger      log.Logger
+	registerer  prometheus.Registerer
+	externalURL *url.URL
+	retention   time.Duration
+	transport   notify.TransportWrapper
+	now         func() time.Time
+
+	marker          types.Marker
+	alerts          storage.Alerts
+	silences        *silence.Silences
+	notificationLog *nflog.Log
+	deadLetters     *deadletter.Queue
+	metrics         *metrics.Alerts
+	routerOpts      router.Options
+
+	watchdogs  *watchdog.Watchdogs
+	lifecycle  *lifecycle.Webhook
+	analytics  *analytics.Exporter
+	heartbeats *heartbeat.Manager
+	ingesters  *ingest.Manager
+
+	stopc chan struct{}
+	wg    sync.WaitGroup
+
+	// applyMtx serializes the changes of the configuration and Stop.
+	applyMtx sync.Mutex
+
+	mtx     sync.RWMutex
+	conf    *config.Config
+	router  *router.Router
+	stopped bool
+}
+
+// New returns an Alertmanager routing alerts according to the configuration.
+// The Alertmanager runs until Stop is called.
+func New(conf *config.Config, opts ...Option) (*Alertmanager, error) {
+	if conf == nil {
+		return nil, errors.New("missing configuration")
+	}
+	am := &Alertmanager{
+		logger:    log.NewNopLogger(),
+		retention: defaultRetention,
+		now:       time.Now,
+		stopc:     make(chan struct{}),
+	}
+	for _, o := range opts {
+		if err := o(am); err != nil {
+			return nil, err
+		}
+	}
+	if am.registerer == nil {
+		am.registerer = prometheus.NewRegistry()
+	}
+	if am.externalURL == nil {
+		am.externalURL = &url.URL{}
+	}
+	if err := checkSupported(conf); err != nil {
+		return nil, err
+	}
+
+	var err error
+	am.marker = types.NewMarker(am.registerer)
+	alerts, err := mem.NewAlerts(context.Background(), am.marker, alertGCInterval, am.retention, nil, log.With(am.logger, "component", "provider"), am.registerer)
+	if err != nil {
+		return nil, err
+	}
+	am.lifecycle = lifecycle.NewWebhook(am.registerer, log.With(am.logger, "component", "lifecycle"))
+	am.alerts = lifecycle.NewAlerts(alerts, am.lifecycle)
+	am.wg.Add(1)
+	am.notificationLog, err = nflog.New(
+		nflog.WithRetention(am.retention),
+		nflog.WithLogger(log.With(am.logger, "component", "nflog")),
+		nflog.WithMetrics(am.registerer),
+		nflog.WithMaintenance(maintenanceInterval, am.stopc, am.wg.Done, nil),
+	)
+	if err != nil {
+		am.lifecycle.Stop()
+		am.alerts.Close()
+		return nil, err
+	}
+	am.silences, err = silence.New(silence.Options{
+		Retention: am.retention,
+		Logger:    log.With(am.logger, "component", "silences"),
+		Metrics:   am.registerer,
+	})
+	if err != nil {
+		am.stop()
+		return nil, err
+	}
+	am.deadLetters, err = deadletter.New(deadletter.Options{
+		Retention: am.retention,
+		Logger:    log.With(am.logger, "component", "deadletter"),
+		Metrics:   am.registerer,
+	})
+	if err != nil {
+		am.stop()
+		return nil, err
+	}
+	am.metrics = metrics.NewAlerts("embedded", am.registerer)
+
+	quietHours := notify.NewQuietHours(am.receivers, log.With(am.logger, "component", "quiet-hours"), am.registerer)
+	budgets := notify.NewBudgets(am.receivers, log.With(am.logger, "component", "budgets"), am.registerer)
+	am.watchdogs = watchdog.New(am.alerts, am.receivers, nil, log.With(am.logger, "component", "watchdog"), am.registerer)
+	am.analytics = analytics.NewExporter(am.registerer, log.With(am.logger, "component", "analytics"))
+	am.heartbeats = heartbeat.NewManager(am.registerer, log.With(am.logger, "component", "heartbeat"))
+	am.ingesters = ingest.NewManager(am.PutAlerts, am.registerer, log.With(am.logger, "component", "ingest"))
+	am.wg.Add(5)
+	go func() {
+		am.silences.Maintenance(maintenanceInterval, "", am.stopc, nil)
+		am.wg.Done()
+	}()
+	go func() {
+		am.deadLetters.Maintenance(maintenanceInterval, "", am.stopc, nil)
+		am.wg.Done()
+	}()
+	go func() {
+		quietHours.Run(30*time.Second, am.stopc)
+		am.wg.Done()
+	}()
+	go func() {
+		budgets.Run(30*time.Second, am.stopc)
+		am.wg.Done()
+	}()
+	go func() {
+		am.watchdogs.Run(30*time.Second, am.stopc)
+		am.wg.Done()
+	}()
+
+	am.routerOpts = router.Options{
+		Alerts:            am.alerts,
+		Marker:            am.marker,
+		Silences:          am.silences,
+		ExternalURL:       am.externalURL,
+		Transport:         am.transport,
+		PipelineBuilder:   notify.NewPipelineBuilderWithOptions(am.registerer, notify.PipelineOptions{Now: am.now}),
+		DispatcherMetrics: dispatch.NewDispatcherMetrics(false, am.registerer),
+		QuietHours:        quietHours,
+		RouteDedup:        notify.NewRouteDedup(am.registerer),
+		Budgets:           budgets,
+		Events:            am.lifecycle,
+		Attempts:          am.analytics,
+		NotificationLog:   am.notificationLog,
+		DeadLetters:       am.deadLetters,
+		Logger:            am.logger,
+	}
+
+	if err := am.ApplyConfig(conf); err != nil {
+		am.Stop()
+		return nil, err
+	}
+	return am, nil
+}
+
+// checkSupported returns an error if the configuration uses features which
+// an embedded Alertmanager can't honour.
+func checkSupported(conf *config.Config) error {
+	var unsupported []string
+	for name, used := range map[string]bool{
+		"ack_webhooks":    conf.AckWebhooks != nil,
+		"authorization":   conf.Authorization != nil,
+		"cors":            conf.CORS != nil,
+		"oidc":            conf.OIDC != nil,
+		"silence_policy":  conf.SilencePolicy != nil,
+		"silence_presets": len(conf.SilencePresets) > 0,
+		"tenancy":         conf.Tenancy != nil,
+		"tracing":         conf.Tracing != nil,
+	} {
+		if used {
+			unsupported = append(unsupported, name)
+		}
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+	sort.Strings(unsupported)
+	return errors.Errorf("not supported by an embedded Alertmanager: %s", strings.Join(unsupported, ", "))
+}
+
+// receivers looks up the integrations of a receiver of the current
+// configuration.
+func (am *Alertmanager) receivers(name string) ([]notify.Integration, bool) {
+	am.mtx.RLock()
+	defer am.mtx.RUnlock()
+	if am.router == nil {
+		return nil, false
+	}
+	integrations, ok := am.router.Receivers[name]
+	return integrations, ok
+}
+
+// ApplyConfig replaces the configuration of the Alertmanager. The alerts,
+// silences and notification log are kept. The previous configuration stays
+// in effect if the new one fails to apply.
+func (am *Alertmanager) ApplyConfig(conf *config.Config) error {
+	if err := checkSupported(conf); err != nil {
+		return err
+	}
+
+	// The ingesters put alerts, so the lock of the configuration can't be
+	// held while they are updated.
+	am.applyMtx.Lock()
+	defer am.applyMtx.Unlock()
+	am.mtx.RLock()
+	stopped := am.stopped
+	am.mtx.RUnlock()
+	if stopped {
+		return errors.New("alertmanager stopped")
+	}
+
+	r, err := router.Build(conf, am.routerOpts)
+	if err != nil {
+		return err
+	}
+	am.watchdogs.Update(conf)
+	if err := am.heartbeats.Update(conf); err != nil {
+		return errors.Wrap(err, "failed to set up heartbeat")
+	}
+	if err := am.lifecycle.Update(conf); err != nil {
+		return errors.Wrap(err, "failed to set up lifecycle webhook")
+	}
+	if err := am.analytics.Update(conf); err != nil {
+		return errors.Wrap(err, "failed to set up notification analytics")
+	}
+
+	am.mtx.Lock()
+	am.router.Stop()
+	am.conf, am.router = conf, r
+	r.Run()
+	am.mtx.Unlock()
+
+	return errors.Wrap(am.ingesters.Update(conf), "failed to set up ingesters")
+}
+
+// PutAlerts relabels, validates and routes the alerts, within the ingestion
+// limits of the configuration. Alerts without a start time start now and
+// alerts without an end time are resolved after the resolve timeout, as when
+// they are posted to the API. The alerts which are valid are put even if
+// others are not.
+func (am *Alertmanager) PutAlerts(alerts ...*types.Alert) error {
+	am.mtx.RLock()
+	conf, r := am.conf, am.router
+	am.mtx.RUnlock()
+
+	res, err := ingestion.Insert(alerts, ingestion.Options{
+		Config:  conf,
+		Route:   r.Route,
+		Alerts:  am.alerts,
+		Metrics: am.metrics,
+	})
+	if err != nil {
+		return err
+	}
+	return res.Err()
+}
+
+// Alerts returns the provider of the alerts of the Alertmanager.
+func (am *Alertmanager) Alerts() provider.Alerts {
+	return am.alerts
+}
+
+// Silences returns the silences of the Alertmanager.
+func (am *Alertmanager) Silences() *silence.Silences {
+	return am.silences
+}
+
+// DeadLetters returns the notifications of the Alertmanager which could not
+// be delivered.
+func (am *Alertmanager) DeadLetters() *deadletter.Queue {
+	return am.deadLetters
+}
+
+// Marker returns the marker of the state of the alerts of the Alertmanager.
+func (am *Alertmanager) Marker() types.Marker {
+	return am.marker
+}
+
+// Stop stops routing alerts and releases the resources of the Alertmanager.
+func (am *Alertmanager) Stop() {
+	am.applyMtx.Lock()
+	defer am.applyMtx.Unlock()
+	am.mtx.Lock()
+	if am.stopped {
+		am.mtx.Unlock()
+		return
+	}
+	am.stopped = true
+	am.router.Stop()
+	am.mtx.Unlock()
+
+	am.ingesters.Stop()
+	am.heartbeats.Stop()
+	am.analytics.Stop()
+	am.stop()
+}
+
+// stop stops the maintenance of the state and releases it.
+func (am *Alertmanager) stop() {
+	close(am.stopc)
+	am.wg.Wait()
+	am.lifecycle.Stop()
+	am.alerts.Close()
+}
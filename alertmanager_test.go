@@ -0,0 +1,158 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/api/ingestion"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const testConfig = `
+route:
+  receiver: team-X
+  group_by: [alertname]
+  group_wait: 10ms
+  group_interval: 1h
+receivers:
+- name: team-X
+  webhook_configs:
+  - url: http://example.invalid/hook
+`
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestAlertmanager(t *testing.T) {
+	conf, err := config.Load(testConfig)
+	require.NoError(t, err)
+
+	bodies := make(chan string, 1)
+	am, err := New(conf, WithTransport(func(http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			b, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			bodies <- string(b)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				Request:    req,
+			}, nil
+		})
+	}))
+	require.NoError(t, err)
+	defer am.Stop()
+
+	err = am.PutAlerts(&types.Alert{
+		Alert: model.Alert{Labels: model.LabelSet{"alertname": "HighLatency", "empty": ""}},
+	})
+	require.NoError(t, err)
+
+	select {
+	case b := <-bodies:
+		require.Contains(t, b, `"receiver":"team-X"`)
+		require.Contains(t, b, `"alertname":"HighLatency"`)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification sent")
+	}
+
+	// The invalid alerts are rejected, the valid ones put.
+	err = am.PutAlerts(
+		&types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Valid"}}},
+		&types.Alert{Alert: model.Alert{Labels: model.LabelSet{}}},
+	)
+	require.Error(t, err)
+	it := am.Alerts().GetPending()
+	defer it.Close()
+	var names []string
+	for a := range it.Next() {
+		names = append(names, string(a.Labels["alertname"]))
+	}
+	require.ElementsMatch(t, []string{"HighLatency", "Valid"}, names)
+}
+
+func TestAlertmanagerApplyConfig(t *testing.T) {
+	conf, err := config.Load(testConfig)
+	require.NoError(t, err)
+	am, err := New(conf)
+	require.NoError(t, err)
+	defer am.Stop()
+
+	bad := *conf
+	bad.Templates = []string{"/nonexistent/["}
+	require.Error(t, am.ApplyConfig(&bad))
+
+	// Another Alertmanager registers its metrics with its own registry.
+	other, err := New(conf)
+	require.NoError(t, err)
+	other.Stop()
+
+	am.Stop()
+	require.Error(t, am.ApplyConfig(conf))
+}
+
+func TestAlertmanagerIngestionLimits(t *testing.T) {
+	conf, err := config.Load(testConfig + `
+ingestion_limits:
+  max_active_alerts: 1
+  max_label_bytes: 64
+`)
+	require.NoError(t, err)
+	am, err := New(conf)
+	require.NoError(t, err)
+	defer am.Stop()
+
+	err = am.PutAlerts(&types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": model.LabelValue(strings.Repeat("x", 64))}}})
+	require.Error(t, err)
+	require.IsType(t, &types.MultiError{}, err)
+
+	require.NoError(t, am.PutAlerts(&types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "First"}}}))
+	err = am.PutAlerts(&types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Second"}}})
+	require.True(t, errors.Is(err, ingestion.ErrTooManyAlerts), err)
+	// Updates of the alerts already held are accepted.
+	require.NoError(t, am.PutAlerts(&types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "First"}}}))
+}
+
+func TestAlertmanagerUnsupportedConfig(t *testing.T) {
+	conf, err := config.Load(testConfig + `
+tenancy:
+  header: X-Tenant
+  label: tenant
+  tenants:
+    prometheus: team-a
+`)
+	require.NoError(t, err)
+	_, err = New(conf)
+	require.EqualError(t, err, "not supported by an embedded Alertmanager: tenancy")
+
+	supported, err := config.Load(testConfig)
+	require.NoError(t, err)
+	am, err := New(supported)
+	require.NoError(t, err)
+	defer am.Stop()
+	require.Error(t, am.ApplyConfig(conf))
+}
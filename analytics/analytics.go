@@ -0,0 +1,366 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analytics exports an event for every notification attempt to
+// Honeycomb or an OTLP logs endpoint, to analyze the notification pipeline
+// itself.
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/version"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+)
+
+const (
+	// queueCapacity is the number of events waiting to be sent above which
+	// events are dropped.
+	queueCapacity = 10000
+	// requestTimeout is the timeout of a request to the endpoint.
+	requestTimeout = 30 * time.Second
+	// scopeName is the instrumentation scope of the OTLP log records.
+	scopeName = "github.com/prometheus/alertmanager/analytics"
+)
+
+var userAgentHeader = fmt.Sprintf("Alertmanager/%s", version.Version)
+
+// Exporter sends the notification attempts it records to the endpoint of the
+// configuration, in batches. Events are dropped if the endpoint fails or can't
+// keep up.
+type Exporter struct {
+	mtx    sync.RWMutex
+	conf   *config.NotificationAnalyticsConfig
+	queue  chan notify.Attempt
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	recorded       prometheus.Counter
+	dropped        prometheus.Counter
+	requestsFailed prometheus.Counter
+	logger         log.Logger
+}
+
+// NewExporter returns an exporter which doesn't record attempts until a
+// configuration with notification analytics is applied.
+func NewExporter(r prometheus.Registerer, l log.Logger) *Exporter {
+	e := &Exporter{
+		recorded: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_notification_analytics_events_total",
+			Help: "The total number of notification attempts recorded for analytics.",
+		}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_notification_analytics_events_dropped_total",
+			Help: "The total number of notification analytics events dropped because the queue was full or the request failed.",
+		}),
+		requestsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_notification_analytics_requests_failed_total",
+			Help: "The total number of failed requests to the notification analytics endpoint.",
+		}),
+		logger: l,
+	}
+	if r != nil {
+		r.MustRegister(e.recorded, e.dropped, e.requestsFailed)
+	}
+	return e
+}
+
+// Update applies the notification analytics configuration. The events queued
+// are dropped if the configuration changed.
+func (e *Exporter) Update(c *config.Config) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if reflect.DeepEqual(c.NotificationAnalytics, e.conf) {
+		return nil
+	}
+
+	var client *http.Client
+	if c.NotificationAnalytics != nil {
+		var err error
+		client, err = commoncfg.NewClientFromConfig(*c.NotificationAnalytics.HTTPConfig, "notification_analytics")
+		if err != nil {
+			return err
+		}
+	}
+
+	e.stop()
+	e.conf = c.NotificationAnalytics
+	if c.NotificationAnalytics != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		e.queue = make(chan notify.Attempt, queueCapacity)
+		e.cancel, e.done = cancel, make(chan struct{})
+		go e.run(ctx, c.NotificationAnalytics, client, e.queue, e.done)
+	}
+	return nil
+}
+
+// Stop stops sending the events.
+func (e *Exporter) Stop() {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.stop()
+	e.conf = nil
+}
+
+func (e *Exporter) stop() {
+	if e.cancel == nil {
+		return
+	}
+	e.cancel()
+	<-e.done
+	e.cancel, e.done, e.queue = nil, nil, nil
+}
+
+// RecordAttempt implements the notify.AttemptRecorder interface. It never
+// blocks.
+func (e *Exporter) RecordAttempt(a notify.Attempt) {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	if e.queue == nil {
+		return
+	}
+	e.recorded.Inc()
+	select {
+	case e.queue <- a:
+	default:
+		e.dropped.Inc()
+	}
+}
+
+// run sends the queued events whenever a batch is full or the flush interval
+// elapsed, until the context is canceled.
+func (e *Exporter) run(ctx context.Context, conf *config.NotificationAnalyticsConfig, client *http.Client, queue <-chan notify.Attempt, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(time.Duration(conf.FlushInterval))
+	defer ticker.Stop()
+
+	batch := make([]notify.Attempt, 0, conf.MaxEvents)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := send(ctx, conf, client, batch); err != nil && ctx.Err() == nil {
+			e.requestsFailed.Inc()
+			e.dropped.Add(float64(len(batch)))
+			level.Warn(e.logger).Log("msg", "Failed to send notification analytics events", "events", len(batch), "err", err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case a := <-queue:
+			batch = append(batch, a)
+			if len(batch) >= conf.MaxEvents {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// field is an attribute of an event.
+type field struct {
+	key   string
+	value interface{}
+}
+
+// fields returns the attributes of the event of an attempt. The values are
+// strings, ints or float64s.
+func fields(a notify.Attempt) []field {
+	fs := []field{
+		{"receiver", a.Receiver},
+		{"integration", a.Integration},
+		{"integration_index", a.Index},
+		{"group_key", a.GroupKey},
+		{"attempt", a.Number},
+		{"outcome", a.Outcome},
+		{"duration_ms", float64(a.Duration) / float64(time.Millisecond)},
+		{"alerts", a.Firing + a.Resolved},
+		{"firing_alerts", a.Firing},
+		{"resolved_alerts", a.Resolved},
+	}
+	if a.StatusCode != 0 {
+		fs = append(fs, field{"status_code", a.StatusCode})
+	}
+	if a.Error != "" {
+		fs = append(fs, field{"error", a.Error})
+	}
+	return fs
+}
+
+// honeycombEvent is an event of the batch API of Honeycomb.
+type honeycombEvent struct {
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data"`
+}
+
+func honeycombBatch(attempts []notify.Attempt) interface{} {
+	events := make([]honeycombEvent, 0, len(attempts))
+	for _, a := range attempts {
+		data := make(map[string]interface{})
+		for _, f := range fields(a) {
+			data[f.key] = f.value
+		}
+		events = append(events, honeycombEvent{Time: a.Time, Data: data})
+	}
+	return events
+}
+
+// The OTLP/HTTP JSON encoding of the logs export request.
+type (
+	otlpRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	otlpLogRecord struct {
+		TimeUnixNano string         `json:"timeUnixNano"`
+		SeverityText string         `json:"severityText"`
+		Body         otlpAnyValue   `json:"body"`
+		Attributes   []otlpKeyValue `json:"attributes"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	// otlpAnyValue has one of its fields set. 64-bit integers are encoded
+	// as strings.
+	otlpAnyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+func otlpValue(v interface{}) otlpAnyValue {
+	switch v := v.(type) {
+	case int:
+		s := strconv.Itoa(v)
+		return otlpAnyValue{IntValue: &s}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	default:
+		s := fmt.Sprint(v)
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+func otlpBatch(attempts []notify.Attempt) interface{} {
+	records := make([]otlpLogRecord, 0, len(attempts))
+	for _, a := range attempts {
+		r := otlpLogRecord{
+			TimeUnixNano: strconv.FormatInt(a.Time.UnixNano(), 10),
+			SeverityText: "INFO",
+			Body:         otlpValue("notification attempt " + a.Outcome),
+		}
+		if a.Outcome != notify.AttemptSucceeded {
+			r.SeverityText = "WARN"
+		}
+		for _, f := range fields(a) {
+			r.Attributes = append(r.Attributes, otlpKeyValue{Key: "alertmanager." + f.key, Value: otlpValue(f.value)})
+		}
+		records = append(records, r)
+	}
+	return &otlpRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{
+					{Key: "service.name", Value: otlpValue("alertmanager")},
+					{Key: "service.version", Value: otlpValue(version.Version)},
+				},
+			},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: scopeName, Version: version.Version},
+				LogRecords: records,
+			}},
+		}},
+	}
+}
+
+func send(ctx context.Context, conf *config.NotificationAnalyticsConfig, client *http.Client, attempts []notify.Attempt) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var (
+		u    = conf.URL.String()
+		body interface{}
+	)
+	switch conf.Type {
+	case "honeycomb":
+		u = strings.TrimSuffix(u, "/") + "/1/batch/" + url.PathEscape(conf.Dataset)
+		body = honeycombBatch(attempts)
+	default:
+		body = otlpBatch(attempts)
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, u, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgentHeader)
+	if conf.Type == "honeycomb" {
+		req.Header.Set("X-Honeycomb-Team", string(conf.APIKey))
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+)
+
+var (
+	succeeded = notify.Attempt{
+		Time:        time.Unix(1600000000, 0),
+		Receiver:    "team-X",
+		Integration: "webhook",
+		Index:       1,
+		GroupKey:    "{}:{}",
+		Number:      1,
+		Outcome:     notify.AttemptSucceeded,
+		Duration:    1500 * time.Microsecond,
+		Firing:      2,
+		Resolved:    1,
+	}
+	failed = notify.Attempt{
+		Time:        time.Unix(1600000001, 0),
+		Receiver:    "team-X",
+		Integration: "webhook",
+		Number:      2,
+		Outcome:     notify.AttemptFailed,
+		Firing:      1,
+		StatusCode:  503,
+		Error:       "unexpected status code 503",
+	}
+)
+
+func TestExporterHoneycomb(t *testing.T) {
+	type request struct {
+		path, key string
+		events    []honeycombEvent
+	}
+	requests := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{path: r.URL.Path, key: r.Header.Get("X-Honeycomb-Team")}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req.events))
+		requests <- req
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	e := NewExporter(nil, log.NewNopLogger())
+	defer e.Stop()
+
+	// Nothing is recorded until the analytics are configured.
+	e.RecordAttempt(succeeded)
+	require.Equal(t, 0.0, testutil.ToFloat64(e.recorded))
+
+	require.NoError(t, e.Update(&config.Config{
+		NotificationAnalytics: &config.NotificationAnalyticsConfig{
+			Type:          "honeycomb",
+			URL:           &config.URL{URL: u},
+			Dataset:       "paging",
+			APIKey:        "key",
+			MaxEvents:     2,
+			FlushInterval: model.Duration(time.Hour),
+			HTTPConfig:    &commoncfg.HTTPClientConfig{},
+		},
+	}))
+	e.RecordAttempt(succeeded)
+	e.RecordAttempt(failed)
+
+	select {
+	case req := <-requests:
+		require.Equal(t, "/1/batch/paging", req.path)
+		require.Equal(t, "key", req.key)
+		require.Len(t, req.events, 2)
+		require.True(t, succeeded.Time.Equal(req.events[0].Time))
+		require.Equal(t, map[string]interface{}{
+			"receiver":          "team-X",
+			"integration":       "webhook",
+			"integration_index": 1.0,
+			"group_key":         "{}:{}",
+			"attempt":           1.0,
+			"outcome":           "succeeded",
+			"duration_ms":       1.5,
+			"alerts":            3.0,
+			"firing_alerts":     2.0,
+			"resolved_alerts":   1.0,
+		}, req.events[0].Data)
+		require.Equal(t, 503.0, req.events[1].Data["status_code"])
+		require.Equal(t, "unexpected status code 503", req.events[1].Data["error"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("events not sent")
+	}
+	require.Equal(t, 2.0, testutil.ToFloat64(e.recorded))
+
+	// Removing the configuration stops recording.
+	require.NoError(t, e.Update(&config.Config{}))
+	e.RecordAttempt(succeeded)
+	require.Equal(t, 2.0, testutil.ToFloat64(e.recorded))
+}
+
+func TestExporterOTLP(t *testing.T) {
+	requests := make(chan otlpRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlpRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL + "/v1/logs")
+	require.NoError(t, err)
+
+	e := NewExporter(nil, log.NewNopLogger())
+	defer e.Stop()
+	require.NoError(t, e.Update(&config.Config{
+		NotificationAnalytics: &config.NotificationAnalyticsConfig{
+			Type:          "otlp",
+			URL:           &config.URL{URL: u},
+			MaxEvents:     100,
+			FlushInterval: model.Duration(10 * time.Millisecond),
+			HTTPConfig:    &commoncfg.HTTPClientConfig{},
+		},
+	}))
+	e.RecordAttempt(failed)
+
+	select {
+	case req := <-requests:
+		require.Len(t, req.ResourceLogs, 1)
+		require.Equal(t, "service.name", req.ResourceLogs[0].Resource.Attributes[0].Key)
+		require.Equal(t, "alertmanager", *req.ResourceLogs[0].Resource.Attributes[0].Value.StringValue)
+		records := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+		require.Len(t, records, 1)
+		require.Equal(t, "1600000001000000000", records[0].TimeUnixNano)
+		require.Equal(t, "WARN", records[0].SeverityText)
+		attrs := make(map[string]otlpAnyValue)
+		for _, kv := range records[0].Attributes {
+			attrs[kv.Key] = kv.Value
+		}
+		require.Equal(t, "failed", *attrs["alertmanager.outcome"].StringValue)
+		require.Equal(t, "2", *attrs["alertmanager.attempt"].IntValue)
+		require.Equal(t, "503", *attrs["alertmanager.status_code"].IntValue)
+		require.Equal(t, 0.0, *attrs["alertmanager.duration_ms"].DoubleValue)
+	case <-time.After(5 * time.Second):
+		t.Fatal("events not sent")
+	}
+}
+
+func TestExporterFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	e := NewExporter(nil, log.NewNopLogger())
+	defer e.Stop()
+	require.NoError(t, e.Update(&config.Config{
+		NotificationAnalytics: &config.NotificationAnalyticsConfig{
+			Type:          "otlp",
+			URL:           &config.URL{URL: u},
+			MaxEvents:     1,
+			FlushInterval: model.Duration(time.Hour),
+			HTTPConfig:    &commoncfg.HTTPClientConfig{},
+		},
+	}))
+	e.RecordAttempt(succeeded)
+	require.Eventually(t, func() bool { return testutil.ToFloat64(e.requestsFailed) == 1 }, 5*time.Second, time.Millisecond)
+	require.Equal(t, 1.0, testutil.ToFloat64(e.dropped))
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package annotate adds the annotations rendered from the annotation
+// templates of the configuration to the incoming alerts.
+package annotate
+
+import (
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Data is the data the annotation templates are rendered with.
+type Data struct {
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// Apply adds the annotations of the templates matching the labels of the
+// alert, in order, and returns the number of templates which failed to
+// render. Annotations the alert already has are kept unless the template
+// overwrites them. Later templates see the annotations added by earlier
+// ones.
+func Apply(a *types.Alert, templates []*config.AnnotationTemplate) int {
+	if len(templates) == 0 {
+		return 0
+	}
+
+	var (
+		data = Data{
+			Labels:      toMap(a.Labels),
+			Annotations: toMap(a.Annotations),
+		}
+		annotations = a.Annotations
+		cloned      bool
+		failed      int
+	)
+	for _, t := range templates {
+		if !labels.Matchers(t.Matchers).Matches(a.Labels) {
+			continue
+		}
+		name := model.LabelName(t.Name)
+		if _, ok := annotations[name]; ok && !t.Overwrite {
+			continue
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			failed++
+			continue
+		}
+		if !cloned {
+			// The annotations may be shared with other copies of the
+			// alert, so they are cloned before the first change.
+			annotations = a.Annotations.Clone()
+			cloned = true
+		}
+		annotations[name] = model.LabelValue(b.String())
+		data.Annotations[t.Name] = b.String()
+	}
+	a.Annotations = annotations
+	return failed
+}
+
+func toMap(ls model.LabelSet) map[string]string {
+	m := make(map[string]string, len(ls))
+	for k, v := range ls {
+		m[string(k)] = string(v)
+	}
+	return m
+}
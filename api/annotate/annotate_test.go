@@ -0,0 +1,85 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotate
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func loadTemplates(t *testing.T, in string) []*config.AnnotationTemplate {
+	t.Helper()
+	var templates []*config.AnnotationTemplate
+	require.NoError(t, yaml.UnmarshalStrict([]byte(in), &templates))
+	return templates
+}
+
+func TestApply(t *testing.T) {
+	templates := loadTemplates(t, `
+- name: dashboard
+  template: 'https://grafana/d/k8s?var-cluster={{ .Labels.cluster }}&var-namespace={{ .Labels.namespace | urlquery }}'
+  matchers: ['cluster=~".+"']
+- name: runbook
+  template: 'https://runbooks/{{ .Labels.alertname }}'
+- name: summary
+  template: '{{ .Annotations.summary }} ({{ .Annotations.dashboard }})'
+  overwrite: true
+`)
+
+	annotations := model.LabelSet{"summary": "Pods crashing", "runbook": "https://wiki/crash"}
+	a := &types.Alert{Alert: model.Alert{
+		Labels:      model.LabelSet{"alertname": "KubePodCrashLooping", "cluster": "eu-1", "namespace": "a b"},
+		Annotations: annotations,
+	}}
+	require.Equal(t, 0, Apply(a, templates))
+	require.Equal(t, model.LabelSet{
+		"dashboard": "https://grafana/d/k8s?var-cluster=eu-1&var-namespace=a+b",
+		"runbook":   "https://wiki/crash",
+		"summary":   "Pods crashing (https://grafana/d/k8s?var-cluster=eu-1&var-namespace=a+b)",
+	}, a.Annotations)
+	// The original annotations are left alone.
+	require.Equal(t, model.LabelSet{"summary": "Pods crashing", "runbook": "https://wiki/crash"}, annotations)
+
+	// The dashboard isn't added to alerts without a cluster.
+	a = &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Watchdog"}}}
+	require.Equal(t, 0, Apply(a, templates))
+	require.Equal(t, model.LabelSet{
+		"runbook": "https://runbooks/Watchdog",
+		"summary": " ()",
+	}, a.Annotations)
+}
+
+func TestApplyFailure(t *testing.T) {
+	templates := loadTemplates(t, `
+- name: broken
+  template: '{{ index .Labels.cluster 10 }}'
+- name: runbook
+  template: 'https://runbooks/{{ .Labels.alertname }}'
+`)
+	a := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Watchdog"}}}
+	require.Equal(t, 1, Apply(a, templates))
+	require.Equal(t, model.LabelSet{"runbook": "https://runbooks/Watchdog"}, a.Annotations)
+}
+
+func TestApplyNoTemplates(t *testing.T) {
+	a := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Watchdog"}}}
+	require.Equal(t, 0, Apply(a, nil))
+	require.Nil(t, a.Annotations)
+}
@@ -14,25 +14,49 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
 	"net/http"
+	"path"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/prometheus/alertmanager/ack"
+	"github.com/prometheus/alertmanager/api/auditlog"
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/api/limits"
+	"github.com/prometheus/alertmanager/api/rpc"
 	apiv1 "github.com/prometheus/alertmanager/api/v1"
 	apiv2 "github.com/prometheus/alertmanager/api/v2"
 	"github.com/prometheus/alertmanager/cluster"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/deadletter"
 	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/history"
+	"github.com/prometheus/alertmanager/inhibit"
+	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/provider/shard"
 	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/audit"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/route"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // API represents all APIs of Alertmanager.
@@ -41,8 +65,21 @@ type API struct {
 	v2                       *apiv2.API
 	requestsInFlight         prometheus.Gauge
 	concurrencyLimitExceeded prometheus.Counter
+	rateLimited              *prometheus.CounterVec
 	timeout                  time.Duration
 	inFlightSem              chan struct{}
+	trustBasicAuth           bool
+	rateLimiter              *limits.RateLimiter
+	auditLog                 *auditlog.Log
+	forwardSigner            *shard.Signer
+	rpc                      *rpc.Server
+	logger                   log.Logger
+
+	mtx             sync.RWMutex
+	authorization   *config.AuthorizationConfig
+	tenancy         *config.TenancyConfig
+	ingestionLimits *config.IngestionLimitsConfig
+	draining        bool
 }
 
 // Options for the creation of an API object. Alerts, Silences, and StatusFunc
@@ -74,6 +111,61 @@ type Options struct {
 	// according to the current active configuration. Alerts returned are
 	// filtered by the arguments provided to the function.
 	GroupFunc func(func(*dispatch.Route) bool, func(*types.Alert, time.Time) bool) (dispatch.AlertGroups, map[model.Fingerprint][]string)
+	// InhibitionsFunc returns the alerts currently inhibited by other alerts.
+	// If nil, the inhibitions endpoint returns no inhibitions.
+	InhibitionsFunc func() []inhibit.Inhibition
+	// ConfigDiffFunc returns the difference between the last two applied
+	// configurations. If nil, the config diff endpoint returns an empty diff.
+	ConfigDiffFunc func() *config.Diff
+	// ConfigValidationFunc returns the result of the validation of the
+	// receivers of the last configuration loaded. If nil, the config
+	// validation endpoint returns an empty validation.
+	ConfigValidationFunc func() *config.Validation
+	// DeadLetters holds the notifications that could not be delivered. If
+	// nil, the dead-letter endpoints return no entries.
+	DeadLetters *deadletter.Queue
+	// ReplayFunc sends a dead-lettered notification again. If nil, replaying
+	// is not possible.
+	ReplayFunc func(*deadletter.Entry) error
+	// RenderFunc returns the notifications the integrations of a receiver
+	// would send for the given alerts, or false if the receiver is not used
+	// by any route. If nil, rendering is not possible.
+	RenderFunc func(context.Context, string, []*types.Alert) ([]notify.Rendered, bool)
+	// TestFunc sends the given alerts once through the integrations of a
+	// receiver, or returns false if the receiver is not used by any route.
+	// If nil, testing receivers is not possible.
+	TestFunc func(context.Context, string, []*types.Alert) ([]notify.TestResult, bool)
+	// ResendFunc notifies a receiver of the alert group with the given key
+	// again right away. If nil, resending alert groups is not possible.
+	ResendFunc func(ctx context.Context, groupKey, receiver string) error
+	// ReceiverHealth tracks the health of the integrations of the receivers.
+	// If nil, their health is unknown.
+	ReceiverHealth *notify.Health
+	// Budgets holds the consumption of the notification budgets. If nil,
+	// no budgets are reported.
+	Budgets *notify.Budgets
+	// Acks holds the acknowledgements of alert groups. If nil, alert groups
+	// cannot be acknowledged.
+	Acks *ack.Acks
+	// SilenceAudit holds the changes made to silences. If nil, the silence
+	// events endpoint returns no events.
+	SilenceAudit *audit.Log
+	// NotificationHistory holds the attempts to send notifications. If nil,
+	// the notification history endpoint returns no attempts.
+	NotificationHistory *history.Log
+	// TrustBasicAuth makes the basic authentication users identify the
+	// clients for authorization. It must only be set if the web server
+	// authenticates them.
+	TrustBasicAuth bool
+	// AuditLog records the state-changing requests. If nil, they are not
+	// recorded.
+	AuditLog *auditlog.Log
+	// ForwardSigner authenticates the requests forwarding alerts from
+	// other cluster members. If nil, the forwarding headers of all
+	// requests are ignored.
+	ForwardSigner *shard.Signer
+	// EnableGRPC serves the gRPC API. Its requests must use HTTP/2.
+	EnableGRPC bool
 }
 
 func (o Options) validate() error {
@@ -114,6 +206,9 @@ func New(opts Options) (*API, error) {
 		opts.Alerts,
 		opts.Silences,
 		opts.StatusFunc,
+		opts.InhibitionsFunc,
+		opts.ConfigDiffFunc,
+		opts.ConfigValidationFunc,
 		opts.Peer,
 		log.With(l, "version", "v1"),
 		opts.Registry,
@@ -124,6 +219,16 @@ func New(opts Options) (*API, error) {
 		opts.GroupFunc,
 		opts.StatusFunc,
 		opts.Silences,
+		opts.DeadLetters,
+		opts.ReplayFunc,
+		opts.RenderFunc,
+		opts.TestFunc,
+		opts.ResendFunc,
+		opts.ReceiverHealth,
+		opts.Budgets,
+		opts.Acks,
+		opts.SilenceAudit,
+		opts.NotificationHistory,
 		opts.Peer,
 		log.With(l, "version", "v2"),
 		opts.Registry,
@@ -145,6 +250,10 @@ func New(opts Options) (*API, error) {
 		Help:        "Total number of times an HTTP request failed because the concurrency limit was reached.",
 		ConstLabels: prometheus.Labels{"method": "get"},
 	})
+	rateLimited := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alertmanager_http_rate_limited_requests_total",
+		Help: "Total number of requests posting alerts rejected because their client exceeded its rate limit.",
+	}, []string{"limit"})
 	if opts.Registry != nil {
 		if err := opts.Registry.Register(requestsInFlight); err != nil {
 			return nil, err
@@ -152,16 +261,37 @@ func New(opts Options) (*API, error) {
 		if err := opts.Registry.Register(concurrencyLimitExceeded); err != nil {
 			return nil, err
 		}
+		if err := opts.Registry.Register(rateLimited); err != nil {
+			return nil, err
+		}
 	}
 
-	return &API{
+	api := &API{
 		v1:                       v1,
 		v2:                       v2,
 		requestsInFlight:         requestsInFlight,
 		concurrencyLimitExceeded: concurrencyLimitExceeded,
+		rateLimited:              rateLimited,
 		timeout:                  opts.Timeout,
 		inFlightSem:              make(chan struct{}, concurrency),
-	}, nil
+		trustBasicAuth:           opts.TrustBasicAuth,
+		rateLimiter:              limits.NewRateLimiter(),
+		auditLog:                 opts.AuditLog,
+		forwardSigner:            opts.ForwardSigner,
+		logger:                   l,
+	}
+	if opts.EnableGRPC {
+		api.rpc = rpc.New(rpc.Options{
+			Alerts:     opts.Alerts,
+			Silences:   opts.Silences,
+			StatusFunc: opts.StatusFunc,
+			GroupFunc:  opts.GroupFunc,
+			PostFunc:   v2.PostAlerts,
+			AdmitFunc:  api.admitRPC,
+			Logger:     log.With(l, "version", "grpc"),
+		})
+	}
+	return api, nil
 }
 
 // Register all APIs. It registers APIv1 with the provided router directly. As
@@ -171,34 +301,345 @@ func New(opts Options) (*API, error) {
 // the newly created http.ServeMux. If a timeout has been set on construction of
 // API, it is enforced for all HTTP request going through this mux. The same is
 // true for the concurrency limit, with the exception that it is only applied to
-// GET requests.
+// GET requests. The authorization of the configuration is enforced for all
+// requests, and the rate limits of the clients for the requests posting
+// alerts, which API v2 also accepts as CloudEvents. State-changing requests are recorded in the audit log, if any.
+// The requests forwarding alerts from other cluster members are authenticated
+// by the forward signer. The gRPC API, if enabled, is registered at the root
+// of the mux since gRPC clients don't support path prefixes; the timeout and
+// the concurrency limit don't apply to it.
 func (api *API) Register(r *route.Router, routePrefix string) *http.ServeMux {
 	api.v1.Register(r.WithPrefix("/api/v1"))
 
-	mux := http.NewServeMux()
-	mux.Handle("/", api.limitHandler(r))
-
 	apiPrefix := ""
 	if routePrefix != "/" {
 		apiPrefix = routePrefix
 	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", api.auditHandler(apiPrefix, api.authorizeHandler(apiPrefix, api.limitHandler(r))))
+	mux.Handle(
+		apiPrefix+"/api/v1/",
+		api.auditHandler(apiPrefix, api.authorizeHandler(apiPrefix, api.limitHandler(api.drainHandler(apiPrefix, api.forwardSigner.Handler(api.rateLimitHandler(apiPrefix, api.rejectTenantHandler(r))))))),
+	)
 	// TODO(beorn7): HTTP instrumentation is only in place for Router. Since
 	// /api/v2 works on the Handler level, it is currently not instrumented
 	// at all (with the exception of requestsInFlight, which is handled in
 	// limitHandler below).
 	mux.Handle(
 		apiPrefix+"/api/v2/",
-		api.limitHandler(http.StripPrefix(apiPrefix+"/api/v2", api.v2.Handler)),
+		api.auditHandler(apiPrefix, api.authorizeHandler(apiPrefix, api.limitHandler(api.drainHandler(apiPrefix, api.forwardSigner.Handler(api.rateLimitHandler(apiPrefix, cloudEventsHandler(apiPrefix, http.StripPrefix(apiPrefix+"/api/v2", api.v2.Handler)))))))),
 	)
+	// The alert stream is long-lived, so neither the timeout nor the
+	// concurrency limit apply to it.
+	mux.Handle(
+		apiPrefix+"/api/v2/alerts/stream",
+		api.authorizeHandler(apiPrefix, api.forwardSigner.Handler(http.StripPrefix(apiPrefix+"/api/v2", api.v2.Handler))),
+	)
+	if api.rpc != nil {
+		mux.Handle(rpc.ServicePath, api.authorizeHandler("", api.rejectTenantHandler(api.rpc)))
+	}
 
 	return mux
 }
 
+// PostAlerts stores alerts received by other means than the API, such as
+// ingesters, like the alerts posted to API v2.
+func (api *API) PostAlerts(alerts ...*types.Alert) error {
+	return api.v2.PostAlerts(alerts...)
+}
+
 // Update config and resolve timeout of each API. APIv2 also needs
 // setAlertStatus to be updated.
 func (api *API) Update(cfg *config.Config, setAlertStatus func(model.LabelSet)) {
 	api.v1.Update(cfg)
 	api.v2.Update(cfg, setAlertStatus)
+
+	api.mtx.Lock()
+	api.authorization = cfg.Authorization
+	api.tenancy = cfg.Tenancy
+	api.ingestionLimits = cfg.IngestionLimits
+	api.mtx.Unlock()
+}
+
+// authorizeHandler rejects the requests whose client lacks the role required
+// by the request. All requests are served if no authorization is configured.
+// The identity of the client is set in the context of the served requests.
+func (api *API) authorizeHandler(prefix string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		api.mtx.RLock()
+		c := api.authorization
+		api.mtx.RUnlock()
+
+		identity := auth.Identity(req, api.trustBasicAuth)
+		if c != nil {
+			required := requiredRole(req.Method, strings.TrimPrefix(req.URL.Path, prefix))
+			if role := c.Role(identity, auth.Groups(req)...); !role.Allows(required) {
+				level.Debug(api.logger).Log("msg", "Request not authorized", "identity", identity, "role", role, "required", required, "method", req.Method, "path", req.URL.Path)
+				http.Error(w, fmt.Sprintf("%s role required, %q has the %s role", required, identity, role), http.StatusForbidden)
+				return
+			}
+		}
+		// The handlers get the identity without knowing whether basic
+		// authentication users can be trusted.
+		h.ServeHTTP(w, req.WithContext(auth.WithIdentity(req.Context(), identity)))
+	})
+}
+
+// rejectTenantHandler rejects the requests of the APIs which can't be scoped
+// to a tenant, unless tenancy is disabled or the client is an admin acting
+// across all tenants. Only API v2 supports tenants.
+func (api *API) rejectTenantHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		api.mtx.RLock()
+		tc, ac := api.tenancy, api.authorization
+		api.mtx.RUnlock()
+
+		if tc == nil {
+			h.ServeHTTP(w, req)
+			return
+		}
+		identity := auth.Identity(req, false)
+		admin := ac != nil && ac.Role(identity, auth.Groups(req)...).Allows(config.RoleAdmin)
+		if !admin || req.Header.Get(tc.Header) != "" {
+			http.Error(w, "tenants are only supported by API v2", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// auditedOperations maps the state-changing requests to their operation
+// recorded in the audit log. Paths are relative to the route prefix and
+// matched with path.Match.
+var auditedOperations = []struct {
+	method, path, operation string
+}{
+	{http.MethodPost, "/-/reload", "config.reload"},
+	{http.MethodPost, "/api/v1/silences", "silence.save"},
+	{http.MethodPost, "/api/v2/silences", "silence.save"},
+	{http.MethodDelete, "/api/v1/silence/*", "silence.delete"},
+	{http.MethodDelete, "/api/v2/silence/*", "silence.delete"},
+	{http.MethodPost, "/api/v2/silences/expire", "silence.expire"},
+	{http.MethodPost, "/api/v2/silences/recreate", "silence.recreate"},
+	{http.MethodPost, "/api/v2/silences/presets/*", "silence.preset"},
+	{http.MethodPost, "/ack", "ack.save"},
+	{http.MethodPost, "/api/v2/acks", "ack.save"},
+	{http.MethodDelete, "/api/v2/ack/*", "ack.delete"},
+	{http.MethodPost, "/hooks/*", "ack.webhook"},
+	{http.MethodPost, "/api/v2/deadletter/*/replay", "deadletter.replay"},
+	{http.MethodDelete, "/api/v2/deadletter/*", "deadletter.delete"},
+	{http.MethodPost, "/api/v2/receivers/*/test", "receiver.test"},
+	{http.MethodPost, "/api/v2/alerts/groups/resend", "alertgroup.resend"},
+}
+
+// auditOperation returns the operation of a request with the given method
+// and path, relative to the route prefix, or an empty string if the request
+// is not audited.
+func auditOperation(method, p string) string {
+	for _, o := range auditedOperations {
+		if ok, _ := path.Match(o.path, p); ok && method == o.method {
+			return o.operation
+		}
+	}
+	return ""
+}
+
+// statusRecorder records the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// auditHandler records the state-changing requests in the audit log, along
+// with their client and the digest of their payload. Rejected requests are
+// recorded as well.
+func (api *API) auditHandler(prefix string, h http.Handler) http.Handler {
+	if api.auditLog == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		operation := auditOperation(req.Method, strings.TrimPrefix(req.URL.Path, prefix))
+		if operation == "" {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %s", err), http.StatusBadRequest)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		rec := &statusRecorder{ResponseWriter: w}
+		h.ServeHTTP(rec, req)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		api.auditLog.Record(auditlog.Entry{
+			Operation:  operation,
+			Actor:      auth.Identity(req, api.trustBasicAuth),
+			RemoteAddr: req.RemoteAddr,
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			Status:     rec.status,
+			Digest:     auditlog.Digest(body),
+		})
+	})
+}
+
+// Drain makes the API reject the requests posting alerts from now on, so that
+// no new alerts are received while the Alertmanager shuts down.
+func (api *API) Drain() {
+	api.mtx.Lock()
+	defer api.mtx.Unlock()
+	api.draining = true
+}
+
+// drainHandler rejects the requests posting alerts with a 503 response once
+// the API is draining, so that their clients send them to another
+// Alertmanager or retry later.
+func (api *API) drainHandler(prefix string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path := strings.TrimPrefix(req.URL.Path, prefix)
+		if req.Method == http.MethodPost && (path == "/api/v1/alerts" || path == "/api/v2/alerts") {
+			api.mtx.RLock()
+			draining := api.draining
+			api.mtx.RUnlock()
+			if draining {
+				http.Error(w, "Alertmanager is shutting down", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// rateLimitHandler rejects the requests posting alerts whose client exceeds
+// its rate limits with a 429 response telling when to retry. Clients are
+// identified like for authorization, and by their address otherwise.
+func (api *API) rateLimitHandler(prefix string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path := strings.TrimPrefix(req.URL.Path, prefix)
+		// Alerts forwarded by another cluster member were limited when
+		// they were received. Only forwarding requests authenticated by
+		// the forward signer are exempt, not those merely carrying the
+		// forwarding header.
+		if req.Method != http.MethodPost ||
+			(path != "/api/v1/alerts" && path != "/api/v2/alerts") ||
+			shard.Forwarded(req.Context()) {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		api.mtx.RLock()
+		c := api.ingestionLimits
+		api.mtx.RUnlock()
+
+		if c == nil || (c.ClientRequestsPerSecond <= 0 && c.ClientAlertsPerSecond <= 0) {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		// The alerts are counted without validating them. Malformed
+		// requests are rejected by the handler.
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %s", err), http.StatusBadRequest)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		var alerts []json.RawMessage
+		_ = json.Unmarshal(body, &alerts)
+
+		id := auth.Identity(req, api.trustBasicAuth)
+		if id == "" {
+			id = req.RemoteAddr
+			if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+				id = host
+			}
+		}
+		if limit, wait := api.allow(id, len(alerts), c); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, fmt.Sprintf("rate limit of %s exceeded, retry in %s", limit, wait.Round(time.Millisecond)), http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// allow returns how long the client must wait before posting the given number
+// of alerts, and the exceeded limit, or zero if the client is within its rate
+// limits.
+func (api *API) allow(client string, alerts int, c *config.IngestionLimitsConfig) (string, time.Duration) {
+	limit, wait := api.rateLimiter.Allow(client, alerts, c)
+	if wait > 0 {
+		api.rateLimited.WithLabelValues(limit).Inc()
+		level.Debug(api.logger).Log("msg", "Request rate limited", "client", client, "limit", limit, "wait", wait)
+	}
+	return limit, wait
+}
+
+// admitRPC rejects the alerts posted to the gRPC API while the API is
+// draining or if their client exceeds its rate limits, like drainHandler and
+// rateLimitHandler.
+func (api *API) admitRPC(client string, alerts int) error {
+	api.mtx.RLock()
+	draining, c := api.draining, api.ingestionLimits
+	api.mtx.RUnlock()
+
+	if draining {
+		return status.Error(codes.Unavailable, "Alertmanager is shutting down")
+	}
+	if c == nil || (c.ClientRequestsPerSecond <= 0 && c.ClientAlertsPerSecond <= 0) {
+		return nil
+	}
+	if limit, wait := api.allow(client, alerts, c); wait > 0 {
+		return status.Errorf(codes.ResourceExhausted, "rate limit of %s exceeded, retry in %s", limit, wait.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// requiredRole returns the role required by a request with the given method
+// and path, relative to the route prefix.
+func requiredRole(method, path string) config.Role {
+	switch {
+	// Health probes and CORS preflight requests carry no credentials.
+	case path == "/-/healthy" || path == "/-/ready" || method == http.MethodOptions:
+		return config.RoleNone
+	case path == "/-/reload" || strings.HasPrefix(path, "/debug/"):
+		return config.RoleAdmin
+	// All gRPC requests use the POST method.
+	case strings.HasPrefix(path, rpc.ServicePath):
+		if strings.HasPrefix(strings.TrimPrefix(path, rpc.ServicePath), "Get") {
+			return config.RoleViewer
+		}
+		return config.RoleEditor
+	case method == http.MethodGet || method == http.MethodHead:
+		return config.RoleViewer
+	case strings.HasPrefix(path, "/api/v2/deadletter"):
+		return config.RoleAdmin
+	// Rendered notifications may include the secrets of the integrations.
+	case strings.HasPrefix(path, "/api/v2/receivers/") && strings.HasSuffix(path, "/render"):
+		return config.RoleAdmin
+	}
+	return config.RoleEditor
 }
 
 func (api *API) limitHandler(h http.Handler) http.Handler {
@@ -0,0 +1,278 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/alertmanager/api/auditlog"
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/api/limits"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/provider/shard"
+)
+
+func TestAuthorizeHandler(t *testing.T) {
+	api := &API{trustBasicAuth: true, logger: log.NewNopLogger()}
+	h := api.authorizeHandler("/alertmanager", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	status := func(method, path, user string) int {
+		req := httptest.NewRequest(method, "/alertmanager"+path, nil)
+		if user != "" {
+			req.SetBasicAuth(user, "")
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Everything is allowed without an authorization configuration.
+	require.Equal(t, http.StatusOK, status("POST", "/-/reload", ""))
+
+	api.authorization = &config.AuthorizationConfig{
+		Roles: map[string]config.Role{
+			"alice":      config.RoleAdmin,
+			"prometheus": config.RoleEditor,
+		},
+		DefaultRole: config.RoleViewer,
+	}
+
+	for _, tc := range []struct {
+		method, path, user string
+		expected           int
+	}{
+		{"GET", "/api/v2/alerts", "", http.StatusOK},
+		{"POST", "/api/v2/alerts", "", http.StatusForbidden},
+		{"POST", "/api/v2/alerts", "prometheus", http.StatusOK},
+		{"POST", "/api/v2/silences", "prometheus", http.StatusOK},
+		{"DELETE", "/api/v2/silence/foo", "bob", http.StatusForbidden},
+		{"POST", "/api/v2/deadletter/foo/replay", "prometheus", http.StatusForbidden},
+		{"DELETE", "/api/v2/deadletter/foo", "prometheus", http.StatusForbidden},
+		{"POST", "/api/v2/deadletter/foo/replay", "alice", http.StatusOK},
+		{"GET", "/api/v2/deadletters", "bob", http.StatusOK},
+		{"POST", "/api/v2/receivers/team/render", "prometheus", http.StatusForbidden},
+		{"POST", "/api/v2/receivers/team/render", "alice", http.StatusOK},
+		{"POST", "/-/reload", "prometheus", http.StatusForbidden},
+		{"POST", "/-/reload", "alice", http.StatusOK},
+		{"GET", "/debug/pprof/heap", "prometheus", http.StatusForbidden},
+		{"GET", "/debug/goroutines", "alice", http.StatusOK},
+		{"POST", "/alertmanagerpb.Alertmanager/GetAlerts", "", http.StatusOK},
+		{"POST", "/alertmanagerpb.Alertmanager/PostAlerts", "", http.StatusForbidden},
+		{"POST", "/alertmanagerpb.Alertmanager/PostAlerts", "prometheus", http.StatusOK},
+	} {
+		require.Equal(t, tc.expected, status(tc.method, tc.path, tc.user), "%s %s as %q", tc.method, tc.path, tc.user)
+	}
+
+	api.authorization = &config.AuthorizationConfig{DefaultRole: config.RoleNone}
+	require.Equal(t, http.StatusForbidden, status("GET", "/api/v2/alerts", ""))
+	require.Equal(t, http.StatusOK, status("GET", "/-/healthy", ""))
+	require.Equal(t, http.StatusOK, status("OPTIONS", "/api/v2/alerts", ""))
+}
+
+func TestRejectTenantHandler(t *testing.T) {
+	api := &API{}
+	h := api.rejectTenantHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	status := func(identity, tenant string) int {
+		req := httptest.NewRequest("GET", "/api/v1/alerts", nil)
+		req = req.WithContext(auth.WithIdentity(req.Context(), identity))
+		if tenant != "" {
+			req.Header.Set("X-Scope-OrgID", tenant)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// The header is ignored without a tenancy configuration.
+	require.Equal(t, http.StatusOK, status("", "a"))
+
+	api.tenancy = &config.TenancyConfig{
+		Header:  "X-Scope-OrgID",
+		Label:   "tenant",
+		Tenants: map[string]string{"prometheus-a": "a"},
+	}
+	require.Equal(t, http.StatusForbidden, status("", ""))
+	require.Equal(t, http.StatusForbidden, status("prometheus-a", ""))
+
+	// Only admins act across all tenants.
+	api.authorization = &config.AuthorizationConfig{Roles: map[string]config.Role{"alice": config.RoleAdmin}}
+	require.Equal(t, http.StatusOK, status("alice", ""))
+	require.Equal(t, http.StatusForbidden, status("alice", "a"))
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	api := &API{
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"limit"}),
+		rateLimiter: limits.NewRateLimiter(),
+		logger:      log.NewNopLogger(),
+		ingestionLimits: &config.IngestionLimitsConfig{
+			ClientRequestsPerSecond: 1,
+			ClientAlertsPerSecond:   2,
+		},
+	}
+	var body string
+	h := api.rateLimitHandler("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		body = string(b)
+	}))
+
+	post := func(path, addr, alerts string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(alerts))
+		req.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	// The handler reads the whole body.
+	w := post("/api/v2/alerts", "10.0.0.1:1234", `[{"labels":{"alertname":"a"}}]`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `[{"labels":{"alertname":"a"}}]`, body)
+
+	w = post("/api/v1/alerts", "10.0.0.1:5678", `[]`)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// Other clients and endpoints are not limited.
+	w = post("/api/v2/alerts", "10.0.0.2:1234", `[{}, {}, {}]`)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = post("/api/v2/silences", "10.0.0.1:1234", `{}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Clients can't escape their limits by setting the forwarding header,
+	// only requests authenticated as forwarded by a cluster member are
+	// exempt.
+	signer, err := shard.NewSigner(&shard.Config{Secret: "s3cr3t"})
+	require.NoError(t, err)
+	h = signer.Handler(h)
+	forward := func(sign bool) int {
+		req := httptest.NewRequest("POST", "/api/v2/alerts", strings.NewReader(`[]`))
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set(shard.Header, "true")
+		if sign {
+			signer.Sign(req, []byte(`[]`))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+	require.Equal(t, http.StatusTooManyRequests, forward(false))
+	require.Equal(t, http.StatusOK, forward(true))
+}
+
+func TestAdmitRPC(t *testing.T) {
+	api := &API{
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"limit"}),
+		rateLimiter: limits.NewRateLimiter(),
+		logger:      log.NewNopLogger(),
+	}
+	// Nothing is limited without ingestion limits.
+	require.NoError(t, api.admitRPC("10.0.0.1", 10))
+
+	api.ingestionLimits = &config.IngestionLimitsConfig{ClientAlertsPerSecond: 2}
+	require.NoError(t, api.admitRPC("10.0.0.1", 2))
+	require.Equal(t, codes.ResourceExhausted, status.Code(api.admitRPC("10.0.0.1", 1)))
+	require.NoError(t, api.admitRPC("prometheus", 1))
+
+	api.Drain()
+	require.Equal(t, codes.Unavailable, status.Code(api.admitRPC("prometheus", 1)))
+}
+
+func TestDrainHandler(t *testing.T) {
+	api := &API{logger: log.NewNopLogger()}
+	h := api.drainHandler("/alertmanager", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	post := func(path string) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", path, strings.NewReader(`[]`)))
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, post("/alertmanager/api/v2/alerts"))
+
+	api.Drain()
+	require.Equal(t, http.StatusServiceUnavailable, post("/alertmanager/api/v1/alerts"))
+	require.Equal(t, http.StatusServiceUnavailable, post("/alertmanager/api/v2/alerts"))
+	// Other requests are still served.
+	require.Equal(t, http.StatusOK, post("/alertmanager/api/v2/silences"))
+}
+
+func TestAuditHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "audit.log")
+	l, err := auditlog.Open(filename, nil)
+	require.NoError(t, err)
+
+	api := &API{auditLog: l, trustBasicAuth: true}
+	h := api.auditHandler("/alertmanager", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/alertmanager/api/v2/silence/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+
+	for _, tc := range []struct {
+		method, path, body string
+	}{
+		{"POST", "/api/v2/silences", `{"comment":"maintenance"}`},
+		{"GET", "/api/v2/silences", ""},
+		{"POST", "/api/v2/alerts", `[]`},
+		{"DELETE", "/api/v2/silence/missing", ""},
+		{"POST", "/api/v2/deadletter/foo/replay", ""},
+		{"POST", "/api/v2/receivers/team/render", `[]`},
+		{"POST", "/api/v2/receivers/team/test", ""},
+		{"POST", "/-/reload", ""},
+	} {
+		req := httptest.NewRequest(tc.method, "/alertmanager"+tc.path, strings.NewReader(tc.body))
+		req.SetBasicAuth("alice", "")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	require.NoError(t, l.Close())
+
+	b, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	var entries []auditlog.Entry
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var e auditlog.Entry
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		require.Equal(t, "alice", e.Actor)
+		require.False(t, e.Time.IsZero())
+		e.Time, e.Actor, e.RemoteAddr = time.Time{}, "", ""
+		entries = append(entries, e)
+	}
+	require.Equal(t, []auditlog.Entry{
+		{Operation: "silence.save", Method: "POST", Path: "/alertmanager/api/v2/silences", Status: 200, Digest: auditlog.Digest([]byte(`{"comment":"maintenance"}`))},
+		{Operation: "silence.delete", Method: "DELETE", Path: "/alertmanager/api/v2/silence/missing", Status: 404},
+		{Operation: "deadletter.replay", Method: "POST", Path: "/alertmanager/api/v2/deadletter/foo/replay", Status: 200},
+		{Operation: "receiver.test", Method: "POST", Path: "/alertmanager/api/v2/receivers/team/test", Status: 200},
+		{Operation: "config.reload", Method: "POST", Path: "/alertmanager/-/reload", Status: 200},
+	}, entries)
+}
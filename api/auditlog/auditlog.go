@@ -0,0 +1,116 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package auditlog records the state-changing operations made through the web
+// server, such as creating silences or reloading the configuration, to an
+// append-only file of JSON lines.
+package auditlog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// Entry is an operation recorded in the audit log.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// The kind of operation, e.g. silence.delete.
+	Operation string `json:"operation"`
+	// The identity of the client which made the operation, if known.
+	Actor      string `json:"actor,omitempty"`
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+	Method     string `json:"method,omitempty"`
+	Path       string `json:"path,omitempty"`
+	// The status code of the response.
+	Status int `json:"status,omitempty"`
+	// The SHA-256 digest of the request payload, if any.
+	Digest string `json:"digest,omitempty"`
+	// The error of a failed operation not made through the web server.
+	Error string `json:"error,omitempty"`
+}
+
+// Log writes entries to an audit log file.
+type Log struct {
+	logger log.Logger
+	now    func() time.Time
+
+	mtx sync.Mutex
+	w   io.WriteCloser
+}
+
+// Open returns a Log appending to the given file. The file is created if it
+// doesn't exist. Rotating the file requires truncating it in place.
+func Open(filename string, l log.Logger) (*Log, error) {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return newLog(f, l), nil
+}
+
+func newLog(w io.WriteCloser, l log.Logger) *Log {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	return &Log{
+		logger: l,
+		now:    time.Now,
+		w:      w,
+	}
+}
+
+// Record writes the entry to the log. The time is set if it is zero. Errors
+// are logged as there is nothing the caller could do about them.
+func (l *Log) Record(e Entry) {
+	if e.Time.IsZero() {
+		e.Time = l.now()
+	}
+	e.Time = e.Time.UTC()
+	b, err := json.Marshal(e)
+	if err != nil {
+		level.Error(l.logger).Log("msg", "Failed to encode audit log entry", "err", err)
+		return
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if _, err := l.w.Write(append(b, '\n')); err != nil {
+		level.Error(l.logger).Log("msg", "Failed to write audit log entry", "operation", e.Operation, "err", err)
+	}
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.w.Close()
+}
+
+// Digest returns the digest of a payload as recorded in entries. It is empty
+// for empty payloads.
+func Digest(payload []byte) string {
+	if len(payload) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auditlog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "audit.log")
+
+	l, err := Open(filename, nil)
+	require.NoError(t, err)
+	l.now = func() time.Time { return time.Date(2021, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)) }
+	l.Record(Entry{Operation: "config.reload", Actor: "SIGHUP"})
+	require.NoError(t, l.Close())
+
+	// Entries are appended to existing files.
+	l, err = Open(filename, nil)
+	require.NoError(t, err)
+	l.Record(Entry{
+		Time:      time.Date(2021, 6, 1, 10, 5, 0, 0, time.UTC),
+		Operation: "silence.delete",
+		Actor:     "alice",
+		Method:    "DELETE",
+		Path:      "/api/v2/silence/foo",
+		Status:    200,
+	})
+	require.NoError(t, l.Close())
+
+	b, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, `{"time":"2021-06-01T10:00:00Z","operation":"config.reload","actor":"SIGHUP"}
+{"time":"2021-06-01T10:05:00Z","operation":"silence.delete","actor":"alice","method":"DELETE","path":"/api/v2/silence/foo","status":200}
+`, string(b))
+}
+
+func TestDigest(t *testing.T) {
+	require.Equal(t, "", Digest(nil))
+	require.Equal(t, "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", Digest([]byte("foo")))
+}
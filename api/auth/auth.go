@@ -0,0 +1,281 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth authenticates the requests to the web server with bearer
+// tokens, client certificates and OpenID Connect.
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"gopkg.in/yaml.v2"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// TokenHandler serves the requests carrying one of the bearer tokens listed
+// in a file and rejects the others. The file is read again whenever it is
+// modified, so tokens can be rotated without a restart.
+type TokenHandler struct {
+	file    string
+	handler http.Handler
+	logger  log.Logger
+
+	mtx     sync.Mutex
+	modTime time.Time
+	size    int64
+	// tokens maps the SHA-256 hashes of the tokens to their names. Hashes
+	// are looked up so that the lookup doesn't leak tokens through timing.
+	tokens map[[sha256.Size]byte]string
+}
+
+// NewTokenHandler returns a new TokenHandler. The file holds one token per
+// line, optionally preceded by its name and a space. The name identifies the
+// clients of the token. Blank lines and lines starting with # are ignored.
+func NewTokenHandler(file string, h http.Handler, l log.Logger) (*TokenHandler, error) {
+	t := &TokenHandler{
+		file:    file,
+		handler: h,
+		logger:  l,
+	}
+	if _, err := t.load(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// load returns the tokens, reading the file again if it changed.
+func (t *TokenHandler) load() (map[[sha256.Size]byte]string, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	fi, err := os.Stat(t.file)
+	if err != nil {
+		return nil, err
+	}
+	if t.tokens != nil && fi.ModTime().Equal(t.modTime) && fi.Size() == t.size {
+		return t.tokens, nil
+	}
+
+	b, err := os.ReadFile(t.file)
+	if err != nil {
+		return nil, err
+	}
+	tokens := map[[sha256.Size]byte]string{}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var name string
+		if i := strings.LastIndexAny(line, " \t"); i >= 0 {
+			name, line = strings.TrimSpace(line[:i]), line[i+1:]
+		}
+		tokens[sha256.Sum256([]byte(line))] = name
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	t.tokens, t.modTime, t.size = tokens, fi.ModTime(), fi.Size()
+	return tokens, nil
+}
+
+// ServeHTTP implements http.Handler. Requests of users logged in with OpenID
+// Connect need no token.
+func (t *TokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if hasSession(r) {
+		t.handler.ServeHTTP(w, r)
+		return
+	}
+
+	tokens, err := t.load()
+	if err != nil {
+		level.Error(t.logger).Log("msg", "Unable to read bearer tokens", "err", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	const prefix = "Bearer "
+	if auth := r.Header.Get("Authorization"); len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+		if name, ok := tokens[sha256.Sum256([]byte(auth[len(prefix):]))]; ok {
+			if name != "" {
+				r = r.WithContext(WithIdentity(r.Context(), name))
+			}
+			t.handler.ServeHTTP(w, r)
+			return
+		}
+	}
+
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+// ClientCertHandler serves the requests authenticated with a client
+// certificate whose common name or one of its subject alternative names is
+// allowed, and rejects the others. The certificate is verified by the TLS
+// configuration of the web server, which must require client certificates.
+type ClientCertHandler struct {
+	allowed map[string]struct{}
+	handler http.Handler
+	logger  log.Logger
+}
+
+// NewClientCertHandler returns a new ClientCertHandler allowing the given
+// names.
+func NewClientCertHandler(names []string, h http.Handler, l log.Logger) *ClientCertHandler {
+	allowed := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		allowed[n] = struct{}{}
+	}
+	return &ClientCertHandler{
+		allowed: allowed,
+		handler: h,
+		logger:  l,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (c *ClientCertHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		http.Error(w, "a verified client certificate is required", http.StatusForbidden)
+		return
+	}
+	for _, n := range certNames(r.TLS.VerifiedChains[0][0]) {
+		if _, ok := c.allowed[n]; ok {
+			c.handler.ServeHTTP(w, r)
+			return
+		}
+	}
+	level.Debug(c.logger).Log("msg", "Client certificate not allowed", "subject", r.TLS.VerifiedChains[0][0].Subject)
+	http.Error(w, "client certificate not allowed", http.StatusForbidden)
+}
+
+// certNames returns the common name and the subject alternative names of the
+// certificate.
+func certNames(cert *x509.Certificate) []string {
+	names := []string{cert.Subject.CommonName}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, u := range cert.URIs {
+		names = append(names, u.String())
+	}
+	return names
+}
+
+// TLSPolicy rejects the requests received over TLS connections not allowed by
+// the TLS policy of the configuration. The parameters of the connections are
+// negotiated according to the TLS configuration of the web server, which
+// should match the policy.
+type TLSPolicy struct {
+	logger log.Logger
+
+	mtx    sync.RWMutex
+	policy *config.TLSPolicy
+}
+
+// NewTLSPolicy returns a new TLSPolicy, allowing any connection until updated.
+func NewTLSPolicy(l log.Logger) *TLSPolicy {
+	return &TLSPolicy{logger: l}
+}
+
+// Update sets the TLS policy from the configuration.
+func (t *TLSPolicy) Update(c *config.Config) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.policy = c.Global.TLSPolicy
+}
+
+// Handler returns a handler checking the TLS connections of the requests to
+// h. Plain HTTP requests aren't checked.
+func (t *TLSPolicy) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.mtx.RLock()
+		policy := t.policy
+		t.mtx.RUnlock()
+		if r.TLS != nil {
+			if err := policy.Check(r.TLS.Version, r.TLS.CipherSuite, 0); err != nil {
+				level.Debug(t.logger).Log("msg", "TLS connection not allowed", "remote_addr", r.RemoteAddr, "err", err)
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a context holding the identity of the client.
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity held by the context, which is
+// empty if it holds none.
+func IdentityFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(identityKey{}).(string)
+	return id
+}
+
+// Identity returns the identity of the client of the request: the name of its
+// bearer token, its basic authentication user if trustBasicAuth is set, or
+// the common name of its verified client certificate, in that order. Basic
+// authentication users can only be trusted if they are authenticated by the
+// web server. The identity is empty for anonymous clients.
+func Identity(r *http.Request, trustBasicAuth bool) string {
+	if id, ok := r.Context().Value(identityKey{}).(string); ok {
+		return id
+	}
+	if user, _, ok := r.BasicAuth(); ok && trustBasicAuth {
+		return user
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		return r.TLS.VerifiedChains[0][0].Subject.CommonName
+	}
+	return ""
+}
+
+// BasicAuthConfigured returns whether the web configuration file, as used by
+// the --web.config.file flag, authenticates basic authentication users.
+func BasicAuthConfigured(webConfigFile string) (bool, error) {
+	if webConfigFile == "" {
+		return false, nil
+	}
+	b, err := os.ReadFile(webConfigFile)
+	if err != nil {
+		return false, err
+	}
+	var c struct {
+		Users map[string]string `yaml:"basic_auth_users"`
+	}
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return false, err
+	}
+	return len(c.Users) > 0, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+func TestTokenHandler(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tokens")
+	require.NoError(t, os.WriteFile(file, []byte("# CI\nfoo\n\nprometheus bar\n"), 0o600))
+
+	var identity string
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = Identity(r, false)
+	})
+	h, err := NewTokenHandler(file, ok, log.NewNopLogger())
+	require.NoError(t, err)
+
+	status := func(auth string) int {
+		req := httptest.NewRequest("GET", "/api/v2/status", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, status("Bearer foo"))
+	require.Equal(t, "", identity)
+	require.Equal(t, http.StatusOK, status("bearer bar"))
+	require.Equal(t, "prometheus", identity)
+	require.Equal(t, http.StatusUnauthorized, status("Bearer prometheus bar"))
+	require.Equal(t, http.StatusUnauthorized, status(""))
+	require.Equal(t, http.StatusUnauthorized, status("Bearer baz"))
+	require.Equal(t, http.StatusUnauthorized, status("Bearer # CI"))
+	require.Equal(t, http.StatusUnauthorized, status("Basic Zm9vOmJhcg=="))
+
+	// Users logged in with OpenID Connect need no token.
+	req := httptest.NewRequest("GET", "/api/v2/status", nil)
+	req = req.WithContext(context.WithValue(req.Context(), sessionKey{}, &session{User: "alice"}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Rotated tokens are picked up without creating a new handler.
+	require.NoError(t, os.WriteFile(file, []byte("baz\n"), 0o600))
+	require.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(time.Minute)))
+	require.Equal(t, http.StatusUnauthorized, status("Bearer foo"))
+	require.Equal(t, http.StatusOK, status("Bearer baz"))
+
+	// Requests are rejected rather than let through if the file is gone.
+	require.NoError(t, os.Remove(file))
+	require.Equal(t, http.StatusInternalServerError, status("Bearer baz"))
+}
+
+func TestNewTokenHandlerMissingFile(t *testing.T) {
+	_, err := NewTokenHandler(filepath.Join(t.TempDir(), "tokens"), http.NotFoundHandler(), log.NewNopLogger())
+	require.Error(t, err)
+}
+
+func TestClientCertHandler(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := NewClientCertHandler([]string{"prometheus", "automation.example.com", "spiffe://example.com/ci"}, ok, log.NewNopLogger())
+
+	status := func(cert *x509.Certificate) int {
+		req := httptest.NewRequest("POST", "/api/v2/alerts", nil)
+		req.TLS = &tls.ConnectionState{}
+		if cert != nil {
+			req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	ci, err := url.Parse("spiffe://example.com/ci")
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, status(&x509.Certificate{Subject: pkix.Name{CommonName: "prometheus"}}))
+	require.Equal(t, http.StatusOK, status(&x509.Certificate{Subject: pkix.Name{CommonName: "bot"}, DNSNames: []string{"automation.example.com"}}))
+	require.Equal(t, http.StatusOK, status(&x509.Certificate{URIs: []*url.URL{ci}}))
+	require.Equal(t, http.StatusForbidden, status(&x509.Certificate{Subject: pkix.Name{CommonName: "grafana"}}))
+	require.Equal(t, http.StatusForbidden, status(nil))
+
+	// Plain HTTP requests carry no certificate at all.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/status", nil))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestTLSPolicy(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	p := NewTLSPolicy(log.NewNopLogger())
+	h := p.Handler(ok)
+
+	status := func(state *tls.ConnectionState) int {
+		req := httptest.NewRequest("GET", "/api/v2/status", nil)
+		req.TLS = state
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+	tls11 := &tls.ConnectionState{Version: tls.VersionTLS11, CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA}
+	tls13 := &tls.ConnectionState{Version: tls.VersionTLS13, CipherSuite: tls.TLS_AES_128_GCM_SHA256}
+
+	// Any connection is allowed until a policy is configured.
+	require.Equal(t, http.StatusOK, status(tls11))
+
+	conf, err := config.Load(`
+global:
+  tls_policy:
+    min_version: TLS12
+route:
+  receiver: default
+receivers:
+- name: default
+`)
+	require.NoError(t, err)
+	p.Update(conf)
+	require.Equal(t, http.StatusForbidden, status(tls11))
+	require.Equal(t, http.StatusOK, status(tls13))
+	// Plain HTTP requests aren't checked.
+	require.Equal(t, http.StatusOK, status(nil))
+}
+
+func TestIdentity(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v2/status", nil)
+	require.Equal(t, "", Identity(req, true))
+
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "prometheus"}}}},
+	}
+	require.Equal(t, "prometheus", Identity(req, true))
+
+	// Basic authentication users are only trusted if asked for.
+	req.SetBasicAuth("alice", "secret")
+	require.Equal(t, "prometheus", Identity(req, false))
+	require.Equal(t, "alice", Identity(req, true))
+
+	req = req.WithContext(WithIdentity(req.Context(), "ci"))
+	require.Equal(t, "ci", Identity(req, true))
+}
+
+func TestBasicAuthConfigured(t *testing.T) {
+	ok, err := BasicAuthConfigured("")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	file := filepath.Join(t.TempDir(), "web.yml")
+	require.NoError(t, os.WriteFile(file, []byte("tls_server_config:\n  cert_file: server.crt\n"), 0o600))
+	ok, err = BasicAuthConfigured(file)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, os.WriteFile(file, []byte("basic_auth_users:\n  alice: $2y$10$QOauhQNbBCuQDKes6eFzPeMqBSjb7Mr5DUmpZ/VcEd00UAV/LDeSi\n"), 0o600))
+	ok, err = BasicAuthConfigured(file)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,534 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+const (
+	sessionCookie = "alertmanager_session"
+	stateCookie   = "alertmanager_oidc_state"
+
+	oidcCallbackPath = "/-/oidc/callback"
+	oidcLogoutPath   = "/-/oidc/logout"
+
+	// How long a login may take at the provider.
+	loginTimeout = 10 * time.Minute
+)
+
+// OIDC authenticates the users of the web interface and API with an OpenID
+// Connect provider. Users without a session are redirected to the provider to
+// log in, and are given a session cookie once they logged in. Requests without
+// a session are rejected, except for the health probes and the requests
+// authenticated by other means which OIDC is told to trust.
+type OIDC struct {
+	externalURL *url.URL
+	// Whether requests carrying bearer tokens or basic authentication
+	// credentials are authenticated by other means.
+	trustBearerTokens bool
+	trustBasicAuth    bool
+	logger            log.Logger
+	now               func() time.Time
+
+	mtx        sync.Mutex
+	conf       *config.OIDCConfig
+	groupRoles map[string]config.Role
+	client     *http.Client
+	provider   *oidcProvider
+}
+
+// NewOIDC returns a new OIDC. Users are redirected back to the external URL of
+// the Alertmanager after logging in. It is disabled until it is updated with a
+// configuration setting up OpenID Connect.
+func NewOIDC(externalURL *url.URL, trustBearerTokens, trustBasicAuth bool, l log.Logger) *OIDC {
+	return &OIDC{
+		externalURL:       externalURL,
+		trustBearerTokens: trustBearerTokens,
+		trustBasicAuth:    trustBasicAuth,
+		logger:            l,
+		now:               time.Now,
+	}
+}
+
+// Update applies the OpenID Connect and authorization configuration.
+func (o *OIDC) Update(c *config.Config) error {
+	var (
+		client     *http.Client
+		groupRoles map[string]config.Role
+	)
+	if c.OIDC != nil {
+		var err error
+		client, err = commoncfg.NewClientFromConfig(*c.OIDC.HTTPConfig, "oidc")
+		if err != nil {
+			return err
+		}
+	}
+	if c.Authorization != nil {
+		groupRoles = c.Authorization.GroupRoles
+	}
+
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	// The provider is discovered again with the new configuration and HTTP
+	// client.
+	o.provider = nil
+	o.conf, o.groupRoles, o.client = c.OIDC, groupRoles, client
+	return nil
+}
+
+func (o *OIDC) config() (*config.OIDCConfig, map[string]config.Role, *http.Client) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	return o.conf, o.groupRoles, o.client
+}
+
+// Handler returns a handler authenticating the requests to h, whose paths
+// are relative to the route prefix.
+func (o *OIDC) Handler(routePrefix string, h http.Handler) http.Handler {
+	if routePrefix == "/" {
+		routePrefix = ""
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.serve(w, r, routePrefix, h)
+	})
+}
+
+func (o *OIDC) serve(w http.ResponseWriter, r *http.Request, routePrefix string, h http.Handler) {
+	conf, groupRoles, client := o.config()
+	if conf == nil {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	switch strings.TrimPrefix(r.URL.Path, routePrefix) {
+	case oidcCallbackPath:
+		o.callback(w, r, conf, groupRoles, client)
+		return
+	case oidcLogoutPath:
+		o.setCookie(w, sessionCookie, "", -1)
+		http.Redirect(w, r, o.url("/"), http.StatusFound)
+		return
+	case "/-/healthy", "/-/ready":
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	if s, err := o.session(r, conf); err == nil {
+		ctx := context.WithValue(WithIdentity(r.Context(), s.User), sessionKey{}, s)
+		h.ServeHTTP(w, r.WithContext(ctx))
+		return
+	}
+	if o.trusted(r) {
+		h.ServeHTTP(w, r)
+		return
+	}
+	// Users of the web interface are sent to the provider, the requests
+	// of other clients are rejected.
+	if r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html") {
+		o.login(w, r, conf, client)
+		return
+	}
+	http.Error(w, "login required", http.StatusUnauthorized)
+}
+
+// trusted returns whether the request is authenticated by other means.
+// Client certificates are verified by the web server.
+func (o *OIDC) trusted(r *http.Request) bool {
+	const prefix = "Bearer "
+	if auth := r.Header.Get("Authorization"); o.trustBearerTokens && len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+		return true
+	}
+	if _, _, ok := r.BasicAuth(); ok && o.trustBasicAuth {
+		return true
+	}
+	return r.TLS != nil && len(r.TLS.VerifiedChains) > 0
+}
+
+// url returns the external URL of the path relative to the route prefix.
+func (o *OIDC) url(p string) string {
+	u := *o.externalURL
+	u.Path = path.Join("/", u.Path, p)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u.String()
+}
+
+func (o *OIDC) setCookie(w http.ResponseWriter, name, value string, maxAge int) {
+	cookiePath := o.externalURL.Path
+	if cookiePath == "" {
+		cookiePath = "/"
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     cookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   o.externalURL.Scheme == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// loginState is kept in a cookie while the user logs in at the provider.
+type loginState struct {
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	ReturnTo string `json:"r"`
+	Expires  int64  `json:"e"`
+}
+
+// login redirects the user to the provider.
+func (o *OIDC) login(w http.ResponseWriter, r *http.Request, conf *config.OIDCConfig, client *http.Client) {
+	p, err := o.discover(r.Context(), conf, client)
+	if err != nil {
+		level.Error(o.logger).Log("msg", "OpenID Connect discovery failed", "issuer", conf.IssuerURL, "err", err)
+		http.Error(w, "identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+	state := loginState{
+		State:    randomString(),
+		Nonce:    randomString(),
+		ReturnTo: r.URL.RequestURI(),
+		Expires:  o.now().Add(loginTimeout).Unix(),
+	}
+	value, err := sign(state, sessionKeyOf(conf))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	o.setCookie(w, stateCookie, value, int(loginTimeout.Seconds()))
+
+	u, err := url.Parse(p.Endpoint().AuthURL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", conf.ClientID)
+	q.Set("redirect_uri", o.url(oidcCallbackPath))
+	q.Set("scope", strings.Join(conf.Scopes, " "))
+	q.Set("state", state.State)
+	q.Set("nonce", state.Nonce)
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// callback completes the login of a user redirected back by the provider.
+func (o *OIDC) callback(w http.ResponseWriter, r *http.Request, conf *config.OIDCConfig, groupRoles map[string]config.Role, client *http.Client) {
+	fail := func(status int, msg string, err error) {
+		level.Warn(o.logger).Log("msg", "OpenID Connect login failed", "reason", msg, "err", err)
+		http.Error(w, msg, status)
+	}
+
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		fail(http.StatusForbidden, "login failed at the identity provider: "+e, nil)
+		return
+	}
+	c, err := r.Cookie(stateCookie)
+	if err != nil {
+		fail(http.StatusBadRequest, "missing login state", err)
+		return
+	}
+	var state loginState
+	if err := verify(c.Value, sessionKeyOf(conf), &state); err != nil || o.now().Unix() > state.Expires {
+		fail(http.StatusBadRequest, "invalid or expired login state", err)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(state.State)) != 1 {
+		fail(http.StatusBadRequest, "login state mismatch", nil)
+		return
+	}
+
+	p, err := o.discover(r.Context(), conf, client)
+	if err != nil {
+		fail(http.StatusBadGateway, "identity provider unavailable", err)
+		return
+	}
+	rawToken, err := o.exchange(r.Context(), p, conf, client, q.Get("code"))
+	if err != nil {
+		fail(http.StatusBadGateway, "code exchange failed", err)
+		return
+	}
+	claims, err := p.verifyIDToken(r.Context(), rawToken)
+	if err != nil {
+		fail(http.StatusForbidden, "invalid ID token", err)
+		return
+	}
+	if nonce, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(nonce), []byte(state.Nonce)) != 1 {
+		fail(http.StatusForbidden, "invalid ID token", errors.New("nonce mismatch"))
+		return
+	}
+	user, _ := claims[conf.UsernameClaim].(string)
+	if user == "" {
+		fail(http.StatusForbidden, "invalid ID token", fmt.Errorf("missing claim %q", conf.UsernameClaim))
+		return
+	}
+
+	// Only the groups mapped to roles are kept to keep the cookie small.
+	s := session{
+		User:    user,
+		Expires: o.now().Add(time.Duration(conf.SessionDuration)).Unix(),
+	}
+	for _, g := range stringsClaim(claims[conf.GroupsClaim]) {
+		if _, ok := groupRoles[g]; ok {
+			s.Groups = append(s.Groups, g)
+		}
+	}
+	value, err := sign(s, sessionKeyOf(conf))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	o.setCookie(w, stateCookie, "", -1)
+	o.setCookie(w, sessionCookie, value, int(time.Duration(conf.SessionDuration).Seconds()))
+	level.Info(o.logger).Log("msg", "User logged in", "user", user, "groups", strings.Join(s.Groups, ","))
+
+	// Only local paths are followed to prevent open redirects.
+	returnTo := state.ReturnTo
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") {
+		returnTo = o.url("/")
+	}
+	http.Redirect(w, r, returnTo, http.StatusFound)
+}
+
+// session is the state of a logged in user, kept in a cookie.
+type session struct {
+	User    string   `json:"u"`
+	Groups  []string `json:"g,omitempty"`
+	Expires int64    `json:"e"`
+}
+
+type sessionKey struct{}
+
+// Groups returns the groups of the user logged in with OpenID Connect which
+// are mapped to roles.
+func Groups(r *http.Request) []string {
+	if s, ok := r.Context().Value(sessionKey{}).(*session); ok {
+		return s.Groups
+	}
+	return nil
+}
+
+// hasSession returns whether the request is authenticated by a session.
+func hasSession(r *http.Request) bool {
+	_, ok := r.Context().Value(sessionKey{}).(*session)
+	return ok
+}
+
+func (o *OIDC) session(r *http.Request, conf *config.OIDCConfig) (*session, error) {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil, err
+	}
+	var s session
+	if err := verify(c.Value, sessionKeyOf(conf), &s); err != nil {
+		return nil, err
+	}
+	if o.now().Unix() > s.Expires {
+		return nil, errors.New("session expired")
+	}
+	return &s, nil
+}
+
+// sessionKeyOf returns the key signing the cookies. It is derived from the
+// client credentials so that all Alertmanagers of a cluster accept the
+// sessions of each other, and logging out all users only requires changing
+// the client secret.
+func sessionKeyOf(conf *config.OIDCConfig) []byte {
+	sum := sha256.Sum256([]byte("alertmanager session\x00" + conf.ClientID + "\x00" + string(conf.ClientSecret)))
+	return sum[:]
+}
+
+// sign encodes the value and its HMAC.
+func sign(v interface{}, key []byte) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	return base64.RawURLEncoding.EncodeToString(b) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verify checks the HMAC of a signed value and decodes it.
+func verify(s string, key []byte, v interface{}) error {
+	i := strings.IndexByte(s, '.')
+	if i < 0 {
+		return errors.New("malformed value")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s[:i])
+	if err != nil {
+		return err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(s[i+1:])
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return errors.New("invalid signature")
+	}
+	return json.Unmarshal(b, v)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// stringsClaim returns the strings of a claim holding a string or a list of
+// strings.
+func stringsClaim(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		ss := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				ss = append(ss, s)
+			}
+		}
+		return ss
+	}
+	return nil
+}
+
+// oidcProvider is a discovered provider and the verifier of its ID tokens.
+type oidcProvider struct {
+	*oidc.Provider
+	verifier *oidc.IDTokenVerifier
+}
+
+// discover returns the provider, fetching its configuration if it is not
+// known yet. Its keys are fetched with the client when they are needed, and
+// again when a token is signed with an unknown key.
+func (o *OIDC) discover(ctx context.Context, conf *config.OIDCConfig, client *http.Client) (*oidcProvider, error) {
+	o.mtx.Lock()
+	p := o.provider
+	o.mtx.Unlock()
+	if p != nil {
+		return p, nil
+	}
+
+	issuer := strings.TrimSuffix(conf.IssuerURL.String(), "/")
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, client), issuer)
+	if err != nil {
+		return nil, err
+	}
+	p = &oidcProvider{
+		Provider: provider,
+		verifier: provider.Verifier(&oidc.Config{
+			ClientID:             conf.ClientID,
+			SupportedSigningAlgs: []string{oidc.RS256, oidc.ES256},
+			Now:                  o.now,
+		}),
+	}
+
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	if o.conf == conf {
+		o.provider = p
+	}
+	return p, nil
+}
+
+// exchange exchanges the authorization code for an ID token.
+func (o *OIDC) exchange(ctx context.Context, p *oidcProvider, conf *config.OIDCConfig, client *http.Client, code string) (string, error) {
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {o.url(oidcCallbackPath)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint().TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(conf.ClientID), url.QueryEscape(string(conf.ClientSecret)))
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := doJSON(client, req, &tokens); err != nil {
+		return "", err
+	}
+	if tokens.IDToken == "" {
+		return "", errors.New("no ID token returned")
+	}
+	return tokens.IDToken, nil
+}
+
+// verifyIDToken verifies the signature, issuer, audience and expiry of the
+// ID token and returns its claims. RS256 and ES256 signatures are supported.
+func (p *oidcProvider) verifyIDToken(ctx context.Context, rawToken string) (map[string]interface{}, error) {
+	token, err := p.verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]interface{}
+	if err := token.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func doJSON(client *http.Client, req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s %s: unexpected status %s", req.Method, req.URL, resp.Status)
+	}
+	return json.Unmarshal(b, v)
+}
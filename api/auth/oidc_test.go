@@ -0,0 +1,416 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// fakeProvider is an OpenID Connect provider issuing ID tokens for the
+// claims it is given.
+type fakeProvider struct {
+	*httptest.Server
+	t      *testing.T
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+	alg    string
+	// The ID of the RSA key, which changes when it is rotated.
+	rsaKid string
+	claims map[string]interface{}
+	// If set, token returns the ID token issued for the claims instead of
+	// sign, e.g. to forge it.
+	token func(claims map[string]interface{}) string
+	// The nonce of the last authorization request.
+	nonce string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p := &fakeProvider{t: t, rsaKey: rsaKey, ecKey: ecKey, alg: "RS256", rsaKid: "rsa"}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/auth",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{"kty": "RSA", "kid": p.rsaKid, "n": b64(p.rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(p.rsaKey.E)).Bytes())},
+				{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, _ := r.BasicAuth(); id != "alertmanager" || secret != "secret" || r.FormValue("code") != "code" {
+			http.Error(w, "invalid client or code", http.StatusUnauthorized)
+			return
+		}
+		claims := map[string]interface{}{
+			"iss":   p.URL,
+			"aud":   "alertmanager",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": p.nonce,
+		}
+		for k, v := range p.claims {
+			claims[k] = v
+		}
+		token := p.sign
+		if p.token != nil {
+			token = p.token
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": token(claims)})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *fakeProvider) sign(claims map[string]interface{}) string {
+	kid := map[string]string{"RS256": p.rsaKid, "ES256": "ec"}[p.alg]
+	return p.signWith(map[string]string{"alg": p.alg, "kid": kid}, claims, p.rsaKey)
+}
+
+// signWith signs the claims with the header, using the RSA key for RS256.
+func (p *fakeProvider) signWith(header map[string]string, claims map[string]interface{}, rsaKey *rsa.PrivateKey) string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	signed := p.unsigned(header, claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var (
+		sig []byte
+		err error
+	)
+	switch header["alg"] {
+	case "RS256":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+		require.NoError(p.t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, p.ecKey, digest[:])
+		require.NoError(p.t, err)
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case "HS256":
+		// The public RSA key is used as the secret, as in algorithm
+		// confusion attacks.
+		mac := hmac.New(sha256.New, x509.MarshalPKCS1PublicKey(&p.rsaKey.PublicKey))
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	}
+	return signed + "." + b64(sig)
+}
+
+// unsigned returns the encoded header and claims of a token.
+func (p *fakeProvider) unsigned(header map[string]string, claims map[string]interface{}) string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	h, err := json.Marshal(header)
+	require.NoError(p.t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(p.t, err)
+	return b64(h) + "." + b64(payload)
+}
+
+func TestOIDC(t *testing.T) {
+	p := newFakeProvider(t)
+	defer p.Close()
+
+	externalURL, err := url.Parse("http://alertmanager.example.com/am")
+	require.NoError(t, err)
+	issuerURL, err := url.Parse(p.URL)
+	require.NoError(t, err)
+
+	o := NewOIDC(externalURL, true, false, log.NewNopLogger())
+	var (
+		identity string
+		groups   []string
+	)
+	h := o.Handler("/am", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, groups = Identity(r, false), Groups(r)
+	}))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		identity, groups = "", nil
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	// Requests are served as they are until OpenID Connect is configured.
+	require.Equal(t, http.StatusOK, serve(httptest.NewRequest("POST", "/am/api/v2/alerts", nil)).Code)
+
+	require.NoError(t, o.Update(&config.Config{
+		OIDC: &config.OIDCConfig{
+			IssuerURL:       &config.URL{URL: issuerURL},
+			ClientID:        "alertmanager",
+			ClientSecret:    "secret",
+			Scopes:          []string{"openid", "email"},
+			UsernameClaim:   "email",
+			GroupsClaim:     "groups",
+			SessionDuration: model.Duration(time.Hour),
+			HTTPConfig:      &commoncfg.HTTPClientConfig{},
+		},
+		Authorization: &config.AuthorizationConfig{
+			GroupRoles: map[string]config.Role{"sre": config.RoleAdmin},
+		},
+	}))
+
+	require.Equal(t, http.StatusUnauthorized, serve(httptest.NewRequest("POST", "/am/api/v2/alerts", nil)).Code)
+	require.Equal(t, http.StatusOK, serve(httptest.NewRequest("GET", "/am/-/healthy", nil)).Code)
+	// Bearer tokens are checked by the token handler.
+	req := httptest.NewRequest("POST", "/am/api/v2/alerts", nil)
+	req.Header.Set("Authorization", "Bearer foo")
+	require.Equal(t, http.StatusOK, serve(req).Code)
+
+	login := func() *http.Cookie {
+		// Browsers are redirected to the provider.
+		req := httptest.NewRequest("GET", "/am/", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml")
+		w := serve(req)
+		require.Equal(t, http.StatusFound, w.Code)
+		loc, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, p.URL+"/auth", loc.Scheme+"://"+loc.Host+loc.Path)
+		q := loc.Query()
+		require.Equal(t, "http://alertmanager.example.com/am/-/oidc/callback", q.Get("redirect_uri"))
+		require.Equal(t, "openid email", q.Get("scope"))
+		p.nonce = q.Get("nonce")
+
+		// The provider redirects the user back.
+		req = httptest.NewRequest("GET", "/am/-/oidc/callback?code=code&state="+url.QueryEscape(q.Get("state")), nil)
+		for _, c := range w.Result().Cookies() {
+			req.AddCookie(c)
+		}
+		w = serve(req)
+		require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+		require.Equal(t, "/am/", w.Header().Get("Location"))
+		for _, c := range w.Result().Cookies() {
+			if c.Name == sessionCookie {
+				require.Equal(t, "/am", c.Path)
+				return c
+			}
+		}
+		t.Fatal("no session cookie set")
+		return nil
+	}
+
+	for _, alg := range []string{"RS256", "ES256"} {
+		p.alg = alg
+		p.claims = map[string]interface{}{"email": "alice@example.com", "groups": []string{"dev", "sre"}}
+		session := login()
+
+		req = httptest.NewRequest("POST", "/am/api/v2/silences", nil)
+		req.AddCookie(session)
+		require.Equal(t, http.StatusOK, serve(req).Code)
+		require.Equal(t, "alice@example.com", identity)
+		// Only the groups mapped to roles are kept.
+		require.Equal(t, []string{"sre"}, groups)
+	}
+
+	// Sessions expire.
+	session := login()
+	o.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	req = httptest.NewRequest("POST", "/am/api/v2/silences", nil)
+	req.AddCookie(session)
+	require.Equal(t, http.StatusUnauthorized, serve(req).Code)
+	o.now = time.Now
+
+	// Tampered sessions are rejected.
+	session.Value = session.Value[1:]
+	req = httptest.NewRequest("POST", "/am/api/v2/silences", nil)
+	req.AddCookie(session)
+	require.Equal(t, http.StatusUnauthorized, serve(req).Code)
+
+	// The state must match.
+	req = httptest.NewRequest("GET", "/am/-/oidc/callback?code=code&state=forged", nil)
+	require.Equal(t, http.StatusBadRequest, serve(req).Code)
+
+	// ID tokens without the username claim are rejected.
+	p.claims = map[string]interface{}{"sub": "1234"}
+	req = httptest.NewRequest("GET", "/am/", nil)
+	req.Header.Set("Accept", "text/html")
+	w := serve(req)
+	loc, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	p.nonce = loc.Query().Get("nonce")
+	req = httptest.NewRequest("GET", "/am/-/oidc/callback?code=code&state="+url.QueryEscape(loc.Query().Get("state")), nil)
+	for _, c := range w.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	require.Equal(t, http.StatusForbidden, serve(req).Code)
+}
+
+func TestOIDCIDTokenVerification(t *testing.T) {
+	p := newFakeProvider(t)
+	defer p.Close()
+
+	externalURL, err := url.Parse("http://alertmanager.example.com/")
+	require.NoError(t, err)
+	issuerURL, err := url.Parse(p.URL)
+	require.NoError(t, err)
+	o := NewOIDC(externalURL, false, false, log.NewNopLogger())
+	require.NoError(t, o.Update(&config.Config{
+		OIDC: &config.OIDCConfig{
+			IssuerURL:       &config.URL{URL: issuerURL},
+			ClientID:        "alertmanager",
+			ClientSecret:    "secret",
+			Scopes:          []string{"openid", "email"},
+			UsernameClaim:   "email",
+			SessionDuration: model.Duration(time.Hour),
+			HTTPConfig:      &commoncfg.HTTPClientConfig{},
+		},
+	}))
+	h := o.Handler("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// login returns the status of the callback completing a login.
+	login := func() int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", "text/html")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		loc, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		p.nonce = loc.Query().Get("nonce")
+
+		req = httptest.NewRequest("GET", "/-/oidc/callback?code=code&state="+url.QueryEscape(loc.Query().Get("state")), nil)
+		for _, c := range w.Result().Cookies() {
+			req.AddCookie(c)
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+	p.claims = map[string]interface{}{"email": "alice@example.com"}
+	require.Equal(t, http.StatusFound, login())
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		name  string
+		token func(claims map[string]interface{}) string
+		code  int
+	}{
+		{
+			name: "signed by another key",
+			token: func(claims map[string]interface{}) string {
+				return p.signWith(map[string]string{"alg": "RS256", "kid": "rsa"}, claims, otherKey)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "claims changed after signing",
+			token: func(claims map[string]interface{}) string {
+				signed := strings.Split(p.sign(claims), ".")
+				claims["email"] = "mallory@example.com"
+				forged := strings.Split(p.unsigned(map[string]string{}, claims), ".")
+				return signed[0] + "." + forged[1] + "." + signed[2]
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "unsigned",
+			token: func(claims map[string]interface{}) string {
+				return p.unsigned(map[string]string{"alg": "none", "kid": "rsa"}, claims) + "."
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "signed with the public key as HMAC secret",
+			token: func(claims map[string]interface{}) string {
+				return p.signWith(map[string]string{"alg": "HS256", "kid": "rsa"}, claims, nil)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "issued for another client",
+			token: func(claims map[string]interface{}) string {
+				claims["aud"] = "grafana"
+				return p.sign(claims)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "issued by another provider",
+			token: func(claims map[string]interface{}) string {
+				claims["iss"] = "https://idp.example.com"
+				return p.sign(claims)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "expired",
+			token: func(claims map[string]interface{}) string {
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return p.sign(claims)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			// Clocks running slightly behind the clock of the provider
+			// are tolerated.
+			name: "not valid before a few seconds",
+			token: func(claims map[string]interface{}) string {
+				claims["nbf"] = time.Now().Add(30 * time.Second).Unix()
+				return p.sign(claims)
+			},
+			code: http.StatusFound,
+		},
+		{
+			name: "not valid before minutes",
+			token: func(claims map[string]interface{}) string {
+				claims["nbf"] = time.Now().Add(5 * time.Minute).Unix()
+				return p.sign(claims)
+			},
+			code: http.StatusForbidden,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p.token = tc.token
+			defer func() { p.token = nil }()
+			require.Equal(t, tc.code, login())
+		})
+	}
+
+	// Rotated keys are fetched again.
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p.rsaKey, p.rsaKid = newKey, "rsa-2"
+	require.Equal(t, http.StatusFound, login())
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/notify/webhook"
+)
+
+// structuredCloudEvent is a CloudEvent in the structured content mode.
+type structuredCloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// cloudEventsHandler accepts alerts posted to API v2 as CloudEvents, in the
+// structured or binary content mode. The data of the events are either the
+// alerts of the API, or the messages of webhooks sending CloudEvents.
+func cloudEventsHandler(prefix string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.URL.Path != prefix+"/api/v2/alerts" {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		var ev structuredCloudEvent
+		mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		switch {
+		case mediaType == webhook.CloudEventsContentType:
+			if err := json.NewDecoder(req.Body).Decode(&ev); err != nil {
+				http.Error(w, fmt.Sprintf("invalid CloudEvent: %v", err), http.StatusBadRequest)
+				return
+			}
+		case req.Header.Get("ce-specversion") != "":
+			b, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ev = structuredCloudEvent{
+				SpecVersion:     req.Header.Get("ce-specversion"),
+				ID:              req.Header.Get("ce-id"),
+				Source:          req.Header.Get("ce-source"),
+				Type:            req.Header.Get("ce-type"),
+				DataContentType: req.Header.Get("Content-Type"),
+				Data:            b,
+			}
+		default:
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		data, err := cloudEventAlerts(ev)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+		req.ContentLength = int64(len(data))
+		req.Header.Set("Content-Type", "application/json")
+		h.ServeHTTP(w, req)
+	})
+}
+
+// cloudEventAlerts validates a CloudEvent and returns the alerts of its data
+// in the format of API v2.
+func cloudEventAlerts(ev structuredCloudEvent) ([]byte, error) {
+	if ev.SpecVersion != webhook.CloudEventsSpecVersion {
+		return nil, fmt.Errorf("unsupported CloudEvents specversion %q", ev.SpecVersion)
+	}
+	if ev.ID == "" || ev.Source == "" || ev.Type == "" {
+		return nil, fmt.Errorf("CloudEvent without id, source or type")
+	}
+	if ev.DataContentType != "" {
+		if mediaType, _, _ := mime.ParseMediaType(ev.DataContentType); mediaType != "application/json" {
+			return nil, fmt.Errorf("unsupported CloudEvent datacontenttype %q", ev.DataContentType)
+		}
+	}
+	if len(ev.Data) == 0 {
+		return nil, fmt.Errorf("CloudEvent without data")
+	}
+	if ev.Type != webhook.CloudEventsType {
+		return ev.Data, nil
+	}
+
+	var msg webhook.Message
+	if err := json.Unmarshal(ev.Data, &msg); err != nil {
+		return nil, fmt.Errorf("invalid webhook message: %v", err)
+	}
+	if msg.Data == nil {
+		return nil, fmt.Errorf("webhook message without alerts")
+	}
+	now := time.Now()
+	alerts := make(models.PostableAlerts, 0, len(msg.Alerts))
+	for _, a := range msg.Alerts {
+		pa := &models.PostableAlert{
+			Alert: models.Alert{
+				Labels:       models.LabelSet{},
+				GeneratorURL: strfmt.URI(a.GeneratorURL),
+			},
+			Annotations: models.LabelSet{},
+			StartsAt:    strfmt.DateTime(a.StartsAt),
+			EndsAt:      strfmt.DateTime(a.EndsAt),
+		}
+		for k, v := range a.Labels {
+			pa.Labels[k] = v
+		}
+		for k, v := range a.Annotations {
+			pa.Annotations[k] = v
+		}
+		// Resolved alerts end when they are received if their end is unknown.
+		if a.Status == string(model.AlertResolved) && a.EndsAt.IsZero() {
+			pa.EndsAt = strfmt.DateTime(now)
+		}
+		alerts = append(alerts, pa)
+	}
+	return json.Marshal(alerts)
+}
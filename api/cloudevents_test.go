@@ -0,0 +1,121 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+func TestCloudEventsHandler(t *testing.T) {
+	var (
+		contentType string
+		body        string
+	)
+	h := cloudEventsHandler("/alertmanager", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+
+	post := func(path, body string, header map[string]string) int {
+		req := httptest.NewRequest("POST", "/alertmanager"+path, strings.NewReader(body))
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	const alerts = `[{"labels":{"alertname":"HighLatency"}}]`
+
+	// Plain requests are left untouched.
+	require.Equal(t, http.StatusOK, post("/api/v2/alerts", alerts, map[string]string{"Content-Type": "application/json"}))
+	require.Equal(t, alerts, body)
+
+	// Structured content mode.
+	require.Equal(t, http.StatusOK, post("/api/v2/alerts", `{
+		"specversion": "1.0",
+		"id": "1",
+		"source": "http://prometheus",
+		"type": "io.prometheus.alerts",
+		"data": `+alerts+`
+	}`, map[string]string{"Content-Type": "application/cloudevents+json; charset=utf-8"}))
+	require.Equal(t, "application/json", contentType)
+	require.Equal(t, alerts, body)
+
+	// Binary content mode.
+	body = ""
+	require.Equal(t, http.StatusOK, post("/api/v2/alerts", alerts, map[string]string{
+		"Content-Type":   "application/json",
+		"ce-specversion": "1.0",
+		"ce-id":          "1",
+		"ce-source":      "http://prometheus",
+		"ce-type":        "io.prometheus.alerts",
+	}))
+	require.Equal(t, alerts, body)
+
+	// The notifications of webhooks are converted to alerts.
+	require.Equal(t, http.StatusOK, post("/api/v2/alerts", `{
+		"specversion": "1.0",
+		"id": "1",
+		"source": "http://am",
+		"type": "io.prometheus.alertmanager.notification",
+		"datacontenttype": "application/json",
+		"data": {
+			"version": "4",
+			"status": "resolved",
+			"alerts": [{
+				"status": "resolved",
+				"labels": {"alertname": "HighLatency"},
+				"annotations": {"summary": "Latency is high"},
+				"startsAt": "2021-06-01T10:00:00Z",
+				"endsAt": "2021-06-01T11:00:00Z",
+				"generatorURL": "http://prometheus/graph"
+			}]
+		}
+	}`, map[string]string{"Content-Type": "application/cloudevents+json"}))
+	var posted models.PostableAlerts
+	require.NoError(t, json.Unmarshal([]byte(body), &posted))
+	require.Len(t, posted, 1)
+	require.Equal(t, models.LabelSet{"alertname": "HighLatency"}, posted[0].Labels)
+	require.Equal(t, models.LabelSet{"summary": "Latency is high"}, posted[0].Annotations)
+	require.Equal(t, "2021-06-01T11:00:00.000Z", posted[0].EndsAt.String())
+	require.Equal(t, "http://prometheus/graph", posted[0].GeneratorURL.String())
+
+	// Invalid events are rejected.
+	for _, ev := range []string{
+		`{"specversion": "0.3", "id": "1", "source": "s", "type": "t", "data": []}`,
+		`{"specversion": "1.0", "source": "s", "type": "t", "data": []}`,
+		`{"specversion": "1.0", "id": "1", "source": "s", "type": "t", "datacontenttype": "text/plain", "data": []}`,
+		`{"specversion": "1.0", "id": "1", "source": "s", "type": "t"}`,
+		`{"specversion": "1.0", "id": "1", "source": "s", "type": "io.prometheus.alertmanager.notification", "data": []}`,
+	} {
+		require.Equal(t, http.StatusBadRequest, post("/api/v2/alerts", ev, map[string]string{"Content-Type": "application/cloudevents+json"}), ev)
+	}
+
+	// Other endpoints are left untouched.
+	body = ""
+	require.Equal(t, http.StatusOK, post("/api/v2/silences", "{}", map[string]string{"Content-Type": "application/cloudevents+json"}))
+	require.Equal(t, "{}", body)
+}
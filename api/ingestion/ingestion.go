@@ -0,0 +1,230 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ingestion prepares the alerts received by the Alertmanager, from
+// its APIs or from a program embedding it, and stores them within the
+// ingestion limits of the configuration.
+package ingestion
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/api/annotate"
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/api/limits"
+	"github.com/prometheus/alertmanager/api/metrics"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/relabel"
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/provider/shard"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// ReasonUnrouted is the reason for rejecting the alerts matching no route
+// when the root route rejects unrouted alerts.
+const ReasonUnrouted = "unrouted"
+
+// ErrTooManyAlerts is wrapped by the error of a Result when alerts are
+// rejected because the limit of active alerts is reached.
+var ErrTooManyAlerts = errors.New("the limit of active alerts is reached")
+
+// Options configures the ingestion of alerts.
+type Options struct {
+	// The configuration whose resolve timeout, label validation, alert
+	// relabeling, annotation templates and ingestion limits are applied.
+	Config *config.Config
+	// The routing tree of the configuration, which may override the
+	// resolve timeout and reject the alerts matching no route.
+	Route *dispatch.Route
+	// Alerts stores the alerts.
+	Alerts provider.Alerts
+	// Metrics counts the received alerts. It must not be nil.
+	Metrics *metrics.Alerts
+	// Scope, if set, is called with every alert after it was relabeled,
+	// e.g. to set its tenant.
+	Scope func(*types.Alert)
+	// Local alerts are stored without being forwarded to their owner in
+	// the cluster, as they were forwarded by another member.
+	Local bool
+}
+
+// Result is the outcome of the ingestion of alerts.
+type Result struct {
+	// The number of alerts stored.
+	Accepted int
+	// The number of alerts rejected by the limit of active alerts.
+	Rejected int
+	// The errors of the alerts rejected for being invalid or for exceeding
+	// the size limits.
+	Invalid types.MultiError
+}
+
+// Err returns an error wrapping ErrTooManyAlerts if alerts were rejected by
+// the limit of active alerts, the validation errors if alerts were invalid,
+// and nil otherwise.
+func (r *Result) Err() error {
+	if r.Rejected > 0 {
+		return fmt.Errorf("%d alerts rejected, %w", r.Rejected, ErrTooManyAlerts)
+	}
+	if r.Invalid.Len() > 0 {
+		return &r.Invalid
+	}
+	return nil
+}
+
+// Insert defaults the timestamps of the alerts, relabels, annotates, scopes
+// and validates them, and stores the valid ones within the ingestion limits.
+// It makes a best effort to store all valid alerts. The error is only set if
+// the alerts couldn't be stored.
+func Insert(alerts []*types.Alert, o Options) (*Result, error) {
+	var (
+		now            = time.Now()
+		c              = o.Config
+		resolveTimeout = time.Duration(c.Global.ResolveTimeout)
+		validate       = Validator(c.Global.LabelValidation)
+		m              = o.Metrics
+		res            = &Result{}
+	)
+
+	for _, a := range alerts {
+		Default(a, now, resolveTimeout)
+		if a.EndsAt.After(now) {
+			m.Firing().Inc()
+		} else {
+			m.Resolved().Inc()
+		}
+	}
+
+	valid := make([]*types.Alert, 0, len(alerts))
+	for _, a := range alerts {
+		RemoveEmptyLabels(a.Labels)
+
+		if a.Labels = relabel.Process(a.Labels, c.AlertRelabelConfigs...); a.Labels == nil {
+			m.Dropped().Inc()
+			continue
+		}
+		m.AnnotationFailures().Add(float64(annotate.Apply(a, c.AnnotationTemplates)))
+		// The tenant is set after relabeling so that it can't be changed.
+		if o.Scope != nil {
+			o.Scope(a)
+		}
+		// Routes may override the global resolve timeout. They are matched
+		// against the relabeled labels.
+		if a.Timeout && o.Route != nil {
+			if d := o.Route.ResolveTimeout(a.RoutingLabels()); d > 0 {
+				a.EndsAt = now.Add(d)
+			}
+		}
+
+		if err := validate(a); err != nil {
+			res.Invalid.Add(err)
+			m.Invalid().Inc()
+			continue
+		}
+		if limits.TruncateAnnotations(a, c.IngestionLimits) > 0 {
+			m.Truncated().Inc()
+		}
+		if reason, err := limits.CheckSize(a, c.IngestionLimits); err != nil {
+			res.Invalid.Add(err)
+			m.Rejected(reason).Inc()
+			continue
+		}
+		if o.Route != nil && o.Route.Unrouted == config.UnroutedReject && o.Route.IsUnrouted(o.Route.MatchAlert(a)) {
+			res.Invalid.Add(fmt.Errorf("%s: alert matches no route", a.Name()))
+			m.Rejected(ReasonUnrouted).Inc()
+			continue
+		}
+		valid = append(valid, a)
+	}
+	valid, res.Rejected = limits.Admit(valid, o.Alerts, c.IngestionLimits)
+	m.Rejected(limits.ReasonMaxActiveAlerts).Add(float64(res.Rejected))
+
+	put := o.Alerts.Put
+	if p, ok := o.Alerts.(*shard.Alerts); ok && o.Local {
+		put = p.PutLocal
+	}
+	if err := put(valid...); err != nil {
+		return res, err
+	}
+	res.Accepted = len(valid)
+	return res, nil
+}
+
+// Default sets the update time of the alert to now, its start time to now
+// or its end time if it has none, and its end time to the resolve timeout
+// from now if it has none, in which case the alert is resolved unless it is
+// updated in time.
+func Default(a *types.Alert, now time.Time, resolveTimeout time.Duration) {
+	a.UpdatedAt = now
+	if a.StartsAt.IsZero() {
+		if a.EndsAt.IsZero() {
+			a.StartsAt = now
+		} else {
+			a.StartsAt = a.EndsAt
+		}
+	}
+	if a.EndsAt.IsZero() {
+		a.Timeout = true
+		a.EndsAt = now.Add(resolveTimeout)
+	}
+}
+
+// RemoveEmptyLabels removes the labels with empty values, which are
+// equivalent to missing labels.
+func RemoveEmptyLabels(ls model.LabelSet) {
+	for k, v := range ls {
+		if v == "" {
+			delete(ls, k)
+		}
+	}
+}
+
+// Validator returns the function validating alerts with the label
+// validation scheme.
+func Validator(v config.LabelValidation) func(*types.Alert) error {
+	if v == config.LabelValidationUTF8 {
+		return (*types.Alert).ValidateUTF8
+	}
+	return (*types.Alert).Validate
+}
+
+// SetSource records the address and the identity of the client which sent
+// the alerts: its remote address and its verified identity. Alerts forwarded
+// by another cluster member carry those of the client which sent them to the
+// member in headers, which are only trusted if the forward signer
+// authenticated the request.
+func SetSource(req *http.Request, alerts []*types.Alert) {
+	var address, identity string
+	if shard.Forwarded(req.Context()) {
+		address = req.Header.Get(shard.SourceAddressHeader)
+		identity = req.Header.Get(shard.SourceIdentityHeader)
+	} else {
+		address = req.RemoteAddr
+		if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+			address = host
+		}
+		// The identity is resolved by the authorization handler.
+		identity = auth.Identity(req, false)
+	}
+	for _, a := range alerts {
+		a.Source.Address = address
+		a.Source.Identity = identity
+	}
+}
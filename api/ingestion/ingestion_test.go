@@ -0,0 +1,139 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/api/metrics"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestInsert(t *testing.T) {
+	conf, err := config.Load(`
+global:
+  resolve_timeout: 5m
+route:
+  receiver: team
+  unrouted:
+    action: reject
+  routes:
+  - receiver: team
+    matchers: ['__source__="staging"']
+    resolve_timeout: 1h
+  - receiver: team
+    matchers: ['alertname=~".+"']
+receivers:
+- name: team
+alert_relabel_configs:
+- source_labels: [drop]
+  regex: "true"
+  action: drop
+ingestion_limits:
+  max_active_alerts: 3
+  max_label_bytes: 64
+`)
+	require.NoError(t, err)
+
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	var scoped []model.LabelSet
+	opts := Options{
+		Config:  conf,
+		Route:   dispatch.NewRoute(conf.Route, nil),
+		Alerts:  alerts,
+		Metrics: metrics.NewAlerts("test", nil),
+		Scope:   func(a *types.Alert) { scoped = append(scoped, a.Labels) },
+	}
+
+	staging := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Staging", "empty": ""}}}
+	staging.Source.Name = "staging"
+	production := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Production"}}}
+	before := time.Now()
+	res, err := Insert([]*types.Alert{
+		staging,
+		production,
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Dropped", "drop": "true"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"instance": "unrouted"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": model.LabelValue(strings.Repeat("x", 64))}}},
+		{Alert: model.Alert{Labels: model.LabelSet{}}},
+	}, opts)
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Accepted)
+	require.Equal(t, 0, res.Rejected)
+	require.Equal(t, 3, res.Invalid.Len())
+	require.Len(t, scoped, 5)
+
+	// The empty labels are removed and the routes matching the source of
+	// the alert override the resolve timeout.
+	a, err := alerts.Get(model.LabelSet{"alertname": "Staging"}.Fingerprint())
+	require.NoError(t, err)
+	require.True(t, a.Timeout)
+	require.False(t, a.StartsAt.Before(before))
+	require.Equal(t, time.Hour, a.EndsAt.Sub(a.UpdatedAt))
+	a, err = alerts.Get(model.LabelSet{"alertname": "Production"}.Fingerprint())
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Minute, a.EndsAt.Sub(a.UpdatedAt))
+
+	// New alerts are rejected once the limit of active alerts is reached,
+	// while updates are accepted.
+	res, err = Insert([]*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Production"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Third"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "Fourth"}}},
+	}, opts)
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Accepted)
+	require.Equal(t, 1, res.Rejected)
+	require.True(t, errors.Is(res.Err(), ErrTooManyAlerts))
+}
+
+func TestResultErr(t *testing.T) {
+	require.NoError(t, (&Result{Accepted: 1}).Err())
+
+	res := &Result{}
+	res.Invalid.Add(errors.New("invalid"))
+	require.EqualError(t, res.Err(), "invalid")
+
+	res.Rejected = 2
+	require.EqualError(t, res.Err(), "2 alerts rejected, the limit of active alerts is reached")
+}
+
+func TestSetSource(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/v2/alerts", nil)
+	req.RemoteAddr = "10.0.0.1:4321"
+	req = req.WithContext(auth.WithIdentity(req.Context(), "prometheus"))
+
+	a := &types.Alert{}
+	a.Source.Name = "staging"
+	SetSource(req, []*types.Alert{a})
+	require.Equal(t, types.Source{Name: "staging", Address: "10.0.0.1", Identity: "prometheus"}, a.Source)
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package limits enforces the ingestion limits of the alert APIs.
+package limits
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Reasons for rejecting alerts. They are used as label values of the
+// rejected alerts metric.
+const (
+	ReasonMaxActiveAlerts    = "max_active_alerts"
+	ReasonMaxLabelBytes      = "max_label_bytes"
+	ReasonMaxAnnotationBytes = "max_annotation_bytes"
+
+	ReasonMaxAnnotationValueBytes = "max_annotation_value_bytes"
+)
+
+// TruncationMarker ends the annotation values truncated by the ingestion
+// limits.
+const TruncationMarker = "[truncated]"
+
+// CheckSize returns an error and the reason for rejecting the alert if its
+// labels or annotations exceed the configured sizes.
+func CheckSize(a *types.Alert, c *config.IngestionLimitsConfig) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	if n := size(a.Labels); c.MaxLabelBytes > 0 && n > c.MaxLabelBytes {
+		return ReasonMaxLabelBytes, fmt.Errorf("labels of alert %s have %d bytes, exceeding the limit of %d", a.Labels, n, c.MaxLabelBytes)
+	}
+	if n := size(a.Annotations); c.MaxAnnotationBytes > 0 && n > c.MaxAnnotationBytes {
+		return ReasonMaxAnnotationBytes, fmt.Errorf("annotations of alert %s have %d bytes, exceeding the limit of %d", a.Labels, n, c.MaxAnnotationBytes)
+	}
+	if c.MaxAnnotationValueBytes > 0 {
+		for _, name := range sortedNames(a.Annotations) {
+			if n := len(a.Annotations[name]); n > c.MaxAnnotationValueBytes {
+				return ReasonMaxAnnotationValueBytes, fmt.Errorf("annotation %q of alert %s has %d bytes, exceeding the limit of %d", name, a.Labels, n, c.MaxAnnotationValueBytes)
+			}
+		}
+	}
+	return "", nil
+}
+
+// TruncateAnnotations truncates the annotation values of the alert exceeding
+// the size limits if the annotation size policy is to truncate them, and
+// returns the number of truncated values. Truncated values end with the
+// TruncationMarker. The longest values are truncated first to respect the
+// limit of the size of all annotations.
+//
+// Labels are never truncated as it would change the identity of alerts.
+func TruncateAnnotations(a *types.Alert, c *config.IngestionLimitsConfig) int {
+	if c == nil || c.AnnotationSizePolicy != config.SizePolicyTruncate {
+		return 0
+	}
+
+	var (
+		annotations = a.Annotations.Clone()
+		truncated   = map[model.LabelName]struct{}{}
+	)
+	if c.MaxAnnotationValueBytes > 0 {
+		for name, v := range annotations {
+			if len(v) > c.MaxAnnotationValueBytes {
+				annotations[name] = truncate(v, c.MaxAnnotationValueBytes)
+				truncated[name] = struct{}{}
+			}
+		}
+	}
+	if excess := size(annotations) - c.MaxAnnotationBytes; c.MaxAnnotationBytes > 0 && excess > 0 {
+		names := sortedNames(annotations)
+		sort.SliceStable(names, func(i, j int) bool {
+			return len(annotations[names[i]]) > len(annotations[names[j]])
+		})
+		for _, name := range names {
+			if excess <= 0 {
+				break
+			}
+			v := annotations[name]
+			if len(v) <= len(TruncationMarker) {
+				continue
+			}
+			t := truncate(v, len(v)-excess)
+			excess -= len(v) - len(t)
+			annotations[name] = t
+			truncated[name] = struct{}{}
+		}
+	}
+
+	if len(truncated) > 0 {
+		a.Annotations = annotations
+	}
+	return len(truncated)
+}
+
+// truncate shortens the value to at most n bytes, or to the marker alone if
+// n is shorter than the marker, without splitting UTF-8 characters.
+func truncate(v model.LabelValue, n int) model.LabelValue {
+	n -= len(TruncationMarker)
+	if n < 0 {
+		n = 0
+	}
+	for n > 0 && !utf8.RuneStart(v[n]) {
+		n--
+	}
+	return v[:n] + TruncationMarker
+}
+
+func sortedNames(ls model.LabelSet) []model.LabelName {
+	names := make([]model.LabelName, 0, len(ls))
+	for name := range ls {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func size(ls model.LabelSet) int {
+	var n int
+	for k, v := range ls {
+		n += len(k) + len(v)
+	}
+	return n
+}
+
+// Admit returns the alerts which can be added to the provider without
+// exceeding the maximum number of active alerts, and the number of rejected
+// alerts. Updates of alerts held by the provider are always admitted.
+//
+// Concurrent requests are not coordinated, so the limit may be exceeded by
+// a small margin.
+func Admit(alerts []*types.Alert, p provider.Alerts, c *config.IngestionLimitsConfig) ([]*types.Alert, int) {
+	if c == nil || c.MaxActiveAlerts <= 0 {
+		return alerts, 0
+	}
+	var (
+		free     = c.MaxActiveAlerts - p.Count()
+		admitted = make([]*types.Alert, 0, len(alerts))
+		added    = map[model.Fingerprint]struct{}{}
+		rejected int
+	)
+	for _, a := range alerts {
+		fp := a.Fingerprint()
+		if old, err := p.Get(fp); err == nil && old != nil {
+			admitted = append(admitted, a)
+			continue
+		}
+		if _, ok := added[fp]; ok {
+			admitted = append(admitted, a)
+			continue
+		}
+		if free <= 0 {
+			rejected++
+			continue
+		}
+		free--
+		added[fp] = struct{}{}
+		admitted = append(admitted, a)
+	}
+	return admitted, rejected
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/store"
+	"github.com/prometheus/alertmanager/types"
+)
+
+type fakeAlerts struct {
+	provider.Alerts
+	alerts *store.Alerts
+}
+
+func (f *fakeAlerts) Get(fp model.Fingerprint) (*types.Alert, error) { return f.alerts.Get(fp) }
+func (f *fakeAlerts) Count() int                                     { return f.alerts.Len() }
+
+func newAlert(name string) *types.Alert {
+	return &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": model.LabelValue(name)}}}
+}
+
+func TestCheckSize(t *testing.T) {
+	a := newAlert("test")
+	a.Annotations = model.LabelSet{"summary": "too long"}
+
+	reason, err := CheckSize(a, nil)
+	require.NoError(t, err)
+	require.Equal(t, "", reason)
+
+	reason, err = CheckSize(a, &config.IngestionLimitsConfig{MaxLabelBytes: 13, MaxAnnotationBytes: 15})
+	require.NoError(t, err)
+	require.Equal(t, "", reason)
+
+	reason, err = CheckSize(a, &config.IngestionLimitsConfig{MaxLabelBytes: 12})
+	require.Error(t, err)
+	require.Equal(t, ReasonMaxLabelBytes, reason)
+
+	reason, err = CheckSize(a, &config.IngestionLimitsConfig{MaxAnnotationBytes: 14})
+	require.Error(t, err)
+	require.Equal(t, ReasonMaxAnnotationBytes, reason)
+
+	reason, err = CheckSize(a, &config.IngestionLimitsConfig{MaxAnnotationValueBytes: 7})
+	require.EqualError(t, err, `annotation "summary" of alert {alertname="test"} has 8 bytes, exceeding the limit of 7`)
+	require.Equal(t, ReasonMaxAnnotationValueBytes, reason)
+}
+
+func TestTruncateAnnotations(t *testing.T) {
+	newAnnotated := func() *types.Alert {
+		a := newAlert("test")
+		a.Annotations = model.LabelSet{
+			"summary":     "short",
+			"description": model.LabelValue(strings.Repeat("é", 20)),
+			"runbook":     model.LabelValue(strings.Repeat("x", 30)),
+		}
+		return a
+	}
+
+	// Alerts are left alone unless the policy is to truncate.
+	a := newAnnotated()
+	annotations := a.Annotations
+	require.Equal(t, 0, TruncateAnnotations(a, &config.IngestionLimitsConfig{MaxAnnotationValueBytes: 20}))
+	require.Equal(t, annotations, a.Annotations)
+
+	a = newAnnotated()
+	c := &config.IngestionLimitsConfig{
+		MaxAnnotationValueBytes: 20,
+		AnnotationSizePolicy:    config.SizePolicyTruncate,
+	}
+	require.Equal(t, 2, TruncateAnnotations(a, c))
+	require.Equal(t, model.LabelSet{
+		"summary":     "short",
+		"description": "éééé" + TruncationMarker,
+		"runbook":     "xxxxxxxxx" + TruncationMarker,
+	}, a.Annotations)
+	require.Equal(t, model.LabelValue(strings.Repeat("é", 20)), annotations["description"], "the original annotations must not be modified")
+	_, err := CheckSize(a, c)
+	require.NoError(t, err)
+
+	// The longest values are truncated first to fit all annotations.
+	a = newAnnotated()
+	c = &config.IngestionLimitsConfig{
+		MaxAnnotationBytes:   80,
+		AnnotationSizePolicy: config.SizePolicyTruncate,
+	}
+	require.Equal(t, 1, TruncateAnnotations(a, c))
+	require.Equal(t, model.LabelValue("éééé"+TruncationMarker), a.Annotations["description"])
+	_, err = CheckSize(a, c)
+	require.NoError(t, err)
+
+	// Annotations which can't be truncated enough are rejected.
+	a = newAnnotated()
+	c.MaxAnnotationBytes = 20
+	require.Equal(t, 2, TruncateAnnotations(a, c))
+	reason, err := CheckSize(a, c)
+	require.Error(t, err)
+	require.Equal(t, ReasonMaxAnnotationBytes, reason)
+}
+
+func TestAdmit(t *testing.T) {
+	s := store.NewAlerts()
+	require.NoError(t, s.Set(newAlert("existing")))
+	p := &fakeAlerts{alerts: s}
+
+	alerts := []*types.Alert{newAlert("a"), newAlert("existing"), newAlert("a"), newAlert("b")}
+
+	admitted, rejected := Admit(alerts, p, nil)
+	require.Equal(t, alerts, admitted)
+	require.Equal(t, 0, rejected)
+
+	// Updates of the existing alert and repeated new alerts do not count
+	// against the limit.
+	admitted, rejected = Admit(alerts, p, &config.IngestionLimitsConfig{MaxActiveAlerts: 2})
+	require.Equal(t, alerts[:3], admitted)
+	require.Equal(t, 1, rejected)
+
+	admitted, rejected = Admit(alerts, p, &config.IngestionLimitsConfig{MaxActiveAlerts: 1})
+	require.Equal(t, []*types.Alert{alerts[1]}, admitted)
+	require.Equal(t, 3, rejected)
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package limits
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// Limits enforced by the RateLimiter. They are used as label values of the
+// rate limited requests metric.
+const (
+	LimitRequests = "requests"
+	LimitAlerts   = "alerts"
+)
+
+// bucket is a token bucket. Its tokens may become negative when a request
+// takes more tokens than the bucket can hold.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *bucket) refill(now time.Time, rate float64, burst int) {
+	b.tokens = math.Min(b.tokens+now.Sub(b.last).Seconds()*rate, float64(burst))
+	b.last = now
+}
+
+// wait returns how long to wait until the bucket holds n tokens, capped by
+// its size.
+func (b *bucket) wait(n int, rate float64, burst int) time.Duration {
+	missing := math.Min(float64(n), float64(burst)) - b.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / rate * float64(time.Second))
+}
+
+func (b *bucket) full(burst int) bool {
+	return b.tokens >= float64(burst)
+}
+
+type client struct {
+	requests, alerts bucket
+}
+
+// RateLimiter limits the rate of the requests posting alerts and of the
+// alerts they post, separately for each client.
+type RateLimiter struct {
+	mtx       sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		clients: map[string]*client{},
+		now:     time.Now,
+	}
+}
+
+// Allow takes a request posting the given number of alerts from the client
+// into account. If the request exceeds the configured rates, it is not taken
+// into account and Allow returns the exceeded limit and how long the client
+// has to wait before retrying. A request posting more alerts than the burst
+// is allowed once the bucket of the client is full.
+func (l *RateLimiter) Allow(id string, alerts int, c *config.IngestionLimitsConfig) (string, time.Duration) {
+	if c == nil || (c.ClientRequestsPerSecond <= 0 && c.ClientAlertsPerSecond <= 0) {
+		return "", 0
+	}
+	var (
+		requestBurst = burst(c.ClientRequestsPerSecond, c.ClientRequestBurst)
+		alertBurst   = burst(c.ClientAlertsPerSecond, c.ClientAlertBurst)
+	)
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	l.sweep(now, requestBurst, alertBurst, c)
+
+	cl, ok := l.clients[id]
+	if !ok {
+		cl = &client{
+			requests: bucket{tokens: float64(requestBurst), last: now},
+			alerts:   bucket{tokens: float64(alertBurst), last: now},
+		}
+		l.clients[id] = cl
+	}
+	if c.ClientRequestsPerSecond > 0 {
+		cl.requests.refill(now, c.ClientRequestsPerSecond, requestBurst)
+		if d := cl.requests.wait(1, c.ClientRequestsPerSecond, requestBurst); d > 0 {
+			return LimitRequests, d
+		}
+	}
+	if c.ClientAlertsPerSecond > 0 {
+		cl.alerts.refill(now, c.ClientAlertsPerSecond, alertBurst)
+		if d := cl.alerts.wait(alerts, c.ClientAlertsPerSecond, alertBurst); d > 0 {
+			return LimitAlerts, d
+		}
+	}
+	if c.ClientRequestsPerSecond > 0 {
+		cl.requests.tokens--
+	}
+	if c.ClientAlertsPerSecond > 0 {
+		cl.alerts.tokens -= float64(alerts)
+	}
+	return "", 0
+}
+
+// sweep forgets the clients whose buckets are full at most once a minute, as
+// they are in the same state as unknown clients.
+func (l *RateLimiter) sweep(now time.Time, requestBurst, alertBurst int, c *config.IngestionLimitsConfig) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for id, cl := range l.clients {
+		cl.requests.refill(now, c.ClientRequestsPerSecond, requestBurst)
+		cl.alerts.refill(now, c.ClientAlertsPerSecond, alertBurst)
+		if (c.ClientRequestsPerSecond <= 0 || cl.requests.full(requestBurst)) &&
+			(c.ClientAlertsPerSecond <= 0 || cl.alerts.full(alertBurst)) {
+			delete(l.clients, id)
+		}
+	}
+}
+
+// burst returns the configured burst, which is at least the rate rounded up
+// and at least one.
+func burst(rate float64, burst int) int {
+	return int(math.Max(math.Max(float64(burst), math.Ceil(rate)), 1))
+}
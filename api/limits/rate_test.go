@@ -0,0 +1,74 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package limits
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewRateLimiter()
+	l.now = func() time.Time { return now }
+
+	// No limits are enforced without configuration.
+	limit, wait := l.Allow("prometheus", 1000, nil)
+	require.Equal(t, "", limit)
+	require.Zero(t, wait)
+
+	c := &config.IngestionLimitsConfig{
+		ClientRequestsPerSecond: 1,
+		ClientRequestBurst:      2,
+		ClientAlertsPerSecond:   10,
+	}
+
+	// The request burst is used up, then one request per second is allowed.
+	for i := 0; i < 2; i++ {
+		_, wait = l.Allow("prometheus", 1, c)
+		require.Zero(t, wait)
+	}
+	limit, wait = l.Allow("prometheus", 1, c)
+	require.Equal(t, LimitRequests, limit)
+	require.Equal(t, time.Second, wait)
+
+	// Other clients are limited separately.
+	_, wait = l.Allow("script", 1, c)
+	require.Zero(t, wait)
+
+	now = now.Add(time.Second)
+	_, wait = l.Allow("prometheus", 1, c)
+	require.Zero(t, wait)
+
+	// The alert burst defaults to the rate. Larger requests are allowed
+	// once the bucket is full.
+	now = now.Add(time.Minute)
+	_, wait = l.Allow("prometheus", 25, c)
+	require.Zero(t, wait)
+	now = now.Add(time.Second)
+	limit, wait = l.Allow("prometheus", 5, c)
+	require.Equal(t, LimitAlerts, limit)
+	require.Equal(t, time.Second, wait)
+	now = now.Add(wait)
+	_, wait = l.Allow("prometheus", 5, c)
+	require.Zero(t, wait)
+
+	// Clients with full buckets are forgotten.
+	now = now.Add(time.Hour)
+	l.Allow("prometheus", 1, c)
+	require.Len(t, l.clients, 1)
+}
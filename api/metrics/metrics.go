@@ -17,9 +17,14 @@ import "github.com/prometheus/client_golang/prometheus"
 
 // Alerts stores metrics for alerts which are common across all API versions.
 type Alerts struct {
-	firing   prometheus.Counter
-	resolved prometheus.Counter
-	invalid  prometheus.Counter
+	firing    prometheus.Counter
+	resolved  prometheus.Counter
+	invalid   prometheus.Counter
+	dropped   prometheus.Counter
+	rejected  *prometheus.CounterVec
+	truncated prometheus.Counter
+
+	annotationFailures prometheus.Counter
 }
 
 // NewAlerts returns an *Alerts struct for the given API version.
@@ -34,13 +39,38 @@ func NewAlerts(version string, r prometheus.Registerer) *Alerts {
 		Help:        "The total number of received alerts that were invalid.",
 		ConstLabels: prometheus.Labels{"version": version},
 	})
+	numDroppedAlerts := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:        "alertmanager_alerts_dropped_total",
+		Help:        "The total number of received alerts that were dropped by relabeling.",
+		ConstLabels: prometheus.Labels{"version": version},
+	})
+	numRejectedAlerts := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "alertmanager_alerts_rejected_total",
+		Help:        "The total number of received alerts that were rejected by ingestion limits or for matching no route.",
+		ConstLabels: prometheus.Labels{"version": version},
+	}, []string{"reason"})
+	numTruncatedAlerts := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:        "alertmanager_alerts_truncated_total",
+		Help:        "The total number of received alerts whose annotations were truncated by ingestion limits.",
+		ConstLabels: prometheus.Labels{"version": version},
+	})
+	numAnnotationFailures := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:        "alertmanager_alerts_annotation_template_failures_total",
+		Help:        "The total number of annotation templates which failed to render for received alerts.",
+		ConstLabels: prometheus.Labels{"version": version},
+	})
 	if r != nil {
-		r.MustRegister(numReceivedAlerts, numInvalidAlerts)
+		r.MustRegister(numReceivedAlerts, numInvalidAlerts, numDroppedAlerts, numRejectedAlerts, numTruncatedAlerts, numAnnotationFailures)
 	}
 	return &Alerts{
-		firing:   numReceivedAlerts.WithLabelValues("firing"),
-		resolved: numReceivedAlerts.WithLabelValues("resolved"),
-		invalid:  numInvalidAlerts,
+		firing:    numReceivedAlerts.WithLabelValues("firing"),
+		resolved:  numReceivedAlerts.WithLabelValues("resolved"),
+		invalid:   numInvalidAlerts,
+		dropped:   numDroppedAlerts,
+		rejected:  numRejectedAlerts,
+		truncated: numTruncatedAlerts,
+
+		annotationFailures: numAnnotationFailures,
 	}
 }
 
@@ -52,3 +82,20 @@ func (a *Alerts) Resolved() prometheus.Counter { return a.resolved }
 
 // Invalid returns a counter of invalid alerts.
 func (a *Alerts) Invalid() prometheus.Counter { return a.invalid }
+
+// Dropped returns a counter of alerts dropped by relabeling.
+func (a *Alerts) Dropped() prometheus.Counter { return a.dropped }
+
+// Rejected returns a counter of alerts rejected by ingestion limits or for
+// matching no route for the given reason.
+func (a *Alerts) Rejected(reason string) prometheus.Counter {
+	return a.rejected.WithLabelValues(reason)
+}
+
+// Truncated returns a counter of alerts whose annotations were truncated by
+// ingestion limits.
+func (a *Alerts) Truncated() prometheus.Counter { return a.truncated }
+
+// AnnotationFailures returns a counter of annotation templates which failed
+// to render.
+func (a *Alerts) AnnotationFailures() prometheus.Counter { return a.annotationFailures }
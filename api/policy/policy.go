@@ -0,0 +1,68 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy enforces the silence policy of the silence APIs.
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/silence"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// CheckSilence returns an error if the silence violates the policy. The
+// silences are used to count the silences of its creator.
+func CheckSilence(sil *pb.Silence, s *silence.Silences, c *config.SilencePolicyConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.RequireComment && strings.TrimSpace(sil.Comment) == "" {
+		return fmt.Errorf("silence policy requires a comment")
+	}
+	if c.MinMatchers > 0 && len(sil.Matchers) < c.MinMatchers {
+		return fmt.Errorf("silence has %d matchers, the silence policy requires at least %d", len(sil.Matchers), c.MinMatchers)
+	}
+	if c.MaxDuration > 0 {
+		d := sil.EndsAt.Sub(sil.StartsAt)
+		if sil.Schedule != nil {
+			d = sil.Schedule.Duration
+		}
+		if max := time.Duration(c.MaxDuration); d > max {
+			return fmt.Errorf("silence lasts %s, exceeding the maximum duration of %s allowed by the silence policy", model.Duration(d), c.MaxDuration)
+		}
+	}
+	if c.MaxActivePerCreator > 0 {
+		sils, _, err := s.Query(silence.QState(types.SilenceStateActive, types.SilenceStatePending))
+		if err != nil {
+			return err
+		}
+		var n int
+		for _, other := range sils {
+			// An update replaces the silence, so it doesn't count.
+			if other.CreatedBy == sil.CreatedBy && other.Id != sil.Id {
+				n++
+			}
+		}
+		if n >= c.MaxActivePerCreator {
+			return fmt.Errorf("%s has %d active or pending silences, the silence policy allows at most %d", sil.CreatedBy, n, c.MaxActivePerCreator)
+		}
+	}
+	return nil
+}
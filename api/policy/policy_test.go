@@ -0,0 +1,83 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/silence"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+func TestCheckSilence(t *testing.T) {
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	now := time.Now()
+	newSilence := func() *pb.Silence {
+		return &pb.Silence{
+			Matchers: []*pb.Matcher{
+				{Name: "job", Pattern: "api"},
+				{Name: "instance", Pattern: "a"},
+			},
+			StartsAt:  now,
+			EndsAt:    now.Add(time.Hour),
+			CreatedBy: "alice",
+			Comment:   "maintenance",
+		}
+	}
+	policy := &config.SilencePolicyConfig{
+		MaxDuration:         model.Duration(2 * time.Hour),
+		RequireComment:      true,
+		MinMatchers:         2,
+		MaxActivePerCreator: 1,
+	}
+
+	require.NoError(t, CheckSilence(newSilence(), sils, nil))
+	require.NoError(t, CheckSilence(newSilence(), sils, policy))
+
+	sil := newSilence()
+	sil.Comment = " "
+	require.EqualError(t, CheckSilence(sil, sils, policy), "silence policy requires a comment")
+
+	sil = newSilence()
+	sil.Matchers = sil.Matchers[:1]
+	require.EqualError(t, CheckSilence(sil, sils, policy), "silence has 1 matchers, the silence policy requires at least 2")
+
+	sil = newSilence()
+	sil.EndsAt = now.Add(3 * time.Hour)
+	require.EqualError(t, CheckSilence(sil, sils, policy), "silence lasts 3h, exceeding the maximum duration of 2h allowed by the silence policy")
+
+	sil = newSilence()
+	sil.Schedule = &pb.Schedule{Cron: "@daily", Duration: 3 * time.Hour}
+	require.Error(t, CheckSilence(sil, sils, policy))
+
+	id, err := sils.Set(newSilence())
+	require.NoError(t, err)
+	require.EqualError(t, CheckSilence(newSilence(), sils, policy), "alice has 1 active or pending silences, the silence policy allows at most 1")
+
+	// Updating the existing silence is allowed, as is creating one for
+	// another user.
+	sil = newSilence()
+	sil.Id = id
+	require.NoError(t, CheckSilence(sil, sils, policy))
+	sil = newSilence()
+	sil.CreatedBy = "bob"
+	require.NoError(t, CheckSilence(sil, sils, policy))
+}
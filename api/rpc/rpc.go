@@ -0,0 +1,362 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpc implements the gRPC API of the Alertmanager. It is served over
+// HTTP/2 by the web server, alongside the HTTP APIs, so that the same
+// authentication and authorization apply to it.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/api/ingestion"
+	"github.com/prometheus/alertmanager/api/rpc/alertmanagerpb"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// ServicePath is the path prefix of the methods of the gRPC service.
+const ServicePath = "/alertmanagerpb.Alertmanager/"
+
+// Options configures the gRPC API. All fields but the logger are mandatory.
+type Options struct {
+	Alerts     provider.Alerts
+	Silences   *silence.Silences
+	StatusFunc func(model.Fingerprint) types.AlertStatus
+	GroupFunc  func(func(*dispatch.Route) bool, func(*types.Alert, time.Time) bool) (dispatch.AlertGroups, map[model.Fingerprint][]string)
+	// PostFunc stores the posted alerts like the alerts posted to API v2.
+	// It returns a *types.MultiError if alerts are invalid, and wraps
+	// ingestion.ErrTooManyAlerts if alerts are rejected by the limit of active
+	// alerts.
+	PostFunc func(...*types.Alert) error
+	// AdmitFunc returns a gRPC status error if the alerts posted by the
+	// client with the given identity or address must be rejected, e.g.
+	// because the client exceeds its rate limits.
+	AdmitFunc func(client string, alerts int) error
+	Logger    log.Logger
+}
+
+// Server serves the gRPC API.
+type Server struct {
+	alertmanagerpb.UnimplementedAlertmanagerServer
+
+	alerts   provider.Alerts
+	silences *silence.Silences
+	status   func(model.Fingerprint) types.AlertStatus
+	groups   func(func(*dispatch.Route) bool, func(*types.Alert, time.Time) bool) (dispatch.AlertGroups, map[model.Fingerprint][]string)
+	post     func(...*types.Alert) error
+	admit    func(string, int) error
+	logger   log.Logger
+	grpc     *grpc.Server
+}
+
+// New returns a Server.
+func New(o Options) *Server {
+	if o.Logger == nil {
+		o.Logger = log.NewNopLogger()
+	}
+	if o.AdmitFunc == nil {
+		o.AdmitFunc = func(string, int) error { return nil }
+	}
+	s := &Server{
+		alerts:   o.Alerts,
+		silences: o.Silences,
+		status:   o.StatusFunc,
+		groups:   o.GroupFunc,
+		post:     o.PostFunc,
+		admit:    o.AdmitFunc,
+		logger:   o.Logger,
+		grpc:     grpc.NewServer(),
+	}
+	alertmanagerpb.RegisterAlertmanagerServer(s.grpc, s)
+	return s
+}
+
+// IsRequest returns whether the request is a gRPC request.
+func IsRequest(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// ServeHTTP serves the gRPC requests, which must use HTTP/2.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.grpc.ServeHTTP(w, r)
+}
+
+// PostAlerts implements alertmanagerpb.AlertmanagerServer.
+func (s *Server) PostAlerts(ctx context.Context, req *alertmanagerpb.PostAlertsRequest) (*alertmanagerpb.PostAlertsResponse, error) {
+	var address string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		address = p.Addr.String()
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			address = host
+		}
+	}
+	identity := auth.IdentityFromContext(ctx)
+
+	client := identity
+	if client == "" {
+		client = address
+	}
+	if err := s.admit(client, len(req.Alerts)); err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*types.Alert, 0, len(req.Alerts))
+	for _, a := range req.Alerts {
+		alerts = append(alerts, &types.Alert{
+			Alert: model.Alert{
+				Labels:       labelSet(a.Labels),
+				Annotations:  labelSet(a.Annotations),
+				StartsAt:     a.StartsAt,
+				EndsAt:       a.EndsAt,
+				GeneratorURL: a.GeneratorUrl,
+			},
+			Source: types.Source{Name: a.Source, Address: address, Identity: identity},
+		})
+	}
+
+	err := s.post(alerts...)
+	var merr *types.MultiError
+	switch {
+	case err == nil:
+		return &alertmanagerpb.PostAlertsResponse{}, nil
+	case errors.As(err, &merr):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ingestion.ErrTooManyAlerts):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	level.Error(s.logger).Log("msg", "Failed to create alerts", "err", err)
+	return nil, status.Error(codes.Internal, err.Error())
+}
+
+// GetAlerts implements alertmanagerpb.AlertmanagerServer.
+func (s *Server) GetAlerts(ctx context.Context, req *alertmanagerpb.GetAlertsRequest) (*alertmanagerpb.GetAlertsResponse, error) {
+	matchers, err := parseFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	filter := alertFilter(matchers)
+
+	// The receivers of the alerts are those of their groups.
+	_, receivers := s.groups(func(*dispatch.Route) bool { return true }, filter)
+
+	it := s.alerts.GetPending()
+	defer it.Close()
+
+	res := &alertmanagerpb.GetAlertsResponse{}
+	now := time.Now()
+	for a := range it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		if !filter(a, now) {
+			continue
+		}
+		res.Alerts = append(res.Alerts, s.alert(a, receivers[a.Fingerprint()]))
+	}
+	if err := it.Err(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	sort.Slice(res.Alerts, func(i, j int) bool { return res.Alerts[i].Fingerprint < res.Alerts[j].Fingerprint })
+	return res, nil
+}
+
+// GetAlertGroups implements alertmanagerpb.AlertmanagerServer.
+func (s *Server) GetAlertGroups(ctx context.Context, req *alertmanagerpb.GetAlertGroupsRequest) (*alertmanagerpb.GetAlertGroupsResponse, error) {
+	matchers, err := parseFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	groups, receivers := s.groups(func(*dispatch.Route) bool { return true }, alertFilter(matchers))
+
+	res := &alertmanagerpb.GetAlertGroupsResponse{Groups: make([]*alertmanagerpb.AlertGroup, 0, len(groups))}
+	for _, g := range groups {
+		ag := &alertmanagerpb.AlertGroup{
+			Labels:   stringMap(g.Labels),
+			Receiver: g.Receiver,
+			Alerts:   make([]*alertmanagerpb.Alert, 0, len(g.Alerts)),
+		}
+		for _, a := range g.Alerts {
+			ag.Alerts = append(ag.Alerts, s.alert(a, receivers[a.Fingerprint()]))
+		}
+		res.Groups = append(res.Groups, ag)
+	}
+	return res, nil
+}
+
+// GetSilences implements alertmanagerpb.AlertmanagerServer.
+func (s *Server) GetSilences(ctx context.Context, req *alertmanagerpb.GetSilencesRequest) (*alertmanagerpb.GetSilencesResponse, error) {
+	matchers, err := parseFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	sils, _, err := s.silences.Query()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &alertmanagerpb.GetSilencesResponse{}
+	now := time.Now()
+	for _, sil := range sils {
+		if !silenceMatches(sil, matchers) {
+			continue
+		}
+		res.Silences = append(res.Silences, silenceToProto(sil, now))
+	}
+	sort.Slice(res.Silences, func(i, j int) bool { return res.Silences[i].Id < res.Silences[j].Id })
+	return res, nil
+}
+
+func (s *Server) alert(a *types.Alert, receivers []string) *alertmanagerpb.Alert {
+	st := s.status(a.Fingerprint())
+	res := &alertmanagerpb.Alert{
+		Labels:       stringMap(a.Labels),
+		Annotations:  stringMap(a.Annotations),
+		StartsAt:     a.StartsAt,
+		EndsAt:       a.EndsAt,
+		GeneratorUrl: a.GeneratorURL,
+		Source:       a.Source.Name,
+		Fingerprint:  a.Fingerprint().String(),
+		UpdatedAt:    a.UpdatedAt,
+		Status: alertmanagerpb.AlertStatus{
+			SilencedBy:  st.SilencedBy,
+			InhibitedBy: st.InhibitedBy,
+		},
+		Receivers: receivers,
+	}
+	switch st.State {
+	case types.AlertStateActive:
+		res.Status.State = alertmanagerpb.AlertStatus_ACTIVE
+	case types.AlertStateSuppressed:
+		res.Status.State = alertmanagerpb.AlertStatus_SUPPRESSED
+	default:
+		res.Status.State = alertmanagerpb.AlertStatus_UNPROCESSED
+	}
+	return res
+}
+
+func silenceToProto(sil *silencepb.Silence, now time.Time) *alertmanagerpb.Silence {
+	res := &alertmanagerpb.Silence{
+		Id:        sil.Id,
+		Matchers:  make([]*alertmanagerpb.Matcher, 0, len(sil.Matchers)),
+		StartsAt:  sil.StartsAt,
+		EndsAt:    sil.EndsAt,
+		UpdatedAt: sil.UpdatedAt,
+		CreatedBy: sil.CreatedBy,
+		Comment:   sil.Comment,
+		State:     string(types.CalcSilenceState(sil.StartsAt, sil.EndsAt)),
+	}
+	for _, m := range sil.Matchers {
+		res.Matchers = append(res.Matchers, &alertmanagerpb.Matcher{
+			Name:    m.Name,
+			Value:   m.Pattern,
+			IsRegex: m.Type == silencepb.Matcher_REGEXP || m.Type == silencepb.Matcher_NOT_REGEXP,
+			IsEqual: m.Type == silencepb.Matcher_EQUAL || m.Type == silencepb.Matcher_REGEXP,
+		})
+	}
+	return res
+}
+
+// parseFilter parses the label matchers of a filter.
+func parseFilter(filter []string) ([]*labels.Matcher, error) {
+	matchers := make([]*labels.Matcher, 0, len(filter))
+	for _, f := range filter {
+		m, err := labels.ParseMatcher(f)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// alertFilter returns a function telling whether an alert is unresolved and
+// matched by all matchers.
+func alertFilter(matchers []*labels.Matcher) func(*types.Alert, time.Time) bool {
+	return func(a *types.Alert, now time.Time) bool {
+		if !a.EndsAt.IsZero() && a.EndsAt.Before(now) {
+			return false
+		}
+		for _, m := range matchers {
+			if !m.Matches(string(a.Labels[model.LabelName(m.Name)])) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// silenceMatches tells whether every matcher equals one of the matchers of
+// the silence, like the filter of the silences of API v2.
+func silenceMatches(sil *silencepb.Silence, matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		found := false
+		for _, sm := range sil.Matchers {
+			if m.Name != sm.Name || m.Value != sm.Pattern {
+				continue
+			}
+			switch sm.Type {
+			case silencepb.Matcher_EQUAL:
+				found = m.Type == labels.MatchEqual
+			case silencepb.Matcher_NOT_EQUAL:
+				found = m.Type == labels.MatchNotEqual
+			case silencepb.Matcher_REGEXP:
+				found = m.Type == labels.MatchRegexp
+			case silencepb.Matcher_NOT_REGEXP:
+				found = m.Type == labels.MatchNotRegexp
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func labelSet(m map[string]string) model.LabelSet {
+	ls := make(model.LabelSet, len(m))
+	for k, v := range m {
+		ls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return ls
+}
+
+func stringMap(ls model.LabelSet) map[string]string {
+	m := make(map[string]string, len(ls))
+	for k, v := range ls {
+		m[string(k)] = string(v)
+	}
+	return m
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/api/ingestion"
+	"github.com/prometheus/alertmanager/api/rpc/alertmanagerpb"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestServer(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	now := time.Now()
+	_, err = sils.Set(&silencepb.Silence{
+		Matchers: []*silencepb.Matcher{{Name: "alertname", Pattern: "Foo", Type: silencepb.Matcher_EQUAL}},
+		StartsAt: now,
+		EndsAt:   now.Add(time.Hour),
+		Comment:  "maintenance",
+	})
+	require.NoError(t, err)
+	_, err = sils.Set(&silencepb.Silence{
+		Matchers: []*silencepb.Matcher{{Name: "alertname", Pattern: "Ba.*", Type: silencepb.Matcher_REGEXP}},
+		StartsAt: now,
+		EndsAt:   now.Add(time.Hour),
+		Comment:  "noisy",
+	})
+	require.NoError(t, err)
+
+	var (
+		postErr error
+		admit   []string
+	)
+	s := New(Options{
+		Alerts:     alerts,
+		Silences:   sils,
+		StatusFunc: marker.Status,
+		GroupFunc: func(_ func(*dispatch.Route) bool, filter func(*types.Alert, time.Time) bool) (dispatch.AlertGroups, map[model.Fingerprint][]string) {
+			group := &dispatch.AlertGroup{Labels: model.LabelSet{"alertname": "Foo"}, Receiver: "team"}
+			receivers := map[model.Fingerprint][]string{}
+			it := alerts.GetPending()
+			defer it.Close()
+			for a := range it.Next() {
+				if filter(a, time.Now()) {
+					group.Alerts = append(group.Alerts, a)
+					receivers[a.Fingerprint()] = []string{"team"}
+				}
+			}
+			return dispatch.AlertGroups{group}, receivers
+		},
+		PostFunc: func(as ...*types.Alert) error {
+			if postErr != nil {
+				return postErr
+			}
+			return alerts.Put(as...)
+		},
+		AdmitFunc: func(client string, n int) error {
+			admit = append(admit, fmt.Sprintf("%s:%d", client, n))
+			if n > 2 {
+				return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+			}
+			return nil
+		},
+	})
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get("X-Identity"); id != "" {
+			r = r.WithContext(auth.WithIdentity(r.Context(), id))
+		}
+		s.ServeHTTP(w, r)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	conn, err := grpc.Dial(srv.Listener.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pool})))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := alertmanagerpb.NewAlertmanagerClient(conn)
+
+	ctx := context.Background()
+	_, err = client.PostAlerts(ctx, &alertmanagerpb.PostAlertsRequest{Alerts: []*alertmanagerpb.Alert{
+		{Labels: map[string]string{"alertname": "Foo"}, Source: "prometheus", StartsAt: now},
+		{Labels: map[string]string{"alertname": "Bar"}, Annotations: map[string]string{"summary": "bar"}, StartsAt: now},
+	}})
+	require.NoError(t, err)
+	// Anonymous clients are identified by their address.
+	require.Equal(t, []string{"127.0.0.1:2"}, admit)
+
+	foo, err := alerts.Get(model.LabelSet{"alertname": "Foo"}.Fingerprint())
+	require.NoError(t, err)
+	require.Equal(t, types.Source{Name: "prometheus", Address: "127.0.0.1"}, foo.Source)
+
+	res, err := client.GetAlerts(ctx, &alertmanagerpb.GetAlertsRequest{Filter: []string{`alertname="Bar"`}})
+	require.NoError(t, err)
+	require.Len(t, res.Alerts, 1)
+	require.Equal(t, map[string]string{"alertname": "Bar"}, res.Alerts[0].Labels)
+	require.Equal(t, map[string]string{"summary": "bar"}, res.Alerts[0].Annotations)
+	require.Equal(t, []string{"team"}, res.Alerts[0].Receivers)
+	require.Equal(t, alertmanagerpb.AlertStatus_UNPROCESSED, res.Alerts[0].Status.State)
+
+	groups, err := client.GetAlertGroups(ctx, &alertmanagerpb.GetAlertGroupsRequest{})
+	require.NoError(t, err)
+	require.Len(t, groups.Groups, 1)
+	require.Equal(t, "team", groups.Groups[0].Receiver)
+	require.Len(t, groups.Groups[0].Alerts, 2)
+
+	sres, err := client.GetSilences(ctx, &alertmanagerpb.GetSilencesRequest{Filter: []string{`alertname=~"Ba.*"`}})
+	require.NoError(t, err)
+	require.Len(t, sres.Silences, 1)
+	require.Equal(t, "noisy", sres.Silences[0].Comment)
+	require.Equal(t, string(types.SilenceStateActive), sres.Silences[0].State)
+	require.Equal(t, &alertmanagerpb.Matcher{Name: "alertname", Value: "Ba.*", IsRegex: true, IsEqual: true}, sres.Silences[0].Matchers[0])
+
+	_, err = client.GetSilences(ctx, &alertmanagerpb.GetSilencesRequest{Filter: []string{`alertname`}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Authenticated clients are identified by their identity.
+	admit = nil
+	idCtx := metadata.AppendToOutgoingContext(ctx, "x-identity", "prometheus")
+	postBaz := func() error {
+		_, err := client.PostAlerts(idCtx, &alertmanagerpb.PostAlertsRequest{Alerts: []*alertmanagerpb.Alert{
+			{Labels: map[string]string{"alertname": "Baz"}, StartsAt: now},
+		}})
+		return err
+	}
+	require.NoError(t, postBaz())
+	require.Equal(t, []string{"prometheus:1"}, admit)
+	baz, err := alerts.Get(model.LabelSet{"alertname": "Baz"}.Fingerprint())
+	require.NoError(t, err)
+	require.Equal(t, "prometheus", baz.Source.Identity)
+
+	for _, tc := range []struct {
+		err  error
+		code codes.Code
+	}{
+		{err: func() error { e := &types.MultiError{}; e.Add(fmt.Errorf("invalid")); return e }(), code: codes.InvalidArgument},
+		{err: fmt.Errorf("1 alerts rejected, %w", ingestion.ErrTooManyAlerts), code: codes.ResourceExhausted},
+		{err: fmt.Errorf("storage failure"), code: codes.Internal},
+	} {
+		postErr = tc.err
+		require.Equal(t, tc.code, status.Code(postBaz()), tc.err.Error())
+	}
+	postErr = nil
+
+	_, err = client.PostAlerts(ctx, &alertmanagerpb.PostAlertsRequest{Alerts: []*alertmanagerpb.Alert{{}, {}, {}}})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -30,12 +30,16 @@ import (
 	"github.com/prometheus/common/route"
 	"github.com/prometheus/common/version"
 
+	"github.com/prometheus/alertmanager/api/ingestion"
 	"github.com/prometheus/alertmanager/api/metrics"
+	"github.com/prometheus/alertmanager/api/policy"
 	"github.com/prometheus/alertmanager/cluster"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/inhibit"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/provider/shard"
 	"github.com/prometheus/alertmanager/silence"
 	"github.com/prometheus/alertmanager/silence/silencepb"
 	"github.com/prometheus/alertmanager/types"
@@ -58,6 +62,17 @@ type Alert struct {
 	Fingerprint string            `json:"fingerprint"`
 }
 
+// Inhibition is the API representation of an alert inhibited by another
+// alert.
+type Inhibition struct {
+	Target            *model.Alert        `json:"target"`
+	TargetFingerprint string              `json:"targetFingerprint"`
+	Source            *model.Alert        `json:"source"`
+	SourceFingerprint string              `json:"sourceFingerprint"`
+	Rule              *config.InhibitRule `json:"rule"`
+	RuleIndex         int                 `json:"ruleIndex"`
+}
+
 // Enables cross-site script calls.
 func setCORS(w http.ResponseWriter) {
 	for h, v := range corsHeaders {
@@ -77,6 +92,9 @@ type API struct {
 	m        *metrics.Alerts
 
 	getAlertStatus getAlertStatusFn
+	inhibitions    func() []inhibit.Inhibition
+	configDiff     func() *config.Diff
+	validation     func() *config.Validation
 
 	mtx sync.RWMutex
 }
@@ -88,6 +106,9 @@ func New(
 	alerts provider.Alerts,
 	silences *silence.Silences,
 	sf getAlertStatusFn,
+	inhibitions func() []inhibit.Inhibition,
+	configDiff func() *config.Diff,
+	validation func() *config.Validation,
 	peer cluster.ClusterPeer,
 	l log.Logger,
 	r prometheus.Registerer,
@@ -100,6 +121,9 @@ func New(
 		alerts:         alerts,
 		silences:       silences,
 		getAlertStatus: sf,
+		inhibitions:    inhibitions,
+		configDiff:     configDiff,
+		validation:     validation,
 		uptime:         time.Now(),
 		peer:           peer,
 		logger:         l,
@@ -120,11 +144,16 @@ func (api *API) Register(r *route.Router) {
 	r.Options("/*path", wrap(func(w http.ResponseWriter, r *http.Request) {}))
 
 	r.Get("/status", wrap(api.status))
+	r.Get("/cluster", wrap(api.cluster))
 	r.Get("/receivers", wrap(api.receivers))
+	r.Get("/config/diff", wrap(api.getConfigDiff))
+	r.Get("/config/validation", wrap(api.getConfigValidation))
 
 	r.Get("/alerts", wrap(api.listAlerts))
 	r.Post("/alerts", wrap(api.addAlerts))
 
+	r.Get("/inhibitions", wrap(api.listInhibitions))
+
 	r.Get("/silences", wrap(api.listSilences))
 	r.Post("/silences", wrap(api.setSilence))
 	r.Get("/silence/:sid", wrap(api.getSilence))
@@ -143,8 +172,9 @@ func (api *API) Update(cfg *config.Config) {
 type errorType string
 
 const (
-	errorInternal errorType = "server_error"
-	errorBadData  errorType = "bad_data"
+	errorInternal        errorType = "server_error"
+	errorBadData         errorType = "bad_data"
+	errorTooManyRequests errorType = "too_many_requests"
 )
 
 type apiError struct {
@@ -168,6 +198,28 @@ func (api *API) receivers(w http.ResponseWriter, req *http.Request) {
 	api.respond(w, receivers)
 }
 
+func (api *API) getConfigDiff(w http.ResponseWriter, req *http.Request) {
+	var diff *config.Diff
+	if api.configDiff != nil {
+		diff = api.configDiff()
+	}
+	if diff == nil {
+		diff = &config.Diff{}
+	}
+	api.respond(w, diff)
+}
+
+func (api *API) getConfigValidation(w http.ResponseWriter, req *http.Request) {
+	var v *config.Validation
+	if api.validation != nil {
+		v = api.validation()
+	}
+	if v == nil {
+		v = &config.Validation{}
+	}
+	api.respond(w, v)
+}
+
 func (api *API) status(w http.ResponseWriter, req *http.Request) {
 	api.mtx.RLock()
 
@@ -198,8 +250,9 @@ func (api *API) status(w http.ResponseWriter, req *http.Request) {
 }
 
 type peerStatus struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
+	Name          string     `json:"name"`
+	Address       string     `json:"address"`
+	LastHeartbeat *time.Time `json:"lastHeartbeat,omitempty"`
 }
 
 type clusterStatus struct {
@@ -215,14 +268,30 @@ func getClusterStatus(p cluster.ClusterPeer) *clusterStatus {
 	s := &clusterStatus{Name: p.Name(), Status: p.Status()}
 
 	for _, n := range p.Peers() {
-		s.Peers = append(s.Peers, peerStatus{
+		ps := peerStatus{
 			Name:    n.Name(),
 			Address: n.Address(),
-		})
+		}
+		// The peer itself has no heartbeat.
+		if t := p.LastHeartbeat(n.Name()); !t.IsZero() {
+			ps.LastHeartbeat = &t
+		}
+		s.Peers = append(s.Peers, ps)
 	}
+	sort.Slice(s.Peers, func(i, j int) bool {
+		return s.Peers[i].Name < s.Peers[j].Name
+	})
 	return s
 }
 
+func (api *API) cluster(w http.ResponseWriter, req *http.Request) {
+	s := getClusterStatus(api.peer)
+	if s == nil {
+		s = &clusterStatus{Status: "disabled", Peers: []peerStatus{}}
+	}
+	api.respond(w, s)
+}
+
 func (api *API) listAlerts(w http.ResponseWriter, r *http.Request) {
 	var (
 		err            error
@@ -313,7 +382,7 @@ func (api *API) listAlerts(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 
-		routes := api.route.Match(a.Labels)
+		routes := api.route.MatchAlert(a)
 		receivers := make([]string, 0, len(routes))
 		for _, r := range routes {
 			receivers = append(receivers, r.RouteOpts.Receiver)
@@ -374,6 +443,56 @@ func (api *API) listAlerts(w http.ResponseWriter, r *http.Request) {
 	api.respond(w, res)
 }
 
+func (api *API) listInhibitions(w http.ResponseWriter, r *http.Request) {
+	matchers := []*labels.Matcher{}
+	if filter := r.FormValue("filter"); filter != "" {
+		var err error
+		matchers, err = labels.ParseMatchers(filter)
+		if err != nil {
+			api.respondError(w, apiError{
+				typ: errorBadData,
+				err: err,
+			}, nil)
+			return
+		}
+	}
+
+	// Initialize result slice to prevent api returning `null` when there
+	// are no inhibitions.
+	res := []*Inhibition{}
+	if api.inhibitions == nil {
+		api.respond(w, res)
+		return
+	}
+
+	api.mtx.RLock()
+	for _, ih := range api.inhibitions() {
+		if !alertMatchesFilterLabels(&ih.Target.Alert, matchers) {
+			continue
+		}
+		var rule *config.InhibitRule
+		// The configuration may have been reloaded since the inhibitions
+		// were computed.
+		if api.config != nil && ih.Rule < len(api.config.InhibitRules) {
+			rule = api.config.InhibitRules[ih.Rule]
+		}
+		res = append(res, &Inhibition{
+			Target:            &ih.Target.Alert,
+			TargetFingerprint: ih.Target.Fingerprint().String(),
+			Source:            &ih.Source.Alert,
+			SourceFingerprint: ih.Source.Fingerprint().String(),
+			Rule:              rule,
+			RuleIndex:         ih.Rule,
+		})
+	}
+	api.mtx.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].TargetFingerprint < res[j].TargetFingerprint
+	})
+	api.respond(w, res)
+}
+
 func receiversMatchFilter(receivers []string, filter *regexp.Regexp) bool {
 	for _, r := range receivers {
 		if filter.MatchString(r) {
@@ -406,52 +525,19 @@ func (api *API) addAlerts(w http.ResponseWriter, r *http.Request) {
 }
 
 func (api *API) insertAlerts(w http.ResponseWriter, r *http.Request, alerts ...*types.Alert) {
-	now := time.Now()
-
 	api.mtx.RLock()
-	resolveTimeout := time.Duration(api.config.Global.ResolveTimeout)
+	conf, route := api.config, api.route
 	api.mtx.RUnlock()
 
-	for _, alert := range alerts {
-		alert.UpdatedAt = now
-
-		// Ensure StartsAt is set.
-		if alert.StartsAt.IsZero() {
-			if alert.EndsAt.IsZero() {
-				alert.StartsAt = now
-			} else {
-				alert.StartsAt = alert.EndsAt
-			}
-		}
-		// If no end time is defined, set a timeout after which an alert
-		// is marked resolved if it is not updated.
-		if alert.EndsAt.IsZero() {
-			alert.Timeout = true
-			alert.EndsAt = now.Add(resolveTimeout)
-		}
-		if alert.EndsAt.After(time.Now()) {
-			api.m.Firing().Inc()
-		} else {
-			api.m.Resolved().Inc()
-		}
-	}
-
-	// Make a best effort to insert all alerts that are valid.
-	var (
-		validAlerts    = make([]*types.Alert, 0, len(alerts))
-		validationErrs = &types.MultiError{}
-	)
-	for _, a := range alerts {
-		removeEmptyLabels(a.Labels)
-
-		if err := a.Validate(); err != nil {
-			validationErrs.Add(err)
-			api.m.Invalid().Inc()
-			continue
-		}
-		validAlerts = append(validAlerts, a)
-	}
-	if err := api.alerts.Put(validAlerts...); err != nil {
+	ingestion.SetSource(r, alerts)
+	res, err := ingestion.Insert(alerts, ingestion.Options{
+		Config:  conf,
+		Route:   route,
+		Alerts:  api.alerts,
+		Metrics: api.m,
+		Local:   shard.Forwarded(r.Context()),
+	})
+	if err != nil {
 		api.respondError(w, apiError{
 			typ: errorInternal,
 			err: err,
@@ -459,10 +545,17 @@ func (api *API) insertAlerts(w http.ResponseWriter, r *http.Request, alerts ...*
 		return
 	}
 
-	if validationErrs.Len() > 0 {
+	if res.Rejected > 0 {
+		api.respondError(w, apiError{
+			typ: errorTooManyRequests,
+			err: res.Err(),
+		}, nil)
+		return
+	}
+	if res.Invalid.Len() > 0 {
 		api.respondError(w, apiError{
 			typ: errorBadData,
-			err: validationErrs,
+			err: &res.Invalid,
 		}, nil)
 		return
 	}
@@ -470,14 +563,6 @@ func (api *API) insertAlerts(w http.ResponseWriter, r *http.Request, alerts ...*
 	api.respond(w, nil)
 }
 
-func removeEmptyLabels(ls model.LabelSet) {
-	for k, v := range ls {
-		if string(v) == "" {
-			delete(ls, k)
-		}
-	}
-}
-
 func (api *API) setSilence(w http.ResponseWriter, r *http.Request) {
 	var sil types.Silence
 	if err := api.receive(r, &sil); err != nil {
@@ -517,6 +602,20 @@ func (api *API) setSilence(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	api.mtx.RLock()
+	var silencePolicy *config.SilencePolicyConfig
+	if api.config != nil {
+		silencePolicy = api.config.SilencePolicy
+	}
+	api.mtx.RUnlock()
+	if err := policy.CheckSilence(psil, api.silences, silencePolicy); err != nil {
+		api.respondError(w, apiError{
+			typ: errorBadData,
+			err: err,
+		}, nil)
+		return
+	}
+
 	sid, err := api.silences.Set(psil)
 	if err != nil {
 		api.respondError(w, apiError{
@@ -775,6 +874,8 @@ func (api *API) respondError(w http.ResponseWriter, apiErr apiError, data interf
 		w.WriteHeader(http.StatusBadRequest)
 	case errorInternal:
 		w.WriteHeader(http.StatusInternalServerError)
+	case errorTooManyRequests:
+		w.WriteHeader(http.StatusTooManyRequests)
 	default:
 		panic(fmt.Sprintf("unknown error type %q", apiErr.Error()))
 	}
@@ -22,15 +22,20 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/cluster"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/inhibit"
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/pkg/relabel"
 	"github.com/prometheus/alertmanager/provider"
 	"github.com/prometheus/alertmanager/types"
 )
@@ -39,6 +44,7 @@ import (
 type fakeAlerts struct {
 	fps    map[model.Fingerprint]int
 	alerts []*types.Alert
+	put    []*types.Alert
 	err    error
 }
 
@@ -60,8 +66,10 @@ func newFakeAlerts(alerts []*types.Alert, withErr bool) *fakeAlerts {
 func (f *fakeAlerts) Subscribe() provider.AlertIterator           { return nil }
 func (f *fakeAlerts) Get(model.Fingerprint) (*types.Alert, error) { return nil, nil }
 func (f *fakeAlerts) Put(alerts ...*types.Alert) error {
+	f.put = append(f.put, alerts...)
 	return f.err
 }
+func (f *fakeAlerts) Count() int { return len(f.alerts) + len(f.put) }
 func (f *fakeAlerts) GetPending() provider.AlertIterator {
 	ch := make(chan *types.Alert)
 	done := make(chan struct{})
@@ -133,7 +141,7 @@ func TestAddAlerts(t *testing.T) {
 		}
 
 		alertsProvider := newFakeAlerts([]*types.Alert{}, tc.err)
-		api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil)
+		api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil, nil, nil, nil)
 		defaultGlobalConfig := config.DefaultGlobalConfig()
 		route := config.Route{}
 		api.Update(&config.Config{
@@ -155,6 +163,190 @@ func TestAddAlerts(t *testing.T) {
 	}
 }
 
+func TestAddAlertsRelabeling(t *testing.T) {
+	alerts := []model.Alert{
+		{Labels: model.LabelSet{"alertname": "a", "pod": "web-5f7d8"}},
+		{Labels: model.LabelSet{"alertname": "b", "env": "test"}},
+	}
+	b, err := json.Marshal(&alerts)
+	require.NoError(t, err)
+
+	alertsProvider := newFakeAlerts([]*types.Alert{}, false)
+	api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil, nil, nil, nil)
+	defaultGlobalConfig := config.DefaultGlobalConfig()
+	api.Update(&config.Config{
+		Global: &defaultGlobalConfig,
+		Route:  &config.Route{},
+		AlertRelabelConfigs: []*relabel.Config{
+			{
+				SourceLabels: model.LabelNames{"env"},
+				Regex:        relabel.MustNewRegexp("test"),
+				Action:       relabel.Drop,
+			},
+			{
+				Regex:  relabel.MustNewRegexp("pod"),
+				Action: relabel.LabelDrop,
+			},
+		},
+	})
+
+	r, err := http.NewRequest("POST", "/api/v1/alerts", bytes.NewReader(b))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	api.addAlerts(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, alertsProvider.put, 1)
+	require.Equal(t, model.LabelSet{"alertname": "a"}, alertsProvider.put[0].Labels)
+}
+
+func TestAddAlertsAnnotationTemplates(t *testing.T) {
+	alerts := []model.Alert{
+		{Labels: model.LabelSet{"alertname": "a", "k8s_cluster": "eu-1"}},
+	}
+	b, err := json.Marshal(&alerts)
+	require.NoError(t, err)
+
+	alertsProvider := newFakeAlerts([]*types.Alert{}, false)
+	api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil, nil, nil, nil)
+	defaultGlobalConfig := config.DefaultGlobalConfig()
+	api.Update(&config.Config{
+		Global: &defaultGlobalConfig,
+		Route:  &config.Route{},
+		AlertRelabelConfigs: []*relabel.Config{
+			{
+				Regex:       relabel.MustNewRegexp("k8s_(.+)"),
+				Replacement: "$1",
+				Action:      relabel.LabelMap,
+			},
+			{
+				Regex:  relabel.MustNewRegexp("k8s_.+"),
+				Action: relabel.LabelDrop,
+			},
+		},
+		AnnotationTemplates: []*config.AnnotationTemplate{
+			{
+				Name:     "dashboard",
+				Template: "https://grafana/d/k8s?var-cluster={{ .Labels.cluster }}",
+			},
+		},
+	})
+
+	r, err := http.NewRequest("POST", "/api/v1/alerts", bytes.NewReader(b))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	api.addAlerts(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, alertsProvider.put, 1)
+	require.Equal(t, model.LabelSet{"dashboard": "https://grafana/d/k8s?var-cluster=eu-1"}, alertsProvider.put[0].Annotations)
+}
+
+func TestAddAlertsRouteResolveTimeout(t *testing.T) {
+	alerts := []model.Alert{
+		{Labels: model.LabelSet{"alertname": "a", "job": "batch"}},
+		{Labels: model.LabelSet{"alertname": "b"}},
+	}
+	b, err := json.Marshal(&alerts)
+	require.NoError(t, err)
+
+	alertsProvider := newFakeAlerts([]*types.Alert{}, false)
+	api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil, nil, nil, nil)
+	defaultGlobalConfig := config.DefaultGlobalConfig()
+	resolveTimeout := model.Duration(time.Hour)
+	api.Update(&config.Config{
+		Global: &defaultGlobalConfig,
+		Route: &config.Route{
+			Routes: []*config.Route{
+				{
+					Match:          map[string]string{"job": "batch"},
+					ResolveTimeout: &resolveTimeout,
+				},
+			},
+		},
+	})
+
+	r, err := http.NewRequest("POST", "/api/v1/alerts", bytes.NewReader(b))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	start := time.Now()
+	api.addAlerts(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, alertsProvider.put, 2)
+	require.WithinDuration(t, start.Add(time.Hour), alertsProvider.put[0].EndsAt, time.Minute)
+	require.WithinDuration(t, start.Add(time.Duration(defaultGlobalConfig.ResolveTimeout)), alertsProvider.put[1].EndsAt, time.Minute)
+}
+
+// TestAddAlertsSource checks that the alerts posted to the v1 API record
+// their source and are routed on it like those posted to the v2 API.
+func TestAddAlertsSource(t *testing.T) {
+	b, err := json.Marshal([]model.Alert{{Labels: model.LabelSet{"alertname": "a"}}})
+	require.NoError(t, err)
+
+	alertsProvider := newFakeAlerts([]*types.Alert{}, false)
+	api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil, nil, nil, nil)
+	defaultGlobalConfig := config.DefaultGlobalConfig()
+	resolveTimeout := model.Duration(time.Hour)
+	api.Update(&config.Config{
+		Global: &defaultGlobalConfig,
+		Route: &config.Route{
+			Routes: []*config.Route{
+				{
+					Match:          map[string]string{"__source_identity__": "prometheus"},
+					ResolveTimeout: &resolveTimeout,
+				},
+			},
+		},
+	})
+
+	r := httptest.NewRequest("POST", "/api/v1/alerts", bytes.NewReader(b))
+	r.RemoteAddr = "10.0.0.1:4321"
+	r = r.WithContext(auth.WithIdentity(r.Context(), "prometheus"))
+	w := httptest.NewRecorder()
+	start := time.Now()
+	api.addAlerts(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, alertsProvider.put, 1)
+	require.Equal(t, types.Source{Address: "10.0.0.1", Identity: "prometheus"}, alertsProvider.put[0].Source)
+	require.WithinDuration(t, start.Add(time.Hour), alertsProvider.put[0].EndsAt, time.Minute)
+}
+
+func TestAddAlertsIngestionLimits(t *testing.T) {
+	alerts := []model.Alert{
+		{Labels: model.LabelSet{"alertname": "a"}},
+		{Labels: model.LabelSet{"alertname": "b", "instance": "a-very-long-instance-name"}},
+		{Labels: model.LabelSet{"alertname": "c"}},
+	}
+	b, err := json.Marshal(&alerts)
+	require.NoError(t, err)
+
+	existing := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "existing"}}}
+	alertsProvider := newFakeAlerts([]*types.Alert{existing}, false)
+	api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil, nil, nil, nil)
+	defaultGlobalConfig := config.DefaultGlobalConfig()
+	api.Update(&config.Config{
+		Global: &defaultGlobalConfig,
+		Route:  &config.Route{},
+		IngestionLimits: &config.IngestionLimitsConfig{
+			MaxActiveAlerts: 2,
+			MaxLabelBytes:   32,
+		},
+	})
+
+	r, err := http.NewRequest("POST", "/api/v1/alerts", bytes.NewReader(b))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	api.addAlerts(w, r)
+
+	// The alert with too large labels is invalid, and only one of the
+	// remaining alerts fits below the limit of active alerts.
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Len(t, alertsProvider.put, 1)
+	require.Equal(t, model.LabelSet{"alertname": "a"}, alertsProvider.put[0].Labels)
+}
+
 func TestListAlerts(t *testing.T) {
 	now := time.Now()
 	alerts := []*types.Alert{
@@ -266,7 +458,7 @@ func TestListAlerts(t *testing.T) {
 		},
 	} {
 		alertsProvider := newFakeAlerts(alerts, tc.err)
-		api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil)
+		api := New(alertsProvider, nil, newGetAlertStatus(alertsProvider), nil, nil, nil, nil, nil, nil)
 		api.route = dispatch.NewRoute(&config.Route{Receiver: "def-receiver"}, nil)
 
 		r, err := http.NewRequest("GET", "/api/v1/alerts", nil)
@@ -316,6 +508,62 @@ func TestListAlerts(t *testing.T) {
 	}
 }
 
+func TestListInhibitions(t *testing.T) {
+	source := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "cluster_down"}}}
+	targets := []*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b"}}},
+	}
+	rule := &config.InhibitRule{
+		SourceMatch: map[string]string{"alertname": "cluster_down"},
+	}
+
+	api := New(newFakeAlerts(nil, false), nil, nil, func() []inhibit.Inhibition {
+		return []inhibit.Inhibition{
+			{Target: targets[0], Source: source, Rule: 0},
+			{Target: targets[1], Source: source, Rule: 0},
+		}
+	}, nil, nil, nil, nil, nil)
+	api.config = &config.Config{InhibitRules: []*config.InhibitRule{rule}}
+
+	for _, tc := range []struct {
+		filter  string
+		code    int
+		targets []string
+	}{
+		{"", 200, []string{targets[0].Fingerprint().String(), targets[1].Fingerprint().String()}},
+		{`{alertname="b"}`, 200, []string{targets[1].Fingerprint().String()}},
+		{"{invalid", 400, nil},
+	} {
+		r, err := http.NewRequest("GET", "/api/v1/inhibitions", nil)
+		require.NoError(t, err)
+		q := r.URL.Query()
+		q.Add("filter", tc.filter)
+		r.URL.RawQuery = q.Encode()
+		w := httptest.NewRecorder()
+
+		api.listInhibitions(w, r)
+		require.Equal(t, tc.code, w.Code)
+		if w.Code != 200 {
+			continue
+		}
+
+		res := struct {
+			Data []*Inhibition `json:"data"`
+		}{}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+		fps := []string{}
+		for _, ih := range res.Data {
+			require.Equal(t, source.Fingerprint().String(), ih.SourceFingerprint)
+			require.Equal(t, source.Labels, ih.Source.Labels)
+			require.NotNil(t, ih.Rule)
+			fps = append(fps, ih.TargetFingerprint)
+		}
+		sort.Strings(tc.targets)
+		require.Equal(t, tc.targets, fps)
+	}
+}
+
 func TestAlertFiltering(t *testing.T) {
 	type test struct {
 		alert    *model.Alert
@@ -583,3 +831,119 @@ func newMatcher(labelSet model.LabelSet) labels.Matchers {
 	}
 	return matchers
 }
+
+type fakeMember struct{ name, address string }
+
+func (m fakeMember) Name() string    { return m.name }
+func (m fakeMember) Address() string { return m.address }
+
+type fakePeer struct {
+	heartbeats map[string]time.Time
+}
+
+func (p *fakePeer) Name() string   { return "b" }
+func (p *fakePeer) Status() string { return "ready" }
+func (p *fakePeer) Peers() []cluster.ClusterMember {
+	return []cluster.ClusterMember{fakeMember{"b", "10.0.0.2:9094"}, fakeMember{"a", "10.0.0.1:9094"}}
+}
+func (p *fakePeer) LastHeartbeat(name string) time.Time { return p.heartbeats[name] }
+
+func TestCluster(t *testing.T) {
+	heartbeat := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		peer     cluster.ClusterPeer
+		expected clusterStatus
+	}{
+		{
+			expected: clusterStatus{Status: "disabled", Peers: []peerStatus{}},
+		},
+		{
+			peer: &fakePeer{heartbeats: map[string]time.Time{"a": heartbeat}},
+			expected: clusterStatus{
+				Name:   "b",
+				Status: "ready",
+				Peers: []peerStatus{
+					{Name: "a", Address: "10.0.0.1:9094", LastHeartbeat: &heartbeat},
+					{Name: "b", Address: "10.0.0.2:9094"},
+				},
+			},
+		},
+	} {
+		api := New(newFakeAlerts(nil, false), nil, nil, nil, nil, nil, tc.peer, nil, nil)
+
+		r, err := http.NewRequest("GET", "/api/v1/cluster", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		api.cluster(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res struct {
+			Data clusterStatus `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+		require.Equal(t, tc.expected, res.Data)
+	}
+}
+
+func TestGetConfigDiff(t *testing.T) {
+	diff := &config.Diff{ReceiversAdded: []string{"team-X"}}
+	for _, tc := range []struct {
+		configDiff func() *config.Diff
+		body       string
+	}{
+		{nil, `{}`},
+		{func() *config.Diff { return nil }, `{}`},
+		{func() *config.Diff { return diff }, `{"receiversAdded":["team-X"]}`},
+	} {
+		api := New(newFakeAlerts(nil, false), nil, nil, nil, tc.configDiff, nil, nil, nil, nil)
+
+		r, err := http.NewRequest("GET", "/api/v1/config/diff", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		api.getConfigDiff(w, r)
+		require.Equal(t, 200, w.Code)
+
+		var res response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		data, err := json.Marshal(res.Data)
+		require.NoError(t, err)
+		require.JSONEq(t, tc.body, string(data))
+	}
+}
+
+func TestGetConfigValidation(t *testing.T) {
+	v := &config.Validation{
+		Time: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC),
+		Receivers: []config.ReceiverValidation{{
+			Name: "team-X",
+			Integrations: []config.IntegrationValidation{
+				{Integration: "slack", Index: 0},
+				{Integration: "webhook", Index: 0, Error: "dial tcp: connection refused"},
+			},
+		}},
+	}
+	for _, tc := range []struct {
+		validation func() *config.Validation
+		body       string
+	}{
+		{nil, `{"time":"0001-01-01T00:00:00Z","receivers":null}`},
+		{func() *config.Validation { return v }, `{"time":"2021-06-01T10:00:00Z","receivers":[{"name":"team-X","integrations":[{"integration":"slack","index":0},{"integration":"webhook","index":0,"error":"dial tcp: connection refused"}]}]}`},
+	} {
+		api := New(newFakeAlerts(nil, false), nil, nil, nil, nil, tc.validation, nil, nil, nil)
+
+		r, err := http.NewRequest("GET", "/api/v1/config/validation", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		api.getConfigValidation(w, r)
+		require.Equal(t, 200, w.Code)
+
+		var res response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		data, err := json.Marshal(res.Data)
+		require.NoError(t, err)
+		require.JSONEq(t, tc.body, string(data))
+	}
+}
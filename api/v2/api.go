@@ -14,39 +14,56 @@
 package v2
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 	"github.com/prometheus/client_golang/prometheus"
 	prometheus_model "github.com/prometheus/common/model"
 	"github.com/prometheus/common/version"
 	"github.com/rs/cors"
 
+	"github.com/prometheus/alertmanager/ack"
+	"github.com/prometheus/alertmanager/api/ingestion"
 	"github.com/prometheus/alertmanager/api/metrics"
+	"github.com/prometheus/alertmanager/api/policy"
 	open_api_models "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/api/v2/restapi"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations"
+	ack_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/ack"
 	alert_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/alert"
 	alertgroup_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/alertgroup"
+	deadletter_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/deadletter"
 	general_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/general"
 	receiver_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/receiver"
 	silence_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/silence"
 	"github.com/prometheus/alertmanager/cluster"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/deadletter"
 	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/history"
+	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/provider/shard"
 	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/audit"
+	silence_preset "github.com/prometheus/alertmanager/silence/preset"
 	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/tracing"
 	"github.com/prometheus/alertmanager/types"
 )
 
@@ -57,15 +74,26 @@ type API struct {
 	alerts         provider.Alerts
 	alertGroups    groupsFn
 	getAlertStatus getAlertStatusFn
+	deadLetters    *deadletter.Queue
+	replay         replayFn
+	render         renderFn
+	test           testFn
+	resend         resendFn
+	health         *notify.Health
+	budgets        *notify.Budgets
+	acks           *ack.Acks
+	silenceAudit   *audit.Log
+	history        *history.Log
 	uptime         time.Time
 
-	// mtx protects alertmanagerConfig, setAlertStatus and route.
+	// mtx protects alertmanagerConfig, setAlertStatus, route and cors.
 	mtx sync.RWMutex
 	// resolveTimeout represents the default resolve timeout that an alert is
 	// assigned if no end time is specified.
 	alertmanagerConfig *config.Config
 	route              *dispatch.Route
 	setAlertStatus     setAlertStatusFn
+	cors               *cors.Cors
 
 	logger log.Logger
 	m      *metrics.Alerts
@@ -76,6 +104,10 @@ type API struct {
 type groupsFn func(func(*dispatch.Route) bool, func(*types.Alert, time.Time) bool) (dispatch.AlertGroups, map[prometheus_model.Fingerprint][]string)
 type getAlertStatusFn func(prometheus_model.Fingerprint) types.AlertStatus
 type setAlertStatusFn func(prometheus_model.LabelSet)
+type replayFn func(*deadletter.Entry) error
+type renderFn func(context.Context, string, []*types.Alert) ([]notify.Rendered, bool)
+type testFn func(context.Context, string, []*types.Alert) ([]notify.TestResult, bool)
+type resendFn func(context.Context, string, string) error
 
 // NewAPI returns a new Alertmanager API v2
 func NewAPI(
@@ -83,6 +115,16 @@ func NewAPI(
 	gf groupsFn,
 	sf getAlertStatusFn,
 	silences *silence.Silences,
+	deadLetters *deadletter.Queue,
+	replay replayFn,
+	render renderFn,
+	test testFn,
+	resend resendFn,
+	health *notify.Health,
+	budgets *notify.Budgets,
+	acks *ack.Acks,
+	silenceAudit *audit.Log,
+	notificationHistory *history.Log,
 	peer cluster.ClusterPeer,
 	l log.Logger,
 	r prometheus.Registerer,
@@ -93,6 +135,16 @@ func NewAPI(
 		alertGroups:    gf,
 		peer:           peer,
 		silences:       silences,
+		deadLetters:    deadLetters,
+		replay:         replay,
+		render:         render,
+		test:           test,
+		resend:         resend,
+		health:         health,
+		budgets:        budgets,
+		acks:           acks,
+		silenceAudit:   silenceAudit,
+		history:        notificationHistory,
 		logger:         l,
 		m:              metrics.NewAlerts("v2", r),
 		uptime:         time.Now(),
@@ -107,6 +159,9 @@ func NewAPI(
 	// Create new service API.
 	openAPI := operations.NewAlertmanagerAPI(swaggerSpec)
 
+	// Errors of the alert stream are returned as plain text.
+	openAPI.TextEventStreamProducer = runtime.TextProducer()
+
 	// Skip the  redoc middleware, only serving the OpenAPI specification and
 	// the API itself via RoutesHandler. See:
 	// https://github.com/go-swagger/go-swagger/issues/1779
@@ -114,18 +169,39 @@ func NewAPI(
 		return middleware.Spec("", swaggerSpec.Raw(), openAPI.Context().RoutesHandler(b))
 	}
 
+	openAPI.AckDeleteAckHandler = ack_ops.DeleteAckHandlerFunc(api.deleteAckHandler)
+	openAPI.AckGetAcksHandler = ack_ops.GetAcksHandlerFunc(api.getAcksHandler)
+	openAPI.AckPostAcksHandler = ack_ops.PostAcksHandlerFunc(api.postAcksHandler)
+	openAPI.AlertGetAlertStatsHandler = alert_ops.GetAlertStatsHandlerFunc(api.getAlertStatsHandler)
 	openAPI.AlertGetAlertsHandler = alert_ops.GetAlertsHandlerFunc(api.getAlertsHandler)
 	openAPI.AlertPostAlertsHandler = alert_ops.PostAlertsHandlerFunc(api.postAlertsHandler)
+	openAPI.AlertStreamAlertsHandler = alert_ops.StreamAlertsHandlerFunc(api.streamAlertsHandler)
 	openAPI.AlertgroupGetAlertGroupsHandler = alertgroup_ops.GetAlertGroupsHandlerFunc(api.getAlertGroupsHandler)
+	openAPI.AlertgroupResendAlertGroupHandler = alertgroup_ops.ResendAlertGroupHandlerFunc(api.resendAlertGroupHandler)
+	openAPI.DeadletterDeleteDeadLetterHandler = deadletter_ops.DeleteDeadLetterHandlerFunc(api.deleteDeadLetterHandler)
+	openAPI.DeadletterGetDeadLetterHandler = deadletter_ops.GetDeadLetterHandlerFunc(api.getDeadLetterHandler)
+	openAPI.DeadletterGetDeadLettersHandler = deadletter_ops.GetDeadLettersHandlerFunc(api.getDeadLettersHandler)
+	openAPI.DeadletterReplayDeadLetterHandler = deadletter_ops.ReplayDeadLetterHandlerFunc(api.replayDeadLetterHandler)
 	openAPI.GeneralGetStatusHandler = general_ops.GetStatusHandlerFunc(api.getStatusHandler)
 	openAPI.ReceiverGetReceiversHandler = receiver_ops.GetReceiversHandlerFunc(api.getReceiversHandler)
+	openAPI.ReceiverGetReceiversHealthHandler = receiver_ops.GetReceiversHealthHandlerFunc(api.getReceiversHealthHandler)
+	openAPI.ReceiverGetBudgetsHandler = receiver_ops.GetBudgetsHandlerFunc(api.getBudgetsHandler)
+	openAPI.ReceiverGetNotificationHistoryHandler = receiver_ops.GetNotificationHistoryHandlerFunc(api.getNotificationHistoryHandler)
+	openAPI.ReceiverRenderReceiverHandler = receiver_ops.RenderReceiverHandlerFunc(api.renderReceiverHandler)
+	openAPI.ReceiverTestReceiverHandler = receiver_ops.TestReceiverHandlerFunc(api.testReceiverHandler)
 	openAPI.SilenceDeleteSilenceHandler = silence_ops.DeleteSilenceHandlerFunc(api.deleteSilenceHandler)
 	openAPI.SilenceGetSilenceHandler = silence_ops.GetSilenceHandlerFunc(api.getSilenceHandler)
+	openAPI.SilenceGetSilenceEventsHandler = silence_ops.GetSilenceEventsHandlerFunc(api.getSilenceEventsHandler)
+	openAPI.SilenceExpireSilencesHandler = silence_ops.ExpireSilencesHandlerFunc(api.expireSilencesHandler)
+	openAPI.SilenceRecreateSilencesHandler = silence_ops.RecreateSilencesHandlerFunc(api.recreateSilencesHandler)
+	openAPI.SilenceGetSilencePresetsHandler = silence_ops.GetSilencePresetsHandlerFunc(api.getSilencePresetsHandler)
+	openAPI.SilencePostSilencePresetHandler = silence_ops.PostSilencePresetHandlerFunc(api.postSilencePresetHandler)
+	openAPI.SilenceGetSilenceOccurrencesHandler = silence_ops.GetSilenceOccurrencesHandlerFunc(api.getSilenceOccurrencesHandler)
 	openAPI.SilenceGetSilencesHandler = silence_ops.GetSilencesHandlerFunc(api.getSilencesHandler)
 	openAPI.SilencePostSilencesHandler = silence_ops.PostSilencesHandlerFunc(api.postSilencesHandler)
 
-	handleCORS := cors.Default().Handler
-	api.Handler = handleCORS(openAPI.Serve(nil))
+	api.cors = cors.Default()
+	api.Handler = api.corsHandler(api.tenantHandler(openAPI.Serve(nil)))
 
 	return &api, nil
 }
@@ -142,6 +218,36 @@ func (api *API) Update(cfg *config.Config, setAlertStatus setAlertStatusFn) {
 	api.alertmanagerConfig = cfg
 	api.route = dispatch.NewRoute(cfg.Route, nil)
 	api.setAlertStatus = setAlertStatus
+	api.cors = newCORS(cfg.CORS)
+}
+
+// newCORS returns the handler of cross-origin requests for the given
+// configuration. All origins are allowed to make simple requests if no
+// configuration is given.
+func newCORS(c *config.CORSConfig) *cors.Cors {
+	if c == nil {
+		return cors.Default()
+	}
+	return cors.New(cors.Options{
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           int(time.Duration(c.MaxAge).Seconds()),
+	})
+}
+
+// corsHandler handles cross-origin requests according to the current
+// configuration.
+func (api *API) corsHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		api.mtx.RLock()
+		c := api.cors
+		api.mtx.RUnlock()
+
+		c.Handler(h).ServeHTTP(w, req)
+	})
 }
 
 func (api *API) getStatusHandler(params general_ops.GetStatusParams) middleware.Responder {
@@ -201,15 +307,331 @@ func (api *API) getStatusHandler(params general_ops.GetStatusParams) middleware.
 }
 
 func (api *API) getReceiversHandler(params receiver_ops.GetReceiversParams) middleware.Responder {
+	api.mtx.RLock()
+	var configReceivers []*config.Receiver
+	if api.alertmanagerConfig != nil {
+		configReceivers = api.alertmanagerConfig.Receivers
+	}
+	api.mtx.RUnlock()
+
+	receivers := make([]*open_api_models.Receiver, 0, len(configReceivers))
+	for _, r := range configReceivers {
+		if !api.ownsReceiver(params.HTTPRequest, r.Name) {
+			continue
+		}
+		receivers = append(receivers, &open_api_models.Receiver{
+			Name:         &r.Name,
+			Integrations: receiverIntegrations(r),
+		})
+	}
+
+	return receiver_ops.NewGetReceiversOK().WithPayload(receivers)
+}
+
+func (api *API) getReceiversHealthHandler(params receiver_ops.GetReceiversHealthParams) middleware.Responder {
+	api.mtx.RLock()
+	var receivers []*config.Receiver
+	if api.alertmanagerConfig != nil {
+		receivers = api.alertmanagerConfig.Receivers
+	}
+	api.mtx.RUnlock()
+
+	res := make(open_api_models.ReceiversHealth, 0, len(receivers))
+	for _, r := range receivers {
+		if !api.ownsReceiver(params.HTTPRequest, r.Name) {
+			continue
+		}
+		res = append(res, api.receiverHealth(r))
+	}
+	return receiver_ops.NewGetReceiversHealthOK().WithPayload(res)
+}
+
+func (api *API) getBudgetsHandler(params receiver_ops.GetBudgetsParams) middleware.Responder {
+	res := open_api_models.Budgets{}
+	if api.budgets == nil {
+		return receiver_ops.NewGetBudgetsOK().WithPayload(res)
+	}
+	// A tenant only sees its own budget and those of its receivers.
+	_, tenant, scoped := api.tenant(params.HTTPRequest)
+	for _, s := range api.budgets.Status() {
+		if scoped {
+			if s.Scope == notify.BudgetScopeTenant && s.Name != tenant {
+				continue
+			}
+			if s.Scope == notify.BudgetScopeReceiver && !strings.HasPrefix(s.Name, tenant+"/") {
+				continue
+			}
+		}
+		res = append(res, BudgetStatusToOpenAPI(s))
+	}
+	return receiver_ops.NewGetBudgetsOK().WithPayload(res)
+}
+
+func (api *API) getNotificationHistoryHandler(params receiver_ops.GetNotificationHistoryParams) middleware.Responder {
+	res := open_api_models.NotificationHistory{}
+	if api.history == nil {
+		return receiver_ops.NewGetNotificationHistoryOK().WithPayload(res)
+	}
+
+	var q history.Query
+	if params.Receiver != nil {
+		q.Receiver = *params.Receiver
+	}
+	if params.Since != nil {
+		q.Since = time.Time(*params.Since)
+	}
+	if params.Until != nil {
+		q.Until = time.Time(*params.Until)
+	}
+	for _, e := range api.history.Query(q) {
+		if api.ownsGroup(params.HTTPRequest, e.GroupLabels) {
+			res = append(res, NotificationHistoryEntryToOpenAPI(e))
+		}
+	}
+	return receiver_ops.NewGetNotificationHistoryOK().WithPayload(res)
+}
+
+// receiverHealth returns the health of the integrations of the receiver. The
+// integrations which didn't attempt to send a notification since the
+// Alertmanager started are in the unknown state.
+func (api *API) receiverHealth(r *config.Receiver) *open_api_models.ReceiverHealth {
+	type key struct {
+		integration string
+		index       int
+	}
+	attempted := map[key]notify.IntegrationHealth{}
+	if api.health != nil {
+		for _, ih := range api.health.Receiver(r.Name) {
+			attempted[key{ih.Integration, ih.Index}] = ih
+		}
+	}
+
+	var deadLetters int64
+	if api.deadLetters != nil {
+		deadLetters = int64(len(api.deadLetters.List(r.Name)))
+	}
+	rh := &open_api_models.ReceiverHealth{
+		Name:         swag.String(r.Name),
+		State:        swag.String(notify.HealthUnknown),
+		DeadLetters:  &deadLetters,
+		Integrations: []*open_api_models.IntegrationHealth{},
+	}
+	for _, i := range receiverIntegrations(r) {
+		ih := attempted[key{*i.Name, int(*i.Index)}]
+		rh.Integrations = append(rh.Integrations, IntegrationHealthToOpenAPI(*i.Name, *i.Index, ih))
+		if state := ih.State(); state == notify.HealthFailing || (state == notify.HealthHealthy && *rh.State == notify.HealthUnknown) {
+			rh.State = swag.String(state)
+		}
+	}
+	return rh
+}
+
+func (api *API) renderReceiverHandler(params receiver_ops.RenderReceiverParams) middleware.Responder {
+	if api.render == nil || !api.hasReceiver(params.Name) || !api.ownsReceiver(params.HTTPRequest, params.Name) {
+		return receiver_ops.NewRenderReceiverNotFound()
+	}
+
+	alerts := OpenAPIAlertsToAlerts(params.Alerts)
+	now := time.Now()
+
+	api.mtx.RLock()
+	resolveTimeout := time.Duration(api.alertmanagerConfig.Global.ResolveTimeout)
+	validate := ingestion.Validator(api.alertmanagerConfig.Global.LabelValidation)
+	api.mtx.RUnlock()
+
+	for _, a := range alerts {
+		ingestion.Default(a, now, resolveTimeout)
+		ingestion.RemoveEmptyLabels(a.Labels)
+		if err := validate(a); err != nil {
+			return receiver_ops.NewRenderReceiverBadRequest().WithPayload(err.Error())
+		}
+	}
+	api.scopeAlerts(params.HTTPRequest, alerts)
+
+	rendered, ok := api.render(params.HTTPRequest.Context(), params.Name, alerts)
+	if !ok {
+		return receiver_ops.NewRenderReceiverNotFound()
+	}
+
+	res := make(open_api_models.RenderedNotifications, 0, len(rendered))
+	for _, r := range rendered {
+		r := r
+		n := &open_api_models.RenderedNotification{
+			Integration: &r.Integration,
+			Index:       swag.Int64(int64(r.Index)),
+			Payloads:    make([]*open_api_models.RenderedPayload, 0, len(r.Payloads)),
+		}
+		for _, p := range r.Payloads {
+			n.Payloads = append(n.Payloads, &open_api_models.RenderedPayload{
+				ContentType: swag.String(p.ContentType),
+				Body:        swag.String(string(p.Body)),
+			})
+		}
+		if r.Err != nil {
+			n.Error = r.Err.Error()
+		}
+		res = append(res, n)
+	}
+	return receiver_ops.NewRenderReceiverOK().WithPayload(res)
+}
+
+// testAlert returns the alert sent to test the integrations of a receiver.
+func (api *API) testAlert(receiver string, now time.Time) *types.Alert {
+	return &types.Alert{
+		Alert: prometheus_model.Alert{
+			Labels: prometheus_model.LabelSet{
+				prometheus_model.AlertNameLabel: "TestAlert",
+				"receiver":                      prometheus_model.LabelValue(receiver),
+			},
+			Annotations: prometheus_model.LabelSet{
+				"summary":     "Test notification",
+				"description": "This notification tests the integrations of the receiver, no action is needed.",
+			},
+			StartsAt: now,
+			EndsAt:   now.Add(5 * time.Minute),
+		},
+		UpdatedAt: now,
+	}
+}
+
+func (api *API) testReceiverHandler(params receiver_ops.TestReceiverParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	if api.test == nil || !api.hasReceiver(params.Name) || !api.ownsReceiver(params.HTTPRequest, params.Name) {
+		return receiver_ops.NewTestReceiverNotFound()
+	}
+
+	alerts := []*types.Alert{api.testAlert(params.Name, time.Now())}
+	api.scopeAlerts(params.HTTPRequest, alerts)
+
+	results, ok := api.test(params.HTTPRequest.Context(), params.Name, alerts)
+	if !ok {
+		return receiver_ops.NewTestReceiverNotFound()
+	}
+
+	res := make(open_api_models.IntegrationTestResults, 0, len(results))
+	for _, r := range results {
+		r := r
+		tr := &open_api_models.IntegrationTestResult{
+			Integration: &r.Integration,
+			Index:       swag.Int64(int64(r.Index)),
+			Success:     swag.Bool(r.Err == nil),
+		}
+		if r.Err != nil {
+			tr.Error = r.Err.Error()
+			level.Warn(logger).Log("msg", "Test notification failed", "receiver", params.Name, "integration", fmt.Sprintf("%s[%d]", r.Integration, r.Index), "err", r.Err)
+		}
+		res = append(res, tr)
+	}
+	return receiver_ops.NewTestReceiverOK().WithPayload(res)
+}
+
+// receiverIntegrations returns the integrations of the receiver. Only their
+// type is exposed, never their configuration and its secrets.
+func receiverIntegrations(r *config.Receiver) []*open_api_models.Integration {
+	integrations := []*open_api_models.Integration{}
+	add := func(name string, i int, rs interface{ SendResolved() bool }) {
+		integrations = append(integrations, &open_api_models.Integration{
+			Name:         &name,
+			Index:        swag.Int64(int64(i)),
+			SendResolved: swag.Bool(rs.SendResolved()),
+		})
+	}
+
+	for i, c := range r.WebhookConfigs {
+		add("webhook", i, c)
+	}
+	for i, c := range r.EmailConfigs {
+		add("email", i, c)
+	}
+	for i, c := range r.PagerdutyConfigs {
+		add("pagerduty", i, c)
+	}
+	for i, c := range r.OpsGenieConfigs {
+		add("opsgenie", i, c)
+	}
+	for i, c := range r.WechatConfigs {
+		add("wechat", i, c)
+	}
+	for i, c := range r.SlackConfigs {
+		add("slack", i, c)
+	}
+	for i, c := range r.VictorOpsConfigs {
+		add("victorops", i, c)
+	}
+	for i, c := range r.PushoverConfigs {
+		add("pushover", i, c)
+	}
+	for i, c := range r.SNSConfigs {
+		add("sns", i, c)
+	}
+	for i, c := range r.IncidentIOConfigs {
+		add("incidentio", i, c)
+	}
+	for i, c := range r.StatuspageConfigs {
+		add("statuspage", i, c)
+	}
+	for i, c := range r.GithubConfigs {
+		add("github", i, c)
+	}
+	for i, c := range r.GrafanaConfigs {
+		add("grafana", i, c)
+	}
+	for i, c := range r.PushbulletConfigs {
+		add("pushbullet", i, c)
+	}
+	for i, c := range r.GotifyConfigs {
+		add("gotify", i, c)
+	}
+	for i, c := range r.NtfyConfigs {
+		add("ntfy", i, c)
+	}
+	for i, c := range r.NSCAConfigs {
+		add("nsca", i, c)
+	}
+	for i, c := range r.SplunkHECConfigs {
+		add("splunk_hec", i, c)
+	}
+	for i, c := range r.ElasticsearchConfigs {
+		add("elasticsearch", i, c)
+	}
+	for i, c := range r.PluginConfigs {
+		add("plugin", i, c)
+	}
+	for i, c := range r.LocalLogConfigs {
+		add("local_log", i, c)
+	}
+	for i, c := range r.StdoutConfigs {
+		add("stdout", i, c)
+	}
+	return integrations
+}
+
+// hasReceiver returns whether the configuration has a receiver with the given
+// name.
+func (api *API) hasReceiver(name string) bool {
 	api.mtx.RLock()
 	defer api.mtx.RUnlock()
 
-	receivers := make([]*open_api_models.Receiver, 0, len(api.alertmanagerConfig.Receivers))
+	if api.alertmanagerConfig == nil {
+		return false
+	}
 	for _, r := range api.alertmanagerConfig.Receivers {
-		receivers = append(receivers, &open_api_models.Receiver{Name: &r.Name})
+		if r.Name == name {
+			return true
+		}
 	}
+	return false
+}
 
-	return receiver_ops.NewGetReceiversOK().WithPayload(receivers)
+func (api *API) silencePolicy() *config.SilencePolicyConfig {
+	api.mtx.RLock()
+	defer api.mtx.RUnlock()
+
+	if api.alertmanagerConfig == nil {
+		return nil
+	}
+	return api.alertmanagerConfig.SilencePolicy
 }
 
 func (api *API) getAlertsHandler(params alert_ops.GetAlertsParams) middleware.Responder {
@@ -228,6 +650,7 @@ func (api *API) getAlertsHandler(params alert_ops.GetAlertsParams) middleware.Re
 		level.Error(logger).Log("msg", "Failed to parse matchers", "err", err)
 		return alertgroup_ops.NewGetAlertGroupsBadRequest().WithPayload(err.Error())
 	}
+	matchers = api.tenantMatchers(params.HTTPRequest, matchers)
 
 	if params.Receiver != nil {
 		receiverFilter, err = regexp.Compile("^(?:" + *params.Receiver + ")$")
@@ -244,9 +667,13 @@ func (api *API) getAlertsHandler(params alert_ops.GetAlertsParams) middleware.Re
 	alerts := api.alerts.GetPending()
 	defer alerts.Close()
 
-	alertFilter := api.alertFilter(matchers, *params.Silenced, *params.Inhibited, *params.Active)
+	alertFilter := api.alertFilter(matchers, *params.Silenced, *params.Inhibited, *params.Active, *params.Unprocessed)
 	now := time.Now()
 
+	// Only the requested page is converted to its API representation, the
+	// matching alerts are kept as references until then.
+	var matched []routedAlert
+
 	api.mtx.RLock()
 	for a := range alerts.Next() {
 		if err = alerts.Err(); err != nil {
@@ -256,7 +683,7 @@ func (api *API) getAlertsHandler(params alert_ops.GetAlertsParams) middleware.Re
 			break
 		}
 
-		routes := api.route.Match(a.Labels)
+		routes := api.route.MatchAlert(a)
 		receivers := make([]string, 0, len(routes))
 		for _, r := range routes {
 			receivers = append(receivers, r.RouteOpts.Receiver)
@@ -270,85 +697,166 @@ func (api *API) getAlertsHandler(params alert_ops.GetAlertsParams) middleware.Re
 			continue
 		}
 
-		alert := AlertToOpenAPIAlert(a, api.getAlertStatus(a.Fingerprint()), receivers)
-
-		res = append(res, alert)
+		matched = append(matched, routedAlert{alert: a, receivers: receivers})
 	}
+	less := api.alertsLess(matched, *params.Sort)
 	api.mtx.RUnlock()
 
 	if err != nil {
 		level.Error(logger).Log("msg", "Failed to get alerts", "err", err)
 		return alert_ops.NewGetAlertsInternalServerError().WithPayload(err.Error())
 	}
-	sort.Slice(res, func(i, j int) bool {
-		return *res[i].Fingerprint < *res[j].Fingerprint
-	})
+	sort.SliceStable(matched, less)
 
-	return alert_ops.NewGetAlertsOK().WithPayload(res)
-}
+	total := len(matched)
+	if params.Offset != nil {
+		if int(*params.Offset) >= len(matched) {
+			matched = nil
+		} else {
+			matched = matched[*params.Offset:]
+		}
+	}
+	if params.Limit != nil && *params.Limit > 0 && int(*params.Limit) < len(matched) {
+		matched = matched[:*params.Limit]
+	}
 
-func (api *API) postAlertsHandler(params alert_ops.PostAlertsParams) middleware.Responder {
-	logger := api.requestLogger(params.HTTPRequest)
+	for _, m := range matched {
+		res = append(res, AlertToOpenAPIAlert(m.alert, api.getAlertStatus(m.alert.Fingerprint()), m.receivers))
+	}
 
-	alerts := OpenAPIAlertsToAlerts(params.Alerts)
-	now := time.Now()
+	return alert_ops.NewGetAlertsOK().WithXTotalCount(int64(total)).WithPayload(res)
+}
 
-	api.mtx.RLock()
-	resolveTimeout := time.Duration(api.alertmanagerConfig.Global.ResolveTimeout)
-	api.mtx.RUnlock()
+// routedAlert is an alert along with the receivers it is routed to.
+type routedAlert struct {
+	alert     *types.Alert
+	receivers []string
+}
 
-	for _, alert := range alerts {
-		alert.UpdatedAt = now
+// alertsLess returns the less function sorting the alerts by the given key.
+// Ties are broken by fingerprint to keep pages stable between requests. The
+// caller must hold api.mtx.
+func (api *API) alertsLess(alerts []routedAlert, key string) func(i, j int) bool {
+	byFingerprint := func(i, j int) bool {
+		return alerts[i].alert.Fingerprint() < alerts[j].alert.Fingerprint()
+	}
 
-		// Ensure StartsAt is set.
-		if alert.StartsAt.IsZero() {
-			if alert.EndsAt.IsZero() {
-				alert.StartsAt = now
-			} else {
-				alert.StartsAt = alert.EndsAt
+	switch key {
+	case "startsAt":
+		return func(i, j int) bool {
+			si, sj := alerts[i].alert.StartsAt, alerts[j].alert.StartsAt
+			if !si.Equal(sj) {
+				return si.Before(sj)
 			}
+			return byFingerprint(i, j)
 		}
-		// If no end time is defined, set a timeout after which an alert
-		// is marked resolved if it is not updated.
-		if alert.EndsAt.IsZero() {
-			alert.Timeout = true
-			alert.EndsAt = now.Add(resolveTimeout)
+	case "alertname":
+		return func(i, j int) bool {
+			ni, nj := alerts[i].alert.Name(), alerts[j].alert.Name()
+			if ni != nj {
+				return ni < nj
+			}
+			return byFingerprint(i, j)
 		}
-		if alert.EndsAt.After(time.Now()) {
-			api.m.Firing().Inc()
-		} else {
-			api.m.Resolved().Inc()
+	case "severity":
+		label := config.DefaultGlobalConfig().SeverityLabel
+		var mappings []*config.SeverityMapping
+		if api.alertmanagerConfig != nil && api.alertmanagerConfig.Global != nil {
+			label = api.alertmanagerConfig.Global.SeverityLabel
+			mappings = api.alertmanagerConfig.Global.SeverityMapping
+		}
+		// Severities are ranked by their position in the mapping, most
+		// severe first. Unknown severities come last.
+		ranks := make(map[prometheus_model.LabelValue]int, len(mappings))
+		for i, m := range mappings {
+			ranks[prometheus_model.LabelValue(m.Severity)] = i
+		}
+		rank := func(v prometheus_model.LabelValue) int {
+			if r, ok := ranks[v]; ok {
+				return r
+			}
+			return len(ranks)
+		}
+		return func(i, j int) bool {
+			vi, vj := alerts[i].alert.Labels[label], alerts[j].alert.Labels[label]
+			if ri, rj := rank(vi), rank(vj); ri != rj {
+				return ri < rj
+			}
+			if vi != vj {
+				return vi < vj
+			}
+			return byFingerprint(i, j)
 		}
 	}
+	return byFingerprint
+}
 
-	// Make a best effort to insert all alerts that are valid.
-	var (
-		validAlerts    = make([]*types.Alert, 0, len(alerts))
-		validationErrs = &types.MultiError{}
-	)
-	for _, a := range alerts {
-		removeEmptyLabels(a.Labels)
+func (api *API) postAlertsHandler(params alert_ops.PostAlertsParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
 
-		if err := a.Validate(); err != nil {
-			validationErrs.Add(err)
-			api.m.Invalid().Inc()
-			continue
-		}
-		validAlerts = append(validAlerts, a)
-	}
-	if err := api.alerts.Put(validAlerts...); err != nil {
+	ctx := tracing.Extract(params.HTTPRequest.Context(), params.HTTPRequest.Header)
+	_, span := tracing.Start(ctx, "api.post_alerts", tracing.SpanKindServer, "alerts", len(params.Alerts))
+	defer span.End()
+
+	// Alerts forwarded to their owner by another cluster member are stored
+	// without being forwarded again.
+	local := shard.Forwarded(params.HTTPRequest.Context())
+	alerts := OpenAPIAlertsToAlerts(params.Alerts)
+	ingestion.SetSource(params.HTTPRequest, alerts)
+	res, err := api.insertAlerts(alerts, func(a *types.Alert) {
+		api.scopeAlerts(params.HTTPRequest, []*types.Alert{a})
+	}, local)
+	span.SetAttributes("alerts.accepted", res.Accepted)
+	if err != nil {
+		span.RecordError(err)
 		level.Error(logger).Log("msg", "Failed to create alerts", "err", err)
 		return alert_ops.NewPostAlertsInternalServerError().WithPayload(err.Error())
 	}
 
-	if validationErrs.Len() > 0 {
-		level.Error(logger).Log("msg", "Failed to validate alerts", "err", validationErrs.Error())
-		return alert_ops.NewPostAlertsBadRequest().WithPayload(validationErrs.Error())
+	if res.Rejected > 0 {
+		msg := res.Err().Error()
+		level.Warn(logger).Log("msg", "Failed to create alerts", "err", msg)
+		return alert_ops.NewPostAlertsTooManyRequests().WithPayload(msg)
+	}
+
+	if res.Invalid.Len() > 0 {
+		level.Error(logger).Log("msg", "Failed to validate alerts", "err", res.Invalid.Error())
+		return alert_ops.NewPostAlertsBadRequest().WithPayload(res.Invalid.Error())
 	}
 
 	return alert_ops.NewPostAlertsOK()
 }
 
+// PostAlerts stores alerts received by other means than the API, such as
+// ingesters, like the alerts posted to the API. It fails if one of the
+// alerts wasn't stored, with an error wrapping ingestion.ErrTooManyAlerts if
+// the limit of active alerts is reached.
+func (api *API) PostAlerts(alerts ...*types.Alert) error {
+	res, err := api.insertAlerts(alerts, nil, false)
+	if err != nil {
+		return err
+	}
+	return res.Err()
+}
+
+// insertAlerts stores the alerts with the current configuration, see
+// ingestion.Insert. Local alerts are stored without being forwarded to their
+// owner in the cluster.
+func (api *API) insertAlerts(alerts []*types.Alert, scope func(*types.Alert), local bool) (*ingestion.Result, error) {
+	api.mtx.RLock()
+	conf, route := api.alertmanagerConfig, api.route
+	api.mtx.RUnlock()
+
+	return ingestion.Insert(alerts, ingestion.Options{
+		Config:  conf,
+		Route:   route,
+		Alerts:  api.alerts,
+		Metrics: api.m,
+		Scope:   scope,
+		Local:   local,
+	})
+}
+
 func (api *API) getAlertGroupsHandler(params alertgroup_ops.GetAlertGroupsParams) middleware.Responder {
 	logger := api.requestLogger(params.HTTPRequest)
 
@@ -357,6 +865,7 @@ func (api *API) getAlertGroupsHandler(params alertgroup_ops.GetAlertGroupsParams
 		level.Error(logger).Log("msg", "Failed to parse matchers", "err", err)
 		return alertgroup_ops.NewGetAlertGroupsBadRequest().WithPayload(err.Error())
 	}
+	matchers = api.tenantMatchers(params.HTTPRequest, matchers)
 
 	var receiverFilter *regexp.Regexp
 	if params.Receiver != nil {
@@ -381,16 +890,24 @@ func (api *API) getAlertGroupsHandler(params alertgroup_ops.GetAlertGroupsParams
 		}
 	}(receiverFilter)
 
-	af := api.alertFilter(matchers, *params.Silenced, *params.Inhibited, *params.Active)
+	af := api.alertFilter(matchers, *params.Silenced, *params.Inhibited, *params.Active, *params.Unprocessed)
 	alertGroups, allReceivers := api.alertGroups(rf, af)
 
 	res := make(open_api_models.AlertGroups, 0, len(alertGroups))
 
 	for _, alertGroup := range alertGroups {
 		ag := &open_api_models.AlertGroup{
-			Receiver: &open_api_models.Receiver{Name: &alertGroup.Receiver},
-			Labels:   ModelLabelSetToAPILabelSet(alertGroup.Labels),
-			Alerts:   make([]*open_api_models.GettableAlert, 0, len(alertGroup.Alerts)),
+			Receiver:  &open_api_models.Receiver{Name: &alertGroup.Receiver},
+			Labels:    ModelLabelSetToAPILabelSet(alertGroup.Labels),
+			Alerts:    make([]*open_api_models.GettableAlert, 0, len(alertGroup.Alerts)),
+			GroupKey:  alertGroup.GroupKey,
+			GroupID:   notify.Key(alertGroup.GroupKey).Hash(),
+			NextFlush: strfmt.DateTime(alertGroup.NextFlush),
+		}
+		if api.acks != nil {
+			if a, ok := api.acks.Get(alertGroup.GroupKey); ok {
+				ag.Acknowledgement = AckToOpenAPIAck(a)
+			}
 		}
 
 		for _, alert := range alertGroup.Alerts {
@@ -406,7 +923,7 @@ func (api *API) getAlertGroupsHandler(params alertgroup_ops.GetAlertGroupsParams
 	return alertgroup_ops.NewGetAlertGroupsOK().WithPayload(res)
 }
 
-func (api *API) alertFilter(matchers []*labels.Matcher, silenced, inhibited, active bool) func(a *types.Alert, now time.Time) bool {
+func (api *API) alertFilter(matchers []*labels.Matcher, silenced, inhibited, active, unprocessed bool) func(a *types.Alert, now time.Time) bool {
 	return func(a *types.Alert, now time.Time) bool {
 		if !a.EndsAt.IsZero() && a.EndsAt.Before(now) {
 			return false
@@ -422,6 +939,10 @@ func (api *API) alertFilter(matchers []*labels.Matcher, silenced, inhibited, act
 			return false
 		}
 
+		if !unprocessed && status.State == types.AlertStateUnprocessed {
+			return false
+		}
+
 		if !silenced && len(status.SilencedBy) != 0 {
 			return false
 		}
@@ -434,14 +955,6 @@ func (api *API) alertFilter(matchers []*labels.Matcher, silenced, inhibited, act
 	}
 }
 
-func removeEmptyLabels(ls prometheus_model.LabelSet) {
-	for k, v := range ls {
-		if string(v) == "" {
-			delete(ls, k)
-		}
-	}
-}
-
 func receiversMatchFilter(receivers []string, filter *regexp.Regexp) bool {
 	for _, r := range receivers {
 		if filter.MatchString(r) {
@@ -493,14 +1006,23 @@ func (api *API) getSilencesHandler(params silence_ops.GetSilencesParams) middlew
 			matcher, err := labels.ParseMatcher(matcherString)
 			if err != nil {
 				level.Error(logger).Log("msg", "Failed to parse matchers", "err", err)
-				return alert_ops.NewGetAlertsBadRequest().WithPayload(err.Error())
+				return silence_ops.NewGetSilencesBadRequest().WithPayload(err.Error())
 			}
 
 			matchers = append(matchers, matcher)
 		}
 	}
 
-	psils, _, err := api.silences.Query()
+	var query []silence.QueryParam
+	if len(params.State) > 0 {
+		states := make([]types.SilenceState, 0, len(params.State))
+		for _, s := range params.State {
+			states = append(states, types.SilenceState(s))
+		}
+		query = append(query, silence.QState(states...))
+	}
+
+	psils, _, err := api.silences.Query(query...)
 	if err != nil {
 		level.Error(logger).Log("msg", "Failed to get silences", "err", err)
 		return silence_ops.NewGetSilencesInternalServerError().WithPayload(err.Error())
@@ -508,7 +1030,17 @@ func (api *API) getSilencesHandler(params silence_ops.GetSilencesParams) middlew
 
 	sils := open_api_models.GettableSilences{}
 	for _, ps := range psils {
-		if !CheckSilenceMatchesFilterLabels(ps, matchers) {
+		if !CheckSilenceMatchesFilterLabels(ps, matchers) || !api.ownsSilence(params.HTTPRequest, ps) {
+			continue
+		}
+		if params.CreatedBy != nil && ps.CreatedBy != *params.CreatedBy {
+			continue
+		}
+		// Only silences overlapping the requested time range are kept.
+		if params.Since != nil && ps.EndsAt.Before(time.Time(*params.Since)) {
+			continue
+		}
+		if params.Until != nil && ps.StartsAt.After(time.Time(*params.Until)) {
 			continue
 		}
 		silence, err := GettableSilenceFromProto(ps)
@@ -521,7 +1053,19 @@ func (api *API) getSilencesHandler(params silence_ops.GetSilencesParams) middlew
 
 	SortSilences(sils)
 
-	return silence_ops.NewGetSilencesOK().WithPayload(sils)
+	total := len(sils)
+	if params.Offset != nil {
+		if int(*params.Offset) >= len(sils) {
+			sils = open_api_models.GettableSilences{}
+		} else {
+			sils = sils[*params.Offset:]
+		}
+	}
+	if params.Limit != nil && *params.Limit > 0 && int(*params.Limit) < len(sils) {
+		sils = sils[:*params.Limit]
+	}
+
+	return silence_ops.NewGetSilencesOK().WithXTotalCount(int64(total)).WithPayload(sils)
 }
 
 var (
@@ -598,7 +1142,7 @@ func (api *API) getSilenceHandler(params silence_ops.GetSilenceParams) middlewar
 		return silence_ops.NewGetSilenceInternalServerError().WithPayload(err.Error())
 	}
 
-	if len(sils) == 0 {
+	if len(sils) == 0 || !api.ownsSilence(params.HTTPRequest, sils[0]) {
 		level.Error(logger).Log("msg", "Failed to find silence", "err", err, "id", params.SilenceID.String())
 		return silence_ops.NewGetSilenceNotFound()
 	}
@@ -612,17 +1156,309 @@ func (api *API) getSilenceHandler(params silence_ops.GetSilenceParams) middlewar
 	return silence_ops.NewGetSilenceOK().WithPayload(&sil)
 }
 
+func (api *API) getSilenceOccurrencesHandler(params silence_ops.GetSilenceOccurrencesParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	sil, err := api.silences.QueryOne(silence.QIDs(params.SilenceID.String()))
+	if err == silence.ErrNotFound || (err == nil && !api.ownsSilence(params.HTTPRequest, sil)) {
+		return silence_ops.NewGetSilenceOccurrencesNotFound()
+	}
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to get silence by id", "err", err, "id", params.SilenceID.String())
+		return silence_ops.NewGetSilenceOccurrencesInternalServerError().WithPayload(err.Error())
+	}
+
+	limit := silence_ops.NewGetSilenceOccurrencesParams().Limit
+	if params.Limit != nil {
+		limit = params.Limit
+	}
+	occ, err := silence.Occurrences(sil, time.Now(), int(*limit))
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to compute silence occurrences", "err", err, "id", params.SilenceID.String())
+		return silence_ops.NewGetSilenceOccurrencesInternalServerError().WithPayload(err.Error())
+	}
+
+	res := make(open_api_models.SilenceOccurrences, 0, len(occ))
+	for _, o := range occ {
+		start, end := strfmt.DateTime(o.StartsAt), strfmt.DateTime(o.EndsAt)
+		res = append(res, &open_api_models.SilenceOccurrence{StartsAt: &start, EndsAt: &end})
+	}
+	return silence_ops.NewGetSilenceOccurrencesOK().WithPayload(res)
+}
+
 func (api *API) deleteSilenceHandler(params silence_ops.DeleteSilenceParams) middleware.Responder {
 	logger := api.requestLogger(params.HTTPRequest)
 
 	sid := params.SilenceID.String()
-	if err := api.silences.Expire(sid); err != nil {
+	var user string
+	if params.ExpiredBy != nil {
+		user = *params.ExpiredBy
+	}
+	sil, err := api.silences.QueryOne(silence.QIDs(sid))
+	if err == silence.ErrNotFound || (err == nil && !api.ownsSilence(params.HTTPRequest, sil)) {
+		return silence_ops.NewDeleteSilenceNotFound()
+	}
+	if err := api.silences.ExpireBy(sid, user); err != nil {
 		level.Error(logger).Log("msg", "Failed to expire silence", "err", err)
 		return silence_ops.NewDeleteSilenceInternalServerError().WithPayload(err.Error())
 	}
 	return silence_ops.NewDeleteSilenceOK()
 }
 
+// selectSilences returns the silences in the given states matching the filter
+// of a bulk request. A request must select silences by matchers or by author.
+func (api *API) selectSilences(r *http.Request, req *open_api_models.BulkSilenceRequest, states ...types.SilenceState) ([]*silencepb.Silence, error) {
+	if len(req.Filter) == 0 && req.CreatedBy == "" {
+		return nil, errors.New("a filter or createdBy is required")
+	}
+	matchers := make([]*labels.Matcher, 0, len(req.Filter))
+	for _, s := range req.Filter {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+
+	psils, _, err := api.silences.Query(silence.QState(states...))
+	if err != nil {
+		return nil, err
+	}
+	var res []*silencepb.Silence
+	for _, ps := range psils {
+		if !CheckSilenceMatchesFilterLabels(ps, matchers) || !api.ownsSilence(r, ps) {
+			continue
+		}
+		if req.CreatedBy != "" && ps.CreatedBy != req.CreatedBy {
+			continue
+		}
+		res = append(res, ps)
+	}
+	return res, nil
+}
+
+func bulkSilenceResult(sils []*silencepb.Silence) (*open_api_models.BulkSilenceResult, error) {
+	res := &open_api_models.BulkSilenceResult{Silences: open_api_models.GettableSilences{}}
+	for _, ps := range sils {
+		s, err := GettableSilenceFromProto(ps)
+		if err != nil {
+			return nil, err
+		}
+		res.Silences = append(res.Silences, &s)
+	}
+	SortSilences(res.Silences)
+	return res, nil
+}
+
+func (api *API) expireSilencesHandler(params silence_ops.ExpireSilencesParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	sils, err := api.selectSilences(params.HTTPRequest, params.Request, types.SilenceStateActive, types.SilenceStatePending)
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to select silences", "err", err)
+		return silence_ops.NewExpireSilencesBadRequest().WithPayload(err.Error())
+	}
+	res, err := bulkSilenceResult(sils)
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to unmarshal silence from proto", "err", err)
+		return silence_ops.NewExpireSilencesInternalServerError().WithPayload(err.Error())
+	}
+	if params.Request.DryRun {
+		return silence_ops.NewExpireSilencesOK().WithPayload(res)
+	}
+
+	for _, sil := range sils {
+		if err := api.silences.ExpireBy(sil.Id, params.Request.User); err != nil {
+			level.Error(logger).Log("msg", "Failed to expire silence", "id", sil.Id, "err", err)
+			return silence_ops.NewExpireSilencesInternalServerError().WithPayload(err.Error())
+		}
+	}
+	return silence_ops.NewExpireSilencesOK().WithPayload(res)
+}
+
+// matchersKey returns a key identifying the matchers of a silence
+// independently of their order.
+func matchersKey(sil *silencepb.Silence) string {
+	ms := make([]string, 0, len(sil.Matchers))
+	for _, m := range sil.Matchers {
+		ms = append(ms, m.String())
+	}
+	sort.Strings(ms)
+	return strings.Join(ms, ",")
+}
+
+func (api *API) recreateSilencesHandler(params silence_ops.RecreateSilencesParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	d, err := prometheus_model.ParseDuration(params.Request.Duration)
+	if err != nil || d <= 0 {
+		return silence_ops.NewRecreateSilencesBadRequest().WithPayload(fmt.Sprintf("invalid duration %q", params.Request.Duration))
+	}
+	sils, err := api.selectSilences(params.HTTPRequest, params.Request, types.SilenceStateExpired)
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to select silences", "err", err)
+		return silence_ops.NewRecreateSilencesBadRequest().WithPayload(err.Error())
+	}
+
+	// Only the latest of several expired silences with the same matchers is
+	// re-created.
+	latest := map[string]*silencepb.Silence{}
+	for _, sil := range sils {
+		k := matchersKey(sil)
+		if prev, ok := latest[k]; !ok || sil.EndsAt.After(prev.EndsAt) {
+			latest[k] = sil
+		}
+	}
+	sils = sils[:0]
+	for _, sil := range latest {
+		sils = append(sils, sil)
+	}
+
+	res, err := bulkSilenceResult(sils)
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to unmarshal silence from proto", "err", err)
+		return silence_ops.NewRecreateSilencesInternalServerError().WithPayload(err.Error())
+	}
+
+	now := time.Now()
+	recreated := make([]*silencepb.Silence, 0, len(sils))
+	for _, sil := range sils {
+		createdBy := params.Request.User
+		if createdBy == "" {
+			createdBy = sil.CreatedBy
+		}
+		s := &silencepb.Silence{
+			Matchers:       sil.Matchers,
+			StartsAt:       now,
+			EndsAt:         now.Add(time.Duration(d)),
+			CreatedBy:      createdBy,
+			Comment:        sil.Comment,
+			NotifyReceiver: sil.NotifyReceiver,
+			NotifyBefore:   sil.NotifyBefore,
+		}
+		if err := policy.CheckSilence(s, api.silences, api.silencePolicy()); err != nil {
+			msg := fmt.Sprintf("Failed to recreate silence %s: %v", sil.Id, err)
+			level.Error(logger).Log("msg", msg)
+			return silence_ops.NewRecreateSilencesBadRequest().WithPayload(msg)
+		}
+		recreated = append(recreated, s)
+	}
+	if params.Request.DryRun {
+		return silence_ops.NewRecreateSilencesOK().WithPayload(res)
+	}
+
+	res.Recreated = make(map[string]string, len(sils))
+	for i, sil := range sils {
+		sid, err := api.silences.Set(recreated[i])
+		if err != nil {
+			level.Error(logger).Log("msg", "Failed to recreate silence", "id", sil.Id, "err", err)
+			return silence_ops.NewRecreateSilencesInternalServerError().WithPayload(err.Error())
+		}
+		res.Recreated[sil.Id] = sid
+	}
+	return silence_ops.NewRecreateSilencesOK().WithPayload(res)
+}
+
+func (api *API) getSilencePresetsHandler(params silence_ops.GetSilencePresetsParams) middleware.Responder {
+	api.mtx.RLock()
+	defer api.mtx.RUnlock()
+
+	res := open_api_models.SilencePresets{}
+	if api.alertmanagerConfig == nil {
+		return silence_ops.NewGetSilencePresetsOK().WithPayload(res)
+	}
+	for _, p := range api.alertmanagerConfig.SilencePresets {
+		name, duration, comment := p.Name, p.Duration.String(), p.Comment
+		res = append(res, &open_api_models.SilencePreset{
+			Name:     &name,
+			Matchers: append([]string{}, p.Matchers...),
+			Duration: &duration,
+			Comment:  &comment,
+		})
+	}
+	return silence_ops.NewGetSilencePresetsOK().WithPayload(res)
+}
+
+func (api *API) postSilencePresetHandler(params silence_ops.PostSilencePresetParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	var preset *config.SilencePreset
+	api.mtx.RLock()
+	if api.alertmanagerConfig != nil {
+		for _, p := range api.alertmanagerConfig.SilencePresets {
+			if p.Name == params.PresetName {
+				preset = p
+				break
+			}
+		}
+	}
+	api.mtx.RUnlock()
+	if preset == nil {
+		return silence_ops.NewPostSilencePresetNotFound()
+	}
+
+	var d time.Duration
+	if params.Instantiation.Duration != "" {
+		md, err := prometheus_model.ParseDuration(params.Instantiation.Duration)
+		if err != nil {
+			return silence_ops.NewPostSilencePresetBadRequest().WithPayload(fmt.Sprintf("invalid duration: %v", err))
+		}
+		d = time.Duration(md)
+	}
+	sil, err := silence_preset.Instantiate(preset, params.Instantiation.Parameters, *params.Instantiation.CreatedBy, d, time.Now())
+	if err != nil {
+		msg := fmt.Sprintf("Failed to instantiate silence preset %q: %v", preset.Name, err)
+		level.Error(logger).Log("msg", msg)
+		return silence_ops.NewPostSilencePresetBadRequest().WithPayload(msg)
+	}
+	api.scopeSilence(params.HTTPRequest, sil)
+	if err := policy.CheckSilence(sil, api.silences, api.silencePolicy()); err != nil {
+		msg := fmt.Sprintf("Failed to create silence: %v", err)
+		level.Error(logger).Log("msg", msg)
+		return silence_ops.NewPostSilencePresetBadRequest().WithPayload(msg)
+	}
+
+	sid, err := api.silences.Set(sil)
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to create silence", "err", err)
+		return silence_ops.NewPostSilencePresetBadRequest().WithPayload(err.Error())
+	}
+	return silence_ops.NewPostSilencePresetOK().WithPayload(&silence_ops.PostSilencePresetOKBody{
+		SilenceID: sid,
+	})
+}
+
+func (api *API) getSilenceEventsHandler(params silence_ops.GetSilenceEventsParams) middleware.Responder {
+	res := open_api_models.SilenceEvents{}
+	if api.silenceAudit == nil {
+		return silence_ops.NewGetSilenceEventsOK().WithPayload(res)
+	}
+
+	var q audit.Query
+	if params.SilenceID != nil {
+		q.SilenceID = *params.SilenceID
+	}
+	if params.User != nil {
+		q.User = *params.User
+	}
+	if params.Since != nil {
+		q.Since = time.Time(*params.Since)
+	}
+	if params.Until != nil {
+		q.Until = time.Time(*params.Until)
+	}
+	for _, e := range api.silenceAudit.Query(q) {
+		if _, _, ok := api.tenant(params.HTTPRequest); ok {
+			sil, err := api.silences.QueryOne(silence.QIDs(e.SilenceID))
+			if err != nil || !api.ownsSilence(params.HTTPRequest, sil) {
+				continue
+			}
+		}
+		res = append(res, SilenceEventToOpenAPISilenceEvent(e))
+	}
+	return silence_ops.NewGetSilenceEventsOK().WithPayload(res)
+}
+
 func (api *API) postSilencesHandler(params silence_ops.PostSilencesParams) middleware.Responder {
 	logger := api.requestLogger(params.HTTPRequest)
 
@@ -634,15 +1470,38 @@ func (api *API) postSilencesHandler(params silence_ops.PostSilencesParams) middl
 		)
 	}
 
-	if sil.StartsAt.After(sil.EndsAt) || sil.StartsAt.Equal(sil.EndsAt) {
-		msg := "Failed to create silence: start time must be before end time"
-		level.Error(logger).Log("msg", msg, "starts_at", sil.StartsAt, "ends_at", sil.EndsAt)
+	// The time range of a recurring silence is derived from its schedule.
+	if sil.Schedule == nil {
+		if sil.StartsAt.After(sil.EndsAt) || sil.StartsAt.Equal(sil.EndsAt) {
+			msg := "Failed to create silence: start time must be before end time"
+			level.Error(logger).Log("msg", msg, "starts_at", sil.StartsAt, "ends_at", sil.EndsAt)
+			return silence_ops.NewPostSilencesBadRequest().WithPayload(msg)
+		}
+
+		if sil.EndsAt.Before(time.Now()) {
+			msg := "Failed to create silence: end time can't be in the past"
+			level.Error(logger).Log("msg", msg, "ends_at", sil.EndsAt)
+			return silence_ops.NewPostSilencesBadRequest().WithPayload(msg)
+		}
+	}
+
+	// A tenant can only update its own silences.
+	if sil.Id != "" {
+		if old, err := api.silences.QueryOne(silence.QIDs(sil.Id)); err == nil && !api.ownsSilence(params.HTTPRequest, old) {
+			return silence_ops.NewPostSilencesNotFound().WithPayload(silence.ErrNotFound.Error())
+		}
+	}
+	api.scopeSilence(params.HTTPRequest, sil)
+
+	if sil.NotifyReceiver != "" && !api.hasReceiver(sil.NotifyReceiver) {
+		msg := fmt.Sprintf("Failed to create silence: unknown receiver %q for expiry notification", sil.NotifyReceiver)
+		level.Error(logger).Log("msg", msg)
 		return silence_ops.NewPostSilencesBadRequest().WithPayload(msg)
 	}
 
-	if sil.EndsAt.Before(time.Now()) {
-		msg := "Failed to create silence: end time can't be in the past"
-		level.Error(logger).Log("msg", msg, "ends_at", sil.EndsAt)
+	if err := policy.CheckSilence(sil, api.silences, api.silencePolicy()); err != nil {
+		msg := fmt.Sprintf("Failed to create silence: %v", err)
+		level.Error(logger).Log("msg", msg)
 		return silence_ops.NewPostSilencesBadRequest().WithPayload(msg)
 	}
 
@@ -660,6 +1519,165 @@ func (api *API) postSilencesHandler(params silence_ops.PostSilencesParams) middl
 	})
 }
 
+func (api *API) getDeadLettersHandler(params deadletter_ops.GetDeadLettersParams) middleware.Responder {
+	res := open_api_models.DeadLetters{}
+	if api.deadLetters == nil {
+		return deadletter_ops.NewGetDeadLettersOK().WithPayload(res)
+	}
+
+	var receiver string
+	if params.Receiver != nil {
+		receiver = *params.Receiver
+	}
+	for _, e := range api.deadLetters.List(receiver) {
+		if api.ownsGroup(params.HTTPRequest, e.GroupLabels) {
+			res = append(res, DeadLetterToOpenAPIDeadLetter(e))
+		}
+	}
+	return deadletter_ops.NewGetDeadLettersOK().WithPayload(res)
+}
+
+func (api *API) getDeadLetterHandler(params deadletter_ops.GetDeadLetterParams) middleware.Responder {
+	if api.deadLetters == nil {
+		return deadletter_ops.NewGetDeadLetterNotFound()
+	}
+
+	e, err := api.deadLetters.Get(params.DeadLetterID.String())
+	if err != nil || !api.ownsGroup(params.HTTPRequest, e.GroupLabels) {
+		return deadletter_ops.NewGetDeadLetterNotFound()
+	}
+	return deadletter_ops.NewGetDeadLetterOK().WithPayload(DeadLetterToOpenAPIDeadLetter(e))
+}
+
+func (api *API) deleteDeadLetterHandler(params deadletter_ops.DeleteDeadLetterParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	if api.deadLetters == nil {
+		return deadletter_ops.NewDeleteDeadLetterNotFound()
+	}
+
+	id := params.DeadLetterID.String()
+	if e, err := api.deadLetters.Get(id); err != nil || !api.ownsGroup(params.HTTPRequest, e.GroupLabels) {
+		return deadletter_ops.NewDeleteDeadLetterNotFound()
+	}
+	if err := api.deadLetters.Delete(id); err != nil {
+		level.Debug(logger).Log("msg", "Failed to delete dead letter", "err", err, "id", id)
+		return deadletter_ops.NewDeleteDeadLetterNotFound()
+	}
+	return deadletter_ops.NewDeleteDeadLetterOK()
+}
+
+func (api *API) replayDeadLetterHandler(params deadletter_ops.ReplayDeadLetterParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	if api.deadLetters == nil || api.replay == nil {
+		return deadletter_ops.NewReplayDeadLetterNotFound()
+	}
+
+	id := params.DeadLetterID.String()
+	if e, err := api.deadLetters.Get(id); err != nil || !api.ownsGroup(params.HTTPRequest, e.GroupLabels) {
+		return deadletter_ops.NewReplayDeadLetterNotFound()
+	}
+	err := api.deadLetters.Replay(id, api.replay)
+	if err == deadletter.ErrNotFound {
+		return deadletter_ops.NewReplayDeadLetterNotFound()
+	}
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to replay dead letter", "err", err, "id", id)
+		return deadletter_ops.NewReplayDeadLetterInternalServerError().WithPayload(err.Error())
+	}
+	return deadletter_ops.NewReplayDeadLetterOK()
+}
+
+func (api *API) resendAlertGroupHandler(params alertgroup_ops.ResendAlertGroupParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	if api.resend == nil {
+		return alertgroup_ops.NewResendAlertGroupBadRequest().WithPayload("resending alert groups is not enabled")
+	}
+	groupKey, receiver := *params.Resend.GroupKey, *params.Resend.Receiver
+	if !api.ownsGroupKey(params.HTTPRequest, groupKey) {
+		return alertgroup_ops.NewResendAlertGroupNotFound()
+	}
+
+	switch err := api.resend(params.HTTPRequest.Context(), groupKey, receiver); err {
+	case nil:
+		return alertgroup_ops.NewResendAlertGroupOK()
+	case dispatch.ErrGroupNotFound:
+		return alertgroup_ops.NewResendAlertGroupNotFound()
+	case dispatch.ErrNoFiringAlerts:
+		return alertgroup_ops.NewResendAlertGroupBadRequest().WithPayload(err.Error())
+	default:
+		level.Error(logger).Log("msg", "Failed to resend alert group", "err", err, "groupKey", groupKey, "receiver", receiver)
+		return alertgroup_ops.NewResendAlertGroupInternalServerError().WithPayload(err.Error())
+	}
+}
+
+func (api *API) getAcksHandler(params ack_ops.GetAcksParams) middleware.Responder {
+	res := open_api_models.Acks{}
+	if api.acks == nil {
+		return ack_ops.NewGetAcksOK().WithPayload(res)
+	}
+
+	owns := api.ownedGroupKeys(params.HTTPRequest)
+	for _, a := range api.acks.List() {
+		if owns(a.GroupKey) {
+			res = append(res, AckToOpenAPIAck(a))
+		}
+	}
+	return ack_ops.NewGetAcksOK().WithPayload(res)
+}
+
+func (api *API) postAcksHandler(params ack_ops.PostAcksParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	if api.acks == nil {
+		return ack_ops.NewPostAcksBadRequest().WithPayload("acknowledgements are not enabled")
+	}
+	if !api.ownsGroupKey(params.HTTPRequest, *params.Ack.GroupKey) {
+		return ack_ops.NewPostAcksBadRequest().WithPayload(fmt.Sprintf("unknown alert group %q", *params.Ack.GroupKey))
+	}
+
+	a, err := api.acks.Set(&ack.Ack{
+		GroupKey: *params.Ack.GroupKey,
+		AckedBy:  *params.Ack.AckedBy,
+		Comment:  params.Ack.Comment,
+		EndsAt:   time.Time(*params.Ack.EndsAt),
+	})
+	if err != nil {
+		level.Debug(logger).Log("msg", "Failed to create acknowledgement", "err", err)
+		return ack_ops.NewPostAcksBadRequest().WithPayload(err.Error())
+	}
+	return ack_ops.NewPostAcksOK().WithPayload(AckToOpenAPIAck(a))
+}
+
+func (api *API) deleteAckHandler(params ack_ops.DeleteAckParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	if api.acks == nil {
+		return ack_ops.NewDeleteAckNotFound()
+	}
+
+	id := params.AckID.String()
+	if _, _, ok := api.tenant(params.HTTPRequest); ok {
+		var owned bool
+		for _, a := range api.acks.List() {
+			if a.ID == id {
+				owned = api.ownsGroupKey(params.HTTPRequest, a.GroupKey)
+				break
+			}
+		}
+		if !owned {
+			return ack_ops.NewDeleteAckNotFound()
+		}
+	}
+	if err := api.acks.Expire(id); err != nil {
+		level.Debug(logger).Log("msg", "Failed to expire acknowledgement", "err", err, "id", id)
+		return ack_ops.NewDeleteAckNotFound()
+	}
+	return ack_ops.NewDeleteAckOK()
+}
+
 func parseFilter(filter []string) ([]*labels.Matcher, error) {
 	matchers := make([]*labels.Matcher, 0, len(filter))
 	for _, matcherString := range filter {
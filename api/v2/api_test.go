@@ -14,18 +14,44 @@
 package v2
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/go-kit/log"
+	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
+	"github.com/prometheus/alertmanager/ack"
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/api/metrics"
 	open_api_models "github.com/prometheus/alertmanager/api/v2/models"
+	ack_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/ack"
+	alert_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/alert"
+	alertgroup_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/alertgroup"
 	general_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/general"
+	receiver_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/receiver"
+	silence_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/silence"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/deadletter"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/history"
+	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/provider/shard"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/audit"
 	"github.com/prometheus/alertmanager/silence/silencepb"
 	"github.com/prometheus/alertmanager/types"
 )
@@ -131,6 +157,107 @@ func TestGetSilencesHandler(t *testing.T) {
 	}
 }
 
+func TestGetSilencesHandlerFiltering(t *testing.T) {
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	now := time.Now()
+	create := func(createdBy string, start, end time.Time) string {
+		id, err := sils.Set(&silencepb.Silence{
+			Matchers:  []*silencepb.Matcher{createSilenceMatcher("job", createdBy, silencepb.Matcher_EQUAL)},
+			StartsAt:  start,
+			EndsAt:    end,
+			CreatedBy: createdBy,
+			Comment:   "test",
+		})
+		require.NoError(t, err)
+		return id
+	}
+	active1 := create("alice", now, now.Add(time.Hour))
+	active2 := create("bob", now, now.Add(2*time.Hour))
+	pending := create("alice", now.Add(3*time.Hour), now.Add(4*time.Hour))
+	expired := create("bob", now, now.Add(time.Hour))
+	require.NoError(t, sils.Expire(expired))
+
+	api := API{silences: sils, logger: log.NewNopLogger()}
+	since := strfmt.DateTime(now.Add(90 * time.Minute))
+	until := strfmt.DateTime(now.Add(150 * time.Minute))
+	alice := "alice"
+	one, two := int64(1), int64(2)
+
+	for _, tc := range []struct {
+		name     string
+		params   silence_ops.GetSilencesParams
+		expected []string
+		total    int64
+	}{
+		{
+			name:     "all",
+			expected: []string{active1, active2, pending, expired},
+		},
+		{
+			name:     "state",
+			params:   silence_ops.GetSilencesParams{State: []string{"active", "pending"}},
+			expected: []string{active1, active2, pending},
+		},
+		{
+			name:     "created by",
+			params:   silence_ops.GetSilencesParams{CreatedBy: &alice},
+			expected: []string{active1, pending},
+		},
+		{
+			name:     "matcher",
+			params:   silence_ops.GetSilencesParams{Filter: []string{`job="bob"`}},
+			expected: []string{active2, expired},
+		},
+		{
+			name:     "time range",
+			params:   silence_ops.GetSilencesParams{Since: &since, Until: &until},
+			expected: []string{active2},
+		},
+		{
+			name:     "limit",
+			params:   silence_ops.GetSilencesParams{Limit: &two},
+			expected: []string{active1, active2},
+			total:    4,
+		},
+		{
+			name:     "offset and limit",
+			params:   silence_ops.GetSilencesParams{Offset: &one, Limit: &one},
+			expected: []string{active2},
+			total:    4,
+		},
+		{
+			name:     "offset beyond the end",
+			params:   silence_ops.GetSilencesParams{Offset: &two, State: []string{"active"}},
+			expected: []string{},
+			total:    2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.params.HTTPRequest = httptest.NewRequest("GET", "/api/v2/silences", nil)
+			res, ok := api.getSilencesHandler(tc.params).(*silence_ops.GetSilencesOK)
+			require.True(t, ok)
+
+			ids := []string{}
+			for _, s := range res.Payload {
+				ids = append(ids, *s.ID)
+			}
+			require.Equal(t, tc.expected, ids)
+			if tc.total == 0 {
+				tc.total = int64(len(tc.expected))
+			}
+			require.Equal(t, tc.total, res.XTotalCount)
+		})
+	}
+
+	_, ok := api.getSilencesHandler(silence_ops.GetSilencesParams{
+		HTTPRequest: httptest.NewRequest("GET", "/api/v2/silences", nil),
+		Filter:      []string{"invalid"},
+	}).(*silence_ops.GetSilencesBadRequest)
+	require.True(t, ok)
+}
+
 func createSilenceMatcher(name string, pattern string, matcherType silencepb.Matcher_Type) *silencepb.Matcher {
 	return &silencepb.Matcher{
 		Name:    name,
@@ -234,6 +361,54 @@ func TestCheckSilenceMatchesFilterLabels(t *testing.T) {
 	}
 }
 
+func TestPostSilencesHandlerExpiryNotification(t *testing.T) {
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	api := API{
+		silences:           sils,
+		logger:             log.NewNopLogger(),
+		alertmanagerConfig: &config.Config{Receivers: []*config.Receiver{{Name: "team"}}},
+	}
+
+	now := time.Now()
+	post := func(receiver string) middleware.Responder {
+		name, pattern, comment, createdBy := "job", "api", "test", "alice"
+		isRegex, isEqual := false, true
+		before := "15m"
+		return api.postSilencesHandler(silence_ops.PostSilencesParams{
+			HTTPRequest: httptest.NewRequest("POST", "/api/v2/silences", nil),
+			Silence: &open_api_models.PostableSilence{
+				Silence: open_api_models.Silence{
+					Matchers:  open_api_models.Matchers{{Name: &name, Value: &pattern, IsRegex: &isRegex, IsEqual: &isEqual}},
+					StartsAt:  convertDateTime(now),
+					EndsAt:    convertDateTime(now.Add(time.Hour)),
+					Comment:   &comment,
+					CreatedBy: &createdBy,
+					ExpiryNotification: &open_api_models.SilenceExpiryNotification{
+						Receiver: &receiver,
+						Before:   &before,
+					},
+				},
+			},
+		})
+	}
+
+	require.IsType(t, &silence_ops.PostSilencesBadRequest{}, post("unknown"))
+
+	resp, ok := post("team").(*silence_ops.PostSilencesOK)
+	require.True(t, ok)
+	sil, err := sils.QueryOne(silence.QIDs(resp.Payload.SilenceID))
+	require.NoError(t, err)
+	require.Equal(t, "team", sil.NotifyReceiver)
+	require.Equal(t, 15*time.Minute, sil.NotifyBefore)
+
+	gettable, err := GettableSilenceFromProto(sil)
+	require.NoError(t, err)
+	require.Equal(t, "team", *gettable.ExpiryNotification.Receiver)
+	require.Equal(t, "15m", *gettable.ExpiryNotification.Before)
+}
+
 func convertDateTime(ts time.Time) *strfmt.DateTime {
 	dt := strfmt.DateTime(ts)
 	return &dt
@@ -314,3 +489,1338 @@ func TestMatchFilterLabels(t *testing.T) {
 		require.Equal(t, tc.expected, matchFilterLabels(ms, sms))
 	}
 }
+
+func TestRecurringSilence(t *testing.T) {
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	api := API{silences: sils, logger: log.NewNopLogger()}
+
+	name, pattern, comment, createdBy := "job", "api", "maintenance", "alice"
+	isRegex, isEqual := false, true
+	cron, duration := "0 22 * * sat", "4h"
+	// The time range of a recurring silence is ignored.
+	past := time.Now().Add(-time.Hour)
+	resp, ok := api.postSilencesHandler(silence_ops.PostSilencesParams{
+		HTTPRequest: httptest.NewRequest("POST", "/api/v2/silences", nil),
+		Silence: &open_api_models.PostableSilence{
+			Silence: open_api_models.Silence{
+				Matchers:  open_api_models.Matchers{{Name: &name, Value: &pattern, IsRegex: &isRegex, IsEqual: &isEqual}},
+				StartsAt:  convertDateTime(past),
+				EndsAt:    convertDateTime(past),
+				Comment:   &comment,
+				CreatedBy: &createdBy,
+				Schedule:  &open_api_models.SilenceSchedule{Cron: &cron, Duration: &duration},
+			},
+		},
+	}).(*silence_ops.PostSilencesOK)
+	require.True(t, ok)
+
+	sil, err := sils.QueryOne(silence.QIDs(resp.Payload.SilenceID))
+	require.NoError(t, err)
+	require.Equal(t, time.Saturday, sil.StartsAt.Weekday())
+	require.Equal(t, 4*time.Hour, sil.EndsAt.Sub(sil.StartsAt))
+
+	gettable, err := GettableSilenceFromProto(sil)
+	require.NoError(t, err)
+	require.Equal(t, cron, *gettable.Schedule.Cron)
+	require.Equal(t, duration, *gettable.Schedule.Duration)
+	require.Nil(t, gettable.Schedule.Until)
+
+	three := int64(3)
+	occ, ok := api.getSilenceOccurrencesHandler(silence_ops.GetSilenceOccurrencesParams{
+		HTTPRequest: httptest.NewRequest("GET", "/api/v2/silence/"+sil.Id+"/occurrences", nil),
+		SilenceID:   strfmt.UUID(sil.Id),
+		Limit:       &three,
+	}).(*silence_ops.GetSilenceOccurrencesOK)
+	require.True(t, ok)
+	require.Len(t, occ.Payload, 3)
+	for i, o := range occ.Payload {
+		require.Equal(t, sil.StartsAt.AddDate(0, 0, 7*i), time.Time(*o.StartsAt))
+	}
+
+	_, ok = api.getSilenceOccurrencesHandler(silence_ops.GetSilenceOccurrencesParams{
+		HTTPRequest: httptest.NewRequest("GET", "/api/v2/silence/unknown/occurrences", nil),
+		SilenceID:   strfmt.UUID("1ff9c8b4-26ad-46c5-a1dc-6e8b3f1c3f3b"),
+	}).(*silence_ops.GetSilenceOccurrencesNotFound)
+	require.True(t, ok)
+}
+
+func TestGetSilenceEventsHandler(t *testing.T) {
+	auditLog, err := audit.New(audit.Options{})
+	require.NoError(t, err)
+	sils, err := silence.New(silence.Options{Retention: time.Hour, OnChange: auditLog.Record})
+	require.NoError(t, err)
+
+	api := API{silences: sils, silenceAudit: auditLog, logger: log.NewNopLogger()}
+
+	now := time.Now()
+	id, err := sils.Set(&silencepb.Silence{
+		Matchers:  []*silencepb.Matcher{createSilenceMatcher("job", "api", silencepb.Matcher_EQUAL)},
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		CreatedBy: "alice",
+		Comment:   "test",
+	})
+	require.NoError(t, err)
+
+	bob := "bob"
+	resp := api.deleteSilenceHandler(silence_ops.DeleteSilenceParams{
+		HTTPRequest: httptest.NewRequest("DELETE", "/api/v2/silence/"+id, nil),
+		SilenceID:   strfmt.UUID(id),
+		ExpiredBy:   &bob,
+	})
+	require.IsType(t, &silence_ops.DeleteSilenceOK{}, resp)
+
+	events, ok := api.getSilenceEventsHandler(silence_ops.GetSilenceEventsParams{
+		HTTPRequest: httptest.NewRequest("GET", "/api/v2/silences/events", nil),
+		SilenceID:   &id,
+	}).(*silence_ops.GetSilenceEventsOK)
+	require.True(t, ok)
+	require.Len(t, events.Payload, 2)
+	require.Equal(t, "created", *events.Payload[0].Type)
+	require.Equal(t, "alice", events.Payload[0].User)
+	require.Equal(t, "expired", *events.Payload[1].Type)
+	require.Equal(t, "bob", events.Payload[1].User)
+	require.Equal(t, "endsAt", *events.Payload[1].Changes[0].Field)
+
+	events, ok = api.getSilenceEventsHandler(silence_ops.GetSilenceEventsParams{
+		HTTPRequest: httptest.NewRequest("GET", "/api/v2/silences/events", nil),
+		User:        &bob,
+	}).(*silence_ops.GetSilenceEventsOK)
+	require.True(t, ok)
+	require.Len(t, events.Payload, 1)
+}
+
+func TestPostSilencesHandlerPolicy(t *testing.T) {
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	api := API{
+		silences: sils,
+		logger:   log.NewNopLogger(),
+		alertmanagerConfig: &config.Config{
+			SilencePolicy: &config.SilencePolicyConfig{RequireComment: true},
+		},
+	}
+
+	name, pattern, comment, createdBy := "job", "api", "", "alice"
+	isRegex, isEqual := false, true
+	now := time.Now()
+	resp, ok := api.postSilencesHandler(silence_ops.PostSilencesParams{
+		HTTPRequest: httptest.NewRequest("POST", "/api/v2/silences", nil),
+		Silence: &open_api_models.PostableSilence{
+			Silence: open_api_models.Silence{
+				Matchers:  open_api_models.Matchers{{Name: &name, Value: &pattern, IsRegex: &isRegex, IsEqual: &isEqual}},
+				StartsAt:  convertDateTime(now),
+				EndsAt:    convertDateTime(now.Add(time.Hour)),
+				Comment:   &comment,
+				CreatedBy: &createdBy,
+			},
+		},
+	}).(*silence_ops.PostSilencesBadRequest)
+	require.True(t, ok)
+	require.Equal(t, "Failed to create silence: silence policy requires a comment", resp.Payload)
+}
+
+func TestSilencePresets(t *testing.T) {
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	api := API{
+		silences: sils,
+		logger:   log.NewNopLogger(),
+		alertmanagerConfig: &config.Config{
+			SilencePresets: []*config.SilencePreset{{
+				Name:     "deploy",
+				Matchers: []string{`service="{{ .service }}"`},
+				Duration: model.Duration(time.Hour),
+				Comment:  "Deploying {{ .service }}",
+			}},
+		},
+	}
+
+	list, ok := api.getSilencePresetsHandler(silence_ops.GetSilencePresetsParams{
+		HTTPRequest: httptest.NewRequest("GET", "/api/v2/silences/presets", nil),
+	}).(*silence_ops.GetSilencePresetsOK)
+	require.True(t, ok)
+	require.Len(t, list.Payload, 1)
+	require.Equal(t, "deploy", *list.Payload[0].Name)
+	require.Equal(t, "1h", *list.Payload[0].Duration)
+
+	createdBy := "alice"
+	post := func(name string, params map[string]string) middleware.Responder {
+		return api.postSilencePresetHandler(silence_ops.PostSilencePresetParams{
+			HTTPRequest: httptest.NewRequest("POST", "/api/v2/silences/presets/"+name, nil),
+			PresetName:  name,
+			Instantiation: &open_api_models.SilencePresetInstantiation{
+				CreatedBy:  &createdBy,
+				Parameters: params,
+			},
+		})
+	}
+
+	_, ok = post("unknown", nil).(*silence_ops.PostSilencePresetNotFound)
+	require.True(t, ok)
+
+	_, ok = post("deploy", nil).(*silence_ops.PostSilencePresetBadRequest)
+	require.True(t, ok)
+
+	resp, ok := post("deploy", map[string]string{"service": "api"}).(*silence_ops.PostSilencePresetOK)
+	require.True(t, ok)
+
+	sil, err := sils.QueryOne(silence.QIDs(resp.Payload.SilenceID))
+	require.NoError(t, err)
+	require.Equal(t, "Deploying api", sil.Comment)
+	require.Equal(t, "alice", sil.CreatedBy)
+	require.WithinDuration(t, time.Now().Add(time.Hour), sil.EndsAt, time.Minute)
+	require.Equal(t, "service", sil.Matchers[0].Name)
+	require.Equal(t, "api", sil.Matchers[0].Pattern)
+}
+
+func TestBulkSilences(t *testing.T) {
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	api := API{silences: sils, logger: log.NewNopLogger()}
+
+	now := time.Now()
+	for _, s := range []*silencepb.Silence{
+		{CreatedBy: "deploy-bot", Matchers: []*silencepb.Matcher{{Name: "job", Pattern: "a"}}},
+		{CreatedBy: "deploy-bot", Matchers: []*silencepb.Matcher{{Name: "job", Pattern: "b"}}},
+		{CreatedBy: "alice", Matchers: []*silencepb.Matcher{{Name: "job", Pattern: "a"}}},
+	} {
+		s.StartsAt, s.EndsAt = now, now.Add(time.Hour)
+		_, err := sils.Set(s)
+		require.NoError(t, err)
+	}
+
+	expire := func(req *open_api_models.BulkSilenceRequest) middleware.Responder {
+		return api.expireSilencesHandler(silence_ops.ExpireSilencesParams{
+			HTTPRequest: httptest.NewRequest("POST", "/api/v2/silences/expire", nil),
+			Request:     req,
+		})
+	}
+
+	// Selecting all silences requires an explicit filter.
+	_, ok := expire(&open_api_models.BulkSilenceRequest{}).(*silence_ops.ExpireSilencesBadRequest)
+	require.True(t, ok)
+
+	resp, ok := expire(&open_api_models.BulkSilenceRequest{CreatedBy: "deploy-bot", DryRun: true}).(*silence_ops.ExpireSilencesOK)
+	require.True(t, ok)
+	require.Len(t, resp.Payload.Silences, 2)
+	active, _, err := sils.Query(silence.QState(types.SilenceStateActive))
+	require.NoError(t, err)
+	require.Len(t, active, 3)
+
+	resp, ok = expire(&open_api_models.BulkSilenceRequest{CreatedBy: "deploy-bot", User: "bob"}).(*silence_ops.ExpireSilencesOK)
+	require.True(t, ok)
+	require.Len(t, resp.Payload.Silences, 2)
+	active, _, err = sils.Query(silence.QState(types.SilenceStateActive))
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	require.Equal(t, "alice", active[0].CreatedBy)
+
+	recreate := func(req *open_api_models.BulkSilenceRequest) middleware.Responder {
+		return api.recreateSilencesHandler(silence_ops.RecreateSilencesParams{
+			HTTPRequest: httptest.NewRequest("POST", "/api/v2/silences/recreate", nil),
+			Request:     req,
+		})
+	}
+
+	_, ok = recreate(&open_api_models.BulkSilenceRequest{CreatedBy: "deploy-bot"}).(*silence_ops.RecreateSilencesBadRequest)
+	require.True(t, ok)
+
+	rresp, ok := recreate(&open_api_models.BulkSilenceRequest{
+		Filter:    []string{`job="a"`},
+		CreatedBy: "deploy-bot",
+		Duration:  "2h",
+	}).(*silence_ops.RecreateSilencesOK)
+	require.True(t, ok)
+	require.Len(t, rresp.Payload.Silences, 1)
+	require.Len(t, rresp.Payload.Recreated, 1)
+
+	sil, err := sils.QueryOne(silence.QIDs(rresp.Payload.Recreated[*rresp.Payload.Silences[0].ID]))
+	require.NoError(t, err)
+	require.Equal(t, "deploy-bot", sil.CreatedBy)
+	require.Equal(t, types.SilenceStateActive, types.CalcSilenceState(sil.StartsAt, sil.EndsAt))
+	require.WithinDuration(t, time.Now().Add(2*time.Hour), sil.EndsAt, time.Minute)
+}
+
+func TestAlertStream(t *testing.T) {
+	now := time.Now()
+	var silencedBy []string
+	s := newAlertStream(
+		[]*labels.Matcher{mustMatcher(t, labels.MatchEqual, "team", "db")},
+		func(*types.Alert) types.AlertStatus {
+			return types.AlertStatus{SilencedBy: silencedBy}
+		},
+	)
+	alert := func(team string, endsAt time.Time) *types.Alert {
+		return &types.Alert{Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "a", "team": model.LabelValue(team)},
+			StartsAt: now.Add(-time.Minute),
+			EndsAt:   endsAt,
+		}}
+	}
+	typ := func(ev alertEvent, ok bool) string {
+		require.True(t, ok)
+		return ev.typ
+	}
+
+	_, ok := s.update(alert("web", now.Add(time.Hour)), now)
+	require.False(t, ok)
+	// Alerts resolved before being seen are not reported.
+	_, ok = s.update(alert("db", now.Add(-time.Second)), now)
+	require.False(t, ok)
+
+	require.Equal(t, "new", typ(s.update(alert("db", now.Add(time.Hour)), now)))
+	require.Equal(t, "updated", typ(s.update(alert("db", now.Add(2*time.Hour)), now)))
+	require.Empty(t, s.check(now))
+
+	silencedBy = []string{"id"}
+	events := s.check(now)
+	require.Len(t, events, 1)
+	require.Equal(t, "silenced", events[0].typ)
+	silencedBy = nil
+	require.Equal(t, "active", typ(s.update(alert("db", now.Add(2*time.Hour)), now)))
+
+	// Alerts timing out are resolved.
+	events = s.check(now.Add(3 * time.Hour))
+	require.Len(t, events, 1)
+	require.Equal(t, "resolved", events[0].typ)
+	require.Empty(t, s.check(now.Add(3*time.Hour)))
+}
+
+func mustMatcher(t *testing.T, mType labels.MatchType, name, val string) *labels.Matcher {
+	m, err := labels.NewMatcher(mType, name, val)
+	require.NoError(t, err)
+	return m
+}
+
+func TestGetAlertsStateFilter(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	now := time.Now()
+	var fps []model.Fingerprint
+	for _, name := range []string{"active", "unprocessed", "silenced"} {
+		a := &types.Alert{Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": model.LabelValue(name)},
+			StartsAt: now,
+			EndsAt:   now.Add(time.Hour),
+		}}
+		require.NoError(t, alerts.Put(a))
+		fps = append(fps, a.Fingerprint())
+	}
+	marker.SetSilenced(fps[0], 0, nil, nil)
+	marker.SetSilenced(fps[2], 0, []string{"id"}, nil)
+
+	api := API{
+		alerts:         alerts,
+		getAlertStatus: marker.Status,
+		setAlertStatus: func(model.LabelSet) {},
+		route:          dispatch.NewRoute(&config.Route{Receiver: "team"}, nil),
+		logger:         log.NewNopLogger(),
+	}
+
+	for _, tc := range []struct {
+		active, silenced, unprocessed bool
+		expected                      []string
+	}{
+		{true, true, true, []string{"active", "silenced", "unprocessed"}},
+		{true, true, false, []string{"active", "silenced"}},
+		{false, true, true, []string{"silenced", "unprocessed"}},
+		{true, false, true, []string{"active", "unprocessed"}},
+	} {
+		params := alert_ops.NewGetAlertsParams()
+		params.HTTPRequest = httptest.NewRequest("GET", "/api/v2/alerts", nil)
+		params.Active = &tc.active
+		params.Silenced = &tc.silenced
+		params.Unprocessed = &tc.unprocessed
+
+		resp, ok := api.getAlertsHandler(params).(*alert_ops.GetAlertsOK)
+		require.True(t, ok)
+
+		var names []string
+		for _, a := range resp.Payload {
+			names = append(names, a.Labels["alertname"])
+		}
+		sort.Strings(names)
+		require.Equal(t, tc.expected, names)
+	}
+}
+
+func TestGetAlertsSortAndPagination(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	now := time.Now()
+	for i, a := range []struct{ name, severity string }{
+		{"c", "warning"},
+		{"a", "info"},
+		{"d", "critical"},
+		{"b", "unknown"},
+	} {
+		require.NoError(t, alerts.Put(&types.Alert{Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": model.LabelValue(a.name), "severity": model.LabelValue(a.severity)},
+			StartsAt: now.Add(-time.Duration(i) * time.Minute),
+			EndsAt:   now.Add(time.Hour),
+		}}))
+	}
+
+	global := config.DefaultGlobalConfig()
+	global.SeverityMapping = []*config.SeverityMapping{
+		{Severity: "critical"},
+		{Severity: "warning"},
+		{Severity: "info"},
+	}
+	api := API{
+		alerts:             alerts,
+		getAlertStatus:     marker.Status,
+		setAlertStatus:     func(model.LabelSet) {},
+		route:              dispatch.NewRoute(&config.Route{Receiver: "team"}, nil),
+		alertmanagerConfig: &config.Config{Global: &global},
+		logger:             log.NewNopLogger(),
+	}
+
+	for _, tc := range []struct {
+		sort          string
+		offset, limit int64
+		expected      []string
+	}{
+		{"alertname", 0, 0, []string{"a", "b", "c", "d"}},
+		{"startsAt", 0, 0, []string{"b", "d", "a", "c"}},
+		{"severity", 0, 0, []string{"d", "c", "a", "b"}},
+		{"alertname", 1, 2, []string{"b", "c"}},
+		{"alertname", 3, 2, []string{"d"}},
+		{"alertname", 4, 0, []string{}},
+	} {
+		params := alert_ops.NewGetAlertsParams()
+		params.HTTPRequest = httptest.NewRequest("GET", "/api/v2/alerts", nil)
+		params.Sort = &tc.sort
+		params.Offset = &tc.offset
+		params.Limit = &tc.limit
+
+		resp, ok := api.getAlertsHandler(params).(*alert_ops.GetAlertsOK)
+		require.True(t, ok)
+		require.Equal(t, int64(4), resp.XTotalCount)
+
+		names := []string{}
+		for _, a := range resp.Payload {
+			names = append(names, a.Labels["alertname"])
+		}
+		require.Equal(t, tc.expected, names, "sort=%s offset=%d limit=%d", tc.sort, tc.offset, tc.limit)
+	}
+}
+
+func TestGetAlertStatsHandler(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	now := time.Now()
+	for _, a := range []struct {
+		name, severity, team string
+		endsAt               time.Time
+	}{
+		{"DiskFull", "warning", "db", now.Add(time.Hour)},
+		{"DiskFull", "critical", "web", now.Add(time.Hour)},
+		{"HighLatency", "", "web", now.Add(-30 * time.Minute)},
+		{"InstanceDown", "critical", "db", now.Add(-2 * time.Hour)},
+	} {
+		lset := model.LabelSet{"alertname": model.LabelValue(a.name), "team": model.LabelValue(a.team)}
+		if a.severity != "" {
+			lset["severity"] = model.LabelValue(a.severity)
+		}
+		require.NoError(t, alerts.Put(&types.Alert{Alert: model.Alert{
+			Labels:   lset,
+			StartsAt: now.Add(-3 * time.Hour),
+			EndsAt:   a.endsAt,
+		}}))
+	}
+
+	global := config.DefaultGlobalConfig()
+	api := API{
+		alerts: alerts,
+		route: dispatch.NewRoute(&config.Route{
+			Receiver: "default",
+			Routes: []*config.Route{
+				{Receiver: "db", Match: map[string]string{"team": "db"}},
+			},
+		}, nil),
+		alertmanagerConfig: &config.Config{Global: &global},
+		logger:             log.NewNopLogger(),
+	}
+
+	stats := func(filter []string, windows ...string) middleware.Responder {
+		params := alert_ops.NewGetAlertStatsParams()
+		params.HTTPRequest = httptest.NewRequest("GET", "/api/v2/stats", nil)
+		params.Filter = filter
+		params.Window = windows
+		return api.getAlertStatsHandler(params)
+	}
+	window := func(name string, total int64, byAlertname, bySeverity, byReceiver open_api_models.AlertCounts) *open_api_models.AlertStatsWindow {
+		return &open_api_models.AlertStatsWindow{
+			Window:      &name,
+			Total:       &total,
+			ByAlertname: byAlertname,
+			BySeverity:  bySeverity,
+			ByReceiver:  byReceiver,
+		}
+	}
+
+	resp, ok := stats(nil).(*alert_ops.GetAlertStatsOK)
+	require.True(t, ok)
+	require.Equal(t, []*open_api_models.AlertStatsWindow{
+		window("1h", 3,
+			open_api_models.AlertCounts{"DiskFull": 2, "HighLatency": 1},
+			open_api_models.AlertCounts{"warning": 1, "critical": 1},
+			open_api_models.AlertCounts{"db": 1, "default": 2},
+		),
+		window("24h", 4,
+			open_api_models.AlertCounts{"DiskFull": 2, "HighLatency": 1, "InstanceDown": 1},
+			open_api_models.AlertCounts{"warning": 1, "critical": 2},
+			open_api_models.AlertCounts{"db": 2, "default": 2},
+		),
+	}, resp.Payload.Windows)
+
+	resp, ok = stats([]string{`team="db"`}, "10m").(*alert_ops.GetAlertStatsOK)
+	require.True(t, ok)
+	require.Equal(t, []*open_api_models.AlertStatsWindow{
+		window("10m", 1,
+			open_api_models.AlertCounts{"DiskFull": 1},
+			open_api_models.AlertCounts{"warning": 1},
+			open_api_models.AlertCounts{"db": 1},
+		),
+	}, resp.Payload.Windows)
+
+	bad, ok := stats(nil, "1x").(*alert_ops.GetAlertStatsBadRequest)
+	require.True(t, ok)
+	require.Equal(t, `invalid window "1x"`, bad.Payload)
+}
+
+func TestGetReceiversHandler(t *testing.T) {
+	api := API{
+		alertmanagerConfig: &config.Config{
+			Receivers: []*config.Receiver{
+				{Name: "blackhole"},
+				{
+					Name: "team",
+					SlackConfigs: []*config.SlackConfig{
+						{NotifierConfig: config.NotifierConfig{VSendResolved: true}},
+						{},
+					},
+					WebhookConfigs: []*config.WebhookConfig{{}},
+				},
+			},
+		},
+		logger: log.NewNopLogger(),
+	}
+
+	resp, ok := api.getReceiversHandler(receiver_ops.GetReceiversParams{
+		HTTPRequest: httptest.NewRequest("GET", "/api/v2/receivers", nil),
+	}).(*receiver_ops.GetReceiversOK)
+	require.True(t, ok)
+
+	integration := func(name string, index int64, sendResolved bool) *open_api_models.Integration {
+		return &open_api_models.Integration{Name: &name, Index: &index, SendResolved: &sendResolved}
+	}
+	require.Equal(t, []*open_api_models.Receiver{
+		{Name: swag.String("blackhole"), Integrations: []*open_api_models.Integration{}},
+		{Name: swag.String("team"), Integrations: []*open_api_models.Integration{
+			integration("webhook", 0, false),
+			integration("slack", 0, true),
+			integration("slack", 1, false),
+		}},
+	}, resp.Payload)
+}
+
+func TestGetReceiversHealthHandler(t *testing.T) {
+	deadLetters, err := deadletter.New(deadletter.Options{Retention: time.Hour})
+	require.NoError(t, err)
+	_, err = deadLetters.Add(&deadletter.Entry{Receiver: "team", Integration: "slack", GroupKey: "{}:{}"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	health := notify.NewHealth()
+	health.RecordAttempt(notify.Attempt{Time: now, Receiver: "team", Integration: "webhook", Outcome: notify.AttemptSucceeded})
+	health.RecordAttempt(notify.Attempt{Time: now, Receiver: "team", Integration: "slack", Index: 1, Outcome: notify.AttemptSucceeded})
+	health.RecordAttempt(notify.Attempt{Time: now, Receiver: "team", Integration: "slack", Index: 1, Outcome: notify.AttemptFailed, StatusCode: http.StatusTooManyRequests, Error: "throttled"})
+	health.RecordAttempt(notify.Attempt{Time: now, Receiver: "ops", Integration: "webhook", Outcome: notify.AttemptSucceeded})
+
+	api := API{
+		alertmanagerConfig: &config.Config{
+			Receivers: []*config.Receiver{
+				{Name: "blackhole"},
+				{
+					Name:           "team",
+					SlackConfigs:   []*config.SlackConfig{{}, {}},
+					WebhookConfigs: []*config.WebhookConfig{{}},
+				},
+				{
+					Name:           "ops",
+					WebhookConfigs: []*config.WebhookConfig{{}},
+				},
+			},
+		},
+		deadLetters: deadLetters,
+		health:      health,
+		logger:      log.NewNopLogger(),
+	}
+
+	resp, ok := api.getReceiversHealthHandler(receiver_ops.GetReceiversHealthParams{
+		HTTPRequest: httptest.NewRequest("GET", "/api/v2/receivers/health", nil),
+	}).(*receiver_ops.GetReceiversHealthOK)
+	require.True(t, ok)
+
+	ts := strfmt.DateTime(now)
+	require.Equal(t, open_api_models.ReceiversHealth{
+		{
+			Name:         swag.String("blackhole"),
+			State:        swag.String(notify.HealthUnknown),
+			DeadLetters:  swag.Int64(0),
+			Integrations: []*open_api_models.IntegrationHealth{},
+		},
+		{
+			Name:        swag.String("team"),
+			State:       swag.String(notify.HealthFailing),
+			DeadLetters: swag.Int64(1),
+			Integrations: []*open_api_models.IntegrationHealth{
+				{
+					Name:                swag.String("webhook"),
+					Index:               swag.Int64(0),
+					State:               swag.String(notify.HealthHealthy),
+					LastSuccess:         ts,
+					ConsecutiveFailures: swag.Int64(0),
+				},
+				{
+					Name:                swag.String("slack"),
+					Index:               swag.Int64(0),
+					State:               swag.String(notify.HealthUnknown),
+					ConsecutiveFailures: swag.Int64(0),
+				},
+				{
+					Name:                swag.String("slack"),
+					Index:               swag.Int64(1),
+					State:               swag.String(notify.HealthFailing),
+					LastSuccess:         ts,
+					LastFailure:         ts,
+					LastError:           "throttled",
+					ConsecutiveFailures: swag.Int64(1),
+					LastThrottled:       ts,
+				},
+			},
+		},
+		{
+			Name:        swag.String("ops"),
+			State:       swag.String(notify.HealthHealthy),
+			DeadLetters: swag.Int64(0),
+			Integrations: []*open_api_models.IntegrationHealth{
+				{
+					Name:                swag.String("webhook"),
+					Index:               swag.Int64(0),
+					State:               swag.String(notify.HealthHealthy),
+					LastSuccess:         ts,
+					ConsecutiveFailures: swag.Int64(0),
+				},
+			},
+		},
+	}, resp.Payload)
+
+	getNames := func(identity string) []string {
+		req := httptest.NewRequest("GET", "/api/v2/receivers/health", nil)
+		req = req.WithContext(auth.WithIdentity(req.Context(), identity))
+		resp, ok := api.getReceiversHealthHandler(receiver_ops.GetReceiversHealthParams{HTTPRequest: req}).(*receiver_ops.GetReceiversHealthOK)
+		require.True(t, ok)
+		var names []string
+		for _, r := range resp.Payload {
+			names = append(names, *r.Name)
+		}
+		return names
+	}
+
+	// A tenant only sees the receivers of its overlay.
+	api.alertmanagerConfig.Receivers = append(api.alertmanagerConfig.Receivers, &config.Receiver{Name: "a/team"})
+	api.alertmanagerConfig.Tenancy = &config.TenancyConfig{
+		Header:  "X-Scope-OrgID",
+		Label:   "tenant",
+		Tenants: map[string]string{"prometheus-a": "a"},
+	}
+	require.Equal(t, []string{"a/team"}, getNames("prometheus-a"))
+
+	// No receivers are known before the configuration is loaded.
+	api.alertmanagerConfig = nil
+	require.Len(t, getNames("prometheus-a"), 0)
+}
+
+func TestGetBudgetsHandler(t *testing.T) {
+	conf, err := config.Load(`
+tenancy:
+  header: X-Scope-OrgID
+  label: tenant
+  tenants:
+    prometheus-a: a
+  budget:
+    weekly: 10
+authorization:
+  roles:
+    alice: admin
+route:
+  receiver: team
+receivers:
+- name: team
+  budget:
+    daily: 5
+- name: a/team
+  budget:
+    daily: 5
+`)
+	require.NoError(t, err)
+
+	budgets := notify.NewBudgets(nil, nil, nil)
+	budgets.Update(conf)
+	deliver := notify.StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		return ctx, alerts, nil
+	})
+	now := time.Now()
+	for _, n := range []struct{ receiver, tenant string }{{"team", "b"}, {"a/team", "a"}} {
+		stage := budgets.Stage(n.receiver, notify.NewIntegration(nil, nil, "webhook", 0), deliver, deliver)
+		alert := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a", "tenant": model.LabelValue(n.tenant)}}}
+		_, _, err = stage.Exec(notify.WithNow(context.Background(), now), log.NewNopLogger(), alert)
+		require.NoError(t, err)
+	}
+
+	api := API{
+		alertmanagerConfig: conf,
+		budgets:            budgets,
+		logger:             log.NewNopLogger(),
+	}
+	getBudgets := func(identity string) []string {
+		req := httptest.NewRequest("GET", "/api/v2/budgets", nil)
+		req = req.WithContext(auth.WithIdentity(req.Context(), identity))
+		resp, ok := api.getBudgetsHandler(receiver_ops.GetBudgetsParams{HTTPRequest: req}).(*receiver_ops.GetBudgetsOK)
+		require.True(t, ok)
+		var res []string
+		for _, b := range resp.Payload {
+			require.Equal(t, int64(1), *b.Used)
+			res = append(res, *b.Scope+"/"+*b.Name+"/"+*b.Window)
+		}
+		return res
+	}
+
+	require.Equal(t, []string{
+		"receiver/a/team/daily",
+		"receiver/team/daily",
+		"tenant/a/weekly",
+		"tenant/b/weekly",
+	}, getBudgets("alice"))
+	// A tenant only sees its own budgets.
+	require.Equal(t, []string{
+		"receiver/a/team/daily",
+		"tenant/a/weekly",
+	}, getBudgets("prometheus-a"))
+
+	api.budgets = nil
+	require.Len(t, getBudgets("alice"), 0)
+}
+
+func TestGetNotificationHistoryHandler(t *testing.T) {
+	notificationHistory, err := history.New(history.Options{})
+	require.NoError(t, err)
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	notificationHistory.RecordAttempt(notify.Attempt{
+		Time:         now,
+		Receiver:     "team",
+		Integration:  "webhook",
+		GroupKey:     `{}:{tenant="a"}`,
+		GroupLabels:  model.LabelSet{"tenant": "a"},
+		Fingerprints: []model.Fingerprint{1},
+		Number:       1,
+		Outcome:      notify.AttemptFailed,
+		StatusCode:   http.StatusServiceUnavailable,
+		Error:        "unavailable",
+	})
+	notificationHistory.RecordAttempt(notify.Attempt{
+		Time:         now.Add(time.Minute),
+		Receiver:     "team",
+		Integration:  "webhook",
+		GroupKey:     `{}:{tenant="a"}`,
+		GroupLabels:  model.LabelSet{"tenant": "a"},
+		Fingerprints: []model.Fingerprint{1},
+		Number:       2,
+		Outcome:      notify.AttemptSucceeded,
+	})
+	notificationHistory.RecordAttempt(notify.Attempt{
+		Time:        now.Add(2 * time.Minute),
+		Receiver:    "ops",
+		Integration: "slack",
+		GroupKey:    `{}:{tenant="b"}`,
+		GroupLabels: model.LabelSet{"tenant": "b"},
+		Number:      1,
+		Outcome:     notify.AttemptSucceeded,
+	})
+
+	api := API{
+		alertmanagerConfig: &config.Config{
+			Tenancy: &config.TenancyConfig{
+				Header:  "X-Scope-OrgID",
+				Label:   "tenant",
+				Tenants: map[string]string{"prometheus-b": "b"},
+			},
+			Authorization: &config.AuthorizationConfig{Roles: map[string]config.Role{"alice": config.RoleAdmin}},
+		},
+		history: notificationHistory,
+		logger:  log.NewNopLogger(),
+	}
+	getHistory := func(identity string, params receiver_ops.GetNotificationHistoryParams) open_api_models.NotificationHistory {
+		req := httptest.NewRequest("GET", "/api/v2/history", nil)
+		params.HTTPRequest = req.WithContext(auth.WithIdentity(req.Context(), identity))
+		resp, ok := api.getNotificationHistoryHandler(params).(*receiver_ops.GetNotificationHistoryOK)
+		require.True(t, ok)
+		return resp.Payload
+	}
+
+	ts := strfmt.DateTime(now)
+	require.Equal(t, &open_api_models.NotificationHistoryEntry{
+		Time:         &ts,
+		Receiver:     swag.String("team"),
+		Integration:  swag.String("webhook"),
+		Index:        swag.Int64(0),
+		GroupKey:     swag.String(`{}:{tenant="a"}`),
+		GroupLabels:  open_api_models.LabelSet{"tenant": "a"},
+		Fingerprints: []string{model.Fingerprint(1).String()},
+		Attempt:      swag.Int64(1),
+		Outcome:      swag.String(notify.AttemptFailed),
+		StatusCode:   http.StatusServiceUnavailable,
+		Error:        "unavailable",
+	}, getHistory("alice", receiver_ops.GetNotificationHistoryParams{})[0])
+
+	attempts := func(h open_api_models.NotificationHistory) []string {
+		var res []string
+		for _, e := range h {
+			res = append(res, fmt.Sprintf("%s/%d", *e.Receiver, *e.Attempt))
+		}
+		return res
+	}
+	require.Equal(t, []string{"team/1", "team/2", "ops/1"}, attempts(getHistory("alice", receiver_ops.GetNotificationHistoryParams{})))
+	require.Equal(t, []string{"ops/1"}, attempts(getHistory("alice", receiver_ops.GetNotificationHistoryParams{Receiver: swag.String("ops")})))
+	since, until := strfmt.DateTime(now.Add(time.Minute)), strfmt.DateTime(now.Add(time.Minute))
+	require.Equal(t, []string{"team/2"}, attempts(getHistory("alice", receiver_ops.GetNotificationHistoryParams{Since: &since, Until: &until})))
+	// A tenant only sees the notifications of its alert groups.
+	require.Equal(t, []string{"ops/1"}, attempts(getHistory("prometheus-b", receiver_ops.GetNotificationHistoryParams{})))
+
+	api.history = nil
+	require.Len(t, getHistory("alice", receiver_ops.GetNotificationHistoryParams{}), 0)
+}
+
+func TestRenderReceiverHandler(t *testing.T) {
+	var rendered []*types.Alert
+	api := API{
+		alertmanagerConfig: &config.Config{
+			Global:    &config.GlobalConfig{ResolveTimeout: model.Duration(5 * time.Minute)},
+			Receivers: []*config.Receiver{{Name: "team"}, {Name: "unused"}},
+		},
+		render: func(_ context.Context, receiver string, alerts []*types.Alert) ([]notify.Rendered, bool) {
+			if receiver != "team" {
+				return nil, false
+			}
+			rendered = alerts
+			return []notify.Rendered{
+				{
+					Integration: "webhook",
+					Index:       0,
+					Payloads:    []notify.Payload{{ContentType: "application/json", Body: []byte(`{"status":"firing"}`)}},
+				},
+				{
+					Integration: "email",
+					Index:       0,
+					Payloads:    []notify.Payload{},
+					Err:         errors.New("execute 'to' template"),
+				},
+			}, true
+		},
+		logger: log.NewNopLogger(),
+	}
+
+	render := func(name string, alerts ...*open_api_models.PostableAlert) middleware.Responder {
+		return api.renderReceiverHandler(receiver_ops.RenderReceiverParams{
+			HTTPRequest: httptest.NewRequest("POST", "/api/v2/receivers/"+name+"/render", nil),
+			Name:        name,
+			Alerts:      alerts,
+		})
+	}
+	alert := &open_api_models.PostableAlert{
+		Alert: open_api_models.Alert{Labels: open_api_models.LabelSet{"alertname": "HighLatency"}},
+	}
+
+	resp, ok := render("team", alert).(*receiver_ops.RenderReceiverOK)
+	require.True(t, ok)
+	require.Equal(t, open_api_models.RenderedNotifications{
+		{
+			Integration: swag.String("webhook"),
+			Index:       swag.Int64(0),
+			Payloads: []*open_api_models.RenderedPayload{
+				{ContentType: swag.String("application/json"), Body: swag.String(`{"status":"firing"}`)},
+			},
+		},
+		{
+			Integration: swag.String("email"),
+			Index:       swag.Int64(0),
+			Payloads:    []*open_api_models.RenderedPayload{},
+			Error:       "execute 'to' template",
+		},
+	}, resp.Payload)
+
+	// Alerts without an end time are considered firing.
+	require.Len(t, rendered, 1)
+	require.Equal(t, model.LabelSet{"alertname": "HighLatency"}, rendered[0].Labels)
+	require.Equal(t, model.AlertFiring, rendered[0].Status())
+
+	_, ok = render("team", &open_api_models.PostableAlert{}).(*receiver_ops.RenderReceiverBadRequest)
+	require.True(t, ok)
+	_, ok = render("unused", alert).(*receiver_ops.RenderReceiverNotFound)
+	require.True(t, ok)
+	_, ok = render("unknown", alert).(*receiver_ops.RenderReceiverNotFound)
+	require.True(t, ok)
+}
+
+func TestTestReceiverHandler(t *testing.T) {
+	var tested []*types.Alert
+	api := API{
+		alertmanagerConfig: &config.Config{
+			Receivers: []*config.Receiver{{Name: "team"}, {Name: "unused"}},
+		},
+		test: func(_ context.Context, receiver string, alerts []*types.Alert) ([]notify.TestResult, bool) {
+			if receiver != "team" {
+				return nil, false
+			}
+			tested = alerts
+			return []notify.TestResult{
+				{Integration: "slack", Index: 0},
+				{Integration: "pagerduty", Index: 0, Err: errors.New("unexpected status code 400")},
+			}, true
+		},
+		logger: log.NewNopLogger(),
+	}
+
+	test := func(name string) middleware.Responder {
+		return api.testReceiverHandler(receiver_ops.TestReceiverParams{
+			HTTPRequest: httptest.NewRequest("POST", "/api/v2/receivers/"+name+"/test", nil),
+			Name:        name,
+		})
+	}
+
+	resp, ok := test("team").(*receiver_ops.TestReceiverOK)
+	require.True(t, ok)
+	require.Equal(t, open_api_models.IntegrationTestResults{
+		{Integration: swag.String("slack"), Index: swag.Int64(0), Success: swag.Bool(true)},
+		{Integration: swag.String("pagerduty"), Index: swag.Int64(0), Success: swag.Bool(false), Error: "unexpected status code 400"},
+	}, resp.Payload)
+
+	require.Len(t, tested, 1)
+	require.Equal(t, model.LabelSet{"alertname": "TestAlert", "receiver": "team"}, tested[0].Labels)
+	require.NoError(t, tested[0].Validate())
+	require.Equal(t, model.AlertFiring, tested[0].Status())
+
+	_, ok = test("unused").(*receiver_ops.TestReceiverNotFound)
+	require.True(t, ok)
+	_, ok = test("unknown").(*receiver_ops.TestReceiverNotFound)
+	require.True(t, ok)
+}
+
+func TestResendAlertGroupHandler(t *testing.T) {
+	api := API{
+		resend: func(_ context.Context, groupKey, receiver string) error {
+			if groupKey != `{}:{alertname="HighLatency"}` || receiver != "team" {
+				return dispatch.ErrGroupNotFound
+			}
+			return nil
+		},
+		logger: log.NewNopLogger(),
+	}
+
+	resend := func(groupKey, receiver string) middleware.Responder {
+		return api.resendAlertGroupHandler(alertgroup_ops.ResendAlertGroupParams{
+			HTTPRequest: httptest.NewRequest("POST", "/api/v2/alerts/groups/resend", nil),
+			Resend:      &open_api_models.ResendRequest{GroupKey: &groupKey, Receiver: &receiver},
+		})
+	}
+
+	require.IsType(t, &alertgroup_ops.ResendAlertGroupOK{}, resend(`{}:{alertname="HighLatency"}`, "team"))
+	require.IsType(t, &alertgroup_ops.ResendAlertGroupNotFound{}, resend(`{}:{alertname="HighLatency"}`, "other"))
+
+	api.resend = func(context.Context, string, string) error { return dispatch.ErrNoFiringAlerts }
+	require.IsType(t, &alertgroup_ops.ResendAlertGroupBadRequest{}, resend(`{}:{alertname="HighLatency"}`, "team"))
+	api.resend = func(context.Context, string, string) error { return dispatch.ErrResendFailed }
+	require.IsType(t, &alertgroup_ops.ResendAlertGroupInternalServerError{}, resend(`{}:{alertname="HighLatency"}`, "team"))
+}
+
+func TestPostAlertsLabelValidation(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	global := config.DefaultGlobalConfig()
+	api := API{
+		alerts:             alerts,
+		route:              dispatch.NewRoute(&config.Route{Receiver: "team"}, nil),
+		alertmanagerConfig: &config.Config{Global: &global},
+		logger:             log.NewNopLogger(),
+		m:                  metrics.NewAlerts("v2", nil),
+	}
+
+	post := func() middleware.Responder {
+		return api.postAlertsHandler(alert_ops.PostAlertsParams{
+			HTTPRequest: httptest.NewRequest("POST", "/api/v2/alerts", nil),
+			Alerts: open_api_models.PostableAlerts{{
+				Alert: open_api_models.Alert{Labels: open_api_models.LabelSet{"alertname": "HighLatency", "service.name": "api"}},
+			}},
+		})
+	}
+
+	require.IsType(t, &alert_ops.PostAlertsBadRequest{}, post())
+
+	global.LabelValidation = config.LabelValidationUTF8
+	require.IsType(t, &alert_ops.PostAlertsOK{}, post())
+	_, err = alerts.Get(model.LabelSet{"alertname": "HighLatency", "service.name": "api"}.Fingerprint())
+	require.NoError(t, err)
+}
+
+func TestPostAlertsUnrouted(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	global := config.DefaultGlobalConfig()
+	api := API{
+		alerts: alerts,
+		route: dispatch.NewRoute(&config.Route{
+			Receiver: "default",
+			Unrouted: &config.UnroutedConfig{Action: config.UnroutedReject},
+			Routes: []*config.Route{
+				{Receiver: "team-db", Match: map[string]string{"team": "db"}},
+			},
+		}, nil),
+		alertmanagerConfig: &config.Config{Global: &global},
+		logger:             log.NewNopLogger(),
+		m:                  metrics.NewAlerts("v2", nil),
+	}
+
+	res := api.postAlertsHandler(alert_ops.PostAlertsParams{
+		HTTPRequest: httptest.NewRequest("POST", "/api/v2/alerts", nil),
+		Alerts: open_api_models.PostableAlerts{
+			{Alert: open_api_models.Alert{Labels: open_api_models.LabelSet{"alertname": "HighLatency", "team": "db"}}},
+			{Alert: open_api_models.Alert{Labels: open_api_models.LabelSet{"alertname": "HighLatency", "team": "web"}}},
+		},
+	})
+	require.IsType(t, &alert_ops.PostAlertsBadRequest{}, res)
+	require.Contains(t, res.(*alert_ops.PostAlertsBadRequest).Payload, "HighLatency: alert matches no route")
+
+	_, err = alerts.Get(model.LabelSet{"alertname": "HighLatency", "team": "db"}.Fingerprint())
+	require.NoError(t, err)
+	_, err = alerts.Get(model.LabelSet{"alertname": "HighLatency", "team": "web"}.Fingerprint())
+	require.Error(t, err)
+}
+
+func TestPostAlertsSource(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	global := config.DefaultGlobalConfig()
+	api := API{
+		alerts:             alerts,
+		route:              dispatch.NewRoute(&config.Route{Receiver: "team"}, nil),
+		alertmanagerConfig: &config.Config{Global: &global},
+		logger:             log.NewNopLogger(),
+		m:                  metrics.NewAlerts("v2", nil),
+	}
+	lset := model.LabelSet{"alertname": "HighLatency"}
+	post := func(req *http.Request) {
+		res := api.postAlertsHandler(alert_ops.PostAlertsParams{
+			HTTPRequest: req,
+			Alerts: open_api_models.PostableAlerts{{
+				Alert:  open_api_models.Alert{Labels: open_api_models.LabelSet{"alertname": "HighLatency"}},
+				Source: "staging",
+			}},
+		})
+		require.IsType(t, &alert_ops.PostAlertsOK{}, res)
+	}
+
+	req := httptest.NewRequest("POST", "/api/v2/alerts", nil)
+	req.RemoteAddr = "10.0.0.1:4321"
+	post(req.WithContext(auth.WithIdentity(req.Context(), "prometheus")))
+	a, err := alerts.Get(lset.Fingerprint())
+	require.NoError(t, err)
+	require.Equal(t, types.Source{Name: "staging", Address: "10.0.0.1", Identity: "prometheus"}, a.Source)
+	require.Equal(t, model.LabelSet{
+		"alertname":           "HighLatency",
+		"__source__":          "staging",
+		"__source_address__":  "10.0.0.1",
+		"__source_identity__": "prometheus",
+	}, a.RoutingLabels())
+
+	// Forwarded alerts keep the address and identity of their client.
+	signer, err := shard.NewSigner(&shard.Config{Secret: "s3cr3t"})
+	require.NoError(t, err)
+	req = httptest.NewRequest("POST", "/api/v2/alerts", nil)
+	req.Header.Set(shard.Header, "true")
+	req.Header.Set(shard.SourceAddressHeader, "10.0.0.2")
+	signer.Sign(req, nil)
+	signer.Handler(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		post(req)
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	a, err = alerts.Get(lset.Fingerprint())
+	require.NoError(t, err)
+	require.Equal(t, types.Source{Name: "staging", Address: "10.0.0.2"}, a.Source)
+
+	gettable := AlertToOpenAPIAlert(a, types.AlertStatus{}, nil)
+	require.Equal(t, &open_api_models.AlertSource{Name: "staging", Address: "10.0.0.2"}, gettable.Source)
+
+	// The source headers of requests which aren't authenticated as
+	// forwarded are ignored, whether or not the signer checked them.
+	forged := func() *http.Request {
+		req := httptest.NewRequest("POST", "/api/v2/alerts", nil)
+		req.RemoteAddr = "10.0.0.3:4321"
+		req.Header.Set(shard.Header, "true")
+		req.Header.Set(shard.SourceAddressHeader, "10.0.0.2")
+		req.Header.Set(shard.SourceIdentityHeader, "admin")
+		return req.WithContext(auth.WithIdentity(req.Context(), "prometheus"))
+	}
+	for _, h := range []http.Handler{
+		http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) { post(req) }),
+		signer.Handler(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) { post(req) })),
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), forged())
+		a, err = alerts.Get(lset.Fingerprint())
+		require.NoError(t, err)
+		require.Equal(t, types.Source{Name: "staging", Address: "10.0.0.3", Identity: "prometheus"}, a.Source)
+	}
+}
+
+func TestTenancy(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	global := config.DefaultGlobalConfig()
+	api := API{
+		alerts:         alerts,
+		silences:       sils,
+		getAlertStatus: marker.Status,
+		setAlertStatus: func(model.LabelSet) {},
+		route:          dispatch.NewRoute(&config.Route{Receiver: "team"}, nil),
+		alertmanagerConfig: &config.Config{
+			Global: &global,
+			Tenancy: &config.TenancyConfig{
+				Header:  "X-Scope-OrgID",
+				Label:   "tenant",
+				Tenants: map[string]string{"prometheus-a": "a", "prometheus-b": "b"},
+			},
+			Authorization: &config.AuthorizationConfig{Roles: map[string]config.Role{"alice": config.RoleAdmin}},
+		},
+		logger: log.NewNopLogger(),
+		m:      metrics.NewAlerts("v2", nil),
+	}
+
+	request := func(method, path, identity string) *http.Request {
+		req := httptest.NewRequest(method, path, nil)
+		return req.WithContext(auth.WithIdentity(req.Context(), identity))
+	}
+
+	// Requests are rejected unless their client has a tenant or is an
+	// admin, and clients may not name another tenant.
+	tenantStatus := func(identity, tenant string) int {
+		h := api.tenantHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		req := request("GET", "/api/v2/alerts", identity)
+		if tenant != "" {
+			req.Header.Set("X-Scope-OrgID", tenant)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+	require.Equal(t, http.StatusOK, tenantStatus("prometheus-a", ""))
+	require.Equal(t, http.StatusOK, tenantStatus("prometheus-a", "a"))
+	require.Equal(t, http.StatusForbidden, tenantStatus("prometheus-a", "b"))
+	require.Equal(t, http.StatusForbidden, tenantStatus("", ""))
+	require.Equal(t, http.StatusForbidden, tenantStatus("bob", "a"))
+	require.Equal(t, http.StatusOK, tenantStatus("alice", ""))
+	require.Equal(t, http.StatusOK, tenantStatus("alice", "b"))
+
+	// Posted alerts are labeled with the tenant, overriding the label sent.
+	for _, identity := range []string{"prometheus-a", "prometheus-b"} {
+		resp := api.postAlertsHandler(alert_ops.PostAlertsParams{
+			HTTPRequest: request("POST", "/api/v2/alerts", identity),
+			Alerts: open_api_models.PostableAlerts{{
+				Alert: open_api_models.Alert{Labels: open_api_models.LabelSet{"alertname": "HighLatency", "tenant": "c"}},
+			}},
+		})
+		require.IsType(t, &alert_ops.PostAlertsOK{}, resp)
+	}
+
+	getAlerts := func(req *http.Request) []string {
+		params := alert_ops.NewGetAlertsParams()
+		params.HTTPRequest = req
+		resp, ok := api.getAlertsHandler(params).(*alert_ops.GetAlertsOK)
+		require.True(t, ok)
+		var tenants []string
+		for _, a := range resp.Payload {
+			tenants = append(tenants, a.Labels["tenant"])
+		}
+		sort.Strings(tenants)
+		return tenants
+	}
+	require.Equal(t, []string{"a"}, getAlerts(request("GET", "/api/v2/alerts", "prometheus-a")))
+	require.Equal(t, []string{"a", "b"}, getAlerts(request("GET", "/api/v2/alerts", "alice")))
+	// Admins act as the tenant named by the header.
+	req := request("GET", "/api/v2/alerts", "alice")
+	req.Header.Set("X-Scope-OrgID", "b")
+	require.Equal(t, []string{"b"}, getAlerts(req))
+
+	// Silences are restricted to the alerts of the tenant.
+	name, pattern, isRegex, isEqual := "tenant", "b", false, true
+	comment, createdBy := "maintenance", "alice"
+	now := time.Now()
+	postResp, ok := api.postSilencesHandler(silence_ops.PostSilencesParams{
+		HTTPRequest: request("POST", "/api/v2/silences", "prometheus-a"),
+		Silence: &open_api_models.PostableSilence{
+			Silence: open_api_models.Silence{
+				Matchers:  open_api_models.Matchers{{Name: &name, Value: &pattern, IsRegex: &isRegex, IsEqual: &isEqual}},
+				StartsAt:  convertDateTime(now),
+				EndsAt:    convertDateTime(now.Add(time.Hour)),
+				Comment:   &comment,
+				CreatedBy: &createdBy,
+			},
+		},
+	}).(*silence_ops.PostSilencesOK)
+	require.True(t, ok)
+	sid := postResp.Payload.SilenceID
+
+	sil, err := sils.QueryOne(silence.QIDs(sid))
+	require.NoError(t, err)
+	require.Equal(t, []*silencepb.Matcher{{Type: silencepb.Matcher_EQUAL, Name: "tenant", Pattern: "a"}}, sil.Matchers)
+
+	getSilences := func(identity string) int {
+		resp, ok := api.getSilencesHandler(silence_ops.GetSilencesParams{
+			HTTPRequest: request("GET", "/api/v2/silences", identity),
+		}).(*silence_ops.GetSilencesOK)
+		require.True(t, ok)
+		return len(resp.Payload)
+	}
+	require.Equal(t, 1, getSilences("prometheus-a"))
+	require.Equal(t, 0, getSilences("prometheus-b"))
+	require.Equal(t, 1, getSilences("alice"))
+
+	require.IsType(t, &silence_ops.GetSilenceNotFound{}, api.getSilenceHandler(silence_ops.GetSilenceParams{
+		HTTPRequest: request("GET", "/api/v2/silence/"+sid, "prometheus-b"),
+		SilenceID:   strfmt.UUID(sid),
+	}))
+	require.IsType(t, &silence_ops.DeleteSilenceNotFound{}, api.deleteSilenceHandler(silence_ops.DeleteSilenceParams{
+		HTTPRequest: request("DELETE", "/api/v2/silence/"+sid, "prometheus-b"),
+		SilenceID:   strfmt.UUID(sid),
+	}))
+	require.IsType(t, &silence_ops.DeleteSilenceOK{}, api.deleteSilenceHandler(silence_ops.DeleteSilenceParams{
+		HTTPRequest: request("DELETE", "/api/v2/silence/"+sid, "prometheus-a"),
+		SilenceID:   strfmt.UUID(sid),
+	}))
+}
+
+func TestTenantAcks(t *testing.T) {
+	acks, err := ack.New(ack.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	global := config.DefaultGlobalConfig()
+	var listed int
+	api := API{
+		acks: acks,
+		alertGroups: func(func(*dispatch.Route) bool, func(*types.Alert, time.Time) bool) (dispatch.AlertGroups, map[model.Fingerprint][]string) {
+			listed++
+			return dispatch.AlertGroups{
+				{GroupKey: "{}:{tenant=\"a\"}", Labels: model.LabelSet{"tenant": "a"}},
+				{GroupKey: "{}:{tenant=\"b\"}", Labels: model.LabelSet{"tenant": "b"}},
+			}, nil
+		},
+		alertmanagerConfig: &config.Config{
+			Global: &global,
+			Tenancy: &config.TenancyConfig{
+				Header:  "X-Scope-OrgID",
+				Label:   "tenant",
+				Tenants: map[string]string{"prometheus-a": "a", "prometheus-b": "b"},
+			},
+		},
+		logger: log.NewNopLogger(),
+	}
+	request := func(method, path, identity string) *http.Request {
+		req := httptest.NewRequest(method, path, nil)
+		return req.WithContext(auth.WithIdentity(req.Context(), identity))
+	}
+
+	var ids []string
+	for _, key := range []string{"{}:{tenant=\"a\"}", "{}:{tenant=\"b\"}", "{}:{tenant=\"c\"}"} {
+		a, err := acks.Set(&ack.Ack{GroupKey: key, AckedBy: "alice", EndsAt: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		ids = append(ids, a.ID)
+	}
+
+	// The acknowledgements are filtered against the groups listed once per
+	// request.
+	resp, ok := api.getAcksHandler(ack_ops.GetAcksParams{
+		HTTPRequest: request("GET", "/api/v2/acks", "prometheus-a"),
+	}).(*ack_ops.GetAcksOK)
+	require.True(t, ok)
+	require.Len(t, resp.Payload, 1)
+	require.Equal(t, "{}:{tenant=\"a\"}", *resp.Payload[0].GroupKey)
+	require.Equal(t, 1, listed)
+
+	require.IsType(t, &ack_ops.DeleteAckNotFound{}, api.deleteAckHandler(ack_ops.DeleteAckParams{
+		HTTPRequest: request("DELETE", "/api/v2/ack/"+ids[0], "prometheus-b"),
+		AckID:       strfmt.UUID(ids[0]),
+	}))
+	require.IsType(t, &ack_ops.DeleteAckOK{}, api.deleteAckHandler(ack_ops.DeleteAckParams{
+		HTTPRequest: request("DELETE", "/api/v2/ack/"+ids[0], "prometheus-a"),
+		AckID:       strfmt.UUID(ids[0]),
+	}))
+}
+
+func TestCORSHandler(t *testing.T) {
+	api := API{cors: newCORS(nil)}
+	h := api.corsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	preflight := func(origin, method string) http.Header {
+		req := httptest.NewRequest("OPTIONS", "/silence/foo", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Header()
+	}
+
+	// Without configuration every origin may make simple requests only.
+	require.Equal(t, "*", preflight("https://dashboard.example.com", "GET").Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "", preflight("https://dashboard.example.com", "DELETE").Get("Access-Control-Allow-Origin"))
+
+	api.cors = newCORS(&config.CORSConfig{
+		AllowedOrigins:   []string{"https://*.example.com"},
+		AllowedMethods:   []string{"GET", "DELETE"},
+		AllowCredentials: true,
+		MaxAge:           model.Duration(10 * time.Minute),
+	})
+	header := preflight("https://dashboard.example.com", "DELETE")
+	require.Equal(t, "https://dashboard.example.com", header.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "true", header.Get("Access-Control-Allow-Credentials"))
+	require.Equal(t, "600", header.Get("Access-Control-Max-Age"))
+	require.Equal(t, "", preflight("https://example.org", "GET").Get("Access-Control-Allow-Origin"))
+}
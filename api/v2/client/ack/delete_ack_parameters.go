@@ -0,0 +1,149 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewDeleteAckParams creates a new DeleteAckParams object
+// with the default values initialized.
+func NewDeleteAckParams() *DeleteAckParams {
+	var ()
+	return &DeleteAckParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewDeleteAckParamsWithTimeout creates a new DeleteAckParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewDeleteAckParamsWithTimeout(timeout time.Duration) *DeleteAckParams {
+	var ()
+	return &DeleteAckParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewDeleteAckParamsWithContext creates a new DeleteAckParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewDeleteAckParamsWithContext(ctx context.Context) *DeleteAckParams {
+	var ()
+	return &DeleteAckParams{
+
+		Context: ctx,
+	}
+}
+
+// NewDeleteAckParamsWithHTTPClient creates a new DeleteAckParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewDeleteAckParamsWithHTTPClient(client *http.Client) *DeleteAckParams {
+	var ()
+	return &DeleteAckParams{
+		HTTPClient: client,
+	}
+}
+
+/*DeleteAckParams contains all the parameters to send to the API endpoint
+for the delete ack operation typically these are written to a http.Request
+*/
+type DeleteAckParams struct {
+
+	/*AckID
+	  ID of the acknowledgement
+
+	*/
+	AckID strfmt.UUID
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the delete ack params
+func (o *DeleteAckParams) WithTimeout(timeout time.Duration) *DeleteAckParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the delete ack params
+func (o *DeleteAckParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the delete ack params
+func (o *DeleteAckParams) WithContext(ctx context.Context) *DeleteAckParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the delete ack params
+func (o *DeleteAckParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the delete ack params
+func (o *DeleteAckParams) WithHTTPClient(client *http.Client) *DeleteAckParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the delete ack params
+func (o *DeleteAckParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithAckID adds the ackID to the delete ack params
+func (o *DeleteAckParams) WithAckID(ackID strfmt.UUID) *DeleteAckParams {
+	o.SetAckID(ackID)
+	return o
+}
+
+// SetAckID adds the ackId to the delete ack params
+func (o *DeleteAckParams) SetAckID(ackID strfmt.UUID) {
+	o.AckID = ackID
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *DeleteAckParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param ackID
+	if err := r.SetPathParam("ackID", o.AckID.String()); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+// DeleteAckReader is a Reader for the DeleteAck structure.
+type DeleteAckReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *DeleteAckReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewDeleteAckOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewDeleteAckNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewDeleteAckOK creates a DeleteAckOK with default headers values
+func NewDeleteAckOK() *DeleteAckOK {
+	return &DeleteAckOK{}
+}
+
+/*DeleteAckOK handles this case with default header values.
+
+Delete acknowledgement response
+*/
+type DeleteAckOK struct {
+}
+
+func (o *DeleteAckOK) Error() string {
+	return fmt.Sprintf("[DELETE /ack/{ackID}][%d] deleteAckOK ", 200)
+}
+
+func (o *DeleteAckOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewDeleteAckNotFound creates a DeleteAckNotFound with default headers values
+func NewDeleteAckNotFound() *DeleteAckNotFound {
+	return &DeleteAckNotFound{}
+}
+
+/*DeleteAckNotFound handles this case with default header values.
+
+An active acknowledgement with the specified ID was not found
+*/
+type DeleteAckNotFound struct {
+}
+
+func (o *DeleteAckNotFound) Error() string {
+	return fmt.Sprintf("[DELETE /ack/{ackID}][%d] deleteAckNotFound ", 404)
+}
+
+func (o *DeleteAckNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
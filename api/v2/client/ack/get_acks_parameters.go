@@ -0,0 +1,126 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewGetAcksParams creates a new GetAcksParams object
+// with the default values initialized.
+func NewGetAcksParams() *GetAcksParams {
+
+	return &GetAcksParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetAcksParamsWithTimeout creates a new GetAcksParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewGetAcksParamsWithTimeout(timeout time.Duration) *GetAcksParams {
+
+	return &GetAcksParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewGetAcksParamsWithContext creates a new GetAcksParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewGetAcksParamsWithContext(ctx context.Context) *GetAcksParams {
+
+	return &GetAcksParams{
+
+		Context: ctx,
+	}
+}
+
+// NewGetAcksParamsWithHTTPClient creates a new GetAcksParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewGetAcksParamsWithHTTPClient(client *http.Client) *GetAcksParams {
+
+	return &GetAcksParams{
+		HTTPClient: client,
+	}
+}
+
+/*GetAcksParams contains all the parameters to send to the API endpoint
+for the get acks operation typically these are written to a http.Request
+*/
+type GetAcksParams struct {
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the get acks params
+func (o *GetAcksParams) WithTimeout(timeout time.Duration) *GetAcksParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get acks params
+func (o *GetAcksParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get acks params
+func (o *GetAcksParams) WithContext(ctx context.Context) *GetAcksParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get acks params
+func (o *GetAcksParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get acks params
+func (o *GetAcksParams) WithHTTPClient(client *http.Client) *GetAcksParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get acks params
+func (o *GetAcksParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetAcksParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// NewPostAcksParams creates a new PostAcksParams object
+// with the default values initialized.
+func NewPostAcksParams() *PostAcksParams {
+	var ()
+	return &PostAcksParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewPostAcksParamsWithTimeout creates a new PostAcksParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewPostAcksParamsWithTimeout(timeout time.Duration) *PostAcksParams {
+	var ()
+	return &PostAcksParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewPostAcksParamsWithContext creates a new PostAcksParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewPostAcksParamsWithContext(ctx context.Context) *PostAcksParams {
+	var ()
+	return &PostAcksParams{
+
+		Context: ctx,
+	}
+}
+
+// NewPostAcksParamsWithHTTPClient creates a new PostAcksParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewPostAcksParamsWithHTTPClient(client *http.Client) *PostAcksParams {
+	var ()
+	return &PostAcksParams{
+		HTTPClient: client,
+	}
+}
+
+/*PostAcksParams contains all the parameters to send to the API endpoint
+for the post acks operation typically these are written to a http.Request
+*/
+type PostAcksParams struct {
+
+	/*Ack
+	  The acknowledgement to create
+
+	*/
+	Ack *models.PostableAck
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the post acks params
+func (o *PostAcksParams) WithTimeout(timeout time.Duration) *PostAcksParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the post acks params
+func (o *PostAcksParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the post acks params
+func (o *PostAcksParams) WithContext(ctx context.Context) *PostAcksParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the post acks params
+func (o *PostAcksParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the post acks params
+func (o *PostAcksParams) WithHTTPClient(client *http.Client) *PostAcksParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the post acks params
+func (o *PostAcksParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithAck adds the ack to the post acks params
+func (o *PostAcksParams) WithAck(ack *models.PostableAck) *PostAcksParams {
+	o.SetAck(ack)
+	return o
+}
+
+// SetAck adds the ack to the post acks params
+func (o *PostAcksParams) SetAck(ack *models.PostableAck) {
+	o.Ack = ack
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *PostAcksParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if o.Ack != nil {
+		if err := r.SetBodyParam(o.Ack); err != nil {
+			return err
+		}
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
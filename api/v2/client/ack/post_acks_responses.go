@@ -0,0 +1,120 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// PostAcksReader is a Reader for the PostAcks structure.
+type PostAcksReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *PostAcksReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewPostAcksOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 400:
+		result := NewPostAcksBadRequest()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewPostAcksOK creates a PostAcksOK with default headers values
+func NewPostAcksOK() *PostAcksOK {
+	return &PostAcksOK{}
+}
+
+/*PostAcksOK handles this case with default header values.
+
+Create acknowledgement response
+*/
+type PostAcksOK struct {
+	Payload *models.Ack
+}
+
+func (o *PostAcksOK) Error() string {
+	return fmt.Sprintf("[POST /acks][%d] postAcksOK  %+v", 200, o.Payload)
+}
+
+func (o *PostAcksOK) GetPayload() *models.Ack {
+	return o.Payload
+}
+
+func (o *PostAcksOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.Ack)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewPostAcksBadRequest creates a PostAcksBadRequest with default headers values
+func NewPostAcksBadRequest() *PostAcksBadRequest {
+	return &PostAcksBadRequest{}
+}
+
+/*PostAcksBadRequest handles this case with default header values.
+
+Bad request
+*/
+type PostAcksBadRequest struct {
+	Payload string
+}
+
+func (o *PostAcksBadRequest) Error() string {
+	return fmt.Sprintf("[POST /acks][%d] postAcksBadRequest  %+v", 400, o.Payload)
+}
+
+func (o *PostAcksBadRequest) GetPayload() string {
+	return o.Payload
+}
+
+func (o *PostAcksBadRequest) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
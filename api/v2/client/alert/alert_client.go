@@ -41,13 +41,51 @@ type Client struct {
 
 // ClientService is the interface for Client methods
 type ClientService interface {
+	GetAlertStats(params *GetAlertStatsParams) (*GetAlertStatsOK, error)
+
 	GetAlerts(params *GetAlertsParams) (*GetAlertsOK, error)
 
 	PostAlerts(params *PostAlertsParams) (*PostAlertsOK, error)
 
+	StreamAlerts(params *StreamAlertsParams) (*StreamAlertsOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
+/*
+  GetAlertStats Get the number of alerts by alertname, severity and receiver over recent windows
+*/
+func (a *Client) GetAlertStats(params *GetAlertStatsParams) (*GetAlertStatsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetAlertStatsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getAlertStats",
+		Method:             "GET",
+		PathPattern:        "/stats",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetAlertStatsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetAlertStatsOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for getAlertStats: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 /*
   GetAlerts Get a list of alerts
 */
@@ -116,6 +154,40 @@ func (a *Client) PostAlerts(params *PostAlertsParams) (*PostAlertsOK, error) {
 	panic(msg)
 }
 
+/*
+  StreamAlerts Stream the changes of alerts as server-sent events. Each event carries an alertEvent.
+*/
+func (a *Client) StreamAlerts(params *StreamAlertsParams) (*StreamAlertsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewStreamAlertsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "streamAlerts",
+		Method:             "GET",
+		PathPattern:        "/alerts/stream",
+		ProducesMediaTypes: []string{"text/event-stream"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &StreamAlertsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*StreamAlertsOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for streamAlerts: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -37,13 +37,19 @@ func NewGetAlertsParams() *GetAlertsParams {
 	var (
 		activeDefault      = bool(true)
 		inhibitedDefault   = bool(true)
+		limitDefault       = int64(0)
+		offsetDefault      = int64(0)
 		silencedDefault    = bool(true)
+		sortDefault        = string("fingerprint")
 		unprocessedDefault = bool(true)
 	)
 	return &GetAlertsParams{
 		Active:      &activeDefault,
 		Inhibited:   &inhibitedDefault,
+		Limit:       &limitDefault,
+		Offset:      &offsetDefault,
 		Silenced:    &silencedDefault,
+		Sort:        &sortDefault,
 		Unprocessed: &unprocessedDefault,
 
 		timeout: cr.DefaultTimeout,
@@ -56,13 +62,19 @@ func NewGetAlertsParamsWithTimeout(timeout time.Duration) *GetAlertsParams {
 	var (
 		activeDefault      = bool(true)
 		inhibitedDefault   = bool(true)
+		limitDefault       = int64(0)
+		offsetDefault      = int64(0)
 		silencedDefault    = bool(true)
+		sortDefault        = string("fingerprint")
 		unprocessedDefault = bool(true)
 	)
 	return &GetAlertsParams{
 		Active:      &activeDefault,
 		Inhibited:   &inhibitedDefault,
+		Limit:       &limitDefault,
+		Offset:      &offsetDefault,
 		Silenced:    &silencedDefault,
+		Sort:        &sortDefault,
 		Unprocessed: &unprocessedDefault,
 
 		timeout: timeout,
@@ -75,13 +87,19 @@ func NewGetAlertsParamsWithContext(ctx context.Context) *GetAlertsParams {
 	var (
 		activeDefault      = bool(true)
 		inhibitedDefault   = bool(true)
+		limitDefault       = int64(0)
+		offsetDefault      = int64(0)
 		silencedDefault    = bool(true)
+		sortDefault        = string("fingerprint")
 		unprocessedDefault = bool(true)
 	)
 	return &GetAlertsParams{
 		Active:      &activeDefault,
 		Inhibited:   &inhibitedDefault,
+		Limit:       &limitDefault,
+		Offset:      &offsetDefault,
 		Silenced:    &silencedDefault,
+		Sort:        &sortDefault,
 		Unprocessed: &unprocessedDefault,
 
 		Context: ctx,
@@ -94,13 +112,19 @@ func NewGetAlertsParamsWithHTTPClient(client *http.Client) *GetAlertsParams {
 	var (
 		activeDefault      = bool(true)
 		inhibitedDefault   = bool(true)
+		limitDefault       = int64(0)
+		offsetDefault      = int64(0)
 		silencedDefault    = bool(true)
+		sortDefault        = string("fingerprint")
 		unprocessedDefault = bool(true)
 	)
 	return &GetAlertsParams{
 		Active:      &activeDefault,
 		Inhibited:   &inhibitedDefault,
+		Limit:       &limitDefault,
+		Offset:      &offsetDefault,
 		Silenced:    &silencedDefault,
+		Sort:        &sortDefault,
 		Unprocessed: &unprocessedDefault,
 		HTTPClient:  client,
 	}
@@ -126,6 +150,16 @@ type GetAlertsParams struct {
 
 	*/
 	Inhibited *bool
+	/*Limit
+	  Maximum number of alerts to return. 0 returns all of them.
+
+	*/
+	Limit *int64
+	/*Offset
+	  Number of alerts to skip
+
+	*/
+	Offset *int64
 	/*Receiver
 	  A regex matching receivers to filter alerts by
 
@@ -136,6 +170,11 @@ type GetAlertsParams struct {
 
 	*/
 	Silenced *bool
+	/*Sort
+	  The order of the returned alerts. Severities are ranked by the order of the global severity mapping.
+
+	*/
+	Sort *string
 	/*Unprocessed
 	  Show unprocessed alerts
 
@@ -213,6 +252,28 @@ func (o *GetAlertsParams) SetInhibited(inhibited *bool) {
 	o.Inhibited = inhibited
 }
 
+// WithLimit adds the limit to the get alerts params
+func (o *GetAlertsParams) WithLimit(limit *int64) *GetAlertsParams {
+	o.SetLimit(limit)
+	return o
+}
+
+// SetLimit adds the limit to the get alerts params
+func (o *GetAlertsParams) SetLimit(limit *int64) {
+	o.Limit = limit
+}
+
+// WithOffset adds the offset to the get alerts params
+func (o *GetAlertsParams) WithOffset(offset *int64) *GetAlertsParams {
+	o.SetOffset(offset)
+	return o
+}
+
+// SetOffset adds the offset to the get alerts params
+func (o *GetAlertsParams) SetOffset(offset *int64) {
+	o.Offset = offset
+}
+
 // WithReceiver adds the receiver to the get alerts params
 func (o *GetAlertsParams) WithReceiver(receiver *string) *GetAlertsParams {
 	o.SetReceiver(receiver)
@@ -235,6 +296,17 @@ func (o *GetAlertsParams) SetSilenced(silenced *bool) {
 	o.Silenced = silenced
 }
 
+// WithSort adds the sort to the get alerts params
+func (o *GetAlertsParams) WithSort(sort *string) *GetAlertsParams {
+	o.SetSort(sort)
+	return o
+}
+
+// SetSort adds the sort to the get alerts params
+func (o *GetAlertsParams) SetSort(sort *string) {
+	o.Sort = sort
+}
+
 // WithUnprocessed adds the unprocessed to the get alerts params
 func (o *GetAlertsParams) WithUnprocessed(unprocessed *bool) *GetAlertsParams {
 	o.SetUnprocessed(unprocessed)
@@ -294,6 +366,38 @@ func (o *GetAlertsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Reg
 
 	}
 
+	if o.Limit != nil {
+
+		// query param limit
+		var qrLimit int64
+		if o.Limit != nil {
+			qrLimit = *o.Limit
+		}
+		qLimit := swag.FormatInt64(qrLimit)
+		if qLimit != "" {
+			if err := r.SetQueryParam("limit", qLimit); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Offset != nil {
+
+		// query param offset
+		var qrOffset int64
+		if o.Offset != nil {
+			qrOffset = *o.Offset
+		}
+		qOffset := swag.FormatInt64(qrOffset)
+		if qOffset != "" {
+			if err := r.SetQueryParam("offset", qOffset); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	if o.Receiver != nil {
 
 		// query param receiver
@@ -326,6 +430,22 @@ func (o *GetAlertsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Reg
 
 	}
 
+	if o.Sort != nil {
+
+		// query param sort
+		var qrSort string
+		if o.Sort != nil {
+			qrSort = *o.Sort
+		}
+		qSort := qrSort
+		if qSort != "" {
+			if err := r.SetQueryParam("sort", qSort); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	if o.Unprocessed != nil {
 
 		// query param unprocessed
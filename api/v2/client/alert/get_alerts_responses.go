@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 
 	"github.com/prometheus/alertmanager/api/v2/models"
 )
@@ -71,6 +73,10 @@ func NewGetAlertsOK() *GetAlertsOK {
 Get alerts response
 */
 type GetAlertsOK struct {
+	/*The number of alerts matching the filters, regardless of limit and offset
+	 */
+	XTotalCount int64
+
 	Payload models.GettableAlerts
 }
 
@@ -84,6 +90,13 @@ func (o *GetAlertsOK) GetPayload() models.GettableAlerts {
 
 func (o *GetAlertsOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
 
+	// response header X-Total-Count
+	xTotalCount, err := swag.ConvertInt64(response.GetHeader("X-Total-Count"))
+	if err != nil {
+		return errors.InvalidType("X-Total-Count", "header", "int64", response.GetHeader("X-Total-Count"))
+	}
+	o.XTotalCount = xTotalCount
+
 	// response payload
 	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
 		return err
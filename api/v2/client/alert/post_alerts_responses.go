@@ -47,6 +47,12 @@ func (o *PostAlertsReader) ReadResponse(response runtime.ClientResponse, consume
 			return nil, err
 		}
 		return nil, result
+	case 429:
+		result := NewPostAlertsTooManyRequests()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
 	case 500:
 		result := NewPostAlertsInternalServerError()
 		if err := result.readResponse(response, consumer, o.formats); err != nil {
@@ -111,6 +117,37 @@ func (o *PostAlertsBadRequest) readResponse(response runtime.ClientResponse, con
 	return nil
 }
 
+// NewPostAlertsTooManyRequests creates a PostAlertsTooManyRequests with default headers values
+func NewPostAlertsTooManyRequests() *PostAlertsTooManyRequests {
+	return &PostAlertsTooManyRequests{}
+}
+
+/*PostAlertsTooManyRequests handles this case with default header values.
+
+Too many requests
+*/
+type PostAlertsTooManyRequests struct {
+	Payload string
+}
+
+func (o *PostAlertsTooManyRequests) Error() string {
+	return fmt.Sprintf("[POST /alerts][%d] postAlertsTooManyRequests  %+v", 429, o.Payload)
+}
+
+func (o *PostAlertsTooManyRequests) GetPayload() string {
+	return o.Payload
+}
+
+func (o *PostAlertsTooManyRequests) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
 // NewPostAlertsInternalServerError creates a PostAlertsInternalServerError with default headers values
 func NewPostAlertsInternalServerError() *PostAlertsInternalServerError {
 	return &PostAlertsInternalServerError{}
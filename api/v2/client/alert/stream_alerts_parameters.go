@@ -0,0 +1,153 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package alert
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// NewStreamAlertsParams creates a new StreamAlertsParams object
+// with the default values initialized.
+func NewStreamAlertsParams() *StreamAlertsParams {
+	var ()
+	return &StreamAlertsParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewStreamAlertsParamsWithTimeout creates a new StreamAlertsParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewStreamAlertsParamsWithTimeout(timeout time.Duration) *StreamAlertsParams {
+	var ()
+	return &StreamAlertsParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewStreamAlertsParamsWithContext creates a new StreamAlertsParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewStreamAlertsParamsWithContext(ctx context.Context) *StreamAlertsParams {
+	var ()
+	return &StreamAlertsParams{
+
+		Context: ctx,
+	}
+}
+
+// NewStreamAlertsParamsWithHTTPClient creates a new StreamAlertsParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewStreamAlertsParamsWithHTTPClient(client *http.Client) *StreamAlertsParams {
+	var ()
+	return &StreamAlertsParams{
+		HTTPClient: client,
+	}
+}
+
+/*StreamAlertsParams contains all the parameters to send to the API endpoint
+for the stream alerts operation typically these are written to a http.Request
+*/
+type StreamAlertsParams struct {
+
+	/*Filter
+	  A list of matchers to filter alerts by
+
+	*/
+	Filter []string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the stream alerts params
+func (o *StreamAlertsParams) WithTimeout(timeout time.Duration) *StreamAlertsParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the stream alerts params
+func (o *StreamAlertsParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the stream alerts params
+func (o *StreamAlertsParams) WithContext(ctx context.Context) *StreamAlertsParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the stream alerts params
+func (o *StreamAlertsParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the stream alerts params
+func (o *StreamAlertsParams) WithHTTPClient(client *http.Client) *StreamAlertsParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the stream alerts params
+func (o *StreamAlertsParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithFilter adds the filter to the stream alerts params
+func (o *StreamAlertsParams) WithFilter(filter []string) *StreamAlertsParams {
+	o.SetFilter(filter)
+	return o
+}
+
+// SetFilter adds the filter to the stream alerts params
+func (o *StreamAlertsParams) SetFilter(filter []string) {
+	o.Filter = filter
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *StreamAlertsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	valuesFilter := o.Filter
+
+	joinedFilter := swag.JoinByFormat(valuesFilter, "multi")
+	// query array param filter
+	if err := r.SetQueryParam("filter", joinedFilter...); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
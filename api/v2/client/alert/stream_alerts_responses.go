@@ -0,0 +1,120 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package alert
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// StreamAlertsReader is a Reader for the StreamAlerts structure.
+type StreamAlertsReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *StreamAlertsReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewStreamAlertsOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 400:
+		result := NewStreamAlertsBadRequest()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewStreamAlertsOK creates a StreamAlertsOK with default headers values
+func NewStreamAlertsOK() *StreamAlertsOK {
+	return &StreamAlertsOK{}
+}
+
+/*StreamAlertsOK handles this case with default header values.
+
+Stream of alert events
+*/
+type StreamAlertsOK struct {
+	Payload *models.AlertEvent
+}
+
+func (o *StreamAlertsOK) Error() string {
+	return fmt.Sprintf("[GET /alerts/stream][%d] streamAlertsOK  %+v", 200, o.Payload)
+}
+
+func (o *StreamAlertsOK) GetPayload() *models.AlertEvent {
+	return o.Payload
+}
+
+func (o *StreamAlertsOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.AlertEvent)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewStreamAlertsBadRequest creates a StreamAlertsBadRequest with default headers values
+func NewStreamAlertsBadRequest() *StreamAlertsBadRequest {
+	return &StreamAlertsBadRequest{}
+}
+
+/*StreamAlertsBadRequest handles this case with default header values.
+
+Bad request
+*/
+type StreamAlertsBadRequest struct {
+	Payload string
+}
+
+func (o *StreamAlertsBadRequest) Error() string {
+	return fmt.Sprintf("[GET /alerts/stream][%d] streamAlertsBadRequest  %+v", 400, o.Payload)
+}
+
+func (o *StreamAlertsBadRequest) GetPayload() string {
+	return o.Payload
+}
+
+func (o *StreamAlertsBadRequest) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
@@ -43,6 +43,8 @@ type Client struct {
 type ClientService interface {
 	GetAlertGroups(params *GetAlertGroupsParams) (*GetAlertGroupsOK, error)
 
+	ResendAlertGroup(params *ResendAlertGroupParams) (*ResendAlertGroupOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
@@ -80,6 +82,40 @@ func (a *Client) GetAlertGroups(params *GetAlertGroupsParams) (*GetAlertGroupsOK
 	panic(msg)
 }
 
+/*
+  ResendAlertGroup Notify a receiver of an alert group again right away about its firing alerts, regardless of the repeat interval and of any acknowledgement of the group
+*/
+func (a *Client) ResendAlertGroup(params *ResendAlertGroupParams) (*ResendAlertGroupOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewResendAlertGroupParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "resendAlertGroup",
+		Method:             "POST",
+		PathPattern:        "/alerts/groups/resend",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &ResendAlertGroupReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*ResendAlertGroupOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for resendAlertGroup: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -35,14 +35,16 @@ import (
 // with the default values initialized.
 func NewGetAlertGroupsParams() *GetAlertGroupsParams {
 	var (
-		activeDefault    = bool(true)
-		inhibitedDefault = bool(true)
-		silencedDefault  = bool(true)
+		activeDefault      = bool(true)
+		inhibitedDefault   = bool(true)
+		silencedDefault    = bool(true)
+		unprocessedDefault = bool(true)
 	)
 	return &GetAlertGroupsParams{
-		Active:    &activeDefault,
-		Inhibited: &inhibitedDefault,
-		Silenced:  &silencedDefault,
+		Active:      &activeDefault,
+		Inhibited:   &inhibitedDefault,
+		Silenced:    &silencedDefault,
+		Unprocessed: &unprocessedDefault,
 
 		timeout: cr.DefaultTimeout,
 	}
@@ -52,14 +54,16 @@ func NewGetAlertGroupsParams() *GetAlertGroupsParams {
 // with the default values initialized, and the ability to set a timeout on a request
 func NewGetAlertGroupsParamsWithTimeout(timeout time.Duration) *GetAlertGroupsParams {
 	var (
-		activeDefault    = bool(true)
-		inhibitedDefault = bool(true)
-		silencedDefault  = bool(true)
+		activeDefault      = bool(true)
+		inhibitedDefault   = bool(true)
+		silencedDefault    = bool(true)
+		unprocessedDefault = bool(true)
 	)
 	return &GetAlertGroupsParams{
-		Active:    &activeDefault,
-		Inhibited: &inhibitedDefault,
-		Silenced:  &silencedDefault,
+		Active:      &activeDefault,
+		Inhibited:   &inhibitedDefault,
+		Silenced:    &silencedDefault,
+		Unprocessed: &unprocessedDefault,
 
 		timeout: timeout,
 	}
@@ -69,14 +73,16 @@ func NewGetAlertGroupsParamsWithTimeout(timeout time.Duration) *GetAlertGroupsPa
 // with the default values initialized, and the ability to set a context for a request
 func NewGetAlertGroupsParamsWithContext(ctx context.Context) *GetAlertGroupsParams {
 	var (
-		activeDefault    = bool(true)
-		inhibitedDefault = bool(true)
-		silencedDefault  = bool(true)
+		activeDefault      = bool(true)
+		inhibitedDefault   = bool(true)
+		silencedDefault    = bool(true)
+		unprocessedDefault = bool(true)
 	)
 	return &GetAlertGroupsParams{
-		Active:    &activeDefault,
-		Inhibited: &inhibitedDefault,
-		Silenced:  &silencedDefault,
+		Active:      &activeDefault,
+		Inhibited:   &inhibitedDefault,
+		Silenced:    &silencedDefault,
+		Unprocessed: &unprocessedDefault,
 
 		Context: ctx,
 	}
@@ -86,15 +92,17 @@ func NewGetAlertGroupsParamsWithContext(ctx context.Context) *GetAlertGroupsPara
 // with the default values initialized, and the ability to set a custom HTTPClient for a request
 func NewGetAlertGroupsParamsWithHTTPClient(client *http.Client) *GetAlertGroupsParams {
 	var (
-		activeDefault    = bool(true)
-		inhibitedDefault = bool(true)
-		silencedDefault  = bool(true)
+		activeDefault      = bool(true)
+		inhibitedDefault   = bool(true)
+		silencedDefault    = bool(true)
+		unprocessedDefault = bool(true)
 	)
 	return &GetAlertGroupsParams{
-		Active:     &activeDefault,
-		Inhibited:  &inhibitedDefault,
-		Silenced:   &silencedDefault,
-		HTTPClient: client,
+		Active:      &activeDefault,
+		Inhibited:   &inhibitedDefault,
+		Silenced:    &silencedDefault,
+		Unprocessed: &unprocessedDefault,
+		HTTPClient:  client,
 	}
 }
 
@@ -128,6 +136,11 @@ type GetAlertGroupsParams struct {
 
 	*/
 	Silenced *bool
+	/*Unprocessed
+	  Show unprocessed alerts
+
+	*/
+	Unprocessed *bool
 
 	timeout    time.Duration
 	Context    context.Context
@@ -222,6 +235,17 @@ func (o *GetAlertGroupsParams) SetSilenced(silenced *bool) {
 	o.Silenced = silenced
 }
 
+// WithUnprocessed adds the unprocessed to the get alert groups params
+func (o *GetAlertGroupsParams) WithUnprocessed(unprocessed *bool) *GetAlertGroupsParams {
+	o.SetUnprocessed(unprocessed)
+	return o
+}
+
+// SetUnprocessed adds the unprocessed to the get alert groups params
+func (o *GetAlertGroupsParams) SetUnprocessed(unprocessed *bool) {
+	o.Unprocessed = unprocessed
+}
+
 // WriteToRequest writes these params to a swagger request
 func (o *GetAlertGroupsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
 
@@ -302,6 +326,22 @@ func (o *GetAlertGroupsParams) WriteToRequest(r runtime.ClientRequest, reg strfm
 
 	}
 
+	if o.Unprocessed != nil {
+
+		// query param unprocessed
+		var qrUnprocessed bool
+		if o.Unprocessed != nil {
+			qrUnprocessed = *o.Unprocessed
+		}
+		qUnprocessed := swag.FormatBool(qrUnprocessed)
+		if qUnprocessed != "" {
+			if err := r.SetQueryParam("unprocessed", qUnprocessed); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
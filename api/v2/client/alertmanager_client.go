@@ -24,8 +24,10 @@ import (
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 
+	"github.com/prometheus/alertmanager/api/v2/client/ack"
 	"github.com/prometheus/alertmanager/api/v2/client/alert"
 	"github.com/prometheus/alertmanager/api/v2/client/alertgroup"
+	"github.com/prometheus/alertmanager/api/v2/client/deadletter"
 	"github.com/prometheus/alertmanager/api/v2/client/general"
 	"github.com/prometheus/alertmanager/api/v2/client/receiver"
 	"github.com/prometheus/alertmanager/api/v2/client/silence"
@@ -73,8 +75,10 @@ func New(transport runtime.ClientTransport, formats strfmt.Registry) *Alertmanag
 
 	cli := new(Alertmanager)
 	cli.Transport = transport
+	cli.Ack = ack.New(transport, formats)
 	cli.Alert = alert.New(transport, formats)
 	cli.Alertgroup = alertgroup.New(transport, formats)
+	cli.Deadletter = deadletter.New(transport, formats)
 	cli.General = general.New(transport, formats)
 	cli.Receiver = receiver.New(transport, formats)
 	cli.Silence = silence.New(transport, formats)
@@ -122,10 +126,14 @@ func (cfg *TransportConfig) WithSchemes(schemes []string) *TransportConfig {
 
 // Alertmanager is a client for alertmanager
 type Alertmanager struct {
+	Ack ack.ClientService
+
 	Alert alert.ClientService
 
 	Alertgroup alertgroup.ClientService
 
+	Deadletter deadletter.ClientService
+
 	General general.ClientService
 
 	Receiver receiver.ClientService
@@ -138,8 +146,10 @@ type Alertmanager struct {
 // SetTransport changes the transport on the client and all its subresources
 func (c *Alertmanager) SetTransport(transport runtime.ClientTransport) {
 	c.Transport = transport
+	c.Ack.SetTransport(transport)
 	c.Alert.SetTransport(transport)
 	c.Alertgroup.SetTransport(transport)
+	c.Deadletter.SetTransport(transport)
 	c.General.SetTransport(transport)
 	c.Receiver.SetTransport(transport)
 	c.Silence.SetTransport(transport)
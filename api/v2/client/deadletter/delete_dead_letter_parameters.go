@@ -0,0 +1,149 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewDeleteDeadLetterParams creates a new DeleteDeadLetterParams object
+// with the default values initialized.
+func NewDeleteDeadLetterParams() *DeleteDeadLetterParams {
+	var ()
+	return &DeleteDeadLetterParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewDeleteDeadLetterParamsWithTimeout creates a new DeleteDeadLetterParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewDeleteDeadLetterParamsWithTimeout(timeout time.Duration) *DeleteDeadLetterParams {
+	var ()
+	return &DeleteDeadLetterParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewDeleteDeadLetterParamsWithContext creates a new DeleteDeadLetterParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewDeleteDeadLetterParamsWithContext(ctx context.Context) *DeleteDeadLetterParams {
+	var ()
+	return &DeleteDeadLetterParams{
+
+		Context: ctx,
+	}
+}
+
+// NewDeleteDeadLetterParamsWithHTTPClient creates a new DeleteDeadLetterParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewDeleteDeadLetterParamsWithHTTPClient(client *http.Client) *DeleteDeadLetterParams {
+	var ()
+	return &DeleteDeadLetterParams{
+		HTTPClient: client,
+	}
+}
+
+/*DeleteDeadLetterParams contains all the parameters to send to the API endpoint
+for the delete dead letter operation typically these are written to a http.Request
+*/
+type DeleteDeadLetterParams struct {
+
+	/*DeadLetterID
+	  ID of the dead letter
+
+	*/
+	DeadLetterID strfmt.UUID
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the delete dead letter params
+func (o *DeleteDeadLetterParams) WithTimeout(timeout time.Duration) *DeleteDeadLetterParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the delete dead letter params
+func (o *DeleteDeadLetterParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the delete dead letter params
+func (o *DeleteDeadLetterParams) WithContext(ctx context.Context) *DeleteDeadLetterParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the delete dead letter params
+func (o *DeleteDeadLetterParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the delete dead letter params
+func (o *DeleteDeadLetterParams) WithHTTPClient(client *http.Client) *DeleteDeadLetterParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the delete dead letter params
+func (o *DeleteDeadLetterParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithDeadLetterID adds the deadLetterID to the delete dead letter params
+func (o *DeleteDeadLetterParams) WithDeadLetterID(deadLetterID strfmt.UUID) *DeleteDeadLetterParams {
+	o.SetDeadLetterID(deadLetterID)
+	return o
+}
+
+// SetDeadLetterID adds the deadLetterId to the delete dead letter params
+func (o *DeleteDeadLetterParams) SetDeadLetterID(deadLetterID strfmt.UUID) {
+	o.DeadLetterID = deadLetterID
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *DeleteDeadLetterParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param deadLetterID
+	if err := r.SetPathParam("deadLetterID", o.DeadLetterID.String()); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
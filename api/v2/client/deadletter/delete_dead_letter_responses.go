@@ -0,0 +1,95 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+// DeleteDeadLetterReader is a Reader for the DeleteDeadLetter structure.
+type DeleteDeadLetterReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *DeleteDeadLetterReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewDeleteDeadLetterOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewDeleteDeadLetterNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewDeleteDeadLetterOK creates a DeleteDeadLetterOK with default headers values
+func NewDeleteDeadLetterOK() *DeleteDeadLetterOK {
+	return &DeleteDeadLetterOK{}
+}
+
+/*DeleteDeadLetterOK handles this case with default header values.
+
+Delete dead letter response
+*/
+type DeleteDeadLetterOK struct {
+}
+
+func (o *DeleteDeadLetterOK) Error() string {
+	return fmt.Sprintf("[DELETE /deadletter/{deadLetterID}][%d] deleteDeadLetterOK ", 200)
+}
+
+func (o *DeleteDeadLetterOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewDeleteDeadLetterNotFound creates a DeleteDeadLetterNotFound with default headers values
+func NewDeleteDeadLetterNotFound() *DeleteDeadLetterNotFound {
+	return &DeleteDeadLetterNotFound{}
+}
+
+/*DeleteDeadLetterNotFound handles this case with default header values.
+
+A dead letter with the specified ID was not found
+*/
+type DeleteDeadLetterNotFound struct {
+}
+
+func (o *DeleteDeadLetterNotFound) Error() string {
+	return fmt.Sprintf("[DELETE /deadletter/{deadLetterID}][%d] deleteDeadLetterNotFound ", 404)
+}
+
+func (o *DeleteDeadLetterNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
@@ -0,0 +1,110 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// GetDeadLetterReader is a Reader for the GetDeadLetter structure.
+type GetDeadLetterReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetDeadLetterReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetDeadLetterOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewGetDeadLetterNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewGetDeadLetterOK creates a GetDeadLetterOK with default headers values
+func NewGetDeadLetterOK() *GetDeadLetterOK {
+	return &GetDeadLetterOK{}
+}
+
+/*GetDeadLetterOK handles this case with default header values.
+
+Get dead letter response
+*/
+type GetDeadLetterOK struct {
+	Payload *models.DeadLetter
+}
+
+func (o *GetDeadLetterOK) Error() string {
+	return fmt.Sprintf("[GET /deadletter/{deadLetterID}][%d] getDeadLetterOK  %+v", 200, o.Payload)
+}
+
+func (o *GetDeadLetterOK) GetPayload() *models.DeadLetter {
+	return o.Payload
+}
+
+func (o *GetDeadLetterOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.DeadLetter)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetDeadLetterNotFound creates a GetDeadLetterNotFound with default headers values
+func NewGetDeadLetterNotFound() *GetDeadLetterNotFound {
+	return &GetDeadLetterNotFound{}
+}
+
+/*GetDeadLetterNotFound handles this case with default header values.
+
+A dead letter with the specified ID was not found
+*/
+type GetDeadLetterNotFound struct {
+}
+
+func (o *GetDeadLetterNotFound) Error() string {
+	return fmt.Sprintf("[GET /deadletter/{deadLetterID}][%d] getDeadLetterNotFound ", 404)
+}
+
+func (o *GetDeadLetterNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewGetDeadLettersParams creates a new GetDeadLettersParams object
+// with the default values initialized.
+func NewGetDeadLettersParams() *GetDeadLettersParams {
+	var ()
+	return &GetDeadLettersParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetDeadLettersParamsWithTimeout creates a new GetDeadLettersParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewGetDeadLettersParamsWithTimeout(timeout time.Duration) *GetDeadLettersParams {
+	var ()
+	return &GetDeadLettersParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewGetDeadLettersParamsWithContext creates a new GetDeadLettersParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewGetDeadLettersParamsWithContext(ctx context.Context) *GetDeadLettersParams {
+	var ()
+	return &GetDeadLettersParams{
+
+		Context: ctx,
+	}
+}
+
+// NewGetDeadLettersParamsWithHTTPClient creates a new GetDeadLettersParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewGetDeadLettersParamsWithHTTPClient(client *http.Client) *GetDeadLettersParams {
+	var ()
+	return &GetDeadLettersParams{
+		HTTPClient: client,
+	}
+}
+
+/*GetDeadLettersParams contains all the parameters to send to the API endpoint
+for the get dead letters operation typically these are written to a http.Request
+*/
+type GetDeadLettersParams struct {
+
+	/*Receiver
+	  Only return dead letters of the given receiver
+
+	*/
+	Receiver *string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the get dead letters params
+func (o *GetDeadLettersParams) WithTimeout(timeout time.Duration) *GetDeadLettersParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get dead letters params
+func (o *GetDeadLettersParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get dead letters params
+func (o *GetDeadLettersParams) WithContext(ctx context.Context) *GetDeadLettersParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get dead letters params
+func (o *GetDeadLettersParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get dead letters params
+func (o *GetDeadLettersParams) WithHTTPClient(client *http.Client) *GetDeadLettersParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get dead letters params
+func (o *GetDeadLettersParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithReceiver adds the receiver to the get dead letters params
+func (o *GetDeadLettersParams) WithReceiver(receiver *string) *GetDeadLettersParams {
+	o.SetReceiver(receiver)
+	return o
+}
+
+// SetReceiver adds the receiver to the get dead letters params
+func (o *GetDeadLettersParams) SetReceiver(receiver *string) {
+	o.Receiver = receiver
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetDeadLettersParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if o.Receiver != nil {
+
+		// query param receiver
+		var qrReceiver string
+		if o.Receiver != nil {
+			qrReceiver = *o.Receiver
+		}
+		qReceiver := qrReceiver
+		if qReceiver != "" {
+			if err := r.SetQueryParam("receiver", qReceiver); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
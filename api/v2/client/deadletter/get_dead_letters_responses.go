@@ -0,0 +1,81 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// GetDeadLettersReader is a Reader for the GetDeadLetters structure.
+type GetDeadLettersReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetDeadLettersReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetDeadLettersOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewGetDeadLettersOK creates a GetDeadLettersOK with default headers values
+func NewGetDeadLettersOK() *GetDeadLettersOK {
+	return &GetDeadLettersOK{}
+}
+
+/*GetDeadLettersOK handles this case with default header values.
+
+Get dead letters response
+*/
+type GetDeadLettersOK struct {
+	Payload models.DeadLetters
+}
+
+func (o *GetDeadLettersOK) Error() string {
+	return fmt.Sprintf("[GET /deadletters][%d] getDeadLettersOK  %+v", 200, o.Payload)
+}
+
+func (o *GetDeadLettersOK) GetPayload() models.DeadLetters {
+	return o.Payload
+}
+
+func (o *GetDeadLettersOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
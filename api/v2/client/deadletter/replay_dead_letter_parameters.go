@@ -0,0 +1,149 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewReplayDeadLetterParams creates a new ReplayDeadLetterParams object
+// with the default values initialized.
+func NewReplayDeadLetterParams() *ReplayDeadLetterParams {
+	var ()
+	return &ReplayDeadLetterParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewReplayDeadLetterParamsWithTimeout creates a new ReplayDeadLetterParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewReplayDeadLetterParamsWithTimeout(timeout time.Duration) *ReplayDeadLetterParams {
+	var ()
+	return &ReplayDeadLetterParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewReplayDeadLetterParamsWithContext creates a new ReplayDeadLetterParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewReplayDeadLetterParamsWithContext(ctx context.Context) *ReplayDeadLetterParams {
+	var ()
+	return &ReplayDeadLetterParams{
+
+		Context: ctx,
+	}
+}
+
+// NewReplayDeadLetterParamsWithHTTPClient creates a new ReplayDeadLetterParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewReplayDeadLetterParamsWithHTTPClient(client *http.Client) *ReplayDeadLetterParams {
+	var ()
+	return &ReplayDeadLetterParams{
+		HTTPClient: client,
+	}
+}
+
+/*ReplayDeadLetterParams contains all the parameters to send to the API endpoint
+for the replay dead letter operation typically these are written to a http.Request
+*/
+type ReplayDeadLetterParams struct {
+
+	/*DeadLetterID
+	  ID of the dead letter
+
+	*/
+	DeadLetterID strfmt.UUID
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the replay dead letter params
+func (o *ReplayDeadLetterParams) WithTimeout(timeout time.Duration) *ReplayDeadLetterParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the replay dead letter params
+func (o *ReplayDeadLetterParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the replay dead letter params
+func (o *ReplayDeadLetterParams) WithContext(ctx context.Context) *ReplayDeadLetterParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the replay dead letter params
+func (o *ReplayDeadLetterParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the replay dead letter params
+func (o *ReplayDeadLetterParams) WithHTTPClient(client *http.Client) *ReplayDeadLetterParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the replay dead letter params
+func (o *ReplayDeadLetterParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithDeadLetterID adds the deadLetterID to the replay dead letter params
+func (o *ReplayDeadLetterParams) WithDeadLetterID(deadLetterID strfmt.UUID) *ReplayDeadLetterParams {
+	o.SetDeadLetterID(deadLetterID)
+	return o
+}
+
+// SetDeadLetterID adds the deadLetterId to the replay dead letter params
+func (o *ReplayDeadLetterParams) SetDeadLetterID(deadLetterID strfmt.UUID) {
+	o.DeadLetterID = deadLetterID
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *ReplayDeadLetterParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param deadLetterID
+	if err := r.SetPathParam("deadLetterID", o.DeadLetterID.String()); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+// ReplayDeadLetterReader is a Reader for the ReplayDeadLetter structure.
+type ReplayDeadLetterReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *ReplayDeadLetterReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewReplayDeadLetterOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewReplayDeadLetterNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 500:
+		result := NewReplayDeadLetterInternalServerError()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewReplayDeadLetterOK creates a ReplayDeadLetterOK with default headers values
+func NewReplayDeadLetterOK() *ReplayDeadLetterOK {
+	return &ReplayDeadLetterOK{}
+}
+
+/*ReplayDeadLetterOK handles this case with default header values.
+
+Replay dead letter response
+*/
+type ReplayDeadLetterOK struct {
+}
+
+func (o *ReplayDeadLetterOK) Error() string {
+	return fmt.Sprintf("[POST /deadletter/{deadLetterID}/replay][%d] replayDeadLetterOK ", 200)
+}
+
+func (o *ReplayDeadLetterOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewReplayDeadLetterNotFound creates a ReplayDeadLetterNotFound with default headers values
+func NewReplayDeadLetterNotFound() *ReplayDeadLetterNotFound {
+	return &ReplayDeadLetterNotFound{}
+}
+
+/*ReplayDeadLetterNotFound handles this case with default header values.
+
+A dead letter with the specified ID was not found
+*/
+type ReplayDeadLetterNotFound struct {
+}
+
+func (o *ReplayDeadLetterNotFound) Error() string {
+	return fmt.Sprintf("[POST /deadletter/{deadLetterID}/replay][%d] replayDeadLetterNotFound ", 404)
+}
+
+func (o *ReplayDeadLetterNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewReplayDeadLetterInternalServerError creates a ReplayDeadLetterInternalServerError with default headers values
+func NewReplayDeadLetterInternalServerError() *ReplayDeadLetterInternalServerError {
+	return &ReplayDeadLetterInternalServerError{}
+}
+
+/*ReplayDeadLetterInternalServerError handles this case with default header values.
+
+Internal server error
+*/
+type ReplayDeadLetterInternalServerError struct {
+	Payload string
+}
+
+func (o *ReplayDeadLetterInternalServerError) Error() string {
+	return fmt.Sprintf("[POST /deadletter/{deadLetterID}/replay][%d] replayDeadLetterInternalServerError  %+v", 500, o.Payload)
+}
+
+func (o *ReplayDeadLetterInternalServerError) GetPayload() string {
+	return o.Payload
+}
+
+func (o *ReplayDeadLetterInternalServerError) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
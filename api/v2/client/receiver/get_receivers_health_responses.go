@@ -0,0 +1,81 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// GetReceiversHealthReader is a Reader for the GetReceiversHealth structure.
+type GetReceiversHealthReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetReceiversHealthReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetReceiversHealthOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewGetReceiversHealthOK creates a GetReceiversHealthOK with default headers values
+func NewGetReceiversHealthOK() *GetReceiversHealthOK {
+	return &GetReceiversHealthOK{}
+}
+
+/*GetReceiversHealthOK handles this case with default header values.
+
+Get receivers health response
+*/
+type GetReceiversHealthOK struct {
+	Payload models.ReceiversHealth
+}
+
+func (o *GetReceiversHealthOK) Error() string {
+	return fmt.Sprintf("[GET /receivers/health][%d] getReceiversHealthOK  %+v", 200, o.Payload)
+}
+
+func (o *GetReceiversHealthOK) GetPayload() models.ReceiversHealth {
+	return o.Payload
+}
+
+func (o *GetReceiversHealthOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
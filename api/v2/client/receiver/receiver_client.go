@@ -41,13 +41,91 @@ type Client struct {
 
 // ClientService is the interface for Client methods
 type ClientService interface {
+	GetBudgets(params *GetBudgetsParams) (*GetBudgetsOK, error)
+
+	GetNotificationHistory(params *GetNotificationHistoryParams) (*GetNotificationHistoryOK, error)
+
 	GetReceivers(params *GetReceiversParams) (*GetReceiversOK, error)
 
+	GetReceiversHealth(params *GetReceiversHealthParams) (*GetReceiversHealthOK, error)
+
+	RenderReceiver(params *RenderReceiverParams) (*RenderReceiverOK, error)
+
+	TestReceiver(params *TestReceiverParams) (*TestReceiverOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
 /*
-  GetReceivers Get list of all receivers (name of notification integrations)
+  GetBudgets Get the consumption of the notification budgets of receivers and tenants in their current window
+*/
+func (a *Client) GetBudgets(params *GetBudgetsParams) (*GetBudgetsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetBudgetsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getBudgets",
+		Method:             "GET",
+		PathPattern:        "/budgets",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetBudgetsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetBudgetsOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for getBudgets: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  GetNotificationHistory Get the recorded attempts to send notifications, oldest first
+*/
+func (a *Client) GetNotificationHistory(params *GetNotificationHistoryParams) (*GetNotificationHistoryOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetNotificationHistoryParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getNotificationHistory",
+		Method:             "GET",
+		PathPattern:        "/history",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetNotificationHistoryReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetNotificationHistoryOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for getNotificationHistory: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  GetReceivers Get list of all receivers along with the types of their integrations
 */
 func (a *Client) GetReceivers(params *GetReceiversParams) (*GetReceiversOK, error) {
 	// TODO: Validate the params before sending
@@ -80,6 +158,108 @@ func (a *Client) GetReceivers(params *GetReceiversParams) (*GetReceiversOK, erro
 	panic(msg)
 }
 
+/*
+  GetReceiversHealth Get the health of the integrations of all receivers, summarized from their notification attempts
+*/
+func (a *Client) GetReceiversHealth(params *GetReceiversHealthParams) (*GetReceiversHealthOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetReceiversHealthParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getReceiversHealth",
+		Method:             "GET",
+		PathPattern:        "/receivers/health",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetReceiversHealthReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetReceiversHealthOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for getReceiversHealth: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  RenderReceiver Render the notifications each integration of the receiver would send for the given alerts, without sending them
+*/
+func (a *Client) RenderReceiver(params *RenderReceiverParams) (*RenderReceiverOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewRenderReceiverParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "renderReceiver",
+		Method:             "POST",
+		PathPattern:        "/receivers/{name}/render",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &RenderReceiverReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*RenderReceiverOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for renderReceiver: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  TestReceiver Send a test alert once through each integration of the receiver
+*/
+func (a *Client) TestReceiver(params *TestReceiverParams) (*TestReceiverOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewTestReceiverParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "testReceiver",
+		Method:             "POST",
+		PathPattern:        "/receivers/{name}/test",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &TestReceiverReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*TestReceiverOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for testReceiver: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -0,0 +1,173 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// NewRenderReceiverParams creates a new RenderReceiverParams object
+// with the default values initialized.
+func NewRenderReceiverParams() *RenderReceiverParams {
+	var ()
+	return &RenderReceiverParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewRenderReceiverParamsWithTimeout creates a new RenderReceiverParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewRenderReceiverParamsWithTimeout(timeout time.Duration) *RenderReceiverParams {
+	var ()
+	return &RenderReceiverParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewRenderReceiverParamsWithContext creates a new RenderReceiverParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewRenderReceiverParamsWithContext(ctx context.Context) *RenderReceiverParams {
+	var ()
+	return &RenderReceiverParams{
+
+		Context: ctx,
+	}
+}
+
+// NewRenderReceiverParamsWithHTTPClient creates a new RenderReceiverParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewRenderReceiverParamsWithHTTPClient(client *http.Client) *RenderReceiverParams {
+	var ()
+	return &RenderReceiverParams{
+		HTTPClient: client,
+	}
+}
+
+/*RenderReceiverParams contains all the parameters to send to the API endpoint
+for the render receiver operation typically these are written to a http.Request
+*/
+type RenderReceiverParams struct {
+
+	/*Alerts
+	  The alerts to render the notifications for
+
+	*/
+	Alerts models.PostableAlerts
+	/*Name
+	  Name of the receiver
+
+	*/
+	Name string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the render receiver params
+func (o *RenderReceiverParams) WithTimeout(timeout time.Duration) *RenderReceiverParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the render receiver params
+func (o *RenderReceiverParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the render receiver params
+func (o *RenderReceiverParams) WithContext(ctx context.Context) *RenderReceiverParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the render receiver params
+func (o *RenderReceiverParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the render receiver params
+func (o *RenderReceiverParams) WithHTTPClient(client *http.Client) *RenderReceiverParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the render receiver params
+func (o *RenderReceiverParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithAlerts adds the alerts to the render receiver params
+func (o *RenderReceiverParams) WithAlerts(alerts models.PostableAlerts) *RenderReceiverParams {
+	o.SetAlerts(alerts)
+	return o
+}
+
+// SetAlerts adds the alerts to the render receiver params
+func (o *RenderReceiverParams) SetAlerts(alerts models.PostableAlerts) {
+	o.Alerts = alerts
+}
+
+// WithName adds the name to the render receiver params
+func (o *RenderReceiverParams) WithName(name string) *RenderReceiverParams {
+	o.SetName(name)
+	return o
+}
+
+// SetName adds the name to the render receiver params
+func (o *RenderReceiverParams) SetName(name string) {
+	o.Name = name
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *RenderReceiverParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if o.Alerts != nil {
+		if err := r.SetBodyParam(o.Alerts); err != nil {
+			return err
+		}
+	}
+
+	// path param name
+	if err := r.SetPathParam("name", o.Name); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
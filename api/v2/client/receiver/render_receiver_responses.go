@@ -0,0 +1,145 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// RenderReceiverReader is a Reader for the RenderReceiver structure.
+type RenderReceiverReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *RenderReceiverReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewRenderReceiverOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 400:
+		result := NewRenderReceiverBadRequest()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 404:
+		result := NewRenderReceiverNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewRenderReceiverOK creates a RenderReceiverOK with default headers values
+func NewRenderReceiverOK() *RenderReceiverOK {
+	return &RenderReceiverOK{}
+}
+
+/*RenderReceiverOK handles this case with default header values.
+
+Render receiver response
+*/
+type RenderReceiverOK struct {
+	Payload models.RenderedNotifications
+}
+
+func (o *RenderReceiverOK) Error() string {
+	return fmt.Sprintf("[POST /receivers/{name}/render][%d] renderReceiverOK  %+v", 200, o.Payload)
+}
+
+func (o *RenderReceiverOK) GetPayload() models.RenderedNotifications {
+	return o.Payload
+}
+
+func (o *RenderReceiverOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewRenderReceiverBadRequest creates a RenderReceiverBadRequest with default headers values
+func NewRenderReceiverBadRequest() *RenderReceiverBadRequest {
+	return &RenderReceiverBadRequest{}
+}
+
+/*RenderReceiverBadRequest handles this case with default header values.
+
+Bad request
+*/
+type RenderReceiverBadRequest struct {
+	Payload string
+}
+
+func (o *RenderReceiverBadRequest) Error() string {
+	return fmt.Sprintf("[POST /receivers/{name}/render][%d] renderReceiverBadRequest  %+v", 400, o.Payload)
+}
+
+func (o *RenderReceiverBadRequest) GetPayload() string {
+	return o.Payload
+}
+
+func (o *RenderReceiverBadRequest) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewRenderReceiverNotFound creates a RenderReceiverNotFound with default headers values
+func NewRenderReceiverNotFound() *RenderReceiverNotFound {
+	return &RenderReceiverNotFound{}
+}
+
+/*RenderReceiverNotFound handles this case with default header values.
+
+A receiver with the specified name was not found or is not used by any route
+*/
+type RenderReceiverNotFound struct {
+}
+
+func (o *RenderReceiverNotFound) Error() string {
+	return fmt.Sprintf("[POST /receivers/{name}/render][%d] renderReceiverNotFound ", 404)
+}
+
+func (o *RenderReceiverNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
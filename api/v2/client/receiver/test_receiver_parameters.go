@@ -0,0 +1,149 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewTestReceiverParams creates a new TestReceiverParams object
+// with the default values initialized.
+func NewTestReceiverParams() *TestReceiverParams {
+	var ()
+	return &TestReceiverParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewTestReceiverParamsWithTimeout creates a new TestReceiverParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewTestReceiverParamsWithTimeout(timeout time.Duration) *TestReceiverParams {
+	var ()
+	return &TestReceiverParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewTestReceiverParamsWithContext creates a new TestReceiverParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewTestReceiverParamsWithContext(ctx context.Context) *TestReceiverParams {
+	var ()
+	return &TestReceiverParams{
+
+		Context: ctx,
+	}
+}
+
+// NewTestReceiverParamsWithHTTPClient creates a new TestReceiverParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewTestReceiverParamsWithHTTPClient(client *http.Client) *TestReceiverParams {
+	var ()
+	return &TestReceiverParams{
+		HTTPClient: client,
+	}
+}
+
+/*TestReceiverParams contains all the parameters to send to the API endpoint
+for the test receiver operation typically these are written to a http.Request
+*/
+type TestReceiverParams struct {
+
+	/*Name
+	  Name of the receiver
+
+	*/
+	Name string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the test receiver params
+func (o *TestReceiverParams) WithTimeout(timeout time.Duration) *TestReceiverParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the test receiver params
+func (o *TestReceiverParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the test receiver params
+func (o *TestReceiverParams) WithContext(ctx context.Context) *TestReceiverParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the test receiver params
+func (o *TestReceiverParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the test receiver params
+func (o *TestReceiverParams) WithHTTPClient(client *http.Client) *TestReceiverParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the test receiver params
+func (o *TestReceiverParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithName adds the name to the test receiver params
+func (o *TestReceiverParams) WithName(name string) *TestReceiverParams {
+	o.SetName(name)
+	return o
+}
+
+// SetName adds the name to the test receiver params
+func (o *TestReceiverParams) SetName(name string) {
+	o.Name = name
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *TestReceiverParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param name
+	if err := r.SetPathParam("name", o.Name); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// TestReceiverReader is a Reader for the TestReceiver structure.
+type TestReceiverReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *TestReceiverReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewTestReceiverOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewTestReceiverNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewTestReceiverOK creates a TestReceiverOK with default headers values
+func NewTestReceiverOK() *TestReceiverOK {
+	return &TestReceiverOK{}
+}
+
+/*TestReceiverOK handles this case with default header values.
+
+Test receiver response
+*/
+type TestReceiverOK struct {
+	Payload models.IntegrationTestResults
+}
+
+func (o *TestReceiverOK) Error() string {
+	return fmt.Sprintf("[POST /receivers/{name}/test][%d] testReceiverOK  %+v", 200, o.Payload)
+}
+
+func (o *TestReceiverOK) GetPayload() models.IntegrationTestResults {
+	return o.Payload
+}
+
+func (o *TestReceiverOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewTestReceiverNotFound creates a TestReceiverNotFound with default headers values
+func NewTestReceiverNotFound() *TestReceiverNotFound {
+	return &TestReceiverNotFound{}
+}
+
+/*TestReceiverNotFound handles this case with default header values.
+
+A receiver with the specified name was not found or is not used by any route
+*/
+type TestReceiverNotFound struct {
+}
+
+func (o *TestReceiverNotFound) Error() string {
+	return fmt.Sprintf("[POST /receivers/{name}/test][%d] testReceiverNotFound ", 404)
+}
+
+func (o *TestReceiverNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
@@ -74,6 +74,11 @@ for the delete silence operation typically these are written to a http.Request
 */
 type DeleteSilenceParams struct {
 
+	/*ExpiredBy
+	  The user expiring the silence, recorded in the silence events
+
+	*/
+	ExpiredBy *string
 	/*SilenceID
 	  ID of the silence to get
 
@@ -118,6 +123,17 @@ func (o *DeleteSilenceParams) SetHTTPClient(client *http.Client) {
 	o.HTTPClient = client
 }
 
+// WithExpiredBy adds the expiredBy to the delete silence params
+func (o *DeleteSilenceParams) WithExpiredBy(expiredBy *string) *DeleteSilenceParams {
+	o.SetExpiredBy(expiredBy)
+	return o
+}
+
+// SetExpiredBy adds the expiredBy to the delete silence params
+func (o *DeleteSilenceParams) SetExpiredBy(expiredBy *string) {
+	o.ExpiredBy = expiredBy
+}
+
 // WithSilenceID adds the silenceID to the delete silence params
 func (o *DeleteSilenceParams) WithSilenceID(silenceID strfmt.UUID) *DeleteSilenceParams {
 	o.SetSilenceID(silenceID)
@@ -137,6 +153,22 @@ func (o *DeleteSilenceParams) WriteToRequest(r runtime.ClientRequest, reg strfmt
 	}
 	var res []error
 
+	if o.ExpiredBy != nil {
+
+		// query param expiredBy
+		var qrExpiredBy string
+		if o.ExpiredBy != nil {
+			qrExpiredBy = *o.ExpiredBy
+		}
+		qExpiredBy := qrExpiredBy
+		if qExpiredBy != "" {
+			if err := r.SetQueryParam("expiredBy", qExpiredBy); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	// path param silenceID
 	if err := r.SetPathParam("silenceID", o.SilenceID.String()); err != nil {
 		return err
@@ -0,0 +1,152 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// NewExpireSilencesParams creates a new ExpireSilencesParams object
+// with the default values initialized.
+func NewExpireSilencesParams() *ExpireSilencesParams {
+	var ()
+	return &ExpireSilencesParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewExpireSilencesParamsWithTimeout creates a new ExpireSilencesParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewExpireSilencesParamsWithTimeout(timeout time.Duration) *ExpireSilencesParams {
+	var ()
+	return &ExpireSilencesParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewExpireSilencesParamsWithContext creates a new ExpireSilencesParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewExpireSilencesParamsWithContext(ctx context.Context) *ExpireSilencesParams {
+	var ()
+	return &ExpireSilencesParams{
+
+		Context: ctx,
+	}
+}
+
+// NewExpireSilencesParamsWithHTTPClient creates a new ExpireSilencesParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewExpireSilencesParamsWithHTTPClient(client *http.Client) *ExpireSilencesParams {
+	var ()
+	return &ExpireSilencesParams{
+		HTTPClient: client,
+	}
+}
+
+/*ExpireSilencesParams contains all the parameters to send to the API endpoint
+for the expire silences operation typically these are written to a http.Request
+*/
+type ExpireSilencesParams struct {
+
+	/*Request
+	  The silences to expire
+
+	*/
+	Request *models.BulkSilenceRequest
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the expire silences params
+func (o *ExpireSilencesParams) WithTimeout(timeout time.Duration) *ExpireSilencesParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the expire silences params
+func (o *ExpireSilencesParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the expire silences params
+func (o *ExpireSilencesParams) WithContext(ctx context.Context) *ExpireSilencesParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the expire silences params
+func (o *ExpireSilencesParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the expire silences params
+func (o *ExpireSilencesParams) WithHTTPClient(client *http.Client) *ExpireSilencesParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the expire silences params
+func (o *ExpireSilencesParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithRequest adds the request to the expire silences params
+func (o *ExpireSilencesParams) WithRequest(request *models.BulkSilenceRequest) *ExpireSilencesParams {
+	o.SetRequest(request)
+	return o
+}
+
+// SetRequest adds the request to the expire silences params
+func (o *ExpireSilencesParams) SetRequest(request *models.BulkSilenceRequest) {
+	o.Request = request
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *ExpireSilencesParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if o.Request != nil {
+		if err := r.SetBodyParam(o.Request); err != nil {
+			return err
+		}
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// ExpireSilencesReader is a Reader for the ExpireSilences structure.
+type ExpireSilencesReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *ExpireSilencesReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewExpireSilencesOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 400:
+		result := NewExpireSilencesBadRequest()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 500:
+		result := NewExpireSilencesInternalServerError()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewExpireSilencesOK creates a ExpireSilencesOK with default headers values
+func NewExpireSilencesOK() *ExpireSilencesOK {
+	return &ExpireSilencesOK{}
+}
+
+/*ExpireSilencesOK handles this case with default header values.
+
+Expire silences response
+*/
+type ExpireSilencesOK struct {
+	Payload *models.BulkSilenceResult
+}
+
+func (o *ExpireSilencesOK) Error() string {
+	return fmt.Sprintf("[POST /silences/expire][%d] expireSilencesOK  %+v", 200, o.Payload)
+}
+
+func (o *ExpireSilencesOK) GetPayload() *models.BulkSilenceResult {
+	return o.Payload
+}
+
+func (o *ExpireSilencesOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.BulkSilenceResult)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewExpireSilencesBadRequest creates a ExpireSilencesBadRequest with default headers values
+func NewExpireSilencesBadRequest() *ExpireSilencesBadRequest {
+	return &ExpireSilencesBadRequest{}
+}
+
+/*ExpireSilencesBadRequest handles this case with default header values.
+
+Bad request
+*/
+type ExpireSilencesBadRequest struct {
+	Payload string
+}
+
+func (o *ExpireSilencesBadRequest) Error() string {
+	return fmt.Sprintf("[POST /silences/expire][%d] expireSilencesBadRequest  %+v", 400, o.Payload)
+}
+
+func (o *ExpireSilencesBadRequest) GetPayload() string {
+	return o.Payload
+}
+
+func (o *ExpireSilencesBadRequest) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewExpireSilencesInternalServerError creates a ExpireSilencesInternalServerError with default headers values
+func NewExpireSilencesInternalServerError() *ExpireSilencesInternalServerError {
+	return &ExpireSilencesInternalServerError{}
+}
+
+/*ExpireSilencesInternalServerError handles this case with default header values.
+
+Internal server error
+*/
+type ExpireSilencesInternalServerError struct {
+	Payload string
+}
+
+func (o *ExpireSilencesInternalServerError) Error() string {
+	return fmt.Sprintf("[POST /silences/expire][%d] expireSilencesInternalServerError  %+v", 500, o.Payload)
+}
+
+func (o *ExpireSilencesInternalServerError) GetPayload() string {
+	return o.Payload
+}
+
+func (o *ExpireSilencesInternalServerError) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
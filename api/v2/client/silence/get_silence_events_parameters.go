@@ -0,0 +1,256 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewGetSilenceEventsParams creates a new GetSilenceEventsParams object
+// with the default values initialized.
+func NewGetSilenceEventsParams() *GetSilenceEventsParams {
+	var ()
+	return &GetSilenceEventsParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetSilenceEventsParamsWithTimeout creates a new GetSilenceEventsParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewGetSilenceEventsParamsWithTimeout(timeout time.Duration) *GetSilenceEventsParams {
+	var ()
+	return &GetSilenceEventsParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewGetSilenceEventsParamsWithContext creates a new GetSilenceEventsParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewGetSilenceEventsParamsWithContext(ctx context.Context) *GetSilenceEventsParams {
+	var ()
+	return &GetSilenceEventsParams{
+
+		Context: ctx,
+	}
+}
+
+// NewGetSilenceEventsParamsWithHTTPClient creates a new GetSilenceEventsParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewGetSilenceEventsParamsWithHTTPClient(client *http.Client) *GetSilenceEventsParams {
+	var ()
+	return &GetSilenceEventsParams{
+		HTTPClient: client,
+	}
+}
+
+/*GetSilenceEventsParams contains all the parameters to send to the API endpoint
+for the get silence events operation typically these are written to a http.Request
+*/
+type GetSilenceEventsParams struct {
+
+	/*SilenceID
+	  Only return events of the given silence
+
+	*/
+	SilenceID *string
+	/*Since
+	  Only return events which happened at or after the given time
+
+	*/
+	Since *strfmt.DateTime
+	/*Until
+	  Only return events which happened at or before the given time
+
+	*/
+	Until *strfmt.DateTime
+	/*User
+	  Only return events made by the given user
+
+	*/
+	User *string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the get silence events params
+func (o *GetSilenceEventsParams) WithTimeout(timeout time.Duration) *GetSilenceEventsParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get silence events params
+func (o *GetSilenceEventsParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get silence events params
+func (o *GetSilenceEventsParams) WithContext(ctx context.Context) *GetSilenceEventsParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get silence events params
+func (o *GetSilenceEventsParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get silence events params
+func (o *GetSilenceEventsParams) WithHTTPClient(client *http.Client) *GetSilenceEventsParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get silence events params
+func (o *GetSilenceEventsParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithSilenceID adds the silenceID to the get silence events params
+func (o *GetSilenceEventsParams) WithSilenceID(silenceID *string) *GetSilenceEventsParams {
+	o.SetSilenceID(silenceID)
+	return o
+}
+
+// SetSilenceID adds the silenceId to the get silence events params
+func (o *GetSilenceEventsParams) SetSilenceID(silenceID *string) {
+	o.SilenceID = silenceID
+}
+
+// WithSince adds the since to the get silence events params
+func (o *GetSilenceEventsParams) WithSince(since *strfmt.DateTime) *GetSilenceEventsParams {
+	o.SetSince(since)
+	return o
+}
+
+// SetSince adds the since to the get silence events params
+func (o *GetSilenceEventsParams) SetSince(since *strfmt.DateTime) {
+	o.Since = since
+}
+
+// WithUntil adds the until to the get silence events params
+func (o *GetSilenceEventsParams) WithUntil(until *strfmt.DateTime) *GetSilenceEventsParams {
+	o.SetUntil(until)
+	return o
+}
+
+// SetUntil adds the until to the get silence events params
+func (o *GetSilenceEventsParams) SetUntil(until *strfmt.DateTime) {
+	o.Until = until
+}
+
+// WithUser adds the user to the get silence events params
+func (o *GetSilenceEventsParams) WithUser(user *string) *GetSilenceEventsParams {
+	o.SetUser(user)
+	return o
+}
+
+// SetUser adds the user to the get silence events params
+func (o *GetSilenceEventsParams) SetUser(user *string) {
+	o.User = user
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetSilenceEventsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if o.SilenceID != nil {
+
+		// query param silenceID
+		var qrSilenceID string
+		if o.SilenceID != nil {
+			qrSilenceID = *o.SilenceID
+		}
+		qSilenceID := qrSilenceID
+		if qSilenceID != "" {
+			if err := r.SetQueryParam("silenceID", qSilenceID); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Since != nil {
+
+		// query param since
+		var qrSince strfmt.DateTime
+		if o.Since != nil {
+			qrSince = *o.Since
+		}
+		qSince := qrSince.String()
+		if qSince != "" {
+			if err := r.SetQueryParam("since", qSince); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Until != nil {
+
+		// query param until
+		var qrUntil strfmt.DateTime
+		if o.Until != nil {
+			qrUntil = *o.Until
+		}
+		qUntil := qrUntil.String()
+		if qUntil != "" {
+			if err := r.SetQueryParam("until", qUntil); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.User != nil {
+
+		// query param user
+		var qrUser string
+		if o.User != nil {
+			qrUser = *o.User
+		}
+		qUser := qrUser
+		if qUser != "" {
+			if err := r.SetQueryParam("user", qUser); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
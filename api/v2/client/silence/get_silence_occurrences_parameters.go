@@ -0,0 +1,194 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// NewGetSilenceOccurrencesParams creates a new GetSilenceOccurrencesParams object
+// with the default values initialized.
+func NewGetSilenceOccurrencesParams() *GetSilenceOccurrencesParams {
+	var (
+		limitDefault = int64(10)
+	)
+	return &GetSilenceOccurrencesParams{
+		Limit: &limitDefault,
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetSilenceOccurrencesParamsWithTimeout creates a new GetSilenceOccurrencesParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewGetSilenceOccurrencesParamsWithTimeout(timeout time.Duration) *GetSilenceOccurrencesParams {
+	var (
+		limitDefault = int64(10)
+	)
+	return &GetSilenceOccurrencesParams{
+		Limit: &limitDefault,
+
+		timeout: timeout,
+	}
+}
+
+// NewGetSilenceOccurrencesParamsWithContext creates a new GetSilenceOccurrencesParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewGetSilenceOccurrencesParamsWithContext(ctx context.Context) *GetSilenceOccurrencesParams {
+	var (
+		limitDefault = int64(10)
+	)
+	return &GetSilenceOccurrencesParams{
+		Limit: &limitDefault,
+
+		Context: ctx,
+	}
+}
+
+// NewGetSilenceOccurrencesParamsWithHTTPClient creates a new GetSilenceOccurrencesParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewGetSilenceOccurrencesParamsWithHTTPClient(client *http.Client) *GetSilenceOccurrencesParams {
+	var (
+		limitDefault = int64(10)
+	)
+	return &GetSilenceOccurrencesParams{
+		Limit:      &limitDefault,
+		HTTPClient: client,
+	}
+}
+
+/*GetSilenceOccurrencesParams contains all the parameters to send to the API endpoint
+for the get silence occurrences operation typically these are written to a http.Request
+*/
+type GetSilenceOccurrencesParams struct {
+
+	/*Limit
+	  The maximum number of occurrences to return
+
+	*/
+	Limit *int64
+	/*SilenceID
+	  ID of the silence
+
+	*/
+	SilenceID strfmt.UUID
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) WithTimeout(timeout time.Duration) *GetSilenceOccurrencesParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) WithContext(ctx context.Context) *GetSilenceOccurrencesParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) WithHTTPClient(client *http.Client) *GetSilenceOccurrencesParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithLimit adds the limit to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) WithLimit(limit *int64) *GetSilenceOccurrencesParams {
+	o.SetLimit(limit)
+	return o
+}
+
+// SetLimit adds the limit to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) SetLimit(limit *int64) {
+	o.Limit = limit
+}
+
+// WithSilenceID adds the silenceID to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) WithSilenceID(silenceID strfmt.UUID) *GetSilenceOccurrencesParams {
+	o.SetSilenceID(silenceID)
+	return o
+}
+
+// SetSilenceID adds the silenceId to the get silence occurrences params
+func (o *GetSilenceOccurrencesParams) SetSilenceID(silenceID strfmt.UUID) {
+	o.SilenceID = silenceID
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetSilenceOccurrencesParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if o.Limit != nil {
+
+		// query param limit
+		var qrLimit int64
+		if o.Limit != nil {
+			qrLimit = *o.Limit
+		}
+		qLimit := swag.FormatInt64(qrLimit)
+		if qLimit != "" {
+			if err := r.SetQueryParam("limit", qLimit); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	// path param silenceID
+	if err := r.SetPathParam("silenceID", o.SilenceID.String()); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// GetSilenceOccurrencesReader is a Reader for the GetSilenceOccurrences structure.
+type GetSilenceOccurrencesReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetSilenceOccurrencesReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetSilenceOccurrencesOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewGetSilenceOccurrencesNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 500:
+		result := NewGetSilenceOccurrencesInternalServerError()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewGetSilenceOccurrencesOK creates a GetSilenceOccurrencesOK with default headers values
+func NewGetSilenceOccurrencesOK() *GetSilenceOccurrencesOK {
+	return &GetSilenceOccurrencesOK{}
+}
+
+/*GetSilenceOccurrencesOK handles this case with default header values.
+
+Get silence occurrences response
+*/
+type GetSilenceOccurrencesOK struct {
+	Payload models.SilenceOccurrences
+}
+
+func (o *GetSilenceOccurrencesOK) Error() string {
+	return fmt.Sprintf("[GET /silence/{silenceID}/occurrences][%d] getSilenceOccurrencesOK  %+v", 200, o.Payload)
+}
+
+func (o *GetSilenceOccurrencesOK) GetPayload() models.SilenceOccurrences {
+	return o.Payload
+}
+
+func (o *GetSilenceOccurrencesOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetSilenceOccurrencesNotFound creates a GetSilenceOccurrencesNotFound with default headers values
+func NewGetSilenceOccurrencesNotFound() *GetSilenceOccurrencesNotFound {
+	return &GetSilenceOccurrencesNotFound{}
+}
+
+/*GetSilenceOccurrencesNotFound handles this case with default header values.
+
+A silence with the specified ID was not found
+*/
+type GetSilenceOccurrencesNotFound struct {
+}
+
+func (o *GetSilenceOccurrencesNotFound) Error() string {
+	return fmt.Sprintf("[GET /silence/{silenceID}/occurrences][%d] getSilenceOccurrencesNotFound ", 404)
+}
+
+func (o *GetSilenceOccurrencesNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewGetSilenceOccurrencesInternalServerError creates a GetSilenceOccurrencesInternalServerError with default headers values
+func NewGetSilenceOccurrencesInternalServerError() *GetSilenceOccurrencesInternalServerError {
+	return &GetSilenceOccurrencesInternalServerError{}
+}
+
+/*GetSilenceOccurrencesInternalServerError handles this case with default header values.
+
+Internal server error
+*/
+type GetSilenceOccurrencesInternalServerError struct {
+	Payload string
+}
+
+func (o *GetSilenceOccurrencesInternalServerError) Error() string {
+	return fmt.Sprintf("[GET /silence/{silenceID}/occurrences][%d] getSilenceOccurrencesInternalServerError  %+v", 500, o.Payload)
+}
+
+func (o *GetSilenceOccurrencesInternalServerError) GetPayload() string {
+	return o.Payload
+}
+
+func (o *GetSilenceOccurrencesInternalServerError) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+)
+
+// NewGetSilencePresetsParams creates a new GetSilencePresetsParams object
+// with the default values initialized.
+func NewGetSilencePresetsParams() *GetSilencePresetsParams {
+
+	return &GetSilencePresetsParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetSilencePresetsParamsWithTimeout creates a new GetSilencePresetsParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewGetSilencePresetsParamsWithTimeout(timeout time.Duration) *GetSilencePresetsParams {
+
+	return &GetSilencePresetsParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewGetSilencePresetsParamsWithContext creates a new GetSilencePresetsParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewGetSilencePresetsParamsWithContext(ctx context.Context) *GetSilencePresetsParams {
+
+	return &GetSilencePresetsParams{
+
+		Context: ctx,
+	}
+}
+
+// NewGetSilencePresetsParamsWithHTTPClient creates a new GetSilencePresetsParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewGetSilencePresetsParamsWithHTTPClient(client *http.Client) *GetSilencePresetsParams {
+
+	return &GetSilencePresetsParams{
+		HTTPClient: client,
+	}
+}
+
+/*GetSilencePresetsParams contains all the parameters to send to the API endpoint
+for the get silence presets operation typically these are written to a http.Request
+*/
+type GetSilencePresetsParams struct {
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the get silence presets params
+func (o *GetSilencePresetsParams) WithTimeout(timeout time.Duration) *GetSilencePresetsParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get silence presets params
+func (o *GetSilencePresetsParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get silence presets params
+func (o *GetSilencePresetsParams) WithContext(ctx context.Context) *GetSilencePresetsParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get silence presets params
+func (o *GetSilencePresetsParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get silence presets params
+func (o *GetSilencePresetsParams) WithHTTPClient(client *http.Client) *GetSilencePresetsParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get silence presets params
+func (o *GetSilencePresetsParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetSilencePresetsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
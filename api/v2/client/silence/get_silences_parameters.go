@@ -34,8 +34,13 @@ import (
 // NewGetSilencesParams creates a new GetSilencesParams object
 // with the default values initialized.
 func NewGetSilencesParams() *GetSilencesParams {
-	var ()
+	var (
+		limitDefault  = int64(0)
+		offsetDefault = int64(0)
+	)
 	return &GetSilencesParams{
+		Limit:  &limitDefault,
+		Offset: &offsetDefault,
 
 		timeout: cr.DefaultTimeout,
 	}
@@ -44,8 +49,13 @@ func NewGetSilencesParams() *GetSilencesParams {
 // NewGetSilencesParamsWithTimeout creates a new GetSilencesParams object
 // with the default values initialized, and the ability to set a timeout on a request
 func NewGetSilencesParamsWithTimeout(timeout time.Duration) *GetSilencesParams {
-	var ()
+	var (
+		limitDefault  = int64(0)
+		offsetDefault = int64(0)
+	)
 	return &GetSilencesParams{
+		Limit:  &limitDefault,
+		Offset: &offsetDefault,
 
 		timeout: timeout,
 	}
@@ -54,8 +64,13 @@ func NewGetSilencesParamsWithTimeout(timeout time.Duration) *GetSilencesParams {
 // NewGetSilencesParamsWithContext creates a new GetSilencesParams object
 // with the default values initialized, and the ability to set a context for a request
 func NewGetSilencesParamsWithContext(ctx context.Context) *GetSilencesParams {
-	var ()
+	var (
+		limitDefault  = int64(0)
+		offsetDefault = int64(0)
+	)
 	return &GetSilencesParams{
+		Limit:  &limitDefault,
+		Offset: &offsetDefault,
 
 		Context: ctx,
 	}
@@ -64,8 +79,13 @@ func NewGetSilencesParamsWithContext(ctx context.Context) *GetSilencesParams {
 // NewGetSilencesParamsWithHTTPClient creates a new GetSilencesParams object
 // with the default values initialized, and the ability to set a custom HTTPClient for a request
 func NewGetSilencesParamsWithHTTPClient(client *http.Client) *GetSilencesParams {
-	var ()
+	var (
+		limitDefault  = int64(0)
+		offsetDefault = int64(0)
+	)
 	return &GetSilencesParams{
+		Limit:      &limitDefault,
+		Offset:     &offsetDefault,
 		HTTPClient: client,
 	}
 }
@@ -75,11 +95,41 @@ for the get silences operation typically these are written to a http.Request
 */
 type GetSilencesParams struct {
 
+	/*CreatedBy
+	  Show only silences created by the given author
+
+	*/
+	CreatedBy *string
 	/*Filter
 	  A list of matchers to filter silences by
 
 	*/
 	Filter []string
+	/*Limit
+	  Maximum number of silences to return. 0 returns all of them.
+
+	*/
+	Limit *int64
+	/*Offset
+	  Number of silences to skip
+
+	*/
+	Offset *int64
+	/*Since
+	  Show only silences ending at or after the given time
+
+	*/
+	Since *strfmt.DateTime
+	/*State
+	  A list of states to filter silences by
+
+	*/
+	State []string
+	/*Until
+	  Show only silences starting at or before the given time
+
+	*/
+	Until *strfmt.DateTime
 
 	timeout    time.Duration
 	Context    context.Context
@@ -119,6 +169,17 @@ func (o *GetSilencesParams) SetHTTPClient(client *http.Client) {
 	o.HTTPClient = client
 }
 
+// WithCreatedBy adds the createdBy to the get silences params
+func (o *GetSilencesParams) WithCreatedBy(createdBy *string) *GetSilencesParams {
+	o.SetCreatedBy(createdBy)
+	return o
+}
+
+// SetCreatedBy adds the createdBy to the get silences params
+func (o *GetSilencesParams) SetCreatedBy(createdBy *string) {
+	o.CreatedBy = createdBy
+}
+
 // WithFilter adds the filter to the get silences params
 func (o *GetSilencesParams) WithFilter(filter []string) *GetSilencesParams {
 	o.SetFilter(filter)
@@ -130,6 +191,61 @@ func (o *GetSilencesParams) SetFilter(filter []string) {
 	o.Filter = filter
 }
 
+// WithLimit adds the limit to the get silences params
+func (o *GetSilencesParams) WithLimit(limit *int64) *GetSilencesParams {
+	o.SetLimit(limit)
+	return o
+}
+
+// SetLimit adds the limit to the get silences params
+func (o *GetSilencesParams) SetLimit(limit *int64) {
+	o.Limit = limit
+}
+
+// WithOffset adds the offset to the get silences params
+func (o *GetSilencesParams) WithOffset(offset *int64) *GetSilencesParams {
+	o.SetOffset(offset)
+	return o
+}
+
+// SetOffset adds the offset to the get silences params
+func (o *GetSilencesParams) SetOffset(offset *int64) {
+	o.Offset = offset
+}
+
+// WithSince adds the since to the get silences params
+func (o *GetSilencesParams) WithSince(since *strfmt.DateTime) *GetSilencesParams {
+	o.SetSince(since)
+	return o
+}
+
+// SetSince adds the since to the get silences params
+func (o *GetSilencesParams) SetSince(since *strfmt.DateTime) {
+	o.Since = since
+}
+
+// WithState adds the state to the get silences params
+func (o *GetSilencesParams) WithState(state []string) *GetSilencesParams {
+	o.SetState(state)
+	return o
+}
+
+// SetState adds the state to the get silences params
+func (o *GetSilencesParams) SetState(state []string) {
+	o.State = state
+}
+
+// WithUntil adds the until to the get silences params
+func (o *GetSilencesParams) WithUntil(until *strfmt.DateTime) *GetSilencesParams {
+	o.SetUntil(until)
+	return o
+}
+
+// SetUntil adds the until to the get silences params
+func (o *GetSilencesParams) SetUntil(until *strfmt.DateTime) {
+	o.Until = until
+}
+
 // WriteToRequest writes these params to a swagger request
 func (o *GetSilencesParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
 
@@ -138,6 +254,22 @@ func (o *GetSilencesParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.R
 	}
 	var res []error
 
+	if o.CreatedBy != nil {
+
+		// query param createdBy
+		var qrCreatedBy string
+		if o.CreatedBy != nil {
+			qrCreatedBy = *o.CreatedBy
+		}
+		qCreatedBy := qrCreatedBy
+		if qCreatedBy != "" {
+			if err := r.SetQueryParam("createdBy", qCreatedBy); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	valuesFilter := o.Filter
 
 	joinedFilter := swag.JoinByFormat(valuesFilter, "multi")
@@ -146,6 +278,78 @@ func (o *GetSilencesParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.R
 		return err
 	}
 
+	if o.Limit != nil {
+
+		// query param limit
+		var qrLimit int64
+		if o.Limit != nil {
+			qrLimit = *o.Limit
+		}
+		qLimit := swag.FormatInt64(qrLimit)
+		if qLimit != "" {
+			if err := r.SetQueryParam("limit", qLimit); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Offset != nil {
+
+		// query param offset
+		var qrOffset int64
+		if o.Offset != nil {
+			qrOffset = *o.Offset
+		}
+		qOffset := swag.FormatInt64(qrOffset)
+		if qOffset != "" {
+			if err := r.SetQueryParam("offset", qOffset); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Since != nil {
+
+		// query param since
+		var qrSince strfmt.DateTime
+		if o.Since != nil {
+			qrSince = *o.Since
+		}
+		qSince := qrSince.String()
+		if qSince != "" {
+			if err := r.SetQueryParam("since", qSince); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	valuesState := o.State
+
+	joinedState := swag.JoinByFormat(valuesState, "multi")
+	// query array param state
+	if err := r.SetQueryParam("state", joinedState...); err != nil {
+		return err
+	}
+
+	if o.Until != nil {
+
+		// query param until
+		var qrUntil strfmt.DateTime
+		if o.Until != nil {
+			qrUntil = *o.Until
+		}
+		qUntil := qrUntil.String()
+		if qUntil != "" {
+			if err := r.SetQueryParam("until", qUntil); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 
 	"github.com/prometheus/alertmanager/api/v2/models"
 )
@@ -43,6 +45,12 @@ func (o *GetSilencesReader) ReadResponse(response runtime.ClientResponse, consum
 			return nil, err
 		}
 		return result, nil
+	case 400:
+		result := NewGetSilencesBadRequest()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
 	case 500:
 		result := NewGetSilencesInternalServerError()
 		if err := result.readResponse(response, consumer, o.formats); err != nil {
@@ -65,6 +73,10 @@ func NewGetSilencesOK() *GetSilencesOK {
 Get silences response
 */
 type GetSilencesOK struct {
+	/*The number of silences matching the filters, regardless of limit and offset
+	 */
+	XTotalCount int64
+
 	Payload models.GettableSilences
 }
 
@@ -78,6 +90,44 @@ func (o *GetSilencesOK) GetPayload() models.GettableSilences {
 
 func (o *GetSilencesOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
 
+	// response header X-Total-Count
+	xTotalCount, err := swag.ConvertInt64(response.GetHeader("X-Total-Count"))
+	if err != nil {
+		return errors.InvalidType("X-Total-Count", "header", "int64", response.GetHeader("X-Total-Count"))
+	}
+	o.XTotalCount = xTotalCount
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetSilencesBadRequest creates a GetSilencesBadRequest with default headers values
+func NewGetSilencesBadRequest() *GetSilencesBadRequest {
+	return &GetSilencesBadRequest{}
+}
+
+/*GetSilencesBadRequest handles this case with default header values.
+
+Bad request
+*/
+type GetSilencesBadRequest struct {
+	Payload string
+}
+
+func (o *GetSilencesBadRequest) Error() string {
+	return fmt.Sprintf("[GET /silences][%d] getSilencesBadRequest  %+v", 400, o.Payload)
+}
+
+func (o *GetSilencesBadRequest) GetPayload() string {
+	return o.Payload
+}
+
+func (o *GetSilencesBadRequest) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
 	// response payload
 	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
 		return err
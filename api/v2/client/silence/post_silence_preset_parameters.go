@@ -0,0 +1,173 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// NewPostSilencePresetParams creates a new PostSilencePresetParams object
+// with the default values initialized.
+func NewPostSilencePresetParams() *PostSilencePresetParams {
+	var ()
+	return &PostSilencePresetParams{
+
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewPostSilencePresetParamsWithTimeout creates a new PostSilencePresetParams object
+// with the default values initialized, and the ability to set a timeout on a request
+func NewPostSilencePresetParamsWithTimeout(timeout time.Duration) *PostSilencePresetParams {
+	var ()
+	return &PostSilencePresetParams{
+
+		timeout: timeout,
+	}
+}
+
+// NewPostSilencePresetParamsWithContext creates a new PostSilencePresetParams object
+// with the default values initialized, and the ability to set a context for a request
+func NewPostSilencePresetParamsWithContext(ctx context.Context) *PostSilencePresetParams {
+	var ()
+	return &PostSilencePresetParams{
+
+		Context: ctx,
+	}
+}
+
+// NewPostSilencePresetParamsWithHTTPClient creates a new PostSilencePresetParams object
+// with the default values initialized, and the ability to set a custom HTTPClient for a request
+func NewPostSilencePresetParamsWithHTTPClient(client *http.Client) *PostSilencePresetParams {
+	var ()
+	return &PostSilencePresetParams{
+		HTTPClient: client,
+	}
+}
+
+/*PostSilencePresetParams contains all the parameters to send to the API endpoint
+for the post silence preset operation typically these are written to a http.Request
+*/
+type PostSilencePresetParams struct {
+
+	/*Instantiation
+	  The parameters of the silence
+
+	*/
+	Instantiation *models.SilencePresetInstantiation
+	/*PresetName
+	  Name of the silence preset
+
+	*/
+	PresetName string
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithTimeout adds the timeout to the post silence preset params
+func (o *PostSilencePresetParams) WithTimeout(timeout time.Duration) *PostSilencePresetParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the post silence preset params
+func (o *PostSilencePresetParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the post silence preset params
+func (o *PostSilencePresetParams) WithContext(ctx context.Context) *PostSilencePresetParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the post silence preset params
+func (o *PostSilencePresetParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the post silence preset params
+func (o *PostSilencePresetParams) WithHTTPClient(client *http.Client) *PostSilencePresetParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the post silence preset params
+func (o *PostSilencePresetParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithInstantiation adds the instantiation to the post silence preset params
+func (o *PostSilencePresetParams) WithInstantiation(instantiation *models.SilencePresetInstantiation) *PostSilencePresetParams {
+	o.SetInstantiation(instantiation)
+	return o
+}
+
+// SetInstantiation adds the instantiation to the post silence preset params
+func (o *PostSilencePresetParams) SetInstantiation(instantiation *models.SilencePresetInstantiation) {
+	o.Instantiation = instantiation
+}
+
+// WithPresetName adds the presetName to the post silence preset params
+func (o *PostSilencePresetParams) WithPresetName(presetName string) *PostSilencePresetParams {
+	o.SetPresetName(presetName)
+	return o
+}
+
+// SetPresetName adds the presetName to the post silence preset params
+func (o *PostSilencePresetParams) SetPresetName(presetName string) {
+	o.PresetName = presetName
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *PostSilencePresetParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	if o.Instantiation != nil {
+		if err := r.SetBodyParam(o.Instantiation); err != nil {
+			return err
+		}
+	}
+
+	// path param presetName
+	if err := r.SetPathParam("presetName", o.PresetName); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// PostSilencePresetReader is a Reader for the PostSilencePreset structure.
+type PostSilencePresetReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *PostSilencePresetReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewPostSilencePresetOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 400:
+		result := NewPostSilencePresetBadRequest()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 404:
+		result := NewPostSilencePresetNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewPostSilencePresetOK creates a PostSilencePresetOK with default headers values
+func NewPostSilencePresetOK() *PostSilencePresetOK {
+	return &PostSilencePresetOK{}
+}
+
+/*PostSilencePresetOK handles this case with default header values.
+
+Create silence response
+*/
+type PostSilencePresetOK struct {
+	Payload *PostSilencePresetOKBody
+}
+
+func (o *PostSilencePresetOK) Error() string {
+	return fmt.Sprintf("[POST /silences/presets/{presetName}][%d] postSilencePresetOK  %+v", 200, o.Payload)
+}
+
+func (o *PostSilencePresetOK) GetPayload() *PostSilencePresetOKBody {
+	return o.Payload
+}
+
+func (o *PostSilencePresetOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(PostSilencePresetOKBody)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewPostSilencePresetBadRequest creates a PostSilencePresetBadRequest with default headers values
+func NewPostSilencePresetBadRequest() *PostSilencePresetBadRequest {
+	return &PostSilencePresetBadRequest{}
+}
+
+/*PostSilencePresetBadRequest handles this case with default header values.
+
+Bad request
+*/
+type PostSilencePresetBadRequest struct {
+	Payload string
+}
+
+func (o *PostSilencePresetBadRequest) Error() string {
+	return fmt.Sprintf("[POST /silences/presets/{presetName}][%d] postSilencePresetBadRequest  %+v", 400, o.Payload)
+}
+
+func (o *PostSilencePresetBadRequest) GetPayload() string {
+	return o.Payload
+}
+
+func (o *PostSilencePresetBadRequest) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewPostSilencePresetNotFound creates a PostSilencePresetNotFound with default headers values
+func NewPostSilencePresetNotFound() *PostSilencePresetNotFound {
+	return &PostSilencePresetNotFound{}
+}
+
+/*PostSilencePresetNotFound handles this case with default header values.
+
+A silence preset with the specified name was not found
+*/
+type PostSilencePresetNotFound struct {
+}
+
+func (o *PostSilencePresetNotFound) Error() string {
+	return fmt.Sprintf("[POST /silences/presets/{presetName}][%d] postSilencePresetNotFound ", 404)
+}
+
+func (o *PostSilencePresetNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+/*PostSilencePresetOKBody post silence preset o k body
+swagger:model PostSilencePresetOKBody
+*/
+type PostSilencePresetOKBody struct {
+
+	// silence ID
+	SilenceID string `json:"silenceID,omitempty"`
+}
+
+// Validate validates this post silence preset o k body
+func (o *PostSilencePresetOKBody) Validate(formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (o *PostSilencePresetOKBody) MarshalBinary() ([]byte, error) {
+	if o == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(o)
+}
+
+// UnmarshalBinary interface implementation
+func (o *PostSilencePresetOKBody) UnmarshalBinary(b []byte) error {
+	var res PostSilencePresetOKBody
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*o = res
+	return nil
+}
@@ -0,0 +1,157 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// RecreateSilencesReader is a Reader for the RecreateSilences structure.
+type RecreateSilencesReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *RecreateSilencesReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewRecreateSilencesOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 400:
+		result := NewRecreateSilencesBadRequest()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 500:
+		result := NewRecreateSilencesInternalServerError()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewRecreateSilencesOK creates a RecreateSilencesOK with default headers values
+func NewRecreateSilencesOK() *RecreateSilencesOK {
+	return &RecreateSilencesOK{}
+}
+
+/*RecreateSilencesOK handles this case with default header values.
+
+Re-create silences response
+*/
+type RecreateSilencesOK struct {
+	Payload *models.BulkSilenceResult
+}
+
+func (o *RecreateSilencesOK) Error() string {
+	return fmt.Sprintf("[POST /silences/recreate][%d] recreateSilencesOK  %+v", 200, o.Payload)
+}
+
+func (o *RecreateSilencesOK) GetPayload() *models.BulkSilenceResult {
+	return o.Payload
+}
+
+func (o *RecreateSilencesOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.BulkSilenceResult)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewRecreateSilencesBadRequest creates a RecreateSilencesBadRequest with default headers values
+func NewRecreateSilencesBadRequest() *RecreateSilencesBadRequest {
+	return &RecreateSilencesBadRequest{}
+}
+
+/*RecreateSilencesBadRequest handles this case with default header values.
+
+Bad request
+*/
+type RecreateSilencesBadRequest struct {
+	Payload string
+}
+
+func (o *RecreateSilencesBadRequest) Error() string {
+	return fmt.Sprintf("[POST /silences/recreate][%d] recreateSilencesBadRequest  %+v", 400, o.Payload)
+}
+
+func (o *RecreateSilencesBadRequest) GetPayload() string {
+	return o.Payload
+}
+
+func (o *RecreateSilencesBadRequest) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewRecreateSilencesInternalServerError creates a RecreateSilencesInternalServerError with default headers values
+func NewRecreateSilencesInternalServerError() *RecreateSilencesInternalServerError {
+	return &RecreateSilencesInternalServerError{}
+}
+
+/*RecreateSilencesInternalServerError handles this case with default header values.
+
+Internal server error
+*/
+type RecreateSilencesInternalServerError struct {
+	Payload string
+}
+
+func (o *RecreateSilencesInternalServerError) Error() string {
+	return fmt.Sprintf("[POST /silences/recreate][%d] recreateSilencesInternalServerError  %+v", 500, o.Payload)
+}
+
+func (o *RecreateSilencesInternalServerError) GetPayload() string {
+	return o.Payload
+}
+
+func (o *RecreateSilencesInternalServerError) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
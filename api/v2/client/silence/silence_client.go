@@ -43,12 +43,24 @@ type Client struct {
 type ClientService interface {
 	DeleteSilence(params *DeleteSilenceParams) (*DeleteSilenceOK, error)
 
+	ExpireSilences(params *ExpireSilencesParams) (*ExpireSilencesOK, error)
+
 	GetSilence(params *GetSilenceParams) (*GetSilenceOK, error)
 
+	GetSilenceEvents(params *GetSilenceEventsParams) (*GetSilenceEventsOK, error)
+
+	GetSilenceOccurrences(params *GetSilenceOccurrencesParams) (*GetSilenceOccurrencesOK, error)
+
+	GetSilencePresets(params *GetSilencePresetsParams) (*GetSilencePresetsOK, error)
+
 	GetSilences(params *GetSilencesParams) (*GetSilencesOK, error)
 
+	PostSilencePreset(params *PostSilencePresetParams) (*PostSilencePresetOK, error)
+
 	PostSilences(params *PostSilencesParams) (*PostSilencesOK, error)
 
+	RecreateSilences(params *RecreateSilencesParams) (*RecreateSilencesOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
@@ -86,6 +98,40 @@ func (a *Client) DeleteSilence(params *DeleteSilenceParams) (*DeleteSilenceOK, e
 	panic(msg)
 }
 
+/*
+  ExpireSilences Expire all active and pending silences matching a filter
+*/
+func (a *Client) ExpireSilences(params *ExpireSilencesParams) (*ExpireSilencesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewExpireSilencesParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "expireSilences",
+		Method:             "POST",
+		PathPattern:        "/silences/expire",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &ExpireSilencesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*ExpireSilencesOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for expireSilences: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 /*
   GetSilence Get a silence by its ID
 */
@@ -120,6 +166,108 @@ func (a *Client) GetSilence(params *GetSilenceParams) (*GetSilenceOK, error) {
 	panic(msg)
 }
 
+/*
+  GetSilenceEvents Get the creations, modifications and expiries of silences, oldest first
+*/
+func (a *Client) GetSilenceEvents(params *GetSilenceEventsParams) (*GetSilenceEventsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetSilenceEventsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getSilenceEvents",
+		Method:             "GET",
+		PathPattern:        "/silences/events",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetSilenceEventsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetSilenceEventsOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for getSilenceEvents: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  GetSilenceOccurrences Get the current and upcoming occurrences of a silence
+*/
+func (a *Client) GetSilenceOccurrences(params *GetSilenceOccurrencesParams) (*GetSilenceOccurrencesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetSilenceOccurrencesParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getSilenceOccurrences",
+		Method:             "GET",
+		PathPattern:        "/silence/{silenceID}/occurrences",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetSilenceOccurrencesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetSilenceOccurrencesOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for getSilenceOccurrences: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  GetSilencePresets Get the silence presets of the configuration
+*/
+func (a *Client) GetSilencePresets(params *GetSilencePresetsParams) (*GetSilencePresetsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetSilencePresetsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getSilencePresets",
+		Method:             "GET",
+		PathPattern:        "/silences/presets",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetSilencePresetsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetSilencePresetsOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for getSilencePresets: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 /*
   GetSilences Get a list of silences
 */
@@ -154,6 +302,40 @@ func (a *Client) GetSilences(params *GetSilencesParams) (*GetSilencesOK, error)
 	panic(msg)
 }
 
+/*
+  PostSilencePreset Create a silence from a silence preset
+*/
+func (a *Client) PostSilencePreset(params *PostSilencePresetParams) (*PostSilencePresetOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPostSilencePresetParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "postSilencePreset",
+		Method:             "POST",
+		PathPattern:        "/silences/presets/{presetName}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PostSilencePresetReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*PostSilencePresetOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for postSilencePreset: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 /*
   PostSilences Post a new silence or update an existing one
 */
@@ -188,6 +370,40 @@ func (a *Client) PostSilences(params *PostSilencesParams) (*PostSilencesOK, erro
 	panic(msg)
 }
 
+/*
+  RecreateSilences Re-create all expired silences matching a filter, starting now
+*/
+func (a *Client) RecreateSilences(params *RecreateSilencesParams) (*RecreateSilencesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewRecreateSilencesParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "recreateSilences",
+		Method:             "POST",
+		PathPattern:        "/silences/recreate",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &RecreateSilencesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*RecreateSilencesOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for recreateSilences: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -18,7 +18,12 @@ import (
 	"time"
 
 	"github.com/go-openapi/strfmt"
+	"github.com/prometheus/alertmanager/ack"
 	open_api_models "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/deadletter"
+	"github.com/prometheus/alertmanager/history"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/silence/audit"
 	"github.com/prometheus/alertmanager/silence/silencepb"
 	"github.com/prometheus/alertmanager/types"
 	prometheus_model "github.com/prometheus/common/model"
@@ -43,6 +48,24 @@ func GettableSilenceFromProto(s *silencepb.Silence) (open_api_models.GettableSil
 			State: &state,
 		},
 	}
+	if s.NotifyReceiver != "" {
+		before := prometheus_model.Duration(s.NotifyBefore).String()
+		sil.ExpiryNotification = &open_api_models.SilenceExpiryNotification{
+			Receiver: &s.NotifyReceiver,
+			Before:   &before,
+		}
+	}
+	if sched := s.Schedule; sched != nil {
+		duration := prometheus_model.Duration(sched.Duration).String()
+		sil.Schedule = &open_api_models.SilenceSchedule{
+			Cron:     &sched.Cron,
+			Duration: &duration,
+		}
+		if !sched.Until.IsZero() {
+			until := strfmt.DateTime(sched.Until)
+			sil.Schedule.Until = &until
+		}
+	}
 
 	for _, m := range s.Matchers {
 		matcher := &open_api_models.Matcher{
@@ -86,6 +109,27 @@ func PostableSilenceToProto(s *open_api_models.PostableSilence) (*silencepb.Sile
 		Comment:   *s.Comment,
 		CreatedBy: *s.CreatedBy,
 	}
+	if n := s.ExpiryNotification; n != nil {
+		before, err := prometheus_model.ParseDuration(*n.Before)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry notification lead time: %v", err)
+		}
+		sil.NotifyReceiver = *n.Receiver
+		sil.NotifyBefore = time.Duration(before)
+	}
+	if sched := s.Schedule; sched != nil {
+		duration, err := prometheus_model.ParseDuration(*sched.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule duration: %v", err)
+		}
+		sil.Schedule = &silencepb.Schedule{
+			Cron:     *sched.Cron,
+			Duration: time.Duration(duration),
+		}
+		if sched.Until != nil {
+			sil.Schedule.Until = time.Time(*sched.Until)
+		}
+	}
 	for _, m := range s.Matchers {
 		matcher := &silencepb.Matcher{
 			Name:    *m.Name,
@@ -146,6 +190,14 @@ func AlertToOpenAPIAlert(alert *types.Alert, status types.AlertStatus, receivers
 		},
 	}
 
+	if alert.Source != (types.Source{}) {
+		aa.Source = &open_api_models.AlertSource{
+			Name:     alert.Source.Name,
+			Address:  alert.Source.Address,
+			Identity: alert.Source.Identity,
+		}
+	}
+
 	if aa.Status.SilencedBy == nil {
 		aa.Status.SilencedBy = []string{}
 	}
@@ -169,6 +221,7 @@ func OpenAPIAlertsToAlerts(apiAlerts open_api_models.PostableAlerts) []*types.Al
 				EndsAt:       time.Time(apiAlert.EndsAt),
 				GeneratorURL: string(apiAlert.GeneratorURL),
 			},
+			Source: types.Source{Name: apiAlert.Source},
 		}
 		alerts = append(alerts, &alert)
 	}
@@ -176,6 +229,111 @@ func OpenAPIAlertsToAlerts(apiAlerts open_api_models.PostableAlerts) []*types.Al
 	return alerts
 }
 
+// DeadLetterToOpenAPIDeadLetter converts *deadletter.Entry to *open_api_models.DeadLetter.
+func DeadLetterToOpenAPIDeadLetter(e *deadletter.Entry) *open_api_models.DeadLetter {
+	createdAt := strfmt.DateTime(e.CreatedAt)
+	replays := int64(e.Replays)
+
+	alerts := make([]*open_api_models.PostableAlert, 0, len(e.Alerts))
+	for _, a := range e.Alerts {
+		alerts = append(alerts, &open_api_models.PostableAlert{
+			Alert: open_api_models.Alert{
+				GeneratorURL: strfmt.URI(a.GeneratorURL),
+				Labels:       ModelLabelSetToAPILabelSet(a.Labels),
+			},
+			Annotations: ModelLabelSetToAPILabelSet(a.Annotations),
+			StartsAt:    strfmt.DateTime(a.StartsAt),
+			EndsAt:      strfmt.DateTime(a.EndsAt),
+		})
+	}
+
+	dl := &open_api_models.DeadLetter{
+		ID:          &e.ID,
+		Receiver:    &e.Receiver,
+		Integration: &e.Integration,
+		GroupKey:    &e.GroupKey,
+		GroupLabels: ModelLabelSetToAPILabelSet(e.GroupLabels),
+		Alerts:      alerts,
+		Error:       &e.Error,
+		CreatedAt:   &createdAt,
+		Replays:     &replays,
+	}
+	if !e.LastReplayAt.IsZero() {
+		dl.LastReplayAt = strfmt.DateTime(e.LastReplayAt)
+	}
+	return dl
+}
+
+// NotificationHistoryEntryToOpenAPI converts *history.Entry to
+// *open_api_models.NotificationHistoryEntry.
+func NotificationHistoryEntryToOpenAPI(e *history.Entry) *open_api_models.NotificationHistoryEntry {
+	ts := strfmt.DateTime(e.Time)
+	idx, attempt := int64(e.Idx), int64(e.Attempt)
+
+	fingerprints := make([]string, 0, len(e.Fingerprints))
+	for _, fp := range e.Fingerprints {
+		fingerprints = append(fingerprints, fp.String())
+	}
+	return &open_api_models.NotificationHistoryEntry{
+		Time:         &ts,
+		Receiver:     &e.Receiver,
+		Integration:  &e.Integration,
+		Index:        &idx,
+		GroupKey:     &e.GroupKey,
+		GroupLabels:  ModelLabelSetToAPILabelSet(e.GroupLabels),
+		Fingerprints: fingerprints,
+		Attempt:      &attempt,
+		Outcome:      &e.Outcome,
+		StatusCode:   int64(e.StatusCode),
+		Error:        e.Error,
+	}
+}
+
+// SilenceEventToOpenAPISilenceEvent converts audit.Event to
+// open_api_models.SilenceEvent.
+func SilenceEventToOpenAPISilenceEvent(e *audit.Event) *open_api_models.SilenceEvent {
+	ts := strfmt.DateTime(e.Time)
+	typ := string(e.Type)
+
+	changes := make([]*open_api_models.SilenceChange, 0, len(e.Changes))
+	for i := range e.Changes {
+		c := e.Changes[i]
+		changes = append(changes, &open_api_models.SilenceChange{
+			Field: &c.Field,
+			Old:   c.Old,
+			New:   c.New,
+		})
+	}
+
+	return &open_api_models.SilenceEvent{
+		ID:        &e.ID,
+		SilenceID: &e.SilenceID,
+		Type:      &typ,
+		User:      e.User,
+		Time:      &ts,
+		Changes:   changes,
+	}
+}
+
+// AckToOpenAPIAck converts ack.Ack to open_api_models.Ack.
+func AckToOpenAPIAck(a *ack.Ack) *open_api_models.Ack {
+	startsAt := strfmt.DateTime(a.StartsAt)
+	updatedAt := strfmt.DateTime(a.UpdatedAt)
+	endsAt := strfmt.DateTime(a.EndsAt)
+
+	return &open_api_models.Ack{
+		ID:        &a.ID,
+		StartsAt:  &startsAt,
+		UpdatedAt: &updatedAt,
+		PostableAck: open_api_models.PostableAck{
+			GroupKey: &a.GroupKey,
+			AckedBy:  &a.AckedBy,
+			Comment:  a.Comment,
+			EndsAt:   &endsAt,
+		},
+	}
+}
+
 // ModelLabelSetToAPILabelSet converts prometheus_model.LabelSet to open_api_models.LabelSet.
 func ModelLabelSetToAPILabelSet(modelLabelSet prometheus_model.LabelSet) open_api_models.LabelSet {
 	apiLabelSet := open_api_models.LabelSet{}
@@ -195,3 +353,43 @@ func APILabelSetToModelLabelSet(apiLabelSet open_api_models.LabelSet) prometheus
 
 	return modelLabelSet
 }
+
+// IntegrationHealthToOpenAPI converts the health of the integration with the
+// given type and index to open_api_models.IntegrationHealth.
+func IntegrationHealthToOpenAPI(name string, index int64, h notify.IntegrationHealth) *open_api_models.IntegrationHealth {
+	state := h.State()
+	failures := int64(h.ConsecutiveFailures)
+	ih := &open_api_models.IntegrationHealth{
+		Name:                &name,
+		Index:               &index,
+		State:               &state,
+		LastError:           h.LastError,
+		ConsecutiveFailures: &failures,
+	}
+	if !h.LastSuccess.IsZero() {
+		ih.LastSuccess = strfmt.DateTime(h.LastSuccess)
+	}
+	if !h.LastFailure.IsZero() {
+		ih.LastFailure = strfmt.DateTime(h.LastFailure)
+	}
+	if !h.LastThrottled.IsZero() {
+		ih.LastThrottled = strfmt.DateTime(h.LastThrottled)
+	}
+	return ih
+}
+
+// BudgetStatusToOpenAPI converts the consumption of a notification budget to
+// its OpenAPI model.
+func BudgetStatusToOpenAPI(s notify.BudgetStatus) *open_api_models.Budget {
+	limit, used, queued := int64(s.Limit), int64(s.Used), int64(s.Queued)
+	resetsAt := strfmt.DateTime(s.ResetsAt)
+	return &open_api_models.Budget{
+		Scope:    &s.Scope,
+		Name:     &s.Name,
+		Window:   &s.Window,
+		Limit:    &limit,
+		Used:     &used,
+		ResetsAt: &resetsAt,
+		Queued:   &queued,
+	}
+}
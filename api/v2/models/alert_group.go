@@ -33,14 +33,27 @@ import (
 // swagger:model alertGroup
 type AlertGroup struct {
 
+	// acknowledgement
+	Acknowledgement *Ack `json:"acknowledgement,omitempty"`
+
 	// alerts
 	// Required: true
 	Alerts []*GettableAlert `json:"alerts"`
 
+	// The SHA-256 hash of the group key
+	GroupID string `json:"groupId,omitempty"`
+
+	// The key identifying the group, also sent in its notifications
+	GroupKey string `json:"groupKey,omitempty"`
+
 	// labels
 	// Required: true
 	Labels LabelSet `json:"labels"`
 
+	// The time at which the group is next flushed to its receiver
+	// Format: date-time
+	NextFlush strfmt.DateTime `json:"nextFlush,omitempty"`
+
 	// receiver
 	// Required: true
 	Receiver *Receiver `json:"receiver"`
@@ -50,6 +63,10 @@ type AlertGroup struct {
 func (m *AlertGroup) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateAcknowledgement(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateAlerts(formats); err != nil {
 		res = append(res, err)
 	}
@@ -58,6 +75,10 @@ func (m *AlertGroup) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateNextFlush(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateReceiver(formats); err != nil {
 		res = append(res, err)
 	}
@@ -68,6 +89,24 @@ func (m *AlertGroup) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *AlertGroup) validateAcknowledgement(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Acknowledgement) { // not required
+		return nil
+	}
+
+	if m.Acknowledgement != nil {
+		if err := m.Acknowledgement.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("acknowledgement")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *AlertGroup) validateAlerts(formats strfmt.Registry) error {
 
 	if err := validate.Required("alerts", "body", m.Alerts); err != nil {
@@ -105,6 +144,19 @@ func (m *AlertGroup) validateLabels(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *AlertGroup) validateNextFlush(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.NextFlush) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("nextFlush", "body", "date-time", m.NextFlush.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *AlertGroup) validateReceiver(formats strfmt.Registry) error {
 
 	if err := validate.Required("receiver", "body", m.Receiver); err != nil {
@@ -50,6 +50,9 @@ type GettableAlert struct {
 	// Required: true
 	Receivers []*Receiver `json:"receivers"`
 
+	// source
+	Source *AlertSource `json:"source,omitempty"`
+
 	// starts at
 	// Required: true
 	// Format: date-time
@@ -79,6 +82,8 @@ func (m *GettableAlert) UnmarshalJSON(raw []byte) error {
 
 		Receivers []*Receiver `json:"receivers"`
 
+		Source *AlertSource `json:"source,omitempty"`
+
 		StartsAt *strfmt.DateTime `json:"startsAt"`
 
 		Status *AlertStatus `json:"status"`
@@ -97,6 +102,8 @@ func (m *GettableAlert) UnmarshalJSON(raw []byte) error {
 
 	m.Receivers = dataAO0.Receivers
 
+	m.Source = dataAO0.Source
+
 	m.StartsAt = dataAO0.StartsAt
 
 	m.Status = dataAO0.Status
@@ -126,6 +133,8 @@ func (m GettableAlert) MarshalJSON() ([]byte, error) {
 
 		Receivers []*Receiver `json:"receivers"`
 
+		Source *AlertSource `json:"source,omitempty"`
+
 		StartsAt *strfmt.DateTime `json:"startsAt"`
 
 		Status *AlertStatus `json:"status"`
@@ -141,6 +150,8 @@ func (m GettableAlert) MarshalJSON() ([]byte, error) {
 
 	dataAO0.Receivers = m.Receivers
 
+	dataAO0.Source = m.Source
+
 	dataAO0.StartsAt = m.StartsAt
 
 	dataAO0.Status = m.Status
@@ -181,6 +192,10 @@ func (m *GettableAlert) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateSource(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateStartsAt(formats); err != nil {
 		res = append(res, err)
 	}
@@ -263,6 +278,24 @@ func (m *GettableAlert) validateReceivers(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *GettableAlert) validateSource(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Source) { // not required
+		return nil
+	}
+
+	if m.Source != nil {
+		if err := m.Source.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("source")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *GettableAlert) validateStartsAt(formats strfmt.Registry) error {
 
 	if err := validate.Required("startsAt", "body", m.StartsAt); err != nil {
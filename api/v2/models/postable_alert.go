@@ -38,6 +38,9 @@ type PostableAlert struct {
 	// Format: date-time
 	EndsAt strfmt.DateTime `json:"endsAt,omitempty"`
 
+	// The name of the source of the alert, which routes can match with the __source__ pseudo-label
+	Source string `json:"source,omitempty"`
+
 	// starts at
 	// Format: date-time
 	StartsAt strfmt.DateTime `json:"startsAt,omitempty"`
@@ -53,6 +56,8 @@ func (m *PostableAlert) UnmarshalJSON(raw []byte) error {
 
 		EndsAt strfmt.DateTime `json:"endsAt,omitempty"`
 
+		Source string `json:"source,omitempty"`
+
 		StartsAt strfmt.DateTime `json:"startsAt,omitempty"`
 	}
 	if err := swag.ReadJSON(raw, &dataAO0); err != nil {
@@ -63,6 +68,8 @@ func (m *PostableAlert) UnmarshalJSON(raw []byte) error {
 
 	m.EndsAt = dataAO0.EndsAt
 
+	m.Source = dataAO0.Source
+
 	m.StartsAt = dataAO0.StartsAt
 
 	// AO1
@@ -84,6 +91,8 @@ func (m PostableAlert) MarshalJSON() ([]byte, error) {
 
 		EndsAt strfmt.DateTime `json:"endsAt,omitempty"`
 
+		Source string `json:"source,omitempty"`
+
 		StartsAt strfmt.DateTime `json:"startsAt,omitempty"`
 	}
 
@@ -91,6 +100,8 @@ func (m PostableAlert) MarshalJSON() ([]byte, error) {
 
 	dataAO0.EndsAt = m.EndsAt
 
+	dataAO0.Source = m.Source
+
 	dataAO0.StartsAt = m.StartsAt
 
 	jsonDataAO0, errAO0 := swag.WriteJSON(dataAO0)
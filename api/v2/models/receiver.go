@@ -20,6 +20,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"strconv"
+
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
@@ -31,6 +33,9 @@ import (
 // swagger:model receiver
 type Receiver struct {
 
+	// The integrations of the receiver, only set by the receivers endpoint
+	Integrations []*Integration `json:"integrations,omitempty"`
+
 	// name
 	// Required: true
 	Name *string `json:"name"`
@@ -40,6 +45,10 @@ type Receiver struct {
 func (m *Receiver) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateIntegrations(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateName(formats); err != nil {
 		res = append(res, err)
 	}
@@ -50,6 +59,31 @@ func (m *Receiver) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Receiver) validateIntegrations(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Integrations) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Integrations); i++ {
+		if swag.IsZero(m.Integrations[i]) { // not required
+			continue
+		}
+
+		if m.Integrations[i] != nil {
+			if err := m.Integrations[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("integrations" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *Receiver) validateName(formats strfmt.Registry) error {
 
 	if err := validate.Required("name", "body", m.Name); err != nil {
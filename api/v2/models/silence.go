@@ -44,10 +44,16 @@ type Silence struct {
 	// Format: date-time
 	EndsAt *strfmt.DateTime `json:"endsAt"`
 
+	// expiry notification
+	ExpiryNotification *SilenceExpiryNotification `json:"expiryNotification,omitempty"`
+
 	// matchers
 	// Required: true
 	Matchers Matchers `json:"matchers"`
 
+	// schedule
+	Schedule *SilenceSchedule `json:"schedule,omitempty"`
+
 	// starts at
 	// Required: true
 	// Format: date-time
@@ -70,10 +76,18 @@ func (m *Silence) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateExpiryNotification(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateMatchers(formats); err != nil {
 		res = append(res, err)
 	}
 
+	if err := m.validateSchedule(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateStartsAt(formats); err != nil {
 		res = append(res, err)
 	}
@@ -115,6 +129,24 @@ func (m *Silence) validateEndsAt(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Silence) validateExpiryNotification(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ExpiryNotification) { // not required
+		return nil
+	}
+
+	if m.ExpiryNotification != nil {
+		if err := m.ExpiryNotification.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("expiryNotification")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Silence) validateMatchers(formats strfmt.Registry) error {
 
 	if err := validate.Required("matchers", "body", m.Matchers); err != nil {
@@ -131,6 +163,24 @@ func (m *Silence) validateMatchers(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Silence) validateSchedule(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Schedule) { // not required
+		return nil
+	}
+
+	if m.Schedule != nil {
+		if err := m.Schedule.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("schedule")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Silence) validateStartsAt(formats strfmt.Registry) error {
 
 	if err := validate.Required("startsAt", "body", m.StartsAt); err != nil {
@@ -18,6 +18,7 @@ package restapi
 
 import (
 	"crypto/tls"
+	"io"
 	"net/http"
 
 	"github.com/go-openapi/errors"
@@ -25,8 +26,10 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations"
+	"github.com/prometheus/alertmanager/api/v2/restapi/operations/ack"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/alert"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/alertgroup"
+	"github.com/prometheus/alertmanager/api/v2/restapi/operations/deadletter"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/general"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/receiver"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/silence"
@@ -51,32 +54,100 @@ func configureAPI(api *operations.AlertmanagerAPI) http.Handler {
 	api.JSONConsumer = runtime.JSONConsumer()
 
 	api.JSONProducer = runtime.JSONProducer()
+	api.TextEventStreamProducer = runtime.ProducerFunc(func(w io.Writer, data interface{}) error {
+		return errors.NotImplemented("textEventStream producer has not yet been implemented")
+	})
 
+	if api.AckDeleteAckHandler == nil {
+		api.AckDeleteAckHandler = ack.DeleteAckHandlerFunc(func(params ack.DeleteAckParams) middleware.Responder {
+			return middleware.NotImplemented("operation ack.DeleteAck has not yet been implemented")
+		})
+	}
+	if api.DeadletterDeleteDeadLetterHandler == nil {
+		api.DeadletterDeleteDeadLetterHandler = deadletter.DeleteDeadLetterHandlerFunc(func(params deadletter.DeleteDeadLetterParams) middleware.Responder {
+			return middleware.NotImplemented("operation deadletter.DeleteDeadLetter has not yet been implemented")
+		})
+	}
 	if api.SilenceDeleteSilenceHandler == nil {
 		api.SilenceDeleteSilenceHandler = silence.DeleteSilenceHandlerFunc(func(params silence.DeleteSilenceParams) middleware.Responder {
 			return middleware.NotImplemented("operation silence.DeleteSilence has not yet been implemented")
 		})
 	}
+	if api.SilenceExpireSilencesHandler == nil {
+		api.SilenceExpireSilencesHandler = silence.ExpireSilencesHandlerFunc(func(params silence.ExpireSilencesParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.ExpireSilences has not yet been implemented")
+		})
+	}
+	if api.AckGetAcksHandler == nil {
+		api.AckGetAcksHandler = ack.GetAcksHandlerFunc(func(params ack.GetAcksParams) middleware.Responder {
+			return middleware.NotImplemented("operation ack.GetAcks has not yet been implemented")
+		})
+	}
 	if api.AlertgroupGetAlertGroupsHandler == nil {
 		api.AlertgroupGetAlertGroupsHandler = alertgroup.GetAlertGroupsHandlerFunc(func(params alertgroup.GetAlertGroupsParams) middleware.Responder {
 			return middleware.NotImplemented("operation alertgroup.GetAlertGroups has not yet been implemented")
 		})
 	}
+	if api.AlertGetAlertStatsHandler == nil {
+		api.AlertGetAlertStatsHandler = alert.GetAlertStatsHandlerFunc(func(params alert.GetAlertStatsParams) middleware.Responder {
+			return middleware.NotImplemented("operation alert.GetAlertStats has not yet been implemented")
+		})
+	}
 	if api.AlertGetAlertsHandler == nil {
 		api.AlertGetAlertsHandler = alert.GetAlertsHandlerFunc(func(params alert.GetAlertsParams) middleware.Responder {
 			return middleware.NotImplemented("operation alert.GetAlerts has not yet been implemented")
 		})
 	}
+	if api.DeadletterGetDeadLetterHandler == nil {
+		api.DeadletterGetDeadLetterHandler = deadletter.GetDeadLetterHandlerFunc(func(params deadletter.GetDeadLetterParams) middleware.Responder {
+			return middleware.NotImplemented("operation deadletter.GetDeadLetter has not yet been implemented")
+		})
+	}
+	if api.DeadletterGetDeadLettersHandler == nil {
+		api.DeadletterGetDeadLettersHandler = deadletter.GetDeadLettersHandlerFunc(func(params deadletter.GetDeadLettersParams) middleware.Responder {
+			return middleware.NotImplemented("operation deadletter.GetDeadLetters has not yet been implemented")
+		})
+	}
+	if api.ReceiverGetBudgetsHandler == nil {
+		api.ReceiverGetBudgetsHandler = receiver.GetBudgetsHandlerFunc(func(params receiver.GetBudgetsParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.GetBudgets has not yet been implemented")
+		})
+	}
+	if api.ReceiverGetNotificationHistoryHandler == nil {
+		api.ReceiverGetNotificationHistoryHandler = receiver.GetNotificationHistoryHandlerFunc(func(params receiver.GetNotificationHistoryParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.GetNotificationHistory has not yet been implemented")
+		})
+	}
 	if api.ReceiverGetReceiversHandler == nil {
 		api.ReceiverGetReceiversHandler = receiver.GetReceiversHandlerFunc(func(params receiver.GetReceiversParams) middleware.Responder {
 			return middleware.NotImplemented("operation receiver.GetReceivers has not yet been implemented")
 		})
 	}
+	if api.ReceiverGetReceiversHealthHandler == nil {
+		api.ReceiverGetReceiversHealthHandler = receiver.GetReceiversHealthHandlerFunc(func(params receiver.GetReceiversHealthParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.GetReceiversHealth has not yet been implemented")
+		})
+	}
 	if api.SilenceGetSilenceHandler == nil {
 		api.SilenceGetSilenceHandler = silence.GetSilenceHandlerFunc(func(params silence.GetSilenceParams) middleware.Responder {
 			return middleware.NotImplemented("operation silence.GetSilence has not yet been implemented")
 		})
 	}
+	if api.SilenceGetSilenceEventsHandler == nil {
+		api.SilenceGetSilenceEventsHandler = silence.GetSilenceEventsHandlerFunc(func(params silence.GetSilenceEventsParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.GetSilenceEvents has not yet been implemented")
+		})
+	}
+	if api.SilenceGetSilenceOccurrencesHandler == nil {
+		api.SilenceGetSilenceOccurrencesHandler = silence.GetSilenceOccurrencesHandlerFunc(func(params silence.GetSilenceOccurrencesParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.GetSilenceOccurrences has not yet been implemented")
+		})
+	}
+	if api.SilenceGetSilencePresetsHandler == nil {
+		api.SilenceGetSilencePresetsHandler = silence.GetSilencePresetsHandlerFunc(func(params silence.GetSilencePresetsParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.GetSilencePresets has not yet been implemented")
+		})
+	}
 	if api.SilenceGetSilencesHandler == nil {
 		api.SilenceGetSilencesHandler = silence.GetSilencesHandlerFunc(func(params silence.GetSilencesParams) middleware.Responder {
 			return middleware.NotImplemented("operation silence.GetSilences has not yet been implemented")
@@ -87,16 +158,56 @@ func configureAPI(api *operations.AlertmanagerAPI) http.Handler {
 			return middleware.NotImplemented("operation general.GetStatus has not yet been implemented")
 		})
 	}
+	if api.AckPostAcksHandler == nil {
+		api.AckPostAcksHandler = ack.PostAcksHandlerFunc(func(params ack.PostAcksParams) middleware.Responder {
+			return middleware.NotImplemented("operation ack.PostAcks has not yet been implemented")
+		})
+	}
 	if api.AlertPostAlertsHandler == nil {
 		api.AlertPostAlertsHandler = alert.PostAlertsHandlerFunc(func(params alert.PostAlertsParams) middleware.Responder {
 			return middleware.NotImplemented("operation alert.PostAlerts has not yet been implemented")
 		})
 	}
+	if api.SilencePostSilencePresetHandler == nil {
+		api.SilencePostSilencePresetHandler = silence.PostSilencePresetHandlerFunc(func(params silence.PostSilencePresetParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.PostSilencePreset has not yet been implemented")
+		})
+	}
 	if api.SilencePostSilencesHandler == nil {
 		api.SilencePostSilencesHandler = silence.PostSilencesHandlerFunc(func(params silence.PostSilencesParams) middleware.Responder {
 			return middleware.NotImplemented("operation silence.PostSilences has not yet been implemented")
 		})
 	}
+	if api.SilenceRecreateSilencesHandler == nil {
+		api.SilenceRecreateSilencesHandler = silence.RecreateSilencesHandlerFunc(func(params silence.RecreateSilencesParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.RecreateSilences has not yet been implemented")
+		})
+	}
+	if api.ReceiverRenderReceiverHandler == nil {
+		api.ReceiverRenderReceiverHandler = receiver.RenderReceiverHandlerFunc(func(params receiver.RenderReceiverParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.RenderReceiver has not yet been implemented")
+		})
+	}
+	if api.DeadletterReplayDeadLetterHandler == nil {
+		api.DeadletterReplayDeadLetterHandler = deadletter.ReplayDeadLetterHandlerFunc(func(params deadletter.ReplayDeadLetterParams) middleware.Responder {
+			return middleware.NotImplemented("operation deadletter.ReplayDeadLetter has not yet been implemented")
+		})
+	}
+	if api.AlertgroupResendAlertGroupHandler == nil {
+		api.AlertgroupResendAlertGroupHandler = alertgroup.ResendAlertGroupHandlerFunc(func(params alertgroup.ResendAlertGroupParams) middleware.Responder {
+			return middleware.NotImplemented("operation alertgroup.ResendAlertGroup has not yet been implemented")
+		})
+	}
+	if api.AlertStreamAlertsHandler == nil {
+		api.AlertStreamAlertsHandler = alert.StreamAlertsHandlerFunc(func(params alert.StreamAlertsParams) middleware.Responder {
+			return middleware.NotImplemented("operation alert.StreamAlerts has not yet been implemented")
+		})
+	}
+	if api.ReceiverTestReceiverHandler == nil {
+		api.ReceiverTestReceiverHandler = receiver.TestReceiverHandlerFunc(func(params receiver.TestReceiverParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.TestReceiver has not yet been implemented")
+		})
+	}
 
 	api.PreServerShutdown = func() {}
 
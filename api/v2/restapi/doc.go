@@ -28,6 +28,7 @@
 //
 //  Produces:
 //    - application/json
+//    - text/event-stream
 //
 // swagger:meta
 package restapi
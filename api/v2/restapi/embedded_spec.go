@@ -49,6 +49,79 @@ func init() {
     "version": "0.0.1"
   },
   "paths": {
+    "/ack/{ackID}": {
+      "delete": {
+        "description": "End an active acknowledgement by its ID",
+        "tags": [
+          "ack"
+        ],
+        "operationId": "deleteAck",
+        "responses": {
+          "200": {
+            "description": "Delete acknowledgement response"
+          },
+          "404": {
+            "description": "An active acknowledgement with the specified ID was not found"
+          }
+        }
+      },
+      "parameters": [
+        {
+          "type": "string",
+          "format": "uuid",
+          "description": "ID of the acknowledgement",
+          "name": "ackID",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/acks": {
+      "get": {
+        "description": "Get a list of active alert group acknowledgements",
+        "tags": [
+          "ack"
+        ],
+        "operationId": "getAcks",
+        "responses": {
+          "200": {
+            "description": "Get acknowledgements response",
+            "schema": {
+              "$ref": "#/definitions/acks"
+            }
+          }
+        }
+      },
+      "post": {
+        "description": "Acknowledge an alert group, replacing any previous acknowledgement of the group",
+        "tags": [
+          "ack"
+        ],
+        "operationId": "postAcks",
+        "parameters": [
+          {
+            "description": "The acknowledgement to create",
+            "name": "ack",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/postableAck"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Create acknowledgement response",
+            "schema": {
+              "$ref": "#/definitions/ack"
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          }
+        }
+      }
+    },
     "/alerts": {
       "get": {
         "description": "Get a list of alerts",
@@ -100,6 +173,33 @@ func init() {
             "description": "A regex matching receivers to filter alerts by",
             "name": "receiver",
             "in": "query"
+          },
+          {
+            "enum": [
+              "fingerprint",
+              "startsAt",
+              "severity",
+              "alertname"
+            ],
+            "type": "string",
+            "default": "fingerprint",
+            "description": "The order of the returned alerts. Severities are ranked by the order of the global severity mapping.",
+            "name": "sort",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "default": 0,
+            "description": "Number of alerts to skip",
+            "name": "offset",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "default": 0,
+            "description": "Maximum number of alerts to return. 0 returns all of them.",
+            "name": "limit",
+            "in": "query"
           }
         ],
         "responses": {
@@ -107,6 +207,12 @@ func init() {
             "description": "Get alerts response",
             "schema": {
               "$ref": "#/definitions/gettableAlerts"
+            },
+            "headers": {
+              "X-Total-Count": {
+                "type": "integer",
+                "description": "The number of alerts matching the filters, regardless of limit and offset"
+              }
             }
           },
           "400": {
@@ -141,6 +247,9 @@ func init() {
           "400": {
             "$ref": "#/responses/BadRequest"
           },
+          "429": {
+            "$ref": "#/responses/TooManyRequests"
+          },
           "500": {
             "$ref": "#/responses/InternalServerError"
           }
@@ -176,6 +285,13 @@ func init() {
             "name": "inhibited",
             "in": "query"
           },
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show unprocessed alerts",
+            "name": "unprocessed",
+            "in": "query"
+          },
           {
             "type": "array",
             "items": {
@@ -209,67 +325,150 @@ func init() {
         }
       }
     },
-    "/receivers": {
+    "/alerts/groups/resend": {
+      "post": {
+        "description": "Notify a receiver of an alert group again right away about its firing alerts, regardless of the repeat interval and of any acknowledgement of the group",
+        "tags": [
+          "alertgroup"
+        ],
+        "operationId": "resendAlertGroup",
+        "parameters": [
+          {
+            "description": "The alert group and receiver to notify",
+            "name": "resend",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/resendRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Resend alert group response"
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          },
+          "404": {
+            "description": "No alert group with the specified key notifies the receiver"
+          },
+          "500": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      }
+    },
+    "/alerts/stream": {
+      "get": {
+        "description": "Stream the changes of alerts as server-sent events. Each event carries an alertEvent.",
+        "produces": [
+          "text/event-stream"
+        ],
+        "tags": [
+          "alert"
+        ],
+        "operationId": "streamAlerts",
+        "parameters": [
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of matchers to filter alerts by",
+            "name": "filter",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Stream of alert events",
+            "schema": {
+              "$ref": "#/definitions/alertEvent"
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          }
+        }
+      }
+    },
+    "/budgets": {
       "get": {
-        "description": "Get list of all receivers (name of notification integrations)",
+        "description": "Get the consumption of the notification budgets of receivers and tenants in their current window",
         "tags": [
           "receiver"
         ],
-        "operationId": "getReceivers",
+        "operationId": "getBudgets",
         "responses": {
           "200": {
-            "description": "Get receivers response",
+            "description": "Get budgets response",
             "schema": {
-              "type": "array",
-              "items": {
-                "$ref": "#/definitions/receiver"
-              }
+              "$ref": "#/definitions/budgets"
             }
           }
         }
       }
     },
-    "/silence/{silenceID}": {
+    "/deadletter/{deadLetterID}": {
       "get": {
-        "description": "Get a silence by its ID",
+        "description": "Get a dead letter by its ID",
         "tags": [
-          "silence"
+          "deadletter"
         ],
-        "operationId": "getSilence",
+        "operationId": "getDeadLetter",
         "responses": {
           "200": {
-            "description": "Get silence response",
+            "description": "Get dead letter response",
             "schema": {
-              "$ref": "#/definitions/gettableSilence"
+              "$ref": "#/definitions/deadLetter"
             }
           },
           "404": {
-            "description": "A silence with the specified ID was not found"
-          },
-          "500": {
-            "$ref": "#/responses/InternalServerError"
+            "description": "A dead letter with the specified ID was not found"
           }
         }
       },
       "delete": {
-        "description": "Delete a silence by its ID",
+        "description": "Delete a dead letter by its ID without sending it",
         "tags": [
-          "silence"
+          "deadletter"
         ],
-        "operationId": "deleteSilence",
-        "parameters": [
-          {
-            "type": "string",
-            "format": "uuid",
-            "description": "ID of the silence to get",
-            "name": "silenceID",
-            "in": "path",
-            "required": true
+        "operationId": "deleteDeadLetter",
+        "responses": {
+          "200": {
+            "description": "Delete dead letter response"
+          },
+          "404": {
+            "description": "A dead letter with the specified ID was not found"
           }
+        }
+      },
+      "parameters": [
+        {
+          "type": "string",
+          "format": "uuid",
+          "description": "ID of the dead letter",
+          "name": "deadLetterID",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/deadletter/{deadLetterID}/replay": {
+      "post": {
+        "description": "Send a dead letter to its integration again. The dead letter is removed on success.",
+        "tags": [
+          "deadletter"
         ],
+        "operationId": "replayDeadLetter",
         "responses": {
           "200": {
-            "description": "Delete silence response"
+            "description": "Replay dead letter response"
+          },
+          "404": {
+            "description": "A dead letter with the specified ID was not found"
           },
           "500": {
             "$ref": "#/responses/InternalServerError"
@@ -280,616 +479,2770 @@ func init() {
         {
           "type": "string",
           "format": "uuid",
-          "description": "ID of the silence to get",
-          "name": "silenceID",
+          "description": "ID of the dead letter",
+          "name": "deadLetterID",
           "in": "path",
           "required": true
         }
       ]
     },
-    "/silences": {
+    "/deadletters": {
       "get": {
-        "description": "Get a list of silences",
+        "description": "Get a list of notifications that could not be delivered",
         "tags": [
-          "silence"
+          "deadletter"
         ],
-        "operationId": "getSilences",
+        "operationId": "getDeadLetters",
         "parameters": [
           {
-            "type": "array",
-            "items": {
-              "type": "string"
-            },
-            "collectionFormat": "multi",
-            "description": "A list of matchers to filter silences by",
-            "name": "filter",
+            "type": "string",
+            "description": "Only return dead letters of the given receiver",
+            "name": "receiver",
             "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "Get silences response",
+            "description": "Get dead letters response",
             "schema": {
-              "$ref": "#/definitions/gettableSilences"
+              "$ref": "#/definitions/deadLetters"
             }
-          },
-          "500": {
-            "$ref": "#/responses/InternalServerError"
           }
         }
-      },
-      "post": {
-        "description": "Post a new silence or update an existing one",
+      }
+    },
+    "/history": {
+      "get": {
+        "description": "Get the recorded attempts to send notifications, oldest first",
         "tags": [
-          "silence"
+          "receiver"
         ],
-        "operationId": "postSilences",
+        "operationId": "getNotificationHistory",
         "parameters": [
           {
-            "description": "The silence to create",
-            "name": "silence",
-            "in": "body",
-            "required": true,
+            "type": "string",
+            "description": "Only return attempts of the given receiver",
+            "name": "receiver",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only return attempts which started at or after the given time",
+            "name": "since",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only return attempts which started at or before the given time",
+            "name": "until",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get notification history response",
             "schema": {
-              "$ref": "#/definitions/postableSilence"
+              "$ref": "#/definitions/notificationHistory"
             }
           }
+        }
+      }
+    },
+    "/receivers": {
+      "get": {
+        "description": "Get list of all receivers along with the types of their integrations",
+        "tags": [
+          "receiver"
         ],
+        "operationId": "getReceivers",
         "responses": {
           "200": {
-            "description": "Create / update silence response",
+            "description": "Get receivers response",
             "schema": {
-              "type": "object",
-              "properties": {
-                "silenceID": {
-                  "type": "string"
-                }
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/receiver"
               }
             }
-          },
-          "400": {
-            "$ref": "#/responses/BadRequest"
-          },
-          "404": {
-            "description": "A silence with the specified ID was not found",
-            "schema": {
-              "type": "string"
-            }
           }
         }
       }
     },
-    "/status": {
+    "/receivers/health": {
       "get": {
-        "description": "Get current status of an Alertmanager instance and its cluster",
+        "description": "Get the health of the integrations of all receivers, summarized from their notification attempts",
         "tags": [
-          "general"
+          "receiver"
         ],
-        "operationId": "getStatus",
+        "operationId": "getReceiversHealth",
         "responses": {
           "200": {
-            "description": "Get status response",
+            "description": "Get receivers health response",
             "schema": {
-              "$ref": "#/definitions/alertmanagerStatus"
+              "$ref": "#/definitions/receiversHealth"
             }
           }
         }
       }
+    },
+    "/receivers/{name}/render": {
+      "post": {
+        "description": "Render the notifications each integration of the receiver would send for the given alerts, without sending them",
+        "tags": [
+          "receiver"
+        ],
+        "operationId": "renderReceiver",
+        "parameters": [
+          {
+            "description": "The alerts to render the notifications for",
+            "name": "alerts",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/postableAlerts"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Render receiver response",
+            "schema": {
+              "$ref": "#/definitions/renderedNotifications"
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          },
+          "404": {
+            "description": "A receiver with the specified name was not found or is not used by any route"
+          }
+        }
+      },
+      "parameters": [
+        {
+          "type": "string",
+          "description": "Name of the receiver",
+          "name": "name",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/receivers/{name}/test": {
+      "post": {
+        "description": "Send a test alert once through each integration of the receiver",
+        "tags": [
+          "receiver"
+        ],
+        "operationId": "testReceiver",
+        "responses": {
+          "200": {
+            "description": "Test receiver response",
+            "schema": {
+              "$ref": "#/definitions/integrationTestResults"
+            }
+          },
+          "404": {
+            "description": "A receiver with the specified name was not found or is not used by any route"
+          }
+        }
+      },
+      "parameters": [
+        {
+          "type": "string",
+          "description": "Name of the receiver",
+          "name": "name",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/silence/{silenceID}": {
+      "get": {
+        "description": "Get a silence by its ID",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "getSilence",
+        "responses": {
+          "200": {
+            "description": "Get silence response",
+            "schema": {
+              "$ref": "#/definitions/gettableSilence"
+            }
+          },
+          "404": {
+            "description": "A silence with the specified ID was not found"
+          },
+          "500": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      },
+      "delete": {
+        "description": "Delete a silence by its ID",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "deleteSilence",
+        "parameters": [
+          {
+            "type": "string",
+            "format": "uuid",
+            "description": "ID of the silence to get",
+            "name": "silenceID",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "The user expiring the silence, recorded in the silence events",
+            "name": "expiredBy",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Delete silence response"
+          },
+          "404": {
+            "description": "A silence with the specified ID was not found"
+          },
+          "500": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      },
+      "parameters": [
+        {
+          "type": "string",
+          "format": "uuid",
+          "description": "ID of the silence to get",
+          "name": "silenceID",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/silence/{silenceID}/occurrences": {
+      "get": {
+        "description": "Get the current and upcoming occurrences of a silence",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "getSilenceOccurrences",
+        "parameters": [
+          {
+            "maximum": 100,
+            "minimum": 1,
+            "type": "integer",
+            "default": 10,
+            "description": "The maximum number of occurrences to return",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get silence occurrences response",
+            "schema": {
+              "$ref": "#/definitions/silenceOccurrences"
+            }
+          },
+          "404": {
+            "description": "A silence with the specified ID was not found"
+          },
+          "500": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      },
+      "parameters": [
+        {
+          "type": "string",
+          "format": "uuid",
+          "description": "ID of the silence",
+          "name": "silenceID",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/silences": {
+      "get": {
+        "description": "Get a list of silences",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "getSilences",
+        "parameters": [
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of matchers to filter silences by",
+            "name": "filter",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "enum": [
+                "expired",
+                "active",
+                "pending"
+              ],
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of states to filter silences by",
+            "name": "state",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Show only silences created by the given author",
+            "name": "createdBy",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Show only silences ending at or after the given time",
+            "name": "since",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Show only silences starting at or before the given time",
+            "name": "until",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "default": 0,
+            "description": "Number of silences to skip",
+            "name": "offset",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "default": 0,
+            "description": "Maximum number of silences to return. 0 returns all of them.",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get silences response",
+            "schema": {
+              "$ref": "#/definitions/gettableSilences"
+            },
+            "headers": {
+              "X-Total-Count": {
+                "type": "integer",
+                "description": "The number of silences matching the filters, regardless of limit and offset"
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          },
+          "500": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      },
+      "post": {
+        "description": "Post a new silence or update an existing one",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "postSilences",
+        "parameters": [
+          {
+            "description": "The silence to create",
+            "name": "silence",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/postableSilence"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Create / update silence response",
+            "schema": {
+              "type": "object",
+              "properties": {
+                "silenceID": {
+                  "type": "string"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          },
+          "404": {
+            "description": "A silence with the specified ID was not found",
+            "schema": {
+              "type": "string"
+            }
+          }
+        }
+      }
+    },
+    "/silences/events": {
+      "get": {
+        "description": "Get the creations, modifications and expiries of silences, oldest first",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "getSilenceEvents",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Only return events of the given silence",
+            "name": "silenceID",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Only return events made by the given user",
+            "name": "user",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only return events which happened at or after the given time",
+            "name": "since",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only return events which happened at or before the given time",
+            "name": "until",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get silence events response",
+            "schema": {
+              "$ref": "#/definitions/silenceEvents"
+            }
+          }
+        }
+      }
+    },
+    "/silences/expire": {
+      "post": {
+        "description": "Expire all active and pending silences matching a filter",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "expireSilences",
+        "parameters": [
+          {
+            "description": "The silences to expire",
+            "name": "request",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/bulkSilenceRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Expire silences response",
+            "schema": {
+              "$ref": "#/definitions/bulkSilenceResult"
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          },
+          "500": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      }
+    },
+    "/silences/presets": {
+      "get": {
+        "description": "Get the silence presets of the configuration",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "getSilencePresets",
+        "responses": {
+          "200": {
+            "description": "Get silence presets response",
+            "schema": {
+              "$ref": "#/definitions/silencePresets"
+            }
+          }
+        }
+      }
+    },
+    "/silences/presets/{presetName}": {
+      "post": {
+        "description": "Create a silence from a silence preset",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "postSilencePreset",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Name of the silence preset",
+            "name": "presetName",
+            "in": "path",
+            "required": true
+          },
+          {
+            "description": "The parameters of the silence",
+            "name": "instantiation",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/silencePresetInstantiation"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Create silence response",
+            "schema": {
+              "type": "object",
+              "properties": {
+                "silenceID": {
+                  "type": "string"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          },
+          "404": {
+            "description": "A silence preset with the specified name was not found"
+          }
+        }
+      }
+    },
+    "/silences/recreate": {
+      "post": {
+        "description": "Re-create all expired silences matching a filter, starting now",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "recreateSilences",
+        "parameters": [
+          {
+            "description": "The silences to re-create",
+            "name": "request",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/bulkSilenceRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Re-create silences response",
+            "schema": {
+              "$ref": "#/definitions/bulkSilenceResult"
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          },
+          "500": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      }
+    },
+    "/stats": {
+      "get": {
+        "description": "Get the number of alerts by alertname, severity and receiver over recent windows",
+        "tags": [
+          "alert"
+        ],
+        "operationId": "getAlertStats",
+        "parameters": [
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "The windows to count the alerts firing in, e.g. 1h. Defaults to 1h and 24h.",
+            "name": "window",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of matchers to filter alerts by",
+            "name": "filter",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get alert stats response",
+            "schema": {
+              "$ref": "#/definitions/alertStats"
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadRequest"
+          },
+          "500": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      }
+    },
+    "/status": {
+      "get": {
+        "description": "Get current status of an Alertmanager instance and its cluster",
+        "tags": [
+          "general"
+        ],
+        "operationId": "getStatus",
+        "responses": {
+          "200": {
+            "description": "Get status response",
+            "schema": {
+              "$ref": "#/definitions/alertmanagerStatus"
+            }
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "ack": {
+      "allOf": [
+        {
+          "type": "object",
+          "required": [
+            "id",
+            "startsAt",
+            "updatedAt"
+          ],
+          "properties": {
+            "id": {
+              "type": "string"
+            },
+            "startsAt": {
+              "type": "string",
+              "format": "date-time"
+            },
+            "updatedAt": {
+              "type": "string",
+              "format": "date-time"
+            }
+          }
+        },
+        {
+          "$ref": "#/definitions/postableAck"
+        }
+      ]
+    },
+    "acks": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/ack"
+      }
+    },
+    "alert": {
+      "type": "object",
+      "required": [
+        "labels"
+      ],
+      "properties": {
+        "generatorURL": {
+          "type": "string",
+          "format": "uri"
+        },
+        "labels": {
+          "$ref": "#/definitions/labelSet"
+        }
+      }
+    },
+    "alertCounts": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "integer"
+      }
+    },
+    "alertEvent": {
+      "type": "object",
+      "required": [
+        "type",
+        "alert"
+      ],
+      "properties": {
+        "alert": {
+          "$ref": "#/definitions/gettableAlert"
+        },
+        "type": {
+          "type": "string",
+          "enum": [
+            "new",
+            "updated",
+            "resolved",
+            "silenced",
+            "inhibited",
+            "active"
+          ]
+        }
+      }
+    },
+    "alertGroup": {
+      "type": "object",
+      "required": [
+        "labels",
+        "receiver",
+        "alerts"
+      ],
+      "properties": {
+        "acknowledgement": {
+          "$ref": "#/definitions/ack"
+        },
+        "alerts": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/gettableAlert"
+          }
+        },
+        "groupId": {
+          "description": "The SHA-256 hash of the group key",
+          "type": "string"
+        },
+        "groupKey": {
+          "description": "The key identifying the group, also sent in its notifications",
+          "type": "string"
+        },
+        "labels": {
+          "$ref": "#/definitions/labelSet"
+        },
+        "nextFlush": {
+          "description": "The time at which the group is next flushed to its receiver",
+          "type": "string",
+          "format": "date-time"
+        },
+        "receiver": {
+          "$ref": "#/definitions/receiver"
+        }
+      }
+    },
+    "alertGroups": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/alertGroup"
+      }
+    },
+    "alertSource": {
+      "description": "The client which sent the alert",
+      "type": "object",
+      "properties": {
+        "address": {
+          "description": "The IP address of the client",
+          "type": "string"
+        },
+        "identity": {
+          "description": "The authenticated identity of the client",
+          "type": "string"
+        },
+        "name": {
+          "description": "The name of the source set by the client",
+          "type": "string"
+        }
+      }
+    },
+    "alertStats": {
+      "type": "object",
+      "required": [
+        "windows"
+      ],
+      "properties": {
+        "windows": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/alertStatsWindow"
+          }
+        }
+      }
+    },
+    "alertStatsWindow": {
+      "type": "object",
+      "required": [
+        "window",
+        "total",
+        "byAlertname",
+        "bySeverity",
+        "byReceiver"
+      ],
+      "properties": {
+        "byAlertname": {
+          "$ref": "#/definitions/alertCounts"
+        },
+        "byReceiver": {
+          "$ref": "#/definitions/alertCounts"
+        },
+        "bySeverity": {
+          "$ref": "#/definitions/alertCounts"
+        },
+        "total": {
+          "description": "The number of alerts firing at any time in the window",
+          "type": "integer"
+        },
+        "window": {
+          "description": "The duration of the window, ending now",
+          "type": "string"
+        }
+      }
+    },
+    "alertStatus": {
+      "type": "object",
+      "required": [
+        "state",
+        "silencedBy",
+        "inhibitedBy"
+      ],
+      "properties": {
+        "inhibitedBy": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "silencedBy": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "state": {
+          "type": "string",
+          "enum": [
+            "unprocessed",
+            "active",
+            "suppressed"
+          ]
+        }
+      }
+    },
+    "alertmanagerConfig": {
+      "type": "object",
+      "required": [
+        "original"
+      ],
+      "properties": {
+        "original": {
+          "type": "string"
+        }
+      }
+    },
+    "alertmanagerStatus": {
+      "type": "object",
+      "required": [
+        "cluster",
+        "versionInfo",
+        "config",
+        "uptime"
+      ],
+      "properties": {
+        "cluster": {
+          "$ref": "#/definitions/clusterStatus"
+        },
+        "config": {
+          "$ref": "#/definitions/alertmanagerConfig"
+        },
+        "uptime": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "versionInfo": {
+          "$ref": "#/definitions/versionInfo"
+        }
+      }
+    },
+    "budget": {
+      "type": "object",
+      "required": [
+        "scope",
+        "name",
+        "window",
+        "limit",
+        "used",
+        "resetsAt",
+        "queued"
+      ],
+      "properties": {
+        "limit": {
+          "description": "The maximum number of notifications in the window",
+          "type": "integer"
+        },
+        "name": {
+          "description": "The name of the receiver or of the tenant",
+          "type": "string"
+        },
+        "queued": {
+          "description": "The number of alerts queued for the digest sent once the budget resets",
+          "type": "integer"
+        },
+        "resetsAt": {
+          "description": "The end of the current window",
+          "type": "string",
+          "format": "date-time"
+        },
+        "scope": {
+          "description": "receiver or tenant",
+          "type": "string"
+        },
+        "used": {
+          "description": "The number of notifications counted against the budget in the current window",
+          "type": "integer"
+        },
+        "window": {
+          "description": "daily or weekly",
+          "type": "string"
+        }
+      }
+    },
+    "budgets": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/budget"
+      }
+    },
+    "bulkSilenceRequest": {
+      "type": "object",
+      "properties": {
+        "createdBy": {
+          "description": "Only select the silences created by the given author",
+          "type": "string"
+        },
+        "dryRun": {
+          "description": "Only return the selected silences without changing them",
+          "type": "boolean"
+        },
+        "duration": {
+          "description": "The duration of the re-created silences, e.g. 4h",
+          "type": "string"
+        },
+        "filter": {
+          "description": "Matchers which the silences must have",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "user": {
+          "description": "The user performing the operation",
+          "type": "string"
+        }
+      }
+    },
+    "bulkSilenceResult": {
+      "type": "object",
+      "required": [
+        "silences"
+      ],
+      "properties": {
+        "recreated": {
+          "description": "The IDs of the re-created silences, keyed by the ID of the silence they were re-created from",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
+        "silences": {
+          "$ref": "#/definitions/gettableSilences"
+        }
+      }
+    },
+    "clusterStatus": {
+      "type": "object",
+      "required": [
+        "status"
+      ],
+      "properties": {
+        "name": {
+          "type": "string"
+        },
+        "peers": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/peerStatus"
+          }
+        },
+        "status": {
+          "type": "string",
+          "enum": [
+            "ready",
+            "settling",
+            "disabled"
+          ]
+        }
+      }
+    },
+    "deadLetter": {
+      "type": "object",
+      "required": [
+        "id",
+        "receiver",
+        "integration",
+        "groupKey",
+        "groupLabels",
+        "alerts",
+        "error",
+        "createdAt",
+        "replays"
+      ],
+      "properties": {
+        "alerts": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/postableAlert"
+          }
+        },
+        "createdAt": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "error": {
+          "type": "string"
+        },
+        "groupKey": {
+          "type": "string"
+        },
+        "groupLabels": {
+          "$ref": "#/definitions/labelSet"
+        },
+        "id": {
+          "type": "string"
+        },
+        "integration": {
+          "type": "string"
+        },
+        "lastReplayAt": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "receiver": {
+          "type": "string"
+        },
+        "replays": {
+          "type": "integer"
+        }
+      }
+    },
+    "deadLetters": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/deadLetter"
+      }
+    },
+    "gettableAlert": {
+      "allOf": [
+        {
+          "type": "object",
+          "required": [
+            "receivers",
+            "fingerprint",
+            "startsAt",
+            "updatedAt",
+            "endsAt",
+            "annotations",
+            "status"
+          ],
+          "properties": {
+            "annotations": {
+              "$ref": "#/definitions/labelSet"
+            },
+            "endsAt": {
+              "type": "string",
+              "format": "date-time"
+            },
+            "fingerprint": {
+              "type": "string"
+            },
+            "receivers": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/receiver"
+              }
+            },
+            "source": {
+              "$ref": "#/definitions/alertSource"
+            },
+            "startsAt": {
+              "type": "string",
+              "format": "date-time"
+            },
+            "status": {
+              "$ref": "#/definitions/alertStatus"
+            },
+            "updatedAt": {
+              "type": "string",
+              "format": "date-time"
+            }
+          }
+        },
+        {
+          "$ref": "#/definitions/alert"
+        }
+      ]
+    },
+    "gettableAlerts": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/gettableAlert"
+      }
+    },
+    "gettableSilence": {
+      "allOf": [
+        {
+          "type": "object",
+          "required": [
+            "id",
+            "status",
+            "updatedAt"
+          ],
+          "properties": {
+            "id": {
+              "type": "string"
+            },
+            "status": {
+              "$ref": "#/definitions/silenceStatus"
+            },
+            "updatedAt": {
+              "type": "string",
+              "format": "date-time"
+            }
+          }
+        },
+        {
+          "$ref": "#/definitions/silence"
+        }
+      ]
+    },
+    "gettableSilences": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/gettableSilence"
+      }
+    },
+    "integration": {
+      "type": "object",
+      "required": [
+        "name",
+        "index",
+        "sendResolved"
+      ],
+      "properties": {
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "name": {
+          "description": "The type of the integration, e.g. email or slack",
+          "type": "string"
+        },
+        "sendResolved": {
+          "type": "boolean"
+        }
+      }
+    },
+    "integrationHealth": {
+      "type": "object",
+      "required": [
+        "name",
+        "index",
+        "state",
+        "consecutiveFailures"
+      ],
+      "properties": {
+        "consecutiveFailures": {
+          "description": "The number of attempts which failed since the last successful one",
+          "type": "integer"
+        },
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "lastError": {
+          "description": "The error of the last failed attempt",
+          "type": "string"
+        },
+        "lastFailure": {
+          "description": "The time of the last failed attempt",
+          "type": "string",
+          "format": "date-time"
+        },
+        "lastSuccess": {
+          "description": "The time of the last successful attempt",
+          "type": "string",
+          "format": "date-time"
+        },
+        "lastThrottled": {
+          "description": "The last time the receiver answered that the integration sent too many requests",
+          "type": "string",
+          "format": "date-time"
+        },
+        "name": {
+          "description": "The type of the integration, e.g. email or slack",
+          "type": "string"
+        },
+        "state": {
+          "description": "unknown if the integration didn't attempt to send a notification yet, healthy if its last attempt succeeded and failing otherwise",
+          "type": "string"
+        }
+      }
+    },
+    "integrationTestResult": {
+      "type": "object",
+      "required": [
+        "integration",
+        "index",
+        "success"
+      ],
+      "properties": {
+        "error": {
+          "description": "The error returned by the integration if the test failed",
+          "type": "string"
+        },
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "integration": {
+          "description": "The type of the integration, e.g. email or slack",
+          "type": "string"
+        },
+        "success": {
+          "type": "boolean"
+        }
+      }
+    },
+    "integrationTestResults": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/integrationTestResult"
+      }
+    },
+    "labelSet": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      }
+    },
+    "matcher": {
+      "type": "object",
+      "required": [
+        "name",
+        "value",
+        "isRegex"
+      ],
+      "properties": {
+        "isEqual": {
+          "type": "boolean",
+          "default": true
+        },
+        "isRegex": {
+          "type": "boolean"
+        },
+        "name": {
+          "type": "string"
+        },
+        "value": {
+          "type": "string"
+        }
+      }
+    },
+    "matchers": {
+      "type": "array",
+      "minItems": 1,
+      "items": {
+        "$ref": "#/definitions/matcher"
+      }
+    },
+    "notificationHistory": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/notificationHistoryEntry"
+      }
+    },
+    "notificationHistoryEntry": {
+      "type": "object",
+      "required": [
+        "time",
+        "receiver",
+        "integration",
+        "index",
+        "groupKey",
+        "groupLabels",
+        "fingerprints",
+        "attempt",
+        "outcome"
+      ],
+      "properties": {
+        "attempt": {
+          "description": "The number of the attempt, starting at 1",
+          "type": "integer"
+        },
+        "error": {
+          "type": "string"
+        },
+        "fingerprints": {
+          "description": "The fingerprints of the alerts of the notification",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "groupKey": {
+          "type": "string"
+        },
+        "groupLabels": {
+          "$ref": "#/definitions/labelSet"
+        },
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "integration": {
+          "type": "string"
+        },
+        "outcome": {
+          "description": "succeeded, failed or aborted",
+          "type": "string"
+        },
+        "receiver": {
+          "type": "string"
+        },
+        "statusCode": {
+          "description": "The status code answered by the receiver to a failed attempt",
+          "type": "integer"
+        },
+        "time": {
+          "description": "The time the attempt started",
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "peerStatus": {
+      "type": "object",
+      "required": [
+        "name",
+        "address"
+      ],
+      "properties": {
+        "address": {
+          "type": "string"
+        },
+        "name": {
+          "type": "string"
+        }
+      }
+    },
+    "postableAck": {
+      "type": "object",
+      "required": [
+        "groupKey",
+        "ackedBy",
+        "endsAt"
+      ],
+      "properties": {
+        "ackedBy": {
+          "type": "string"
+        },
+        "comment": {
+          "type": "string"
+        },
+        "endsAt": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "groupKey": {
+          "type": "string"
+        }
+      }
+    },
+    "postableAlert": {
+      "allOf": [
+        {
+          "type": "object",
+          "properties": {
+            "annotations": {
+              "$ref": "#/definitions/labelSet"
+            },
+            "endsAt": {
+              "type": "string",
+              "format": "date-time"
+            },
+            "source": {
+              "description": "The name of the source of the alert, which routes can match with the __source__ pseudo-label",
+              "type": "string"
+            },
+            "startsAt": {
+              "type": "string",
+              "format": "date-time"
+            }
+          }
+        },
+        {
+          "$ref": "#/definitions/alert"
+        }
+      ]
+    },
+    "postableAlerts": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/postableAlert"
+      }
+    },
+    "postableSilence": {
+      "allOf": [
+        {
+          "type": "object",
+          "properties": {
+            "id": {
+              "type": "string"
+            }
+          }
+        },
+        {
+          "$ref": "#/definitions/silence"
+        }
+      ]
+    },
+    "receiver": {
+      "type": "object",
+      "required": [
+        "name"
+      ],
+      "properties": {
+        "integrations": {
+          "description": "The integrations of the receiver, only set by the receivers endpoint",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/integration"
+          },
+          "x-omitempty": true
+        },
+        "name": {
+          "type": "string"
+        }
+      }
+    },
+    "receiverHealth": {
+      "type": "object",
+      "required": [
+        "name",
+        "state",
+        "deadLetters",
+        "integrations"
+      ],
+      "properties": {
+        "deadLetters": {
+          "description": "The number of notifications of the receiver in the dead-letter queue",
+          "type": "integer"
+        },
+        "integrations": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/integrationHealth"
+          }
+        },
+        "name": {
+          "type": "string"
+        },
+        "state": {
+          "description": "failing if one of the integrations is failing, healthy if one of them is healthy and unknown otherwise",
+          "type": "string"
+        }
+      }
+    },
+    "receiversHealth": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/receiverHealth"
+      }
+    },
+    "renderedNotification": {
+      "type": "object",
+      "required": [
+        "integration",
+        "index",
+        "payloads"
+      ],
+      "properties": {
+        "error": {
+          "description": "The error which prevented the integration from rendering its notification",
+          "type": "string"
+        },
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "integration": {
+          "description": "The type of the integration, e.g. email or slack",
+          "type": "string"
+        },
+        "payloads": {
+          "description": "The payloads of the requests the integration would send, in order",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/renderedPayload"
+          }
+        }
+      }
+    },
+    "renderedNotifications": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/renderedNotification"
+      }
+    },
+    "renderedPayload": {
+      "type": "object",
+      "required": [
+        "contentType",
+        "body"
+      ],
+      "properties": {
+        "body": {
+          "type": "string"
+        },
+        "contentType": {
+          "type": "string"
+        }
+      }
+    },
+    "resendRequest": {
+      "type": "object",
+      "required": [
+        "groupKey",
+        "receiver"
+      ],
+      "properties": {
+        "groupKey": {
+          "type": "string"
+        },
+        "receiver": {
+          "type": "string"
+        }
+      }
+    },
+    "silence": {
+      "type": "object",
+      "required": [
+        "matchers",
+        "startsAt",
+        "endsAt",
+        "createdBy",
+        "comment"
+      ],
+      "properties": {
+        "comment": {
+          "type": "string"
+        },
+        "createdBy": {
+          "type": "string"
+        },
+        "endsAt": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "expiryNotification": {
+          "$ref": "#/definitions/silenceExpiryNotification"
+        },
+        "matchers": {
+          "$ref": "#/definitions/matchers"
+        },
+        "schedule": {
+          "$ref": "#/definitions/silenceSchedule"
+        },
+        "startsAt": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "silenceChange": {
+      "type": "object",
+      "required": [
+        "field"
+      ],
+      "properties": {
+        "field": {
+          "type": "string"
+        },
+        "new": {
+          "type": "string"
+        },
+        "old": {
+          "type": "string"
+        }
+      }
+    },
+    "silenceEvent": {
+      "type": "object",
+      "required": [
+        "id",
+        "silenceID",
+        "type",
+        "time",
+        "changes"
+      ],
+      "properties": {
+        "changes": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/silenceChange"
+          }
+        },
+        "id": {
+          "type": "string"
+        },
+        "silenceID": {
+          "type": "string"
+        },
+        "time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "type": {
+          "type": "string",
+          "enum": [
+            "created",
+            "updated",
+            "expired",
+            "rescheduled"
+          ]
+        },
+        "user": {
+          "description": "The user who made the change, if known",
+          "type": "string"
+        }
+      }
+    },
+    "silenceEvents": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/silenceEvent"
+      }
+    },
+    "silenceExpiryNotification": {
+      "description": "A notification sent to a receiver shortly before the silence expires",
+      "type": "object",
+      "required": [
+        "receiver",
+        "before"
+      ],
+      "properties": {
+        "before": {
+          "description": "How long before the end of the silence the receiver is notified, e.g. 1h",
+          "type": "string"
+        },
+        "receiver": {
+          "description": "The name of the receiver to notify",
+          "type": "string"
+        }
+      }
+    },
+    "silenceOccurrence": {
+      "type": "object",
+      "required": [
+        "startsAt",
+        "endsAt"
+      ],
+      "properties": {
+        "endsAt": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "startsAt": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "silenceOccurrences": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/silenceOccurrence"
+      }
+    },
+    "silencePreset": {
+      "type": "object",
+      "required": [
+        "name",
+        "matchers",
+        "duration",
+        "comment"
+      ],
+      "properties": {
+        "comment": {
+          "description": "Comment template, executed with the parameters of an instantiation",
+          "type": "string"
+        },
+        "duration": {
+          "type": "string"
+        },
+        "matchers": {
+          "description": "Matcher templates, executed with the parameters of an instantiation",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "name": {
+          "type": "string"
+        }
+      }
+    },
+    "silencePresetInstantiation": {
+      "type": "object",
+      "required": [
+        "createdBy"
+      ],
+      "properties": {
+        "createdBy": {
+          "type": "string"
+        },
+        "duration": {
+          "description": "Overrides the duration of the preset, e.g. 4h",
+          "type": "string"
+        },
+        "parameters": {
+          "description": "The values used in the templates of the preset",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "silencePresets": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/silencePreset"
+      }
+    },
+    "silenceSchedule": {
+      "description": "A schedule making the silence recur. The start and end time of a recurring silence are set to its current or next occurrence.",
+      "type": "object",
+      "required": [
+        "cron",
+        "duration"
+      ],
+      "properties": {
+        "cron": {
+          "description": "A cron expression for the start of the occurrences, e.g. \"0 22 * * SAT\". It is evaluated in UTC unless prefixed with CRON_TZ=\u003clocation\u003e.",
+          "type": "string"
+        },
+        "duration": {
+          "description": "The duration of each occurrence, e.g. 4h",
+          "type": "string"
+        },
+        "until": {
+          "description": "No occurrence starts after this time if it is set",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        }
+      }
+    },
+    "silenceStatus": {
+      "type": "object",
+      "required": [
+        "state"
+      ],
+      "properties": {
+        "state": {
+          "type": "string",
+          "enum": [
+            "expired",
+            "active",
+            "pending"
+          ]
+        }
+      }
+    },
+    "versionInfo": {
+      "type": "object",
+      "required": [
+        "version",
+        "revision",
+        "branch",
+        "buildUser",
+        "buildDate",
+        "goVersion"
+      ],
+      "properties": {
+        "branch": {
+          "type": "string"
+        },
+        "buildDate": {
+          "type": "string"
+        },
+        "buildUser": {
+          "type": "string"
+        },
+        "goVersion": {
+          "type": "string"
+        },
+        "revision": {
+          "type": "string"
+        },
+        "version": {
+          "type": "string"
+        }
+      }
     }
   },
-  "definitions": {
-    "alert": {
-      "type": "object",
-      "required": [
-        "labels"
-      ],
-      "properties": {
-        "generatorURL": {
+  "responses": {
+    "BadRequest": {
+      "description": "Bad request",
+      "schema": {
+        "type": "string"
+      }
+    },
+    "InternalServerError": {
+      "description": "Internal server error",
+      "schema": {
+        "type": "string"
+      }
+    },
+    "TooManyRequests": {
+      "description": "Too many requests",
+      "schema": {
+        "type": "string"
+      }
+    }
+  },
+  "tags": [
+    {
+      "description": "General Alertmanager operations",
+      "name": "general"
+    },
+    {
+      "description": "Everything related to Alertmanager receivers",
+      "name": "receiver"
+    },
+    {
+      "description": "Everything related to Alertmanager silences",
+      "name": "silence"
+    },
+    {
+      "description": "Everything related to Alertmanager alerts",
+      "name": "alert"
+    },
+    {
+      "description": "Everything related to notifications that could not be delivered",
+      "name": "deadletter"
+    },
+    {
+      "description": "Everything related to alert group acknowledgements",
+      "name": "ack"
+    }
+  ]
+}`))
+	FlatSwaggerJSON = json.RawMessage([]byte(`{
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "swagger": "2.0",
+  "info": {
+    "description": "API of the Prometheus Alertmanager (https://github.com/prometheus/alertmanager)",
+    "title": "Alertmanager API",
+    "license": {
+      "name": "Apache 2.0",
+      "url": "http://www.apache.org/licenses/LICENSE-2.0.html"
+    },
+    "version": "0.0.1"
+  },
+  "paths": {
+    "/ack/{ackID}": {
+      "delete": {
+        "description": "End an active acknowledgement by its ID",
+        "tags": [
+          "ack"
+        ],
+        "operationId": "deleteAck",
+        "responses": {
+          "200": {
+            "description": "Delete acknowledgement response"
+          },
+          "404": {
+            "description": "An active acknowledgement with the specified ID was not found"
+          }
+        }
+      },
+      "parameters": [
+        {
           "type": "string",
-          "format": "uri"
-        },
-        "labels": {
-          "$ref": "#/definitions/labelSet"
+          "format": "uuid",
+          "description": "ID of the acknowledgement",
+          "name": "ackID",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/acks": {
+      "get": {
+        "description": "Get a list of active alert group acknowledgements",
+        "tags": [
+          "ack"
+        ],
+        "operationId": "getAcks",
+        "responses": {
+          "200": {
+            "description": "Get acknowledgements response",
+            "schema": {
+              "$ref": "#/definitions/acks"
+            }
+          }
+        }
+      },
+      "post": {
+        "description": "Acknowledge an alert group, replacing any previous acknowledgement of the group",
+        "tags": [
+          "ack"
+        ],
+        "operationId": "postAcks",
+        "parameters": [
+          {
+            "description": "The acknowledgement to create",
+            "name": "ack",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/postableAck"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Create acknowledgement response",
+            "schema": {
+              "$ref": "#/definitions/ack"
+            }
+          },
+          "400": {
+            "description": "Bad request",
+            "schema": {
+              "type": "string"
+            }
+          }
         }
       }
     },
-    "alertGroup": {
-      "type": "object",
-      "required": [
-        "labels",
-        "receiver",
-        "alerts"
-      ],
-      "properties": {
-        "alerts": {
-          "type": "array",
-          "items": {
-            "$ref": "#/definitions/gettableAlert"
+    "/alerts": {
+      "get": {
+        "description": "Get a list of alerts",
+        "tags": [
+          "alert"
+        ],
+        "operationId": "getAlerts",
+        "parameters": [
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show active alerts",
+            "name": "active",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show silenced alerts",
+            "name": "silenced",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show inhibited alerts",
+            "name": "inhibited",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show unprocessed alerts",
+            "name": "unprocessed",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of matchers to filter alerts by",
+            "name": "filter",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "A regex matching receivers to filter alerts by",
+            "name": "receiver",
+            "in": "query"
+          },
+          {
+            "enum": [
+              "fingerprint",
+              "startsAt",
+              "severity",
+              "alertname"
+            ],
+            "type": "string",
+            "default": "fingerprint",
+            "description": "The order of the returned alerts. Severities are ranked by the order of the global severity mapping.",
+            "name": "sort",
+            "in": "query"
+          },
+          {
+            "minimum": 0,
+            "type": "integer",
+            "default": 0,
+            "description": "Number of alerts to skip",
+            "name": "offset",
+            "in": "query"
+          },
+          {
+            "minimum": 0,
+            "type": "integer",
+            "default": 0,
+            "description": "Maximum number of alerts to return. 0 returns all of them.",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get alerts response",
+            "schema": {
+              "$ref": "#/definitions/gettableAlerts"
+            },
+            "headers": {
+              "X-Total-Count": {
+                "type": "integer",
+                "description": "The number of alerts matching the filters, regardless of limit and offset"
+              }
+            }
+          },
+          "400": {
+            "description": "Bad request",
+            "schema": {
+              "type": "string"
+            }
+          },
+          "500": {
+            "description": "Internal server error",
+            "schema": {
+              "type": "string"
+            }
+          }
+        }
+      },
+      "post": {
+        "description": "Create new Alerts",
+        "tags": [
+          "alert"
+        ],
+        "operationId": "postAlerts",
+        "parameters": [
+          {
+            "description": "The alerts to create",
+            "name": "alerts",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/postableAlerts"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Create alerts response"
+          },
+          "400": {
+            "description": "Bad request",
+            "schema": {
+              "type": "string"
+            }
+          },
+          "429": {
+            "description": "Too many requests",
+            "schema": {
+              "type": "string"
+            }
+          },
+          "500": {
+            "description": "Internal server error",
+            "schema": {
+              "type": "string"
+            }
           }
-        },
-        "labels": {
-          "$ref": "#/definitions/labelSet"
-        },
-        "receiver": {
-          "$ref": "#/definitions/receiver"
         }
       }
     },
-    "alertGroups": {
-      "type": "array",
-      "items": {
-        "$ref": "#/definitions/alertGroup"
-      }
-    },
-    "alertStatus": {
-      "type": "object",
-      "required": [
-        "state",
-        "silencedBy",
-        "inhibitedBy"
-      ],
-      "properties": {
-        "inhibitedBy": {
-          "type": "array",
-          "items": {
-            "type": "string"
+    "/alerts/groups": {
+      "get": {
+        "description": "Get a list of alert groups",
+        "tags": [
+          "alertgroup"
+        ],
+        "operationId": "getAlertGroups",
+        "parameters": [
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show active alerts",
+            "name": "active",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show silenced alerts",
+            "name": "silenced",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show inhibited alerts",
+            "name": "inhibited",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "default": true,
+            "description": "Show unprocessed alerts",
+            "name": "unprocessed",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of matchers to filter alerts by",
+            "name": "filter",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "A regex matching receivers to filter alerts by",
+            "name": "receiver",
+            "in": "query"
           }
-        },
-        "silencedBy": {
-          "type": "array",
-          "items": {
-            "type": "string"
+        ],
+        "responses": {
+          "200": {
+            "description": "Get alert groups response",
+            "schema": {
+              "$ref": "#/definitions/alertGroups"
+            }
+          },
+          "400": {
+            "description": "Bad request",
+            "schema": {
+              "type": "string"
+            }
+          },
+          "500": {
+            "description": "Internal server error",
+            "schema": {
+              "type": "string"
+            }
           }
-        },
-        "state": {
-          "type": "string",
-          "enum": [
-            "unprocessed",
-            "active",
-            "suppressed"
-          ]
-        }
-      }
-    },
-    "alertmanagerConfig": {
-      "type": "object",
-      "required": [
-        "original"
-      ],
-      "properties": {
-        "original": {
-          "type": "string"
-        }
-      }
-    },
-    "alertmanagerStatus": {
-      "type": "object",
-      "required": [
-        "cluster",
-        "versionInfo",
-        "config",
-        "uptime"
-      ],
-      "properties": {
-        "cluster": {
-          "$ref": "#/definitions/clusterStatus"
-        },
-        "config": {
-          "$ref": "#/definitions/alertmanagerConfig"
-        },
-        "uptime": {
-          "type": "string",
-          "format": "date-time"
-        },
-        "versionInfo": {
-          "$ref": "#/definitions/versionInfo"
         }
       }
     },
-    "clusterStatus": {
-      "type": "object",
-      "required": [
-        "status"
-      ],
-      "properties": {
-        "name": {
-          "type": "string"
-        },
-        "peers": {
-          "type": "array",
-          "items": {
-            "$ref": "#/definitions/peerStatus"
+    "/alerts/groups/resend": {
+      "post": {
+        "description": "Notify a receiver of an alert group again right away about its firing alerts, regardless of the repeat interval and of any acknowledgement of the group",
+        "tags": [
+          "alertgroup"
+        ],
+        "operationId": "resendAlertGroup",
+        "parameters": [
+          {
+            "description": "The alert group and receiver to notify",
+            "name": "resend",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/resendRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Resend alert group response"
+          },
+          "400": {
+            "description": "Bad request",
+            "schema": {
+              "type": "string"
+            }
+          },
+          "404": {
+            "description": "No alert group with the specified key notifies the receiver"
+          },
+          "500": {
+            "description": "Internal server error",
+            "schema": {
+              "type": "string"
+            }
           }
-        },
-        "status": {
-          "type": "string",
-          "enum": [
-            "ready",
-            "settling",
-            "disabled"
-          ]
         }
       }
     },
-    "gettableAlert": {
-      "allOf": [
-        {
-          "type": "object",
-          "required": [
-            "receivers",
-            "fingerprint",
-            "startsAt",
-            "updatedAt",
-            "endsAt",
-            "annotations",
-            "status"
-          ],
-          "properties": {
-            "annotations": {
-              "$ref": "#/definitions/labelSet"
-            },
-            "endsAt": {
-              "type": "string",
-              "format": "date-time"
-            },
-            "fingerprint": {
+    "/alerts/stream": {
+      "get": {
+        "description": "Stream the changes of alerts as server-sent events. Each event carries an alertEvent.",
+        "produces": [
+          "text/event-stream"
+        ],
+        "tags": [
+          "alert"
+        ],
+        "operationId": "streamAlerts",
+        "parameters": [
+          {
+            "type": "array",
+            "items": {
               "type": "string"
             },
-            "receivers": {
-              "type": "array",
-              "items": {
-                "$ref": "#/definitions/receiver"
-              }
-            },
-            "startsAt": {
-              "type": "string",
-              "format": "date-time"
-            },
-            "status": {
-              "$ref": "#/definitions/alertStatus"
-            },
-            "updatedAt": {
-              "type": "string",
-              "format": "date-time"
+            "collectionFormat": "multi",
+            "description": "A list of matchers to filter alerts by",
+            "name": "filter",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Stream of alert events",
+            "schema": {
+              "$ref": "#/definitions/alertEvent"
+            }
+          },
+          "400": {
+            "description": "Bad request",
+            "schema": {
+              "type": "string"
+            }
+          }
+        }
+      }
+    },
+    "/budgets": {
+      "get": {
+        "description": "Get the consumption of the notification budgets of receivers and tenants in their current window",
+        "tags": [
+          "receiver"
+        ],
+        "operationId": "getBudgets",
+        "responses": {
+          "200": {
+            "description": "Get budgets response",
+            "schema": {
+              "$ref": "#/definitions/budgets"
             }
           }
-        },
-        {
-          "$ref": "#/definitions/alert"
         }
-      ]
-    },
-    "gettableAlerts": {
-      "type": "array",
-      "items": {
-        "$ref": "#/definitions/gettableAlert"
       }
     },
-    "gettableSilence": {
-      "allOf": [
+    "/deadletter/{deadLetterID}": {
+      "get": {
+        "description": "Get a dead letter by its ID",
+        "tags": [
+          "deadletter"
+        ],
+        "operationId": "getDeadLetter",
+        "responses": {
+          "200": {
+            "description": "Get dead letter response",
+            "schema": {
+              "$ref": "#/definitions/deadLetter"
+            }
+          },
+          "404": {
+            "description": "A dead letter with the specified ID was not found"
+          }
+        }
+      },
+      "delete": {
+        "description": "Delete a dead letter by its ID without sending it",
+        "tags": [
+          "deadletter"
+        ],
+        "operationId": "deleteDeadLetter",
+        "responses": {
+          "200": {
+            "description": "Delete dead letter response"
+          },
+          "404": {
+            "description": "A dead letter with the specified ID was not found"
+          }
+        }
+      },
+      "parameters": [
         {
-          "type": "object",
-          "required": [
-            "id",
-            "status",
-            "updatedAt"
-          ],
-          "properties": {
-            "id": {
+          "type": "string",
+          "format": "uuid",
+          "description": "ID of the dead letter",
+          "name": "deadLetterID",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/deadletter/{deadLetterID}/replay": {
+      "post": {
+        "description": "Send a dead letter to its integration again. The dead letter is removed on success.",
+        "tags": [
+          "deadletter"
+        ],
+        "operationId": "replayDeadLetter",
+        "responses": {
+          "200": {
+            "description": "Replay dead letter response"
+          },
+          "404": {
+            "description": "A dead letter with the specified ID was not found"
+          },
+          "500": {
+            "description": "Internal server error",
+            "schema": {
               "type": "string"
-            },
-            "status": {
-              "$ref": "#/definitions/silenceStatus"
-            },
-            "updatedAt": {
-              "type": "string",
-              "format": "date-time"
             }
           }
-        },
+        }
+      },
+      "parameters": [
         {
-          "$ref": "#/definitions/silence"
+          "type": "string",
+          "format": "uuid",
+          "description": "ID of the dead letter",
+          "name": "deadLetterID",
+          "in": "path",
+          "required": true
         }
       ]
     },
-    "gettableSilences": {
-      "type": "array",
-      "items": {
-        "$ref": "#/definitions/gettableSilence"
-      }
-    },
-    "labelSet": {
-      "type": "object",
-      "additionalProperties": {
-        "type": "string"
+    "/deadletters": {
+      "get": {
+        "description": "Get a list of notifications that could not be delivered",
+        "tags": [
+          "deadletter"
+        ],
+        "operationId": "getDeadLetters",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Only return dead letters of the given receiver",
+            "name": "receiver",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get dead letters response",
+            "schema": {
+              "$ref": "#/definitions/deadLetters"
+            }
+          }
+        }
       }
     },
-    "matcher": {
-      "type": "object",
-      "required": [
-        "name",
-        "value",
-        "isRegex"
-      ],
-      "properties": {
-        "isEqual": {
-          "type": "boolean",
-          "default": true
-        },
-        "isRegex": {
-          "type": "boolean"
-        },
-        "name": {
-          "type": "string"
-        },
-        "value": {
-          "type": "string"
+    "/history": {
+      "get": {
+        "description": "Get the recorded attempts to send notifications, oldest first",
+        "tags": [
+          "receiver"
+        ],
+        "operationId": "getNotificationHistory",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Only return attempts of the given receiver",
+            "name": "receiver",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only return attempts which started at or after the given time",
+            "name": "since",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only return attempts which started at or before the given time",
+            "name": "until",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get notification history response",
+            "schema": {
+              "$ref": "#/definitions/notificationHistory"
+            }
+          }
         }
       }
     },
-    "matchers": {
-      "type": "array",
-      "minItems": 1,
-      "items": {
-        "$ref": "#/definitions/matcher"
+    "/receivers": {
+      "get": {
+        "description": "Get list of all receivers along with the types of their integrations",
+        "tags": [
+          "receiver"
+        ],
+        "operationId": "getReceivers",
+        "responses": {
+          "200": {
+            "description": "Get receivers response",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/receiver"
+              }
+            }
+          }
+        }
       }
     },
-    "peerStatus": {
-      "type": "object",
-      "required": [
-        "name",
-        "address"
-      ],
-      "properties": {
-        "address": {
-          "type": "string"
-        },
-        "name": {
-          "type": "string"
+    "/receivers/health": {
+      "get": {
+        "description": "Get the health of the integrations of all receivers, summarized from their notification attempts",
+        "tags": [
+          "receiver"
+        ],
+        "operationId": "getReceiversHealth",
+        "responses": {
+          "200": {
+            "description": "Get receivers health response",
+            "schema": {
+              "$ref": "#/definitions/receiversHealth"
+            }
+          }
         }
       }
     },
-    "postableAlert": {
-      "allOf": [
+    "/receivers/{name}/render": {
+      "post": {
+        "description": "Render the notifications each integration of the receiver would send for the given alerts, without sending them",
+        "tags": [
+          "receiver"
+        ],
+        "operationId": "renderReceiver",
+        "parameters": [
+          {
+            "description": "The alerts to render the notifications for",
+            "name": "alerts",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/postableAlerts"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Render receiver response",
+            "schema": {
+              "$ref": "#/definitions/renderedNotifications"
+            }
+          },
+          "400": {
+            "description": "Bad request",
+            "schema": {
+              "type": "string"
+            }
+          },
+          "404": {
+            "description": "A receiver with the specified name was not found or is not used by any route"
+          }
+        }
+      },
+      "parameters": [
         {
-          "type": "object",
-          "properties": {
-            "annotations": {
-              "$ref": "#/definitions/labelSet"
-            },
-            "endsAt": {
-              "type": "string",
-              "format": "date-time"
-            },
-            "startsAt": {
-              "type": "string",
-              "format": "date-time"
+          "type": "string",
+          "description": "Name of the receiver",
+          "name": "name",
+          "in": "path",
+          "required": true
+        }
+      ]
+    },
+    "/receivers/{name}/test": {
+      "post": {
+        "description": "Send a test alert once through each integration of the receiver",
+        "tags": [
+          "receiver"
+        ],
+        "operationId": "testReceiver",
+        "responses": {
+          "200": {
+            "description": "Test receiver response",
+            "schema": {
+              "$ref": "#/definitions/integrationTestResults"
             }
+          },
+          "404": {
+            "description": "A receiver with the specified name was not found or is not used by any route"
           }
-        },
+        }
+      },
+      "parameters": [
         {
-          "$ref": "#/definitions/alert"
+          "type": "string",
+          "description": "Name of the receiver",
+          "name": "name",
+          "in": "path",
+          "required": true
         }
       ]
     },
-    "postableAlerts": {
-      "type": "array",
-      "items": {
-        "$ref": "#/definitions/postableAlert"
-      }
-    },
-    "postableSilence": {
-      "allOf": [
-        {
-          "type": "object",
-          "properties": {
-            "id": {
+    "/silence/{silenceID}": {
+      "get": {
+        "description": "Get a silence by its ID",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "getSilence",
+        "responses": {
+          "200": {
+            "description": "Get silence response",
+            "schema": {
+              "$ref": "#/definitions/gettableSilence"
+            }
+          },
+          "404": {
+            "description": "A silence with the specified ID was not found"
+          },
+          "500": {
+            "description": "Internal server error",
+            "schema": {
               "type": "string"
             }
           }
-        },
-        {
-          "$ref": "#/definitions/silence"
         }
-      ]
-    },
-    "receiver": {
-      "type": "object",
-      "required": [
-        "name"
-      ],
-      "properties": {
-        "name": {
-          "type": "string"
+      },
+      "delete": {
+        "description": "Delete a silence by its ID",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "deleteSilence",
+        "parameters": [
+          {
+            "type": "string",
+            "format": "uuid",
+            "description": "ID of the silence to get",
+            "name": "silenceID",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "The user expiring the silence, recorded in the silence events",
+            "name": "expiredBy",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Delete silence response"
+          },
+          "404": {
+            "description": "A silence with the specified ID was not found"
+          },
+          "500": {
+            "description": "Internal server error",
+            "schema": {
+              "type": "string"
+            }
+          }
         }
-      }
-    },
-    "silence": {
-      "type": "object",
-      "required": [
-        "matchers",
-        "startsAt",
-        "endsAt",
-        "createdBy",
-        "comment"
-      ],
-      "properties": {
-        "comment": {
-          "type": "string"
-        },
-        "createdBy": {
-          "type": "string"
-        },
-        "endsAt": {
-          "type": "string",
-          "format": "date-time"
-        },
-        "matchers": {
-          "$ref": "#/definitions/matchers"
-        },
-        "startsAt": {
+      },
+      "parameters": [
+        {
           "type": "string",
-          "format": "date-time"
+          "format": "uuid",
+          "description": "ID of the silence to get",
+          "name": "silenceID",
+          "in": "path",
+          "required": true
         }
-      }
+      ]
     },
-    "silenceStatus": {
-      "type": "object",
-      "required": [
-        "state"
-      ],
-      "properties": {
-        "state": {
-          "type": "string",
-          "enum": [
-            "expired",
-            "active",
-            "pending"
-          ]
+    "/silence/{silenceID}/occurrences": {
+      "get": {
+        "description": "Get the current and upcoming occurrences of a silence",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "getSilenceOccurrences",
+        "parameters": [
+          {
+            "maximum": 100,
+            "minimum": 1,
+            "type": "integer",
+            "default": 10,
+            "description": "The maximum number of occurrences to return",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Get silence occurrences response",
+            "schema": {
+              "$ref": "#/definitions/silenceOccurrences"
+            }
+          },
+          "404": {
+            "description": "A silence with the specified ID was not found"
+          },
+          "500": {
+            "description": "Internal server error",
+            "schema": {
+              "type": "string"
+            }
+          }
         }
-      }
-    },
-    "versionInfo": {
-      "type": "object",
-      "required": [
-        "version",
-        "revision",
-        "branch",
-        "buildUser",
-        "buildDate",
-        "goVersion"
-      ],
-      "properties": {
-        "branch": {
-          "type": "string"
-        },
-        "buildDate": {
-          "type": "string"
-        },
-        "buildUser": {
-          "type": "string"
-        },
-        "goVersion": {
-          "type": "string"
-        },
-        "revision": {
-          "type": "string"
-        },
-        "version": {
-          "type": "string"
+      },
+      "parameters": [
+        {
+          "type": "string",
+          "format": "uuid",
+          "description": "ID of the silence",
+          "name": "silenceID",
+          "in": "path",
+          "required": true
         }
-      }
-    }
-  },
-  "responses": {
-    "BadRequest": {
-      "description": "Bad request",
-      "schema": {
-        "type": "string"
-      }
-    },
-    "InternalServerError": {
-      "description": "Internal server error",
-      "schema": {
-        "type": "string"
-      }
-    }
-  },
-  "tags": [
-    {
-      "description": "General Alertmanager operations",
-      "name": "general"
-    },
-    {
-      "description": "Everything related to Alertmanager receivers",
-      "name": "receiver"
-    },
-    {
-      "description": "Everything related to Alertmanager silences",
-      "name": "silence"
-    },
-    {
-      "description": "Everything related to Alertmanager alerts",
-      "name": "alert"
-    }
-  ]
-}`))
-	FlatSwaggerJSON = json.RawMessage([]byte(`{
-  "consumes": [
-    "application/json"
-  ],
-  "produces": [
-    "application/json"
-  ],
-  "swagger": "2.0",
-  "info": {
-    "description": "API of the Prometheus Alertmanager (https://github.com/prometheus/alertmanager)",
-    "title": "Alertmanager API",
-    "license": {
-      "name": "Apache 2.0",
-      "url": "http://www.apache.org/licenses/LICENSE-2.0.html"
+      ]
     },
-    "version": "0.0.1"
-  },
-  "paths": {
-    "/alerts": {
+    "/silences": {
       "get": {
-        "description": "Get a list of alerts",
+        "description": "Get a list of silences",
         "tags": [
-          "alert"
+          "silence"
         ],
-        "operationId": "getAlerts",
+        "operationId": "getSilences",
         "parameters": [
           {
-            "type": "boolean",
-            "default": true,
-            "description": "Show active alerts",
-            "name": "active",
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of matchers to filter silences by",
+            "name": "filter",
             "in": "query"
           },
           {
-            "type": "boolean",
-            "default": true,
-            "description": "Show silenced alerts",
-            "name": "silenced",
+            "type": "array",
+            "items": {
+              "enum": [
+                "expired",
+                "active",
+                "pending"
+              ],
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of states to filter silences by",
+            "name": "state",
             "in": "query"
           },
           {
-            "type": "boolean",
-            "default": true,
-            "description": "Show inhibited alerts",
-            "name": "inhibited",
+            "type": "string",
+            "description": "Show only silences created by the given author",
+            "name": "createdBy",
             "in": "query"
           },
           {
-            "type": "boolean",
-            "default": true,
-            "description": "Show unprocessed alerts",
-            "name": "unprocessed",
+            "type": "string",
+            "format": "date-time",
+            "description": "Show only silences ending at or after the given time",
+            "name": "since",
             "in": "query"
           },
           {
-            "type": "array",
-            "items": {
-              "type": "string"
-            },
-            "collectionFormat": "multi",
-            "description": "A list of matchers to filter alerts by",
-            "name": "filter",
+            "type": "string",
+            "format": "date-time",
+            "description": "Show only silences starting at or before the given time",
+            "name": "until",
             "in": "query"
           },
           {
-            "type": "string",
-            "description": "A regex matching receivers to filter alerts by",
-            "name": "receiver",
+            "minimum": 0,
+            "type": "integer",
+            "default": 0,
+            "description": "Number of silences to skip",
+            "name": "offset",
+            "in": "query"
+          },
+          {
+            "minimum": 0,
+            "type": "integer",
+            "default": 0,
+            "description": "Maximum number of silences to return. 0 returns all of them.",
+            "name": "limit",
             "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "Get alerts response",
+            "description": "Get silences response",
             "schema": {
-              "$ref": "#/definitions/gettableAlerts"
+              "$ref": "#/definitions/gettableSilences"
+            },
+            "headers": {
+              "X-Total-Count": {
+                "type": "integer",
+                "description": "The number of silences matching the filters, regardless of limit and offset"
+              }
             }
           },
           "400": {
@@ -907,25 +3260,33 @@ func init() {
         }
       },
       "post": {
-        "description": "Create new Alerts",
+        "description": "Post a new silence or update an existing one",
         "tags": [
-          "alert"
+          "silence"
         ],
-        "operationId": "postAlerts",
+        "operationId": "postSilences",
         "parameters": [
           {
-            "description": "The alerts to create",
-            "name": "alerts",
+            "description": "The silence to create",
+            "name": "silence",
             "in": "body",
             "required": true,
             "schema": {
-              "$ref": "#/definitions/postableAlerts"
+              "$ref": "#/definitions/postableSilence"
             }
           }
         ],
         "responses": {
           "200": {
-            "description": "Create alerts response"
+            "description": "Create / update silence response",
+            "schema": {
+              "type": "object",
+              "properties": {
+                "silenceID": {
+                  "type": "string"
+                }
+              }
+            }
           },
           "400": {
             "description": "Bad request",
@@ -933,8 +3294,8 @@ func init() {
               "type": "string"
             }
           },
-          "500": {
-            "description": "Internal server error",
+          "404": {
+            "description": "A silence with the specified ID was not found",
             "schema": {
               "type": "string"
             }
@@ -942,57 +3303,74 @@ func init() {
         }
       }
     },
-    "/alerts/groups": {
+    "/silences/events": {
       "get": {
-        "description": "Get a list of alert groups",
+        "description": "Get the creations, modifications and expiries of silences, oldest first",
         "tags": [
-          "alertgroup"
+          "silence"
         ],
-        "operationId": "getAlertGroups",
+        "operationId": "getSilenceEvents",
         "parameters": [
           {
-            "type": "boolean",
-            "default": true,
-            "description": "Show active alerts",
-            "name": "active",
-            "in": "query"
-          },
-          {
-            "type": "boolean",
-            "default": true,
-            "description": "Show silenced alerts",
-            "name": "silenced",
+            "type": "string",
+            "description": "Only return events of the given silence",
+            "name": "silenceID",
             "in": "query"
           },
           {
-            "type": "boolean",
-            "default": true,
-            "description": "Show inhibited alerts",
-            "name": "inhibited",
+            "type": "string",
+            "description": "Only return events made by the given user",
+            "name": "user",
             "in": "query"
           },
           {
-            "type": "array",
-            "items": {
-              "type": "string"
-            },
-            "collectionFormat": "multi",
-            "description": "A list of matchers to filter alerts by",
-            "name": "filter",
+            "type": "string",
+            "format": "date-time",
+            "description": "Only return events which happened at or after the given time",
+            "name": "since",
             "in": "query"
           },
           {
             "type": "string",
-            "description": "A regex matching receivers to filter alerts by",
-            "name": "receiver",
+            "format": "date-time",
+            "description": "Only return events which happened at or before the given time",
+            "name": "until",
             "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "Get alert groups response",
+            "description": "Get silence events response",
             "schema": {
-              "$ref": "#/definitions/alertGroups"
+              "$ref": "#/definitions/silenceEvents"
+            }
+          }
+        }
+      }
+    },
+    "/silences/expire": {
+      "post": {
+        "description": "Expire all active and pending silences matching a filter",
+        "tags": [
+          "silence"
+        ],
+        "operationId": "expireSilences",
+        "parameters": [
+          {
+            "description": "The silences to expire",
+            "name": "request",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/bulkSilenceRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Expire silences response",
+            "schema": {
+              "$ref": "#/definitions/bulkSilenceResult"
             }
           },
           "400": {
@@ -1010,114 +3388,101 @@ func init() {
         }
       }
     },
-    "/receivers": {
+    "/silences/presets": {
       "get": {
-        "description": "Get list of all receivers (name of notification integrations)",
+        "description": "Get the silence presets of the configuration",
         "tags": [
-          "receiver"
+          "silence"
         ],
-        "operationId": "getReceivers",
+        "operationId": "getSilencePresets",
         "responses": {
           "200": {
-            "description": "Get receivers response",
+            "description": "Get silence presets response",
             "schema": {
-              "type": "array",
-              "items": {
-                "$ref": "#/definitions/receiver"
-              }
+              "$ref": "#/definitions/silencePresets"
             }
           }
         }
       }
     },
-    "/silence/{silenceID}": {
-      "get": {
-        "description": "Get a silence by its ID",
-        "tags": [
-          "silence"
-        ],
-        "operationId": "getSilence",
-        "responses": {
-          "200": {
-            "description": "Get silence response",
-            "schema": {
-              "$ref": "#/definitions/gettableSilence"
-            }
-          },
-          "404": {
-            "description": "A silence with the specified ID was not found"
-          },
-          "500": {
-            "description": "Internal server error",
-            "schema": {
-              "type": "string"
-            }
-          }
-        }
-      },
-      "delete": {
-        "description": "Delete a silence by its ID",
+    "/silences/presets/{presetName}": {
+      "post": {
+        "description": "Create a silence from a silence preset",
         "tags": [
           "silence"
         ],
-        "operationId": "deleteSilence",
+        "operationId": "postSilencePreset",
         "parameters": [
           {
             "type": "string",
-            "format": "uuid",
-            "description": "ID of the silence to get",
-            "name": "silenceID",
+            "description": "Name of the silence preset",
+            "name": "presetName",
             "in": "path",
             "required": true
+          },
+          {
+            "description": "The parameters of the silence",
+            "name": "instantiation",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/silencePresetInstantiation"
+            }
           }
         ],
         "responses": {
           "200": {
-            "description": "Delete silence response"
+            "description": "Create silence response",
+            "schema": {
+              "type": "object",
+              "properties": {
+                "silenceID": {
+                  "type": "string"
+                }
+              }
+            }
           },
-          "500": {
-            "description": "Internal server error",
+          "400": {
+            "description": "Bad request",
             "schema": {
               "type": "string"
             }
+          },
+          "404": {
+            "description": "A silence preset with the specified name was not found"
           }
         }
-      },
-      "parameters": [
-        {
-          "type": "string",
-          "format": "uuid",
-          "description": "ID of the silence to get",
-          "name": "silenceID",
-          "in": "path",
-          "required": true
-        }
-      ]
+      }
     },
-    "/silences": {
-      "get": {
-        "description": "Get a list of silences",
+    "/silences/recreate": {
+      "post": {
+        "description": "Re-create all expired silences matching a filter, starting now",
         "tags": [
           "silence"
         ],
-        "operationId": "getSilences",
+        "operationId": "recreateSilences",
         "parameters": [
           {
-            "type": "array",
-            "items": {
-              "type": "string"
-            },
-            "collectionFormat": "multi",
-            "description": "A list of matchers to filter silences by",
-            "name": "filter",
-            "in": "query"
+            "description": "The silences to re-create",
+            "name": "request",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/bulkSilenceRequest"
+            }
           }
         ],
         "responses": {
           "200": {
-            "description": "Get silences response",
+            "description": "Re-create silences response",
             "schema": {
-              "$ref": "#/definitions/gettableSilences"
+              "$ref": "#/definitions/bulkSilenceResult"
+            }
+          },
+          "400": {
+            "description": "Bad request",
+            "schema": {
+              "type": "string"
             }
           },
           "500": {
@@ -1127,34 +3492,42 @@ func init() {
             }
           }
         }
-      },
-      "post": {
-        "description": "Post a new silence or update an existing one",
+      }
+    },
+    "/stats": {
+      "get": {
+        "description": "Get the number of alerts by alertname, severity and receiver over recent windows",
         "tags": [
-          "silence"
+          "alert"
         ],
-        "operationId": "postSilences",
+        "operationId": "getAlertStats",
         "parameters": [
           {
-            "description": "The silence to create",
-            "name": "silence",
-            "in": "body",
-            "required": true,
-            "schema": {
-              "$ref": "#/definitions/postableSilence"
-            }
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "The windows to count the alerts firing in, e.g. 1h. Defaults to 1h and 24h.",
+            "name": "window",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi",
+            "description": "A list of matchers to filter alerts by",
+            "name": "filter",
+            "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "Create / update silence response",
+            "description": "Get alert stats response",
             "schema": {
-              "type": "object",
-              "properties": {
-                "silenceID": {
-                  "type": "string"
-                }
-              }
+              "$ref": "#/definitions/alertStats"
             }
           },
           "400": {
@@ -1163,8 +3536,8 @@ func init() {
               "type": "string"
             }
           },
-          "404": {
-            "description": "A silence with the specified ID was not found",
+          "500": {
+            "description": "Internal server error",
             "schema": {
               "type": "string"
             }
@@ -1191,6 +3564,40 @@ func init() {
     }
   },
   "definitions": {
+    "ack": {
+      "allOf": [
+        {
+          "type": "object",
+          "required": [
+            "id",
+            "startsAt",
+            "updatedAt"
+          ],
+          "properties": {
+            "id": {
+              "type": "string"
+            },
+            "startsAt": {
+              "type": "string",
+              "format": "date-time"
+            },
+            "updatedAt": {
+              "type": "string",
+              "format": "date-time"
+            }
+          }
+        },
+        {
+          "$ref": "#/definitions/postableAck"
+        }
+      ]
+    },
+    "acks": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/ack"
+      }
+    },
     "alert": {
       "type": "object",
       "required": [
@@ -1206,6 +3613,35 @@ func init() {
         }
       }
     },
+    "alertCounts": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "integer"
+      }
+    },
+    "alertEvent": {
+      "type": "object",
+      "required": [
+        "type",
+        "alert"
+      ],
+      "properties": {
+        "alert": {
+          "$ref": "#/definitions/gettableAlert"
+        },
+        "type": {
+          "type": "string",
+          "enum": [
+            "new",
+            "updated",
+            "resolved",
+            "silenced",
+            "inhibited",
+            "active"
+          ]
+        }
+      }
+    },
     "alertGroup": {
       "type": "object",
       "required": [
@@ -1214,15 +3650,31 @@ func init() {
         "alerts"
       ],
       "properties": {
+        "acknowledgement": {
+          "$ref": "#/definitions/ack"
+        },
         "alerts": {
           "type": "array",
           "items": {
             "$ref": "#/definitions/gettableAlert"
           }
         },
+        "groupId": {
+          "description": "The SHA-256 hash of the group key",
+          "type": "string"
+        },
+        "groupKey": {
+          "description": "The key identifying the group, also sent in its notifications",
+          "type": "string"
+        },
         "labels": {
           "$ref": "#/definitions/labelSet"
         },
+        "nextFlush": {
+          "description": "The time at which the group is next flushed to its receiver",
+          "type": "string",
+          "format": "date-time"
+        },
         "receiver": {
           "$ref": "#/definitions/receiver"
         }
@@ -1234,6 +3686,67 @@ func init() {
         "$ref": "#/definitions/alertGroup"
       }
     },
+    "alertSource": {
+      "description": "The client which sent the alert",
+      "type": "object",
+      "properties": {
+        "address": {
+          "description": "The IP address of the client",
+          "type": "string"
+        },
+        "identity": {
+          "description": "The authenticated identity of the client",
+          "type": "string"
+        },
+        "name": {
+          "description": "The name of the source set by the client",
+          "type": "string"
+        }
+      }
+    },
+    "alertStats": {
+      "type": "object",
+      "required": [
+        "windows"
+      ],
+      "properties": {
+        "windows": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/alertStatsWindow"
+          }
+        }
+      }
+    },
+    "alertStatsWindow": {
+      "type": "object",
+      "required": [
+        "window",
+        "total",
+        "byAlertname",
+        "bySeverity",
+        "byReceiver"
+      ],
+      "properties": {
+        "byAlertname": {
+          "$ref": "#/definitions/alertCounts"
+        },
+        "byReceiver": {
+          "$ref": "#/definitions/alertCounts"
+        },
+        "bySeverity": {
+          "$ref": "#/definitions/alertCounts"
+        },
+        "total": {
+          "description": "The number of alerts firing at any time in the window",
+          "type": "integer"
+        },
+        "window": {
+          "description": "The duration of the window, ending now",
+          "type": "string"
+        }
+      }
+    },
     "alertStatus": {
       "type": "object",
       "required": [
@@ -1299,6 +3812,101 @@ func init() {
         }
       }
     },
+    "budget": {
+      "type": "object",
+      "required": [
+        "scope",
+        "name",
+        "window",
+        "limit",
+        "used",
+        "resetsAt",
+        "queued"
+      ],
+      "properties": {
+        "limit": {
+          "description": "The maximum number of notifications in the window",
+          "type": "integer"
+        },
+        "name": {
+          "description": "The name of the receiver or of the tenant",
+          "type": "string"
+        },
+        "queued": {
+          "description": "The number of alerts queued for the digest sent once the budget resets",
+          "type": "integer"
+        },
+        "resetsAt": {
+          "description": "The end of the current window",
+          "type": "string",
+          "format": "date-time"
+        },
+        "scope": {
+          "description": "receiver or tenant",
+          "type": "string"
+        },
+        "used": {
+          "description": "The number of notifications counted against the budget in the current window",
+          "type": "integer"
+        },
+        "window": {
+          "description": "daily or weekly",
+          "type": "string"
+        }
+      }
+    },
+    "budgets": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/budget"
+      }
+    },
+    "bulkSilenceRequest": {
+      "type": "object",
+      "properties": {
+        "createdBy": {
+          "description": "Only select the silences created by the given author",
+          "type": "string"
+        },
+        "dryRun": {
+          "description": "Only return the selected silences without changing them",
+          "type": "boolean"
+        },
+        "duration": {
+          "description": "The duration of the re-created silences, e.g. 4h",
+          "type": "string"
+        },
+        "filter": {
+          "description": "Matchers which the silences must have",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "user": {
+          "description": "The user performing the operation",
+          "type": "string"
+        }
+      }
+    },
+    "bulkSilenceResult": {
+      "type": "object",
+      "required": [
+        "silences"
+      ],
+      "properties": {
+        "recreated": {
+          "description": "The IDs of the re-created silences, keyed by the ID of the silence they were re-created from",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
+        "silences": {
+          "$ref": "#/definitions/gettableSilences"
+        }
+      }
+    },
     "clusterStatus": {
       "type": "object",
       "required": [
@@ -1324,6 +3932,63 @@ func init() {
         }
       }
     },
+    "deadLetter": {
+      "type": "object",
+      "required": [
+        "id",
+        "receiver",
+        "integration",
+        "groupKey",
+        "groupLabels",
+        "alerts",
+        "error",
+        "createdAt",
+        "replays"
+      ],
+      "properties": {
+        "alerts": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/postableAlert"
+          }
+        },
+        "createdAt": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "error": {
+          "type": "string"
+        },
+        "groupKey": {
+          "type": "string"
+        },
+        "groupLabels": {
+          "$ref": "#/definitions/labelSet"
+        },
+        "id": {
+          "type": "string"
+        },
+        "integration": {
+          "type": "string"
+        },
+        "lastReplayAt": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "receiver": {
+          "type": "string"
+        },
+        "replays": {
+          "type": "integer"
+        }
+      }
+    },
+    "deadLetters": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/deadLetter"
+      }
+    },
     "gettableAlert": {
       "allOf": [
         {
@@ -1354,6 +4019,9 @@ func init() {
                 "$ref": "#/definitions/receiver"
               }
             },
+            "source": {
+              "$ref": "#/definitions/alertSource"
+            },
             "startsAt": {
               "type": "string",
               "format": "date-time"
@@ -1411,6 +4079,104 @@ func init() {
         "$ref": "#/definitions/gettableSilence"
       }
     },
+    "integration": {
+      "type": "object",
+      "required": [
+        "name",
+        "index",
+        "sendResolved"
+      ],
+      "properties": {
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "name": {
+          "description": "The type of the integration, e.g. email or slack",
+          "type": "string"
+        },
+        "sendResolved": {
+          "type": "boolean"
+        }
+      }
+    },
+    "integrationHealth": {
+      "type": "object",
+      "required": [
+        "name",
+        "index",
+        "state",
+        "consecutiveFailures"
+      ],
+      "properties": {
+        "consecutiveFailures": {
+          "description": "The number of attempts which failed since the last successful one",
+          "type": "integer"
+        },
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "lastError": {
+          "description": "The error of the last failed attempt",
+          "type": "string"
+        },
+        "lastFailure": {
+          "description": "The time of the last failed attempt",
+          "type": "string",
+          "format": "date-time"
+        },
+        "lastSuccess": {
+          "description": "The time of the last successful attempt",
+          "type": "string",
+          "format": "date-time"
+        },
+        "lastThrottled": {
+          "description": "The last time the receiver answered that the integration sent too many requests",
+          "type": "string",
+          "format": "date-time"
+        },
+        "name": {
+          "description": "The type of the integration, e.g. email or slack",
+          "type": "string"
+        },
+        "state": {
+          "description": "unknown if the integration didn't attempt to send a notification yet, healthy if its last attempt succeeded and failing otherwise",
+          "type": "string"
+        }
+      }
+    },
+    "integrationTestResult": {
+      "type": "object",
+      "required": [
+        "integration",
+        "index",
+        "success"
+      ],
+      "properties": {
+        "error": {
+          "description": "The error returned by the integration if the test failed",
+          "type": "string"
+        },
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "integration": {
+          "description": "The type of the integration, e.g. email or slack",
+          "type": "string"
+        },
+        "success": {
+          "type": "boolean"
+        }
+      }
+    },
+    "integrationTestResults": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/integrationTestResult"
+      }
+    },
     "labelSet": {
       "type": "object",
       "additionalProperties": {
@@ -1447,6 +4213,71 @@ func init() {
         "$ref": "#/definitions/matcher"
       }
     },
+    "notificationHistory": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/notificationHistoryEntry"
+      }
+    },
+    "notificationHistoryEntry": {
+      "type": "object",
+      "required": [
+        "time",
+        "receiver",
+        "integration",
+        "index",
+        "groupKey",
+        "groupLabels",
+        "fingerprints",
+        "attempt",
+        "outcome"
+      ],
+      "properties": {
+        "attempt": {
+          "description": "The number of the attempt, starting at 1",
+          "type": "integer"
+        },
+        "error": {
+          "type": "string"
+        },
+        "fingerprints": {
+          "description": "The fingerprints of the alerts of the notification",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "groupKey": {
+          "type": "string"
+        },
+        "groupLabels": {
+          "$ref": "#/definitions/labelSet"
+        },
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "integration": {
+          "type": "string"
+        },
+        "outcome": {
+          "description": "succeeded, failed or aborted",
+          "type": "string"
+        },
+        "receiver": {
+          "type": "string"
+        },
+        "statusCode": {
+          "description": "The status code answered by the receiver to a failed attempt",
+          "type": "integer"
+        },
+        "time": {
+          "description": "The time the attempt started",
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
     "peerStatus": {
       "type": "object",
       "required": [
@@ -1462,6 +4293,29 @@ func init() {
         }
       }
     },
+    "postableAck": {
+      "type": "object",
+      "required": [
+        "groupKey",
+        "ackedBy",
+        "endsAt"
+      ],
+      "properties": {
+        "ackedBy": {
+          "type": "string"
+        },
+        "comment": {
+          "type": "string"
+        },
+        "endsAt": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "groupKey": {
+          "type": "string"
+        }
+      }
+    },
     "postableAlert": {
       "allOf": [
         {
@@ -1474,6 +4328,10 @@ func init() {
               "type": "string",
               "format": "date-time"
             },
+            "source": {
+              "description": "The name of the source of the alert, which routes can match with the __source__ pseudo-label",
+              "type": "string"
+            },
             "startsAt": {
               "type": "string",
               "format": "date-time"
@@ -1512,8 +4370,115 @@ func init() {
         "name"
       ],
       "properties": {
+        "integrations": {
+          "description": "The integrations of the receiver, only set by the receivers endpoint",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/integration"
+          },
+          "x-omitempty": true
+        },
+        "name": {
+          "type": "string"
+        }
+      }
+    },
+    "receiverHealth": {
+      "type": "object",
+      "required": [
+        "name",
+        "state",
+        "deadLetters",
+        "integrations"
+      ],
+      "properties": {
+        "deadLetters": {
+          "description": "The number of notifications of the receiver in the dead-letter queue",
+          "type": "integer"
+        },
+        "integrations": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/integrationHealth"
+          }
+        },
         "name": {
           "type": "string"
+        },
+        "state": {
+          "description": "failing if one of the integrations is failing, healthy if one of them is healthy and unknown otherwise",
+          "type": "string"
+        }
+      }
+    },
+    "receiversHealth": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/receiverHealth"
+      }
+    },
+    "renderedNotification": {
+      "type": "object",
+      "required": [
+        "integration",
+        "index",
+        "payloads"
+      ],
+      "properties": {
+        "error": {
+          "description": "The error which prevented the integration from rendering its notification",
+          "type": "string"
+        },
+        "index": {
+          "description": "The position of the integration among those of the same type in the receiver",
+          "type": "integer"
+        },
+        "integration": {
+          "description": "The type of the integration, e.g. email or slack",
+          "type": "string"
+        },
+        "payloads": {
+          "description": "The payloads of the requests the integration would send, in order",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/renderedPayload"
+          }
+        }
+      }
+    },
+    "renderedNotifications": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/renderedNotification"
+      }
+    },
+    "renderedPayload": {
+      "type": "object",
+      "required": [
+        "contentType",
+        "body"
+      ],
+      "properties": {
+        "body": {
+          "type": "string"
+        },
+        "contentType": {
+          "type": "string"
+        }
+      }
+    },
+    "resendRequest": {
+      "type": "object",
+      "required": [
+        "groupKey",
+        "receiver"
+      ],
+      "properties": {
+        "groupKey": {
+          "type": "string"
+        },
+        "receiver": {
+          "type": "string"
         }
       }
     },
@@ -1537,15 +4502,206 @@ func init() {
           "type": "string",
           "format": "date-time"
         },
+        "expiryNotification": {
+          "$ref": "#/definitions/silenceExpiryNotification"
+        },
         "matchers": {
           "$ref": "#/definitions/matchers"
         },
+        "schedule": {
+          "$ref": "#/definitions/silenceSchedule"
+        },
+        "startsAt": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "silenceChange": {
+      "type": "object",
+      "required": [
+        "field"
+      ],
+      "properties": {
+        "field": {
+          "type": "string"
+        },
+        "new": {
+          "type": "string"
+        },
+        "old": {
+          "type": "string"
+        }
+      }
+    },
+    "silenceEvent": {
+      "type": "object",
+      "required": [
+        "id",
+        "silenceID",
+        "type",
+        "time",
+        "changes"
+      ],
+      "properties": {
+        "changes": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/silenceChange"
+          }
+        },
+        "id": {
+          "type": "string"
+        },
+        "silenceID": {
+          "type": "string"
+        },
+        "time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "type": {
+          "type": "string",
+          "enum": [
+            "created",
+            "updated",
+            "expired",
+            "rescheduled"
+          ]
+        },
+        "user": {
+          "description": "The user who made the change, if known",
+          "type": "string"
+        }
+      }
+    },
+    "silenceEvents": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/silenceEvent"
+      }
+    },
+    "silenceExpiryNotification": {
+      "description": "A notification sent to a receiver shortly before the silence expires",
+      "type": "object",
+      "required": [
+        "receiver",
+        "before"
+      ],
+      "properties": {
+        "before": {
+          "description": "How long before the end of the silence the receiver is notified, e.g. 1h",
+          "type": "string"
+        },
+        "receiver": {
+          "description": "The name of the receiver to notify",
+          "type": "string"
+        }
+      }
+    },
+    "silenceOccurrence": {
+      "type": "object",
+      "required": [
+        "startsAt",
+        "endsAt"
+      ],
+      "properties": {
+        "endsAt": {
+          "type": "string",
+          "format": "date-time"
+        },
         "startsAt": {
           "type": "string",
           "format": "date-time"
         }
       }
     },
+    "silenceOccurrences": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/silenceOccurrence"
+      }
+    },
+    "silencePreset": {
+      "type": "object",
+      "required": [
+        "name",
+        "matchers",
+        "duration",
+        "comment"
+      ],
+      "properties": {
+        "comment": {
+          "description": "Comment template, executed with the parameters of an instantiation",
+          "type": "string"
+        },
+        "duration": {
+          "type": "string"
+        },
+        "matchers": {
+          "description": "Matcher templates, executed with the parameters of an instantiation",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "name": {
+          "type": "string"
+        }
+      }
+    },
+    "silencePresetInstantiation": {
+      "type": "object",
+      "required": [
+        "createdBy"
+      ],
+      "properties": {
+        "createdBy": {
+          "type": "string"
+        },
+        "duration": {
+          "description": "Overrides the duration of the preset, e.g. 4h",
+          "type": "string"
+        },
+        "parameters": {
+          "description": "The values used in the templates of the preset",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "silencePresets": {
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/silencePreset"
+      }
+    },
+    "silenceSchedule": {
+      "description": "A schedule making the silence recur. The start and end time of a recurring silence are set to its current or next occurrence.",
+      "type": "object",
+      "required": [
+        "cron",
+        "duration"
+      ],
+      "properties": {
+        "cron": {
+          "description": "A cron expression for the start of the occurrences, e.g. \"0 22 * * SAT\". It is evaluated in UTC unless prefixed with CRON_TZ=\u003clocation\u003e.",
+          "type": "string"
+        },
+        "duration": {
+          "description": "The duration of each occurrence, e.g. 4h",
+          "type": "string"
+        },
+        "until": {
+          "description": "No occurrence starts after this time if it is set",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        }
+      }
+    },
     "silenceStatus": {
       "type": "object",
       "required": [
@@ -1606,6 +4762,12 @@ func init() {
       "schema": {
         "type": "string"
       }
+    },
+    "TooManyRequests": {
+      "description": "Too many requests",
+      "schema": {
+        "type": "string"
+      }
     }
   },
   "tags": [
@@ -1624,6 +4786,14 @@ func init() {
     {
       "description": "Everything related to Alertmanager alerts",
       "name": "alert"
+    },
+    {
+      "description": "Everything related to notifications that could not be delivered",
+      "name": "deadletter"
+    },
+    {
+      "description": "Everything related to alert group acknowledgements",
+      "name": "ack"
     }
   ]
 }`))
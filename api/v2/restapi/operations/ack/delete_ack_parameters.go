@@ -0,0 +1,105 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+)
+
+// NewDeleteAckParams creates a new DeleteAckParams object
+// no default values defined in spec.
+func NewDeleteAckParams() DeleteAckParams {
+
+	return DeleteAckParams{}
+}
+
+// DeleteAckParams contains all the bound params for the delete ack operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters deleteAck
+type DeleteAckParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request `json:"-"`
+
+	/*ID of the acknowledgement
+	  Required: true
+	  In: path
+	*/
+	AckID strfmt.UUID
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls.
+//
+// To ensure default values, the struct must have been initialized with NewDeleteAckParams() beforehand.
+func (o *DeleteAckParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+
+	o.HTTPRequest = r
+
+	rAckID, rhkAckID, _ := route.Params.GetOK("ackID")
+	if err := o.bindAckID(rAckID, rhkAckID, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// bindAckID binds and validates parameter AckID from path.
+func (o *DeleteAckParams) bindAckID(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: true
+	// Parameter is provided by construction from the route
+
+	// Format: uuid
+	value, err := formats.Parse("uuid", raw)
+	if err != nil {
+		return errors.InvalidType("ackID", "path", "strfmt.UUID", raw)
+	}
+	o.AckID = *(value.(*strfmt.UUID))
+
+	if err := o.validateAckID(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateAckID carries on validations for parameter AckID
+func (o *DeleteAckParams) validateAckID(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("ackID", "path", "uuid", o.AckID.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
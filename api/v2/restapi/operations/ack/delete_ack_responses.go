@@ -0,0 +1,74 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+)
+
+// DeleteAckOKCode is the HTTP code returned for type DeleteAckOK
+const DeleteAckOKCode int = 200
+
+/*DeleteAckOK Delete acknowledgement response
+
+swagger:response deleteAckOK
+*/
+type DeleteAckOK struct {
+}
+
+// NewDeleteAckOK creates DeleteAckOK with default headers values
+func NewDeleteAckOK() *DeleteAckOK {
+
+	return &DeleteAckOK{}
+}
+
+// WriteResponse to the client
+func (o *DeleteAckOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(200)
+}
+
+// DeleteAckNotFoundCode is the HTTP code returned for type DeleteAckNotFound
+const DeleteAckNotFoundCode int = 404
+
+/*DeleteAckNotFound An active acknowledgement with the specified ID was not found
+
+swagger:response deleteAckNotFound
+*/
+type DeleteAckNotFound struct {
+}
+
+// NewDeleteAckNotFound creates DeleteAckNotFound with default headers values
+func NewDeleteAckNotFound() *DeleteAckNotFound {
+
+	return &DeleteAckNotFound{}
+}
+
+// WriteResponse to the client
+func (o *DeleteAckNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// GetAcksHandlerFunc turns a function with the right signature into a get acks handler
+type GetAcksHandlerFunc func(GetAcksParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetAcksHandlerFunc) Handle(params GetAcksParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetAcksHandler interface for that can handle valid get acks params
+type GetAcksHandler interface {
+	Handle(GetAcksParams) middleware.Responder
+}
+
+// NewGetAcks creates a new http.Handler for the get acks operation
+func NewGetAcks(ctx *middleware.Context, handler GetAcksHandler) *GetAcks {
+	return &GetAcks{Context: ctx, Handler: handler}
+}
+
+/*GetAcks swagger:route GET /acks ack getAcks
+
+Get a list of active alert group acknowledgements
+
+*/
+type GetAcks struct {
+	Context *middleware.Context
+	Handler GetAcksHandler
+}
+
+func (o *GetAcks) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewGetAcksParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
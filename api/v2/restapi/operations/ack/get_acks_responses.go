@@ -0,0 +1,75 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// GetAcksOKCode is the HTTP code returned for type GetAcksOK
+const GetAcksOKCode int = 200
+
+/*GetAcksOK Get acknowledgements response
+
+swagger:response getAcksOK
+*/
+type GetAcksOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload models.Acks `json:"body,omitempty"`
+}
+
+// NewGetAcksOK creates GetAcksOK with default headers values
+func NewGetAcksOK() *GetAcksOK {
+
+	return &GetAcksOK{}
+}
+
+// WithPayload adds the payload to the get acks o k response
+func (o *GetAcksOK) WithPayload(payload models.Acks) *GetAcksOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get acks o k response
+func (o *GetAcksOK) SetPayload(payload models.Acks) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetAcksOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if payload == nil {
+		// return empty array
+		payload = models.Acks{}
+	}
+
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
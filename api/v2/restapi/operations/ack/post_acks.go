@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// PostAcksHandlerFunc turns a function with the right signature into a post acks handler
+type PostAcksHandlerFunc func(PostAcksParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn PostAcksHandlerFunc) Handle(params PostAcksParams) middleware.Responder {
+	return fn(params)
+}
+
+// PostAcksHandler interface for that can handle valid post acks params
+type PostAcksHandler interface {
+	Handle(PostAcksParams) middleware.Responder
+}
+
+// NewPostAcks creates a new http.Handler for the post acks operation
+func NewPostAcks(ctx *middleware.Context, handler PostAcksHandler) *PostAcks {
+	return &PostAcks{Context: ctx, Handler: handler}
+}
+
+/*PostAcks swagger:route POST /acks ack postAcks
+
+Acknowledge an alert group, replacing any previous acknowledgement of the group
+
+*/
+type PostAcks struct {
+	Context *middleware.Context
+	Handler PostAcksHandler
+}
+
+func (o *PostAcks) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewPostAcksParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
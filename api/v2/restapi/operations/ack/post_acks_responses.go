@@ -0,0 +1,114 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ack
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// PostAcksOKCode is the HTTP code returned for type PostAcksOK
+const PostAcksOKCode int = 200
+
+/*PostAcksOK Create acknowledgement response
+
+swagger:response postAcksOK
+*/
+type PostAcksOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *models.Ack `json:"body,omitempty"`
+}
+
+// NewPostAcksOK creates PostAcksOK with default headers values
+func NewPostAcksOK() *PostAcksOK {
+
+	return &PostAcksOK{}
+}
+
+// WithPayload adds the payload to the post acks o k response
+func (o *PostAcksOK) WithPayload(payload *models.Ack) *PostAcksOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post acks o k response
+func (o *PostAcksOK) SetPayload(payload *models.Ack) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostAcksOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+// PostAcksBadRequestCode is the HTTP code returned for type PostAcksBadRequest
+const PostAcksBadRequestCode int = 400
+
+/*PostAcksBadRequest Bad request
+
+swagger:response postAcksBadRequest
+*/
+type PostAcksBadRequest struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewPostAcksBadRequest creates PostAcksBadRequest with default headers values
+func NewPostAcksBadRequest() *PostAcksBadRequest {
+
+	return &PostAcksBadRequest{}
+}
+
+// WithPayload adds the payload to the post acks bad request response
+func (o *PostAcksBadRequest) WithPayload(payload string) *PostAcksBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post acks bad request response
+func (o *PostAcksBadRequest) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostAcksBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // NewGetAlertsParams creates a new GetAlertsParams object
@@ -39,8 +40,11 @@ func NewGetAlertsParams() GetAlertsParams {
 		activeDefault = bool(true)
 
 		inhibitedDefault = bool(true)
+		limitDefault     = int64(0)
+		offsetDefault    = int64(0)
 
 		silencedDefault    = bool(true)
+		sortDefault        = string("fingerprint")
 		unprocessedDefault = bool(true)
 	)
 
@@ -49,8 +53,14 @@ func NewGetAlertsParams() GetAlertsParams {
 
 		Inhibited: &inhibitedDefault,
 
+		Limit: &limitDefault,
+
+		Offset: &offsetDefault,
+
 		Silenced: &silencedDefault,
 
+		Sort: &sortDefault,
+
 		Unprocessed: &unprocessedDefault,
 	}
 }
@@ -79,6 +89,18 @@ type GetAlertsParams struct {
 	  Default: true
 	*/
 	Inhibited *bool
+	/*Maximum number of alerts to return. 0 returns all of them.
+	  Minimum: 0
+	  In: query
+	  Default: 0
+	*/
+	Limit *int64
+	/*Number of alerts to skip
+	  Minimum: 0
+	  In: query
+	  Default: 0
+	*/
+	Offset *int64
 	/*A regex matching receivers to filter alerts by
 	  In: query
 	*/
@@ -88,6 +110,11 @@ type GetAlertsParams struct {
 	  Default: true
 	*/
 	Silenced *bool
+	/*The order of the returned alerts. Severities are ranked by the order of the global severity mapping.
+	  In: query
+	  Default: "fingerprint"
+	*/
+	Sort *string
 	/*Show unprocessed alerts
 	  In: query
 	  Default: true
@@ -121,6 +148,16 @@ func (o *GetAlertsParams) BindRequest(r *http.Request, route *middleware.Matched
 		res = append(res, err)
 	}
 
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qOffset, qhkOffset, _ := qs.GetOK("offset")
+	if err := o.bindOffset(qOffset, qhkOffset, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qReceiver, qhkReceiver, _ := qs.GetOK("receiver")
 	if err := o.bindReceiver(qReceiver, qhkReceiver, route.Formats); err != nil {
 		res = append(res, err)
@@ -131,6 +168,11 @@ func (o *GetAlertsParams) BindRequest(r *http.Request, route *middleware.Matched
 		res = append(res, err)
 	}
 
+	qSort, qhkSort, _ := qs.GetOK("sort")
+	if err := o.bindSort(qSort, qhkSort, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qUnprocessed, qhkUnprocessed, _ := qs.GetOK("unprocessed")
 	if err := o.bindUnprocessed(qUnprocessed, qhkUnprocessed, route.Formats); err != nil {
 		res = append(res, err)
@@ -212,6 +254,80 @@ func (o *GetAlertsParams) bindInhibited(rawData []string, hasKey bool, formats s
 	return nil
 }
 
+// bindLimit binds and validates parameter Limit from query.
+func (o *GetAlertsParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		// Default values have been previously initialized by NewGetAlertsParams()
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "int64", raw)
+	}
+	o.Limit = &value
+
+	if err := o.validateLimit(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateLimit carries on validations for parameter Limit
+func (o *GetAlertsParams) validateLimit(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("limit", "query", int64(*o.Limit), 0, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindOffset binds and validates parameter Offset from query.
+func (o *GetAlertsParams) bindOffset(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		// Default values have been previously initialized by NewGetAlertsParams()
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("offset", "query", "int64", raw)
+	}
+	o.Offset = &value
+
+	if err := o.validateOffset(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateOffset carries on validations for parameter Offset
+func (o *GetAlertsParams) validateOffset(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("offset", "query", int64(*o.Offset), 0, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // bindReceiver binds and validates parameter Receiver from query.
 func (o *GetAlertsParams) bindReceiver(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
@@ -253,6 +369,39 @@ func (o *GetAlertsParams) bindSilenced(rawData []string, hasKey bool, formats st
 	return nil
 }
 
+// bindSort binds and validates parameter Sort from query.
+func (o *GetAlertsParams) bindSort(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		// Default values have been previously initialized by NewGetAlertsParams()
+		return nil
+	}
+
+	o.Sort = &raw
+
+	if err := o.validateSort(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSort carries on validations for parameter Sort
+func (o *GetAlertsParams) validateSort(formats strfmt.Registry) error {
+
+	if err := validate.EnumCase("sort", "query", *o.Sort, []interface{}{"fingerprint", "startsAt", "severity", "alertname"}, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // bindUnprocessed binds and validates parameter Unprocessed from query.
 func (o *GetAlertsParams) bindUnprocessed(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/swag"
 
 	"github.com/prometheus/alertmanager/api/v2/models"
 )
@@ -35,6 +36,10 @@ const GetAlertsOKCode int = 200
 swagger:response getAlertsOK
 */
 type GetAlertsOK struct {
+	/*The number of alerts matching the filters, regardless of limit and offset
+
+	 */
+	XTotalCount int64 `json:"X-Total-Count"`
 
 	/*
 	  In: Body
@@ -48,6 +53,17 @@ func NewGetAlertsOK() *GetAlertsOK {
 	return &GetAlertsOK{}
 }
 
+// WithXTotalCount adds the xTotalCount to the get alerts o k response
+func (o *GetAlertsOK) WithXTotalCount(xTotalCount int64) *GetAlertsOK {
+	o.XTotalCount = xTotalCount
+	return o
+}
+
+// SetXTotalCount sets the xTotalCount to the get alerts o k response
+func (o *GetAlertsOK) SetXTotalCount(xTotalCount int64) {
+	o.XTotalCount = xTotalCount
+}
+
 // WithPayload adds the payload to the get alerts o k response
 func (o *GetAlertsOK) WithPayload(payload models.GettableAlerts) *GetAlertsOK {
 	o.Payload = payload
@@ -62,6 +78,13 @@ func (o *GetAlertsOK) SetPayload(payload models.GettableAlerts) {
 // WriteResponse to the client
 func (o *GetAlertsOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
+	// response header X-Total-Count
+
+	xTotalCount := swag.FormatInt64(o.XTotalCount)
+	if xTotalCount != "" {
+		rw.Header().Set("X-Total-Count", xTotalCount)
+	}
+
 	rw.WriteHeader(200)
 	payload := o.Payload
 	if payload == nil {
@@ -32,8 +32,11 @@ type GetAlertsURL struct {
 	Active      *bool
 	Filter      []string
 	Inhibited   *bool
+	Limit       *int64
+	Offset      *int64
 	Receiver    *string
 	Silenced    *bool
+	Sort        *string
 	Unprocessed *bool
 
 	_basePath string
@@ -97,6 +100,22 @@ func (o *GetAlertsURL) Build() (*url.URL, error) {
 		qs.Set("inhibited", inhibitedQ)
 	}
 
+	var limitQ string
+	if o.Limit != nil {
+		limitQ = swag.FormatInt64(*o.Limit)
+	}
+	if limitQ != "" {
+		qs.Set("limit", limitQ)
+	}
+
+	var offsetQ string
+	if o.Offset != nil {
+		offsetQ = swag.FormatInt64(*o.Offset)
+	}
+	if offsetQ != "" {
+		qs.Set("offset", offsetQ)
+	}
+
 	var receiverQ string
 	if o.Receiver != nil {
 		receiverQ = *o.Receiver
@@ -113,6 +132,14 @@ func (o *GetAlertsURL) Build() (*url.URL, error) {
 		qs.Set("silenced", silencedQ)
 	}
 
+	var sortQ string
+	if o.Sort != nil {
+		sortQ = *o.Sort
+	}
+	if sortQ != "" {
+		qs.Set("sort", sortQ)
+	}
+
 	var unprocessedQ string
 	if o.Unprocessed != nil {
 		unprocessedQ = swag.FormatBool(*o.Unprocessed)
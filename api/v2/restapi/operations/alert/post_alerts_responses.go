@@ -91,6 +91,48 @@ func (o *PostAlertsBadRequest) WriteResponse(rw http.ResponseWriter, producer ru
 	}
 }
 
+// PostAlertsTooManyRequestsCode is the HTTP code returned for type PostAlertsTooManyRequests
+const PostAlertsTooManyRequestsCode int = 429
+
+/*PostAlertsTooManyRequests Too many requests
+
+swagger:response postAlertsTooManyRequests
+*/
+type PostAlertsTooManyRequests struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewPostAlertsTooManyRequests creates PostAlertsTooManyRequests with default headers values
+func NewPostAlertsTooManyRequests() *PostAlertsTooManyRequests {
+
+	return &PostAlertsTooManyRequests{}
+}
+
+// WithPayload adds the payload to the post alerts too many requests response
+func (o *PostAlertsTooManyRequests) WithPayload(payload string) *PostAlertsTooManyRequests {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post alerts too many requests response
+func (o *PostAlertsTooManyRequests) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostAlertsTooManyRequests) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(429)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
 // PostAlertsInternalServerErrorCode is the HTTP code returned for type PostAlertsInternalServerError
 const PostAlertsInternalServerErrorCode int = 500
 
@@ -0,0 +1,114 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package alert
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// StreamAlertsOKCode is the HTTP code returned for type StreamAlertsOK
+const StreamAlertsOKCode int = 200
+
+/*StreamAlertsOK Stream of alert events
+
+swagger:response streamAlertsOK
+*/
+type StreamAlertsOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *models.AlertEvent `json:"body,omitempty"`
+}
+
+// NewStreamAlertsOK creates StreamAlertsOK with default headers values
+func NewStreamAlertsOK() *StreamAlertsOK {
+
+	return &StreamAlertsOK{}
+}
+
+// WithPayload adds the payload to the stream alerts o k response
+func (o *StreamAlertsOK) WithPayload(payload *models.AlertEvent) *StreamAlertsOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the stream alerts o k response
+func (o *StreamAlertsOK) SetPayload(payload *models.AlertEvent) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *StreamAlertsOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+// StreamAlertsBadRequestCode is the HTTP code returned for type StreamAlertsBadRequest
+const StreamAlertsBadRequestCode int = 400
+
+/*StreamAlertsBadRequest Bad request
+
+swagger:response streamAlertsBadRequest
+*/
+type StreamAlertsBadRequest struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewStreamAlertsBadRequest creates StreamAlertsBadRequest with default headers values
+func NewStreamAlertsBadRequest() *StreamAlertsBadRequest {
+
+	return &StreamAlertsBadRequest{}
+}
+
+// WithPayload adds the payload to the stream alerts bad request response
+func (o *StreamAlertsBadRequest) WithPayload(payload string) *StreamAlertsBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the stream alerts bad request response
+func (o *StreamAlertsBadRequest) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *StreamAlertsBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
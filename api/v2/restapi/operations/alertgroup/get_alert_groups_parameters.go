@@ -40,7 +40,8 @@ func NewGetAlertGroupsParams() GetAlertGroupsParams {
 
 		inhibitedDefault = bool(true)
 
-		silencedDefault = bool(true)
+		silencedDefault    = bool(true)
+		unprocessedDefault = bool(true)
 	)
 
 	return GetAlertGroupsParams{
@@ -49,6 +50,8 @@ func NewGetAlertGroupsParams() GetAlertGroupsParams {
 		Inhibited: &inhibitedDefault,
 
 		Silenced: &silencedDefault,
+
+		Unprocessed: &unprocessedDefault,
 	}
 }
 
@@ -85,6 +88,11 @@ type GetAlertGroupsParams struct {
 	  Default: true
 	*/
 	Silenced *bool
+	/*Show unprocessed alerts
+	  In: query
+	  Default: true
+	*/
+	Unprocessed *bool
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -123,6 +131,11 @@ func (o *GetAlertGroupsParams) BindRequest(r *http.Request, route *middleware.Ma
 		res = append(res, err)
 	}
 
+	qUnprocessed, qhkUnprocessed, _ := qs.GetOK("unprocessed")
+	if err := o.bindUnprocessed(qUnprocessed, qhkUnprocessed, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -239,3 +252,26 @@ func (o *GetAlertGroupsParams) bindSilenced(rawData []string, hasKey bool, forma
 
 	return nil
 }
+
+// bindUnprocessed binds and validates parameter Unprocessed from query.
+func (o *GetAlertGroupsParams) bindUnprocessed(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		// Default values have been previously initialized by NewGetAlertGroupsParams()
+		return nil
+	}
+
+	value, err := swag.ConvertBool(raw)
+	if err != nil {
+		return errors.InvalidType("unprocessed", "query", "bool", raw)
+	}
+	o.Unprocessed = &value
+
+	return nil
+}
@@ -29,11 +29,12 @@ import (
 
 // GetAlertGroupsURL generates an URL for the get alert groups operation
 type GetAlertGroupsURL struct {
-	Active    *bool
-	Filter    []string
-	Inhibited *bool
-	Receiver  *string
-	Silenced  *bool
+	Active      *bool
+	Filter      []string
+	Inhibited   *bool
+	Receiver    *string
+	Silenced    *bool
+	Unprocessed *bool
 
 	_basePath string
 	// avoid unkeyed usage
@@ -112,6 +113,14 @@ func (o *GetAlertGroupsURL) Build() (*url.URL, error) {
 		qs.Set("silenced", silencedQ)
 	}
 
+	var unprocessedQ string
+	if o.Unprocessed != nil {
+		unprocessedQ = swag.FormatBool(*o.Unprocessed)
+	}
+	if unprocessedQ != "" {
+		qs.Set("unprocessed", unprocessedQ)
+	}
+
 	_result.RawQuery = qs.Encode()
 
 	return &_result, nil
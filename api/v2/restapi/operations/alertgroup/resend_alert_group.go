@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package alertgroup
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// ResendAlertGroupHandlerFunc turns a function with the right signature into a resend alert group handler
+type ResendAlertGroupHandlerFunc func(ResendAlertGroupParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn ResendAlertGroupHandlerFunc) Handle(params ResendAlertGroupParams) middleware.Responder {
+	return fn(params)
+}
+
+// ResendAlertGroupHandler interface for that can handle valid resend alert group params
+type ResendAlertGroupHandler interface {
+	Handle(ResendAlertGroupParams) middleware.Responder
+}
+
+// NewResendAlertGroup creates a new http.Handler for the resend alert group operation
+func NewResendAlertGroup(ctx *middleware.Context, handler ResendAlertGroupHandler) *ResendAlertGroup {
+	return &ResendAlertGroup{Context: ctx, Handler: handler}
+}
+
+/*ResendAlertGroup swagger:route POST /alerts/groups/resend alertgroup resendAlertGroup
+
+Notify a receiver of an alert group again right away about its firing alerts, regardless of the repeat interval and of any acknowledgement of the group
+
+*/
+type ResendAlertGroup struct {
+	Context *middleware.Context
+	Handler ResendAlertGroupHandler
+}
+
+func (o *ResendAlertGroup) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewResendAlertGroupParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -21,6 +21,7 @@ package operations
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -33,8 +34,10 @@ import (
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 
+	"github.com/prometheus/alertmanager/api/v2/restapi/operations/ack"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/alert"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/alertgroup"
+	"github.com/prometheus/alertmanager/api/v2/restapi/operations/deadletter"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/general"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/receiver"
 	"github.com/prometheus/alertmanager/api/v2/restapi/operations/silence"
@@ -60,34 +63,100 @@ func NewAlertmanagerAPI(spec *loads.Document) *AlertmanagerAPI {
 		JSONConsumer: runtime.JSONConsumer(),
 
 		JSONProducer: runtime.JSONProducer(),
+		TextEventStreamProducer: runtime.ProducerFunc(func(w io.Writer, data interface{}) error {
+			return errors.NotImplemented("textEventStream producer has not yet been implemented")
+		}),
 
+		AckDeleteAckHandler: ack.DeleteAckHandlerFunc(func(params ack.DeleteAckParams) middleware.Responder {
+			return middleware.NotImplemented("operation ack.DeleteAck has not yet been implemented")
+		}),
+		DeadletterDeleteDeadLetterHandler: deadletter.DeleteDeadLetterHandlerFunc(func(params deadletter.DeleteDeadLetterParams) middleware.Responder {
+			return middleware.NotImplemented("operation deadletter.DeleteDeadLetter has not yet been implemented")
+		}),
 		SilenceDeleteSilenceHandler: silence.DeleteSilenceHandlerFunc(func(params silence.DeleteSilenceParams) middleware.Responder {
 			return middleware.NotImplemented("operation silence.DeleteSilence has not yet been implemented")
 		}),
+		SilenceExpireSilencesHandler: silence.ExpireSilencesHandlerFunc(func(params silence.ExpireSilencesParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.ExpireSilences has not yet been implemented")
+		}),
+		AckGetAcksHandler: ack.GetAcksHandlerFunc(func(params ack.GetAcksParams) middleware.Responder {
+			return middleware.NotImplemented("operation ack.GetAcks has not yet been implemented")
+		}),
 		AlertgroupGetAlertGroupsHandler: alertgroup.GetAlertGroupsHandlerFunc(func(params alertgroup.GetAlertGroupsParams) middleware.Responder {
 			return middleware.NotImplemented("operation alertgroup.GetAlertGroups has not yet been implemented")
 		}),
+		AlertGetAlertStatsHandler: alert.GetAlertStatsHandlerFunc(func(params alert.GetAlertStatsParams) middleware.Responder {
+			return middleware.NotImplemented("operation alert.GetAlertStats has not yet been implemented")
+		}),
 		AlertGetAlertsHandler: alert.GetAlertsHandlerFunc(func(params alert.GetAlertsParams) middleware.Responder {
 			return middleware.NotImplemented("operation alert.GetAlerts has not yet been implemented")
 		}),
+		DeadletterGetDeadLetterHandler: deadletter.GetDeadLetterHandlerFunc(func(params deadletter.GetDeadLetterParams) middleware.Responder {
+			return middleware.NotImplemented("operation deadletter.GetDeadLetter has not yet been implemented")
+		}),
+		DeadletterGetDeadLettersHandler: deadletter.GetDeadLettersHandlerFunc(func(params deadletter.GetDeadLettersParams) middleware.Responder {
+			return middleware.NotImplemented("operation deadletter.GetDeadLetters has not yet been implemented")
+		}),
+		ReceiverGetBudgetsHandler: receiver.GetBudgetsHandlerFunc(func(params receiver.GetBudgetsParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.GetBudgets has not yet been implemented")
+		}),
+		ReceiverGetNotificationHistoryHandler: receiver.GetNotificationHistoryHandlerFunc(func(params receiver.GetNotificationHistoryParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.GetNotificationHistory has not yet been implemented")
+		}),
 		ReceiverGetReceiversHandler: receiver.GetReceiversHandlerFunc(func(params receiver.GetReceiversParams) middleware.Responder {
 			return middleware.NotImplemented("operation receiver.GetReceivers has not yet been implemented")
 		}),
+		ReceiverGetReceiversHealthHandler: receiver.GetReceiversHealthHandlerFunc(func(params receiver.GetReceiversHealthParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.GetReceiversHealth has not yet been implemented")
+		}),
 		SilenceGetSilenceHandler: silence.GetSilenceHandlerFunc(func(params silence.GetSilenceParams) middleware.Responder {
 			return middleware.NotImplemented("operation silence.GetSilence has not yet been implemented")
 		}),
+		SilenceGetSilenceEventsHandler: silence.GetSilenceEventsHandlerFunc(func(params silence.GetSilenceEventsParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.GetSilenceEvents has not yet been implemented")
+		}),
+		SilenceGetSilenceOccurrencesHandler: silence.GetSilenceOccurrencesHandlerFunc(func(params silence.GetSilenceOccurrencesParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.GetSilenceOccurrences has not yet been implemented")
+		}),
+		SilenceGetSilencePresetsHandler: silence.GetSilencePresetsHandlerFunc(func(params silence.GetSilencePresetsParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.GetSilencePresets has not yet been implemented")
+		}),
 		SilenceGetSilencesHandler: silence.GetSilencesHandlerFunc(func(params silence.GetSilencesParams) middleware.Responder {
 			return middleware.NotImplemented("operation silence.GetSilences has not yet been implemented")
 		}),
 		GeneralGetStatusHandler: general.GetStatusHandlerFunc(func(params general.GetStatusParams) middleware.Responder {
 			return middleware.NotImplemented("operation general.GetStatus has not yet been implemented")
 		}),
+		AckPostAcksHandler: ack.PostAcksHandlerFunc(func(params ack.PostAcksParams) middleware.Responder {
+			return middleware.NotImplemented("operation ack.PostAcks has not yet been implemented")
+		}),
 		AlertPostAlertsHandler: alert.PostAlertsHandlerFunc(func(params alert.PostAlertsParams) middleware.Responder {
 			return middleware.NotImplemented("operation alert.PostAlerts has not yet been implemented")
 		}),
+		SilencePostSilencePresetHandler: silence.PostSilencePresetHandlerFunc(func(params silence.PostSilencePresetParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.PostSilencePreset has not yet been implemented")
+		}),
 		SilencePostSilencesHandler: silence.PostSilencesHandlerFunc(func(params silence.PostSilencesParams) middleware.Responder {
 			return middleware.NotImplemented("operation silence.PostSilences has not yet been implemented")
 		}),
+		SilenceRecreateSilencesHandler: silence.RecreateSilencesHandlerFunc(func(params silence.RecreateSilencesParams) middleware.Responder {
+			return middleware.NotImplemented("operation silence.RecreateSilences has not yet been implemented")
+		}),
+		ReceiverRenderReceiverHandler: receiver.RenderReceiverHandlerFunc(func(params receiver.RenderReceiverParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.RenderReceiver has not yet been implemented")
+		}),
+		DeadletterReplayDeadLetterHandler: deadletter.ReplayDeadLetterHandlerFunc(func(params deadletter.ReplayDeadLetterParams) middleware.Responder {
+			return middleware.NotImplemented("operation deadletter.ReplayDeadLetter has not yet been implemented")
+		}),
+		AlertgroupResendAlertGroupHandler: alertgroup.ResendAlertGroupHandlerFunc(func(params alertgroup.ResendAlertGroupParams) middleware.Responder {
+			return middleware.NotImplemented("operation alertgroup.ResendAlertGroup has not yet been implemented")
+		}),
+		AlertStreamAlertsHandler: alert.StreamAlertsHandlerFunc(func(params alert.StreamAlertsParams) middleware.Responder {
+			return middleware.NotImplemented("operation alert.StreamAlerts has not yet been implemented")
+		}),
+		ReceiverTestReceiverHandler: receiver.TestReceiverHandlerFunc(func(params receiver.TestReceiverParams) middleware.Responder {
+			return middleware.NotImplemented("operation receiver.TestReceiver has not yet been implemented")
+		}),
 	}
 }
 
@@ -120,25 +189,70 @@ type AlertmanagerAPI struct {
 	// JSONProducer registers a producer for the following mime types:
 	//   - application/json
 	JSONProducer runtime.Producer
-
+	// TextEventStreamProducer registers a producer for the following mime types:
+	//   - text/event-stream
+	TextEventStreamProducer runtime.Producer
+
+	// AckDeleteAckHandler sets the operation handler for the delete ack operation
+	AckDeleteAckHandler ack.DeleteAckHandler
+	// DeadletterDeleteDeadLetterHandler sets the operation handler for the delete dead letter operation
+	DeadletterDeleteDeadLetterHandler deadletter.DeleteDeadLetterHandler
 	// SilenceDeleteSilenceHandler sets the operation handler for the delete silence operation
 	SilenceDeleteSilenceHandler silence.DeleteSilenceHandler
+	// SilenceExpireSilencesHandler sets the operation handler for the expire silences operation
+	SilenceExpireSilencesHandler silence.ExpireSilencesHandler
+	// AckGetAcksHandler sets the operation handler for the get acks operation
+	AckGetAcksHandler ack.GetAcksHandler
 	// AlertgroupGetAlertGroupsHandler sets the operation handler for the get alert groups operation
 	AlertgroupGetAlertGroupsHandler alertgroup.GetAlertGroupsHandler
+	// AlertGetAlertStatsHandler sets the operation handler for the get alert stats operation
+	AlertGetAlertStatsHandler alert.GetAlertStatsHandler
 	// AlertGetAlertsHandler sets the operation handler for the get alerts operation
 	AlertGetAlertsHandler alert.GetAlertsHandler
+	// DeadletterGetDeadLetterHandler sets the operation handler for the get dead letter operation
+	DeadletterGetDeadLetterHandler deadletter.GetDeadLetterHandler
+	// DeadletterGetDeadLettersHandler sets the operation handler for the get dead letters operation
+	DeadletterGetDeadLettersHandler deadletter.GetDeadLettersHandler
+	// ReceiverGetBudgetsHandler sets the operation handler for the get budgets operation
+	ReceiverGetBudgetsHandler receiver.GetBudgetsHandler
+	// ReceiverGetNotificationHistoryHandler sets the operation handler for the get notification history operation
+	ReceiverGetNotificationHistoryHandler receiver.GetNotificationHistoryHandler
 	// ReceiverGetReceiversHandler sets the operation handler for the get receivers operation
 	ReceiverGetReceiversHandler receiver.GetReceiversHandler
+	// ReceiverGetReceiversHealthHandler sets the operation handler for the get receivers health operation
+	ReceiverGetReceiversHealthHandler receiver.GetReceiversHealthHandler
 	// SilenceGetSilenceHandler sets the operation handler for the get silence operation
 	SilenceGetSilenceHandler silence.GetSilenceHandler
+	// SilenceGetSilenceEventsHandler sets the operation handler for the get silence events operation
+	SilenceGetSilenceEventsHandler silence.GetSilenceEventsHandler
+	// SilenceGetSilenceOccurrencesHandler sets the operation handler for the get silence occurrences operation
+	SilenceGetSilenceOccurrencesHandler silence.GetSilenceOccurrencesHandler
+	// SilenceGetSilencePresetsHandler sets the operation handler for the get silence presets operation
+	SilenceGetSilencePresetsHandler silence.GetSilencePresetsHandler
 	// SilenceGetSilencesHandler sets the operation handler for the get silences operation
 	SilenceGetSilencesHandler silence.GetSilencesHandler
 	// GeneralGetStatusHandler sets the operation handler for the get status operation
 	GeneralGetStatusHandler general.GetStatusHandler
+	// AckPostAcksHandler sets the operation handler for the post acks operation
+	AckPostAcksHandler ack.PostAcksHandler
 	// AlertPostAlertsHandler sets the operation handler for the post alerts operation
 	AlertPostAlertsHandler alert.PostAlertsHandler
+	// SilencePostSilencePresetHandler sets the operation handler for the post silence preset operation
+	SilencePostSilencePresetHandler silence.PostSilencePresetHandler
 	// SilencePostSilencesHandler sets the operation handler for the post silences operation
 	SilencePostSilencesHandler silence.PostSilencesHandler
+	// SilenceRecreateSilencesHandler sets the operation handler for the recreate silences operation
+	SilenceRecreateSilencesHandler silence.RecreateSilencesHandler
+	// ReceiverRenderReceiverHandler sets the operation handler for the render receiver operation
+	ReceiverRenderReceiverHandler receiver.RenderReceiverHandler
+	// DeadletterReplayDeadLetterHandler sets the operation handler for the replay dead letter operation
+	DeadletterReplayDeadLetterHandler deadletter.ReplayDeadLetterHandler
+	// AlertgroupResendAlertGroupHandler sets the operation handler for the resend alert group operation
+	AlertgroupResendAlertGroupHandler alertgroup.ResendAlertGroupHandler
+	// AlertStreamAlertsHandler sets the operation handler for the stream alerts operation
+	AlertStreamAlertsHandler alert.StreamAlertsHandler
+	// ReceiverTestReceiverHandler sets the operation handler for the test receiver operation
+	ReceiverTestReceiverHandler receiver.TestReceiverHandler
 	// ServeError is called when an error is received, there is a default handler
 	// but you can set your own with this
 	ServeError func(http.ResponseWriter, *http.Request, error)
@@ -204,34 +318,100 @@ func (o *AlertmanagerAPI) Validate() error {
 	if o.JSONProducer == nil {
 		unregistered = append(unregistered, "JSONProducer")
 	}
+	if o.TextEventStreamProducer == nil {
+		unregistered = append(unregistered, "TextEventStreamProducer")
+	}
 
+	if o.AckDeleteAckHandler == nil {
+		unregistered = append(unregistered, "ack.DeleteAckHandler")
+	}
+	if o.DeadletterDeleteDeadLetterHandler == nil {
+		unregistered = append(unregistered, "deadletter.DeleteDeadLetterHandler")
+	}
 	if o.SilenceDeleteSilenceHandler == nil {
 		unregistered = append(unregistered, "silence.DeleteSilenceHandler")
 	}
+	if o.SilenceExpireSilencesHandler == nil {
+		unregistered = append(unregistered, "silence.ExpireSilencesHandler")
+	}
+	if o.AckGetAcksHandler == nil {
+		unregistered = append(unregistered, "ack.GetAcksHandler")
+	}
 	if o.AlertgroupGetAlertGroupsHandler == nil {
 		unregistered = append(unregistered, "alertgroup.GetAlertGroupsHandler")
 	}
+	if o.AlertGetAlertStatsHandler == nil {
+		unregistered = append(unregistered, "alert.GetAlertStatsHandler")
+	}
 	if o.AlertGetAlertsHandler == nil {
 		unregistered = append(unregistered, "alert.GetAlertsHandler")
 	}
+	if o.DeadletterGetDeadLetterHandler == nil {
+		unregistered = append(unregistered, "deadletter.GetDeadLetterHandler")
+	}
+	if o.DeadletterGetDeadLettersHandler == nil {
+		unregistered = append(unregistered, "deadletter.GetDeadLettersHandler")
+	}
+	if o.ReceiverGetBudgetsHandler == nil {
+		unregistered = append(unregistered, "receiver.GetBudgetsHandler")
+	}
+	if o.ReceiverGetNotificationHistoryHandler == nil {
+		unregistered = append(unregistered, "receiver.GetNotificationHistoryHandler")
+	}
 	if o.ReceiverGetReceiversHandler == nil {
 		unregistered = append(unregistered, "receiver.GetReceiversHandler")
 	}
+	if o.ReceiverGetReceiversHealthHandler == nil {
+		unregistered = append(unregistered, "receiver.GetReceiversHealthHandler")
+	}
 	if o.SilenceGetSilenceHandler == nil {
 		unregistered = append(unregistered, "silence.GetSilenceHandler")
 	}
+	if o.SilenceGetSilenceEventsHandler == nil {
+		unregistered = append(unregistered, "silence.GetSilenceEventsHandler")
+	}
+	if o.SilenceGetSilenceOccurrencesHandler == nil {
+		unregistered = append(unregistered, "silence.GetSilenceOccurrencesHandler")
+	}
+	if o.SilenceGetSilencePresetsHandler == nil {
+		unregistered = append(unregistered, "silence.GetSilencePresetsHandler")
+	}
 	if o.SilenceGetSilencesHandler == nil {
 		unregistered = append(unregistered, "silence.GetSilencesHandler")
 	}
 	if o.GeneralGetStatusHandler == nil {
 		unregistered = append(unregistered, "general.GetStatusHandler")
 	}
+	if o.AckPostAcksHandler == nil {
+		unregistered = append(unregistered, "ack.PostAcksHandler")
+	}
 	if o.AlertPostAlertsHandler == nil {
 		unregistered = append(unregistered, "alert.PostAlertsHandler")
 	}
+	if o.SilencePostSilencePresetHandler == nil {
+		unregistered = append(unregistered, "silence.PostSilencePresetHandler")
+	}
 	if o.SilencePostSilencesHandler == nil {
 		unregistered = append(unregistered, "silence.PostSilencesHandler")
 	}
+	if o.SilenceRecreateSilencesHandler == nil {
+		unregistered = append(unregistered, "silence.RecreateSilencesHandler")
+	}
+	if o.ReceiverRenderReceiverHandler == nil {
+		unregistered = append(unregistered, "receiver.RenderReceiverHandler")
+	}
+	if o.DeadletterReplayDeadLetterHandler == nil {
+		unregistered = append(unregistered, "deadletter.ReplayDeadLetterHandler")
+	}
+	if o.AlertgroupResendAlertGroupHandler == nil {
+		unregistered = append(unregistered, "alertgroup.ResendAlertGroupHandler")
+	}
+	if o.AlertStreamAlertsHandler == nil {
+		unregistered = append(unregistered, "alert.StreamAlertsHandler")
+	}
+	if o.ReceiverTestReceiverHandler == nil {
+		unregistered = append(unregistered, "receiver.TestReceiverHandler")
+	}
 
 	if len(unregistered) > 0 {
 		return fmt.Errorf("missing registration: %s", strings.Join(unregistered, ", "))
@@ -280,6 +460,8 @@ func (o *AlertmanagerAPI) ProducersFor(mediaTypes []string) map[string]runtime.P
 		switch mt {
 		case "application/json":
 			result["application/json"] = o.JSONProducer
+		case "text/event-stream":
+			result["text/event-stream"] = o.TextEventStreamProducer
 		}
 
 		if p, ok := o.customProducers[mt]; ok {
@@ -320,10 +502,26 @@ func (o *AlertmanagerAPI) initHandlerCache() {
 		o.handlers = make(map[string]map[string]http.Handler)
 	}
 
+	if o.handlers["DELETE"] == nil {
+		o.handlers["DELETE"] = make(map[string]http.Handler)
+	}
+	o.handlers["DELETE"]["/ack/{ackID}"] = ack.NewDeleteAck(o.context, o.AckDeleteAckHandler)
+	if o.handlers["DELETE"] == nil {
+		o.handlers["DELETE"] = make(map[string]http.Handler)
+	}
+	o.handlers["DELETE"]["/deadletter/{deadLetterID}"] = deadletter.NewDeleteDeadLetter(o.context, o.DeadletterDeleteDeadLetterHandler)
 	if o.handlers["DELETE"] == nil {
 		o.handlers["DELETE"] = make(map[string]http.Handler)
 	}
 	o.handlers["DELETE"]["/silence/{silenceID}"] = silence.NewDeleteSilence(o.context, o.SilenceDeleteSilenceHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/silences/expire"] = silence.NewExpireSilences(o.context, o.SilenceExpireSilencesHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/acks"] = ack.NewGetAcks(o.context, o.AckGetAcksHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -331,18 +529,54 @@ func (o *AlertmanagerAPI) initHandlerCache() {
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
+	o.handlers["GET"]["/stats"] = alert.NewGetAlertStats(o.context, o.AlertGetAlertStatsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
 	o.handlers["GET"]["/alerts"] = alert.NewGetAlerts(o.context, o.AlertGetAlertsHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
+	o.handlers["GET"]["/deadletter/{deadLetterID}"] = deadletter.NewGetDeadLetter(o.context, o.DeadletterGetDeadLetterHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/deadletters"] = deadletter.NewGetDeadLetters(o.context, o.DeadletterGetDeadLettersHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/budgets"] = receiver.NewGetBudgets(o.context, o.ReceiverGetBudgetsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/history"] = receiver.NewGetNotificationHistory(o.context, o.ReceiverGetNotificationHistoryHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
 	o.handlers["GET"]["/receivers"] = receiver.NewGetReceivers(o.context, o.ReceiverGetReceiversHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
+	o.handlers["GET"]["/receivers/health"] = receiver.NewGetReceiversHealth(o.context, o.ReceiverGetReceiversHealthHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
 	o.handlers["GET"]["/silence/{silenceID}"] = silence.NewGetSilence(o.context, o.SilenceGetSilenceHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
+	o.handlers["GET"]["/silences/events"] = silence.NewGetSilenceEvents(o.context, o.SilenceGetSilenceEventsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/silence/{silenceID}/occurrences"] = silence.NewGetSilenceOccurrences(o.context, o.SilenceGetSilenceOccurrencesHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/silences/presets"] = silence.NewGetSilencePresets(o.context, o.SilenceGetSilencePresetsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
 	o.handlers["GET"]["/silences"] = silence.NewGetSilences(o.context, o.SilenceGetSilencesHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
@@ -351,11 +585,43 @@ func (o *AlertmanagerAPI) initHandlerCache() {
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
+	o.handlers["POST"]["/acks"] = ack.NewPostAcks(o.context, o.AckPostAcksHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
 	o.handlers["POST"]["/alerts"] = alert.NewPostAlerts(o.context, o.AlertPostAlertsHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
+	o.handlers["POST"]["/silences/presets/{presetName}"] = silence.NewPostSilencePreset(o.context, o.SilencePostSilencePresetHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
 	o.handlers["POST"]["/silences"] = silence.NewPostSilences(o.context, o.SilencePostSilencesHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/silences/recreate"] = silence.NewRecreateSilences(o.context, o.SilenceRecreateSilencesHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/receivers/{name}/render"] = receiver.NewRenderReceiver(o.context, o.ReceiverRenderReceiverHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/deadletter/{deadLetterID}/replay"] = deadletter.NewReplayDeadLetter(o.context, o.DeadletterReplayDeadLetterHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/alerts/groups/resend"] = alertgroup.NewResendAlertGroup(o.context, o.AlertgroupResendAlertGroupHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/alerts/stream"] = alert.NewStreamAlerts(o.context, o.AlertStreamAlertsHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/receivers/{name}/test"] = receiver.NewTestReceiver(o.context, o.ReceiverTestReceiverHandler)
 }
 
 // Serve creates a http handler to serve the API over HTTP
@@ -0,0 +1,74 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+)
+
+// DeleteDeadLetterOKCode is the HTTP code returned for type DeleteDeadLetterOK
+const DeleteDeadLetterOKCode int = 200
+
+/*DeleteDeadLetterOK Delete dead letter response
+
+swagger:response deleteDeadLetterOK
+*/
+type DeleteDeadLetterOK struct {
+}
+
+// NewDeleteDeadLetterOK creates DeleteDeadLetterOK with default headers values
+func NewDeleteDeadLetterOK() *DeleteDeadLetterOK {
+
+	return &DeleteDeadLetterOK{}
+}
+
+// WriteResponse to the client
+func (o *DeleteDeadLetterOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(200)
+}
+
+// DeleteDeadLetterNotFoundCode is the HTTP code returned for type DeleteDeadLetterNotFound
+const DeleteDeadLetterNotFoundCode int = 404
+
+/*DeleteDeadLetterNotFound A dead letter with the specified ID was not found
+
+swagger:response deleteDeadLetterNotFound
+*/
+type DeleteDeadLetterNotFound struct {
+}
+
+// NewDeleteDeadLetterNotFound creates DeleteDeadLetterNotFound with default headers values
+func NewDeleteDeadLetterNotFound() *DeleteDeadLetterNotFound {
+
+	return &DeleteDeadLetterNotFound{}
+}
+
+// WriteResponse to the client
+func (o *DeleteDeadLetterNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
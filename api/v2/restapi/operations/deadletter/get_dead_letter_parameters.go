@@ -0,0 +1,105 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+)
+
+// NewGetDeadLetterParams creates a new GetDeadLetterParams object
+// no default values defined in spec.
+func NewGetDeadLetterParams() GetDeadLetterParams {
+
+	return GetDeadLetterParams{}
+}
+
+// GetDeadLetterParams contains all the bound params for the get dead letter operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters getDeadLetter
+type GetDeadLetterParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request `json:"-"`
+
+	/*ID of the dead letter
+	  Required: true
+	  In: path
+	*/
+	DeadLetterID strfmt.UUID
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls.
+//
+// To ensure default values, the struct must have been initialized with NewGetDeadLetterParams() beforehand.
+func (o *GetDeadLetterParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+
+	o.HTTPRequest = r
+
+	rDeadLetterID, rhkDeadLetterID, _ := route.Params.GetOK("deadLetterID")
+	if err := o.bindDeadLetterID(rDeadLetterID, rhkDeadLetterID, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// bindDeadLetterID binds and validates parameter DeadLetterID from path.
+func (o *GetDeadLetterParams) bindDeadLetterID(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: true
+	// Parameter is provided by construction from the route
+
+	// Format: uuid
+	value, err := formats.Parse("uuid", raw)
+	if err != nil {
+		return errors.InvalidType("deadLetterID", "path", "strfmt.UUID", raw)
+	}
+	o.DeadLetterID = *(value.(*strfmt.UUID))
+
+	if err := o.validateDeadLetterID(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateDeadLetterID carries on validations for parameter DeadLetterID
+func (o *GetDeadLetterParams) validateDeadLetterID(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("deadLetterID", "path", "uuid", o.DeadLetterID.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// GetDeadLetterOKCode is the HTTP code returned for type GetDeadLetterOK
+const GetDeadLetterOKCode int = 200
+
+/*GetDeadLetterOK Get dead letter response
+
+swagger:response getDeadLetterOK
+*/
+type GetDeadLetterOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *models.DeadLetter `json:"body,omitempty"`
+}
+
+// NewGetDeadLetterOK creates GetDeadLetterOK with default headers values
+func NewGetDeadLetterOK() *GetDeadLetterOK {
+
+	return &GetDeadLetterOK{}
+}
+
+// WithPayload adds the payload to the get dead letter o k response
+func (o *GetDeadLetterOK) WithPayload(payload *models.DeadLetter) *GetDeadLetterOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get dead letter o k response
+func (o *GetDeadLetterOK) SetPayload(payload *models.DeadLetter) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDeadLetterOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+// GetDeadLetterNotFoundCode is the HTTP code returned for type GetDeadLetterNotFound
+const GetDeadLetterNotFoundCode int = 404
+
+/*GetDeadLetterNotFound A dead letter with the specified ID was not found
+
+swagger:response getDeadLetterNotFound
+*/
+type GetDeadLetterNotFound struct {
+}
+
+// NewGetDeadLetterNotFound creates GetDeadLetterNotFound with default headers values
+func NewGetDeadLetterNotFound() *GetDeadLetterNotFound {
+
+	return &GetDeadLetterNotFound{}
+}
+
+// WriteResponse to the client
+func (o *GetDeadLetterNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
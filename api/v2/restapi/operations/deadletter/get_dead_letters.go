@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// GetDeadLettersHandlerFunc turns a function with the right signature into a get dead letters handler
+type GetDeadLettersHandlerFunc func(GetDeadLettersParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetDeadLettersHandlerFunc) Handle(params GetDeadLettersParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetDeadLettersHandler interface for that can handle valid get dead letters params
+type GetDeadLettersHandler interface {
+	Handle(GetDeadLettersParams) middleware.Responder
+}
+
+// NewGetDeadLetters creates a new http.Handler for the get dead letters operation
+func NewGetDeadLetters(ctx *middleware.Context, handler GetDeadLettersHandler) *GetDeadLetters {
+	return &GetDeadLetters{Context: ctx, Handler: handler}
+}
+
+/*GetDeadLetters swagger:route GET /deadletters deadletter getDeadLetters
+
+Get a list of notifications that could not be delivered
+
+*/
+type GetDeadLetters struct {
+	Context *middleware.Context
+	Handler GetDeadLettersHandler
+}
+
+func (o *GetDeadLetters) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewGetDeadLettersParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
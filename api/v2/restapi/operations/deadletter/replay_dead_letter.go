@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// ReplayDeadLetterHandlerFunc turns a function with the right signature into a replay dead letter handler
+type ReplayDeadLetterHandlerFunc func(ReplayDeadLetterParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn ReplayDeadLetterHandlerFunc) Handle(params ReplayDeadLetterParams) middleware.Responder {
+	return fn(params)
+}
+
+// ReplayDeadLetterHandler interface for that can handle valid replay dead letter params
+type ReplayDeadLetterHandler interface {
+	Handle(ReplayDeadLetterParams) middleware.Responder
+}
+
+// NewReplayDeadLetter creates a new http.Handler for the replay dead letter operation
+func NewReplayDeadLetter(ctx *middleware.Context, handler ReplayDeadLetterHandler) *ReplayDeadLetter {
+	return &ReplayDeadLetter{Context: ctx, Handler: handler}
+}
+
+/*ReplayDeadLetter swagger:route POST /deadletter/{deadLetterID}/replay deadletter replayDeadLetter
+
+Send a dead letter to its integration again. The dead letter is removed on success.
+
+*/
+type ReplayDeadLetter struct {
+	Context *middleware.Context
+	Handler ReplayDeadLetterHandler
+}
+
+func (o *ReplayDeadLetter) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewReplayDeadLetterParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,116 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package deadletter
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+)
+
+// ReplayDeadLetterOKCode is the HTTP code returned for type ReplayDeadLetterOK
+const ReplayDeadLetterOKCode int = 200
+
+/*ReplayDeadLetterOK Replay dead letter response
+
+swagger:response replayDeadLetterOK
+*/
+type ReplayDeadLetterOK struct {
+}
+
+// NewReplayDeadLetterOK creates ReplayDeadLetterOK with default headers values
+func NewReplayDeadLetterOK() *ReplayDeadLetterOK {
+
+	return &ReplayDeadLetterOK{}
+}
+
+// WriteResponse to the client
+func (o *ReplayDeadLetterOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(200)
+}
+
+// ReplayDeadLetterNotFoundCode is the HTTP code returned for type ReplayDeadLetterNotFound
+const ReplayDeadLetterNotFoundCode int = 404
+
+/*ReplayDeadLetterNotFound A dead letter with the specified ID was not found
+
+swagger:response replayDeadLetterNotFound
+*/
+type ReplayDeadLetterNotFound struct {
+}
+
+// NewReplayDeadLetterNotFound creates ReplayDeadLetterNotFound with default headers values
+func NewReplayDeadLetterNotFound() *ReplayDeadLetterNotFound {
+
+	return &ReplayDeadLetterNotFound{}
+}
+
+// WriteResponse to the client
+func (o *ReplayDeadLetterNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
+
+// ReplayDeadLetterInternalServerErrorCode is the HTTP code returned for type ReplayDeadLetterInternalServerError
+const ReplayDeadLetterInternalServerErrorCode int = 500
+
+/*ReplayDeadLetterInternalServerError Internal server error
+
+swagger:response replayDeadLetterInternalServerError
+*/
+type ReplayDeadLetterInternalServerError struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewReplayDeadLetterInternalServerError creates ReplayDeadLetterInternalServerError with default headers values
+func NewReplayDeadLetterInternalServerError() *ReplayDeadLetterInternalServerError {
+
+	return &ReplayDeadLetterInternalServerError{}
+}
+
+// WithPayload adds the payload to the replay dead letter internal server error response
+func (o *ReplayDeadLetterInternalServerError) WithPayload(payload string) *ReplayDeadLetterInternalServerError {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the replay dead letter internal server error response
+func (o *ReplayDeadLetterInternalServerError) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *ReplayDeadLetterInternalServerError) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(500)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
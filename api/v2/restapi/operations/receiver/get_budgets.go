@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// GetBudgetsHandlerFunc turns a function with the right signature into a get budgets handler
+type GetBudgetsHandlerFunc func(GetBudgetsParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetBudgetsHandlerFunc) Handle(params GetBudgetsParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetBudgetsHandler interface for that can handle valid get budgets params
+type GetBudgetsHandler interface {
+	Handle(GetBudgetsParams) middleware.Responder
+}
+
+// NewGetBudgets creates a new http.Handler for the get budgets operation
+func NewGetBudgets(ctx *middleware.Context, handler GetBudgetsHandler) *GetBudgets {
+	return &GetBudgets{Context: ctx, Handler: handler}
+}
+
+/*GetBudgets swagger:route GET /budgets receiver getBudgets
+
+Get the consumption of the notification budgets of receivers and tenants in their current window
+
+*/
+type GetBudgets struct {
+	Context *middleware.Context
+	Handler GetBudgetsHandler
+}
+
+func (o *GetBudgets) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewGetBudgetsParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
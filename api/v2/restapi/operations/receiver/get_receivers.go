@@ -45,7 +45,7 @@ func NewGetReceivers(ctx *middleware.Context, handler GetReceiversHandler) *GetR
 
 /*GetReceivers swagger:route GET /receivers receiver getReceivers
 
-Get list of all receivers (name of notification integrations)
+Get list of all receivers along with the types of their integrations
 
 */
 type GetReceivers struct {
@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// GetReceiversHealthHandlerFunc turns a function with the right signature into a get receivers health handler
+type GetReceiversHealthHandlerFunc func(GetReceiversHealthParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetReceiversHealthHandlerFunc) Handle(params GetReceiversHealthParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetReceiversHealthHandler interface for that can handle valid get receivers health params
+type GetReceiversHealthHandler interface {
+	Handle(GetReceiversHealthParams) middleware.Responder
+}
+
+// NewGetReceiversHealth creates a new http.Handler for the get receivers health operation
+func NewGetReceiversHealth(ctx *middleware.Context, handler GetReceiversHealthHandler) *GetReceiversHealth {
+	return &GetReceiversHealth{Context: ctx, Handler: handler}
+}
+
+/*GetReceiversHealth swagger:route GET /receivers/health receiver getReceiversHealth
+
+Get the health of the integrations of all receivers, summarized from their notification attempts
+
+*/
+type GetReceiversHealth struct {
+	Context *middleware.Context
+	Handler GetReceiversHealthHandler
+}
+
+func (o *GetReceiversHealth) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewGetReceiversHealthParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// RenderReceiverHandlerFunc turns a function with the right signature into a render receiver handler
+type RenderReceiverHandlerFunc func(RenderReceiverParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn RenderReceiverHandlerFunc) Handle(params RenderReceiverParams) middleware.Responder {
+	return fn(params)
+}
+
+// RenderReceiverHandler interface for that can handle valid render receiver params
+type RenderReceiverHandler interface {
+	Handle(RenderReceiverParams) middleware.Responder
+}
+
+// NewRenderReceiver creates a new http.Handler for the render receiver operation
+func NewRenderReceiver(ctx *middleware.Context, handler RenderReceiverHandler) *RenderReceiver {
+	return &RenderReceiver{Context: ctx, Handler: handler}
+}
+
+/*RenderReceiver swagger:route POST /receivers/{name}/render receiver renderReceiver
+
+Render the notifications each integration of the receiver would send for the given alerts, without sending them
+
+*/
+type RenderReceiver struct {
+	Context *middleware.Context
+	Handler RenderReceiverHandler
+}
+
+func (o *RenderReceiver) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewRenderReceiverParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
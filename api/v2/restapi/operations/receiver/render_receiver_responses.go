@@ -0,0 +1,141 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// RenderReceiverOKCode is the HTTP code returned for type RenderReceiverOK
+const RenderReceiverOKCode int = 200
+
+/*RenderReceiverOK Render receiver response
+
+swagger:response renderReceiverOK
+*/
+type RenderReceiverOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload models.RenderedNotifications `json:"body,omitempty"`
+}
+
+// NewRenderReceiverOK creates RenderReceiverOK with default headers values
+func NewRenderReceiverOK() *RenderReceiverOK {
+
+	return &RenderReceiverOK{}
+}
+
+// WithPayload adds the payload to the render receiver o k response
+func (o *RenderReceiverOK) WithPayload(payload models.RenderedNotifications) *RenderReceiverOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the render receiver o k response
+func (o *RenderReceiverOK) SetPayload(payload models.RenderedNotifications) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *RenderReceiverOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if payload == nil {
+		// return empty array
+		payload = models.RenderedNotifications{}
+	}
+
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// RenderReceiverBadRequestCode is the HTTP code returned for type RenderReceiverBadRequest
+const RenderReceiverBadRequestCode int = 400
+
+/*RenderReceiverBadRequest Bad request
+
+swagger:response renderReceiverBadRequest
+*/
+type RenderReceiverBadRequest struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewRenderReceiverBadRequest creates RenderReceiverBadRequest with default headers values
+func NewRenderReceiverBadRequest() *RenderReceiverBadRequest {
+
+	return &RenderReceiverBadRequest{}
+}
+
+// WithPayload adds the payload to the render receiver bad request response
+func (o *RenderReceiverBadRequest) WithPayload(payload string) *RenderReceiverBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the render receiver bad request response
+func (o *RenderReceiverBadRequest) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *RenderReceiverBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// RenderReceiverNotFoundCode is the HTTP code returned for type RenderReceiverNotFound
+const RenderReceiverNotFoundCode int = 404
+
+/*RenderReceiverNotFound A receiver with the specified name was not found or is not used by any route
+
+swagger:response renderReceiverNotFound
+*/
+type RenderReceiverNotFound struct {
+}
+
+// NewRenderReceiverNotFound creates RenderReceiverNotFound with default headers values
+func NewRenderReceiverNotFound() *RenderReceiverNotFound {
+
+	return &RenderReceiverNotFound{}
+}
+
+// WriteResponse to the client
+func (o *RenderReceiverNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
@@ -0,0 +1,72 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// TestReceiverHandlerFunc turns a function with the right signature into a test receiver handler
+type TestReceiverHandlerFunc func(TestReceiverParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn TestReceiverHandlerFunc) Handle(params TestReceiverParams) middleware.Responder {
+	return fn(params)
+}
+
+// TestReceiverHandler interface for that can handle valid test receiver params
+type TestReceiverHandler interface {
+	Handle(TestReceiverParams) middleware.Responder
+}
+
+// NewTestReceiver creates a new http.Handler for the test receiver operation
+func NewTestReceiver(ctx *middleware.Context, handler TestReceiverHandler) *TestReceiver {
+	return &TestReceiver{Context: ctx, Handler: handler}
+}
+
+/*TestReceiver swagger:route POST /receivers/{name}/test receiver testReceiver
+
+Send a test alert once through each integration of the receiver
+
+*/
+type TestReceiver struct {
+	Context *middleware.Context
+	Handler TestReceiverHandler
+}
+
+func (o *TestReceiver) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewTestReceiverParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,99 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package receiver
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// TestReceiverOKCode is the HTTP code returned for type TestReceiverOK
+const TestReceiverOKCode int = 200
+
+/*TestReceiverOK Test receiver response
+
+swagger:response testReceiverOK
+*/
+type TestReceiverOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload models.IntegrationTestResults `json:"body,omitempty"`
+}
+
+// NewTestReceiverOK creates TestReceiverOK with default headers values
+func NewTestReceiverOK() *TestReceiverOK {
+
+	return &TestReceiverOK{}
+}
+
+// WithPayload adds the payload to the test receiver o k response
+func (o *TestReceiverOK) WithPayload(payload models.IntegrationTestResults) *TestReceiverOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the test receiver o k response
+func (o *TestReceiverOK) SetPayload(payload models.IntegrationTestResults) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *TestReceiverOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if payload == nil {
+		// return empty array
+		payload = models.IntegrationTestResults{}
+	}
+
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// TestReceiverNotFoundCode is the HTTP code returned for type TestReceiverNotFound
+const TestReceiverNotFoundCode int = 404
+
+/*TestReceiverNotFound A receiver with the specified name was not found or is not used by any route
+
+swagger:response testReceiverNotFound
+*/
+type TestReceiverNotFound struct {
+}
+
+// NewTestReceiverNotFound creates TestReceiverNotFound with default headers values
+func NewTestReceiverNotFound() *TestReceiverNotFound {
+
+	return &TestReceiverNotFound{}
+}
+
+// WriteResponse to the client
+func (o *TestReceiverNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
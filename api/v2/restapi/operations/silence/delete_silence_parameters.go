@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/validate"
@@ -44,6 +45,10 @@ type DeleteSilenceParams struct {
 	// HTTP Request Object
 	HTTPRequest *http.Request `json:"-"`
 
+	/*The user expiring the silence, recorded in the silence events
+	  In: query
+	*/
+	ExpiredBy *string
 	/*ID of the silence to get
 	  Required: true
 	  In: path
@@ -60,6 +65,13 @@ func (o *DeleteSilenceParams) BindRequest(r *http.Request, route *middleware.Mat
 
 	o.HTTPRequest = r
 
+	qs := runtime.Values(r.URL.Query())
+
+	qExpiredBy, qhkExpiredBy, _ := qs.GetOK("expiredBy")
+	if err := o.bindExpiredBy(qExpiredBy, qhkExpiredBy, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	rSilenceID, rhkSilenceID, _ := route.Params.GetOK("silenceID")
 	if err := o.bindSilenceID(rSilenceID, rhkSilenceID, route.Formats); err != nil {
 		res = append(res, err)
@@ -71,6 +83,24 @@ func (o *DeleteSilenceParams) BindRequest(r *http.Request, route *middleware.Mat
 	return nil
 }
 
+// bindExpiredBy binds and validates parameter ExpiredBy from query.
+func (o *DeleteSilenceParams) bindExpiredBy(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.ExpiredBy = &raw
+
+	return nil
+}
+
 // bindSilenceID binds and validates parameter SilenceID from path.
 func (o *DeleteSilenceParams) bindSilenceID(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
@@ -32,6 +32,8 @@ import (
 type DeleteSilenceURL struct {
 	SilenceID strfmt.UUID
 
+	ExpiredBy *string
+
 	_basePath string
 	// avoid unkeyed usage
 	_ struct{}
@@ -68,6 +70,18 @@ func (o *DeleteSilenceURL) Build() (*url.URL, error) {
 	_basePath := o._basePath
 	_result.Path = golangswaggerpaths.Join(_basePath, _path)
 
+	qs := make(url.Values)
+
+	var expiredByQ string
+	if o.ExpiredBy != nil {
+		expiredByQ = *o.ExpiredBy
+	}
+	if expiredByQ != "" {
+		qs.Set("expiredBy", expiredByQ)
+	}
+
+	_result.RawQuery = qs.Encode()
+
 	return &_result, nil
 }
 
@@ -0,0 +1,156 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// ExpireSilencesOKCode is the HTTP code returned for type ExpireSilencesOK
+const ExpireSilencesOKCode int = 200
+
+/*ExpireSilencesOK Expire silences response
+
+swagger:response expireSilencesOK
+*/
+type ExpireSilencesOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *models.BulkSilenceResult `json:"body,omitempty"`
+}
+
+// NewExpireSilencesOK creates ExpireSilencesOK with default headers values
+func NewExpireSilencesOK() *ExpireSilencesOK {
+
+	return &ExpireSilencesOK{}
+}
+
+// WithPayload adds the payload to the expire silences o k response
+func (o *ExpireSilencesOK) WithPayload(payload *models.BulkSilenceResult) *ExpireSilencesOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the expire silences o k response
+func (o *ExpireSilencesOK) SetPayload(payload *models.BulkSilenceResult) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *ExpireSilencesOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+// ExpireSilencesBadRequestCode is the HTTP code returned for type ExpireSilencesBadRequest
+const ExpireSilencesBadRequestCode int = 400
+
+/*ExpireSilencesBadRequest Bad request
+
+swagger:response expireSilencesBadRequest
+*/
+type ExpireSilencesBadRequest struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewExpireSilencesBadRequest creates ExpireSilencesBadRequest with default headers values
+func NewExpireSilencesBadRequest() *ExpireSilencesBadRequest {
+
+	return &ExpireSilencesBadRequest{}
+}
+
+// WithPayload adds the payload to the expire silences bad request response
+func (o *ExpireSilencesBadRequest) WithPayload(payload string) *ExpireSilencesBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the expire silences bad request response
+func (o *ExpireSilencesBadRequest) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *ExpireSilencesBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// ExpireSilencesInternalServerErrorCode is the HTTP code returned for type ExpireSilencesInternalServerError
+const ExpireSilencesInternalServerErrorCode int = 500
+
+/*ExpireSilencesInternalServerError Internal server error
+
+swagger:response expireSilencesInternalServerError
+*/
+type ExpireSilencesInternalServerError struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewExpireSilencesInternalServerError creates ExpireSilencesInternalServerError with default headers values
+func NewExpireSilencesInternalServerError() *ExpireSilencesInternalServerError {
+
+	return &ExpireSilencesInternalServerError{}
+}
+
+// WithPayload adds the payload to the expire silences internal server error response
+func (o *ExpireSilencesInternalServerError) WithPayload(payload string) *ExpireSilencesInternalServerError {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the expire silences internal server error response
+func (o *ExpireSilencesInternalServerError) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *ExpireSilencesInternalServerError) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(500)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
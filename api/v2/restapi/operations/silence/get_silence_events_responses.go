@@ -0,0 +1,75 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// GetSilenceEventsOKCode is the HTTP code returned for type GetSilenceEventsOK
+const GetSilenceEventsOKCode int = 200
+
+/*GetSilenceEventsOK Get silence events response
+
+swagger:response getSilenceEventsOK
+*/
+type GetSilenceEventsOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload models.SilenceEvents `json:"body,omitempty"`
+}
+
+// NewGetSilenceEventsOK creates GetSilenceEventsOK with default headers values
+func NewGetSilenceEventsOK() *GetSilenceEventsOK {
+
+	return &GetSilenceEventsOK{}
+}
+
+// WithPayload adds the payload to the get silence events o k response
+func (o *GetSilenceEventsOK) WithPayload(payload models.SilenceEvents) *GetSilenceEventsOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get silence events o k response
+func (o *GetSilenceEventsOK) SetPayload(payload models.SilenceEvents) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetSilenceEventsOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if payload == nil {
+		// return empty array
+		payload = models.SilenceEvents{}
+	}
+
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
@@ -0,0 +1,170 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NewGetSilenceOccurrencesParams creates a new GetSilenceOccurrencesParams object
+// with the default values initialized.
+func NewGetSilenceOccurrencesParams() GetSilenceOccurrencesParams {
+
+	var (
+		// initialize parameters with default values
+
+		limitDefault = int64(10)
+	)
+
+	return GetSilenceOccurrencesParams{
+		Limit: &limitDefault,
+	}
+}
+
+// GetSilenceOccurrencesParams contains all the bound params for the get silence occurrences operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters getSilenceOccurrences
+type GetSilenceOccurrencesParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request `json:"-"`
+
+	/*The maximum number of occurrences to return
+	  Maximum: 100
+	  Minimum: 1
+	  In: query
+	  Default: 10
+	*/
+	Limit *int64
+	/*ID of the silence
+	  Required: true
+	  In: path
+	*/
+	SilenceID strfmt.UUID
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls.
+//
+// To ensure default values, the struct must have been initialized with NewGetSilenceOccurrencesParams() beforehand.
+func (o *GetSilenceOccurrencesParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+
+	o.HTTPRequest = r
+
+	qs := runtime.Values(r.URL.Query())
+
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rSilenceID, rhkSilenceID, _ := route.Params.GetOK("silenceID")
+	if err := o.bindSilenceID(rSilenceID, rhkSilenceID, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// bindLimit binds and validates parameter Limit from query.
+func (o *GetSilenceOccurrencesParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		// Default values have been previously initialized by NewGetSilenceOccurrencesParams()
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "int64", raw)
+	}
+	o.Limit = &value
+
+	if err := o.validateLimit(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateLimit carries on validations for parameter Limit
+func (o *GetSilenceOccurrencesParams) validateLimit(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("limit", "query", int64(*o.Limit), 1, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("limit", "query", int64(*o.Limit), 100, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindSilenceID binds and validates parameter SilenceID from path.
+func (o *GetSilenceOccurrencesParams) bindSilenceID(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: true
+	// Parameter is provided by construction from the route
+
+	// Format: uuid
+	value, err := formats.Parse("uuid", raw)
+	if err != nil {
+		return errors.InvalidType("silenceID", "path", "strfmt.UUID", raw)
+	}
+	o.SilenceID = *(value.(*strfmt.UUID))
+
+	if err := o.validateSilenceID(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSilenceID carries on validations for parameter SilenceID
+func (o *GetSilenceOccurrencesParams) validateSilenceID(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("silenceID", "path", "uuid", o.SilenceID.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// GetSilenceOccurrencesOKCode is the HTTP code returned for type GetSilenceOccurrencesOK
+const GetSilenceOccurrencesOKCode int = 200
+
+/*GetSilenceOccurrencesOK Get silence occurrences response
+
+swagger:response getSilenceOccurrencesOK
+*/
+type GetSilenceOccurrencesOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload models.SilenceOccurrences `json:"body,omitempty"`
+}
+
+// NewGetSilenceOccurrencesOK creates GetSilenceOccurrencesOK with default headers values
+func NewGetSilenceOccurrencesOK() *GetSilenceOccurrencesOK {
+
+	return &GetSilenceOccurrencesOK{}
+}
+
+// WithPayload adds the payload to the get silence occurrences o k response
+func (o *GetSilenceOccurrencesOK) WithPayload(payload models.SilenceOccurrences) *GetSilenceOccurrencesOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get silence occurrences o k response
+func (o *GetSilenceOccurrencesOK) SetPayload(payload models.SilenceOccurrences) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetSilenceOccurrencesOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if payload == nil {
+		// return empty array
+		payload = models.SilenceOccurrences{}
+	}
+
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// GetSilenceOccurrencesNotFoundCode is the HTTP code returned for type GetSilenceOccurrencesNotFound
+const GetSilenceOccurrencesNotFoundCode int = 404
+
+/*GetSilenceOccurrencesNotFound A silence with the specified ID was not found
+
+swagger:response getSilenceOccurrencesNotFound
+*/
+type GetSilenceOccurrencesNotFound struct {
+}
+
+// NewGetSilenceOccurrencesNotFound creates GetSilenceOccurrencesNotFound with default headers values
+func NewGetSilenceOccurrencesNotFound() *GetSilenceOccurrencesNotFound {
+
+	return &GetSilenceOccurrencesNotFound{}
+}
+
+// WriteResponse to the client
+func (o *GetSilenceOccurrencesNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
+
+// GetSilenceOccurrencesInternalServerErrorCode is the HTTP code returned for type GetSilenceOccurrencesInternalServerError
+const GetSilenceOccurrencesInternalServerErrorCode int = 500
+
+/*GetSilenceOccurrencesInternalServerError Internal server error
+
+swagger:response getSilenceOccurrencesInternalServerError
+*/
+type GetSilenceOccurrencesInternalServerError struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewGetSilenceOccurrencesInternalServerError creates GetSilenceOccurrencesInternalServerError with default headers values
+func NewGetSilenceOccurrencesInternalServerError() *GetSilenceOccurrencesInternalServerError {
+
+	return &GetSilenceOccurrencesInternalServerError{}
+}
+
+// WithPayload adds the payload to the get silence occurrences internal server error response
+func (o *GetSilenceOccurrencesInternalServerError) WithPayload(payload string) *GetSilenceOccurrencesInternalServerError {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get silence occurrences internal server error response
+func (o *GetSilenceOccurrencesInternalServerError) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetSilenceOccurrencesInternalServerError) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(500)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/swag"
 
 	"github.com/prometheus/alertmanager/api/v2/models"
 )
@@ -35,6 +36,10 @@ const GetSilencesOKCode int = 200
 swagger:response getSilencesOK
 */
 type GetSilencesOK struct {
+	/*The number of silences matching the filters, regardless of limit and offset
+
+	 */
+	XTotalCount int64 `json:"X-Total-Count"`
 
 	/*
 	  In: Body
@@ -48,6 +53,17 @@ func NewGetSilencesOK() *GetSilencesOK {
 	return &GetSilencesOK{}
 }
 
+// WithXTotalCount adds the xTotalCount to the get silences o k response
+func (o *GetSilencesOK) WithXTotalCount(xTotalCount int64) *GetSilencesOK {
+	o.XTotalCount = xTotalCount
+	return o
+}
+
+// SetXTotalCount sets the xTotalCount to the get silences o k response
+func (o *GetSilencesOK) SetXTotalCount(xTotalCount int64) {
+	o.XTotalCount = xTotalCount
+}
+
 // WithPayload adds the payload to the get silences o k response
 func (o *GetSilencesOK) WithPayload(payload models.GettableSilences) *GetSilencesOK {
 	o.Payload = payload
@@ -62,6 +78,13 @@ func (o *GetSilencesOK) SetPayload(payload models.GettableSilences) {
 // WriteResponse to the client
 func (o *GetSilencesOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
+	// response header X-Total-Count
+
+	xTotalCount := swag.FormatInt64(o.XTotalCount)
+	if xTotalCount != "" {
+		rw.Header().Set("X-Total-Count", xTotalCount)
+	}
+
 	rw.WriteHeader(200)
 	payload := o.Payload
 	if payload == nil {
@@ -74,6 +97,48 @@ func (o *GetSilencesOK) WriteResponse(rw http.ResponseWriter, producer runtime.P
 	}
 }
 
+// GetSilencesBadRequestCode is the HTTP code returned for type GetSilencesBadRequest
+const GetSilencesBadRequestCode int = 400
+
+/*GetSilencesBadRequest Bad request
+
+swagger:response getSilencesBadRequest
+*/
+type GetSilencesBadRequest struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewGetSilencesBadRequest creates GetSilencesBadRequest with default headers values
+func NewGetSilencesBadRequest() *GetSilencesBadRequest {
+
+	return &GetSilencesBadRequest{}
+}
+
+// WithPayload adds the payload to the get silences bad request response
+func (o *GetSilencesBadRequest) WithPayload(payload string) *GetSilencesBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get silences bad request response
+func (o *GetSilencesBadRequest) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetSilencesBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
 // GetSilencesInternalServerErrorCode is the HTTP code returned for type GetSilencesInternalServerError
 const GetSilencesInternalServerErrorCode int = 500
 
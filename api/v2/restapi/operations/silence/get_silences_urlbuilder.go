@@ -24,12 +24,19 @@ import (
 	"net/url"
 	golangswaggerpaths "path"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 )
 
 // GetSilencesURL generates an URL for the get silences operation
 type GetSilencesURL struct {
-	Filter []string
+	CreatedBy *string
+	Filter    []string
+	Limit     *int64
+	Offset    *int64
+	Since     *strfmt.DateTime
+	State     []string
+	Until     *strfmt.DateTime
 
 	_basePath string
 	// avoid unkeyed usage
@@ -62,6 +69,14 @@ func (o *GetSilencesURL) Build() (*url.URL, error) {
 
 	qs := make(url.Values)
 
+	var createdByQ string
+	if o.CreatedBy != nil {
+		createdByQ = *o.CreatedBy
+	}
+	if createdByQ != "" {
+		qs.Set("createdBy", createdByQ)
+	}
+
 	var filterIR []string
 	for _, filterI := range o.Filter {
 		filterIS := filterI
@@ -76,6 +91,52 @@ func (o *GetSilencesURL) Build() (*url.URL, error) {
 		qs.Add("filter", qsv)
 	}
 
+	var limitQ string
+	if o.Limit != nil {
+		limitQ = swag.FormatInt64(*o.Limit)
+	}
+	if limitQ != "" {
+		qs.Set("limit", limitQ)
+	}
+
+	var offsetQ string
+	if o.Offset != nil {
+		offsetQ = swag.FormatInt64(*o.Offset)
+	}
+	if offsetQ != "" {
+		qs.Set("offset", offsetQ)
+	}
+
+	var sinceQ string
+	if o.Since != nil {
+		sinceQ = o.Since.String()
+	}
+	if sinceQ != "" {
+		qs.Set("since", sinceQ)
+	}
+
+	var stateIR []string
+	for _, stateI := range o.State {
+		stateIS := stateI
+		if stateIS != "" {
+			stateIR = append(stateIR, stateIS)
+		}
+	}
+
+	state := swag.JoinByFormat(stateIR, "multi")
+
+	for _, qsv := range state {
+		qs.Add("state", qsv)
+	}
+
+	var untilQ string
+	if o.Until != nil {
+		untilQ = o.Until.String()
+	}
+	if untilQ != "" {
+		qs.Set("until", untilQ)
+	}
+
 	_result.RawQuery = qs.Encode()
 
 	return &_result, nil
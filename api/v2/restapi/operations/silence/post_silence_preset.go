@@ -0,0 +1,106 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// PostSilencePresetHandlerFunc turns a function with the right signature into a post silence preset handler
+type PostSilencePresetHandlerFunc func(PostSilencePresetParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn PostSilencePresetHandlerFunc) Handle(params PostSilencePresetParams) middleware.Responder {
+	return fn(params)
+}
+
+// PostSilencePresetHandler interface for that can handle valid post silence preset params
+type PostSilencePresetHandler interface {
+	Handle(PostSilencePresetParams) middleware.Responder
+}
+
+// NewPostSilencePreset creates a new http.Handler for the post silence preset operation
+func NewPostSilencePreset(ctx *middleware.Context, handler PostSilencePresetHandler) *PostSilencePreset {
+	return &PostSilencePreset{Context: ctx, Handler: handler}
+}
+
+/*PostSilencePreset swagger:route POST /silences/presets/{presetName} silence postSilencePreset
+
+Create a silence from a silence preset
+
+*/
+type PostSilencePreset struct {
+	Context *middleware.Context
+	Handler PostSilencePresetHandler
+}
+
+func (o *PostSilencePreset) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		r = rCtx
+	}
+	var Params = NewPostSilencePresetParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
+
+// PostSilencePresetOKBody post silence preset o k body
+//
+// swagger:model PostSilencePresetOKBody
+type PostSilencePresetOKBody struct {
+
+	// silence ID
+	SilenceID string `json:"silenceID,omitempty"`
+}
+
+// Validate validates this post silence preset o k body
+func (o *PostSilencePresetOKBody) Validate(formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (o *PostSilencePresetOKBody) MarshalBinary() ([]byte, error) {
+	if o == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(o)
+}
+
+// UnmarshalBinary interface implementation
+func (o *PostSilencePresetOKBody) UnmarshalBinary(b []byte) error {
+	var res PostSilencePresetOKBody
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*o = res
+	return nil
+}
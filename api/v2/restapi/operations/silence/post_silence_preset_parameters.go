@@ -0,0 +1,117 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// NewPostSilencePresetParams creates a new PostSilencePresetParams object
+// no default values defined in spec.
+func NewPostSilencePresetParams() PostSilencePresetParams {
+
+	return PostSilencePresetParams{}
+}
+
+// PostSilencePresetParams contains all the bound params for the post silence preset operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters postSilencePreset
+type PostSilencePresetParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request `json:"-"`
+
+	/*The parameters of the silence
+	  Required: true
+	  In: body
+	*/
+	Instantiation *models.SilencePresetInstantiation
+	/*Name of the silence preset
+	  Required: true
+	  In: path
+	*/
+	PresetName string
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls.
+//
+// To ensure default values, the struct must have been initialized with NewPostSilencePresetParams() beforehand.
+func (o *PostSilencePresetParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+
+	o.HTTPRequest = r
+
+	if runtime.HasBody(r) {
+		defer r.Body.Close()
+		var body models.SilencePresetInstantiation
+		if err := route.Consumer.Consume(r.Body, &body); err != nil {
+			if err == io.EOF {
+				res = append(res, errors.Required("instantiation", "body", ""))
+			} else {
+				res = append(res, errors.NewParseError("instantiation", "body", "", err))
+			}
+		} else {
+			// validate body object
+			if err := body.Validate(route.Formats); err != nil {
+				res = append(res, err)
+			}
+
+			if len(res) == 0 {
+				o.Instantiation = &body
+			}
+		}
+	} else {
+		res = append(res, errors.Required("instantiation", "body", ""))
+	}
+	rPresetName, rhkPresetName, _ := route.Params.GetOK("presetName")
+	if err := o.bindPresetName(rPresetName, rhkPresetName, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// bindPresetName binds and validates parameter PresetName from path.
+func (o *PostSilencePresetParams) bindPresetName(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: true
+	// Parameter is provided by construction from the route
+
+	o.PresetName = raw
+
+	return nil
+}
@@ -0,0 +1,136 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+)
+
+// PostSilencePresetOKCode is the HTTP code returned for type PostSilencePresetOK
+const PostSilencePresetOKCode int = 200
+
+/*PostSilencePresetOK Create silence response
+
+swagger:response postSilencePresetOK
+*/
+type PostSilencePresetOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *PostSilencePresetOKBody `json:"body,omitempty"`
+}
+
+// NewPostSilencePresetOK creates PostSilencePresetOK with default headers values
+func NewPostSilencePresetOK() *PostSilencePresetOK {
+
+	return &PostSilencePresetOK{}
+}
+
+// WithPayload adds the payload to the post silence preset o k response
+func (o *PostSilencePresetOK) WithPayload(payload *PostSilencePresetOKBody) *PostSilencePresetOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post silence preset o k response
+func (o *PostSilencePresetOK) SetPayload(payload *PostSilencePresetOKBody) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostSilencePresetOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+// PostSilencePresetBadRequestCode is the HTTP code returned for type PostSilencePresetBadRequest
+const PostSilencePresetBadRequestCode int = 400
+
+/*PostSilencePresetBadRequest Bad request
+
+swagger:response postSilencePresetBadRequest
+*/
+type PostSilencePresetBadRequest struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewPostSilencePresetBadRequest creates PostSilencePresetBadRequest with default headers values
+func NewPostSilencePresetBadRequest() *PostSilencePresetBadRequest {
+
+	return &PostSilencePresetBadRequest{}
+}
+
+// WithPayload adds the payload to the post silence preset bad request response
+func (o *PostSilencePresetBadRequest) WithPayload(payload string) *PostSilencePresetBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post silence preset bad request response
+func (o *PostSilencePresetBadRequest) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostSilencePresetBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// PostSilencePresetNotFoundCode is the HTTP code returned for type PostSilencePresetNotFound
+const PostSilencePresetNotFoundCode int = 404
+
+/*PostSilencePresetNotFound A silence preset with the specified name was not found
+
+swagger:response postSilencePresetNotFound
+*/
+type PostSilencePresetNotFound struct {
+}
+
+// NewPostSilencePresetNotFound creates PostSilencePresetNotFound with default headers values
+func NewPostSilencePresetNotFound() *PostSilencePresetNotFound {
+
+	return &PostSilencePresetNotFound{}
+}
+
+// WriteResponse to the client
+func (o *PostSilencePresetNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
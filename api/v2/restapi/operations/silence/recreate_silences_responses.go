@@ -0,0 +1,156 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package silence
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// RecreateSilencesOKCode is the HTTP code returned for type RecreateSilencesOK
+const RecreateSilencesOKCode int = 200
+
+/*RecreateSilencesOK Re-create silences response
+
+swagger:response recreateSilencesOK
+*/
+type RecreateSilencesOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *models.BulkSilenceResult `json:"body,omitempty"`
+}
+
+// NewRecreateSilencesOK creates RecreateSilencesOK with default headers values
+func NewRecreateSilencesOK() *RecreateSilencesOK {
+
+	return &RecreateSilencesOK{}
+}
+
+// WithPayload adds the payload to the recreate silences o k response
+func (o *RecreateSilencesOK) WithPayload(payload *models.BulkSilenceResult) *RecreateSilencesOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the recreate silences o k response
+func (o *RecreateSilencesOK) SetPayload(payload *models.BulkSilenceResult) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *RecreateSilencesOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+// RecreateSilencesBadRequestCode is the HTTP code returned for type RecreateSilencesBadRequest
+const RecreateSilencesBadRequestCode int = 400
+
+/*RecreateSilencesBadRequest Bad request
+
+swagger:response recreateSilencesBadRequest
+*/
+type RecreateSilencesBadRequest struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewRecreateSilencesBadRequest creates RecreateSilencesBadRequest with default headers values
+func NewRecreateSilencesBadRequest() *RecreateSilencesBadRequest {
+
+	return &RecreateSilencesBadRequest{}
+}
+
+// WithPayload adds the payload to the recreate silences bad request response
+func (o *RecreateSilencesBadRequest) WithPayload(payload string) *RecreateSilencesBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the recreate silences bad request response
+func (o *RecreateSilencesBadRequest) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *RecreateSilencesBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// RecreateSilencesInternalServerErrorCode is the HTTP code returned for type RecreateSilencesInternalServerError
+const RecreateSilencesInternalServerErrorCode int = 500
+
+/*RecreateSilencesInternalServerError Internal server error
+
+swagger:response recreateSilencesInternalServerError
+*/
+type RecreateSilencesInternalServerError struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewRecreateSilencesInternalServerError creates RecreateSilencesInternalServerError with default headers values
+func NewRecreateSilencesInternalServerError() *RecreateSilencesInternalServerError {
+
+	return &RecreateSilencesInternalServerError{}
+}
+
+// WithPayload adds the payload to the recreate silences internal server error response
+func (o *RecreateSilencesInternalServerError) WithPayload(payload string) *RecreateSilencesInternalServerError {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the recreate silences internal server error response
+func (o *RecreateSilencesInternalServerError) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *RecreateSilencesInternalServerError) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(500)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/go-openapi/runtime/middleware"
+	prometheus_model "github.com/prometheus/common/model"
+
+	open_api_models "github.com/prometheus/alertmanager/api/v2/models"
+	alert_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/alert"
+	"github.com/prometheus/alertmanager/config"
+)
+
+// maxStatsWindows bounds the number of windows of a stats request.
+const maxStatsWindows = 10
+
+// defaultStatsWindows are the windows of stats requests without any.
+var defaultStatsWindows = []string{"1h", "24h"}
+
+// statsWindow counts the alerts firing at any time in a window ending now.
+type statsWindow struct {
+	start time.Time
+	res   *open_api_models.AlertStatsWindow
+}
+
+func (w *statsWindow) add(name, severity prometheus_model.LabelValue, receivers []string) {
+	*w.res.Total++
+	w.res.ByAlertname[string(name)]++
+	if severity != "" {
+		w.res.BySeverity[string(severity)]++
+	}
+	for _, r := range receivers {
+		w.res.ByReceiver[r]++
+	}
+}
+
+func (api *API) getAlertStatsHandler(params alert_ops.GetAlertStatsParams) middleware.Responder {
+	var (
+		ctx    = params.HTTPRequest.Context()
+		logger = api.requestLogger(params.HTTPRequest)
+		now    = time.Now()
+	)
+
+	matchers, err := parseFilter(params.Filter)
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to parse matchers", "err", err)
+		return alert_ops.NewGetAlertStatsBadRequest().WithPayload(err.Error())
+	}
+	matchers = api.tenantMatchers(params.HTTPRequest, matchers)
+
+	names := params.Window
+	if len(names) == 0 {
+		names = defaultStatsWindows
+	}
+	if len(names) > maxStatsWindows {
+		return alert_ops.NewGetAlertStatsBadRequest().WithPayload(fmt.Sprintf("too many windows, the maximum is %d", maxStatsWindows))
+	}
+	windows := make([]*statsWindow, 0, len(names))
+	for _, name := range names {
+		d, err := prometheus_model.ParseDuration(name)
+		if err != nil || d <= 0 {
+			return alert_ops.NewGetAlertStatsBadRequest().WithPayload(fmt.Sprintf("invalid window %q", name))
+		}
+		name := name
+		windows = append(windows, &statsWindow{
+			start: now.Add(-time.Duration(d)),
+			res: &open_api_models.AlertStatsWindow{
+				Window:      &name,
+				Total:       new(int64),
+				ByAlertname: open_api_models.AlertCounts{},
+				BySeverity:  open_api_models.AlertCounts{},
+				ByReceiver:  open_api_models.AlertCounts{},
+			},
+		})
+	}
+
+	alerts := api.alerts.GetPending()
+	defer alerts.Close()
+
+	api.mtx.RLock()
+	severityLabel := config.DefaultGlobalConfig().SeverityLabel
+	if api.alertmanagerConfig != nil && api.alertmanagerConfig.Global != nil {
+		severityLabel = api.alertmanagerConfig.Global.SeverityLabel
+	}
+	for a := range alerts.Next() {
+		if err = alerts.Err(); err != nil {
+			break
+		}
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if a.StartsAt.After(now) || !alertMatchesFilterLabels(&a.Alert, matchers) {
+			continue
+		}
+
+		var receivers []string
+		for _, w := range windows {
+			// Alerts resolved before the window didn't fire in it.
+			if !a.EndsAt.IsZero() && a.EndsAt.Before(w.start) {
+				continue
+			}
+			if receivers == nil {
+				receivers = []string{}
+				for _, r := range api.route.MatchAlert(a) {
+					receivers = append(receivers, r.RouteOpts.Receiver)
+				}
+			}
+			w.add(a.Labels[prometheus_model.AlertNameLabel], a.Labels[severityLabel], receivers)
+		}
+	}
+	api.mtx.RUnlock()
+
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to get alerts", "err", err)
+		return alert_ops.NewGetAlertStatsInternalServerError().WithPayload(err.Error())
+	}
+
+	res := &open_api_models.AlertStats{Windows: make([]*open_api_models.AlertStatsWindow, 0, len(windows))}
+	for _, w := range windows {
+		res.Windows = append(res.Windows, w.res)
+	}
+	return alert_ops.NewGetAlertStatsOK().WithPayload(res)
+}
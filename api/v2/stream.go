@@ -0,0 +1,248 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	prometheus_model "github.com/prometheus/common/model"
+
+	open_api_models "github.com/prometheus/alertmanager/api/v2/models"
+	alert_ops "github.com/prometheus/alertmanager/api/v2/restapi/operations/alert"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const (
+	// streamCheckInterval is how often the streamed alerts are checked for
+	// changes which are not caused by an update of the alert, such as being
+	// silenced or timing out.
+	streamCheckInterval = 10 * time.Second
+	// streamBufferSize is the number of alert updates buffered for a stream.
+	// Clients falling further behind are disconnected.
+	streamBufferSize = 1000
+)
+
+type alertEvent struct {
+	typ    string
+	alert  *types.Alert
+	status types.AlertStatus
+}
+
+type streamedAlert struct {
+	alert     *types.Alert
+	silenced  bool
+	inhibited bool
+}
+
+// alertStream turns the updates of alerts into the events sent to a
+// subscriber.
+type alertStream struct {
+	matchers []*labels.Matcher
+	status   func(*types.Alert) types.AlertStatus
+	alerts   map[prometheus_model.Fingerprint]*streamedAlert
+}
+
+func newAlertStream(matchers []*labels.Matcher, status func(*types.Alert) types.AlertStatus) *alertStream {
+	return &alertStream{
+		matchers: matchers,
+		status:   status,
+		alerts:   map[prometheus_model.Fingerprint]*streamedAlert{},
+	}
+}
+
+// update returns the event caused by an update of the alert, if any.
+func (s *alertStream) update(a *types.Alert, now time.Time) (alertEvent, bool) {
+	if !alertMatchesFilterLabels(&a.Alert, s.matchers) {
+		return alertEvent{}, false
+	}
+	fp := a.Fingerprint()
+	prev, ok := s.alerts[fp]
+	if a.ResolvedAt(now) {
+		if !ok {
+			return alertEvent{}, false
+		}
+		delete(s.alerts, fp)
+		return alertEvent{typ: open_api_models.AlertEventTypeResolved, alert: a, status: s.status(a)}, true
+	}
+
+	status := s.status(a)
+	cur := &streamedAlert{
+		alert:     a,
+		silenced:  len(status.SilencedBy) > 0,
+		inhibited: len(status.InhibitedBy) > 0,
+	}
+	s.alerts[fp] = cur
+	if !ok {
+		return alertEvent{typ: open_api_models.AlertEventTypeNew, alert: a, status: status}, true
+	}
+	if typ, changed := statusEvent(prev, cur); changed {
+		return alertEvent{typ: typ, alert: a, status: status}, true
+	}
+	return alertEvent{typ: open_api_models.AlertEventTypeUpdated, alert: a, status: status}, true
+}
+
+// check returns the events of the alerts which timed out or whose silenced
+// or inhibited state changed since they were last seen.
+func (s *alertStream) check(now time.Time) []alertEvent {
+	var events []alertEvent
+	for fp, prev := range s.alerts {
+		a := prev.alert
+		status := s.status(a)
+		if a.ResolvedAt(now) {
+			delete(s.alerts, fp)
+			events = append(events, alertEvent{typ: open_api_models.AlertEventTypeResolved, alert: a, status: status})
+			continue
+		}
+		cur := &streamedAlert{
+			alert:     a,
+			silenced:  len(status.SilencedBy) > 0,
+			inhibited: len(status.InhibitedBy) > 0,
+		}
+		s.alerts[fp] = cur
+		if typ, changed := statusEvent(prev, cur); changed {
+			events = append(events, alertEvent{typ: typ, alert: a, status: status})
+		}
+	}
+	return events
+}
+
+// statusEvent returns the type of the event caused by a change of the
+// silenced or inhibited state of an alert.
+func statusEvent(prev, cur *streamedAlert) (string, bool) {
+	switch {
+	case cur.silenced && !prev.silenced:
+		return open_api_models.AlertEventTypeSilenced, true
+	case cur.inhibited && !prev.inhibited:
+		return open_api_models.AlertEventTypeInhibited, true
+	case !cur.silenced && !cur.inhibited && (prev.silenced || prev.inhibited):
+		return open_api_models.AlertEventTypeActive, true
+	}
+	return "", false
+}
+
+func (api *API) streamAlertsHandler(params alert_ops.StreamAlertsParams) middleware.Responder {
+	logger := api.requestLogger(params.HTTPRequest)
+
+	matchers, err := parseFilter(params.Filter)
+	if err != nil {
+		level.Error(logger).Log("msg", "Failed to parse matchers", "err", err)
+		return alert_ops.NewStreamAlertsBadRequest().WithPayload(err.Error())
+	}
+	matchers = api.tenantMatchers(params.HTTPRequest, matchers)
+
+	return middleware.ResponderFunc(func(w http.ResponseWriter, _ runtime.Producer) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		ctx, cancel := context.WithCancel(params.HTTPRequest.Context())
+		defer cancel()
+
+		// The alert provider blocks on slow subscribers, so the updates are
+		// read independently of the client.
+		it := api.alerts.Subscribe()
+		defer it.Close()
+		updates := make(chan *types.Alert, streamBufferSize)
+		go func() {
+			overflowed := false
+			for a := range it.Next() {
+				if overflowed {
+					continue
+				}
+				select {
+				case updates <- a:
+				default:
+					level.Warn(logger).Log("msg", "Closing alert stream of slow client")
+					overflowed = true
+					cancel()
+				}
+			}
+		}()
+
+		stream := newAlertStream(matchers, api.alertStatus)
+		ticker := time.NewTicker(streamCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			var events []alertEvent
+			select {
+			case <-ctx.Done():
+				return
+			case a := <-updates:
+				if ev, ok := stream.update(a, time.Now()); ok {
+					events = append(events, ev)
+				}
+			case <-ticker.C:
+				events = stream.check(time.Now())
+			}
+			if len(events) == 0 {
+				continue
+			}
+			for _, ev := range events {
+				if err := api.writeAlertEvent(w, ev); err != nil {
+					level.Debug(logger).Log("msg", "Failed to write alert event", "err", err)
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	})
+}
+
+// alertStatus returns the current status of the alert.
+func (api *API) alertStatus(a *types.Alert) types.AlertStatus {
+	api.mtx.RLock()
+	setAlertStatus := api.setAlertStatus
+	api.mtx.RUnlock()
+
+	if setAlertStatus != nil {
+		setAlertStatus(a.Labels)
+	}
+	return api.getAlertStatus(a.Fingerprint())
+}
+
+func (api *API) writeAlertEvent(w http.ResponseWriter, ev alertEvent) error {
+	api.mtx.RLock()
+	var receivers []string
+	if api.route != nil {
+		for _, r := range api.route.MatchAlert(ev.alert) {
+			receivers = append(receivers, r.RouteOpts.Receiver)
+		}
+	}
+	api.mtx.RUnlock()
+
+	b, err := json.Marshal(&open_api_models.AlertEvent{
+		Type:  &ev.typ,
+		Alert: AlertToOpenAPIAlert(ev.alert, ev.status, receivers),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.typ, b)
+	return err
+}
@@ -0,0 +1,196 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	prometheus_model "github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/provider/shard"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// resolveTenant returns the label holding the tenant of the alerts and the
+// tenant the request is scoped to, which is the tenant of its client. Admins
+// act as the tenant named by the tenant header, or across all tenants without
+// it, and so do the requests forwarding alerts from other cluster members,
+// which were scoped when the member received them. The request isn't scoped
+// if tenancy is disabled. It fails if the client has no tenant or names
+// another one.
+func (api *API) resolveTenant(req *http.Request) (prometheus_model.LabelName, string, bool, error) {
+	api.mtx.RLock()
+	var (
+		tc *config.TenancyConfig
+		ac *config.AuthorizationConfig
+	)
+	if api.alertmanagerConfig != nil {
+		tc, ac = api.alertmanagerConfig.Tenancy, api.alertmanagerConfig.Authorization
+	}
+	api.mtx.RUnlock()
+
+	if tc == nil || shard.Forwarded(req.Context()) {
+		return "", "", false, nil
+	}
+	identity := auth.Identity(req, false)
+	header := req.Header.Get(tc.Header)
+	if t, ok := tc.Tenant(identity); ok {
+		if header != "" && header != t {
+			return tc.Label, "", false, fmt.Errorf("%q may not act as tenant %q", identity, header)
+		}
+		return tc.Label, t, true, nil
+	}
+	if ac != nil && ac.Role(identity, auth.Groups(req)...).Allows(config.RoleAdmin) {
+		return tc.Label, header, header != "", nil
+	}
+	return tc.Label, "", false, fmt.Errorf("%q has no tenant", identity)
+}
+
+// tenant returns the label holding the tenant of the alerts and the tenant
+// the request is scoped to, like resolveTenant. Requests which fail to
+// resolve their tenant are rejected by tenantHandler, and are otherwise
+// scoped to no tenant.
+func (api *API) tenant(req *http.Request) (prometheus_model.LabelName, string, bool) {
+	ln, t, ok, err := api.resolveTenant(req)
+	if err != nil {
+		return ln, "", true
+	}
+	return ln, t, ok
+}
+
+// tenantHandler rejects the requests whose tenant can't be resolved.
+func (api *API) tenantHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, _, _, err := api.resolveTenant(req); err != nil {
+			level.Debug(api.logger).Log("msg", "Request rejected by tenancy", "err", err)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// ownsReceiver returns whether the receiver belongs to the tenant of the
+// request, which owns the receivers of its overlay.
+func (api *API) ownsReceiver(req *http.Request, name string) bool {
+	_, t, ok := api.tenant(req)
+	return !ok || strings.HasPrefix(name, t+"/")
+}
+
+// tenantMatchers returns the matchers restricted to the alerts of the tenant
+// of the request.
+func (api *API) tenantMatchers(req *http.Request, matchers []*labels.Matcher) []*labels.Matcher {
+	ln, t, ok := api.tenant(req)
+	if !ok {
+		return matchers
+	}
+	return append(matchers, &labels.Matcher{Type: labels.MatchEqual, Name: string(ln), Value: t})
+}
+
+// scopeAlerts labels the alerts with the tenant of the request.
+func (api *API) scopeAlerts(req *http.Request, alerts []*types.Alert) {
+	ln, t, ok := api.tenant(req)
+	if !ok {
+		return
+	}
+	for _, a := range alerts {
+		if a.Labels == nil {
+			a.Labels = prometheus_model.LabelSet{}
+		}
+		a.Labels[ln] = prometheus_model.LabelValue(t)
+	}
+}
+
+// scopeSilence restricts the silence to the alerts of the tenant of the
+// request, replacing the matchers of the tenant label.
+func (api *API) scopeSilence(req *http.Request, sil *silencepb.Silence) {
+	ln, t, ok := api.tenant(req)
+	if !ok {
+		return
+	}
+	matchers := sil.Matchers[:0]
+	for _, m := range sil.Matchers {
+		if m.Name != string(ln) {
+			matchers = append(matchers, m)
+		}
+	}
+	sil.Matchers = append(matchers, &silencepb.Matcher{
+		Type:    silencepb.Matcher_EQUAL,
+		Name:    string(ln),
+		Pattern: t,
+	})
+}
+
+// ownsSilence returns whether the silence belongs to the tenant of the
+// request. Requests which aren't scoped to a tenant own every silence.
+func (api *API) ownsSilence(req *http.Request, sil *silencepb.Silence) bool {
+	ln, t, ok := api.tenant(req)
+	if !ok {
+		return true
+	}
+	for _, m := range sil.Matchers {
+		if m.Type == silencepb.Matcher_EQUAL && m.Name == string(ln) && m.Pattern == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsGroup returns whether the aggregation group with the given labels
+// belongs to the tenant of the request. As the tenant label is part of the
+// grouping of every route, the group labels hold the tenant.
+func (api *API) ownsGroup(req *http.Request, groupLabels prometheus_model.LabelSet) bool {
+	ln, t, ok := api.tenant(req)
+	return !ok || groupLabels[ln] == prometheus_model.LabelValue(t)
+}
+
+// ownsGroupKey returns whether the aggregation group with the given key
+// belongs to the tenant of the request.
+func (api *API) ownsGroupKey(req *http.Request, groupKey string) bool {
+	return api.ownedGroupKeys(req)(groupKey)
+}
+
+// ownedGroupKeys returns a function telling whether the aggregation group
+// with the given key belongs to the tenant of the request. The groups are
+// listed once, so that it can be called for every acknowledgement of a
+// request. Only the groups currently holding alerts are known.
+func (api *API) ownedGroupKeys(req *http.Request) func(groupKey string) bool {
+	ln, t, ok := api.tenant(req)
+	if !ok {
+		return func(string) bool { return true }
+	}
+	groups, _ := api.alertGroups(
+		func(*dispatch.Route) bool { return true },
+		func(*types.Alert, time.Time) bool { return true },
+	)
+	owned := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		if g.Labels[ln] == prometheus_model.LabelValue(t) {
+			owned[g.GroupKey] = struct{}{}
+		}
+	}
+	return func(groupKey string) bool {
+		_, ok := owned[groupKey]
+		return ok
+	}
+}
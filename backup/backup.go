@@ -0,0 +1,244 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup uploads snapshots of the silences and the notification log
+// to object storage and downloads them again, so that a rebuilt instance
+// recovers its state.
+//
+// Buckets are addressed by URLs like s3://bucket/prefix. Google Cloud Storage
+// buckets, addressed by gs://bucket/prefix, are accessed through its
+// S3-compatible API and require HMAC keys. Credentials are read from the
+// default AWS credential chain, e.g. the AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY environment variables.
+package backup
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Names of the snapshots held by a bucket.
+const (
+	SnapshotSilences = "silences"
+	SnapshotNflog    = "nflog"
+)
+
+// gcsEndpoint is the endpoint of the S3-compatible API of Google Cloud
+// Storage.
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// Snapshot writes a snapshot, e.g. (*silence.Silences).Snapshot.
+type Snapshot func(io.Writer) (int64, error)
+
+// Bucket holds snapshots in object storage. All methods are goroutine-safe.
+type Bucket struct {
+	client  s3iface.S3API
+	bucket  string
+	prefix  string
+	logger  log.Logger
+	metrics *metrics
+}
+
+type metrics struct {
+	uploadFailures prometheus.Counter
+	lastUpload     prometheus.Gauge
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		uploadFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_backup_upload_failures_total",
+			Help: "Total number of failed uploads of snapshots to object storage.",
+		}),
+		lastUpload: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alertmanager_backup_last_upload_timestamp_seconds",
+			Help: "Timestamp of the last successful upload of snapshots to object storage.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(m.uploadFailures, m.lastUpload)
+	}
+	return m
+}
+
+// New returns a bucket addressed by the given URL, i.e.
+// s3://bucket/prefix or gs://bucket/prefix. The region and endpoint of S3
+// buckets can be set by the region and endpoint query parameters, e.g.
+// s3://bucket/prefix?region=eu-west-1.
+func New(rawurl string, l log.Logger, r prometheus.Registerer) (*Bucket, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("missing bucket in %q", rawurl)
+	}
+	cfg := aws.Config{}
+	switch u.Scheme {
+	case "s3":
+		if region := u.Query().Get("region"); region != "" {
+			cfg.Region = aws.String(region)
+		}
+		if endpoint := u.Query().Get("endpoint"); endpoint != "" {
+			cfg.Endpoint = aws.String(endpoint)
+			cfg.S3ForcePathStyle = aws.Bool(true)
+		}
+	case "gs":
+		cfg.Region = aws.String("auto")
+		cfg.Endpoint = aws.String(gcsEndpoint)
+	default:
+		return nil, errors.Errorf("unsupported scheme %q, must be s3 or gs", u.Scheme)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            cfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if aws.StringValue(sess.Config.Region) == "" {
+		return nil, errors.New("region not configured in the URL or in the default credentials chain")
+	}
+	return newBucket(s3.New(sess), u.Host, u.Path, l, r), nil
+}
+
+func newBucket(c s3iface.S3API, bucket, prefix string, l log.Logger, r prometheus.Registerer) *Bucket {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &Bucket{
+		client:  c,
+		bucket:  bucket,
+		prefix:  prefix,
+		logger:  l,
+		metrics: newMetrics(r),
+	}
+}
+
+// Upload replaces the snapshot with the given name by the one written by the
+// snapshot function. It returns the size of the snapshot.
+func (b *Bucket) Upload(name string, snapshot Snapshot) (int64, error) {
+	var buf bytes.Buffer
+	size, err := snapshot(&buf)
+	if err != nil {
+		return size, err
+	}
+	_, err = b.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.prefix + name),
+		Body:   bytes.NewReader(buf.Bytes()),
+	})
+	return size, err
+}
+
+// Download returns a reader of the snapshot with the given name, or nil if
+// there is none.
+func (b *Bucket) Download(name string) (io.Reader, error) {
+	out, err := b.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.prefix + name),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	data, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// RestoreFile downloads the snapshot with the given name to the file at path
+// unless the file already exists. It returns whether the file was restored.
+func (b *Bucket) RestoreFile(name, path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil || !os.IsNotExist(err) {
+		return false, err
+	}
+	r, err := b.Download(name)
+	if err != nil || r == nil {
+		return false, err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return false, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return false, err
+	}
+	return true, os.Rename(f.Name(), path)
+}
+
+// Run uploads the given snapshots at the given interval until stopc is
+// closed, and a last time on shutdown.
+func (b *Bucket) Run(interval time.Duration, stopc <-chan struct{}, snapshots map[string]Snapshot) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			b.uploadAll(snapshots)
+			return
+		case <-t.C:
+			b.uploadAll(snapshots)
+		}
+	}
+}
+
+func (b *Bucket) uploadAll(snapshots map[string]Snapshot) {
+	failed := false
+	for name, snapshot := range snapshots {
+		size, err := b.Upload(name, snapshot)
+		if err != nil {
+			failed = true
+			b.metrics.uploadFailures.Inc()
+			level.Error(b.logger).Log("msg", "Uploading snapshot failed", "name", name, "err", err)
+			continue
+		}
+		level.Debug(b.logger).Log("msg", "Uploaded snapshot", "name", name, "size", size)
+	}
+	if !failed {
+		b.metrics.lastUpload.SetToCurrentTime()
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 holds objects in memory.
+type fakeS3 struct {
+	s3iface.S3API
+
+	mtx     sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}}
+}
+
+func (f *fakeS3) PutObject(in *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	b, err := ioutil.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.objects[aws.StringValue(in.Bucket)+"/"+aws.StringValue(in.Key)] = b
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) GetObject(in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	b, ok := f.objects[aws.StringValue(in.Bucket)+"/"+aws.StringValue(in.Key)]
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func snapshotOf(s string) Snapshot {
+	return func(w io.Writer) (int64, error) {
+		n, err := io.WriteString(w, s)
+		return int64(n), err
+	}
+}
+
+func TestUploadDownload(t *testing.T) {
+	f := newFakeS3()
+	b := newBucket(f, "bucket", "/am/prod/", nil, nil)
+
+	r, err := b.Download(SnapshotSilences)
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	size, err := b.Upload(SnapshotSilences, snapshotOf("state"))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), size)
+	require.Contains(t, f.objects, "bucket/am/prod/silences")
+
+	r, err = b.Download(SnapshotSilences)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "state", string(data))
+}
+
+func TestRestoreFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b := newBucket(newFakeS3(), "bucket", "", nil, nil)
+	path := filepath.Join(dir, "silences")
+
+	// Nothing is restored without a snapshot.
+	restored, err := b.RestoreFile(SnapshotSilences, path)
+	require.NoError(t, err)
+	require.False(t, restored)
+	require.NoFileExists(t, path)
+
+	_, err = b.Upload(SnapshotSilences, snapshotOf("remote"))
+	require.NoError(t, err)
+	restored, err = b.RestoreFile(SnapshotSilences, path)
+	require.NoError(t, err)
+	require.True(t, restored)
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "remote", string(data))
+
+	// Existing files are left untouched.
+	require.NoError(t, ioutil.WriteFile(path, []byte("local"), 0666))
+	restored, err = b.RestoreFile(SnapshotSilences, path)
+	require.NoError(t, err)
+	require.False(t, restored)
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "local", string(data))
+}
+
+func TestRunUploadsOnShutdown(t *testing.T) {
+	f := newFakeS3()
+	b := newBucket(f, "bucket", "", nil, nil)
+
+	stopc := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		b.Run(time.Hour, stopc, map[string]Snapshot{
+			SnapshotSilences: snapshotOf("silences"),
+			SnapshotNflog:    snapshotOf("nflog"),
+		})
+		close(done)
+	}()
+	close(stopc)
+	<-done
+
+	require.Equal(t, []byte("silences"), f.objects["bucket/silences"])
+	require.Equal(t, []byte("nflog"), f.objects["bucket/nflog"])
+}
+
+func TestNewInvalidURL(t *testing.T) {
+	for _, u := range []string{"http://bucket/prefix", "s3:///prefix"} {
+		_, err := New(u, nil, nil)
+		require.Error(t, err, u)
+	}
+}
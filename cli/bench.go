@@ -0,0 +1,335 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/prometheus/alertmanager/api/v2/client/alert"
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+const benchHelp = `Generate synthetic alert load and measure how the Alertmanager copes.
+
+The alerts are labeled with alertname=AmtoolBench, a bench_run label unique to
+the run, a group label and an instance label. They are posted again and again
+at the given rate, like Prometheus does, for the given duration, and resolved
+at the end.
+
+To measure how long alerts take to be notified, and the notification
+throughput, route the alerts to a webhook receiver pointing at the address
+given with --webhook.listen-address:
+
+	route:
+	  routes:
+	  - matchers: [ alertname="AmtoolBench" ]
+	    receiver: bench
+	    group_by: [ group ]
+	receivers:
+	- name: bench
+	  webhook_configs:
+	  - url: http://<amtool host>:9099/
+
+	amtool bench --alerts=5000 --rate=500 --duration=5m --webhook.listen-address=:9099
+`
+
+type benchCmd struct {
+	alerts        int
+	groups        int
+	rate          float64
+	batchSize     int
+	concurrency   int
+	duration      time.Duration
+	drain         time.Duration
+	listenAddress string
+	resolve       bool
+}
+
+func configureBenchCmd(app *kingpin.Application) {
+	var (
+		c        = &benchCmd{}
+		benchCmd = app.Command("bench", benchHelp).PreAction(requireAlertManagerURL)
+	)
+	benchCmd.Flag("alerts", "Number of distinct alerts").Default("1000").IntVar(&c.alerts)
+	benchCmd.Flag("groups", "Number of distinct values of the group label").Default("10").IntVar(&c.groups)
+	benchCmd.Flag("rate", "Alerts posted per second").Default("100").Float64Var(&c.rate)
+	benchCmd.Flag("batch-size", "Alerts posted per request").Default("100").IntVar(&c.batchSize)
+	benchCmd.Flag("concurrency", "Maximum number of concurrent requests").Default("10").IntVar(&c.concurrency)
+	benchCmd.Flag("duration", "How long to post alerts").Default("1m").DurationVar(&c.duration)
+	benchCmd.Flag("drain", "How long to wait for notifications after posting").Default("1m").DurationVar(&c.drain)
+	benchCmd.Flag("webhook.listen-address", "Address to receive the webhook notifications of the alerts on. Notifications aren't measured if empty").StringVar(&c.listenAddress)
+	benchCmd.Flag("resolve", "Resolve the alerts at the end. Use --no-resolve to let them time out").Default("true").BoolVar(&c.resolve)
+	benchCmd.Action(c.bench)
+}
+
+func (c *benchCmd) bench(*kingpin.ParseContext) error {
+	if c.alerts <= 0 || c.groups <= 0 || c.batchSize <= 0 || c.concurrency <= 0 || c.rate <= 0 {
+		return fmt.Errorf("--alerts, --groups, --rate, --batch-size and --concurrency must be positive")
+	}
+	stats := newBenchStats(fmt.Sprintf("%08x", rand.New(rand.NewSource(time.Now().UnixNano())).Uint32()))
+
+	if c.listenAddress != "" {
+		ln, err := net.Listen("tcp", c.listenAddress)
+		if err != nil {
+			return err
+		}
+		stats.listen = true
+		srv := &http.Server{Handler: stats}
+		go srv.Serve(ln)
+		defer srv.Close()
+		fmt.Fprintf(os.Stderr, "Receiving webhook notifications on %s\n", ln.Addr())
+	}
+
+	amclient := NewAlertmanagerClient(alertmanagerURL)
+	post := func(ctx context.Context, alerts models.PostableAlerts) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		_, err := amclient.Alert.PostAlerts(alert.NewPostAlertsParams().WithContext(ctx).WithAlerts(alerts))
+		return err
+	}
+	c.run(context.Background(), stats, post)
+	stats.report(os.Stdout)
+	return nil
+}
+
+// run posts the alerts and waits for their notifications.
+func (c *benchCmd) run(ctx context.Context, stats *benchStats, post func(context.Context, models.PostableAlerts) error) {
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, c.concurrency)
+		interval = time.Duration(float64(time.Second) * float64(c.batchSize) / c.rate)
+		ticker   = time.NewTicker(interval)
+		end      = time.After(c.duration)
+		next     int
+		sent     int
+	)
+	defer ticker.Stop()
+
+	stats.start = time.Now()
+	send := func(batch models.PostableAlerts, ingest bool) {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			start := time.Now()
+			err := post(ctx, batch)
+			if ingest {
+				stats.posted(batch, start, time.Since(start), err)
+			}
+		}()
+	}
+
+loop:
+	for {
+		select {
+		case <-end:
+			break loop
+		case <-ticker.C:
+			batch := make(models.PostableAlerts, 0, c.batchSize)
+			for i := 0; i < c.batchSize; i++ {
+				batch = append(batch, c.alert(stats.run, next, time.Time{}))
+				next = (next + 1) % c.alerts
+			}
+			sent += len(batch)
+			send(batch, true)
+		}
+	}
+	wg.Wait()
+	stats.end = time.Now()
+
+	if stats.listening() {
+		deadline := time.Now().Add(c.drain)
+		for time.Now().Before(deadline) && !stats.allNotified() {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	if c.resolve {
+		// Only the alerts posted at least once need to be resolved.
+		if sent > c.alerts {
+			sent = c.alerts
+		}
+		now := time.Now()
+		for i := 0; i < sent; i += c.batchSize {
+			batch := make(models.PostableAlerts, 0, c.batchSize)
+			for j := i; j < i+c.batchSize && j < sent; j++ {
+				batch = append(batch, c.alert(stats.run, j, now))
+			}
+			send(batch, false)
+		}
+		wg.Wait()
+	}
+}
+
+// alert returns the i-th alert of the run, resolved at endsAt if not zero.
+func (c *benchCmd) alert(run string, i int, endsAt time.Time) *models.PostableAlert {
+	return &models.PostableAlert{
+		Alert: models.Alert{
+			Labels: models.LabelSet{
+				"alertname": "AmtoolBench",
+				"bench_run": run,
+				"group":     fmt.Sprintf("group-%d", i%c.groups),
+				"instance":  fmt.Sprintf("instance-%d", i),
+			},
+		},
+		Annotations: models.LabelSet{"summary": "Synthetic alert generated by amtool bench"},
+		EndsAt:      strfmt.DateTime(endsAt),
+	}
+}
+
+// benchStats collects the measures of a run. It receives the webhook
+// notifications of the alerts.
+type benchStats struct {
+	run        string
+	start, end time.Time
+
+	mtx           sync.Mutex
+	listen        bool
+	requests      int
+	failed        int
+	alerts        int
+	ingestion     []time.Duration
+	firstPosted   map[string]time.Time
+	notified      map[string]struct{}
+	flush         []time.Duration
+	notifications int
+	lastNotified  time.Time
+}
+
+func newBenchStats(run string) *benchStats {
+	return &benchStats{
+		run:         run,
+		firstPosted: map[string]time.Time{},
+		notified:    map[string]struct{}{},
+	}
+}
+
+func (s *benchStats) posted(batch models.PostableAlerts, start time.Time, latency time.Duration, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.requests++
+	if err != nil {
+		s.failed++
+		return
+	}
+	s.alerts += len(batch)
+	s.ingestion = append(s.ingestion, latency)
+	for _, a := range batch {
+		instance := a.Labels["instance"]
+		if _, ok := s.firstPosted[instance]; !ok {
+			s.firstPosted[instance] = start
+		}
+	}
+}
+
+func (s *benchStats) listening() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.listen
+}
+
+func (s *benchStats) allNotified() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.notified) >= len(s.firstPosted)
+}
+
+// ServeHTTP receives the webhook notifications. The latency of the first
+// notification of every alert is measured from the first time it was posted.
+func (s *benchStats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var msg struct {
+		Alerts []struct {
+			Status string            `json:"status"`
+			Labels map[string]string `json:"labels"`
+		} `json:"alerts"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var counted bool
+	for _, a := range msg.Alerts {
+		if a.Labels["bench_run"] != s.run || a.Status != "firing" {
+			continue
+		}
+		counted = true
+		instance := a.Labels["instance"]
+		if _, ok := s.notified[instance]; ok {
+			continue
+		}
+		if first, ok := s.firstPosted[instance]; ok {
+			s.notified[instance] = struct{}{}
+			s.flush = append(s.flush, now.Sub(first))
+		}
+	}
+	if counted {
+		s.notifications++
+		s.lastNotified = now
+	}
+}
+
+// report writes the measures of the run.
+func (s *benchStats) report(w io.Writer) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	elapsed := s.end.Sub(s.start)
+	fmt.Fprintf(w, "Run %s: posted %d alerts in %d requests over %s (%.1f alerts/s), %d requests failed\n",
+		s.run, s.alerts, s.requests, elapsed.Round(time.Millisecond), float64(s.alerts)/elapsed.Seconds(), s.failed)
+	fmt.Fprintf(w, "Ingestion latency: %s\n", quantiles(s.ingestion))
+	if !s.listen {
+		return
+	}
+	var rate float64
+	if d := s.lastNotified.Sub(s.start); s.notifications > 0 && d > 0 {
+		rate = float64(s.notifications) / d.Seconds()
+	}
+	fmt.Fprintf(w, "Notifications: %d received (%.1f/s), %d of %d alerts notified\n", s.notifications, rate, len(s.notified), len(s.firstPosted))
+	fmt.Fprintf(w, "Flush latency: %s\n", quantiles(s.flush))
+}
+
+// quantiles formats the median, the 90th and 99th percentiles and the
+// maximum of the durations.
+func quantiles(ds []time.Duration) string {
+	if len(ds) == 0 {
+		return "no data"
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	q := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))].Round(time.Microsecond)
+	}
+	return fmt.Sprintf("p50=%s p90=%s p99=%s max=%s", q(0.5), q(0.9), q(0.99), q(1))
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+func TestBench(t *testing.T) {
+	c := &benchCmd{
+		alerts:      20,
+		groups:      2,
+		rate:        1000,
+		batchSize:   10,
+		concurrency: 2,
+		duration:    200 * time.Millisecond,
+		drain:       time.Second,
+		resolve:     true,
+	}
+	stats := newBenchStats("test")
+	stats.listen = true
+
+	var (
+		mtx      sync.Mutex
+		resolved = map[string]struct{}{}
+	)
+	post := func(_ context.Context, alerts models.PostableAlerts) error {
+		msg := map[string]interface{}{}
+		var as []map[string]interface{}
+		for _, a := range alerts {
+			require.Equal(t, "AmtoolBench", a.Labels["alertname"])
+			if !time.Time(a.EndsAt).IsZero() {
+				mtx.Lock()
+				resolved[a.Labels["instance"]] = struct{}{}
+				mtx.Unlock()
+				continue
+			}
+			as = append(as, map[string]interface{}{"status": "firing", "labels": a.Labels})
+		}
+		// Notify alerts from another run too, they must be ignored.
+		as = append(as, map[string]interface{}{"status": "firing", "labels": map[string]string{"bench_run": "other", "instance": "instance-0"}})
+		msg["alerts"] = as
+		b, err := json.Marshal(msg)
+		require.NoError(t, err)
+		// The notification is received before the request completes.
+		stats.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", bytes.NewReader(b)))
+		return nil
+	}
+	c.run(context.Background(), stats, post)
+
+	require.Greater(t, stats.requests, 0)
+	require.Equal(t, 0, stats.failed)
+	require.Equal(t, stats.requests*c.batchSize, stats.alerts)
+	require.Len(t, stats.ingestion, stats.requests)
+	require.Equal(t, stats.requests, stats.notifications)
+	require.Len(t, stats.notified, len(stats.firstPosted))
+	require.Len(t, resolved, len(stats.firstPosted))
+
+	var out bytes.Buffer
+	stats.report(&out)
+	require.Contains(t, out.String(), "Run test: posted")
+	require.Contains(t, out.String(), "Flush latency: p50=")
+}
+
+func TestQuantiles(t *testing.T) {
+	require.Equal(t, "no data", quantiles(nil))
+
+	var ds []time.Duration
+	for i := 100; i > 0; i-- {
+		ds = append(ds, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, "p50=50ms p90=90ms p99=99ms max=100ms", quantiles(ds))
+}
@@ -14,7 +14,9 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/prometheus/alertmanager/config"
@@ -32,6 +34,10 @@ const checkConfigHelp = `Validate alertmanager config files
 Will validate the syntax and schema for alertmanager config file
 and associated templates. Non existing templates will not trigger
 errors.
+
+With the json output format, the errors of each file are printed as a list
+of objects with the path of the offending node in the configuration, its
+line and column, and the error message.
 `
 
 func configureCheckConfigCmd(app *kingpin.Application) {
@@ -58,6 +64,9 @@ func CheckConfig(args []string) error {
 		}
 		args = []string{os.Stdin.Name()}
 	}
+	if output == "json" {
+		return checkConfigJSON(args, os.Stdout)
+	}
 
 	failed := 0
 
@@ -99,3 +108,39 @@ func CheckConfig(args []string) error {
 	}
 	return nil
 }
+
+// configCheck is the result of checking a configuration file with the json
+// output format.
+type configCheck struct {
+	File   string          `json:"file"`
+	Errors []*config.Error `json:"errors"`
+}
+
+func checkConfigJSON(args []string, w io.Writer) error {
+	var (
+		checks = make([]configCheck, 0, len(args))
+		failed int
+	)
+	for _, arg := range args {
+		check := configCheck{File: arg, Errors: []*config.Error{}}
+		cfg, err := config.LoadFile(arg)
+		if err != nil {
+			check.Errors = append(check.Errors, config.Errors(err)...)
+		} else if len(cfg.Templates) > 0 {
+			if _, err := template.FromGlobs(cfg.Templates...); err != nil {
+				check.Errors = append(check.Errors, &config.Error{Message: err.Error()})
+			}
+		}
+		if len(check.Errors) > 0 {
+			failed++
+		}
+		checks = append(checks, check)
+	}
+	if err := json.NewEncoder(w).Encode(checks); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to validate %d file(s)", failed)
+	}
+	return nil
+}
@@ -14,7 +14,13 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
 )
 
 func TestCheckConfig(t *testing.T) {
@@ -28,3 +34,20 @@ func TestCheckConfig(t *testing.T) {
 		t.Fatalf("failed to detect invalid file.")
 	}
 }
+
+func TestCheckConfigJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := checkConfigJSON([]string{"testdata/conf.good.yml", "testdata/conf.bad.yml"}, &buf)
+	require.EqualError(t, err, "failed to validate 1 file(s)")
+
+	var checks []configCheck
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &checks))
+	require.Equal(t, []configCheck{
+		{File: "testdata/conf.good.yml", Errors: []*config.Error{}},
+		{File: "testdata/conf.bad.yml", Errors: []*config.Error{{
+			Line:    1,
+			Column:  1,
+			Message: "cannot unmarshal !!str `BAD` into config.Config",
+		}}},
+	}, checks)
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-openapi/strfmt"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/prometheus/alertmanager/api/v2/client/deadletter"
+	"github.com/prometheus/alertmanager/cli/format"
+)
+
+const deadLetterHelp = `View, replay or delete notifications that could not be delivered.
+
+Notifications that failed after all retries were exhausted are kept in the
+dead-letter queue of the Alertmanager that tried to send them. Once the
+downstream provider has recovered, they can be replayed:
+
+amtool deadletter query -r team-X
+
+amtool deadletter replay 5c5a7a8c-9d0c-4d3c-9d32-0f6a5e8b3f1a
+`
+
+type deadLetterQueryCmd struct {
+	receiver string
+}
+
+type deadLetterIDsCmd struct {
+	ids []string
+}
+
+// deadLetterCmd represents the deadletter command
+func configureDeadLetterCmd(app *kingpin.Application) {
+	var (
+		deadLetterCmd = app.Command("deadletter", deadLetterHelp).PreAction(requireAlertManagerURL)
+		q             = &deadLetterQueryCmd{}
+		queryCmd      = deadLetterCmd.Command("query", "View dead-lettered notifications").Default()
+		r             = &deadLetterIDsCmd{}
+		replayCmd     = deadLetterCmd.Command("replay", "Send dead-lettered notifications again")
+		d             = &deadLetterIDsCmd{}
+		deleteCmd     = deadLetterCmd.Command("delete", "Delete dead-lettered notifications without sending them")
+	)
+	queryCmd.Flag("receiver", "Show only notifications of the given receiver").Short('r').StringVar(&q.receiver)
+	queryCmd.Action(execWithTimeout(q.query))
+	replayCmd.Arg("dead-letter-ids", "IDs of dead letters to replay").StringsVar(&r.ids)
+	replayCmd.Action(execWithTimeout(r.replay))
+	deleteCmd.Arg("dead-letter-ids", "IDs of dead letters to delete").StringsVar(&d.ids)
+	deleteCmd.Action(execWithTimeout(d.delete))
+}
+
+func (c *deadLetterQueryCmd) query(ctx context.Context, _ *kingpin.ParseContext) error {
+	params := deadletter.NewGetDeadLettersParams().WithContext(ctx)
+	if c.receiver != "" {
+		params = params.WithReceiver(&c.receiver)
+	}
+
+	amclient := NewAlertmanagerClient(alertmanagerURL)
+	getOk, err := amclient.Deadletter.GetDeadLetters(params)
+	if err != nil {
+		return err
+	}
+
+	formatter, found := format.Formatters[output]
+	if !found {
+		return errors.New("unknown output formatter")
+	}
+	return formatter.FormatDeadLetters(getOk.Payload)
+}
+
+func (c *deadLetterIDsCmd) replay(ctx context.Context, _ *kingpin.ParseContext) error {
+	if len(c.ids) < 1 {
+		return errors.New("no dead letter IDs specified")
+	}
+
+	amclient := NewAlertmanagerClient(alertmanagerURL)
+
+	for _, id := range c.ids {
+		params := deadletter.NewReplayDeadLetterParams().WithContext(ctx)
+		params.DeadLetterID = strfmt.UUID(id)
+		if _, err := amclient.Deadletter.ReplayDeadLetter(params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *deadLetterIDsCmd) delete(ctx context.Context, _ *kingpin.ParseContext) error {
+	if len(c.ids) < 1 {
+		return errors.New("no dead letter IDs specified")
+	}
+
+	amclient := NewAlertmanagerClient(alertmanagerURL)
+
+	for _, id := range c.ids {
+		params := deadletter.NewDeleteDeadLetterParams().WithContext(ctx)
+		params.DeadLetterID = strfmt.UUID(id)
+		if _, err := amclient.Deadletter.DeleteDeadLetter(params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
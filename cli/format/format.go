@@ -41,6 +41,17 @@ type Formatter interface {
 	FormatAlerts([]*models.GettableAlert) error
 	FormatConfig(*models.AlertmanagerStatus) error
 	FormatClusterStatus(status *models.ClusterStatus) error
+	FormatDeadLetters(models.DeadLetters) error
+	FormatStatus(*Status) error
+}
+
+// Status is the status of an Alertmanager along with the result of its
+// health and readiness probes.
+type Status struct {
+	*models.AlertmanagerStatus
+	ConfigHash string `json:"configHash"`
+	Healthy    bool   `json:"healthy"`
+	Ready      bool   `json:"ready"`
 }
 
 // Formatters is a map of cli argument names to formatter interface object.
@@ -112,6 +112,50 @@ func (formatter *ExtendedFormatter) FormatClusterStatus(status *models.ClusterSt
 	return w.Flush()
 }
 
+// FormatStatus formats the status of the Alertmanager with its version info
+// and cluster peers into a readable string.
+func (formatter *ExtendedFormatter) FormatStatus(status *Status) error {
+	w := tabwriter.NewWriter(formatter.writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w,
+		"Version:\t%s\nBranch:\t%s\nRevision:\t%s\nBuild Date:\t%s\nGo Version:\t%s\nUptime:\t%s\nConfig Hash:\t%s\nHealthy:\t%t\nReady:\t%t\n",
+		*status.VersionInfo.Version,
+		*status.VersionInfo.Branch,
+		*status.VersionInfo.Revision,
+		*status.VersionInfo.BuildDate,
+		*status.VersionInfo.GoVersion,
+		FormatDate(*status.Uptime),
+		status.ConfigHash,
+		status.Healthy,
+		status.Ready,
+	)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(formatter.writer)
+	return formatter.FormatClusterStatus(status.Cluster)
+}
+
+// FormatDeadLetters formats the dead-lettered notifications into a readable string.
+func (formatter *ExtendedFormatter) FormatDeadLetters(deadLetters models.DeadLetters) error {
+	w := tabwriter.NewWriter(formatter.writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tReceiver\tIntegration\tGroup Labels\tAlerts\tCreated At\tReplays\tError\t")
+	for _, dl := range deadLetters {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%d\t%s\t%d\t%s\t\n",
+			*dl.ID,
+			*dl.Receiver,
+			*dl.Integration,
+			extendedFormatLabels(dl.GroupLabels),
+			len(dl.Alerts),
+			FormatDate(*dl.CreatedAt),
+			*dl.Replays,
+			*dl.Error,
+		)
+	}
+	return w.Flush()
+}
+
 func extendedFormatLabels(labels models.LabelSet) string {
 	output := []string{}
 	for name, value := range labels {
@@ -48,7 +48,17 @@ func (formatter *JSONFormatter) FormatConfig(status *models.AlertmanagerStatus)
 	return enc.Encode(status)
 }
 
+func (formatter *JSONFormatter) FormatStatus(status *Status) error {
+	enc := json.NewEncoder(formatter.writer)
+	return enc.Encode(status)
+}
+
 func (formatter *JSONFormatter) FormatClusterStatus(status *models.ClusterStatus) error {
 	enc := json.NewEncoder(formatter.writer)
 	return enc.Encode(status)
 }
+
+func (formatter *JSONFormatter) FormatDeadLetters(deadLetters models.DeadLetters) error {
+	enc := json.NewEncoder(formatter.writer)
+	return enc.Encode(deadLetters)
+}
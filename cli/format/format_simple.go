@@ -86,6 +86,39 @@ func (formatter *SimpleFormatter) FormatClusterStatus(status *models.ClusterStat
 	return w.Flush()
 }
 
+func (formatter *SimpleFormatter) FormatStatus(status *Status) error {
+	w := tabwriter.NewWriter(formatter.writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w,
+		"Version:\t%s\nUptime:\t%s\nConfig Hash:\t%s\nHealthy:\t%t\nReady:\t%t\nCluster Status:\t%s\nPeers:\t%d\n",
+		*status.VersionInfo.Version,
+		FormatDate(*status.Uptime),
+		status.ConfigHash,
+		status.Healthy,
+		status.Ready,
+		*status.Cluster.Status,
+		len(status.Cluster.Peers),
+	)
+	return w.Flush()
+}
+
+func (formatter *SimpleFormatter) FormatDeadLetters(deadLetters models.DeadLetters) error {
+	w := tabwriter.NewWriter(formatter.writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tReceiver\tIntegration\tAlerts\tCreated At\tError\t")
+	for _, dl := range deadLetters {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%d\t%s\t%s\t\n",
+			*dl.ID,
+			*dl.Receiver,
+			*dl.Integration,
+			len(dl.Alerts),
+			FormatDate(*dl.CreatedAt),
+			*dl.Error,
+		)
+	}
+	return w.Flush()
+}
+
 func simpleFormatMatchers(matchers models.Matchers) string {
 	output := []string{}
 	for _, matcher := range matchers {
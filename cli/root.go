@@ -20,6 +20,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/go-openapi/strfmt"
@@ -40,6 +41,7 @@ var (
 	output                string
 	timeout               time.Duration
 	tlsInsecureSkipVerify bool
+	bearerTokenFile       string
 	versionCheck          bool
 
 	configFiles = []string{os.ExpandEnv("$HOME/.config/amtool/config.yml"), "/etc/amtool/config.yml"}
@@ -96,6 +98,18 @@ func NewAlertmanagerClient(amURL *url.URL) *client.Alertmanager {
 		cr.DefaultAuthentication = clientruntime.BasicAuth(amURL.User.Username(), password)
 	}
 
+	if bearerTokenFile != "" {
+		token, err := os.ReadFile(bearerTokenFile)
+		if err != nil {
+			kingpin.Fatalf("could not read bearer token file: %v\n", err)
+		}
+		bearer := clientruntime.BearerToken(strings.TrimSpace(string(token)))
+		if cr.DefaultAuthentication != nil {
+			bearer = clientruntime.Compose(cr.DefaultAuthentication, bearer)
+		}
+		cr.DefaultAuthentication = bearer
+	}
+
 	c := client.New(cr, strfmt.Default)
 
 	if !versionCheck {
@@ -128,6 +142,7 @@ func Execute() {
 	app.Flag("output", "Output formatter (simple, extended, json)").Short('o').Default("simple").EnumVar(&output, "simple", "extended", "json")
 	app.Flag("timeout", "Timeout for the executed command").Default("30s").DurationVar(&timeout)
 	app.Flag("tls.insecure.skip.verify", "Skip TLS certificate verification").BoolVar(&tlsInsecureSkipVerify)
+	app.Flag("bearer-token-file", "File holding the bearer token sent with each request").StringVar(&bearerTokenFile)
 	app.Flag("version-check", "Check alertmanager version. Use --no-version-check to disable.").Default("true").BoolVar(&versionCheck)
 
 	app.Version(version.Print("amtool"))
@@ -143,8 +158,11 @@ func Execute() {
 	configureSilenceCmd(app)
 	configureCheckConfigCmd(app)
 	configureClusterCmd(app)
+	configureStatusCmd(app)
 	configureConfigCmd(app)
 	configureTemplateCmd(app)
+	configureDeadLetterCmd(app)
+	configureBenchCmd(app)
 
 	err = resolver.Bind(app, os.Args[1:])
 	if err != nil {
@@ -187,5 +205,8 @@ static configuration:
 	tls.insecure.skip.verify
 		Skips TLS certificate verification for all HTTPS requests.
 		Defaults to false.
+
+	bearer-token-file
+		Path to a file holding the bearer token sent with each request
 `
 )
@@ -22,7 +22,9 @@ func configureSilenceCmd(app *kingpin.Application) {
 	silenceCmd := app.Command("silence", "Add, expire or view silences. For more information and additional flags see query help").PreAction(requireAlertManagerURL)
 	configureSilenceAddCmd(silenceCmd)
 	configureSilenceExpireCmd(silenceCmd)
+	configureSilenceExportCmd(silenceCmd)
 	configureSilenceImportCmd(silenceCmd)
+	configureSilencePresetCmd(silenceCmd)
 	configureSilenceQueryCmd(silenceCmd)
 	configureSilenceUpdateCmd(silenceCmd)
 }
@@ -23,7 +23,8 @@ import (
 )
 
 type silenceExpireCmd struct {
-	ids []string
+	ids    []string
+	author string
 }
 
 func configureSilenceExpireCmd(cc *kingpin.CmdClause) {
@@ -32,6 +33,7 @@ func configureSilenceExpireCmd(cc *kingpin.CmdClause) {
 		expireCmd = cc.Command("expire", "expire an alertmanager silence")
 	)
 	expireCmd.Arg("silence-ids", "Ids of silences to expire").StringsVar(&c.ids)
+	expireCmd.Flag("author", "Username recorded as having expired the silences").Short('a').Default(username()).StringVar(&c.author)
 	expireCmd.Action(execWithTimeout(c.expire))
 }
 
@@ -45,6 +47,7 @@ func (c *silenceExpireCmd) expire(ctx context.Context, _ *kingpin.ParseContext)
 	for _, id := range c.ids {
 		params := silence.NewDeleteSilenceParams().WithContext(ctx)
 		params.SilenceID = strfmt.UUID(id)
+		params.ExpiredBy = &c.author
 		_, err := amclient.Silence.DeleteSilence(params)
 		if err != nil {
 			return err
@@ -0,0 +1,82 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/prometheus/alertmanager/api/v2/client/silence"
+	"github.com/prometheus/alertmanager/types"
+)
+
+type silenceExportCmd struct {
+	expired bool
+	file    string
+}
+
+const silenceExportHelp = `Export alertmanager silences to a JSON file or stdout
+
+This command writes all active and pending silences as JSON, which can be
+imported into another Alertmanager with the import command. For example:
+
+amtool silence export > silences.json
+
+amtool --alertmanager.url=http://new:9093 silence import silences.json
+
+The "--expired" parameter exports expired silences as well.
+`
+
+func configureSilenceExportCmd(cc *kingpin.CmdClause) {
+	var (
+		c         = &silenceExportCmd{}
+		exportCmd = cc.Command("export", silenceExportHelp)
+	)
+
+	exportCmd.Flag("expired", "Export expired silences as well").BoolVar(&c.expired)
+	exportCmd.Arg("output-file", "File to write the silences to").StringVar(&c.file)
+	exportCmd.Action(execWithTimeout(c.export))
+}
+
+func (c *silenceExportCmd) export(ctx context.Context, _ *kingpin.ParseContext) error {
+	params := silence.NewGetSilencesParams().WithContext(ctx)
+	if !c.expired {
+		params = params.WithState([]string{string(types.SilenceStateActive), string(types.SilenceStatePending)})
+	}
+
+	amclient := NewAlertmanagerClient(alertmanagerURL)
+	getOk, err := amclient.Silence.GetSilences(params)
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = os.Stdout
+	if c.file != "" {
+		f, err := os.Create(c.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		output = f
+	}
+
+	enc := json.NewEncoder(output)
+	enc.SetIndent("", "    ")
+	return errors.Wrap(enc.Encode(getOk.Payload), "couldn't write silences")
+}
@@ -17,20 +17,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"sync"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/pkg/errors"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/prometheus/alertmanager/api/v2/client/silence"
 	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/cli/format"
 )
 
 type silenceImportCmd struct {
 	force   bool
+	dryRun  bool
 	workers int
 	file    string
+	idMap   string
 }
 
 const silenceImportHelp = `Import alertmanager silences from JSON file or stdin
@@ -43,6 +48,18 @@ amtool silence query -o json foo > foo.json
 amtool silence import foo.json
 
 JSON data can also come from stdin if no param is specified.
+
+Silences which don't exist in the target Alertmanager are created with a new
+ID. The "--id-map" parameter writes a JSON object mapping the IDs in the input
+to the IDs of the imported silences, for example when migrating silences
+exported with the export command to another Alertmanager:
+
+amtool silence export > silences.json
+
+amtool --alertmanager.url=http://new:9093 silence import --id-map=ids.json silences.json
+
+The "--dry-run" parameter only validates the input and prints the silences
+that would be imported.
 `
 
 func configureSilenceImportCmd(cc *kingpin.CmdClause) {
@@ -53,14 +70,29 @@ func configureSilenceImportCmd(cc *kingpin.CmdClause) {
 
 	importCmd.Flag("force", "Force adding new silences even if it already exists").Short('f').BoolVar(&c.force)
 	importCmd.Flag("worker", "Number of concurrent workers to use for import").Short('w').Default("8").IntVar(&c.workers)
+	importCmd.Flag("dry-run", "Validate the input and print the silences which would be imported without importing them").BoolVar(&c.dryRun)
+	importCmd.Flag("id-map", "Write a JSON object mapping the IDs in the input to the IDs of the imported silences to the given file").StringVar(&c.idMap)
 	importCmd.Arg("input-file", "JSON file with silences").ExistingFileVar(&c.file)
 	importCmd.Action(execWithTimeout(c.bulkImport))
 }
 
-func addSilenceWorker(ctx context.Context, sclient silence.ClientService, silencec <-chan *models.PostableSilence, errc chan<- error) {
+// importedSilence is the result of importing a silence.
+type importedSilence struct {
+	// The ID of the silence in the input.
+	oldID string
+	newID string
+	err   error
+}
+
+// importSilence is a silence to import along with its ID in the input.
+type importSilence struct {
+	oldID   string
+	silence *models.PostableSilence
+}
+
+func addSilenceWorker(ctx context.Context, sclient silence.ClientService, silencec <-chan importSilence, resc chan<- importedSilence) {
 	for s := range silencec {
-		sid := s.ID
-		params := silence.NewPostSilencesParams().WithContext(ctx).WithSilence(s)
+		params := silence.NewPostSilencesParams().WithContext(ctx).WithSilence(s.silence)
 		postOk, err := sclient.PostSilences(params)
 		if _, ok := err.(*silence.PostSilencesNotFound); ok {
 			// silence doesn't exists yet, retry to create as a new one
@@ -68,12 +100,14 @@ func addSilenceWorker(ctx context.Context, sclient silence.ClientService, silenc
 			postOk, err = sclient.PostSilences(params)
 		}
 
+		res := importedSilence{oldID: s.oldID, err: err}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error adding silence id='%v': %v\n", sid, err)
+			fmt.Fprintf(os.Stderr, "Error adding silence id='%v': %v\n", s.oldID, err)
 		} else {
-			fmt.Println(postOk.Payload.SilenceID)
+			res.newID = postOk.Payload.SilenceID
+			fmt.Println(res.newID)
 		}
-		errc <- err
+		resc <- res
 	}
 }
 
@@ -95,25 +129,36 @@ func (c *silenceImportCmd) bulkImport(ctx context.Context, _ *kingpin.ParseConte
 		return errors.Wrap(err, "couldn't unmarshal input data, is it JSON?")
 	}
 
+	if c.dryRun {
+		return c.dryRunImport(dec)
+	}
+
 	amclient := NewAlertmanagerClient(alertmanagerURL)
-	silencec := make(chan *models.PostableSilence, 100)
-	errc := make(chan error, 100)
+	silencec := make(chan importSilence, 100)
+	resc := make(chan importedSilence, 100)
 	var wg sync.WaitGroup
 	for w := 0; w < c.workers; w++ {
 		wg.Add(1)
 		go func() {
-			addSilenceWorker(ctx, amclient.Silence, silencec, errc)
+			addSilenceWorker(ctx, amclient.Silence, silencec, resc)
 			wg.Done()
 		}()
 	}
 
 	errCount := 0
+	ids := map[string]string{}
+	done := make(chan struct{})
 	go func() {
-		for err := range errc {
-			if err != nil {
+		for res := range resc {
+			if res.err != nil {
 				errCount++
+				continue
+			}
+			if res.oldID != "" {
+				ids[res.oldID] = res.newID
 			}
 		}
+		close(done)
 	}()
 
 	count := 0
@@ -121,24 +166,69 @@ func (c *silenceImportCmd) bulkImport(ctx context.Context, _ *kingpin.ParseConte
 		var s models.PostableSilence
 		err := dec.Decode(&s)
 		if err != nil {
+			close(silencec)
 			return errors.Wrap(err, "couldn't unmarshal input data, is it JSON?")
 		}
 
+		oldID := s.ID
 		if c.force {
 			// reset the silence ID so Alertmanager will always create new silence
 			s.ID = ""
 		}
 
-		silencec <- &s
+		silencec <- importSilence{oldID: oldID, silence: &s}
 		count++
 	}
 
 	close(silencec)
 	wg.Wait()
-	close(errc)
+	close(resc)
+	<-done
 
+	if c.idMap != "" {
+		if err := writeIDMap(c.idMap, ids); err != nil {
+			return err
+		}
+	}
 	if errCount > 0 {
 		return fmt.Errorf("couldn't import %v out of %v silences", errCount, count)
 	}
 	return nil
 }
+
+// dryRunImport validates the silences read from the decoder and prints them
+// instead of importing them.
+func (c *silenceImportCmd) dryRunImport(dec *json.Decoder) error {
+	count, errCount := 0, 0
+	for dec.More() {
+		var s models.PostableSilence
+		if err := dec.Decode(&s); err != nil {
+			return errors.Wrap(err, "couldn't unmarshal input data, is it JSON?")
+		}
+		count++
+		if err := s.Validate(strfmt.Default); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid silence id='%v': %v\n", s.ID, err)
+			errCount++
+			continue
+		}
+		action := "update or create"
+		if c.force || s.ID == "" {
+			action = "create"
+		}
+		fmt.Printf("Would %s silence id='%v' created_by='%v' ends=%s\n", action, s.ID, *s.CreatedBy, format.FormatDate(*s.EndsAt))
+	}
+
+	if errCount > 0 {
+		return fmt.Errorf("%v out of %v silences are invalid", errCount, count)
+	}
+	return nil
+}
+
+// writeIDMap writes the mapping of the IDs of the imported silences to a file.
+func writeIDMap(path string, ids map[string]string) error {
+	b, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(ioutil.WriteFile(path, append(b, '\n'), 0644), "couldn't write ID map")
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/prometheus/alertmanager/api/v2/client/silence"
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+type silencePresetCmd struct {
+	author   string
+	duration string
+	name     string
+	params   []string
+}
+
+const silencePresetHelp = `Create a silence from a preset defined in the Alertmanager configuration
+
+  amtool silence preset
+
+	Lists the presets configured in Alertmanager.
+
+  amtool silence preset node-maintenance instance=node1
+
+	Creates a silence from the node-maintenance preset, filling in the
+	instance parameter of its matcher and comment templates.
+`
+
+func configureSilencePresetCmd(cc *kingpin.CmdClause) {
+	var (
+		c         = &silencePresetCmd{}
+		presetCmd = cc.Command("preset", silencePresetHelp)
+	)
+	presetCmd.Flag("author", "Username for CreatedBy field").Short('a').Default(username()).StringVar(&c.author)
+	presetCmd.Flag("duration", "Duration of silence, overriding the preset default").Short('d').StringVar(&c.duration)
+	presetCmd.Arg("name", "Name of the preset").StringVar(&c.name)
+	presetCmd.Arg("parameters", "Template parameters as key=value pairs").StringsVar(&c.params)
+	presetCmd.Action(execWithTimeout(c.preset))
+}
+
+func (c *silencePresetCmd) preset(ctx context.Context, _ *kingpin.ParseContext) error {
+	amclient := NewAlertmanagerClient(alertmanagerURL)
+
+	if c.name == "" {
+		getOk, err := amclient.Silence.GetSilencePresets(silence.NewGetSilencePresetsParams().WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Name\tDuration\tMatchers\tComment\t")
+		for _, p := range getOk.Payload {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", *p.Name, *p.Duration, strings.Join(p.Matchers, " "), *p.Comment)
+		}
+		return w.Flush()
+	}
+
+	params := make(map[string]string, len(c.params))
+	for _, p := range c.params {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid parameter %q, expected key=value", p)
+		}
+		params[kv[0]] = kv[1]
+	}
+	if c.author == "" {
+		return errors.New("author is required")
+	}
+
+	postParams := silence.NewPostSilencePresetParams().WithContext(ctx).
+		WithPresetName(c.name).
+		WithInstantiation(&models.SilencePresetInstantiation{
+			CreatedBy:  &c.author,
+			Duration:   c.duration,
+			Parameters: params,
+		})
+	postOk, err := amclient.Silence.PostSilencePreset(postParams)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(postOk.Payload.SilenceID)
+	return err
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/prometheus/alertmanager/cli/format"
+)
+
+const statusHelp = `View the status of an Alertmanager.
+
+Prints the version, uptime and configuration hash of the Alertmanager along
+with the result of its health and readiness probes and its cluster status.
+Alertmanagers running the same configuration report the same hash.
+
+The command fails if the Alertmanager is not healthy or not ready, so that it
+can be used to verify deployments:
+
+amtool status --alertmanager.url=http://localhost:9093
+`
+
+// statusCmd represents the status command
+func configureStatusCmd(app *kingpin.Application) {
+	app.Command("status", statusHelp).Action(execWithTimeout(showAlertmanagerStatus)).PreAction(requireAlertManagerURL)
+}
+
+func showAlertmanagerStatus(ctx context.Context, _ *kingpin.ParseContext) error {
+	alertManagerStatus, err := getRemoteAlertmanagerConfigStatus(ctx, alertmanagerURL)
+	if err != nil {
+		return err
+	}
+	status := &format.Status{AlertmanagerStatus: alertManagerStatus}
+	if alertManagerStatus.Config != nil && alertManagerStatus.Config.Original != nil {
+		status.ConfigHash = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(*alertManagerStatus.Config.Original)))
+	}
+	if status.Healthy, err = probe(ctx, alertmanagerURL, "/-/healthy"); err != nil {
+		return err
+	}
+	if status.Ready, err = probe(ctx, alertmanagerURL, "/-/ready"); err != nil {
+		return err
+	}
+
+	formatter, found := format.Formatters[output]
+	if !found {
+		return errors.New("unknown output formatter")
+	}
+	if err := formatter.FormatStatus(status); err != nil {
+		return err
+	}
+
+	switch {
+	case !status.Healthy:
+		return errors.New("alertmanager is not healthy")
+	case !status.Ready:
+		return errors.New("alertmanager is not ready")
+	}
+	return nil
+}
+
+// probe returns whether the given probe endpoint of the Alertmanager responds
+// successfully.
+func probe(ctx context.Context, amURL *url.URL, endpoint string) (bool, error) {
+	u := *amURL
+	u.User = nil
+	u.Path = path.Join(u.Path, endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, err
+	}
+	if amURL.User != nil {
+		password, _ := amURL.User.Password()
+		req.SetBasicAuth(amURL.User.Username(), password)
+	}
+	if bearerTokenFile != "" {
+		token, err := os.ReadFile(bearerTokenFile)
+		if err != nil {
+			return false, fmt.Errorf("could not read bearer token file: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	client := &http.Client{}
+	if tlsInsecureSkipVerify {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode/100 == 2, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/am/-/healthy":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL + "/am")
+	require.NoError(t, err)
+	u.User = url.UserPassword("admin", "secret")
+
+	ok, err := probe(context.Background(), u, "/-/healthy")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = probe(context.Background(), u, "/-/ready")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	u.User = nil
+	ok, err = probe(context.Background(), u, "/-/healthy")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -17,10 +17,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
 	"github.com/xlab/treeprint"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
@@ -37,6 +39,10 @@ Example:
 
 ./amtool config routes test --config.file=doc/examples/simple.yml --verify.receivers=team-DB-pager service=database
 
+With --details, the grouping and timing parameters of every matching route
+are printed as well, along with whether the route continues matching its
+siblings.
+
 `
 
 func configureRoutingTestCmd(cc *kingpin.CmdClause, c *routingShow) {
@@ -44,6 +50,7 @@ func configureRoutingTestCmd(cc *kingpin.CmdClause, c *routingShow) {
 
 	routingTestCmd.Flag("verify.receivers", "Checks if specified receivers matches resolved receivers. The command fails if the labelset does not route to the specified receivers.").StringVar(&c.expectedReceivers)
 	routingTestCmd.Flag("tree", "Prints out matching routes tree.").BoolVar(&c.debugTree)
+	routingTestCmd.Flag("details", "Prints out the grouping and timing parameters of the matching routes.").BoolVar(&c.details)
 	routingTestCmd.Arg("labels", "List of labels to be tested against the configured routes.").StringsVar(&c.labels)
 	routingTestCmd.Action(execWithTimeout(c.routingTestAction))
 }
@@ -61,6 +68,25 @@ func resolveAlertReceivers(mainRoute *dispatch.Route, labels *models.LabelSet) (
 	return receivers, nil
 }
 
+// routeDetails returns the receiver, grouping and timing parameters of the
+// given route on one line.
+func routeDetails(r *dispatch.Route) string {
+	groupBy := "[...]"
+	if !r.RouteOpts.GroupByAll {
+		labels := make([]string, 0, len(r.RouteOpts.GroupBy))
+		for ln := range r.RouteOpts.GroupBy {
+			labels = append(labels, string(ln))
+		}
+		sort.Strings(labels)
+		groupBy = "[" + strings.Join(labels, ",") + "]"
+	}
+	return fmt.Sprintf("%s  route=%s  group_by=%s  group_wait=%s  group_interval=%s  repeat_interval=%s  continue=%t",
+		r.RouteOpts.Receiver, r.Key(), groupBy,
+		model.Duration(r.RouteOpts.GroupWait), model.Duration(r.RouteOpts.GroupInterval), model.Duration(r.RouteOpts.RepeatInterval),
+		r.Continue,
+	)
+}
+
 func printMatchingTree(mainRoute *dispatch.Route, ls models.LabelSet) {
 	tree := treeprint.New()
 	getMatchingTree(mainRoute, tree, ls)
@@ -88,6 +114,14 @@ func (c *routingShow) routingTestAction(ctx context.Context, _ *kingpin.ParseCon
 		printMatchingTree(mainRoute, ls)
 	}
 
+	if c.details {
+		fmt.Println("Matching routes:")
+		for _, r := range mainRoute.Match(convertClientToCommonLabelSet(ls)) {
+			fmt.Println(routeDetails(r))
+		}
+		fmt.Print("\n")
+	}
+
 	receivers, err := resolveAlertReceivers(mainRoute, &ls)
 	receiversSlug := strings.Join(receivers, ",")
 	fmt.Printf("%s\n", receiversSlug)
@@ -62,3 +62,23 @@ func TestRoutingTest(t *testing.T) {
 		fmt.Println("  OK")
 	}
 }
+
+func TestRouteDetails(t *testing.T) {
+	cfg, err := config.LoadFile("testdata/conf.routing.yml")
+	if err != nil {
+		t.Fatalf("failed to load test configuration: %v", err)
+	}
+	mainRoute := dispatch.NewRoute(cfg.Route, nil)
+
+	var details []string
+	for _, r := range mainRoute.Match(convertClientToCommonLabelSet(models.LabelSet{"test": "2"})) {
+		details = append(details, routeDetails(r))
+	}
+	expected := []string{
+		`test1  route={}/{test=~"^(?:^[12]$)$"}  group_by=[]  group_wait=30s  group_interval=5m  repeat_interval=4h  continue=true`,
+		`test2  route={}/{test="2"}  group_by=[]  group_wait=30s  group_interval=5m  repeat_interval=4h  continue=false`,
+	}
+	if !reflect.DeepEqual(expected, details) {
+		t.Fatalf("unexpected route details want: %q, got: %q", expected, details)
+	}
+}
@@ -16,6 +16,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"math/bits"
 	"math/rand"
 	"net"
 	"sort"
@@ -40,6 +41,9 @@ type ClusterPeer interface {
 	Status() string
 	// Peers returns the peer nodes in the cluster.
 	Peers() []ClusterMember
+	// LastHeartbeat returns when the named peer was last seen alive, or
+	// the zero time if it hasn't been seen yet.
+	LastHeartbeat(name string) time.Time
 }
 
 // ClusterMember interface that represents node peers in a cluster
@@ -70,10 +74,14 @@ type Peer struct {
 	peerLock    sync.RWMutex
 	peers       map[string]peer
 	failedPeers []peer
+	heartbeats  map[string]time.Time
 
 	knownPeers    []string
 	advertiseAddr string
 
+	metaMtx sync.RWMutex
+	meta    []byte
+
 	failedReconnectionsCounter prometheus.Counter
 	reconnectionsCounter       prometheus.Counter
 	failedRefreshCounter       prometheus.Counter
@@ -198,6 +206,7 @@ func Create(
 		readyc:        make(chan struct{}),
 		logger:        l,
 		peers:         map[string]peer{},
+		heartbeats:    map[string]time.Time{},
 		resolvedPeers: resolvedPeers,
 		knownPeers:    knownPeers,
 	}
@@ -418,6 +427,7 @@ func (p *Peer) removeFailedPeers(timeout time.Duration) {
 		} else {
 			level.Debug(p.logger).Log("msg", "failed peer has timed out", "peer", pr.Node, "addr", pr.Address())
 			delete(p.peers, pr.Name)
+			delete(p.heartbeats, pr.Name)
 		}
 	}
 
@@ -652,6 +662,20 @@ func (p *Peer) Peers() []ClusterMember {
 	return peers
 }
 
+// LastHeartbeat implements ClusterPeer.
+func (p *Peer) LastHeartbeat(name string) time.Time {
+	p.peerLock.RLock()
+	defer p.peerLock.RUnlock()
+	return p.heartbeats[name]
+}
+
+// heartbeat records that the named peer was seen alive.
+func (p *Peer) heartbeat(name string) {
+	p.peerLock.Lock()
+	defer p.peerLock.Unlock()
+	p.heartbeats[name] = time.Now()
+}
+
 // Position returns the position of the peer in the cluster.
 func (p *Peer) Position() int {
 	all := p.mlist.Members()
@@ -669,6 +693,44 @@ func (p *Peer) Position() int {
 	return k
 }
 
+// SetMeta sets the metadata of the peer, e.g. the URL of its API, which is
+// gossiped to the other peers.
+func (p *Peer) SetMeta(meta []byte) error {
+	if len(meta) > memberlist.MetaMaxSize {
+		return errors.Errorf("metadata of %d bytes exceeds the maximum of %d bytes", len(meta), memberlist.MetaMaxSize)
+	}
+	p.metaMtx.Lock()
+	p.meta = meta
+	p.metaMtx.Unlock()
+	return p.mlist.UpdateNode(DefaultTcpTimeout)
+}
+
+// Owner returns the peer owning the given hash, as the peers divide the hash
+// space into disjoint ranges by their position. It returns whether the peer
+// itself is the owner and otherwise the metadata of the owner.
+func (p *Peer) Owner(h uint64) (self bool, meta []byte) {
+	all := p.mlist.Members()
+	if len(all) == 0 {
+		return true, nil
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	// The upper half of the product is the index of the range holding h.
+	i, _ := bits.Mul64(h, uint64(len(all)))
+	owner := all[i]
+	if owner.Name == p.Self().Name {
+		return true, nil
+	}
+	return false, owner.Meta
+}
+
+// IsLeader returns whether the peer is the leader of the cluster, i.e. the
+// first one by position.
+func (p *Peer) IsLeader() bool {
+	return p.Position() == 0
+}
+
 // Settle waits until the mesh is ready (and sets the appropriate internal state when it is).
 // The idea is that we don't want to start "working" before we get a chance to know most of the alerts and/or silences.
 // Inspired from https://github.com/apache/cassandra/blob/7a40abb6a5108688fb1b10c375bb751cbb782ea4/src/java/org/apache/cassandra/gms/Gossiper.java
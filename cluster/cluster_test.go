@@ -15,11 +15,14 @@ package cluster
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/hashicorp/go-sockaddr"
+	"github.com/hashicorp/memberlist"
 	"github.com/stretchr/testify/require"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -36,6 +39,57 @@ func TestClusterJoinAndReconnect(t *testing.T) {
 	t.Run("TestRemoveFailedPeers", testRemoveFailedPeers)
 	t.Run("TestInitiallyFailingPeers", testInitiallyFailingPeers)
 	t.Run("TestTLSConnection", testTLSConnection)
+	t.Run("TestOwner", testOwner)
+}
+
+func testOwner(t *testing.T) {
+	logger := log.NewNopLogger()
+	var peers []*Peer
+	for i := 0; i < 2; i++ {
+		var known []string
+		if i > 0 {
+			known = []string{peers[0].Self().Address()}
+		}
+		p, err := Create(
+			logger,
+			prometheus.NewRegistry(),
+			"127.0.0.1:0",
+			"",
+			known,
+			true,
+			DefaultPushPullInterval,
+			DefaultGossipInterval,
+			DefaultTcpTimeout,
+			DefaultProbeTimeout,
+			DefaultProbeInterval,
+			nil,
+		)
+		require.NoError(t, err)
+		require.NoError(t, p.SetMeta([]byte(fmt.Sprintf("peer%d", i))))
+		require.NoError(t, p.Join(DefaultReconnectInterval, DefaultReconnectTimeout))
+		defer p.Leave(0)
+		peers = append(peers, p)
+	}
+	require.Eventually(t, func() bool {
+		return peers[0].ClusterSize() == 2 && peers[1].ClusterSize() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The lower and upper halves of the hash space are owned by different
+	// peers, which agree on the owner.
+	for _, h := range []uint64{0, 1 << 62, 1<<63 + 1, math.MaxUint64} {
+		self0, meta0 := peers[0].Owner(h)
+		self1, meta1 := peers[1].Owner(h)
+		require.NotEqual(t, self0, self1)
+		if self0 {
+			require.Equal(t, "peer0", string(meta1))
+		} else {
+			require.Equal(t, "peer1", string(meta0))
+		}
+	}
+	self, _ := peers[0].Owner(0)
+	require.Equal(t, peers[0].Position() == 0, self)
+
+	require.Error(t, peers[0].SetMeta(make([]byte, memberlist.MetaMaxSize+1)))
 }
 
 func testJoinLeave(t *testing.T) {
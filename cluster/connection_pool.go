@@ -28,10 +28,10 @@ const capacity = 1024
 type connectionPool struct {
 	mtx       sync.Mutex
 	cache     *lru.Cache
-	tlsConfig *tls.Config
+	tlsConfig func() (*tls.Config, error)
 }
 
-func newConnectionPool(tlsClientCfg *tls.Config) (*connectionPool, error) {
+func newConnectionPool(tlsClientCfg func() (*tls.Config, error)) (*connectionPool, error) {
 	cache, err := lru.NewWithEvict(
 		capacity, func(_ interface{}, value interface{}) {
 			conn, ok := value.(*tlsConn)
@@ -65,7 +65,11 @@ func (pool *connectionPool) borrowConnection(addr string, timeout time.Duration)
 			return conn, nil
 		}
 	}
-	conn, err := dialTLSConn(addr, timeout, pool.tlsConfig)
+	tlsConfig, err := pool.tlsConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid TLS client config")
+	}
+	conn, err := dialTLSConn(addr, timeout, tlsConfig)
 	if err != nil {
 		return nil, err
 	}
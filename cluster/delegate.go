@@ -143,7 +143,9 @@ func newDelegate(l log.Logger, reg prometheus.Registerer, p *Peer, retransmit in
 
 // NodeMeta retrieves meta-data about the current node when broadcasting an alive message.
 func (d *delegate) NodeMeta(limit int) []byte {
-	return []byte{}
+	d.metaMtx.RLock()
+	defer d.metaMtx.RUnlock()
+	return d.meta
 }
 
 // NotifyMsg is the callback invoked when a user-level gossip message is received.
@@ -251,6 +253,7 @@ func (d *delegate) NotifyUpdate(n *memberlist.Node) {
 // NotifyAlive implements the memberlist.AliveDelegate interface.
 func (d *delegate) NotifyAlive(peer *memberlist.Node) error {
 	d.nodeAlive.WithLabelValues(peer.Name).Inc()
+	d.Peer.heartbeat(peer.Name)
 	return nil
 }
 
@@ -262,6 +265,7 @@ func (d *delegate) AckPayload() []byte {
 // NotifyPingComplete implements the memberlist.PingDelegate interface.
 func (d *delegate) NotifyPingComplete(peer *memberlist.Node, rtt time.Duration, payload []byte) {
 	d.nodePingDuration.WithLabelValues(peer.Name).Observe(rtt.Seconds())
+	d.Peer.heartbeat(peer.Name)
 }
 
 // handleQueueDepth ensures that the queue doesn't grow unbounded by pruning
@@ -55,7 +55,7 @@ type TLSTransport struct {
 	streamCh     chan net.Conn
 	connPool     *connectionPool
 	tlsServerCfg *tls.Config
-	tlsClientCfg *tls.Config
+	tlsClientCfg func() (*tls.Config, error)
 
 	packetsSent prometheus.Counter
 	packetsRcvd prometheus.Counter
@@ -84,10 +84,17 @@ func NewTLSTransport(
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid TLS server config")
 	}
-	tlsClientCfg, err := common.NewTLSConfig(cfg.TLSClientConfig)
-	if err != nil {
+	// Reload the certificates and CAs on new connections so that they can be
+	// rotated without restarting.
+	tlsServerCfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return web.ConfigToTLSConfig(cfg.TLSServerConfig)
+	}
+	if _, err := common.NewTLSConfig(cfg.TLSClientConfig); err != nil {
 		return nil, errors.Wrap(err, "invalid TLS client config")
 	}
+	tlsClientCfg := func() (*tls.Config, error) {
+		return common.NewTLSConfig(cfg.TLSClientConfig)
+	}
 	ip := net.ParseIP(bindAddr)
 	if ip == nil {
 		return nil, fmt.Errorf("invalid bind address \"%s\"", bindAddr)
@@ -212,7 +219,12 @@ func (t *TLSTransport) WriteTo(b []byte, addr string) (time.Time, error) {
 // DialTimeout is used to create a connection that allows memberlist
 // to perform two-way communications with a peer.
 func (t *TLSTransport) DialTimeout(addr string, timeout time.Duration) (net.Conn, error) {
-	conn, err := dialTLSConn(addr, timeout, t.tlsClientCfg)
+	tlsConfig, err := t.tlsClientCfg()
+	if err != nil {
+		t.writeErrs.WithLabelValues("stream").Inc()
+		return nil, errors.Wrap(err, "invalid TLS client config")
+	}
+	conn, err := dialTLSConn(addr, timeout, tlsConfig)
 	if err != nil {
 		t.writeErrs.WithLabelValues("stream").Inc()
 		return nil, errors.Wrap(err, "failed to dial")
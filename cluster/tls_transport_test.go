@@ -16,9 +16,19 @@ package cluster
 import (
 	"bufio"
 	context2 "context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -183,6 +193,101 @@ func TestDialTimout(t *testing.T) {
 	require.Equal(t, sent, buf)
 }
 
+func TestTLSCertificateReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls_reload")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The node certificate is signed by the second CA only, the CA file
+	// starts with the first one.
+	ca1, _ := mustGenerateCert(t, nil, nil)
+	ca2, ca2Key := mustGenerateCert(t, nil, nil)
+	node, nodeKey := mustGenerateCert(t, ca2, ca2Key)
+	caFile := filepath.Join(dir, "ca.pem")
+	mustWritePEM(t, caFile, "CERTIFICATE", ca1.Raw)
+	mustWritePEM(t, filepath.Join(dir, "node.pem"), "CERTIFICATE", node.Raw)
+	keyBytes, err := x509.MarshalECPrivateKey(nodeKey)
+	require.NoError(t, err)
+	mustWritePEM(t, filepath.Join(dir, "node-key.pem"), "EC PRIVATE KEY", keyBytes)
+
+	cfgFile := filepath.Join(dir, "tls_config.yml")
+	require.NoError(t, ioutil.WriteFile(cfgFile, []byte(`tls_server_config:
+  cert_file: "node.pem"
+  key_file: "node-key.pem"
+  client_ca_file: "ca.pem"
+  client_auth_type: "RequireAndVerifyClientCert"
+tls_client_config:
+  cert_file: "node.pem"
+  key_file: "node-key.pem"
+  ca_file: "ca.pem"
+`), 0o644))
+	tlsConf := mustTLSTransportConfig(cfgFile)
+
+	t1, err := NewTLSTransport(context2.Background(), logger, nil, "127.0.0.1", 0, tlsConf)
+	require.NoError(t, err)
+	defer t1.Shutdown()
+	t2, err := NewTLSTransport(context2.Background(), logger, nil, "127.0.0.1", 0, tlsConf)
+	require.NoError(t, err)
+	defer t2.Shutdown()
+
+	addr := fmt.Sprintf("%s:%d", t2.bindAddr, t2.GetAutoBindPort())
+	_, err = t1.DialTimeout(addr, 5*time.Second)
+	require.Error(t, err)
+
+	// Rotate the CA file, new connections should pick it up.
+	mustWritePEM(t, caFile, "CERTIFICATE", ca2.Raw)
+	from, err := t1.DialTimeout(addr, 5*time.Second)
+	require.NoError(t, err)
+	defer from.Close()
+
+	sent := []byte("test stream")
+	_, err = from.Write(sent)
+	require.NoError(t, err)
+	select {
+	case to := <-t2.StreamCh():
+		buf := make([]byte, len(sent))
+		_, err = io.ReadFull(bufio.NewReader(to), buf)
+		require.NoError(t, err)
+		require.Equal(t, sent, buf)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream not received")
+	}
+}
+
+// mustGenerateCert returns a certificate valid for 127.0.0.1 signed by the
+// given parent, or a self-signed CA if parent is nil.
+func mustGenerateCert(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "alertmanager"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	} else {
+		tmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func mustWritePEM(t *testing.T, filename, typ string, b []byte) {
+	require.NoError(t, ioutil.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0o644))
+}
+
 type logWr struct {
 	bytes []byte
 }
@@ -16,6 +16,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -23,6 +24,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -40,30 +42,44 @@ import (
 	"github.com/prometheus/common/version"
 	"github.com/prometheus/exporter-toolkit/web"
 	webflag "github.com/prometheus/exporter-toolkit/web/kingpinflag"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"gopkg.in/alecthomas/kingpin.v2"
 
+	"github.com/prometheus/alertmanager/ack"
+	"github.com/prometheus/alertmanager/ack/inbound"
+	"github.com/prometheus/alertmanager/analytics"
 	"github.com/prometheus/alertmanager/api"
+	"github.com/prometheus/alertmanager/api/auditlog"
+	"github.com/prometheus/alertmanager/api/auth"
+	"github.com/prometheus/alertmanager/backup"
 	"github.com/prometheus/alertmanager/cluster"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/deadletter"
 	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/heartbeat"
+	"github.com/prometheus/alertmanager/history"
+	"github.com/prometheus/alertmanager/ingest"
 	"github.com/prometheus/alertmanager/inhibit"
+	"github.com/prometheus/alertmanager/lifecycle"
 	"github.com/prometheus/alertmanager/nflog"
 	"github.com/prometheus/alertmanager/notify"
-	"github.com/prometheus/alertmanager/notify/email"
-	"github.com/prometheus/alertmanager/notify/opsgenie"
-	"github.com/prometheus/alertmanager/notify/pagerduty"
-	"github.com/prometheus/alertmanager/notify/pushover"
-	"github.com/prometheus/alertmanager/notify/slack"
-	"github.com/prometheus/alertmanager/notify/sns"
-	"github.com/prometheus/alertmanager/notify/victorops"
-	"github.com/prometheus/alertmanager/notify/webhook"
-	"github.com/prometheus/alertmanager/notify/wechat"
+	"github.com/prometheus/alertmanager/persist"
 	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/provider/shard"
+	"github.com/prometheus/alertmanager/provider/wal"
+	"github.com/prometheus/alertmanager/router"
 	"github.com/prometheus/alertmanager/silence"
-	"github.com/prometheus/alertmanager/template"
-	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/alertmanager/silence/audit"
+	"github.com/prometheus/alertmanager/silence/expiry"
+	"github.com/prometheus/alertmanager/spool"
+	"github.com/prometheus/alertmanager/storage"
+	"github.com/prometheus/alertmanager/storage/redis"
+	"github.com/prometheus/alertmanager/storage/sqlstore"
+	"github.com/prometheus/alertmanager/tracing"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/alertmanager/ui"
+	"github.com/prometheus/alertmanager/watchdog"
 )
 
 var (
@@ -126,53 +142,61 @@ func instrumentHandler(handlerName string, handler http.HandlerFunc) http.Handle
 
 const defaultClusterAddr = "0.0.0.0:9094"
 
-// buildReceiverIntegrations builds a list of integration notifiers off of a
-// receiver config.
-func buildReceiverIntegrations(nc *config.Receiver, tmpl *template.Template, logger log.Logger) ([]notify.Integration, error) {
+// emailSmarthosts returns the distinct SMTP smarthosts of the receivers, as
+// host:port.
+func emailSmarthosts(receivers []*config.Receiver) []string {
 	var (
-		errs         types.MultiError
-		integrations []notify.Integration
-		add          = func(name string, i int, rs notify.ResolvedSender, f func(l log.Logger) (notify.Notifier, error)) {
-			n, err := f(log.With(logger, "integration", name))
-			if err != nil {
-				errs.Add(err)
-				return
+		seen  = map[string]struct{}{}
+		hosts []string
+	)
+	for _, rcv := range receivers {
+		for _, c := range rcv.EmailConfigs {
+			addr := c.Smarthost.String()
+			if _, ok := seen[addr]; ok {
+				continue
 			}
-			integrations = append(integrations, notify.NewIntegration(n, rs, name, i))
+			seen[addr] = struct{}{}
+			hosts = append(hosts, addr)
 		}
-	)
-
-	for i, c := range nc.WebhookConfigs {
-		add("webhook", i, c, func(l log.Logger) (notify.Notifier, error) { return webhook.New(c, tmpl, l) })
-	}
-	for i, c := range nc.EmailConfigs {
-		add("email", i, c, func(l log.Logger) (notify.Notifier, error) { return email.New(c, tmpl, l), nil })
-	}
-	for i, c := range nc.PagerdutyConfigs {
-		add("pagerduty", i, c, func(l log.Logger) (notify.Notifier, error) { return pagerduty.New(c, tmpl, l) })
-	}
-	for i, c := range nc.OpsGenieConfigs {
-		add("opsgenie", i, c, func(l log.Logger) (notify.Notifier, error) { return opsgenie.New(c, tmpl, l) })
-	}
-	for i, c := range nc.WechatConfigs {
-		add("wechat", i, c, func(l log.Logger) (notify.Notifier, error) { return wechat.New(c, tmpl, l) })
-	}
-	for i, c := range nc.SlackConfigs {
-		add("slack", i, c, func(l log.Logger) (notify.Notifier, error) { return slack.New(c, tmpl, l) })
 	}
-	for i, c := range nc.VictorOpsConfigs {
-		add("victorops", i, c, func(l log.Logger) (notify.Notifier, error) { return victorops.New(c, tmpl, l) })
-	}
-	for i, c := range nc.PushoverConfigs {
-		add("pushover", i, c, func(l log.Logger) (notify.Notifier, error) { return pushover.New(c, tmpl, l) })
+	return hosts
+}
+
+// validateReceivers renders a test notification through every integration of
+// the receivers, and connects to their endpoints if connectivity is true,
+// without sending anything.
+func validateReceivers(receivers map[string][]notify.Integration, connectivity bool) *config.Validation {
+	names := make([]string, 0, len(receivers))
+	for name := range receivers {
+		names = append(names, name)
 	}
-	for i, c := range nc.SNSConfigs {
-		add("sns", i, c, func(l log.Logger) (notify.Notifier, error) { return sns.New(c, tmpl, l) })
+	sort.Strings(names)
+
+	v := &config.Validation{
+		Time:      time.Now(),
+		Receivers: make([]config.ReceiverValidation, len(names)),
 	}
-	if errs.Len() > 0 {
-		return nil, &errs
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), notify.MinTimeout)
+			defer cancel()
+
+			rv := config.ReceiverValidation{Name: name, Integrations: []config.IntegrationValidation{}}
+			for _, r := range notify.Validate(ctx, name, receivers[name], connectivity) {
+				iv := config.IntegrationValidation{Integration: r.Integration, Index: r.Index}
+				if r.Err != nil {
+					iv.Error = r.Err.Error()
+				}
+				rv.Integrations = append(rv.Integrations, iv)
+			}
+			v.Receivers[i] = rv
+		}(i, name)
 	}
-	return integrations, nil
+	wg.Wait()
+	return v
 }
 
 func main() {
@@ -186,10 +210,28 @@ func run() int {
 	}
 
 	var (
-		configFile      = kingpin.Flag("config.file", "Alertmanager configuration file name.").Default("alertmanager.yml").String()
-		dataDir         = kingpin.Flag("storage.path", "Base path for data storage.").Default("data/").String()
-		retention       = kingpin.Flag("data.retention", "How long to keep data for.").Default("120h").Duration()
-		alertGCInterval = kingpin.Flag("alerts.gc-interval", "Interval between alert GC.").Default("30m").Duration()
+		configFile       = kingpin.Flag("config.file", "Alertmanager configuration file name.").Default("alertmanager.yml").String()
+		configValidation = kingpin.Flag("config.validation", "Validation of the receivers of a configuration before applying it: 'none', 'render' to render a test notification through every integration without sending it, or 'connectivity' to also connect to their endpoints. A configuration failing validation is not applied.").Default("none").Enum("none", "render", "connectivity")
+		dataDir          = kingpin.Flag("storage.path", "Base path for data storage.").Default("data/").String()
+		retention        = kingpin.Flag("data.retention", "How long to keep data for.").Default("120h").Duration()
+		boltPath         = kingpin.Flag("storage.bolt-path", "Path of a BoltDB file persisting alerts, silences and the notification log, which are restored from it on start. It replaces the silences and nflog snapshot files in the storage path. If empty, alerts are not persisted.").Default("").String()
+		alertGCInterval  = kingpin.Flag("alerts.gc-interval", "Interval between alert GC.").Default("30m").Duration()
+		alertWALPath     = kingpin.Flag("alerts.wal-path", "Path of a write-ahead log recording ingested alerts before they are acknowledged. The log is replayed on start. If empty, no log is written.").Default("").String()
+		alertRetention   = kingpin.Flag("alerts.resolved-retention", "How long resolved alerts are kept in memory before they are garbage collected.").Default("0s").Duration()
+		redisURL         = kingpin.Flag("storage.redis-url", "URL of a Redis server sharing alerts, silences, the notification log and acknowledgements with other replicas, e.g. redis://:password@localhost:6379/0. If set, this state is not gossiped.").Default("").String()
+		redisPrefix      = kingpin.Flag("storage.redis-prefix", "Prefix of the Redis keys and channels, allowing several Alertmanager clusters to share a Redis server.").Default(redis.DefaultPrefix).String()
+		sqlDriver        = kingpin.Flag("storage.sql-driver", "Name of the database/sql driver of a database recording the history of silences and the notification log. Only the sqlite driver is bundled, postgres and pgx require a binary built with their driver.").Default("sqlite").String()
+		sqlDSN           = kingpin.Flag("storage.sql-dsn", "Data source name of the database recording the history of silences and the notification log. If empty, no history is recorded.").Default("").String()
+		backupURL        = kingpin.Flag("storage.backup-url", "URL of an object storage bucket to which snapshots of silences and the notification log are uploaded, e.g. s3://bucket/prefix or gs://bucket/prefix. If empty, no snapshots are uploaded.").Default("").String()
+		backupInterval   = kingpin.Flag("storage.backup-interval", "Interval between uploads of snapshots to object storage.").Default("1h").Duration()
+		restoreFrom      = kingpin.Flag("storage.restore-from", "URL of an object storage bucket from which silences and the notification log are restored on start if there is no local snapshot of them, e.g. s3://bucket/prefix.").Default("").String()
+		spoolPath        = kingpin.Flag("spool.path", "Directory in which outbound notifications are spooled until they are delivered, so that they survive a crash. Spooled notifications are replayed on startup. If empty, spooling is disabled.").Default("").String()
+		maxDeadLetters   = kingpin.Flag("deadletter.max-entries", "Maximum number of undeliverable notifications kept for replay. Once reached, the oldest ones are dropped. 0 means no limit.").Default("1000").Int()
+		historyRetention = kingpin.Flag("notification-history.retention", "How long to keep the history of notification attempts for. 0 means they are kept until the maximum number of entries is reached.").Default("720h").Duration()
+		maxHistory       = kingpin.Flag("notification-history.max-entries", "Maximum number of notification attempts kept in the history. Once reached, the oldest ones are dropped. 0 means no limit.").Default("100000").Int()
+		dispatchShards   = kingpin.Flag("dispatch.shards", "Number of workers the aggregation groups are sharded across. 0 means GOMAXPROCS.").Default("0").Int()
+		drainTimeout     = kingpin.Flag("shutdown.drain-timeout", "Maximum time to wait on SIGTERM for the aggregation groups with pending alerts to be flushed and for their notifications to finish before exiting. Alerts posted meanwhile are rejected. 0 disables draining.").Default("10s").Duration()
+		dryRun           = kingpin.Flag("notifications.dry-run", "Render and log the payloads of all notifications instead of sending them. Receivers can also be put in dry-run mode individually with dry_run in the configuration.").Default("false").Bool()
 
 		webConfig      = webflag.AddFlags(kingpin.CommandLine)
 		externalURL    = kingpin.Flag("web.external-url", "The URL under which Alertmanager is externally reachable (for example, if Alertmanager is served via a reverse proxy). Used for generating relative and absolute links back to Alertmanager itself. If the URL has a path portion, it will be used to prefix all HTTP endpoints served by Alertmanager. If omitted, relevant URL components will be derived automatically.").String()
@@ -197,12 +239,22 @@ func run() int {
 		listenAddress  = kingpin.Flag("web.listen-address", "Address to listen on for the web interface and API.").Default(":9093").String()
 		getConcurrency = kingpin.Flag("web.get-concurrency", "Maximum number of GET requests processed concurrently. If negative or zero, the limit is GOMAXPROC or 8, whichever is larger.").Default("0").Int()
 		httpTimeout    = kingpin.Flag("web.timeout", "Timeout for HTTP requests. If negative or zero, no timeout is set.").Default("0").Duration()
+		allowedNames   = kingpin.Flag("web.client-allowed-name", "Common name or subject alternative name of the client certificates allowed to access the web interface and API (may be repeated). Client certificates must be required and verified by the TLS configuration of --web.config.file. If unset, any client is allowed.").Strings()
+		tokensFile     = kingpin.Flag("web.bearer-tokens-file", "Path to a file listing the bearer tokens, one per line, of which requests to the web interface and API must carry one. The file is read again when it changes. If empty, no bearer token is required.").Default("").String()
+		healthyChecks  = kingpin.Flag("web.healthy-check", "Dependency checked by /-/healthy (may be repeated): 'cluster' for the synchronization with the peers, 'storage' for the writability of the storage path or 'smtp' for the reachability of the SMTP smarthosts.").Enums("cluster", "storage", "smtp")
+		readyChecks    = kingpin.Flag("web.ready-check", "Dependency checked by /-/ready (may be repeated), like --web.healthy-check.").Default("cluster").Enums("cluster", "storage", "smtp")
+		enableDebug    = kingpin.Flag("web.enable-debug", "Enable the profiling and debugging endpoints under /debug: pprof, the recent requests at /debug/requests and the stacks of all goroutines at /debug/goroutines. Only admins may access them if authorization is configured.").Bool()
+		enableGRPC     = kingpin.Flag("web.enable-grpc", "Serve the gRPC API, which posts and queries alerts, on the listen address. Its requests use HTTP/2, over TLS if configured by --web.config.file and in clear text otherwise. They are authenticated and authorized like the requests of the HTTP API.").Bool()
+		auditLogFile   = kingpin.Flag("web.audit-log-file", "Path to a file to which the state-changing operations, such as creating silences or reloading the configuration, are appended as JSON lines. If empty, no audit log is written.").Default("").String()
 
 		clusterBindAddr = kingpin.Flag("cluster.listen-address", "Listen address for cluster. Set to empty string to disable HA mode.").
 				Default(defaultClusterAddr).String()
 		clusterAdvertiseAddr = kingpin.Flag("cluster.advertise-address", "Explicit address to advertise in cluster.").String()
 		peers                = kingpin.Flag("cluster.peer", "Initial peers (may be repeated).").Strings()
 		peerTimeout          = kingpin.Flag("cluster.peer-timeout", "Time to wait between peers to send notifications.").Default("15s").Duration()
+		shardAlerts          = kingpin.Flag("cluster.shard-alerts", "Divide the alerts between the peers by their fingerprint. Alerts received by a peer not owning them are forwarded to their owner through its --web.external-url.").Default("false").Bool()
+		forwardConfigFile    = kingpin.Flag("cluster.forward-config", "Path to the YAML file configuring how peers forward alerts to their owner with --cluster.shard-alerts: the secret shared by the peers which authenticates the forwarded alerts, and the HTTP client settings of the requests forwarding them.").Default("").String()
+		notifyMode           = kingpin.Flag("cluster.notify-mode", "How peers avoid sending duplicate notifications. With \"wait\", every peer sends notifications after waiting for the peers before it. With \"leader\", only the first peer sends notifications while the others stay on standby.").Default("wait").Enum("wait", "leader")
 		gossipInterval       = kingpin.Flag("cluster.gossip-interval", "Interval between sending gossip messages. By lowering this value (more frequent) gossip messages are propagated across the cluster more quickly at the expense of increased bandwidth.").Default(cluster.DefaultGossipInterval.String()).Duration()
 		pushPullInterval     = kingpin.Flag("cluster.pushpull-interval", "Interval for gossip state syncs. Setting this interval lower (more frequent) will increase convergence speeds across larger clusters at the expense of increased bandwidth usage.").Default(cluster.DefaultPushPullInterval.String()).Duration()
 		tcpTimeout           = kingpin.Flag("cluster.tcp-timeout", "Timeout for establishing a stream connection with a remote node for a full state sync, and for stream read and write operations.").Default(cluster.DefaultTcpTimeout.String()).Duration()
@@ -260,35 +312,218 @@ func run() int {
 		clusterEnabled.Set(1)
 	}
 
+	marker := types.NewMarker(prometheus.DefaultRegisterer)
+
+	var backend storage.Backend
+	if *redisURL != "" {
+		backend, err = redis.New(redis.Options{
+			URL:          *redisURL,
+			Prefix:       *redisPrefix,
+			GCInterval:   *alertGCInterval,
+			Retention:    *alertRetention,
+			SyncInterval: 15 * time.Minute,
+			Marker:       marker,
+			Logger:       log.With(logger, "component", "redis"),
+			Metrics:      prometheus.DefaultRegisterer,
+		})
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to connect to Redis", "err", err)
+			return 1
+		}
+		defer backend.Close()
+	}
+	var sqlHistory *sqlstore.Store
+	if *sqlDSN != "" {
+		sqlHistory, err = sqlstore.Open(sqlstore.Options{
+			Driver:  *sqlDriver,
+			DSN:     *sqlDSN,
+			Logger:  log.With(logger, "component", "sqlstore"),
+			Metrics: prometheus.DefaultRegisterer,
+		})
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to open SQL database", "err", err)
+			return 1
+		}
+		defer sqlHistory.Close()
+	}
+	// addState shares the state with the other replicas through the storage
+	// backend or, if none is configured, the gossip cluster. Silences and the
+	// notification log are additionally recorded in the SQL database.
+	addState := func(key string, s cluster.State) (cluster.ClusterChannel, error) {
+		var channels teeChannel
+		if sqlHistory != nil && (key == sqlstore.KeySilences || key == sqlstore.KeyNflog) {
+			c, err := sqlHistory.AddState(key, s)
+			if err != nil {
+				return nil, err
+			}
+			channels = append(channels, c)
+		}
+		if backend != nil {
+			c, err := backend.AddState(key, s)
+			if err != nil {
+				return nil, err
+			}
+			channels = append(channels, c)
+		} else if peer != nil {
+			channels = append(channels, peer.AddState(key, s, prometheus.DefaultRegisterer))
+		}
+		switch len(channels) {
+		case 0:
+			return nil, nil
+		case 1:
+			return channels[0], nil
+		}
+		return channels, nil
+	}
+
+	var db *persist.DB
+	if *boltPath != "" {
+		db, err = persist.Open(*boltPath, log.With(logger, "component", "persist"), prometheus.DefaultRegisterer)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to open persistence file", "err", err)
+			return 1
+		}
+		defer db.Close()
+	}
+
+	var backupBucket *backup.Bucket
+	if *backupURL != "" {
+		backupBucket, err = backup.New(*backupURL, log.With(logger, "component", "backup"), prometheus.DefaultRegisterer)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to access backup bucket", "err", err)
+			return 1
+		}
+	}
+	var restoreBucket *backup.Bucket
+	if *restoreFrom != "" {
+		restoreBucket, err = backup.New(*restoreFrom, log.With(logger, "component", "backup"), nil)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to access restore bucket", "err", err)
+			return 1
+		}
+	}
+	// restoreSnapshot downloads the named snapshot to the file if there is
+	// no such file yet.
+	restoreSnapshot := func(name, file string) error {
+		if restoreBucket == nil {
+			return nil
+		}
+		restored, err := restoreBucket.RestoreFile(name, file)
+		if restored {
+			level.Info(logger).Log("msg", "Restored snapshot from object storage", "name", name)
+		}
+		return err
+	}
+	// restoreReader returns the named snapshot if r is nil, i.e. when there
+	// is no local snapshot.
+	restoreReader := func(name string, r io.Reader) (io.Reader, error) {
+		if restoreBucket == nil || r != nil {
+			return r, nil
+		}
+		r, err := restoreBucket.Download(name)
+		if r != nil {
+			level.Info(logger).Log("msg", "Restored snapshot from object storage", "name", name)
+		}
+		return r, err
+	}
+
 	stopc := make(chan struct{})
 	var wg sync.WaitGroup
 	wg.Add(1)
 
 	notificationLogOpts := []nflog.Option{
 		nflog.WithRetention(*retention),
-		nflog.WithSnapshot(filepath.Join(*dataDir, "nflog")),
-		nflog.WithMaintenance(15*time.Minute, stopc, wg.Done, nil),
 		nflog.WithMetrics(prometheus.DefaultRegisterer),
 		nflog.WithLogger(log.With(logger, "component", "nflog")),
 	}
+	// The notification log is declared before its options are complete, as
+	// the maintenance persisting it refers to it.
+	var notificationLog *nflog.Log
+	if db != nil {
+		r, err := db.LoadSnapshot(persist.SnapshotNflog)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to read notification log from persistence file", "err", err)
+			return 1
+		}
+		if r, err = restoreReader(backup.SnapshotNflog, r); err != nil {
+			level.Error(logger).Log("msg", "Unable to restore notification log", "err", err)
+			return 1
+		}
+		if r != nil {
+			notificationLogOpts = append(notificationLogOpts, nflog.WithSnapshotReader(r))
+		}
+		saveNflog := func() (int64, error) {
+			if _, err := notificationLog.GC(); err != nil {
+				return 0, err
+			}
+			return db.SaveSnapshot(persist.SnapshotNflog, notificationLog.Snapshot)
+		}
+		done := func() {
+			if _, err := saveNflog(); err != nil {
+				level.Error(logger).Log("msg", "Saving notification log on shutdown failed", "err", err)
+			}
+			wg.Done()
+		}
+		notificationLogOpts = append(notificationLogOpts, nflog.WithMaintenance(15*time.Minute, stopc, done, saveNflog))
+	} else {
+		if err := restoreSnapshot(backup.SnapshotNflog, filepath.Join(*dataDir, "nflog")); err != nil {
+			level.Error(logger).Log("msg", "Unable to restore notification log", "err", err)
+			return 1
+		}
+		notificationLogOpts = append(notificationLogOpts,
+			nflog.WithSnapshot(filepath.Join(*dataDir, "nflog")),
+			nflog.WithMaintenance(15*time.Minute, stopc, wg.Done, nil),
+		)
+	}
 
-	notificationLog, err := nflog.New(notificationLogOpts...)
+	notificationLog, err = nflog.New(notificationLogOpts...)
 	if err != nil {
 		level.Error(logger).Log("err", err)
 		return 1
 	}
-	if peer != nil {
-		c := peer.AddState("nfl", notificationLog, prometheus.DefaultRegisterer)
+	if c, err := addState("nfl", notificationLog); err != nil {
+		level.Error(logger).Log("msg", "Unable to share notification log", "err", err)
+		return 1
+	} else if c != nil {
 		notificationLog.SetBroadcast(c.Broadcast)
 	}
 
-	marker := types.NewMarker(prometheus.DefaultRegisterer)
-
-	silenceOpts := silence.Options{
-		SnapshotFile: filepath.Join(*dataDir, "silences"),
+	silenceAudit, err := audit.New(audit.Options{
+		SnapshotFile: filepath.Join(*dataDir, "silence_audit"),
 		Retention:    *retention,
-		Logger:       log.With(logger, "component", "silences"),
+		Logger:       log.With(logger, "component", "silence-audit"),
 		Metrics:      prometheus.DefaultRegisterer,
+	})
+	if err != nil {
+		level.Error(logger).Log("err", err)
+		return 1
+	}
+
+	silencesFile := filepath.Join(*dataDir, "silences")
+	silenceOpts := silence.Options{
+		Retention: *retention,
+		OnChange:  silenceAudit.Record,
+		Logger:    log.With(logger, "component", "silences"),
+		Metrics:   prometheus.DefaultRegisterer,
+	}
+	if db != nil {
+		// Silences are only snapshotted to the persistence file.
+		silencesFile = ""
+		silenceOpts.SnapshotReader, err = db.LoadSnapshot(persist.SnapshotSilences)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to read silences from persistence file", "err", err)
+			return 1
+		}
+		if silenceOpts.SnapshotReader, err = restoreReader(backup.SnapshotSilences, silenceOpts.SnapshotReader); err != nil {
+			level.Error(logger).Log("msg", "Unable to restore silences", "err", err)
+			return 1
+		}
+	} else {
+		if err := restoreSnapshot(backup.SnapshotSilences, silencesFile); err != nil {
+			level.Error(logger).Log("msg", "Unable to restore silences", "err", err)
+			return 1
+		}
+		silenceOpts.SnapshotFile = silencesFile
 	}
 
 	silences, err := silence.New(silenceOpts)
@@ -296,17 +531,128 @@ func run() int {
 		level.Error(logger).Log("err", err)
 		return 1
 	}
-	if peer != nil {
-		c := peer.AddState("sil", silences, prometheus.DefaultRegisterer)
+	if c, err := addState("sil", silences); err != nil {
+		level.Error(logger).Log("msg", "Unable to share silences", "err", err)
+		return 1
+	} else if c != nil {
 		silences.SetBroadcast(c.Broadcast)
 	}
 
+	deadLetters, err := deadletter.New(deadletter.Options{
+		SnapshotFile: filepath.Join(*dataDir, "deadletter"),
+		Retention:    *retention,
+		MaxEntries:   *maxDeadLetters,
+		Logger:       log.With(logger, "component", "deadletter"),
+		Metrics:      prometheus.DefaultRegisterer,
+	})
+	if err != nil {
+		level.Error(logger).Log("err", err)
+		return 1
+	}
+
+	notificationHistory, err := history.New(history.Options{
+		SnapshotFile: filepath.Join(*dataDir, "notification_history"),
+		Retention:    *historyRetention,
+		MaxEntries:   *maxHistory,
+		Logger:       log.With(logger, "component", "history"),
+		Metrics:      prometheus.DefaultRegisterer,
+	})
+	if err != nil {
+		level.Error(logger).Log("err", err)
+		return 1
+	}
+
+	acks, err := ack.New(ack.Options{
+		SnapshotFile: filepath.Join(*dataDir, "acks"),
+		Retention:    *retention,
+		Logger:       log.With(logger, "component", "acks"),
+		Metrics:      prometheus.DefaultRegisterer,
+	})
+	if err != nil {
+		level.Error(logger).Log("err", err)
+		return 1
+	}
+	if c, err := addState("ack", acks); err != nil {
+		level.Error(logger).Log("msg", "Unable to share acknowledgements", "err", err)
+		return 1
+	} else if c != nil {
+		acks.SetBroadcast(c.Broadcast)
+	}
+
+	// The spool is read before the first configuration is loaded so that only
+	// notifications spooled by a previous run are replayed.
+	var (
+		notificationSpool notify.Spool
+		spooled           []*spool.Entry
+	)
+	if *spoolPath != "" {
+		sp, err := spool.New(*spoolPath, log.With(logger, "component", "spool"), prometheus.DefaultRegisterer)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to create spool", "err", err)
+			return 1
+		}
+		spooled, err = sp.List()
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to read spool", "err", err)
+			return 1
+		}
+		notificationSpool = sp
+	}
+
 	// Start providers before router potentially sends updates.
 	wg.Add(1)
 	go func() {
-		silences.Maintenance(15*time.Minute, filepath.Join(*dataDir, "silences"), stopc, nil)
+		defer wg.Done()
+		if db == nil {
+			silences.Maintenance(15*time.Minute, silencesFile, stopc, nil)
+			return
+		}
+		saveSilences := func() (int64, error) {
+			if _, err := silences.GC(); err != nil {
+				return 0, err
+			}
+			return db.SaveSnapshot(persist.SnapshotSilences, silences.Snapshot)
+		}
+		silences.Maintenance(15*time.Minute, silencesFile, stopc, saveSilences)
+		if _, err := saveSilences(); err != nil {
+			level.Error(logger).Log("msg", "Saving silences on shutdown failed", "err", err)
+		}
+	}()
+	wg.Add(1)
+	go func() {
+		silences.Reschedule(10*time.Second, stopc)
 		wg.Done()
 	}()
+	wg.Add(1)
+	go func() {
+		silenceAudit.Maintenance(15*time.Minute, filepath.Join(*dataDir, "silence_audit"), stopc, nil)
+		wg.Done()
+	}()
+	wg.Add(1)
+	go func() {
+		deadLetters.Maintenance(15*time.Minute, filepath.Join(*dataDir, "deadletter"), stopc, nil)
+		wg.Done()
+	}()
+	wg.Add(1)
+	go func() {
+		notificationHistory.Maintenance(15*time.Minute, filepath.Join(*dataDir, "notification_history"), stopc, nil)
+		wg.Done()
+	}()
+	wg.Add(1)
+	go func() {
+		acks.Maintenance(15*time.Minute, filepath.Join(*dataDir, "acks"), stopc, nil)
+		wg.Done()
+	}()
+	if backupBucket != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			backupBucket.Run(*backupInterval, stopc, map[string]backup.Snapshot{
+				backup.SnapshotSilences: silences.Snapshot,
+				backup.SnapshotNflog:    notificationLog.Snapshot,
+			})
+		}()
+	}
 
 	defer func() {
 		close(stopc)
@@ -332,20 +678,165 @@ func run() int {
 		go peer.Settle(ctx, *gossipInterval*10)
 	}
 
-	alerts, err := mem.NewAlerts(context.Background(), marker, *alertGCInterval, nil, logger)
-	if err != nil {
-		level.Error(logger).Log("err", err)
-		return 1
+	var alerts storage.Alerts
+	if backend != nil {
+		alerts = backend.Alerts()
+	} else {
+		alerts, err = mem.NewAlerts(context.Background(), marker, *alertGCInterval, *alertRetention, nil, logger, prometheus.DefaultRegisterer)
+		if err != nil {
+			level.Error(logger).Log("err", err)
+			return 1
+		}
+	}
+	if *alertWALPath != "" {
+		w, err := wal.Open(*alertWALPath, alerts, log.With(logger, "component", "wal"), prometheus.DefaultRegisterer)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to open write-ahead log", "err", err)
+			return 1
+		}
+		alerts = w
+		wg.Add(1)
+		go func() {
+			w.Maintenance(15*time.Minute, stopc)
+			wg.Done()
+		}()
 	}
+	var forwardSigner *shard.Signer
+	if *shardAlerts && peer != nil {
+		if backend != nil {
+			level.Error(logger).Log("msg", "Sharding alerts is not supported with a storage backend")
+			return 1
+		}
+		if *forwardConfigFile == "" {
+			level.Error(logger).Log("msg", "Sharding alerts requires --cluster.forward-config")
+			return 1
+		}
+		forwardConfig, err := shard.LoadConfig(*forwardConfigFile)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to load the forwarding configuration", "err", err)
+			return 1
+		}
+		forwardSigner, err = shard.NewSigner(forwardConfig)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to load the forwarding secret", "err", err)
+			return 1
+		}
+		alerts, err = shard.NewAlerts(alerts, peer, forwardConfig, forwardSigner, log.With(logger, "component", "shard"), prometheus.DefaultRegisterer)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to create the forwarding client", "err", err)
+			return 1
+		}
+	}
+	lifecycleWebhook := lifecycle.NewWebhook(prometheus.DefaultRegisterer, log.With(logger, "component", "lifecycle"))
+	defer lifecycleWebhook.Stop()
+	alerts = lifecycle.NewAlerts(alerts, lifecycleWebhook)
+
+	notificationAnalytics := analytics.NewExporter(prometheus.DefaultRegisterer, log.With(logger, "component", "analytics"))
+	defer notificationAnalytics.Stop()
+	receiverHealth := notify.NewHealth()
 	defer alerts.Close()
 
-	var disp *dispatch.Dispatcher
+	if db != nil {
+		persisted, err := db.LoadAlerts()
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to read alerts from persistence file", "err", err)
+			return 1
+		}
+		if err := alerts.Put(persisted...); err != nil {
+			level.Error(logger).Log("msg", "Unable to restore alerts", "err", err)
+			return 1
+		}
+		level.Info(logger).Log("msg", "Restored alerts from persistence file", "alerts", len(persisted))
+
+		wg.Add(1)
+		go func() {
+			db.MaintainAlerts(15*time.Minute, alerts, stopc)
+			wg.Done()
+		}()
+	}
+
+	var (
+		disp      *dispatch.Dispatcher
+		inhibitor *inhibit.Inhibitor
+	)
 	defer disp.Stop()
 
+	// The receivers of the currently loaded configuration, used to replay
+	// dead-lettered notifications and to render or test notifications, and
+	// their SMTP smarthosts, used by the smtp health check.
+	var (
+		receiversMtx     sync.RWMutex
+		currentReceivers map[string][]notify.Integration
+		smarthosts       []string
+	)
+	replayFn := func(e *deadletter.Entry) error {
+		receiversMtx.RLock()
+		integrations, ok := currentReceivers[e.Receiver]
+		receiversMtx.RUnlock()
+		if !ok {
+			return fmt.Errorf("receiver %q not found in current configuration", e.Receiver)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), notify.MinTimeout)
+		defer cancel()
+		return notify.Replay(ctx, integrations, e)
+	}
+	renderFn := func(ctx context.Context, receiver string, alerts []*types.Alert) ([]notify.Rendered, bool) {
+		receiversMtx.RLock()
+		integrations, ok := currentReceivers[receiver]
+		receiversMtx.RUnlock()
+		if !ok {
+			return nil, false
+		}
+		ctx, cancel := context.WithTimeout(ctx, notify.MinTimeout)
+		defer cancel()
+		return notify.Render(ctx, receiver, integrations, alerts...), true
+	}
+	testFn := func(ctx context.Context, receiver string, alerts []*types.Alert) ([]notify.TestResult, bool) {
+		receiversMtx.RLock()
+		integrations, ok := currentReceivers[receiver]
+		receiversMtx.RUnlock()
+		if !ok {
+			return nil, false
+		}
+		ctx, cancel := context.WithTimeout(ctx, notify.MinTimeout)
+		defer cancel()
+		return notify.Test(ctx, receiver, integrations, alerts...), true
+	}
+
+	// The alerts queued by exceeded notification budgets are delivered by
+	// the instance which queued them.
+	budgets := notify.NewBudgets(func(name string) ([]notify.Integration, bool) {
+		receiversMtx.RLock()
+		defer receiversMtx.RUnlock()
+		integrations, ok := currentReceivers[name]
+		return integrations, ok
+	}, log.With(logger, "component", "budgets"), prometheus.DefaultRegisterer)
+	wg.Add(1)
+	go func() {
+		budgets.Run(30*time.Second, stopc)
+		wg.Done()
+	}()
+
 	groupFn := func(routeFilter func(*dispatch.Route) bool, alertFilter func(*types.Alert, time.Time) bool) (dispatch.AlertGroups, map[model.Fingerprint][]string) {
 		return disp.Groups(routeFilter, alertFilter)
 	}
 
+	resendFn := func(ctx context.Context, groupKey, receiver string) error {
+		return disp.Resend(ctx, groupKey, receiver)
+	}
+
+	inhibitionsFn := func() []inhibit.Inhibition {
+		return inhibitor.Inhibitions()
+	}
+
+	ackHooks := inbound.New(acks, silences, func() dispatch.AlertGroups {
+		groups, _ := groupFn(
+			func(*dispatch.Route) bool { return true },
+			func(*types.Alert, time.Time) bool { return true },
+		)
+		return groups
+	}, log.With(logger, "component", "ack-webhooks"))
+
 	// An interface value that holds a nil concrete value is non-nil.
 	// Therefore we explicly pass an empty interface, to detect if the
 	// cluster is not enabled in notify.
@@ -354,16 +845,67 @@ func run() int {
 		clusterPeer = peer
 	}
 
+	trustBasicAuth, err := auth.BasicAuthConfigured(*webConfig)
+	if err != nil {
+		level.Error(logger).Log("msg", "Unable to read web configuration", "err", err)
+		return 1
+	}
+
+	var auditLog *auditlog.Log
+	if *auditLogFile != "" {
+		auditLog, err = auditlog.Open(*auditLogFile, log.With(logger, "component", "auditlog"))
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to open audit log", "err", err)
+			return 1
+		}
+		defer auditLog.Close()
+	}
+
+	configLogger := log.With(logger, "component", "configuration")
+	configCoordinator := config.NewCoordinator(
+		*configFile,
+		prometheus.DefaultRegisterer,
+		configLogger,
+	)
+
+	// The validation of the receivers of the last configuration loaded.
+	var (
+		validationMtx  sync.RWMutex
+		lastValidation *config.Validation
+	)
+	validationFn := func() *config.Validation {
+		validationMtx.RLock()
+		defer validationMtx.RUnlock()
+		return lastValidation
+	}
+
 	api, err := api.New(api.Options{
-		Alerts:      alerts,
-		Silences:    silences,
-		StatusFunc:  marker.Status,
-		Peer:        clusterPeer,
-		Timeout:     *httpTimeout,
-		Concurrency: *getConcurrency,
-		Logger:      log.With(logger, "component", "api"),
-		Registry:    prometheus.DefaultRegisterer,
-		GroupFunc:   groupFn,
+		Alerts:               alerts,
+		Silences:             silences,
+		StatusFunc:           marker.Status,
+		Peer:                 clusterPeer,
+		Timeout:              *httpTimeout,
+		Concurrency:          *getConcurrency,
+		Logger:               log.With(logger, "component", "api"),
+		Registry:             prometheus.DefaultRegisterer,
+		GroupFunc:            groupFn,
+		InhibitionsFunc:      inhibitionsFn,
+		ConfigDiffFunc:       configCoordinator.LastDiff,
+		ConfigValidationFunc: validationFn,
+		DeadLetters:          deadLetters,
+		ReplayFunc:           replayFn,
+		RenderFunc:           renderFn,
+		TestFunc:             testFn,
+		ResendFunc:           resendFn,
+		ReceiverHealth:       receiverHealth,
+		Budgets:              budgets,
+		Acks:                 acks,
+		SilenceAudit:         silenceAudit,
+		NotificationHistory:  notificationHistory,
+		TrustBasicAuth:       trustBasicAuth,
+		AuditLog:             auditLog,
+		ForwardSigner:        forwardSigner,
+		EnableGRPC:           *enableGRPC,
 	})
 
 	if err != nil {
@@ -377,10 +919,90 @@ func run() int {
 		return 1
 	}
 	level.Debug(logger).Log("externalURL", amURL.String())
+	if *shardAlerts && peer != nil {
+		// Peers forward the alerts owned by this instance to its URL.
+		if err := peer.SetMeta([]byte(amURL.String())); err != nil {
+			level.Error(logger).Log("msg", "Unable to announce external URL to peers", "err", err)
+			return 1
+		}
+	}
+
+	oidc := auth.NewOIDC(amURL, *tokensFile != "", trustBasicAuth, log.With(logger, "component", "auth"))
+	tlsPolicy := auth.NewTLSPolicy(log.With(logger, "component", "auth"))
+
+	tracer := tracing.NewManager(log.With(logger, "component", "tracing"))
+	defer tracer.Stop()
+
+	heartbeats := heartbeat.NewManager(prometheus.DefaultRegisterer, log.With(logger, "component", "heartbeat"))
+	defer heartbeats.Stop()
+
+	ingesters := ingest.NewManager(api.PostAlerts, prometheus.DefaultRegisterer, log.With(logger, "component", "ingest"))
+	defer ingesters.Stop()
+
+	// Notifications about expiring silences are sent by a single instance:
+	// the leader of the cluster if there is one.
+	expiryNotifier := expiry.New(silences, func(name string) ([]notify.Integration, bool) {
+		receiversMtx.RLock()
+		defer receiversMtx.RUnlock()
+		integrations, ok := currentReceivers[name]
+		return integrations, ok
+	}, func() bool {
+		return peer == nil || peer.IsLeader()
+	}, log.With(logger, "component", "silence-expiry"), prometheus.DefaultRegisterer)
+	wg.Add(1)
+	go func() {
+		expiryNotifier.Run(time.Minute, stopc)
+		wg.Done()
+	}()
+
+	// Like the notifications about expiring silences, those about missing
+	// watchdog alerts are sent by the leader.
+	watchdogs := watchdog.New(alerts, func(name string) ([]notify.Integration, bool) {
+		receiversMtx.RLock()
+		defer receiversMtx.RUnlock()
+		integrations, ok := currentReceivers[name]
+		return integrations, ok
+	}, func() bool {
+		return peer == nil || peer.IsLeader()
+	}, log.With(logger, "component", "watchdog"), prometheus.DefaultRegisterer)
+	wg.Add(1)
+	go func() {
+		watchdogs.Run(30*time.Second, stopc)
+		wg.Done()
+	}()
+
+	// The alerts queued during quiet hours are delivered by the instance
+	// which queued them.
+	quietHours := notify.NewQuietHours(func(name string) ([]notify.Integration, bool) {
+		receiversMtx.RLock()
+		defer receiversMtx.RUnlock()
+		integrations, ok := currentReceivers[name]
+		return integrations, ok
+	}, log.With(logger, "component", "quiet-hours"), prometheus.DefaultRegisterer)
+	wg.Add(1)
+	go func() {
+		quietHours.Run(30*time.Second, stopc)
+		wg.Done()
+	}()
+
+	routeDedup := notify.NewRouteDedup(prometheus.DefaultRegisterer)
 
 	waitFunc := func() time.Duration { return 0 }
+	var isLeader func() bool
 	if peer != nil {
 		waitFunc = clusterWait(peer, *peerTimeout)
+		if *notifyMode == "leader" {
+			isLeader = peer.IsLeader
+			prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Name: "alertmanager_cluster_leader",
+				Help: "Indicates whether this instance is the leader sending notifications.",
+			}, func() float64 {
+				if peer.IsLeader() {
+					return 1
+				}
+				return 0
+			}))
+		}
 	}
 	timeoutFunc := func(d time.Duration) time.Duration {
 		if d < notify.MinTimeout {
@@ -389,90 +1011,100 @@ func run() int {
 		return d + waitFunc()
 	}
 
-	var (
-		inhibitor *inhibit.Inhibitor
-		tmpl      *template.Template
-	)
-
-	dispMetrics := dispatch.NewDispatcherMetrics(false, prometheus.DefaultRegisterer)
-	pipelineBuilder := notify.NewPipelineBuilder(prometheus.DefaultRegisterer)
-	configLogger := log.With(logger, "component", "configuration")
-	configCoordinator := config.NewCoordinator(
-		*configFile,
-		prometheus.DefaultRegisterer,
-		configLogger,
-	)
+	// An interface value that holds a nil concrete value is non-nil.
+	// Therefore we explicly pass an empty interface, to detect if the
+	// cluster is not enabled in notify.
+	var pipelinePeer notify.Peer
+	if peer != nil {
+		pipelinePeer = peer
+	}
+	routerOpts := router.Options{
+		Alerts:      alerts,
+		Marker:      marker,
+		Silences:    silences,
+		ExternalURL: amURL,
+		DryRun:      *dryRun,
+		Validate: func(receivers map[string][]notify.Integration) error {
+			if *configValidation == "none" {
+				return nil
+			}
+			v := validateReceivers(receivers, *configValidation == "connectivity")
+			validationMtx.Lock()
+			lastValidation = v
+			validationMtx.Unlock()
+			return v.Err()
+		},
+		PipelineBuilder:   notify.NewPipelineBuilder(prometheus.DefaultRegisterer),
+		DispatcherMetrics: dispatch.NewDispatcherMetrics(false, prometheus.DefaultRegisterer),
+		Wait:              waitFunc,
+		IsLeader:          isLeader,
+		QuietHours:        quietHours,
+		RouteDedup:        routeDedup,
+		Budgets:           budgets,
+		Events:            lifecycleWebhook,
+		Attempts:          notify.AttemptRecorders{notificationAnalytics, receiverHealth, notificationHistory},
+		NotificationLog:   notificationLog,
+		DeadLetters:       deadLetters,
+		Spool:             notificationSpool,
+		Peer:              pipelinePeer,
+		Timeout:           timeoutFunc,
+		Acknowledgements:  acks,
+		Shards:            *dispatchShards,
+		Logger:            logger,
+	}
 	configCoordinator.Subscribe(func(conf *config.Config) error {
-		tmpl, err = template.FromGlobs(conf.Templates...)
+		// The new router is built and validated before anything is
+		// changed, so that the current one keeps running if the
+		// configuration cannot be applied.
+		rtr, err := router.Build(conf, routerOpts)
 		if err != nil {
-			return errors.Wrap(err, "failed to parse templates")
+			return err
 		}
-		tmpl.ExternalURL = amURL
-
-		// Build the routing tree and record which receivers are used.
-		routes := dispatch.NewRoute(conf.Route, nil)
-		activeReceivers := make(map[string]struct{})
-		routes.Walk(func(r *dispatch.Route) {
-			activeReceivers[r.RouteOpts.Receiver] = struct{}{}
-		})
 
-		// Build the map of receiver to integrations.
-		receivers := make(map[string][]notify.Integration, len(activeReceivers))
-		var integrationsNum int
-		for _, rcv := range conf.Receivers {
-			if _, found := activeReceivers[rcv.Name]; !found {
-				// No need to build a receiver if no route is using it.
-				level.Info(configLogger).Log("msg", "skipping creation of receiver not referenced by any route", "receiver", rcv.Name)
-				continue
-			}
-			integrations, err := buildReceiverIntegrations(rcv, tmpl, logger)
-			if err != nil {
-				return err
-			}
-			// rcv.Name is guaranteed to be unique across all receivers.
-			receivers[rcv.Name] = integrations
-			integrationsNum += len(integrations)
+		ackHooks.Update(conf.AckWebhooks)
+		watchdogs.Update(conf)
+		tlsPolicy.Update(conf)
+		if err := oidc.Update(conf); err != nil {
+			return errors.Wrap(err, "failed to set up OpenID Connect")
 		}
-
-		// Build the map of time interval names to mute time definitions.
-		muteTimes := make(map[string][]timeinterval.TimeInterval, len(conf.MuteTimeIntervals))
-		for _, ti := range conf.MuteTimeIntervals {
-			muteTimes[ti.Name] = ti.TimeIntervals
+		if err := tracer.Update(conf); err != nil {
+			return errors.Wrap(err, "failed to set up tracing")
+		}
+		if err := heartbeats.Update(conf); err != nil {
+			return errors.Wrap(err, "failed to set up heartbeat")
+		}
+		if err := lifecycleWebhook.Update(conf); err != nil {
+			return errors.Wrap(err, "failed to set up lifecycle webhook")
+		}
+		if err := notificationAnalytics.Update(conf); err != nil {
+			return errors.Wrap(err, "failed to set up notification analytics")
+		}
+		if err := ingesters.Update(conf); err != nil {
+			return errors.Wrap(err, "failed to set up ingesters")
 		}
 
+		// Swap the dispatcher and the inhibitor, which only pauses the
+		// processing of alerts while the old ones stop.
 		inhibitor.Stop()
 		disp.Stop()
+		inhibitor = rtr.Inhibitor
+		disp = rtr.Dispatcher
+		rtr.Run()
 
-		inhibitor = inhibit.NewInhibitor(alerts, conf.InhibitRules, marker, logger)
-		silencer := silence.NewSilencer(silences, marker, logger)
-
-		// An interface value that holds a nil concrete value is non-nil.
-		// Therefore we explicly pass an empty interface, to detect if the
-		// cluster is not enabled in notify.
-		var pipelinePeer notify.Peer
-		if peer != nil {
-			pipelinePeer = peer
-		}
-
-		pipeline := pipelineBuilder.New(
-			receivers,
-			waitFunc,
-			inhibitor,
-			silencer,
-			muteTimes,
-			notificationLog,
-			pipelinePeer,
-		)
-		configuredReceivers.Set(float64(len(activeReceivers)))
-		configuredIntegrations.Set(float64(integrationsNum))
+		configuredReceivers.Set(float64(len(rtr.Receivers)))
+		configuredIntegrations.Set(float64(rtr.Integrations))
+
+		receiversMtx.Lock()
+		currentReceivers = rtr.Receivers
+		smarthosts = emailSmarthosts(conf.Receivers)
+		receiversMtx.Unlock()
 
 		api.Update(conf, func(labels model.LabelSet) {
-			inhibitor.Mutes(labels)
-			silencer.Mutes(labels)
+			rtr.Inhibitor.Mutes(labels)
+			rtr.Silencer.Mutes(labels)
 		})
 
-		disp = dispatch.NewDispatcher(alerts, routes, pipeline, marker, timeoutFunc, nil, logger, dispMetrics)
-		routes.Walk(func(r *dispatch.Route) {
+		rtr.Route.Walk(func(r *dispatch.Route) {
 			if r.RouteOpts.RepeatInterval > *retention {
 				level.Warn(configLogger).Log(
 					"msg",
@@ -487,9 +1119,6 @@ func run() int {
 			}
 		})
 
-		go disp.Run()
-		go inhibitor.Run()
-
 		return nil
 	})
 
@@ -497,6 +1126,17 @@ func run() int {
 		return 1
 	}
 
+	if len(spooled) > 0 {
+		level.Info(logger).Log("msg", "Replaying spooled notifications", "count", len(spooled))
+		replayCtx, cancelReplay := context.WithCancel(context.Background())
+		defer cancelReplay()
+
+		receiversMtx.RLock()
+		receivers := currentReceivers
+		receiversMtx.RUnlock()
+		go notify.ReplaySpool(replayCtx, spooled, notificationSpool, receivers, notificationLog, deadLetters, log.With(logger, "component", "spool"))
+	}
+
 	// Make routePrefix default to externalURL path if empty string.
 	if *routePrefix == "" {
 		*routePrefix = amURL.Path
@@ -514,11 +1154,57 @@ func run() int {
 
 	webReload := make(chan chan error)
 
-	ui.Register(router, webReload, logger)
+	// The instance isn't ready until it has synchronized its state, e.g.
+	// silences, with its peers.
+	var ready func() bool
+	if peer != nil {
+		ready = peer.Ready
+	}
+	healthChecks := map[string]ui.HealthCheck{
+		"cluster": ui.ClusterCheck(ready),
+		"storage": ui.StorageCheck(*dataDir),
+		"smtp": ui.SMTPCheck(func() []string {
+			receiversMtx.RLock()
+			defer receiversMtx.RUnlock()
+			return smarthosts
+		}),
+	}
+	selectChecks := func(names []string) []ui.HealthCheck {
+		checks := make([]ui.HealthCheck, 0, len(names))
+		for _, name := range names {
+			checks = append(checks, healthChecks[name])
+		}
+		return checks
+	}
+	ui.Register(router, webReload, selectChecks(*healthyChecks), selectChecks(*readyChecks), logger)
+	if *enableDebug {
+		ui.RegisterDebug(router)
+	}
+	ui.RegisterAck(router, acks, logger)
+	ackHooks.Register(router)
 
-	mux := api.Register(router, *routePrefix)
+	var handler http.Handler = api.Register(router, *routePrefix)
+	if *enableDebug {
+		handler = ui.TraceRequests(handler)
+	}
+	if *tokensFile != "" {
+		handler, err = auth.NewTokenHandler(*tokensFile, handler, log.With(logger, "component", "auth"))
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to read bearer tokens", "err", err)
+			return 1
+		}
+	}
+	handler = oidc.Handler(*routePrefix, handler)
+	if len(*allowedNames) > 0 {
+		handler = auth.NewClientCertHandler(*allowedNames, handler, log.With(logger, "component", "auth"))
+	}
+	handler = tlsPolicy.Handler(handler)
+	if *enableGRPC {
+		// Clients without TLS use HTTP/2 with prior knowledge.
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
 
-	srv := &http.Server{Addr: *listenAddress, Handler: mux}
+	srv := &http.Server{Addr: *listenAddress, Handler: handler}
 	srvc := make(chan struct{})
 
 	go func() {
@@ -548,7 +1234,14 @@ func run() int {
 			select {
 			case <-hup:
 				// ignore error, already logged in `reload()`
-				_ = configCoordinator.Reload()
+				err := configCoordinator.Reload()
+				if auditLog != nil {
+					e := auditlog.Entry{Operation: "config.reload", Actor: "SIGHUP"}
+					if err != nil {
+						e.Error = err.Error()
+					}
+					auditLog.Record(e)
+				}
 			case errc := <-webReload:
 				errc <- configCoordinator.Reload()
 			}
@@ -562,6 +1255,16 @@ func run() int {
 		select {
 		case <-term:
 			level.Info(logger).Log("msg", "Received SIGTERM, exiting gracefully...")
+			if *drainTimeout > 0 {
+				// Stop receiving alerts before notifying the pending
+				// changes of the aggregation groups. The state is
+				// persisted once the dispatcher is stopped.
+				api.Drain()
+				ingesters.Stop()
+				ctx, cancel := context.WithTimeout(context.Background(), *drainTimeout)
+				disp.Drain(ctx)
+				cancel()
+			}
 			return 0
 		case <-srvc:
 			return 1
@@ -577,6 +1280,15 @@ func clusterWait(p *cluster.Peer, timeout time.Duration) func() time.Duration {
 	}
 }
 
+// teeChannel broadcasts messages on all of its channels.
+type teeChannel []cluster.ClusterChannel
+
+func (t teeChannel) Broadcast(b []byte) {
+	for _, c := range t {
+		c.Broadcast(b)
+	}
+}
+
 func extURL(logger log.Logger, hostnamef func() (string, error), listen, external string) (*url.URL, error) {
 	if external == "" {
 		hostname, err := hostnamef()
@@ -14,23 +14,33 @@
 package config
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/url"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/pkg/errors"
 	commoncfg "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/sigv4"
 	"gopkg.in/yaml.v2"
 
+	"github.com/prometheus/alertmanager/pkg/cron"
+	"github.com/prometheus/alertmanager/pkg/expr"
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/pkg/relabel"
+	"github.com/prometheus/alertmanager/pkg/srv"
 	"github.com/prometheus/alertmanager/timeinterval"
 )
 
@@ -60,7 +70,16 @@ func (s Secret) MarshalYAML() (interface{}, error) {
 // UnmarshalYAML implements the yaml.Unmarshaler interface for Secret.
 func (s *Secret) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	type plain Secret
-	return unmarshal((*plain)(s))
+	err := unmarshal((*plain)(s))
+	if err == nil {
+		return nil
+	}
+	name, ok := unmarshalCredentialRef(unmarshal)
+	if !ok {
+		return err
+	}
+	*s = Secret(credentialRefPrefix + name)
+	return nil
 }
 
 // MarshalJSON implements the json.Marshaler interface for Secret.
@@ -138,7 +157,12 @@ func (s SecretURL) MarshalYAML() (interface{}, error) {
 func (s *SecretURL) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var str string
 	if err := unmarshal(&str); err != nil {
-		return err
+		name, ok := unmarshalCredentialRef(unmarshal)
+		if !ok {
+			return err
+		}
+		s.URL = &url.URL{Scheme: credentialRefScheme, Opaque: name}
+		return nil
 	}
 	// In order to deserialize a previously serialized configuration (eg from
 	// the Alertmanager API with amtool), `<secret>` needs to be treated
@@ -172,7 +196,7 @@ func Load(s string) (*Config, error) {
 	cfg := &Config{}
 	err := yaml.UnmarshalStrict([]byte(s), cfg)
 	if err != nil {
-		return nil, err
+		return nil, locate(s, cfg, err)
 	}
 	// Check if we have a root route. We cannot check for it in the
 	// UnmarshalYAML method because it won't be called if the input is empty
@@ -219,31 +243,109 @@ func resolveFilepaths(baseDir string, cfg *Config) {
 		cfg.Templates[i] = join(tf)
 	}
 
+	if cfg.Tenancy != nil {
+		cfg.Tenancy.OverlaysDir = join(cfg.Tenancy.OverlaysDir)
+	}
+
 	cfg.Global.HTTPConfig.SetDirectory(baseDir)
-	for _, receiver := range cfg.Receivers {
+	if cfg.OIDC != nil && cfg.OIDC.HTTPConfig != cfg.Global.HTTPConfig {
+		cfg.OIDC.HTTPConfig.SetDirectory(baseDir)
+	}
+	if cfg.Tracing != nil && cfg.Tracing.HTTPConfig != cfg.Global.HTTPConfig {
+		cfg.Tracing.HTTPConfig.SetDirectory(baseDir)
+	}
+	if cfg.Heartbeat != nil && cfg.Heartbeat.HTTPConfig != cfg.Global.HTTPConfig {
+		cfg.Heartbeat.HTTPConfig.SetDirectory(baseDir)
+	}
+	if cfg.LifecycleWebhook != nil && cfg.LifecycleWebhook.HTTPConfig != cfg.Global.HTTPConfig {
+		cfg.LifecycleWebhook.HTTPConfig.SetDirectory(baseDir)
+	}
+	if cfg.NotificationAnalytics != nil && cfg.NotificationAnalytics.HTTPConfig != cfg.Global.HTTPConfig {
+		cfg.NotificationAnalytics.HTTPConfig.SetDirectory(baseDir)
+	}
+	for _, k := range cfg.KafkaIngesters {
+		if k.HTTPConfig != cfg.Global.HTTPConfig {
+			k.HTTPConfig.SetDirectory(baseDir)
+		}
+	}
+	for _, q := range cfg.SQSIngesters {
+		if q.HTTPConfig != cfg.Global.HTTPConfig {
+			q.HTTPConfig.SetDirectory(baseDir)
+		}
+	}
+	resolveReceiverFilepaths(baseDir, cfg.Receivers, cfg.Global.HTTPConfig)
+}
+
+// resolveReceiverFilepaths joins all relative paths in the receivers with a
+// given base directory. The HTTP client configurations which the receivers
+// inherit from the global configuration are left untouched.
+func resolveReceiverFilepaths(baseDir string, receivers []*Receiver, global *commoncfg.HTTPClientConfig) {
+	setDirectory := func(c *commoncfg.HTTPClientConfig) {
+		if c != global {
+			c.SetDirectory(baseDir)
+		}
+	}
+	for _, receiver := range receivers {
 		for _, cfg := range receiver.OpsGenieConfigs {
-			cfg.HTTPConfig.SetDirectory(baseDir)
+			setDirectory(cfg.HTTPConfig)
 		}
 		for _, cfg := range receiver.PagerdutyConfigs {
-			cfg.HTTPConfig.SetDirectory(baseDir)
+			setDirectory(cfg.HTTPConfig)
 		}
 		for _, cfg := range receiver.PushoverConfigs {
-			cfg.HTTPConfig.SetDirectory(baseDir)
+			setDirectory(cfg.HTTPConfig)
 		}
 		for _, cfg := range receiver.SlackConfigs {
-			cfg.HTTPConfig.SetDirectory(baseDir)
+			setDirectory(cfg.HTTPConfig)
 		}
 		for _, cfg := range receiver.VictorOpsConfigs {
-			cfg.HTTPConfig.SetDirectory(baseDir)
+			setDirectory(cfg.HTTPConfig)
 		}
 		for _, cfg := range receiver.WebhookConfigs {
-			cfg.HTTPConfig.SetDirectory(baseDir)
+			setDirectory(cfg.HTTPConfig)
 		}
 		for _, cfg := range receiver.WechatConfigs {
-			cfg.HTTPConfig.SetDirectory(baseDir)
+			setDirectory(cfg.HTTPConfig)
 		}
 		for _, cfg := range receiver.SNSConfigs {
-			cfg.HTTPConfig.SetDirectory(baseDir)
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.IncidentIOConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.StatuspageConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.GithubConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.GrafanaConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.PushbulletConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.GotifyConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.NtfyConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.SplunkHECConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.ElasticsearchConfigs {
+			setDirectory(cfg.HTTPConfig)
+		}
+		for _, cfg := range receiver.EmailConfigs {
+			if cfg.DKIM != nil && cfg.DKIM.PrivateKeyFile != "" && !filepath.IsAbs(cfg.DKIM.PrivateKeyFile) {
+				cfg.DKIM.PrivateKeyFile = filepath.Join(baseDir, cfg.DKIM.PrivateKeyFile)
+			}
+		}
+		for _, cfg := range receiver.PluginConfigs {
+			if strings.Contains(cfg.Command, "/") && !filepath.IsAbs(cfg.Command) {
+				cfg.Command = filepath.Join(baseDir, cfg.Command)
+			}
 		}
 	}
 }
@@ -266,236 +368,1364 @@ func (mt *MuteTimeInterval) UnmarshalYAML(unmarshal func(interface{}) error) err
 	return nil
 }
 
-// Config is the top-level configuration for Alertmanager's config files.
-type Config struct {
-	Global            *GlobalConfig      `yaml:"global,omitempty" json:"global,omitempty"`
-	Route             *Route             `yaml:"route,omitempty" json:"route,omitempty"`
-	InhibitRules      []*InhibitRule     `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
-	Receivers         []*Receiver        `yaml:"receivers,omitempty" json:"receivers,omitempty"`
-	Templates         []string           `yaml:"templates" json:"templates"`
-	MuteTimeIntervals []MuteTimeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
-
-	// original is the input from which the config was parsed.
-	original string
+// DefaultAckWebhooksConfig provides default values for AckWebhooksConfig.
+var DefaultAckWebhooksConfig = AckWebhooksConfig{
+	AckDuration:     model.Duration(24 * time.Hour),
+	SilenceDuration: model.Duration(24 * time.Hour),
 }
 
-func (c Config) String() string {
-	b, err := yaml.Marshal(c)
-	if err != nil {
-		return fmt.Sprintf("<error creating config string: %s>", err)
-	}
-	return string(b)
+// AckWebhooksConfig configures the endpoints receiving acknowledgement and
+// close events from on-call providers.
+type AckWebhooksConfig struct {
+	// The secret PagerDuty signs its webhook payloads with. If empty,
+	// signatures are not checked.
+	PagerDutySigningSecret Secret `yaml:"pagerduty_signing_secret,omitempty" json:"pagerduty_signing_secret,omitempty"`
+	// The token OpsGenie has to pass in the token query parameter. If empty,
+	// no token is required.
+	OpsGenieToken Secret `yaml:"opsgenie_token,omitempty" json:"opsgenie_token,omitempty"`
+
+	// How long an acknowledgement received from a provider lasts.
+	AckDuration model.Duration `yaml:"ack_duration,omitempty" json:"ack_duration,omitempty"`
+	// How long the alerts of a group closed in a provider are silenced.
+	SilenceDuration model.Duration `yaml:"silence_duration,omitempty" json:"silence_duration,omitempty"`
 }
 
-// UnmarshalYAML implements the yaml.Unmarshaler interface for Config.
-func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	// We want to set c to the defaults and then overwrite it with the input.
-	// To make unmarshal fill the plain data struct rather than calling UnmarshalYAML
-	// again, we have to hide it using a type indirection.
-	type plain Config
+// UnmarshalYAML implements the yaml.Unmarshaler interface for AckWebhooksConfig.
+func (c *AckWebhooksConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultAckWebhooksConfig
+	type plain AckWebhooksConfig
 	if err := unmarshal((*plain)(c)); err != nil {
 		return err
 	}
-
-	// If a global block was open but empty the default global config is overwritten.
-	// We have to restore it here.
-	if c.Global == nil {
-		c.Global = &GlobalConfig{}
-		*c.Global = DefaultGlobalConfig()
+	if c.AckDuration <= 0 {
+		return fmt.Errorf("ack_duration must be greater than zero")
 	}
-
-	if c.Global.SlackAPIURL != nil && len(c.Global.SlackAPIURLFile) > 0 {
-		return fmt.Errorf("at most one of slack_api_url & slack_api_url_file must be configured")
+	if c.SilenceDuration <= 0 {
+		return fmt.Errorf("silence_duration must be greater than zero")
 	}
+	return nil
+}
 
-	names := map[string]struct{}{}
-
-	for _, rcv := range c.Receivers {
-		if _, ok := names[rcv.Name]; ok {
-			return fmt.Errorf("notification config name %q is not unique", rcv.Name)
-		}
-		for _, wh := range rcv.WebhookConfigs {
-			if wh.HTTPConfig == nil {
-				wh.HTTPConfig = c.Global.HTTPConfig
-			}
-		}
-		for _, ec := range rcv.EmailConfigs {
-			if ec.Smarthost.String() == "" {
-				if c.Global.SMTPSmarthost.String() == "" {
-					return fmt.Errorf("no global SMTP smarthost set")
-				}
-				ec.Smarthost = c.Global.SMTPSmarthost
-			}
-			if ec.From == "" {
-				if c.Global.SMTPFrom == "" {
-					return fmt.Errorf("no global SMTP from set")
-				}
-				ec.From = c.Global.SMTPFrom
-			}
-			if ec.Hello == "" {
-				ec.Hello = c.Global.SMTPHello
-			}
-			if ec.AuthUsername == "" {
-				ec.AuthUsername = c.Global.SMTPAuthUsername
-			}
-			if ec.AuthPassword == "" {
-				ec.AuthPassword = c.Global.SMTPAuthPassword
-			}
-			if ec.AuthSecret == "" {
-				ec.AuthSecret = c.Global.SMTPAuthSecret
-			}
-			if ec.AuthIdentity == "" {
-				ec.AuthIdentity = c.Global.SMTPAuthIdentity
-			}
-			if ec.RequireTLS == nil {
-				ec.RequireTLS = new(bool)
-				*ec.RequireTLS = c.Global.SMTPRequireTLS
-			}
-		}
-		for _, sc := range rcv.SlackConfigs {
-			if sc.HTTPConfig == nil {
-				sc.HTTPConfig = c.Global.HTTPConfig
-			}
-			if sc.APIURL == nil && len(sc.APIURLFile) == 0 {
-				if c.Global.SlackAPIURL == nil && len(c.Global.SlackAPIURLFile) == 0 {
-					return fmt.Errorf("no global Slack API URL set either inline or in a file")
-				}
-				sc.APIURL = c.Global.SlackAPIURL
-				sc.APIURLFile = c.Global.SlackAPIURLFile
-			}
-		}
-		for _, poc := range rcv.PushoverConfigs {
-			if poc.HTTPConfig == nil {
-				poc.HTTPConfig = c.Global.HTTPConfig
-			}
-		}
-		for _, pdc := range rcv.PagerdutyConfigs {
-			if pdc.HTTPConfig == nil {
-				pdc.HTTPConfig = c.Global.HTTPConfig
-			}
-			if pdc.URL == nil {
-				if c.Global.PagerdutyURL == nil {
-					return fmt.Errorf("no global PagerDuty URL set")
-				}
-				pdc.URL = c.Global.PagerdutyURL
-			}
-		}
-		for _, ogc := range rcv.OpsGenieConfigs {
-			if ogc.HTTPConfig == nil {
-				ogc.HTTPConfig = c.Global.HTTPConfig
-			}
-			if ogc.APIURL == nil {
-				if c.Global.OpsGenieAPIURL == nil {
-					return fmt.Errorf("no global OpsGenie URL set")
-				}
-				ogc.APIURL = c.Global.OpsGenieAPIURL
-			}
-			if !strings.HasSuffix(ogc.APIURL.Path, "/") {
-				ogc.APIURL.Path += "/"
-			}
-			if ogc.APIKey == "" {
-				if c.Global.OpsGenieAPIKey == "" {
-					return fmt.Errorf("no global OpsGenie API Key set")
-				}
-				ogc.APIKey = c.Global.OpsGenieAPIKey
-			}
-		}
-		for _, wcc := range rcv.WechatConfigs {
-			if wcc.HTTPConfig == nil {
-				wcc.HTTPConfig = c.Global.HTTPConfig
-			}
-
-			if wcc.APIURL == nil {
-				if c.Global.WeChatAPIURL == nil {
-					return fmt.Errorf("no global Wechat URL set")
-				}
-				wcc.APIURL = c.Global.WeChatAPIURL
-			}
-
-			if wcc.APISecret == "" {
-				if c.Global.WeChatAPISecret == "" {
-					return fmt.Errorf("no global Wechat ApiSecret set")
-				}
-				wcc.APISecret = c.Global.WeChatAPISecret
-			}
-
-			if wcc.CorpID == "" {
-				if c.Global.WeChatAPICorpID == "" {
-					return fmt.Errorf("no global Wechat CorpID set")
-				}
-				wcc.CorpID = c.Global.WeChatAPICorpID
-			}
+// IngestionLimitsConfig limits the alerts accepted by the alert APIs to
+// protect the Alertmanager's memory during alert storms. A limit of zero
+// disables it.
+type IngestionLimitsConfig struct {
+	// The maximum number of alerts held in memory. Resolved alerts count
+	// until they are garbage collected. Updates of alerts which are already
+	// held are accepted when the limit is reached.
+	MaxActiveAlerts int `yaml:"max_active_alerts,omitempty" json:"max_active_alerts,omitempty"`
+	// The maximum size of the label names and values of an alert in bytes.
+	MaxLabelBytes int `yaml:"max_label_bytes,omitempty" json:"max_label_bytes,omitempty"`
+	// The maximum size of the annotation names and values of an alert in bytes.
+	MaxAnnotationBytes int `yaml:"max_annotation_bytes,omitempty" json:"max_annotation_bytes,omitempty"`
+	// The maximum size of each annotation value of an alert in bytes.
+	MaxAnnotationValueBytes int `yaml:"max_annotation_value_bytes,omitempty" json:"max_annotation_value_bytes,omitempty"`
+	// AnnotationSizePolicy selects what happens to alerts whose annotations
+	// exceed the size limits. Alerts are rejected if it is empty.
+	AnnotationSizePolicy SizePolicy `yaml:"annotation_size_policy,omitempty" json:"annotation_size_policy,omitempty"`
+	// The number of requests per second each client may make to post
+	// alerts, and how many requests may be made at once.
+	ClientRequestsPerSecond float64 `yaml:"client_requests_per_second,omitempty" json:"client_requests_per_second,omitempty"`
+	ClientRequestBurst      int     `yaml:"client_request_burst,omitempty" json:"client_request_burst,omitempty"`
+	// The number of alerts per second each client may post, and how many
+	// alerts may be posted at once.
+	ClientAlertsPerSecond float64 `yaml:"client_alerts_per_second,omitempty" json:"client_alerts_per_second,omitempty"`
+	ClientAlertBurst      int     `yaml:"client_alert_burst,omitempty" json:"client_alert_burst,omitempty"`
+}
 
-			if !strings.HasSuffix(wcc.APIURL.Path, "/") {
-				wcc.APIURL.Path += "/"
-			}
-		}
-		for _, voc := range rcv.VictorOpsConfigs {
-			if voc.HTTPConfig == nil {
-				voc.HTTPConfig = c.Global.HTTPConfig
-			}
-			if voc.APIURL == nil {
-				if c.Global.VictorOpsAPIURL == nil {
-					return fmt.Errorf("no global VictorOps URL set")
-				}
-				voc.APIURL = c.Global.VictorOpsAPIURL
-			}
-			if !strings.HasSuffix(voc.APIURL.Path, "/") {
-				voc.APIURL.Path += "/"
-			}
-			if voc.APIKey == "" {
-				if c.Global.VictorOpsAPIKey == "" {
-					return fmt.Errorf("no global VictorOps API Key set")
-				}
-				voc.APIKey = c.Global.VictorOpsAPIKey
-			}
-		}
-		for _, sns := range rcv.SNSConfigs {
-			if sns.HTTPConfig == nil {
-				sns.HTTPConfig = c.Global.HTTPConfig
-			}
-		}
-		names[rcv.Name] = struct{}{}
+// UnmarshalYAML implements the yaml.Unmarshaler interface for IngestionLimitsConfig.
+func (c *IngestionLimitsConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain IngestionLimitsConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
 	}
-
-	// The root route must not have any matchers as it is the fallback node
-	// for all alerts.
-	if c.Route == nil {
-		return fmt.Errorf("no routes provided")
+	if c.MaxActiveAlerts < 0 {
+		return fmt.Errorf("max_active_alerts must not be negative")
 	}
-	if len(c.Route.Receiver) == 0 {
-		return fmt.Errorf("root route must specify a default receiver")
+	if c.MaxLabelBytes < 0 {
+		return fmt.Errorf("max_label_bytes must not be negative")
 	}
-	if len(c.Route.Match) > 0 || len(c.Route.MatchRE) > 0 {
-		return fmt.Errorf("root route must not have any matchers")
+	if c.MaxAnnotationBytes < 0 {
+		return fmt.Errorf("max_annotation_bytes must not be negative")
 	}
-	if len(c.Route.MuteTimeIntervals) > 0 {
-		return fmt.Errorf("root route must not have any mute time intervals")
+	if c.MaxAnnotationValueBytes < 0 {
+		return fmt.Errorf("max_annotation_value_bytes must not be negative")
 	}
+	if c.ClientRequestsPerSecond < 0 || c.ClientRequestBurst < 0 {
+		return fmt.Errorf("client_requests_per_second and client_request_burst must not be negative")
+	}
+	if c.ClientAlertsPerSecond < 0 || c.ClientAlertBurst < 0 {
+		return fmt.Errorf("client_alerts_per_second and client_alert_burst must not be negative")
+	}
+	return nil
+}
 
-	// Validate that all receivers used in the routing tree are defined.
-	if err := checkReceiver(c.Route, names); err != nil {
+// SizePolicy is what happens to alerts exceeding a size limit.
+type SizePolicy string
+
+// The policies for alerts exceeding a size limit.
+const (
+	// SizePolicyReject rejects the alerts.
+	SizePolicyReject SizePolicy = "reject"
+	// SizePolicyTruncate truncates the values exceeding the limits and marks
+	// them as truncated.
+	SizePolicyTruncate SizePolicy = "truncate"
+)
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for SizePolicy.
+func (p *SizePolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
 		return err
 	}
-
-	tiNames := make(map[string]struct{})
-	for _, mt := range c.MuteTimeIntervals {
-		if _, ok := tiNames[mt.Name]; ok {
-			return fmt.Errorf("mute time interval %q is not unique", mt.Name)
-		}
-		tiNames[mt.Name] = struct{}{}
+	switch SizePolicy(s) {
+	case SizePolicyReject, SizePolicyTruncate:
+	default:
+		return fmt.Errorf("unknown size policy %q, must be reject or truncate", s)
 	}
-	return checkTimeInterval(c.Route, tiNames)
+	*p = SizePolicy(s)
+	return nil
 }
 
-// checkReceiver returns an error if a node in the routing tree
-// references a receiver not in the given map.
-func checkReceiver(r *Route, receivers map[string]struct{}) error {
-	for _, sr := range r.Routes {
+// SilencePolicyConfig restricts the silences which can be created through
+// the APIs. A limit of zero disables it.
+type SilencePolicyConfig struct {
+	// The maximum time between the start and the end of a silence.
+	MaxDuration model.Duration `yaml:"max_duration,omitempty" json:"max_duration,omitempty"`
+	// Whether silences must have a comment.
+	RequireComment bool `yaml:"require_comment,omitempty" json:"require_comment,omitempty"`
+	// The minimum number of matchers of a silence.
+	MinMatchers int `yaml:"min_matchers,omitempty" json:"min_matchers,omitempty"`
+	// The maximum number of active and pending silences per creator.
+	MaxActivePerCreator int `yaml:"max_active_per_creator,omitempty" json:"max_active_per_creator,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for SilencePolicyConfig.
+func (c *SilencePolicyConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain SilencePolicyConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.MaxDuration < 0 {
+		return fmt.Errorf("max_duration must not be negative")
+	}
+	if c.MinMatchers < 0 {
+		return fmt.Errorf("min_matchers must not be negative")
+	}
+	if c.MaxActivePerCreator < 0 {
+		return fmt.Errorf("max_active_per_creator must not be negative")
+	}
+	return nil
+}
+
+// Role is the set of actions a client of the web server may take.
+type Role string
+
+// The roles, each one allowing the actions of the previous ones.
+const (
+	// RoleNone allows no action.
+	RoleNone Role = "none"
+	// RoleViewer allows reading alerts, silences and the status.
+	RoleViewer Role = "viewer"
+	// RoleEditor allows posting alerts and changing silences and
+	// acknowledgements.
+	RoleEditor Role = "editor"
+	// RoleAdmin allows reloading the configuration and replaying or
+	// deleting dead letters.
+	RoleAdmin Role = "admin"
+)
+
+var roleRanks = map[Role]int{RoleNone: 0, RoleViewer: 1, RoleEditor: 2, RoleAdmin: 3}
+
+// Allows returns whether the role allows the actions of the other role.
+func (r Role) Allows(other Role) bool {
+	return roleRanks[r] >= roleRanks[other]
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Role.
+func (r *Role) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	if _, ok := roleRanks[Role(s)]; !ok {
+		return fmt.Errorf("unknown role %q, must be one of none, viewer, editor or admin", s)
+	}
+	*r = Role(s)
+	return nil
+}
+
+// DefaultAuthorizationConfig provides default values for the authorization.
+var DefaultAuthorizationConfig = AuthorizationConfig{
+	DefaultRole: RoleNone,
+}
+
+// AuthorizationConfig maps the identities of the clients of the web server,
+// their basic authentication user, the name of their bearer token, the common
+// name of their client certificate or their OpenID Connect user, to roles.
+type AuthorizationConfig struct {
+	// Roles maps identities to their role.
+	Roles map[string]Role `yaml:"roles,omitempty" json:"roles,omitempty"`
+	// GroupRoles maps the groups of OpenID Connect users to their role.
+	GroupRoles map[string]Role `yaml:"group_roles,omitempty" json:"group_roles,omitempty"`
+	// DefaultRole is the role of the identities not listed in Roles,
+	// including anonymous clients.
+	DefaultRole Role `yaml:"default_role,omitempty" json:"default_role,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for AuthorizationConfig.
+func (c *AuthorizationConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultAuthorizationConfig
+	type plain AuthorizationConfig
+	return unmarshal((*plain)(c))
+}
+
+// Role returns the role of the identity, the highest of the role of the
+// identity, or the default role if it isn't listed, and of the roles of its
+// groups.
+func (c *AuthorizationConfig) Role(identity string, groups ...string) Role {
+	role := c.DefaultRole
+	if r, ok := c.Roles[identity]; ok && identity != "" {
+		role = r
+	}
+	for _, g := range groups {
+		if r, ok := c.GroupRoles[g]; ok && !role.Allows(r) {
+			role = r
+		}
+	}
+	return role
+}
+
+// DefaultTenancyConfig provides default values for the tenancy.
+var DefaultTenancyConfig = TenancyConfig{
+	Header: "X-Scope-OrgID",
+	Label:  "tenant",
+}
+
+// TenancyConfig partitions the alerts and silences between tenants. The
+// requests to API v2 are scoped to the tenant of their client: the alerts
+// they post are labeled with the tenant, and they only see and change the
+// alerts and silences of the tenant.
+type TenancyConfig struct {
+	// Header is the HTTP header naming the tenant of a request, which
+	// admins may set to act as any tenant.
+	Header string `yaml:"header,omitempty" json:"header,omitempty"`
+	// Tenants maps the identities of the clients to their tenant.
+	Tenants map[string]string `yaml:"tenants,omitempty" json:"tenants,omitempty"`
+	// Label is the label holding the tenant of the alerts.
+	Label model.LabelName `yaml:"label,omitempty" json:"label,omitempty"`
+	// OverlaysDir is the directory holding the routes and receivers of the
+	// tenants, one <tenant>.yml file per tenant.
+	OverlaysDir string `yaml:"overlays_dir,omitempty" json:"overlays_dir,omitempty"`
+	// Budget limits the notifications sent for the alerts of each tenant.
+	Budget *NotificationBudget `yaml:"budget,omitempty" json:"budget,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for TenancyConfig.
+func (c *TenancyConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultTenancyConfig
+	type plain TenancyConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Header == "" {
+		return fmt.Errorf("missing header in tenancy config")
+	}
+	if !c.Label.IsValid() {
+		return fmt.Errorf("invalid tenant label %q", c.Label)
+	}
+	for id, t := range c.Tenants {
+		if id == "" || t == "" {
+			return fmt.Errorf("empty identity or tenant in tenancy config")
+		}
+	}
+	return nil
+}
+
+// Tenant returns the tenant of the client with the given identity, and
+// whether it has one. Anonymous clients have no tenant.
+func (c *TenancyConfig) Tenant(identity string) (string, bool) {
+	t, ok := c.Tenants[identity]
+	return t, ok && identity != ""
+}
+
+// DefaultOIDCConfig provides default values for the OpenID Connect
+// authentication.
+var DefaultOIDCConfig = OIDCConfig{
+	Scopes:          []string{"openid", "profile", "email"},
+	UsernameClaim:   "email",
+	GroupsClaim:     "groups",
+	SessionDuration: model.Duration(12 * time.Hour),
+}
+
+// OIDCConfig configures the login of the users of the web interface and API
+// with an OpenID Connect provider, using the authorization code flow.
+type OIDCConfig struct {
+	// IssuerURL is the URL of the provider, from which its configuration
+	// is discovered.
+	IssuerURL    *URL   `yaml:"issuer_url" json:"issuer_url"`
+	ClientID     string `yaml:"client_id" json:"client_id"`
+	ClientSecret Secret `yaml:"client_secret" json:"client_secret"`
+	// Scopes requested from the provider.
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	// UsernameClaim is the claim of the ID token identifying the user.
+	UsernameClaim string `yaml:"username_claim,omitempty" json:"username_claim,omitempty"`
+	// GroupsClaim is the claim of the ID token listing the groups of the
+	// user.
+	GroupsClaim string `yaml:"groups_claim,omitempty" json:"groups_claim,omitempty"`
+	// SessionDuration is how long users stay logged in.
+	SessionDuration model.Duration `yaml:"session_duration,omitempty" json:"session_duration,omitempty"`
+	// HTTPConfig is used to talk to the provider. It defaults to the
+	// global HTTP client configuration.
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for OIDCConfig.
+func (c *OIDCConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultOIDCConfig
+	type plain OIDCConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.IssuerURL == nil {
+		return fmt.Errorf("missing issuer_url in OIDC config")
+	}
+	if c.ClientID == "" || c.ClientSecret == "" {
+		return fmt.Errorf("missing client_id or client_secret in OIDC config")
+	}
+	openid := false
+	for _, s := range c.Scopes {
+		openid = openid || s == "openid"
+	}
+	if !openid {
+		return fmt.Errorf("scopes of OIDC config must include openid")
+	}
+	if c.UsernameClaim == "" {
+		return fmt.Errorf("missing username_claim in OIDC config")
+	}
+	if c.SessionDuration <= 0 {
+		return fmt.Errorf("session_duration of OIDC config must be greater than zero")
+	}
+	return nil
+}
+
+// DefaultTracingConfig provides default values for the export of traces.
+var DefaultTracingConfig = TracingConfig{
+	SamplingFraction: 1,
+	Timeout:          model.Duration(10 * time.Second),
+}
+
+// TracingConfig configures the export of traces to a receiver of the
+// OpenTelemetry protocol over HTTP.
+type TracingConfig struct {
+	// Endpoint is the URL of the receiver, to which /v1/traces is appended.
+	Endpoint *URL `yaml:"endpoint" json:"endpoint"`
+	// SamplingFraction is the fraction of the traces which are recorded.
+	SamplingFraction float64 `yaml:"sampling_fraction" json:"sampling_fraction"`
+	// Timeout of the requests exporting traces.
+	Timeout model.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// HTTPConfig is used to talk to the receiver. It defaults to the global
+	// HTTP client configuration.
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for TracingConfig.
+func (c *TracingConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultTracingConfig
+	type plain TracingConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Endpoint == nil {
+		return fmt.Errorf("missing endpoint in tracing config")
+	}
+	if c.SamplingFraction < 0 || c.SamplingFraction > 1 {
+		return fmt.Errorf("sampling_fraction of tracing config must be between 0 and 1")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout of tracing config must be greater than zero")
+	}
+	return nil
+}
+
+// DefaultKafkaIngesterConfig provides default values for the Kafka ingesters.
+var DefaultKafkaIngesterConfig = KafkaIngesterConfig{
+	Group:           "alertmanager",
+	AutoOffsetReset: "latest",
+	PollTimeout:     model.Duration(5 * time.Second),
+}
+
+// KafkaIngesterConfig configures the consumption of alerts from Kafka topics,
+// through the consumer API of a Kafka REST Proxy.
+type KafkaIngesterConfig struct {
+	// RESTProxyURL is the base URL of the REST Proxy.
+	RESTProxyURL *URL `yaml:"rest_proxy_url" json:"rest_proxy_url"`
+	// Topics from which alerts are consumed.
+	Topics []string `yaml:"topics" json:"topics"`
+	// Group is the consumer group sharing the partitions of the topics.
+	Group string `yaml:"group,omitempty" json:"group,omitempty"`
+	// AutoOffsetReset is where a group starts consuming partitions without
+	// committed offset: earliest or latest.
+	AutoOffsetReset string `yaml:"auto_offset_reset,omitempty" json:"auto_offset_reset,omitempty"`
+	// PollTimeout is how long the REST Proxy waits for records.
+	PollTimeout model.Duration `yaml:"poll_timeout,omitempty" json:"poll_timeout,omitempty"`
+	// HTTPConfig is used to talk to the REST Proxy. It defaults to the global
+	// HTTP client configuration.
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for KafkaIngesterConfig.
+func (c *KafkaIngesterConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultKafkaIngesterConfig
+	type plain KafkaIngesterConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.RESTProxyURL == nil {
+		return fmt.Errorf("missing rest_proxy_url in Kafka ingester config")
+	}
+	if len(c.Topics) == 0 {
+		return fmt.Errorf("missing topics in Kafka ingester config")
+	}
+	if c.Group == "" {
+		return fmt.Errorf("group of Kafka ingester config must not be empty")
+	}
+	if c.AutoOffsetReset != "earliest" && c.AutoOffsetReset != "latest" {
+		return fmt.Errorf("auto_offset_reset of Kafka ingester config must be earliest or latest")
+	}
+	if c.PollTimeout <= 0 {
+		return fmt.Errorf("poll_timeout of Kafka ingester config must be greater than zero")
+	}
+	return nil
+}
+
+// DefaultSQSIngesterConfig provides default values for the SQS ingesters.
+// The default mapping suits the notifications of CloudWatch alarms.
+var DefaultSQSIngesterConfig = SQSIngesterConfig{
+	WaitTime:    model.Duration(20 * time.Second),
+	MaxMessages: 10,
+	StatusField: "NewStateValue",
+}
+
+// SQSIngesterConfig configures the consumption of alerts from an Amazon SQS
+// queue. Every message is a JSON object, possibly wrapped in an SNS
+// notification, whose fields are mapped to the labels and annotations of an
+// alert.
+type SQSIngesterConfig struct {
+	// QueueURL is the URL of the queue.
+	QueueURL string `yaml:"queue_url" json:"queue_url"`
+	// APIUrl overrides the SQS endpoint.
+	APIUrl string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	Sigv4  sigv4.SigV4Config `yaml:"sigv4" json:"sigv4"`
+	// WaitTime is how long a receive request waits for messages.
+	WaitTime model.Duration `yaml:"wait_time,omitempty" json:"wait_time,omitempty"`
+	// MaxMessages is the number of messages received at most per request.
+	MaxMessages int64 `yaml:"max_messages,omitempty" json:"max_messages,omitempty"`
+	// Labels maps label names to the dot-separated paths of the message
+	// fields holding their values.
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// Annotations maps annotation names to message fields like Labels.
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	// StatusField is the path of the message field holding the state of
+	// the alert, which is resolved if the value is one of ResolvedValues.
+	StatusField    string   `yaml:"status_field,omitempty" json:"status_field,omitempty"`
+	ResolvedValues []string `yaml:"resolved_values,omitempty" json:"resolved_values,omitempty"`
+	// HTTPConfig is used to talk to SQS. It defaults to the global HTTP
+	// client configuration.
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for SQSIngesterConfig.
+func (c *SQSIngesterConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultSQSIngesterConfig
+	type plain SQSIngesterConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.QueueURL == "" {
+		return fmt.Errorf("missing queue_url in SQS ingester config")
+	}
+	if (c.Sigv4.AccessKey == "") != (c.Sigv4.SecretKey == "") {
+		return fmt.Errorf("must provide a AWS SigV4 Access key and Secret Key if credentials are specified in the SQS ingester config")
+	}
+	if c.WaitTime < 0 || time.Duration(c.WaitTime) > 20*time.Second {
+		return fmt.Errorf("wait_time of SQS ingester config must be between 0s and 20s")
+	}
+	if c.MaxMessages < 1 || c.MaxMessages > 10 {
+		return fmt.Errorf("max_messages of SQS ingester config must be between 1 and 10")
+	}
+	// The maps and slices of the defaults are set here rather than in
+	// DefaultSQSIngesterConfig, which the YAML decoder would modify.
+	if c.Labels == nil {
+		c.Labels = map[string]string{"alertname": "AlarmName"}
+	}
+	if c.Annotations == nil {
+		c.Annotations = map[string]string{"description": "AlarmDescription", "reason": "NewStateReason"}
+	}
+	if c.ResolvedValues == nil {
+		c.ResolvedValues = []string{"OK"}
+	}
+	for name := range c.Labels {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q in SQS ingester config", name)
+		}
+	}
+	return nil
+}
+
+// DefaultWatchdog provides default values for the watchdogs.
+var DefaultWatchdog = Watchdog{
+	Interval: model.Duration(10 * time.Minute),
+}
+
+// Watchdog expects alerts matching its matchers to be received continuously,
+// such as an always-firing Watchdog alert of Prometheus, and notifies its
+// receiver if none was received within the interval.
+type Watchdog struct {
+	Name     string         `yaml:"name" json:"name"`
+	Matchers Matchers       `yaml:"matchers" json:"matchers"`
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Receiver string         `yaml:"receiver" json:"receiver"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Watchdog.
+func (w *Watchdog) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*w = DefaultWatchdog
+	type plain Watchdog
+	if err := unmarshal((*plain)(w)); err != nil {
+		return err
+	}
+	if w.Name == "" {
+		return fmt.Errorf("missing name in watchdog")
+	}
+	if len(w.Matchers) == 0 {
+		return fmt.Errorf("watchdog %q has no matchers", w.Name)
+	}
+	if w.Interval <= 0 {
+		return fmt.Errorf("interval of watchdog %q must be greater than zero", w.Name)
+	}
+	if w.Receiver == "" {
+		return fmt.Errorf("missing receiver in watchdog %q", w.Name)
+	}
+	return nil
+}
+
+// DefaultHeartbeatConfig provides default values for the heartbeat.
+var DefaultHeartbeatConfig = HeartbeatConfig{
+	Interval: model.Duration(time.Minute),
+}
+
+// HeartbeatConfig configures the pings of an OpsGenie heartbeat, which pages
+// through OpsGenie if the Alertmanager stops pinging it.
+type HeartbeatConfig struct {
+	// Name is the name of the heartbeat in OpsGenie.
+	Name string `yaml:"name" json:"name"`
+	// Interval is the time between two pings.
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// APIKey and APIURL default to the global OpsGenie settings.
+	APIKey Secret `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	APIURL *URL   `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	// HTTPConfig defaults to the global HTTP client configuration.
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for HeartbeatConfig.
+func (c *HeartbeatConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultHeartbeatConfig
+	type plain HeartbeatConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Name == "" {
+		return fmt.Errorf("missing name in heartbeat config")
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval of heartbeat config must be greater than zero")
+	}
+	return nil
+}
+
+// LifecycleEvents are the types of the events sent to the lifecycle webhook.
+var LifecycleEvents = []string{"received", "grouped", "silenced", "inhibited", "notified", "resolved"}
+
+// DefaultLifecycleWebhookConfig provides default values for the lifecycle
+// webhook.
+var DefaultLifecycleWebhookConfig = LifecycleWebhookConfig{
+	MaxEvents:     100,
+	FlushInterval: model.Duration(10 * time.Second),
+}
+
+// LifecycleWebhookConfig configures the endpoint receiving the state
+// transitions of every alert, independently of the receivers.
+type LifecycleWebhookConfig struct {
+	// URL is the endpoint the events are posted to.
+	URL *SecretURL `yaml:"url" json:"url"`
+	// Events are the types of the events sent. All are sent if empty.
+	Events []string `yaml:"events,omitempty" json:"events,omitempty"`
+	// MaxEvents is the maximum number of events in a request.
+	MaxEvents int `yaml:"max_events,omitempty" json:"max_events,omitempty"`
+	// FlushInterval is the maximum time an event waits to be sent.
+	FlushInterval model.Duration `yaml:"flush_interval,omitempty" json:"flush_interval,omitempty"`
+	// HTTPConfig defaults to the global HTTP client configuration.
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for LifecycleWebhookConfig.
+func (c *LifecycleWebhookConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultLifecycleWebhookConfig
+	type plain LifecycleWebhookConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.URL == nil {
+		return fmt.Errorf("missing url in lifecycle webhook config")
+	}
+	for _, e := range c.Events {
+		if !containsString(LifecycleEvents, e) {
+			return fmt.Errorf("unknown lifecycle event %q, must be one of %s", e, strings.Join(LifecycleEvents, ", "))
+		}
+	}
+	if c.MaxEvents <= 0 {
+		return fmt.Errorf("max_events of lifecycle webhook config must be greater than zero")
+	}
+	if c.FlushInterval <= 0 {
+		return fmt.Errorf("flush_interval of lifecycle webhook config must be greater than zero")
+	}
+	return nil
+}
+
+// NotificationAnalyticsTypes are the formats of the notification analytics
+// events.
+var NotificationAnalyticsTypes = []string{"honeycomb", "otlp"}
+
+// DefaultNotificationAnalyticsConfig provides default values for the
+// notification analytics.
+var DefaultNotificationAnalyticsConfig = NotificationAnalyticsConfig{
+	MaxEvents:     100,
+	FlushInterval: model.Duration(10 * time.Second),
+}
+
+// NotificationAnalyticsConfig configures the export of an event for every
+// notification attempt, to analyze the notification pipeline itself.
+type NotificationAnalyticsConfig struct {
+	// Type is one of NotificationAnalyticsTypes.
+	Type string `yaml:"type" json:"type"`
+	// URL is the base URL of the Honeycomb API, or the OTLP/HTTP logs
+	// endpoint.
+	URL *URL `yaml:"url,omitempty" json:"url,omitempty"`
+	// Dataset and APIKey identify the Honeycomb dataset.
+	Dataset string `yaml:"dataset,omitempty" json:"dataset,omitempty"`
+	APIKey  Secret `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	// MaxEvents is the maximum number of events in a request.
+	MaxEvents int `yaml:"max_events,omitempty" json:"max_events,omitempty"`
+	// FlushInterval is the maximum time an event waits to be sent.
+	FlushInterval model.Duration `yaml:"flush_interval,omitempty" json:"flush_interval,omitempty"`
+	// HTTPConfig defaults to the global HTTP client configuration.
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for NotificationAnalyticsConfig.
+func (c *NotificationAnalyticsConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultNotificationAnalyticsConfig
+	type plain NotificationAnalyticsConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	switch c.Type {
+	case "honeycomb":
+		if c.URL == nil {
+			c.URL = mustParseURL("https://api.honeycomb.io")
+		}
+		if c.Dataset == "" {
+			return fmt.Errorf("missing dataset in notification analytics config")
+		}
+		if c.APIKey == "" {
+			return fmt.Errorf("missing api_key in notification analytics config")
+		}
+	case "otlp":
+		if c.URL == nil {
+			return fmt.Errorf("missing url in notification analytics config")
+		}
+	default:
+		return fmt.Errorf("unknown notification analytics type %q, must be one of %s", c.Type, strings.Join(NotificationAnalyticsTypes, ", "))
+	}
+	if c.MaxEvents <= 0 {
+		return fmt.Errorf("max_events of notification analytics config must be greater than zero")
+	}
+	if c.FlushInterval <= 0 {
+		return fmt.Errorf("flush_interval of notification analytics config must be greater than zero")
+	}
+	return nil
+}
+
+// DefaultCORSConfig provides default values for the cross-origin resource
+// sharing.
+var DefaultCORSConfig = CORSConfig{
+	AllowedOrigins: []string{"*"},
+	AllowedMethods: []string{"GET", "HEAD", "POST", "DELETE"},
+	AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "Origin"},
+}
+
+// CORSConfig configures the cross-origin resource sharing of API v2, which
+// allows web pages served from other origins to call the API.
+type CORSConfig struct {
+	// AllowedOrigins are the origins allowed to call the API. An origin may
+	// contain one '*' wildcard, and '*' alone allows every origin.
+	AllowedOrigins []string `yaml:"allowed_origins,omitempty" json:"allowed_origins,omitempty"`
+	// AllowedMethods are the HTTP methods cross-origin requests may use.
+	AllowedMethods []string `yaml:"allowed_methods,omitempty" json:"allowed_methods,omitempty"`
+	// AllowedHeaders are the headers cross-origin requests may carry.
+	AllowedHeaders []string `yaml:"allowed_headers,omitempty" json:"allowed_headers,omitempty"`
+	// ExposedHeaders are the response headers exposed to the calling page.
+	ExposedHeaders []string `yaml:"exposed_headers,omitempty" json:"exposed_headers,omitempty"`
+	// AllowCredentials allows cross-origin requests to carry credentials
+	// such as cookies and HTTP authentication.
+	AllowCredentials bool `yaml:"allow_credentials,omitempty" json:"allow_credentials,omitempty"`
+	// MaxAge is how long the result of a preflight request may be cached.
+	MaxAge model.Duration `yaml:"max_age,omitempty" json:"max_age,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for CORSConfig.
+func (c *CORSConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultCORSConfig
+	type plain CORSConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if len(c.AllowedOrigins) == 0 {
+		return fmt.Errorf("missing allowed_origins in CORS config")
+	}
+	if len(c.AllowedMethods) == 0 {
+		return fmt.Errorf("missing allowed_methods in CORS config")
+	}
+	for _, o := range c.AllowedOrigins {
+		if strings.Count(o, "*") > 1 {
+			return fmt.Errorf("allowed origin %q may contain at most one wildcard", o)
+		}
+		if o == "*" && c.AllowCredentials {
+			return fmt.Errorf("allow_credentials requires explicit allowed_origins")
+		}
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("max_age of CORS config must not be negative")
+	}
+	return nil
+}
+
+// tenantOverlay is the part of the configuration a tenant may override.
+type tenantOverlay struct {
+	Route     *Route      `yaml:"route"`
+	Receivers []*Receiver `yaml:"receivers,omitempty"`
+}
+
+// AddTenantOverlay validates the routes and receivers of a tenant given in
+// YAML and merges them into the configuration. The receivers of the tenant
+// are renamed to <tenant>/<name>, and its routing tree is prepended to the
+// routes of the root route, matching the alerts of the tenant only. Relative
+// paths in the overlay are resolved against baseDir. The configuration is
+// left unchanged if the overlay is invalid.
+func (c *Config) AddTenantOverlay(tenant, s, baseDir string) error {
+	if c.Tenancy == nil {
+		return fmt.Errorf("tenancy is not configured")
+	}
+	if tenant == "" || !model.LabelValue(tenant).IsValid() {
+		return fmt.Errorf("invalid tenant %q", tenant)
+	}
+	// Only routes and receivers may be given.
+	if err := yaml.UnmarshalStrict([]byte(s), &tenantOverlay{}); err != nil {
+		return err
+	}
+	// The overlay is validated like a configuration of its own, with the
+	// global settings and time intervals of the configuration.
+	global := *c.Global
+	o := &Config{Global: &global, MuteTimeIntervals: c.MuteTimeIntervals}
+	if err := yaml.UnmarshalStrict([]byte(s), o); err != nil {
+		return locate(s, o, err)
+	}
+	if o.Route == nil {
+		return fmt.Errorf("no route provided in tenant overlay")
+	}
+	if o.Route.Continue {
+		return fmt.Errorf("cannot have continue in root route of tenant overlay")
+	}
+	if o.Route.Unrouted != nil {
+		return fmt.Errorf("cannot have unrouted in root route of tenant overlay")
+	}
+	resolveReceiverFilepaths(baseDir, o.Receivers, global.HTTPConfig)
+
+	names := make(map[string]struct{}, len(c.Receivers))
+	for _, rcv := range c.Receivers {
+		names[rcv.Name] = struct{}{}
+	}
+	renamed := make(map[string]string, len(o.Receivers))
+	for _, rcv := range o.Receivers {
+		name := tenant + "/" + rcv.Name
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("notification config name %q is not unique", name)
+		}
+		renamed[rcv.Name] = name
+	}
+
+	for _, rcv := range o.Receivers {
+		rcv.Name = renamed[rcv.Name]
+	}
+	renameReceivers(o.Route, renamed)
+	addGroupBy(o.Route, c.Tenancy.Label, false)
+	m, err := labels.NewMatcher(labels.MatchEqual, string(c.Tenancy.Label), tenant)
+	if err != nil {
+		return err
+	}
+	o.Route.Matchers = append(Matchers{m}, o.Route.Matchers...)
+
+	c.Receivers = append(c.Receivers, o.Receivers...)
+	c.Route.Routes = append([]*Route{o.Route}, c.Route.Routes...)
+	c.original += "\n# Overlay of tenant " + tenant + "\n" + s
+	return nil
+}
+
+// renameReceivers renames the receivers used in the routing tree.
+func renameReceivers(r *Route, renamed map[string]string) {
+	for _, sr := range r.Routes {
+		renameReceivers(sr, renamed)
+	}
+	for _, e := range r.Escalation {
+		e.Receiver = renamed[e.Receiver]
+	}
+	if r.Receiver != "" {
+		r.Receiver = renamed[r.Receiver]
+	}
+}
+
+// SilencePreset is a named skeleton for silences which are created often,
+// such as maintenance silences. Its matchers and comment are Go templates
+// which are executed with the parameters given when the preset is
+// instantiated.
+type SilencePreset struct {
+	Name string `yaml:"name" json:"name"`
+	// Matchers in the same syntax as route matchers, e.g.
+	// 'instance="{{ .instance }}"'.
+	Matchers []string       `yaml:"matchers" json:"matchers"`
+	Duration model.Duration `yaml:"duration" json:"duration"`
+	Comment  string         `yaml:"comment,omitempty" json:"comment,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for SilencePreset.
+func (p *SilencePreset) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain SilencePreset
+	if err := unmarshal((*plain)(p)); err != nil {
+		return err
+	}
+	if p.Name == "" {
+		return fmt.Errorf("missing name in silence preset")
+	}
+	if len(p.Matchers) == 0 {
+		return fmt.Errorf("silence preset %q has no matchers", p.Name)
+	}
+	if p.Duration <= 0 {
+		return fmt.Errorf("duration of silence preset %q must be greater than zero", p.Name)
+	}
+	for _, m := range append([]string{p.Comment}, p.Matchers...) {
+		if _, err := template.New("").Parse(m); err != nil {
+			return fmt.Errorf("invalid template in silence preset %q: %v", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Config is the top-level configuration for Alertmanager's config files.
+type Config struct {
+	Global            *GlobalConfig      `yaml:"global,omitempty" json:"global,omitempty"`
+	Route             *Route             `yaml:"route,omitempty" json:"route,omitempty"`
+	InhibitRules      []*InhibitRule     `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
+	SuppressionRules  []*SuppressionRule `yaml:"suppression_rules,omitempty" json:"suppression_rules,omitempty"`
+	Receivers         []*Receiver        `yaml:"receivers,omitempty" json:"receivers,omitempty"`
+	Templates         []string           `yaml:"templates" json:"templates"`
+	MuteTimeIntervals []MuteTimeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	AckWebhooks       *AckWebhooksConfig `yaml:"ack_webhooks,omitempty" json:"ack_webhooks,omitempty"`
+
+	// AlertRelabelConfigs are applied to the labels of incoming alerts
+	// before they are stored and routed.
+	AlertRelabelConfigs []*relabel.Config `yaml:"alert_relabel_configs,omitempty" json:"alert_relabel_configs,omitempty"`
+	// AnnotationTemplates add annotations rendered from the labels of
+	// incoming alerts after they are relabeled.
+	AnnotationTemplates []*AnnotationTemplate `yaml:"annotation_templates,omitempty" json:"annotation_templates,omitempty"`
+	// IngestionLimits limits the alerts accepted by the alert APIs.
+	IngestionLimits *IngestionLimitsConfig `yaml:"ingestion_limits,omitempty" json:"ingestion_limits,omitempty"`
+	// SilencePolicy restricts the silences accepted by the silence APIs.
+	SilencePolicy *SilencePolicyConfig `yaml:"silence_policy,omitempty" json:"silence_policy,omitempty"`
+	// SilencePresets can be instantiated as silences with a single call.
+	SilencePresets []*SilencePreset `yaml:"silence_presets,omitempty" json:"silence_presets,omitempty"`
+	// Authorization restricts the actions of the clients of the web
+	// server according to their role.
+	Authorization *AuthorizationConfig `yaml:"authorization,omitempty" json:"authorization,omitempty"`
+	// Tenancy partitions the alerts and silences between tenants.
+	Tenancy *TenancyConfig `yaml:"tenancy,omitempty" json:"tenancy,omitempty"`
+	// CORS configures the cross-origin requests allowed by API v2.
+	CORS *CORSConfig `yaml:"cors,omitempty" json:"cors,omitempty"`
+	// OIDC authenticates the users of the web server with an OpenID
+	// Connect provider.
+	OIDC *OIDCConfig `yaml:"oidc,omitempty" json:"oidc,omitempty"`
+	// Tracing exports traces of the handling of alerts.
+	Tracing *TracingConfig `yaml:"tracing,omitempty" json:"tracing,omitempty"`
+	// Watchdogs notify if the alerts expected to be received continuously
+	// stop being received.
+	Watchdogs []*Watchdog `yaml:"watchdogs,omitempty" json:"watchdogs,omitempty"`
+	// Heartbeat pings an OpsGenie heartbeat.
+	Heartbeat *HeartbeatConfig `yaml:"heartbeat,omitempty" json:"heartbeat,omitempty"`
+	// LifecycleWebhook receives the state transitions of every alert.
+	LifecycleWebhook *LifecycleWebhookConfig `yaml:"lifecycle_webhook,omitempty" json:"lifecycle_webhook,omitempty"`
+	// NotificationAnalytics receives an event for every notification
+	// attempt.
+	NotificationAnalytics *NotificationAnalyticsConfig `yaml:"notification_analytics,omitempty" json:"notification_analytics,omitempty"`
+	// KafkaIngesters consume alerts from Kafka topics.
+	KafkaIngesters []*KafkaIngesterConfig `yaml:"kafka_ingesters,omitempty" json:"kafka_ingesters,omitempty"`
+	// SQSIngesters consume alerts from SQS queues.
+	SQSIngesters []*SQSIngesterConfig `yaml:"sqs_ingesters,omitempty" json:"sqs_ingesters,omitempty"`
+	// Scrubbing redacts secrets and personal data from the annotations of
+	// notified alerts.
+	Scrubbing *ScrubbingConfig `yaml:"scrubbing,omitempty" json:"scrubbing,omitempty"`
+	// Credentials are secrets shared by several configurations, which
+	// reference them by name.
+	Credentials map[string]Secret `yaml:"credentials,omitempty" json:"credentials,omitempty"`
+
+	// original is the input from which the config was parsed.
+	original string
+}
+
+func (c Config) String() string {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("<error creating config string: %s>", err)
+	}
+	return string(b)
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Config.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// We want to set c to the defaults and then overwrite it with the input.
+	// To make unmarshal fill the plain data struct rather than calling UnmarshalYAML
+	// again, we have to hide it using a type indirection.
+	type plain Config
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+
+	// If a global block was open but empty the default global config is overwritten.
+	// We have to restore it here.
+	if c.Global == nil {
+		c.Global = &GlobalConfig{}
+		*c.Global = DefaultGlobalConfig()
+	}
+
+	for name, cred := range c.Credentials {
+		if name == "" {
+			return fmt.Errorf("empty credential name")
+		}
+		if strings.HasPrefix(string(cred), credentialRefPrefix) {
+			return fmt.Errorf("credential %q references another credential", name)
+		}
+	}
+	if err := resolveCredentials(reflect.ValueOf(c).Elem(), c.Credentials); err != nil {
+		return err
+	}
+
+	if c.Global.SlackAPIURL != nil && len(c.Global.SlackAPIURLFile) > 0 {
+		return fmt.Errorf("at most one of slack_api_url & slack_api_url_file must be configured")
+	}
+
+	for _, rc := range c.AlertRelabelConfigs {
+		if rc == nil {
+			return fmt.Errorf("empty or null alert relabeling rule")
+		}
+	}
+	for _, at := range c.AnnotationTemplates {
+		if at == nil {
+			return fmt.Errorf("empty or null annotation template")
+		}
+	}
+
+	names := map[string]struct{}{}
+
+	var severityMappings *SeverityMappings
+	if len(c.Global.SeverityMapping) > 0 {
+		severityMappings = &SeverityMappings{
+			Label:    c.Global.SeverityLabel,
+			Mappings: c.Global.SeverityMapping,
+		}
+	}
+
+	for _, rcv := range c.Receivers {
+		if _, ok := names[rcv.Name]; ok {
+			return fmt.Errorf("notification config name %q is not unique", rcv.Name)
+		}
+		for _, wh := range rcv.WebhookConfigs {
+			if wh.HTTPConfig == nil {
+				wh.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		for _, ec := range rcv.EmailConfigs {
+			if ec.Smarthost.String() == "" {
+				if c.Global.SMTPSmarthost.String() == "" {
+					return fmt.Errorf("no global SMTP smarthost set")
+				}
+				ec.Smarthost = c.Global.SMTPSmarthost
+			}
+			if ec.From == "" {
+				if c.Global.SMTPFrom == "" {
+					return fmt.Errorf("no global SMTP from set")
+				}
+				ec.From = c.Global.SMTPFrom
+			}
+			if ec.Hello == "" {
+				ec.Hello = c.Global.SMTPHello
+			}
+			if ec.AuthUsername == "" {
+				ec.AuthUsername = c.Global.SMTPAuthUsername
+			}
+			if ec.AuthPassword == "" {
+				ec.AuthPassword = c.Global.SMTPAuthPassword
+			}
+			if ec.AuthSecret == "" {
+				ec.AuthSecret = c.Global.SMTPAuthSecret
+			}
+			if ec.AuthIdentity == "" {
+				ec.AuthIdentity = c.Global.SMTPAuthIdentity
+			}
+			if ec.RequireTLS == nil {
+				ec.RequireTLS = new(bool)
+				*ec.RequireTLS = c.Global.SMTPRequireTLS
+			}
+		}
+		for _, sc := range rcv.SlackConfigs {
+			if sc.HTTPConfig == nil {
+				sc.HTTPConfig = c.Global.HTTPConfig
+			}
+			if sc.APIURL == nil && len(sc.APIURLFile) == 0 {
+				if c.Global.SlackAPIURL == nil && len(c.Global.SlackAPIURLFile) == 0 {
+					return fmt.Errorf("no global Slack API URL set either inline or in a file")
+				}
+				sc.APIURL = c.Global.SlackAPIURL
+				sc.APIURLFile = c.Global.SlackAPIURLFile
+			}
+		}
+		for _, poc := range rcv.PushoverConfigs {
+			if poc.HTTPConfig == nil {
+				poc.HTTPConfig = c.Global.HTTPConfig
+			}
+			poc.SeverityMappings = severityMappings
+		}
+		for _, pdc := range rcv.PagerdutyConfigs {
+			if pdc.HTTPConfig == nil {
+				pdc.HTTPConfig = c.Global.HTTPConfig
+			}
+			if pdc.URL == nil {
+				if c.Global.PagerdutyURL == nil {
+					return fmt.Errorf("no global PagerDuty URL set")
+				}
+				pdc.URL = c.Global.PagerdutyURL
+			}
+			pdc.SeverityMappings = severityMappings
+		}
+		for _, ogc := range rcv.OpsGenieConfigs {
+			if ogc.HTTPConfig == nil {
+				ogc.HTTPConfig = c.Global.HTTPConfig
+			}
+			if ogc.APIURL == nil {
+				if c.Global.OpsGenieAPIURL == nil {
+					return fmt.Errorf("no global OpsGenie URL set")
+				}
+				ogc.APIURL = c.Global.OpsGenieAPIURL
+			}
+			if !strings.HasSuffix(ogc.APIURL.Path, "/") {
+				ogc.APIURL.Path += "/"
+			}
+			if ogc.APIKey == "" {
+				if c.Global.OpsGenieAPIKey == "" {
+					return fmt.Errorf("no global OpsGenie API Key set")
+				}
+				ogc.APIKey = c.Global.OpsGenieAPIKey
+			}
+			ogc.SeverityMappings = severityMappings
+		}
+		for _, llc := range rcv.LocalLogConfigs {
+			llc.SeverityMappings = severityMappings
+		}
+		for _, wcc := range rcv.WechatConfigs {
+			if wcc.HTTPConfig == nil {
+				wcc.HTTPConfig = c.Global.HTTPConfig
+			}
+
+			if wcc.APIURL == nil {
+				if c.Global.WeChatAPIURL == nil {
+					return fmt.Errorf("no global Wechat URL set")
+				}
+				wcc.APIURL = c.Global.WeChatAPIURL
+			}
+
+			if wcc.APISecret == "" {
+				if c.Global.WeChatAPISecret == "" {
+					return fmt.Errorf("no global Wechat ApiSecret set")
+				}
+				wcc.APISecret = c.Global.WeChatAPISecret
+			}
+
+			if wcc.CorpID == "" {
+				if c.Global.WeChatAPICorpID == "" {
+					return fmt.Errorf("no global Wechat CorpID set")
+				}
+				wcc.CorpID = c.Global.WeChatAPICorpID
+			}
+
+			if !strings.HasSuffix(wcc.APIURL.Path, "/") {
+				wcc.APIURL.Path += "/"
+			}
+		}
+		for _, voc := range rcv.VictorOpsConfigs {
+			if voc.HTTPConfig == nil {
+				voc.HTTPConfig = c.Global.HTTPConfig
+			}
+			if voc.APIURL == nil {
+				if c.Global.VictorOpsAPIURL == nil {
+					return fmt.Errorf("no global VictorOps URL set")
+				}
+				voc.APIURL = c.Global.VictorOpsAPIURL
+			}
+			if !strings.HasSuffix(voc.APIURL.Path, "/") {
+				voc.APIURL.Path += "/"
+			}
+			if voc.APIKey == "" {
+				if c.Global.VictorOpsAPIKey == "" {
+					return fmt.Errorf("no global VictorOps API Key set")
+				}
+				voc.APIKey = c.Global.VictorOpsAPIKey
+			}
+		}
+		for _, sns := range rcv.SNSConfigs {
+			if sns.HTTPConfig == nil {
+				sns.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		for _, ioc := range rcv.IncidentIOConfigs {
+			if ioc.HTTPConfig == nil {
+				ioc.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		for _, spc := range rcv.StatuspageConfigs {
+			if spc.HTTPConfig == nil {
+				spc.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		for _, ghc := range rcv.GithubConfigs {
+			if ghc.HTTPConfig == nil {
+				ghc.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		for _, gfc := range rcv.GrafanaConfigs {
+			if gfc.HTTPConfig == nil {
+				gfc.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		for _, pbc := range rcv.PushbulletConfigs {
+			if pbc.HTTPConfig == nil {
+				pbc.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		for _, gtc := range rcv.GotifyConfigs {
+			if gtc.HTTPConfig == nil {
+				gtc.HTTPConfig = c.Global.HTTPConfig
+			}
+			gtc.SeverityMappings = severityMappings
+		}
+		for _, nfc := range rcv.NtfyConfigs {
+			if nfc.HTTPConfig == nil {
+				nfc.HTTPConfig = c.Global.HTTPConfig
+			}
+			nfc.SeverityMappings = severityMappings
+		}
+		for _, shc := range rcv.SplunkHECConfigs {
+			if shc.HTTPConfig == nil {
+				shc.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		for _, esc := range rcv.ElasticsearchConfigs {
+			if esc.HTTPConfig == nil {
+				esc.HTTPConfig = c.Global.HTTPConfig
+			}
+		}
+		if rcv.HTTPTransport == nil {
+			rcv.HTTPTransport = c.Global.HTTPTransport
+		}
+		names[rcv.Name] = struct{}{}
+	}
+
+	// The root route must not have any matchers as it is the fallback node
+	// for all alerts.
+	if c.Route == nil {
+		return fmt.Errorf("no routes provided")
+	}
+	if len(c.Route.Receiver) == 0 {
+		return fmt.Errorf("root route must specify a default receiver")
+	}
+	if len(c.Route.Match) > 0 || len(c.Route.MatchRE) > 0 || c.Route.Expression != nil {
+		return fmt.Errorf("root route must not have any matchers")
+	}
+	if len(c.Route.MuteTimeIntervals) > 0 {
+		return fmt.Errorf("root route must not have any mute time intervals")
+	}
+	if u := c.Route.Unrouted; u != nil && u.FallbackReceiver != "" {
+		if _, ok := names[u.FallbackReceiver]; !ok {
+			return fmt.Errorf("undefined fallback receiver %q used in root route", u.FallbackReceiver)
+		}
+	}
+
+	// Validate that all receivers used in the routing tree are defined.
+	if err := checkReceiver(c.Route, names); err != nil {
+		return err
+	}
+
+	setEnrichmentHTTPConfig(c.Route, c.Global.HTTPConfig)
+	if err := setOnCallDefaults(c.Route, c.Global); err != nil {
+		return err
+	}
+	if c.OIDC != nil && c.OIDC.HTTPConfig == nil {
+		c.OIDC.HTTPConfig = c.Global.HTTPConfig
+	}
+	if c.Tracing != nil && c.Tracing.HTTPConfig == nil {
+		c.Tracing.HTTPConfig = c.Global.HTTPConfig
+	}
+	if hb := c.Heartbeat; hb != nil {
+		if hb.HTTPConfig == nil {
+			hb.HTTPConfig = c.Global.HTTPConfig
+		}
+		if hb.APIURL == nil {
+			if c.Global.OpsGenieAPIURL == nil {
+				return fmt.Errorf("no global OpsGenie URL set")
+			}
+			hb.APIURL = c.Global.OpsGenieAPIURL
+		}
+		if !strings.HasSuffix(hb.APIURL.Path, "/") {
+			hb.APIURL.Path += "/"
+		}
+		if hb.APIKey == "" {
+			if c.Global.OpsGenieAPIKey == "" {
+				return fmt.Errorf("no global OpsGenie API Key set")
+			}
+			hb.APIKey = c.Global.OpsGenieAPIKey
+		}
+	}
+	if c.LifecycleWebhook != nil && c.LifecycleWebhook.HTTPConfig == nil {
+		c.LifecycleWebhook.HTTPConfig = c.Global.HTTPConfig
+	}
+	if c.NotificationAnalytics != nil && c.NotificationAnalytics.HTTPConfig == nil {
+		c.NotificationAnalytics.HTTPConfig = c.Global.HTTPConfig
+	}
+	for _, k := range c.KafkaIngesters {
+		if k == nil {
+			return fmt.Errorf("empty or null Kafka ingester")
+		}
+		if k.HTTPConfig == nil {
+			k.HTTPConfig = c.Global.HTTPConfig
+		}
+	}
+	for _, q := range c.SQSIngesters {
+		if q == nil {
+			return fmt.Errorf("empty or null SQS ingester")
+		}
+		if q.HTTPConfig == nil {
+			q.HTTPConfig = c.Global.HTTPConfig
+		}
+	}
+
+	// The alerts of different tenants are never grouped together and never
+	// inhibit each other.
+	if c.Tenancy != nil {
+		addGroupBy(c.Route, c.Tenancy.Label, true)
+		for _, ir := range c.InhibitRules {
+			if !containsLabelName(ir.Equal, c.Tenancy.Label) {
+				ir.Equal = append(ir.Equal, c.Tenancy.Label)
+			}
+		}
+	}
+
+	presetNames := make(map[string]struct{})
+	for _, p := range c.SilencePresets {
+		if _, ok := presetNames[p.Name]; ok {
+			return fmt.Errorf("silence preset %q is not unique", p.Name)
+		}
+		presetNames[p.Name] = struct{}{}
+	}
+
+	watchdogNames := make(map[string]struct{})
+	for _, w := range c.Watchdogs {
+		if w == nil {
+			return fmt.Errorf("empty or null watchdog")
+		}
+		if _, ok := watchdogNames[w.Name]; ok {
+			return fmt.Errorf("watchdog %q is not unique", w.Name)
+		}
+		watchdogNames[w.Name] = struct{}{}
+		if _, ok := names[w.Receiver]; !ok {
+			return fmt.Errorf("undefined receiver %q used in watchdog %q", w.Receiver, w.Name)
+		}
+	}
+
+	tiNames := make(map[string]struct{})
+	for _, mt := range c.MuteTimeIntervals {
+		if _, ok := tiNames[mt.Name]; ok {
+			return fmt.Errorf("mute time interval %q is not unique", mt.Name)
+		}
+		tiNames[mt.Name] = struct{}{}
+	}
+
+	severities := make(map[string]struct{}, len(c.Global.SeverityMapping))
+	for _, m := range c.Global.SeverityMapping {
+		severities[m.Severity] = struct{}{}
+	}
+	for _, sr := range c.SuppressionRules {
+		for _, ti := range sr.TimeIntervals {
+			if _, ok := tiNames[ti]; !ok {
+				return fmt.Errorf("undefined time interval %q used in suppression rule", ti)
+			}
+		}
+		if _, ok := severities[sr.MaxSeverity]; sr.MaxSeverity != "" && !ok {
+			return fmt.Errorf("max_severity %q of suppression rule is not in the global severity_mapping", sr.MaxSeverity)
+		}
+	}
+	for _, rcv := range c.Receivers {
+		if rcv.QuietHours == nil {
+			continue
+		}
+		for _, ti := range rcv.QuietHours.TimeIntervals {
+			if _, ok := tiNames[ti]; !ok {
+				return fmt.Errorf("undefined time interval %q used in quiet_hours of receiver %q", ti, rcv.Name)
+			}
+		}
+		if _, ok := severities[rcv.QuietHours.MaxSeverity]; rcv.QuietHours.MaxSeverity != "" && !ok {
+			return fmt.Errorf("max_severity %q of quiet_hours of receiver %q is not in the global severity_mapping", rcv.QuietHours.MaxSeverity, rcv.Name)
+		}
+	}
+
+	return checkTimeInterval(c.Route, tiNames)
+}
+
+// checkReceiver returns an error if a node in the routing tree
+// references a receiver not in the given map.
+func checkReceiver(r *Route, receivers map[string]struct{}) error {
+	for _, sr := range r.Routes {
 		if err := checkReceiver(sr, receivers); err != nil {
 			return err
 		}
 	}
+	for _, e := range r.Escalation {
+		if _, ok := receivers[e.Receiver]; !ok {
+			return fmt.Errorf("undefined receiver %q used in route escalation", e.Receiver)
+		}
+	}
+	for _, sr := range r.Routes {
+		if sr.Unrouted != nil {
+			return fmt.Errorf("unrouted can only be set on the root route")
+		}
+	}
 	if r.Receiver == "" {
 		return nil
 	}
@@ -505,6 +1735,76 @@ func checkReceiver(r *Route, receivers map[string]struct{}) error {
 	return nil
 }
 
+// setEnrichmentHTTPConfig sets the HTTP client configuration of the
+// enrichment configurations in the routing tree that do not define one.
+func setEnrichmentHTTPConfig(r *Route, httpConfig *commoncfg.HTTPClientConfig) {
+	for _, sr := range r.Routes {
+		setEnrichmentHTTPConfig(sr, httpConfig)
+	}
+	if r.Enrichment != nil && r.Enrichment.HTTPConfig == nil {
+		r.Enrichment.HTTPConfig = httpConfig
+	}
+}
+
+// setOnCallDefaults sets the HTTP client configuration of the on-call
+// schedules in the routing tree that do not define one, and the global
+// OpsGenie settings of the OpsGenie schedules.
+func setOnCallDefaults(r *Route, global *GlobalConfig) error {
+	for _, sr := range r.Routes {
+		if err := setOnCallDefaults(sr, global); err != nil {
+			return err
+		}
+	}
+	if r.Owner == nil || r.Owner.OnCall == nil {
+		return nil
+	}
+	oc := r.Owner.OnCall
+	if oc.HTTPConfig == nil {
+		oc.HTTPConfig = global.HTTPConfig
+	}
+	if oc.Provider != "opsgenie" {
+		return nil
+	}
+	if oc.APIURL == nil {
+		if global.OpsGenieAPIURL == nil {
+			return fmt.Errorf("no global OpsGenie URL set")
+		}
+		oc.APIURL = global.OpsGenieAPIURL
+	}
+	if !strings.HasSuffix(oc.APIURL.Path, "/") {
+		oc.APIURL.Path += "/"
+	}
+	if oc.APIKey == "" {
+		if global.OpsGenieAPIKey == "" {
+			return fmt.Errorf("no global OpsGenie API Key set")
+		}
+		oc.APIKey = global.OpsGenieAPIKey
+	}
+	return nil
+}
+
+// addGroupBy adds the label to the grouping of the root route and of the
+// routes in the tree overriding the grouping of their parent.
+func addGroupBy(r *Route, ln model.LabelName, root bool) {
+	for _, sr := range r.Routes {
+		addGroupBy(sr, ln, false)
+	}
+	if r.GroupByAll || (!root && r.GroupBy == nil) || containsLabelName(r.GroupBy, ln) {
+		return
+	}
+	r.GroupBy = append(r.GroupBy, ln)
+	r.GroupByStr = append(r.GroupByStr, string(ln))
+}
+
+func containsLabelName(lns []model.LabelName, ln model.LabelName) bool {
+	for _, l := range lns {
+		if l == ln {
+			return true
+		}
+	}
+	return false
+}
+
 func checkTimeInterval(r *Route, timeIntervals map[string]struct{}) error {
 	for _, sr := range r.Routes {
 		if err := checkTimeInterval(sr, timeIntervals); err != nil {
@@ -524,10 +1824,17 @@ func checkTimeInterval(r *Route, timeIntervals map[string]struct{}) error {
 
 // DefaultGlobalConfig returns GlobalConfig with default values.
 func DefaultGlobalConfig() GlobalConfig {
-	var defaultHTTPConfig = commoncfg.DefaultHTTPClientConfig
+	var (
+		defaultHTTPConfig    = commoncfg.DefaultHTTPClientConfig
+		defaultHTTPTransport = DefaultHTTPTransportConfig
+	)
 	return GlobalConfig{
 		ResolveTimeout: model.Duration(5 * time.Minute),
 		HTTPConfig:     &defaultHTTPConfig,
+		HTTPTransport:  &defaultHTTPTransport,
+
+		SeverityLabel:   "severity",
+		LabelValidation: LabelValidationLegacy,
 
 		SMTPHello:       "localhost",
 		SMTPRequireTLS:  true,
@@ -560,12 +1867,26 @@ func parseURL(s string) (*URL, error) {
 	return &URL{u}, nil
 }
 
-// HostPort represents a "host:port" network address.
+// HostPort represents a "host:port" network address, or the name of a DNS
+// SRV record as "srv+<name>" resolved when connecting.
 type HostPort struct {
 	Host string
 	Port string
 }
 
+// IsSRV returns whether the address is the name of an SRV record.
+func (hp HostPort) IsSRV() bool {
+	return srv.IsSRV(hp.Host)
+}
+
+func (hp *HostPort) setSRV(s string) error {
+	if strings.Contains(s, ":") {
+		return errors.Errorf("address %q: SRV record cannot have a port", s)
+	}
+	hp.Host, hp.Port = s, ""
+	return nil
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface for HostPort.
 func (hp *HostPort) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var (
@@ -578,6 +1899,9 @@ func (hp *HostPort) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if s == "" {
 		return nil
 	}
+	if srv.IsSRV(s) {
+		return hp.setSRV(s)
+	}
 	hp.Host, hp.Port, err = net.SplitHostPort(s)
 	if err != nil {
 		return err
@@ -600,6 +1924,9 @@ func (hp *HostPort) UnmarshalJSON(data []byte) error {
 	if s == "" {
 		return nil
 	}
+	if srv.IsSRV(s) {
+		return hp.setSRV(s)
+	}
 	hp.Host, hp.Port, err = net.SplitHostPort(s)
 	if err != nil {
 		return err
@@ -615,73 +1942,454 @@ func (hp HostPort) MarshalYAML() (interface{}, error) {
 	return hp.String(), nil
 }
 
-// MarshalJSON implements the json.Marshaler interface for HostPort.
-func (hp HostPort) MarshalJSON() ([]byte, error) {
-	return json.Marshal(hp.String())
+// MarshalJSON implements the json.Marshaler interface for HostPort.
+func (hp HostPort) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hp.String())
+}
+
+func (hp HostPort) String() string {
+	if hp.Host == "" && hp.Port == "" {
+		return ""
+	}
+	if hp.IsSRV() {
+		return hp.Host
+	}
+	return fmt.Sprintf("%s:%s", hp.Host, hp.Port)
+}
+
+// GlobalConfig defines configuration parameters that are valid globally
+// unless overwritten.
+type GlobalConfig struct {
+	// ResolveTimeout is the time after which an alert is declared resolved
+	// if it has not been updated.
+	ResolveTimeout model.Duration `yaml:"resolve_timeout" json:"resolve_timeout"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	// HTTPTransport tunes the connections of the notifiers, unless
+	// overwritten by receivers.
+	HTTPTransport *HTTPTransportConfig `yaml:"http_transport,omitempty" json:"http_transport,omitempty"`
+	// TLSPolicy restricts the TLS connections of the web server and of the
+	// notifiers.
+	TLSPolicy *TLSPolicy `yaml:"tls_policy,omitempty" json:"tls_policy,omitempty"`
+
+	SMTPFrom         string     `yaml:"smtp_from,omitempty" json:"smtp_from,omitempty"`
+	SMTPHello        string     `yaml:"smtp_hello,omitempty" json:"smtp_hello,omitempty"`
+	SMTPSmarthost    HostPort   `yaml:"smtp_smarthost,omitempty" json:"smtp_smarthost,omitempty"`
+	SMTPAuthUsername string     `yaml:"smtp_auth_username,omitempty" json:"smtp_auth_username,omitempty"`
+	SMTPAuthPassword Secret     `yaml:"smtp_auth_password,omitempty" json:"smtp_auth_password,omitempty"`
+	SMTPAuthSecret   Secret     `yaml:"smtp_auth_secret,omitempty" json:"smtp_auth_secret,omitempty"`
+	SMTPAuthIdentity string     `yaml:"smtp_auth_identity,omitempty" json:"smtp_auth_identity,omitempty"`
+	SMTPRequireTLS   bool       `yaml:"smtp_require_tls" json:"smtp_require_tls,omitempty"`
+	SlackAPIURL      *SecretURL `yaml:"slack_api_url,omitempty" json:"slack_api_url,omitempty"`
+	SlackAPIURLFile  string     `yaml:"slack_api_url_file,omitempty" json:"slack_api_url_file,omitempty"`
+	PagerdutyURL     *URL       `yaml:"pagerduty_url,omitempty" json:"pagerduty_url,omitempty"`
+	OpsGenieAPIURL   *URL       `yaml:"opsgenie_api_url,omitempty" json:"opsgenie_api_url,omitempty"`
+	OpsGenieAPIKey   Secret     `yaml:"opsgenie_api_key,omitempty" json:"opsgenie_api_key,omitempty"`
+	WeChatAPIURL     *URL       `yaml:"wechat_api_url,omitempty" json:"wechat_api_url,omitempty"`
+	WeChatAPISecret  Secret     `yaml:"wechat_api_secret,omitempty" json:"wechat_api_secret,omitempty"`
+	WeChatAPICorpID  string     `yaml:"wechat_api_corp_id,omitempty" json:"wechat_api_corp_id,omitempty"`
+	VictorOpsAPIURL  *URL       `yaml:"victorops_api_url,omitempty" json:"victorops_api_url,omitempty"`
+	VictorOpsAPIKey  Secret     `yaml:"victorops_api_key,omitempty" json:"victorops_api_key,omitempty"`
+
+	// The label holding the severity of alerts and the mapping of its values
+	// to the priorities of on-call providers, ordered from the most to the
+	// least severe.
+	SeverityLabel   model.LabelName    `yaml:"severity_label,omitempty" json:"severity_label,omitempty"`
+	SeverityMapping []*SeverityMapping `yaml:"severity_mapping,omitempty" json:"severity_mapping,omitempty"`
+
+	// LabelValidation selects the label names accepted in posted alerts.
+	LabelValidation LabelValidation `yaml:"label_validation,omitempty" json:"label_validation,omitempty"`
+
+	// NotificationMiddlewares are the names of the middlewares compiled in
+	// the Alertmanager applied to all notifications, the first one being
+	// called first.
+	NotificationMiddlewares []string `yaml:"notification_middlewares,omitempty" json:"notification_middlewares,omitempty"`
+}
+
+// LabelValidation is a scheme validating the label names of alerts.
+type LabelValidation string
+
+// The schemes validating label names.
+const (
+	// LabelValidationLegacy accepts the label names matching
+	// [a-zA-Z_][a-zA-Z0-9_]*.
+	LabelValidationLegacy LabelValidation = "legacy"
+	// LabelValidationUTF8 accepts any non-empty UTF-8 label name.
+	LabelValidationUTF8 LabelValidation = "utf8"
+)
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for LabelValidation.
+func (v *LabelValidation) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	switch LabelValidation(s) {
+	case LabelValidationLegacy, LabelValidationUTF8:
+	default:
+		return fmt.Errorf("unknown label validation %q, must be legacy or utf8", s)
+	}
+	*v = LabelValidation(s)
+	return nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for GlobalConfig.
+func (c *GlobalConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultGlobalConfig()
+	type plain GlobalConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+
+	if !c.SeverityLabel.IsValid() {
+		return fmt.Errorf("invalid severity_label %q", c.SeverityLabel)
+	}
+	severities := map[string]struct{}{}
+	for _, m := range c.SeverityMapping {
+		if m == nil {
+			return fmt.Errorf("missing severity mapping")
+		}
+		if _, ok := severities[m.Severity]; ok {
+			return fmt.Errorf("severity %q is mapped more than once", m.Severity)
+		}
+		severities[m.Severity] = struct{}{}
+	}
+	middlewares := map[string]struct{}{}
+	for _, name := range c.NotificationMiddlewares {
+		if name == "" {
+			return fmt.Errorf("empty notification middleware name")
+		}
+		if _, ok := middlewares[name]; ok {
+			return fmt.Errorf("notification middleware %q is listed more than once", name)
+		}
+		middlewares[name] = struct{}{}
+	}
+	return nil
+}
+
+// SeverityMapping maps a value of the severity label to the priorities of
+// on-call providers.
+type SeverityMapping struct {
+	Severity  string `yaml:"severity" json:"severity"`
+	PagerDuty string `yaml:"pagerduty,omitempty" json:"pagerduty,omitempty"`
+	OpsGenie  string `yaml:"opsgenie,omitempty" json:"opsgenie,omitempty"`
+	Pushover  string `yaml:"pushover,omitempty" json:"pushover,omitempty"`
+	LocalLog  string `yaml:"local_log,omitempty" json:"local_log,omitempty"`
+	Gotify    string `yaml:"gotify,omitempty" json:"gotify,omitempty"`
+	Ntfy      string `yaml:"ntfy,omitempty" json:"ntfy,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for SeverityMapping.
+func (m *SeverityMapping) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain SeverityMapping
+	if err := unmarshal((*plain)(m)); err != nil {
+		return err
+	}
+
+	if m.Severity == "" {
+		return fmt.Errorf("missing severity in severity mapping")
+	}
+	switch m.PagerDuty {
+	case "", "critical", "error", "warning", "info":
+	default:
+		return fmt.Errorf("invalid PagerDuty severity %q, must be one of critical, error, warning or info", m.PagerDuty)
+	}
+	switch m.OpsGenie {
+	case "", "P1", "P2", "P3", "P4", "P5":
+	default:
+		return fmt.Errorf("invalid OpsGenie priority %q, must be one of P1 to P5", m.OpsGenie)
+	}
+	switch m.Pushover {
+	case "", "-2", "-1", "0", "1", "2":
+	default:
+		return fmt.Errorf("invalid Pushover priority %q, must be between -2 and 2", m.Pushover)
+	}
+	if m.LocalLog != "" && !validLocalLogLevel(m.LocalLog) {
+		return fmt.Errorf("invalid local log level %q, must be one of critical, error, warning or info", m.LocalLog)
+	}
+	if m.Gotify != "" {
+		if p, err := strconv.Atoi(m.Gotify); err != nil || p < 0 || p > 10 {
+			return fmt.Errorf("invalid Gotify priority %q, must be between 0 and 10", m.Gotify)
+		}
+	}
+	switch m.Ntfy {
+	case "", "min", "low", "default", "high", "urgent", "1", "2", "3", "4", "5":
+	default:
+		return fmt.Errorf("invalid ntfy priority %q, must be one of min, low, default, high, urgent or between 1 and 5", m.Ntfy)
+	}
+	return nil
+}
+
+// SeverityMappings holds the severity label and its mappings to provider
+// priorities. It is derived from the global configuration for the notifiers
+// supporting it.
+type SeverityMappings struct {
+	Label    model.LabelName
+	Mappings []*SeverityMapping
+}
+
+// A Route is a node that contains definitions of how to handle alerts.
+type Route struct {
+	Receiver string `yaml:"receiver,omitempty" json:"receiver,omitempty"`
+
+	GroupByStr []string          `yaml:"group_by,omitempty" json:"group_by,omitempty"`
+	GroupBy    []model.LabelName `yaml:"-" json:"-"`
+	GroupByAll bool              `yaml:"-" json:"-"`
+	// Deprecated. Remove before v1.0 release.
+	Match map[string]string `yaml:"match,omitempty" json:"match,omitempty"`
+	// Deprecated. Remove before v1.0 release.
+	MatchRE           MatchRegexps `yaml:"match_re,omitempty" json:"match_re,omitempty"`
+	Matchers          Matchers     `yaml:"matchers,omitempty" json:"matchers,omitempty"`
+	MuteTimeIntervals []string     `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	Continue          bool         `yaml:"continue" json:"continue,omitempty"`
+	Routes            []*Route     `yaml:"routes,omitempty" json:"routes,omitempty"`
+
+	// Expression matches alerts on their labels and annotations, in
+	// addition to the matchers.
+	Expression *Expression `yaml:"expression,omitempty" json:"expression,omitempty"`
+
+	GroupWait      *model.Duration `yaml:"group_wait,omitempty" json:"group_wait,omitempty"`
+	GroupInterval  *model.Duration `yaml:"group_interval,omitempty" json:"group_interval,omitempty"`
+	RepeatInterval *model.Duration `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty"`
+	ResolveTimeout *model.Duration `yaml:"resolve_timeout,omitempty" json:"resolve_timeout,omitempty"`
+
+	// GroupWaitJitter and GroupIntervalJitter are the maximum random
+	// durations added to the group wait and interval.
+	GroupWaitJitter     *model.Duration `yaml:"group_wait_jitter,omitempty" json:"group_wait_jitter,omitempty"`
+	GroupIntervalJitter *model.Duration `yaml:"group_interval_jitter,omitempty" json:"group_interval_jitter,omitempty"`
+
+	Escalation []*EscalationStep `yaml:"escalation,omitempty" json:"escalation,omitempty"`
+
+	LabelRelabelConfigs      []*relabel.Config `yaml:"label_relabel_configs,omitempty" json:"label_relabel_configs,omitempty"`
+	AnnotationRelabelConfigs []*relabel.Config `yaml:"annotation_relabel_configs,omitempty" json:"annotation_relabel_configs,omitempty"`
+
+	Enrichment    *EnrichmentConfig    `yaml:"enrichment,omitempty" json:"enrichment,omitempty"`
+	Owner         *OwnerConfig         `yaml:"owner,omitempty" json:"owner,omitempty"`
+	FlapDetection *FlapDetectionConfig `yaml:"flap_detection,omitempty" json:"flap_detection,omitempty"`
+	Digest        *DigestConfig        `yaml:"digest,omitempty" json:"digest,omitempty"`
+
+	// Unrouted can only be set on the root route.
+	Unrouted *UnroutedConfig `yaml:"unrouted,omitempty" json:"unrouted,omitempty"`
+}
+
+// UnroutedAction is what happens to the alerts matching none of the child
+// routes of the root route.
+type UnroutedAction string
+
+// The actions applying to unrouted alerts.
+const (
+	// UnroutedRoot routes the alerts to the root route.
+	UnroutedRoot UnroutedAction = "root"
+	// UnroutedDrop drops the alerts.
+	UnroutedDrop UnroutedAction = "drop"
+	// UnroutedFallback routes the alerts to the fallback receiver.
+	UnroutedFallback UnroutedAction = "fallback"
+	// UnroutedReject rejects the alerts when they are received.
+	UnroutedReject UnroutedAction = "reject"
+)
+
+// UnroutedConfig configures what happens to the alerts matching none of the
+// child routes of the root route.
+type UnroutedConfig struct {
+	Action           UnroutedAction `yaml:"action" json:"action"`
+	FallbackReceiver string         `yaml:"fallback_receiver,omitempty" json:"fallback_receiver,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for UnroutedConfig.
+func (c *UnroutedConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain UnroutedConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Action == "" {
+		c.Action = UnroutedRoot
+	}
+	switch c.Action {
+	case UnroutedRoot, UnroutedDrop, UnroutedFallback, UnroutedReject:
+	default:
+		return fmt.Errorf("unknown unrouted action %q, must be root, drop, fallback or reject", c.Action)
+	}
+	if (c.Action == UnroutedFallback) != (c.FallbackReceiver != "") {
+		return fmt.Errorf("fallback_receiver must be set if and only if the unrouted action is fallback")
+	}
+	return nil
+}
+
+// DigestConfig configures a route to notify its alerts in periodic digests
+// rather than as soon as they fire.
+type DigestConfig struct {
+	// Interval sends a digest every interval, aligned on the Unix epoch.
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Schedule sends a digest at the activation times of a cron expression.
+	Schedule string `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+
+	// Cron is the parsed Schedule.
+	Cron *cron.Schedule `yaml:"-" json:"-"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for DigestConfig.
+func (c *DigestConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain DigestConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if (c.Interval != 0) == (c.Schedule != "") {
+		return fmt.Errorf("exactly one of interval and schedule must be set in digest config")
+	}
+	c.Cron = nil
+	if c.Schedule != "" {
+		sched, err := cron.Parse(c.Schedule)
+		if err != nil {
+			return fmt.Errorf("invalid digest schedule %q: %s", c.Schedule, err)
+		}
+		if sched.Next(time.Now()).IsZero() {
+			return fmt.Errorf("digest schedule %q never activates", c.Schedule)
+		}
+		c.Cron = sched
+	}
+	return nil
+}
+
+// FlapDetectionConfig configures the detection of alerts which change between
+// firing and resolved more than Threshold times within Window.
+type FlapDetectionConfig struct {
+	Threshold int            `yaml:"threshold" json:"threshold"`
+	Window    model.Duration `yaml:"window" json:"window"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for FlapDetectionConfig.
+func (c *FlapDetectionConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain FlapDetectionConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Threshold <= 0 {
+		return fmt.Errorf("flap detection threshold must be greater than zero")
+	}
+	if c.Window <= 0 {
+		return fmt.Errorf("flap detection window must be greater than zero")
+	}
+	return nil
+}
+
+// DefaultEnrichmentConfig defines default values for enrichment configurations.
+var DefaultEnrichmentConfig = EnrichmentConfig{
+	Timeout:  model.Duration(2 * time.Second),
+	CacheTTL: model.Duration(5 * time.Minute),
+}
+
+// EnrichmentConfig configures an HTTP endpoint that is called with the labels
+// of notified alerts and returns annotations to add to them.
+type EnrichmentConfig struct {
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	URL *URL `yaml:"url" json:"url"`
+	// Timeout bounds the enrichment of all alerts of a notification.
+	Timeout model.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// CacheTTL is how long the annotations returned for a label set are reused.
+	CacheTTL model.Duration `yaml:"cache_ttl,omitempty" json:"cache_ttl,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for EnrichmentConfig.
+func (c *EnrichmentConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultEnrichmentConfig
+	type plain EnrichmentConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.URL == nil {
+		return fmt.Errorf("missing URL in enrichment config")
+	}
+	if c.URL.Scheme != "https" && c.URL.Scheme != "http" {
+		return fmt.Errorf("scheme required for enrichment url")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("enrichment timeout must be greater than zero")
+	}
+	return nil
+}
+
+// OnCallProviders are the services resolving the on-call of schedules.
+var OnCallProviders = []string{"pagerduty", "opsgenie"}
+
+// DefaultOnCallScheduleConfig defines default values for on-call schedule
+// configurations.
+var DefaultOnCallScheduleConfig = OnCallScheduleConfig{
+	Timeout:  model.Duration(2 * time.Second),
+	CacheTTL: model.Duration(time.Minute),
+}
+
+// OwnerConfig describes who owns the alerts of a route.
+type OwnerConfig struct {
+	// Name is the team or person owning the alerts.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// OnCall is the schedule whose current on-call is resolved when
+	// notifying.
+	OnCall *OnCallScheduleConfig `yaml:"on_call,omitempty" json:"on_call,omitempty"`
 }
 
-func (hp HostPort) String() string {
-	if hp.Host == "" && hp.Port == "" {
-		return ""
+// UnmarshalYAML implements the yaml.Unmarshaler interface for OwnerConfig.
+func (c *OwnerConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain OwnerConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
 	}
-	return fmt.Sprintf("%s:%s", hp.Host, hp.Port)
+	if c.Name == "" && c.OnCall == nil {
+		return fmt.Errorf("owner requires a name or an on-call schedule")
+	}
+	return nil
 }
 
-// GlobalConfig defines configuration parameters that are valid globally
-// unless overwritten.
-type GlobalConfig struct {
-	// ResolveTimeout is the time after which an alert is declared resolved
-	// if it has not been updated.
-	ResolveTimeout model.Duration `yaml:"resolve_timeout" json:"resolve_timeout"`
-
+// OnCallScheduleConfig configures the on-call schedule of a PagerDuty or
+// OpsGenie account.
+type OnCallScheduleConfig struct {
 	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
 
-	SMTPFrom         string     `yaml:"smtp_from,omitempty" json:"smtp_from,omitempty"`
-	SMTPHello        string     `yaml:"smtp_hello,omitempty" json:"smtp_hello,omitempty"`
-	SMTPSmarthost    HostPort   `yaml:"smtp_smarthost,omitempty" json:"smtp_smarthost,omitempty"`
-	SMTPAuthUsername string     `yaml:"smtp_auth_username,omitempty" json:"smtp_auth_username,omitempty"`
-	SMTPAuthPassword Secret     `yaml:"smtp_auth_password,omitempty" json:"smtp_auth_password,omitempty"`
-	SMTPAuthSecret   Secret     `yaml:"smtp_auth_secret,omitempty" json:"smtp_auth_secret,omitempty"`
-	SMTPAuthIdentity string     `yaml:"smtp_auth_identity,omitempty" json:"smtp_auth_identity,omitempty"`
-	SMTPRequireTLS   bool       `yaml:"smtp_require_tls" json:"smtp_require_tls,omitempty"`
-	SlackAPIURL      *SecretURL `yaml:"slack_api_url,omitempty" json:"slack_api_url,omitempty"`
-	SlackAPIURLFile  string     `yaml:"slack_api_url_file,omitempty" json:"slack_api_url_file,omitempty"`
-	PagerdutyURL     *URL       `yaml:"pagerduty_url,omitempty" json:"pagerduty_url,omitempty"`
-	OpsGenieAPIURL   *URL       `yaml:"opsgenie_api_url,omitempty" json:"opsgenie_api_url,omitempty"`
-	OpsGenieAPIKey   Secret     `yaml:"opsgenie_api_key,omitempty" json:"opsgenie_api_key,omitempty"`
-	WeChatAPIURL     *URL       `yaml:"wechat_api_url,omitempty" json:"wechat_api_url,omitempty"`
-	WeChatAPISecret  Secret     `yaml:"wechat_api_secret,omitempty" json:"wechat_api_secret,omitempty"`
-	WeChatAPICorpID  string     `yaml:"wechat_api_corp_id,omitempty" json:"wechat_api_corp_id,omitempty"`
-	VictorOpsAPIURL  *URL       `yaml:"victorops_api_url,omitempty" json:"victorops_api_url,omitempty"`
-	VictorOpsAPIKey  Secret     `yaml:"victorops_api_key,omitempty" json:"victorops_api_key,omitempty"`
+	// Provider is one of OnCallProviders.
+	Provider   string `yaml:"provider" json:"provider"`
+	ScheduleID string `yaml:"schedule_id" json:"schedule_id"`
+	// APIKey and APIURL default to the global OpsGenie settings for
+	// OpsGenie schedules.
+	APIKey Secret `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	APIURL *URL   `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	// Timeout bounds the resolution of the on-call of a notification.
+	Timeout model.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// CacheTTL is how long the resolved on-call is reused.
+	CacheTTL model.Duration `yaml:"cache_ttl,omitempty" json:"cache_ttl,omitempty"`
 }
 
-// UnmarshalYAML implements the yaml.Unmarshaler interface for GlobalConfig.
-func (c *GlobalConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	*c = DefaultGlobalConfig()
-	type plain GlobalConfig
-	return unmarshal((*plain)(c))
+// UnmarshalYAML implements the yaml.Unmarshaler interface for OnCallScheduleConfig.
+func (c *OnCallScheduleConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultOnCallScheduleConfig
+	type plain OnCallScheduleConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if !containsString(OnCallProviders, c.Provider) {
+		return fmt.Errorf("unknown on-call provider %q, must be one of %s", c.Provider, strings.Join(OnCallProviders, ", "))
+	}
+	if c.ScheduleID == "" {
+		return fmt.Errorf("missing schedule_id in on-call schedule config")
+	}
+	if c.Provider == "pagerduty" {
+		if c.APIKey == "" {
+			return fmt.Errorf("missing api_key in PagerDuty on-call schedule config")
+		}
+		if c.APIURL == nil {
+			c.APIURL = mustParseURL("https://api.pagerduty.com/")
+		}
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("on-call schedule timeout must be greater than zero")
+	}
+	return nil
 }
 
-// A Route is a node that contains definitions of how to handle alerts.
-type Route struct {
-	Receiver string `yaml:"receiver,omitempty" json:"receiver,omitempty"`
-
-	GroupByStr []string          `yaml:"group_by,omitempty" json:"group_by,omitempty"`
-	GroupBy    []model.LabelName `yaml:"-" json:"-"`
-	GroupByAll bool              `yaml:"-" json:"-"`
-	// Deprecated. Remove before v1.0 release.
-	Match map[string]string `yaml:"match,omitempty" json:"match,omitempty"`
-	// Deprecated. Remove before v1.0 release.
-	MatchRE           MatchRegexps `yaml:"match_re,omitempty" json:"match_re,omitempty"`
-	Matchers          Matchers     `yaml:"matchers,omitempty" json:"matchers,omitempty"`
-	MuteTimeIntervals []string     `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
-	Continue          bool         `yaml:"continue" json:"continue,omitempty"`
-	Routes            []*Route     `yaml:"routes,omitempty" json:"routes,omitempty"`
-
-	GroupWait      *model.Duration `yaml:"group_wait,omitempty" json:"group_wait,omitempty"`
-	GroupInterval  *model.Duration `yaml:"group_interval,omitempty" json:"group_interval,omitempty"`
-	RepeatInterval *model.Duration `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty"`
+// EscalationStep defines a receiver that is notified if an alert group of a
+// route is still firing the given duration after its first notification.
+type EscalationStep struct {
+	After    model.Duration `yaml:"after" json:"after"`
+	Receiver string         `yaml:"receiver" json:"receiver"`
 }
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface for Route.
@@ -728,6 +2436,34 @@ func (r *Route) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if r.RepeatInterval != nil && time.Duration(*r.RepeatInterval) == time.Duration(0) {
 		return fmt.Errorf("repeat_interval cannot be zero")
 	}
+	if r.ResolveTimeout != nil && time.Duration(*r.ResolveTimeout) == time.Duration(0) {
+		return fmt.Errorf("resolve_timeout cannot be zero")
+	}
+
+	var prev model.Duration
+	for _, e := range r.Escalation {
+		if e == nil {
+			return fmt.Errorf("missing escalation step")
+		}
+		if e.Receiver == "" {
+			return fmt.Errorf("escalation step must have a receiver")
+		}
+		if e.After <= prev {
+			return fmt.Errorf("escalation steps must have increasing, non-zero after durations")
+		}
+		prev = e.After
+	}
+
+	for _, rc := range r.LabelRelabelConfigs {
+		if rc == nil {
+			return fmt.Errorf("empty or null label relabeling rule")
+		}
+	}
+	for _, rc := range r.AnnotationRelabelConfigs {
+		if rc == nil {
+			return fmt.Errorf("empty or null annotation relabeling rule")
+		}
+	}
 
 	return nil
 }
@@ -755,6 +2491,13 @@ type InhibitRule struct {
 	// A set of labels that must be equal between the source and target alert
 	// for them to be a match.
 	Equal model.LabelNames `yaml:"equal,omitempty" json:"equal,omitempty"`
+	// RequireEqualLabels makes the rule only apply if the labels in Equal are
+	// present on the source and target alert. By default, a label absent on
+	// both alerts is considered equal.
+	RequireEqualLabels bool `yaml:"require_equal_labels,omitempty" json:"require_equal_labels,omitempty"`
+	// ExternalSource replaces the source alerts by the label sets returned by
+	// an external check.
+	ExternalSource *ExternalInhibitSource `yaml:"external_source,omitempty" json:"external_source,omitempty"`
 }
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface for InhibitRule.
@@ -776,6 +2519,266 @@ func (r *InhibitRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
+	if r.ExternalSource != nil && (len(r.SourceMatch) > 0 || len(r.SourceMatchRE) > 0 || len(r.SourceMatchers) > 0) {
+		return fmt.Errorf("source matchers cannot be used with an external source")
+	}
+
+	return nil
+}
+
+// SuppressionRule suppresses the notifications of the matching alerts during
+// the given time intervals, unless they are more severe than MaxSeverity.
+type SuppressionRule struct {
+	// Matchers select the alerts the rule applies to.
+	Matchers Matchers `yaml:"matchers,omitempty" json:"matchers,omitempty"`
+	// TimeIntervals are the names of the mute time intervals during which
+	// the rule applies.
+	TimeIntervals []string `yaml:"time_intervals" json:"time_intervals"`
+	// MaxSeverity is the most severe severity which is suppressed, according
+	// to the order of the global severity mapping. If empty, alerts are
+	// suppressed regardless of their severity.
+	MaxSeverity string `yaml:"max_severity,omitempty" json:"max_severity,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for SuppressionRule.
+func (r *SuppressionRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain SuppressionRule
+	if err := unmarshal((*plain)(r)); err != nil {
+		return err
+	}
+	if len(r.TimeIntervals) == 0 {
+		return fmt.Errorf("missing time_intervals in suppression rule")
+	}
+	return nil
+}
+
+// AnnotationTemplate adds an annotation to the incoming alerts, rendered
+// from their labels and annotations with a Go text template, e.g.
+// 'https://grafana/d/k8s?var-cluster={{ .Labels.cluster | urlquery }}'.
+type AnnotationTemplate struct {
+	// Name is the name of the added annotation.
+	Name string `yaml:"name" json:"name"`
+	// Template is rendered with the Labels and Annotations of the alert.
+	// Missing labels and annotations are rendered as empty strings.
+	Template string `yaml:"template" json:"template"`
+	// Matchers select the alerts the annotation is added to. It is added
+	// to all alerts if empty.
+	Matchers Matchers `yaml:"matchers,omitempty" json:"matchers,omitempty"`
+	// Overwrite replaces the annotation if the alert already has it.
+	Overwrite bool `yaml:"overwrite,omitempty" json:"overwrite,omitempty"`
+
+	tmpl *template.Template
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for AnnotationTemplate.
+func (t *AnnotationTemplate) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain AnnotationTemplate
+	if err := unmarshal((*plain)(t)); err != nil {
+		return err
+	}
+	if !model.LabelName(t.Name).IsValid() {
+		return fmt.Errorf("invalid annotation name %q in annotation template", t.Name)
+	}
+	if t.Template == "" {
+		return fmt.Errorf("missing template in annotation template %q", t.Name)
+	}
+	tmpl, err := template.New(t.Name).Option("missingkey=zero").Parse(t.Template)
+	if err != nil {
+		return fmt.Errorf("invalid template in annotation template %q: %v", t.Name, err)
+	}
+	t.tmpl = tmpl
+	return nil
+}
+
+// Execute renders the template with the given data. The template is parsed
+// again if it was not unmarshaled.
+func (t *AnnotationTemplate) Execute(w io.Writer, data interface{}) error {
+	tmpl := t.tmpl
+	if tmpl == nil {
+		var err error
+		if tmpl, err = template.New(t.Name).Option("missingkey=zero").Parse(t.Template); err != nil {
+			return err
+		}
+	}
+	return tmpl.Execute(w, data)
+}
+
+// ScrubbingDetectors are the regular expressions of the named detectors of
+// scrubbing configurations.
+var ScrubbingDetectors = map[string]string{
+	"email":             `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	"bearer_token":      `(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*`,
+	"jwt":               `\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`,
+	"aws_access_key_id": `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	"github_token":      `\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+	"slack_token":       `\bxox[abposr]-[A-Za-z0-9-]+`,
+}
+
+// ScrubbingConfig redacts the matches of the detectors and patterns in the
+// annotations of alerts before the notifications are rendered.
+type ScrubbingConfig struct {
+	// Detectors are names of ScrubbingDetectors.
+	Detectors []string `yaml:"detectors,omitempty" json:"detectors,omitempty"`
+	// Patterns are unanchored regular expressions.
+	Patterns []string `yaml:"patterns,omitempty" json:"patterns,omitempty"`
+	// Annotations are the names of the scrubbed annotations. All annotations
+	// are scrubbed if empty.
+	Annotations []string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for ScrubbingConfig.
+func (c *ScrubbingConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ScrubbingConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if len(c.Detectors) == 0 && len(c.Patterns) == 0 {
+		return fmt.Errorf("scrubbing config needs detectors or patterns")
+	}
+	for _, d := range c.Detectors {
+		if _, ok := ScrubbingDetectors[d]; !ok {
+			return fmt.Errorf("unknown scrubbing detector %q", d)
+		}
+	}
+	for _, p := range c.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid scrubbing pattern %q: %v", p, err)
+		}
+	}
+	for _, a := range c.Annotations {
+		if !model.LabelName(a).IsValid() {
+			return fmt.Errorf("invalid annotation name %q in scrubbing config", a)
+		}
+	}
+	return nil
+}
+
+// QuietHours queues the notifications of a receiver while one of the given
+// time intervals is active, unless their alerts are more severe than
+// MaxSeverity, and delivers the queued alerts as a single digest once the
+// time intervals end.
+type QuietHours struct {
+	// TimeIntervals are the names of the mute time intervals making up the
+	// quiet hours.
+	TimeIntervals []string `yaml:"time_intervals" json:"time_intervals"`
+	// MaxSeverity is the most severe severity which is queued, according to
+	// the order of the global severity mapping. If empty, notifications are
+	// queued regardless of the severity of their alerts.
+	MaxSeverity string `yaml:"max_severity,omitempty" json:"max_severity,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for QuietHours.
+func (q *QuietHours) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain QuietHours
+	if err := unmarshal((*plain)(q)); err != nil {
+		return err
+	}
+	if len(q.TimeIntervals) == 0 {
+		return fmt.Errorf("missing time_intervals in quiet_hours")
+	}
+	return nil
+}
+
+// BudgetAction is what happens to the notifications exceeding a
+// notification budget.
+type BudgetAction string
+
+// The actions applying to notifications exceeding their budget.
+const (
+	// BudgetNotify sends a single notification that the budget is
+	// exceeded and drops the notifications until the budget resets.
+	BudgetNotify BudgetAction = "notify"
+	// BudgetDigest queues the notifications and delivers their alerts as a
+	// single digest once the budget resets.
+	BudgetDigest BudgetAction = "digest"
+)
+
+// NotificationBudget limits the number of notifications sent per UTC day
+// and per week, starting on Monday 00:00 UTC.
+type NotificationBudget struct {
+	// Daily and Weekly are the maximum numbers of notifications. Zero
+	// means no limit.
+	Daily  int `yaml:"daily,omitempty" json:"daily,omitempty"`
+	Weekly int `yaml:"weekly,omitempty" json:"weekly,omitempty"`
+	// Action is what happens to the notifications exceeding the budget.
+	Action BudgetAction `yaml:"action,omitempty" json:"action,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for
+// NotificationBudget.
+func (b *NotificationBudget) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain NotificationBudget
+	if err := unmarshal((*plain)(b)); err != nil {
+		return err
+	}
+	if b.Daily < 0 || b.Weekly < 0 {
+		return fmt.Errorf("notification budget must not be negative")
+	}
+	if b.Daily == 0 && b.Weekly == 0 {
+		return fmt.Errorf("missing daily or weekly notification budget")
+	}
+	if b.Action == "" {
+		b.Action = BudgetNotify
+	}
+	switch b.Action {
+	case BudgetNotify, BudgetDigest:
+	default:
+		return fmt.Errorf("unknown budget action %q, must be notify or digest", b.Action)
+	}
+	return nil
+}
+
+// DefaultExternalInhibitSource defines default values for external inhibition
+// sources.
+var DefaultExternalInhibitSource = ExternalInhibitSource{
+	Interval: model.Duration(time.Minute),
+	Timeout:  model.Duration(10 * time.Second),
+}
+
+// ExternalInhibitSource configures a check which is polled for the label sets
+// acting as the source of an inhibition rule. Either URL or PrometheusURL and
+// Query must be set.
+type ExternalInhibitSource struct {
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// URL returns a JSON list of label sets.
+	URL *URL `yaml:"url,omitempty" json:"url,omitempty"`
+	// PrometheusURL is the Prometheus server evaluating Query. Each series
+	// of the result is a label set.
+	PrometheusURL *URL   `yaml:"prometheus_url,omitempty" json:"prometheus_url,omitempty"`
+	Query         string `yaml:"query,omitempty" json:"query,omitempty"`
+
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Timeout  model.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for ExternalInhibitSource.
+func (c *ExternalInhibitSource) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultExternalInhibitSource
+	type plain ExternalInhibitSource
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if (c.URL == nil) == (c.PrometheusURL == nil) {
+		return fmt.Errorf("exactly one of url and prometheus_url must be set in external_source")
+	}
+	if c.PrometheusURL != nil && c.Query == "" {
+		return fmt.Errorf("missing query for prometheus_url in external_source")
+	}
+	if c.URL != nil && c.Query != "" {
+		return fmt.Errorf("query can only be used with prometheus_url in external_source")
+	}
+	for _, u := range []*URL{c.URL, c.PrometheusURL} {
+		if u != nil && u.Scheme != "https" && u.Scheme != "http" {
+			return fmt.Errorf("scheme required for external_source url")
+		}
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("external_source interval must be greater than zero")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("external_source timeout must be greater than zero")
+	}
 	return nil
 }
 
@@ -793,6 +2796,243 @@ type Receiver struct {
 	PushoverConfigs  []*PushoverConfig  `yaml:"pushover_configs,omitempty" json:"pushover_configs,omitempty"`
 	VictorOpsConfigs []*VictorOpsConfig `yaml:"victorops_configs,omitempty" json:"victorops_configs,omitempty"`
 	SNSConfigs       []*SNSConfig       `yaml:"sns_configs,omitempty" json:"sns_configs,omitempty"`
+	PluginConfigs    []*PluginConfig    `yaml:"plugin_configs,omitempty" json:"plugin_configs,omitempty"`
+	LocalLogConfigs  []*LocalLogConfig  `yaml:"local_log_configs,omitempty" json:"local_log_configs,omitempty"`
+	StdoutConfigs    []*StdoutConfig    `yaml:"stdout_configs,omitempty" json:"stdout_configs,omitempty"`
+	// IncidentIOConfigs send alert events to incident.io.
+	IncidentIOConfigs []*IncidentIOConfig `yaml:"incidentio_configs,omitempty" json:"incidentio_configs,omitempty"`
+	// StatuspageConfigs update the components or incidents of Statuspage
+	// pages.
+	StatuspageConfigs []*StatuspageConfig `yaml:"statuspage_configs,omitempty" json:"statuspage_configs,omitempty"`
+	// GithubConfigs open GitHub issues.
+	GithubConfigs []*GithubConfig `yaml:"github_configs,omitempty" json:"github_configs,omitempty"`
+	// GrafanaConfigs annotate Grafana dashboards.
+	GrafanaConfigs []*GrafanaConfig `yaml:"grafana_configs,omitempty" json:"grafana_configs,omitempty"`
+	// PushbulletConfigs push notifications through Pushbullet.
+	PushbulletConfigs []*PushbulletConfig `yaml:"pushbullet_configs,omitempty" json:"pushbullet_configs,omitempty"`
+	// GotifyConfigs send messages to Gotify servers.
+	GotifyConfigs []*GotifyConfig `yaml:"gotify_configs,omitempty" json:"gotify_configs,omitempty"`
+	// NtfyConfigs publish to topics of ntfy servers.
+	NtfyConfigs []*NtfyConfig `yaml:"ntfy_configs,omitempty" json:"ntfy_configs,omitempty"`
+	// NSCAConfigs send passive check results to Nagios or Icinga.
+	NSCAConfigs []*NSCAConfig `yaml:"nsca_configs,omitempty" json:"nsca_configs,omitempty"`
+	// SplunkHECConfigs send events to Splunk HTTP Event Collectors.
+	SplunkHECConfigs []*SplunkHECConfig `yaml:"splunk_hec_configs,omitempty" json:"splunk_hec_configs,omitempty"`
+	// ElasticsearchConfigs index notifications in Elasticsearch.
+	ElasticsearchConfigs []*ElasticsearchConfig `yaml:"elasticsearch_configs,omitempty" json:"elasticsearch_configs,omitempty"`
+
+	// DryRun makes the integrations log their notifications instead of
+	// sending them.
+	DryRun bool `yaml:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// HTTPTransport tunes the connections of the integrations, replacing
+	// the global settings.
+	HTTPTransport *HTTPTransportConfig `yaml:"http_transport,omitempty" json:"http_transport,omitempty"`
+
+	// QuietHours queues the notifications of the receiver during time
+	// intervals and delivers them as a digest once the intervals end.
+	QuietHours *QuietHours `yaml:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+
+	// DedupWindow suppresses a notification of the receiver if the same
+	// alerts were notified to it through another route within the window.
+	DedupWindow model.Duration `yaml:"dedup_window,omitempty" json:"dedup_window,omitempty"`
+
+	// Budget limits the notifications sent by the integrations of the
+	// receiver.
+	Budget *NotificationBudget `yaml:"budget,omitempty" json:"budget,omitempty"`
+
+	// Timezone is the IANA time zone, e.g. Europe/Berlin, in which the
+	// templates of the receiver render the times of the alerts.
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	// TimeFormat is the Go layout of the times formatted by the templates
+	// of the receiver.
+	TimeFormat string `yaml:"time_format,omitempty" json:"time_format,omitempty"`
+}
+
+// DefaultHTTPTransportConfig is the default configuration of the connections
+// of notifiers.
+var DefaultHTTPTransportConfig = HTTPTransportConfig{
+	KeepAlive:       true,
+	IdleConnTimeout: model.Duration(5 * time.Minute),
+}
+
+// HTTPTransportConfig configures the connections of the HTTP clients of
+// notifiers. The maximum number of idle connections per host (1000) and the
+// TLS handshake timeout (10s) are set by the HTTP client library.
+type HTTPTransportConfig struct {
+	// KeepAlive reuses connections between requests.
+	KeepAlive bool `yaml:"keep_alive" json:"keep_alive"`
+	// IdleConnTimeout is how long a connection is kept open for reuse.
+	IdleConnTimeout model.Duration `yaml:"idle_conn_timeout,omitempty" json:"idle_conn_timeout,omitempty"`
+	// EnableHTTP2 negotiates HTTP/2 with the servers supporting it.
+	EnableHTTP2 bool `yaml:"enable_http2" json:"enable_http2"`
+	// LocalAddress is the IP address the connections are bound to.
+	LocalAddress string `yaml:"local_address,omitempty" json:"local_address,omitempty"`
+	// Interface is the network interface the connections are bound to,
+	// through its first IPv4 address or else its first address.
+	Interface string `yaml:"interface,omitempty" json:"interface,omitempty"`
+	// UserAgent replaces the User-Agent header of the requests.
+	UserAgent string `yaml:"user_agent,omitempty" json:"user_agent,omitempty"`
+	// QueryParams are added to the query string of the requests.
+	QueryParams map[string]string `yaml:"query_params,omitempty" json:"query_params,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for HTTPTransportConfig.
+func (c *HTTPTransportConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultHTTPTransportConfig
+	type plain HTTPTransportConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.LocalAddress != "" && net.ParseIP(c.LocalAddress) == nil {
+		return fmt.Errorf("invalid local_address %q in http_transport", c.LocalAddress)
+	}
+	if c.LocalAddress != "" && c.Interface != "" {
+		return fmt.Errorf("at most one of local_address & interface must be configured in http_transport")
+	}
+	return nil
+}
+
+// TLSPolicy restricts the parameters of TLS connections, for instance to the
+// ones approved by FIPS 140.
+type TLSPolicy struct {
+	// MinVersion is the minimum TLS version: TLS10, TLS11, TLS12 or TLS13.
+	MinVersion string `yaml:"min_version,omitempty" json:"min_version,omitempty"`
+	// CipherSuites are the allowed cipher suites for TLS versions up to 1.2,
+	// by their IANA names. The cipher suites of TLS 1.3 aren't configurable.
+	CipherSuites []string `yaml:"cipher_suites,omitempty" json:"cipher_suites,omitempty"`
+	// CurvePreferences are the allowed elliptic curves for the key
+	// exchange, in order of preference: CurveP256, CurveP384, CurveP521 or
+	// X25519.
+	CurvePreferences []string `yaml:"curve_preferences,omitempty" json:"curve_preferences,omitempty"`
+
+	minVersion   uint16
+	cipherSuites []uint16
+	curves       []tls.CurveID
+}
+
+var (
+	tlsVersions = map[string]uint16{
+		"TLS10": tls.VersionTLS10,
+		"TLS11": tls.VersionTLS11,
+		"TLS12": tls.VersionTLS12,
+		"TLS13": tls.VersionTLS13,
+	}
+	tlsCurves = map[string]tls.CurveID{
+		"CurveP256": tls.CurveP256,
+		"CurveP384": tls.CurveP384,
+		"CurveP521": tls.CurveP521,
+		"X25519":    tls.X25519,
+	}
+)
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for TLSPolicy.
+func (p *TLSPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain TLSPolicy
+	if err := unmarshal((*plain)(p)); err != nil {
+		return err
+	}
+	if p.MinVersion != "" {
+		v, ok := tlsVersions[p.MinVersion]
+		if !ok {
+			return fmt.Errorf("unknown min_version %q in tls_policy", p.MinVersion)
+		}
+		p.minVersion = v
+	}
+	p.cipherSuites = nil
+	for _, name := range p.CipherSuites {
+		id, ok := cipherSuiteID(name)
+		if !ok {
+			return fmt.Errorf("unknown or insecure cipher suite %q in tls_policy", name)
+		}
+		p.cipherSuites = append(p.cipherSuites, id)
+	}
+	p.curves = nil
+	for _, name := range p.CurvePreferences {
+		id, ok := tlsCurves[name]
+		if !ok {
+			return fmt.Errorf("unknown curve %q in tls_policy", name)
+		}
+		p.curves = append(p.curves, id)
+	}
+	return nil
+}
+
+// cipherSuiteID returns the ID of the secure cipher suite with the given name.
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, cs := range tls.CipherSuites() {
+		if cs.Name == name {
+			return cs.ID, true
+		}
+	}
+	return 0, false
+}
+
+// Apply restricts the TLS configuration to the policy.
+func (p *TLSPolicy) Apply(c *tls.Config) {
+	if p == nil {
+		return
+	}
+	if p.minVersion > c.MinVersion {
+		c.MinVersion = p.minVersion
+	}
+	if len(p.cipherSuites) > 0 {
+		c.CipherSuites = p.cipherSuites
+	}
+	if len(p.curves) > 0 {
+		c.CurvePreferences = p.curves
+	}
+}
+
+// Check returns an error if a TLS connection negotiated with the given
+// version, cipher suite and curve isn't allowed by the policy. A zero curve,
+// when unknown, isn't checked.
+func (p *TLSPolicy) Check(version, cipherSuite uint16, curve tls.CurveID) error {
+	if p == nil {
+		return nil
+	}
+	if version < p.minVersion {
+		return fmt.Errorf("TLS version %s is not allowed by the TLS policy", tlsVersionName(version))
+	}
+	if version < tls.VersionTLS13 && len(p.cipherSuites) > 0 && !containsUint16(p.cipherSuites, cipherSuite) {
+		return fmt.Errorf("cipher suite %s is not allowed by the TLS policy", tls.CipherSuiteName(cipherSuite))
+	}
+	if curve != 0 && len(p.curves) > 0 {
+		for _, c := range p.curves {
+			if c == curve {
+				return nil
+			}
+		}
+		return fmt.Errorf("curve %s is not allowed by the TLS policy", curve)
+	}
+	return nil
+}
+
+func tlsVersionName(v uint16) string {
+	for name, id := range tlsVersions {
+		if id == v {
+			return name
+		}
+	}
+	return fmt.Sprintf("0x%04X", v)
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint16(s []uint16, v uint16) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
 }
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface for Receiver.
@@ -804,6 +3044,11 @@ func (c *Receiver) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if c.Name == "" {
 		return fmt.Errorf("missing name in receiver")
 	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone of receiver %q: %v", c.Name, err)
+		}
+	}
 	return nil
 }
 
@@ -879,6 +3124,55 @@ func (re Regexp) MarshalJSON() ([]byte, error) {
 	return []byte("null"), nil
 }
 
+// Expression is an expression matching alerts on their labels and
+// annotations, see the expr package.
+type Expression struct {
+	*expr.Expr
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Expression.
+func (e *Expression) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return e.parse(s)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface for Expression.
+func (e Expression) MarshalYAML() (interface{}, error) {
+	if e.Expr == nil {
+		return nil, nil
+	}
+	return e.String(), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Expression.
+func (e *Expression) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return e.parse(s)
+}
+
+// MarshalJSON implements the json.Marshaler interface for Expression.
+func (e Expression) MarshalJSON() ([]byte, error) {
+	if e.Expr == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(e.String())
+}
+
+func (e *Expression) parse(s string) error {
+	x, err := expr.Parse(s)
+	if err != nil {
+		return errors.Wrapf(err, "invalid expression %q", s)
+	}
+	e.Expr = x
+	return nil
+}
+
 // Matchers is label.Matchers with an added UnmarshalYAML method to implement the yaml.Unmarshaler interface
 // and MarshalYAML to implement the yaml.Marshaler interface.
 type Matchers labels.Matchers
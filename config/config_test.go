@@ -14,7 +14,9 @@
 package config
 
 import (
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"net/url"
 	"os"
 	"reflect"
@@ -140,7 +142,7 @@ receivers:
 `
 	_, err := Load(in)
 
-	expected := "missing name in receiver"
+	expected := "line 5: receivers[0]: missing name in receiver"
 
 	if err == nil {
 		t.Fatalf("no error returned, expected:\n%q", expected)
@@ -151,6 +153,25 @@ receivers:
 
 }
 
+func TestReceiverTimezone(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+  timezone: Europe/Berlin
+  time_format: '02.01.2006 15:04'
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", conf.Receivers[0].Timezone)
+	require.Equal(t, "02.01.2006 15:04", conf.Receivers[0].TimeFormat)
+
+	_, err = Load(strings.Replace(in, "Europe/Berlin", "Europe/Atlantis", 1))
+	require.EqualError(t, err, `line 6: receivers[0]: invalid timezone of receiver "team-X": unknown time zone Europe/Atlantis`)
+}
+
 func TestMuteTimeExists(t *testing.T) {
 	in := `
 route:
@@ -199,7 +220,7 @@ route:
 `
 	_, err := Load(in)
 
-	expected := "missing name in mute time interval"
+	expected := "line 3: mute_time_intervals[0]: missing name in mute time interval"
 
 	if err == nil {
 		t.Fatalf("no error returned, expected:\n%q", expected)
@@ -248,159 +269,1605 @@ route:
 
 }
 
-func TestGroupByHasNoDuplicatedLabels(t *testing.T) {
+func TestGroupByHasNoDuplicatedLabels(t *testing.T) {
+	in := `
+route:
+  group_by: ['alertname', 'cluster', 'service', 'cluster']
+
+receivers:
+- name: 'team-X-mails'
+`
+	_, err := Load(in)
+
+	expected := "line 3: route: duplicated label \"cluster\" in group_by"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+
+}
+
+func TestWildcardGroupByWithOtherGroupByLabels(t *testing.T) {
+	in := `
+route:
+  group_by: ['alertname', 'cluster', '...']
+  receiver: team-X-mails
+receivers:
+- name: 'team-X-mails'
+`
+	_, err := Load(in)
+
+	expected := "line 3: route: cannot have wildcard group_by (`...`) and other other labels at the same time"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+}
+
+func TestGroupByInvalidLabel(t *testing.T) {
+	in := `
+route:
+  group_by: ['-invalid-']
+  receiver: team-X-mails
+receivers:
+- name: 'team-X-mails'
+`
+	_, err := Load(in)
+
+	expected := "line 3: route: invalid label name \"-invalid-\" in group_by list"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+
+}
+
+func TestRootRouteExists(t *testing.T) {
+	in := `
+receivers:
+- name: 'team-X-mails'
+`
+	_, err := Load(in)
+
+	expected := "no routes provided"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+
+}
+
+func TestRootRouteNoMuteTimes(t *testing.T) {
+	in := `
+mute_time_intervals:
+- name: my_mute_time
+  time_intervals:
+  - times:
+     - start_time: '09:00'
+       end_time: '17:00'
+
+receivers:
+- name: 'team-X-mails'
+
+route:
+  receiver: 'team-X-mails'
+  mute_time_intervals:
+  - my_mute_time
+`
+	_, err := Load(in)
+
+	expected := "root route must not have any mute time intervals"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+
+}
+
+func TestRootRouteHasNoMatcher(t *testing.T) {
+	in := `
+route:
+  receiver: 'team-X'
+  match:
+    severity: critical
+
+receivers:
+- name: 'team-X'
+`
+	_, err := Load(in)
+
+	expected := "root route must not have any matchers"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+
+}
+
+func TestRouteExpression(t *testing.T) {
+	in := `
+route:
+  receiver: 'team-X'
+  routes:
+  - receiver: 'team-X'
+    expression: 'labels.replicas < 3'
+
+receivers:
+- name: 'team-X'
+`
+	c, err := Load(in)
+	require.NoError(t, err)
+	require.Equal(t, "labels.replicas < 3", c.Route.Routes[0].Expression.String())
+
+	out, err := yaml.Marshal(c.Route.Routes[0])
+	require.NoError(t, err)
+	require.Contains(t, string(out), "expression: labels.replicas < 3\n")
+
+	_, err = Load(strings.Replace(in, "labels.replicas < 3", "labels.replicas <", 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid expression "labels.replicas <"`)
+
+	_, err = Load(`
+route:
+  receiver: 'team-X'
+  expression: 'labels.replicas < 3'
+
+receivers:
+- name: 'team-X'
+`)
+	require.EqualError(t, err, "root route must not have any matchers")
+}
+
+func TestContinueErrorInRouteRoot(t *testing.T) {
+	in := `
+route:
+    receiver: team-X-mails
+    continue: true
+
+receivers:
+- name: 'team-X-mails'
+`
+	_, err := Load(in)
+
+	expected := "cannot have continue in root route"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+
+}
+
+func TestEscalationReceiverExists(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+    escalation:
+    - after: 30m
+      receiver: team-Y
+
+receivers:
+- name: 'team-X'
+`
+	_, err := Load(in)
+
+	expected := "undefined receiver \"team-Y\" used in route escalation"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+}
+
+func TestEscalationStepsAreIncreasing(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+    escalation:
+    - after: 30m
+      receiver: team-Y
+    - after: 15m
+      receiver: team-Z
+
+receivers:
+- name: 'team-X'
+- name: 'team-Y'
+- name: 'team-Z'
+`
+	_, err := Load(in)
+
+	expected := "line 3: route: escalation steps must have increasing, non-zero after durations"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	}
+}
+
+func TestAckWebhooksDefaults(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+ack_webhooks:
+    opsgenie_token: token
+    ack_duration: 1h
+`
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	require.Equal(t, &AckWebhooksConfig{
+		OpsGenieToken:   "token",
+		AckDuration:     model.Duration(time.Hour),
+		SilenceDuration: model.Duration(24 * time.Hour),
+	}, conf.AckWebhooks)
+	require.NotContains(t, conf.String(), "token: token")
+}
+
+func TestIngestionLimits(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+ingestion_limits:
+    max_active_alerts: 10000
+    max_label_bytes: 4096
+    client_alerts_per_second: 2.5
+`
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	require.Equal(t, &IngestionLimitsConfig{
+		MaxActiveAlerts:       10000,
+		MaxLabelBytes:         4096,
+		ClientAlertsPerSecond: 2.5,
+	}, conf.IngestionLimits)
+
+	_, err = Load(in + "    max_annotation_bytes: -1\n")
+	require.EqualError(t, err, "line 9: ingestion_limits: max_annotation_bytes must not be negative")
+
+	conf, err = Load(in + "    max_annotation_value_bytes: 1024\n    annotation_size_policy: truncate\n")
+	require.NoError(t, err)
+	require.Equal(t, 1024, conf.IngestionLimits.MaxAnnotationValueBytes)
+	require.Equal(t, SizePolicyTruncate, conf.IngestionLimits.AnnotationSizePolicy)
+
+	_, err = Load(in + "    max_annotation_value_bytes: -1\n")
+	require.EqualError(t, err, "line 9: ingestion_limits: max_annotation_value_bytes must not be negative")
+
+	_, err = Load(in + "    annotation_size_policy: drop\n")
+	require.EqualError(t, err, `line 12: ingestion_limits.annotation_size_policy: unknown size policy "drop", must be reject or truncate`)
+
+	_, err = Load(in + "    client_request_burst: -1\n")
+	require.EqualError(t, err, "line 9: ingestion_limits: client_requests_per_second and client_request_burst must not be negative")
+}
+
+func TestSilencePolicy(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+silence_policy:
+    max_duration: 7d
+    require_comment: true
+    min_matchers: 2
+`
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	require.Equal(t, &SilencePolicyConfig{
+		MaxDuration:    model.Duration(7 * 24 * time.Hour),
+		RequireComment: true,
+		MinMatchers:    2,
+	}, conf.SilencePolicy)
+
+	_, err = Load(in + "    max_active_per_creator: -1\n")
+	require.EqualError(t, err, "line 9: silence_policy: max_active_per_creator must not be negative")
+}
+
+func TestAuthorization(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+authorization:
+    roles:
+      alice: admin
+      prometheus: editor
+    group_roles:
+      dev: viewer
+      sre: editor
+`
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	require.Equal(t, &AuthorizationConfig{
+		Roles: map[string]Role{
+			"alice":      RoleAdmin,
+			"prometheus": RoleEditor,
+		},
+		GroupRoles: map[string]Role{
+			"dev": RoleViewer,
+			"sre": RoleEditor,
+		},
+		DefaultRole: RoleNone,
+	}, conf.Authorization)
+	require.Equal(t, RoleAdmin, conf.Authorization.Role("alice"))
+	require.Equal(t, RoleNone, conf.Authorization.Role("bob"))
+	require.Equal(t, RoleNone, conf.Authorization.Role(""))
+
+	// The highest role of the identity and its groups applies.
+	require.Equal(t, RoleEditor, conf.Authorization.Role("bob", "dev", "sre"))
+	require.Equal(t, RoleAdmin, conf.Authorization.Role("alice", "dev"))
+	require.Equal(t, RoleNone, conf.Authorization.Role("bob", "ops"))
+
+	require.True(t, RoleAdmin.Allows(RoleEditor))
+	require.True(t, RoleViewer.Allows(RoleViewer))
+	require.False(t, RoleViewer.Allows(RoleEditor))
+	require.True(t, RoleNone.Allows(RoleNone))
+
+	_, err = Load(in + "    default_role: owner\n")
+	require.EqualError(t, err, `line 15: authorization.default_role: unknown role "owner", must be one of none, viewer, editor or admin`)
+}
+
+func TestTenancy(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+    routes:
+    - receiver: team-X
+      group_by: ['alertname']
+    - receiver: team-X
+      group_by: ['...']
+    - receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+inhibit_rules:
+- source_matchers: ['severity="critical"']
+  target_matchers: ['severity="warning"']
+  equal: ['alertname']
+
+tenancy:
+    label: org
+`
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	require.Equal(t, &TenancyConfig{Header: "X-Scope-OrgID", Label: "org"}, conf.Tenancy)
+
+	// Only the routes overriding the grouping of their parent are changed.
+	require.Equal(t, []model.LabelName{"org"}, conf.Route.GroupBy)
+	require.Equal(t, []model.LabelName{"alertname", "org"}, conf.Route.Routes[0].GroupBy)
+	require.True(t, conf.Route.Routes[1].GroupByAll)
+	require.Nil(t, conf.Route.Routes[1].GroupBy)
+	require.Nil(t, conf.Route.Routes[2].GroupBy)
+	require.Equal(t, model.LabelNames{"alertname", "org"}, conf.InhibitRules[0].Equal)
+
+	// The changes are kept when the configuration is loaded again.
+	reloaded, err := Load(conf.String())
+	require.NoError(t, err)
+	require.Equal(t, conf.Route.Routes[0].GroupBy, reloaded.Route.Routes[0].GroupBy)
+	require.Equal(t, conf.InhibitRules[0].Equal, reloaded.InhibitRules[0].Equal)
+
+	_, err = Load(in + "    header: ''\n")
+	require.EqualError(t, err, "line 20: tenancy: missing header in tenancy config")
+
+	conf, err = Load(in + "    tenants:\n      prometheus-a: a\n")
+	require.NoError(t, err)
+	tenant, ok := conf.Tenancy.Tenant("prometheus-a")
+	require.True(t, ok)
+	require.Equal(t, "a", tenant)
+	_, ok = conf.Tenancy.Tenant("prometheus-b")
+	require.False(t, ok)
+
+	_, err = Load(in + "    tenants:\n      prometheus-a: ''\n")
+	require.EqualError(t, err, "line 20: tenancy: empty identity or tenant in tenancy config")
+}
+
+func TestOIDC(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+oidc:
+    issuer_url: https://accounts.example.com
+    client_id: alertmanager
+    client_secret: secret
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Equal(t, "https://accounts.example.com", conf.OIDC.IssuerURL.String())
+	require.Equal(t, []string{"openid", "profile", "email"}, conf.OIDC.Scopes)
+	require.Equal(t, "email", conf.OIDC.UsernameClaim)
+	require.Equal(t, "groups", conf.OIDC.GroupsClaim)
+	require.Equal(t, model.Duration(12*time.Hour), conf.OIDC.SessionDuration)
+	// The global HTTP client configuration is used by default.
+	require.Same(t, conf.Global.HTTPConfig, conf.OIDC.HTTPConfig)
+
+	_, err = Load(in + "    scopes: [email]\n")
+	require.EqualError(t, err, "line 9: oidc: scopes of OIDC config must include openid")
+
+	_, err = Load(strings.Replace(in, "    client_secret: secret\n", "", 1))
+	require.EqualError(t, err, "line 9: oidc: missing client_id or client_secret in OIDC config")
+}
+
+func TestLabelValidation(t *testing.T) {
+	in := `
+global:
+    label_validation: %s
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+`
+	conf, err := Load(fmt.Sprintf(in, "utf8"))
+	require.NoError(t, err)
+	require.Equal(t, LabelValidationUTF8, conf.Global.LabelValidation)
+
+	_, err = Load(fmt.Sprintf(in, "unicode"))
+	require.EqualError(t, err, `line 3: global.label_validation: unknown label validation "unicode", must be legacy or utf8`)
+}
+
+func TestTracing(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+tracing:
+    endpoint: http://collector:4318
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Equal(t, "http://collector:4318", conf.Tracing.Endpoint.String())
+	require.Equal(t, 1.0, conf.Tracing.SamplingFraction)
+	require.Equal(t, model.Duration(10*time.Second), conf.Tracing.Timeout)
+	require.Same(t, conf.Global.HTTPConfig, conf.Tracing.HTTPConfig)
+
+	_, err = Load(in + "    sampling_fraction: 1.5\n")
+	require.EqualError(t, err, "line 9: tracing: sampling_fraction of tracing config must be between 0 and 1")
+
+	_, err = Load(strings.Replace(in, "    endpoint: http://collector:4318\n", "    timeout: 5s\n", 1))
+	require.EqualError(t, err, "line 9: tracing: missing endpoint in tracing config")
+}
+
+func TestWatchdogs(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+watchdogs:
+- name: prometheus
+  matchers: ['alertname="Watchdog"']
+  receiver: team-X
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Len(t, conf.Watchdogs, 1)
+	require.Equal(t, model.Duration(10*time.Minute), conf.Watchdogs[0].Interval)
+	require.Equal(t, `alertname="Watchdog"`, conf.Watchdogs[0].Matchers[0].String())
+
+	_, err = Load(in + in[strings.Index(in, "- name: prometheus"):])
+	require.EqualError(t, err, `watchdog "prometheus" is not unique`)
+
+	_, err = Load(strings.Replace(in, "\n  receiver: team-X", "\n  receiver: team-Y", 1))
+	require.EqualError(t, err, `undefined receiver "team-Y" used in watchdog "prometheus"`)
+
+	_, err = Load(strings.Replace(in, "  matchers: ['alertname=\"Watchdog\"']\n", "", 1))
+	require.EqualError(t, err, `line 9: watchdogs[0]: watchdog "prometheus" has no matchers`)
+}
+
+func TestLifecycleWebhook(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+lifecycle_webhook:
+    url: http://analytics.example.com/events
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Equal(t, "http://analytics.example.com/events", conf.LifecycleWebhook.URL.String())
+	require.Equal(t, 100, conf.LifecycleWebhook.MaxEvents)
+	require.Equal(t, model.Duration(10*time.Second), conf.LifecycleWebhook.FlushInterval)
+	require.Same(t, conf.Global.HTTPConfig, conf.LifecycleWebhook.HTTPConfig)
+
+	conf, err = Load(in + "    events: [silenced, inhibited]\n")
+	require.NoError(t, err)
+	require.Equal(t, []string{"silenced", "inhibited"}, conf.LifecycleWebhook.Events)
+
+	_, err = Load(in + "    events: [escalated]\n")
+	require.EqualError(t, err, `line 9: lifecycle_webhook: unknown lifecycle event "escalated", must be one of received, grouped, silenced, inhibited, notified, resolved`)
+
+	_, err = Load(in + "    max_events: 0\n")
+	require.EqualError(t, err, "line 9: lifecycle_webhook: max_events of lifecycle webhook config must be greater than zero")
+
+	_, err = Load(strings.Replace(in, "    url: http://analytics.example.com/events\n", "    flush_interval: 1s\n", 1))
+	require.EqualError(t, err, "line 9: lifecycle_webhook: missing url in lifecycle webhook config")
+}
+
+func TestNotificationAnalytics(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+notification_analytics:
+`
+	conf, err := Load(in + "    type: honeycomb\n    dataset: alertmanager\n    api_key: key\n")
+	require.NoError(t, err)
+	require.Equal(t, "https://api.honeycomb.io", conf.NotificationAnalytics.URL.String())
+	require.Equal(t, 100, conf.NotificationAnalytics.MaxEvents)
+	require.Equal(t, model.Duration(10*time.Second), conf.NotificationAnalytics.FlushInterval)
+	require.Same(t, conf.Global.HTTPConfig, conf.NotificationAnalytics.HTTPConfig)
+
+	_, err = Load(in + "    type: honeycomb\n    api_key: key\n")
+	require.EqualError(t, err, "line 9: notification_analytics: missing dataset in notification analytics config")
+
+	conf, err = Load(in + "    type: otlp\n    url: http://collector:4318/v1/logs\n")
+	require.NoError(t, err)
+	require.Equal(t, "http://collector:4318/v1/logs", conf.NotificationAnalytics.URL.String())
+
+	_, err = Load(in + "    type: otlp\n")
+	require.EqualError(t, err, "line 9: notification_analytics: missing url in notification analytics config")
+
+	_, err = Load(in + "    type: datadog\n")
+	require.EqualError(t, err, `line 9: notification_analytics: unknown notification analytics type "datadog", must be one of honeycomb, otlp`)
+}
+
+func TestHeartbeat(t *testing.T) {
+	in := `
+global:
+    opsgenie_api_key: key
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+heartbeat:
+    name: alertmanager
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Equal(t, "alertmanager", conf.Heartbeat.Name)
+	require.Equal(t, model.Duration(time.Minute), conf.Heartbeat.Interval)
+	require.Equal(t, Secret("key"), conf.Heartbeat.APIKey)
+	require.Equal(t, "https://api.opsgenie.com/", conf.Heartbeat.APIURL.String())
+	require.Same(t, conf.Global.HTTPConfig, conf.Heartbeat.HTTPConfig)
+
+	conf, err = Load(in + "    api_url: https://api.eu.opsgenie.com\n")
+	require.NoError(t, err)
+	require.Equal(t, "https://api.eu.opsgenie.com/", conf.Heartbeat.APIURL.String())
+
+	_, err = Load(strings.Replace(in, "    opsgenie_api_key: key\n", "    resolve_timeout: 5m\n", 1))
+	require.EqualError(t, err, "no global OpsGenie API Key set")
+
+	_, err = Load(in + "    interval: 0s\n")
+	require.EqualError(t, err, "line 11: heartbeat: interval of heartbeat config must be greater than zero")
+}
+
+func TestHTTPTransport(t *testing.T) {
+	in := `
+global:
+    http_transport:
+        enable_http2: true
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+- name: 'team-Y'
+  http_transport:
+      keep_alive: false
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Equal(t, &HTTPTransportConfig{
+		KeepAlive:       true,
+		IdleConnTimeout: model.Duration(5 * time.Minute),
+		EnableHTTP2:     true,
+	}, conf.Global.HTTPTransport)
+	require.Same(t, conf.Global.HTTPTransport, conf.Receivers[0].HTTPTransport)
+	require.Equal(t, &HTTPTransportConfig{
+		KeepAlive:       false,
+		IdleConnTimeout: model.Duration(5 * time.Minute),
+	}, conf.Receivers[1].HTTPTransport)
+
+	_, err = Load(in + "      local_address: 10.0.0\n")
+	require.EqualError(t, err, "line 12: receivers[1].http_transport: invalid local_address \"10.0.0\" in http_transport")
+
+	_, err = Load(in + "      local_address: 10.0.0.1\n      interface: eth1\n")
+	require.EqualError(t, err, "line 12: receivers[1].http_transport: at most one of local_address & interface must be configured in http_transport")
+}
+
+func TestTLSPolicy(t *testing.T) {
+	in := `
+global:
+    tls_policy:
+        min_version: TLS12
+        cipher_suites: [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256]
+        curve_preferences: [CurveP256, CurveP384]
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	p := conf.Global.TLSPolicy
+
+	c := &tls.Config{}
+	p.Apply(c)
+	require.Equal(t, uint16(tls.VersionTLS12), c.MinVersion)
+	require.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, c.CipherSuites)
+	require.Equal(t, []tls.CurveID{tls.CurveP256, tls.CurveP384}, c.CurvePreferences)
+
+	require.NoError(t, p.Check(tls.VersionTLS12, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, 0))
+	require.NoError(t, p.Check(tls.VersionTLS13, tls.TLS_CHACHA20_POLY1305_SHA256, tls.CurveP384))
+	require.EqualError(t, p.Check(tls.VersionTLS11, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, 0), "TLS version TLS11 is not allowed by the TLS policy")
+	require.EqualError(t, p.Check(tls.VersionTLS12, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, 0), "cipher suite TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 is not allowed by the TLS policy")
+	require.EqualError(t, p.Check(tls.VersionTLS13, tls.TLS_AES_128_GCM_SHA256, tls.X25519), "curve X25519 is not allowed by the TLS policy")
+
+	// Without a policy, anything goes.
+	require.NoError(t, (*TLSPolicy)(nil).Check(tls.VersionTLS10, tls.TLS_RSA_WITH_RC4_128_SHA, 0))
+
+	_, err = Load(strings.Replace(in, "min_version: TLS12", "min_version: SSL30", 1))
+	require.EqualError(t, err, "line 4: global.tls_policy: unknown min_version \"SSL30\" in tls_policy")
+
+	_, err = Load(strings.Replace(in, "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_RC4_128_SHA", 1))
+	require.EqualError(t, err, "line 4: global.tls_policy: unknown or insecure cipher suite \"TLS_RSA_WITH_RC4_128_SHA\" in tls_policy")
+
+	_, err = Load(strings.Replace(in, "CurveP384", "P384", 1))
+	require.EqualError(t, err, "line 4: global.tls_policy: unknown curve \"P384\" in tls_policy")
+}
+
+func TestKafkaIngesters(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+kafka_ingesters:
+- rest_proxy_url: http://kafka-rest:8082
+  topics: [alerts]
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Len(t, conf.KafkaIngesters, 1)
+	k := conf.KafkaIngesters[0]
+	require.Equal(t, "http://kafka-rest:8082", k.RESTProxyURL.String())
+	require.Equal(t, []string{"alerts"}, k.Topics)
+	require.Equal(t, "alertmanager", k.Group)
+	require.Equal(t, "latest", k.AutoOffsetReset)
+	require.Equal(t, model.Duration(5*time.Second), k.PollTimeout)
+	require.Same(t, conf.Global.HTTPConfig, k.HTTPConfig)
+
+	_, err = Load(in + "  auto_offset_reset: none\n")
+	require.EqualError(t, err, "line 9: kafka_ingesters[0]: auto_offset_reset of Kafka ingester config must be earliest or latest")
+
+	_, err = Load(strings.Replace(in, "  topics: [alerts]\n", "", 1))
+	require.EqualError(t, err, "line 9: kafka_ingesters[0]: missing topics in Kafka ingester config")
+
+	_, err = Load(in + "- null\n")
+	require.EqualError(t, err, "empty or null Kafka ingester")
+}
+
+func TestSQSIngesters(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+sqs_ingesters:
+- queue_url: https://sqs.eu-west-1.amazonaws.com/123456789012/alerts
+`
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Len(t, conf.SQSIngesters, 1)
+	q := conf.SQSIngesters[0]
+	require.Equal(t, model.Duration(20*time.Second), q.WaitTime)
+	require.Equal(t, int64(10), q.MaxMessages)
+	require.Equal(t, map[string]string{"alertname": "AlarmName"}, q.Labels)
+	require.Equal(t, "NewStateValue", q.StatusField)
+	require.Equal(t, []string{"OK"}, q.ResolvedValues)
+	require.Same(t, conf.Global.HTTPConfig, q.HTTPConfig)
+
+	conf, err = Load(in + "  labels:\n    alarm: AlarmName\n")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"alarm": "AlarmName"}, conf.SQSIngesters[0].Labels)
+
+	_, err = Load(in + "  labels:\n    alarm-name: AlarmName\n")
+	require.EqualError(t, err, `line 9: sqs_ingesters[0]: invalid label name "alarm-name" in SQS ingester config`)
+
+	_, err = Load(in + "  wait_time: 1m\n")
+	require.EqualError(t, err, "line 9: sqs_ingesters[0]: wait_time of SQS ingester config must be between 0s and 20s")
+
+	_, err = Load(strings.Replace(in, "- queue_url:", "- api_url:", 1))
+	require.EqualError(t, err, "line 9: sqs_ingesters[0]: missing queue_url in SQS ingester config")
+}
+
+func TestCORS(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+cors:
+`
+	conf, err := Load(in + "    max_age: 10m\n")
+	require.NoError(t, err)
+	expected := DefaultCORSConfig
+	expected.MaxAge = model.Duration(10 * time.Minute)
+	require.Equal(t, &expected, conf.CORS)
+
+	for _, tc := range []struct {
+		in  string
+		err string
+	}{
+		{
+			in:  "    allowed_origins: []\n",
+			err: "line 9: cors: missing allowed_origins in CORS config",
+		},
+		{
+			in:  "    allowed_origins: ['https://*.*.example.com']\n",
+			err: `line 9: cors: allowed origin "https://*.*.example.com" may contain at most one wildcard`,
+		},
+		{
+			in:  "    allow_credentials: true\n",
+			err: "line 9: cors: allow_credentials requires explicit allowed_origins",
+		},
+	} {
+		_, err := Load(in + tc.in)
+		require.EqualError(t, err, tc.err)
+	}
+
+	_, err = Load(in + "    allowed_origins: ['https://dashboard.example.com']\n    allow_credentials: true\n")
+	require.NoError(t, err)
+}
+
+func TestAddTenantOverlay(t *testing.T) {
+	conf, err := Load(`
+route:
+    receiver: team-X
+    routes:
+    - receiver: team-X
+      matchers: ['severity="critical"']
+
+receivers:
+- name: 'team-X'
+
+tenancy:
+    label: org
+`)
+	require.NoError(t, err)
+
+	err = conf.AddTenantOverlay("a", `
+route:
+    receiver: default
+    routes:
+    - receiver: pager
+      group_by: ['alertname']
+      matchers: ['severity="critical"']
+
+receivers:
+- name: default
+- name: pager
+`, "")
+	require.NoError(t, err)
+
+	require.Len(t, conf.Route.Routes, 2)
+	r := conf.Route.Routes[0]
+	require.Equal(t, "a/default", r.Receiver)
+	require.Equal(t, `org="a"`, r.Matchers[0].String())
+	require.Nil(t, r.GroupBy)
+	require.Equal(t, "a/pager", r.Routes[0].Receiver)
+	require.Equal(t, []model.LabelName{"alertname", "org"}, r.Routes[0].GroupBy)
+	require.Equal(t, "team-X", conf.Route.Routes[1].Receiver)
+
+	var names []string
+	for _, rcv := range conf.Receivers {
+		names = append(names, rcv.Name)
+	}
+	require.Equal(t, []string{"team-X", "a/default", "a/pager"}, names)
+
+	// The merged configuration can be loaded again.
+	_, err = Load(conf.String())
+	require.NoError(t, err)
+
+	// Invalid overlays leave the configuration unchanged.
+	for _, tc := range []struct {
+		overlay string
+		err     string
+	}{
+		{
+			overlay: "route:\n    receiver: missing\n",
+			err:     `undefined receiver "missing" used in route`,
+		},
+		{
+			overlay: "route:\n    receiver: default\nreceivers:\n- name: default\ninhibit_rules: []\n",
+			err:     "yaml: unmarshal errors:\n  line 5: field inhibit_rules not found in type config.tenantOverlay",
+		},
+		{
+			overlay: "receivers:\n- name: default\n",
+			err:     "no routes provided",
+		},
+	} {
+		require.EqualError(t, conf.AddTenantOverlay("b", tc.overlay, ""), tc.err)
+	}
+	require.Len(t, conf.Route.Routes, 2)
+	require.Len(t, conf.Receivers, 3)
+
+	// The receivers of a tenant must not clash with those of another
+	// tenant.
+	require.EqualError(t,
+		conf.AddTenantOverlay("a", "route:\n    receiver: default\nreceivers:\n- name: default\n", ""),
+		`notification config name "a/default" is not unique`,
+	)
+}
+
+func TestSilencePresets(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+silence_presets:
+- name: node-maintenance
+  matchers: ['instance="{{ .instance }}"']
+  duration: 2h
+  comment: 'Maintenance of {{ .instance }}'
+`
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	require.Equal(t, []*SilencePreset{{
+		Name:     "node-maintenance",
+		Matchers: []string{`instance="{{ .instance }}"`},
+		Duration: model.Duration(2 * time.Hour),
+		Comment:  "Maintenance of {{ .instance }}",
+	}}, conf.SilencePresets)
+
+	_, err = Load(in + `- name: node-maintenance
+  matchers: ['job="node"']
+  duration: 1h
+`)
+	require.EqualError(t, err, `silence preset "node-maintenance" is not unique`)
+
+	_, err = Load(in + `- name: broken
+  matchers: ['job="{{ .job"']
+  duration: 1h
+`)
+	require.Error(t, err)
+}
+
+func TestInhibitRuleExternalSource(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+inhibit_rules:
+- target_matchers: ['severity="warning"']
+  equal: [cluster]
+  external_source:
+    prometheus_url: http://prometheus:9090
+    query: failover_in_progress == 1
+`
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	es := conf.InhibitRules[0].ExternalSource
+	require.Equal(t, "http://prometheus:9090", es.PrometheusURL.String())
+	require.Equal(t, "failover_in_progress == 1", es.Query)
+	require.Equal(t, model.Duration(time.Minute), es.Interval)
+
+	for _, tc := range []struct {
+		rule string
+		err  string
+	}{
+		{
+			rule: `
+- source_matchers: ['alertname="ClusterDown"']
+  external_source:
+    url: http://failover/active`,
+			err: "line 9: inhibit_rules[0]: source matchers cannot be used with an external source",
+		},
+		{
+			rule: `
+- external_source:
+    url: http://failover/active
+    prometheus_url: http://prometheus:9090`,
+			err: "line 10: inhibit_rules[0].external_source: exactly one of url and prometheus_url must be set in external_source",
+		},
+		{
+			rule: `
+- external_source:
+    prometheus_url: http://prometheus:9090`,
+			err: "line 10: inhibit_rules[0].external_source: missing query for prometheus_url in external_source",
+		},
+		{
+			rule: `
+- external_source:
+    url: http://failover/active
+    interval: 0s`,
+			err: "line 10: inhibit_rules[0].external_source: external_source interval must be greater than zero",
+		},
+	} {
+		_, err := Load(`
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+inhibit_rules:` + tc.rule)
+		require.EqualError(t, err, tc.err)
+	}
+}
+
+func TestSuppressionRules(t *testing.T) {
+	in := `
+global:
+  severity_mapping:
+  - severity: critical
+  - severity: warning
+
+route:
+    receiver: team-X
+
+receivers:
+- name: 'team-X'
+
+mute_time_intervals:
+- name: night
+  time_intervals:
+  - times:
+    - start_time: '00:00'
+      end_time: '06:00'
+
+suppression_rules:
+`
+	conf, err := Load(in + `
+- matchers: ['team="db"']
+  time_intervals: [night]
+  max_severity: warning
+`)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	require.Len(t, conf.SuppressionRules, 1)
+	require.Equal(t, []string{"night"}, conf.SuppressionRules[0].TimeIntervals)
+	require.Equal(t, "warning", conf.SuppressionRules[0].MaxSeverity)
+
+	_, err = Load(in + `
+- time_intervals: [day]
+`)
+	require.EqualError(t, err, `undefined time interval "day" used in suppression rule`)
+
+	_, err = Load(in + `
+- time_intervals: [night]
+  max_severity: info
+`)
+	require.EqualError(t, err, `max_severity "info" of suppression rule is not in the global severity_mapping`)
+
+	_, err = Load(in + `
+- matchers: ['team="db"']
+`)
+	require.EqualError(t, err, "line 22: suppression_rules[0]: missing time_intervals in suppression rule")
+}
+
+func TestQuietHours(t *testing.T) {
 	in := `
+global:
+  severity_mapping:
+  - severity: critical
+  - severity: warning
+
 route:
-  group_by: ['alertname', 'cluster', 'service', 'cluster']
+    receiver: team-X
+
+mute_time_intervals:
+- name: night
+  time_intervals:
+  - times:
+    - start_time: '00:00'
+      end_time: '06:00'
 
 receivers:
-- name: 'team-X-mails'
+- name: 'team-X'
+  quiet_hours:
 `
-	_, err := Load(in)
+	conf, err := Load(in + `
+    time_intervals: [night]
+    max_severity: warning
+`)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	require.Equal(t, &QuietHours{TimeIntervals: []string{"night"}, MaxSeverity: "warning"}, conf.Receivers[0].QuietHours)
 
-	expected := "duplicated label \"cluster\" in group_by"
+	_, err = Load(in + `
+    time_intervals: [day]
+`)
+	require.EqualError(t, err, `undefined time interval "day" used in quiet_hours of receiver "team-X"`)
 
-	if err == nil {
-		t.Fatalf("no error returned, expected:\n%q", expected)
-	}
-	if err.Error() != expected {
-		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
-	}
+	_, err = Load(in + `
+    time_intervals: [night]
+    max_severity: info
+`)
+	require.EqualError(t, err, `max_severity "info" of quiet_hours of receiver "team-X" is not in the global severity_mapping`)
 
+	_, err = Load(in + `
+    max_severity: warning
+`)
+	require.EqualError(t, err, "line 21: receivers[0].quiet_hours: missing time_intervals in quiet_hours")
 }
 
-func TestWildcardGroupByWithOtherGroupByLabels(t *testing.T) {
+func TestNotificationBudget(t *testing.T) {
 	in := `
 route:
-  group_by: ['alertname', 'cluster', '...']
-  receiver: team-X-mails
+    receiver: team-X
+
 receivers:
-- name: 'team-X-mails'
+- name: 'team-X'
+  budget:
 `
-	_, err := Load(in)
-
-	expected := "cannot have wildcard group_by (`...`) and other other labels at the same time"
-
-	if err == nil {
-		t.Fatalf("no error returned, expected:\n%q", expected)
-	}
-	if err.Error() != expected {
-		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	conf, err := Load(in + `
+    daily: 10
+`)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
 	}
+	require.Equal(t, &NotificationBudget{Daily: 10, Action: BudgetNotify}, conf.Receivers[0].Budget)
+
+	conf, err = Load(in + `
+    weekly: 50
+    action: digest
+`)
+	require.NoError(t, err)
+	require.Equal(t, &NotificationBudget{Weekly: 50, Action: BudgetDigest}, conf.Receivers[0].Budget)
+
+	_, err = Load(in + `
+    action: digest
+`)
+	require.EqualError(t, err, "line 9: receivers[0].budget: missing daily or weekly notification budget")
+
+	_, err = Load(in + `
+    daily: -1
+`)
+	require.EqualError(t, err, "line 9: receivers[0].budget: notification budget must not be negative")
+
+	_, err = Load(in + `
+    daily: 10
+    action: drop
+`)
+	require.EqualError(t, err, `line 9: receivers[0].budget: unknown budget action "drop", must be notify or digest`)
 }
 
-func TestGroupByInvalidLabel(t *testing.T) {
+func TestSeverityMapping(t *testing.T) {
 	in := `
+global:
+    severity_mapping:
+    - severity: critical
+      pagerduty: critical
+      opsgenie: P1
+      pushover: "2"
+      local_log: critical
+      gotify: "10"
+      ntfy: urgent
+    - severity: warning
+      opsgenie: P3
+
 route:
-  group_by: ['-invalid-']
-  receiver: team-X-mails
+    receiver: team-X
+
 receivers:
-- name: 'team-X-mails'
+- name: 'team-X'
+  pagerduty_configs:
+  - routing_key: key
+  opsgenie_configs:
+  - api_key: key
+  local_log_configs:
+  - identifier: alertmanager
+  gotify_configs:
+  - url: https://gotify.example.com
+    token: token
+  ntfy_configs:
+  - topic: alerts
 `
-	_, err := Load(in)
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
+	}
+	expected := &SeverityMappings{
+		Label:    "severity",
+		Mappings: conf.Global.SeverityMapping,
+	}
+	require.Len(t, expected.Mappings, 2)
+	require.Equal(t, expected, conf.Receivers[0].PagerdutyConfigs[0].SeverityMappings)
+	require.Equal(t, expected, conf.Receivers[0].OpsGenieConfigs[0].SeverityMappings)
+	require.Equal(t, expected, conf.Receivers[0].LocalLogConfigs[0].SeverityMappings)
+	require.Equal(t, expected, conf.Receivers[0].GotifyConfigs[0].SeverityMappings)
+	require.Equal(t, expected, conf.Receivers[0].NtfyConfigs[0].SeverityMappings)
+	require.Equal(t, "https://ntfy.sh", conf.Receivers[0].NtfyConfigs[0].URL.String())
+}
+
+func TestSeverityMappingInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		mapping  string
+		expected string
+	}{
+		{
+			mapping:  "- pagerduty: critical",
+			expected: "line 4: global.severity_mapping[0]: missing severity in severity mapping",
+		},
+		{
+			mapping:  "- severity: critical\n      pagerduty: high",
+			expected: `line 4: global.severity_mapping[0]: invalid PagerDuty severity "high", must be one of critical, error, warning or info`,
+		},
+		{
+			mapping:  "- severity: critical\n      opsgenie: P0",
+			expected: `line 4: global.severity_mapping[0]: invalid OpsGenie priority "P0", must be one of P1 to P5`,
+		},
+		{
+			mapping:  "- severity: critical\n      pushover: 3",
+			expected: `line 4: global.severity_mapping[0]: invalid Pushover priority "3", must be between -2 and 2`,
+		},
+		{
+			mapping:  "- severity: critical\n      local_log: emergency",
+			expected: `line 4: global.severity_mapping[0]: invalid local log level "emergency", must be one of critical, error, warning or info`,
+		},
+		{
+			mapping:  "- severity: critical\n      gotify: 11",
+			expected: `line 4: global.severity_mapping[0]: invalid Gotify priority "11", must be between 0 and 10`,
+		},
+		{
+			mapping:  "- severity: critical\n      ntfy: max",
+			expected: `line 4: global.severity_mapping[0]: invalid ntfy priority "max", must be one of min, low, default, high, urgent or between 1 and 5`,
+		},
+		{
+			mapping:  "- severity: critical\n    - severity: critical",
+			expected: `line 3: global: severity "critical" is mapped more than once`,
+		},
+	} {
+		in := fmt.Sprintf(`
+global:
+    severity_mapping:
+    %s
 
-	expected := "invalid label name \"-invalid-\" in group_by list"
+route:
+    receiver: team-X
 
-	if err == nil {
-		t.Fatalf("no error returned, expected:\n%q", expected)
-	}
-	if err.Error() != expected {
-		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+receivers:
+- name: 'team-X'
+`, tc.mapping)
+		_, err := Load(in)
+		require.EqualError(t, err, tc.expected)
 	}
-
 }
 
-func TestRootRouteExists(t *testing.T) {
+func TestNotificationMiddlewaresDuplicated(t *testing.T) {
 	in := `
+global:
+    notification_middlewares: [scrub, audit, scrub]
+
+route:
+    receiver: team-X
+
 receivers:
-- name: 'team-X-mails'
+- name: 'team-X'
 `
 	_, err := Load(in)
+	require.EqualError(t, err, `line 3: global: notification middleware "scrub" is listed more than once`)
+}
 
-	expected := "no routes provided"
+func TestScrubbingInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		scrubbing string
+		expected  string
+	}{
+		{
+			scrubbing: "annotations: [summary]",
+			expected:  "line 3: scrubbing: scrubbing config needs detectors or patterns",
+		},
+		{
+			scrubbing: "detectors: [phone_number]",
+			expected:  `line 3: scrubbing: unknown scrubbing detector "phone_number"`,
+		},
+		{
+			scrubbing: "patterns: ['token=(']",
+			expected:  "line 3: scrubbing: invalid scrubbing pattern \"token=(\": error parsing regexp: missing closing ): `token=(`",
+		},
+	} {
+		in := fmt.Sprintf(`
+scrubbing:
+    %s
 
-	if err == nil {
-		t.Fatalf("no error returned, expected:\n%q", expected)
-	}
-	if err.Error() != expected {
-		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
-	}
+route:
+    receiver: team-X
 
+receivers:
+- name: 'team-X'
+`, tc.scrubbing)
+		_, err := Load(in)
+		require.EqualError(t, err, tc.expected)
+	}
 }
 
-func TestRootRouteNoMuteTimes(t *testing.T) {
+func TestAnnotationTemplates(t *testing.T) {
 	in := `
-mute_time_intervals:
-- name: my_mute_time
-  time_intervals:
-  - times:
-     - start_time: '09:00'
-       end_time: '17:00'
+route:
+    receiver: team-X
 
 receivers:
-- name: 'team-X-mails'
+- name: 'team-X'
 
-route:
-  receiver: 'team-X-mails'
-  mute_time_intervals:
-  - my_mute_time
+annotation_templates:
+- name: dashboard
+  template: 'https://grafana/d/k8s?var-cluster={{ .Labels.cluster }}'
+  matchers: ['cluster=~".+"']
 `
-	_, err := Load(in)
+	conf, err := Load(in)
+	require.NoError(t, err)
+	require.Len(t, conf.AnnotationTemplates, 1)
+	at := conf.AnnotationTemplates[0]
+	require.Equal(t, "dashboard", at.Name)
+	require.False(t, at.Overwrite)
+	require.Len(t, at.Matchers, 1)
+	require.Equal(t, `cluster=~".+"`, at.Matchers[0].String())
 
-	expected := "root route must not have any mute time intervals"
+	var b strings.Builder
+	require.NoError(t, at.Execute(&b, map[string]map[string]string{"Labels": {"cluster": "eu-1"}}))
+	require.Equal(t, "https://grafana/d/k8s?var-cluster=eu-1", b.String())
 
-	if err == nil {
-		t.Fatalf("no error returned, expected:\n%q", expected)
+	for _, tc := range []struct {
+		template string
+		expected string
+	}{
+		{
+			template: "name: 1-dashboard\n  template: x",
+			expected: `invalid annotation name "1-dashboard" in annotation template`,
+		},
+		{
+			template: "name: dashboard",
+			expected: `missing template in annotation template "dashboard"`,
+		},
+		{
+			template: "name: dashboard\n  template: '{{ .Labels.cluster'",
+			expected: `invalid template in annotation template "dashboard": template: dashboard:1: unclosed action`,
+		},
+	} {
+		_, err := Load(strings.Replace(in, "name: dashboard\n  template: 'https://grafana/d/k8s?var-cluster={{ .Labels.cluster }}'", tc.template, 1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), tc.expected)
 	}
-	if err.Error() != expected {
-		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+}
+
+func TestEnrichmentDefaults(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+    routes:
+    - receiver: team-X
+      enrichment:
+        url: http://enrichment.example.com/
+        timeout: 1s
+
+receivers:
+- name: 'team-X'
+`
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
 	}
+	e := conf.Route.Routes[0].Enrichment
+	require.Equal(t, model.Duration(time.Second), e.Timeout)
+	require.Equal(t, DefaultEnrichmentConfig.CacheTTL, e.CacheTTL)
+	require.Equal(t, conf.Global.HTTPConfig, e.HTTPConfig)
 
+	_, err = Load(`
+route:
+    receiver: team-X
+    enrichment:
+        timeout: 1s
+
+receivers:
+- name: 'team-X'
+`)
+	require.EqualError(t, err, "line 5: route.enrichment: missing URL in enrichment config")
 }
 
-func TestRootRouteHasNoMatcher(t *testing.T) {
+func TestOwnerConfig(t *testing.T) {
 	in := `
+global:
+    opsgenie_api_key: key
 route:
-  receiver: 'team-X'
-  match:
-    severity: critical
+    receiver: team-X
+    owner:
+        name: sre
+        on_call:
+            provider: opsgenie
+            schedule_id: sre-schedule
+    routes:
+    - receiver: team-X
+      owner:
+        name: db
+        on_call:
+            provider: pagerduty
+            schedule_id: PSCHED1
+            api_key: pd-key
 
 receivers:
 - name: 'team-X'
 `
-	_, err := Load(in)
+	conf, err := Load(in)
+	require.NoError(t, err)
+	oc := conf.Route.Owner.OnCall
+	require.Equal(t, "sre", conf.Route.Owner.Name)
+	require.Equal(t, Secret("key"), oc.APIKey)
+	require.Equal(t, "https://api.opsgenie.com/", oc.APIURL.String())
+	require.Equal(t, DefaultOnCallScheduleConfig.Timeout, oc.Timeout)
+	require.Same(t, conf.Global.HTTPConfig, oc.HTTPConfig)
+	oc = conf.Route.Routes[0].Owner.OnCall
+	require.Equal(t, Secret("pd-key"), oc.APIKey)
+	require.Equal(t, "https://api.pagerduty.com/", oc.APIURL.String())
+
+	_, err = Load(strings.Replace(in, "            api_key: pd-key\n", "", 1))
+	require.EqualError(t, err, "line 16: route.routes[0].owner.on_call: missing api_key in PagerDuty on-call schedule config")
+
+	_, err = Load(strings.Replace(in, "provider: opsgenie", "provider: victorops", 1))
+	require.EqualError(t, err, `line 9: route.owner.on_call: unknown on-call provider "victorops", must be one of pagerduty, opsgenie`)
+
+	_, err = Load(`
+route:
+    receiver: team-X
+    owner: {}
 
-	expected := "root route must not have any matchers"
+receivers:
+- name: 'team-X'
+`)
+	require.EqualError(t, err, "line 4: route.owner: owner requires a name or an on-call schedule")
+}
 
-	if err == nil {
-		t.Fatalf("no error returned, expected:\n%q", expected)
+func TestDigestConfig(t *testing.T) {
+	in := `
+route:
+    receiver: team-X
+    routes:
+    - receiver: team-X
+      digest:
+`
+	conf, err := Load(in + `
+        schedule: 'CRON_TZ=Europe/Paris 0 9 * * *'
+
+receivers:
+- name: 'team-X'
+`)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
 	}
-	if err.Error() != expected {
-		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	d := conf.Route.Routes[0].Digest
+	require.NotNil(t, d.Cron)
+	loc, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	require.Equal(t, 9, d.Cron.Next(time.Now()).In(loc).Hour())
+
+	for _, tc := range []struct {
+		in  string
+		err string
+	}{
+		{
+			in:  "interval: 1d\n        schedule: '@daily'",
+			err: "line 7: route.routes[0].digest: exactly one of interval and schedule must be set in digest config",
+		},
+		{
+			in:  "schedule: '0 25 * * *'",
+			err: `line 7: route.routes[0].digest: invalid digest schedule "0 25 * * *": invalid hour field "25": value 25 out of range [0, 23]`,
+		},
+		{
+			in:  "schedule: '0 0 30 2 *'",
+			err: `line 7: route.routes[0].digest: digest schedule "0 0 30 2 *" never activates`,
+		},
+	} {
+		_, err := Load(in + "        " + tc.in + "\nreceivers:\n- name: 'team-X'\n")
+		require.EqualError(t, err, tc.err)
 	}
+}
+
+func TestUnroutedConfig(t *testing.T) {
+	conf, err := Load(`
+route:
+  receiver: team-X
+  unrouted:
+    action: fallback
+    fallback_receiver: team-Y
+
+receivers:
+- name: team-X
+- name: team-Y
+`)
+	require.NoError(t, err)
+	require.Equal(t, &UnroutedConfig{Action: UnroutedFallback, FallbackReceiver: "team-Y"}, conf.Route.Unrouted)
 
+	for _, tc := range []struct {
+		in  string
+		err string
+	}{
+		{
+			in:  "  unrouted:\n    action: ignore\n",
+			err: `line 4: route.unrouted: unknown unrouted action "ignore", must be root, drop, fallback or reject`,
+		},
+		{
+			in:  "  unrouted:\n    action: fallback\n",
+			err: "line 4: route.unrouted: fallback_receiver must be set if and only if the unrouted action is fallback",
+		},
+		{
+			in:  "  unrouted:\n    action: drop\n    fallback_receiver: team-X\n",
+			err: "line 4: route.unrouted: fallback_receiver must be set if and only if the unrouted action is fallback",
+		},
+		{
+			in:  "  unrouted:\n    action: fallback\n    fallback_receiver: team-Z\n",
+			err: `undefined fallback receiver "team-Z" used in root route`,
+		},
+		{
+			in:  "  routes:\n  - receiver: team-X\n    unrouted:\n      action: drop\n",
+			err: "unrouted can only be set on the root route",
+		},
+	} {
+		_, err := Load("route:\n  receiver: team-X\n" + tc.in + "receivers:\n- name: team-X\n")
+		require.EqualError(t, err, tc.err)
+	}
 }
 
-func TestContinueErrorInRouteRoot(t *testing.T) {
+func TestCredentials(t *testing.T) {
 	in := `
+global:
+  opsgenie_api_key: { credential: opsgenie }
+
+credentials:
+  pagerduty: 0123456789abcdef
+  opsgenie: opsgenie-key
+  slack: https://hooks.slack.com/services/ops
+
 route:
-    receiver: team-X-mails
-    continue: true
+  receiver: team-X
 
 receivers:
-- name: 'team-X-mails'
+- name: team-X
+  pagerduty_configs:
+  - routing_key: { credential: pagerduty }
+  opsgenie_configs:
+  - {}
+  slack_configs:
+  - api_url: { credential: slack }
+- name: team-Y
+  pagerduty_configs:
+  - routing_key: { credential: pagerduty }
 `
-	_, err := Load(in)
-
-	expected := "cannot have continue in root route"
-
-	if err == nil {
-		t.Fatalf("no error returned, expected:\n%q", expected)
-	}
-	if err.Error() != expected {
-		t.Errorf("\nexpected:\n%q\ngot:\n%q", expected, err.Error())
+	conf, err := Load(in)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", in, err)
 	}
+	require.Equal(t, Secret("0123456789abcdef"), conf.Receivers[0].PagerdutyConfigs[0].RoutingKey)
+	require.Equal(t, Secret("0123456789abcdef"), conf.Receivers[1].PagerdutyConfigs[0].RoutingKey)
+	require.Equal(t, Secret("opsgenie-key"), conf.Receivers[0].OpsGenieConfigs[0].APIKey)
+	require.Equal(t, "https://hooks.slack.com/services/ops", conf.Receivers[0].SlackConfigs[0].APIURL.String())
+	// The credentials aren't revealed.
+	require.NotContains(t, conf.String(), "0123456789abcdef")
 
+	for _, tc := range []struct {
+		in, err string
+	}{
+		{
+			in: `
+route:
+  receiver: team-X
+receivers:
+- name: team-X
+  pagerduty_configs:
+  - routing_key: { credential: pagerduty }
+`,
+			err: `undefined credential "pagerduty"`,
+		},
+		{
+			in: `
+credentials:
+  slack: not a url
+route:
+  receiver: team-X
+receivers:
+- name: team-X
+  slack_configs:
+  - api_url: { credential: slack }
+`,
+			err: `credential "slack": unsupported scheme "" for URL`,
+		},
+		{
+			in: `
+credentials:
+  a: { credential: b }
+  b: secret
+route:
+  receiver: team-X
+receivers:
+- name: team-X
+`,
+			err: `credential "a" references another credential`,
+		},
+	} {
+		_, err := Load(tc.in)
+		require.EqualError(t, err, tc.err)
+	}
 }
 
 func TestGroupIntervalIsGreaterThanZero(t *testing.T) {
@@ -414,7 +1881,7 @@ receivers:
 `
 	_, err := Load(in)
 
-	expected := "group_interval cannot be zero"
+	expected := "line 3: route: group_interval cannot be zero"
 
 	if err == nil {
 		t.Fatalf("no error returned, expected:\n%q", expected)
@@ -435,7 +1902,7 @@ receivers:
 `
 	_, err := Load(in)
 
-	expected := "repeat_interval cannot be zero"
+	expected := "line 3: route: repeat_interval cannot be zero"
 
 	if err == nil {
 		t.Fatalf("no error returned, expected:\n%q", expected)
@@ -794,6 +2261,7 @@ func TestEmptyFieldsAndRegex(t *testing.T) {
 			HTTPConfig: &commoncfg.HTTPClientConfig{
 				FollowRedirects: true,
 			},
+			HTTPTransport:   &DefaultHTTPTransportConfig,
 			ResolveTimeout:  model.Duration(5 * time.Minute),
 			SMTPSmarthost:   HostPort{Host: "localhost", Port: "25"},
 			SMTPFrom:        "alertmanager@example.org",
@@ -803,6 +2271,8 @@ func TestEmptyFieldsAndRegex(t *testing.T) {
 			OpsGenieAPIURL:  mustParseURL("https://api.opsgenie.com/"),
 			WeChatAPIURL:    mustParseURL("https://qyapi.weixin.qq.com/cgi-bin/"),
 			VictorOpsAPIURL: mustParseURL("https://alert.victorops.com/integrations/generic/20131114/alert/"),
+			SeverityLabel:   "severity",
+			LabelValidation: LabelValidationLegacy,
 		},
 
 		Templates: []string{
@@ -842,6 +2312,7 @@ func TestEmptyFieldsAndRegex(t *testing.T) {
 						RequireTLS: &boolFoo,
 					},
 				},
+				HTTPTransport: &DefaultHTTPTransportConfig,
 			},
 		},
 	}
@@ -1032,7 +2503,7 @@ func TestInvalidSNSConfig(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected error with missing fields on SNS config")
 	}
-	const expectedErr = `must provide either a Target ARN, Topic ARN, or Phone Number for SNS config`
+	const expectedErr = `line 8: receivers[0].sns_configs[0]: must provide either a Target ARN, Topic ARN, or Phone Number for SNS config`
 	if err.Error() != expectedErr {
 		t.Errorf("Expected: %s\nGot: %s", expectedErr, err.Error())
 	}
@@ -1076,6 +2547,17 @@ func TestUnmarshalHostPort(t *testing.T) {
 			in:  `"localhost:"`,
 			err: true,
 		},
+		{
+			in:  `"srv+_smtp._tcp.example.com"`,
+			exp: HostPort{Host: "srv+_smtp._tcp.example.com", Port: ""},
+			yamlOut: `srv+_smtp._tcp.example.com
+`,
+			jsonOut: `"srv+_smtp._tcp.example.com"`,
+		},
+		{
+			in:  `"srv+_smtp._tcp.example.com:25"`,
+			err: true,
+		},
 	} {
 		tc := tc
 		t.Run(tc.in, func(t *testing.T) {
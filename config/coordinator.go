@@ -16,6 +16,10 @@ package config
 import (
 	"crypto/md5"
 	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/go-kit/log"
@@ -33,10 +37,17 @@ type Coordinator struct {
 	mutex       sync.Mutex
 	config      *Config
 	subscribers []func(*Config) error
+	// overlays holds the last valid overlay of every tenant.
+	overlays map[string]string
+	// applied is the last configuration accepted by all subscribers and
+	// diff its difference with the configuration applied before it.
+	applied *Config
+	diff    *Diff
 
-	configHashMetric        prometheus.Gauge
-	configSuccessMetric     prometheus.Gauge
-	configSuccessTimeMetric prometheus.Gauge
+	configHashMetric           prometheus.Gauge
+	configSuccessMetric        prometheus.Gauge
+	configSuccessTimeMetric    prometheus.Gauge
+	tenantOverlaySuccessMetric *prometheus.GaugeVec
 }
 
 // NewCoordinator returns a new coordinator with the given configuration file
@@ -67,11 +78,17 @@ func (c *Coordinator) registerMetrics(r prometheus.Registerer) {
 		Help: "Timestamp of the last successful configuration reload.",
 	})
 
-	r.MustRegister(configHash, configSuccess, configSuccessTime)
+	tenantOverlaySuccess := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "alertmanager_config_tenant_overlay_last_reload_successful",
+		Help: "Whether the last reload attempt of the overlay of a tenant was successful.",
+	}, []string{"tenant"})
+
+	r.MustRegister(configHash, configSuccess, configSuccessTime, tenantOverlaySuccess)
 
 	c.configHashMetric = configHash
 	c.configSuccessMetric = configSuccess
 	c.configSuccessTimeMetric = configSuccessTime
+	c.tenantOverlaySuccessMetric = tenantOverlaySuccess
 }
 
 // Subscribe subscribes the given Subscribers to configuration changes.
@@ -99,11 +116,67 @@ func (c *Coordinator) loadFromFile() error {
 		return err
 	}
 
+	c.loadTenantOverlays(conf)
 	c.config = conf
 
 	return nil
 }
 
+// loadTenantOverlays merges the overlays of the tenants into the
+// configuration. The overlays are validated independently of each other: an
+// invalid overlay is replaced by the last valid overlay of its tenant, if
+// any, and never fails the reload.
+func (c *Coordinator) loadTenantOverlays(conf *Config) {
+	c.tenantOverlaySuccessMetric.Reset()
+	if conf.Tenancy == nil || conf.Tenancy.OverlaysDir == "" {
+		c.overlays = nil
+		return
+	}
+
+	dir := conf.Tenancy.OverlaysDir
+	var files []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Listing tenant overlays failed", "dir", dir, "err", err)
+			return
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	overlays := make(map[string]string, len(files))
+	for _, file := range files {
+		tenant := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if _, ok := overlays[tenant]; ok {
+			level.Error(c.logger).Log("msg", "Ignoring duplicate tenant overlay", "tenant", tenant, "file", file)
+			continue
+		}
+		content, err := ioutil.ReadFile(file)
+		if err == nil {
+			err = conf.AddTenantOverlay(tenant, string(content), dir)
+		}
+		if err == nil {
+			overlays[tenant] = string(content)
+			c.tenantOverlaySuccessMetric.WithLabelValues(tenant).Set(1)
+			continue
+		}
+		level.Error(c.logger).Log("msg", "Loading tenant overlay failed", "tenant", tenant, "file", file, "err", err)
+		c.tenantOverlaySuccessMetric.WithLabelValues(tenant).Set(0)
+		last, ok := c.overlays[tenant]
+		if !ok {
+			continue
+		}
+		if err := conf.AddTenantOverlay(tenant, last, dir); err != nil {
+			level.Error(c.logger).Log("msg", "Loading last valid tenant overlay failed", "tenant", tenant, "err", err)
+			continue
+		}
+		level.Warn(c.logger).Log("msg", "Keeping last valid tenant overlay", "tenant", tenant)
+		overlays[tenant] = last
+	}
+	c.overlays = overlays
+}
+
 // Reload triggers a configuration reload from file and notifies all
 // configuration change subscribers.
 func (c *Coordinator) Reload() error {
@@ -143,9 +216,49 @@ func (c *Coordinator) Reload() error {
 	hash := md5HashAsMetricValue([]byte(c.config.original))
 	c.configHashMetric.Set(hash)
 
+	if c.applied != nil {
+		c.diff = Compare(c.applied, c.config)
+		c.logDiff()
+	}
+	c.applied = c.config
+
 	return nil
 }
 
+func (c *Coordinator) logDiff() {
+	if c.diff.Empty() {
+		level.Info(c.logger).Log("msg", "Configuration unchanged", "file", c.configFilePath)
+		return
+	}
+	kvs := []interface{}{"msg", "Configuration changed", "file", c.configFilePath}
+	for _, f := range []struct {
+		key   string
+		names []string
+	}{
+		{"receivers_added", c.diff.ReceiversAdded},
+		{"receivers_removed", c.diff.ReceiversRemoved},
+		{"receivers_changed", c.diff.ReceiversChanged},
+		{"routes_added", c.diff.RoutesAdded},
+		{"routes_removed", c.diff.RoutesRemoved},
+		{"routes_changed", c.diff.RoutesChanged},
+		{"sections_changed", c.diff.SectionsChanged},
+	} {
+		if len(f.names) > 0 {
+			kvs = append(kvs, f.key, strings.Join(f.names, ","))
+		}
+	}
+	level.Info(c.logger).Log(kvs...)
+}
+
+// LastDiff returns the difference between the last two applied
+// configurations, or nil if the configuration was applied only once.
+func (c *Coordinator) LastDiff() *Diff {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.diff
+}
+
 func md5HashAsMetricValue(data []byte) float64 {
 	sum := md5.Sum(data)
 	// We only want 48 bits as a float64 only has a 53 bit mantissa.
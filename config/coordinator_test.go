@@ -15,10 +15,14 @@ package config
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
 )
 
 type fakeRegisterer struct {
@@ -81,3 +85,82 @@ func TestCoordinatorFailReloadWhenSubscriberFails(t *testing.T) {
 		t.Fatalf("expected error message %q but got %q", errMessage, err)
 	}
 }
+
+func TestCoordinatorTenantOverlays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overlays")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	write("alertmanager.yml", `
+route:
+    receiver: default
+receivers:
+- name: default
+tenancy:
+    overlays_dir: tenants
+`)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "tenants"), 0o755))
+	write("tenants/a.yml", "route:\n    receiver: team\nreceivers:\n- name: team\n")
+	write("tenants/b.yml", "route:\n    receiver: team\nreceivers:\n- name: team\n")
+
+	var conf *Config
+	c := NewCoordinator(filepath.Join(dir, "alertmanager.yml"), prometheus.NewRegistry(), log.NewNopLogger())
+	c.Subscribe(func(cfg *Config) error {
+		conf = cfg
+		return nil
+	})
+	receivers := func() []string {
+		var names []string
+		for _, rcv := range conf.Receivers {
+			names = append(names, rcv.Name)
+		}
+		return names
+	}
+
+	require.NoError(t, c.Reload())
+	require.Equal(t, []string{"default", "a/team", "b/team"}, receivers())
+
+	// A broken overlay keeps the last valid overlay of its tenant without
+	// affecting the other tenants.
+	write("tenants/a.yml", "route:\n    receiver: missing\n")
+	write("tenants/b.yml", "route:\n    receiver: oncall\nreceivers:\n- name: oncall\n")
+	write("tenants/c.yml", "route: {}\n")
+	require.NoError(t, c.Reload())
+	require.Equal(t, []string{"default", "a/team", "b/oncall"}, receivers())
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "tenants/a.yml")))
+	require.NoError(t, c.Reload())
+	require.Equal(t, []string{"default", "b/oncall"}, receivers())
+}
+
+func TestCoordinatorDiff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coordinator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "alertmanager.yml")
+	write := func(receiver string) {
+		require.NoError(t, ioutil.WriteFile(file, []byte(`
+route:
+  receiver: `+receiver+`
+receivers:
+- name: `+receiver+`
+`), 0o644))
+	}
+
+	c := NewCoordinator(file, prometheus.NewRegistry(), log.NewNopLogger())
+	write("team-X")
+	require.NoError(t, c.Reload())
+	require.Nil(t, c.LastDiff())
+
+	write("team-Y")
+	require.NoError(t, c.Reload())
+	require.Equal(t, &Diff{
+		ReceiversAdded:   []string{"team-Y"},
+		ReceiversRemoved: []string{"team-X"},
+		RoutesChanged:    []string{"{}"},
+	}, c.LastDiff())
+}
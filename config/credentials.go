@@ -0,0 +1,128 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// credentialRefPrefix marks the Secret values referencing a credential
+	// until they are resolved. It can't be written in a YAML plain scalar.
+	credentialRefPrefix = "\x00credential:"
+	// credentialRefScheme marks the SecretURL values referencing a
+	// credential until they are resolved.
+	credentialRefScheme = "credential"
+)
+
+var (
+	secretType    = reflect.TypeOf(Secret(""))
+	secretURLType = reflect.TypeOf(SecretURL{})
+)
+
+// unmarshalCredentialRef unmarshals a reference to a credential, written as
+// {credential: <name>}. It returns false if the input isn't one.
+func unmarshalCredentialRef(unmarshal func(interface{}) error) (string, bool) {
+	var ref struct {
+		Credential string `yaml:"credential"`
+	}
+	if err := unmarshal(&ref); err != nil || ref.Credential == "" {
+		return "", false
+	}
+	return ref.Credential, true
+}
+
+// resolveCredentials replaces the references to credentials of the Secret and
+// SecretURL values reachable from v with the credentials.
+func resolveCredentials(v reflect.Value, credentials map[string]Secret) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return resolveCredentials(v.Elem(), credentials)
+
+	case reflect.String:
+		if v.Type() != secretType || !strings.HasPrefix(v.String(), credentialRefPrefix) {
+			return nil
+		}
+		name := strings.TrimPrefix(v.String(), credentialRefPrefix)
+		cred, ok := credentials[name]
+		if !ok {
+			return fmt.Errorf("undefined credential %q", name)
+		}
+		if !v.CanSet() {
+			return fmt.Errorf("credential %q can't be referenced here", name)
+		}
+		v.SetString(string(cred))
+		return nil
+
+	case reflect.Struct:
+		if v.Type() == secretURLType && v.CanAddr() {
+			u := v.Addr().Interface().(*SecretURL)
+			if u.URL == nil || u.URL.Scheme != credentialRefScheme {
+				return nil
+			}
+			cred, ok := credentials[u.URL.Opaque]
+			if !ok {
+				return fmt.Errorf("undefined credential %q", u.URL.Opaque)
+			}
+			parsed, err := parseURL(string(cred))
+			if err != nil {
+				return fmt.Errorf("credential %q: %w", u.URL.Opaque, err)
+			}
+			u.URL = parsed.URL
+			return nil
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				// Unexported fields can't be set.
+				continue
+			}
+			if err := resolveCredentials(v.Field(i), credentials); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveCredentials(v.Index(i), credentials); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		// Map values aren't addressable, the resolved values are set
+		// back.
+		if v.Type().Elem() != secretType {
+			for _, k := range v.MapKeys() {
+				if err := resolveCredentials(v.MapIndex(k), credentials); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for _, k := range v.MapKeys() {
+			e := reflect.New(secretType).Elem()
+			e.Set(v.MapIndex(k))
+			if err := resolveCredentials(e, credentials); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, e)
+		}
+	}
+	return nil
+}
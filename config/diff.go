@@ -0,0 +1,173 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff is the structural difference between two configurations. It only
+// holds the names of the receivers, routes and sections that differ, never
+// their content, so that it does not reveal secrets.
+type Diff struct {
+	ReceiversAdded   []string `json:"receiversAdded,omitempty"`
+	ReceiversRemoved []string `json:"receiversRemoved,omitempty"`
+	ReceiversChanged []string `json:"receiversChanged,omitempty"`
+	// Routes are identified by the matchers of the route and of its parents,
+	// e.g. {}/{team="db"}.
+	RoutesAdded   []string `json:"routesAdded,omitempty"`
+	RoutesRemoved []string `json:"routesRemoved,omitempty"`
+	RoutesChanged []string `json:"routesChanged,omitempty"`
+	// SectionsChanged lists the other top-level sections which differ.
+	SectionsChanged []string `json:"sectionsChanged,omitempty"`
+}
+
+// Empty returns true if the configurations of the diff are equivalent.
+func (d *Diff) Empty() bool {
+	return len(d.ReceiversAdded) == 0 && len(d.ReceiversRemoved) == 0 && len(d.ReceiversChanged) == 0 &&
+		len(d.RoutesAdded) == 0 && len(d.RoutesRemoved) == 0 && len(d.RoutesChanged) == 0 &&
+		len(d.SectionsChanged) == 0
+}
+
+// Compare returns the structural difference between the old and the new
+// configuration.
+func Compare(old, new *Config) *Diff {
+	d := &Diff{}
+
+	oldReceivers := make(map[string]*Receiver, len(old.Receivers))
+	for _, r := range old.Receivers {
+		oldReceivers[r.Name] = r
+	}
+	newReceivers := make(map[string]*Receiver, len(new.Receivers))
+	for _, r := range new.Receivers {
+		newReceivers[r.Name] = r
+		o, ok := oldReceivers[r.Name]
+		switch {
+		case !ok:
+			d.ReceiversAdded = append(d.ReceiversAdded, r.Name)
+		case !reflect.DeepEqual(o, r):
+			d.ReceiversChanged = append(d.ReceiversChanged, r.Name)
+		}
+	}
+	for _, r := range old.Receivers {
+		if _, ok := newReceivers[r.Name]; !ok {
+			d.ReceiversRemoved = append(d.ReceiversRemoved, r.Name)
+		}
+	}
+
+	oldRoutes, newRoutes := map[string]*Route{}, map[string]*Route{}
+	var oldKeys, newKeys []string
+	if old.Route != nil {
+		walkRoutes(old.Route, routeKey(old.Route), func(k string, r *Route) {
+			oldRoutes[k] = r
+			oldKeys = append(oldKeys, k)
+		})
+	}
+	if new.Route != nil {
+		walkRoutes(new.Route, routeKey(new.Route), func(k string, r *Route) {
+			newRoutes[k] = r
+			newKeys = append(newKeys, k)
+		})
+	}
+	for _, k := range newKeys {
+		o, ok := oldRoutes[k]
+		switch {
+		case !ok:
+			d.RoutesAdded = append(d.RoutesAdded, k)
+		case !routeEqual(o, newRoutes[k]):
+			d.RoutesChanged = append(d.RoutesChanged, k)
+		}
+	}
+	for _, k := range oldKeys {
+		if _, ok := newRoutes[k]; !ok {
+			d.RoutesRemoved = append(d.RoutesRemoved, k)
+		}
+	}
+
+	ov, nv := reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem()
+	for i := 0; i < ov.NumField(); i++ {
+		f := ov.Type().Field(i)
+		if f.PkgPath != "" || f.Name == "Route" || f.Name == "Receivers" {
+			continue
+		}
+		if !reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			d.SectionsChanged = append(d.SectionsChanged, strings.Split(f.Tag.Get("yaml"), ",")[0])
+		}
+	}
+
+	return d
+}
+
+// walkRoutes calls fn for the route and all of its descendants in
+// depth-first order, along with the key identifying them.
+func walkRoutes(r *Route, key string, fn func(string, *Route)) {
+	fn(key, r)
+	for i, c := range r.Routes {
+		walkRoutes(c, key+"/"+childKey(r.Routes, i), fn)
+	}
+}
+
+// childKey returns the key of the i-th child route relative to its parent.
+// Siblings with the same matchers are told apart by their occurrence.
+func childKey(routes []*Route, i int) string {
+	k, n := routeKey(routes[i]), 1
+	for _, r := range routes[:i] {
+		if routeKey(r) == k {
+			n++
+		}
+	}
+	if n > 1 {
+		return fmt.Sprintf("%s#%d", k, n)
+	}
+	return k
+}
+
+// routeKey returns the matchers of the route in a stable order.
+func routeKey(r *Route) string {
+	var ms []string
+	for k, v := range r.Match {
+		ms = append(ms, fmt.Sprintf("%s=%q", k, v))
+	}
+	for k, v := range r.MatchRE {
+		ms = append(ms, fmt.Sprintf("%s=~%q", k, v.original))
+	}
+	for _, m := range r.Matchers {
+		ms = append(ms, m.String())
+	}
+	sort.Strings(ms)
+	return "{" + strings.Join(ms, ",") + "}"
+}
+
+// routeEqual returns true if the routes have the same settings and the same
+// children in the same order. The children themselves are compared
+// separately.
+func routeEqual(a, b *Route) bool {
+	ac, bc := *a, *b
+	ac.Routes, bc.Routes = nil, nil
+	if !reflect.DeepEqual(ac, bc) {
+		return false
+	}
+	if len(a.Routes) != len(b.Routes) {
+		return false
+	}
+	for i := range a.Routes {
+		if childKey(a.Routes, i) != childKey(b.Routes, i) {
+			return false
+		}
+	}
+	return true
+}
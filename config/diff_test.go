@@ -0,0 +1,82 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	old, err := Load(`
+route:
+  receiver: team-X
+  routes:
+  - matchers: ['team="db"']
+    receiver: team-Y
+  - match:
+      team: web
+    receiver: team-X
+  - match:
+      team: web
+    receiver: team-Y
+receivers:
+- name: team-X
+  webhook_configs:
+  - url: http://example.com/x
+- name: team-Y
+  webhook_configs:
+  - url: http://example.com/y
+- name: team-Z
+`)
+	require.NoError(t, err)
+
+	require.True(t, Compare(old, old).Empty())
+
+	new, err := Load(`
+route:
+  receiver: team-X
+  routes:
+  - matchers: ['team="db"']
+    receiver: team-X
+  - match:
+      team: web
+    receiver: team-X
+  - matchers: ['team="mail"']
+    receiver: team-W
+receivers:
+- name: team-W
+- name: team-X
+  webhook_configs:
+  - url: http://example.com/x
+- name: team-Y
+  webhook_configs:
+  - url: http://example.com/secret
+inhibit_rules:
+- source_matchers: ['severity="critical"']
+  target_matchers: ['severity="warning"']
+`)
+	require.NoError(t, err)
+
+	require.Equal(t, &Diff{
+		ReceiversAdded:   []string{"team-W"},
+		ReceiversRemoved: []string{"team-Z"},
+		ReceiversChanged: []string{"team-Y"},
+		RoutesAdded:      []string{`{}/{team="mail"}`},
+		RoutesRemoved:    []string{`{}/{team="web"}#2`},
+		RoutesChanged:    []string{`{}`, `{}/{team="db"}`},
+		SectionsChanged:  []string{"inhibit_rules"},
+	}, Compare(old, new))
+}
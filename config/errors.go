@@ -0,0 +1,296 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Error is an error in a configuration, located by the path of the offending
+// node of the YAML document, e.g. receivers[3].slack_configs[0], and its
+// position.
+type Error struct {
+	// Path is empty if the error concerns the configuration as a whole.
+	Path string `json:"path,omitempty"`
+	// Line and Column start at 1. They are 0 if unknown.
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", e.Line)
+	}
+	if e.Path != "" {
+		b.WriteString(e.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// typeError is a YAML type error along with its offending nodes.
+type typeError struct {
+	*yaml.TypeError
+	errs []*Error
+}
+
+func (e *typeError) Unwrap() error { return e.TypeError }
+
+// Errors returns the errors of a failed configuration load as a list, for
+// instance for tooling. A YAML type error is split into one error per
+// offending node.
+func Errors(err error) []*Error {
+	if err == nil {
+		return nil
+	}
+	var cerr *Error
+	if errors.As(err, &cerr) {
+		return []*Error{cerr}
+	}
+	var terr *typeError
+	if errors.As(err, &terr) && len(terr.errs) > 0 {
+		return terr.errs
+	}
+	return []*Error{{Message: err.Error()}}
+}
+
+// locate returns the error of unmarshaling the YAML document into out,
+// located at the deepest node which fails to unmarshal with the same error
+// on its own. Validation errors of a node which depend on other parts of the
+// document, such as references to receivers, are located at the closest
+// node validating them, which may be the document itself. Of several nodes
+// failing with the same error, the first one is located, as yaml.v2 reports
+// the first error in the order of the document.
+//
+// YAML type errors keep their message, and are located at every node which
+// fails to unmarshal with a type error while its children don't.
+func locate(s string, out interface{}, err error) error {
+	var doc yamlv3.Node
+	if yamlv3.Unmarshal([]byte(s), &doc) != nil || len(doc.Content) == 0 {
+		return err
+	}
+	root := expandAliases(doc.Content[0])
+
+	var terr *yaml.TypeError
+	if errors.As(err, &terr) {
+		return &typeError{TypeError: terr, errs: typeErrors(root, "", reflect.TypeOf(out))}
+	}
+	path, n := locateNode(root, "", reflect.TypeOf(out), err.Error())
+	if path == "" {
+		return err
+	}
+	return &Error{Path: path, Line: n.Line, Column: n.Column, Message: err.Error()}
+}
+
+// expandAliases returns the node with its aliases replaced by the nodes they
+// refer to, so that every node can be unmarshaled on its own.
+func expandAliases(n *yamlv3.Node) *yamlv3.Node {
+	if n.Kind == yamlv3.AliasNode && n.Alias != nil {
+		c := *expandAliases(n.Alias)
+		c.Anchor = ""
+		c.Line, c.Column = n.Line, n.Column
+		return &c
+	}
+	if len(n.Content) == 0 {
+		return n
+	}
+	c := *n
+	c.Anchor = ""
+	c.Content = make([]*yamlv3.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = expandAliases(child)
+	}
+	return &c
+}
+
+// unmarshalNode unmarshals the node on its own into a new value of type t.
+func unmarshalNode(n *yamlv3.Node, t reflect.Type, strict bool) error {
+	b, err := yamlv3.Marshal(n)
+	if err != nil {
+		return err
+	}
+	if strict {
+		return yaml.UnmarshalStrict(b, reflect.New(t).Interface())
+	}
+	return yaml.Unmarshal(b, reflect.New(t).Interface())
+}
+
+// children calls f with the path, the node and the type of the children of
+// n, of type t, which are unmarshaled into the corresponding fields, values
+// or elements.
+func children(n *yamlv3.Node, path string, t reflect.Type, f func(path string, key, c *yamlv3.Node, t reflect.Type)) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case n.Kind == yamlv3.MappingNode && (t.Kind() == reflect.Struct || t.Kind() == reflect.Map):
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, c := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			if t.Kind() == reflect.Map {
+				f(join(path, key.Value), key, c, t.Elem())
+				continue
+			}
+			if field, ok := yamlField(t, key.Value); ok {
+				f(join(path, key.Value), key, c, field.Type)
+			} else {
+				f(join(path, key.Value), key, nil, nil)
+			}
+		}
+	case n.Kind == yamlv3.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for i, c := range n.Content {
+			f(fmt.Sprintf("%s[%d]", path, i), nil, c, t.Elem())
+		}
+	}
+}
+
+// locateNode returns the path and the deepest node below n, of type t, whose
+// children of the corresponding types fail to unmarshal with msg.
+func locateNode(n *yamlv3.Node, path string, t reflect.Type, msg string) (string, *yamlv3.Node) {
+	var (
+		found     bool
+		foundPath string
+		foundNode *yamlv3.Node
+	)
+	children(n, path, t, func(p string, _, c *yamlv3.Node, ct reflect.Type) {
+		if found || c == nil {
+			return
+		}
+		if err := unmarshalNode(c, ct, false); err != nil && err.Error() == msg {
+			found = true
+			foundPath, foundNode = locateNode(c, p, ct, msg)
+		}
+	})
+	if found {
+		return foundPath, foundNode
+	}
+	return path, n
+}
+
+// typeErrors returns the errors of the nodes below n, of type t, which fail
+// to unmarshal with a type error while their children don't: unknown fields
+// and values of the wrong kind.
+func typeErrors(n *yamlv3.Node, path string, t reflect.Type) []*Error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var errs []*Error
+	children(n, path, t, func(p string, key, c *yamlv3.Node, ct reflect.Type) {
+		if c == nil {
+			errs = append(errs, &Error{
+				Path:    p,
+				Line:    key.Line,
+				Column:  key.Column,
+				Message: fmt.Sprintf("field %s not found in type %s", key.Value, t),
+			})
+			return
+		}
+		var terr *yaml.TypeError
+		if err := unmarshalNode(c, ct, true); errors.As(err, &terr) {
+			errs = append(errs, typeErrors(c, p, ct)...)
+		}
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	value := shortTag(n)
+	if n.Kind == yamlv3.ScalarNode {
+		value += " `" + n.Value + "`"
+	}
+	return []*Error{{
+		Path:    path,
+		Line:    n.Line,
+		Column:  n.Column,
+		Message: fmt.Sprintf("cannot unmarshal %s into %s", value, t),
+	}}
+}
+
+// shortTag returns the tag of the node as resolved by yaml.v2, which
+// unmarshals the configuration and, unlike yaml.v3, still resolves YAML 1.1
+// plain scalars such as yes to booleans.
+func shortTag(n *yamlv3.Node) string {
+	if n.Kind != yamlv3.ScalarNode || n.Style != 0 {
+		return n.ShortTag()
+	}
+	var v interface{}
+	if yaml.Unmarshal([]byte(n.Value), &v) != nil {
+		return n.ShortTag()
+	}
+	switch v.(type) {
+	case bool:
+		return "!!bool"
+	case int, int64, uint64:
+		return "!!int"
+	case float64:
+		return "!!float"
+	case nil:
+		return "!!null"
+	case string:
+		return "!!str"
+	}
+	return n.ShortTag()
+}
+
+// yamlField returns the field of the struct, or of its inlined structs, with
+// the given YAML key.
+func yamlField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(tag, ",inline") {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if f, ok := yamlField(ft, key); ok {
+					return f, true
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		if name == key {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
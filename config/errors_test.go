@@ -0,0 +1,174 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadErrorPosition(t *testing.T) {
+	in := `
+route:
+  receiver: team-X
+
+receivers:
+- name: team-X
+- name: team-Y
+  slack_configs:
+  - channel: '#alerts'
+    actions:
+    - type: button
+      text: Ack
+`
+	_, err := Load(in)
+	require.EqualError(t, err, "line 11: receivers[1].slack_configs[0].actions[0]: missing name or url in Slack action configuration")
+	require.Equal(t, []*Error{{
+		Path:    "receivers[1].slack_configs[0].actions[0]",
+		Line:    11,
+		Column:  7,
+		Message: "missing name or url in Slack action configuration",
+	}}, Errors(err))
+
+	// Errors of inlined fields are located at the node containing them.
+	_, err = Load(`
+route:
+  receiver: team-X
+
+receivers:
+- name: team-X
+  webhook_configs:
+  - send_resolved: false
+`)
+	require.EqualError(t, err, "line 8: receivers[0].webhook_configs[0]: missing URL in webhook config")
+
+	// Nodes referring to anchors are unmarshaled with the nodes of the
+	// anchors.
+	_, err = Load(`
+route:
+  receiver: team-X
+
+receivers:
+- name: team-X
+  webhook_configs:
+  - &hook
+    url: http://example.com/
+- name: team-Y
+  webhook_configs:
+  - *hook
+  - send_resolved: false
+`)
+	require.EqualError(t, err, "line 13: receivers[1].webhook_configs[1]: missing URL in webhook config")
+
+	// Errors depending on the whole configuration have no position.
+	_, err = Load(`
+route:
+  receiver: team-Z
+
+receivers:
+- name: team-X
+`)
+	require.EqualError(t, err, `undefined receiver "team-Z" used in route`)
+	require.Equal(t, []*Error{{Message: `undefined receiver "team-Z" used in route`}}, Errors(err))
+
+	// YAML type errors keep their message, and are split into one error
+	// per offending node.
+	_, err = Load(`
+route:
+  receiver: team-X
+  group_wait: [1m]
+
+receivers:
+- name: team-X
+  email_configs: yes
+  unknown: true
+`)
+	require.Contains(t, err.Error(), "yaml: unmarshal errors:")
+	require.Equal(t, []*Error{
+		{Path: "route.group_wait", Line: 4, Column: 15, Message: "cannot unmarshal !!seq into model.Duration"},
+		{Path: "receivers[0].email_configs", Line: 8, Column: 18, Message: "cannot unmarshal !!bool `yes` into []*config.EmailConfig"},
+		{Path: "receivers[0].unknown", Line: 9, Column: 3, Message: "field unknown not found in type config.Receiver"},
+	}, Errors(err))
+}
+
+// The errors are located by unmarshaling the nodes of the document on their
+// own and comparing their error with the error of the document. These tests
+// document the limits of this approach.
+func TestLoadErrorPositionLimits(t *testing.T) {
+	// Of several nodes failing with the same message, the first one is
+	// located, which is the one whose error is reported as the document is
+	// unmarshaled in order.
+	_, err := Load(`
+route:
+  receiver: team-X
+
+receivers:
+- name: team-X
+  webhook_configs:
+  - url: http://example.com/
+  - send_resolved: false
+- name: team-Y
+  webhook_configs:
+  - send_resolved: false
+`)
+	require.Equal(t, []*Error{{
+		Path:    "receivers[0].webhook_configs[1]",
+		Line:    9,
+		Column:  5,
+		Message: "missing URL in webhook config",
+	}}, Errors(err))
+
+	// Errors of nodes which only fail in the context of the document, such
+	// as references to undefined receivers or time intervals and settings
+	// defaulting to global ones, are validated by the document as a whole
+	// and have no position, even though a single node is at fault.
+	for _, in := range []string{`
+route:
+  receiver: team-X
+  routes:
+  - receiver: team-X
+    mute_time_intervals: [weekend]
+
+receivers:
+- name: team-X
+`, `
+route:
+  receiver: team-X
+
+receivers:
+- name: team-X
+- name: team-X
+`, `
+global:
+  smtp_from: alertmanager@example.com
+route:
+  receiver: team-X
+
+receivers:
+- name: team-X
+  email_configs:
+  - to: team-X@example.com
+    smarthost: smtp.example.com:25
+- name: team-Y
+  email_configs:
+  - to: team-Y@example.com
+`} {
+		_, err := Load(in)
+		require.Error(t, err)
+		errs := Errors(err)
+		require.Len(t, errs, 1)
+		require.Equal(t, &Error{Message: err.Error()}, errs[0])
+	}
+}
@@ -22,7 +22,10 @@ import (
 	"github.com/pkg/errors"
 
 	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/sigv4"
+
+	"github.com/prometheus/alertmanager/pkg/srv"
 )
 
 var (
@@ -31,6 +34,7 @@ var (
 		NotifierConfig: NotifierConfig{
 			VSendResolved: true,
 		},
+		PayloadVersion: 1,
 	}
 
 	// DefaultEmailConfig defines default values for Email configurations.
@@ -129,6 +133,155 @@ var (
 		HTML:     false,
 	}
 
+	// DefaultPluginConfig defines default values for plugin configurations.
+	DefaultPluginConfig = PluginConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Timeout: model.Duration(30 * time.Second),
+	}
+
+	// DefaultLocalLogConfig defines default values for local log
+	// configurations.
+	DefaultLocalLogConfig = LocalLogConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Identifier: "alertmanager",
+		Message: `{{ template "__subject" . }}
+{{ if gt (len .Alerts.Firing) 0 }}
+Alerts Firing:
+{{ template "__text_alert_list" .Alerts.Firing }}
+{{ end }}
+{{ if gt (len .Alerts.Resolved) 0 }}
+Alerts Resolved:
+{{ template "__text_alert_list" .Alerts.Resolved }}
+{{ end }}`,
+		Level:   "warning",
+		EventID: 1,
+	}
+
+	// DefaultStdoutConfig defines default values for stdout configurations.
+	DefaultStdoutConfig = StdoutConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+	}
+
+	// DefaultIncidentIOConfig defines default values for incident.io
+	// configurations.
+	DefaultIncidentIOConfig = IncidentIOConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Title:       `{{ template "__subject" . }}`,
+		Description: `{{ .CommonAnnotations.SortedPairs.Values | join " " }}`,
+		SourceURL:   `{{ template "__alertmanagerURL" . }}`,
+	}
+
+	// DefaultStatuspageConfig defines default values for Statuspage
+	// configurations.
+	DefaultStatuspageConfig = StatuspageConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		APIURL:          mustParseURL("https://api.statuspage.io/v1/"),
+		ComponentStatus: "major_outage",
+		IncidentName:    `{{ template "__subject" . }}`,
+		IncidentBody:    `{{ .CommonAnnotations.SortedPairs.Values | join " " }}`,
+	}
+
+	// DefaultGithubConfig defines default values for GitHub configurations.
+	DefaultGithubConfig = GithubConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		APIURL: mustParseURL("https://api.github.com/"),
+		Title:  `{{ template "__subject" . }}`,
+		Body: `{{ if gt (len .Alerts.Firing) 0 }}
+Alerts Firing:
+{{ template "__text_alert_list" .Alerts.Firing }}
+{{ end }}
+{{ if gt (len .Alerts.Resolved) 0 }}
+Alerts Resolved:
+{{ template "__text_alert_list" .Alerts.Resolved }}
+{{ end }}`,
+	}
+
+	// DefaultGrafanaConfig defines default values for Grafana
+	// configurations.
+	DefaultGrafanaConfig = GrafanaConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Text: `{{ template "__subject" . }}`,
+		Tags: []string{"alertmanager", `{{ .CommonLabels.alertname }}`},
+	}
+
+	// DefaultPushbulletConfig defines default values for Pushbullet
+	// configurations.
+	DefaultPushbulletConfig = PushbulletConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Title: `{{ template "__subject" . }}`,
+		Body:  `{{ .CommonAnnotations.SortedPairs.Values | join " " }}`,
+		URL:   `{{ template "__alertmanagerURL" . }}`,
+	}
+
+	// DefaultGotifyConfig defines default values for Gotify configurations.
+	DefaultGotifyConfig = GotifyConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Title:    `{{ template "__subject" . }}`,
+		Message:  `{{ .CommonAnnotations.SortedPairs.Values | join " " }}`,
+		Priority: `{{ if eq .Status "firing" }}8{{ else }}2{{ end }}`,
+	}
+
+	// DefaultNtfyConfig defines default values for ntfy configurations.
+	DefaultNtfyConfig = NtfyConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Title:    `{{ template "__subject" . }}`,
+		Message:  `{{ .CommonAnnotations.SortedPairs.Values | join " " }}`,
+		Priority: `{{ if eq .Status "firing" }}high{{ else }}default{{ end }}`,
+		Click:    `{{ template "__alertmanagerURL" . }}`,
+	}
+
+	// DefaultNSCAConfig defines default values for NSCA configurations.
+	DefaultNSCAConfig = NSCAConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Port:               5667,
+		Encryption:         "none",
+		HostName:           `{{ or .CommonLabels.instance "alertmanager" }}`,
+		ServiceDescription: `{{ .CommonLabels.alertname }}`,
+		Output:             `{{ template "__subject" . }}`,
+		State:              `{{ if eq .Status "firing" }}CRITICAL{{ else }}OK{{ end }}`,
+	}
+
+	// DefaultSplunkHECConfig defines default values for Splunk HEC
+	// configurations.
+	DefaultSplunkHECConfig = SplunkHECConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Source:     "alertmanager",
+		Sourcetype: "_json",
+	}
+
+	// DefaultElasticsearchConfig defines default values for Elasticsearch
+	// configurations.
+	DefaultElasticsearchConfig = ElasticsearchConfig{
+		NotifierConfig: NotifierConfig{
+			VSendResolved: true,
+		},
+		Index: "<alertmanager-{now/d}>",
+	}
+
 	// DefaultSNSConfig defines default values for SNS configurations.
 	DefaultSNSConfig = SNSConfig{
 		NotifierConfig: NotifierConfig{
@@ -141,13 +294,20 @@ var (
 
 // NotifierConfig contains base options common across all notifier configurations.
 type NotifierConfig struct {
-	VSendResolved bool `yaml:"send_resolved" json:"send_resolved"`
+	VSendResolved        bool   `yaml:"send_resolved" json:"send_resolved"`
+	VMaxAlertsPerMessage uint64 `yaml:"max_alerts_per_message,omitempty" json:"max_alerts_per_message,omitempty"`
 }
 
 func (nc *NotifierConfig) SendResolved() bool {
 	return nc.VSendResolved
 }
 
+// MaxAlertsPerMessage returns the maximum number of alerts sent in a single
+// message. 0 means no limit.
+func (nc *NotifierConfig) MaxAlertsPerMessage() uint64 {
+	return nc.VMaxAlertsPerMessage
+}
+
 // EmailConfig configures notifications via mail.
 type EmailConfig struct {
 	NotifierConfig `yaml:",inline" json:",inline"`
@@ -166,6 +326,53 @@ type EmailConfig struct {
 	Text         string              `yaml:"text,omitempty" json:"text,omitempty"`
 	RequireTLS   *bool               `yaml:"require_tls,omitempty" json:"require_tls,omitempty"`
 	TLSConfig    commoncfg.TLSConfig `yaml:"tls_config,omitempty" json:"tls_config,omitempty"`
+	// ProxyURL is the SOCKS5 proxy to connect to the smarthost through.
+	ProxyURL commoncfg.URL `yaml:"proxy_url,omitempty" json:"proxy_url,omitempty"`
+	// ConnectionIdleTimeout is how long SMTP connections are kept open after
+	// a message to be reused by the next ones. 0 closes them after each message.
+	ConnectionIdleTimeout model.Duration `yaml:"connection_idle_timeout,omitempty" json:"connection_idle_timeout,omitempty"`
+	// DKIM signs the messages.
+	DKIM *DKIMConfig `yaml:"dkim,omitempty" json:"dkim,omitempty"`
+}
+
+// DefaultDKIMConfig defines default values for DKIM configurations.
+var DefaultDKIMConfig = DKIMConfig{
+	Headers: []string{"From", "To", "Subject", "Date", "Message-Id", "Mime-Version", "Content-Type"},
+}
+
+// DKIMConfig configures the DKIM signatures of emails.
+type DKIMConfig struct {
+	Domain   string `yaml:"domain" json:"domain"`
+	Selector string `yaml:"selector" json:"selector"`
+	// PrivateKeyFile holds a PEM-encoded RSA or Ed25519 private key. It is
+	// read for every message, so that keys can be rotated.
+	PrivateKeyFile string `yaml:"private_key_file" json:"private_key_file"`
+	// Headers are the headers signed if present.
+	Headers []string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *DKIMConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultDKIMConfig
+	type plain DKIMConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Domain == "" {
+		return fmt.Errorf("missing domain in DKIM config")
+	}
+	if c.Selector == "" {
+		return fmt.Errorf("missing selector in DKIM config")
+	}
+	if c.PrivateKeyFile == "" {
+		return fmt.Errorf("missing private_key_file in DKIM config")
+	}
+	for _, h := range c.Headers {
+		if strings.EqualFold(h, "From") {
+			return nil
+		}
+	}
+	return fmt.Errorf("the headers of the DKIM config must include From")
 }
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
@@ -178,6 +385,9 @@ func (c *EmailConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if c.To == "" {
 		return fmt.Errorf("missing to address in email config")
 	}
+	if u := c.ProxyURL.URL; u != nil && u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return fmt.Errorf("unsupported scheme %q for proxy_url in email config, only socks5 and socks5h are supported", u.Scheme)
+	}
 	// Header names are case-insensitive, check for collisions.
 	normalizedHeaders := map[string]string{}
 	for h, v := range c.Headers {
@@ -211,6 +421,12 @@ type PagerdutyConfig struct {
 	Class       string            `yaml:"class,omitempty" json:"class,omitempty"`
 	Component   string            `yaml:"component,omitempty" json:"component,omitempty"`
 	Group       string            `yaml:"group,omitempty" json:"group,omitempty"`
+	// DedupKey is the template of the key identifying the incident of an
+	// alert group. It defaults to the hash of the group key.
+	DedupKey string `yaml:"dedup_key,omitempty" json:"dedup_key,omitempty"`
+
+	// SeverityMappings is set from the global configuration.
+	SeverityMappings *SeverityMappings `yaml:"-" json:"-"`
 }
 
 // PagerdutyLink is a link
@@ -391,8 +607,23 @@ type WebhookConfig struct {
 	// Alerts exceeding this threshold will be truncated. Setting this to 0
 	// allows an unlimited number of alerts.
 	MaxAlerts uint64 `yaml:"max_alerts" json:"max_alerts"`
+	// CloudEventsMode sends the messages as CloudEvents in the structured or
+	// binary content mode. Plain JSON messages are sent if empty.
+	CloudEventsMode string `yaml:"cloudevents_mode,omitempty" json:"cloudevents_mode,omitempty"`
+	// PayloadVersion selects the schema of the messages: 1 for the legacy
+	// messages of version "4", 2 for the messages of schema version "2".
+	PayloadVersion int `yaml:"payload_version,omitempty" json:"payload_version,omitempty"`
+	// ResponseControl honors the retry_after and drop fields of the JSON
+	// responses of the webhook.
+	ResponseControl bool `yaml:"response_control,omitempty" json:"response_control,omitempty"`
 }
 
+// The content modes of the CloudEvents sent by webhooks.
+const (
+	CloudEventsStructured = "structured"
+	CloudEventsBinary     = "binary"
+)
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
 func (c *WebhookConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	*c = DefaultWebhookConfig
@@ -406,6 +637,17 @@ func (c *WebhookConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if c.URL.Scheme != "https" && c.URL.Scheme != "http" {
 		return fmt.Errorf("scheme required for webhook url")
 	}
+	if srv.IsSRV(c.URL.Host) && c.URL.Port() != "" {
+		return fmt.Errorf("webhook url with an SRV record cannot have a port")
+	}
+	switch c.CloudEventsMode {
+	case "", CloudEventsStructured, CloudEventsBinary:
+	default:
+		return fmt.Errorf("unknown cloudevents_mode %q in webhook config, must be structured or binary", c.CloudEventsMode)
+	}
+	if c.PayloadVersion != 1 && c.PayloadVersion != 2 {
+		return fmt.Errorf("payload_version of webhook config must be 1 or 2")
+	}
 	return nil
 }
 
@@ -466,6 +708,9 @@ type OpsGenieConfig struct {
 	Note         string                    `yaml:"note,omitempty" json:"note,omitempty"`
 	Priority     string                    `yaml:"priority,omitempty" json:"priority,omitempty"`
 	UpdateAlerts bool                      `yaml:"update_alerts,omitempty" json:"update_alerts,omitempty"`
+
+	// SeverityMappings is set from the global configuration.
+	SeverityMappings *SeverityMappings `yaml:"-" json:"-"`
 }
 
 const opsgenieValidTypesRe = `^(team|user|escalation|schedule)$`
@@ -573,6 +818,9 @@ type PushoverConfig struct {
 	Retry    duration `yaml:"retry,omitempty" json:"retry,omitempty"`
 	Expire   duration `yaml:"expire,omitempty" json:"expire,omitempty"`
 	HTML     bool     `yaml:"html" json:"html,omitempty"`
+
+	// SeverityMappings is set from the global configuration.
+	SeverityMappings *SeverityMappings `yaml:"-" json:"-"`
 }
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
@@ -621,3 +869,491 @@ func (c *SNSConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	}
 	return nil
 }
+
+// PluginConfig configures notifications through an external program, which
+// receives every notification as JSON on its standard input.
+type PluginConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	// Command is the path of the program. Relative paths containing a
+	// slash are relative to the configuration file, and other names are
+	// looked up in PATH.
+	Command string   `yaml:"command" json:"command"`
+	Args    []string `yaml:"args,omitempty" json:"args,omitempty"`
+	// Settings are templated and passed to the program with the
+	// notification, as well as SecretSettings.
+	Settings       map[string]string `yaml:"settings,omitempty" json:"settings,omitempty"`
+	SecretSettings map[string]Secret `yaml:"secret_settings,omitempty" json:"secret_settings,omitempty"`
+	// Timeout is how long the program may run before it is killed.
+	Timeout model.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *PluginConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultPluginConfig
+	type plain PluginConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Command == "" {
+		return fmt.Errorf("missing command in plugin config")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout of plugin config must be greater than zero")
+	}
+	for k := range c.SecretSettings {
+		if _, ok := c.Settings[k]; ok {
+			return fmt.Errorf("setting %q of plugin config is both a setting and a secret setting", k)
+		}
+	}
+	return nil
+}
+
+// LocalLogConfig configures notifications written to the log of the host: the
+// systemd journal, or the Event Log on Windows.
+type LocalLogConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	// Identifier is the syslog identifier of the journal entries, or the
+	// source of the Windows events.
+	Identifier string `yaml:"identifier" json:"identifier"`
+	Message    string `yaml:"message,omitempty" json:"message,omitempty"`
+	// Level is the level of the firing notifications whose severity isn't
+	// mapped. Resolved notifications are logged at the info level.
+	Level string `yaml:"level,omitempty" json:"level,omitempty"`
+	// EventID is the ID of the Windows events.
+	EventID uint32 `yaml:"event_id,omitempty" json:"event_id,omitempty"`
+
+	// SeverityMappings is set from the global configuration.
+	SeverityMappings *SeverityMappings `yaml:"-" json:"-"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *LocalLogConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultLocalLogConfig
+	type plain LocalLogConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Identifier == "" {
+		return fmt.Errorf("missing identifier in local log config")
+	}
+	if !validLocalLogLevel(c.Level) {
+		return fmt.Errorf("invalid level %q in local log config, must be one of critical, error, warning or info", c.Level)
+	}
+	return nil
+}
+
+func validLocalLogLevel(l string) bool {
+	switch l {
+	case "critical", "error", "warning", "info":
+		return true
+	}
+	return false
+}
+
+// StdoutConfig configures notifications printed as JSON lines on the standard
+// output, for testing.
+type StdoutConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *StdoutConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultStdoutConfig
+	type plain StdoutConfig
+	return unmarshal((*plain)(c))
+}
+
+// IncidentIOConfig configures notifications sent as alert events to an HTTP
+// alert source of incident.io.
+type IncidentIOConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// URL is the URL of the alert source, which contains its ID.
+	URL   *SecretURL `yaml:"url" json:"url"`
+	Token Secret     `yaml:"token" json:"token"`
+
+	Title       string `yaml:"title,omitempty" json:"title,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	SourceURL   string `yaml:"source_url,omitempty" json:"source_url,omitempty"`
+	// Metadata holds templated attributes of the alert events.
+	Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *IncidentIOConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultIncidentIOConfig
+	type plain IncidentIOConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.URL == nil {
+		return fmt.Errorf("missing url in incident.io config")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("missing token in incident.io config")
+	}
+	return nil
+}
+
+// StatuspageConfig configures notifications changing the status of a
+// component of a Statuspage page, or opening incidents on it.
+type StatuspageConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	APIURL *URL   `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	APIKey Secret `yaml:"api_key" json:"api_key"`
+	PageID string `yaml:"page_id" json:"page_id"`
+
+	// ComponentID is templated, typically from a label of the alerts. The
+	// status of the component is set to ComponentStatus while the alerts
+	// fire and back to operational once they are resolved.
+	ComponentID     string `yaml:"component_id,omitempty" json:"component_id,omitempty"`
+	ComponentStatus string `yaml:"component_status,omitempty" json:"component_status,omitempty"`
+
+	// CreateIncident opens an incident while the alerts fire, which is
+	// resolved with them.
+	CreateIncident bool   `yaml:"create_incident,omitempty" json:"create_incident,omitempty"`
+	IncidentName   string `yaml:"incident_name,omitempty" json:"incident_name,omitempty"`
+	IncidentBody   string `yaml:"incident_body,omitempty" json:"incident_body,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *StatuspageConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultStatuspageConfig
+	type plain StatuspageConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.APIKey == "" {
+		return fmt.Errorf("missing api_key in Statuspage config")
+	}
+	if c.PageID == "" {
+		return fmt.Errorf("missing page_id in Statuspage config")
+	}
+	if c.ComponentID == "" && !c.CreateIncident {
+		return fmt.Errorf("one of component_id or create_incident must be configured in Statuspage config")
+	}
+	return nil
+}
+
+// GithubConfig configures notifications opening an issue of a GitHub
+// repository per alert group, which is closed once the group is resolved.
+type GithubConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	APIURL *URL   `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	Token  Secret `yaml:"token" json:"token"`
+	// Repo is the repository of the issues, as owner/name.
+	Repo string `yaml:"repo" json:"repo"`
+
+	Title string `yaml:"title,omitempty" json:"title,omitempty"`
+	Body  string `yaml:"body,omitempty" json:"body,omitempty"`
+	// Labels are set on the opened issues and narrow down the issues
+	// searched for the one of an alert group.
+	Labels    []string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Assignees []string `yaml:"assignees,omitempty" json:"assignees,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *GithubConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultGithubConfig
+	type plain GithubConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Token == "" {
+		return fmt.Errorf("missing token in GitHub config")
+	}
+	if parts := strings.Split(c.Repo, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid repo %q in GitHub config, must be owner/name", c.Repo)
+	}
+	return nil
+}
+
+// GrafanaConfig configures notifications annotating Grafana dashboards with
+// the time ranges during which alert groups fire.
+type GrafanaConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// APIURL is the URL of the Grafana server.
+	APIURL *URL   `yaml:"api_url" json:"api_url"`
+	Token  Secret `yaml:"token" json:"token"`
+
+	// DashboardUID and PanelID restrict the annotations to a dashboard or
+	// one of its panels. The annotations are global if empty.
+	DashboardUID string `yaml:"dashboard_uid,omitempty" json:"dashboard_uid,omitempty"`
+	PanelID      int    `yaml:"panel_id,omitempty" json:"panel_id,omitempty"`
+
+	Text string `yaml:"text,omitempty" json:"text,omitempty"`
+	// Tags are templated. Empty tags are left out.
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *GrafanaConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultGrafanaConfig
+	type plain GrafanaConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.APIURL == nil {
+		return fmt.Errorf("missing api_url in Grafana config")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("missing token in Grafana config")
+	}
+	if c.PanelID != 0 && c.DashboardUID == "" {
+		return fmt.Errorf("panel_id requires dashboard_uid in Grafana config")
+	}
+	return nil
+}
+
+// PushbulletConfig configures notifications pushed through Pushbullet.
+type PushbulletConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	AccessToken Secret `yaml:"access_token" json:"access_token"`
+
+	// At most one of DeviceIden, Email and ChannelTag targets the pushes.
+	// They are pushed to all the devices of the user otherwise.
+	DeviceIden string `yaml:"device_iden,omitempty" json:"device_iden,omitempty"`
+	Email      string `yaml:"email,omitempty" json:"email,omitempty"`
+	ChannelTag string `yaml:"channel_tag,omitempty" json:"channel_tag,omitempty"`
+
+	Title string `yaml:"title,omitempty" json:"title,omitempty"`
+	Body  string `yaml:"body,omitempty" json:"body,omitempty"`
+	// URL makes the pushes links if it isn't empty.
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *PushbulletConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultPushbulletConfig
+	type plain PushbulletConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.AccessToken == "" {
+		return fmt.Errorf("missing access token in Pushbullet config")
+	}
+	targets := 0
+	for _, t := range []string{c.DeviceIden, c.Email, c.ChannelTag} {
+		if t != "" {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return fmt.Errorf("at most one of device_iden, email & channel_tag must be configured in Pushbullet config")
+	}
+	return nil
+}
+
+// GotifyConfig configures notifications sent as messages of a Gotify
+// application.
+type GotifyConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// URL is the base URL of the Gotify server.
+	URL *URL `yaml:"url" json:"url"`
+	// Token is the token of the application the messages are sent as.
+	Token Secret `yaml:"token" json:"token"`
+
+	Title   string `yaml:"title,omitempty" json:"title,omitempty"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+	// Priority must render to an integer between 0 and 10.
+	Priority string `yaml:"priority,omitempty" json:"priority,omitempty"`
+
+	// SeverityMappings is set from the global configuration.
+	SeverityMappings *SeverityMappings `yaml:"-" json:"-"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *GotifyConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultGotifyConfig
+	type plain GotifyConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.URL == nil {
+		return fmt.Errorf("missing url in Gotify config")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("missing token in Gotify config")
+	}
+	return nil
+}
+
+// NtfyPriorities are the names of the priorities of ntfy, from the lowest to
+// the highest.
+var NtfyPriorities = []string{"min", "low", "default", "high", "urgent"}
+
+// NtfyConfig configures notifications published to a topic of an ntfy server.
+type NtfyConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// URL is the base URL of the ntfy server.
+	URL   *URL   `yaml:"url,omitempty" json:"url,omitempty"`
+	Topic string `yaml:"topic" json:"topic"`
+	// Token is the access token of protected topics.
+	Token Secret `yaml:"token,omitempty" json:"token,omitempty"`
+
+	Title   string `yaml:"title,omitempty" json:"title,omitempty"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+	// Priority must render to one of NtfyPriorities or an integer between 1
+	// and 5.
+	Priority string   `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Tags     []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Click is the URL opened when the notification is clicked.
+	Click string `yaml:"click,omitempty" json:"click,omitempty"`
+
+	// SeverityMappings is set from the global configuration.
+	SeverityMappings *SeverityMappings `yaml:"-" json:"-"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *NtfyConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultNtfyConfig
+	type plain NtfyConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.URL == nil {
+		c.URL = mustParseURL("https://ntfy.sh")
+	}
+	if c.Topic == "" {
+		return fmt.Errorf("missing topic in ntfy config")
+	}
+	return nil
+}
+
+// NSCAEncryptions are the encryption methods of NSCA supported by the NSCA
+// notifier.
+var NSCAEncryptions = []string{"none", "xor", "des", "3des", "aes"}
+
+// NSCAConfig configures notifications sent as passive check results to the
+// NSCA daemon of a Nagios or Icinga server.
+type NSCAConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	Host string `yaml:"host" json:"host"`
+	Port int    `yaml:"port,omitempty" json:"port,omitempty"`
+	// Encryption is one of NSCAEncryptions and must match the
+	// decryption_method of the daemon.
+	Encryption string `yaml:"encryption,omitempty" json:"encryption,omitempty"`
+	Password   Secret `yaml:"password,omitempty" json:"password,omitempty"`
+
+	HostName           string `yaml:"host_name,omitempty" json:"host_name,omitempty"`
+	ServiceDescription string `yaml:"service_description,omitempty" json:"service_description,omitempty"`
+	Output             string `yaml:"output,omitempty" json:"output,omitempty"`
+	// State must render to one of OK, WARNING, CRITICAL and UNKNOWN or
+	// their return codes.
+	State string `yaml:"state,omitempty" json:"state,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *NSCAConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultNSCAConfig
+	type plain NSCAConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Host == "" {
+		return fmt.Errorf("missing host in NSCA config")
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid port %d in NSCA config", c.Port)
+	}
+	if !containsString(NSCAEncryptions, c.Encryption) {
+		return fmt.Errorf("unknown encryption %q in NSCA config, must be one of %s", c.Encryption, strings.Join(NSCAEncryptions, ", "))
+	}
+	if c.Encryption != "none" && c.Password == "" {
+		return fmt.Errorf("missing password for %s encryption in NSCA config", c.Encryption)
+	}
+	return nil
+}
+
+// SplunkHECConfig configures notifications sent as events to the HTTP Event
+// Collector of Splunk.
+type SplunkHECConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// Endpoint is the URL of the event endpoint of the collector.
+	Endpoint *URL   `yaml:"endpoint" json:"endpoint"`
+	Token    Secret `yaml:"token" json:"token"`
+
+	Index      string `yaml:"index,omitempty" json:"index,omitempty"`
+	Source     string `yaml:"source,omitempty" json:"source,omitempty"`
+	Sourcetype string `yaml:"sourcetype,omitempty" json:"sourcetype,omitempty"`
+	// Event is the template of the event. If empty, the event is the JSON
+	// document sent by webhooks.
+	Event string `yaml:"event,omitempty" json:"event,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *SplunkHECConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultSplunkHECConfig
+	type plain SplunkHECConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Endpoint == nil {
+		return fmt.Errorf("missing endpoint in Splunk HEC config")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("missing token in Splunk HEC config")
+	}
+	return nil
+}
+
+// ElasticsearchConfig configures notifications indexed as documents in
+// Elasticsearch.
+type ElasticsearchConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// URLs are the nodes of the cluster, tried in turn.
+	URLs   []*URL `yaml:"urls" json:"urls"`
+	APIKey Secret `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	// Index is the name of the index, which may use the date math of
+	// Elasticsearch.
+	Index string `yaml:"index,omitempty" json:"index,omitempty"`
+	// Document is the template of the document, which must render to a
+	// JSON object. If empty, the document is the JSON document sent by
+	// webhooks.
+	Document string `yaml:"document,omitempty" json:"document,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *ElasticsearchConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultElasticsearchConfig
+	type plain ElasticsearchConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if len(c.URLs) == 0 {
+		return fmt.Errorf("missing urls in Elasticsearch config")
+	}
+	if c.Index == "" {
+		return fmt.Errorf("missing index in Elasticsearch config")
+	}
+	return nil
+}
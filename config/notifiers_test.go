@@ -57,6 +57,46 @@ headers:
 	}
 }
 
+func TestEmailProxyURLIsSOCKS5(t *testing.T) {
+	in := `
+to: 'to@email.com'
+proxy_url: http://proxy.example.com:3128
+`
+	var cfg EmailConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "unsupported scheme \"http\" for proxy_url in email config, only socks5 and socks5h are supported"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestEmailDKIMHeadersIncludeFrom(t *testing.T) {
+	in := `
+to: 'to@email.com'
+dkim:
+  domain: example.com
+  selector: alertmanager
+  private_key_file: /etc/alertmanager/dkim.key
+  headers: [To, Subject]
+`
+	var cfg EmailConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "the headers of the DKIM config must include From"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
 func TestPagerdutyRoutingKeyIsPresent(t *testing.T) {
 	in := `
 routing_key: ''
@@ -163,6 +203,23 @@ func TestWebhookURLIsPresent(t *testing.T) {
 	}
 }
 
+func TestWebhookSRVURLHasNoPort(t *testing.T) {
+	in := `
+url: 'http://srv+_alerts._tcp.example.com:8080/hook'
+`
+	var cfg WebhookConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "webhook url with an SRV record cannot have a port"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
 func TestWebhookHttpConfigIsValid(t *testing.T) {
 	in := `
 url: 'http://example.com'
@@ -195,6 +252,42 @@ url: 'http://example.com'
 	}
 }
 
+func TestWebhookCloudEventsModeIsValid(t *testing.T) {
+	in := `
+url: 'http://example.com'
+cloudevents_mode: batched
+`
+	var cfg WebhookConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := `unknown cloudevents_mode "batched" in webhook config, must be structured or binary`
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestWebhookPayloadVersionIsValid(t *testing.T) {
+	in := `
+url: 'http://example.com'
+payload_version: 3
+`
+	var cfg WebhookConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "payload_version of webhook config must be 1 or 2"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
 func TestWebhookPasswordIsObfuscated(t *testing.T) {
 	in := `
 url: 'http://example.com'
@@ -575,6 +668,292 @@ actions:
 	}
 }
 
+func TestPluginCommandIsPresent(t *testing.T) {
+	in := `{}`
+	var cfg PluginConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing command in plugin config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestPluginSettingsCollision(t *testing.T) {
+	in := `
+command: notify-chat
+settings:
+  token: foo
+secret_settings:
+  token: bar
+`
+	var cfg PluginConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "setting \"token\" of plugin config is both a setting and a secret setting"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestLocalLogLevel(t *testing.T) {
+	in := `
+level: debug
+`
+	var cfg LocalLogConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "invalid level \"debug\" in local log config, must be one of critical, error, warning or info"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestLocalLogIdentifierIsPresent(t *testing.T) {
+	in := `
+identifier: ''
+`
+	var cfg LocalLogConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing identifier in local log config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestIncidentIOTokenIsPresent(t *testing.T) {
+	in := `
+url: 'https://api.incident.io/v2/alert_events/http/source'
+`
+	var cfg IncidentIOConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing token in incident.io config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestStatuspageComponentOrIncidentIsPresent(t *testing.T) {
+	in := `
+api_key: key
+page_id: page
+`
+	var cfg StatuspageConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "one of component_id or create_incident must be configured in Statuspage config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestGithubRepo(t *testing.T) {
+	in := `
+token: secret
+repo: prometheus
+`
+	var cfg GithubConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "invalid repo \"prometheus\" in GitHub config, must be owner/name"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestGrafanaPanelRequiresDashboard(t *testing.T) {
+	in := `
+api_url: http://grafana:3000
+token: secret
+panel_id: 2
+`
+	var cfg GrafanaConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "panel_id requires dashboard_uid in Grafana config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestPushbulletAccessTokenIsPresent(t *testing.T) {
+	in := `
+channel_tag: oncall
+`
+	var cfg PushbulletConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing access token in Pushbullet config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestPushbulletTargetsAreExclusive(t *testing.T) {
+	in := `
+access_token: token
+device_iden: ujpah72o0sjAoRtnM0jc
+email: oncall@example.com
+`
+	var cfg PushbulletConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "at most one of device_iden, email & channel_tag must be configured in Pushbullet config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestGotifyTokenIsPresent(t *testing.T) {
+	in := `
+url: 'https://gotify.example.com'
+`
+	var cfg GotifyConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing token in Gotify config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestNtfyTopicIsPresent(t *testing.T) {
+	in := `
+url: 'https://ntfy.example.com'
+`
+	var cfg NtfyConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing topic in ntfy config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestNSCAHostIsPresent(t *testing.T) {
+	in := `
+port: 5667
+`
+	var cfg NSCAConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing host in NSCA config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestNSCAEncryption(t *testing.T) {
+	for in, expected := range map[string]string{
+		`
+host: nagios.example.com
+encryption: blowfish
+`: `unknown encryption "blowfish" in NSCA config, must be one of none, xor, des, 3des, aes`,
+		`
+host: nagios.example.com
+encryption: aes
+`: "missing password for aes encryption in NSCA config",
+	} {
+		var cfg NSCAConfig
+		err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+		if err == nil {
+			t.Fatalf("no error returned, expected:\n%v", expected)
+		}
+		if err.Error() != expected {
+			t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+		}
+	}
+}
+
+func TestSplunkHECTokenIsPresent(t *testing.T) {
+	in := `
+endpoint: 'https://splunk.example.com:8088/services/collector/event'
+`
+	var cfg SplunkHECConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing token in Splunk HEC config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestElasticsearchURLsArePresent(t *testing.T) {
+	in := `
+index: alertmanager
+`
+	var cfg ElasticsearchConfig
+	err := yaml.UnmarshalStrict([]byte(in), &cfg)
+
+	expected := "missing urls in Elasticsearch config"
+
+	if err == nil {
+		t.Fatalf("no error returned, expected:\n%v", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("\nexpected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
 func TestOpsgenieTypeMatcher(t *testing.T) {
 	good := []string{"team", "user", "escalation", "schedule"}
 	for _, g := range good {
@@ -0,0 +1,58 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Validation is the result of validating the receivers of a configuration
+// before applying it.
+type Validation struct {
+	Time      time.Time            `json:"time"`
+	Receivers []ReceiverValidation `json:"receivers"`
+}
+
+// ReceiverValidation is the result of validating the integrations of a
+// receiver.
+type ReceiverValidation struct {
+	Name         string                  `json:"name"`
+	Integrations []IntegrationValidation `json:"integrations"`
+}
+
+// IntegrationValidation is the result of validating an integration. Error
+// is empty if the integration is valid.
+type IntegrationValidation struct {
+	Integration string `json:"integration"`
+	Index       int    `json:"index"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Err returns an error listing the invalid integrations, or nil if all
+// integrations are valid.
+func (v *Validation) Err() error {
+	var errs []string
+	for _, r := range v.Receivers {
+		for _, i := range r.Integrations {
+			if i.Error != "" {
+				errs = append(errs, fmt.Sprintf("receiver %q: %s[%d]: %s", r.Name, i.Integration, i.Index, i.Error))
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid integrations: %s", strings.Join(errs, "; "))
+}
@@ -0,0 +1,524 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deadletter implements a garbage-collected and snapshottable store
+// of notifications that could not be delivered after all retries were
+// exhausted. Stored notifications can be listed and replayed once the
+// downstream provider has recovered.
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	uuid "github.com/gofrs/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/types"
+)
+
+// ErrNotFound is returned if an entry does not exist.
+var ErrNotFound = errors.New("dead letter not found")
+
+// Entry holds a notification that could not be delivered to an integration.
+type Entry struct {
+	// A unique identifier of the entry.
+	ID string `json:"id"`
+	// The name of the receiver and the integration the notification was
+	// destined for.
+	Receiver    string `json:"receiver"`
+	Integration string `json:"integration"`
+	Idx         uint32 `json:"idx"`
+	// The aggregation group the notification belongs to.
+	GroupKey    string         `json:"groupKey"`
+	GroupLabels model.LabelSet `json:"groupLabels"`
+	// The alerts that were part of the notification.
+	Alerts []*types.Alert `json:"alerts"`
+	// The last error returned by the integration.
+	Error string `json:"error"`
+	// The time the notification was given up on.
+	CreatedAt time.Time `json:"createdAt"`
+	// The number of replays that have been attempted and the last time one was
+	// attempted.
+	Replays      int       `json:"replays"`
+	LastReplayAt time.Time `json:"lastReplayAt"`
+}
+
+// Queue stores dead-lettered notifications.
+type Queue struct {
+	logger     log.Logger
+	metrics    *metrics
+	now        func() time.Time
+	retention  time.Duration
+	maxEntries int
+
+	mtx     sync.RWMutex
+	entries map[string]*Entry
+}
+
+// MaintenanceFunc represents the function to run as part of the periodic
+// maintenance for the queue. It returns the size of the snapshot taken or an
+// error if it failed.
+type MaintenanceFunc func() (int64, error)
+
+type metrics struct {
+	entries          prometheus.GaugeFunc
+	addedTotal       prometheus.Counter
+	droppedTotal     prometheus.Counter
+	replaysTotal     prometheus.Counter
+	replaysFailed    prometheus.Counter
+	gcDuration       prometheus.Summary
+	snapshotDuration prometheus.Summary
+	snapshotSize     prometheus.Gauge
+}
+
+func newMetrics(r prometheus.Registerer, q *Queue) *metrics {
+	m := &metrics{}
+
+	m.entries = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "alertmanager_deadletter_entries",
+		Help: "Number of notifications currently held in the dead-letter queue.",
+	}, func() float64 {
+		return float64(q.Len())
+	})
+	m.addedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "alertmanager_deadletter_added_total",
+		Help: "Total number of notifications added to the dead-letter queue.",
+	})
+	m.droppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "alertmanager_deadletter_dropped_total",
+		Help: "Total number of dead-lettered notifications dropped because the queue was full.",
+	})
+	m.replaysTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "alertmanager_deadletter_replays_total",
+		Help: "Total number of attempted replays of dead-lettered notifications.",
+	})
+	m.replaysFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "alertmanager_deadletter_replays_failed_total",
+		Help: "Total number of failed replays of dead-lettered notifications.",
+	})
+	m.gcDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "alertmanager_deadletter_gc_duration_seconds",
+		Help:       "Duration of the last dead-letter queue garbage collection cycle.",
+		Objectives: map[float64]float64{},
+	})
+	m.snapshotDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "alertmanager_deadletter_snapshot_duration_seconds",
+		Help:       "Duration of the last dead-letter queue snapshot.",
+		Objectives: map[float64]float64{},
+	})
+	m.snapshotSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "alertmanager_deadletter_snapshot_size_bytes",
+		Help: "Size of the last dead-letter queue snapshot in bytes.",
+	})
+
+	if r != nil {
+		r.MustRegister(
+			m.entries,
+			m.addedTotal,
+			m.droppedTotal,
+			m.replaysTotal,
+			m.replaysFailed,
+			m.gcDuration,
+			m.snapshotDuration,
+			m.snapshotSize,
+		)
+	}
+	return m
+}
+
+// Options exposes configuration options for creating a new Queue object.
+type Options struct {
+	// A snapshot file or reader from which the initial state is loaded.
+	// None or only one of them must be set.
+	SnapshotFile   string
+	SnapshotReader io.Reader
+
+	// Retention time for entries. Entries are garbage collected once they
+	// are older than the given duration.
+	Retention time.Duration
+	// The maximum number of entries held by the queue. Once reached, the
+	// oldest entry is dropped for every new one. The zero value means no
+	// limit.
+	MaxEntries int
+
+	// A logger used by background processing.
+	Logger  log.Logger
+	Metrics prometheus.Registerer
+}
+
+func (o *Options) validate() error {
+	if o.SnapshotFile != "" && o.SnapshotReader != nil {
+		return fmt.Errorf("only one of SnapshotFile and SnapshotReader must be set")
+	}
+	if o.MaxEntries < 0 {
+		return fmt.Errorf("max entries must not be negative")
+	}
+	return nil
+}
+
+// New returns a new Queue object with the given configuration.
+func New(o Options) (*Queue, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if o.SnapshotFile != "" {
+		if r, err := os.Open(o.SnapshotFile); err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		} else {
+			defer r.Close()
+			o.SnapshotReader = r
+		}
+	}
+	q := &Queue{
+		logger:     log.NewNopLogger(),
+		now:        utcNow,
+		retention:  o.Retention,
+		maxEntries: o.MaxEntries,
+		entries:    map[string]*Entry{},
+	}
+	q.metrics = newMetrics(o.Metrics, q)
+
+	if o.Logger != nil {
+		q.logger = o.Logger
+	}
+	if o.SnapshotReader != nil {
+		if err := q.loadSnapshot(o.SnapshotReader); err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
+
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+func cloneEntry(e *Entry) *Entry {
+	c := *e
+	return &c
+}
+
+// Add stores a new entry in the queue. The ID and creation time are set if
+// they are empty. It returns the ID of the stored entry.
+func (q *Queue) Add(e *Entry) (string, error) {
+	if e.Receiver == "" || e.Integration == "" {
+		return "", errors.New("receiver and integration must be set")
+	}
+	e = cloneEntry(e)
+
+	if e.ID == "" {
+		uid, err := uuid.NewV4()
+		if err != nil {
+			return "", fmt.Errorf("generate uuid: %w", err)
+		}
+		e.ID = uid.String()
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	now := q.now()
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = now
+	}
+	if q.maxEntries > 0 {
+		for len(q.entries) >= q.maxEntries {
+			q.dropOldest()
+		}
+	}
+	q.entries[e.ID] = e
+	q.metrics.addedTotal.Inc()
+
+	return e.ID, nil
+}
+
+// dropOldest removes the oldest entry. The caller must hold the lock.
+func (q *Queue) dropOldest() {
+	var oldest *Entry
+	for _, e := range q.entries {
+		if oldest == nil || e.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = e
+		}
+	}
+	if oldest == nil {
+		return
+	}
+	delete(q.entries, oldest.ID)
+	q.metrics.droppedTotal.Inc()
+	level.Warn(q.logger).Log("msg", "Dead-letter queue is full, dropping oldest entry", "id", oldest.ID, "receiver", oldest.Receiver)
+}
+
+// Get returns the entry with the given ID.
+func (q *Queue) Get(id string) (*Entry, error) {
+	q.mtx.RLock()
+	defer q.mtx.RUnlock()
+
+	e, ok := q.entries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneEntry(e), nil
+}
+
+// List returns all entries sorted by creation time, oldest first. If receiver
+// is not empty, only entries for this receiver are returned.
+func (q *Queue) List(receiver string) []*Entry {
+	q.mtx.RLock()
+	defer q.mtx.RUnlock()
+
+	res := make([]*Entry, 0, len(q.entries))
+	for _, e := range q.entries {
+		if receiver != "" && e.Receiver != receiver {
+			continue
+		}
+		res = append(res, cloneEntry(e))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].CreatedAt.Equal(res[j].CreatedAt) {
+			return res[i].ID < res[j].ID
+		}
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+	return res
+}
+
+// Len returns the number of entries in the queue.
+func (q *Queue) Len() int {
+	q.mtx.RLock()
+	defer q.mtx.RUnlock()
+
+	return len(q.entries)
+}
+
+// Delete removes the entry with the given ID.
+func (q *Queue) Delete(id string) error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if _, ok := q.entries[id]; !ok {
+		return ErrNotFound
+	}
+	delete(q.entries, id)
+	return nil
+}
+
+// Replay passes the entry with the given ID to the provided function. If it
+// succeeds, the entry is removed from the queue. Otherwise the entry is kept
+// with its error updated.
+func (q *Queue) Replay(id string, replay func(*Entry) error) error {
+	e, err := q.Get(id)
+	if err != nil {
+		return err
+	}
+	q.metrics.replaysTotal.Inc()
+	rerr := replay(e)
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	cur, ok := q.entries[id]
+	if rerr == nil {
+		if ok {
+			delete(q.entries, id)
+		}
+		return nil
+	}
+	q.metrics.replaysFailed.Inc()
+	if ok {
+		cur.Replays++
+		cur.LastReplayAt = q.now()
+		cur.Error = rerr.Error()
+	}
+	return rerr
+}
+
+// GC removes entries that were created longer than the configured retention
+// time ago.
+func (q *Queue) GC() (int, error) {
+	start := time.Now()
+	defer func() { q.metrics.gcDuration.Observe(time.Since(start).Seconds()) }()
+
+	if q.retention <= 0 {
+		return 0, nil
+	}
+	now := q.now()
+	var n int
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	for id, e := range q.entries {
+		if !e.CreatedAt.Add(q.retention).After(now) {
+			delete(q.entries, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Maintenance garbage collects the queue at the given interval. If the
+// snapshot file is set, a snapshot is written to it afterwards.
+// Terminates on receiving from stopc.
+// If not nil, the last argument is an override for what to do as part of the maintenance - for advanced usage.
+func (q *Queue) Maintenance(interval time.Duration, snapf string, stopc <-chan struct{}, override MaintenanceFunc) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	doMaintenance := func() (int64, error) {
+		var size int64
+
+		if _, err := q.GC(); err != nil {
+			return size, err
+		}
+		if snapf == "" {
+			return size, nil
+		}
+		f, err := openReplace(snapf)
+		if err != nil {
+			return size, err
+		}
+		if size, err = q.Snapshot(f); err != nil {
+			return size, err
+		}
+		return size, f.Close()
+	}
+
+	if override != nil {
+		doMaintenance = override
+	}
+
+	runMaintenance := func(do MaintenanceFunc) error {
+		start := q.now()
+		level.Debug(q.logger).Log("msg", "Running maintenance")
+		size, err := do()
+		level.Debug(q.logger).Log("msg", "Maintenance done", "duration", q.now().Sub(start), "size", size)
+		q.metrics.snapshotSize.Set(float64(size))
+		return err
+	}
+
+Loop:
+	for {
+		select {
+		case <-stopc:
+			break Loop
+		case <-t.C:
+			if err := runMaintenance(doMaintenance); err != nil {
+				level.Info(q.logger).Log("msg", "Running maintenance failed", "err", err)
+			}
+		}
+	}
+	// No need for final maintenance if we don't want to snapshot.
+	if snapf == "" {
+		return
+	}
+	if err := runMaintenance(doMaintenance); err != nil {
+		level.Info(q.logger).Log("msg", "Creating shutdown snapshot failed", "err", err)
+	}
+}
+
+// loadSnapshot loads a snapshot generated by Snapshot() into the state.
+func (q *Queue) loadSnapshot(r io.Reader) error {
+	entries := map[string]*Entry{}
+
+	dec := json.NewDecoder(r)
+	for {
+		var e Entry
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		if e.ID == "" {
+			return errors.New("invalid dead letter entry: missing ID")
+		}
+		entries[e.ID] = &e
+	}
+
+	q.mtx.Lock()
+	q.entries = entries
+	q.mtx.Unlock()
+
+	return nil
+}
+
+// Snapshot writes the full internal state into the writer and returns the number of bytes
+// written.
+func (q *Queue) Snapshot(w io.Writer) (int64, error) {
+	start := time.Now()
+	defer func() { q.metrics.snapshotDuration.Observe(time.Since(start).Seconds()) }()
+
+	cw := &countingWriter{w: w}
+	enc := json.NewEncoder(cw)
+	for _, e := range q.List("") {
+		if err := enc.Encode(e); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type replaceFile struct {
+	*os.File
+	filename string
+}
+
+func (f *replaceFile) Close() error {
+	if err := f.File.Sync(); err != nil {
+		return err
+	}
+	if err := f.File.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.File.Name(), f.filename)
+}
+
+// openReplace opens a new temporary file that is moved to filename on closing.
+func openReplace(filename string) (*replaceFile, error) {
+	tmpFilename := fmt.Sprintf("%s.%x", filename, uint64(rand.Int63()))
+
+	f, err := os.Create(tmpFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &replaceFile{
+		File:     f,
+		filename: filename,
+	}
+	return rf, nil
+}
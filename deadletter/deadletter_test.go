@@ -0,0 +1,209 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/types"
+)
+
+func newTestQueue(t *testing.T, o Options) *Queue {
+	t.Helper()
+
+	o.Metrics = prometheus.NewRegistry()
+	q, err := New(o)
+	require.NoError(t, err)
+	return q
+}
+
+func testEntry(receiver string) *Entry {
+	return &Entry{
+		Receiver:    receiver,
+		Integration: "webhook",
+		GroupKey:    "{}:{alertname=\"test\"}",
+		GroupLabels: model.LabelSet{"alertname": "test"},
+		Alerts: []*types.Alert{
+			{
+				Alert: model.Alert{
+					Labels:   model.LabelSet{"alertname": "test"},
+					StartsAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		Error: "connection refused",
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		options Options
+		err     string
+	}{
+		{
+			options: Options{SnapshotReader: &bytes.Buffer{}},
+		},
+		{
+			options: Options{SnapshotFile: "test.bkp"},
+		},
+		{
+			options: Options{SnapshotFile: "test.bkp", SnapshotReader: &bytes.Buffer{}},
+			err:     "only one of SnapshotFile and SnapshotReader must be set",
+		},
+		{
+			options: Options{MaxEntries: -1},
+			err:     "max entries must not be negative",
+		},
+	} {
+		err := tc.options.validate()
+		if tc.err == "" {
+			require.NoError(t, err)
+			continue
+		}
+		require.EqualError(t, err, tc.err)
+	}
+}
+
+func TestQueueAddGetDelete(t *testing.T) {
+	q := newTestQueue(t, Options{})
+
+	_, err := q.Add(&Entry{Receiver: "team-X"})
+	require.Error(t, err)
+
+	id, err := q.Add(testEntry("team-X"))
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	require.Equal(t, 1, q.Len())
+
+	e, err := q.Get(id)
+	require.NoError(t, err)
+	require.Equal(t, "team-X", e.Receiver)
+	require.False(t, e.CreatedAt.IsZero())
+
+	require.NoError(t, q.Delete(id))
+	require.Equal(t, ErrNotFound, q.Delete(id))
+	_, err = q.Get(id)
+	require.Equal(t, ErrNotFound, err)
+}
+
+func TestQueueList(t *testing.T) {
+	q := newTestQueue(t, Options{})
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	idX, err := q.Add(testEntry("team-X"))
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	idY, err := q.Add(testEntry("team-Y"))
+	require.NoError(t, err)
+
+	all := q.List("")
+	require.Len(t, all, 2)
+	require.Equal(t, idX, all[0].ID)
+	require.Equal(t, idY, all[1].ID)
+
+	y := q.List("team-Y")
+	require.Len(t, y, 1)
+	require.Equal(t, idY, y[0].ID)
+
+	require.Len(t, q.List("team-Z"), 0)
+}
+
+func TestQueueMaxEntries(t *testing.T) {
+	q := newTestQueue(t, Options{MaxEntries: 2})
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := q.Add(testEntry("team-X"))
+		require.NoError(t, err)
+		ids = append(ids, id)
+		now = now.Add(time.Minute)
+	}
+
+	require.Equal(t, 2, q.Len())
+	_, err := q.Get(ids[0])
+	require.Equal(t, ErrNotFound, err)
+}
+
+func TestQueueReplay(t *testing.T) {
+	q := newTestQueue(t, Options{})
+
+	id, err := q.Add(testEntry("team-X"))
+	require.NoError(t, err)
+
+	err = q.Replay(id, func(e *Entry) error {
+		require.Equal(t, id, e.ID)
+		return errors.New("still down")
+	})
+	require.EqualError(t, err, "still down")
+
+	e, err := q.Get(id)
+	require.NoError(t, err)
+	require.Equal(t, 1, e.Replays)
+	require.Equal(t, "still down", e.Error)
+	require.False(t, e.LastReplayAt.IsZero())
+
+	require.NoError(t, q.Replay(id, func(*Entry) error { return nil }))
+	require.Equal(t, 0, q.Len())
+
+	require.Equal(t, ErrNotFound, q.Replay(id, func(*Entry) error { return nil }))
+}
+
+func TestQueueGC(t *testing.T) {
+	q := newTestQueue(t, Options{Retention: time.Hour})
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	old, err := q.Add(testEntry("team-X"))
+	require.NoError(t, err)
+	now = now.Add(30 * time.Minute)
+	recent, err := q.Add(testEntry("team-X"))
+	require.NoError(t, err)
+
+	now = now.Add(45 * time.Minute)
+	n, err := q.GC()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	_, err = q.Get(old)
+	require.Equal(t, ErrNotFound, err)
+	_, err = q.Get(recent)
+	require.NoError(t, err)
+}
+
+func TestQueueSnapshot(t *testing.T) {
+	q := newTestQueue(t, Options{})
+
+	for _, r := range []string{"team-X", "team-Y"} {
+		_, err := q.Add(testEntry(r))
+		require.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+	n, err := q.Snapshot(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	q2 := newTestQueue(t, Options{SnapshotReader: &buf})
+	require.Equal(t, q.List(""), q2.List(""))
+}
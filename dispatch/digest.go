@@ -0,0 +1,68 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatch
+
+import (
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// maxDigestLookback bounds the search of the previous activation of a digest
+// schedule, in line with the horizon of cron.Schedule.Next.
+const maxDigestLookback = 5 * 365 * 24 * time.Hour
+
+// nextDigest returns the time of the first digest strictly after t, or the
+// zero time if there is none.
+func nextDigest(c *config.DigestConfig, t time.Time) time.Time {
+	if c.Cron != nil {
+		return c.Cron.Next(t)
+	}
+	d := time.Duration(c.Interval)
+	return t.Truncate(d).Add(d)
+}
+
+// prevDigest returns the time of the last digest strictly before t, or the
+// zero time if there is none.
+func prevDigest(c *config.DigestConfig, t time.Time) time.Time {
+	if c.Cron == nil {
+		d := time.Duration(c.Interval)
+		return t.Add(-1).Truncate(d)
+	}
+	// Cron expressions can only be evaluated forward: look back further and
+	// further until an activation is found, then walk up to t.
+	for step := time.Minute; step <= maxDigestLookback; step *= 2 {
+		p := c.Cron.Next(t.Add(-step))
+		if p.IsZero() || !p.Before(t) {
+			continue
+		}
+		for n := c.Cron.Next(p); !n.IsZero() && n.Before(t); n = c.Cron.Next(p) {
+			p = n
+		}
+		return p
+	}
+	return time.Time{}
+}
+
+// digestRepeatInterval returns the repeat interval applying to the digest
+// scheduled at t. It is half the time since the previous digest: replicas
+// sending the same digest deduplicate it, while the digests of two
+// consecutive activations are both sent even if the alerts didn't change.
+func digestRepeatInterval(c *config.DigestConfig, t time.Time) time.Duration {
+	prev := prevDigest(c, t)
+	if prev.IsZero() {
+		return 0
+	}
+	return t.Sub(prev) / 2
+}
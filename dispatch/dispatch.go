@@ -16,8 +16,11 @@ package dispatch
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"runtime"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -25,9 +28,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 
+	"github.com/prometheus/alertmanager/lifecycle"
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/provider"
 	"github.com/prometheus/alertmanager/store"
+	"github.com/prometheus/alertmanager/tracing"
 	"github.com/prometheus/alertmanager/types"
 )
 
@@ -36,8 +41,18 @@ type DispatcherMetrics struct {
 	aggrGroups            prometheus.Gauge
 	processingDuration    prometheus.Summary
 	aggrGroupLimitReached prometheus.Counter
+	aggrGroupAlerts       prometheus.Histogram
+	flushDuration         prometheus.Histogram
+	alertsDropped         *prometheus.CounterVec
+	alertsUnrouted        prometheus.Counter
 }
 
+// The reasons for which the dispatcher drops alerts.
+const (
+	dropReasonAggrGroupLimit = "aggregation_group_limit"
+	dropReasonUnrouted       = "unrouted"
+)
+
 // NewDispatcherMetrics returns a new registered DispatchMetrics.
 func NewDispatcherMetrics(registerLimitMetrics bool, r prometheus.Registerer) *DispatcherMetrics {
 	m := DispatcherMetrics{
@@ -59,10 +74,39 @@ func NewDispatcherMetrics(registerLimitMetrics bool, r prometheus.Registerer) *D
 				Help: "Number of times when dispatcher failed to create new aggregation group due to limit.",
 			},
 		),
+		aggrGroupAlerts: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "alertmanager_dispatcher_aggregation_group_alerts",
+				Help:    "Number of alerts of aggregation groups when they are flushed.",
+				Buckets: []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000},
+			},
+		),
+		flushDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "alertmanager_dispatcher_aggregation_group_flush_duration_seconds",
+				Help:    "Duration of the flushes of aggregation groups, until all their notifications were sent or given up on.",
+				Buckets: []float64{.01, .1, .5, 1, 5, 10, 30, 60, 120, 300},
+			},
+		),
+		alertsDropped: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "alertmanager_dispatcher_alerts_dropped_total",
+				Help: "Number of alerts which the dispatcher dropped instead of adding them to an aggregation group, by reason.",
+			},
+			[]string{"reason"},
+		),
+		alertsUnrouted: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "alertmanager_dispatcher_unrouted_alerts_total",
+				Help: "Number of alerts which matched none of the child routes of the root route.",
+			},
+		),
 	}
+	m.alertsDropped.WithLabelValues(dropReasonAggrGroupLimit)
+	m.alertsDropped.WithLabelValues(dropReasonUnrouted)
 
 	if r != nil {
-		r.MustRegister(m.aggrGroups, m.processingDuration)
+		r.MustRegister(m.aggrGroups, m.processingDuration, m.aggrGroupAlerts, m.flushDuration, m.alertsDropped, m.alertsUnrouted)
 		if registerLimitMetrics {
 			r.MustRegister(m.aggrGroupLimitReached)
 		}
@@ -71,21 +115,35 @@ func NewDispatcherMetrics(registerLimitMetrics bool, r prometheus.Registerer) *D
 	return &m
 }
 
+// shardQueueSize is the number of routed alerts buffered for each shard.
+const shardQueueSize = 1024
+
 // Dispatcher sorts incoming alerts into aggregation groups and
 // assigns the correct notifiers to each.
+//
+// The aggregation groups are sharded by the fingerprint of their group
+// labels. Each shard is owned by a worker goroutine inserting the alerts
+// routed to it, so that groups in different shards do not contend for the
+// same lock. All alerts of a group are processed by the same worker in the
+// order they were received.
 type Dispatcher struct {
 	route   *Route
 	alerts  provider.Alerts
 	stage   notify.Stage
 	metrics *DispatcherMetrics
 	limits  Limits
+	acks    Acknowledgements
+	events  lifecycle.Recorder
 
 	marker  types.Marker
 	timeout func(time.Duration) time.Duration
 
-	mtx                sync.RWMutex
-	aggrGroupsPerRoute map[*Route]map[model.Fingerprint]*aggrGroup
-	aggrGroupsNum      int
+	numShards     int
+	aggrGroupsNum int64
+
+	// mtx protects the shards and the context.
+	mtx    sync.RWMutex
+	shards []*dispatcherShard
 
 	done   chan struct{}
 	ctx    context.Context
@@ -94,6 +152,33 @@ type Dispatcher struct {
 	logger log.Logger
 }
 
+// dispatcherShard holds the aggregation groups of a shard. The groups are
+// only modified by the shard's worker.
+type dispatcherShard struct {
+	mtx                sync.RWMutex
+	aggrGroupsPerRoute map[*Route]map[model.Fingerprint]*aggrGroup
+
+	queue chan *routedAlert
+}
+
+// routedAlert is an alert matching a route, queued for insertion into the
+// aggregation group identified by the fingerprint of its group labels.
+type routedAlert struct {
+	alert       *types.Alert
+	route       *Route
+	groupLabels model.LabelSet
+	fp          model.Fingerprint
+
+	processing *alertProcessing
+}
+
+// alertProcessing tracks the processing of an alert inserted into the
+// aggregation groups of several routes, possibly by different workers.
+type alertProcessing struct {
+	start   time.Time
+	pending int32
+}
+
 // Limits describes limits used by Dispatcher.
 type Limits interface {
 	// MaxNumberOfAggregationGroups returns max number of aggregation groups that dispatcher can have.
@@ -102,19 +187,55 @@ type Limits interface {
 	MaxNumberOfAggregationGroups() int
 }
 
+// Acknowledgements tells whether aggregation groups were acknowledged.
+type Acknowledgements interface {
+	// Acknowledged returns true if the group with the given key is currently
+	// acknowledged. Acknowledged groups are not notified again because of
+	// their repeat interval and are not escalated.
+	Acknowledged(groupKey string) bool
+}
+
+// DispatcherOptions holds the optional settings of a Dispatcher.
+type DispatcherOptions struct {
+	// Timeout returns the timeout of the notifications of an aggregation
+	// group given its group interval. It defaults to the group interval.
+	Timeout func(time.Duration) time.Duration
+	// Limits limits the number of aggregation groups. There is no limit
+	// by default.
+	Limits Limits
+	// Acknowledgements tells which aggregation groups were acknowledged.
+	Acknowledgements Acknowledgements
+	// Events records the lifecycle events of the aggregation groups.
+	Events lifecycle.Recorder
+	// Shards is the number of workers the aggregation groups are sharded
+	// across. It defaults to GOMAXPROCS if it is not greater than zero.
+	Shards int
+}
+
 // NewDispatcher returns a new Dispatcher.
 func NewDispatcher(
 	ap provider.Alerts,
 	r *Route,
 	s notify.Stage,
 	mk types.Marker,
-	to func(time.Duration) time.Duration,
-	lim Limits,
+	o DispatcherOptions,
 	l log.Logger,
 	m *DispatcherMetrics,
 ) *Dispatcher {
-	if lim == nil {
-		lim = nilLimits{}
+	if o.Timeout == nil {
+		o.Timeout = func(d time.Duration) time.Duration { return d }
+	}
+	if o.Limits == nil {
+		o.Limits = nilLimits{}
+	}
+	if o.Acknowledgements == nil {
+		o.Acknowledgements = nilAcknowledgements{}
+	}
+	if o.Events == nil {
+		o.Events = nilRecorder{}
+	}
+	if o.Shards <= 0 {
+		o.Shards = runtime.GOMAXPROCS(0)
 	}
 
 	disp := &Dispatcher{
@@ -122,10 +243,14 @@ func NewDispatcher(
 		stage:   s,
 		route:   r,
 		marker:  mk,
-		timeout: to,
+		timeout: o.Timeout,
 		logger:  log.With(l, "component", "dispatcher"),
 		metrics: m,
-		limits:  lim,
+		limits:  o.Limits,
+		acks:    o.Acknowledgements,
+		events:  o.Events,
+
+		numShards: o.Shards,
 	}
 	return disp
 }
@@ -135,20 +260,38 @@ func (d *Dispatcher) Run() {
 	d.done = make(chan struct{})
 
 	d.mtx.Lock()
-	d.aggrGroupsPerRoute = map[*Route]map[model.Fingerprint]*aggrGroup{}
-	d.aggrGroupsNum = 0
+	d.shards = make([]*dispatcherShard, d.numShards)
+	for i := range d.shards {
+		d.shards[i] = &dispatcherShard{
+			aggrGroupsPerRoute: map[*Route]map[model.Fingerprint]*aggrGroup{},
+			queue:              make(chan *routedAlert, shardQueueSize),
+		}
+	}
+	atomic.StoreInt64(&d.aggrGroupsNum, 0)
 	d.metrics.aggrGroups.Set(0)
 	d.ctx, d.cancel = context.WithCancel(context.Background())
+	shards, ctx := d.shards, d.ctx
 	d.mtx.Unlock()
 
-	d.run(d.alerts.Subscribe())
+	var wg sync.WaitGroup
+	for _, sh := range shards {
+		wg.Add(1)
+		go func(sh *dispatcherShard) {
+			defer wg.Done()
+			d.runShard(ctx, sh)
+		}(sh)
+	}
+
+	d.run(ctx, shards, d.alerts.Subscribe())
+
+	for _, sh := range shards {
+		close(sh.queue)
+	}
+	wg.Wait()
 	close(d.done)
 }
 
-func (d *Dispatcher) run(it provider.AlertIterator) {
-	cleanup := time.NewTicker(30 * time.Second)
-	defer cleanup.Stop()
-
+func (d *Dispatcher) run(ctx context.Context, shards []*dispatcherShard, it provider.AlertIterator) {
 	defer it.Close()
 
 	for {
@@ -171,29 +314,76 @@ func (d *Dispatcher) run(it provider.AlertIterator) {
 			}
 
 			now := time.Now()
-			for _, r := range d.route.Match(alert.Labels) {
-				d.processAlert(alert, r)
+			routes := d.route.MatchAlert(alert)
+			if d.route.IsUnrouted(routes) {
+				d.metrics.alertsUnrouted.Inc()
+				if len(routes) == 0 {
+					d.metrics.alertsDropped.WithLabelValues(dropReasonUnrouted).Inc()
+					level.Debug(d.logger).Log("msg", "Dropping unrouted alert", "alert", alert)
+				}
+			}
+			if len(routes) == 0 {
+				d.metrics.processingDuration.Observe(time.Since(now).Seconds())
+				continue
+			}
+			// The processing of the alert is observed once it was
+			// inserted into the groups of all routes.
+			p := &alertProcessing{start: now, pending: int32(len(routes))}
+			for _, r := range routes {
+				groupLabels := getGroupLabels(alert, r)
+				fp := groupLabels.Fingerprint()
+
+				select {
+				case shards[uint64(fp)%uint64(len(shards))].queue <- &routedAlert{
+					alert:       alert,
+					route:       r,
+					groupLabels: groupLabels,
+					fp:          fp,
+					processing:  p,
+				}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runShard inserts the alerts queued for the shard into their aggregation
+// groups and periodically removes the empty groups of the shard.
+func (d *Dispatcher) runShard(ctx context.Context, sh *dispatcherShard) {
+	cleanup := time.NewTicker(30 * time.Second)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case ra, ok := <-sh.queue:
+			if !ok {
+				return
+			}
+			d.processAlert(ctx, sh, ra)
+			if atomic.AddInt32(&ra.processing.pending, -1) == 0 {
+				d.metrics.processingDuration.Observe(time.Since(ra.processing.start).Seconds())
 			}
-			d.metrics.processingDuration.Observe(time.Since(now).Seconds())
 
 		case <-cleanup.C:
-			d.mtx.Lock()
+			sh.mtx.Lock()
 
-			for _, groups := range d.aggrGroupsPerRoute {
+			for _, groups := range sh.aggrGroupsPerRoute {
 				for _, ag := range groups {
 					if ag.empty() {
 						ag.stop()
 						delete(groups, ag.fingerprint())
-						d.aggrGroupsNum--
+						atomic.AddInt64(&d.aggrGroupsNum, -1)
 						d.metrics.aggrGroups.Dec()
 					}
 				}
 			}
 
-			d.mtx.Unlock()
-
-		case <-d.ctx.Done():
-			return
+			sh.mtx.Unlock()
 		}
 	}
 }
@@ -203,6 +393,10 @@ type AlertGroup struct {
 	Alerts   types.AlertSlice
 	Labels   model.LabelSet
 	Receiver string
+	GroupKey string
+	// NextFlush is the time at which the group is next flushed to its
+	// receiver.
+	NextFlush time.Time
 }
 
 type AlertGroups []*AlertGroup
@@ -221,7 +415,8 @@ func (d *Dispatcher) Groups(routeFilter func(*Route) bool, alertFilter func(*typ
 	groups := AlertGroups{}
 
 	d.mtx.RLock()
-	defer d.mtx.RUnlock()
+	shards := d.shards
+	d.mtx.RUnlock()
 
 	// Keep a list of receivers for an alert to prevent checking each alert
 	// again against all routes. The alert has already matched against this
@@ -229,7 +424,33 @@ func (d *Dispatcher) Groups(routeFilter func(*Route) bool, alertFilter func(*typ
 	receivers := map[model.Fingerprint][]string{}
 
 	now := time.Now()
-	for route, ags := range d.aggrGroupsPerRoute {
+	for _, sh := range shards {
+		sh.mtx.RLock()
+		groups = d.shardGroups(sh, routeFilter, alertFilter, now, groups, receivers)
+		sh.mtx.RUnlock()
+	}
+	sort.Sort(groups)
+	for i := range groups {
+		sort.Sort(groups[i].Alerts)
+	}
+	for i := range receivers {
+		sort.Strings(receivers[i])
+	}
+
+	return groups, receivers
+}
+
+// shardGroups appends the alert groups of the shard to groups and records
+// the receivers of their alerts. The shard must be read-locked.
+func (d *Dispatcher) shardGroups(
+	sh *dispatcherShard,
+	routeFilter func(*Route) bool,
+	alertFilter func(*types.Alert, time.Time) bool,
+	now time.Time,
+	groups AlertGroups,
+	receivers map[model.Fingerprint][]string,
+) AlertGroups {
+	for route, ags := range sh.aggrGroupsPerRoute {
 		if !routeFilter(route) {
 			continue
 		}
@@ -237,8 +458,10 @@ func (d *Dispatcher) Groups(routeFilter func(*Route) bool, alertFilter func(*typ
 		for _, ag := range ags {
 			receiver := route.RouteOpts.Receiver
 			alertGroup := &AlertGroup{
-				Labels:   ag.labels,
-				Receiver: receiver,
+				Labels:    ag.labels,
+				Receiver:  receiver,
+				GroupKey:  ag.GroupKey(),
+				NextFlush: ag.nextFlushTime(),
 			}
 
 			alerts := ag.alerts.List()
@@ -269,15 +492,63 @@ func (d *Dispatcher) Groups(routeFilter func(*Route) bool, alertFilter func(*typ
 			groups = append(groups, alertGroup)
 		}
 	}
-	sort.Sort(groups)
-	for i := range groups {
-		sort.Sort(groups[i].Alerts)
+	return groups
+}
+
+// Drain immediately flushes the aggregation groups with pending alerts,
+// including those still in their group wait, so that no change is lost, and
+// waits for their notifications to finish or for the context to be done.
+// Groups notifying digests are not flushed. It is meant to be called before
+// stopping the dispatcher on shutdown, once no new alerts are received.
+func (d *Dispatcher) Drain(ctx context.Context) {
+	if d == nil {
+		return
 	}
-	for i := range receivers {
-		sort.Strings(receivers[i])
+	d.mtx.RLock()
+	shards := d.shards
+	d.mtx.RUnlock()
+
+	var ags []*aggrGroup
+	for _, sh := range shards {
+		sh.mtx.RLock()
+		for _, groups := range sh.aggrGroupsPerRoute {
+			for _, ag := range groups {
+				ag.mtx.RLock()
+				if !ag.empty() && ag.opts.Digest == nil {
+					ags = append(ags, ag)
+				}
+				ag.mtx.RUnlock()
+			}
+		}
+		sh.mtx.RUnlock()
 	}
 
-	return groups, receivers
+	var wg sync.WaitGroup
+	for _, ag := range ags {
+		wg.Add(1)
+		go func(ag *aggrGroup) {
+			defer wg.Done()
+			done := make(chan struct{})
+			select {
+			case ag.drain <- done:
+			case <-ag.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-done:
+			case <-ag.done:
+			case <-ctx.Done():
+			}
+		}(ag)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		level.Warn(d.logger).Log("msg", "Draining aggregation groups did not finish", "groups", len(ags), "err", err)
+		return
+	}
+	level.Info(d.logger).Log("msg", "Drained aggregation groups", "groups", len(ags))
 }
 
 // Stop the dispatcher.
@@ -302,20 +573,18 @@ func (d *Dispatcher) Stop() {
 // Returns false iff notifying failed.
 type notifyFunc func(context.Context, ...*types.Alert) bool
 
-// processAlert determines in which aggregation group the alert falls
-// and inserts it.
-func (d *Dispatcher) processAlert(alert *types.Alert, route *Route) {
-	groupLabels := getGroupLabels(alert, route)
+// processAlert inserts the alert into its aggregation group in the shard,
+// creating the group if it does not exist yet.
+func (d *Dispatcher) processAlert(ctx context.Context, sh *dispatcherShard, ra *routedAlert) {
+	alert, route, fp := ra.alert, ra.route, ra.fp
 
-	fp := groupLabels.Fingerprint()
+	sh.mtx.Lock()
+	defer sh.mtx.Unlock()
 
-	d.mtx.Lock()
-	defer d.mtx.Unlock()
-
-	routeGroups, ok := d.aggrGroupsPerRoute[route]
+	routeGroups, ok := sh.aggrGroupsPerRoute[route]
 	if !ok {
 		routeGroups = map[model.Fingerprint]*aggrGroup{}
-		d.aggrGroupsPerRoute[route] = routeGroups
+		sh.aggrGroupsPerRoute[route] = routeGroups
 	}
 
 	ag, ok := routeGroups[fp]
@@ -325,15 +594,18 @@ func (d *Dispatcher) processAlert(alert *types.Alert, route *Route) {
 	}
 
 	// If the group does not exist, create it. But check the limit first.
-	if limit := d.limits.MaxNumberOfAggregationGroups(); limit > 0 && d.aggrGroupsNum >= limit {
+	// The limit is shared by all shards.
+	num := atomic.AddInt64(&d.aggrGroupsNum, 1)
+	if limit := d.limits.MaxNumberOfAggregationGroups(); limit > 0 && num > int64(limit) {
+		atomic.AddInt64(&d.aggrGroupsNum, -1)
 		d.metrics.aggrGroupLimitReached.Inc()
-		level.Error(d.logger).Log("msg", "Too many aggregation groups, cannot create new group for alert", "groups", d.aggrGroupsNum, "limit", limit, "alert", alert.Name())
+		d.metrics.alertsDropped.WithLabelValues(dropReasonAggrGroupLimit).Inc()
+		level.Error(d.logger).Log("msg", "Too many aggregation groups, cannot create new group for alert", "groups", num-1, "limit", limit, "alert", alert.Name())
 		return
 	}
 
-	ag = newAggrGroup(d.ctx, groupLabels, route, d.timeout, d.logger)
+	ag = newAggrGroup(ctx, ra.groupLabels, route, d.timeout, d.acks, d.events, d.logger)
 	routeGroups[fp] = ag
-	d.aggrGroupsNum++
 	d.metrics.aggrGroups.Inc()
 
 	// Insert the 1st alert in the group before starting the group's run()
@@ -342,14 +614,25 @@ func (d *Dispatcher) processAlert(alert *types.Alert, route *Route) {
 	ag.insert(alert)
 
 	go ag.run(func(ctx context.Context, alerts ...*types.Alert) bool {
-		_, _, err := d.stage.Exec(ctx, d.logger, alerts...)
+		ctx, span := tracing.Start(ctx, "dispatch.flush", tracing.SpanKindInternal,
+			"group_key", ag.GroupKey(),
+			"receiver", route.RouteOpts.Receiver,
+			"alerts", len(alerts),
+		)
+		defer span.End()
+
+		d.metrics.aggrGroupAlerts.Observe(float64(len(alerts)))
+		start := time.Now()
+		_, _, err := d.stage.Exec(ctx, ag.logger, alerts...)
+		d.metrics.flushDuration.Observe(time.Since(start).Seconds())
+		span.RecordError(err)
 		if err != nil {
-			lvl := level.Error(d.logger)
+			lvl := level.Error(ag.logger)
 			if ctx.Err() == context.Canceled {
 				// It is expected for the context to be canceled on
 				// configuration reload or shutdown. In this case, the
 				// message should only be logged at the debug level.
-				lvl = level.Debug(d.logger)
+				lvl = level.Debug(ag.logger)
 			}
 			lvl.Log("msg", "Notify for alerts failed", "num_alerts", len(alerts), "err", err)
 		}
@@ -383,39 +666,90 @@ type aggrGroup struct {
 	done    chan struct{}
 	next    *time.Timer
 	timeout func(time.Duration) time.Duration
+	acks    Acknowledgements
+	events  lifecycle.Recorder
+	resend  chan resendRequest
+	drain   chan chan struct{}
 
 	mtx        sync.RWMutex
 	hasFlushed bool
+	// nextFlush is the time at which the next timer fires.
+	nextFlush time.Time
+
+	// escalation fires when the next escalation step is due. It is nil if the
+	// route has no escalation steps.
+	escalation *time.Timer
+	// firstNotified is the time of the first notification about the
+	// currently firing alerts and escalated the number of escalation steps
+	// taken since.
+	firstNotified time.Time
+	escalated     int
+
+	// flaps detects flapping alerts. It is nil if flap detection is disabled.
+	flaps *flapDetector
 }
 
 // newAggrGroup returns a new aggregation group.
-func newAggrGroup(ctx context.Context, labels model.LabelSet, r *Route, to func(time.Duration) time.Duration, logger log.Logger) *aggrGroup {
+func newAggrGroup(ctx context.Context, labels model.LabelSet, r *Route, to func(time.Duration) time.Duration, acks Acknowledgements, events lifecycle.Recorder, logger log.Logger) *aggrGroup {
 	if to == nil {
 		to = func(d time.Duration) time.Duration { return d }
 	}
+	if acks == nil {
+		acks = nilAcknowledgements{}
+	}
+	if events == nil {
+		events = nilRecorder{}
+	}
 	ag := &aggrGroup{
 		labels:   labels,
 		routeKey: r.Key(),
 		opts:     &r.RouteOpts,
 		timeout:  to,
+		acks:     acks,
+		events:   events,
 		alerts:   store.NewAlerts(),
 		done:     make(chan struct{}),
+		resend:   make(chan resendRequest),
+		drain:    make(chan chan struct{}),
+		flaps:    newFlapDetector(&r.RouteOpts),
 	}
 	ag.ctx, ag.cancel = context.WithCancel(ctx)
 
 	ag.logger = log.With(logger, "aggrGroup", ag)
 
 	// Set an initial one-time wait before flushing
-	// the first batch of notifications.
-	ag.next = time.NewTimer(ag.opts.GroupWait)
+	// the first batch of notifications. Groups of digest routes are only
+	// flushed at the times of the digests.
+	now := time.Now()
+	ag.nextFlush = now.Add(ag.opts.GroupWait + jitter(ag.opts.GroupWaitJitter))
+	if ag.opts.Digest != nil {
+		ag.nextFlush = nextDigest(ag.opts.Digest, now)
+	}
+	ag.next = time.NewTimer(ag.nextFlush.Sub(now))
+
+	if len(ag.opts.Escalation) > 0 {
+		ag.escalation = time.NewTimer(0)
+		stopTimer(ag.escalation)
+	}
 
 	return ag
 }
 
+// stopTimer stops the timer and drains its channel.
+func stopTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+}
+
 func (ag *aggrGroup) fingerprint() model.Fingerprint {
 	return ag.labels.Fingerprint()
 }
 
+// GroupKey returns the key of the group, see notify.Key for its format.
 func (ag *aggrGroup) GroupKey() string {
 	return fmt.Sprintf("%s:%s", ag.routeKey, ag.labels)
 }
@@ -428,36 +762,33 @@ func (ag *aggrGroup) run(nf notifyFunc) {
 	defer close(ag.done)
 	defer ag.next.Stop()
 
+	var escalationC <-chan time.Time
+	if ag.escalation != nil {
+		defer ag.escalation.Stop()
+		escalationC = ag.escalation.C
+	}
+
 	for {
 		select {
 		case now := <-ag.next.C:
-			// Give the notifications time until the next flush to
-			// finish before terminating them.
-			ctx, cancel := context.WithTimeout(ag.ctx, ag.timeout(ag.opts.GroupInterval))
-
 			// The now time we retrieve from the ticker is the only reliable
 			// point of time reference for the subsequent notification pipeline.
 			// Calculating the current time directly is prone to flaky behavior,
 			// which usually only becomes apparent in tests.
-			ctx = notify.WithNow(ctx, now)
-
-			// Populate context with information needed along the pipeline.
-			ctx = notify.WithGroupKey(ctx, ag.GroupKey())
-			ctx = notify.WithGroupLabels(ctx, ag.labels)
-			ctx = notify.WithReceiverName(ctx, ag.opts.Receiver)
-			ctx = notify.WithRepeatInterval(ctx, ag.opts.RepeatInterval)
-			ctx = notify.WithMuteTimeIntervals(ctx, ag.opts.MuteTimeIntervals)
+			ag.flushAt(now, nf)
 
-			// Wait the configured interval before calling flush again.
-			ag.mtx.Lock()
-			ag.next.Reset(ag.opts.GroupInterval)
-			ag.hasFlushed = true
-			ag.mtx.Unlock()
+		case done := <-ag.drain:
+			ag.flushAt(time.Now(), nf)
+			close(done)
 
-			ag.flush(func(alerts ...*types.Alert) bool {
-				return nf(ctx, alerts...)
-			})
+		case now := <-escalationC:
+			ctx, cancel := context.WithTimeout(ag.ctx, ag.timeout(ag.opts.GroupInterval))
+			ag.escalate(ctx, now, nf)
+			cancel()
 
+		case req := <-ag.resend:
+			ctx, cancel := context.WithTimeout(ag.ctx, ag.timeout(ag.opts.GroupInterval))
+			req.done <- ag.resendTo(ctx, time.Now(), req.receiver, nf)
 			cancel()
 
 		case <-ag.ctx.Done():
@@ -466,6 +797,147 @@ func (ag *aggrGroup) run(nf notifyFunc) {
 	}
 }
 
+// flushAt flushes the group at the given time and schedules its next flush.
+func (ag *aggrGroup) flushAt(now time.Time, nf notifyFunc) {
+	// Give the notifications time until the next flush to
+	// finish before terminating them.
+	ctx, cancel := context.WithTimeout(ag.ctx, ag.timeout(ag.opts.GroupInterval))
+	defer cancel()
+
+	ctx = ag.notifyContext(ctx, now, ag.opts.Receiver)
+
+	// Wait the configured interval, or until the next digest,
+	// before calling flush again.
+	ag.mtx.Lock()
+	if ag.opts.Digest != nil {
+		// Digests are sent even if the alerts didn't change since
+		// the previous one.
+		ctx = notify.WithRepeatInterval(ctx, digestRepeatInterval(ag.opts.Digest, ag.nextFlush))
+		ctx = notify.WithDigest(ctx, true)
+		ag.nextFlush = nextDigest(ag.opts.Digest, now)
+	} else {
+		ag.nextFlush = now.Add(ag.opts.GroupInterval + jitter(ag.opts.GroupIntervalJitter))
+	}
+	ag.next.Reset(ag.nextFlush.Sub(now))
+	ag.hasFlushed = true
+	ag.mtx.Unlock()
+
+	ag.flush(func(alerts ...*types.Alert) bool {
+		ok := nf(ctx, alerts...)
+		// Receivers the group was escalated to are kept up to date
+		// until all alerts are resolved.
+		for _, rcv := range ag.escalatedReceivers() {
+			ok = nf(notify.WithReceiverName(ctx, rcv), alerts...) && ok
+		}
+		ag.updateEscalation(now, ok, alerts)
+		return ok
+	})
+}
+
+// notifyContext populates the context with information needed along the
+// notification pipeline.
+func (ag *aggrGroup) notifyContext(ctx context.Context, now time.Time, receiver string) context.Context {
+	ctx = notify.WithNow(ctx, now)
+	ctx = notify.WithGroupKey(ctx, ag.GroupKey())
+	ctx = notify.WithGroupLabels(ctx, ag.labels)
+	ctx = notify.WithReceiverName(ctx, receiver)
+	ctx = notify.WithRepeatInterval(ctx, ag.opts.RepeatInterval)
+	ctx = notify.WithMuteTimeIntervals(ctx, ag.opts.MuteTimeIntervals)
+	ctx = notify.WithAcknowledged(ctx, ag.acks.Acknowledged(ag.GroupKey()))
+	ctx = notify.WithLabelRelabelConfigs(ctx, ag.opts.LabelRelabelConfigs)
+	ctx = notify.WithAnnotationRelabelConfigs(ctx, ag.opts.AnnotationRelabelConfigs)
+	if ag.opts.Enrichment != nil {
+		ctx = notify.WithEnrichment(ctx, ag.opts.Enrichment)
+	}
+	if ag.opts.Owner != nil {
+		ctx = notify.WithOwner(ctx, ag.opts.Owner)
+	}
+	return ctx
+}
+
+// escalatedReceivers returns the receivers of the escalation steps taken so
+// far.
+func (ag *aggrGroup) escalatedReceivers() []string {
+	ag.mtx.RLock()
+	defer ag.mtx.RUnlock()
+
+	var res []string
+	for _, e := range ag.opts.Escalation[:ag.escalated] {
+		res = append(res, e.Receiver)
+	}
+	return res
+}
+
+// updateEscalation starts the escalation timer on the first notification
+// about firing alerts, and resets the escalation once all alerts were
+// notified as resolved.
+func (ag *aggrGroup) updateEscalation(now time.Time, notified bool, alerts []*types.Alert) {
+	if ag.escalation == nil {
+		return
+	}
+	ag.mtx.Lock()
+	defer ag.mtx.Unlock()
+
+	if types.Alerts(alerts...).Status() == model.AlertFiring {
+		if ag.firstNotified.IsZero() {
+			ag.firstNotified = now
+			ag.escalation.Reset(ag.opts.Escalation[0].After)
+		}
+		return
+	}
+	if notified {
+		stopTimer(ag.escalation)
+		ag.firstNotified = time.Time{}
+		ag.escalated = 0
+	}
+}
+
+// escalate notifies the receiver of the next escalation step about the firing
+// alerts of the group and schedules the step after it.
+func (ag *aggrGroup) escalate(ctx context.Context, now time.Time, nf notifyFunc) {
+	ag.mtx.Lock()
+	if ag.firstNotified.IsZero() || ag.escalated >= len(ag.opts.Escalation) {
+		ag.mtx.Unlock()
+		return
+	}
+	// Acknowledged groups are not escalated. Check again later as the
+	// acknowledgement might end while the group is still firing.
+	if ag.acks.Acknowledged(ag.GroupKey()) {
+		ag.escalation.Reset(ag.opts.GroupInterval)
+		ag.mtx.Unlock()
+		return
+	}
+	step := ag.opts.Escalation[ag.escalated]
+	ag.escalated++
+	if ag.escalated < len(ag.opts.Escalation) {
+		ag.escalation.Reset(ag.firstNotified.Add(ag.opts.Escalation[ag.escalated].After).Sub(now))
+	}
+	ag.mtx.Unlock()
+
+	firing := ag.firingAlerts(now)
+	if len(firing) == 0 {
+		return
+	}
+
+	level.Info(ag.logger).Log("msg", "Escalating alert group", "receiver", step.Receiver, "after", step.After, "alerts", len(firing))
+	nf(ag.notifyContext(ctx, now, step.Receiver), firing...)
+}
+
+// firingAlerts returns the alerts of the group firing at the given time,
+// except the flapping ones, sorted.
+func (ag *aggrGroup) firingAlerts(now time.Time) types.AlertSlice {
+	var firing types.AlertSlice
+	for _, a := range ag.alerts.List() {
+		if !a.ResolvedAt(now) && !ag.flaps.flapping(a.Fingerprint()) {
+			c := *a
+			c.EndsAt = time.Time{}
+			firing = append(firing, &c)
+		}
+	}
+	sort.Stable(firing)
+	return firing
+}
+
 func (ag *aggrGroup) stop() {
 	// Calling cancel will terminate all in-process notifications
 	// and the run() loop.
@@ -475,17 +947,44 @@ func (ag *aggrGroup) stop() {
 
 // insert inserts the alert into the aggregation group.
 func (ag *aggrGroup) insert(alert *types.Alert) {
+	if ag.flaps.observe(alert, time.Now()) {
+		level.Info(ag.logger).Log("msg", "Alert is flapping, holding its notifications", "alert", alert.String())
+	}
+	_, err := ag.alerts.Get(alert.Fingerprint())
+	grouped := err != nil
 	if err := ag.alerts.Set(alert); err != nil {
 		level.Error(ag.logger).Log("msg", "error on set alert", "err", err)
+	} else if grouped {
+		ag.record(lifecycle.EventGrouped, alert)
 	}
 
 	// Immediately trigger a flush if the wait duration for this
-	// alert is already over.
+	// alert is already over, within the jitter of the group wait.
 	ag.mtx.Lock()
 	defer ag.mtx.Unlock()
-	if !ag.hasFlushed && alert.StartsAt.Add(ag.opts.GroupWait).Before(time.Now()) {
-		ag.next.Reset(0)
+	now := time.Now()
+	if !ag.hasFlushed && ag.opts.Digest == nil && alert.StartsAt.Add(ag.opts.GroupWait).Before(now) {
+		next := now.Add(jitter(ag.opts.GroupWaitJitter))
+		if ag.opts.GroupWaitJitter == 0 || next.Before(ag.nextFlush) {
+			ag.next.Reset(next.Sub(now))
+			ag.nextFlush = next
+		}
+	}
+}
+
+// jitter returns a random duration between 0 and max.
+var jitter = func(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
 	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// nextFlushTime returns the time at which the group is next flushed.
+func (ag *aggrGroup) nextFlushTime() time.Time {
+	ag.mtx.RLock()
+	defer ag.mtx.RUnlock()
+	return ag.nextFlush
 }
 
 func (ag *aggrGroup) empty() bool {
@@ -504,13 +1003,28 @@ func (ag *aggrGroup) flush(notify func(...*types.Alert) bool) {
 		now         = time.Now()
 	)
 	for _, alert := range alerts {
+		hold, flapping := ag.flaps.hold(alert, now)
+		if hold {
+			continue
+		}
 		a := *alert
 		// Ensure that alerts don't resolve as time move forwards.
 		if !a.ResolvedAt(now) {
 			a.EndsAt = time.Time{}
 		}
+		if flapping {
+			a.Annotations = a.Annotations.Clone()
+			a.Annotations[FlappingAnnotation] = "true"
+		}
 		alertsSlice = append(alertsSlice, &a)
 	}
+	ag.flaps.gc(now, func(fp model.Fingerprint) bool {
+		_, err := ag.alerts.Get(fp)
+		return err == nil
+	})
+	if len(alertsSlice) == 0 {
+		return
+	}
 	sort.Stable(alertsSlice)
 
 	level.Debug(ag.logger).Log("msg", "flushing", "alerts", fmt.Sprintf("%v", alertsSlice))
@@ -529,12 +1043,34 @@ func (ag *aggrGroup) flush(notify func(...*types.Alert) bool) {
 			if a.Resolved() && got.UpdatedAt == a.UpdatedAt {
 				if err := ag.alerts.Delete(fp); err != nil {
 					level.Error(ag.logger).Log("msg", "error on delete alert", "err", err, "alert", a.String())
+				} else {
+					ag.record(lifecycle.EventResolved, a)
 				}
 			}
 		}
 	}
 }
 
+// record records an event of the given type about the alert in the group.
+func (ag *aggrGroup) record(t lifecycle.EventType, a *types.Alert) {
+	if !ag.events.Enabled(t) {
+		return
+	}
+	e := lifecycle.NewEvent(t, a)
+	e.GroupKey = ag.GroupKey()
+	e.Receiver = ag.opts.Receiver
+	ag.events.Record(e)
+}
+
 type nilLimits struct{}
 
 func (n nilLimits) MaxNumberOfAggregationGroups() int { return 0 }
+
+type nilAcknowledgements struct{}
+
+func (n nilAcknowledgements) Acknowledged(string) bool { return false }
+
+type nilRecorder struct{}
+
+func (n nilRecorder) Enabled(lifecycle.EventType) bool { return false }
+func (n nilRecorder) Record(lifecycle.Event)           {}
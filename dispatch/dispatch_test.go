@@ -15,10 +15,12 @@ package dispatch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -27,8 +29,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/lifecycle"
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/provider/mem"
 	"github.com/prometheus/alertmanager/types"
@@ -138,7 +142,7 @@ func TestAggrGroup(t *testing.T) {
 	}
 
 	// Test regular situation where we wait for group_wait to send out alerts.
-	ag := newAggrGroup(context.Background(), lset, route, nil, log.NewNopLogger())
+	ag := newAggrGroup(context.Background(), lset, route, nil, nil, nil, log.NewNopLogger())
 	go ag.run(ntfy)
 
 	ag.insert(a1)
@@ -192,7 +196,7 @@ func TestAggrGroup(t *testing.T) {
 	// immediate flushing.
 	// Finally, set all alerts to be resolved. After successful notify the aggregation group
 	// should empty itself.
-	ag = newAggrGroup(context.Background(), lset, route, nil, log.NewNopLogger())
+	ag = newAggrGroup(context.Background(), lset, route, nil, nil, nil, log.NewNopLogger())
 	go ag.run(ntfy)
 
 	ag.insert(a1)
@@ -269,6 +273,451 @@ func TestAggrGroup(t *testing.T) {
 	ag.stop()
 }
 
+func TestAggrGroupEscalation(t *testing.T) {
+	lset := model.LabelSet{"a": "v1"}
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "n1",
+			GroupBy:        map[model.LabelName]struct{}{"a": {}},
+			GroupWait:      1 * time.Second,
+			GroupInterval:  300 * time.Millisecond,
+			RepeatInterval: 1 * time.Hour,
+			Escalation: []EscalationStep{
+				{After: 50 * time.Millisecond, Receiver: "n2"},
+				{After: 100 * time.Millisecond, Receiver: "n3"},
+			},
+		},
+	}
+
+	type notification struct {
+		receiver string
+		status   model.AlertStatus
+	}
+	notifications := make(chan notification, 10)
+	ntfy := func(ctx context.Context, alerts ...*types.Alert) bool {
+		rcv, ok := notify.ReceiverName(ctx)
+		require.True(t, ok)
+		notifications <- notification{receiver: rcv, status: types.Alerts(alerts...).Status()}
+		return true
+	}
+	next := func() notification {
+		select {
+		case n := <-notifications:
+			return n
+		case <-time.After(time.Second):
+			t.Fatal("expected notification but received none")
+		}
+		return notification{}
+	}
+
+	ag := newAggrGroup(context.Background(), lset, route, nil, nil, nil, log.NewNopLogger())
+	go ag.run(ntfy)
+	defer ag.stop()
+
+	// The alert started in the past so it is flushed right away.
+	a := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"a": "v1", "b": "v2"},
+			StartsAt: time.Now().Add(-time.Hour),
+			EndsAt:   time.Now().Add(time.Hour),
+		},
+		UpdatedAt: time.Now(),
+	}
+	ag.insert(a)
+
+	require.Equal(t, notification{"n1", model.AlertFiring}, next())
+	require.Equal(t, notification{"n2", model.AlertFiring}, next())
+	require.Equal(t, notification{"n3", model.AlertFiring}, next())
+
+	// Once resolved, all receivers the group was escalated to are notified.
+	ar := *a
+	ar.EndsAt = time.Now()
+	ag.insert(&ar)
+
+	var got []notification
+	for i := 0; i < 3; i++ {
+		got = append(got, next())
+	}
+	require.Equal(t, []notification{
+		{"n1", model.AlertResolved},
+		{"n2", model.AlertResolved},
+		{"n3", model.AlertResolved},
+	}, got)
+
+	select {
+	case n := <-notifications:
+		t.Fatalf("unexpected notification %v", n)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+type testAcks map[string]struct{}
+
+func (a testAcks) Acknowledged(groupKey string) bool {
+	_, ok := a[groupKey]
+	return ok
+}
+
+func TestAggrGroupAcknowledged(t *testing.T) {
+	lset := model.LabelSet{"a": "v1"}
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "n1",
+			GroupBy:        map[model.LabelName]struct{}{"a": {}},
+			GroupWait:      1 * time.Second,
+			GroupInterval:  1 * time.Hour,
+			RepeatInterval: 1 * time.Hour,
+			Escalation: []EscalationStep{
+				{After: 50 * time.Millisecond, Receiver: "n2"},
+			},
+		},
+	}
+
+	receivers := make(chan string, 10)
+	ntfy := func(ctx context.Context, alerts ...*types.Alert) bool {
+		acked, ok := notify.Acknowledged(ctx)
+		require.True(t, ok)
+		require.True(t, acked)
+		rcv, _ := notify.ReceiverName(ctx)
+		receivers <- rcv
+		return true
+	}
+
+	acks := testAcks{}
+	ag := newAggrGroup(context.Background(), lset, route, nil, acks, nil, log.NewNopLogger())
+	acks[ag.GroupKey()] = struct{}{}
+	go ag.run(ntfy)
+	defer ag.stop()
+
+	ag.insert(&types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"a": "v1"},
+			StartsAt: time.Now().Add(-time.Hour),
+			EndsAt:   time.Now().Add(time.Hour),
+		},
+		UpdatedAt: time.Now(),
+	})
+
+	select {
+	case rcv := <-receivers:
+		require.Equal(t, "n1", rcv)
+	case <-time.After(time.Second):
+		t.Fatal("expected notification but received none")
+	}
+
+	// Acknowledged groups aren't escalated.
+	select {
+	case rcv := <-receivers:
+		t.Fatalf("unexpected notification to %q", rcv)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestAggrGroupNextFlush(t *testing.T) {
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "n1",
+			GroupBy:        map[model.LabelName]struct{}{},
+			GroupWait:      time.Minute,
+			GroupInterval:  time.Hour,
+			RepeatInterval: time.Hour,
+		},
+	}
+	// The group isn't run so that its timer doesn't fire.
+	ag := newAggrGroup(context.Background(), model.LabelSet{"a": "v1"}, route, nil, nil, nil, log.NewNopLogger())
+	defer ag.cancel()
+
+	// A new group waits for group_wait before its first flush.
+	require.WithinDuration(t, time.Now().Add(time.Minute), ag.nextFlushTime(), time.Second)
+
+	// An alert older than group_wait is flushed right away.
+	ag.insert(&types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"a": "v1"},
+		StartsAt: time.Now().Add(-time.Hour),
+	}})
+	require.WithinDuration(t, time.Now(), ag.nextFlushTime(), time.Second)
+}
+
+func TestAggrGroupJitter(t *testing.T) {
+	defer func(f func(time.Duration) time.Duration) { jitter = f }(jitter)
+	jitter = func(max time.Duration) time.Duration { return max / 2 }
+
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:            "n1",
+			GroupBy:             map[model.LabelName]struct{}{},
+			GroupWait:           time.Minute,
+			GroupInterval:       time.Hour,
+			RepeatInterval:      time.Hour,
+			GroupWaitJitter:     20 * time.Minute,
+			GroupIntervalJitter: 40 * time.Minute,
+		},
+	}
+	ag := newAggrGroup(context.Background(), model.LabelSet{"a": "v1"}, route, nil, nil, nil, log.NewNopLogger())
+	defer ag.cancel()
+
+	// The jitter is added to the group wait.
+	require.WithinDuration(t, time.Now().Add(11*time.Minute), ag.nextFlushTime(), time.Second)
+
+	// Alerts older than group_wait are flushed within the jitter.
+	ag.insert(&types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"a": "v1"},
+		StartsAt: time.Now().Add(-time.Hour),
+	}})
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), ag.nextFlushTime(), time.Second)
+
+	// And to the group interval.
+	ag.flushAt(time.Now(), func(context.Context, ...*types.Alert) bool { return true })
+	require.WithinDuration(t, time.Now().Add(80*time.Minute), ag.nextFlushTime(), time.Second)
+}
+
+func TestJitter(t *testing.T) {
+	require.Equal(t, time.Duration(0), jitter(0))
+	for i := 0; i < 100; i++ {
+		d := jitter(time.Second)
+		require.GreaterOrEqual(t, int64(d), int64(0))
+		require.Less(t, int64(d), int64(time.Second))
+	}
+}
+
+type lifecycleRecorder []lifecycle.Event
+
+func (r *lifecycleRecorder) Enabled(lifecycle.EventType) bool { return true }
+func (r *lifecycleRecorder) Record(e lifecycle.Event)         { *r = append(*r, e) }
+
+func TestAggrGroupLifecycle(t *testing.T) {
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "n1",
+			GroupBy:        map[model.LabelName]struct{}{},
+			GroupWait:      time.Minute,
+			GroupInterval:  time.Hour,
+			RepeatInterval: time.Hour,
+		},
+	}
+	events := &lifecycleRecorder{}
+	// The group isn't run, it is flushed explicitly.
+	ag := newAggrGroup(context.Background(), model.LabelSet{"a": "v1"}, route, nil, nil, events, log.NewNopLogger())
+	defer ag.cancel()
+
+	a := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"a": "v1"},
+			StartsAt: time.Now().Add(-time.Hour),
+		},
+		UpdatedAt: time.Now(),
+	}
+	ag.insert(a)
+	ag.insert(a)
+	require.Len(t, *events, 1)
+	require.Equal(t, lifecycle.EventGrouped, (*events)[0].Type)
+	require.Equal(t, ag.GroupKey(), (*events)[0].GroupKey)
+	require.Equal(t, "n1", (*events)[0].Receiver)
+
+	// The alert is resolved once it leaves the group.
+	resolved := *a
+	resolved.EndsAt = time.Now().Add(-time.Minute)
+	resolved.UpdatedAt = time.Now()
+	ag.insert(&resolved)
+	ag.flush(func(...*types.Alert) bool { return true })
+	require.Len(t, *events, 2)
+	require.Equal(t, lifecycle.EventResolved, (*events)[1].Type)
+	require.Equal(t, a.Fingerprint().String(), (*events)[1].Fingerprint)
+}
+
+func TestAggrGroupDigest(t *testing.T) {
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "n1",
+			GroupBy:        map[model.LabelName]struct{}{},
+			GroupWait:      0,
+			GroupInterval:  time.Hour,
+			RepeatInterval: time.Hour,
+			Digest:         &config.DigestConfig{Interval: model.Duration(time.Second)},
+		},
+	}
+
+	type flush struct {
+		at     time.Time
+		repeat time.Duration
+		digest bool
+	}
+	flushes := make(chan flush, 10)
+	ntfy := func(ctx context.Context, alerts ...*types.Alert) bool {
+		now, _ := notify.Now(ctx)
+		repeat, _ := notify.RepeatInterval(ctx)
+		digest, _ := notify.Digest(ctx)
+		flushes <- flush{at: now, repeat: repeat, digest: digest}
+		return true
+	}
+
+	ag := newAggrGroup(context.Background(), model.LabelSet{"a": "v1"}, route, nil, nil, nil, log.NewNopLogger())
+	next := ag.nextFlushTime()
+	// Digests are sent every second, on the second.
+	require.WithinDuration(t, time.Now(), next, time.Second)
+	require.Equal(t, next.Truncate(time.Second), next)
+
+	// Alerts older than group_wait don't trigger a flush before the digest.
+	ag.insert(&types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"a": "v1"},
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(time.Hour),
+	}})
+	require.Equal(t, next, ag.nextFlushTime())
+
+	go ag.run(ntfy)
+	defer ag.stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case f := <-flushes:
+			require.False(t, f.at.Before(next))
+			require.True(t, f.digest)
+			require.Equal(t, 500*time.Millisecond, f.repeat)
+			next = next.Add(time.Second)
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected digest but received none")
+		}
+	}
+}
+
+func TestDigestSchedule(t *testing.T) {
+	at := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return ts
+	}
+
+	interval := &config.DigestConfig{Interval: model.Duration(6 * time.Hour)}
+	require.Equal(t, at("2021-08-24T12:00:00Z"), nextDigest(interval, at("2021-08-24T09:30:00Z")))
+	require.Equal(t, at("2021-08-24T18:00:00Z"), nextDigest(interval, at("2021-08-24T12:00:00Z")))
+	require.Equal(t, at("2021-08-24T06:00:00Z"), prevDigest(interval, at("2021-08-24T12:00:00Z")))
+	require.Equal(t, 3*time.Hour, digestRepeatInterval(interval, at("2021-08-24T12:00:00Z")))
+
+	var schedule config.DigestConfig
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`schedule: "CRON_TZ=Europe/Paris 0 9,17 * * MON-FRI"`), &schedule))
+	require.Equal(t, at("2021-08-27T17:00:00+02:00"), nextDigest(&schedule, at("2021-08-27T09:00:00+02:00")))
+	require.Equal(t, at("2021-08-30T09:00:00+02:00"), nextDigest(&schedule, at("2021-08-27T17:00:00+02:00")))
+	require.Equal(t, at("2021-08-27T17:00:00+02:00"), prevDigest(&schedule, at("2021-08-30T09:00:00+02:00")))
+	require.Equal(t, at("2021-08-27T09:00:00+02:00"), prevDigest(&schedule, at("2021-08-27T17:00:00+02:00")))
+	// The repeat interval depends on the time since the previous digest.
+	require.Equal(t, 4*time.Hour, digestRepeatInterval(&schedule, at("2021-08-27T17:00:00+02:00")))
+	require.Equal(t, 32*time.Hour, digestRepeatInterval(&schedule, at("2021-08-30T09:00:00+02:00")))
+}
+
+func TestAggrGroupFlapping(t *testing.T) {
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "n1",
+			GroupBy:        map[model.LabelName]struct{}{"a": {}},
+			GroupWait:      1 * time.Hour,
+			GroupInterval:  1 * time.Hour,
+			RepeatInterval: 1 * time.Hour,
+			FlapDetection: &config.FlapDetectionConfig{
+				Threshold: 2,
+				Window:    model.Duration(time.Hour),
+			},
+		},
+	}
+	ag := newAggrGroup(context.Background(), model.LabelSet{"a": "v1"}, route, nil, nil, nil, log.NewNopLogger())
+
+	insert := func(resolved bool) {
+		a := &types.Alert{
+			Alert: model.Alert{
+				Labels:   model.LabelSet{"a": "v1"},
+				StartsAt: time.Now().Add(-time.Hour),
+				EndsAt:   time.Now().Add(time.Hour),
+			},
+			UpdatedAt: time.Now(),
+		}
+		if resolved {
+			a.EndsAt = time.Now().Add(-time.Minute)
+		}
+		ag.insert(a)
+	}
+
+	var notified []*types.Alert
+	ntfy := func(alerts ...*types.Alert) bool {
+		notified = append(notified, alerts...)
+		return true
+	}
+
+	insert(false)
+	insert(true)
+	insert(false)
+	ag.flush(ntfy)
+	require.Len(t, notified, 1)
+	require.NotContains(t, notified[0].Annotations, model.LabelName(FlappingAnnotation))
+
+	// The third state change within the window exceeds the threshold. The
+	// alert is notified once as flapping and held afterwards.
+	insert(true)
+	notified = nil
+	ag.flush(ntfy)
+	require.Len(t, notified, 1)
+	require.Equal(t, model.LabelValue("true"), notified[0].Annotations[FlappingAnnotation])
+
+	insert(false)
+	notified = nil
+	ag.flush(ntfy)
+	require.Empty(t, notified)
+	require.False(t, ag.empty())
+}
+
+func TestFlapDetector(t *testing.T) {
+	f := newFlapDetector(&RouteOpts{
+		FlapDetection: &config.FlapDetectionConfig{
+			Threshold: 1,
+			Window:    model.Duration(10 * time.Minute),
+		},
+	})
+	now := time.Now()
+	alert := func(resolved bool) *types.Alert {
+		a := &types.Alert{
+			Alert: model.Alert{
+				Labels:   model.LabelSet{"a": "v1"},
+				StartsAt: now.Add(-time.Hour),
+			},
+		}
+		if resolved {
+			a.EndsAt = now.Add(-time.Second)
+		}
+		return a
+	}
+	fp := alert(false).Fingerprint()
+
+	require.False(t, f.observe(alert(false), now))
+	require.False(t, f.observe(alert(true), now))
+	require.True(t, f.observe(alert(false), now))
+	require.True(t, f.flapping(fp))
+
+	hold, flapping := f.hold(alert(false), now)
+	require.False(t, hold)
+	require.True(t, flapping)
+	hold, _ = f.hold(alert(false), now)
+	require.True(t, hold)
+
+	// Flapping ends once the alert did not change for a whole window.
+	now = now.Add(10 * time.Minute)
+	hold, flapping = f.hold(alert(false), now)
+	require.False(t, hold)
+	require.False(t, flapping)
+	require.False(t, f.flapping(fp))
+
+	f.gc(now, func(model.Fingerprint) bool { return true })
+	require.Len(t, f.alerts, 1)
+	f.gc(now, func(model.Fingerprint) bool { return false })
+	require.Empty(t, f.alerts)
+
+	// A disabled flap detector never holds alerts.
+	f = newFlapDetector(&RouteOpts{})
+	require.Nil(t, f)
+	require.False(t, f.observe(alert(false), now))
+	hold, flapping = f.hold(alert(false), now)
+	require.False(t, hold)
+	require.False(t, flapping)
+}
+
 func TestGroupLabels(t *testing.T) {
 	var a = &types.Alert{
 		Alert: model.Alert{
@@ -366,7 +815,7 @@ route:
 	logger := log.NewNopLogger()
 	route := NewRoute(conf.Route, nil)
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, nil, logger)
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, logger, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -374,7 +823,7 @@ route:
 
 	timeout := func(d time.Duration) time.Duration { return time.Duration(0) }
 	recorder := &recordStage{alerts: make(map[string]map[model.Fingerprint]*types.Alert)}
-	dispatcher := NewDispatcher(alerts, route, recorder, marker, timeout, nil, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
+	dispatcher := NewDispatcher(alerts, route, recorder, marker, DispatcherOptions{Timeout: timeout}, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
 	go dispatcher.Run()
 	defer dispatcher.Stop()
 
@@ -408,6 +857,11 @@ route:
 		},
 	)
 
+	for _, ag := range alertGroups {
+		require.False(t, ag.NextFlush.IsZero())
+		ag.NextFlush = time.Time{}
+	}
+
 	require.Equal(t, AlertGroups{
 		&AlertGroup{
 			Alerts: []*types.Alert{inputAlerts[0]},
@@ -415,6 +869,7 @@ route:
 				"alertname": "OtherAlert",
 			},
 			Receiver: "prod",
+			GroupKey: "{}:{alertname=\"OtherAlert\"}",
 		},
 		&AlertGroup{
 			Alerts: []*types.Alert{inputAlerts[1]},
@@ -423,6 +878,7 @@ route:
 				"service":   "api",
 			},
 			Receiver: "testing",
+			GroupKey: "{}/{env=\"testing\"}:{alertname=\"TestingAlert\", service=\"api\"}",
 		},
 		&AlertGroup{
 			Alerts: []*types.Alert{inputAlerts[2], inputAlerts[3]},
@@ -432,6 +888,7 @@ route:
 				"cluster":   "aa",
 			},
 			Receiver: "prod",
+			GroupKey: "{}/{env=\"prod\"}:{alertname=\"HighErrorRate\", cluster=\"aa\", service=\"api\"}",
 		},
 		&AlertGroup{
 			Alerts: []*types.Alert{inputAlerts[4]},
@@ -441,6 +898,7 @@ route:
 				"cluster":   "bb",
 			},
 			Receiver: "prod",
+			GroupKey: "{}/{env=\"prod\"}:{alertname=\"HighErrorRate\", cluster=\"bb\", service=\"api\"}",
 		},
 		&AlertGroup{
 			Alerts: []*types.Alert{inputAlerts[5]},
@@ -450,6 +908,7 @@ route:
 				"cluster":   "bb",
 			},
 			Receiver: "kafka",
+			GroupKey: "{}/{kafka=\"yes\"}:{alertname=\"HighLatency\", cluster=\"bb\", service=\"db\"}",
 		},
 		&AlertGroup{
 			Alerts: []*types.Alert{inputAlerts[5]},
@@ -459,6 +918,7 @@ route:
 				"cluster":   "bb",
 			},
 			Receiver: "prod",
+			GroupKey: "{}/{env=\"prod\"}:{alertname=\"HighLatency\", cluster=\"bb\", service=\"db\"}",
 		},
 	}, alertGroups)
 	require.Equal(t, map[model.Fingerprint][]string{
@@ -504,7 +964,7 @@ route:
 	logger := log.NewNopLogger()
 	route := NewRoute(conf.Route, nil)
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, nil, logger)
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, logger, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -514,7 +974,7 @@ route:
 	recorder := &recordStage{alerts: make(map[string]map[model.Fingerprint]*types.Alert)}
 	lim := limits{groups: 6}
 	m := NewDispatcherMetrics(true, prometheus.NewRegistry())
-	dispatcher := NewDispatcher(alerts, route, recorder, marker, timeout, lim, logger, m)
+	dispatcher := NewDispatcher(alerts, route, recorder, marker, DispatcherOptions{Timeout: timeout, Limits: lim}, logger, m)
 	go dispatcher.Run()
 	defer dispatcher.Stop()
 
@@ -562,6 +1022,7 @@ route:
 		time.Sleep(200 * time.Millisecond)
 	}
 	require.Equal(t, 1.0, testutil.ToFloat64(m.aggrGroupLimitReached))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.alertsDropped.WithLabelValues(dropReasonAggrGroupLimit)))
 
 	// Verify there are still only 6 groups.
 	alertGroups, _ = dispatcher.Groups(routeFilter, alertFilter)
@@ -625,14 +1086,14 @@ func newAlert(labels model.LabelSet) *types.Alert {
 func TestDispatcherRace(t *testing.T) {
 	logger := log.NewNopLogger()
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, nil, logger)
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, logger, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer alerts.Close()
 
 	timeout := func(d time.Duration) time.Duration { return time.Duration(0) }
-	dispatcher := NewDispatcher(alerts, nil, nil, marker, timeout, nil, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
+	dispatcher := NewDispatcher(alerts, nil, nil, marker, DispatcherOptions{Timeout: timeout}, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
 	go dispatcher.Run()
 	dispatcher.Stop()
 }
@@ -642,7 +1103,7 @@ func TestDispatcherRaceOnFirstAlertNotDeliveredWhenGroupWaitIsZero(t *testing.T)
 
 	logger := log.NewNopLogger()
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, nil, logger)
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, logger, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -660,7 +1121,7 @@ func TestDispatcherRaceOnFirstAlertNotDeliveredWhenGroupWaitIsZero(t *testing.T)
 
 	timeout := func(d time.Duration) time.Duration { return d }
 	recorder := &recordStage{alerts: make(map[string]map[model.Fingerprint]*types.Alert)}
-	dispatcher := NewDispatcher(alerts, route, recorder, marker, timeout, nil, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
+	dispatcher := NewDispatcher(alerts, route, recorder, marker, DispatcherOptions{Timeout: timeout}, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
 	go dispatcher.Run()
 	defer dispatcher.Stop()
 
@@ -684,6 +1145,110 @@ func TestDispatcherRaceOnFirstAlertNotDeliveredWhenGroupWaitIsZero(t *testing.T)
 	require.Equal(t, numAlerts, len(recorder.Alerts()))
 }
 
+func TestDispatcherSharding(t *testing.T) {
+	const (
+		numShards = 4
+		numGroups = 100
+	)
+
+	logger := log.NewNopLogger()
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, logger, nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "default",
+			GroupBy:        map[model.LabelName]struct{}{"alertname": {}},
+			GroupWait:      0,
+			GroupInterval:  1 * time.Hour,
+			RepeatInterval: 1 * time.Hour,
+		},
+	}
+
+	timeout := func(d time.Duration) time.Duration { return d }
+	recorder := &recordStage{alerts: make(map[string]map[model.Fingerprint]*types.Alert)}
+	reg := prometheus.NewRegistry()
+	m := NewDispatcherMetrics(false, reg)
+	dispatcher := NewDispatcher(alerts, route, recorder, marker, DispatcherOptions{Timeout: timeout, Shards: numShards}, logger, m)
+	go dispatcher.Run()
+	defer dispatcher.Stop()
+
+	// Two alerts per group.
+	for i := 0; i < numGroups; i++ {
+		for _, instance := range []model.LabelValue{"a", "b"} {
+			alert := newAlert(model.LabelSet{"alertname": model.LabelValue(fmt.Sprintf("Alert_%d", i)), "instance": instance})
+			require.NoError(t, alerts.Put(alert))
+		}
+	}
+
+	// The second alert of a group may be inserted after its first flush, so
+	// the groups are waited for rather than their notifications.
+	groupedAlerts := func() int {
+		groups, _ := dispatcher.Groups(
+			func(*Route) bool { return true },
+			func(*types.Alert, time.Time) bool { return true },
+		)
+		n := 0
+		for _, ag := range groups {
+			n += len(ag.Alerts)
+		}
+		return n
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		if groupedAlerts() >= 2*numGroups {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, 2*numGroups, groupedAlerts())
+	require.Equal(t, float64(numGroups), testutil.ToFloat64(m.aggrGroups))
+
+	// The processing of an alert is observed once it was inserted into its
+	// group by the shard's worker.
+	processed := func() uint64 {
+		mfs, err := reg.Gather()
+		require.NoError(t, err)
+		for _, mf := range mfs {
+			if mf.GetName() == "alertmanager_dispatcher_alert_processing_duration_seconds" {
+				return mf.GetMetric()[0].GetSummary().GetSampleCount()
+			}
+		}
+		return 0
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		if processed() >= 2*numGroups {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, uint64(2*numGroups), processed())
+
+	alertGroups, _ := dispatcher.Groups(
+		func(*Route) bool { return true },
+		func(*types.Alert, time.Time) bool { return true },
+	)
+	require.Len(t, alertGroups, numGroups)
+	for _, ag := range alertGroups {
+		require.Len(t, ag.Alerts, 2)
+	}
+
+	// Every group is owned by the shard its fingerprint maps to.
+	dispatcher.mtx.RLock()
+	shards := dispatcher.shards
+	dispatcher.mtx.RUnlock()
+	require.Len(t, shards, numShards)
+	for i, sh := range shards {
+		sh.mtx.RLock()
+		require.NotEmpty(t, sh.aggrGroupsPerRoute[route])
+		for fp := range sh.aggrGroupsPerRoute[route] {
+			require.Equal(t, uint64(i), uint64(fp)%numShards)
+		}
+		sh.mtx.RUnlock()
+	}
+}
+
 type limits struct {
 	groups int
 }
@@ -691,3 +1256,182 @@ type limits struct {
 func (l limits) MaxNumberOfAggregationGroups() int {
 	return l.groups
 }
+
+func TestDispatcherResend(t *testing.T) {
+	logger := log.NewNopLogger()
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, logger, nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "default",
+			GroupBy:        map[model.LabelName]struct{}{"alertname": {}},
+			GroupWait:      0,
+			GroupInterval:  1 * time.Hour,
+			RepeatInterval: 1 * time.Hour,
+		},
+	}
+
+	type notification struct {
+		receiver string
+		repeat   time.Duration
+	}
+	var failing int32
+	notifications := make(chan notification, 10)
+	stage := notify.StageFunc(func(ctx context.Context, _ log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		rcv, _ := notify.ReceiverName(ctx)
+		repeat, _ := notify.RepeatInterval(ctx)
+		notifications <- notification{receiver: rcv, repeat: repeat}
+		if atomic.LoadInt32(&failing) == 1 {
+			return ctx, nil, errors.New("failed")
+		}
+		return ctx, alerts, nil
+	})
+	next := func() notification {
+		select {
+		case n := <-notifications:
+			return n
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected notification but received none")
+		}
+		return notification{}
+	}
+
+	dispatcher := NewDispatcher(alerts, route, stage, marker, DispatcherOptions{}, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
+	go dispatcher.Run()
+	defer dispatcher.Stop()
+
+	require.NoError(t, alerts.Put(newAlert(model.LabelSet{"alertname": "HighLatency"})))
+	require.Equal(t, notification{"default", time.Hour}, next())
+
+	ctx := context.Background()
+	groupKey := `{}:{alertname="HighLatency"}`
+	require.NoError(t, dispatcher.Resend(ctx, groupKey, "default"))
+	require.Equal(t, notification{"default", 0}, next())
+
+	require.Equal(t, ErrGroupNotFound, dispatcher.Resend(ctx, groupKey, "other"))
+	require.Equal(t, ErrGroupNotFound, dispatcher.Resend(ctx, `{}:{alertname="LowLatency"}`, "default"))
+
+	atomic.StoreInt32(&failing, 1)
+	require.Equal(t, ErrResendFailed, dispatcher.Resend(ctx, groupKey, "default"))
+}
+
+func TestDispatcherDrain(t *testing.T) {
+	logger := log.NewNopLogger()
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, logger, nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "default",
+			GroupBy:        map[model.LabelName]struct{}{"alertname": {}},
+			GroupWait:      1 * time.Hour,
+			GroupInterval:  1 * time.Hour,
+			RepeatInterval: 1 * time.Hour,
+		},
+	}
+
+	notifications := make(chan []*types.Alert, 10)
+	stage := notify.StageFunc(func(ctx context.Context, _ log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		notifications <- alerts
+		return ctx, alerts, nil
+	})
+
+	dispatcher := NewDispatcher(alerts, route, stage, marker, DispatcherOptions{}, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
+	go dispatcher.Run()
+	defer dispatcher.Stop()
+
+	// The alert started before the group wait so its group is flushed
+	// right away.
+	old := newAlert(model.LabelSet{"alertname": "HighLatency", "instance": "a"})
+	old.StartsAt = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, alerts.Put(old))
+	select {
+	case n := <-notifications:
+		require.Len(t, n, 1)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected notification but received none")
+	}
+
+	// A new alert of the flushed group waits for the group interval, and
+	// a new group for the group wait.
+	require.NoError(t, alerts.Put(newAlert(model.LabelSet{"alertname": "HighLatency", "instance": "b"})))
+	fresh := newAlert(model.LabelSet{"alertname": "LowLatency"})
+	fresh.StartsAt = time.Now()
+	require.NoError(t, alerts.Put(fresh))
+	require.Eventually(t, func() bool {
+		groups, _ := dispatcher.Groups(
+			func(*Route) bool { return true },
+			func(*types.Alert, time.Time) bool { return true },
+		)
+		return len(groups) == 2 && len(groups[0].Alerts)+len(groups[1].Alerts) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Both groups are flushed before Drain returns, including the one
+	// still in its group wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dispatcher.Drain(ctx)
+	require.NoError(t, ctx.Err())
+	require.Len(t, notifications, 2)
+	notified := map[model.LabelValue]int{}
+	for i := 0; i < 2; i++ {
+		n := <-notifications
+		notified[n[0].Labels["alertname"]] = len(n)
+	}
+	require.Equal(t, map[model.LabelValue]int{"HighLatency": 2, "LowLatency": 1}, notified)
+}
+
+func TestDispatcherDrainDuringGroupWait(t *testing.T) {
+	logger := log.NewNopLogger()
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, logger, nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	route := &Route{
+		RouteOpts: RouteOpts{
+			Receiver:       "default",
+			GroupBy:        map[model.LabelName]struct{}{"alertname": {}},
+			GroupWait:      1 * time.Hour,
+			GroupInterval:  1 * time.Hour,
+			RepeatInterval: 1 * time.Hour,
+		},
+	}
+
+	notifications := make(chan []*types.Alert, 10)
+	stage := notify.StageFunc(func(ctx context.Context, _ log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		notifications <- alerts
+		return ctx, alerts, nil
+	})
+
+	dispatcher := NewDispatcher(alerts, route, stage, marker, DispatcherOptions{}, logger, NewDispatcherMetrics(false, prometheus.NewRegistry()))
+	go dispatcher.Run()
+
+	a := newAlert(model.LabelSet{"alertname": "HighLatency"})
+	a.StartsAt = time.Now()
+	require.NoError(t, alerts.Put(a))
+	require.Eventually(t, func() bool {
+		groups, _ := dispatcher.Groups(
+			func(*Route) bool { return true },
+			func(*types.Alert, time.Time) bool { return true },
+		)
+		return len(groups) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, notifications, 0)
+
+	// Shutting down during the group wait still notifies the alert.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dispatcher.Drain(ctx)
+	require.NoError(t, ctx.Err())
+	dispatcher.Stop()
+	require.Len(t, notifications, 1)
+	n := <-notifications
+	require.Len(t, n, 1)
+	require.Equal(t, model.LabelValue("HighLatency"), n[0].Labels["alertname"])
+}
@@ -0,0 +1,158 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/types"
+)
+
+// FlappingAnnotation is the annotation added to an alert in the single
+// notification sent once it was detected as flapping.
+const FlappingAnnotation = "flapping"
+
+// flapDetector tracks the changes between firing and resolved of the alerts
+// of an aggregation group. An alert changing its state more than threshold
+// times within the window is flapping until it did not change for a whole
+// window.
+type flapDetector struct {
+	threshold int
+	window    time.Duration
+
+	mtx    sync.Mutex
+	alerts map[model.Fingerprint]*flapState
+}
+
+type flapState struct {
+	resolved bool
+	// The times of the state changes within the window.
+	changes  []time.Time
+	flapping bool
+	// Whether the notification about the alert flapping was sent.
+	notified bool
+}
+
+// newFlapDetector returns a new flap detector for the given route options,
+// or nil if flap detection is disabled.
+func newFlapDetector(opts *RouteOpts) *flapDetector {
+	if opts.FlapDetection == nil {
+		return nil
+	}
+	return &flapDetector{
+		threshold: opts.FlapDetection.Threshold,
+		window:    time.Duration(opts.FlapDetection.Window),
+		alerts:    map[model.Fingerprint]*flapState{},
+	}
+}
+
+// prune removes the state changes which are out of the window and ends
+// flapping once there are none left.
+func (s *flapState) prune(now time.Time, window time.Duration) {
+	i := 0
+	for i < len(s.changes) && !s.changes[i].After(now.Add(-window)) {
+		i++
+	}
+	s.changes = s.changes[i:]
+	if len(s.changes) == 0 {
+		s.flapping = false
+	}
+}
+
+// observe records the state of an inserted alert. It returns true if the
+// alert started flapping.
+func (f *flapDetector) observe(a *types.Alert, now time.Time) bool {
+	if f == nil {
+		return false
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	fp := a.Fingerprint()
+	resolved := a.ResolvedAt(now)
+	s, ok := f.alerts[fp]
+	if !ok {
+		f.alerts[fp] = &flapState{resolved: resolved}
+		return false
+	}
+	if s.resolved != resolved {
+		s.resolved = resolved
+		s.changes = append(s.changes, now)
+	}
+	s.prune(now, f.window)
+
+	if !s.flapping && len(s.changes) > f.threshold {
+		s.flapping = true
+		s.notified = false
+		return true
+	}
+	return false
+}
+
+// hold returns whether notifications about the alert are held because it is
+// flapping. If the notification about the alert flapping is due, the alert is
+// not held and notify is true.
+func (f *flapDetector) hold(a *types.Alert, now time.Time) (hold, notify bool) {
+	if f == nil {
+		return false, false
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	s, ok := f.alerts[a.Fingerprint()]
+	if !ok {
+		return false, false
+	}
+	s.prune(now, f.window)
+	if !s.flapping {
+		return false, false
+	}
+	if !s.notified {
+		s.notified = true
+		return false, true
+	}
+	return true, false
+}
+
+// flapping returns whether the alert is currently flapping.
+func (f *flapDetector) flapping(fp model.Fingerprint) bool {
+	if f == nil {
+		return false
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	s, ok := f.alerts[fp]
+	return ok && s.flapping
+}
+
+// gc removes the state of alerts which are no longer part of the group and
+// did not change within the window.
+func (f *flapDetector) gc(now time.Time, present func(model.Fingerprint) bool) {
+	if f == nil {
+		return
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	for fp, s := range f.alerts {
+		s.prune(now, f.window)
+		if len(s.changes) == 0 && !present(fp) {
+			delete(f.alerts, fp)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/prometheus/alertmanager/notify"
+)
+
+// Errors returned by Resend.
+var (
+	// ErrGroupNotFound is returned if no alert group with the given key
+	// notifies the receiver.
+	ErrGroupNotFound = errors.New("alert group not found")
+	// ErrNoFiringAlerts is returned if the alert group has no firing alerts
+	// to notify.
+	ErrNoFiringAlerts = errors.New("alert group has no firing alerts")
+	// ErrResendFailed is returned if the notification could not be sent.
+	ErrResendFailed = errors.New("notification failed")
+)
+
+// resendRequest asks an aggregation group to notify a receiver again. The
+// result is sent to done.
+type resendRequest struct {
+	receiver string
+	done     chan error
+}
+
+// Resend immediately notifies the receiver again about the firing alerts of
+// the alert group with the given key, regardless of the repeat interval and
+// of any acknowledgement of the group. The receiver must be the receiver of
+// the group's route or one it was escalated to.
+func (d *Dispatcher) Resend(ctx context.Context, groupKey, receiver string) error {
+	ag := d.findGroup(groupKey, receiver)
+	if ag == nil {
+		return ErrGroupNotFound
+	}
+
+	req := resendRequest{receiver: receiver, done: make(chan error, 1)}
+	select {
+	case ag.resend <- req:
+	case <-ag.done:
+		return ErrGroupNotFound
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// findGroup returns the aggregation group with the given key notifying the
+// receiver, or nil if there is none.
+func (d *Dispatcher) findGroup(groupKey, receiver string) *aggrGroup {
+	d.mtx.RLock()
+	shards := d.shards
+	d.mtx.RUnlock()
+
+	for _, sh := range shards {
+		sh.mtx.RLock()
+		for _, ags := range sh.aggrGroupsPerRoute {
+			for _, ag := range ags {
+				if ag.GroupKey() == groupKey && ag.notifies(receiver) {
+					sh.mtx.RUnlock()
+					return ag
+				}
+			}
+		}
+		sh.mtx.RUnlock()
+	}
+	return nil
+}
+
+// notifies returns whether the group currently notifies the receiver.
+func (ag *aggrGroup) notifies(receiver string) bool {
+	if ag.opts.Receiver == receiver {
+		return true
+	}
+	for _, rcv := range ag.escalatedReceivers() {
+		if rcv == receiver {
+			return true
+		}
+	}
+	return false
+}
+
+// resendTo notifies the receiver about the firing alerts of the group,
+// bypassing the repeat interval and acknowledgements.
+func (ag *aggrGroup) resendTo(ctx context.Context, now time.Time, receiver string, nf notifyFunc) error {
+	firing := ag.firingAlerts(now)
+	if len(firing) == 0 {
+		return ErrNoFiringAlerts
+	}
+
+	ctx = ag.notifyContext(ctx, now, receiver)
+	ctx = notify.WithRepeatInterval(ctx, 0)
+	ctx = notify.WithAcknowledged(ctx, false)
+
+	level.Info(ag.logger).Log("msg", "Resending alert group", "receiver", receiver, "alerts", len(firing))
+	if !nf(ctx, firing...) {
+		return ErrResendFailed
+	}
+	return nil
+}
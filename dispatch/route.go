@@ -23,7 +23,10 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/expr"
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/pkg/relabel"
+	"github.com/prometheus/alertmanager/types"
 )
 
 // DefaultRouteOpts are the defaulting routing options which apply
@@ -48,11 +51,20 @@ type Route struct {
 	// this route.
 	Matchers labels.Matchers
 
+	// Expression an alert has to fulfill, in addition to the matchers, to
+	// match this route. It may be nil.
+	Expression *expr.Expr
+
 	// If true, an alert matches further routes on the same level.
 	Continue bool
 
 	// Children routes of this route.
 	Routes []*Route
+
+	// What happens to the alerts matching none of the children of the
+	// root route. It is only set on the root route.
+	Unrouted config.UnroutedAction
+	fallback *Route
 }
 
 // NewRoute returns a new route.
@@ -85,6 +97,12 @@ func NewRoute(cr *config.Route, parent *Route) *Route {
 	if cr.GroupInterval != nil {
 		opts.GroupInterval = time.Duration(*cr.GroupInterval)
 	}
+	if cr.GroupWaitJitter != nil {
+		opts.GroupWaitJitter = time.Duration(*cr.GroupWaitJitter)
+	}
+	if cr.GroupIntervalJitter != nil {
+		opts.GroupIntervalJitter = time.Duration(*cr.GroupIntervalJitter)
+	}
 	if cr.RepeatInterval != nil {
 		opts.RepeatInterval = time.Duration(*cr.RepeatInterval)
 	}
@@ -119,15 +137,64 @@ func NewRoute(cr *config.Route, parent *Route) *Route {
 
 	opts.MuteTimeIntervals = cr.MuteTimeIntervals
 
+	if cr.LabelRelabelConfigs != nil {
+		opts.LabelRelabelConfigs = cr.LabelRelabelConfigs
+	}
+	if cr.AnnotationRelabelConfigs != nil {
+		opts.AnnotationRelabelConfigs = cr.AnnotationRelabelConfigs
+	}
+	if cr.Enrichment != nil {
+		opts.Enrichment = cr.Enrichment
+	}
+	if cr.Owner != nil {
+		opts.Owner = cr.Owner
+	}
+	if cr.FlapDetection != nil {
+		opts.FlapDetection = cr.FlapDetection
+	}
+	if cr.Digest != nil {
+		opts.Digest = cr.Digest
+	}
+	if cr.ResolveTimeout != nil {
+		opts.ResolveTimeout = time.Duration(*cr.ResolveTimeout)
+	}
+
+	// Escalation steps only apply to the route they are defined on.
+	opts.Escalation = nil
+	for _, e := range cr.Escalation {
+		opts.Escalation = append(opts.Escalation, EscalationStep{
+			After:    time.Duration(e.After),
+			Receiver: e.Receiver,
+		})
+	}
+
 	route := &Route{
 		parent:    parent,
 		RouteOpts: opts,
 		Matchers:  matchers,
 		Continue:  cr.Continue,
 	}
+	if cr.Expression != nil {
+		route.Expression = cr.Expression.Expr
+	}
 
 	route.Routes = NewRoutes(cr.Routes, route)
 
+	if parent == nil {
+		route.Unrouted = config.UnroutedRoot
+		if cr.Unrouted != nil {
+			route.Unrouted = cr.Unrouted.Action
+		}
+		if route.Unrouted == config.UnroutedFallback {
+			fopts := opts
+			fopts.Receiver = cr.Unrouted.FallbackReceiver
+			route.fallback = &Route{
+				parent:    route,
+				RouteOpts: fopts,
+			}
+		}
+	}
+
 	return route
 }
 
@@ -141,16 +208,30 @@ func NewRoutes(croutes []*config.Route, parent *Route) []*Route {
 }
 
 // Match does a depth-first left-to-right search through the route tree
-// and returns the matching routing nodes.
+// and returns the matching routing nodes. Expressions are evaluated without
+// annotations, MatchAlert should be used for alerts.
 func (r *Route) Match(lset model.LabelSet) []*Route {
+	return r.match(lset, nil)
+}
+
+// MatchAlert returns the routing nodes matching the alert, evaluating
+// expressions against its annotations too.
+func (r *Route) MatchAlert(a *types.Alert) []*Route {
+	return r.match(a.RoutingLabels(), a.Annotations)
+}
+
+func (r *Route) match(lset, annotations model.LabelSet) []*Route {
 	if !r.Matchers.Matches(lset) {
 		return nil
 	}
+	if r.Expression != nil && !r.Expression.Matches(lset, annotations) {
+		return nil
+	}
 
 	var all []*Route
 
 	for _, cr := range r.Routes {
-		matches := cr.Match(lset)
+		matches := cr.match(lset, annotations)
 
 		all = append(all, matches...)
 
@@ -159,14 +240,49 @@ func (r *Route) Match(lset model.LabelSet) []*Route {
 		}
 	}
 
-	// If no child nodes were matches, the current node itself is a match.
+	// If no child nodes were matches, the current node itself is a match
+	// unless it is the root route handling unrouted alerts differently.
 	if len(all) == 0 {
-		all = append(all, r)
+		switch {
+		case r.parent != nil:
+			all = append(all, r)
+		case r.Unrouted == config.UnroutedDrop, r.Unrouted == config.UnroutedReject:
+			return nil
+		case r.fallback != nil:
+			all = append(all, r.fallback)
+		default:
+			all = append(all, r)
+		}
 	}
 
 	return all
 }
 
+// IsUnrouted returns whether the routes returned by Match for an alert mean
+// that the alert matched none of the children of the root route r.
+func (r *Route) IsUnrouted(routes []*Route) bool {
+	if len(routes) == 0 {
+		return true
+	}
+	return len(routes) == 1 && (routes[0] == r || routes[0] == r.fallback)
+}
+
+// ResolveTimeout returns the resolve timeout of the routes matching the label
+// set. If several routes match, the longest timeout is returned. It is zero if
+// none of the matching routes sets a resolve timeout.
+func (r *Route) ResolveTimeout(lset model.LabelSet) time.Duration {
+	if r == nil {
+		return 0
+	}
+	var d time.Duration
+	for _, m := range r.Match(lset) {
+		if m.RouteOpts.ResolveTimeout > d {
+			d = m.RouteOpts.ResolveTimeout
+		}
+	}
+	return d
+}
+
 // Key returns a key for the route. It does not uniquely identify the route in general.
 func (r *Route) Key() string {
 	b := strings.Builder{}
@@ -176,6 +292,9 @@ func (r *Route) Key() string {
 		b.WriteRune('/')
 	}
 	b.WriteString(r.Matchers.String())
+	if r.Expression != nil {
+		b.WriteString("{" + r.Expression.String() + "}")
+	}
 	return b.String()
 }
 
@@ -208,8 +327,47 @@ type RouteOpts struct {
 	GroupInterval  time.Duration
 	RepeatInterval time.Duration
 
+	// The maximum random durations added to the group wait and to every
+	// group interval, so that the groups created at once don't all flush at
+	// the same time.
+	GroupWaitJitter     time.Duration
+	GroupIntervalJitter time.Duration
+
 	// A list of time intervals for which the route is muted.
 	MuteTimeIntervals []string
+
+	// Receivers to notify if an alert group keeps firing after its first
+	// notification, ordered by increasing delay.
+	Escalation []EscalationStep
+
+	// Relabeling rules applied to the labels and annotations of the alerts
+	// handed to the receiver.
+	LabelRelabelConfigs      []*relabel.Config
+	AnnotationRelabelConfigs []*relabel.Config
+
+	// The endpoint adding annotations to the alerts handed to the receiver.
+	Enrichment *config.EnrichmentConfig
+
+	// Who owns the alerts, inherited by the child routes.
+	Owner *config.OwnerConfig
+
+	// How alerts are detected as flapping. Flap detection is disabled if nil.
+	FlapDetection *config.FlapDetectionConfig
+
+	// When the alerts are notified in digests. Alerts are notified as they
+	// fire if nil.
+	Digest *config.DigestConfig
+
+	// How long alerts without an end time are considered firing after their
+	// last update. The global resolve timeout applies if zero.
+	ResolveTimeout time.Duration
+}
+
+// EscalationStep defines a receiver to notify once an alert group has been
+// firing for the given duration after its first notification.
+type EscalationStep struct {
+	After    time.Duration
+	Receiver string
 }
 
 func (ro *RouteOpts) String() string {
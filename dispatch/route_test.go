@@ -23,6 +23,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
 )
 
 func TestRouteMatch(t *testing.T) {
@@ -383,6 +384,174 @@ routes:
 	require.Equal(t, child2.RouteOpts.GroupByAll, false)
 }
 
+func TestInheritRelabelConfigs(t *testing.T) {
+	in := `
+routes:
+- match:
+    env: 'parent'
+  label_relabel_configs:
+  - target_label: team
+    replacement: ops
+
+  routes:
+  - match:
+      env: 'child1'
+
+  - match:
+      env: 'child2'
+    label_relabel_configs: []
+    annotation_relabel_configs:
+    - regex: runbook
+      action: labeldrop
+`
+
+	var ctree config.Route
+	if err := yaml.UnmarshalStrict([]byte(in), &ctree); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := NewRoute(&ctree, nil)
+	parent := tree.Routes[0]
+	child1 := parent.Routes[0]
+	child2 := parent.Routes[1]
+	require.Len(t, parent.RouteOpts.LabelRelabelConfigs, 1)
+	require.Equal(t, parent.RouteOpts.LabelRelabelConfigs, child1.RouteOpts.LabelRelabelConfigs)
+	require.Empty(t, child2.RouteOpts.LabelRelabelConfigs)
+	require.Len(t, child2.RouteOpts.AnnotationRelabelConfigs, 1)
+}
+
+func TestInheritOwner(t *testing.T) {
+	in := `
+routes:
+- match:
+    team: 'db'
+  owner:
+    name: db
+
+  routes:
+  - match:
+      env: 'prod'
+
+  - match:
+      env: 'staging'
+    owner:
+      name: db-staging
+`
+
+	var ctree config.Route
+	if err := yaml.UnmarshalStrict([]byte(in), &ctree); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := NewRoute(&ctree, nil)
+	parent := tree.Routes[0]
+	require.Nil(t, tree.RouteOpts.Owner)
+	require.Equal(t, "db", parent.RouteOpts.Owner.Name)
+	require.Same(t, parent.RouteOpts.Owner, parent.Routes[0].RouteOpts.Owner)
+	require.Equal(t, "db-staging", parent.Routes[1].RouteOpts.Owner.Name)
+}
+
+func TestInheritJitter(t *testing.T) {
+	in := `
+group_wait_jitter: 10s
+routes:
+- match:
+    team: 'db'
+  group_interval_jitter: 1m
+- match:
+    team: 'web'
+  group_wait_jitter: 0s
+`
+
+	var ctree config.Route
+	if err := yaml.UnmarshalStrict([]byte(in), &ctree); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := NewRoute(&ctree, nil)
+	require.Equal(t, 10*time.Second, tree.Routes[0].RouteOpts.GroupWaitJitter)
+	require.Equal(t, time.Minute, tree.Routes[0].RouteOpts.GroupIntervalJitter)
+	require.Equal(t, time.Duration(0), tree.Routes[1].RouteOpts.GroupWaitJitter)
+	require.Equal(t, time.Duration(0), tree.Routes[1].RouteOpts.GroupIntervalJitter)
+}
+
+func TestRouteResolveTimeout(t *testing.T) {
+	in := `
+receiver: 'notify-def'
+
+routes:
+- match:
+    job: 'batch'
+  resolve_timeout: 1h
+  continue: true
+
+  routes:
+  - match:
+      env: 'prod'
+
+- match:
+    job: 'batch'
+  resolve_timeout: 2h
+
+- match:
+    job: 'probe'
+`
+
+	var ctree config.Route
+	if err := yaml.UnmarshalStrict([]byte(in), &ctree); err != nil {
+		t.Fatal(err)
+	}
+	tree := NewRoute(&ctree, nil)
+
+	// Child routes inherit the resolve timeout.
+	require.Equal(t, time.Hour, tree.Routes[0].Routes[0].RouteOpts.ResolveTimeout)
+	// The longest resolve timeout of all matching routes applies.
+	require.Equal(t, 2*time.Hour, tree.ResolveTimeout(model.LabelSet{"job": "batch", "env": "prod"}))
+	require.Equal(t, time.Duration(0), tree.ResolveTimeout(model.LabelSet{"job": "probe"}))
+}
+
+func TestRouteExpression(t *testing.T) {
+	in := `
+receiver: 'notify-def'
+
+routes:
+- matchers: ['job="web"']
+  expression: 'labels.replicas < 3 && labels.env != "dev"'
+  receiver: 'notify-scaling'
+
+- expression: 'annotations.runbook =~ "https://.*"'
+  receiver: 'notify-runbook'
+`
+
+	var ctree config.Route
+	if err := yaml.UnmarshalStrict([]byte(in), &ctree); err != nil {
+		t.Fatal(err)
+	}
+	tree := NewRoute(&ctree, nil)
+
+	receivers := func(routes []*Route) []string {
+		var res []string
+		for _, r := range routes {
+			res = append(res, r.RouteOpts.Receiver)
+		}
+		return res
+	}
+	alert := func(labels, annotations model.LabelSet) *types.Alert {
+		return &types.Alert{Alert: model.Alert{Labels: labels, Annotations: annotations}}
+	}
+
+	require.Equal(t, []string{"notify-scaling"}, receivers(tree.MatchAlert(alert(model.LabelSet{"job": "web", "replicas": "2"}, nil))))
+	require.Equal(t, []string{"notify-def"}, receivers(tree.MatchAlert(alert(model.LabelSet{"job": "web", "replicas": "2", "env": "dev"}, nil))))
+	require.Equal(t, []string{"notify-def"}, receivers(tree.MatchAlert(alert(model.LabelSet{"job": "db", "replicas": "2"}, nil))))
+	require.Equal(t, []string{"notify-runbook"}, receivers(tree.MatchAlert(alert(model.LabelSet{"job": "db"}, model.LabelSet{"runbook": "https://example.com"}))))
+	// Annotations are unknown when matching label sets.
+	require.Equal(t, []string{"notify-def"}, receivers(tree.Match(model.LabelSet{"job": "db"})))
+
+	// Routes differing only by their expression have different keys.
+	require.Equal(t, `{}/{job="web"}{labels.replicas < 3 && labels.env != "dev"}`, tree.Routes[0].Key())
+	require.Equal(t, `{}/{}{annotations.runbook =~ "https://.*"}`, tree.Routes[1].Key())
+}
+
 func TestRouteMatchers(t *testing.T) {
 	in := `
 receiver: 'notify-def'
@@ -853,3 +1022,74 @@ routes:
 		}
 	}
 }
+
+func TestRouteKeyStable(t *testing.T) {
+	in := `
+receiver: 'notify-def'
+
+routes:
+- matchers: [ 'team="db"', 'env=~"prod|staging"' ]
+  routes:
+  - match:
+      severity: 'critical'
+- matchers: [ 'env=~"prod|staging"', 'team="db"' ]
+  routes:
+  - match:
+      severity: 'critical'
+`
+
+	var ctree config.Route
+	if err := yaml.UnmarshalStrict([]byte(in), &ctree); err != nil {
+		t.Fatal(err)
+	}
+	tree := NewRoute(&ctree, nil)
+
+	// The key doesn't depend on the order of the matchers.
+	require.Equal(t, `{}/{env=~"prod|staging",team="db"}/{severity="critical"}`, tree.Routes[0].Routes[0].Key())
+	require.Equal(t, tree.Routes[0].Routes[0].Key(), tree.Routes[1].Routes[0].Key())
+}
+
+func TestRouteUnrouted(t *testing.T) {
+	in := `
+receiver: 'notify-def'
+group_by: ['alertname']
+
+routes:
+- match:
+    team: 'db'
+  receiver: 'notify-db'
+`
+	for _, tc := range []struct {
+		unrouted string
+		receiver string
+	}{
+		{unrouted: "", receiver: "notify-def"},
+		{unrouted: "unrouted: {action: root}", receiver: "notify-def"},
+		{unrouted: "unrouted: {action: drop}"},
+		{unrouted: "unrouted: {action: reject}"},
+		{unrouted: "unrouted: {action: fallback, fallback_receiver: notify-fallback}", receiver: "notify-fallback"},
+	} {
+		t.Run(tc.unrouted, func(t *testing.T) {
+			var ctree config.Route
+			if err := yaml.UnmarshalStrict([]byte(in+tc.unrouted), &ctree); err != nil {
+				t.Fatal(err)
+			}
+			tree := NewRoute(&ctree, nil)
+
+			routed := tree.Match(model.LabelSet{"team": "db"})
+			require.Len(t, routed, 1)
+			require.Equal(t, "notify-db", routed[0].RouteOpts.Receiver)
+			require.False(t, tree.IsUnrouted(routed))
+
+			unrouted := tree.Match(model.LabelSet{"team": "web"})
+			require.True(t, tree.IsUnrouted(unrouted))
+			if tc.receiver == "" {
+				require.Empty(t, unrouted)
+				return
+			}
+			require.Len(t, unrouted, 1)
+			require.Equal(t, tc.receiver, unrouted[0].RouteOpts.Receiver)
+			require.Equal(t, tree.RouteOpts.GroupBy, unrouted[0].RouteOpts.GroupBy)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package heartbeat pings an OpsGenie heartbeat, so that OpsGenie pages if
+// the Alertmanager stops running.
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/version"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+)
+
+var userAgentHeader = fmt.Sprintf("Alertmanager/%s", version.Version)
+
+// Manager pings the heartbeat of the configuration.
+type Manager struct {
+	mtx    sync.Mutex
+	conf   *config.HeartbeatConfig
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	pings       prometheus.Counter
+	pingsFailed prometheus.Counter
+	logger      log.Logger
+}
+
+// NewManager returns a manager which doesn't ping until a configuration with
+// a heartbeat is applied.
+func NewManager(r prometheus.Registerer, l log.Logger) *Manager {
+	m := &Manager{
+		pings: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_heartbeat_pings_total",
+			Help: "The total number of pings of the OpsGenie heartbeat.",
+		}),
+		pingsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_heartbeat_pings_failed_total",
+			Help: "The total number of failed pings of the OpsGenie heartbeat.",
+		}),
+		logger: l,
+	}
+	if r != nil {
+		r.MustRegister(m.pings, m.pingsFailed)
+	}
+	return m
+}
+
+// Update applies the heartbeat configuration. The pings are restarted only
+// if the configuration changed.
+func (m *Manager) Update(c *config.Config) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if reflect.DeepEqual(c.Heartbeat, m.conf) {
+		return nil
+	}
+
+	var client *http.Client
+	if c.Heartbeat != nil {
+		var err error
+		client, err = commoncfg.NewClientFromConfig(*c.Heartbeat.HTTPConfig, "heartbeat")
+		if err != nil {
+			return err
+		}
+	}
+
+	m.stop()
+	m.conf = c.Heartbeat
+	if c.Heartbeat != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancel, m.done = cancel, make(chan struct{})
+		go m.run(ctx, c.Heartbeat, client, m.done)
+	}
+	return nil
+}
+
+// Stop stops the pings.
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.stop()
+	m.conf = nil
+}
+
+func (m *Manager) stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.done
+	m.cancel, m.done = nil, nil
+}
+
+// run pings the heartbeat right away, and then at every interval until the
+// context is canceled.
+func (m *Manager) run(ctx context.Context, conf *config.HeartbeatConfig, client *http.Client, done chan struct{}) {
+	defer close(done)
+
+	interval := time.Duration(conf.Interval)
+	u := conf.APIURL.String() + "v2/heartbeats/" + url.PathEscape(conf.Name) + "/ping"
+	level.Info(m.logger).Log("msg", "Pinging OpsGenie heartbeat", "name", conf.Name, "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.pings.Inc()
+		if err := ping(ctx, client, u, string(conf.APIKey), interval); err != nil && ctx.Err() == nil {
+			m.pingsFailed.Inc()
+			level.Warn(m.logger).Log("msg", "Failed to ping OpsGenie heartbeat", "name", conf.Name, "err", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func ping(ctx context.Context, client *http.Client, u, apiKey string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "GenieKey "+apiKey)
+	req.Header.Set("User-Agent", userAgentHeader)
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	notify.Drain(resp)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heartbeat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+func TestManager(t *testing.T) {
+	var (
+		mtx   sync.Mutex
+		pings []*http.Request
+		fail  bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		pings = append(pings, r)
+		if fail {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL + "/")
+	require.NoError(t, err)
+
+	count := func() int {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(pings)
+	}
+
+	m := NewManager(nil, log.NewNopLogger())
+	conf := &config.Config{
+		Heartbeat: &config.HeartbeatConfig{
+			Name:       "alertmanager eu",
+			Interval:   model.Duration(10 * time.Millisecond),
+			APIKey:     "key",
+			APIURL:     &config.URL{URL: u},
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+	}
+	require.NoError(t, m.Update(conf))
+	require.Eventually(t, func() bool { return count() >= 2 }, 5*time.Second, time.Millisecond)
+
+	mtx.Lock()
+	require.Equal(t, "/v2/heartbeats/alertmanager%20eu/ping", pings[0].URL.EscapedPath())
+	require.Equal(t, "GenieKey key", pings[0].Header.Get("Authorization"))
+	fail = true
+	mtx.Unlock()
+
+	require.Eventually(t, func() bool { return testutil.ToFloat64(m.pingsFailed) > 0 }, 5*time.Second, time.Millisecond)
+
+	// Removing the heartbeat stops the pings.
+	require.NoError(t, m.Update(&config.Config{}))
+	n := count()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, n, count())
+	m.Stop()
+}
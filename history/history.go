@@ -0,0 +1,450 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history implements a garbage-collected and snapshottable log of the
+// notifications sent by the integrations. Every attempt to send a
+// notification is recorded with its outcome, so that the notifications of an
+// incident can be looked up afterwards.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/notify"
+)
+
+// Entry is an attempt to send a notification.
+type Entry struct {
+	// The time the attempt started.
+	Time time.Time `json:"time"`
+	// The name of the receiver and the integration the notification was
+	// sent to.
+	Receiver    string `json:"receiver"`
+	Integration string `json:"integration"`
+	Idx         int    `json:"idx"`
+	// The aggregation group the notification belongs to.
+	GroupKey    string         `json:"groupKey"`
+	GroupLabels model.LabelSet `json:"groupLabels"`
+	// The fingerprints of the alerts that were part of the notification.
+	Fingerprints []model.Fingerprint `json:"fingerprints"`
+	// The number of the attempt, starting at 1.
+	Attempt int `json:"attempt"`
+	// One of the notify.Attempt* outcomes.
+	Outcome string `json:"outcome"`
+	// The status code answered by the receiver and the error of a failed
+	// attempt.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Query selects entries from the log. Empty fields match all entries.
+type Query struct {
+	Receiver string
+	Since    time.Time
+	Until    time.Time
+}
+
+func (q Query) matches(e *Entry) bool {
+	if q.Receiver != "" && e.Receiver != q.Receiver {
+		return false
+	}
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && e.Time.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Log stores the history of notifications. It is a notify.AttemptRecorder.
+type Log struct {
+	logger     log.Logger
+	metrics    *metrics
+	now        func() time.Time
+	retention  time.Duration
+	maxEntries int
+
+	mtx sync.RWMutex
+	// entries are sorted by time, oldest first.
+	entries []*Entry
+}
+
+// MaintenanceFunc represents the function to run as part of the periodic
+// maintenance for the log. It returns the size of the snapshot taken or an
+// error if it failed.
+type MaintenanceFunc func() (int64, error)
+
+type metrics struct {
+	entries          prometheus.GaugeFunc
+	droppedTotal     prometheus.Counter
+	gcDuration       prometheus.Summary
+	snapshotDuration prometheus.Summary
+	snapshotSize     prometheus.Gauge
+}
+
+func newMetrics(r prometheus.Registerer, l *Log) *metrics {
+	m := &metrics{}
+
+	m.entries = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "alertmanager_notification_history_entries",
+		Help: "Number of notification attempts currently held in the notification history.",
+	}, func() float64 {
+		return float64(l.Len())
+	})
+	m.droppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "alertmanager_notification_history_dropped_total",
+		Help: "Total number of notification attempts dropped from the notification history because it was full.",
+	})
+	m.gcDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "alertmanager_notification_history_gc_duration_seconds",
+		Help:       "Duration of the last notification history garbage collection cycle.",
+		Objectives: map[float64]float64{},
+	})
+	m.snapshotDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "alertmanager_notification_history_snapshot_duration_seconds",
+		Help:       "Duration of the last notification history snapshot.",
+		Objectives: map[float64]float64{},
+	})
+	m.snapshotSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "alertmanager_notification_history_snapshot_size_bytes",
+		Help: "Size of the last notification history snapshot in bytes.",
+	})
+
+	if r != nil {
+		r.MustRegister(
+			m.entries,
+			m.droppedTotal,
+			m.gcDuration,
+			m.snapshotDuration,
+			m.snapshotSize,
+		)
+	}
+	return m
+}
+
+// Options exposes configuration options for creating a new Log object.
+type Options struct {
+	// A snapshot file or reader from which the initial state is loaded.
+	// None or only one of them must be set.
+	SnapshotFile   string
+	SnapshotReader io.Reader
+
+	// Retention time for entries. Entries are garbage collected once they
+	// are older than the given duration.
+	Retention time.Duration
+	// The maximum number of entries held by the log. Once reached, the
+	// oldest entry is dropped for every new one. The zero value means no
+	// limit.
+	MaxEntries int
+
+	// A logger used by background processing.
+	Logger  log.Logger
+	Metrics prometheus.Registerer
+}
+
+func (o *Options) validate() error {
+	if o.SnapshotFile != "" && o.SnapshotReader != nil {
+		return fmt.Errorf("only one of SnapshotFile and SnapshotReader must be set")
+	}
+	if o.MaxEntries < 0 {
+		return fmt.Errorf("max entries must not be negative")
+	}
+	return nil
+}
+
+// New returns a new Log object with the given configuration.
+func New(o Options) (*Log, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if o.SnapshotFile != "" {
+		if r, err := os.Open(o.SnapshotFile); err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		} else {
+			defer r.Close()
+			o.SnapshotReader = r
+		}
+	}
+	l := &Log{
+		logger:     log.NewNopLogger(),
+		now:        utcNow,
+		retention:  o.Retention,
+		maxEntries: o.MaxEntries,
+	}
+	l.metrics = newMetrics(o.Metrics, l)
+
+	if o.Logger != nil {
+		l.logger = o.Logger
+	}
+	if o.SnapshotReader != nil {
+		if err := l.loadSnapshot(o.SnapshotReader); err != nil {
+			return l, err
+		}
+	}
+	return l, nil
+}
+
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+func cloneEntry(e *Entry) *Entry {
+	c := *e
+	c.GroupLabels = e.GroupLabels.Clone()
+	c.Fingerprints = append([]model.Fingerprint(nil), e.Fingerprints...)
+	return &c
+}
+
+// RecordAttempt implements the notify.AttemptRecorder interface.
+func (l *Log) RecordAttempt(a notify.Attempt) {
+	l.Add(&Entry{
+		Time:         a.Time.UTC(),
+		Receiver:     a.Receiver,
+		Integration:  a.Integration,
+		Idx:          a.Index,
+		GroupKey:     a.GroupKey,
+		GroupLabels:  a.GroupLabels,
+		Fingerprints: a.Fingerprints,
+		Attempt:      a.Number,
+		Outcome:      a.Outcome,
+		StatusCode:   a.StatusCode,
+		Error:        a.Error,
+	})
+}
+
+// Add stores a new entry in the log. The time is set if it is empty.
+func (l *Log) Add(e *Entry) {
+	e = cloneEntry(e)
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if e.Time.IsZero() {
+		e.Time = l.now()
+	}
+	if l.maxEntries > 0 && len(l.entries) >= l.maxEntries {
+		n := len(l.entries) - l.maxEntries + 1
+		l.entries = append(l.entries[:0:0], l.entries[n:]...)
+		l.metrics.droppedTotal.Add(float64(n))
+		level.Debug(l.logger).Log("msg", "Notification history is full, dropping oldest entries", "count", n)
+	}
+	// Attempts are mostly recorded in order, the entry is inserted after
+	// the entries which aren't more recent.
+	i := sort.Search(len(l.entries), func(i int) bool { return l.entries[i].Time.After(e.Time) })
+	l.entries = append(l.entries, nil)
+	copy(l.entries[i+1:], l.entries[i:])
+	l.entries[i] = e
+}
+
+// Query returns the entries matching the query, oldest first.
+func (l *Log) Query(q Query) []*Entry {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	var res []*Entry
+	for _, e := range l.entries {
+		if q.matches(e) {
+			res = append(res, cloneEntry(e))
+		}
+	}
+	return res
+}
+
+// Len returns the number of entries in the log.
+func (l *Log) Len() int {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	return len(l.entries)
+}
+
+// GC removes entries that are older than the configured retention time.
+func (l *Log) GC() (int, error) {
+	start := time.Now()
+	defer func() { l.metrics.gcDuration.Observe(time.Since(start).Seconds()) }()
+
+	if l.retention <= 0 {
+		return 0, nil
+	}
+	now := l.now()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	n := sort.Search(len(l.entries), func(i int) bool { return l.entries[i].Time.Add(l.retention).After(now) })
+	if n > 0 {
+		l.entries = append(l.entries[:0:0], l.entries[n:]...)
+	}
+	return n, nil
+}
+
+// Maintenance garbage collects the log at the given interval. If the snapshot
+// file is set, a snapshot is written to it afterwards.
+// Terminates on receiving from stopc.
+// If not nil, the last argument is an override for what to do as part of the maintenance - for advanced usage.
+func (l *Log) Maintenance(interval time.Duration, snapf string, stopc <-chan struct{}, override MaintenanceFunc) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	doMaintenance := func() (int64, error) {
+		var size int64
+
+		if _, err := l.GC(); err != nil {
+			return size, err
+		}
+		if snapf == "" {
+			return size, nil
+		}
+		f, err := openReplace(snapf)
+		if err != nil {
+			return size, err
+		}
+		if size, err = l.Snapshot(f); err != nil {
+			return size, err
+		}
+		return size, f.Close()
+	}
+
+	if override != nil {
+		doMaintenance = override
+	}
+
+	runMaintenance := func(do MaintenanceFunc) error {
+		start := l.now()
+		level.Debug(l.logger).Log("msg", "Running maintenance")
+		size, err := do()
+		level.Debug(l.logger).Log("msg", "Maintenance done", "duration", l.now().Sub(start), "size", size)
+		l.metrics.snapshotSize.Set(float64(size))
+		return err
+	}
+
+Loop:
+	for {
+		select {
+		case <-stopc:
+			break Loop
+		case <-t.C:
+			if err := runMaintenance(doMaintenance); err != nil {
+				level.Info(l.logger).Log("msg", "Running maintenance failed", "err", err)
+			}
+		}
+	}
+	// No need for final maintenance if we don't want to snapshot.
+	if snapf == "" {
+		return
+	}
+	if err := runMaintenance(doMaintenance); err != nil {
+		level.Info(l.logger).Log("msg", "Creating shutdown snapshot failed", "err", err)
+	}
+}
+
+// loadSnapshot loads a snapshot generated by Snapshot() into the state.
+func (l *Log) loadSnapshot(r io.Reader) error {
+	var entries []*Entry
+
+	dec := json.NewDecoder(r)
+	for {
+		var e Entry
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		if e.Receiver == "" {
+			return errors.New("invalid notification history entry: missing receiver")
+		}
+		entries = append(entries, &e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+	l.mtx.Lock()
+	l.entries = entries
+	l.mtx.Unlock()
+
+	return nil
+}
+
+// Snapshot writes the full internal state into the writer and returns the number of bytes
+// written.
+func (l *Log) Snapshot(w io.Writer) (int64, error) {
+	start := time.Now()
+	defer func() { l.metrics.snapshotDuration.Observe(time.Since(start).Seconds()) }()
+
+	cw := &countingWriter{w: w}
+	enc := json.NewEncoder(cw)
+	for _, e := range l.Query(Query{}) {
+		if err := enc.Encode(e); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type replaceFile struct {
+	*os.File
+	filename string
+}
+
+func (f *replaceFile) Close() error {
+	if err := f.File.Sync(); err != nil {
+		return err
+	}
+	if err := f.File.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.File.Name(), f.filename)
+}
+
+// openReplace opens a new temporary file that is moved to filename on closing.
+func openReplace(filename string) (*replaceFile, error) {
+	tmpFilename := fmt.Sprintf("%s.%x", filename, uint64(rand.Int63()))
+
+	f, err := os.Create(tmpFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &replaceFile{
+		File:     f,
+		filename: filename,
+	}
+	return rf, nil
+}
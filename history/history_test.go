@@ -0,0 +1,179 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/notify"
+)
+
+func newTestLog(t *testing.T, o Options) *Log {
+	t.Helper()
+
+	o.Metrics = prometheus.NewRegistry()
+	l, err := New(o)
+	require.NoError(t, err)
+	return l
+}
+
+func testEntry(receiver string, ts time.Time) *Entry {
+	return &Entry{
+		Time:         ts,
+		Receiver:     receiver,
+		Integration:  "webhook",
+		GroupKey:     "{}:{alertname=\"test\"}",
+		GroupLabels:  model.LabelSet{"alertname": "test"},
+		Fingerprints: []model.Fingerprint{1, 2},
+		Attempt:      1,
+		Outcome:      notify.AttemptSucceeded,
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		options Options
+		err     string
+	}{
+		{
+			options: Options{SnapshotReader: &bytes.Buffer{}},
+		},
+		{
+			options: Options{SnapshotFile: "test.bkp"},
+		},
+		{
+			options: Options{SnapshotFile: "test.bkp", SnapshotReader: &bytes.Buffer{}},
+			err:     "only one of SnapshotFile and SnapshotReader must be set",
+		},
+		{
+			options: Options{MaxEntries: -1},
+			err:     "max entries must not be negative",
+		},
+	} {
+		err := tc.options.validate()
+		if tc.err == "" {
+			require.NoError(t, err)
+			continue
+		}
+		require.EqualError(t, err, tc.err)
+	}
+}
+
+func TestLogRecordAttempt(t *testing.T) {
+	l := newTestLog(t, Options{})
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	l.RecordAttempt(notify.Attempt{
+		Time:         now,
+		Receiver:     "team-X",
+		Integration:  "webhook",
+		Index:        1,
+		GroupKey:     "{}:{}",
+		GroupLabels:  model.LabelSet{"alertname": "test"},
+		Number:       2,
+		Outcome:      notify.AttemptFailed,
+		Fingerprints: []model.Fingerprint{3},
+		StatusCode:   503,
+		Error:        "unavailable",
+	})
+	require.Equal(t, []*Entry{{
+		Time:         now,
+		Receiver:     "team-X",
+		Integration:  "webhook",
+		Idx:          1,
+		GroupKey:     "{}:{}",
+		GroupLabels:  model.LabelSet{"alertname": "test"},
+		Fingerprints: []model.Fingerprint{3},
+		Attempt:      2,
+		Outcome:      notify.AttemptFailed,
+		StatusCode:   503,
+		Error:        "unavailable",
+	}}, l.Query(Query{}))
+}
+
+func TestLogQuery(t *testing.T) {
+	l := newTestLog(t, Options{})
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Entries are returned in order even if they are recorded out of order.
+	l.Add(testEntry("team-X", now.Add(2*time.Minute)))
+	l.Add(testEntry("team-Y", now))
+	l.Add(testEntry("team-X", now.Add(time.Minute)))
+
+	times := func(q Query) []time.Time {
+		var res []time.Time
+		for _, e := range l.Query(q) {
+			res = append(res, e.Time)
+		}
+		return res
+	}
+	require.Equal(t, []time.Time{now, now.Add(time.Minute), now.Add(2 * time.Minute)}, times(Query{}))
+	require.Equal(t, []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)}, times(Query{Receiver: "team-X"}))
+	require.Equal(t, []time.Time{now.Add(time.Minute)}, times(Query{Since: now.Add(time.Minute), Until: now.Add(time.Minute)}))
+	require.Len(t, l.Query(Query{Receiver: "team-Z"}), 0)
+}
+
+func TestLogMaxEntries(t *testing.T) {
+	l := newTestLog(t, Options{MaxEntries: 2})
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		l.Add(testEntry("team-X", now.Add(time.Duration(i)*time.Minute)))
+	}
+
+	require.Equal(t, 2, l.Len())
+	require.Equal(t, now.Add(time.Minute), l.Query(Query{})[0].Time)
+}
+
+func TestLogGC(t *testing.T) {
+	l := newTestLog(t, Options{Retention: time.Hour})
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	l.Add(testEntry("team-X", time.Time{}))
+	now = now.Add(30 * time.Minute)
+	l.Add(testEntry("team-X", time.Time{}))
+
+	now = now.Add(45 * time.Minute)
+	n, err := l.GC()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	entries := l.Query(Query{})
+	require.Len(t, entries, 1)
+	require.Equal(t, now.Add(-45*time.Minute), entries[0].Time)
+}
+
+func TestLogSnapshot(t *testing.T) {
+	l := newTestLog(t, Options{})
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, r := range []string{"team-X", "team-Y"} {
+		l.Add(testEntry(r, now.Add(time.Duration(i)*time.Minute)))
+	}
+
+	var buf bytes.Buffer
+	n, err := l.Snapshot(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	l2 := newTestLog(t, Options{SnapshotReader: &buf})
+	require.Equal(t, l.Query(Query{}), l2.Query(Query{}))
+}
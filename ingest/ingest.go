@@ -0,0 +1,197 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ingest receives alerts by other means than the API, such as message
+// queues, and stores them like the alerts posted to the API.
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// retryInterval is the time ingesters wait before connecting again after an
+// error.
+const retryInterval = 5 * time.Second
+
+// PostFunc stores alerts. It fails if one of them wasn't stored.
+type PostFunc func(alerts ...*types.Alert) error
+
+type metrics struct {
+	messages       *prometheus.CounterVec
+	messagesFailed *prometheus.CounterVec
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alertmanager_ingester_messages_total",
+			Help: "The total number of messages received by ingesters.",
+		}, []string{"ingester"}),
+		messagesFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alertmanager_ingester_messages_failed_total",
+			Help: "The total number of messages received by ingesters whose alerts couldn't be decoded or stored.",
+		}, []string{"ingester"}),
+	}
+	for _, ingester := range []string{"kafka", "sqs"} {
+		m.messages.WithLabelValues(ingester)
+		m.messagesFailed.WithLabelValues(ingester)
+	}
+	if r != nil {
+		r.MustRegister(m.messages, m.messagesFailed)
+	}
+	return m
+}
+
+// handle stores the alerts decoded from a message, logging and counting the
+// failures.
+func (m *metrics) handle(ingester string, post PostFunc, l log.Logger, decode func(now time.Time) ([]*types.Alert, error)) error {
+	m.messages.WithLabelValues(ingester).Inc()
+
+	alerts, err := decode(time.Now())
+	if err == nil {
+		err = post(alerts...)
+	}
+	if err != nil {
+		m.messagesFailed.WithLabelValues(ingester).Inc()
+		level.Warn(l).Log("msg", "Failed to ingest alerts", "err", err)
+	}
+	return err
+}
+
+// alertsFromWebhook decodes the alerts of a message of the webhook
+// integration. Resolved alerts without end time end now.
+func alertsFromWebhook(b []byte, now time.Time) ([]*types.Alert, error) {
+	var msg webhook.Message
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return nil, errors.Wrap(err, "invalid webhook message")
+	}
+	if msg.Data == nil || len(msg.Alerts) == 0 {
+		return nil, errors.New("webhook message without alerts")
+	}
+
+	alerts := make([]*types.Alert, 0, len(msg.Alerts))
+	for _, a := range msg.Alerts {
+		alert := &types.Alert{
+			Alert: model.Alert{
+				Labels:       model.LabelSet{},
+				Annotations:  model.LabelSet{},
+				StartsAt:     a.StartsAt,
+				EndsAt:       a.EndsAt,
+				GeneratorURL: a.GeneratorURL,
+			},
+		}
+		for k, v := range a.Labels {
+			alert.Labels[model.LabelName(k)] = model.LabelValue(v)
+		}
+		for k, v := range a.Annotations {
+			alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
+		}
+		if a.Status == string(model.AlertResolved) && alert.EndsAt.IsZero() {
+			alert.EndsAt = now
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// Manager runs the ingesters of the configuration.
+type Manager struct {
+	mtx     sync.Mutex
+	kafka   []*config.KafkaIngesterConfig
+	sqs     []*config.SQSIngesterConfig
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	post    PostFunc
+	metrics *metrics
+	logger  log.Logger
+}
+
+// NewManager returns a manager storing the ingested alerts with post. No
+// ingester runs until a configuration is applied.
+func NewManager(post PostFunc, r prometheus.Registerer, l log.Logger) *Manager {
+	return &Manager{
+		post:    post,
+		metrics: newMetrics(r),
+		logger:  l,
+	}
+}
+
+// Update applies the ingesters of the configuration. The ingesters are
+// restarted only if their configuration changed.
+func (m *Manager) Update(c *config.Config) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.cancel != nil && reflect.DeepEqual(c.KafkaIngesters, m.kafka) && reflect.DeepEqual(c.SQSIngesters, m.sqs) {
+		return nil
+	}
+
+	var ingesters []func(context.Context)
+	for i, kc := range c.KafkaIngesters {
+		k, err := newKafkaIngester(kc, m.post, m.metrics, log.With(m.logger, "ingester", "kafka", "index", i))
+		if err != nil {
+			return err
+		}
+		ingesters = append(ingesters, k.run)
+	}
+	for i, qc := range c.SQSIngesters {
+		client, err := newSQSClient(qc)
+		if err != nil {
+			return err
+		}
+		q := newSQSIngester(qc, client, m.post, m.metrics, log.With(m.logger, "ingester", "sqs", "index", i))
+		ingesters = append(ingesters, q.run)
+	}
+
+	m.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, run := range ingesters {
+		m.wg.Add(1)
+		go func(run func(context.Context)) {
+			defer m.wg.Done()
+			run(ctx)
+		}(run)
+	}
+	m.kafka, m.sqs, m.cancel = c.KafkaIngesters, c.SQSIngesters, cancel
+	return nil
+}
+
+// Stop stops the ingesters.
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.stop()
+}
+
+func (m *Manager) stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	m.wg.Wait()
+	m.cancel = nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// The content types of the consumer API of the Kafka REST Proxy.
+const (
+	kafkaContentType = "application/vnd.kafka.v2+json"
+	kafkaJSONRecords = "application/vnd.kafka.json.v2+json"
+)
+
+// kafkaIngester consumes alerts from Kafka topics as a member of a consumer
+// group, through the consumer API of a Kafka REST Proxy. The records are
+// messages of the webhook integration. Their offsets are committed once their
+// alerts were stored, or couldn't be.
+type kafkaIngester struct {
+	conf     *config.KafkaIngesterConfig
+	client   *http.Client
+	instance string
+	post     PostFunc
+	metrics  *metrics
+	logger   log.Logger
+}
+
+type kafkaRecord struct {
+	Topic     string          `json:"topic"`
+	Partition int32           `json:"partition"`
+	Offset    int64           `json:"offset"`
+	Value     json.RawMessage `json:"value"`
+}
+
+type kafkaOffset struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+func newKafkaIngester(conf *config.KafkaIngesterConfig, post PostFunc, m *metrics, l log.Logger) (*kafkaIngester, error) {
+	client, err := commoncfg.NewClientFromConfig(*conf.HTTPConfig, "kafka")
+	if err != nil {
+		return nil, err
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	return &kafkaIngester{
+		conf:     conf,
+		client:   client,
+		instance: fmt.Sprintf("alertmanager-%x", id),
+		post:     post,
+		metrics:  m,
+		logger:   l,
+	}, nil
+}
+
+// run consumes alerts until the context is canceled. The consumer instance
+// is created again after errors.
+func (k *kafkaIngester) run(ctx context.Context) {
+	for {
+		err := k.consume(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		level.Error(k.logger).Log("msg", "Failed to consume alerts", "err", err)
+
+		select {
+		case <-time.After(retryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (k *kafkaIngester) consume(ctx context.Context) error {
+	if err := k.request(ctx, http.MethodPost, k.groupURL(), map[string]string{
+		"name":               k.instance,
+		"format":             "json",
+		"auto.offset.reset":  k.conf.AutoOffsetReset,
+		"auto.commit.enable": "false",
+	}, nil); err != nil {
+		return errors.Wrap(err, "create consumer")
+	}
+	defer func() {
+		// The consumer is deleted even if the context is canceled, so that
+		// its partitions are assigned to the other members of the group
+		// right away.
+		ctx, cancel := context.WithTimeout(context.Background(), retryInterval)
+		defer cancel()
+		if err := k.request(ctx, http.MethodDelete, k.instanceURL(), nil, nil); err != nil {
+			level.Warn(k.logger).Log("msg", "Failed to delete consumer", "err", err)
+		}
+	}()
+
+	if err := k.request(ctx, http.MethodPost, k.instanceURL()+"/subscription", map[string][]string{"topics": k.conf.Topics}, nil); err != nil {
+		return errors.Wrap(err, "subscribe")
+	}
+	level.Info(k.logger).Log("msg", "Consuming alerts", "topics", strings.Join(k.conf.Topics, ","), "group", k.conf.Group)
+
+	for ctx.Err() == nil {
+		var records []kafkaRecord
+		if err := k.request(ctx, http.MethodGet, k.recordsURL(), nil, &records); err != nil {
+			return errors.Wrap(err, "fetch records")
+		}
+		if len(records) == 0 {
+			continue
+		}
+
+		offsets := map[string]kafkaOffset{}
+		for _, r := range records {
+			k.metrics.handle("kafka", k.post, log.With(k.logger, "topic", r.Topic, "partition", r.Partition, "offset", r.Offset), func(now time.Time) ([]*types.Alert, error) {
+				return alertsFromWebhook(r.Value, now)
+			})
+			key := fmt.Sprintf("%s/%d", r.Topic, r.Partition)
+			if o, ok := offsets[key]; !ok || r.Offset > o.Offset {
+				offsets[key] = kafkaOffset{Topic: r.Topic, Partition: r.Partition, Offset: r.Offset}
+			}
+		}
+
+		// The REST Proxy commits the offsets following the last records.
+		commit := make([]kafkaOffset, 0, len(offsets))
+		for _, o := range offsets {
+			commit = append(commit, o)
+		}
+		if err := k.request(ctx, http.MethodPost, k.instanceURL()+"/offsets", map[string][]kafkaOffset{"offsets": commit}, nil); err != nil {
+			return errors.Wrap(err, "commit offsets")
+		}
+	}
+	return ctx.Err()
+}
+
+func (k *kafkaIngester) groupURL() string {
+	return strings.TrimSuffix(k.conf.RESTProxyURL.String(), "/") + "/consumers/" + url.PathEscape(k.conf.Group)
+}
+
+func (k *kafkaIngester) instanceURL() string {
+	return k.groupURL() + "/instances/" + url.PathEscape(k.instance)
+}
+
+func (k *kafkaIngester) recordsURL() string {
+	return fmt.Sprintf("%s/records?timeout=%d", k.instanceURL(), time.Duration(k.conf.PollTimeout).Milliseconds())
+}
+
+// request sends a request to the REST Proxy with the JSON encoding of in as
+// body, if not nil, and decodes the JSON response into out, if not nil.
+func (k *kafkaIngester) request(ctx context.Context, method, u string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	// Fetching records waits up to the poll timeout for records.
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(k.conf.PollTimeout)+30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	if out != nil {
+		req.Header.Set("Accept", kafkaJSONRecords)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
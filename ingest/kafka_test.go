@@ -0,0 +1,188 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const webhookMessage = `{
+  "version": "4",
+  "status": "firing",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighLatency", "service": "api"},
+      "annotations": {"summary": "Latency is high"},
+      "startsAt": "2021-06-01T10:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus/graph"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "HighErrorRate"},
+      "startsAt": "2021-06-01T09:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z"
+    }
+  ]
+}`
+
+func TestAlertsFromWebhook(t *testing.T) {
+	now := time.Date(2021, 6, 1, 10, 5, 0, 0, time.UTC)
+	alerts, err := alertsFromWebhook([]byte(webhookMessage), now)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+
+	require.Equal(t, model.LabelSet{"alertname": "HighLatency", "service": "api"}, alerts[0].Labels)
+	require.Equal(t, model.LabelSet{"summary": "Latency is high"}, alerts[0].Annotations)
+	require.Equal(t, time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC), alerts[0].StartsAt)
+	require.True(t, alerts[0].EndsAt.IsZero())
+	require.Equal(t, "http://prometheus/graph", alerts[0].GeneratorURL)
+	require.Equal(t, now, alerts[1].EndsAt)
+
+	_, err = alertsFromWebhook([]byte(`{"version": "4"}`), now)
+	require.EqualError(t, err, "webhook message without alerts")
+	_, err = alertsFromWebhook([]byte(`[]`), now)
+	require.Error(t, err)
+}
+
+// fakeRESTProxy implements the consumer API of the Kafka REST Proxy for a
+// single batch of records.
+type fakeRESTProxy struct {
+	mtx      sync.Mutex
+	records  []kafkaRecord
+	created  map[string]string
+	topics   []string
+	offsets  []kafkaOffset
+	deleted  bool
+	polls    int
+	rejected bool
+}
+
+func (p *fakeRESTProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if r.Header.Get("Content-Type") != kafkaContentType {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/consumers/alertmanager":
+		json.Unmarshal(body, &p.created)
+		w.Write([]byte(`{"instance_id": "` + p.created["name"] + `", "base_uri": "http://internal"}`))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/subscription"):
+		var sub map[string][]string
+		json.Unmarshal(body, &sub)
+		p.topics = sub["topics"]
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/records"):
+		if r.Header.Get("Accept") != kafkaJSONRecords || r.URL.Query().Get("timeout") != "1000" {
+			p.rejected = true
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		p.polls++
+		records := p.records
+		p.records = nil
+		json.NewEncoder(w).Encode(append([]kafkaRecord{}, records...))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/offsets"):
+		var commit map[string][]kafkaOffset
+		json.Unmarshal(body, &commit)
+		p.offsets = append(p.offsets, commit["offsets"]...)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/consumers/alertmanager/instances/"):
+		p.deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestKafkaIngester(t *testing.T) {
+	proxy := &fakeRESTProxy{
+		records: []kafkaRecord{
+			{Topic: "alerts", Partition: 0, Offset: 41, Value: json.RawMessage(webhookMessage)},
+			{Topic: "alerts", Partition: 0, Offset: 42, Value: json.RawMessage(`"invalid"`)},
+			{Topic: "alerts", Partition: 1, Offset: 7, Value: json.RawMessage(webhookMessage)},
+		},
+	}
+	srv := httptest.NewServer(proxy)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	var (
+		mtx    sync.Mutex
+		posted []*types.Alert
+	)
+	post := func(alerts ...*types.Alert) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		posted = append(posted, alerts...)
+		return nil
+	}
+	m := NewManager(post, nil, log.NewNopLogger())
+	conf := &config.Config{
+		KafkaIngesters: []*config.KafkaIngesterConfig{{
+			RESTProxyURL:    &config.URL{URL: u},
+			Topics:          []string{"alerts"},
+			Group:           "alertmanager",
+			AutoOffsetReset: "earliest",
+			PollTimeout:     model.Duration(time.Second),
+			HTTPConfig:      &commoncfg.HTTPClientConfig{},
+		}},
+	}
+	require.NoError(t, m.Update(conf))
+
+	require.Eventually(t, func() bool {
+		proxy.mtx.Lock()
+		defer proxy.mtx.Unlock()
+		return proxy.polls > 1
+	}, 5*time.Second, 10*time.Millisecond)
+	m.Stop()
+
+	proxy.mtx.Lock()
+	defer proxy.mtx.Unlock()
+	require.False(t, proxy.rejected)
+	require.Equal(t, "earliest", proxy.created["auto.offset.reset"])
+	require.Equal(t, "false", proxy.created["auto.commit.enable"])
+	require.Equal(t, []string{"alerts"}, proxy.topics)
+	require.ElementsMatch(t, []kafkaOffset{
+		{Topic: "alerts", Partition: 0, Offset: 42},
+		{Topic: "alerts", Partition: 1, Offset: 7},
+	}, proxy.offsets)
+	require.True(t, proxy.deleted)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Len(t, posted, 4)
+}
@@ -0,0 +1,252 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// sqsIngester consumes alerts from an SQS queue. Every message is mapped to
+// an alert. The messages are deleted once their alert was stored, and the
+// others are received again after the visibility timeout of the queue, or
+// moved to its dead-letter queue.
+type sqsIngester struct {
+	conf    *config.SQSIngesterConfig
+	client  sqsiface.SQSAPI
+	post    PostFunc
+	metrics *metrics
+	logger  log.Logger
+}
+
+func newSQSClient(conf *config.SQSIngesterConfig) (sqsiface.SQSAPI, error) {
+	httpClient, err := commoncfg.NewClientFromConfig(*conf.HTTPConfig, "sqs")
+	if err != nil {
+		return nil, err
+	}
+
+	var creds *credentials.Credentials
+	if conf.Sigv4.AccessKey != "" && conf.Sigv4.SecretKey != "" {
+		creds = credentials.NewStaticCredentials(conf.Sigv4.AccessKey, string(conf.Sigv4.SecretKey), "")
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config: aws.Config{
+			Region:   aws.String(conf.Sigv4.Region),
+			Endpoint: aws.String(conf.APIUrl),
+		},
+		Profile: conf.Sigv4.Profile,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if aws.StringValue(sess.Config.Region) == "" {
+		return nil, fmt.Errorf("region not configured in sqs_ingesters.sigv4.region or in default credentials chain")
+	}
+
+	if conf.Sigv4.RoleARN != "" {
+		stsSess := sess
+		if conf.APIUrl != "" {
+			// The STS credentials aren't requested from the SQS endpoint.
+			stsSess, err = session.NewSessionWithOptions(session.Options{
+				Config: aws.Config{
+					Region:      aws.String(conf.Sigv4.Region),
+					Credentials: creds,
+				},
+				Profile: conf.Sigv4.Profile,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		creds = stscreds.NewCredentials(stsSess, conf.Sigv4.RoleARN)
+	}
+	return sqs.New(sess, &aws.Config{Credentials: creds, HTTPClient: httpClient}), nil
+}
+
+func newSQSIngester(conf *config.SQSIngesterConfig, client sqsiface.SQSAPI, post PostFunc, m *metrics, l log.Logger) *sqsIngester {
+	return &sqsIngester{
+		conf:    conf,
+		client:  client,
+		post:    post,
+		metrics: m,
+		logger:  l,
+	}
+}
+
+// run consumes alerts until the context is canceled.
+func (q *sqsIngester) run(ctx context.Context) {
+	level.Info(q.logger).Log("msg", "Consuming alerts", "queue", q.conf.QueueURL)
+	for {
+		err := q.receive(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			continue
+		}
+		level.Error(q.logger).Log("msg", "Failed to consume alerts", "err", err)
+
+		select {
+		case <-time.After(retryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (q *sqsIngester) receive(ctx context.Context) error {
+	out, err := q.client.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(q.conf.QueueURL),
+		MaxNumberOfMessages: aws.Int64(q.conf.MaxMessages),
+		WaitTimeSeconds:     aws.Int64(int64(time.Duration(q.conf.WaitTime) / time.Second)),
+	})
+	if err != nil {
+		return errors.Wrap(err, "receive messages")
+	}
+
+	var entries []*sqs.DeleteMessageBatchRequestEntry
+	for i, msg := range out.Messages {
+		body := aws.StringValue(msg.Body)
+		if err := q.metrics.handle("sqs", q.post, log.With(q.logger, "message_id", aws.StringValue(msg.MessageId)), func(now time.Time) ([]*types.Alert, error) {
+			a, err := q.alertFromMessage(body, now)
+			if err != nil {
+				return nil, err
+			}
+			return []*types.Alert{a}, nil
+		}); err != nil {
+			continue
+		}
+		entries = append(entries, &sqs.DeleteMessageBatchRequestEntry{
+			Id:            aws.String(strconv.Itoa(i)),
+			ReceiptHandle: msg.ReceiptHandle,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// The messages are deleted even if the context is canceled, so that
+	// their alerts aren't ingested twice.
+	ctx, cancel := context.WithTimeout(context.Background(), retryInterval)
+	defer cancel()
+	del, err := q.client.DeleteMessageBatchWithContext(ctx, &sqs.DeleteMessageBatchInput{
+		QueueUrl: aws.String(q.conf.QueueURL),
+		Entries:  entries,
+	})
+	if err != nil {
+		return errors.Wrap(err, "delete messages")
+	}
+	for _, f := range del.Failed {
+		level.Warn(q.logger).Log("msg", "Failed to delete message", "id", aws.StringValue(f.Id), "err", aws.StringValue(f.Message))
+	}
+	return nil
+}
+
+// alertFromMessage maps the fields of a JSON message to an alert. Messages
+// published through SNS are unwrapped from their notification.
+func (q *sqsIngester) alertFromMessage(body string, now time.Time) (*types.Alert, error) {
+	var msg map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &msg); err != nil {
+		return nil, errors.Wrap(err, "message is not a JSON object")
+	}
+	if msg["Type"] == "Notification" {
+		inner, ok := msg["Message"].(string)
+		if !ok {
+			return nil, errors.New("SNS notification without message")
+		}
+		msg = nil
+		if err := json.Unmarshal([]byte(inner), &msg); err != nil {
+			return nil, errors.Wrap(err, "SNS notification message is not a JSON object")
+		}
+	}
+
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{},
+			Annotations: model.LabelSet{},
+		},
+	}
+	for name, path := range q.conf.Labels {
+		if v, ok := messageField(msg, path); ok && v != "" {
+			alert.Labels[model.LabelName(name)] = model.LabelValue(v)
+		}
+	}
+	for name, path := range q.conf.Annotations {
+		if v, ok := messageField(msg, path); ok && v != "" {
+			alert.Annotations[model.LabelName(name)] = model.LabelValue(v)
+		}
+	}
+	if q.conf.StatusField != "" {
+		if status, ok := messageField(msg, q.conf.StatusField); ok {
+			for _, v := range q.conf.ResolvedValues {
+				if status == v {
+					alert.EndsAt = now
+					break
+				}
+			}
+		}
+	}
+	return alert, nil
+}
+
+// messageField returns the value of the field of a decoded JSON message at
+// the dot-separated path. Objects and arrays are returned as JSON.
+func messageField(msg map[string]interface{}, path string) (string, bool) {
+	var v interface{} = msg
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if v, ok = obj[key]; !ok {
+			return "", false
+		}
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+}
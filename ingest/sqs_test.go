@@ -0,0 +1,176 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const cloudWatchAlarm = `{
+  "AlarmName": "HighCPU",
+  "AlarmDescription": "CPU above 90%",
+  "NewStateValue": "STATE",
+  "NewStateReason": "Threshold Crossed",
+  "Region": "EU (Ireland)",
+  "Trigger": {"MetricName": "CPUUtilization", "Threshold": 90.5, "Dimensions": [{"name": "InstanceId", "value": "i-123"}]}
+}`
+
+func cloudWatchMessage(state string) string {
+	return strings.Replace(cloudWatchAlarm, "STATE", state, 1)
+}
+
+func sqsConfig(t *testing.T, in string) *config.SQSIngesterConfig {
+	var c config.SQSIngesterConfig
+	require.NoError(t, yaml.UnmarshalStrict([]byte("queue_url: https://sqs.eu-west-1.amazonaws.com/123/alerts\n"+in), &c))
+	return &c
+}
+
+func TestSQSAlertFromMessage(t *testing.T) {
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	q := newSQSIngester(sqsConfig(t, `
+labels:
+  alertname: AlarmName
+  metric: Trigger.MetricName
+  threshold: Trigger.Threshold
+  missing: Trigger.Unit
+annotations:
+  dimensions: Trigger.Dimensions
+`), nil, nil, nil, log.NewNopLogger())
+
+	a, err := q.alertFromMessage(cloudWatchMessage("ALARM"), now)
+	require.NoError(t, err)
+	require.Equal(t, model.LabelSet{"alertname": "HighCPU", "metric": "CPUUtilization", "threshold": "90.5"}, a.Labels)
+	require.Equal(t, model.LabelSet{"dimensions": `[{"name":"InstanceId","value":"i-123"}]`}, a.Annotations)
+	require.True(t, a.EndsAt.IsZero())
+
+	// Messages published through SNS are unwrapped.
+	envelope, err := json.Marshal(map[string]string{
+		"Type":    "Notification",
+		"Message": cloudWatchMessage("OK"),
+	})
+	require.NoError(t, err)
+	a, err = q.alertFromMessage(string(envelope), now)
+	require.NoError(t, err)
+	require.Equal(t, model.LabelValue("HighCPU"), a.Labels["alertname"])
+	require.Equal(t, now, a.EndsAt)
+
+	_, err = q.alertFromMessage("ALARM: HighCPU", now)
+	require.Error(t, err)
+	_, err = q.alertFromMessage(`{"Type": "Notification", "Message": "ALARM: HighCPU"}`, now)
+	require.Error(t, err)
+}
+
+func TestSQSDefaultMapping(t *testing.T) {
+	q := newSQSIngester(sqsConfig(t, ""), nil, nil, nil, log.NewNopLogger())
+	a, err := q.alertFromMessage(cloudWatchMessage("INSUFFICIENT_DATA"), time.Now())
+	require.NoError(t, err)
+	require.Equal(t, model.LabelSet{"alertname": "HighCPU"}, a.Labels)
+	require.Equal(t, model.LabelSet{"description": "CPU above 90%", "reason": "Threshold Crossed"}, a.Annotations)
+	require.True(t, a.EndsAt.IsZero())
+}
+
+type fakeSQS struct {
+	sqsiface.SQSAPI
+
+	mtx      sync.Mutex
+	messages []*sqs.Message
+	deleted  []string
+}
+
+func (f *fakeSQS) ReceiveMessageWithContext(ctx aws.Context, in *sqs.ReceiveMessageInput, _ ...request.Option) (*sqs.ReceiveMessageOutput, error) {
+	f.mtx.Lock()
+	msgs := f.messages
+	f.messages = nil
+	f.mtx.Unlock()
+
+	if len(msgs) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &sqs.ReceiveMessageOutput{Messages: msgs}, nil
+}
+
+func (f *fakeSQS) DeleteMessageBatchWithContext(_ aws.Context, in *sqs.DeleteMessageBatchInput, _ ...request.Option) (*sqs.DeleteMessageBatchOutput, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, e := range in.Entries {
+		f.deleted = append(f.deleted, aws.StringValue(e.ReceiptHandle))
+	}
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
+
+func TestSQSIngester(t *testing.T) {
+	client := &fakeSQS{
+		messages: []*sqs.Message{
+			{MessageId: aws.String("1"), ReceiptHandle: aws.String("r1"), Body: aws.String(cloudWatchMessage("ALARM"))},
+			{MessageId: aws.String("2"), ReceiptHandle: aws.String("r2"), Body: aws.String("invalid")},
+			{MessageId: aws.String("3"), ReceiptHandle: aws.String("r3"), Body: aws.String(`{"AlarmName": "Rejected"}`)},
+		},
+	}
+	var (
+		mtx    sync.Mutex
+		posted []*types.Alert
+	)
+	post := func(alerts ...*types.Alert) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if alerts[0].Name() == "Rejected" {
+			return errors.New("rejected")
+		}
+		posted = append(posted, alerts...)
+		return nil
+	}
+	m := newMetrics(nil)
+	q := newSQSIngester(sqsConfig(t, ""), client, post, m, log.NewNopLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		client.mtx.Lock()
+		defer client.mtx.Unlock()
+		return len(client.deleted) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	// Only the message whose alert was stored is deleted.
+	require.Equal(t, []string{"r1"}, client.deleted)
+	require.Len(t, posted, 1)
+	require.Equal(t, "HighCPU", posted[0].Name())
+	require.Equal(t, 3.0, testutil.ToFloat64(m.messages.WithLabelValues("sqs")))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.messagesFailed.WithLabelValues("sqs")))
+}
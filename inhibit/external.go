@@ -0,0 +1,170 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inhibit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// externalSource polls an external check for the label sets acting as the
+// source alerts of an inhibition rule.
+type externalSource struct {
+	conf *config.ExternalInhibitSource
+
+	mtx   sync.RWMutex
+	lsets []model.LabelSet
+}
+
+func newExternalSource(conf *config.ExternalInhibitSource) *externalSource {
+	return &externalSource{conf: conf}
+}
+
+// labelSets returns the label sets of the last successful poll.
+func (s *externalSource) labelSets() []model.LabelSet {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.lsets
+}
+
+func (s *externalSource) run(ctx context.Context, l log.Logger) {
+	httpConfig := commoncfg.DefaultHTTPClientConfig
+	if s.conf.HTTPConfig != nil {
+		httpConfig = *s.conf.HTTPConfig
+	}
+	client, err := commoncfg.NewClientFromConfig(httpConfig, "inhibit")
+	if err != nil {
+		level.Error(l).Log("msg", "Failed to create client for external inhibition source", "err", err)
+		return
+	}
+
+	t := time.NewTicker(time.Duration(s.conf.Interval))
+	defer t.Stop()
+	for {
+		s.poll(ctx, client, l)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// poll updates the label sets from the external check. If the check fails,
+// the label sets are cleared so that a broken check does not keep alerts
+// inhibited.
+func (s *externalSource) poll(ctx context.Context, client *http.Client, l log.Logger) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.conf.Timeout))
+	defer cancel()
+
+	var (
+		lsets []model.LabelSet
+		err   error
+	)
+	if s.conf.PrometheusURL != nil {
+		lsets, err = s.query(ctx, client)
+	} else {
+		lsets, err = s.fetch(ctx, client)
+	}
+	if err != nil {
+		level.Warn(l).Log("msg", "Failed to poll external inhibition source", "err", err)
+	}
+
+	s.mtx.Lock()
+	s.lsets = lsets
+	s.mtx.Unlock()
+}
+
+func get(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// fetch returns the label sets listed by the configured URL.
+func (s *externalSource) fetch(ctx context.Context, client *http.Client) ([]model.LabelSet, error) {
+	var lsets []model.LabelSet
+	if err := get(ctx, client, s.conf.URL.String(), &lsets); err != nil {
+		return nil, err
+	}
+	for _, lset := range lsets {
+		if err := lset.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return lsets, nil
+}
+
+// query returns the label sets of the series returned by the configured
+// Prometheus query.
+func (s *externalSource) query(ctx context.Context, client *http.Client) ([]model.LabelSet, error) {
+	u := *s.conf.PrometheusURL.URL
+	u.Path = strings.TrimRight(u.Path, "/") + "/api/v1/query"
+	q := u.Query()
+	q.Set("query", s.conf.Query)
+	u.RawQuery = q.Encode()
+
+	var resp struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err := get(ctx, client, u.String(), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s", resp.Error)
+	}
+	if resp.Data.ResultType != model.ValVector.String() {
+		return nil, fmt.Errorf("unexpected result type %q, expected vector", resp.Data.ResultType)
+	}
+	var vec model.Vector
+	if err := json.Unmarshal(resp.Data.Result, &vec); err != nil {
+		return nil, err
+	}
+
+	lsets := make([]model.LabelSet, 0, len(vec))
+	for _, s := range vec {
+		lset := model.LabelSet(s.Metric).Clone()
+		delete(lset, model.MetricNameLabel)
+		lsets = append(lsets, lset)
+	}
+	return lsets, nil
+}
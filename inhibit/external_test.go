@@ -0,0 +1,94 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inhibit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+func parseURL(t *testing.T, s string) *config.URL {
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	return &config.URL{URL: u}
+}
+
+func TestExternalSourceURL(t *testing.T) {
+	body := `[{"cluster": "eu1"}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	r := NewInhibitRule(&config.InhibitRule{
+		TargetMatch: map[string]string{"severity": "warning"},
+		Equal:       model.LabelNames{"cluster"},
+		ExternalSource: &config.ExternalInhibitSource{
+			URL:      parseURL(t, srv.URL),
+			Interval: model.Duration(time.Minute),
+			Timeout:  model.Duration(time.Second),
+		},
+	})
+	r.external.poll(context.Background(), srv.Client(), nopLogger)
+
+	fp, ok := r.hasEqual(model.LabelSet{"severity": "warning", "cluster": "eu1"}, false)
+	require.True(t, ok)
+	source, ok := r.source(fp)
+	require.True(t, ok)
+	require.Equal(t, model.LabelSet{"cluster": "eu1"}, source.Labels)
+
+	_, ok = r.hasEqual(model.LabelSet{"severity": "warning", "cluster": "eu2"}, false)
+	require.False(t, ok)
+
+	// A failing check stops inhibiting.
+	body = ""
+	r.external.poll(context.Background(), srv.Client(), nopLogger)
+	_, ok = r.hasEqual(model.LabelSet{"severity": "warning", "cluster": "eu1"}, false)
+	require.False(t, ok)
+}
+
+func TestExternalSourcePrometheus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/prometheus/api/v1/query", r.URL.Path)
+		require.Equal(t, "failover_in_progress == 1", r.URL.Query().Get("query"))
+		fmt.Fprint(w, `{"status": "success", "data": {"resultType": "vector", "result": [
+			{"metric": {"__name__": "failover_in_progress", "cluster": "eu1"}, "value": [1600000000, "1"]},
+			{"metric": {"__name__": "failover_in_progress", "cluster": "us1"}, "value": [1600000000, "1"]}
+		]}}`)
+	}))
+	defer srv.Close()
+
+	s := newExternalSource(&config.ExternalInhibitSource{
+		PrometheusURL: parseURL(t, srv.URL+"/prometheus/"),
+		Query:         "failover_in_progress == 1",
+		Interval:      model.Duration(time.Minute),
+		Timeout:       model.Duration(time.Second),
+	})
+	s.poll(context.Background(), srv.Client(), nopLogger)
+	require.ElementsMatch(t, []model.LabelSet{{"cluster": "eu1"}, {"cluster": "us1"}}, s.labelSets())
+}
@@ -72,7 +72,7 @@ func (ih *Inhibitor) run(ctx context.Context) {
 			}
 			// Update the inhibition rules' cache.
 			for _, r := range ih.rules {
-				if r.SourceMatchers.Matches(a.Labels) {
+				if r.external == nil && r.SourceMatchers.Matches(a.Labels) {
 					if err := r.scache.Set(a); err != nil {
 						level.Error(ih.logger).Log("msg", "error on set alert", "err", err)
 					}
@@ -96,6 +96,9 @@ func (ih *Inhibitor) Run() {
 
 	for _, rule := range ih.rules {
 		go rule.scache.Run(runCtx, 15*time.Minute)
+		if rule.external != nil {
+			go rule.external.run(runCtx, ih.logger)
+		}
 	}
 
 	g.Add(func() error {
@@ -128,7 +131,19 @@ func (ih *Inhibitor) Stop() {
 func (ih *Inhibitor) Mutes(lset model.LabelSet) bool {
 	fp := lset.Fingerprint()
 
-	for _, r := range ih.rules {
+	if _, inhibitedByFP, ok := ih.inhibitedBy(lset); ok {
+		ih.marker.SetInhibited(fp, inhibitedByFP.String())
+		return true
+	}
+	ih.marker.SetInhibited(fp)
+
+	return false
+}
+
+// inhibitedBy returns the index of the first rule inhibiting the given label
+// set and the fingerprint of the inhibiting alert.
+func (ih *Inhibitor) inhibitedBy(lset model.LabelSet) (int, model.Fingerprint, bool) {
+	for i, r := range ih.rules {
 		if !r.TargetMatchers.Matches(lset) {
 			// If target side of rule doesn't match, we don't need to look any further.
 			continue
@@ -136,13 +151,49 @@ func (ih *Inhibitor) Mutes(lset model.LabelSet) bool {
 		// If we are here, the target side matches. If the source side matches, too, we
 		// need to exclude inhibiting alerts for which the same is true.
 		if inhibitedByFP, eq := r.hasEqual(lset, r.SourceMatchers.Matches(lset)); eq {
-			ih.marker.SetInhibited(fp, inhibitedByFP.String())
-			return true
+			return i, inhibitedByFP, true
 		}
 	}
-	ih.marker.SetInhibited(fp)
+	return 0, model.Fingerprint(0), false
+}
 
-	return false
+// An Inhibition is an alert currently inhibited by another alert.
+type Inhibition struct {
+	// The inhibited alert.
+	Target *types.Alert
+	// The alert inhibiting the target.
+	Source *types.Alert
+	// The index of the inhibiting rule in the configuration.
+	Rule int
+}
+
+// Inhibitions returns the active alerts which are currently inhibited along
+// with the alerts and rules inhibiting them.
+func (ih *Inhibitor) Inhibitions() []Inhibition {
+	if ih == nil {
+		return nil
+	}
+
+	it := ih.alerts.GetPending()
+	defer it.Close()
+
+	var res []Inhibition
+	for a := range it.Next() {
+		if a.Resolved() {
+			continue
+		}
+		i, fp, ok := ih.inhibitedBy(a.Labels)
+		if !ok {
+			continue
+		}
+		// The source alert may have been removed from the cache since.
+		source, ok := ih.rules[i].source(fp)
+		if !ok {
+			continue
+		}
+		res = append(res, Inhibition{Target: a, Source: source, Rule: i})
+	}
+	return res
 }
 
 // An InhibitRule specifies that a class of (source) alerts should inhibit
@@ -160,9 +211,14 @@ type InhibitRule struct {
 	// A set of label names whose label values need to be identical in source and
 	// target alerts in order for the inhibition to take effect.
 	Equal map[model.LabelName]struct{}
+	// Whether the labels in Equal must be present on both alerts rather than
+	// being considered equal when absent on both.
+	RequireEqualLabels bool
 
 	// Cache of alerts matching source labels.
 	scache *store.Alerts
+	// The external check replacing the source alerts, if any.
+	external *externalSource
 }
 
 // NewInhibitRule returns a new InhibitRule based on a configuration definition.
@@ -218,12 +274,45 @@ func NewInhibitRule(cr *config.InhibitRule) *InhibitRule {
 		equal[ln] = struct{}{}
 	}
 
-	return &InhibitRule{
-		SourceMatchers: sourcem,
-		TargetMatchers: targetm,
-		Equal:          equal,
-		scache:         store.NewAlerts(),
+	r := &InhibitRule{
+		SourceMatchers:     sourcem,
+		TargetMatchers:     targetm,
+		Equal:              equal,
+		RequireEqualLabels: cr.RequireEqualLabels,
+		scache:             store.NewAlerts(),
+	}
+	if cr.ExternalSource != nil {
+		r.external = newExternalSource(cr.ExternalSource)
+	}
+	return r
+}
+
+// source returns the source alert with the given fingerprint.
+func (r *InhibitRule) source(fp model.Fingerprint) (*types.Alert, bool) {
+	if r.external != nil {
+		for _, lset := range r.external.labelSets() {
+			if lset.Fingerprint() == fp {
+				return &types.Alert{Alert: model.Alert{Labels: lset}}, true
+			}
+		}
+		return nil, false
+	}
+	a, err := r.scache.Get(fp)
+	return a, err == nil
+}
+
+// equal returns true iff the source and target label sets have the same
+// values for the equal labels of the rule.
+func (r *InhibitRule) equal(source, target model.LabelSet) bool {
+	for n := range r.Equal {
+		if r.RequireEqualLabels && target[n] == "" {
+			return false
+		}
+		if source[n] != target[n] {
+			return false
+		}
 	}
+	return true
 }
 
 // hasEqual checks whether the source cache contains alerts matching the equal
@@ -231,19 +320,25 @@ func NewInhibitRule(cr *config.InhibitRule) *InhibitRule {
 // is returned. If excludeTwoSidedMatch is true, alerts that match both the
 // source and the target side of the rule are disregarded.
 func (r *InhibitRule) hasEqual(lset model.LabelSet, excludeTwoSidedMatch bool) (model.Fingerprint, bool) {
-Outer:
+	if r.external != nil {
+		for _, s := range r.external.labelSets() {
+			if r.equal(s, lset) {
+				return s.Fingerprint(), true
+			}
+		}
+		return model.Fingerprint(0), false
+	}
+
 	for _, a := range r.scache.List() {
 		// The cache might be stale and contain resolved alerts.
 		if a.Resolved() {
 			continue
 		}
-		for n := range r.Equal {
-			if a.Labels[n] != lset[n] {
-				continue Outer
-			}
+		if !r.equal(a.Labels, lset) {
+			continue
 		}
 		if excludeTwoSidedMatch && r.TargetMatchers.Matches(a.Labels) {
-			continue Outer
+			continue
 		}
 		return a.Fingerprint(), true
 	}
@@ -20,6 +20,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
 
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
@@ -35,10 +36,11 @@ func TestInhibitRuleHasEqual(t *testing.T) {
 
 	now := time.Now()
 	cases := []struct {
-		initial map[model.Fingerprint]*types.Alert
-		equal   model.LabelNames
-		input   model.LabelSet
-		result  bool
+		initial      map[model.Fingerprint]*types.Alert
+		equal        model.LabelNames
+		requireEqual bool
+		input        model.LabelSet
+		result       bool
 	}{
 		{
 			// No source alerts at all.
@@ -118,12 +120,60 @@ func TestInhibitRuleHasEqual(t *testing.T) {
 			input:  model.LabelSet{"a": "b"},
 			result: false,
 		},
+		{
+			// Equal label absent on both alerts.
+			initial: map[model.Fingerprint]*types.Alert{
+				1: &types.Alert{
+					Alert: model.Alert{
+						Labels:   model.LabelSet{"c": "d"},
+						StartsAt: now.Add(-time.Minute),
+						EndsAt:   now.Add(time.Hour),
+					},
+				},
+			},
+			equal:  model.LabelNames{"a"},
+			input:  model.LabelSet{"b": "c"},
+			result: true,
+		},
+		{
+			// Equal label absent on both alerts but required.
+			initial: map[model.Fingerprint]*types.Alert{
+				1: &types.Alert{
+					Alert: model.Alert{
+						Labels:   model.LabelSet{"c": "d"},
+						StartsAt: now.Add(-time.Minute),
+						EndsAt:   now.Add(time.Hour),
+					},
+				},
+			},
+			equal:        model.LabelNames{"a"},
+			requireEqual: true,
+			input:        model.LabelSet{"b": "c"},
+			result:       false,
+		},
+		{
+			// Equal label present on both alerts and required.
+			initial: map[model.Fingerprint]*types.Alert{
+				1: &types.Alert{
+					Alert: model.Alert{
+						Labels:   model.LabelSet{"a": "b", "c": "d"},
+						StartsAt: now.Add(-time.Minute),
+						EndsAt:   now.Add(time.Hour),
+					},
+				},
+			},
+			equal:        model.LabelNames{"a"},
+			requireEqual: true,
+			input:        model.LabelSet{"a": "b"},
+			result:       true,
+		},
 	}
 
 	for _, c := range cases {
 		r := &InhibitRule{
-			Equal:  map[model.LabelName]struct{}{},
-			scache: store.NewAlerts(),
+			Equal:              map[model.LabelName]struct{}{},
+			RequireEqualLabels: c.requireEqual,
+			scache:             store.NewAlerts(),
 		}
 		for _, ln := range c.equal {
 			r.Equal[ln] = struct{}{}
@@ -341,9 +391,24 @@ func newFakeAlerts(alerts []*types.Alert) *fakeAlerts {
 	}
 }
 
-func (f *fakeAlerts) GetPending() provider.AlertIterator          { return nil }
 func (f *fakeAlerts) Get(model.Fingerprint) (*types.Alert, error) { return nil, nil }
 func (f *fakeAlerts) Put(...*types.Alert) error                   { return nil }
+func (f *fakeAlerts) Count() int                                  { return len(f.alerts) }
+func (f *fakeAlerts) GetPending() provider.AlertIterator {
+	ch := make(chan *types.Alert)
+	done := make(chan struct{})
+	go func() {
+		defer close(ch)
+		for _, a := range f.alerts {
+			select {
+			case ch <- a:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return provider.NewAlertIterator(ch, done, nil)
+}
 func (f *fakeAlerts) Subscribe() provider.AlertIterator {
 	ch := make(chan *types.Alert)
 	done := make(chan struct{})
@@ -476,3 +541,50 @@ func TestInhibit(t *testing.T) {
 		}
 	}
 }
+
+func TestInhibitions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	source := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"s": "1", "e": "f"},
+			StartsAt: now.Add(-time.Minute),
+			EndsAt:   now.Add(time.Hour),
+		},
+	}
+	target := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"t": "1", "e": "f"},
+			StartsAt: now.Add(-time.Minute),
+			EndsAt:   now.Add(time.Hour),
+		},
+	}
+	other := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"t": "1", "e": "g"},
+			StartsAt: now.Add(-time.Minute),
+			EndsAt:   now.Add(time.Hour),
+		},
+	}
+
+	ap := newFakeAlerts([]*types.Alert{source, target, other})
+	mk := types.NewMarker(prometheus.NewRegistry())
+	inhibitor := NewInhibitor(ap, []*config.InhibitRule{
+		{
+			SourceMatch: map[string]string{"s": "2"},
+			TargetMatch: map[string]string{"t": "1"},
+		},
+		{
+			SourceMatch: map[string]string{"s": "1"},
+			TargetMatch: map[string]string{"t": "1"},
+			Equal:       model.LabelNames{"e"},
+		},
+	}, mk, nopLogger)
+	require.NoError(t, inhibitor.rules[1].scache.Set(source))
+
+	require.Equal(t, []Inhibition{{Target: target, Source: source, Rule: 1}}, inhibitor.Inhibitions())
+
+	var nilInhibitor *Inhibitor
+	require.Nil(t, nilInhibitor.Inhibitions())
+}
@@ -0,0 +1,296 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lifecycle sends the state transitions of every alert to a webhook,
+// independently of the receivers.
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/version"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// EventType is the type of a state transition.
+type EventType string
+
+const (
+	// EventReceived is recorded when a new alert is received, or a resolved
+	// alert fires again.
+	EventReceived EventType = "received"
+	// EventGrouped is recorded when an alert joins an aggregation group.
+	EventGrouped EventType = "grouped"
+	// EventSilenced is recorded when an alert becomes silenced.
+	EventSilenced EventType = "silenced"
+	// EventInhibited is recorded when an alert becomes inhibited.
+	EventInhibited EventType = "inhibited"
+	// EventNotified is recorded when an integration successfully notified
+	// about an alert.
+	EventNotified EventType = "notified"
+	// EventResolved is recorded when a resolved alert leaves an aggregation
+	// group after its last notification.
+	EventResolved EventType = "resolved"
+)
+
+// Event is a state transition of an alert.
+type Event struct {
+	Type        EventType      `json:"type"`
+	Time        time.Time      `json:"time"`
+	Fingerprint string         `json:"fingerprint"`
+	Labels      model.LabelSet `json:"labels"`
+	// GroupKey and Receiver identify the aggregation group of the grouped,
+	// notified and resolved events.
+	GroupKey string `json:"groupKey,omitempty"`
+	Receiver string `json:"receiver,omitempty"`
+	// Integration is the integration of the notified events.
+	Integration string `json:"integration,omitempty"`
+	// SilencedBy and InhibitedBy are the IDs of the silences and the
+	// fingerprints of the alerts muting the alert.
+	SilencedBy  []string `json:"silencedBy,omitempty"`
+	InhibitedBy []string `json:"inhibitedBy,omitempty"`
+}
+
+// NewEvent returns an event of the given type about the alert.
+func NewEvent(t EventType, a *types.Alert) Event {
+	return Event{
+		Type:        t,
+		Time:        time.Now(),
+		Fingerprint: a.Fingerprint().String(),
+		Labels:      a.Labels,
+	}
+}
+
+// Recorder records the state transitions of the alerts.
+type Recorder interface {
+	// Enabled returns whether the events of the given type are recorded.
+	Enabled(EventType) bool
+	// Record records the event.
+	Record(Event)
+}
+
+// Message is the payload of the requests to the webhook.
+type Message struct {
+	Version string  `json:"version"`
+	Events  []Event `json:"events"`
+}
+
+const (
+	// queueCapacity is the number of events waiting to be sent above which
+	// events are dropped.
+	queueCapacity = 10000
+	// requestTimeout is the timeout of a request to the webhook.
+	requestTimeout = 30 * time.Second
+)
+
+var userAgentHeader = fmt.Sprintf("Alertmanager/%s", version.Version)
+
+// Webhook sends the events it records to the lifecycle webhook of the
+// configuration, in batches. Events are dropped if the webhook fails or can't
+// keep up.
+type Webhook struct {
+	mtx    sync.RWMutex
+	conf   *config.LifecycleWebhookConfig
+	events map[EventType]struct{}
+	queue  chan Event
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	recorded       *prometheus.CounterVec
+	dropped        prometheus.Counter
+	requestsFailed prometheus.Counter
+	logger         log.Logger
+}
+
+// NewWebhook returns a webhook which doesn't record events until a
+// configuration with a lifecycle webhook is applied.
+func NewWebhook(r prometheus.Registerer, l log.Logger) *Webhook {
+	w := &Webhook{
+		recorded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alertmanager_lifecycle_events_total",
+			Help: "The total number of alert lifecycle events recorded.",
+		}, []string{"type"}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_lifecycle_events_dropped_total",
+			Help: "The total number of alert lifecycle events dropped because the queue was full or the request failed.",
+		}),
+		requestsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_lifecycle_requests_failed_total",
+			Help: "The total number of failed requests to the lifecycle webhook.",
+		}),
+		logger: l,
+	}
+	for _, t := range config.LifecycleEvents {
+		w.recorded.WithLabelValues(t)
+	}
+	if r != nil {
+		r.MustRegister(w.recorded, w.dropped, w.requestsFailed)
+	}
+	return w
+}
+
+// Update applies the lifecycle webhook configuration. The events queued are
+// dropped if the configuration changed.
+func (w *Webhook) Update(c *config.Config) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if reflect.DeepEqual(c.LifecycleWebhook, w.conf) {
+		return nil
+	}
+
+	var client *http.Client
+	if c.LifecycleWebhook != nil {
+		var err error
+		client, err = commoncfg.NewClientFromConfig(*c.LifecycleWebhook.HTTPConfig, "lifecycle_webhook")
+		if err != nil {
+			return err
+		}
+	}
+
+	w.stop()
+	w.conf = c.LifecycleWebhook
+	if c.LifecycleWebhook != nil {
+		w.events = make(map[EventType]struct{})
+		events := c.LifecycleWebhook.Events
+		if len(events) == 0 {
+			events = config.LifecycleEvents
+		}
+		for _, e := range events {
+			w.events[EventType(e)] = struct{}{}
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		w.queue = make(chan Event, queueCapacity)
+		w.cancel, w.done = cancel, make(chan struct{})
+		go w.run(ctx, c.LifecycleWebhook, client, w.queue, w.done)
+	}
+	return nil
+}
+
+// Stop stops sending the events.
+func (w *Webhook) Stop() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.stop()
+	w.conf = nil
+}
+
+func (w *Webhook) stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+	w.cancel, w.done, w.queue, w.events = nil, nil, nil, nil
+}
+
+// Enabled implements the Recorder interface.
+func (w *Webhook) Enabled(t EventType) bool {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	_, ok := w.events[t]
+	return ok
+}
+
+// Record implements the Recorder interface. It never blocks.
+func (w *Webhook) Record(e Event) {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	if _, ok := w.events[e.Type]; !ok {
+		return
+	}
+	w.recorded.WithLabelValues(string(e.Type)).Inc()
+	select {
+	case w.queue <- e:
+	default:
+		w.dropped.Inc()
+	}
+}
+
+// run sends the queued events whenever a batch is full or the flush interval
+// elapsed, until the context is canceled.
+func (w *Webhook) run(ctx context.Context, conf *config.LifecycleWebhookConfig, client *http.Client, queue <-chan Event, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(time.Duration(conf.FlushInterval))
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, conf.MaxEvents)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := send(ctx, client, conf.URL.String(), batch); err != nil && ctx.Err() == nil {
+			w.requestsFailed.Inc()
+			w.dropped.Add(float64(len(batch)))
+			level.Warn(w.logger).Log("msg", "Failed to send lifecycle events", "events", len(batch), "err", err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case e := <-queue:
+			batch = append(batch, e)
+			if len(batch) >= conf.MaxEvents {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func send(ctx context.Context, client *http.Client, u string, events []Event) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(&Message{Version: "1", Events: events}); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, u, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgentHeader)
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestWebhook(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		messages []Message
+		fail     bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var m Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&m))
+		messages = append(messages, m)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	received := func() []Message {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]Message(nil), messages...)
+	}
+
+	w := NewWebhook(nil, log.NewNopLogger())
+	a := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "HighLatency"}}}
+
+	// Nothing is recorded until a webhook is configured.
+	require.False(t, w.Enabled(EventReceived))
+	w.Record(NewEvent(EventReceived, a))
+
+	conf := &config.Config{
+		LifecycleWebhook: &config.LifecycleWebhookConfig{
+			URL:           &config.SecretURL{URL: u},
+			Events:        []string{"received", "notified"},
+			MaxEvents:     2,
+			FlushInterval: model.Duration(time.Hour),
+			HTTPConfig:    &commoncfg.HTTPClientConfig{},
+		},
+	}
+	require.NoError(t, w.Update(conf))
+	require.True(t, w.Enabled(EventReceived))
+	require.False(t, w.Enabled(EventSilenced))
+
+	w.Record(NewEvent(EventReceived, a))
+	w.Record(NewEvent(EventSilenced, a))
+	notified := NewEvent(EventNotified, a)
+	notified.GroupKey, notified.Receiver, notified.Integration = "{}:{}", "team-X", "webhook[0]"
+	w.Record(notified)
+
+	// A full batch is sent right away.
+	require.Eventually(t, func() bool { return len(received()) == 1 }, 5*time.Second, time.Millisecond)
+	m := received()[0]
+	require.Equal(t, "1", m.Version)
+	require.Len(t, m.Events, 2)
+	require.Equal(t, EventReceived, m.Events[0].Type)
+	require.Equal(t, a.Fingerprint().String(), m.Events[0].Fingerprint)
+	require.Equal(t, a.Labels, m.Events[0].Labels)
+	require.Equal(t, EventNotified, m.Events[1].Type)
+	require.Equal(t, "team-X", m.Events[1].Receiver)
+	require.Equal(t, "webhook[0]", m.Events[1].Integration)
+	require.Equal(t, 1.0, testutil.ToFloat64(w.recorded.WithLabelValues("received")))
+	require.Equal(t, 0.0, testutil.ToFloat64(w.recorded.WithLabelValues("silenced")))
+
+	// The events of failed requests are dropped.
+	mtx.Lock()
+	fail = true
+	mtx.Unlock()
+	w.Record(NewEvent(EventReceived, a))
+	w.Record(NewEvent(EventReceived, a))
+	require.Eventually(t, func() bool { return testutil.ToFloat64(w.requestsFailed) == 1 }, 5*time.Second, time.Millisecond)
+	require.Equal(t, 2.0, testutil.ToFloat64(w.dropped))
+
+	// Removing the webhook stops recording.
+	require.NoError(t, w.Update(&config.Config{}))
+	require.False(t, w.Enabled(EventReceived))
+	w.Record(NewEvent(EventReceived, a))
+	require.Len(t, received(), 1)
+}
+
+func TestWebhookFlushInterval(t *testing.T) {
+	events := make(chan []Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&m))
+		events <- m.Events
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	w := NewWebhook(nil, log.NewNopLogger())
+	defer w.Stop()
+	require.NoError(t, w.Update(&config.Config{
+		LifecycleWebhook: &config.LifecycleWebhookConfig{
+			URL:           &config.SecretURL{URL: u},
+			MaxEvents:     100,
+			FlushInterval: model.Duration(10 * time.Millisecond),
+			HTTPConfig:    &commoncfg.HTTPClientConfig{},
+		},
+	}))
+	for _, e := range config.LifecycleEvents {
+		require.True(t, w.Enabled(EventType(e)))
+	}
+
+	w.Record(NewEvent(EventGrouped, &types.Alert{}))
+	select {
+	case got := <-events:
+		require.Len(t, got, 1)
+		require.Equal(t, EventGrouped, got[0].Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("events not sent")
+	}
+}
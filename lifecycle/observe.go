@@ -0,0 +1,118 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/storage"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Alerts records the received events of the alerts put into the wrapped
+// provider.
+type Alerts struct {
+	storage.Alerts
+	rec Recorder
+}
+
+// NewAlerts returns a provider recording the received events of the alerts put
+// into a.
+func NewAlerts(a storage.Alerts, rec Recorder) *Alerts {
+	return &Alerts{Alerts: a, rec: rec}
+}
+
+// Put implements the provider.Alerts interface. An alert is received if it
+// fires and it is new or was resolved.
+func (a *Alerts) Put(alerts ...*types.Alert) error {
+	if !a.rec.Enabled(EventReceived) {
+		return a.Alerts.Put(alerts...)
+	}
+
+	var received []*types.Alert
+	for _, alert := range alerts {
+		if alert.Resolved() {
+			continue
+		}
+		old, err := a.Alerts.Get(alert.Fingerprint())
+		if err != nil || old.Resolved() {
+			received = append(received, alert)
+		}
+	}
+	if err := a.Alerts.Put(alerts...); err != nil {
+		return err
+	}
+	for _, alert := range received {
+		a.rec.Record(NewEvent(EventReceived, alert))
+	}
+	return nil
+}
+
+// Marker records the silenced and inhibited events of the alerts marked in the
+// wrapped marker.
+type Marker struct {
+	types.Marker
+	alerts provider.Alerts
+	rec    Recorder
+}
+
+// NewMarker returns a marker recording the silenced and inhibited events of
+// the alerts marked in m. The labels of the alerts are looked up in alerts.
+func NewMarker(m types.Marker, alerts provider.Alerts, rec Recorder) *Marker {
+	return &Marker{Marker: m, alerts: alerts, rec: rec}
+}
+
+// SetSilenced implements the types.Marker interface. The alert is silenced if
+// it wasn't silenced by any active silence before.
+func (m *Marker) SetSilenced(fp model.Fingerprint, version int, activeIDs []string, pendingIDs []string) {
+	if !m.rec.Enabled(EventSilenced) {
+		m.Marker.SetSilenced(fp, version, activeIDs, pendingIDs)
+		return
+	}
+	_, _, _, silenced := m.Marker.Silenced(fp)
+	m.Marker.SetSilenced(fp, version, activeIDs, pendingIDs)
+	if !silenced && len(activeIDs) > 0 {
+		e := m.event(EventSilenced, fp)
+		e.SilencedBy = activeIDs
+		m.rec.Record(e)
+	}
+}
+
+// SetInhibited implements the types.Marker interface. The alert is inhibited
+// if it wasn't inhibited before.
+func (m *Marker) SetInhibited(fp model.Fingerprint, ids ...string) {
+	if !m.rec.Enabled(EventInhibited) {
+		m.Marker.SetInhibited(fp, ids...)
+		return
+	}
+	_, inhibited := m.Marker.Inhibited(fp)
+	m.Marker.SetInhibited(fp, ids...)
+	if !inhibited && len(ids) > 0 {
+		e := m.event(EventInhibited, fp)
+		e.InhibitedBy = ids
+		m.rec.Record(e)
+	}
+}
+
+// event returns an event about the alert with the given fingerprint, without
+// labels if the alert is unknown.
+func (m *Marker) event(t EventType, fp model.Fingerprint) Event {
+	if a, err := m.alerts.Get(fp); err == nil {
+		return NewEvent(t, a)
+	}
+	return Event{Type: t, Time: time.Now(), Fingerprint: fp.String()}
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/types"
+)
+
+type recorder struct {
+	mtx    sync.Mutex
+	events []Event
+}
+
+func (r *recorder) Enabled(EventType) bool { return true }
+
+func (r *recorder) Record(e Event) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) types() []EventType {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	var res []EventType
+	for _, e := range r.events {
+		res = append(res, e.Type)
+	}
+	return res
+}
+
+func newAlert(labels model.LabelSet, resolved bool) *types.Alert {
+	now := time.Now()
+	a := &types.Alert{
+		Alert: model.Alert{
+			Labels:   labels,
+			StartsAt: now.Add(-time.Minute),
+			EndsAt:   now.Add(time.Hour),
+		},
+		UpdatedAt: now,
+	}
+	if resolved {
+		a.EndsAt = now.Add(-time.Second)
+	}
+	return a
+}
+
+func TestAlerts(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	ma, err := mem.NewAlerts(context.Background(), marker, time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer ma.Close()
+
+	rec := &recorder{}
+	alerts := NewAlerts(ma, rec)
+
+	labels := model.LabelSet{"alertname": "HighLatency"}
+	require.NoError(t, alerts.Put(newAlert(labels, false)))
+	require.Equal(t, []EventType{EventReceived}, rec.types())
+	require.Equal(t, labels, rec.events[0].Labels)
+
+	// Updates of a firing alert aren't received again.
+	require.NoError(t, alerts.Put(newAlert(labels, false)))
+	require.Len(t, rec.types(), 1)
+
+	// Resolved alerts aren't received, but they fire again when they are
+	// received after being resolved.
+	require.NoError(t, alerts.Put(newAlert(labels, true)))
+	require.Len(t, rec.types(), 1)
+	require.NoError(t, alerts.Put(newAlert(labels, false)))
+	require.Equal(t, []EventType{EventReceived, EventReceived}, rec.types())
+}
+
+func TestMarker(t *testing.T) {
+	ma, err := mem.NewAlerts(context.Background(), types.NewMarker(prometheus.NewRegistry()), time.Hour, time.Hour, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer ma.Close()
+
+	a := newAlert(model.LabelSet{"alertname": "HighLatency"}, false)
+	require.NoError(t, ma.Put(a))
+	fp := a.Fingerprint()
+
+	rec := &recorder{}
+	marker := NewMarker(types.NewMarker(prometheus.NewRegistry()), ma, rec)
+
+	marker.SetSilenced(fp, 1, nil, nil)
+	require.Empty(t, rec.types())
+	marker.SetSilenced(fp, 2, []string{"s1"}, nil)
+	marker.SetSilenced(fp, 3, []string{"s1", "s2"}, nil)
+	require.Equal(t, []EventType{EventSilenced}, rec.types())
+	require.Equal(t, []string{"s1"}, rec.events[0].SilencedBy)
+	require.Equal(t, a.Labels, rec.events[0].Labels)
+
+	marker.SetInhibited(fp, "0123456789abcdef")
+	marker.SetInhibited(fp, "0123456789abcdef")
+	require.Equal(t, []EventType{EventSilenced, EventInhibited}, rec.types())
+	require.Equal(t, []string{"0123456789abcdef"}, rec.events[1].InhibitedBy)
+
+	// Alerts muted again after being unmuted are recorded again.
+	marker.SetSilenced(fp, 4, nil, nil)
+	marker.SetInhibited(fp)
+	marker.SetSilenced(fp, 5, []string{"s3"}, nil)
+	require.Equal(t, []EventType{EventSilenced, EventInhibited, EventSilenced}, rec.types())
+
+	// The labels of unknown alerts are unknown.
+	marker.SetInhibited(model.Fingerprint(1), "0123456789abcdef")
+	require.Equal(t, model.Fingerprint(1).String(), rec.events[3].Fingerprint)
+	require.Nil(t, rec.events[3].Labels)
+}
@@ -80,6 +80,7 @@ type Log struct {
 
 	runInterval time.Duration
 	snapf       string
+	snapr       io.Reader
 	stopc       chan struct{}
 	done        func()
 
@@ -219,6 +220,15 @@ func WithSnapshot(sf string) Option {
 	}
 }
 
+// WithSnapshotReader configures the log to be initialized from the snapshot
+// read from r. Unlike WithSnapshot, no snapshot is saved by the maintenance.
+func WithSnapshotReader(r io.Reader) Option {
+	return func(l *Log) error {
+		l.snapr = r
+		return nil
+	}
+}
+
 func utcNow() time.Time {
 	return time.Now().UTC()
 }
@@ -305,7 +315,15 @@ func New(opts ...Option) (*Log, error) {
 	if l.metrics == nil {
 		l.metrics = newMetrics(nil)
 	}
+	if l.snapf != "" && l.snapr != nil {
+		return nil, errors.New("only one of WithSnapshot and WithSnapshotReader must be used")
+	}
 
+	if l.snapr != nil {
+		if err := l.loadSnapshot(l.snapr); err != nil {
+			return l, err
+		}
+	}
 	if l.snapf != "" {
 		if f, err := os.Open(l.snapf); !os.IsNotExist(err) {
 			if err != nil {
@@ -126,6 +126,23 @@ func TestLogSnapshot(t *testing.T) {
 	}
 }
 
+func TestWithSnapshotReader(t *testing.T) {
+	l1, err := New()
+	require.NoError(t, err)
+	require.NoError(t, l1.Log(&pb.Receiver{GroupName: "abc", Integration: "test", Idx: 1}, "key", []uint64{1}, nil))
+
+	var buf bytes.Buffer
+	_, err = l1.Snapshot(&buf)
+	require.NoError(t, err)
+
+	l2, err := New(WithSnapshotReader(&buf))
+	require.NoError(t, err)
+	require.Equal(t, l1.st, l2.st)
+
+	_, err = New(WithSnapshot("nflog"), WithSnapshotReader(&buf))
+	require.EqualError(t, err, "only one of WithSnapshot and WithSnapshotReader must be used")
+}
+
 func TestWithMaintenance_SupportsCustomCallback(t *testing.T) {
 	f, err := ioutil.TempFile("", "snapshot")
 	require.NoError(t, err, "creating temp file failed")
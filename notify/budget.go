@@ -0,0 +1,461 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// The scopes of notification budgets.
+const (
+	BudgetScopeReceiver = "receiver"
+	BudgetScopeTenant   = "tenant"
+)
+
+// The windows of notification budgets.
+const (
+	BudgetWindowDaily  = "daily"
+	BudgetWindowWeekly = "weekly"
+)
+
+const week = 7 * 24 * time.Hour
+
+// budgetKey identifies the budget of a receiver or a tenant.
+type budgetKey struct {
+	scope string
+	name  string
+}
+
+// budgetUsage counts the notifications of a budget in its current windows.
+type budgetUsage struct {
+	day, week     time.Time
+	daily, weekly int
+}
+
+// reset starts the windows containing now if they changed. The zero time is
+// midnight UTC on a Monday, so truncating to days and weeks gives their UTC
+// start.
+func (u *budgetUsage) reset(now time.Time) {
+	if day := now.Truncate(24 * time.Hour); !day.Equal(u.day) {
+		u.day, u.daily = day, 0
+	}
+	if w := now.Truncate(week); !w.Equal(u.week) {
+		u.week, u.weekly = w, 0
+	}
+}
+
+// exhausted returns whether the budget allows no more notifications, and
+// when it resets.
+func (u *budgetUsage) exhausted(b *config.NotificationBudget) (bool, time.Time) {
+	var (
+		exhausted bool
+		resets    time.Time
+	)
+	if b.Weekly > 0 && u.weekly >= b.Weekly {
+		exhausted, resets = true, u.week.Add(week)
+	}
+	if b.Daily > 0 && u.daily >= b.Daily && !exhausted {
+		exhausted, resets = true, u.day.Add(24*time.Hour)
+	}
+	return exhausted, resets
+}
+
+// budgetQueueKey identifies the queue of an integration of a receiver.
+type budgetQueueKey struct {
+	receiver    string
+	integration string
+	idx         int
+}
+
+// BudgetStatus is the consumption of a notification budget in its current
+// window.
+type BudgetStatus struct {
+	Scope    string
+	Name     string
+	Window   string
+	Limit    int
+	Used     int
+	ResetsAt time.Time
+	// Queued is the number of alerts queued for the digest of the budget.
+	Queued int
+}
+
+// Budgets limits the number of notifications sent per day and per week by
+// the integrations of each receiver and for the alerts of each tenant. Each
+// notification of an integration counts once against the budgets of its
+// receiver and of its tenant. Exceeding notifications are either dropped
+// after a single notification that the budget is exceeded, or queued and
+// delivered as a digest once the budget resets. The counts and queues are
+// kept in memory only.
+type Budgets struct {
+	receivers func(name string) ([]Integration, bool)
+	logger    log.Logger
+	now       func() time.Time
+
+	exceededTotal *prometheus.CounterVec
+	usedDesc      *prometheus.Desc
+	limitDesc     *prometheus.Desc
+
+	mtx         sync.Mutex
+	confs       map[string]*config.NotificationBudget
+	tenantConf  *config.NotificationBudget
+	tenantLabel model.LabelName
+	usage       map[budgetKey]*budgetUsage
+	// noticed holds until when the integrations were notified that a
+	// budget is exceeded.
+	noticed map[budgetQueueKey]time.Time
+	queues  map[budgetQueueKey]map[model.Fingerprint]*types.Alert
+	// queued holds the budget delaying each queue.
+	queued map[budgetQueueKey]budgetKey
+}
+
+// NewBudgets returns a new Budgets. The receivers function looks up the
+// integrations of a receiver in the current configuration.
+func NewBudgets(receivers func(name string) ([]Integration, bool), l log.Logger, r prometheus.Registerer) *Budgets {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	b := &Budgets{
+		receivers: receivers,
+		logger:    l,
+		now:       time.Now,
+		exceededTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alertmanager_notification_budget_exceeded_total",
+			Help: "The total number of notifications exceeding a notification budget.",
+		}, []string{"scope", "name"}),
+		usedDesc: prometheus.NewDesc(
+			"alertmanager_notification_budget_used",
+			"The number of notifications counted against a notification budget in its current window.",
+			[]string{"scope", "name", "window"}, nil,
+		),
+		limitDesc: prometheus.NewDesc(
+			"alertmanager_notification_budget_limit",
+			"The maximum number of notifications of a notification budget in a window.",
+			[]string{"scope", "name", "window"}, nil,
+		),
+		usage:   map[budgetKey]*budgetUsage{},
+		noticed: map[budgetQueueKey]time.Time{},
+		queues:  map[budgetQueueKey]map[model.Fingerprint]*types.Alert{},
+		queued:  map[budgetQueueKey]budgetKey{},
+	}
+	if r != nil {
+		r.MustRegister(b.exceededTotal, b)
+	}
+	return b
+}
+
+// Describe implements prometheus.Collector.
+func (b *Budgets) Describe(ch chan<- *prometheus.Desc) {
+	ch <- b.usedDesc
+	ch <- b.limitDesc
+}
+
+// Collect implements prometheus.Collector.
+func (b *Budgets) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range b.Status() {
+		ch <- prometheus.MustNewConstMetric(b.usedDesc, prometheus.GaugeValue, float64(s.Used), s.Scope, s.Name, s.Window)
+		ch <- prometheus.MustNewConstMetric(b.limitDesc, prometheus.GaugeValue, float64(s.Limit), s.Scope, s.Name, s.Window)
+	}
+}
+
+// Update applies the budgets of the receivers and of the tenants of the
+// configuration. The counts and the queued alerts are kept.
+func (b *Budgets) Update(c *config.Config) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.confs = make(map[string]*config.NotificationBudget)
+	for _, rcv := range c.Receivers {
+		if rcv.Budget != nil {
+			b.confs[rcv.Name] = rcv.Budget
+		}
+	}
+	b.tenantConf, b.tenantLabel = nil, ""
+	if c.Tenancy != nil {
+		b.tenantConf, b.tenantLabel = c.Tenancy.Budget, c.Tenancy.Label
+	}
+}
+
+// Status returns the consumption of the budgets which counted notifications
+// or queued alerts, sorted by scope, name and window.
+func (b *Budgets) Status() []BudgetStatus {
+	now := b.now()
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	queued := map[budgetKey]int{}
+	for qk, q := range b.queues {
+		queued[b.queued[qk]] += len(q)
+	}
+	var res []BudgetStatus
+	for key, u := range b.usage {
+		conf := b.conf(key)
+		if conf == nil {
+			continue
+		}
+		u.reset(now)
+		if conf.Daily > 0 {
+			res = append(res, BudgetStatus{Scope: key.scope, Name: key.name, Window: BudgetWindowDaily, Limit: conf.Daily, Used: u.daily, ResetsAt: u.day.Add(24 * time.Hour), Queued: queued[key]})
+		}
+		if conf.Weekly > 0 {
+			res = append(res, BudgetStatus{Scope: key.scope, Name: key.name, Window: BudgetWindowWeekly, Limit: conf.Weekly, Used: u.weekly, ResetsAt: u.week.Add(week), Queued: queued[key]})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Scope != res[j].Scope {
+			return res[i].Scope < res[j].Scope
+		}
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].Window < res[j].Window
+	})
+	return res
+}
+
+// conf returns the budget configuration of the key, or nil if it has none.
+func (b *Budgets) conf(key budgetKey) *config.NotificationBudget {
+	if key.scope == BudgetScopeTenant {
+		return b.tenantConf
+	}
+	return b.confs[key.name]
+}
+
+// keys returns the budgets applying to a notification of the receiver for
+// the alerts of the tenant.
+func (b *Budgets) keys(receiver, tenant string) []budgetKey {
+	var keys []budgetKey
+	if b.confs[receiver] != nil {
+		keys = append(keys, budgetKey{scope: BudgetScopeReceiver, name: receiver})
+	}
+	if b.tenantConf != nil && tenant != "" {
+		keys = append(keys, budgetKey{scope: BudgetScopeTenant, name: tenant})
+	}
+	return keys
+}
+
+// consume counts a notification against the budgets if none of them is
+// exhausted. Otherwise it returns the first exhausted budget and when it
+// resets.
+func (b *Budgets) consume(keys []budgetKey, now time.Time) (bool, budgetKey, time.Time) {
+	usages := make([]*budgetUsage, 0, len(keys))
+	for _, key := range keys {
+		u, ok := b.usage[key]
+		if !ok {
+			u = &budgetUsage{}
+			b.usage[key] = u
+		}
+		u.reset(now)
+		if exhausted, resets := u.exhausted(b.conf(key)); exhausted {
+			return false, key, resets
+		}
+		usages = append(usages, u)
+	}
+	for _, u := range usages {
+		u.daily++
+		u.weekly++
+	}
+	return true, budgetKey{}, time.Time{}
+}
+
+// tenant returns the tenant of the alerts of a notification.
+func (b *Budgets) tenant(ctx context.Context, alerts []*types.Alert) string {
+	if b.tenantLabel == "" {
+		return ""
+	}
+	if gl, ok := GroupLabels(ctx); ok {
+		if t, ok := gl[b.tenantLabel]; ok {
+			return string(t)
+		}
+	}
+	if len(alerts) > 0 {
+		return string(alerts[0].Labels[b.tenantLabel])
+	}
+	return ""
+}
+
+// Stage returns a stage which passes the notifications of the integration to
+// deliver while the budgets of the receiver and of the tenant of their alerts
+// allow it. Notifications exceeding a budget are recorded by setNotifies as
+// if they were delivered, so that the group doesn't notify them again.
+func (b *Budgets) Stage(receiver string, i Integration, deliver, setNotifies Stage) Stage {
+	qk := budgetQueueKey{receiver: receiver, integration: i.Name(), idx: i.Index()}
+	return StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		now, ok := Now(ctx)
+		if !ok {
+			return ctx, nil, errors.New("missing now timestamp")
+		}
+
+		b.mtx.Lock()
+		ok, key, resets := b.consume(b.keys(receiver, b.tenant(ctx, alerts)), now)
+		if ok {
+			b.mtx.Unlock()
+			return deliver.Exec(ctx, l, alerts...)
+		}
+		b.exceededTotal.WithLabelValues(key.scope, key.name).Inc()
+		action := b.conf(key).Action
+		notice := false
+		switch action {
+		case config.BudgetDigest:
+			b.enqueue(qk, key, now, alerts)
+		default:
+			if until, ok := b.noticed[qk]; !ok || !now.Before(until) {
+				b.noticed[qk] = resets
+				notice = true
+			}
+		}
+		b.mtx.Unlock()
+
+		if action == config.BudgetDigest {
+			level.Debug(l).Log("msg", "Notification budget exceeded, alerts queued until it resets", "scope", key.scope, "name", key.name, "alerts", len(alerts))
+			return setNotifies.Exec(ctx, l, alerts...)
+		}
+		level.Debug(l).Log("msg", "Notification budget exceeded, notification dropped", "scope", key.scope, "name", key.name, "alerts", len(alerts))
+		if notice {
+			if _, err := i.Notify(ctx, budgetExceededAlert(key, receiver, resets, now)); err != nil {
+				level.Error(l).Log("msg", "Notifying that the notification budget is exceeded failed", "scope", key.scope, "name", key.name, "err", err)
+			}
+		}
+		return setNotifies.Exec(ctx, l, alerts...)
+	})
+}
+
+// budgetExceededAlert returns the alert notifying that a budget is exceeded
+// until it resets.
+func budgetExceededAlert(key budgetKey, receiver string, resets, now time.Time) *types.Alert {
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{
+				model.AlertNameLabel: "NotificationBudgetExceeded",
+				"receiver":           model.LabelValue(receiver),
+				"budget_scope":       model.LabelValue(key.scope),
+				"budget_name":        model.LabelValue(key.name),
+			},
+			Annotations: model.LabelSet{
+				"summary":     model.LabelValue(fmt.Sprintf("The notification budget of %s %q is exceeded", key.scope, key.name)),
+				"description": model.LabelValue(fmt.Sprintf("Notifications are dropped until the budget resets at %s.", resets.UTC().Format(time.RFC3339))),
+			},
+			StartsAt: now,
+			EndsAt:   resets,
+		},
+		UpdatedAt: now,
+	}
+}
+
+// enqueue queues the alerts for the digest of the integration.
+func (b *Budgets) enqueue(qk budgetQueueKey, key budgetKey, now time.Time, alerts []*types.Alert) {
+	queue, ok := b.queues[qk]
+	if !ok {
+		queue = map[model.Fingerprint]*types.Alert{}
+		b.queues[qk] = queue
+	}
+	b.queued[qk] = key
+	for _, a := range alerts {
+		// The digest reports the state of the alerts when they were
+		// queued, so firing alerts must not resolve in the meantime.
+		c := *a
+		if !a.ResolvedAt(now) {
+			c.EndsAt = time.Time{}
+		}
+		queue[a.Fingerprint()] = &c
+	}
+}
+
+// Run flushes the queues at the given interval until stopc is closed.
+func (b *Budgets) Run(interval time.Duration, stopc <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			return
+		case <-t.C:
+			b.Flush(context.Background())
+		}
+	}
+}
+
+// Flush sends the digests of the queues whose budget reset, counting each
+// digest against the budget. The alerts of digests which failed with a
+// recoverable error are queued again.
+func (b *Budgets) Flush(ctx context.Context) {
+	now := b.now()
+
+	b.mtx.Lock()
+	due := make(map[budgetQueueKey]map[model.Fingerprint]*types.Alert)
+	for qk, queue := range b.queues {
+		key := b.queued[qk]
+		var keys []budgetKey
+		if b.conf(key) != nil {
+			keys = []budgetKey{key}
+		}
+		if ok, _, _ := b.consume(keys, now); !ok {
+			continue
+		}
+		due[qk] = queue
+		delete(b.queues, qk)
+		delete(b.queued, qk)
+	}
+	b.mtx.Unlock()
+
+	for qk, queue := range due {
+		alerts := make(types.AlertSlice, 0, len(queue))
+		for _, a := range queue {
+			alerts = append(alerts, a)
+		}
+		sort.Stable(alerts)
+
+		integrations, _ := b.receivers(qk.receiver)
+		retry, err := notifyDigest(ctx, b.logger, integrations, qk.receiver, qk.integration, qk.idx, "budget/", "notification budget", alerts, now)
+		if err == nil {
+			continue
+		}
+		level.Error(b.logger).Log("msg", "Sending notification budget digest failed", "receiver", qk.receiver, "integration", fmt.Sprintf("%s[%d]", qk.integration, qk.idx), "retry", retry, "err", err)
+		if !retry {
+			continue
+		}
+
+		b.mtx.Lock()
+		requeue, ok := b.queues[qk]
+		if !ok {
+			// The digest was already counted against the budget, it is
+			// sent again at the next flush.
+			b.queues[qk] = queue
+			b.queued[qk] = budgetKey{}
+		} else {
+			// Alerts queued since the flush are more recent.
+			for fp, a := range queue {
+				if _, ok := requeue[fp]; !ok {
+					requeue[fp] = a
+				}
+			}
+		}
+		b.mtx.Unlock()
+	}
+}
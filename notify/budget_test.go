@@ -0,0 +1,210 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestBudgetsNotify(t *testing.T) {
+	conf, err := config.Load(`
+tenancy:
+  header: X-Tenant
+  label: tenant
+  budget:
+    weekly: 3
+route:
+  receiver: team-X
+receivers:
+- name: team-X
+  budget:
+    daily: 1
+- name: team-Y
+`)
+	require.NoError(t, err)
+
+	var notified [][]*types.Alert
+	integration := NewIntegration(notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		notified = append(notified, alerts)
+		return false, nil
+	}), sendResolved(true), "webhook", 0)
+
+	b := NewBudgets(func(name string) ([]Integration, bool) {
+		return []Integration{integration}, true
+	}, nil, nil)
+	b.Update(conf)
+
+	var delivered, recorded int
+	stage := func(receiver string) Stage {
+		return b.Stage(receiver, integration,
+			StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+				delivered++
+				return ctx, alerts, nil
+			}),
+			StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+				recorded++
+				return ctx, alerts, nil
+			}),
+		)
+	}
+
+	// 2021-01-06 is a Wednesday.
+	now := time.Date(2021, 1, 6, 10, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	alert := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a", "tenant": "acme"}}}
+	ctx := WithNow(context.Background(), now)
+
+	_, _, err = stage("team-X").Exec(ctx, log.NewNopLogger(), alert)
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+
+	// The exceeding notifications are dropped after a single notification
+	// that the budget is exceeded.
+	for i := 0; i < 2; i++ {
+		_, _, err = stage("team-X").Exec(ctx, log.NewNopLogger(), alert)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, delivered)
+	require.Equal(t, 2, recorded)
+	require.Len(t, notified, 1)
+	require.Equal(t, model.LabelValue("NotificationBudgetExceeded"), notified[0][0].Labels[model.AlertNameLabel])
+	require.Equal(t, time.Date(2021, 1, 7, 0, 0, 0, 0, time.UTC), notified[0][0].EndsAt)
+
+	// Receivers without a budget only count against the budget of the
+	// tenant.
+	for i := 0; i < 3; i++ {
+		_, _, err = stage("team-Y").Exec(ctx, log.NewNopLogger(), alert)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, delivered)
+	require.Equal(t, 3, recorded)
+	require.Len(t, notified, 2)
+	require.Equal(t, model.LabelValue("tenant"), notified[1][0].Labels["budget_scope"])
+
+	require.Equal(t, []BudgetStatus{
+		{Scope: BudgetScopeReceiver, Name: "team-X", Window: BudgetWindowDaily, Limit: 1, Used: 1, ResetsAt: time.Date(2021, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{Scope: BudgetScopeTenant, Name: "acme", Window: BudgetWindowWeekly, Limit: 3, Used: 3, ResetsAt: time.Date(2021, 1, 11, 0, 0, 0, 0, time.UTC)},
+	}, b.Status())
+
+	// The daily budget resets at midnight UTC, the weekly one on Monday.
+	now = time.Date(2021, 1, 7, 0, 0, 0, 0, time.UTC)
+	_, _, err = stage("team-X").Exec(WithNow(context.Background(), now), log.NewNopLogger(), alert)
+	require.NoError(t, err)
+	require.Equal(t, 3, delivered)
+	now = time.Date(2021, 1, 11, 0, 0, 0, 0, time.UTC)
+	_, _, err = stage("team-X").Exec(WithNow(context.Background(), now), log.NewNopLogger(), alert)
+	require.NoError(t, err)
+	require.Equal(t, 4, delivered)
+
+	_, _, err = stage("team-X").Exec(context.Background(), log.NewNopLogger(), alert)
+	require.Error(t, err)
+}
+
+func TestBudgetsDigest(t *testing.T) {
+	conf, err := config.Load(`
+route:
+  receiver: team-X
+receivers:
+- name: team-X
+  budget:
+    daily: 1
+    action: digest
+`)
+	require.NoError(t, err)
+
+	var (
+		notified [][]*types.Alert
+		fail     error
+	)
+	integration := NewIntegration(notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		if fail != nil {
+			return true, fail
+		}
+		key, _ := GroupKey(ctx)
+		require.Equal(t, "budget/team-X", key)
+		notified = append(notified, alerts)
+		return false, nil
+	}), sendResolved(true), "webhook", 0)
+
+	b := NewBudgets(func(name string) ([]Integration, bool) {
+		return []Integration{integration}, name == "team-X"
+	}, nil, nil)
+	b.Update(conf)
+
+	var delivered, recorded int
+	stage := b.Stage("team-X", integration,
+		StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+			delivered++
+			return ctx, alerts, nil
+		}),
+		StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+			recorded++
+			return ctx, alerts, nil
+		}),
+	)
+
+	now := time.Date(2021, 1, 6, 10, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	a1 := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "a"},
+		StartsAt: now.Add(-time.Minute),
+		EndsAt:   now.Add(5 * time.Minute),
+	}}
+	a2 := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b"}}}
+	ctx := WithNow(context.Background(), now)
+
+	_, _, err = stage.Exec(ctx, log.NewNopLogger(), a1)
+	require.NoError(t, err)
+	_, _, err = stage.Exec(ctx, log.NewNopLogger(), a1)
+	require.NoError(t, err)
+	_, _, err = stage.Exec(ctx, log.NewNopLogger(), a2)
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+	require.Equal(t, 2, recorded)
+	require.Equal(t, 2, b.Status()[0].Queued)
+
+	// Nothing is sent until the budget resets.
+	b.Flush(context.Background())
+	require.Len(t, notified, 0)
+
+	// A failed digest is kept for the next flush.
+	now = time.Date(2021, 1, 7, 0, 0, 0, 0, time.UTC)
+	fail = errors.New("unavailable")
+	b.Flush(context.Background())
+	require.Len(t, notified, 0)
+
+	// The digest reports the alerts as they were queued.
+	fail = nil
+	b.Flush(context.Background())
+	require.Len(t, notified, 1)
+	require.Len(t, notified[0], 2)
+	require.Equal(t, a1.Labels, notified[0][0].Labels)
+	require.Equal(t, model.AlertFiring, notified[0][0].Status())
+	require.Equal(t, 0, b.Status()[0].Queued)
+
+	// The digest counted against the budget.
+	_, _, err = stage.Exec(WithNow(context.Background(), now), log.NewNopLogger(), a2)
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+}
@@ -0,0 +1,247 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/tracing"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// RecordFunc receives the payload of a notification which would have been
+// sent, along with its content type.
+type RecordFunc func(contentType string, payload []byte)
+
+type dryRunKey struct{}
+
+type connectivityCheckKey struct{}
+
+// WithDryRun returns a context in which notifiers hand the payloads of their
+// notifications to record instead of sending them.
+func WithDryRun(ctx context.Context, record RecordFunc) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, record)
+}
+
+// DryRun returns the function recording the payloads of the notifications if
+// the context is in dry-run mode.
+func DryRun(ctx context.Context) (RecordFunc, bool) {
+	record, ok := ctx.Value(dryRunKey{}).(RecordFunc)
+	return record, ok
+}
+
+// WithConnectivityCheck returns a context in which notifiers in dry-run mode
+// also connect to the endpoints of their notifications, without sending
+// anything, and fail if they cannot.
+func WithConnectivityCheck(ctx context.Context) context.Context {
+	return context.WithValue(ctx, connectivityCheckKey{}, true)
+}
+
+// ConnectivityCheck returns true if notifiers in dry-run mode connect to the
+// endpoints of their notifications.
+func ConnectivityCheck(ctx context.Context) bool {
+	v, _ := ctx.Value(connectivityCheckKey{}).(bool)
+	return v
+}
+
+// dial opens a TCP connection to the host of the URL and closes it. Proxies
+// are not used.
+func dial(ctx context.Context, u *url.URL) error {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Do sends the request with the client, after setting its request options
+// and wrapping its transport. In dry-run mode, the body of the request is recorded instead and an empty
+// successful response is returned. Requests without a body record their
+// query string.
+func Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	SetRequestOptions(req)
+	record, ok := DryRun(req.Context())
+	if !ok {
+		tracing.Inject(req.Context(), req.Header)
+		return wrapTransport(client, req).Do(req)
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	payload := []byte(req.URL.RawQuery)
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		payload = b
+	} else {
+		contentType = "application/x-www-form-urlencoded"
+	}
+	record(contentType, payload)
+	if ConnectivityCheck(req.Context()) {
+		if err := dial(req.Context(), req.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+		Request:    req,
+	}, nil
+}
+
+// DryRunNotifier logs the payloads of the notifications of a notifier instead
+// of sending them.
+type DryRunNotifier struct {
+	notifier Notifier
+	logger   log.Logger
+}
+
+// NewDryRunNotifier returns a new DryRunNotifier wrapping the given notifier.
+func NewDryRunNotifier(n Notifier, l log.Logger) *DryRunNotifier {
+	return &DryRunNotifier{notifier: n, logger: l}
+}
+
+// Notify implements the Notifier interface.
+func (n *DryRunNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	// Payloads already recorded by the caller, e.g. when rendering
+	// notifications, are not logged.
+	if _, ok := DryRun(ctx); ok {
+		return n.notifier.Notify(ctx, alerts...)
+	}
+	ctx = WithDryRun(ctx, func(contentType string, payload []byte) {
+		level.Info(n.logger).Log(
+			"msg", "Dry run, notification not sent",
+			"alerts", len(alerts),
+			"content_type", contentType,
+			"payload", string(payload),
+		)
+	})
+	return n.notifier.Notify(ctx, alerts...)
+}
+
+// Payload is the payload of a request which a notifier would send.
+type Payload struct {
+	ContentType string
+	Body        []byte
+}
+
+// Rendered holds the payloads which an integration would send for a
+// notification.
+type Rendered struct {
+	Integration string
+	Index       int
+	Payloads    []Payload
+	Err         error
+}
+
+// Render returns the payloads which the given integrations of a receiver
+// would send for the alerts, without sending them. The alerts are grouped by
+// the labels they have in common.
+func Render(ctx context.Context, receiver string, integrations []Integration, alerts ...*types.Alert) []Rendered {
+	ctx = adHocGroupContext(ctx, receiver, alerts)
+
+	rendered := make([]Rendered, 0, len(integrations))
+	for _, i := range integrations {
+		r := Rendered{Integration: i.Name(), Index: i.Index(), Payloads: []Payload{}}
+		ictx := WithDryRun(ctx, func(contentType string, payload []byte) {
+			r.Payloads = append(r.Payloads, Payload{ContentType: contentType, Body: payload})
+		})
+		_, r.Err = i.Notify(ictx, alerts...)
+		rendered = append(rendered, r)
+	}
+	return rendered
+}
+
+// Validate renders a test notification through each of the given
+// integrations of a receiver without sending it, reporting the errors of
+// their templates. With connectivity, the integrations also connect to their
+// endpoints, without sending anything.
+func Validate(ctx context.Context, receiver string, integrations []Integration, connectivity bool) []TestResult {
+	now := time.Now()
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{
+				model.AlertNameLabel: "ConfigValidation",
+				"receiver":           model.LabelValue(receiver),
+			},
+			Annotations: model.LabelSet{
+				"summary":     "Configuration validation",
+				"description": "This notification validates the integrations of the receiver, it is never sent.",
+			},
+			StartsAt: now,
+			EndsAt:   now.Add(5 * time.Minute),
+		},
+		UpdatedAt: now,
+	}
+	if connectivity {
+		ctx = WithConnectivityCheck(ctx)
+	}
+
+	results := make([]TestResult, 0, len(integrations))
+	for _, r := range Render(ctx, receiver, integrations, alert) {
+		results = append(results, TestResult{Integration: r.Integration, Index: r.Index, Err: r.Err})
+	}
+	return results
+}
+
+// adHocGroupContext returns a context for notifying the alerts outside of the
+// pipeline, as a single group whose labels are the ones they have in common.
+func adHocGroupContext(ctx context.Context, receiver string, alerts []*types.Alert) context.Context {
+	groupLabels := commonLabels(alerts)
+	ctx = WithReceiverName(ctx, receiver)
+	ctx = WithGroupKey(ctx, "{}:"+groupLabels.String())
+	ctx = WithGroupLabels(ctx, groupLabels)
+	return WithNow(ctx, time.Now())
+}
+
+// commonLabels returns the labels which all alerts have with the same value.
+func commonLabels(alerts []*types.Alert) model.LabelSet {
+	if len(alerts) == 0 {
+		return model.LabelSet{}
+	}
+	common := alerts[0].Labels.Clone()
+	for _, a := range alerts[1:] {
+		for ln, lv := range common {
+			if a.Labels[ln] != lv {
+				delete(common, ln)
+			}
+		}
+	}
+	return common
+}
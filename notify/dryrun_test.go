@@ -0,0 +1,189 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestDo(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	resp, err := PostJSON(context.Background(), http.DefaultClient, srv.URL, bytes.NewBufferString(`{"text":"hello"}`))
+	require.NoError(t, err)
+	Drain(resp)
+	require.Equal(t, http.StatusTeapot, resp.StatusCode)
+	require.Equal(t, 1, requests)
+
+	var (
+		contentType string
+		payload     []byte
+	)
+	ctx := WithDryRun(context.Background(), func(ct string, p []byte) {
+		contentType, payload = ct, p
+	})
+	resp, err = PostJSON(ctx, http.DefaultClient, srv.URL, bytes.NewBufferString(`{"text":"hello"}`))
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "{}", string(body))
+	require.Equal(t, 1, requests)
+	require.Equal(t, "application/json", contentType)
+	require.Equal(t, `{"text":"hello"}`, string(payload))
+
+	// Requests without a body record their query string.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"?message=hello", nil)
+	require.NoError(t, err)
+	resp, err = Do(http.DefaultClient, req)
+	require.NoError(t, err)
+	Drain(resp)
+	require.Equal(t, 1, requests)
+	require.Equal(t, "application/x-www-form-urlencoded", contentType)
+	require.Equal(t, "message=hello", string(payload))
+}
+
+func TestDryRunNotifier(t *testing.T) {
+	var dryRun bool
+	n := NewDryRunNotifier(notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		record, ok := DryRun(ctx)
+		dryRun = ok
+		if ok {
+			record("text/plain", []byte("hello"))
+		}
+		return false, nil
+	}), log.NewNopLogger())
+
+	retry, err := n.Notify(context.Background(), &types.Alert{})
+	require.NoError(t, err)
+	require.False(t, retry)
+	require.True(t, dryRun)
+}
+
+func TestRender(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	var (
+		receiver    string
+		groupLabels model.LabelSet
+	)
+	post := notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		receiver, _ = ReceiverName(ctx)
+		groupLabels, _ = GroupLabels(ctx)
+		for _, a := range alerts {
+			resp, err := PostJSON(ctx, http.DefaultClient, srv.URL, bytes.NewBufferString(`{"alertname":"`+string(a.Labels["alertname"])+`"}`))
+			if err != nil {
+				return true, err
+			}
+			Drain(resp)
+		}
+		return false, nil
+	})
+	failing := notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		return false, errors.New("execute template")
+	})
+	integrations := []Integration{
+		NewIntegration(NewDryRunNotifier(post, log.NewNopLogger()), sendResolved(false), "webhook", 0),
+		NewIntegration(failing, sendResolved(false), "slack", 1),
+	}
+
+	rendered := Render(context.Background(), "team", integrations,
+		&types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "HighLatency", "team": "a"}}},
+		&types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "HighErrorRate", "team": "a"}}},
+	)
+	require.Equal(t, []Rendered{
+		{
+			Integration: "webhook",
+			Index:       0,
+			Payloads: []Payload{
+				{ContentType: "application/json", Body: []byte(`{"alertname":"HighLatency"}`)},
+				{ContentType: "application/json", Body: []byte(`{"alertname":"HighErrorRate"}`)},
+			},
+		},
+		{
+			Integration: "slack",
+			Index:       1,
+			Payloads:    []Payload{},
+			Err:         errors.New("execute template"),
+		},
+	}, rendered)
+	require.Equal(t, 0, requests)
+	require.Equal(t, "team", receiver)
+	require.Equal(t, model.LabelSet{"team": "a"}, groupLabels)
+}
+
+func TestValidate(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	post := func(url string) Notifier {
+		return notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			resp, err := PostJSON(ctx, http.DefaultClient, url, bytes.NewBufferString(`{}`))
+			if err != nil {
+				return true, err
+			}
+			Drain(resp)
+			return false, nil
+		})
+	}
+	failing := notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		return false, errors.New("execute template")
+	})
+	integrations := []Integration{
+		NewIntegration(post(srv.URL), sendResolved(false), "webhook", 0),
+		NewIntegration(post(closed.URL), sendResolved(false), "webhook", 1),
+		NewIntegration(failing, sendResolved(false), "slack", 0),
+	}
+
+	results := Validate(context.Background(), "team", integrations, false)
+	require.Equal(t, []TestResult{
+		{Integration: "webhook", Index: 0},
+		{Integration: "webhook", Index: 1},
+		{Integration: "slack", Index: 0, Err: errors.New("execute template")},
+	}, results)
+
+	results = Validate(context.Background(), "team", integrations, true)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.Error(t, results[1].Err)
+	require.EqualError(t, results[2].Err, "execute template")
+
+	// Nothing was sent.
+	require.Equal(t, 0, requests)
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Notifier implements a Notifier indexing notifications in Elasticsearch.
+type Notifier struct {
+	conf    *config.ElasticsearchConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+}
+
+// New returns a new Elasticsearch notifier.
+func New(c *config.ElasticsearchConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "elasticsearch", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:    c,
+		tmpl:    t,
+		logger:  l,
+		client:  client,
+		retrier: &notify.Retrier{},
+	}, nil
+}
+
+// document is the default document, the webhook message with the time of
+// the notification.
+type document struct {
+	Timestamp time.Time `json:"@timestamp"`
+	*webhook.Message
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	data := notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+	var body []byte
+	if n.conf.Document != "" {
+		text := notify.TmplText(n.tmpl, data, &err)(n.conf.Document)
+		if err != nil {
+			return false, fmt.Errorf("templating error: %s", err)
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(text), &obj); err != nil {
+			return false, fmt.Errorf("document is not a JSON object: %w", err)
+		}
+		body = []byte(text)
+	} else {
+		body, err = json.Marshal(document{
+			Timestamp: time.Now().UTC(),
+			Message: &webhook.Message{
+				Version:  "4",
+				Data:     data,
+				GroupKey: key.String(),
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// The index is escaped as a whole, as date math uses slashes.
+	path := "/" + url.PathEscape(n.conf.Index) + "/_doc"
+	level.Debug(n.logger).Log("msg", "Indexing notification", "incident", key)
+	// Fail over to the next nodes while the errors are recoverable.
+	var retry bool
+	for _, u := range n.conf.URLs {
+		retry, err = n.post(ctx, strings.TrimSuffix(u.String(), "/")+path, body)
+		if !retry {
+			break
+		}
+		level.Debug(n.logger).Log("msg", "failed to index notification", "err", err)
+	}
+	return retry, err
+}
+
+func (n *Notifier) post(ctx context.Context, target string, body []byte) (bool, error) {
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.conf.APIKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+string(n.conf.APIKey))
+	}
+
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	return n.retrier.CheckResponse(resp, resp.Body)
+}
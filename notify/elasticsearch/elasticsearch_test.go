@@ -0,0 +1,137 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestElasticsearchRetry(t *testing.T) {
+	notifier, err := New(
+		&config.ElasticsearchConfig{
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	for statusCode, expected := range test.RetryTests(test.DefaultRetryCodes()) {
+		actual, _ := notifier.retrier.Check(statusCode, nil)
+		require.Equal(t, expected, actual, fmt.Sprintf("error on status %d", statusCode))
+	}
+}
+
+func TestElasticsearchRedactedURL(t *testing.T) {
+	ctx, u, fn := test.GetContextWithCancelingURL()
+	defer fn()
+
+	secret := "secret"
+	notifier, err := New(
+		&config.ElasticsearchConfig{
+			URLs:       []*config.URL{{URL: u}},
+			APIKey:     config.Secret(secret),
+			Index:      "alertmanager",
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	test.AssertNotifyLeaksNoSecret(t, ctx, notifier, secret)
+}
+
+func TestElasticsearchNotify(t *testing.T) {
+	var (
+		path, auth string
+		got        map[string]interface{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		auth = r.Header.Get("Authorization")
+		got = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	// The first node is down.
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	d, err := url.Parse(down.URL)
+	require.NoError(t, err)
+	down.Close()
+
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "DiskFull"},
+			Annotations: model.LabelSet{"summary": "The disk is full"},
+			StartsAt:    time.Now(),
+			EndsAt:      time.Now().Add(time.Hour),
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	ctx = notify.WithReceiverName(ctx, "es")
+
+	notifier, err := New(
+		&config.ElasticsearchConfig{
+			URLs:       []*config.URL{{URL: d}, {URL: u}},
+			APIKey:     config.Secret("key"),
+			Index:      "<alertmanager-{now/d}>",
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	retry, err := notifier.Notify(ctx, alert)
+	require.NoError(t, err)
+	require.False(t, retry)
+	require.Equal(t, "/%3Calertmanager-%7Bnow%2Fd%7D%3E/_doc", path)
+	require.Equal(t, "ApiKey key", auth)
+	require.NotEmpty(t, got["@timestamp"])
+	require.Equal(t, "4", got["version"])
+	require.Equal(t, "es", got["receiver"])
+	require.Equal(t, "firing", got["status"])
+	require.Len(t, got["alerts"], 1)
+
+	notifier.conf.Document = `{"alertname": "{{ .CommonLabels.alertname }}"}`
+	_, err = notifier.Notify(ctx, alert)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"alertname": "DiskFull"}, got)
+
+	notifier.conf.Document = `{{ .CommonLabels.alertname }}`
+	retry, err = notifier.Notify(ctx, alert)
+	require.Error(t, err)
+	require.False(t, retry)
+}
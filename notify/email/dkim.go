@@ -0,0 +1,192 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// loadDKIMKey reads the PEM-encoded RSA or Ed25519 private key of the file.
+func loadDKIMKey(file string) (crypto.Signer, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	}
+	return nil, errors.Errorf("unsupported key type %T", key)
+}
+
+// dkimSign returns the message with a DKIM-Signature header (RFC 6376) using
+// the relaxed canonicalization of both the headers and the body, signed with
+// rsa-sha256 or ed25519-sha256 (RFC 8463) depending on the key.
+func dkimSign(msg []byte, c *config.DKIMConfig, key crypto.Signer, now time.Time) ([]byte, error) {
+	i := bytes.Index(msg, []byte("\r\n\r\n"))
+	if i < 0 {
+		return nil, errors.New("no end of the headers in message")
+	}
+	headers, body := msg[:i+2], msg[i+4:]
+
+	algorithm := "rsa-sha256"
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		algorithm = "ed25519-sha256"
+	}
+
+	bh := sha256.Sum256(relaxedBody(body))
+
+	fields := parseHeaders(headers)
+	var (
+		names  []string
+		signed []string
+	)
+	for _, name := range c.Headers {
+		// The last occurrence of a header is signed.
+		for j := len(fields) - 1; j >= 0; j-- {
+			if strings.EqualFold(fields[j].name, name) {
+				names = append(names, name)
+				signed = append(signed, relaxedHeader(fields[j].name, fields[j].value))
+				break
+			}
+		}
+	}
+
+	value := fmt.Sprintf("v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		algorithm, c.Domain, c.Selector, now.Unix(), strings.Join(names, ":"), base64.StdEncoding.EncodeToString(bh[:]))
+
+	h := sha256.New()
+	for _, s := range signed {
+		h.Write([]byte(s))
+	}
+	// The signature header is hashed without its trailing CRLF.
+	h.Write([]byte(strings.TrimSuffix(relaxedHeader("DKIM-Signature", " "+value), "\r\n")))
+	digest := h.Sum(nil)
+
+	var (
+		sig []byte
+		err error
+	)
+	if algorithm == "ed25519-sha256" {
+		sig, err = key.Sign(rand.Reader, digest, crypto.Hash(0))
+	} else {
+		sig, err = key.Sign(rand.Reader, digest, crypto.SHA256)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "sign message")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "DKIM-Signature: %s%s\r\n", value, base64.StdEncoding.EncodeToString(sig))
+	buf.Write(msg)
+	return buf.Bytes(), nil
+}
+
+type headerField struct {
+	name, value string
+}
+
+// parseHeaders splits the headers into fields, keeping the folding of their
+// values.
+func parseHeaders(headers []byte) []headerField {
+	var fields []headerField
+	for _, line := range strings.SplitAfter(string(headers), "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1].value += line
+			continue
+		}
+		if i := strings.Index(line, ":"); i > 0 {
+			fields = append(fields, headerField{name: strings.TrimRight(line[:i], " \t"), value: line[i+1:]})
+		}
+	}
+	return fields
+}
+
+// relaxedHeader returns the header field in the relaxed canonical form of
+// RFC 6376, section 3.4.2.
+func relaxedHeader(name, value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "")
+	return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + strings.TrimSpace(collapseWSP(value)) + "\r\n"
+}
+
+// relaxedBody returns the body in the relaxed canonical form of RFC 6376,
+// section 3.4.4.
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(collapseWSP(l), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// collapseWSP replaces the sequences of spaces and tabs with a single space.
+func collapseWSP(s string) string {
+	var (
+		b   strings.Builder
+		wsp bool
+	)
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			wsp = true
+			continue
+		}
+		if wsp {
+			b.WriteByte(' ')
+			wsp = false
+		}
+		b.WriteRune(r)
+	}
+	if wsp {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
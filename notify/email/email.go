@@ -34,9 +34,11 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/pkg/errors"
 	commoncfg "github.com/prometheus/common/config"
+	"golang.org/x/net/proxy"
 
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/pkg/srv"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 )
@@ -47,10 +49,14 @@ type Email struct {
 	tmpl     *template.Template
 	logger   log.Logger
 	hostname string
+	resolver srv.Resolver
+	policy   *config.TLSPolicy
+	pool     *pool
 }
 
-// New returns a new Email notifier.
-func New(c *config.EmailConfig, t *template.Template, l log.Logger) *Email {
+// New returns a new Email notifier restricting its TLS connections to the
+// given policy, if any.
+func New(c *config.EmailConfig, t *template.Template, l log.Logger, p *config.TLSPolicy) *Email {
 	if _, ok := c.Headers["Subject"]; !ok {
 		c.Headers["Subject"] = config.DefaultEmailSubject
 	}
@@ -66,11 +72,15 @@ func New(c *config.EmailConfig, t *template.Template, l log.Logger) *Email {
 	if err != nil {
 		h = "localhost.localdomain"
 	}
-	return &Email{conf: c, tmpl: t, logger: l, hostname: h}
+	n := &Email{conf: c, tmpl: t, logger: l, hostname: h, resolver: net.DefaultResolver, policy: p}
+	if c.ConnectionIdleTimeout > 0 {
+		n.pool = newPool(time.Duration(c.ConnectionIdleTimeout))
+	}
+	return n
 }
 
-// auth resolves a string of authentication mechanisms.
-func (n *Email) auth(mechs string) (smtp.Auth, error) {
+// auth resolves a string of authentication mechanisms for the smarthost.
+func (n *Email) auth(mechs, host string) (smtp.Auth, error) {
 	username := n.conf.AuthUsername
 
 	// If no username is set, keep going without authentication.
@@ -98,7 +108,7 @@ func (n *Email) auth(mechs string) (smtp.Auth, error) {
 			}
 			identity := n.conf.AuthIdentity
 
-			return smtp.PlainAuth(identity, username, password, n.conf.Smarthost.Host), nil
+			return smtp.PlainAuth(identity, username, password, host), nil
 		case "LOGIN":
 			password := string(n.conf.AuthPassword)
 			if password == "" {
@@ -114,133 +124,239 @@ func (n *Email) auth(mechs string) (smtp.Auth, error) {
 	return nil, err
 }
 
+// connect opens a connection to the smarthost, over TLS if its port is 465.
+func connect(ctx context.Context, dial func(context.Context, string, string) (net.Conn, error), smarthost config.HostPort, tlsConfig *tls.Config) (net.Conn, error) {
+	if smarthost.Port != "465" {
+		conn, err := dial(ctx, "tcp", smarthost.String())
+		if err != nil {
+			return nil, errors.Wrap(err, "establish connection to server")
+		}
+		return conn, nil
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = smarthost.Host
+	}
+	rawConn, err := dial(ctx, "tcp", smarthost.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "establish TLS connection to server")
+	}
+	conn := tls.Client(rawConn, tlsConfig)
+	if err := conn.Handshake(); err != nil {
+		rawConn.Close()
+		return nil, errors.Wrap(err, "establish TLS connection to server")
+	}
+	return conn, nil
+}
+
 // Notify implements the Notifier interface.
 func (n *Email) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
 	var (
-		c       *smtp.Client
-		conn    net.Conn
-		err     error
-		success = false
+		tmplErr error
+		data    = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl    = notify.TmplText(n.tmpl, data, &tmplErr)
 	)
-	if n.conf.Smarthost.Port == "465" {
-		tlsConfig, err := commoncfg.NewTLSConfig(&n.conf.TLSConfig)
+	from := tmpl(n.conf.From)
+	if tmplErr != nil {
+		return false, errors.Wrap(tmplErr, "execute 'from' template")
+	}
+	to := tmpl(n.conf.To)
+	if tmplErr != nil {
+		return false, errors.Wrap(tmplErr, "execute 'to' template")
+	}
+
+	fromAddrs, err := mail.ParseAddressList(from)
+	if err != nil {
+		return false, errors.Wrap(err, "parse 'from' addresses")
+	}
+	if len(fromAddrs) != 1 {
+		return false, errors.Errorf("must be exactly one 'from' address (got: %d)", len(fromAddrs))
+	}
+	toAddrs, err := mail.ParseAddressList(to)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse 'to' addresses")
+	}
+
+	msg, err := n.message(data)
+	if err != nil {
+		return false, err
+	}
+	if n.conf.DKIM != nil {
+		key, err := loadDKIMKey(n.conf.DKIM.PrivateKeyFile)
 		if err != nil {
-			return false, errors.Wrap(err, "parse TLS configuration")
+			return false, errors.Wrap(err, "load DKIM private key")
 		}
-		if tlsConfig.ServerName == "" {
-			tlsConfig.ServerName = n.conf.Smarthost.Host
+		if msg, err = dkimSign(msg, n.conf.DKIM, key, time.Now()); err != nil {
+			return false, errors.Wrap(err, "DKIM sign message")
 		}
-
-		conn, err = tls.Dial("tcp", n.conf.Smarthost.String(), tlsConfig)
-		if err != nil {
-			return true, errors.Wrap(err, "establish TLS connection to server")
+	}
+	if record, ok := notify.DryRun(ctx); ok {
+		record("message/rfc822", msg)
+		if notify.ConnectivityCheck(ctx) {
+			c, err := n.client(ctx)
+			if err != nil {
+				return false, err
+			}
+			c.Close()
 		}
-	} else {
-		var (
-			d   = net.Dialer{}
-			err error
-		)
-		conn, err = d.DialContext(ctx, "tcp", n.conf.Smarthost.String())
-		if err != nil {
-			return true, errors.Wrap(err, "establish connection to server")
+		return false, nil
+	}
+
+	var c *pooledClient
+	if n.pool != nil {
+		// Reset the session of an idle connection, which the server may
+		// have closed in the meantime.
+		if c = n.pool.get(); c != nil && c.Reset() != nil {
+			c.Close()
+			c = nil
 		}
 	}
-	c, err = smtp.NewClient(conn, n.conf.Smarthost.Host)
-	if err != nil {
-		conn.Close()
-		return true, errors.Wrap(err, "create SMTP client")
+	if c == nil {
+		if c, err = n.client(ctx); err != nil {
+			return true, err
+		}
 	}
+	success := false
 	defer func() {
+		if success && n.pool != nil {
+			n.pool.put(c)
+			return
+		}
 		// Try to clean up after ourselves but don't log anything if something has failed.
 		if err := c.Quit(); success && err != nil {
 			level.Warn(n.logger).Log("msg", "failed to close SMTP connection", "err", err)
 		}
 	}()
 
-	if n.conf.Hello != "" {
-		err = c.Hello(n.conf.Hello)
-		if err != nil {
-			return true, errors.Wrap(err, "send EHLO command")
+	if err = c.Mail(fromAddrs[0].Address); err != nil {
+		return true, errors.Wrap(err, "send MAIL command")
+	}
+	for _, addr := range toAddrs {
+		if err = c.Rcpt(addr.Address); err != nil {
+			return true, errors.Wrapf(err, "send RCPT command")
 		}
 	}
 
-	// Global Config guarantees RequireTLS is not nil.
-	if *n.conf.RequireTLS {
-		if ok, _ := c.Extension("STARTTLS"); !ok {
-			return true, errors.Errorf("'require_tls' is true (default) but %q does not advertise the STARTTLS extension", n.conf.Smarthost)
-		}
+	// Send the email headers and body.
+	message, err := c.Data()
+	if err != nil {
+		return true, errors.Wrapf(err, "send DATA command")
+	}
 
-		tlsConf, err := commoncfg.NewTLSConfig(&n.conf.TLSConfig)
-		if err != nil {
-			return false, errors.Wrap(err, "parse TLS configuration")
-		}
-		if tlsConf.ServerName == "" {
-			tlsConf.ServerName = n.conf.Smarthost.Host
-		}
+	_, err = message.Write(msg)
+	if err != nil {
+		message.Close()
+		return false, errors.Wrap(err, "write message")
+	}
+	if err := message.Close(); err != nil {
+		return false, errors.Wrap(err, "end DATA command")
+	}
 
-		if err := c.StartTLS(tlsConf); err != nil {
-			return true, errors.Wrap(err, "send STARTTLS command")
+	success = true
+	return false, nil
+}
+
+// client connects to the smarthost, failing over to the next targets of its
+// SRV records, and opens a SMTP session.
+func (n *Email) client(ctx context.Context) (*pooledClient, error) {
+	dial := (&net.Dialer{}).DialContext
+	if n.conf.ProxyURL.URL != nil {
+		d, err := proxy.FromURL(n.conf.ProxyURL.URL, &net.Dialer{})
+		if err != nil {
+			return nil, errors.Wrap(err, "create proxy dialer")
 		}
+		dial = d.(proxy.ContextDialer).DialContext
+	}
+	tlsConfig, err := commoncfg.NewTLSConfig(&n.conf.TLSConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse TLS configuration")
 	}
+	n.policy.Apply(tlsConfig)
 
-	if ok, mech := c.Extension("AUTH"); ok {
-		auth, err := n.auth(mech)
+	smarthosts := []config.HostPort{n.conf.Smarthost}
+	if n.conf.Smarthost.IsSRV() {
+		addrs, err := srv.Lookup(ctx, n.resolver, n.conf.Smarthost.Host)
 		if err != nil {
-			return true, errors.Wrap(err, "find auth mechanism")
+			return nil, errors.Wrap(err, "resolve smarthost")
 		}
-		if auth != nil {
-			if err := c.Auth(auth); err != nil {
-				return true, errors.Wrapf(err, "%T auth", auth)
-			}
+		smarthosts = smarthosts[:0]
+		for _, addr := range addrs {
+			host, port, _ := net.SplitHostPort(addr)
+			smarthosts = append(smarthosts, config.HostPort{Host: host, Port: port})
 		}
 	}
-
 	var (
-		tmplErr error
-		data    = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
-		tmpl    = notify.TmplText(n.tmpl, data, &tmplErr)
+		conn      net.Conn
+		smarthost config.HostPort
 	)
-	from := tmpl(n.conf.From)
-	if tmplErr != nil {
-		return false, errors.Wrap(tmplErr, "execute 'from' template")
-	}
-	to := tmpl(n.conf.To)
-	if tmplErr != nil {
-		return false, errors.Wrap(tmplErr, "execute 'to' template")
+	for _, smarthost = range smarthosts {
+		conn, err = connect(ctx, dial, smarthost, tlsConfig.Clone())
+		if err == nil {
+			break
+		}
+		level.Debug(n.logger).Log("msg", "failed to connect to smarthost", "smarthost", smarthost, "err", err)
 	}
-
-	addrs, err := mail.ParseAddressList(from)
 	if err != nil {
-		return false, errors.Wrap(err, "parse 'from' addresses")
+		return nil, err
 	}
-	if len(addrs) != 1 {
-		return false, errors.Errorf("must be exactly one 'from' address (got: %d)", len(addrs))
+	c, err := smtp.NewClient(conn, smarthost.Host)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "create SMTP client")
 	}
-	if err = c.Mail(addrs[0].Address); err != nil {
-		return true, errors.Wrap(err, "send MAIL command")
+	if err := n.open(c, smarthost, tlsConfig); err != nil {
+		c.Quit()
+		return nil, err
 	}
-	addrs, err = mail.ParseAddressList(to)
-	if err != nil {
-		return false, errors.Wrapf(err, "parse 'to' addresses")
+	return &pooledClient{Client: c}, nil
+}
+
+// open greets the server, upgrades the connection to TLS if required and
+// authenticates.
+func (n *Email) open(c *smtp.Client, smarthost config.HostPort, tlsConfig *tls.Config) error {
+	if n.conf.Hello != "" {
+		if err := c.Hello(n.conf.Hello); err != nil {
+			return errors.Wrap(err, "send EHLO command")
+		}
 	}
-	for _, addr := range addrs {
-		if err = c.Rcpt(addr.Address); err != nil {
-			return true, errors.Wrapf(err, "send RCPT command")
+
+	// Global Config guarantees RequireTLS is not nil.
+	if *n.conf.RequireTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.Errorf("'require_tls' is true (default) but %q does not advertise the STARTTLS extension", smarthost)
+		}
+
+		tlsConf := tlsConfig.Clone()
+		if tlsConf.ServerName == "" {
+			tlsConf.ServerName = smarthost.Host
+		}
+
+		if err := c.StartTLS(tlsConf); err != nil {
+			return errors.Wrap(err, "send STARTTLS command")
 		}
 	}
 
-	// Send the email headers and body.
-	message, err := c.Data()
-	if err != nil {
-		return true, errors.Wrapf(err, "send DATA command")
+	if ok, mech := c.Extension("AUTH"); ok {
+		auth, err := n.auth(mech, smarthost.Host)
+		if err != nil {
+			return errors.Wrap(err, "find auth mechanism")
+		}
+		if auth != nil {
+			if err := c.Auth(auth); err != nil {
+				return errors.Wrapf(err, "%T auth", auth)
+			}
+		}
 	}
-	defer message.Close()
+	return nil
+}
 
+// message renders the headers and body of the email.
+func (n *Email) message(data *template.Data) ([]byte, error) {
 	buffer := &bytes.Buffer{}
 	for header, t := range n.conf.Headers {
 		value, err := n.tmpl.ExecuteTextString(t, data)
 		if err != nil {
-			return false, errors.Wrapf(err, "execute %q header template", header)
+			return nil, errors.Wrapf(err, "execute %q header template", header)
 		}
 		fmt.Fprintf(buffer, "%s: %s\r\n", header, mime.QEncoding.Encode("utf-8", value))
 	}
@@ -249,8 +365,7 @@ func (n *Email) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
 		fmt.Fprintf(buffer, "Message-Id: %s\r\n", fmt.Sprintf("<%d.%d@%s>", time.Now().UnixNano(), rand.Uint64(), n.hostname))
 	}
 
-	multipartBuffer := &bytes.Buffer{}
-	multipartWriter := multipart.NewWriter(multipartBuffer)
+	multipartWriter := multipart.NewWriter(buffer)
 
 	fmt.Fprintf(buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
 	fmt.Fprintf(buffer, "Content-Type: multipart/alternative;  boundary=%s\r\n", multipartWriter.Boundary())
@@ -258,10 +373,6 @@ func (n *Email) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
 
 	// TODO: Add some useful headers here, such as URL of the alertmanager
 	// and active/resolved.
-	_, err = message.Write(buffer.Bytes())
-	if err != nil {
-		return false, errors.Wrap(err, "write headers")
-	}
 
 	if len(n.conf.Text) > 0 {
 		// Text template
@@ -270,20 +381,20 @@ func (n *Email) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
 			"Content-Type":              {"text/plain; charset=UTF-8"},
 		})
 		if err != nil {
-			return false, errors.Wrap(err, "create part for text template")
+			return nil, errors.Wrap(err, "create part for text template")
 		}
 		body, err := n.tmpl.ExecuteTextString(n.conf.Text, data)
 		if err != nil {
-			return false, errors.Wrap(err, "execute text template")
+			return nil, errors.Wrap(err, "execute text template")
 		}
 		qw := quotedprintable.NewWriter(w)
 		_, err = qw.Write([]byte(body))
 		if err != nil {
-			return true, errors.Wrap(err, "write text part")
+			return nil, errors.Wrap(err, "write text part")
 		}
 		err = qw.Close()
 		if err != nil {
-			return true, errors.Wrap(err, "close text part")
+			return nil, errors.Wrap(err, "close text part")
 		}
 	}
 
@@ -296,35 +407,29 @@ func (n *Email) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
 			"Content-Type":              {"text/html; charset=UTF-8"},
 		})
 		if err != nil {
-			return false, errors.Wrap(err, "create part for html template")
+			return nil, errors.Wrap(err, "create part for html template")
 		}
 		body, err := n.tmpl.ExecuteHTMLString(n.conf.HTML, data)
 		if err != nil {
-			return false, errors.Wrap(err, "execute html template")
+			return nil, errors.Wrap(err, "execute html template")
 		}
 		qw := quotedprintable.NewWriter(w)
 		_, err = qw.Write([]byte(body))
 		if err != nil {
-			return true, errors.Wrap(err, "write HTML part")
+			return nil, errors.Wrap(err, "write HTML part")
 		}
 		err = qw.Close()
 		if err != nil {
-			return true, errors.Wrap(err, "close HTML part")
+			return nil, errors.Wrap(err, "close HTML part")
 		}
 	}
 
-	err = multipartWriter.Close()
-	if err != nil {
-		return false, errors.Wrap(err, "close multipartWriter")
-	}
-
-	_, err = message.Write(multipartBuffer.Bytes())
+	err := multipartWriter.Close()
 	if err != nil {
-		return false, errors.Wrap(err, "write body buffer")
+		return nil, errors.Wrap(err, "close multipartWriter")
 	}
 
-	success = true
-	return false, nil
+	return buffer.Bytes(), nil
 }
 
 type loginAuth struct {
@@ -29,13 +29,24 @@
 package email
 
 import (
+	"bufio"
 	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,6 +57,7 @@ import (
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 )
@@ -188,7 +200,7 @@ func notifyEmailWithContext(ctx context.Context, cfg *config.EmailConfig, server
 		return nil, false, err
 	}
 	tmpl.ExternalURL, _ = url.Parse("http://am")
-	email := New(cfg, tmpl, log.NewNopLogger())
+	email := New(cfg, tmpl, log.NewNopLogger(), nil)
 
 	retry, err := email.Notify(ctx, firingAlert)
 	if err != nil {
@@ -256,24 +268,21 @@ func TestEmailNotifyWithErrors(t *testing.T) {
 			updateCfg: func(cfg *config.EmailConfig) {
 				cfg.Headers["subject"] = `{{ template "invalid" }}`
 			},
-			errMsg:   `execute "subject" header template:`,
-			hasEmail: true,
+			errMsg: `execute "subject" header template:`,
 		},
 		{
 			title: "invalid 'text' template",
 			updateCfg: func(cfg *config.EmailConfig) {
 				cfg.Text = `{{ template "invalid" }}`
 			},
-			errMsg:   `execute text template:`,
-			hasEmail: true,
+			errMsg: `execute text template:`,
 		},
 		{
 			title: "invalid 'html' template",
 			updateCfg: func(cfg *config.EmailConfig) {
 				cfg.HTML = `{{ template "invalid" }}`
 			},
-			errMsg:   `execute html template:`,
-			hasEmail: true,
+			errMsg: `execute html template:`,
 		},
 	} {
 		tc := tc
@@ -342,6 +351,171 @@ func TestEmailNotifyWithDoneContext(t *testing.T) {
 	require.Contains(t, err.Error(), "establish connection to server")
 }
 
+// TestEmailNotifyDryRun renders an email without connecting to the server.
+func TestEmailNotifyThroughSOCKS5Proxy(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// The proxy records the destination and refuses to connect to it.
+	target := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		// Greeting: version, number of methods and methods.
+		hdr := make([]byte, 2)
+		if _, err := io.ReadFull(r, hdr); err != nil {
+			return
+		}
+		if _, err := io.ReadFull(r, make([]byte, hdr[1])); err != nil {
+			return
+		}
+		conn.Write([]byte{5, 0})
+		// Request: version, command, reserved, address type and address.
+		req := make([]byte, 4)
+		if _, err := io.ReadFull(r, req); err != nil {
+			return
+		}
+		n, err := r.ReadByte()
+		if err != nil || req[3] != 3 {
+			return
+		}
+		addr := make([]byte, int(n)+2)
+		if _, err := io.ReadFull(r, addr); err != nil {
+			return
+		}
+		target <- fmt.Sprintf("%s:%d", addr[:n], int(addr[n])<<8|int(addr[n+1]))
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+	}()
+
+	proxyURL, err := url.Parse("socks5://" + ln.Addr().String())
+	require.NoError(t, err)
+	tmpl, err := template.FromGlobs()
+	require.NoError(t, err)
+	tmpl.ExternalURL, _ = url.Parse("http://am")
+	email := New(&config.EmailConfig{
+		Smarthost: config.HostPort{Host: "smtp.example.com", Port: "25"},
+		To:        emailTo,
+		From:      emailFrom,
+		Headers:   map[string]string{},
+		ProxyURL:  commoncfg.URL{URL: proxyURL},
+	}, tmpl, log.NewNopLogger(), nil)
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	retry, err := email.Notify(ctx, &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{},
+			StartsAt: time.Now(),
+		},
+	})
+	require.Error(t, err)
+	require.True(t, retry)
+	require.Equal(t, "smtp.example.com:25", <-target)
+}
+
+type fakeResolver map[string][]*net.SRV
+
+func (r fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	return name, r[name], nil
+}
+
+func TestEmailNotifySRVFailover(t *testing.T) {
+	// The first target refuses connections.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed.Close()
+
+	// The second target accepts the connection and rejects the session.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	accepted := make(chan struct{}, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		accepted <- struct{}{}
+		fmt.Fprint(conn, "554 no service\r\n")
+	}()
+
+	srvRecord := func(addr net.Addr) *net.SRV {
+		tcpAddr := addr.(*net.TCPAddr)
+		return &net.SRV{Target: tcpAddr.IP.String() + ".", Port: uint16(tcpAddr.Port)}
+	}
+	tmpl, err := template.FromGlobs()
+	require.NoError(t, err)
+	tmpl.ExternalURL, _ = url.Parse("http://am")
+	email := New(&config.EmailConfig{
+		Smarthost: config.HostPort{Host: "srv+_smtp._tcp.example.com"},
+		To:        emailTo,
+		From:      emailFrom,
+		Headers:   map[string]string{},
+	}, tmpl, log.NewNopLogger(), nil)
+	email.resolver = fakeResolver{
+		"_smtp._tcp.example.com": {srvRecord(closed.Addr()), srvRecord(ln.Addr())},
+	}
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	retry, err := email.Notify(ctx, &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{},
+			StartsAt: time.Now(),
+		},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "create SMTP client")
+	require.True(t, retry)
+	select {
+	case <-accepted:
+	default:
+		t.Fatal("no connection to the second target")
+	}
+}
+
+func TestEmailNotifyDryRun(t *testing.T) {
+	tmpl, err := template.FromGlobs()
+	require.NoError(t, err)
+	tmpl.ExternalURL, _ = url.Parse("http://am")
+
+	requireTLS := true
+	email := New(&config.EmailConfig{
+		Smarthost:  config.HostPort{Host: "localhost", Port: "0"},
+		To:         emailTo,
+		From:       emailFrom,
+		RequireTLS: &requireTLS,
+		Text:       "Text body",
+		Headers: map[string]string{
+			"Subject": "{{ len .Alerts }} {{ .Status }} alert(s)",
+		},
+	}, tmpl, log.NewNopLogger(), nil)
+
+	var (
+		contentType string
+		payload     []byte
+	)
+	ctx := notify.WithDryRun(context.Background(), func(ct string, p []byte) {
+		contentType, payload = ct, p
+	})
+	retry, err := email.Notify(ctx, &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{},
+			StartsAt: time.Now(),
+			EndsAt:   time.Now().Add(time.Hour),
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, retry)
+	require.Equal(t, "message/rfc822", contentType)
+	require.Contains(t, string(payload), "Subject: 1 firing alert(s)\r\n")
+	require.Contains(t, string(payload), "Text body")
+}
+
 // TestEmailNotifyWithoutAuthentication sends an email to an instance of
 // MailDev configured with no authentication then it checks that the server has
 // successfully processed the email.
@@ -571,7 +745,7 @@ func TestEmailConfigNoAuthMechs(t *testing.T) {
 	email := &Email{
 		conf: &config.EmailConfig{AuthUsername: "test"}, tmpl: &template.Template{}, logger: log.NewNopLogger(),
 	}
-	_, err := email.auth("")
+	_, err := email.auth("", "localhost")
 	require.Error(t, err)
 	require.Equal(t, err.Error(), "unknown auth mechanism: ")
 }
@@ -581,19 +755,19 @@ func TestEmailConfigMissingAuthParam(t *testing.T) {
 	email := &Email{
 		conf: conf, tmpl: &template.Template{}, logger: log.NewNopLogger(),
 	}
-	_, err := email.auth("CRAM-MD5")
+	_, err := email.auth("CRAM-MD5", "localhost")
 	require.Error(t, err)
 	require.Equal(t, err.Error(), "missing secret for CRAM-MD5 auth mechanism")
 
-	_, err = email.auth("PLAIN")
+	_, err = email.auth("PLAIN", "localhost")
 	require.Error(t, err)
 	require.Equal(t, err.Error(), "missing password for PLAIN auth mechanism")
 
-	_, err = email.auth("LOGIN")
+	_, err = email.auth("LOGIN", "localhost")
 	require.Error(t, err)
 	require.Equal(t, err.Error(), "missing password for LOGIN auth mechanism")
 
-	_, err = email.auth("PLAIN LOGIN")
+	_, err = email.auth("PLAIN LOGIN", "localhost")
 	require.Error(t, err)
 	require.Equal(t, err.Error(), "missing password for PLAIN auth mechanism; missing password for LOGIN auth mechanism")
 }
@@ -602,7 +776,180 @@ func TestEmailNoUsernameStillOk(t *testing.T) {
 	email := &Email{
 		conf: &config.EmailConfig{}, tmpl: &template.Template{}, logger: log.NewNopLogger(),
 	}
-	a, err := email.auth("CRAM-MD5")
+	a, err := email.auth("CRAM-MD5", "localhost")
 	require.NoError(t, err)
 	require.Nil(t, a)
 }
+
+func TestDKIMCanonicalization(t *testing.T) {
+	// Example of RFC 6376, section 3.4.5.
+	fields := parseHeaders([]byte("A: X\r\nB : Y\t\r\n\tZ  \r\n"))
+	require.Len(t, fields, 2)
+	require.Equal(t, "a:X\r\n", relaxedHeader(fields[0].name, fields[0].value))
+	require.Equal(t, "b:Y Z\r\n", relaxedHeader(fields[1].name, fields[1].value))
+
+	require.Equal(t, " C\r\nD E\r\n", string(relaxedBody([]byte(" C \r\nD \t E\r\n\r\n\r\n"))))
+	require.Empty(t, relaxedBody([]byte("\r\n\r\n")))
+}
+
+func TestDKIMSign(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f, err := ioutil.TempFile("", "dkim")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	require.NoError(t, pem.Encode(f, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	require.NoError(t, f.Close())
+
+	signer, err := loadDKIMKey(f.Name())
+	require.NoError(t, err)
+
+	c := &config.DKIMConfig{
+		Domain:   "example.com",
+		Selector: "alertmanager",
+		Headers:  []string{"From", "To", "Subject"},
+	}
+	msg := []byte("From: alertmanager@example.com\r\nTo: team@example.com\r\nSubject: [FIRING:1]  test\r\n\r\nbody \r\n\r\n")
+	signed, err := dkimSign(msg, c, signer, time.Unix(1600000000, 0))
+	require.NoError(t, err)
+
+	parts := strings.SplitN(string(signed), "\r\n", 2)
+	require.Len(t, parts, 2)
+	header := parts[0]
+	require.Equal(t, string(msg), parts[1])
+	require.True(t, strings.HasPrefix(header, "DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=example.com; s=alertmanager; t=1600000000; h=From:To:Subject; bh="))
+
+	i := strings.LastIndex(header, "b=")
+	sig, err := base64.StdEncoding.DecodeString(header[i+2:])
+	require.NoError(t, err)
+
+	h := sha256.New()
+	h.Write([]byte("from:alertmanager@example.com\r\nto:team@example.com\r\nsubject:[FIRING:1] test\r\n"))
+	h.Write([]byte("dkim-signature:" + header[len("DKIM-Signature: "):i+2]))
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, h.Sum(nil), sig))
+
+	bh := sha256.Sum256([]byte("body\r\n"))
+	require.Contains(t, header, "bh="+base64.StdEncoding.EncodeToString(bh[:])+";")
+}
+
+// fakeSMTPServer accepts SMTP sessions without extensions and records the
+// HELO names and the number of connections.
+type fakeSMTPServer struct {
+	ln    net.Listener
+	mtx   sync.Mutex
+	conns int
+	hello []string
+	mails int
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeSMTPServer{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mtx.Lock()
+			s.conns++
+			s.mtx.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 localhost\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.Fields(line)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			s.mtx.Lock()
+			s.hello = append(s.hello, strings.TrimSpace(line[5:]))
+			s.mtx.Unlock()
+			fmt.Fprint(conn, "250 localhost\r\n")
+		case "DATA":
+			fmt.Fprint(conn, "354 go ahead\r\n")
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+			}
+			s.mtx.Lock()
+			s.mails++
+			s.mtx.Unlock()
+			fmt.Fprint(conn, "250 queued\r\n")
+		case "QUIT":
+			fmt.Fprint(conn, "221 bye\r\n")
+			return
+		default:
+			fmt.Fprint(conn, "250 ok\r\n")
+		}
+	}
+}
+
+func TestEmailNotifyReusesConnection(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	defer server.ln.Close()
+	host, port, err := net.SplitHostPort(server.ln.Addr().String())
+	require.NoError(t, err)
+
+	tmpl, err := template.FromGlobs()
+	require.NoError(t, err)
+	tmpl.ExternalURL, _ = url.Parse("http://am")
+
+	requireTLS := false
+	email := New(&config.EmailConfig{
+		Smarthost:             config.HostPort{Host: host, Port: port},
+		To:                    emailTo,
+		From:                  emailFrom,
+		Hello:                 "alertmanager.example.com",
+		RequireTLS:            &requireTLS,
+		Headers:               map[string]string{},
+		ConnectionIdleTimeout: model.Duration(time.Minute),
+	}, tmpl, log.NewNopLogger(), nil)
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{},
+			StartsAt: time.Now(),
+		},
+	}
+	for i := 0; i < 3; i++ {
+		retry, err := email.Notify(ctx, alert)
+		require.NoError(t, err)
+		require.False(t, retry)
+	}
+
+	server.mtx.Lock()
+	require.Equal(t, 1, server.conns)
+	require.Equal(t, 3, server.mails)
+	require.Equal(t, []string{"alertmanager.example.com"}, server.hello)
+	server.mtx.Unlock()
+
+	// The idle connection is closed after the timeout.
+	c := email.pool.get()
+	require.NotNil(t, c)
+	email.pool.put(c)
+	email.pool.timeout = 0
+	email.pool.put(email.pool.get())
+	require.Eventually(t, func() bool {
+		email.pool.mtx.Lock()
+		defer email.pool.mtx.Unlock()
+		return len(email.pool.idle) == 0
+	}, time.Second, 10*time.Millisecond)
+}
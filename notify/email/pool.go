@@ -0,0 +1,79 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"net/smtp"
+	"sync"
+	"time"
+)
+
+// pool keeps the SMTP connections open after a message for the next ones,
+// until they have been idle for the timeout.
+type pool struct {
+	mtx     sync.Mutex
+	timeout time.Duration
+	idle    []*pooledClient
+}
+
+type pooledClient struct {
+	*smtp.Client
+	timer *time.Timer
+	// gen counts the returns to the pool, so that the timers of the previous
+	// ones are ignored.
+	gen uint64
+}
+
+func newPool(timeout time.Duration) *pool {
+	return &pool{timeout: timeout}
+}
+
+// get returns the most recently used idle client, if any.
+func (p *pool) get() *pooledClient {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if len(p.idle) == 0 {
+		return nil
+	}
+	c := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+	c.timer.Stop()
+	return c
+}
+
+// put returns the client to the pool. It is closed once idle for the timeout
+// unless taken again.
+func (p *pool) put(c *pooledClient) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.idle = append(p.idle, c)
+	c.gen++
+	gen := c.gen
+	c.timer = time.AfterFunc(p.timeout, func() { p.expire(c, gen) })
+}
+
+func (p *pool) expire(c *pooledClient, gen uint64) {
+	p.mtx.Lock()
+	for i, ic := range p.idle {
+		if ic == c && c.gen == gen {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			p.mtx.Unlock()
+			c.Quit()
+			return
+		}
+	}
+	p.mtx.Unlock()
+}
@@ -0,0 +1,214 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// enrichmentRequest is the body sent to enrichment endpoints.
+type enrichmentRequest struct {
+	Labels model.LabelSet `json:"labels"`
+}
+
+// enrichmentResponse is the body expected from enrichment endpoints.
+type enrichmentResponse struct {
+	Annotations model.LabelSet `json:"annotations"`
+}
+
+type enrichmentKey struct {
+	url string
+	fp  model.Fingerprint
+}
+
+type enrichmentEntry struct {
+	annotations model.LabelSet
+	expiresAt   time.Time
+}
+
+// EnrichStage adds the annotations returned by the enrichment endpoint of the
+// route to the alerts. Annotations already set on an alert are kept.
+// Responses are cached per label set for the configured time.
+type EnrichStage struct {
+	metrics *Metrics
+	now     func() time.Time
+
+	mtx     sync.Mutex
+	clients map[*config.EnrichmentConfig]*http.Client
+	cache   map[enrichmentKey]enrichmentEntry
+}
+
+// NewEnrichStage returns a new EnrichStage.
+func NewEnrichStage(m *Metrics) *EnrichStage {
+	return &EnrichStage{
+		metrics: m,
+		now:     time.Now,
+		clients: map[*config.EnrichmentConfig]*http.Client{},
+		cache:   map[enrichmentKey]enrichmentEntry{},
+	}
+}
+
+// Exec implements the Stage interface. Alerts which cannot be enriched within
+// the timeout are passed on unchanged.
+func (n *EnrichStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	conf, ok := Enrichment(ctx)
+	if !ok || conf == nil {
+		return ctx, alerts, nil
+	}
+	client, err := n.client(conf)
+	if err != nil {
+		level.Warn(l).Log("msg", "Failed to create enrichment client", "err", err)
+		return ctx, alerts, nil
+	}
+
+	n.gc()
+
+	ectx, cancel := context.WithTimeout(ctx, time.Duration(conf.Timeout))
+	defer cancel()
+
+	var (
+		res     = make([]*types.Alert, 0, len(alerts))
+		failed  int
+		lastErr error
+	)
+	for _, a := range alerts {
+		annotations, err := n.lookup(ectx, client, conf, a.Labels)
+		if err != nil {
+			failed++
+			lastErr = err
+			res = append(res, a)
+			continue
+		}
+		res = append(res, withAnnotations(a, annotations))
+	}
+	if failed > 0 {
+		level.Warn(l).Log("msg", "Failed to enrich alerts", "failed", failed, "err", lastErr)
+	}
+	return ctx, res, nil
+}
+
+func (n *EnrichStage) client(conf *config.EnrichmentConfig) (*http.Client, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if c, ok := n.clients[conf]; ok {
+		return c, nil
+	}
+	httpConfig := commoncfg.DefaultHTTPClientConfig
+	if conf.HTTPConfig != nil {
+		httpConfig = *conf.HTTPConfig
+	}
+	c, err := commoncfg.NewClientFromConfig(httpConfig, "enrichment")
+	if err != nil {
+		return nil, err
+	}
+	n.clients[conf] = c
+	return c, nil
+}
+
+// gc removes expired cache entries.
+func (n *EnrichStage) gc() {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	now := n.now()
+	for k, e := range n.cache {
+		if !now.Before(e.expiresAt) {
+			delete(n.cache, k)
+		}
+	}
+}
+
+// lookup returns the annotations for the label set from the cache or the
+// enrichment endpoint.
+func (n *EnrichStage) lookup(ctx context.Context, client *http.Client, conf *config.EnrichmentConfig, lset model.LabelSet) (model.LabelSet, error) {
+	key := enrichmentKey{url: conf.URL.String(), fp: lset.Fingerprint()}
+
+	n.mtx.Lock()
+	e, ok := n.cache[key]
+	n.mtx.Unlock()
+	if ok && n.now().Before(e.expiresAt) {
+		return e.annotations, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	n.metrics.numEnrichmentRequestsTotal.Inc()
+	annotations, err := n.request(ctx, client, conf, lset)
+	if err != nil {
+		n.metrics.numEnrichmentRequestsFailedTotal.Inc()
+		return nil, err
+	}
+
+	n.mtx.Lock()
+	n.cache[key] = enrichmentEntry{
+		annotations: annotations,
+		expiresAt:   n.now().Add(time.Duration(conf.CacheTTL)),
+	}
+	n.mtx.Unlock()
+	return annotations, nil
+}
+
+func (n *EnrichStage) request(ctx context.Context, client *http.Client, conf *config.EnrichmentConfig, lset model.LabelSet) (model.LabelSet, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(&enrichmentRequest{Labels: lset}); err != nil {
+		return nil, err
+	}
+	resp, err := PostJSON(ctx, client, conf.URL.String(), &buf)
+	if err != nil {
+		return nil, RedactURL(err)
+	}
+	defer Drain(resp)
+
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+	var msg enrichmentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return nil, err
+	}
+	return msg.Annotations, nil
+}
+
+// withAnnotations returns a copy of the alert with the given annotations
+// added. Annotations of the alert take precedence.
+func withAnnotations(a *types.Alert, annotations model.LabelSet) *types.Alert {
+	if len(annotations) == 0 {
+		return a
+	}
+	na := *a
+	na.Annotations = make(model.LabelSet, len(a.Annotations)+len(annotations))
+	for ln, lv := range annotations {
+		na.Annotations[ln] = lv
+	}
+	for ln, lv := range a.Annotations {
+		na.Annotations[ln] = lv
+	}
+	return &na
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestEnrichStage(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req enrichmentRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Labels["alertname"] == "fail" {
+			http.Error(w, "failed", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(&enrichmentResponse{
+			Annotations: model.LabelSet{
+				"owner":   "team-" + req.Labels["alertname"],
+				"summary": "enriched",
+			},
+		})
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	conf := &config.EnrichmentConfig{
+		URL:      &config.URL{URL: u},
+		Timeout:  model.Duration(time.Second),
+		CacheTTL: model.Duration(time.Minute),
+	}
+
+	stage := NewEnrichStage(NewMetrics(prometheus.NewRegistry()))
+	now := time.Now()
+	stage.now = func() time.Time { return now }
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "a"},
+				Annotations: model.LabelSet{"summary": "original"},
+			},
+		},
+		{
+			Alert: model.Alert{
+				Labels: model.LabelSet{"alertname": "fail"},
+			},
+		},
+	}
+
+	// Without an enrichment configuration the alerts are passed on unchanged.
+	_, res, err := stage.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Equal(t, alerts, res)
+	require.Equal(t, 0, requests)
+
+	ctx := WithEnrichment(context.Background(), conf)
+	_, res, err = stage.Exec(ctx, log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, model.LabelSet{"owner": "team-a", "summary": "original"}, res[0].Annotations)
+	// Alerts that failed to be enriched are passed on unchanged.
+	require.Equal(t, alerts[1], res[1])
+	require.Equal(t, 2, requests)
+	// The original alerts are not modified.
+	require.Equal(t, model.LabelSet{"summary": "original"}, alerts[0].Annotations)
+
+	// Successful responses are cached.
+	_, _, err = stage.Exec(ctx, log.NewNopLogger(), alerts[0])
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+
+	now = now.Add(time.Minute)
+	_, _, err = stage.Exec(ctx, log.NewNopLogger(), alerts[0])
+	require.NoError(t, err)
+	require.Equal(t, 3, requests)
+}
+
+func TestEnrichStageTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The request context is only canceled once the body was read.
+		ioutil.ReadAll(r.Body)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	conf := &config.EnrichmentConfig{
+		URL:      &config.URL{URL: u},
+		Timeout:  model.Duration(50 * time.Millisecond),
+		CacheTTL: model.Duration(time.Minute),
+	}
+
+	stage := NewEnrichStage(NewMetrics(prometheus.NewRegistry()))
+	alert := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}}
+
+	start := time.Now()
+	_, res, err := stage.Exec(WithEnrichment(context.Background(), conf), log.NewNopLogger(), alert)
+	require.NoError(t, err)
+	require.Equal(t, []*types.Alert{alert}, res)
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+}
@@ -0,0 +1,203 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const (
+	// perPage is the number of open issues listed per request, and
+	// maxPages the number of pages searched for the issue of a group.
+	perPage  = 100
+	maxPages = 10
+	// maxBodyLen is the maximum length of the bodies of issues and
+	// comments.
+	maxBodyLen = 65536
+)
+
+// Notifier implements a Notifier for GitHub issues.
+type Notifier struct {
+	conf    *config.GithubConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+}
+
+// New returns a new GitHub notifier.
+func New(c *config.GithubConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "github", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:    c,
+		tmpl:    t,
+		logger:  l,
+		client:  client,
+		retrier: &notify.Retrier{},
+	}, nil
+}
+
+// issue is an issue, or a comment, sent to the API.
+type issue struct {
+	Title     string   `json:"title,omitempty"`
+	Body      string   `json:"body,omitempty"`
+	State     string   `json:"state,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// marker returns the hidden text identifying the issue of an alert group
+// in its body.
+func marker(key notify.Key) string {
+	return fmt.Sprintf("<!-- alertmanager group_id: %s -->", key.Hash())
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		resolved = types.Alerts(as...).Status() == model.AlertResolved
+		data     = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl     = notify.TmplText(n.tmpl, data, &err)
+		title    = tmpl(n.conf.Title)
+		body     = tmpl(n.conf.Body)
+	)
+	if err != nil {
+		return false, fmt.Errorf("templating error: %s", err)
+	}
+	body, truncated := notify.TruncateMessage(body, maxBodyLen-len(marker(key))-2, data)
+	if truncated {
+		level.Debug(n.logger).Log("msg", "Truncated body", "key", key)
+	}
+
+	// In dry-run mode, the group is assumed to have no open issue.
+	var number int
+	if _, ok := notify.DryRun(ctx); !ok {
+		var retry bool
+		number, retry, err = n.findIssue(ctx, key)
+		if err != nil {
+			return retry, err
+		}
+	}
+
+	switch {
+	case number == 0 && !resolved:
+		return n.request(ctx, http.MethodPost, "issues", nil, issue{
+			Title:     title,
+			Body:      body + "\n\n" + marker(key),
+			Labels:    n.conf.Labels,
+			Assignees: n.conf.Assignees,
+		}, nil)
+	case number != 0 && resolved:
+		path := "issues/" + strconv.Itoa(number)
+		if retry, err := n.request(ctx, http.MethodPost, path+"/comments", nil, issue{Body: body}, nil); err != nil {
+			return retry, err
+		}
+		return n.request(ctx, http.MethodPatch, path, nil, issue{State: "closed"}, nil)
+	}
+	return false, nil
+}
+
+// findIssue returns the number of the open issue of the group, or 0 if
+// there is none.
+func (n *Notifier) findIssue(ctx context.Context, key notify.Key) (int, bool, error) {
+	m := marker(key)
+	for page := 1; page <= maxPages; page++ {
+		q := url.Values{}
+		q.Set("state", "open")
+		q.Set("per_page", strconv.Itoa(perPage))
+		q.Set("page", strconv.Itoa(page))
+		if len(n.conf.Labels) > 0 {
+			q.Set("labels", strings.Join(n.conf.Labels, ","))
+		}
+		var issues []struct {
+			Number int    `json:"number"`
+			Body   string `json:"body"`
+		}
+		if retry, err := n.request(ctx, http.MethodGet, "issues", q, nil, &issues); err != nil {
+			return 0, retry, err
+		}
+		for _, i := range issues {
+			if strings.Contains(i.Body, m) {
+				return i.Number, false, nil
+			}
+		}
+		if len(issues) < perPage {
+			break
+		}
+	}
+	return 0, false, nil
+}
+
+// request sends a request to the given path of the API of the repository
+// and decodes the response into out, if not nil.
+func (n *Notifier) request(ctx context.Context, method, path string, q url.Values, in, out interface{}) (bool, error) {
+	u := n.conf.APIURL.Copy()
+	u.Path = fmt.Sprintf("%s/repos/%s/%s", strings.TrimSuffix(u.Path, "/"), n.conf.Repo, path)
+	u.RawQuery = q.Encode()
+
+	var buf bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&buf).Encode(in); err != nil {
+			return false, err
+		}
+	}
+	req, err := http.NewRequest(method, u.String(), &buf)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+string(n.conf.Token))
+
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	if retry, err := n.retrier.CheckResponse(resp, resp.Body); err != nil {
+		return retry, err
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return true, fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return false, nil
+}
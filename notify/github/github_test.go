@@ -0,0 +1,129 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestGithubRetry(t *testing.T) {
+	notifier, err := New(
+		&config.GithubConfig{
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	for statusCode, expected := range test.RetryTests(test.DefaultRetryCodes()) {
+		actual, _ := notifier.retrier.Check(statusCode, nil)
+		require.Equal(t, expected, actual, fmt.Sprintf("error on status %d", statusCode))
+	}
+}
+
+func TestGithubNotify(t *testing.T) {
+	type request struct {
+		method, path, query string
+		body                map[string]interface{}
+	}
+	var (
+		reqs []request
+		open []map[string]interface{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		req := request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery}
+		if r.Method == http.MethodGet {
+			require.NoError(t, json.NewEncoder(w).Encode(open))
+		} else {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req.body))
+		}
+		reqs = append(reqs, req)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	notifier, err := New(
+		&config.GithubConfig{
+			APIURL:     &config.URL{URL: u},
+			Token:      config.Secret("token"),
+			Repo:       "acme/ops",
+			Title:      `{{ .CommonLabels.alertname }}`,
+			Body:       `{{ .Status }}`,
+			Labels:     []string{"alert", "toil"},
+			Assignees:  []string{"oncall"},
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	key := notify.Key("1")
+	ctx := notify.WithGroupKey(context.Background(), string(key))
+	alert := func(endsAt time.Time) *types.Alert {
+		return &types.Alert{Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "DiskFull"},
+			StartsAt: time.Now().Add(-time.Hour),
+			EndsAt:   endsAt,
+		}}
+	}
+
+	// A firing group without an open issue opens one.
+	_, err = notifier.Notify(ctx, alert(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, []request{
+		{method: http.MethodGet, path: "/repos/acme/ops/issues", query: "labels=alert%2Ctoil&page=1&per_page=100&state=open"},
+		{method: http.MethodPost, path: "/repos/acme/ops/issues", body: map[string]interface{}{
+			"title":     "DiskFull",
+			"body":      "firing\n\n" + marker(key),
+			"labels":    []interface{}{"alert", "toil"},
+			"assignees": []interface{}{"oncall"},
+		}},
+	}, reqs)
+
+	// The open issue of the group is not opened again and is commented and
+	// closed once the group is resolved.
+	reqs = nil
+	open = []map[string]interface{}{
+		{"number": 1, "body": "other\n\n" + marker(notify.Key("2"))},
+		{"number": 7, "body": "firing\n\n" + marker(key)},
+	}
+	_, err = notifier.Notify(ctx, alert(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	_, err = notifier.Notify(ctx, alert(time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+	require.Len(t, reqs, 4)
+	require.Equal(t, request{method: http.MethodPost, path: "/repos/acme/ops/issues/7/comments", body: map[string]interface{}{"body": "resolved"}}, reqs[2])
+	require.Equal(t, request{method: http.MethodPatch, path: "/repos/acme/ops/issues/7", body: map[string]interface{}{"state": "closed"}}, reqs[3])
+}
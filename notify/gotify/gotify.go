@@ -0,0 +1,116 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Notifier implements a Notifier for Gotify messages.
+type Notifier struct {
+	conf    *config.GotifyConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+}
+
+// New returns a new Gotify notifier.
+func New(c *config.GotifyConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "gotify", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:    c,
+		tmpl:    t,
+		logger:  l,
+		client:  client,
+		retrier: &notify.Retrier{},
+	}, nil
+}
+
+// message is the payload of the message API.
+type message struct {
+	Title    string `json:"title,omitempty"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		data     = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl     = notify.TmplText(n.tmpl, data, &err)
+		title    = tmpl(n.conf.Title)
+		text     = tmpl(n.conf.Message)
+		priority = tmpl(n.conf.Priority)
+	)
+	if err != nil {
+		return false, fmt.Errorf("templating error: %s", err)
+	}
+	if m := notify.LookupSeverity(n.conf.SeverityMappings, as...); m != nil && m.Gotify != "" {
+		priority = m.Gotify
+	}
+	p, err := strconv.Atoi(strings.TrimSpace(priority))
+	if err != nil || p < 0 || p > 10 {
+		return false, fmt.Errorf("invalid priority %q, must be between 0 and 10", priority)
+	}
+	if strings.TrimSpace(text) == "" {
+		// Gotify rejects empty messages.
+		text = "(no details)"
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(message{Title: title, Message: text, Priority: p}); err != nil {
+		return false, err
+	}
+
+	u := strings.TrimSuffix(n.conf.URL.String(), "/") + "/message"
+	req, err := http.NewRequest("POST", u, &buf)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", string(n.conf.Token))
+
+	level.Debug(n.logger).Log("msg", "Sending message", "incident", key)
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	return n.retrier.CheckResponse(resp, resp.Body)
+}
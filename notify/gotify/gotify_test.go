@@ -0,0 +1,139 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestGotifyRetry(t *testing.T) {
+	notifier, err := New(
+		&config.GotifyConfig{
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	for statusCode, expected := range test.RetryTests(test.DefaultRetryCodes()) {
+		actual, _ := notifier.retrier.Check(statusCode, nil)
+		require.Equal(t, expected, actual, fmt.Sprintf("error on status %d", statusCode))
+	}
+}
+
+func TestGotifyRedactedURL(t *testing.T) {
+	ctx, u, fn := test.GetContextWithCancelingURL()
+	defer fn()
+
+	secret := "secret"
+	notifier, err := New(
+		&config.GotifyConfig{
+			URL:        &config.URL{URL: u},
+			Token:      config.Secret(secret),
+			Priority:   "5",
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	test.AssertNotifyLeaksNoSecret(t, ctx, notifier, secret)
+}
+
+func TestGotifyNotify(t *testing.T) {
+	var (
+		path, token string
+		got         message
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		token = r.Header.Get("X-Gotify-Key")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL + "/gotify/")
+	require.NoError(t, err)
+
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "DiskFull", "severity": "critical"},
+			Annotations: model.LabelSet{"summary": "The disk is full"},
+			StartsAt:    time.Now(),
+			EndsAt:      time.Now().Add(time.Hour),
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "1")
+
+	for _, tc := range []struct {
+		priority string
+		mappings *config.SeverityMappings
+		expected int
+		err      string
+	}{
+		{priority: "7", expected: 7},
+		{
+			priority: "7",
+			mappings: &config.SeverityMappings{
+				Label:    "severity",
+				Mappings: []*config.SeverityMapping{{Severity: "critical", Gotify: "10"}},
+			},
+			expected: 10,
+		},
+		{priority: "urgent", err: `invalid priority "urgent", must be between 0 and 10`},
+	} {
+		notifier, err := New(
+			&config.GotifyConfig{
+				URL:              &config.URL{URL: u},
+				Token:            config.Secret("token"),
+				Title:            `{{ .CommonLabels.alertname }}`,
+				Message:          `{{ .CommonAnnotations.summary }}`,
+				Priority:         tc.priority,
+				SeverityMappings: tc.mappings,
+				HTTPConfig:       &commoncfg.HTTPClientConfig{},
+			},
+			test.CreateTmpl(t),
+			log.NewNopLogger(),
+		)
+		require.NoError(t, err)
+
+		retry, err := notifier.Notify(ctx, alert)
+		require.False(t, retry)
+		if tc.err != "" {
+			require.EqualError(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, "/gotify/message", path)
+		require.Equal(t, "token", token)
+		require.Equal(t, message{Title: "DiskFull", Message: "The disk is full", Priority: tc.expected}, got)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Notifier implements a Notifier for Grafana annotations.
+type Notifier struct {
+	conf    *config.GrafanaConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+}
+
+// New returns a new Grafana notifier.
+func New(c *config.GrafanaConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "grafana", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:    c,
+		tmpl:    t,
+		logger:  l,
+		client:  client,
+		retrier: &notify.Retrier{},
+	}, nil
+}
+
+// annotation is an annotation of the Grafana API. Times are in milliseconds.
+type annotation struct {
+	ID           int64    `json:"id,omitempty"`
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      int      `json:"panelId,omitempty"`
+	Time         int64    `json:"time,omitempty"`
+	TimeEnd      int64    `json:"timeEnd,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Text         string   `json:"text,omitempty"`
+}
+
+// groupTag returns the tag identifying the annotations of an alert group.
+func groupTag(key notify.Key) string {
+	return "group_id:" + key.Hash()
+}
+
+// Notify implements the Notifier interface. A firing alert group opens an
+// annotation at the start of its earliest alert, which becomes a region
+// ending with its latest alert once the group is resolved.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		alerts = types.Alerts(as...)
+		data   = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl   = notify.TmplText(n.tmpl, data, &err)
+		text   = tmpl(n.conf.Text)
+		tags   []string
+	)
+	for _, t := range n.conf.Tags {
+		if t = tmpl(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("templating error: %s", err)
+	}
+	tags = append(tags, groupTag(key))
+
+	var start, end time.Time
+	for _, a := range alerts {
+		if start.IsZero() || a.StartsAt.Before(start) {
+			start = a.StartsAt
+		}
+		if a.EndsAt.After(end) {
+			end = a.EndsAt
+		}
+	}
+
+	// In dry-run mode, the group is assumed to have no open annotation.
+	var open *annotation
+	if _, ok := notify.DryRun(ctx); !ok {
+		q := url.Values{}
+		q.Set("tags", groupTag(key))
+		q.Set("type", "annotation")
+		var found []annotation
+		if retry, err := n.request(ctx, http.MethodGet, "", q, nil, &found); err != nil {
+			return retry, err
+		}
+		// An annotation is open until its end is set.
+		for i := range found {
+			if found[i].TimeEnd == 0 || found[i].TimeEnd == found[i].Time {
+				open = &found[i]
+				break
+			}
+		}
+	}
+
+	switch {
+	case open == nil && alerts.Status() == model.AlertFiring:
+		return n.request(ctx, http.MethodPost, "", nil, annotation{
+			DashboardUID: n.conf.DashboardUID,
+			PanelID:      n.conf.PanelID,
+			Time:         toMillis(start),
+			Tags:         tags,
+			Text:         text,
+		}, nil)
+	case open != nil && alerts.Status() == model.AlertResolved:
+		return n.request(ctx, http.MethodPatch, strconv.FormatInt(open.ID, 10), nil, annotation{
+			TimeEnd: toMillis(end),
+			Tags:    tags,
+			Text:    text,
+		}, nil)
+	}
+	return false, nil
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// request sends a request to the given path of the annotations API and
+// decodes the response into out, if not nil.
+func (n *Notifier) request(ctx context.Context, method, path string, q url.Values, in, out interface{}) (bool, error) {
+	u := n.conf.APIURL.Copy()
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/annotations"
+	if path != "" {
+		u.Path += "/" + path
+	}
+	u.RawQuery = q.Encode()
+
+	var buf bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&buf).Encode(in); err != nil {
+			return false, err
+		}
+	}
+	req, err := http.NewRequest(method, u.String(), &buf)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+string(n.conf.Token))
+
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	if retry, err := n.retrier.CheckResponse(resp, resp.Body); err != nil {
+		return retry, err
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return true, fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestGrafanaRetry(t *testing.T) {
+	notifier, err := New(
+		&config.GrafanaConfig{
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	for statusCode, expected := range test.RetryTests(test.DefaultRetryCodes()) {
+		actual, _ := notifier.retrier.Check(statusCode, nil)
+		require.Equal(t, expected, actual, fmt.Sprintf("error on status %d", statusCode))
+	}
+}
+
+func TestGrafanaNotify(t *testing.T) {
+	type request struct {
+		method, path, query string
+		body                annotation
+	}
+	var (
+		reqs []request
+		// found are the annotations returned by the search.
+		found []annotation
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		req := request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery}
+		if r.Method == http.MethodGet {
+			require.NoError(t, json.NewEncoder(w).Encode(found))
+		} else {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req.body))
+		}
+		reqs = append(reqs, req)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL + "/grafana/")
+	require.NoError(t, err)
+	notifier, err := New(
+		&config.GrafanaConfig{
+			APIURL:       &config.URL{URL: u},
+			Token:        config.Secret("token"),
+			DashboardUID: "dash",
+			Text:         `{{ .Status }}`,
+			Tags:         []string{"alertmanager", `{{ .CommonLabels.team }}`},
+			HTTPConfig:   &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	key := notify.Key("1")
+	ctx := notify.WithGroupKey(context.Background(), string(key))
+	start := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	alerts := func(endsAt time.Time) []*types.Alert {
+		return []*types.Alert{
+			{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}, StartsAt: start.Add(time.Minute), EndsAt: endsAt}},
+			{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b"}, StartsAt: start, EndsAt: endsAt.Add(-time.Minute)}},
+		}
+	}
+	query := "tags=group_id%3A" + key.Hash() + "&type=annotation"
+
+	// A firing group without an open annotation adds one at the start of
+	// its earliest alert.
+	_, err = notifier.Notify(ctx, alerts(time.Now().Add(time.Hour))...)
+	require.NoError(t, err)
+	require.Equal(t, []request{
+		{method: http.MethodGet, path: "/grafana/api/annotations", query: query},
+		{method: http.MethodPost, path: "/grafana/api/annotations", body: annotation{
+			DashboardUID: "dash",
+			Time:         start.UnixNano() / int64(time.Millisecond),
+			Tags:         []string{"alertmanager", "group_id:" + key.Hash()},
+			Text:         "firing",
+		}},
+	}, reqs)
+
+	// The open annotation is ended with the latest alert of the resolved
+	// group, and a closed one is left alone.
+	reqs = nil
+	found = []annotation{
+		{ID: 3, Time: 1000, TimeEnd: 2000},
+		{ID: 4, Time: 5000, TimeEnd: 5000},
+	}
+	_, err = notifier.Notify(ctx, alerts(time.Now().Add(time.Hour))...)
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	end := start.Add(2 * time.Hour)
+	_, err = notifier.Notify(ctx, alerts(end)...)
+	require.NoError(t, err)
+	require.Len(t, reqs, 3)
+	require.Equal(t, request{method: http.MethodPatch, path: "/grafana/api/annotations/4", body: annotation{
+		TimeEnd: end.UnixNano() / int64(time.Millisecond),
+		Tags:    []string{"alertmanager", "group_id:" + key.Hash()},
+		Text:    "resolved",
+	}}, reqs[2])
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// The health states of integrations and receivers.
+const (
+	// HealthUnknown is the state of the integrations which didn't attempt
+	// to send a notification yet.
+	HealthUnknown = "unknown"
+	// HealthHealthy is the state of the integrations whose last attempt
+	// succeeded.
+	HealthHealthy = "healthy"
+	// HealthFailing is the state of the integrations whose last attempt
+	// failed.
+	HealthFailing = "failing"
+)
+
+// IntegrationHealth summarizes the notification attempts of an integration.
+type IntegrationHealth struct {
+	Integration string
+	Index       int
+
+	LastSuccess time.Time
+	LastFailure time.Time
+	LastError   string
+	// ConsecutiveFailures is the number of attempts which failed since the
+	// last successful one.
+	ConsecutiveFailures int
+	// LastThrottled is the last time the receiver answered that the
+	// integration sent too many requests.
+	LastThrottled time.Time
+}
+
+// State returns the health state of the integration.
+func (h IntegrationHealth) State() string {
+	switch {
+	case h.ConsecutiveFailures > 0:
+		return HealthFailing
+	case h.LastSuccess.IsZero():
+		return HealthUnknown
+	}
+	return HealthHealthy
+}
+
+type healthKey struct {
+	receiver    string
+	integration string
+	index       int
+}
+
+// Health tracks the health of the integrations of the receivers from their
+// notification attempts. It is an AttemptRecorder.
+type Health struct {
+	mtx          sync.RWMutex
+	integrations map[healthKey]*IntegrationHealth
+}
+
+// NewHealth returns a new Health.
+func NewHealth() *Health {
+	return &Health{integrations: map[healthKey]*IntegrationHealth{}}
+}
+
+// RecordAttempt implements the AttemptRecorder interface.
+func (h *Health) RecordAttempt(a Attempt) {
+	k := healthKey{receiver: a.Receiver, integration: a.Integration, index: a.Index}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	ih, ok := h.integrations[k]
+	if !ok {
+		ih = &IntegrationHealth{Integration: a.Integration, Index: a.Index}
+		h.integrations[k] = ih
+	}
+	end := a.Time.Add(a.Duration)
+	if a.Outcome == AttemptSucceeded {
+		ih.LastSuccess = end
+		ih.ConsecutiveFailures = 0
+		return
+	}
+	ih.LastFailure, ih.LastError = end, a.Error
+	ih.ConsecutiveFailures++
+	if a.StatusCode == http.StatusTooManyRequests {
+		ih.LastThrottled = end
+	}
+}
+
+// Receiver returns the health of the integrations of the receiver which
+// attempted to send notifications, sorted by integration and index.
+func (h *Health) Receiver(name string) []IntegrationHealth {
+	h.mtx.RLock()
+	var res []IntegrationHealth
+	for k, ih := range h.integrations {
+		if k.receiver == name {
+			res = append(res, *ih)
+		}
+	}
+	h.mtx.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Integration != res[j].Integration {
+			return res[i].Integration < res[j].Integration
+		}
+		return res[i].Index < res[j].Index
+	})
+	return res
+}
+
+// AttemptRecorders records the notification attempts with each of the
+// recorders.
+type AttemptRecorders []AttemptRecorder
+
+// RecordAttempt implements the AttemptRecorder interface.
+func (rs AttemptRecorders) RecordAttempt(a Attempt) {
+	for _, r := range rs {
+		r.RecordAttempt(a)
+	}
+}
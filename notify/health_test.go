@@ -0,0 +1,82 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	h := NewHealth()
+	require.Empty(t, h.Receiver("team"))
+	require.Equal(t, HealthUnknown, IntegrationHealth{}.State())
+
+	start := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	attempt := func(integration string, index int, outcome string, statusCode int, err string) {
+		start = start.Add(time.Minute)
+		h.RecordAttempt(Attempt{
+			Time:        start,
+			Receiver:    "team",
+			Integration: integration,
+			Index:       index,
+			Outcome:     outcome,
+			Duration:    time.Second,
+			StatusCode:  statusCode,
+			Error:       err,
+		})
+	}
+
+	attempt("webhook", 1, AttemptSucceeded, 0, "")
+	attempt("webhook", 0, AttemptFailed, http.StatusTooManyRequests, "unexpected status code 429")
+	attempt("webhook", 0, AttemptAborted, http.StatusBadRequest, "unexpected status code 400")
+	attempt("email", 0, AttemptSucceeded, 0, "")
+	h.RecordAttempt(Attempt{Time: start, Receiver: "ops", Integration: "email", Outcome: AttemptFailed})
+
+	at := func(minutes int) time.Time {
+		return time.Date(2021, 7, 1, 12, minutes, 1, 0, time.UTC)
+	}
+	res := h.Receiver("team")
+	require.Equal(t, []IntegrationHealth{
+		{Integration: "email", Index: 0, LastSuccess: at(4)},
+		{
+			Integration:         "webhook",
+			Index:               0,
+			LastFailure:         at(3),
+			LastError:           "unexpected status code 400",
+			ConsecutiveFailures: 2,
+			LastThrottled:       at(2),
+		},
+		{Integration: "webhook", Index: 1, LastSuccess: at(1)},
+	}, res)
+	require.Equal(t, HealthHealthy, res[0].State())
+	require.Equal(t, HealthFailing, res[1].State())
+
+	// A successful attempt resets the failures.
+	attempt("webhook", 0, AttemptSucceeded, 0, "")
+	res = h.Receiver("team")
+	require.Equal(t, 0, res[1].ConsecutiveFailures)
+	require.Equal(t, "unexpected status code 400", res[1].LastError)
+	require.Equal(t, HealthHealthy, res[1].State())
+}
+
+func TestAttemptRecorders(t *testing.T) {
+	h1, h2 := NewHealth(), NewHealth()
+	AttemptRecorders{h1, h2}.RecordAttempt(Attempt{Receiver: "team", Integration: "email", Outcome: AttemptFailed})
+	require.Len(t, h1.Receiver("team"), 1)
+	require.Len(t, h2.Receiver("team"), 1)
+}
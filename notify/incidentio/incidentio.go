@@ -0,0 +1,127 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package incidentio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// maxDescriptionLen is the maximum length of the description of alert
+// events.
+const maxDescriptionLen = 10000
+
+// Notifier implements a Notifier for incident.io alert events.
+type Notifier struct {
+	conf    *config.IncidentIOConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+}
+
+// New returns a new incident.io notifier.
+func New(c *config.IncidentIOConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "incidentio", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:   c,
+		tmpl:   t,
+		logger: l,
+		client: client,
+		// Rate limited requests, answered with status code 429, are
+		// retried by CheckResponse.
+		retrier: &notify.Retrier{},
+	}, nil
+}
+
+// alertEvent is the payload of the alert events API. Events with the same
+// deduplication key update the same alert of incident.io.
+type alertEvent struct {
+	Title            string            `json:"title"`
+	Description      string            `json:"description,omitempty"`
+	DeduplicationKey string            `json:"deduplication_key"`
+	Status           string            `json:"status"`
+	SourceURL        string            `json:"source_url,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		data  = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl  = notify.TmplText(n.tmpl, data, &err)
+		event = alertEvent{
+			Title:            tmpl(n.conf.Title),
+			Description:      tmpl(n.conf.Description),
+			DeduplicationKey: key.Hash(),
+			Status:           string(types.Alerts(as...).Status()),
+			SourceURL:        tmpl(n.conf.SourceURL),
+		}
+	)
+	if len(n.conf.Metadata) > 0 {
+		event.Metadata = make(map[string]string, len(n.conf.Metadata))
+		for k, v := range n.conf.Metadata {
+			event.Metadata[k] = tmpl(v)
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("templating error: %s", err)
+	}
+
+	description, truncated := notify.TruncateMessage(event.Description, maxDescriptionLen, data)
+	if truncated {
+		level.Debug(n.logger).Log("msg", "Truncated description", "key", key)
+		event.Description = description
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(event); err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest("POST", n.conf.URL.String(), &buf)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+string(n.conf.Token))
+
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	return n.retrier.CheckResponse(resp, resp.Body)
+}
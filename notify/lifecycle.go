@@ -0,0 +1,60 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+
+	"github.com/prometheus/alertmanager/lifecycle"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// LifecycleStage records the notified events of the alerts an integration
+// successfully notified about.
+type LifecycleStage struct {
+	events      lifecycle.Recorder
+	receiver    string
+	integration Integration
+}
+
+// NewLifecycleStage returns a new instance of a LifecycleStage.
+func NewLifecycleStage(events lifecycle.Recorder, receiver string, integration Integration) *LifecycleStage {
+	return &LifecycleStage{
+		events:      events,
+		receiver:    receiver,
+		integration: integration,
+	}
+}
+
+// Exec implements the Stage interface. The resolved alerts are only notified
+// about if the integration sends resolved notifications.
+func (s *LifecycleStage) Exec(ctx context.Context, _ log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	if !s.events.Enabled(lifecycle.EventNotified) {
+		return ctx, alerts, nil
+	}
+	gkey, _ := GroupKey(ctx)
+	for _, a := range alerts {
+		if a.Resolved() && !s.integration.SendResolved() {
+			continue
+		}
+		e := lifecycle.NewEvent(lifecycle.EventNotified, a)
+		e.GroupKey = gkey
+		e.Receiver = s.receiver
+		e.Integration = s.integration.String()
+		s.events.Record(e)
+	}
+	return ctx, alerts, nil
+}
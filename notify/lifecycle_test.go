@@ -0,0 +1,68 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/lifecycle"
+	"github.com/prometheus/alertmanager/types"
+)
+
+type lifecycleRecorder []lifecycle.Event
+
+func (r *lifecycleRecorder) Enabled(lifecycle.EventType) bool { return true }
+func (r *lifecycleRecorder) Record(e lifecycle.Event)         { *r = append(*r, e) }
+
+func TestLifecycleStage(t *testing.T) {
+	firing := &types.Alert{Alert: model.Alert{
+		Labels: model.LabelSet{"alertname": "firing"},
+		EndsAt: time.Now().Add(time.Hour),
+	}}
+	resolved := &types.Alert{Alert: model.Alert{
+		Labels: model.LabelSet{"alertname": "resolved"},
+		EndsAt: time.Now().Add(-time.Hour),
+	}}
+	ctx := WithGroupKey(context.Background(), "{}:{}")
+
+	for _, tc := range []struct {
+		sendResolved bool
+		notified     []*types.Alert
+	}{
+		{sendResolved: false, notified: []*types.Alert{firing}},
+		{sendResolved: true, notified: []*types.Alert{firing, resolved}},
+	} {
+		events := &lifecycleRecorder{}
+		i := NewIntegration(notifierFunc(nil), sendResolved(tc.sendResolved), "webhook", 1)
+		s := NewLifecycleStage(events, "team-X", i)
+
+		_, res, err := s.Exec(ctx, log.NewNopLogger(), firing, resolved)
+		require.NoError(t, err)
+		require.Equal(t, []*types.Alert{firing, resolved}, res)
+		require.Len(t, *events, len(tc.notified))
+		for j, e := range *events {
+			require.Equal(t, lifecycle.EventNotified, e.Type)
+			require.Equal(t, tc.notified[j].Labels, e.Labels)
+			require.Equal(t, "{}:{}", e.GroupKey)
+			require.Equal(t, "team-X", e.Receiver)
+			require.Equal(t, "webhook[1]", e.Integration)
+		}
+	}
+}
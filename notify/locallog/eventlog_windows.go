@@ -0,0 +1,52 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package locallog
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/svc/eventlog"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// maxMessageLen is the maximum length of the messages of events.
+const maxMessageLen = 31839
+
+// eventLog writes the entries to the Windows Event Log, as events of the
+// identifier as source. The source should be registered, or the events are
+// shown with a warning about their missing description.
+type eventLog struct {
+	source  string
+	eventID uint32
+}
+
+func newSink(c *config.LocalLogConfig) sink {
+	return &eventLog{source: c.Identifier, eventID: c.EventID}
+}
+
+func (l *eventLog) write(e *entry) error {
+	w, err := eventlog.Open(l.source)
+	if err != nil {
+		return errors.Wrap(err, "open the event log")
+	}
+	defer w.Close()
+	switch e.level {
+	case "critical", "error":
+		err = w.Error(l.eventID, e.message)
+	case "warning":
+		err = w.Warning(l.eventID, e.message)
+	default:
+		err = w.Info(l.eventID, e.message)
+	}
+	return errors.Wrap(err, "write to the event log")
+}
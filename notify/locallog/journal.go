@@ -0,0 +1,89 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build !windows
+// +build !windows
+
+package locallog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// journalSocket is the socket of the native protocol of the systemd journal.
+const journalSocket = "/run/systemd/journal/socket"
+
+// maxMessageLen keeps the entries, in characters of up to 4 bytes, within a
+// single datagram.
+const maxMessageLen = 16 << 10
+
+// journalPriorities maps the levels to syslog priorities.
+var journalPriorities = map[string]int{
+	"critical": 2,
+	"error":    3,
+	"warning":  4,
+	"info":     6,
+}
+
+// journal writes the entries to the systemd journal.
+type journal struct {
+	socket     string
+	identifier string
+}
+
+func newSink(c *config.LocalLogConfig) sink {
+	return &journal{socket: journalSocket, identifier: c.Identifier}
+}
+
+func (j *journal) write(e *entry) error {
+	var b bytes.Buffer
+	writeJournalField(&b, "MESSAGE", e.message)
+	writeJournalField(&b, "PRIORITY", strconv.Itoa(journalPriorities[e.level]))
+	writeJournalField(&b, "SYSLOG_IDENTIFIER", j.identifier)
+	writeJournalField(&b, "ALERTMANAGER_STATUS", e.status)
+	writeJournalField(&b, "ALERTMANAGER_GROUP_KEY", e.groupKey)
+
+	conn, err := net.Dial("unixgram", j.socket)
+	if err != nil {
+		return errors.Wrap(err, "connect to the systemd journal")
+	}
+	defer conn.Close()
+	if _, err := conn.Write(b.Bytes()); err != nil {
+		return errors.Wrap(err, "write to the systemd journal")
+	}
+	return nil
+}
+
+// writeJournalField writes a field in the native protocol of the journal.
+// Values with newlines are prefixed with their length instead of being
+// terminated by a newline.
+func writeJournalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
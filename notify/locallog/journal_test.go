@@ -0,0 +1,122 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build !windows
+// +build !windows
+
+package locallog
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	conf := config.DefaultLocalLogConfig
+	conf.SeverityMappings = &config.SeverityMappings{
+		Label:    "severity",
+		Mappings: []*config.SeverityMapping{{Severity: "page", LocalLog: "critical"}},
+	}
+	n := New(&conf, test.CreateTmpl(t), log.NewNopLogger())
+	n.sink.(*journal).socket = socket
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	notifyAlert := func(labels model.LabelSet, endsAt time.Time) map[string]string {
+		t.Helper()
+		retry, err := n.Notify(ctx, &types.Alert{
+			Alert: model.Alert{
+				Labels:   labels,
+				StartsAt: time.Now().Add(-time.Hour),
+				EndsAt:   endsAt,
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, retry)
+
+		b := make([]byte, 1<<16)
+		k, err := conn.Read(b)
+		require.NoError(t, err)
+		return parseJournalFields(t, b[:k])
+	}
+
+	fields := notifyAlert(model.LabelSet{"alertname": "DiskFull"}, time.Time{})
+	require.Equal(t, "4", fields["PRIORITY"])
+	require.Equal(t, "alertmanager", fields["SYSLOG_IDENTIFIER"])
+	require.Equal(t, "firing", fields["ALERTMANAGER_STATUS"])
+	require.Equal(t, "1", fields["ALERTMANAGER_GROUP_KEY"])
+	require.Contains(t, fields["MESSAGE"], "Alerts Firing:\nLabels:\n - alertname = DiskFull\n")
+
+	fields = notifyAlert(model.LabelSet{"alertname": "DiskFull", "severity": "page"}, time.Time{})
+	require.Equal(t, "2", fields["PRIORITY"])
+
+	fields = notifyAlert(model.LabelSet{"alertname": "DiskFull", "severity": "page"}, time.Now().Add(-time.Minute))
+	require.Equal(t, "6", fields["PRIORITY"])
+	require.Equal(t, "resolved", fields["ALERTMANAGER_STATUS"])
+}
+
+func TestJournalUnavailable(t *testing.T) {
+	n := New(&config.DefaultLocalLogConfig, test.CreateTmpl(t), log.NewNopLogger())
+	n.sink.(*journal).socket = filepath.Join(os.TempDir(), "does-not-exist")
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	retry, err := n.Notify(ctx, &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "DiskFull"}}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "connect to the systemd journal")
+	require.True(t, retry)
+}
+
+// parseJournalFields parses an entry in the native protocol of the journal.
+func parseJournalFields(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+	fields := map[string]string{}
+	for len(b) > 0 {
+		i := bytes.IndexAny(b, "=\n")
+		require.True(t, i > 0, "invalid field in %q", b)
+		name := string(b[:i])
+		if b[i] == '=' {
+			j := bytes.IndexByte(b, '\n')
+			fields[name] = string(b[i+1 : j])
+			b = b[j+1:]
+			continue
+		}
+		b = b[i+1:]
+		n := binary.LittleEndian.Uint64(b)
+		fields[name] = string(b[8 : 8+n])
+		require.Equal(t, byte('\n'), b[8+n])
+		b = b[9+n:]
+	}
+	return fields
+}
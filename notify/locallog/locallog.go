@@ -0,0 +1,106 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package locallog writes notifications to the log of the host, which is
+// scraped by host-level monitoring: the systemd journal, or the Event Log on
+// Windows.
+package locallog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// entry is a notification written to the log of the host.
+type entry struct {
+	level    string
+	message  string
+	status   string
+	groupKey string
+}
+
+// sink writes the entries to the log of the host.
+type sink interface {
+	write(e *entry) error
+}
+
+// Notifier implements a Notifier writing to the log of the host.
+type Notifier struct {
+	conf   *config.LocalLogConfig
+	tmpl   *template.Template
+	logger log.Logger
+	sink   sink
+}
+
+// New returns a new local log notifier.
+func New(c *config.LocalLogConfig, t *template.Template, l log.Logger) *Notifier {
+	return &Notifier{conf: c, tmpl: t, logger: l, sink: newSink(c)}
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	data := notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+
+	var tmplErr error
+	message := strings.TrimSpace(notify.TmplText(n.tmpl, data, &tmplErr)(n.conf.Message))
+	if tmplErr != nil {
+		return false, errors.Wrap(tmplErr, "failed to template message")
+	}
+	message, truncated := notify.TruncateMessage(message, maxMessageLen, data)
+	if truncated {
+		level.Debug(n.logger).Log("msg", "Truncated message", "message", message, "incident", key)
+	}
+
+	e := &entry{
+		level:    n.level(as),
+		message:  message,
+		status:   data.Status,
+		groupKey: key.String(),
+	}
+	if record, ok := notify.DryRun(ctx); ok {
+		record("text/plain", []byte(e.message))
+		return false, nil
+	}
+	level.Debug(n.logger).Log("msg", "writing notification to the local log", "incident", key, "level", e.level)
+	if err := n.sink.write(e); err != nil {
+		// The logging service of the host may be restarting.
+		return true, err
+	}
+	return false, nil
+}
+
+// level returns the level of the notification: the mapped one of the most
+// severe firing alert, or else the configured one. Resolved notifications
+// are informational.
+func (n *Notifier) level(as []*types.Alert) string {
+	if types.Alerts(as...).Status() == model.AlertResolved {
+		return "info"
+	}
+	if m := notify.LookupSeverity(n.conf.SeverityMappings, as...); m != nil && m.LocalLog != "" {
+		return m.LocalLog
+	}
+	return n.conf.Level
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/alertmanager/types"
+)
+
+// NotifierFunc is an adapter to use functions as Notifiers.
+type NotifierFunc func(ctx context.Context, alerts ...*types.Alert) (bool, error)
+
+// Notify implements the Notifier interface.
+func (f NotifierFunc) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	return f(ctx, alerts...)
+}
+
+// Middleware returns a notifier sending the notifications of an integration,
+// given by its name, through next. It can observe the notifications, change
+// their alerts or context, or veto them by returning without calling next:
+// vetoed notifications are considered sent unless an error is returned.
+//
+// Middlewares enforce site-specific policies without changing the notifiers.
+// They are compiled in the Alertmanager, registered by the init functions of
+// their packages, and enabled by the global notification_middlewares setting.
+type Middleware func(integration string, next Notifier) Notifier
+
+var (
+	middlewaresMtx sync.RWMutex
+	middlewares    = map[string]Middleware{}
+)
+
+// RegisterMiddleware makes a middleware available under the given name. It
+// panics if the name is already registered.
+func RegisterMiddleware(name string, m Middleware) {
+	middlewaresMtx.Lock()
+	defer middlewaresMtx.Unlock()
+
+	if _, ok := middlewares[name]; ok {
+		panic(fmt.Sprintf("notify: middleware %q registered twice", name))
+	}
+	middlewares[name] = m
+}
+
+// LookupMiddlewares returns the registered middlewares of the given names.
+func LookupMiddlewares(names []string) ([]Middleware, error) {
+	middlewaresMtx.RLock()
+	defer middlewaresMtx.RUnlock()
+
+	ms := make([]Middleware, 0, len(names))
+	for _, name := range names {
+		m, ok := middlewares[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown notification middleware %q", name)
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+// WithMiddlewares returns the notifier of the integration wrapped in the
+// middlewares, the first one being called first.
+func WithMiddlewares(n Notifier, integration string, ms []Middleware) Notifier {
+	for i := len(ms) - 1; i >= 0; i-- {
+		n = ms[i](integration, n)
+	}
+	return n
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestMiddlewares(t *testing.T) {
+	var calls []string
+	RegisterMiddleware("test-observe", func(integration string, next Notifier) Notifier {
+		return NotifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			calls = append(calls, "observe:"+integration)
+			return next.Notify(ctx, alerts...)
+		})
+	})
+	RegisterMiddleware("test-veto", func(integration string, next Notifier) Notifier {
+		return NotifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			calls = append(calls, "veto")
+			var kept []*types.Alert
+			for _, a := range alerts {
+				if a.Labels["secret"] == "" {
+					kept = append(kept, a)
+				}
+			}
+			if len(kept) == 0 {
+				return false, nil
+			}
+			return next.Notify(ctx, kept...)
+		})
+	})
+	require.Panics(t, func() { RegisterMiddleware("test-veto", nil) })
+
+	_, err := LookupMiddlewares([]string{"test-observe", "unknown"})
+	require.EqualError(t, err, `unknown notification middleware "unknown"`)
+
+	ms, err := LookupMiddlewares([]string{"test-observe", "test-veto"})
+	require.NoError(t, err)
+
+	var sent []*types.Alert
+	n := WithMiddlewares(NotifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		calls = append(calls, "send")
+		sent = alerts
+		return false, nil
+	}), "webhook", ms)
+
+	public := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}}
+	secret := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b", "secret": "true"}}}
+
+	_, err = n.Notify(context.Background(), public, secret)
+	require.NoError(t, err)
+	require.Equal(t, []string{"observe:webhook", "veto", "send"}, calls)
+	require.Equal(t, []*types.Alert{public}, sent)
+
+	// The notification is vetoed.
+	calls = nil
+	_, err = n.Notify(context.Background(), secret)
+	require.NoError(t, err)
+	require.Equal(t, []string{"observe:webhook", "veto"}, calls)
+
+	// No middleware leaves the notifier as is.
+	require.IsType(t, NotifierFunc(nil), WithMiddlewares(NotifierFunc(nil), "webhook", nil))
+}
@@ -0,0 +1,168 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notifiers builds the integrations notifying the receivers of a
+// configuration. It is used by the Alertmanager binary and can be used by
+// projects embedding the notification pipeline.
+package notifiers
+
+import (
+	"github.com/go-kit/log"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/elasticsearch"
+	"github.com/prometheus/alertmanager/notify/email"
+	"github.com/prometheus/alertmanager/notify/github"
+	"github.com/prometheus/alertmanager/notify/gotify"
+	"github.com/prometheus/alertmanager/notify/grafana"
+	"github.com/prometheus/alertmanager/notify/incidentio"
+	"github.com/prometheus/alertmanager/notify/locallog"
+	"github.com/prometheus/alertmanager/notify/nsca"
+	"github.com/prometheus/alertmanager/notify/ntfy"
+	"github.com/prometheus/alertmanager/notify/opsgenie"
+	"github.com/prometheus/alertmanager/notify/pagerduty"
+	"github.com/prometheus/alertmanager/notify/plugin"
+	"github.com/prometheus/alertmanager/notify/pushbullet"
+	"github.com/prometheus/alertmanager/notify/pushover"
+	"github.com/prometheus/alertmanager/notify/slack"
+	"github.com/prometheus/alertmanager/notify/sns"
+	"github.com/prometheus/alertmanager/notify/splunkhec"
+	"github.com/prometheus/alertmanager/notify/statuspage"
+	"github.com/prometheus/alertmanager/notify/stdout"
+	"github.com/prometheus/alertmanager/notify/victorops"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/alertmanager/notify/wechat"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Options customizes the integrations built for a receiver.
+type Options struct {
+	// TLSPolicy restricts the TLS connections of the integrations.
+	TLSPolicy *config.TLSPolicy
+	// Middlewares wrap the notifiers of the integrations.
+	Middlewares []notify.Middleware
+	// DryRun logs the notifications instead of sending them.
+	DryRun bool
+	// Logger is the parent of the loggers of the integrations. It defaults
+	// to a no-op logger.
+	Logger log.Logger
+	// Transport wraps the round tripper of the HTTP clients of the
+	// integrations, e.g. to instrument or stub their requests.
+	Transport notify.TransportWrapper
+}
+
+// Build returns the integrations notifying the receiver. The errors of all
+// the integrations which could not be built are returned.
+func Build(rcv *config.Receiver, tmpl *template.Template, o Options) ([]notify.Integration, error) {
+	logger := o.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	var (
+		errs         types.MultiError
+		integrations []notify.Integration
+		httpOpts     = notify.HTTPClientOptions(rcv.HTTPTransport, o.TLSPolicy)
+		add          = func(name string, i int, rs notify.ResolvedSender, f func(l log.Logger) (notify.Notifier, error)) {
+			l := log.With(logger, "integration", name)
+			n, err := f(l)
+			if err != nil {
+				errs.Add(err)
+				return
+			}
+			n = notify.WithMiddlewares(n, name, o.Middlewares)
+			n = notify.NewRequestOptionsNotifier(n, rcv.HTTPTransport)
+			n = notify.NewTransportNotifier(n, o.Transport)
+			if n, err = notify.NewTimeFormatNotifier(n, rcv); err != nil {
+				errs.Add(err)
+				return
+			}
+			if o.DryRun || rcv.DryRun {
+				n = notify.NewDryRunNotifier(n, l)
+			}
+			integrations = append(integrations, notify.NewIntegration(n, rs, name, i))
+		}
+	)
+
+	for i, c := range rcv.WebhookConfigs {
+		add("webhook", i, c, func(l log.Logger) (notify.Notifier, error) { return webhook.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.EmailConfigs {
+		add("email", i, c, func(l log.Logger) (notify.Notifier, error) { return email.New(c, tmpl, l, o.TLSPolicy), nil })
+	}
+	for i, c := range rcv.PagerdutyConfigs {
+		add("pagerduty", i, c, func(l log.Logger) (notify.Notifier, error) { return pagerduty.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.OpsGenieConfigs {
+		add("opsgenie", i, c, func(l log.Logger) (notify.Notifier, error) { return opsgenie.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.WechatConfigs {
+		add("wechat", i, c, func(l log.Logger) (notify.Notifier, error) { return wechat.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.SlackConfigs {
+		add("slack", i, c, func(l log.Logger) (notify.Notifier, error) { return slack.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.VictorOpsConfigs {
+		add("victorops", i, c, func(l log.Logger) (notify.Notifier, error) { return victorops.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.PushoverConfigs {
+		add("pushover", i, c, func(l log.Logger) (notify.Notifier, error) { return pushover.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.SNSConfigs {
+		add("sns", i, c, func(l log.Logger) (notify.Notifier, error) { return sns.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.IncidentIOConfigs {
+		add("incidentio", i, c, func(l log.Logger) (notify.Notifier, error) { return incidentio.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.StatuspageConfigs {
+		add("statuspage", i, c, func(l log.Logger) (notify.Notifier, error) { return statuspage.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.GithubConfigs {
+		add("github", i, c, func(l log.Logger) (notify.Notifier, error) { return github.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.GrafanaConfigs {
+		add("grafana", i, c, func(l log.Logger) (notify.Notifier, error) { return grafana.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.PushbulletConfigs {
+		add("pushbullet", i, c, func(l log.Logger) (notify.Notifier, error) { return pushbullet.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.GotifyConfigs {
+		add("gotify", i, c, func(l log.Logger) (notify.Notifier, error) { return gotify.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.NtfyConfigs {
+		add("ntfy", i, c, func(l log.Logger) (notify.Notifier, error) { return ntfy.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.NSCAConfigs {
+		add("nsca", i, c, func(l log.Logger) (notify.Notifier, error) { return nsca.New(c, tmpl, l), nil })
+	}
+	for i, c := range rcv.SplunkHECConfigs {
+		add("splunk_hec", i, c, func(l log.Logger) (notify.Notifier, error) { return splunkhec.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.ElasticsearchConfigs {
+		add("elasticsearch", i, c, func(l log.Logger) (notify.Notifier, error) { return elasticsearch.New(c, tmpl, l, httpOpts...) })
+	}
+	for i, c := range rcv.PluginConfigs {
+		add("plugin", i, c, func(l log.Logger) (notify.Notifier, error) { return plugin.New(c, tmpl, l) })
+	}
+	for i, c := range rcv.LocalLogConfigs {
+		add("local_log", i, c, func(l log.Logger) (notify.Notifier, error) { return locallog.New(c, tmpl, l), nil })
+	}
+	for i, c := range rcv.StdoutConfigs {
+		add("stdout", i, c, func(l log.Logger) (notify.Notifier, error) { return stdout.New(c, tmpl, l), nil })
+	}
+	if errs.Len() > 0 {
+		return nil, &errs
+	}
+	return integrations, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifiers
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+type sendResolved bool
+
+func (s sendResolved) SendResolved() bool { return bool(s) }
+
+func TestBuild(t *testing.T) {
+	for _, tc := range []struct {
+		receiver *config.Receiver
+		err      bool
+		exp      []notify.Integration
+	}{
+		{
+			receiver: &config.Receiver{
+				Name: "foo",
+				WebhookConfigs: []*config.WebhookConfig{
+					&config.WebhookConfig{
+						HTTPConfig: &commoncfg.HTTPClientConfig{},
+					},
+					&config.WebhookConfig{
+						HTTPConfig: &commoncfg.HTTPClientConfig{},
+						NotifierConfig: config.NotifierConfig{
+							VSendResolved: true,
+						},
+					},
+				},
+			},
+			exp: []notify.Integration{
+				notify.NewIntegration(nil, sendResolved(false), "webhook", 0),
+				notify.NewIntegration(nil, sendResolved(true), "webhook", 1),
+			},
+		},
+		{
+			receiver: &config.Receiver{
+				Name: "foo",
+				WebhookConfigs: []*config.WebhookConfig{
+					&config.WebhookConfig{
+						HTTPConfig: &commoncfg.HTTPClientConfig{
+							TLSConfig: commoncfg.TLSConfig{
+								CAFile: "not_existing",
+							},
+						},
+					},
+				},
+			},
+			err: true,
+		},
+	} {
+		tc := tc
+		t.Run("", func(t *testing.T) {
+			integrations, err := Build(tc.receiver, nil, Options{})
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, integrations, len(tc.exp))
+			for i := range tc.exp {
+				require.Equal(t, tc.exp[i].SendResolved(), integrations[i].SendResolved())
+				require.Equal(t, tc.exp[i].Name(), integrations[i].Name())
+				require.Equal(t, tc.exp[i].Index(), integrations[i].Index())
+			}
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestBuildTransport(t *testing.T) {
+	u, err := url.Parse("http://example.invalid/hook")
+	require.NoError(t, err)
+	rcv := &config.Receiver{
+		Name: "foo",
+		WebhookConfigs: []*config.WebhookConfig{
+			{
+				HTTPConfig: &commoncfg.HTTPClientConfig{},
+				URL:        &config.URL{URL: u},
+			},
+		},
+	}
+
+	var (
+		wrapped  http.RoundTripper
+		requests []*http.Request
+	)
+	integrations, err := Build(rcv, test.CreateTmpl(t), Options{
+		Transport: func(rt http.RoundTripper) http.RoundTripper {
+			wrapped = rt
+			return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+					Request:    req,
+				}, nil
+			})
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, integrations, 1)
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "foo"},
+			StartsAt: time.Now(),
+			EndsAt:   time.Now().Add(time.Hour),
+		},
+	}
+	retry, err := integrations[0].Notify(ctx, alert)
+	require.NoError(t, err)
+	require.False(t, retry)
+
+	// The stub received the request instead of the network, and wrapped the
+	// round tripper of the configured client.
+	require.Len(t, requests, 1)
+	require.Equal(t, u.String(), requests[0].URL.String())
+	require.NotNil(t, wrapped)
+}
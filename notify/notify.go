@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -28,11 +29,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/deadletter"
 	"github.com/prometheus/alertmanager/inhibit"
+	"github.com/prometheus/alertmanager/lifecycle"
 	"github.com/prometheus/alertmanager/nflog"
 	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/pkg/relabel"
 	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/spool"
+	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/alertmanager/tracing"
 	"github.com/prometheus/alertmanager/types"
 )
 
@@ -87,6 +95,15 @@ func (i *Integration) SendResolved() bool {
 	return i.rs.SendResolved()
 }
 
+// MaxAlertsPerMessage returns the maximum number of alerts the integration
+// sends in a single message. 0 means no limit.
+func (i *Integration) MaxAlertsPerMessage() uint64 {
+	if ms, ok := i.rs.(interface{ MaxAlertsPerMessage() uint64 }); ok {
+		return ms.MaxAlertsPerMessage()
+	}
+	return 0
+}
+
 // Name returns the name of the integration.
 func (i *Integration) Name() string {
 	return i.name
@@ -115,8 +132,20 @@ const (
 	keyResolvedAlerts
 	keyNow
 	keyMuteTimeIntervals
+	keyMessagePart
+	keyAcknowledged
+	keyLabelRelabelConfigs
+	keyAnnotationRelabelConfigs
+	keyEnrichment
+	keyDigest
+	keyOwner
+	keyOnCall
 )
 
+type messagePart struct {
+	part, parts int
+}
+
 // WithReceiverName populates a context with a receiver name.
 func WithReceiverName(ctx context.Context, rcv string) context.Context {
 	return context.WithValue(ctx, keyReceiverName, rcv)
@@ -157,6 +186,53 @@ func WithMuteTimeIntervals(ctx context.Context, mt []string) context.Context {
 	return context.WithValue(ctx, keyMuteTimeIntervals, mt)
 }
 
+// WithMessagePart populates a context with the number of the message part
+// being sent, starting at 1, and the total number of parts.
+func WithMessagePart(ctx context.Context, part, parts int) context.Context {
+	return context.WithValue(ctx, keyMessagePart, messagePart{part: part, parts: parts})
+}
+
+// WithAcknowledged populates a context with whether the alert group was
+// acknowledged.
+func WithAcknowledged(ctx context.Context, acked bool) context.Context {
+	return context.WithValue(ctx, keyAcknowledged, acked)
+}
+
+// WithLabelRelabelConfigs populates a context with the relabeling rules
+// applied to the labels of notified alerts.
+func WithLabelRelabelConfigs(ctx context.Context, cfgs []*relabel.Config) context.Context {
+	return context.WithValue(ctx, keyLabelRelabelConfigs, cfgs)
+}
+
+// WithAnnotationRelabelConfigs populates a context with the relabeling rules
+// applied to the annotations of notified alerts.
+func WithAnnotationRelabelConfigs(ctx context.Context, cfgs []*relabel.Config) context.Context {
+	return context.WithValue(ctx, keyAnnotationRelabelConfigs, cfgs)
+}
+
+// WithEnrichment populates a context with the enrichment configuration of
+// the route.
+func WithEnrichment(ctx context.Context, conf *config.EnrichmentConfig) context.Context {
+	return context.WithValue(ctx, keyEnrichment, conf)
+}
+
+// WithOwner populates a context with the owner of the route.
+func WithOwner(ctx context.Context, owner *config.OwnerConfig) context.Context {
+	return context.WithValue(ctx, keyOwner, owner)
+}
+
+// WithOnCall populates a context with the people currently on call for the
+// owner of the route.
+func WithOnCall(ctx context.Context, oncall []template.OnCall) context.Context {
+	return context.WithValue(ctx, keyOnCall, oncall)
+}
+
+// WithDigest populates a context with whether the alerts are notified in a
+// scheduled digest.
+func WithDigest(ctx context.Context, digest bool) context.Context {
+	return context.WithValue(ctx, keyDigest, digest)
+}
+
 // RepeatInterval extracts a repeat interval from the context. Iff none exists, the
 // second argument is false.
 func RepeatInterval(ctx context.Context) (time.Duration, bool) {
@@ -206,6 +282,62 @@ func ResolvedAlerts(ctx context.Context) ([]uint64, bool) {
 	return v, ok
 }
 
+// MessagePart extracts the message part and the total number of parts from the
+// context. Iff none exists, the third argument is false.
+func MessagePart(ctx context.Context) (int, int, bool) {
+	v, ok := ctx.Value(keyMessagePart).(messagePart)
+	return v.part, v.parts, ok
+}
+
+// Acknowledged extracts whether the alert group was acknowledged from the
+// context. Iff none exists, the second argument is false.
+func Acknowledged(ctx context.Context) (bool, bool) {
+	v, ok := ctx.Value(keyAcknowledged).(bool)
+	return v, ok
+}
+
+// LabelRelabelConfigs extracts the relabeling rules for labels from the
+// context. Iff none exists, the second argument is false.
+func LabelRelabelConfigs(ctx context.Context) ([]*relabel.Config, bool) {
+	v, ok := ctx.Value(keyLabelRelabelConfigs).([]*relabel.Config)
+	return v, ok
+}
+
+// AnnotationRelabelConfigs extracts the relabeling rules for annotations from
+// the context. Iff none exists, the second argument is false.
+func AnnotationRelabelConfigs(ctx context.Context) ([]*relabel.Config, bool) {
+	v, ok := ctx.Value(keyAnnotationRelabelConfigs).([]*relabel.Config)
+	return v, ok
+}
+
+// Enrichment extracts the enrichment configuration from the context. Iff none
+// exists, the second argument is false.
+func Enrichment(ctx context.Context) (*config.EnrichmentConfig, bool) {
+	v, ok := ctx.Value(keyEnrichment).(*config.EnrichmentConfig)
+	return v, ok
+}
+
+// Owner extracts the owner of the route from the context. Iff none exists,
+// the second argument is false.
+func Owner(ctx context.Context) (*config.OwnerConfig, bool) {
+	v, ok := ctx.Value(keyOwner).(*config.OwnerConfig)
+	return v, ok
+}
+
+// OnCall extracts the people on call for the owner of the route from the
+// context. Iff none exists, the second argument is false.
+func OnCall(ctx context.Context) ([]template.OnCall, bool) {
+	v, ok := ctx.Value(keyOnCall).([]template.OnCall)
+	return v, ok
+}
+
+// Digest extracts whether the alerts are notified in a scheduled digest from
+// the context. Iff none exists, the second argument is false.
+func Digest(ctx context.Context) (bool, bool) {
+	v, ok := ctx.Value(keyDigest).(bool)
+	return v, ok
+}
+
 // MuteTimeIntervalNames extracts a slice of mute time names from the context. Iff none exists, the
 // second argument is false.
 func MuteTimeIntervalNames(ctx context.Context) ([]string, bool) {
@@ -231,98 +363,262 @@ type NotificationLog interface {
 	Query(params ...nflog.QueryParam) ([]*nflogpb.Entry, error)
 }
 
+// Spool durably stores outbound notifications until they were delivered or
+// given up on.
+type Spool interface {
+	Put(e *spool.Entry) (string, error)
+	Remove(id string) error
+}
+
+// DeadLetterQueue stores notifications that could not be delivered after all
+// retries were exhausted.
+type DeadLetterQueue interface {
+	Add(e *deadletter.Entry) (string, error)
+}
+
 type Metrics struct {
 	numNotifications                   *prometheus.CounterVec
 	numTotalFailedNotifications        *prometheus.CounterVec
 	numNotificationRequestsTotal       *prometheus.CounterVec
 	numNotificationRequestsFailedTotal *prometheus.CounterVec
+	numNotificationRetriesTotal        *prometheus.CounterVec
+	numNotificationsThrottledTotal     *prometheus.CounterVec
 	notificationLatencySeconds         *prometheus.HistogramVec
+	notificationDurationSeconds        *prometheus.HistogramVec
+	numNotificationsSuppressedTotal    *prometheus.CounterVec
+	numEnrichmentRequestsTotal         prometheus.Counter
+	numEnrichmentRequestsFailedTotal   prometheus.Counter
+	numOnCallRequestsTotal             *prometheus.CounterVec
+	numOnCallRequestsFailedTotal       *prometheus.CounterVec
 }
 
+// The reasons for which alerts are removed from notifications.
+const (
+	SuppressedReasonInhibited  = "inhibited"
+	SuppressedReasonSuppressed = "suppressed"
+	SuppressedReasonMuted      = "muted"
+	SuppressedReasonSilenced   = "silenced"
+)
+
 func NewMetrics(r prometheus.Registerer) *Metrics {
 	m := &Metrics{
 		numNotifications: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: "alertmanager",
 			Name:      "notifications_total",
 			Help:      "The total number of attempted notifications.",
-		}, []string{"integration"}),
+		}, []string{"integration", "receiver"}),
 		numTotalFailedNotifications: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: "alertmanager",
 			Name:      "notifications_failed_total",
 			Help:      "The total number of failed notifications.",
-		}, []string{"integration"}),
+		}, []string{"integration", "receiver"}),
 		numNotificationRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: "alertmanager",
 			Name:      "notification_requests_total",
 			Help:      "The total number of attempted notification requests.",
-		}, []string{"integration"}),
+		}, []string{"integration", "receiver"}),
 		numNotificationRequestsFailedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: "alertmanager",
 			Name:      "notification_requests_failed_total",
-			Help:      "The total number of failed notification requests.",
-		}, []string{"integration"}),
+			Help:      "The total number of failed notification requests, by HTTP status code or 'other' if the request got no response.",
+		}, []string{"integration", "receiver", "code"}),
+		numNotificationRetriesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      "notification_retries_total",
+			Help:      "The total number of notification attempts which were retries of failed ones.",
+		}, []string{"integration", "receiver"}),
+		numNotificationsThrottledTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      "notifications_throttled_total",
+			Help:      "The total number of failed notification attempts for which the receiver asked to wait before retrying.",
+		}, []string{"integration", "receiver"}),
 		notificationLatencySeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: "alertmanager",
 			Name:      "notification_latency_seconds",
 			Help:      "The latency of notifications in seconds.",
 			Buckets:   []float64{1, 5, 10, 15, 20},
-		}, []string{"integration"}),
-	}
-	for _, integration := range []string{
-		"email",
-		"pagerduty",
-		"wechat",
-		"pushover",
-		"slack",
-		"opsgenie",
-		"webhook",
-		"victorops",
-		"sns",
+		}, []string{"integration", "receiver"}),
+		notificationDurationSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "alertmanager",
+			Name:      "notification_duration_seconds",
+			Help:      "The time from the first attempt of a notification until it was delivered or given up on, including retries.",
+			Buckets:   []float64{1, 5, 10, 30, 60, 120, 300},
+		}, []string{"integration", "receiver"}),
+		numNotificationsSuppressedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      "notifications_suppressed_total",
+			Help:      "The total number of alerts removed from notifications, by reason.",
+		}, []string{"reason"}),
+		numEnrichmentRequestsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      "enrichment_requests_total",
+			Help:      "The total number of requests to enrichment endpoints.",
+		}),
+		numEnrichmentRequestsFailedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      "enrichment_requests_failed_total",
+			Help:      "The total number of failed requests to enrichment endpoints.",
+		}),
+		numOnCallRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      "oncall_requests_total",
+			Help:      "The total number of requests resolving the on-call of schedules.",
+		}, []string{"provider"}),
+		numOnCallRequestsFailedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      "oncall_requests_failed_total",
+			Help:      "The total number of failed requests resolving the on-call of schedules.",
+		}, []string{"provider"}),
+	}
+	for _, provider := range config.OnCallProviders {
+		m.numOnCallRequestsTotal.WithLabelValues(provider)
+		m.numOnCallRequestsFailedTotal.WithLabelValues(provider)
+	}
+	for _, reason := range []string{
+		SuppressedReasonInhibited,
+		SuppressedReasonSuppressed,
+		SuppressedReasonMuted,
+		SuppressedReasonSilenced,
 	} {
-		m.numNotifications.WithLabelValues(integration)
-		m.numTotalFailedNotifications.WithLabelValues(integration)
-		m.numNotificationRequestsTotal.WithLabelValues(integration)
-		m.numNotificationRequestsFailedTotal.WithLabelValues(integration)
-		m.notificationLatencySeconds.WithLabelValues(integration)
+		m.numNotificationsSuppressedTotal.WithLabelValues(reason)
 	}
 	r.MustRegister(
 		m.numNotifications, m.numTotalFailedNotifications,
 		m.numNotificationRequestsTotal, m.numNotificationRequestsFailedTotal,
-		m.notificationLatencySeconds,
+		m.numNotificationRetriesTotal, m.numNotificationsThrottledTotal,
+		m.notificationLatencySeconds, m.notificationDurationSeconds,
+		m.numNotificationsSuppressedTotal,
+		m.numEnrichmentRequestsTotal, m.numEnrichmentRequestsFailedTotal,
+		m.numOnCallRequestsTotal, m.numOnCallRequestsFailedTotal,
 	)
 	return m
 }
 
+// initIntegration initializes the metrics of the integration of a receiver so
+// that they are exported before the first notification.
+func (m *Metrics) initIntegration(integration, receiver string) {
+	m.numNotifications.WithLabelValues(integration, receiver)
+	m.numTotalFailedNotifications.WithLabelValues(integration, receiver)
+	m.numNotificationRequestsTotal.WithLabelValues(integration, receiver)
+	m.numNotificationRetriesTotal.WithLabelValues(integration, receiver)
+	m.numNotificationsThrottledTotal.WithLabelValues(integration, receiver)
+	m.notificationLatencySeconds.WithLabelValues(integration, receiver)
+	m.notificationDurationSeconds.WithLabelValues(integration, receiver)
+}
+
+// countSuppressed returns a stage executing the given stage and counting the
+// alerts it removed for the given reason.
+func (m *Metrics) countSuppressed(s Stage, reason string) Stage {
+	return StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		ctx, res, err := s.Exec(ctx, l, alerts...)
+		if err == nil && len(res) < len(alerts) {
+			m.numNotificationsSuppressedTotal.WithLabelValues(reason).Add(float64(len(alerts) - len(res)))
+		}
+		return ctx, res, err
+	})
+}
+
+// statusCode returns the HTTP status code of a failed notification request,
+// or "other" if the request got no response.
+func statusCode(err error) string {
+	var serr *StatusCodeError
+	if errors.As(err, &serr) {
+		return strconv.Itoa(serr.StatusCode)
+	}
+	return "other"
+}
+
 type PipelineBuilder struct {
 	metrics *Metrics
+	now     func() time.Time
+}
+
+// PipelineOptions customizes the pipelines built by a PipelineBuilder.
+type PipelineOptions struct {
+	// Now returns the current time to the deduplication, enrichment and
+	// on-call stages. It defaults to time.Now.
+	Now func() time.Time
 }
 
 func NewPipelineBuilder(r prometheus.Registerer) *PipelineBuilder {
+	return NewPipelineBuilderWithOptions(r, PipelineOptions{})
+}
+
+// NewPipelineBuilderWithOptions returns a PipelineBuilder customized by the
+// options, e.g. to stub the clock of the pipelines in tests.
+func NewPipelineBuilderWithOptions(r prometheus.Registerer, o PipelineOptions) *PipelineBuilder {
+	if o.Now == nil {
+		o.Now = time.Now
+	}
 	return &PipelineBuilder{
 		metrics: NewMetrics(r),
+		now:     o.Now,
 	}
 }
 
+// PipelineComponents holds what the pipelines built by a PipelineBuilder
+// are made of. The stages of the components which are nil are left out of
+// the pipelines.
+type PipelineComponents struct {
+	// Wait returns how long to wait before sending notifications, so that
+	// peers don't send duplicates. Notifications are sent without waiting
+	// if it is nil.
+	Wait func() time.Duration
+	// IsLeader tells whether the local peer is the leader. If it isn't
+	// nil, only the leader sends notifications and Wait is ignored.
+	IsLeader   func() bool
+	Inhibitor  *inhibit.Inhibitor
+	Silencer   *silence.Silencer
+	MuteTimes  map[string][]timeinterval.TimeInterval
+	Suppressor *SuppressStage
+	QuietHours *QuietHours
+	RouteDedup *RouteDedup
+	Budgets    *Budgets
+	Scrubber   *ScrubStage
+	Events     lifecycle.Recorder
+	Attempts   AttemptRecorder
+	// NotificationLog records the notifications to deduplicate them.
+	NotificationLog NotificationLog
+	DeadLetters     DeadLetterQueue
+	Spool           Spool
+	// Peer is the cluster peer, which must be a nil interface value if
+	// the cluster is disabled.
+	Peer Peer
+}
+
 // New returns a map of receivers to Stages.
-func (pb *PipelineBuilder) New(
-	receivers map[string][]Integration,
-	wait func() time.Duration,
-	inhibitor *inhibit.Inhibitor,
-	silencer *silence.Silencer,
-	muteTimes map[string][]timeinterval.TimeInterval,
-	notificationLog NotificationLog,
-	peer Peer,
-) RoutingStage {
+func (pb *PipelineBuilder) New(receivers map[string][]Integration, c PipelineComponents) RoutingStage {
 	rs := make(RoutingStage, len(receivers))
-
-	ms := NewGossipSettleStage(peer)
-	is := NewMuteStage(inhibitor)
-	ss := NewMuteStage(silencer)
-	tms := NewTimeMuteStage(muteTimes)
+	es := NewEnrichStage(pb.metrics)
+	es.now = pb.now
+	ocs := NewOnCallStage(pb.metrics)
+	ocs.now = pb.now
+
+	ms := NewGossipSettleStage(c.Peer)
+	is := withSpan(pb.metrics.countSuppressed(NewMuteStage(c.Inhibitor), SuppressedReasonInhibited), "notify.inhibit")
+	ss := withSpan(pb.metrics.countSuppressed(NewMuteStage(c.Silencer), SuppressedReasonSilenced), "notify.silence")
+	tms := withSpan(pb.metrics.countSuppressed(NewTimeMuteStage(c.MuteTimes), SuppressedReasonMuted), "notify.time_mute")
+
+	var ls *LeaderStage
+	if c.IsLeader != nil {
+		ls = NewLeaderStage(c.IsLeader)
+		c.Wait = nil
+	}
+	if c.Wait == nil {
+		c.Wait = func() time.Duration { return 0 }
+	}
 
 	for name := range receivers {
-		st := createReceiverStage(name, receivers[name], wait, notificationLog, pb.metrics)
-		rs[name] = MultiStage{ms, is, tms, ss, st}
+		st := createReceiverStage(name, receivers[name], c, es, ocs, pb.now, pb.metrics)
+		stages := MultiStage{ms}
+		if ls != nil {
+			stages = append(stages, ls)
+		}
+		stages = append(stages, is)
+		if c.Suppressor != nil {
+			stages = append(stages, withSpan(pb.metrics.countSuppressed(c.Suppressor, SuppressedReasonSuppressed), "notify.suppress"))
+		}
+		rs[name] = append(stages, tms, ss, st)
 	}
 	return rs
 }
@@ -331,28 +627,83 @@ func (pb *PipelineBuilder) New(
 func createReceiverStage(
 	name string,
 	integrations []Integration,
-	wait func() time.Duration,
-	notificationLog NotificationLog,
+	c PipelineComponents,
+	es *EnrichStage,
+	ocs *OnCallStage,
+	now func() time.Time,
 	metrics *Metrics,
 ) Stage {
 	var fs FanoutStage
 	for i := range integrations {
+		metrics.initIntegration(integrations[i].Name(), name)
 		recv := &nflogpb.Receiver{
 			GroupName:   name,
 			Integration: integrations[i].Name(),
 			Idx:         uint32(integrations[i].Index()),
 		}
 		var s MultiStage
-		s = append(s, NewWaitStage(wait))
-		s = append(s, NewDedupStage(&integrations[i], notificationLog, recv))
-		s = append(s, NewRetryStage(integrations[i], name, metrics))
-		s = append(s, NewSetNotifiesStage(notificationLog, recv))
+		s = append(s, NewWaitStage(c.Wait))
+		ds := NewDedupStage(&integrations[i], c.NotificationLog, recv)
+		ds.now = func() time.Time { return now().UTC() }
+		s = append(s, ds)
+		s = append(s, NewRelabelStage())
+		s = append(s, es, ocs)
+		if c.Scrubber != nil {
+			s = append(s, c.Scrubber)
+		}
+
+		send := MultiStage{
+			NewRetryStage(integrations[i], name, c.DeadLetters, c.Attempts, metrics),
+			NewSetNotifiesStage(c.NotificationLog, recv),
+		}
+		if c.Events != nil {
+			send = append(send, NewLifecycleStage(c.Events, name, integrations[i]))
+		}
+		var deliver Stage = send
+		if c.Spool != nil {
+			deliver = NewSpoolStage(c.Spool, recv, deliver)
+		}
+		if c.Budgets != nil {
+			deliver = c.Budgets.Stage(name, integrations[i], deliver, NewSetNotifiesStage(c.NotificationLog, recv))
+		}
+		if c.QuietHours != nil {
+			deliver = c.QuietHours.Stage(name, integrations[i], deliver, NewSetNotifiesStage(c.NotificationLog, recv))
+		}
+		if c.RouteDedup != nil {
+			deliver = c.RouteDedup.Stage(name, integrations[i], deliver, NewSetNotifiesStage(c.NotificationLog, recv))
+		}
+		s = append(s, deliver)
 
-		fs = append(fs, s)
+		st := withSpan(s, "notify.integration", "receiver", name, "integration", integrations[i].String())
+		fs = append(fs, withLogger(st, "integration", integrations[i].String()))
 	}
 	return fs
 }
 
+// withLogger returns a stage executing the given stage with the key-value
+// pairs added to its logger.
+func withLogger(s Stage, keyvals ...interface{}) Stage {
+	return StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		return s.Exec(ctx, log.With(l, keyvals...), alerts...)
+	})
+}
+
+// withSpan returns a stage executing the given stage within a span with the
+// key-value pairs as attributes. The stages executed after it don't become
+// children of the span.
+func withSpan(s Stage, name string, keyvals ...interface{}) Stage {
+	return StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		sctx, span := tracing.Start(ctx, name, tracing.SpanKindInternal, keyvals...)
+		defer span.End()
+		span.SetAttributes("alerts", len(alerts))
+
+		rctx, res, err := s.Exec(sctx, l, alerts...)
+		span.SetAttributes("alerts.passed", len(res))
+		span.RecordError(err)
+		return tracing.WithSpanOf(rctx, ctx), res, err
+	})
+}
+
 // RoutingStage executes the inner stages based on the receiver specified in
 // the context.
 type RoutingStage map[string]Stage
@@ -369,7 +720,7 @@ func (rs RoutingStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.
 		return ctx, nil, errors.New("stage for receiver missing")
 	}
 
-	return s.Exec(ctx, l, alerts...)
+	return s.Exec(ctx, log.With(l, "receiver", receiver), alerts...)
 }
 
 // A MultiStage executes a series of stages sequentially.
@@ -438,6 +789,26 @@ func (n *GossipSettleStage) Exec(ctx context.Context, _ log.Logger, alerts ...*t
 	return ctx, alerts, nil
 }
 
+// LeaderStage drops all alerts unless the instance is the leader of its
+// cluster, so that followers don't send notifications.
+type LeaderStage struct {
+	isLeader func() bool
+}
+
+// NewLeaderStage returns a new LeaderStage.
+func NewLeaderStage(isLeader func() bool) *LeaderStage {
+	return &LeaderStage{isLeader: isLeader}
+}
+
+// Exec implements the Stage interface.
+func (n *LeaderStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	if !n.isLeader() {
+		level.Debug(l).Log("msg", "Not the cluster leader, not sending notifications")
+		return ctx, nil, nil
+	}
+	return ctx, alerts, nil
+}
+
 // MuteStage filters alerts through a Muter.
 type MuteStage struct {
 	muter types.Muter
@@ -552,7 +923,7 @@ func hashAlert(a *types.Alert) uint64 {
 	return hash
 }
 
-func (n *DedupStage) needsUpdate(entry *nflogpb.Entry, firing, resolved map[uint64]struct{}, repeat time.Duration) bool {
+func (n *DedupStage) needsUpdate(entry *nflogpb.Entry, firing, resolved map[uint64]struct{}, repeat time.Duration, acked bool) bool {
 	// If we haven't notified about the alert group before, notify right away
 	// unless we only have resolved alerts.
 	if entry == nil {
@@ -578,8 +949,9 @@ func (n *DedupStage) needsUpdate(entry *nflogpb.Entry, firing, resolved map[uint
 		return true
 	}
 
-	// Nothing changed, only notify if the repeat interval has passed.
-	return entry.Timestamp.Before(n.now().Add(-repeat))
+	// Nothing changed, only notify if the repeat interval has passed and
+	// nobody acknowledged the alert group.
+	return !acked && entry.Timestamp.Before(n.now().Add(-repeat))
 }
 
 // Exec implements the Stage interface.
@@ -628,38 +1000,194 @@ func (n *DedupStage) Exec(ctx context.Context, _ log.Logger, alerts ...*types.Al
 		return ctx, nil, errors.Errorf("unexpected entry result size %d", len(entries))
 	}
 
-	if n.needsUpdate(entry, firingSet, resolvedSet, repeatInterval) {
+	acked, _ := Acknowledged(ctx)
+
+	if n.needsUpdate(entry, firingSet, resolvedSet, repeatInterval, acked) {
 		return ctx, alerts, nil
 	}
 	return ctx, nil, nil
 }
 
+// The outcomes of notification attempts.
+const (
+	AttemptSucceeded = "succeeded"
+	// AttemptFailed is the outcome of failed attempts which are retried.
+	AttemptFailed = "failed"
+	// AttemptAborted is the outcome of attempts which failed with an
+	// unrecoverable error.
+	AttemptAborted = "aborted"
+)
+
+// Attempt is a request of an integration to send a notification, or a part
+// of it.
+type Attempt struct {
+	Time        time.Time
+	Receiver    string
+	Integration string
+	Index       int
+	GroupKey    string
+	GroupLabels model.LabelSet
+	// Number is the number of the attempt, starting at 1.
+	Number   int
+	Outcome  string
+	Duration time.Duration
+	// Firing and Resolved are the numbers of alerts sent.
+	Firing   int
+	Resolved int
+	// Fingerprints are the fingerprints of the alerts sent.
+	Fingerprints []model.Fingerprint
+	// StatusCode and Error describe the failed attempts. StatusCode is empty
+	// unless the receiver answered.
+	StatusCode int
+	Error      string
+}
+
+// AttemptRecorder records the notification attempts.
+type AttemptRecorder interface {
+	// RecordAttempt records the attempt. It must not block.
+	RecordAttempt(Attempt)
+}
+
 // RetryStage notifies via passed integration with exponential backoff until it
-// succeeds. It aborts if the context is canceled or timed out.
+// succeeds. It aborts if the context is canceled or timed out. If a dead-letter
+// queue is set, notifications that failed for good are added to it.
 type RetryStage struct {
 	integration Integration
 	groupName   string
+	deadLetters DeadLetterQueue
+	attempts    AttemptRecorder
 	metrics     *Metrics
 }
 
-// NewRetryStage returns a new instance of a RetryStage.
-func NewRetryStage(i Integration, groupName string, metrics *Metrics) *RetryStage {
+// NewRetryStage returns a new instance of a RetryStage. The dead-letter queue
+// and the attempt recorder may be nil.
+func NewRetryStage(i Integration, groupName string, deadLetters DeadLetterQueue, attempts AttemptRecorder, metrics *Metrics) *RetryStage {
 	return &RetryStage{
 		integration: i,
 		groupName:   groupName,
+		deadLetters: deadLetters,
+		attempts:    attempts,
 		metrics:     metrics,
 	}
 }
 
 func (r RetryStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
-	r.metrics.numNotifications.WithLabelValues(r.integration.Name()).Inc()
+	r.metrics.numNotifications.WithLabelValues(r.integration.Name(), r.groupName).Inc()
+	origAlerts := alerts
+	start := time.Now()
 	ctx, alerts, err := r.exec(ctx, l, alerts...)
+	r.metrics.notificationDurationSeconds.WithLabelValues(r.integration.Name(), r.groupName).Observe(time.Since(start).Seconds())
 	if err != nil {
-		r.metrics.numTotalFailedNotifications.WithLabelValues(r.integration.Name()).Inc()
+		r.metrics.numTotalFailedNotifications.WithLabelValues(r.integration.Name(), r.groupName).Inc()
+		r.deadLetter(ctx, l, err, origAlerts)
 	}
 	return ctx, alerts, err
 }
 
+// recordAttempt records a notification attempt started at the given time.
+func (r RetryStage) recordAttempt(ctx context.Context, number int, start time.Time, retry bool, err error, alerts []*types.Alert) {
+	if r.attempts == nil {
+		return
+	}
+	a := Attempt{
+		Time:        start,
+		Receiver:    r.groupName,
+		Integration: r.integration.Name(),
+		Index:       r.integration.Index(),
+		Number:      number,
+		Outcome:     AttemptSucceeded,
+		Duration:    time.Since(start),
+	}
+	a.GroupKey, _ = GroupKey(ctx)
+	a.GroupLabels, _ = GroupLabels(ctx)
+	a.Fingerprints = make([]model.Fingerprint, 0, len(alerts))
+	for _, alert := range alerts {
+		a.Fingerprints = append(a.Fingerprints, alert.Fingerprint())
+		if alert.Resolved() {
+			a.Resolved++
+		} else {
+			a.Firing++
+		}
+	}
+	if err != nil {
+		a.Outcome, a.Error = AttemptFailed, err.Error()
+		if !retry {
+			a.Outcome = AttemptAborted
+		}
+		var serr *StatusCodeError
+		if errors.As(err, &serr) {
+			a.StatusCode = serr.StatusCode
+		}
+	}
+	r.attempts.RecordAttempt(a)
+}
+
+// deadLetter adds the failed notification to the dead-letter queue. Nothing is
+// recorded if the notification was canceled rather than timed out, as this
+// only happens on shutdown or reload.
+func (r RetryStage) deadLetter(ctx context.Context, l log.Logger, err error, alerts []*types.Alert) {
+	if r.deadLetters == nil || errors.Is(ctx.Err(), context.Canceled) {
+		return
+	}
+	groupKey, _ := GroupKey(ctx)
+	groupLabels, _ := GroupLabels(ctx)
+	id, derr := r.deadLetters.Add(&deadletter.Entry{
+		Receiver:    r.groupName,
+		Integration: r.integration.Name(),
+		Idx:         uint32(r.integration.Index()),
+		GroupKey:    groupKey,
+		GroupLabels: groupLabels,
+		Alerts:      alerts,
+		Error:       err.Error(),
+	})
+	if derr != nil {
+		level.Error(l).Log("msg", "Failed to add notification to dead-letter queue", "err", derr)
+		return
+	}
+	level.Info(l).Log("msg", "Notification added to dead-letter queue", "id", id)
+}
+
+// Replay sends a dead-lettered notification once through the matching
+// integration out of the given ones. It does not retry and does not update the
+// notification log.
+func Replay(ctx context.Context, integrations []Integration, e *deadletter.Entry) error {
+	for _, i := range integrations {
+		if i.Name() != e.Integration || uint32(i.Index()) != e.Idx {
+			continue
+		}
+		ctx = WithReceiverName(ctx, e.Receiver)
+		ctx = WithGroupKey(ctx, e.GroupKey)
+		ctx = WithGroupLabels(ctx, e.GroupLabels)
+		ctx = WithNow(ctx, time.Now())
+
+		_, err := i.Notify(ctx, e.Alerts...)
+		return err
+	}
+	return fmt.Errorf("integration %s[%d] not found in receiver %q", e.Integration, e.Idx, e.Receiver)
+}
+
+// TestResult is the outcome of sending a test notification through an
+// integration.
+type TestResult struct {
+	Integration string
+	Index       int
+	Err         error
+}
+
+// Test sends the alerts once through each of the given integrations of a
+// receiver, as a single group whose labels are the ones they have in common.
+// It does not retry and does not update the notification log.
+func Test(ctx context.Context, receiver string, integrations []Integration, alerts ...*types.Alert) []TestResult {
+	ctx = adHocGroupContext(ctx, receiver, alerts)
+
+	results := make([]TestResult, 0, len(integrations))
+	for _, i := range integrations {
+		_, err := i.Notify(ctx, alerts...)
+		results = append(results, TestResult{Integration: i.Name(), Index: i.Index(), Err: err})
+	}
+	return results
+}
+
 func (r RetryStage) exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
 	var sent []*types.Alert
 
@@ -683,17 +1211,23 @@ func (r RetryStage) exec(ctx context.Context, l log.Logger, alerts ...*types.Ale
 		sent = alerts
 	}
 
+	// Split the alerts into several messages if the integration limits the
+	// number of alerts per message. Parts already sent are not sent again
+	// when retrying.
+	parts := splitAlerts(sent, r.integration.MaxAlertsPerMessage())
+
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 0 // Always retry.
 
-	tick := backoff.NewTicker(b)
-	defer tick.Stop()
+	// The first attempt is immediate.
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
 	var (
 		i    = 0
+		next = 0
 		iErr error
 	)
-	l = log.With(l, "receiver", r.groupName, "integration", r.integration.String())
 
 	for {
 		i++
@@ -709,13 +1243,33 @@ func (r RetryStage) exec(ctx context.Context, l log.Logger, alerts ...*types.Ale
 		}
 
 		select {
-		case <-tick.C:
-			now := time.Now()
-			retry, err := r.integration.Notify(ctx, sent...)
-			r.metrics.notificationLatencySeconds.WithLabelValues(r.integration.Name()).Observe(time.Since(now).Seconds())
-			r.metrics.numNotificationRequestsTotal.WithLabelValues(r.integration.Name()).Inc()
+		case <-timer.C:
+			var (
+				retry bool
+				err   error
+			)
+			if i > 1 {
+				r.metrics.numNotificationRetriesTotal.WithLabelValues(r.integration.Name(), r.groupName).Inc()
+			}
+			for ; next < len(parts); next++ {
+				pctx := ctx
+				if len(parts) > 1 {
+					pctx = WithMessagePart(ctx, next+1, len(parts))
+				}
+				now := time.Now()
+				sctx, span := tracing.Start(pctx, "notify.send", tracing.SpanKindClient, "attempt", i, "alerts", len(parts[next]))
+				retry, err = r.integration.Notify(sctx, parts[next]...)
+				span.RecordError(err)
+				span.End()
+				r.metrics.notificationLatencySeconds.WithLabelValues(r.integration.Name(), r.groupName).Observe(time.Since(now).Seconds())
+				r.metrics.numNotificationRequestsTotal.WithLabelValues(r.integration.Name(), r.groupName).Inc()
+				r.recordAttempt(ctx, i, now, retry, err, parts[next])
+				if err != nil {
+					break
+				}
+			}
 			if err != nil {
-				r.metrics.numNotificationRequestsFailedTotal.WithLabelValues(r.integration.Name()).Inc()
+				r.metrics.numNotificationRequestsFailedTotal.WithLabelValues(r.integration.Name(), r.groupName, statusCode(err)).Inc()
 				if !retry {
 					return ctx, alerts, errors.Wrapf(err, "%s/%s: notify retry canceled due to unrecoverable error after %d attempts", r.groupName, r.integration.String(), i)
 				}
@@ -727,6 +1281,18 @@ func (r RetryStage) exec(ctx context.Context, l log.Logger, alerts ...*types.Ale
 				// Save this error to be able to return the last seen error by an
 				// integration upon context timeout.
 				iErr = err
+
+				// Wait for as long as a throttling receiver asks for if
+				// longer than the backoff.
+				delay := b.NextBackOff()
+				var serr *StatusCodeError
+				if errors.As(err, &serr) && serr.RetryAfter > 0 {
+					r.metrics.numNotificationsThrottledTotal.WithLabelValues(r.integration.Name(), r.groupName).Inc()
+					if serr.RetryAfter > delay {
+						delay = serr.RetryAfter
+					}
+				}
+				timer.Reset(delay)
 			} else {
 				lvl := level.Debug(l)
 				if i > 1 {
@@ -745,6 +1311,151 @@ func (r RetryStage) exec(ctx context.Context, l log.Logger, alerts ...*types.Ale
 	}
 }
 
+// splitAlerts splits the alerts into chunks of at most max alerts. A max of 0
+// means no limit. At least one chunk is always returned.
+func splitAlerts(alerts []*types.Alert, max uint64) [][]*types.Alert {
+	if max == 0 || uint64(len(alerts)) <= max {
+		return [][]*types.Alert{alerts}
+	}
+	var parts [][]*types.Alert
+	for uint64(len(alerts)) > max {
+		parts = append(parts, alerts[:max])
+		alerts = alerts[max:]
+	}
+	if len(alerts) > 0 {
+		parts = append(parts, alerts)
+	}
+	return parts
+}
+
+// SpoolStage writes the notification to a spool before passing it on to the
+// delivery stages, and removes it again once it was delivered or given up on.
+// Notifications interrupted by a shutdown stay in the spool to be replayed on
+// the next start.
+type SpoolStage struct {
+	spool Spool
+	recv  *nflogpb.Receiver
+	next  Stage
+}
+
+// NewSpoolStage returns a new instance of a SpoolStage.
+func NewSpoolStage(sp Spool, recv *nflogpb.Receiver, next Stage) *SpoolStage {
+	return &SpoolStage{
+		spool: sp,
+		recv:  recv,
+		next:  next,
+	}
+}
+
+// Exec implements the Stage interface.
+func (n SpoolStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	groupKey, _ := GroupKey(ctx)
+	groupLabels, _ := GroupLabels(ctx)
+	firing, _ := FiringAlerts(ctx)
+	resolved, _ := ResolvedAlerts(ctx)
+
+	id, err := n.spool.Put(&spool.Entry{
+		Receiver:       n.recv.GroupName,
+		Integration:    n.recv.Integration,
+		Idx:            n.recv.Idx,
+		GroupKey:       groupKey,
+		GroupLabels:    groupLabels,
+		Alerts:         alerts,
+		FiringAlerts:   firing,
+		ResolvedAlerts: resolved,
+	})
+	if err != nil {
+		// Not being able to spool must not prevent the notification.
+		level.Error(l).Log("msg", "Failed to spool notification", "err", err)
+		return n.next.Exec(ctx, l, alerts...)
+	}
+
+	ctx, res, err := n.next.Exec(ctx, l, alerts...)
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		return ctx, res, err
+	}
+	if rerr := n.spool.Remove(id); rerr != nil {
+		level.Error(l).Log("msg", "Failed to remove notification from spool", "id", id, "err", rerr)
+	}
+	return ctx, res, err
+}
+
+// ReplaySpool sends the spooled notifications in the order they were spooled
+// through the matching integrations out of the given receivers. A notification
+// is skipped if the notification log shows that the group was notified after
+// it was spooled. Each notification is attempted once with a timeout of
+// MinTimeout. Failed ones are added
+// to the dead-letter queue, if set. Notifications are removed from the spool
+// afterwards, unless the context is canceled.
+func ReplaySpool(
+	ctx context.Context,
+	entries []*spool.Entry,
+	sp Spool,
+	receivers map[string][]Integration,
+	notificationLog NotificationLog,
+	deadLetters DeadLetterQueue,
+	l log.Logger,
+) {
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		el := log.With(l, "receiver", e.Receiver, "integration", fmt.Sprintf("%s[%d]", e.Integration, e.Idx), "id", e.ID)
+		dl := &deadletter.Entry{
+			Receiver:    e.Receiver,
+			Integration: e.Integration,
+			Idx:         e.Idx,
+			GroupKey:    e.GroupKey,
+			GroupLabels: e.GroupLabels,
+			Alerts:      e.Alerts,
+		}
+
+		ectx, cancel := context.WithTimeout(ctx, MinTimeout)
+		sent, err := replaySpoolEntry(ectx, e, dl, receivers, notificationLog)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case err != nil:
+			level.Error(el).Log("msg", "Failed to replay spooled notification", "err", err)
+			if deadLetters != nil {
+				dl.Error = err.Error()
+				if _, err := deadLetters.Add(dl); err != nil {
+					level.Error(el).Log("msg", "Failed to add notification to dead-letter queue", "err", err)
+				}
+			}
+		case sent:
+			level.Info(el).Log("msg", "Replayed spooled notification")
+		default:
+			level.Debug(el).Log("msg", "Skipped spooled notification already sent")
+		}
+		if err := sp.Remove(e.ID); err != nil {
+			level.Error(el).Log("msg", "Failed to remove notification from spool", "err", err)
+		}
+	}
+}
+
+func replaySpoolEntry(ctx context.Context, e *spool.Entry, dl *deadletter.Entry, receivers map[string][]Integration, notificationLog NotificationLog) (bool, error) {
+	recv := &nflogpb.Receiver{
+		GroupName:   e.Receiver,
+		Integration: e.Integration,
+		Idx:         e.Idx,
+	}
+	entries, err := notificationLog.Query(nflog.QGroupKey(e.GroupKey), nflog.QReceiver(recv))
+	if err != nil && err != nflog.ErrNotFound {
+		return false, err
+	}
+	if len(entries) == 1 && !entries[0].Timestamp.Before(e.CreatedAt) {
+		return false, nil
+	}
+
+	if err := Replay(ctx, receivers[e.Receiver], dl); err != nil {
+		return false, err
+	}
+	return true, notificationLog.Log(recv, e.GroupKey, e.FiringAlerts, e.ResolvedAlerts)
+}
+
 // SetNotifiesStage sets the notification information about passed alerts. The
 // passed alerts should have already been sent to the receivers.
 type SetNotifiesStage struct {
@@ -821,3 +1532,44 @@ Loop:
 	}
 	return ctx, alerts, nil
 }
+
+// RelabelStage rewrites the labels and annotations of the alerts with the
+// relabeling rules of the route they were routed by. The alerts are copied,
+// so that the stored alerts are left unchanged.
+type RelabelStage struct{}
+
+// NewRelabelStage returns a new RelabelStage.
+func NewRelabelStage() *RelabelStage {
+	return &RelabelStage{}
+}
+
+// Exec implements the Stage interface. Alerts dropped by the relabeling rules
+// are not notified.
+func (n RelabelStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	labelCfgs, _ := LabelRelabelConfigs(ctx)
+	annotationCfgs, _ := AnnotationRelabelConfigs(ctx)
+	if len(labelCfgs) == 0 && len(annotationCfgs) == 0 {
+		return ctx, alerts, nil
+	}
+
+	res := make([]*types.Alert, 0, len(alerts))
+	for _, a := range alerts {
+		lset := relabel.Process(a.Labels, labelCfgs...)
+		annotations := relabel.Process(a.Annotations, annotationCfgs...)
+		if lset == nil || annotations == nil {
+			level.Debug(l).Log("msg", "Alert dropped by relabeling", "alert", a)
+			continue
+		}
+		na := *a
+		na.Labels = lset
+		na.Annotations = annotations
+		res = append(res, &na)
+	}
+
+	if gl, ok := GroupLabels(ctx); ok && len(labelCfgs) > 0 {
+		if lset := relabel.Process(gl, labelCfgs...); lset != nil {
+			ctx = WithGroupLabels(ctx, lset)
+		}
+	}
+	return ctx, res, nil
+}
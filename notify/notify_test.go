@@ -14,6 +14,7 @@
 package notify
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -24,14 +25,18 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 
+	"github.com/prometheus/alertmanager/deadletter"
 	"github.com/prometheus/alertmanager/nflog"
 	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/pkg/relabel"
 	"github.com/prometheus/alertmanager/silence"
 	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/spool"
 	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/alertmanager/types"
 )
@@ -96,6 +101,7 @@ func TestDedupStageNeedsUpdate(t *testing.T) {
 		resolvedAlerts map[uint64]struct{}
 		repeat         time.Duration
 		resolve        bool
+		acked          bool
 
 		res bool
 	}{
@@ -140,6 +146,26 @@ func TestDedupStageNeedsUpdate(t *testing.T) {
 			repeat:       10 * time.Minute,
 			firingAlerts: alertHashSet(1, 2, 3),
 			res:          true,
+		}, {
+			// Identical sets of alerts shouldn't update after repeat_interval if acknowledged.
+			entry: &nflogpb.Entry{
+				FiringAlerts: []uint64{1, 2, 3},
+				Timestamp:    now.Add(-11 * time.Minute),
+			},
+			repeat:       10 * time.Minute,
+			firingAlerts: alertHashSet(1, 2, 3),
+			acked:        true,
+			res:          false,
+		}, {
+			// Different sets of firing alerts should update if acknowledged.
+			entry: &nflogpb.Entry{
+				FiringAlerts: []uint64{1, 2, 3},
+				Timestamp:    now.Add(-9 * time.Minute),
+			},
+			repeat:       10 * time.Minute,
+			firingAlerts: alertHashSet(2, 3, 4),
+			acked:        true,
+			res:          true,
 		}, {
 			// Different sets of resolved alerts without firing alerts shouldn't update after repeat_interval.
 			entry: &nflogpb.Entry{
@@ -207,7 +233,7 @@ func TestDedupStageNeedsUpdate(t *testing.T) {
 			now: func() time.Time { return now },
 			rs:  sendResolved(c.resolve),
 		}
-		res := s.needsUpdate(c.entry, c.firingAlerts, c.resolvedAlerts, c.repeat)
+		res := s.needsUpdate(c.entry, c.firingAlerts, c.resolvedAlerts, c.repeat, c.acked)
 		require.Equal(t, c.res, res)
 	}
 }
@@ -422,6 +448,478 @@ func TestRetryStageWithError(t *testing.T) {
 	require.NotNil(t, resctx)
 }
 
+func TestRetryStageMetrics(t *testing.T) {
+	fail := true
+	i := Integration{
+		name: "webhook",
+		notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			if fail {
+				fail = false
+				return true, &StatusCodeError{StatusCode: 503, msg: "unexpected status code 503"}
+			}
+			return false, nil
+		}),
+		rs: sendResolved(true),
+	}
+	m := NewMetrics(prometheus.NewRegistry())
+	r := NewRetryStage(i, "team-X", nil, nil, m)
+
+	alerts := []*types.Alert{
+		&types.Alert{
+			Alert: model.Alert{
+				EndsAt: time.Now().Add(time.Hour),
+			},
+		},
+	}
+	_, _, err := r.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(m.numNotifications.WithLabelValues("webhook", "team-X")))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.numTotalFailedNotifications.WithLabelValues("webhook", "team-X")))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.numNotificationRequestsTotal.WithLabelValues("webhook", "team-X")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.numNotificationRequestsFailedTotal.WithLabelValues("webhook", "team-X", "503")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.numNotificationRetriesTotal.WithLabelValues("webhook", "team-X")))
+	require.Equal(t, 1, testutil.CollectAndCount(m.notificationDurationSeconds))
+}
+
+type testAttempts []Attempt
+
+func (a *testAttempts) RecordAttempt(at Attempt) {
+	*a = append(*a, at)
+}
+
+func TestRetryStageAttempts(t *testing.T) {
+	errs := []error{
+		&StatusCodeError{StatusCode: 503, msg: "unexpected status code 503"},
+		nil,
+		&StatusCodeError{StatusCode: 400, msg: "unexpected status code 400"},
+	}
+	i := Integration{
+		name: "webhook",
+		idx:  2,
+		notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			err := errs[0]
+			errs = errs[1:]
+			return err != nil && err.(*StatusCodeError).StatusCode == 503, err
+		}),
+		rs: sendResolved(true),
+	}
+	attempts := &testAttempts{}
+	r := NewRetryStage(i, "team-X", nil, attempts, NewMetrics(prometheus.NewRegistry()))
+
+	alerts := []*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}, EndsAt: time.Now().Add(time.Hour)}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b"}, EndsAt: time.Now().Add(-time.Hour)}},
+	}
+	ctx := WithGroupKey(context.Background(), "1")
+	ctx = WithGroupLabels(ctx, model.LabelSet{"team": "X"})
+	_, _, err := r.Exec(ctx, log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	_, _, err = r.Exec(ctx, log.NewNopLogger(), alerts...)
+	require.Error(t, err)
+
+	require.Len(t, *attempts, 3)
+	for n, a := range *attempts {
+		require.Equal(t, "team-X", a.Receiver)
+		require.Equal(t, "webhook", a.Integration)
+		require.Equal(t, 2, a.Index)
+		require.Equal(t, "1", a.GroupKey)
+		require.Equal(t, model.LabelSet{"team": "X"}, a.GroupLabels)
+		require.Equal(t, []model.Fingerprint{alerts[0].Fingerprint(), alerts[1].Fingerprint()}, a.Fingerprints)
+		require.Equal(t, 1, a.Firing)
+		require.Equal(t, 1, a.Resolved)
+		require.False(t, a.Time.IsZero(), "attempt %d", n)
+	}
+	require.Equal(t, AttemptFailed, (*attempts)[0].Outcome)
+	require.Equal(t, 1, (*attempts)[0].Number)
+	require.Equal(t, 503, (*attempts)[0].StatusCode)
+	require.Equal(t, "unexpected status code 503", (*attempts)[0].Error)
+	require.Equal(t, AttemptSucceeded, (*attempts)[1].Outcome)
+	require.Equal(t, 2, (*attempts)[1].Number)
+	require.Zero(t, (*attempts)[1].StatusCode)
+	require.Empty(t, (*attempts)[1].Error)
+	require.Equal(t, AttemptAborted, (*attempts)[2].Outcome)
+	require.Equal(t, 1, (*attempts)[2].Number)
+	require.Equal(t, 400, (*attempts)[2].StatusCode)
+}
+
+func TestRetryStageThrottled(t *testing.T) {
+	var attempts []time.Time
+	i := Integration{
+		name: "slack",
+		notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			attempts = append(attempts, time.Now())
+			if len(attempts) == 1 {
+				return true, &StatusCodeError{StatusCode: 429, RetryAfter: 2 * time.Second, msg: "unexpected status code 429"}
+			}
+			return false, nil
+		}),
+		rs: sendResolved(true),
+	}
+	m := NewMetrics(prometheus.NewRegistry())
+	r := NewRetryStage(i, "team-X", nil, nil, m)
+
+	alerts := []*types.Alert{
+		&types.Alert{
+			Alert: model.Alert{
+				EndsAt: time.Now().Add(time.Hour),
+			},
+		},
+	}
+	_, _, err := r.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+
+	require.Len(t, attempts, 2)
+	require.GreaterOrEqual(t, int64(attempts[1].Sub(attempts[0])), int64(2*time.Second))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.numNotificationsThrottledTotal.WithLabelValues("slack", "team-X")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.numNotificationRequestsFailedTotal.WithLabelValues("slack", "team-X", "429")))
+}
+
+func TestCountSuppressed(t *testing.T) {
+	m := NewMetrics(prometheus.NewRegistry())
+	muter := types.MuteFunc(func(lset model.LabelSet) bool {
+		return lset["muted"] == "true"
+	})
+	s := m.countSuppressed(NewMuteStage(muter), SuppressedReasonSilenced)
+
+	alerts := []*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"muted": "true"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"muted": "false"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"muted": "true"}}},
+	}
+	_, res, err := s.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.numNotificationsSuppressedTotal.WithLabelValues(SuppressedReasonSilenced)))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.numNotificationsSuppressedTotal.WithLabelValues(SuppressedReasonInhibited)))
+}
+
+type testDeadLetters struct {
+	entries []*deadletter.Entry
+}
+
+func (d *testDeadLetters) Add(e *deadletter.Entry) (string, error) {
+	d.entries = append(d.entries, e)
+	return fmt.Sprintf("%d", len(d.entries)), nil
+}
+
+func TestRetryStageDeadLetter(t *testing.T) {
+	i := Integration{
+		name: "webhook",
+		idx:  1,
+		notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			return false, errors.New("fail to deliver notification")
+		}),
+		rs: sendResolved(true),
+	}
+	dl := &testDeadLetters{}
+	r := NewRetryStage(i, "team-X", dl, nil, NewMetrics(prometheus.NewRegistry()))
+
+	alerts := []*types.Alert{
+		&types.Alert{
+			Alert: model.Alert{
+				Labels: model.LabelSet{"alertname": "test"},
+				EndsAt: time.Now().Add(time.Hour),
+			},
+		},
+	}
+
+	ctx := context.Background()
+	ctx = WithGroupKey(ctx, "1")
+	ctx = WithGroupLabels(ctx, model.LabelSet{"alertname": "test"})
+
+	_, _, err := r.Exec(ctx, log.NewNopLogger(), alerts...)
+	require.NotNil(t, err)
+	require.Len(t, dl.entries, 1)
+
+	e := dl.entries[0]
+	require.Equal(t, "team-X", e.Receiver)
+	require.Equal(t, "webhook", e.Integration)
+	require.Equal(t, uint32(1), e.Idx)
+	require.Equal(t, "1", e.GroupKey)
+	require.Equal(t, model.LabelSet{"alertname": "test"}, e.GroupLabels)
+	require.Equal(t, alerts, e.Alerts)
+	require.Contains(t, e.Error, "fail to deliver notification")
+
+	// A canceled notification must not be dead-lettered.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err = r.Exec(ctx, log.NewNopLogger(), alerts...)
+	require.NotNil(t, err)
+	require.Len(t, dl.entries, 1)
+}
+
+func TestReplay(t *testing.T) {
+	var (
+		sent     []*types.Alert
+		groupKey string
+	)
+	integrations := []Integration{
+		{
+			name: "webhook",
+			idx:  0,
+			notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+				return false, errors.New("wrong integration")
+			}),
+		},
+		{
+			name: "webhook",
+			idx:  1,
+			notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+				groupKey, _ = GroupKey(ctx)
+				sent = append(sent, alerts...)
+				return false, nil
+			}),
+		},
+	}
+	alerts := []*types.Alert{{Alert: model.Alert{Labels: model.LabelSet{"alertname": "test"}}}}
+
+	err := Replay(context.Background(), integrations, &deadletter.Entry{
+		Receiver:    "team-X",
+		Integration: "webhook",
+		Idx:         1,
+		GroupKey:    "1",
+		Alerts:      alerts,
+	})
+	require.NoError(t, err)
+	require.Equal(t, alerts, sent)
+	require.Equal(t, "1", groupKey)
+
+	err = Replay(context.Background(), integrations, &deadletter.Entry{
+		Receiver:    "team-X",
+		Integration: "email",
+	})
+	require.Error(t, err)
+}
+
+func TestStageLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	stage := RoutingStage{
+		"team-X": withLogger(StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+			l.Log("msg", "Notify success", "attempts", 1)
+			return ctx, alerts, nil
+		}), "integration", "webhook[0]"),
+	}
+
+	ctx := WithReceiverName(context.Background(), "team-X")
+	l := log.With(log.NewLogfmtLogger(&buf), "aggrGroup", "{}:{}")
+	_, _, err := stage.Exec(ctx, l, &types.Alert{})
+	require.NoError(t, err)
+	require.Equal(t, "aggrGroup={}:{} receiver=team-X integration=webhook[0] msg=\"Notify success\" attempts=1\n", buf.String())
+}
+
+func TestTest(t *testing.T) {
+	var (
+		sent     []*types.Alert
+		receiver string
+	)
+	integrations := []Integration{
+		{
+			name: "webhook",
+			idx:  0,
+			notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+				receiver, _ = ReceiverName(ctx)
+				sent = append(sent, alerts...)
+				return false, nil
+			}),
+		},
+		{
+			name: "slack",
+			idx:  0,
+			notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+				return true, errors.New("invalid webhook URL")
+			}),
+		},
+	}
+	alerts := []*types.Alert{{Alert: model.Alert{Labels: model.LabelSet{"alertname": "TestAlert"}}}}
+
+	results := Test(context.Background(), "team-X", integrations, alerts...)
+	require.Equal(t, []TestResult{
+		{Integration: "webhook", Index: 0},
+		{Integration: "slack", Index: 0, Err: errors.New("invalid webhook URL")},
+	}, results)
+	require.Equal(t, alerts, sent)
+	require.Equal(t, "team-X", receiver)
+}
+
+type testMaxAlerts uint64
+
+func (m testMaxAlerts) SendResolved() bool { return true }
+
+func (m testMaxAlerts) MaxAlertsPerMessage() uint64 { return uint64(m) }
+
+func TestRetryStageSplitsMessages(t *testing.T) {
+	var (
+		sent  [][]*types.Alert
+		parts [][2]int
+		fail  = true
+	)
+	i := Integration{
+		notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+			part, total, _ := MessagePart(ctx)
+			// Fail the second part once to check that the first one isn't
+			// sent again when retrying.
+			if part == 2 && fail {
+				fail = false
+				return true, errors.New("fail to deliver notification")
+			}
+			sent = append(sent, alerts)
+			parts = append(parts, [2]int{part, total})
+			return false, nil
+		}),
+		rs: testMaxAlerts(2),
+	}
+	r := RetryStage{
+		integration: i,
+		metrics:     NewMetrics(prometheus.NewRegistry()),
+	}
+
+	var alerts []*types.Alert
+	for j := 0; j < 5; j++ {
+		alerts = append(alerts, &types.Alert{
+			Alert: model.Alert{
+				Labels: model.LabelSet{"i": model.LabelValue(fmt.Sprint(j))},
+				EndsAt: time.Now().Add(time.Hour),
+			},
+		})
+	}
+
+	_, res, err := r.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Equal(t, alerts, res)
+	require.Equal(t, [][]*types.Alert{alerts[0:2], alerts[2:4], alerts[4:5]}, sent)
+	require.Equal(t, [][2]int{{1, 3}, {2, 3}, {3, 3}}, parts)
+}
+
+func TestSplitAlerts(t *testing.T) {
+	alerts := []*types.Alert{{}, {}, {}, {}}
+
+	require.Equal(t, [][]*types.Alert{alerts}, splitAlerts(alerts, 0))
+	require.Equal(t, [][]*types.Alert{alerts}, splitAlerts(alerts, 4))
+	require.Equal(t, [][]*types.Alert{alerts[:3], alerts[3:]}, splitAlerts(alerts, 3))
+	require.Equal(t, [][]*types.Alert{alerts[:2], alerts[2:]}, splitAlerts(alerts, 2))
+	require.Equal(t, [][]*types.Alert{nil}, splitAlerts(nil, 2))
+}
+
+type testSpool struct {
+	entries map[string]*spool.Entry
+	n       int
+}
+
+func (s *testSpool) Put(e *spool.Entry) (string, error) {
+	s.n++
+	id := fmt.Sprintf("%d", s.n)
+	s.entries[id] = e
+	return id, nil
+}
+
+func (s *testSpool) Remove(id string) error {
+	if _, ok := s.entries[id]; !ok {
+		return errors.New("not found")
+	}
+	delete(s.entries, id)
+	return nil
+}
+
+type testErrKey struct{}
+
+func TestSpoolStage(t *testing.T) {
+	sp := &testSpool{entries: map[string]*spool.Entry{}}
+	recv := &nflogpb.Receiver{GroupName: "team-X", Integration: "webhook", Idx: 0}
+
+	var spooled []*spool.Entry
+	next := StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		for _, e := range sp.entries {
+			spooled = append(spooled, e)
+		}
+		if err, ok := ctx.Value(testErrKey{}).(error); ok {
+			return ctx, nil, err
+		}
+		return ctx, alerts, nil
+	})
+	s := NewSpoolStage(sp, recv, next)
+
+	alerts := []*types.Alert{{Alert: model.Alert{Labels: model.LabelSet{"alertname": "test"}}}}
+	ctx := context.Background()
+	ctx = WithGroupKey(ctx, "1")
+	ctx = WithFiringAlerts(ctx, []uint64{1})
+
+	// The notification is spooled while it is sent and removed afterwards.
+	_, res, err := s.Exec(ctx, log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Equal(t, alerts, res)
+	require.Len(t, spooled, 1)
+	require.Equal(t, "team-X", spooled[0].Receiver)
+	require.Equal(t, "1", spooled[0].GroupKey)
+	require.Equal(t, []uint64{1}, spooled[0].FiringAlerts)
+	require.Len(t, sp.entries, 0)
+
+	// A notification that failed for good is removed as well.
+	_, _, err = s.Exec(context.WithValue(ctx, testErrKey{}, errors.New("failed")), log.NewNopLogger(), alerts...)
+	require.Error(t, err)
+	require.Len(t, sp.entries, 0)
+
+	// A notification interrupted by a shutdown stays in the spool.
+	cctx, cancel := context.WithCancel(context.WithValue(ctx, testErrKey{}, errors.New("canceled")))
+	cancel()
+	_, _, err = s.Exec(cctx, log.NewNopLogger(), alerts...)
+	require.Error(t, err)
+	require.Len(t, sp.entries, 1)
+}
+
+func TestReplaySpool(t *testing.T) {
+	created := time.Now()
+	sp := &testSpool{entries: map[string]*spool.Entry{
+		"1": {ID: "1", Receiver: "team-X", Integration: "webhook", GroupKey: "1", FiringAlerts: []uint64{1}, CreatedAt: created},
+		"2": {ID: "2", Receiver: "team-X", Integration: "webhook", GroupKey: "2", FiringAlerts: []uint64{2}, CreatedAt: created},
+		"3": {ID: "3", Receiver: "team-Y", Integration: "webhook", GroupKey: "3", CreatedAt: created},
+	}}
+	entries := []*spool.Entry{sp.entries["1"], sp.entries["2"], sp.entries["3"]}
+
+	var sent []string
+	receivers := map[string][]Integration{
+		"team-X": {
+			{
+				name: "webhook",
+				notifier: notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+					gkey, _ := GroupKey(ctx)
+					sent = append(sent, gkey)
+					return false, nil
+				}),
+			},
+		},
+	}
+
+	var logged []string
+	nfl := &testNflog{
+		logFunc: func(r *nflogpb.Receiver, gkey string, firingAlerts, resolvedAlerts []uint64) error {
+			logged = append(logged, gkey)
+			return nil
+		},
+	}
+	nfl.qerr = nflog.ErrNotFound
+
+	dl := &testDeadLetters{}
+	ReplaySpool(context.Background(), entries, sp, receivers, nfl, dl, log.NewNopLogger())
+
+	require.Equal(t, []string{"1", "2"}, sent)
+	require.Equal(t, []string{"1", "2"}, logged)
+	require.Len(t, dl.entries, 1)
+	require.Equal(t, "team-Y", dl.entries[0].Receiver)
+	require.Len(t, sp.entries, 0)
+
+	// Entries already notified after they were spooled are skipped.
+	sent, logged = nil, nil
+	nfl.qerr = nil
+	nfl.qres = []*nflogpb.Entry{{Timestamp: created.Add(time.Second)}}
+	sp.entries["4"] = &spool.Entry{ID: "4", Receiver: "team-X", Integration: "webhook", GroupKey: "1", CreatedAt: created}
+	ReplaySpool(context.Background(), []*spool.Entry{sp.entries["4"]}, sp, receivers, nfl, dl, log.NewNopLogger())
+	require.Len(t, sent, 0)
+	require.Len(t, logged, 0)
+	require.Len(t, sp.entries, 0)
+}
+
 func TestRetryStageNoResolved(t *testing.T) {
 	sent := []*types.Alert{}
 	i := Integration{
@@ -582,6 +1080,21 @@ func TestSetNotifiesStage(t *testing.T) {
 	require.NotNil(t, resctx)
 }
 
+func TestLeaderStage(t *testing.T) {
+	leader := false
+	stage := NewLeaderStage(func() bool { return leader })
+	alerts := []*types.Alert{{}, {}}
+
+	_, res, err := stage.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	leader = true
+	_, res, err = stage.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Equal(t, alerts, res)
+}
+
 func TestMuteStage(t *testing.T) {
 	// Mute all label sets that have a "mute" key.
 	muter := types.MuteFunc(func(lset model.LabelSet) bool {
@@ -817,3 +1330,61 @@ func TestTimeMuteStage(t *testing.T) {
 		t.Fatalf("Expected %d alerts after time mute stage but got %d", nonMuteCount, len(outAlerts))
 	}
 }
+
+func TestRelabelStage(t *testing.T) {
+	var labelCfgs, annotationCfgs []*relabel.Config
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- target_label: team
+  replacement: ops
+- regex: env
+  action: labelmap
+  replacement: environment
+- regex: env
+  action: labeldrop
+- source_labels: [environment]
+  regex: test
+  action: drop
+`), &labelCfgs))
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- regex: runbook
+  action: labeldrop
+`), &annotationCfgs))
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "a", "env": "prod"},
+				Annotations: model.LabelSet{"summary": "foo", "runbook": "http://runbook"},
+			},
+		},
+		{
+			Alert: model.Alert{
+				Labels: model.LabelSet{"alertname": "b", "env": "test"},
+			},
+		},
+	}
+
+	stage := NewRelabelStage()
+
+	// Without relabeling rules the alerts are passed on unchanged.
+	_, res, err := stage.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Equal(t, alerts, res)
+
+	ctx := WithGroupLabels(context.Background(), model.LabelSet{"env": "prod"})
+	ctx = WithLabelRelabelConfigs(ctx, labelCfgs)
+	ctx = WithAnnotationRelabelConfigs(ctx, annotationCfgs)
+
+	ctx, res, err = stage.Exec(ctx, log.NewNopLogger(), alerts...)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, model.LabelSet{"alertname": "a", "environment": "prod", "team": "ops"}, res[0].Labels)
+	require.Equal(t, model.LabelSet{"summary": "foo"}, res[0].Annotations)
+
+	gl, _ := GroupLabels(ctx)
+	require.Equal(t, model.LabelSet{"environment": "prod", "team": "ops"}, gl)
+
+	// The original alerts are not modified.
+	require.Equal(t, model.LabelSet{"alertname": "a", "env": "prod"}, alerts[0].Labels)
+	require.Equal(t, model.LabelSet{"summary": "foo", "runbook": "http://runbook"}, alerts[0].Annotations)
+}
@@ -0,0 +1,251 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nsca sends notifications as passive check results to the NSCA
+// daemon of a Nagios or Icinga server, using the version 3 packets of NSCA 2.x.
+package nsca
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const (
+	packetVersion = 3
+
+	// The sizes of the fields of the packets, which are fixed.
+	ivSize                = 128
+	maxHostNameLen        = 64
+	maxServiceDescLen     = 128
+	maxPluginOutputLen    = 512
+	initPacketSize        = ivSize + 4
+	dataPacketSize        = 2 + 2 + 4 + 4 + 2 + maxHostNameLen + maxServiceDescLen + maxPluginOutputLen + 2
+	offsetCRC             = 4
+	offsetTimestamp       = 8
+	offsetReturnCode      = 12
+	offsetHostName        = 14
+	offsetServiceDesc     = offsetHostName + maxHostNameLen
+	offsetPluginOutput    = offsetServiceDesc + maxServiceDescLen
+	defaultConnectTimeout = 10 * time.Second
+)
+
+// returnCodes are the return codes of the states of passive checks.
+var returnCodes = map[string]int16{
+	"OK":       0,
+	"WARNING":  1,
+	"CRITICAL": 2,
+	"UNKNOWN":  3,
+}
+
+// Notifier implements a Notifier for NSCA passive check results.
+type Notifier struct {
+	conf   *config.NSCAConfig
+	tmpl   *template.Template
+	logger log.Logger
+}
+
+// New returns a new NSCA notifier.
+func New(c *config.NSCAConfig, t *template.Template, l log.Logger) *Notifier {
+	return &Notifier{conf: c, tmpl: t, logger: l}
+}
+
+// checkResult is the passive check result of a notification.
+type checkResult struct {
+	hostName    string
+	serviceDesc string
+	returnCode  int16
+	output      string
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		data  = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl  = notify.TmplText(n.tmpl, data, &err)
+		state = strings.TrimSpace(tmpl(n.conf.State))
+		res   = checkResult{
+			hostName:    tmpl(n.conf.HostName),
+			serviceDesc: tmpl(n.conf.ServiceDescription),
+			output:      tmpl(n.conf.Output),
+		}
+	)
+	if err != nil {
+		return false, fmt.Errorf("templating error: %s", err)
+	}
+	if res.returnCode, err = parseState(state); err != nil {
+		return false, err
+	}
+	if res.hostName == "" {
+		return false, fmt.Errorf("empty host name")
+	}
+	// The fields are null-terminated.
+	var truncated bool
+	if res.output, truncated = notify.Truncate(res.output, maxPluginOutputLen-1); truncated {
+		level.Debug(n.logger).Log("msg", "Truncated output", "incident", key)
+	}
+	res.hostName, _ = notify.Truncate(res.hostName, maxHostNameLen-1)
+	res.serviceDesc, _ = notify.Truncate(res.serviceDesc, maxServiceDescLen-1)
+
+	if record, ok := notify.DryRun(ctx); ok {
+		record("text/plain", []byte(fmt.Sprintf("%s\t%s\t%d\t%s\n", res.hostName, res.serviceDesc, res.returnCode, res.output)))
+		if notify.ConnectivityCheck(ctx) {
+			conn, _, _, err := n.connect(ctx)
+			if err != nil {
+				return false, err
+			}
+			conn.Close()
+		}
+		return false, nil
+	}
+
+	level.Debug(n.logger).Log("msg", "Sending passive check result", "incident", key, "host", res.hostName, "service", res.serviceDesc)
+	conn, iv, timestamp, err := n.connect(ctx)
+	if err != nil {
+		return true, err
+	}
+	defer conn.Close()
+
+	packet := res.packet(timestamp)
+	if err := encrypt(n.conf.Encryption, string(n.conf.Password), iv, packet); err != nil {
+		return false, err
+	}
+	if _, err := conn.Write(packet); err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
+// connect connects to the daemon and reads the initialization vector and the
+// timestamp it sends first.
+func (n *Notifier) connect(ctx context.Context) (net.Conn, []byte, uint32, error) {
+	addr := net.JoinHostPort(n.conf.Host, strconv.Itoa(n.conf.Port))
+	d := net.Dialer{Timeout: defaultConnectTimeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	init := make([]byte, initPacketSize)
+	if _, err := io.ReadFull(conn, init); err != nil {
+		conn.Close()
+		return nil, nil, 0, fmt.Errorf("read initialization packet: %w", err)
+	}
+	return conn, init[:ivSize], binary.BigEndian.Uint32(init[ivSize:]), nil
+}
+
+// parseState returns the return code of a state given by name or code.
+func parseState(s string) (int16, error) {
+	if rc, ok := returnCodes[strings.ToUpper(s)]; ok {
+		return rc, nil
+	}
+	if rc, err := strconv.Atoi(s); err == nil && rc >= 0 && rc <= 3 {
+		return int16(rc), nil
+	}
+	return 0, fmt.Errorf("invalid state %q, must be one of OK, WARNING, CRITICAL, UNKNOWN or between 0 and 3", s)
+}
+
+// packet returns the data packet of the check result, stamped with the
+// timestamp of the daemon.
+func (r checkResult) packet(timestamp uint32) []byte {
+	p := make([]byte, dataPacketSize)
+	binary.BigEndian.PutUint16(p, packetVersion)
+	binary.BigEndian.PutUint32(p[offsetTimestamp:], timestamp)
+	binary.BigEndian.PutUint16(p[offsetReturnCode:], uint16(r.returnCode))
+	copy(p[offsetHostName:offsetServiceDesc-1], r.hostName)
+	copy(p[offsetServiceDesc:offsetPluginOutput-1], r.serviceDesc)
+	copy(p[offsetPluginOutput:dataPacketSize-3], r.output)
+	binary.BigEndian.PutUint32(p[offsetCRC:], crc32.ChecksumIEEE(p))
+	return p
+}
+
+// encrypt encrypts the packet in place like the mcrypt library used by the
+// daemon does, in CFB mode with 8-bit feedback.
+func encrypt(method, password string, iv, packet []byte) error {
+	var (
+		block   cipher.Block
+		keySize int
+		err     error
+	)
+	switch method {
+	case "none":
+		return nil
+	case "xor":
+		for i := range packet {
+			packet[i] ^= iv[i%len(iv)]
+		}
+		if password != "" {
+			for i := range packet {
+				packet[i] ^= password[i%len(password)]
+			}
+		}
+		return nil
+	case "des":
+		keySize = 8
+		block, err = des.NewCipher(key(password, keySize))
+	case "3des":
+		keySize = 24
+		block, err = des.NewTripleDESCipher(key(password, keySize))
+	case "aes":
+		// The key size of Rijndael-128 in mcrypt is the largest of AES.
+		keySize = 32
+		block, err = aes.NewCipher(key(password, keySize))
+	default:
+		return fmt.Errorf("unsupported encryption %q", method)
+	}
+	if err != nil {
+		return err
+	}
+
+	register := make([]byte, block.BlockSize())
+	copy(register, iv)
+	out := make([]byte, block.BlockSize())
+	for i := range packet {
+		block.Encrypt(out, register)
+		packet[i] ^= out[0]
+		copy(register, register[1:])
+		register[len(register)-1] = packet[i]
+	}
+	return nil
+}
+
+// key returns the password truncated or padded with zeros to the key size.
+func key(password string, size int) []byte {
+	k := make([]byte, size)
+	copy(k, password)
+	return k
+}
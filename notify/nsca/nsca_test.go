@@ -0,0 +1,182 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsca
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// decrypt decrypts a packet like the daemon does.
+func decrypt(t *testing.T, method, password string, iv, packet []byte) {
+	var block cipher.Block
+	var err error
+	switch method {
+	case "none":
+		return
+	case "xor":
+		require.NoError(t, encrypt(method, password, iv, packet))
+		return
+	case "des":
+		block, err = des.NewCipher(key(password, 8))
+	case "3des":
+		block, err = des.NewTripleDESCipher(key(password, 24))
+	case "aes":
+		block, err = aes.NewCipher(key(password, 32))
+	}
+	require.NoError(t, err)
+
+	register := append([]byte(nil), iv[:block.BlockSize()]...)
+	out := make([]byte, block.BlockSize())
+	for i := range packet {
+		block.Encrypt(out, register)
+		c := packet[i]
+		packet[i] ^= out[0]
+		copy(register, register[1:])
+		register[len(register)-1] = c
+	}
+}
+
+// cString returns the null-terminated string of a field.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return string(b[:i])
+	}
+	return string(b)
+}
+
+func TestNSCANotify(t *testing.T) {
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "DiskFull", "instance": "db1"},
+			Annotations: model.LabelSet{"summary": "The disk is full"},
+			StartsAt:    time.Now(),
+			EndsAt:      time.Now().Add(time.Hour),
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "1")
+
+	for _, method := range config.NSCAEncryptions {
+		t.Run(method, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer l.Close()
+
+			iv := make([]byte, ivSize)
+			for i := range iv {
+				iv[i] = byte(i * 7)
+			}
+			packets := make(chan []byte, 1)
+			go func() {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				init := append(append([]byte(nil), iv...), 0, 0, 0x30, 0x39)
+				conn.Write(init)
+				p := make([]byte, dataPacketSize)
+				if _, err := io.ReadFull(conn, p); err == nil {
+					packets <- p
+				}
+			}()
+
+			host, port, err := net.SplitHostPort(l.Addr().String())
+			require.NoError(t, err)
+			p, err := strconv.Atoi(port)
+			require.NoError(t, err)
+			notifier := New(
+				&config.NSCAConfig{
+					Host:               host,
+					Port:               p,
+					Encryption:         method,
+					Password:           "secret",
+					HostName:           `{{ .CommonLabels.instance }}`,
+					ServiceDescription: `{{ .CommonLabels.alertname }}`,
+					Output:             `{{ .CommonAnnotations.summary }}`,
+					State:              `{{ if eq .Status "firing" }}WARNING{{ else }}OK{{ end }}`,
+				},
+				test.CreateTmpl(t),
+				log.NewNopLogger(),
+			)
+
+			retry, err := notifier.Notify(ctx, alert)
+			require.NoError(t, err)
+			require.False(t, retry)
+
+			packet := <-packets
+			decrypt(t, method, "secret", iv, packet)
+			require.Equal(t, uint16(packetVersion), binary.BigEndian.Uint16(packet))
+			crc := binary.BigEndian.Uint32(packet[offsetCRC:])
+			binary.BigEndian.PutUint32(packet[offsetCRC:], 0)
+			require.Equal(t, crc32.ChecksumIEEE(packet), crc)
+			require.Equal(t, uint32(12345), binary.BigEndian.Uint32(packet[offsetTimestamp:]))
+			require.Equal(t, uint16(1), binary.BigEndian.Uint16(packet[offsetReturnCode:]))
+			require.Equal(t, "db1", cString(packet[offsetHostName:offsetServiceDesc]))
+			require.Equal(t, "DiskFull", cString(packet[offsetServiceDesc:offsetPluginOutput]))
+			require.Equal(t, "The disk is full", cString(packet[offsetPluginOutput:]))
+		})
+	}
+}
+
+func TestNSCANotifyUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().(*net.TCPAddr)
+	l.Close()
+
+	notifier := New(
+		&config.NSCAConfig{
+			Host:       addr.IP.String(),
+			Port:       addr.Port,
+			Encryption: "none",
+			HostName:   "db1",
+			State:      "CRITICAL",
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	retry, err := notifier.Notify(notify.WithGroupKey(context.Background(), "1"), &types.Alert{})
+	require.Error(t, err)
+	require.True(t, retry)
+}
+
+func TestParseState(t *testing.T) {
+	for in, expected := range map[string]int16{"OK": 0, "warning": 1, "CRITICAL": 2, "UNKNOWN": 3, "2": 2} {
+		rc, err := parseState(in)
+		require.NoError(t, err)
+		require.Equal(t, expected, rc)
+	}
+	_, err := parseState("DOWN")
+	require.EqualError(t, err, `invalid state "DOWN", must be one of OK, WARNING, CRITICAL, UNKNOWN or between 0 and 3`)
+}
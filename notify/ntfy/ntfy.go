@@ -0,0 +1,144 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ntfy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Notifier implements a Notifier for ntfy notifications.
+type Notifier struct {
+	conf    *config.NtfyConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+}
+
+// New returns a new ntfy notifier.
+func New(c *config.NtfyConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "ntfy", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:   c,
+		tmpl:   t,
+		logger: l,
+		client: client,
+		// Rate limited requests, answered with status code 429, are
+		// retried by CheckResponse.
+		retrier: &notify.Retrier{},
+	}, nil
+}
+
+// publication is the payload of JSON publishing.
+type publication struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Click    string   `json:"click,omitempty"`
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		data     = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl     = notify.TmplText(n.tmpl, data, &err)
+		priority = tmpl(n.conf.Priority)
+		pub      = publication{
+			Topic:   tmpl(n.conf.Topic),
+			Title:   tmpl(n.conf.Title),
+			Message: tmpl(n.conf.Message),
+			Click:   tmpl(n.conf.Click),
+		}
+	)
+	for _, t := range n.conf.Tags {
+		if t = tmpl(t); t != "" {
+			pub.Tags = append(pub.Tags, t)
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("templating error: %s", err)
+	}
+	if m := notify.LookupSeverity(n.conf.SeverityMappings, as...); m != nil && m.Ntfy != "" {
+		priority = m.Ntfy
+	}
+	if pub.Priority, err = parsePriority(priority); err != nil {
+		return false, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(pub); err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest("POST", n.conf.URL.String(), &buf)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.conf.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+string(n.conf.Token))
+	}
+
+	level.Debug(n.logger).Log("msg", "Publishing notification", "incident", key, "topic", pub.Topic)
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	return n.retrier.CheckResponse(resp, resp.Body)
+}
+
+// parsePriority returns the number of a priority given by name or number. The
+// empty priority is the default one of the server.
+func parsePriority(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	for i, p := range config.NtfyPriorities {
+		if s == p {
+			return i + 1, nil
+		}
+	}
+	if p, err := strconv.Atoi(s); err == nil && p >= 1 && p <= 5 {
+		return p, nil
+	}
+	return 0, fmt.Errorf("invalid priority %q, must be one of %s or between 1 and 5", s, strings.Join(config.NtfyPriorities, ", "))
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ntfy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestNtfyRetry(t *testing.T) {
+	notifier, err := New(
+		&config.NtfyConfig{
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	for statusCode, expected := range test.RetryTests(test.DefaultRetryCodes()) {
+		actual, _ := notifier.retrier.Check(statusCode, nil)
+		require.Equal(t, expected, actual, fmt.Sprintf("error on status %d", statusCode))
+	}
+}
+
+func TestNtfyRedactedURL(t *testing.T) {
+	ctx, u, fn := test.GetContextWithCancelingURL()
+	defer fn()
+
+	secret := "secret"
+	notifier, err := New(
+		&config.NtfyConfig{
+			URL:        &config.URL{URL: u},
+			Topic:      "alerts",
+			Token:      config.Secret(secret),
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	test.AssertNotifyLeaksNoSecret(t, ctx, notifier, secret)
+}
+
+func TestNtfyNotify(t *testing.T) {
+	var (
+		auth string
+		got  publication
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "DiskFull", "severity": "warning", "team": "db"},
+			Annotations: model.LabelSet{"summary": "The disk is full"},
+			StartsAt:    time.Now(),
+			EndsAt:      time.Now().Add(time.Hour),
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "1")
+
+	for _, tc := range []struct {
+		token    config.Secret
+		priority string
+		mappings *config.SeverityMappings
+		expected int
+		err      string
+	}{
+		{token: "token", priority: "high", expected: 4},
+		{priority: "2", expected: 2},
+		{expected: 0},
+		{
+			priority: "high",
+			mappings: &config.SeverityMappings{
+				Label:    "severity",
+				Mappings: []*config.SeverityMapping{{Severity: "warning", Ntfy: "low"}},
+			},
+			expected: 2,
+		},
+		{priority: "6", err: `invalid priority "6", must be one of min, low, default, high, urgent or between 1 and 5`},
+	} {
+		got = publication{}
+		notifier, err := New(
+			&config.NtfyConfig{
+				URL:              &config.URL{URL: u},
+				Topic:            `{{ .CommonLabels.team }}-alerts`,
+				Token:            tc.token,
+				Title:            `{{ .CommonLabels.alertname }}`,
+				Message:          `{{ .CommonAnnotations.summary }}`,
+				Priority:         tc.priority,
+				Tags:             []string{"warning", `{{ .CommonLabels.missing }}`},
+				Click:            `{{ .ExternalURL }}`,
+				SeverityMappings: tc.mappings,
+				HTTPConfig:       &commoncfg.HTTPClientConfig{},
+			},
+			test.CreateTmpl(t),
+			log.NewNopLogger(),
+		)
+		require.NoError(t, err)
+
+		retry, err := notifier.Notify(ctx, alert)
+		require.False(t, retry)
+		if tc.err != "" {
+			require.EqualError(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		if tc.token != "" {
+			require.Equal(t, "Bearer token", auth)
+		} else {
+			require.Empty(t, auth)
+		}
+		require.Equal(t, publication{
+			Topic:    "db-alerts",
+			Title:    "DiskFull",
+			Message:  "The disk is full",
+			Priority: tc.expected,
+			Tags:     []string{"warning"},
+			Click:    "http://am",
+		}, got)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+type onCallKey struct {
+	provider, url, schedule string
+}
+
+type onCallEntry struct {
+	oncall    []template.OnCall
+	expiresAt time.Time
+}
+
+// OnCallStage resolves the people on call in the schedule of the owner of the
+// route and adds them to the context, from which they reach the template
+// data. Responses are cached per schedule for the configured time.
+type OnCallStage struct {
+	metrics *Metrics
+	now     func() time.Time
+
+	mtx     sync.Mutex
+	clients map[*config.OnCallScheduleConfig]*http.Client
+	cache   map[onCallKey]onCallEntry
+}
+
+// NewOnCallStage returns a new OnCallStage.
+func NewOnCallStage(m *Metrics) *OnCallStage {
+	return &OnCallStage{
+		metrics: m,
+		now:     time.Now,
+		clients: map[*config.OnCallScheduleConfig]*http.Client{},
+		cache:   map[onCallKey]onCallEntry{},
+	}
+}
+
+// Exec implements the Stage interface. The notification is sent without the
+// on-call if the schedule cannot be resolved within the timeout.
+func (n *OnCallStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	owner, ok := Owner(ctx)
+	if !ok || owner == nil || owner.OnCall == nil {
+		return ctx, alerts, nil
+	}
+	conf := owner.OnCall
+	client, err := n.client(conf)
+	if err != nil {
+		level.Warn(l).Log("msg", "Failed to create on-call client", "err", err)
+		return ctx, alerts, nil
+	}
+
+	octx, cancel := context.WithTimeout(ctx, time.Duration(conf.Timeout))
+	defer cancel()
+	oncall, err := n.lookup(octx, client, conf)
+	if err != nil {
+		level.Warn(l).Log("msg", "Failed to resolve on-call", "provider", conf.Provider, "schedule", conf.ScheduleID, "err", err)
+		return ctx, alerts, nil
+	}
+	return WithOnCall(ctx, oncall), alerts, nil
+}
+
+func (n *OnCallStage) client(conf *config.OnCallScheduleConfig) (*http.Client, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if c, ok := n.clients[conf]; ok {
+		return c, nil
+	}
+	httpConfig := commoncfg.DefaultHTTPClientConfig
+	if conf.HTTPConfig != nil {
+		httpConfig = *conf.HTTPConfig
+	}
+	c, err := commoncfg.NewClientFromConfig(httpConfig, "oncall")
+	if err != nil {
+		return nil, err
+	}
+	n.clients[conf] = c
+	return c, nil
+}
+
+// lookup returns the on-call of the schedule from the cache or the provider.
+func (n *OnCallStage) lookup(ctx context.Context, client *http.Client, conf *config.OnCallScheduleConfig) ([]template.OnCall, error) {
+	key := onCallKey{provider: conf.Provider, url: conf.APIURL.String(), schedule: conf.ScheduleID}
+
+	n.mtx.Lock()
+	e, ok := n.cache[key]
+	n.mtx.Unlock()
+	if ok && n.now().Before(e.expiresAt) {
+		return e.oncall, nil
+	}
+
+	n.metrics.numOnCallRequestsTotal.WithLabelValues(conf.Provider).Inc()
+	var (
+		oncall []template.OnCall
+		err    error
+	)
+	switch conf.Provider {
+	case "pagerduty":
+		oncall, err = pagerDutyOnCall(ctx, client, conf)
+	case "opsgenie":
+		oncall, err = opsGenieOnCall(ctx, client, conf)
+	default:
+		err = fmt.Errorf("unknown on-call provider %q", conf.Provider)
+	}
+	if err != nil {
+		n.metrics.numOnCallRequestsFailedTotal.WithLabelValues(conf.Provider).Inc()
+		return nil, err
+	}
+
+	now := n.now()
+	n.mtx.Lock()
+	// Remove the expired entries of schedules no longer used.
+	for k, e := range n.cache {
+		if !now.Before(e.expiresAt) {
+			delete(n.cache, k)
+		}
+	}
+	n.cache[key] = onCallEntry{
+		oncall:    oncall,
+		expiresAt: now.Add(time.Duration(conf.CacheTTL)),
+	}
+	n.mtx.Unlock()
+	return oncall, nil
+}
+
+// pagerDutyOnCalls is the response of the on-calls API of PagerDuty.
+type pagerDutyOnCalls struct {
+	OnCalls []struct {
+		EscalationLevel int `json:"escalation_level"`
+		User            struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"user"`
+	} `json:"oncalls"`
+}
+
+// pagerDutyOnCall returns the users on call in the schedule, ordered by
+// escalation level.
+func pagerDutyOnCall(ctx context.Context, client *http.Client, conf *config.OnCallScheduleConfig) ([]template.OnCall, error) {
+	q := url.Values{
+		"schedule_ids[]": {conf.ScheduleID},
+		"include[]":      {"users"},
+		"earliest":       {"true"},
+	}
+	u := strings.TrimSuffix(conf.APIURL.String(), "/") + "/oncalls?" + q.Encode()
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	header.Set("Authorization", "Token token="+string(conf.APIKey))
+
+	var resp pagerDutyOnCalls
+	if err := getJSON(ctx, client, u, header, &resp); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(resp.OnCalls, func(i, j int) bool {
+		return resp.OnCalls[i].EscalationLevel < resp.OnCalls[j].EscalationLevel
+	})
+	var (
+		res  []template.OnCall
+		seen = map[string]struct{}{}
+	)
+	for _, oc := range resp.OnCalls {
+		if _, ok := seen[oc.User.Email]; ok {
+			continue
+		}
+		seen[oc.User.Email] = struct{}{}
+		res = append(res, template.OnCall{Name: oc.User.Name, Email: oc.User.Email})
+	}
+	return res, nil
+}
+
+// opsGenieOnCalls is the response of the on-calls API of OpsGenie with flat
+// results.
+type opsGenieOnCalls struct {
+	Data struct {
+		OnCallRecipients []string `json:"onCallRecipients"`
+	} `json:"data"`
+}
+
+// opsGenieOnCall returns the users on call in the schedule. Their names are
+// their OpsGenie usernames, which are email addresses.
+func opsGenieOnCall(ctx context.Context, client *http.Client, conf *config.OnCallScheduleConfig) ([]template.OnCall, error) {
+	u := conf.APIURL.String() + "v2/schedules/" + url.PathEscape(conf.ScheduleID) + "/on-calls?flat=true"
+	header := http.Header{}
+	header.Set("Authorization", "GenieKey "+string(conf.APIKey))
+
+	var resp opsGenieOnCalls
+	if err := getJSON(ctx, client, u, header, &resp); err != nil {
+		return nil, err
+	}
+	var res []template.OnCall
+	for _, r := range resp.Data.OnCallRecipients {
+		oc := template.OnCall{Name: r}
+		if strings.Contains(r, "@") {
+			oc.Email = r
+		}
+		res = append(res, oc)
+	}
+	return res, nil
+}
+
+// getJSON decodes the JSON response of a GET request into v.
+func getJSON(ctx context.Context, client *http.Client, u string, header http.Header, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return RedactURL(err)
+	}
+	defer Drain(resp)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestOnCallStagePagerDuty(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.Equal(t, "/oncalls", r.URL.Path)
+		require.Equal(t, []string{"PSCHED1"}, r.URL.Query()["schedule_ids[]"])
+		require.Equal(t, "Token token=key", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"oncalls": [
+			{"escalation_level": 2, "user": {"name": "Bob", "email": "bob@example.com"}},
+			{"escalation_level": 1, "user": {"name": "Alice", "email": "alice@example.com"}},
+			{"escalation_level": 3, "user": {"name": "Alice", "email": "alice@example.com"}}
+		]}`))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	owner := &config.OwnerConfig{
+		Name: "team-db",
+		OnCall: &config.OnCallScheduleConfig{
+			Provider:   "pagerduty",
+			ScheduleID: "PSCHED1",
+			APIKey:     "key",
+			APIURL:     &config.URL{URL: u},
+			Timeout:    model.Duration(time.Second),
+			CacheTTL:   model.Duration(time.Minute),
+		},
+	}
+	stage := NewOnCallStage(NewMetrics(prometheus.NewRegistry()))
+	now := time.Now()
+	stage.now = func() time.Time { return now }
+	alert := &types.Alert{}
+
+	// Without an owner, nothing is resolved.
+	ctx, _, err := stage.Exec(context.Background(), log.NewNopLogger(), alert)
+	require.NoError(t, err)
+	_, ok := OnCall(ctx)
+	require.False(t, ok)
+	require.Equal(t, 0, requests)
+
+	ctx, res, err := stage.Exec(WithOwner(context.Background(), owner), log.NewNopLogger(), alert)
+	require.NoError(t, err)
+	require.Equal(t, []*types.Alert{alert}, res)
+	oncall, ok := OnCall(ctx)
+	require.True(t, ok)
+	expected := []template.OnCall{
+		{Name: "Alice", Email: "alice@example.com"},
+		{Name: "Bob", Email: "bob@example.com"},
+	}
+	require.Equal(t, expected, oncall)
+
+	// The on-call reaches the template data.
+	tmpl, err := template.FromGlobs()
+	require.NoError(t, err)
+	tmpl.ExternalURL, _ = url.Parse("http://am")
+	ctx = WithReceiverName(WithGroupLabels(ctx, model.LabelSet{}), "team-db")
+	data := GetTemplateData(ctx, tmpl, []*types.Alert{alert}, log.NewNopLogger())
+	require.Equal(t, &template.Owner{Name: "team-db", OnCall: expected}, data.Owner)
+
+	// Responses are cached.
+	_, _, err = stage.Exec(WithOwner(context.Background(), owner), log.NewNopLogger(), alert)
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+
+	now = now.Add(time.Minute)
+	_, _, err = stage.Exec(WithOwner(context.Background(), owner), log.NewNopLogger(), alert)
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+}
+
+func TestOnCallStageOpsGenie(t *testing.T) {
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "failed", http.StatusInternalServerError)
+			return
+		}
+		require.Equal(t, "/v2/schedules/sre/on-calls", r.URL.Path)
+		require.Equal(t, "true", r.URL.Query().Get("flat"))
+		require.Equal(t, "GenieKey key", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"data": {"onCallRecipients": ["alice@example.com", "bob"]}}`))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL + "/")
+	require.NoError(t, err)
+
+	conf := &config.OnCallScheduleConfig{
+		Provider:   "opsgenie",
+		ScheduleID: "sre",
+		APIKey:     "key",
+		APIURL:     &config.URL{URL: u},
+		Timeout:    model.Duration(time.Second),
+	}
+	m := NewMetrics(prometheus.NewRegistry())
+	stage := NewOnCallStage(m)
+	ctx := WithOwner(context.Background(), &config.OwnerConfig{OnCall: conf})
+
+	octx, _, err := stage.Exec(ctx, log.NewNopLogger())
+	require.NoError(t, err)
+	oncall, _ := OnCall(octx)
+	require.Equal(t, []template.OnCall{{Name: "alice@example.com", Email: "alice@example.com"}, {Name: "bob"}}, oncall)
+
+	// Notifications are sent without the on-call if it can't be resolved.
+	fail = true
+	octx, _, err = stage.Exec(ctx, log.NewNopLogger())
+	require.NoError(t, err)
+	_, ok := OnCall(octx)
+	require.False(t, ok)
+	require.Equal(t, 2.0, testutil.ToFloat64(m.numOnCallRequestsTotal.WithLabelValues("opsgenie")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.numOnCallRequestsFailedTotal.WithLabelValues("opsgenie")))
+}
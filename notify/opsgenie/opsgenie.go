@@ -44,7 +44,7 @@ type Notifier struct {
 
 // New returns a new OpsGenie notifier.
 func New(c *config.OpsGenieConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
-	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "opsgenie", append(httpOpts, commoncfg.WithHTTP2Disabled())...)
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "opsgenie", httpOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -96,11 +96,11 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 	}
 
 	for _, req := range requests {
-		resp, err := n.client.Do(req)
+		resp, err := notify.Do(n.client, req)
 		if err != nil {
 			return true, err
 		}
-		shouldRetry, err := n.retrier.Check(resp.StatusCode, resp.Body)
+		shouldRetry, err := n.retrier.CheckResponse(resp, resp.Body)
 		notify.Drain(resp)
 		if err != nil {
 			return shouldRetry, err
@@ -167,7 +167,7 @@ func (n *Notifier) createRequests(ctx context.Context, as ...*types.Alert) ([]*h
 		}
 		requests = append(requests, req.WithContext(ctx))
 	default:
-		message, truncated := notify.Truncate(tmpl(n.conf.Message), 130)
+		message, truncated := notify.Truncate(tmpl(n.conf.Message), notify.MaxOpsGenieMessageLen)
 		if truncated {
 			level.Debug(n.logger).Log("msg", "truncated message", "truncated_message", message, "alert", key)
 		}
@@ -204,6 +204,9 @@ func (n *Notifier) createRequests(ctx context.Context, as ...*types.Alert) ([]*h
 			Note:        tmpl(n.conf.Note),
 			Priority:    tmpl(n.conf.Priority),
 		}
+		if m := notify.LookupSeverity(n.conf.SeverityMappings, as...); m != nil && m.OpsGenie != "" {
+			msg.Priority = m.OpsGenie
+		}
 		var buf bytes.Buffer
 		if err := json.NewEncoder(&buf).Encode(msg); err != nil {
 			return nil, false, err
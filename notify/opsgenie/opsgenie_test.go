@@ -263,3 +263,48 @@ func readBody(t *testing.T, r *http.Request) string {
 	require.NoError(t, err)
 	return string(body)
 }
+
+func TestOpsGenieSeverityMapping(t *testing.T) {
+	u, err := url.Parse("https://test-opsgenie-url")
+	require.NoError(t, err)
+	ctx := notify.WithGroupKey(context.Background(), "1")
+
+	notifier, err := New(&config.OpsGenieConfig{
+		Message:    `{{ .CommonLabels.Message }}`,
+		Priority:   "P3",
+		APIKey:     "test-api-key",
+		APIURL:     &config.URL{URL: u},
+		HTTPConfig: &commoncfg.HTTPClientConfig{},
+		SeverityMappings: &config.SeverityMappings{
+			Label: "severity",
+			Mappings: []*config.SeverityMapping{
+				{Severity: "critical", OpsGenie: "P1"},
+				{Severity: "warning", OpsGenie: "P2"},
+			},
+		},
+	}, test.CreateTmpl(t), log.NewNopLogger())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		severities []model.LabelValue
+		priority   string
+	}{
+		{severities: []model.LabelValue{"warning"}, priority: "P2"},
+		{severities: []model.LabelValue{"warning", "critical"}, priority: "P1"},
+		{severities: []model.LabelValue{"info"}, priority: "P3"},
+	} {
+		var alerts []*types.Alert
+		for _, s := range tc.severities {
+			alerts = append(alerts, &types.Alert{
+				Alert: model.Alert{
+					Labels:   model.LabelSet{"severity": s},
+					StartsAt: time.Now(),
+					EndsAt:   time.Now().Add(time.Hour),
+				},
+			})
+		}
+		requests, _, err := notifier.createRequests(ctx, alerts...)
+		require.NoError(t, err)
+		require.Contains(t, readBody(t, requests[0]), fmt.Sprintf(`"priority":%q`, tc.priority))
+	}
+}
@@ -35,7 +35,12 @@ import (
 	"github.com/prometheus/alertmanager/types"
 )
 
-const maxEventSize int = 512000
+const (
+	maxEventSize int = 512000
+	// maxDedupKeyLen is the maximum length of dedup keys accepted by
+	// PagerDuty.
+	maxDedupKeyLen = 255
+)
 
 // Notifier implements a Notifier for PagerDuty notifications.
 type Notifier struct {
@@ -49,7 +54,7 @@ type Notifier struct {
 
 // New returns a new PagerDuty notifier.
 func New(c *config.PagerdutyConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
-	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "pagerduty", append(httpOpts, commoncfg.WithHTTP2Disabled())...)
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "pagerduty", httpOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -141,6 +146,7 @@ func (n *Notifier) notifyV1(
 	ctx context.Context,
 	eventType string,
 	key notify.Key,
+	dedupKey string,
 	data *template.Data,
 	details map[string]string,
 	as ...*types.Alert,
@@ -148,7 +154,7 @@ func (n *Notifier) notifyV1(
 	var tmplErr error
 	tmpl := notify.TmplText(n.tmpl, data, &tmplErr)
 
-	description, truncated := notify.Truncate(tmpl(n.conf.Description), 1024)
+	description, truncated := notify.TruncateMessage(tmpl(n.conf.Description), notify.MaxPagerDutySummaryLen, data)
 	if truncated {
 		level.Debug(n.logger).Log("msg", "Truncated description", "description", description, "key", key)
 	}
@@ -156,7 +162,7 @@ func (n *Notifier) notifyV1(
 	msg := &pagerDutyMessage{
 		ServiceKey:  tmpl(string(n.conf.ServiceKey)),
 		EventType:   eventType,
-		IncidentKey: key.Hash(),
+		IncidentKey: dedupKey,
 		Description: description,
 		Details:     details,
 	}
@@ -186,13 +192,14 @@ func (n *Notifier) notifyV1(
 	}
 	defer notify.Drain(resp)
 
-	return n.retrier.Check(resp.StatusCode, resp.Body)
+	return n.retrier.CheckResponse(resp, resp.Body)
 }
 
 func (n *Notifier) notifyV2(
 	ctx context.Context,
 	eventType string,
 	key notify.Key,
+	dedupKey string,
 	data *template.Data,
 	details map[string]string,
 	as ...*types.Alert,
@@ -204,7 +211,7 @@ func (n *Notifier) notifyV2(
 		n.conf.Severity = "error"
 	}
 
-	summary, truncated := notify.Truncate(tmpl(n.conf.Description), 1024)
+	summary, truncated := notify.TruncateMessage(tmpl(n.conf.Description), notify.MaxPagerDutySummaryLen, data)
 	if truncated {
 		level.Debug(n.logger).Log("msg", "Truncated summary", "summary", summary, "key", key)
 	}
@@ -214,7 +221,7 @@ func (n *Notifier) notifyV2(
 		ClientURL:   tmpl(n.conf.ClientURL),
 		RoutingKey:  tmpl(string(n.conf.RoutingKey)),
 		EventAction: eventType,
-		DedupKey:    key.Hash(),
+		DedupKey:    dedupKey,
 		Images:      make([]pagerDutyImage, 0, len(n.conf.Images)),
 		Links:       make([]pagerDutyLink, 0, len(n.conf.Links)),
 		Payload: &pagerDutyPayload{
@@ -251,6 +258,10 @@ func (n *Notifier) notifyV2(
 		}
 	}
 
+	if m := notify.LookupSeverity(n.conf.SeverityMappings, as...); m != nil && m.PagerDuty != "" {
+		msg.Payload.Severity = m.PagerDuty
+	}
+
 	if tmplErr != nil {
 		return false, errors.Wrap(tmplErr, "failed to template PagerDuty v2 message")
 	}
@@ -271,7 +282,7 @@ func (n *Notifier) notifyV2(
 	}
 	defer notify.Drain(resp)
 
-	return n.retrier.Check(resp.StatusCode, resp.Body)
+	return n.retrier.CheckResponse(resp, resp.Body)
 }
 
 // Notify implements the Notifier interface.
@@ -290,7 +301,11 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 		eventType = pagerDutyEventResolve
 	}
 
-	level.Debug(n.logger).Log("incident", key, "eventType", eventType)
+	dedupKey, err := n.dedupKey(key, data)
+	if err != nil {
+		return false, err
+	}
+	level.Debug(n.logger).Log("incident", key, "dedupKey", dedupKey, "eventType", eventType)
 
 	details := make(map[string]string, len(n.conf.Details))
 	for k, v := range n.conf.Details {
@@ -302,9 +317,29 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 	}
 
 	if n.apiV1 != "" {
-		return n.notifyV1(ctx, eventType, key, data, details, as...)
+		return n.notifyV1(ctx, eventType, key, dedupKey, data, details, as...)
+	}
+	return n.notifyV2(ctx, eventType, key, dedupKey, data, details, as...)
+}
+
+// dedupKey returns the key identifying the incident of the alert group: the
+// hash of the group key, or the configured template. Templated keys longer
+// than PagerDuty accepts are hashed.
+func (n *Notifier) dedupKey(key notify.Key, data *template.Data) (string, error) {
+	if n.conf.DedupKey == "" {
+		return key.Hash(), nil
+	}
+	dedupKey, err := n.tmpl.ExecuteTextString(n.conf.DedupKey, data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to template dedup key")
+	}
+	if dedupKey == "" {
+		return "", errors.New("dedup key cannot be empty")
+	}
+	if len(dedupKey) > maxDedupKeyLen {
+		dedupKey = notify.Key(dedupKey).Hash()
 	}
-	return n.notifyV2(ctx, eventType, key, data, details, as...)
+	return dedupKey, nil
 }
 
 func errDetails(status int, body io.Reader) string {
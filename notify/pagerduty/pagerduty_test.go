@@ -184,6 +184,22 @@ func TestPagerDutyTemplating(t *testing.T) {
 			},
 			errMsg: "failed to template",
 		},
+		{
+			title: "dedup key cannot be empty",
+			cfg: &config.PagerdutyConfig{
+				RoutingKey: config.Secret("01234567890123456789012345678901"),
+				DedupKey:   `{{ .CommonLabels.missing }}`,
+			},
+			errMsg: "dedup key cannot be empty",
+		},
+		{
+			title: "dedup key with templating errors",
+			cfg: &config.PagerdutyConfig{
+				RoutingKey: config.Secret("01234567890123456789012345678901"),
+				DedupKey:   "{{ ",
+			},
+			errMsg: "failed to template dedup key",
+		},
 		{
 			title: "routing key cannot be empty",
 			cfg: &config.PagerdutyConfig{
@@ -233,6 +249,77 @@ func TestPagerDutyTemplating(t *testing.T) {
 	}
 }
 
+func TestPagerDutyDedupKey(t *testing.T) {
+	var msg pagerDutyMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	firing := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "HighLatency", "service": "api"},
+			StartsAt: time.Now(),
+			EndsAt:   time.Now().Add(time.Hour),
+		},
+	}
+	resolved := &types.Alert{
+		Alert: model.Alert{
+			Labels:   firing.Labels,
+			StartsAt: firing.StartsAt,
+			EndsAt:   time.Now().Add(-time.Minute),
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{service=\"api\"}")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"service": "api"})
+
+	for _, tc := range []struct {
+		title    string
+		dedupKey string
+		expected string
+	}{
+		{
+			title:    "hash of the group key by default",
+			expected: notify.Key("{}:{service=\"api\"}").Hash(),
+		},
+		{
+			title:    "templated key",
+			dedupKey: `service/{{ .GroupLabels.service }}`,
+			expected: "service/api",
+		},
+		{
+			title:    "long templated key",
+			dedupKey: strings.Repeat("x", 300) + `{{ .GroupLabels.service }}`,
+			expected: notify.Key(strings.Repeat("x", 300) + "api").Hash(),
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			pd, err := New(&config.PagerdutyConfig{
+				RoutingKey: config.Secret("01234567890123456789012345678901"),
+				DedupKey:   tc.dedupKey,
+				URL:        &config.URL{URL: u},
+				HTTPConfig: &commoncfg.HTTPClientConfig{},
+			}, test.CreateTmpl(t), log.NewNopLogger())
+			require.NoError(t, err)
+
+			// The incident is resolved with the key which triggered it.
+			for _, tt := range []struct {
+				alert  *types.Alert
+				action string
+			}{
+				{firing, pagerDutyEventTrigger},
+				{resolved, pagerDutyEventResolve},
+			} {
+				_, err = pd.Notify(ctx, tt.alert)
+				require.NoError(t, err)
+				require.Equal(t, tt.action, msg.EventAction)
+				require.Equal(t, tc.expected, msg.DedupKey)
+			}
+		})
+	}
+}
+
 func TestErrDetails(t *testing.T) {
 	for _, tc := range []struct {
 		status int
@@ -0,0 +1,158 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin notifies through external programs, so that notifiers can
+// be shipped without changing the Alertmanager.
+//
+// The program is run for every notification. It receives a Request as JSON
+// on its standard input, and may print a Response as JSON on its standard
+// output. The notification succeeded if the program exits with status 0 and
+// its response has no error. If the program fails without a response, the
+// notification is retried.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Version is the version of the protocol between the Alertmanager and the
+// plugins.
+const Version = "1"
+
+// maxErrLen is the maximum length of the standard error of a plugin quoted
+// in errors.
+const maxErrLen = 1024
+
+// Request is the notification sent to a plugin.
+type Request struct {
+	*template.Data
+
+	// Version is the version of the protocol.
+	Version  string `json:"version"`
+	GroupKey string `json:"groupKey"`
+	// Settings are the settings and secret settings of the plugin
+	// configuration, templated.
+	Settings map[string]string `json:"settings"`
+}
+
+// Response is the optional result printed by a plugin.
+type Response struct {
+	// Error fails the notification.
+	Error string `json:"error,omitempty"`
+	// Retry asks for the failed notification to be sent again.
+	Retry bool `json:"retry,omitempty"`
+}
+
+// Notifier implements a Notifier running a plugin.
+type Notifier struct {
+	conf   *config.PluginConfig
+	tmpl   *template.Template
+	logger log.Logger
+}
+
+// New returns a new plugin notifier. The program of the plugin must exist.
+func New(c *config.PluginConfig, t *template.Template, l log.Logger) (*Notifier, error) {
+	if _, err := exec.LookPath(c.Command); err != nil {
+		return nil, errors.Wrap(err, "plugin command")
+	}
+	return &Notifier{conf: c, tmpl: t, logger: l}, nil
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	data := notify.GetTemplateData(ctx, n.tmpl, alerts, n.logger)
+
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		level.Error(n.logger).Log("err", err)
+	}
+
+	var tmplErr error
+	tmpl := notify.TmplText(n.tmpl, data, &tmplErr)
+	settings := make(map[string]string, len(n.conf.Settings)+len(n.conf.SecretSettings))
+	for k, v := range n.conf.Settings {
+		settings[k] = tmpl(v)
+	}
+	for k, v := range n.conf.SecretSettings {
+		settings[k] = tmpl(string(v))
+	}
+	if tmplErr != nil {
+		return false, errors.Wrap(tmplErr, "failed to template plugin settings")
+	}
+
+	req, err := json.Marshal(&Request{
+		Data:     data,
+		Version:  Version,
+		GroupKey: groupKey.String(),
+		Settings: settings,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(n.conf.Timeout))
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, n.conf.Command, n.conf.Args...)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	if ctx.Err() != nil {
+		return true, errors.Wrap(ctx.Err(), "plugin did not complete")
+	}
+
+	var resp *Response
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+		resp = &Response{}
+		if err := json.Unmarshal(out, resp); err != nil {
+			return runErr != nil, errors.Wrap(err, "invalid plugin response")
+		}
+	}
+
+	switch {
+	case runErr != nil && resp == nil:
+		return true, errors.Errorf("plugin failed: %v: %s", runErr, stderrTail(stderr.String()))
+	case runErr != nil:
+		return resp.Retry, errors.Errorf("plugin failed: %v: %s", runErr, resp.Error)
+	case resp != nil && resp.Error != "":
+		return resp.Retry, errors.Errorf("plugin failed: %s", resp.Error)
+	}
+	return false, nil
+}
+
+// stderrTail returns the end of the standard error of a plugin, where the
+// cause of its failure is most likely to be.
+func stderrTail(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > maxErrLen {
+		s = "..." + s[len(s)-maxErrLen:]
+	}
+	return s
+}
@@ -0,0 +1,156 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// TestHelperProcess is not a test: it is the plugin run by the other tests,
+// behaving as told by the argument following "--".
+func TestHelperProcess(t *testing.T) {
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) < 2 {
+		return
+	}
+	defer os.Exit(0)
+
+	var req Request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	switch args[1] {
+	case "echo":
+		// Report the request as an error to check it.
+		b, _ := json.Marshal(req)
+		json.NewEncoder(os.Stdout).Encode(Response{Error: string(b)})
+	case "reject":
+		json.NewEncoder(os.Stdout).Encode(Response{Error: "rejected"})
+	case "crash":
+		fmt.Fprintln(os.Stderr, "crashed")
+		os.Exit(1)
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+}
+
+func helperNotifier(t *testing.T, mode string, c *config.PluginConfig) *Notifier {
+	t.Helper()
+
+	if c == nil {
+		c = &config.PluginConfig{}
+	}
+	c.Command = os.Args[0]
+	c.Args = []string{"-test.run=TestHelperProcess", "--", mode}
+	if c.Timeout == 0 {
+		c.Timeout = model.Duration(10 * time.Second)
+	}
+	n, err := New(c, test.CreateTmpl(t), log.NewNopLogger())
+	require.NoError(t, err)
+	return n
+}
+
+func TestPluginNotify(t *testing.T) {
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "HighLatency"},
+			StartsAt: time.Now(),
+			EndsAt:   time.Now().Add(time.Hour),
+		},
+	}
+
+	n := helperNotifier(t, "ok", nil)
+	retry, err := n.Notify(ctx, alert)
+	require.NoError(t, err)
+	require.False(t, retry)
+
+	n = helperNotifier(t, "echo", &config.PluginConfig{
+		Settings:       map[string]string{"channel": "{{ .CommonLabels.alertname }}"},
+		SecretSettings: map[string]config.Secret{"token": "secret"},
+	})
+	_, err = n.Notify(ctx, alert)
+	require.Error(t, err)
+
+	var req Request
+	require.NoError(t, json.Unmarshal([]byte(err.Error()[len("plugin failed: "):]), &req))
+	require.Equal(t, Version, req.Version)
+	require.Equal(t, "1", req.GroupKey)
+	require.Equal(t, map[string]string{"channel": "HighLatency", "token": "secret"}, req.Settings)
+	require.Equal(t, "firing", req.Status)
+	require.Len(t, req.Alerts, 1)
+}
+
+func TestPluginFailures(t *testing.T) {
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "HighLatency"},
+			StartsAt: time.Now(),
+		},
+	}
+
+	for _, tc := range []struct {
+		mode    string
+		timeout time.Duration
+		retry   bool
+		err     string
+	}{
+		{mode: "reject", retry: false, err: "plugin failed: rejected"},
+		{mode: "crash", retry: true, err: "plugin failed: exit status 1: crashed"},
+		{mode: "hang", timeout: 100 * time.Millisecond, retry: true, err: "plugin did not complete: context deadline exceeded"},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			n := helperNotifier(t, tc.mode, &config.PluginConfig{Timeout: model.Duration(tc.timeout)})
+			retry, err := n.Notify(ctx, alert)
+			require.EqualError(t, err, tc.err)
+			require.Equal(t, tc.retry, retry)
+		})
+	}
+}
+
+func TestPluginMissingCommand(t *testing.T) {
+	_, err := New(&config.PluginConfig{Command: "alertmanager-plugin-does-not-exist"}, test.CreateTmpl(t), log.NewNopLogger())
+	require.Error(t, err)
+}
+
+func TestPluginNotifyLeaksNoSecret(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	n := helperNotifier(t, "hang", &config.PluginConfig{
+		SecretSettings: map[string]config.Secret{"token": "secret"},
+	})
+	test.AssertNotifyLeaksNoSecret(t, ctx, n, "secret")
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pushbullet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Notifier implements a Notifier for Pushbullet notifications.
+type Notifier struct {
+	conf    *config.PushbulletConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+	apiURL  string // for tests.
+}
+
+// New returns a new Pushbullet notifier.
+func New(c *config.PushbulletConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "pushbullet", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:   c,
+		tmpl:   t,
+		logger: l,
+		client: client,
+		// Rate limited requests, answered with status code 429, are
+		// retried by CheckResponse.
+		retrier: &notify.Retrier{},
+		apiURL:  "https://api.pushbullet.com/v2/pushes",
+	}, nil
+}
+
+// push is the payload of the pushes API.
+type push struct {
+	Type       string `json:"type"`
+	Title      string `json:"title,omitempty"`
+	Body       string `json:"body,omitempty"`
+	URL        string `json:"url,omitempty"`
+	DeviceIden string `json:"device_iden,omitempty"`
+	Email      string `json:"email,omitempty"`
+	ChannelTag string `json:"channel_tag,omitempty"`
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		data = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl = notify.TmplText(n.tmpl, data, &err)
+		p    = push{
+			Type:       "note",
+			Title:      tmpl(n.conf.Title),
+			Body:       tmpl(n.conf.Body),
+			URL:        tmpl(n.conf.URL),
+			DeviceIden: tmpl(n.conf.DeviceIden),
+			Email:      tmpl(n.conf.Email),
+			ChannelTag: tmpl(n.conf.ChannelTag),
+		}
+	)
+	if err != nil {
+		return false, fmt.Errorf("templating error: %s", err)
+	}
+	if p.URL != "" {
+		p.Type = "link"
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(p); err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest("POST", n.apiURL, &buf)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Access-Token", string(n.conf.AccessToken))
+
+	level.Debug(n.logger).Log("msg", "Sending push", "incident", key)
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	return n.retrier.CheckResponse(resp, resp.Body)
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pushbullet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestPushbulletRetry(t *testing.T) {
+	notifier, err := New(
+		&config.PushbulletConfig{
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	for statusCode, expected := range test.RetryTests(test.DefaultRetryCodes()) {
+		actual, _ := notifier.retrier.Check(statusCode, nil)
+		require.Equal(t, expected, actual, fmt.Sprintf("error on status %d", statusCode))
+	}
+}
+
+func TestPushbulletRedactedURL(t *testing.T) {
+	ctx, u, fn := test.GetContextWithCancelingURL()
+	defer fn()
+
+	token := "access_token"
+	notifier, err := New(
+		&config.PushbulletConfig{
+			AccessToken: config.Secret(token),
+			HTTPConfig:  &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	notifier.apiURL = u.String()
+
+	test.AssertNotifyLeaksNoSecret(t, ctx, notifier, token)
+}
+
+func TestPushbulletNotify(t *testing.T) {
+	var (
+		token string
+		got   push
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("Access-Token")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "DiskFull", "team": "db"},
+			Annotations: model.LabelSet{"summary": "The disk is full"},
+			StartsAt:    time.Now(),
+			EndsAt:      time.Now().Add(time.Hour),
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "1")
+
+	for _, tc := range []struct {
+		url      string
+		expected push
+	}{
+		{
+			url: `{{ .ExternalURL }}`,
+			expected: push{
+				Type:       "link",
+				Title:      "DiskFull",
+				Body:       "The disk is full",
+				URL:        "http://am",
+				ChannelTag: "db-oncall",
+			},
+		},
+		{
+			expected: push{
+				Type:       "note",
+				Title:      "DiskFull",
+				Body:       "The disk is full",
+				ChannelTag: "db-oncall",
+			},
+		},
+	} {
+		notifier, err := New(
+			&config.PushbulletConfig{
+				AccessToken: config.Secret("token"),
+				ChannelTag:  `{{ .CommonLabels.team }}-oncall`,
+				Title:       `{{ .CommonLabels.alertname }}`,
+				Body:        `{{ .CommonAnnotations.summary }}`,
+				URL:         tc.url,
+				HTTPConfig:  &commoncfg.HTTPClientConfig{},
+			},
+			test.CreateTmpl(t),
+			log.NewNopLogger(),
+		)
+		require.NoError(t, err)
+		notifier.apiURL = srv.URL
+
+		retry, err := notifier.Notify(ctx, alert)
+		require.NoError(t, err)
+		require.False(t, retry)
+		require.Equal(t, "token", token)
+		require.Equal(t, tc.expected, got)
+		got = push{}
+	}
+}
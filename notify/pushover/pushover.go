@@ -43,7 +43,7 @@ type Notifier struct {
 
 // New returns a new Pushover notifier.
 func New(c *config.PushoverConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
-	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "pushover", append(httpOpts, commoncfg.WithHTTP2Disabled())...)
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "pushover", httpOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +78,7 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 	parameters.Add("token", tmpl(string(n.conf.Token)))
 	parameters.Add("user", tmpl(string(n.conf.UserKey)))
 
-	title, truncated := notify.Truncate(tmpl(n.conf.Title), 250)
+	title, truncated := notify.Truncate(tmpl(n.conf.Title), notify.MaxPushoverTitleLen)
 	if truncated {
 		level.Debug(n.logger).Log("msg", "Truncated title", "truncated_title", title, "incident", key)
 	}
@@ -91,7 +91,7 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 		message = tmpl(n.conf.Message)
 	}
 
-	message, truncated = notify.Truncate(message, 1024)
+	message, truncated = notify.TruncateMessage(message, notify.MaxPushoverMessageLen, data)
 	if truncated {
 		level.Debug(n.logger).Log("msg", "Truncated message", "truncated_message", message, "incident", key)
 	}
@@ -102,14 +102,18 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 	}
 	parameters.Add("message", message)
 
-	supplementaryURL, truncated := notify.Truncate(tmpl(n.conf.URL), 512)
+	supplementaryURL, truncated := notify.Truncate(tmpl(n.conf.URL), notify.MaxPushoverURLLen)
 	if truncated {
 		level.Debug(n.logger).Log("msg", "Truncated URL", "truncated_url", supplementaryURL, "incident", key)
 	}
 	parameters.Add("url", supplementaryURL)
 	parameters.Add("url_title", tmpl(n.conf.URLTitle))
 
-	parameters.Add("priority", tmpl(n.conf.Priority))
+	priority := tmpl(n.conf.Priority)
+	if m := notify.LookupSeverity(n.conf.SeverityMappings, as...); m != nil && m.Pushover != "" {
+		priority = m.Pushover
+	}
+	parameters.Add("priority", priority)
 	parameters.Add("retry", fmt.Sprintf("%d", int64(time.Duration(n.conf.Retry).Seconds())))
 	parameters.Add("expire", fmt.Sprintf("%d", int64(time.Duration(n.conf.Expire).Seconds())))
 	parameters.Add("sound", tmpl(n.conf.Sound))
@@ -130,5 +134,5 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 	}
 	defer notify.Drain(resp)
 
-	return n.retrier.Check(resp.StatusCode, nil)
+	return n.retrier.CheckResponse(resp, nil)
 }
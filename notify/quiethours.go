@@ -0,0 +1,295 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// quietHoursKey identifies the queue of an integration of a receiver.
+type quietHoursKey struct {
+	receiver    string
+	integration string
+	idx         int
+}
+
+// QuietHours queues the notifications of receivers during their quiet hours
+// and delivers the queued alerts of each integration as a single digest once
+// the quiet hours end. The queues are kept in memory only.
+type QuietHours struct {
+	receivers func(name string) ([]Integration, bool)
+	logger    log.Logger
+	now       func() time.Time
+
+	digestsTotal       prometheus.Counter
+	digestsFailedTotal prometheus.Counter
+
+	mtx       sync.Mutex
+	confs     map[string]*config.QuietHours
+	muteTimes map[string][]timeinterval.TimeInterval
+	label     model.LabelName
+	ranks     map[string]int
+	// queues holds the latest state of the queued alerts by fingerprint.
+	queues map[quietHoursKey]map[model.Fingerprint]*types.Alert
+}
+
+// NewQuietHours returns a new QuietHours. The receivers function looks up the
+// integrations of a receiver in the current configuration.
+func NewQuietHours(receivers func(name string) ([]Integration, bool), l log.Logger, r prometheus.Registerer) *QuietHours {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	q := &QuietHours{
+		receivers: receivers,
+		logger:    l,
+		now:       time.Now,
+		digestsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_quiet_hours_digests_total",
+			Help: "The total number of digests sent at the end of quiet hours.",
+		}),
+		digestsFailedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_quiet_hours_digests_failed_total",
+			Help: "The total number of digests which failed to be sent at the end of quiet hours.",
+		}),
+		queues: map[quietHoursKey]map[model.Fingerprint]*types.Alert{},
+	}
+	if r != nil {
+		r.MustRegister(q.digestsTotal, q.digestsFailedTotal, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "alertmanager_quiet_hours_queued_alerts",
+			Help: "The number of alerts queued until the end of quiet hours.",
+		}, q.queued))
+	}
+	return q
+}
+
+func (q *QuietHours) queued() float64 {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	var n int
+	for _, alerts := range q.queues {
+		n += len(alerts)
+	}
+	return float64(n)
+}
+
+// Update applies the quiet hours of the receivers of the configuration. The
+// queued alerts are kept, and those of receivers which lost their quiet hours
+// are delivered at the next flush.
+func (q *QuietHours) Update(c *config.Config) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.confs = make(map[string]*config.QuietHours)
+	for _, rcv := range c.Receivers {
+		if rcv.QuietHours != nil {
+			q.confs[rcv.Name] = rcv.QuietHours
+		}
+	}
+	q.muteTimes = make(map[string][]timeinterval.TimeInterval, len(c.MuteTimeIntervals))
+	for _, ti := range c.MuteTimeIntervals {
+		q.muteTimes[ti.Name] = ti.TimeIntervals
+	}
+	q.label = c.Global.SeverityLabel
+	q.ranks = severityRanks(c.Global.SeverityMapping)
+}
+
+// Stage returns a stage which queues the notifications of the integration
+// during the quiet hours of the receiver instead of passing them to deliver.
+// Queued notifications are recorded by setNotifies as if they were
+// delivered, so that the group doesn't notify them again once the quiet
+// hours end.
+func (q *QuietHours) Stage(receiver string, i Integration, deliver, setNotifies Stage) Stage {
+	key := quietHoursKey{receiver: receiver, integration: i.Name(), idx: i.Index()}
+	return StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		now, ok := Now(ctx)
+		if !ok {
+			return ctx, nil, errors.New("missing now timestamp")
+		}
+		if !q.enqueue(key, now, alerts) {
+			return deliver.Exec(ctx, l, alerts...)
+		}
+		level.Debug(l).Log("msg", "Notification queued until the end of the quiet hours", "alerts", len(alerts))
+		return setNotifies.Exec(ctx, l, alerts...)
+	})
+}
+
+// enqueue queues the alerts if the receiver is in its quiet hours and none
+// of the alerts is more severe than the maximum severity.
+func (q *QuietHours) enqueue(key quietHoursKey, now time.Time, alerts []*types.Alert) bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	conf, ok := q.confs[key.receiver]
+	if !ok || !inTimeIntervals(q.muteTimes, conf.TimeIntervals, now) {
+		return false
+	}
+	if conf.MaxSeverity != "" {
+		// Alerts with an unknown severity are never queued.
+		for _, a := range alerts {
+			if !notMoreSevere(q.ranks, q.label, a.Labels, conf.MaxSeverity) {
+				return false
+			}
+		}
+	}
+
+	queue, ok := q.queues[key]
+	if !ok {
+		queue = map[model.Fingerprint]*types.Alert{}
+		q.queues[key] = queue
+	}
+	for _, a := range alerts {
+		// The digest reports the state of the alerts when they were
+		// queued, so firing alerts must not resolve in the meantime.
+		c := *a
+		if !a.ResolvedAt(now) {
+			c.EndsAt = time.Time{}
+		}
+		queue[a.Fingerprint()] = &c
+	}
+	return true
+}
+
+// Run flushes the queues at the given interval until stopc is closed.
+func (q *QuietHours) Run(interval time.Duration, stopc <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			return
+		case <-t.C:
+			q.Flush(context.Background())
+		}
+	}
+}
+
+// Flush sends the digests of the queues whose receiver isn't in its quiet
+// hours anymore. The alerts of digests which failed with a recoverable error
+// are queued again.
+func (q *QuietHours) Flush(ctx context.Context) {
+	now := q.now()
+
+	q.mtx.Lock()
+	due := make(map[quietHoursKey]map[model.Fingerprint]*types.Alert)
+	for key, queue := range q.queues {
+		conf, ok := q.confs[key.receiver]
+		if ok && inTimeIntervals(q.muteTimes, conf.TimeIntervals, now) {
+			continue
+		}
+		due[key] = queue
+		delete(q.queues, key)
+	}
+	q.mtx.Unlock()
+
+	for key, queue := range due {
+		alerts := make(types.AlertSlice, 0, len(queue))
+		for _, a := range queue {
+			alerts = append(alerts, a)
+		}
+		sort.Stable(alerts)
+
+		retry, err := q.notify(ctx, key, alerts, now)
+		if err == nil {
+			continue
+		}
+		q.digestsFailedTotal.Inc()
+		level.Error(q.logger).Log("msg", "Sending quiet hours digest failed", "receiver", key.receiver, "integration", fmt.Sprintf("%s[%d]", key.integration, key.idx), "retry", retry, "err", err)
+		if !retry {
+			continue
+		}
+
+		q.mtx.Lock()
+		requeue, ok := q.queues[key]
+		if !ok {
+			q.queues[key] = queue
+		} else {
+			// Alerts queued since the flush are more recent.
+			for fp, a := range queue {
+				if _, ok := requeue[fp]; !ok {
+					requeue[fp] = a
+				}
+			}
+		}
+		q.mtx.Unlock()
+	}
+}
+
+func (q *QuietHours) notify(ctx context.Context, key quietHoursKey, alerts []*types.Alert, now time.Time) (bool, error) {
+	integrations, _ := q.receivers(key.receiver)
+	retry, err := notifyDigest(ctx, q.logger, integrations, key.receiver, key.integration, key.idx, "quiet_hours/", "quiet hours", alerts, now)
+	if err == nil {
+		q.digestsTotal.Inc()
+	}
+	return retry, err
+}
+
+// notifyDigest sends the alerts queued for an integration of a receiver as
+// a single notification, in a group whose key is the prefix followed by the
+// receiver name.
+func notifyDigest(ctx context.Context, l log.Logger, integrations []Integration, receiver, name string, idx int, groupKeyPrefix, kind string, alerts []*types.Alert, now time.Time) (bool, error) {
+	var integration *Integration
+	for i := range integrations {
+		if integrations[i].Name() == name && integrations[i].Index() == idx {
+			integration = &integrations[i]
+			break
+		}
+	}
+	if integration == nil {
+		return false, fmt.Errorf("integration not found in current configuration, dropping %d alerts", len(alerts))
+	}
+
+	if !integration.SendResolved() {
+		var firing []*types.Alert
+		for _, a := range alerts {
+			if a.Status() != model.AlertResolved {
+				firing = append(firing, a)
+			}
+		}
+		alerts = firing
+	}
+	if len(alerts) == 0 {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, MinTimeout)
+	defer cancel()
+	ctx = WithReceiverName(ctx, receiver)
+	ctx = WithGroupKey(ctx, groupKeyPrefix+receiver)
+	ctx = WithGroupLabels(ctx, model.LabelSet{})
+	ctx = WithNow(ctx, now)
+
+	level.Info(l).Log("msg", "Sending "+kind+" digest", "receiver", receiver, "integration", integration.String(), "alerts", len(alerts))
+	for _, part := range splitAlerts(alerts, integration.MaxAlertsPerMessage()) {
+		if retry, err := integration.Notify(ctx, part...); err != nil {
+			return retry, err
+		}
+	}
+	return false, nil
+}
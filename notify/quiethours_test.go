@@ -0,0 +1,134 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestQuietHours(t *testing.T) {
+	conf, err := config.Load(`
+global:
+  severity_mapping:
+  - severity: critical
+  - severity: warning
+route:
+  receiver: team-X
+mute_time_intervals:
+- name: night
+  time_intervals:
+  - times:
+    - start_time: '00:00'
+      end_time: '06:00'
+receivers:
+- name: team-X
+  quiet_hours:
+    time_intervals: [night]
+    max_severity: warning
+`)
+	require.NoError(t, err)
+
+	var (
+		notified [][]*types.Alert
+		fail     error
+	)
+	integration := NewIntegration(notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		if fail != nil {
+			return true, fail
+		}
+		key, _ := GroupKey(ctx)
+		require.Equal(t, "quiet_hours/team-X", key)
+		notified = append(notified, alerts)
+		return false, nil
+	}), sendResolved(true), "webhook", 0)
+
+	q := NewQuietHours(func(name string) ([]Integration, bool) {
+		return []Integration{integration}, name == "team-X"
+	}, nil, nil)
+	q.Update(conf)
+
+	var delivered, recorded int
+	stage := q.Stage("team-X", integration,
+		StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+			delivered++
+			return ctx, alerts, nil
+		}),
+		StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+			recorded++
+			return ctx, alerts, nil
+		}),
+	)
+
+	night := time.Date(2021, 1, 1, 3, 0, 0, 0, time.UTC)
+	warning := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "a", "severity": "warning"},
+		StartsAt: night.Add(-time.Minute),
+		EndsAt:   night.Add(5 * time.Minute),
+	}}
+	critical := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b", "severity": "critical"}}}
+
+	// Notifications are delivered right away outside of the quiet hours, and
+	// when one of their alerts is more severe than the maximum severity.
+	ctx := WithNow(context.Background(), night.Add(6*time.Hour))
+	_, _, err = stage.Exec(ctx, log.NewNopLogger(), warning)
+	require.NoError(t, err)
+	ctx = WithNow(context.Background(), night)
+	_, _, err = stage.Exec(ctx, log.NewNopLogger(), warning, critical)
+	require.NoError(t, err)
+	require.Equal(t, 2, delivered)
+	require.Equal(t, 0, recorded)
+
+	// Other notifications are queued and recorded as notified.
+	_, _, err = stage.Exec(ctx, log.NewNopLogger(), warning)
+	require.NoError(t, err)
+	require.Equal(t, 2, delivered)
+	require.Equal(t, 1, recorded)
+
+	_, _, err = stage.Exec(context.Background(), log.NewNopLogger(), warning)
+	require.Error(t, err)
+
+	// Nothing is sent during the quiet hours.
+	q.now = func() time.Time { return night.Add(time.Hour) }
+	q.Flush(context.Background())
+	require.Len(t, notified, 0)
+
+	// A failed digest is kept for the next flush.
+	q.now = func() time.Time { return night.Add(4 * time.Hour) }
+	fail = errors.New("unavailable")
+	q.Flush(context.Background())
+	require.Len(t, notified, 0)
+	require.Equal(t, float64(1), q.queued())
+
+	// The digest reports the alerts as they were queued.
+	fail = nil
+	q.Flush(context.Background())
+	require.Len(t, notified, 1)
+	require.Len(t, notified[0], 1)
+	require.Equal(t, warning.Labels, notified[0][0].Labels)
+	require.Equal(t, model.AlertFiring, notified[0][0].Status())
+	require.Equal(t, float64(0), q.queued())
+
+	q.Flush(context.Background())
+	require.Len(t, notified, 1)
+}
@@ -0,0 +1,168 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// routeDedupKey identifies a set of alerts notified by an integration of a
+// receiver.
+type routeDedupKey struct {
+	receiver    string
+	integration string
+	idx         int
+	alerts      [sha256.Size]byte
+}
+
+type routeDedupEntry struct {
+	groupKey string
+	until    time.Time
+}
+
+// RouteDedup suppresses the notifications of a receiver whose alerts were
+// already notified to the receiver by another aggregation group, typically
+// of another route, within the dedup window of the receiver. The notified
+// alert sets are kept in memory only.
+type RouteDedup struct {
+	suppressedTotal *prometheus.CounterVec
+
+	mtx     sync.Mutex
+	windows map[string]time.Duration
+	sent    map[routeDedupKey]routeDedupEntry
+}
+
+// NewRouteDedup returns a new RouteDedup.
+func NewRouteDedup(r prometheus.Registerer) *RouteDedup {
+	d := &RouteDedup{
+		suppressedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alertmanager_notifications_route_deduplicated_total",
+			Help: "The total number of notifications suppressed because they were already sent through another route.",
+		}, []string{"receiver"}),
+		sent: map[routeDedupKey]routeDedupEntry{},
+	}
+	if r != nil {
+		r.MustRegister(d.suppressedTotal)
+	}
+	return d
+}
+
+// Update applies the dedup windows of the receivers of the configuration.
+func (d *RouteDedup) Update(c *config.Config) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.windows = make(map[string]time.Duration)
+	for _, rcv := range c.Receivers {
+		if rcv.DedupWindow > 0 {
+			d.windows[rcv.Name] = time.Duration(rcv.DedupWindow)
+		}
+	}
+}
+
+// Stage returns a stage which passes the notifications of the integration
+// to deliver unless the same alerts were delivered by another group within
+// the dedup window of the receiver. Suppressed notifications are recorded
+// by setNotifies as if they were delivered, so that the other members of
+// the cluster don't send them either.
+func (d *RouteDedup) Stage(receiver string, i Integration, deliver, setNotifies Stage) Stage {
+	return StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+		d.mtx.Lock()
+		window := d.windows[receiver]
+		d.mtx.Unlock()
+		if window <= 0 {
+			return deliver.Exec(ctx, l, alerts...)
+		}
+
+		gkey, ok := GroupKey(ctx)
+		if !ok {
+			return deliver.Exec(ctx, l, alerts...)
+		}
+		firing, ok := FiringAlerts(ctx)
+		if !ok {
+			return deliver.Exec(ctx, l, alerts...)
+		}
+		resolved, ok := ResolvedAlerts(ctx)
+		if !ok {
+			return deliver.Exec(ctx, l, alerts...)
+		}
+		now, ok := Now(ctx)
+		if !ok {
+			now = time.Now()
+		}
+
+		key := routeDedupKey{
+			receiver:    receiver,
+			integration: i.Name(),
+			idx:         i.Index(),
+			alerts:      hashAlertSet(firing, resolved),
+		}
+		d.mtx.Lock()
+		e, ok := d.sent[key]
+		d.mtx.Unlock()
+		if ok && e.groupKey != gkey && now.Before(e.until) {
+			d.suppressedTotal.WithLabelValues(receiver).Inc()
+			level.Debug(l).Log("msg", "Notification already sent through another route", "alerts", len(alerts))
+			return setNotifies.Exec(ctx, l, alerts...)
+		}
+
+		ctx, res, err := deliver.Exec(ctx, l, alerts...)
+		if err != nil {
+			return ctx, res, err
+		}
+
+		d.mtx.Lock()
+		for k, e := range d.sent {
+			if !now.Before(e.until) {
+				delete(d.sent, k)
+			}
+		}
+		d.sent[key] = routeDedupEntry{groupKey: gkey, until: now.Add(window)}
+		d.mtx.Unlock()
+
+		return ctx, res, nil
+	})
+}
+
+// hashAlertSet returns a hash of the firing and resolved alert hashes which
+// doesn't depend on their order.
+func hashAlertSet(firing, resolved []uint64) [sha256.Size]byte {
+	h := sha256.New()
+	b := make([]byte, 8)
+	for _, hashes := range [][]uint64{firing, resolved} {
+		sorted := append([]uint64(nil), hashes...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		binary.BigEndian.PutUint64(b, uint64(len(sorted)))
+		h.Write(b)
+		for _, v := range sorted {
+			binary.BigEndian.PutUint64(b, v)
+			h.Write(b)
+		}
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestRouteDedup(t *testing.T) {
+	conf, err := config.Load(`
+route:
+  receiver: team-X
+receivers:
+- name: team-X
+  dedup_window: 10m
+- name: team-Y
+`)
+	require.NoError(t, err)
+
+	d := NewRouteDedup(nil)
+	d.Update(conf)
+
+	integration := NewIntegration(nil, sendResolved(true), "webhook", 0)
+	var (
+		delivered, recorded int
+		fail                error
+	)
+	stage := func(receiver string) Stage {
+		return d.Stage(receiver, integration,
+			StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+				delivered++
+				return ctx, alerts, fail
+			}),
+			StageFunc(func(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+				recorded++
+				return ctx, alerts, nil
+			}),
+		)
+	}
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	exec := func(receiver, groupKey string, at time.Duration, firing ...uint64) error {
+		ctx := WithGroupKey(context.Background(), groupKey)
+		ctx = WithNow(ctx, now.Add(at))
+		ctx = WithFiringAlerts(ctx, firing)
+		ctx = WithResolvedAlerts(ctx, []uint64{})
+		_, _, err := stage(receiver).Exec(ctx, log.NewNopLogger())
+		return err
+	}
+
+	// A failed delivery doesn't suppress the other routes.
+	fail = errors.New("failed")
+	require.Error(t, exec("team-X", "route-a", 0, 1, 2))
+	fail = nil
+
+	require.NoError(t, exec("team-X", "route-a", 0, 1, 2))
+	// The same alerts are suppressed for other groups within the window,
+	// whatever their order, but not for the group which sent them.
+	require.NoError(t, exec("team-X", "route-b", time.Minute, 2, 1))
+	require.NoError(t, exec("team-X", "route-a", time.Minute, 1, 2))
+	require.Equal(t, 3, delivered)
+	require.Equal(t, 1, recorded)
+
+	// Other alerts, other receivers and later notifications are delivered.
+	require.NoError(t, exec("team-X", "route-b", 2*time.Minute, 1))
+	require.NoError(t, exec("team-Y", "route-a", 2*time.Minute, 3))
+	require.NoError(t, exec("team-Y", "route-b", 2*time.Minute, 3))
+	require.NoError(t, exec("team-X", "route-b", 11*time.Minute, 1, 2))
+	require.Equal(t, 7, delivered)
+	require.Equal(t, 1, recorded)
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Redacted replaces the matches of scrubbing detectors and patterns.
+const Redacted = "[REDACTED]"
+
+// ScrubStage replaces the secrets and personal data found in the annotations
+// of the alerts with Redacted, before the notifications are rendered. The
+// alerts are copied, so that the stored alerts are left unchanged.
+type ScrubStage struct {
+	regexps     []*regexp.Regexp
+	annotations map[model.LabelName]struct{}
+}
+
+// NewScrubStage returns a new ScrubStage for the configuration, or nil if
+// scrubbing is not configured.
+func NewScrubStage(c *config.ScrubbingConfig) *ScrubStage {
+	if c == nil {
+		return nil
+	}
+	s := &ScrubStage{}
+	for _, d := range c.Detectors {
+		s.regexps = append(s.regexps, regexp.MustCompile(config.ScrubbingDetectors[d]))
+	}
+	// The patterns are validated when loading the configuration.
+	for _, p := range c.Patterns {
+		s.regexps = append(s.regexps, regexp.MustCompile(p))
+	}
+	if len(c.Annotations) > 0 {
+		s.annotations = make(map[model.LabelName]struct{}, len(c.Annotations))
+		for _, a := range c.Annotations {
+			s.annotations[model.LabelName(a)] = struct{}{}
+		}
+	}
+	return s
+}
+
+// Exec implements the Stage interface.
+func (s *ScrubStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	var (
+		res      = make([]*types.Alert, 0, len(alerts))
+		scrubbed int
+	)
+	for _, a := range alerts {
+		var annotations model.LabelSet
+		for k, v := range a.Annotations {
+			if s.annotations != nil {
+				if _, ok := s.annotations[k]; !ok {
+					continue
+				}
+			}
+			nv := s.scrub(string(v))
+			if nv == string(v) {
+				continue
+			}
+			if annotations == nil {
+				annotations = a.Annotations.Clone()
+			}
+			annotations[k] = model.LabelValue(nv)
+			scrubbed++
+		}
+		if annotations == nil {
+			res = append(res, a)
+			continue
+		}
+		na := *a
+		na.Annotations = annotations
+		res = append(res, &na)
+	}
+	if scrubbed > 0 {
+		level.Debug(l).Log("msg", "Scrubbed annotations", "annotations", scrubbed)
+	}
+	return ctx, res, nil
+}
+
+func (s *ScrubStage) scrub(v string) string {
+	for _, re := range s.regexps {
+		v = re.ReplaceAllLiteralString(v, Redacted)
+	}
+	return v
+}
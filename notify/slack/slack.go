@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	commoncfg "github.com/prometheus/common/config"
 
 	"github.com/prometheus/alertmanager/config"
@@ -43,7 +44,7 @@ type Notifier struct {
 
 // New returns a new Slack notification handler.
 func New(c *config.SlackConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
-	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "slack", append(httpOpts, commoncfg.WithHTTP2Disabled())...)
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "slack", httpOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +111,10 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 		Color:      tmplText(n.conf.Color),
 		MrkdwnIn:   markdownIn,
 	}
+	if text, truncated := notify.TruncateMessage(att.Text, notify.MaxSlackTextLen, data); truncated {
+		level.Debug(n.logger).Log("msg", "Truncated text", "text", text)
+		att.Text = text
+	}
 
 	var numFields = len(n.conf.Fields)
 	if numFields > 0 {
@@ -194,10 +199,12 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 	}
 	defer notify.Drain(resp)
 
-	// Only 5xx response codes are recoverable and 2xx codes are successful.
+	// Only 5xx response codes and 429 rate limiting responses are
+	// recoverable and 2xx codes are successful.
+	// https://api.slack.com/docs/rate-limits
 	// https://api.slack.com/incoming-webhooks#handling_errors
 	// https://api.slack.com/changelog/2016-05-17-changes-to-errors-for-incoming-webhooks
-	retry, err := n.retrier.Check(resp.StatusCode, resp.Body)
+	retry, err := n.retrier.CheckResponse(resp, resp.Body)
 	err = errors.Wrap(err, fmt.Sprintf("channel %q", req.Channel))
 	return retry, err
 }
@@ -15,6 +15,7 @@ package sns
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -48,7 +49,7 @@ type Notifier struct {
 
 // New returns a new SNS notification handler.
 func New(c *config.SNSConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
-	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "sns", append(httpOpts, commoncfg.WithHTTP2Disabled())...)
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "sns", httpOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +69,20 @@ func (n *Notifier) Notify(ctx context.Context, alert ...*types.Alert) (bool, err
 		tmpl = notify.TmplText(n.tmpl, data, &err)
 	)
 
+	publishInput, err := createPublishInput(ctx, n, tmpl)
+	if err != nil {
+		return true, err
+	}
+
+	if record, ok := notify.DryRun(ctx); ok {
+		b, err := json.Marshal(publishInput)
+		if err != nil {
+			return false, err
+		}
+		record("application/json", b)
+		return false, nil
+	}
+
 	client, err := createSNSClient(n.client, n, tmpl)
 	if err != nil {
 		if e, ok := err.(awserr.RequestFailure); ok {
@@ -77,11 +92,6 @@ func (n *Notifier) Notify(ctx context.Context, alert ...*types.Alert) (bool, err
 		}
 	}
 
-	publishInput, err := createPublishInput(ctx, n, tmpl)
-	if err != nil {
-		return true, err
-	}
-
 	publishOutput, err := client.Publish(publishInput)
 	if err != nil {
 		if e, ok := err.(awserr.RequestFailure); ok {
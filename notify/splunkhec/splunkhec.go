@@ -0,0 +1,120 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Notifier implements a Notifier for the HTTP Event Collector of Splunk.
+type Notifier struct {
+	conf    *config.SplunkHECConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+}
+
+// New returns a new Splunk HEC notifier.
+func New(c *config.SplunkHECConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "splunk_hec", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:    c,
+		tmpl:    t,
+		logger:  l,
+		client:  client,
+		retrier: &notify.Retrier{},
+	}, nil
+}
+
+// event is the payload of the event endpoint.
+type event struct {
+	Time       float64     `json:"time"`
+	Index      string      `json:"index,omitempty"`
+	Source     string      `json:"source,omitempty"`
+	Sourcetype string      `json:"sourcetype,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	data := notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+	var body interface{} = &webhook.Message{
+		Version:  "4",
+		Data:     data,
+		GroupKey: key.String(),
+	}
+	if n.conf.Event != "" {
+		text := notify.TmplText(n.tmpl, data, &err)(n.conf.Event)
+		if err != nil {
+			return false, fmt.Errorf("templating error: %s", err)
+		}
+		// Events rendering to JSON are indexed as such, so that Splunk
+		// extracts their fields.
+		body = text
+		if json.Valid([]byte(text)) {
+			body = json.RawMessage(text)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(event{
+		Time:       float64(time.Now().UnixNano()) / 1e9,
+		Index:      n.conf.Index,
+		Source:     n.conf.Source,
+		Sourcetype: n.conf.Sourcetype,
+		Event:      body,
+	}); err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest("POST", n.conf.Endpoint.String(), &buf)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Splunk "+string(n.conf.Token))
+
+	level.Debug(n.logger).Log("msg", "Sending event", "incident", key)
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	return n.retrier.CheckResponse(resp, resp.Body)
+}
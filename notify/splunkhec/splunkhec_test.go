@@ -0,0 +1,156 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestSplunkHECRetry(t *testing.T) {
+	notifier, err := New(
+		&config.SplunkHECConfig{
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	for statusCode, expected := range test.RetryTests(test.DefaultRetryCodes()) {
+		actual, _ := notifier.retrier.Check(statusCode, nil)
+		require.Equal(t, expected, actual, fmt.Sprintf("error on status %d", statusCode))
+	}
+}
+
+func TestSplunkHECRedactedURL(t *testing.T) {
+	ctx, u, fn := test.GetContextWithCancelingURL()
+	defer fn()
+
+	secret := "secret"
+	notifier, err := New(
+		&config.SplunkHECConfig{
+			Endpoint:   &config.URL{URL: u},
+			Token:      config.Secret(secret),
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	test.AssertNotifyLeaksNoSecret(t, ctx, notifier, secret)
+}
+
+func TestSplunkHECNotify(t *testing.T) {
+	var (
+		auth string
+		got  map[string]interface{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		got = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL + "/services/collector/event")
+	require.NoError(t, err)
+
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "DiskFull"},
+			Annotations: model.LabelSet{"summary": "The disk is full"},
+			StartsAt:    time.Now(),
+			EndsAt:      time.Now().Add(time.Hour),
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	ctx = notify.WithReceiverName(ctx, "splunk")
+
+	for _, tc := range []struct {
+		event    string
+		expected interface{}
+	}{
+		{
+			event:    `{{ .CommonAnnotations.summary }}`,
+			expected: "The disk is full",
+		},
+		{
+			event:    `{"alertname": "{{ .CommonLabels.alertname }}", "count": {{ len .Alerts }}}`,
+			expected: map[string]interface{}{"alertname": "DiskFull", "count": float64(1)},
+		},
+	} {
+		notifier, err := New(
+			&config.SplunkHECConfig{
+				Endpoint:   &config.URL{URL: u},
+				Token:      config.Secret("token"),
+				Index:      "alerts",
+				Source:     "alertmanager",
+				Sourcetype: "_json",
+				Event:      tc.event,
+				HTTPConfig: &commoncfg.HTTPClientConfig{},
+			},
+			test.CreateTmpl(t),
+			log.NewNopLogger(),
+		)
+		require.NoError(t, err)
+
+		retry, err := notifier.Notify(ctx, alert)
+		require.NoError(t, err)
+		require.False(t, retry)
+		require.Equal(t, "Splunk token", auth)
+		require.Equal(t, "alerts", got["index"])
+		require.Equal(t, "alertmanager", got["source"])
+		require.Equal(t, "_json", got["sourcetype"])
+		require.NotZero(t, got["time"])
+		require.Equal(t, tc.expected, got["event"])
+	}
+
+	// Without a template, the event is the webhook message.
+	notifier, err := New(
+		&config.SplunkHECConfig{
+			Endpoint:   &config.URL{URL: u},
+			Token:      config.Secret("token"),
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	_, err = notifier.Notify(ctx, alert)
+	require.NoError(t, err)
+	_, ok := got["index"]
+	require.False(t, ok)
+	ev := got["event"].(map[string]interface{})
+	require.Equal(t, "4", ev["version"])
+	require.Equal(t, "splunk", ev["receiver"])
+	require.Equal(t, "firing", ev["status"])
+	require.Len(t, ev["alerts"], 1)
+}
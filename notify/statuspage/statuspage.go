@@ -0,0 +1,182 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// The statuses of components and incidents set by the notifier.
+const (
+	componentOperational  = "operational"
+	incidentInvestigating = "investigating"
+	incidentResolved      = "resolved"
+)
+
+// Notifier implements a Notifier for Statuspage components and incidents.
+type Notifier struct {
+	conf    *config.StatuspageConfig
+	tmpl    *template.Template
+	logger  log.Logger
+	client  *http.Client
+	retrier *notify.Retrier
+}
+
+// New returns a new Statuspage notifier.
+func New(c *config.StatuspageConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "statuspage", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		conf:    c,
+		tmpl:    t,
+		logger:  l,
+		client:  client,
+		retrier: &notify.Retrier{},
+	}, nil
+}
+
+// incident is an incident of the Statuspage API. The metadata of the
+// incidents opened by the notifier hold the ID of their alert group.
+type incident struct {
+	ID           string                       `json:"id,omitempty"`
+	Name         string                       `json:"name,omitempty"`
+	Status       string                       `json:"status,omitempty"`
+	Body         string                       `json:"body,omitempty"`
+	ComponentIDs []string                     `json:"component_ids,omitempty"`
+	Components   map[string]string            `json:"components,omitempty"`
+	Metadata     map[string]map[string]string `json:"metadata,omitempty"`
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		resolved = types.Alerts(as...).Status() == model.AlertResolved
+		data     = notify.GetTemplateData(ctx, n.tmpl, as, n.logger)
+		tmpl     = notify.TmplText(n.tmpl, data, &err)
+
+		componentID     = tmpl(n.conf.ComponentID)
+		componentStatus = tmpl(n.conf.ComponentStatus)
+		name            = tmpl(n.conf.IncidentName)
+		body            = tmpl(n.conf.IncidentBody)
+	)
+	if err != nil {
+		return false, fmt.Errorf("templating error: %s", err)
+	}
+	if resolved {
+		componentStatus = componentOperational
+	}
+
+	if !n.conf.CreateIncident {
+		if componentID == "" {
+			level.Debug(n.logger).Log("msg", "No component to update", "key", key)
+			return false, nil
+		}
+		req := map[string]map[string]string{"component": {"status": componentStatus}}
+		return n.request(ctx, http.MethodPatch, "components/"+componentID, req, nil)
+	}
+
+	// The notifications of a group are sent again until it is resolved, so
+	// the open incident of the group is looked up first. In dry-run mode,
+	// the group is assumed to have none.
+	var open []incident
+	if _, ok := notify.DryRun(ctx); !ok {
+		if retry, err := n.request(ctx, http.MethodGet, "incidents/unresolved", nil, &open); err != nil {
+			return retry, err
+		}
+	}
+	var current *incident
+	for i := range open {
+		if open[i].Metadata["alertmanager"]["group_id"] == key.Hash() {
+			current = &open[i]
+			break
+		}
+	}
+
+	inc := incident{Body: body}
+	if componentID != "" {
+		inc.ComponentIDs = []string{componentID}
+		inc.Components = map[string]string{componentID: componentStatus}
+	}
+	switch {
+	case current == nil && resolved:
+		return false, nil
+	case current == nil:
+		inc.Name = name
+		inc.Status = incidentInvestigating
+		inc.Metadata = map[string]map[string]string{"alertmanager": {"group_id": key.Hash()}}
+		return n.request(ctx, http.MethodPost, "incidents", map[string]incident{"incident": inc}, nil)
+	case resolved:
+		inc.Status = incidentResolved
+		return n.request(ctx, http.MethodPatch, "incidents/"+current.ID, map[string]incident{"incident": inc}, nil)
+	}
+	return false, nil
+}
+
+// request sends a request to the given path of the API of the page and
+// decodes the response into out, if not nil.
+func (n *Notifier) request(ctx context.Context, method, path string, in, out interface{}) (bool, error) {
+	u := n.conf.APIURL.Copy()
+	u.Path = fmt.Sprintf("%s/pages/%s/%s", strings.TrimSuffix(u.Path, "/"), n.conf.PageID, path)
+
+	var buf bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&buf).Encode(in); err != nil {
+			return false, err
+		}
+	}
+	req, err := http.NewRequest(method, u.String(), &buf)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "OAuth "+string(n.conf.APIKey))
+
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
+	if err != nil {
+		return true, notify.RedactURL(err)
+	}
+	defer notify.Drain(resp)
+
+	if retry, err := n.retrier.CheckResponse(resp, resp.Body); err != nil {
+		return retry, err
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return true, fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return false, nil
+}
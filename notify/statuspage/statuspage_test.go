@@ -0,0 +1,165 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestStatuspageRetry(t *testing.T) {
+	notifier, err := New(
+		&config.StatuspageConfig{
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	for statusCode, expected := range test.RetryTests(test.DefaultRetryCodes()) {
+		actual, _ := notifier.retrier.Check(statusCode, nil)
+		require.Equal(t, expected, actual, fmt.Sprintf("error on status %d", statusCode))
+	}
+}
+
+// request is a request received by the fake Statuspage API.
+type request struct {
+	method, path, auth string
+	body               map[string]map[string]interface{}
+}
+
+func newServer(t *testing.T, unresolved []incident) (*httptest.Server, *[]request) {
+	var reqs []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.Path, auth: r.Header.Get("Authorization")}
+		if r.Method == http.MethodGet {
+			require.NoError(t, json.NewEncoder(w).Encode(unresolved))
+		} else {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req.body))
+		}
+		reqs = append(reqs, req)
+	}))
+	return srv, &reqs
+}
+
+func newNotifier(t *testing.T, srv *httptest.Server, createIncident bool) *Notifier {
+	u, err := url.Parse(srv.URL + "/v1/")
+	require.NoError(t, err)
+	notifier, err := New(
+		&config.StatuspageConfig{
+			APIURL:          &config.URL{URL: u},
+			APIKey:          config.Secret("key"),
+			PageID:          "page",
+			ComponentID:     `{{ .CommonLabels.component }}`,
+			ComponentStatus: "partial_outage",
+			CreateIncident:  createIncident,
+			IncidentName:    `{{ .CommonLabels.alertname }}`,
+			HTTPConfig:      &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	return notifier
+}
+
+func newAlert(endsAt time.Time) *types.Alert {
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "APIDown", "component": "c1"},
+			StartsAt: time.Now().Add(-time.Hour),
+			EndsAt:   endsAt,
+		},
+	}
+}
+
+func TestStatuspageComponent(t *testing.T) {
+	srv, reqs := newServer(t, nil)
+	defer srv.Close()
+	notifier := newNotifier(t, srv, false)
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	_, err := notifier.Notify(ctx, newAlert(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	_, err = notifier.Notify(ctx, newAlert(time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+
+	require.Equal(t, []request{
+		{
+			method: http.MethodPatch, path: "/v1/pages/page/components/c1", auth: "OAuth key",
+			body: map[string]map[string]interface{}{"component": {"status": "partial_outage"}},
+		},
+		{
+			method: http.MethodPatch, path: "/v1/pages/page/components/c1", auth: "OAuth key",
+			body: map[string]map[string]interface{}{"component": {"status": "operational"}},
+		},
+	}, *reqs)
+}
+
+func TestStatuspageIncident(t *testing.T) {
+	key := notify.Key("1")
+	open := []incident{
+		{ID: "other", Metadata: map[string]map[string]string{"alertmanager": {"group_id": notify.Key("2").Hash()}}},
+	}
+
+	// A firing group without an open incident opens one.
+	srv, reqs := newServer(t, open)
+	defer srv.Close()
+	ctx := notify.WithGroupKey(context.Background(), string(key))
+	_, err := newNotifier(t, srv, true).Notify(ctx, newAlert(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.Len(t, *reqs, 2)
+	require.Equal(t, "/v1/pages/page/incidents/unresolved", (*reqs)[0].path)
+	require.Equal(t, http.MethodPost, (*reqs)[1].method)
+	require.Equal(t, "/v1/pages/page/incidents", (*reqs)[1].path)
+	inc := (*reqs)[1].body["incident"]
+	require.Equal(t, "APIDown", inc["name"])
+	require.Equal(t, "investigating", inc["status"])
+	require.Equal(t, map[string]interface{}{"c1": "partial_outage"}, inc["components"])
+	require.Equal(t, map[string]interface{}{"alertmanager": map[string]interface{}{"group_id": key.Hash()}}, inc["metadata"])
+
+	// The open incident of the group is resolved with it, and isn't opened
+	// again while the group fires.
+	open = append(open, incident{ID: "mine", Metadata: map[string]map[string]string{"alertmanager": {"group_id": key.Hash()}}})
+	srv2, reqs := newServer(t, open)
+	defer srv2.Close()
+	notifier := newNotifier(t, srv2, true)
+	_, err = notifier.Notify(ctx, newAlert(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.Len(t, *reqs, 1)
+	_, err = notifier.Notify(ctx, newAlert(time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+	require.Len(t, *reqs, 3)
+	require.Equal(t, http.MethodPatch, (*reqs)[2].method)
+	require.Equal(t, "/v1/pages/page/incidents/mine", (*reqs)[2].path)
+	inc = (*reqs)[2].body["incident"]
+	require.Equal(t, "resolved", inc["status"])
+	require.Equal(t, map[string]interface{}{"c1": "operational"}, inc["components"])
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package stdout prints notifications as JSON lines on the standard output,
+// so that tests can assert on what would be sent.
+package stdout
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/go-kit/log"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// mtx keeps the lines of concurrent notifications from interleaving.
+var mtx sync.Mutex
+
+// Notifier implements a Notifier printing to the standard output.
+type Notifier struct {
+	conf   *config.StdoutConfig
+	tmpl   *template.Template
+	logger log.Logger
+	out    io.Writer
+}
+
+// New returns a new stdout notifier.
+func New(c *config.StdoutConfig, t *template.Template, l log.Logger) *Notifier {
+	return &Notifier{conf: c, tmpl: t, logger: l, out: os.Stdout}
+}
+
+// Notify implements the Notifier interface. The line is the payload of the
+// webhook notifier.
+func (n *Notifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	msg := &webhook.Message{
+		Version:  "4",
+		Data:     notify.GetTemplateData(ctx, n.tmpl, alerts, n.logger),
+		GroupKey: groupKey.String(),
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+	if record, ok := notify.DryRun(ctx); ok {
+		record("application/json", b)
+		return false, nil
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	_, err = n.out.Write(append(b, '\n'))
+	return false, err
+}
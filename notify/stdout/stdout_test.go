@@ -0,0 +1,82 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package stdout
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/test"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestStdout(t *testing.T) {
+	var out bytes.Buffer
+	n := New(&config.DefaultStdoutConfig, test.CreateTmpl(t), log.NewNopLogger())
+	n.out = &out
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	ctx = notify.WithReceiverName(ctx, "ci")
+	retry, err := n.Notify(ctx, &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "DiskFull"}}})
+	require.NoError(t, err)
+	require.False(t, retry)
+
+	line, err := out.ReadBytes('\n')
+	require.NoError(t, err)
+	require.Equal(t, 0, out.Len())
+	var msg webhook.Message
+	require.NoError(t, json.Unmarshal(line, &msg))
+	require.Equal(t, "4", msg.Version)
+	require.Equal(t, "1", msg.GroupKey)
+	require.Equal(t, "ci", msg.Receiver)
+	require.Equal(t, "firing", msg.Status)
+	require.Len(t, msg.Alerts, 1)
+	require.Equal(t, "DiskFull", msg.Alerts[0].Labels["alertname"])
+}
+
+func TestStdoutConcurrentLines(t *testing.T) {
+	var out bytes.Buffer
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		n := New(&config.DefaultStdoutConfig, test.CreateTmpl(t), log.NewNopLogger())
+		n.out = &out
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := n.Notify(ctx, &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "DiskFull"}}})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	var lines int
+	s := bufio.NewScanner(&out)
+	for s.Scan() {
+		var msg webhook.Message
+		require.NoError(t, json.Unmarshal(s.Bytes(), &msg))
+		lines++
+	}
+	require.Equal(t, 10, lines)
+}
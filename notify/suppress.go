@@ -0,0 +1,134 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// SuppressStage removes the alerts matched by a suppression rule while one of
+// the rule's time intervals is active, unless they are more severe than the
+// rule's maximum severity.
+type SuppressStage struct {
+	rules     []*config.SuppressionRule
+	muteTimes map[string][]timeinterval.TimeInterval
+	label     model.LabelName
+	// ranks maps severities to their position in the severity mapping, the
+	// most severe one first.
+	ranks map[string]int
+}
+
+// NewSuppressStage returns a new SuppressStage. The severities are ordered
+// from the most to the least severe.
+func NewSuppressStage(
+	rules []*config.SuppressionRule,
+	muteTimes map[string][]timeinterval.TimeInterval,
+	severityLabel model.LabelName,
+	severities []*config.SeverityMapping,
+) *SuppressStage {
+	return &SuppressStage{
+		rules:     rules,
+		muteTimes: muteTimes,
+		label:     severityLabel,
+		ranks:     severityRanks(severities),
+	}
+}
+
+// severityRanks maps the severities of a severity mapping to their position,
+// the most severe one first.
+func severityRanks(severities []*config.SeverityMapping) map[string]int {
+	ranks := make(map[string]int, len(severities))
+	for i, m := range severities {
+		ranks[m.Severity] = i
+	}
+	return ranks
+}
+
+// notMoreSevere returns true if the severity of the label set is ranked and
+// not more severe than max.
+func notMoreSevere(ranks map[string]int, label model.LabelName, lset model.LabelSet, max string) bool {
+	rank, ok := ranks[string(lset[label])]
+	return ok && rank >= ranks[max]
+}
+
+// Exec implements the Stage interface.
+func (s *SuppressStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	if len(s.rules) == 0 {
+		return ctx, alerts, nil
+	}
+	now, ok := Now(ctx)
+	if !ok {
+		return ctx, alerts, errors.New("missing now timestamp")
+	}
+
+	var (
+		filtered   []*types.Alert
+		suppressed int
+	)
+	for _, a := range alerts {
+		if s.suppresses(a.Labels, now) {
+			suppressed++
+			continue
+		}
+		filtered = append(filtered, a)
+	}
+	if suppressed > 0 {
+		level.Debug(l).Log("msg", "Notifications suppressed", "alerts", suppressed)
+	}
+	return ctx, filtered, nil
+}
+
+func (s *SuppressStage) suppresses(lset model.LabelSet, now time.Time) bool {
+	for _, r := range s.rules {
+		if !labels.Matchers(r.Matchers).Matches(lset) || !s.active(r, now) {
+			continue
+		}
+		if r.MaxSeverity == "" {
+			return true
+		}
+		// Alerts with an unknown severity are never suppressed.
+		if notMoreSevere(s.ranks, s.label, lset, r.MaxSeverity) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SuppressStage) active(r *config.SuppressionRule, now time.Time) bool {
+	return inTimeIntervals(s.muteTimes, r.TimeIntervals, now)
+}
+
+// inTimeIntervals returns true if one of the named time intervals contains
+// the given time.
+func inTimeIntervals(muteTimes map[string][]timeinterval.TimeInterval, names []string, now time.Time) bool {
+	for _, name := range names {
+		for _, ti := range muteTimes[name] {
+			if ti.ContainsTime(now.UTC()) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestSuppressStage(t *testing.T) {
+	var night []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- times:
+  - start_time: '00:00'
+    end_time: '06:00'`), &night))
+
+	m, err := labels.NewMatcher(labels.MatchEqual, "team", "db")
+	require.NoError(t, err)
+	stage := NewSuppressStage(
+		[]*config.SuppressionRule{{
+			Matchers:      config.Matchers{m},
+			TimeIntervals: []string{"night"},
+			MaxSeverity:   "warning",
+		}},
+		map[string][]timeinterval.TimeInterval{"night": night},
+		"severity",
+		[]*config.SeverityMapping{{Severity: "critical"}, {Severity: "warning"}, {Severity: "info"}},
+	)
+
+	alerts := []*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"team": "db", "severity": "critical"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"team": "db", "severity": "warning"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"team": "db", "severity": "info"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"team": "db", "severity": "unknown"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"team": "web", "severity": "warning"}}},
+	}
+
+	for _, tc := range []struct {
+		now      time.Time
+		expected []*types.Alert
+	}{
+		{
+			now:      time.Date(2021, 1, 1, 3, 0, 0, 0, time.UTC),
+			expected: []*types.Alert{alerts[0], alerts[3], alerts[4]},
+		},
+		{
+			now:      time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+			expected: alerts,
+		},
+	} {
+		ctx := WithNow(context.Background(), tc.now)
+		_, res, err := stage.Exec(ctx, log.NewNopLogger(), alerts...)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, res)
+	}
+
+	_, _, err = stage.Exec(context.Background(), log.NewNopLogger(), alerts...)
+	require.Error(t, err)
+}
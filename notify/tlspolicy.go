@@ -0,0 +1,176 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/pkg/errors"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+const (
+	recordTypeHandshake        = 22
+	handshakeTypeServerHello   = 2
+	extensionSupportedVersions = 43
+	extensionKeyShare          = 51
+
+	// maxServerHelloLen bounds the bytes buffered while looking for the
+	// ServerHello message.
+	maxServerHelloLen = 32 << 10
+)
+
+var (
+	errNotTLS               = errors.New("not a TLS connection")
+	errMalformedServerHello = errors.New("malformed TLS ServerHello message")
+)
+
+// tlsPolicyDialContext returns a dial function checking the TLS connections
+// against the policy. The TLS configuration of the HTTP clients can't be
+// changed, so the parameters chosen by the server in its ServerHello message
+// are checked instead, before any request is sent.
+func tlsPolicyDialContext(dial commoncfg.DialContextFunc, p *config.TLSPolicy) commoncfg.DialContextFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		return &tlsPolicyConn{Conn: conn, policy: p}, nil
+	}
+}
+
+// tlsPolicyConn closes the connection if the ServerHello message read from
+// it isn't allowed by the TLS policy. Connections which aren't TLS, like plain
+// HTTP ones, are left alone.
+type tlsPolicyConn struct {
+	net.Conn
+	policy  *config.TLSPolicy
+	buf     []byte
+	checked bool
+}
+
+// Read implements net.Conn.
+func (c *tlsPolicyConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if c.checked || n == 0 {
+		return n, err
+	}
+	c.buf = append(c.buf, b[:n]...)
+	hello, perr := parseServerHello(c.buf)
+	switch {
+	case perr == errNotTLS:
+	case perr != nil:
+		c.Conn.Close()
+		return 0, perr
+	case hello == nil:
+		if len(c.buf) < maxServerHelloLen {
+			return n, err
+		}
+	default:
+		if perr = c.policy.Check(hello.version, hello.cipherSuite, hello.curve); perr != nil {
+			c.Conn.Close()
+			return 0, perr
+		}
+	}
+	c.checked, c.buf = true, nil
+	return n, err
+}
+
+// serverHello holds the parameters of a TLS connection chosen by the server.
+type serverHello struct {
+	version     uint16
+	cipherSuite uint16
+	curve       tls.CurveID
+}
+
+// parseServerHello parses the ServerHello message at the start of the bytes
+// read from a TLS connection, after the response of a proxy to a CONNECT
+// request if any. It returns nil and no error if more bytes are needed. The
+// curve is only known for TLS 1.3.
+func parseServerHello(b []byte) (*serverHello, error) {
+	if bytes.HasPrefix(b, []byte("HTTP/")) {
+		i := bytes.Index(b, []byte("\r\n\r\n"))
+		if i < 0 {
+			return nil, nil
+		}
+		b = b[i+4:]
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if b[0] != recordTypeHandshake {
+		return nil, errNotTLS
+	}
+	if len(b) < 5 {
+		return nil, nil
+	}
+	n := int(b[3])<<8 | int(b[4])
+	if len(b) < 5+n {
+		return nil, nil
+	}
+	// The ServerHello message is expected to fit in the first record.
+	msg := b[5 : 5+n]
+	if len(msg) < 4 || msg[0] != handshakeTypeServerHello {
+		return nil, errMalformedServerHello
+	}
+	m := int(msg[1])<<16 | int(msg[2])<<8 | int(msg[3])
+	if len(msg) < 4+m {
+		return nil, errMalformedServerHello
+	}
+	body := msg[4 : 4+m]
+
+	// Legacy version, random and session ID.
+	if len(body) < 35 || len(body) < 35+int(body[34])+3 {
+		return nil, errMalformedServerHello
+	}
+	h := &serverHello{version: uint16(body[0])<<8 | uint16(body[1])}
+	body = body[35+int(body[34]):]
+	h.cipherSuite = uint16(body[0])<<8 | uint16(body[1])
+	body = body[3:]
+	if len(body) < 2 {
+		return h, nil
+	}
+	exts := body[2:]
+	l := int(body[0])<<8 | int(body[1])
+	if len(exts) < l {
+		return nil, errMalformedServerHello
+	}
+	exts = exts[:l]
+	for len(exts) >= 4 {
+		typ := int(exts[0])<<8 | int(exts[1])
+		l := int(exts[2])<<8 | int(exts[3])
+		if len(exts) < 4+l {
+			return nil, errMalformedServerHello
+		}
+		data := exts[4 : 4+l]
+		exts = exts[4+l:]
+		if len(data) < 2 {
+			continue
+		}
+		switch typ {
+		case extensionSupportedVersions:
+			h.version = uint16(data[0])<<8 | uint16(data[1])
+		case extensionKeyShare:
+			h.curve = tls.CurveID(uint16(data[0])<<8 | uint16(data[1]))
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package notify
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+func TestTLSPolicyDialContext(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		server *tls.Config
+		policy string
+		err    string
+	}{
+		{
+			name:   "allowed TLS 1.2",
+			server: &tls.Config{MaxVersion: tls.VersionTLS12, CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}},
+			policy: "{min_version: TLS12, cipher_suites: [TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256]}",
+		},
+		{
+			name:   "allowed TLS 1.3",
+			server: &tls.Config{CurvePreferences: []tls.CurveID{tls.CurveP256}},
+			policy: "{min_version: TLS13, curve_preferences: [CurveP256]}",
+		},
+		{
+			name:   "version below minimum",
+			server: &tls.Config{MaxVersion: tls.VersionTLS12},
+			policy: "{min_version: TLS13}",
+			err:    "TLS version TLS12 is not allowed by the TLS policy",
+		},
+		{
+			name:   "cipher suite not allowed",
+			server: &tls.Config{MaxVersion: tls.VersionTLS12, CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}},
+			policy: "{cipher_suites: [TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256]}",
+			err:    "cipher suite TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 is not allowed by the TLS policy",
+		},
+		{
+			name:   "curve not allowed",
+			server: &tls.Config{CurvePreferences: []tls.CurveID{tls.CurveP384}},
+			policy: "{curve_preferences: [CurveP256, X25519]}",
+			err:    "curve CurveP384 is not allowed by the TLS policy",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var received bool
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = true
+			}))
+			srv.TLS = tc.server
+			srv.StartTLS()
+			defer srv.Close()
+
+			var policy config.TLSPolicy
+			require.NoError(t, yaml.UnmarshalStrict([]byte(tc.policy), &policy))
+			httpConfig := commoncfg.HTTPClientConfig{TLSConfig: commoncfg.TLSConfig{InsecureSkipVerify: true}}
+			client, err := commoncfg.NewClientFromConfig(httpConfig, "test", HTTPClientOptions(nil, &policy)...)
+			require.NoError(t, err)
+
+			resp, err := client.Post(srv.URL, "text/plain", nil)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				require.False(t, received)
+				return
+			}
+			require.NoError(t, err)
+			Drain(resp)
+			require.True(t, received)
+		})
+	}
+}
+
+func TestTLSPolicyDialContextPlainHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var policy config.TLSPolicy
+	require.NoError(t, yaml.UnmarshalStrict([]byte("{min_version: TLS13}"), &policy))
+	client, err := commoncfg.NewClientFromConfig(commoncfg.DefaultHTTPClientConfig, "test", HTTPClientOptions(nil, &policy)...)
+	require.NoError(t, err)
+
+	// Several requests go through the same connection.
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		Drain(resp)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestTLSPolicyDialContextProxy(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	// The proxy tunnels CONNECT requests to the server.
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodConnect, r.Method)
+		upstream, err := net.Dial("tcp", r.Host)
+		require.NoError(t, err)
+		defer upstream.Close()
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		go io.Copy(upstream, conn)
+		io.Copy(conn, upstream)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	var policy config.TLSPolicy
+	require.NoError(t, yaml.UnmarshalStrict([]byte("{min_version: TLS13}"), &policy))
+	httpConfig := commoncfg.HTTPClientConfig{
+		ProxyURL:  commoncfg.URL{URL: proxyURL},
+		TLSConfig: commoncfg.TLSConfig{InsecureSkipVerify: true},
+	}
+	client, err := commoncfg.NewClientFromConfig(httpConfig, "test", HTTPClientOptions(nil, &policy)...)
+	require.NoError(t, err)
+
+	_, err = client.Get(srv.URL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "TLS version TLS12 is not allowed by the TLS policy")
+}
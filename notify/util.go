@@ -19,13 +19,19 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/pkg/errors"
+	commoncfg "github.com/prometheus/common/config"
 
+	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 )
@@ -56,7 +62,7 @@ func post(ctx context.Context, client *http.Client, url string, bodyType string,
 		return nil, err
 	}
 	req.Header.Set("Content-Type", bodyType)
-	return client.Do(req.WithContext(ctx))
+	return Do(client, req.WithContext(ctx))
 }
 
 // Drain consumes and closes the response's body to make sure that the
@@ -66,6 +72,205 @@ func Drain(r *http.Response) {
 	r.Body.Close()
 }
 
+// HTTPClientOptions returns the options of the HTTP clients of notifiers for
+// the given transport configuration, or the default one if nil, and TLS
+// policy, if any.
+func HTTPClientOptions(c *config.HTTPTransportConfig, p *config.TLSPolicy) []commoncfg.HTTPClientOption {
+	if c == nil {
+		c = &config.DefaultHTTPTransportConfig
+	}
+	opts := []commoncfg.HTTPClientOption{
+		commoncfg.WithIdleConnTimeout(time.Duration(c.IdleConnTimeout)),
+	}
+	if !c.KeepAlive {
+		opts = append(opts, commoncfg.WithKeepAlivesDisabled())
+	}
+	if !c.EnableHTTP2 {
+		opts = append(opts, commoncfg.WithHTTP2Disabled())
+	}
+	var dial commoncfg.DialContextFunc
+	if c.LocalAddress != "" || c.Interface != "" {
+		dial = localDialContext(c.LocalAddress, c.Interface)
+	}
+	if p != nil {
+		dial = tlsPolicyDialContext(dial, p)
+	}
+	if dial != nil {
+		opts = append(opts, commoncfg.WithDialContextFunc(dial))
+	}
+	return opts
+}
+
+// localDialContext returns a dial function binding the connections to the
+// given local address or network interface. The address of the interface is
+// looked up on every dial, as interfaces may change.
+func localDialContext(addr, iface string) commoncfg.DialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		ip := net.ParseIP(addr)
+		if iface != "" {
+			var err error
+			if ip, err = interfaceIP(iface); err != nil {
+				return nil, err
+			}
+		}
+		d := net.Dialer{LocalAddr: &net.TCPAddr{IP: ip}}
+		return d.DialContext(ctx, network, address)
+	}
+}
+
+// interfaceIP returns the first IPv4 address of the network interface, or
+// else its first address.
+func interfaceIP(name string) (net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	var ip net.IP
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP, nil
+		}
+		if ip == nil {
+			ip = ipNet.IP
+		}
+	}
+	if ip == nil {
+		return nil, errors.Errorf("no address on network interface %q", name)
+	}
+	return ip, nil
+}
+
+type requestOptionsKey struct{}
+
+// RequestOptionsNotifier sets the User-Agent header and the query parameters
+// of an HTTP transport configuration on the requests of a notifier.
+type RequestOptionsNotifier struct {
+	notifier Notifier
+	conf     *config.HTTPTransportConfig
+}
+
+// NewRequestOptionsNotifier wraps the notifier in a RequestOptionsNotifier,
+// unless the configuration has neither a User-Agent nor query parameters.
+func NewRequestOptionsNotifier(n Notifier, c *config.HTTPTransportConfig) Notifier {
+	if c == nil || (c.UserAgent == "" && len(c.QueryParams) == 0) {
+		return n
+	}
+	return &RequestOptionsNotifier{notifier: n, conf: c}
+}
+
+// Notify implements the Notifier interface.
+func (n *RequestOptionsNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	return n.notifier.Notify(context.WithValue(ctx, requestOptionsKey{}, n.conf), alerts...)
+}
+
+type transportKey struct{}
+
+// TransportWrapper wraps the round tripper of an HTTP client, e.g. to
+// instrument or stub its requests.
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
+
+// TransportNotifier sends the requests of a notifier through the round
+// tripper returned by a TransportWrapper.
+type TransportNotifier struct {
+	notifier Notifier
+	wrap     TransportWrapper
+}
+
+// NewTransportNotifier wraps the notifier in a TransportNotifier, unless the
+// wrapper is nil.
+func NewTransportNotifier(n Notifier, w TransportWrapper) Notifier {
+	if w == nil {
+		return n
+	}
+	return &TransportNotifier{notifier: n, wrap: w}
+}
+
+// Notify implements the Notifier interface.
+func (n *TransportNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	return n.notifier.Notify(context.WithValue(ctx, transportKey{}, n.wrap), alerts...)
+}
+
+// wrapTransport returns a copy of the client whose round tripper is wrapped
+// by the TransportNotifier sending the request, if any. The round trippers of
+// the client, e.g. adding its credentials, are kept.
+func wrapTransport(client *http.Client, req *http.Request) *http.Client {
+	w, ok := req.Context().Value(transportKey{}).(TransportWrapper)
+	if !ok {
+		return client
+	}
+	c := *client
+	rt := c.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	c.Transport = w(rt)
+	return &c
+}
+
+type timeFormatKey struct{}
+
+type timeFormat struct {
+	location *time.Location
+	layout   string
+}
+
+// TimeFormatNotifier makes the templates of a notifier render the times of
+// the alerts in the time zone and with the time format of its receiver.
+type TimeFormatNotifier struct {
+	notifier Notifier
+	format   timeFormat
+}
+
+// NewTimeFormatNotifier wraps the notifier in a TimeFormatNotifier, unless
+// the receiver has neither a time zone nor a time format.
+func NewTimeFormatNotifier(n Notifier, c *config.Receiver) (Notifier, error) {
+	if c.Timezone == "" && c.TimeFormat == "" {
+		return n, nil
+	}
+	f := timeFormat{layout: c.TimeFormat}
+	if c.Timezone != "" {
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		f.location = loc
+	}
+	return &TimeFormatNotifier{notifier: n, format: f}, nil
+}
+
+// Notify implements the Notifier interface.
+func (n *TimeFormatNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	return n.notifier.Notify(context.WithValue(ctx, timeFormatKey{}, n.format), alerts...)
+}
+
+// SetRequestOptions sets the User-Agent header and the query parameters of
+// the RequestOptionsNotifier sending the request, if any. Do calls it for
+// all requests.
+func SetRequestOptions(req *http.Request) {
+	c, ok := req.Context().Value(requestOptionsKey{}).(*config.HTTPTransportConfig)
+	if !ok {
+		return
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if len(c.QueryParams) > 0 {
+		q := req.URL.Query()
+		for k, v := range c.QueryParams {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
 // Truncate truncates a string to fit the given size.
 func Truncate(s string, n int) (string, bool) {
 	r := []rune(s)
@@ -78,6 +283,44 @@ func Truncate(s string, n int) (string, bool) {
 	return string(r[:n-3]) + "...", true
 }
 
+// The maximum lengths in characters of the texts of the integrations, beyond
+// which the providers reject or cut the notifications.
+const (
+	// MaxSlackTextLen is the maximum length of Slack message texts.
+	MaxSlackTextLen = 40000
+	// MaxPagerDutySummaryLen is the maximum length of the summaries of
+	// PagerDuty events, and of the descriptions of legacy events.
+	MaxPagerDutySummaryLen = 1024
+	// MaxPushoverTitleLen, MaxPushoverMessageLen and MaxPushoverURLLen are
+	// the maximum lengths of Pushover titles, messages and URLs.
+	MaxPushoverTitleLen   = 250
+	MaxPushoverMessageLen = 1024
+	MaxPushoverURLLen     = 512
+	// MaxOpsGenieMessageLen is the maximum length of OpsGenie alert messages.
+	MaxOpsGenieMessageLen = 130
+	// MaxVictorOpsStateMessageLen is the maximum length of VictorOps state
+	// messages.
+	MaxVictorOpsStateMessageLen = 20480
+)
+
+// TruncateMessage truncates the message of a notification to fit the given
+// size like Truncate, and ends truncated messages with the number of alerts of
+// the notification and a link to the Alertmanager, for the recipients to find
+// the details that were cut off. Sizes too small for them fall back to
+// Truncate.
+func TruncateMessage(s string, n int, data *template.Data) (string, bool) {
+	if utf8.RuneCountInString(s) <= n {
+		return s, false
+	}
+	suffix := fmt.Sprintf("\n[truncated, %d alerts: %s]", len(data.Alerts), data.ExternalURL)
+	m := n - utf8.RuneCountInString(suffix)
+	if m <= 3 {
+		return Truncate(s, n)
+	}
+	s, _ = Truncate(s, m)
+	return s + suffix, true
+}
+
 // TmplText is using monadic error handling in order to make string templating
 // less verbose. Use with care as the final error checking is easily missed.
 func TmplText(tmpl *template.Template, data *template.Data, err *error) func(string) string {
@@ -102,7 +345,12 @@ func TmplHTML(tmpl *template.Template, data *template.Data, err *error) func(str
 	}
 }
 
-// Key is a string that can be hashed.
+// Key is the key of an alert group. It is made of the matchers of its route
+// and of the parents of the route, joined by slashes, and of its group labels,
+// e.g. {}/{team="db"}:{alertname="InstanceDown"}. The matchers and the labels
+// are sorted, so the key only depends on the routing tree and on the labels of
+// the alerts: it is the same across restarts and on all the replicas running
+// the same configuration.
 type Key string
 
 // ExtractGroupKey gets the group key from the context.
@@ -138,7 +386,46 @@ func GetTemplateData(ctx context.Context, tmpl *template.Template, alerts []*typ
 	if !ok {
 		level.Error(l).Log("msg", "Missing group labels")
 	}
-	return tmpl.Data(recv, groupLabels, alerts...)
+	data := tmpl.Data(recv, groupLabels, alerts...)
+	if gkey, ok := GroupKey(ctx); ok {
+		data.GroupKey, data.GroupID = gkey, Key(gkey).Hash()
+		data.AckURL = tmpl.AckURL(gkey)
+	}
+	data.Digest, _ = Digest(ctx)
+	if f, ok := ctx.Value(timeFormatKey{}).(timeFormat); ok {
+		data.Localize(f.location, f.layout)
+	}
+	if owner, ok := Owner(ctx); ok && owner != nil {
+		data.Owner = &template.Owner{Name: owner.Name}
+		data.Owner.OnCall, _ = OnCall(ctx)
+	}
+	if part, parts, ok := MessagePart(ctx); ok {
+		data.Part, data.Parts = part, parts
+	}
+	return data
+}
+
+// LookupSeverity returns the severity mapping matching the most severe of the
+// firing alerts, or nil if none matches.
+func LookupSeverity(s *config.SeverityMappings, alerts ...*types.Alert) *config.SeverityMapping {
+	if s == nil {
+		return nil
+	}
+	severities := map[string]struct{}{}
+	for _, a := range alerts {
+		if a.Resolved() {
+			continue
+		}
+		if v, ok := a.Labels[s.Label]; ok {
+			severities[string(v)] = struct{}{}
+		}
+	}
+	for _, m := range s.Mappings {
+		if _, ok := severities[m.Severity]; ok {
+			return m
+		}
+	}
+	return nil
 }
 
 func readAll(r io.Reader) string {
@@ -152,6 +439,26 @@ func readAll(r io.Reader) string {
 	return string(bs)
 }
 
+// StatusCodeError is the error of a request to a receiver answered with an
+// unexpected HTTP status code.
+type StatusCodeError struct {
+	StatusCode int
+	// RetryAfter is the delay before retrying asked for by a receiver
+	// throttling the requests, or 0.
+	RetryAfter time.Duration
+	msg        string
+}
+
+func (e *StatusCodeError) Error() string {
+	return e.msg
+}
+
+// NewStatusCodeError returns the error of a response with the given status
+// code, retried after the given delay if positive.
+func NewStatusCodeError(statusCode int, retryAfter time.Duration, msg string) *StatusCodeError {
+	return &StatusCodeError{StatusCode: statusCode, RetryAfter: retryAfter, msg: msg}
+}
+
 // Retrier knows when to retry an HTTP request to a receiver. 2xx status codes
 // are successful, anything else is a failure and only 5xx status codes should
 // be retried.
@@ -192,5 +499,42 @@ func (r *Retrier) Check(statusCode int, body io.Reader) (bool, error) {
 	if details != "" {
 		s = fmt.Sprintf("%s: %s", s, details)
 	}
-	return retry, errors.New(s)
+	return retry, &StatusCodeError{StatusCode: statusCode, msg: s}
+}
+
+// CheckResponse is like Check for the response of a receiver. Throttled
+// requests, answered with status code 429, are retried too. The delay asked for
+// by the Retry-After header of retried requests is set in the error.
+func (r *Retrier) CheckResponse(resp *http.Response, body io.Reader) (bool, error) {
+	retry, err := r.Check(resp.StatusCode, body)
+	var serr *StatusCodeError
+	if !errors.As(err, &serr) {
+		return retry, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retry = true
+	}
+	if retry {
+		serr.RetryAfter = RetryAfter(resp.Header, time.Now())
+	}
+	return retry, err
+}
+
+// RetryAfter returns the delay of the Retry-After header, given in seconds or
+// as an HTTP date, or 0 if it is missing or invalid.
+func RetryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
 }
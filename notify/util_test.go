@@ -15,13 +15,26 @@ package notify
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
 )
 
 func TestTruncate(t *testing.T) {
@@ -91,6 +104,229 @@ func (b brokenReader) Read([]byte) (int, error) {
 	return 0, fmt.Errorf("some error")
 }
 
+func TestHTTPClientOptions(t *testing.T) {
+	var closed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		closed = r.Close
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		conf   *config.HTTPTransportConfig
+		closed bool
+	}{
+		{conf: nil, closed: false},
+		{conf: &config.HTTPTransportConfig{KeepAlive: true}, closed: false},
+		{conf: &config.HTTPTransportConfig{KeepAlive: false}, closed: true},
+	} {
+		client, err := commoncfg.NewClientFromConfig(commoncfg.DefaultHTTPClientConfig, "test", HTTPClientOptions(tc.conf, nil)...)
+		require.NoError(t, err)
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		Drain(resp)
+		require.Equal(t, tc.closed, closed)
+	}
+}
+
+func TestHTTPClientOptionsLocalAddress(t *testing.T) {
+	var remoteAddr string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+	}))
+	defer srv.Close()
+
+	var loopback string
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			loopback = iface.Name
+			break
+		}
+	}
+
+	for _, conf := range []*config.HTTPTransportConfig{
+		{LocalAddress: "127.0.0.1"},
+		{Interface: loopback},
+	} {
+		if conf.Interface == "" && conf.LocalAddress == "" {
+			continue
+		}
+		client, err := commoncfg.NewClientFromConfig(commoncfg.DefaultHTTPClientConfig, "test", HTTPClientOptions(conf, nil)...)
+		require.NoError(t, err)
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		Drain(resp)
+		host, _, err := net.SplitHostPort(remoteAddr)
+		require.NoError(t, err)
+		require.Equal(t, "127.0.0.1", host)
+	}
+
+	client, err := commoncfg.NewClientFromConfig(commoncfg.DefaultHTTPClientConfig, "test", HTTPClientOptions(&config.HTTPTransportConfig{Interface: "does-not-exist0"}, nil)...)
+	require.NoError(t, err)
+	_, err = client.Get(srv.URL)
+	require.Error(t, err)
+}
+
+func TestRequestOptionsNotifier(t *testing.T) {
+	var (
+		userAgent string
+		query     string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		query = r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	var n Notifier = notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		req, err := http.NewRequest("POST", srv.URL+"?a=1&b=2", nil)
+		require.NoError(t, err)
+		req.Header.Set("User-Agent", "Alertmanager")
+		resp, err := Do(http.DefaultClient, req.WithContext(ctx))
+		require.NoError(t, err)
+		Drain(resp)
+		return false, nil
+	})
+
+	require.IsType(t, notifierFunc(nil), NewRequestOptionsNotifier(n, nil))
+	require.IsType(t, notifierFunc(nil), NewRequestOptionsNotifier(n, &config.HTTPTransportConfig{KeepAlive: true}))
+
+	_, err := n.Notify(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Alertmanager", userAgent)
+	require.Equal(t, "a=1&b=2", query)
+
+	n = NewRequestOptionsNotifier(n, &config.HTTPTransportConfig{
+		UserAgent:   "gateway-client/1.0",
+		QueryParams: map[string]string{"b": "3", "tenant": "ops"},
+	})
+	_, err = n.Notify(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "gateway-client/1.0", userAgent)
+	require.Equal(t, "a=1&b=3&tenant=ops", query)
+}
+
+func TestTimeFormatNotifier(t *testing.T) {
+	tmpl, err := template.FromGlobs()
+	require.NoError(t, err)
+	tmpl.ExternalURL, _ = url.Parse("http://am")
+
+	var data *template.Data
+	var n Notifier = notifierFunc(func(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+		data = GetTemplateData(ctx, tmpl, alerts, log.NewNopLogger())
+		return false, nil
+	})
+	alert := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "InstanceDown"},
+		StartsAt: time.Date(2021, 7, 1, 12, 30, 0, 0, time.UTC),
+	}}
+	ctx := WithReceiverName(context.Background(), "team-db")
+	ctx = WithGroupLabels(ctx, model.LabelSet{"alertname": "InstanceDown"})
+
+	nn, err := NewTimeFormatNotifier(n, &config.Receiver{Name: "team-db"})
+	require.NoError(t, err)
+	require.IsType(t, notifierFunc(nil), nn)
+	_, err = nn.Notify(ctx, alert)
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, data.Alerts[0].StartsAt.Location())
+	require.Equal(t, "2021-07-01 12:30:00 UTC", data.FormatTime(data.Alerts[0].StartsAt))
+
+	nn, err = NewTimeFormatNotifier(n, &config.Receiver{Name: "team-db", Timezone: "America/New_York"})
+	require.NoError(t, err)
+	_, err = nn.Notify(ctx, alert)
+	require.NoError(t, err)
+	require.Equal(t, "America/New_York", data.Alerts[0].StartsAt.Location().String())
+	require.Equal(t, "2021-07-01 08:30:00 EDT", data.FormatTime(data.Alerts[0].StartsAt))
+
+	nn, err = NewTimeFormatNotifier(n, &config.Receiver{Name: "team-db", Timezone: "Europe/Berlin", TimeFormat: "02.01.2006 15:04"})
+	require.NoError(t, err)
+	_, err = nn.Notify(ctx, alert)
+	require.NoError(t, err)
+	s, err := tmpl.ExecuteTextString(`{{ range .Alerts }}{{ $.FormatTime .StartsAt }}{{ end }}`, data)
+	require.NoError(t, err)
+	require.Equal(t, "01.07.2021 14:30", s)
+
+	_, err = NewTimeFormatNotifier(n, &config.Receiver{Name: "team-db", Timezone: "Mars/Olympus"})
+	require.Error(t, err)
+}
+
+func TestTruncateMessage(t *testing.T) {
+	data := &template.Data{
+		Alerts:      template.Alerts{{}, {}, {}},
+		ExternalURL: "http://am",
+	}
+	suffix := "\n[truncated, 3 alerts: http://am]"
+
+	s, truncated := TruncateMessage("short", 100, data)
+	require.False(t, truncated)
+	require.Equal(t, "short", s)
+
+	s, truncated = TruncateMessage(strings.Repeat("a", 100), 50, data)
+	require.True(t, truncated)
+	require.Equal(t, strings.Repeat("a", 50-len(suffix)-3)+"..."+suffix, s)
+	require.Len(t, []rune(s), 50)
+
+	// Sizes too small for the suffix are truncated plainly.
+	s, truncated = TruncateMessage(strings.Repeat("a", 100), 20, data)
+	require.True(t, truncated)
+	require.Equal(t, strings.Repeat("a", 17)+"...", s)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value string
+		exp   time.Duration
+	}{
+		{value: "", exp: 0},
+		{value: "30", exp: 30 * time.Second},
+		{value: "-1", exp: 0},
+		{value: "Fri, 01 Oct 2021 12:01:00 GMT", exp: time.Minute},
+		{value: "Fri, 01 Oct 2021 11:59:00 GMT", exp: 0},
+		{value: "soon", exp: 0},
+	} {
+		h := http.Header{}
+		if tc.value != "" {
+			h.Set("Retry-After", tc.value)
+		}
+		require.Equal(t, tc.exp, RetryAfter(h, now), tc.value)
+	}
+}
+
+func TestRetrierCheckResponse(t *testing.T) {
+	r := &Retrier{}
+	resp := func(code int, retryAfter string) *http.Response {
+		h := http.Header{}
+		if retryAfter != "" {
+			h.Set("Retry-After", retryAfter)
+		}
+		return &http.Response{StatusCode: code, Header: h}
+	}
+
+	retry, err := r.CheckResponse(resp(200, ""), nil)
+	require.False(t, retry)
+	require.NoError(t, err)
+
+	// Rate limiting responses are retried after the given delay.
+	retry, err = r.CheckResponse(resp(429, "10"), nil)
+	require.True(t, retry)
+	var serr *StatusCodeError
+	require.ErrorAs(t, err, &serr)
+	require.Equal(t, 10*time.Second, serr.RetryAfter)
+
+	retry, err = r.CheckResponse(resp(503, "5"), nil)
+	require.True(t, retry)
+	require.ErrorAs(t, err, &serr)
+	require.Equal(t, 5*time.Second, serr.RetryAfter)
+
+	// The delay isn't set for unrecoverable errors.
+	retry, err = r.CheckResponse(resp(400, "5"), nil)
+	require.False(t, retry)
+	require.ErrorAs(t, err, &serr)
+	require.Equal(t, time.Duration(0), serr.RetryAfter)
+}
+
 func TestRetrierCheck(t *testing.T) {
 	for _, tc := range []struct {
 		retrier Retrier
@@ -174,6 +410,56 @@ func TestRetrierCheck(t *testing.T) {
 				return
 			}
 			require.EqualError(t, err, tc.expectedErr)
+			var serr *StatusCodeError
+			require.ErrorAs(t, err, &serr)
+			require.Equal(t, tc.status, serr.StatusCode)
 		})
 	}
 }
+
+func TestGetTemplateDataGroupKey(t *testing.T) {
+	tmpl, err := template.FromGlobs()
+	require.NoError(t, err)
+	tmpl.ExternalURL, _ = url.Parse("http://am")
+
+	ctx := WithReceiverName(context.Background(), "team-db")
+	ctx = WithGroupLabels(ctx, model.LabelSet{"alertname": "InstanceDown"})
+	data := GetTemplateData(ctx, tmpl, nil, log.NewNopLogger())
+	require.Empty(t, data.GroupKey)
+	require.Empty(t, data.GroupID)
+
+	ctx = WithGroupKey(ctx, `{}/{team="db"}:{alertname="InstanceDown"}`)
+	data = GetTemplateData(ctx, tmpl, nil, log.NewNopLogger())
+	require.Equal(t, `{}/{team="db"}:{alertname="InstanceDown"}`, data.GroupKey)
+	require.Equal(t, Key(data.GroupKey).Hash(), data.GroupID)
+	require.Len(t, data.GroupID, 64)
+}
+
+func TestLookupSeverity(t *testing.T) {
+	critical := &config.SeverityMapping{Severity: "critical", PagerDuty: "critical"}
+	warning := &config.SeverityMapping{Severity: "warning", PagerDuty: "warning"}
+	mappings := &config.SeverityMappings{
+		Label:    "severity",
+		Mappings: []*config.SeverityMapping{critical, warning},
+	}
+
+	alert := func(severity string, resolved bool) *types.Alert {
+		a := &types.Alert{
+			Alert: model.Alert{
+				Labels:   model.LabelSet{"severity": model.LabelValue(severity)},
+				StartsAt: time.Now().Add(-time.Hour),
+			},
+		}
+		if resolved {
+			a.EndsAt = time.Now().Add(-time.Minute)
+		}
+		return a
+	}
+
+	require.Nil(t, LookupSeverity(nil, alert("critical", false)))
+	require.Nil(t, LookupSeverity(mappings, alert("info", false)))
+	require.Equal(t, warning, LookupSeverity(mappings, alert("warning", false)))
+	require.Equal(t, critical, LookupSeverity(mappings, alert("warning", false), alert("critical", false)))
+	// Resolved alerts are not considered.
+	require.Equal(t, warning, LookupSeverity(mappings, alert("warning", false), alert("critical", true)))
+}
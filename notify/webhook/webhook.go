@@ -19,15 +19,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	uuid "github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	commoncfg "github.com/prometheus/common/config"
 	"github.com/prometheus/common/version"
 
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/pkg/srv"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 )
@@ -36,24 +45,26 @@ var userAgentHeader = fmt.Sprintf("Alertmanager/%s", version.Version)
 
 // Notifier implements a Notifier for generic webhooks.
 type Notifier struct {
-	conf    *config.WebhookConfig
-	tmpl    *template.Template
-	logger  log.Logger
-	client  *http.Client
-	retrier *notify.Retrier
+	conf     *config.WebhookConfig
+	tmpl     *template.Template
+	logger   log.Logger
+	client   *http.Client
+	retrier  *notify.Retrier
+	resolver srv.Resolver
 }
 
 // New returns a new Webhook.
 func New(conf *config.WebhookConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
-	client, err := commoncfg.NewClientFromConfig(*conf.HTTPConfig, "webhook", append(httpOpts, commoncfg.WithHTTP2Disabled())...)
+	client, err := commoncfg.NewClientFromConfig(*conf.HTTPConfig, "webhook", httpOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return &Notifier{
-		conf:   conf,
-		tmpl:   t,
-		logger: l,
-		client: client,
+		conf:     conf,
+		tmpl:     t,
+		logger:   l,
+		client:   client,
+		resolver: net.DefaultResolver,
 		// Webhooks are assumed to respond with 2xx response codes on a successful
 		// request and 5xx response codes are assumed to be recoverable.
 		retrier: &notify.Retrier{
@@ -74,6 +85,113 @@ type Message struct {
 	TruncatedAlerts uint64 `json:"truncatedAlerts"`
 }
 
+// MessageV2 defines the JSON object sent to webhook endpoints with version 2
+// of the payload. Its alerts are a superset of those of Message.
+type MessageV2 struct {
+	// The schema version, always "2".
+	SchemaVersion string `json:"schemaVersion"`
+	Receiver      string `json:"receiver"`
+	Status        string `json:"status"`
+	// GroupKey identifies the group of alerts, and GroupID is its hash,
+	// which is stable across restarts and safe to use as an identifier.
+	GroupKey string `json:"groupKey"`
+	GroupID  string `json:"groupID"`
+
+	GroupLabels       template.KV `json:"groupLabels"`
+	CommonLabels      template.KV `json:"commonLabels"`
+	CommonAnnotations template.KV `json:"commonAnnotations"`
+
+	ExternalURL string     `json:"externalURL"`
+	AckURL      string     `json:"ackURL,omitempty"`
+	Truncation  Truncation `json:"truncation"`
+	Alerts      []AlertV2  `json:"alerts"`
+}
+
+// Truncation describes the alerts of the group left out of a message.
+type Truncation struct {
+	// TotalAlerts is the number of alerts of the group or, if it was split
+	// into several messages, of the part.
+	TotalAlerts     int    `json:"totalAlerts"`
+	TruncatedAlerts uint64 `json:"truncatedAlerts"`
+	// Part and Parts are set if the alerts of the group were split into
+	// several messages. Part starts at 1.
+	Part  int `json:"part,omitempty"`
+	Parts int `json:"parts,omitempty"`
+}
+
+// AlertV2 is an alert of a MessageV2.
+type AlertV2 struct {
+	template.Alert
+	// SilenceURL links to the form creating a silence for the alert.
+	SilenceURL string `json:"silenceURL"`
+}
+
+func newMessageV2(data *template.Data, groupKey notify.Key, total int, truncated uint64) *MessageV2 {
+	msg := &MessageV2{
+		SchemaVersion:     "2",
+		Receiver:          data.Receiver,
+		Status:            data.Status,
+		GroupKey:          groupKey.String(),
+		GroupID:           groupKey.Hash(),
+		GroupLabels:       data.GroupLabels,
+		CommonLabels:      data.CommonLabels,
+		CommonAnnotations: data.CommonAnnotations,
+		ExternalURL:       data.ExternalURL,
+		AckURL:            data.AckURL,
+		Truncation: Truncation{
+			TotalAlerts:     total,
+			TruncatedAlerts: truncated,
+			Part:            data.Part,
+			Parts:           data.Parts,
+		},
+		Alerts: make([]AlertV2, 0, len(data.Alerts)),
+	}
+	for _, a := range data.Alerts {
+		msg.Alerts = append(msg.Alerts, AlertV2{
+			Alert:      a,
+			SilenceURL: silenceURL(data.ExternalURL, a.Labels),
+		})
+	}
+	return msg
+}
+
+// silenceURL returns the URL of the form of the web UI creating a silence
+// which matches the given labels.
+func silenceURL(externalURL string, ls template.KV) string {
+	ms := make(labels.Matchers, 0, len(ls))
+	for _, p := range ls.SortedPairs() {
+		m, err := labels.NewMatcher(labels.MatchEqual, p.Name, p.Value)
+		if err != nil {
+			continue
+		}
+		ms = append(ms, m)
+	}
+	// The web UI decodes the spaces of the fragment from %20 only.
+	filter := strings.ReplaceAll(url.QueryEscape(ms.String()), "+", "%20")
+	return strings.TrimSuffix(externalURL, "/") + "/#/silences/new?filter=" + filter
+}
+
+// The attributes of the CloudEvents sent by webhooks, which carry a Message
+// as data.
+const (
+	CloudEventsSpecVersion = "1.0"
+	CloudEventsType        = "io.prometheus.alertmanager.notification"
+	CloudEventsContentType = "application/cloudevents+json"
+)
+
+// cloudEvent is a CloudEvent in the structured content mode. The source is
+// the external URL of the Alertmanager and the subject the group key.
+type cloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
 func truncateAlerts(maxAlerts uint64, alerts []*types.Alert) ([]*types.Alert, uint64) {
 	if maxAlerts != 0 && uint64(len(alerts)) > maxAlerts {
 		return alerts[:maxAlerts], uint64(len(alerts)) - maxAlerts
@@ -84,6 +202,7 @@ func truncateAlerts(maxAlerts uint64, alerts []*types.Alert) ([]*types.Alert, ui
 
 // Notify implements the Notifier interface.
 func (n *Notifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	total := len(alerts)
 	alerts, numTruncated := truncateAlerts(n.conf.MaxAlerts, alerts)
 	data := notify.GetTemplateData(ctx, n.tmpl, alerts, n.logger)
 
@@ -92,30 +211,144 @@ func (n *Notifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, er
 		level.Error(n.logger).Log("err", err)
 	}
 
-	msg := &Message{
+	var body interface{} = &Message{
 		Version:         "4",
 		Data:            data,
 		GroupKey:        groupKey.String(),
 		TruncatedAlerts: numTruncated,
 	}
+	if n.conf.PayloadVersion == 2 {
+		body = newMessageV2(data, groupKey, total, numTruncated)
+	}
 
+	var (
+		contentType = "application/json"
+		header      = http.Header{}
+	)
+	if n.conf.CloudEventsMode != "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return false, err
+		}
+		ev := cloudEvent{
+			SpecVersion:     CloudEventsSpecVersion,
+			ID:              id.String(),
+			Source:          data.ExternalURL,
+			Type:            CloudEventsType,
+			Subject:         groupKey.String(),
+			Time:            time.Now().UTC(),
+			DataContentType: contentType,
+		}
+		if n.conf.CloudEventsMode == config.CloudEventsStructured {
+			ev.Data = body
+			body, contentType = ev, CloudEventsContentType
+		} else {
+			header.Set("ce-specversion", ev.SpecVersion)
+			header.Set("ce-id", ev.ID)
+			header.Set("ce-source", ev.Source)
+			header.Set("ce-type", ev.Type)
+			header.Set("ce-subject", ev.Subject)
+			header.Set("ce-time", ev.Time.Format(time.RFC3339Nano))
+		}
+	}
 	var buf bytes.Buffer
-	if err := json.NewEncoder(&buf).Encode(msg); err != nil {
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
 		return false, err
 	}
 
-	req, err := http.NewRequest("POST", n.conf.URL.String(), &buf)
+	header.Set("Content-Type", contentType)
+	header.Set("User-Agent", userAgentHeader)
+
+	urls := []string{n.conf.URL.String()}
+	if srv.IsSRV(n.conf.URL.Host) {
+		addrs, err := srv.Lookup(ctx, n.resolver, n.conf.URL.Host)
+		if err != nil {
+			return true, errors.Wrap(err, "resolve webhook URL")
+		}
+		urls = urls[:0]
+		for _, addr := range addrs {
+			u := *n.conf.URL.URL
+			u.Host = addr
+			urls = append(urls, u.String())
+		}
+	}
+	// Fail over to the next targets of SRV records while the errors are
+	// recoverable.
+	var retry bool
+	for _, u := range urls {
+		retry, err = n.post(ctx, u, header, buf.Bytes())
+		if !retry {
+			break
+		}
+		level.Debug(n.logger).Log("msg", "failed to notify webhook", "err", err)
+	}
+	return retry, err
+}
+
+func (n *Notifier) post(ctx context.Context, target string, header http.Header, body []byte) (bool, error) {
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
 	if err != nil {
 		return true, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", userAgentHeader)
+	req.Header = header.Clone()
 
-	resp, err := n.client.Do(req.WithContext(ctx))
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
 	if err != nil {
 		return true, err
 	}
-	notify.Drain(resp)
+	defer notify.Drain(resp)
 
-	return n.retrier.Check(resp.StatusCode, nil)
+	retry, err := n.retrier.CheckResponse(resp, nil)
+	if !n.conf.ResponseControl {
+		return retry, err
+	}
+	ctrl := readControl(resp)
+	switch {
+	case ctrl.Drop:
+		level.Info(n.logger).Log("msg", "Notification dropped by the webhook", "reason", ctrl.Reason, "status", resp.StatusCode)
+		return false, nil
+	case ctrl.RetryAfter > 0:
+		delay := time.Duration(ctrl.RetryAfter) * time.Second
+		var serr *notify.StatusCodeError
+		if errors.As(err, &serr) {
+			serr.RetryAfter = delay
+			return true, err
+		}
+		msg := fmt.Sprintf("webhook asked to retry after %s", delay)
+		if ctrl.Reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, ctrl.Reason)
+		}
+		return true, notify.NewStatusCodeError(resp.StatusCode, delay, msg)
+	}
+	return retry, err
+}
+
+// maxControlSize is the maximum size of the responses read for control
+// fields.
+const maxControlSize = 64 << 10
+
+// control holds the fields of the JSON responses of webhooks controlling the
+// notifications.
+type control struct {
+	// RetryAfter asks to retry the notification after the given number of
+	// seconds, even if the request was successful.
+	RetryAfter int `json:"retry_after"`
+	// Drop asks to consider the notification sent and not to retry it, even
+	// if the request failed.
+	Drop   bool   `json:"drop"`
+	Reason string `json:"reason"`
+}
+
+// readControl returns the control fields of the response, which are ignored
+// unless the response is a valid JSON object.
+func readControl(resp *http.Response) control {
+	var ctrl control
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mt != "application/json" {
+		return ctrl
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxControlSize)).Decode(&ctrl); err != nil {
+		return control{}
+	}
+	return ctrl
 }
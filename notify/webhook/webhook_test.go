@@ -14,15 +14,26 @@
 package webhook
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/notify/test"
 	"github.com/prometheus/alertmanager/types"
 )
@@ -64,3 +75,269 @@ func TestWebhookTruncateAlerts(t *testing.T) {
 	require.Len(t, truncatedAlerts, 10)
 	require.EqualValues(t, numTruncated, 0)
 }
+
+func TestWebhookCloudEvents(t *testing.T) {
+	var (
+		header http.Header
+		body   []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "HighLatency"},
+			StartsAt: time.Now(),
+			EndsAt:   time.Now().Add(time.Hour),
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "1")
+
+	for _, tc := range []struct {
+		mode        string
+		contentType string
+	}{
+		{mode: "", contentType: "application/json"},
+		{mode: config.CloudEventsStructured, contentType: CloudEventsContentType},
+		{mode: config.CloudEventsBinary, contentType: "application/json"},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			notifier, err := New(
+				&config.WebhookConfig{
+					URL:             &config.URL{URL: u},
+					HTTPConfig:      &commoncfg.HTTPClientConfig{},
+					CloudEventsMode: tc.mode,
+				},
+				test.CreateTmpl(t),
+				log.NewNopLogger(),
+			)
+			require.NoError(t, err)
+			_, err = notifier.Notify(ctx, alert)
+			require.NoError(t, err)
+			require.Equal(t, tc.contentType, header.Get("Content-Type"))
+
+			var msg Message
+			switch tc.mode {
+			case config.CloudEventsStructured:
+				ev := cloudEvent{Data: &msg}
+				require.NoError(t, json.Unmarshal(body, &ev))
+				require.Equal(t, CloudEventsSpecVersion, ev.SpecVersion)
+				require.Equal(t, CloudEventsType, ev.Type)
+				require.Equal(t, "http://am", ev.Source)
+				require.Equal(t, "1", ev.Subject)
+				require.NotEmpty(t, ev.ID)
+				require.Equal(t, "application/json", ev.DataContentType)
+			case config.CloudEventsBinary:
+				require.Equal(t, CloudEventsSpecVersion, header.Get("ce-specversion"))
+				require.Equal(t, CloudEventsType, header.Get("ce-type"))
+				require.Equal(t, "http://am", header.Get("ce-source"))
+				require.NotEmpty(t, header.Get("ce-id"))
+				require.NotEmpty(t, header.Get("ce-time"))
+				fallthrough
+			default:
+				require.NoError(t, json.Unmarshal(body, &msg))
+			}
+			require.Equal(t, "4", msg.Version)
+			require.Equal(t, "1", msg.GroupKey)
+			require.Len(t, msg.Alerts, 1)
+		})
+	}
+}
+
+func TestWebhookPayloadV2(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	notifier, err := New(
+		&config.WebhookConfig{
+			URL:            &config.URL{URL: u},
+			HTTPConfig:     &commoncfg.HTTPClientConfig{},
+			MaxAlerts:      1,
+			PayloadVersion: 2,
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	var alerts []*types.Alert
+	for _, instance := range []string{"a b", "c"} {
+		alerts = append(alerts, &types.Alert{
+			Alert: model.Alert{
+				Labels:   model.LabelSet{"alertname": "HighLatency", "instance": model.LabelValue(instance)},
+				StartsAt: time.Now(),
+				EndsAt:   time.Now().Add(time.Hour),
+			},
+		})
+	}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"HighLatency\"}")
+	ctx = notify.WithReceiverName(ctx, "team-X")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "HighLatency"})
+	_, err = notifier.Notify(ctx, alerts...)
+	require.NoError(t, err)
+
+	var msg MessageV2
+	require.NoError(t, json.Unmarshal(body, &msg))
+	require.Equal(t, "2", msg.SchemaVersion)
+	require.Equal(t, "team-X", msg.Receiver)
+	require.Equal(t, "firing", msg.Status)
+	require.Equal(t, "{}:{alertname=\"HighLatency\"}", msg.GroupKey)
+	require.Equal(t, notify.Key(msg.GroupKey).Hash(), msg.GroupID)
+	require.Equal(t, Truncation{TotalAlerts: 2, TruncatedAlerts: 1}, msg.Truncation)
+	require.Len(t, msg.Alerts, 1)
+	require.Equal(t, alerts[0].Fingerprint().String(), msg.Alerts[0].Fingerprint)
+	require.Equal(t, `http://am/#/silences/new?filter=%7Balertname%3D%22HighLatency%22%2Cinstance%3D%22a%20b%22%7D`, msg.Alerts[0].SilenceURL)
+
+	// Version 2 messages can be decoded as legacy messages.
+	var legacy Message
+	require.NoError(t, json.Unmarshal(body, &legacy))
+	require.Equal(t, msg.GroupKey, legacy.GroupKey)
+	require.Equal(t, "a b", legacy.Alerts[0].Labels["instance"])
+}
+
+type fakeResolver map[string][]*net.SRV
+
+func (r fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	return name, r[name], nil
+}
+
+func TestWebhookSRVFailover(t *testing.T) {
+	var calls []string
+	handler := func(name string, code int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/hook", r.URL.Path)
+			calls = append(calls, name)
+			w.WriteHeader(code)
+		}))
+	}
+	srvRecord := func(s *httptest.Server) *net.SRV {
+		u, err := url.Parse(s.URL)
+		require.NoError(t, err)
+		port, err := strconv.Atoi(u.Port())
+		require.NoError(t, err)
+		return &net.SRV{Target: u.Hostname() + ".", Port: uint16(port)}
+	}
+	unavailable := handler("unavailable", http.StatusServiceUnavailable)
+	defer unavailable.Close()
+	rejecting := handler("rejecting", http.StatusBadRequest)
+	defer rejecting.Close()
+	ok := handler("ok", http.StatusOK)
+	defer ok.Close()
+
+	u, err := url.Parse("http://srv+_alerts._tcp.example.com/hook")
+	require.NoError(t, err)
+	notifier, err := New(
+		&config.WebhookConfig{
+			URL:        &config.URL{URL: u},
+			HTTPConfig: &commoncfg.HTTPClientConfig{},
+		},
+		test.CreateTmpl(t),
+		log.NewNopLogger(),
+	)
+	require.NoError(t, err)
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "HighLatency"},
+			StartsAt: time.Now(),
+		},
+	}
+
+	// Recoverable errors fail over to the next target.
+	notifier.resolver = fakeResolver{
+		"_alerts._tcp.example.com": {srvRecord(unavailable), srvRecord(ok)},
+	}
+	retry, err := notifier.Notify(ctx, alert)
+	require.NoError(t, err)
+	require.False(t, retry)
+	require.Equal(t, []string{"unavailable", "ok"}, calls)
+
+	// Other errors don't.
+	calls = nil
+	notifier.resolver = fakeResolver{
+		"_alerts._tcp.example.com": {srvRecord(rejecting), srvRecord(ok)},
+	}
+	retry, err = notifier.Notify(ctx, alert)
+	require.Error(t, err)
+	require.False(t, retry)
+	require.Equal(t, []string{"rejecting"}, calls)
+}
+
+func TestWebhookResponseControl(t *testing.T) {
+	var (
+		code     int
+		response string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		fmt.Fprint(w, response)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	conf := &config.WebhookConfig{
+		URL:             &config.URL{URL: u},
+		HTTPConfig:      &commoncfg.HTTPClientConfig{},
+		ResponseControl: true,
+	}
+	notifier, err := New(conf, test.CreateTmpl(t), log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := notify.WithGroupKey(context.Background(), "1")
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "HighLatency"},
+			StartsAt: time.Now(),
+		},
+	}
+
+	for _, tc := range []struct {
+		code       int
+		response   string
+		retry      bool
+		retryAfter time.Duration
+		err        bool
+	}{
+		{code: http.StatusOK, response: `{}`},
+		{code: http.StatusOK, response: `not json`},
+		{code: http.StatusOK, response: `{"retry_after": 30, "reason": "busy"}`, retry: true, retryAfter: 30 * time.Second, err: true},
+		{code: http.StatusServiceUnavailable, response: `{"retry_after": 10}`, retry: true, retryAfter: 10 * time.Second, err: true},
+		{code: http.StatusServiceUnavailable, response: `{"drop": true}`},
+		{code: http.StatusBadRequest, response: `{}`, err: true},
+	} {
+		t.Run(fmt.Sprintf("%d %s", tc.code, tc.response), func(t *testing.T) {
+			code, response = tc.code, tc.response
+			retry, err := notifier.Notify(ctx, alert)
+			require.Equal(t, tc.retry, retry)
+			if !tc.err {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var serr *notify.StatusCodeError
+			require.True(t, errors.As(err, &serr))
+			require.Equal(t, tc.code, serr.StatusCode)
+			require.Equal(t, tc.retryAfter, serr.RetryAfter)
+		})
+	}
+
+	// The responses are ignored unless enabled.
+	conf.ResponseControl = false
+	code, response = http.StatusOK, `{"retry_after": 30}`
+	retry, err := notifier.Notify(ctx, alert)
+	require.NoError(t, err)
+	require.False(t, retry)
+}
@@ -72,7 +72,7 @@ type weChatResponse struct {
 
 // New returns a new Wechat notifier.
 func New(c *config.WechatConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
-	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "wechat", append(httpOpts, commoncfg.WithHTTP2Disabled())...)
+	client, err := commoncfg.NewClientFromConfig(*c.HTTPConfig, "wechat", httpOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -114,8 +114,10 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 		}
 
 		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(ctx)
+		notify.SetRequestOptions(req)
 
-		resp, err := n.client.Do(req.WithContext(ctx))
+		resp, err := n.client.Do(req)
 		if err != nil {
 			return true, notify.RedactURL(err)
 		}
@@ -173,7 +175,7 @@ func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error)
 		return true, err
 	}
 
-	resp, err := n.client.Do(req.WithContext(ctx))
+	resp, err := notify.Do(n.client, req.WithContext(ctx))
 	if err != nil {
 		return true, notify.RedactURL(err)
 	}
@@ -0,0 +1,221 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package persist stores the state of the Alertmanager in an embedded BoltDB
+// file, so that active alerts, silences and the notification log survive
+// restarts. The state is written periodically and on shutdown, and replayed
+// on start.
+package persist
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Names of the snapshots held by the DB.
+const (
+	SnapshotSilences = "silences"
+	SnapshotNflog    = "nflog"
+)
+
+var (
+	bucketAlerts    = []byte("alerts")
+	bucketSnapshots = []byte("snapshots")
+)
+
+// DB is a BoltDB file holding the persisted state. All methods are
+// goroutine-safe.
+type DB struct {
+	db      *bolt.DB
+	logger  log.Logger
+	metrics *metrics
+}
+
+type metrics struct {
+	writeDuration prometheus.Summary
+	writeFailures prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		writeDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Name:       "alertmanager_persist_write_duration_seconds",
+			Help:       "Duration of the last write of state to the persistence file.",
+			Objectives: map[float64]float64{},
+		}),
+		writeFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_persist_write_failures_total",
+			Help: "Total number of failed writes of state to the persistence file.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(m.writeDuration, m.writeFailures)
+	}
+	return m
+}
+
+// Open opens the BoltDB file at the given path, creating it if it doesn't
+// exist.
+func Open(path string, l log.Logger, r prometheus.Registerer) (*DB, error) {
+	db, err := bolt.Open(path, 0666, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{bucketAlerts, bucketSnapshots} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	return &DB{
+		db:      db,
+		logger:  l,
+		metrics: newMetrics(r),
+	}, nil
+}
+
+// Close closes the file.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// update runs fn in a write transaction and records its outcome.
+func (d *DB) update(fn func(tx *bolt.Tx) error) error {
+	start := time.Now()
+	err := d.db.Update(fn)
+	d.metrics.writeDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		d.metrics.writeFailures.Inc()
+	}
+	return err
+}
+
+// SaveSnapshot replaces the snapshot with the given name by the one written
+// by the snapshot function, e.g. (*silence.Silences).Snapshot. It returns the
+// size of the snapshot.
+func (d *DB) SaveSnapshot(name string, snapshot func(io.Writer) (int64, error)) (int64, error) {
+	var buf bytes.Buffer
+	size, err := snapshot(&buf)
+	if err != nil {
+		return size, err
+	}
+	return size, d.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSnapshots).Put([]byte(name), buf.Bytes())
+	})
+}
+
+// LoadSnapshot returns a reader of the snapshot with the given name, or nil
+// if there is none.
+func (d *DB) LoadSnapshot(name string) (io.Reader, error) {
+	var b []byte
+	err := d.db.View(func(tx *bolt.Tx) error {
+		// Values are only valid during the transaction.
+		if v := tx.Bucket(bucketSnapshots).Get([]byte(name)); v != nil {
+			b = append([]byte{}, v...)
+		}
+		return nil
+	})
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// SaveAlerts replaces the persisted alerts by the alerts of the provider.
+func (d *DB) SaveAlerts(p provider.Alerts) error {
+	var alerts []*types.Alert
+	it := p.GetPending()
+	for a := range it.Next() {
+		alerts = append(alerts, a)
+	}
+	it.Close()
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	return d.update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(bucketAlerts); err != nil {
+			return err
+		}
+		b, err := tx.CreateBucket(bucketAlerts)
+		if err != nil {
+			return err
+		}
+		for _, a := range alerts {
+			v, err := json.Marshal(a)
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(a.Fingerprint().String()), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MaintainAlerts saves the alerts of the provider at the given interval and
+// a last time after receiving from stopc.
+func (d *DB) MaintainAlerts(interval time.Duration, p provider.Alerts, stopc <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			if err := d.SaveAlerts(p); err != nil {
+				level.Error(d.logger).Log("msg", "Saving alerts on shutdown failed", "err", err)
+			}
+			return
+		case <-t.C:
+			if err := d.SaveAlerts(p); err != nil {
+				level.Error(d.logger).Log("msg", "Saving alerts failed", "err", err)
+			}
+		}
+	}
+}
+
+// LoadAlerts returns the persisted alerts.
+func (d *DB) LoadAlerts() ([]*types.Alert, error) {
+	var alerts []*types.Alert
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketAlerts).ForEach(func(_, v []byte) error {
+			var a types.Alert
+			if err := json.Unmarshal(v, &a); err != nil {
+				return err
+			}
+			alerts = append(alerts, &a)
+			return nil
+		})
+	})
+	return alerts, err
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func openTestDB(t *testing.T) (*DB, string) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "persist")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "alertmanager.db")
+	db, err := Open(path, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+	return db, path
+}
+
+func TestSnapshot(t *testing.T) {
+	db, path := openTestDB(t)
+
+	r, err := db.LoadSnapshot(SnapshotSilences)
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	size, err := db.SaveSnapshot(SnapshotSilences, func(w io.Writer) (int64, error) {
+		n, err := w.Write([]byte("state"))
+		return int64(n), err
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), size)
+	require.NoError(t, db.Close())
+
+	// The snapshot survives reopening the file.
+	db, err = Open(path, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer db.Close()
+
+	r, err = db.LoadSnapshot(SnapshotSilences)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(r)
+	require.NoError(t, err)
+	require.Equal(t, "state", buf.String())
+
+	r, err = db.LoadSnapshot(SnapshotNflog)
+	require.NoError(t, err)
+	require.Nil(t, r)
+}
+
+func TestAlerts(t *testing.T) {
+	db, _ := openTestDB(t)
+	defer db.Close()
+
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	a1 := &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "a"},
+			Annotations: model.LabelSet{"summary": "first"},
+			StartsAt:    now,
+			EndsAt:      now.Add(time.Hour),
+		},
+		UpdatedAt: now,
+		Timeout:   true,
+	}
+	a2 := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "b"},
+			StartsAt: now,
+			EndsAt:   now.Add(time.Hour),
+		},
+		UpdatedAt: now,
+	}
+	require.NoError(t, alerts.Put(a1, a2))
+	require.NoError(t, db.SaveAlerts(alerts))
+
+	loaded, err := db.LoadAlerts()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*types.Alert{a1, a2}, loaded)
+
+	// Saving replaces all previously saved alerts.
+	empty, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer empty.Close()
+	require.NoError(t, db.SaveAlerts(empty))
+
+	loaded, err = db.LoadAlerts()
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+}
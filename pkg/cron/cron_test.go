@@ -0,0 +1,84 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"CRON_TZ=Nowhere/Special * * * * *",
+		"CRON_TZ=UTC",
+	} {
+		_, err := Parse(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestNext(t *testing.T) {
+	ts := func(s string) time.Time {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	for _, tc := range []struct {
+		spec     string
+		from     string
+		expected string
+	}{
+		{"* * * * *", "2021-08-10T10:15:30Z", "2021-08-10T10:16:00Z"},
+		{"* * * * *", "2021-08-10T10:15:00Z", "2021-08-10T10:16:00Z"},
+		{"*/15 * * * *", "2021-08-10T10:15:00Z", "2021-08-10T10:30:00Z"},
+		{"5/20 * * * *", "2021-08-10T10:46:00Z", "2021-08-10T11:05:00Z"},
+		{"0 22 * * sat", "2021-08-10T10:15:00Z", "2021-08-14T22:00:00Z"},
+		{"30 2 * * 1-5", "2021-08-13T03:00:00Z", "2021-08-16T02:30:00Z"},
+		{"0 0 * * 7", "2021-08-10T00:00:00Z", "2021-08-15T00:00:00Z"},
+		{"0 0 1,15 * *", "2021-08-02T00:00:00Z", "2021-08-15T00:00:00Z"},
+		// Day of month and day of week are ORed if both are restricted.
+		{"0 0 13 * fri", "2021-08-10T00:00:00Z", "2021-08-13T00:00:00Z"},
+		{"0 0 13 * mon", "2021-08-10T00:00:00Z", "2021-08-13T00:00:00Z"},
+		{"0 0 29 feb *", "2021-03-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"@monthly", "2021-12-10T10:15:00Z", "2022-01-01T00:00:00Z"},
+		{"@hourly", "2021-12-31T23:15:00Z", "2022-01-01T00:00:00Z"},
+		{"CRON_TZ=Europe/Berlin 0 22 * * *", "2021-08-10T10:15:00Z", "2021-08-10T20:00:00Z"},
+		{"CRON_TZ=Europe/Berlin 0 22 * * *", "2021-12-10T10:15:00Z", "2021-12-10T21:00:00Z"},
+		{"0 0 30 feb *", "2021-08-10T10:15:00Z", ""},
+	} {
+		s, err := Parse(tc.spec)
+		require.NoError(t, err, tc.spec)
+		next := s.Next(ts(tc.from))
+		if tc.expected == "" {
+			require.True(t, next.IsZero(), "%s: unexpected %s", tc.spec, next)
+			continue
+		}
+		require.True(t, ts(tc.expected).Equal(next), "%s from %s: expected %s, got %s", tc.spec, tc.from, tc.expected, next)
+	}
+}
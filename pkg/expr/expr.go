@@ -0,0 +1,414 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package expr parses and evaluates the expressions matching alerts on their
+// labels and annotations.
+//
+// An expression combines comparisons with &&, || and !, and parentheses:
+//
+//	labels.replicas < 3 && labels.env != "dev"
+//	annotations.runbook =~ "https://.*" || labels["app.kubernetes.io/name"] in ["api", "web"]
+//
+// The operands of the comparisons are labels (labels.name or
+// labels["name"]), annotations (annotations.name or annotations["name"]),
+// double-quoted strings and numbers. Missing labels and annotations are empty.
+// == and != compare numerically if an operand is a number and as strings
+// otherwise. <, <=, > and >= only hold if both values are numbers. =~ and !~
+// match a quoted regular expression, anchored on both ends. in matches a
+// list of quoted strings.
+package expr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+)
+
+// Expr is a parsed expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse parses an expression.
+func Parse(s string) (*Expr, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errors.Errorf("unexpected %s at position %d", t, t.pos)
+	}
+	return &Expr{src: s, root: root}, nil
+}
+
+// String returns the expression as it was parsed.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Matches returns true if the labels and annotations satisfy the expression.
+func (e *Expr) Matches(labels, annotations model.LabelSet) bool {
+	return e.root.eval(env{labels: labels, annotations: annotations})
+}
+
+type env struct {
+	labels, annotations model.LabelSet
+}
+
+type node interface {
+	eval(env) bool
+}
+
+type andNode struct{ l, r node }
+
+func (n andNode) eval(e env) bool { return n.l.eval(e) && n.r.eval(e) }
+
+type orNode struct{ l, r node }
+
+func (n orNode) eval(e env) bool { return n.l.eval(e) || n.r.eval(e) }
+
+type notNode struct{ n node }
+
+func (n notNode) eval(e env) bool { return !n.n.eval(e) }
+
+type operandKind int
+
+const (
+	operandLiteral operandKind = iota
+	operandLabel
+	operandAnnotation
+)
+
+type operand struct {
+	kind  operandKind
+	value string
+	// Whether the operand is a number literal.
+	number bool
+}
+
+func (o operand) eval(e env) string {
+	switch o.kind {
+	case operandLabel:
+		return string(e.labels[model.LabelName(o.value)])
+	case operandAnnotation:
+		return string(e.annotations[model.LabelName(o.value)])
+	}
+	return o.value
+}
+
+type compareNode struct {
+	op   string
+	l, r operand
+}
+
+func (n compareNode) eval(e env) bool {
+	l, r := n.l.eval(e), n.r.eval(e)
+	lf, lerr := strconv.ParseFloat(l, 64)
+	rf, rerr := strconv.ParseFloat(r, 64)
+	numeric := lerr == nil && rerr == nil
+	switch n.op {
+	case "==":
+		if n.l.number || n.r.number {
+			return numeric && lf == rf
+		}
+		return l == r
+	case "!=":
+		if n.l.number || n.r.number {
+			return !numeric || lf != rf
+		}
+		return l != r
+	case "<":
+		return numeric && lf < rf
+	case "<=":
+		return numeric && lf <= rf
+	case ">":
+		return numeric && lf > rf
+	case ">=":
+		return numeric && lf >= rf
+	}
+	return false
+}
+
+type regexNode struct {
+	o   operand
+	re  *regexp.Regexp
+	neg bool
+}
+
+func (n regexNode) eval(e env) bool { return n.re.MatchString(n.o.eval(e)) != n.neg }
+
+type inNode struct {
+	o      operand
+	values map[string]struct{}
+}
+
+func (n inNode) eval(e env) bool {
+	_, ok := n.values[n.o.eval(e)]
+	return ok
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.val)
+}
+
+// The operators, longest first.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				return nil, errors.Errorf("unterminated string at position %d", i)
+			}
+			v, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return nil, errors.Errorf("invalid string at position %d", i)
+			}
+			toks = append(toks, token{tokString, v, i})
+			i = j + 1
+		case c == '-' || c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' || s[j] == 'e' || s[j] == 'E') {
+				j++
+			}
+			if _, err := strconv.ParseFloat(s[i:j], 64); err != nil {
+				return nil, errors.Errorf("invalid number %q at position %d", s[i:j], i)
+			}
+			toks = append(toks, token{tokNumber, s[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(c):
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, s[i:j], i})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.Errorf("unexpected character %q at position %d", c, i)
+			}
+			toks = append(toks, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(s)}), nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.val == op
+}
+
+func (p *parser) expect(op string) error {
+	if t := p.next(); t.kind != tokOp || t.val != op {
+		return errors.Errorf("expected %q, got %s at position %d", op, t, t.pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = orNode{l, r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = andNode{l, r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOp("!") {
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	}
+	if p.isOp("(") {
+		p.next()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.next()
+	switch {
+	case t.kind == tokOp && (t.val == "==" || t.val == "!=" || t.val == "<" || t.val == "<=" || t.val == ">" || t.val == ">="):
+		r, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{op: t.val, l: l, r: r}, nil
+	case t.kind == tokOp && (t.val == "=~" || t.val == "!~"):
+		s := p.next()
+		if s.kind != tokString {
+			return nil, errors.Errorf("expected a quoted regular expression, got %s at position %d", s, s.pos)
+		}
+		re, err := regexp.Compile("^(?:" + s.val + ")$")
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid regular expression at position %d", s.pos)
+		}
+		return regexNode{o: l, re: re, neg: t.val == "!~"}, nil
+	case t.kind == tokIdent && t.val == "in":
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		values := map[string]struct{}{}
+		for !p.isOp("]") {
+			if len(values) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			s := p.next()
+			if s.kind != tokString {
+				return nil, errors.Errorf("expected a quoted string, got %s at position %d", s, s.pos)
+			}
+			values[s.val] = struct{}{}
+		}
+		p.next()
+		return inNode{o: l, values: values}, nil
+	}
+	return nil, errors.Errorf("expected a comparison operator, got %s at position %d", t, t.pos)
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString, tokNumber:
+		return operand{kind: operandLiteral, value: t.val, number: t.kind == tokNumber}, nil
+	case tokIdent:
+		var kind operandKind
+		switch t.val {
+		case "labels":
+			kind = operandLabel
+		case "annotations":
+			kind = operandAnnotation
+		default:
+			return operand{}, errors.Errorf("unknown identifier %s at position %d, expected labels or annotations", t, t.pos)
+		}
+		var name token
+		if p.isOp("[") {
+			p.next()
+			if name = p.next(); name.kind != tokString {
+				return operand{}, errors.Errorf("expected a quoted name, got %s at position %d", name, name.pos)
+			}
+			if err := p.expect("]"); err != nil {
+				return operand{}, err
+			}
+		} else {
+			if err := p.expect("."); err != nil {
+				return operand{}, err
+			}
+			if name = p.next(); name.kind != tokIdent {
+				return operand{}, errors.Errorf("expected a name, got %s at position %d", name, name.pos)
+			}
+		}
+		return operand{kind: kind, value: name.val}, nil
+	}
+	return operand{}, errors.Errorf("expected a label, annotation, string or number, got %s at position %d", t, t.pos)
+}
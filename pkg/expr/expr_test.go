@@ -0,0 +1,94 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expr
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"labels.env",
+		"labels.env ==",
+		"labels.env = \"dev\"",
+		"env == \"dev\"",
+		"labels.",
+		"labels[env] == \"dev\"",
+		"labels.env == \"dev",
+		"labels.env =~ \"(\"",
+		"labels.env =~ dev",
+		"labels.env in [\"dev\" \"prod\"]",
+		"labels.env in [1]",
+		"(labels.env == \"dev\"",
+		"labels.env == \"dev\")",
+		"labels.env == \"dev\" &&",
+		"labels.env == \"dev\" # comment",
+		"labels.replicas < 1.2.3",
+	} {
+		_, err := Parse(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	labels := model.LabelSet{
+		"env":                    "prod",
+		"replicas":               "2",
+		"version":                "1.10",
+		"app.kubernetes.io/name": "api",
+	}
+	annotations := model.LabelSet{
+		"runbook": "https://runbooks.example.com/api",
+	}
+	for _, tc := range []struct {
+		expr string
+		exp  bool
+	}{
+		{`labels.replicas < 3 && labels.env != "dev"`, true},
+		{`labels.replicas < 2`, false},
+		{`labels.replicas <= 2`, true},
+		{`labels.replicas == 2.0`, true},
+		{`labels.replicas > -1`, true},
+		{`labels.env >= 1`, false},
+		{`labels.version == "1.1"`, false},
+		{`labels.version == 1.1`, true},
+		{`labels.env != 1`, true},
+		{`labels.replicas != "2.0"`, true},
+		{`labels.version < 1.9`, true},
+		{`labels.missing == ""`, true},
+		{`labels.missing < 3`, false},
+		{`labels.env == "dev" || labels.env == "prod"`, true},
+		{`!(labels.env == "prod")`, false},
+		{`!labels.env == "dev"`, true},
+		{`labels.env == "dev" || labels.env == "prod" && labels.replicas > 5`, false},
+		{`(labels.env == "dev" || labels.env == "prod") && labels.replicas < 5`, true},
+		{`labels.env =~ "pro.*"`, true},
+		{`labels.env =~ "pro"`, false},
+		{`labels.env !~ "dev|staging"`, true},
+		{`labels["app.kubernetes.io/name"] in ["api", "web"]`, true},
+		{`labels.env in []`, false},
+		{`annotations.runbook =~ "https://.*"`, true},
+		{`annotations["runbook"] == labels.env`, false},
+		{`labels.env == "prod"`, true},
+	} {
+		e, err := Parse(tc.expr)
+		require.NoError(t, err, tc.expr)
+		require.Equal(t, tc.expr, e.String())
+		require.Equal(t, tc.exp, e.Matches(labels, annotations), tc.expr)
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relabel
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestProcess(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    model.LabelSet
+		configs  []*Config
+		expected model.LabelSet
+	}{
+		{
+			name:     "no configs",
+			input:    model.LabelSet{"a": "foo"},
+			expected: model.LabelSet{"a": "foo"},
+		},
+		{
+			name:  "replace",
+			input: model.LabelSet{"a": "foo", "b": "bar"},
+			configs: []*Config{
+				{
+					SourceLabels: model.LabelNames{"a", "b"},
+					Separator:    ";",
+					Regex:        MustNewRegexp("f(.*);(.*)r"),
+					TargetLabel:  "c",
+					Replacement:  "ch${1}-ch${2}",
+					Action:       Replace,
+				},
+			},
+			expected: model.LabelSet{"a": "foo", "b": "bar", "c": "choo-chba"},
+		},
+		{
+			name:  "replace with empty value deletes the target",
+			input: model.LabelSet{"a": "foo", "c": "baz"},
+			configs: []*Config{
+				{
+					SourceLabels: model.LabelNames{"b"},
+					Separator:    ";",
+					Regex:        MustNewRegexp("(.*)"),
+					TargetLabel:  "c",
+					Replacement:  "$1",
+					Action:       Replace,
+				},
+			},
+			expected: model.LabelSet{"a": "foo"},
+		},
+		{
+			name:  "replace with interpolated target",
+			input: model.LabelSet{"a": "some-name-value"},
+			configs: []*Config{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Separator:    ";",
+					Regex:        MustNewRegexp("some-([^-]+)-([^,]+)"),
+					TargetLabel:  "${1}",
+					Replacement:  "${2}",
+					Action:       Replace,
+				},
+			},
+			expected: model.LabelSet{"a": "some-name-value", "name": "value"},
+		},
+		{
+			name:  "drop",
+			input: model.LabelSet{"a": "foo"},
+			configs: []*Config{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Regex:        MustNewRegexp("f.*"),
+					Action:       Drop,
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:  "keep",
+			input: model.LabelSet{"a": "foo"},
+			configs: []*Config{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					Regex:        MustNewRegexp("b.*"),
+					Action:       Keep,
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:  "hashmod",
+			input: model.LabelSet{"a": "foo"},
+			configs: []*Config{
+				{
+					SourceLabels: model.LabelNames{"a"},
+					TargetLabel:  "shard",
+					Modulus:      1000,
+					Action:       HashMod,
+				},
+			},
+			expected: model.LabelSet{"a": "foo", "shard": "696"},
+		},
+		{
+			name:  "labelmap",
+			input: model.LabelSet{"a": "foo", "b_x": "bar"},
+			configs: []*Config{
+				{
+					Regex:       MustNewRegexp("b_(.*)"),
+					Replacement: "c_$1",
+					Action:      LabelMap,
+				},
+			},
+			expected: model.LabelSet{"a": "foo", "b_x": "bar", "c_x": "bar"},
+		},
+		{
+			name:  "labeldrop and labelkeep",
+			input: model.LabelSet{"a": "foo", "b": "bar", "pod": "web-1"},
+			configs: []*Config{
+				{
+					Regex:  MustNewRegexp("pod"),
+					Action: LabelDrop,
+				},
+				{
+					Regex:  MustNewRegexp("a"),
+					Action: LabelKeep,
+				},
+			},
+			expected: model.LabelSet{"a": "foo"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			input := tc.input.Clone()
+			require.Equal(t, tc.expected, Process(input, tc.configs...))
+			require.Equal(t, tc.input, input, "input label set must not be modified")
+		})
+	}
+}
+
+func TestConfigUnmarshalYAML(t *testing.T) {
+	var c Config
+	require.NoError(t, yaml.Unmarshal([]byte("target_label: env\nsource_labels: [environment]\n"), &c))
+	require.Equal(t, Replace, c.Action)
+	require.Equal(t, ";", c.Separator)
+	require.Equal(t, "$1", c.Replacement)
+	require.Equal(t, "(.*)", c.Regex.original)
+
+	for _, tc := range []struct {
+		in       string
+		expected string
+	}{
+		{
+			in:       "action: replace\n",
+			expected: "relabel configuration for replace action requires 'target_label' value",
+		},
+		{
+			in:       "action: hashmod\ntarget_label: shard\n",
+			expected: "relabel configuration for hashmod requires non-zero modulus",
+		},
+		{
+			in:       "action: labeldrop\ntarget_label: foo\n",
+			expected: "labeldrop action requires only 'regex', and no other fields",
+		},
+		{
+			in:       "action: unknown\n",
+			expected: `unknown relabel action "unknown"`,
+		},
+		{
+			in:       "target_label: 1foo\n",
+			expected: `"1foo" is invalid 'target_label' for replace action`,
+		},
+	} {
+		var c Config
+		require.EqualError(t, yaml.Unmarshal([]byte(tc.in), &c), tc.expected)
+	}
+}
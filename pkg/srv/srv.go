@@ -0,0 +1,62 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package srv resolves the DNS SRV records given in place of hosts, as
+// srv+<name>, to the addresses of their targets.
+package srv
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Prefix marks the hosts which are the names of SRV records.
+const Prefix = "srv+"
+
+// Resolver looks up SRV records. It is implemented by net.Resolver.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// IsSRV returns whether the host is the name of an SRV record.
+func IsSRV(host string) bool {
+	return strings.HasPrefix(host, Prefix)
+}
+
+// Lookup returns the targets of the SRV record named by the host as host:port
+// addresses, ordered by priority and randomized by weight, to be tried in
+// turn.
+func Lookup(ctx context.Context, r Resolver, host string) ([]string, error) {
+	name := strings.TrimPrefix(host, Prefix)
+	_, records, err := r.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(records))
+	for _, rec := range records {
+		// A target of "." means that the service isn't available.
+		target := strings.TrimSuffix(rec.Target, ".")
+		if target == "" {
+			continue
+		}
+		addrs = append(addrs, net.JoinHostPort(target, strconv.Itoa(int(rec.Port))))
+	}
+	if len(addrs) == 0 {
+		return nil, errors.Errorf("no target for SRV record %q", name)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package srv
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver map[string][]*net.SRV
+
+func (r fakeResolver) LookupSRV(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	records, ok := r[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return name, records, nil
+}
+
+func TestLookup(t *testing.T) {
+	r := fakeResolver{
+		"_smtp._tcp.example.com": {
+			{Target: "mx1.example.com.", Port: 25},
+			{Target: "mx2.example.com.", Port: 2525},
+		},
+		"_smtp._tcp.example.org": {
+			{Target: ".", Port: 0},
+		},
+	}
+
+	addrs, err := Lookup(context.Background(), r, "srv+_smtp._tcp.example.com")
+	require.NoError(t, err)
+	require.Equal(t, []string{"mx1.example.com:25", "mx2.example.com:2525"}, addrs)
+
+	_, err = Lookup(context.Background(), r, "srv+_smtp._tcp.example.org")
+	require.EqualError(t, err, `no target for SRV record "_smtp._tcp.example.org"`)
+
+	_, err = Lookup(context.Background(), r, "srv+_smtp._tcp.example.net")
+	require.Error(t, err)
+}
+
+func TestIsSRV(t *testing.T) {
+	require.True(t, IsSRV("srv+_smtp._tcp.example.com"))
+	require.False(t, IsSRV("smtp.example.com"))
+}
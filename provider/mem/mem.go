@@ -20,6 +20,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 
 	"github.com/prometheus/alertmanager/provider"
@@ -29,17 +30,26 @@ import (
 
 const alertChannelLength = 200
 
+// numShards is the number of stores the alerts are spread across by their
+// fingerprint. Listing all alerts locks one shard at a time, so that large
+// reads do not block the ingestion of alerts.
+const numShards = 32
+
 // Alerts gives access to a set of alerts. All methods are goroutine-safe.
 type Alerts struct {
 	cancel context.CancelFunc
 
+	shards [numShards]*store.Alerts
+
 	mtx       sync.Mutex
-	alerts    *store.Alerts
 	listeners map[int]listeningAlerts
 	next      int
 
 	callback AlertStoreCallback
 
+	gcDuration prometheus.Summary
+	gcRemoved  prometheus.Counter
+
 	logger log.Logger
 }
 
@@ -62,45 +72,109 @@ type listeningAlerts struct {
 	done   chan struct{}
 }
 
-// NewAlerts returns a new alert provider.
-func NewAlerts(ctx context.Context, m types.Marker, intervalGC time.Duration, alertCallback AlertStoreCallback, l log.Logger) (*Alerts, error) {
+// NewAlerts returns a new alert provider. Resolved alerts are garbage
+// collected every intervalGC once they have been resolved for longer than
+// the retention.
+func NewAlerts(
+	ctx context.Context,
+	m types.Marker,
+	intervalGC time.Duration,
+	retention time.Duration,
+	alertCallback AlertStoreCallback,
+	l log.Logger,
+	r prometheus.Registerer,
+) (*Alerts, error) {
 	if alertCallback == nil {
 		alertCallback = noopCallback{}
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	a := &Alerts{
-		alerts:    store.NewAlerts(),
 		cancel:    cancel,
 		listeners: map[int]listeningAlerts{},
 		next:      0,
 		logger:    log.With(l, "component", "provider"),
 		callback:  alertCallback,
+		gcDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Name:       "alertmanager_alerts_gc_duration_seconds",
+			Help:       "Duration of the last alert garbage collection cycle.",
+			Objectives: map[float64]float64{},
+		}),
+		gcRemoved: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_alerts_gc_removed_total",
+			Help: "The total number of resolved alerts removed by garbage collection.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(a.gcDuration, a.gcRemoved)
 	}
-	a.alerts.SetGCCallback(func(alerts []*types.Alert) {
-		for _, alert := range alerts {
-			// As we don't persist alerts, we no longer consider them after
-			// they are resolved. Alerts waiting for resolved notifications are
-			// held in memory in aggregation groups redundantly.
-			m.Delete(alert.Fingerprint())
-			a.callback.PostDelete(alert)
+	for i := range a.shards {
+		a.shards[i] = store.NewAlerts()
+		a.shards[i].SetRetention(retention)
+		a.shards[i].SetGCCallback(func(alerts []*types.Alert) {
+			a.gcRemoved.Add(float64(len(alerts)))
+			for _, alert := range alerts {
+				// As we don't persist alerts, we no longer consider them after
+				// they are resolved. Alerts waiting for resolved notifications are
+				// held in memory in aggregation groups redundantly.
+				m.Delete(alert.Fingerprint())
+				a.callback.PostDelete(alert)
+			}
+		})
+	}
+	go a.runGC(ctx, intervalGC)
+
+	return a, nil
+}
+
+// runGC periodically removes resolved alerts and closed listeners. The
+// interval must be greater than zero.
+func (a *Alerts) runGC(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			a.gc()
 		}
+	}
+}
 
-		a.mtx.Lock()
-		for i, l := range a.listeners {
-			select {
-			case <-l.done:
-				delete(a.listeners, i)
-				close(l.alerts)
-			default:
-				// listener is not closed yet, hence proceed.
-			}
+func (a *Alerts) gc() {
+	start := time.Now()
+	for _, s := range a.shards {
+		s.GC()
+	}
+	a.gcDuration.Observe(time.Since(start).Seconds())
+
+	a.mtx.Lock()
+	for i, l := range a.listeners {
+		select {
+		case <-l.done:
+			delete(a.listeners, i)
+			close(l.alerts)
+		default:
+			// listener is not closed yet, hence proceed.
 		}
-		a.mtx.Unlock()
-	})
-	go a.alerts.Run(ctx, intervalGC)
+	}
+	a.mtx.Unlock()
+}
 
-	return a, nil
+// shard returns the store holding the alert with the given fingerprint.
+func (a *Alerts) shard(fp model.Fingerprint) *store.Alerts {
+	return a.shards[uint64(fp)%numShards]
+}
+
+// list returns all alerts. The shards are locked one after another, so the
+// result is not a consistent snapshot of all shards.
+func (a *Alerts) list() []*types.Alert {
+	var alerts []*types.Alert
+	for _, s := range a.shards {
+		alerts = append(alerts, s.List()...)
+	}
+	return alerts
 }
 
 // Close the alert provider.
@@ -126,7 +200,7 @@ func (a *Alerts) Subscribe() provider.AlertIterator {
 
 	var (
 		done   = make(chan struct{})
-		alerts = a.alerts.List()
+		alerts = a.list()
 		ch     = make(chan *types.Alert, max(len(alerts), alertChannelLength))
 	)
 
@@ -151,7 +225,7 @@ func (a *Alerts) GetPending() provider.AlertIterator {
 	go func() {
 		defer close(ch)
 
-		for _, a := range a.alerts.List() {
+		for _, a := range a.list() {
 			select {
 			case ch <- a:
 			case <-done:
@@ -165,7 +239,16 @@ func (a *Alerts) GetPending() provider.AlertIterator {
 
 // Get returns the alert for a given fingerprint.
 func (a *Alerts) Get(fp model.Fingerprint) (*types.Alert, error) {
-	return a.alerts.Get(fp)
+	return a.shard(fp).Get(fp)
+}
+
+// Count returns the number of alerts held in memory.
+func (a *Alerts) Count() int {
+	var n int
+	for _, s := range a.shards {
+		n += s.Len()
+	}
+	return n
 }
 
 // Put adds the given alert to the set.
@@ -177,7 +260,7 @@ func (a *Alerts) Put(alerts ...*types.Alert) error {
 
 		// Check that there's an alert existing within the store before
 		// trying to merge.
-		if old, err := a.alerts.Get(fp); err == nil {
+		if old, err := a.shard(fp).Get(fp); err == nil {
 			existing = true
 
 			// Merge alerts if there is an overlap in activity range.
@@ -192,7 +275,7 @@ func (a *Alerts) Put(alerts ...*types.Alert) error {
 			continue
 		}
 
-		if err := a.alerts.Set(alert); err != nil {
+		if err := a.shard(fp).Set(alert); err != nil {
 			level.Error(a.logger).Log("msg", "error on set alert", "err", err)
 			continue
 		}
@@ -26,6 +26,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
@@ -87,7 +88,7 @@ func init() {
 // a listener can not unsubscribe as the lock is hold by `alerts.Lock`.
 func TestAlertsSubscribePutStarvation(t *testing.T) {
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := NewAlerts(context.Background(), marker, 30*time.Minute, noopCallback{}, log.NewNopLogger())
+	alerts, err := NewAlerts(context.Background(), marker, 30*time.Minute, 0, noopCallback{}, log.NewNopLogger(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -138,7 +139,7 @@ func TestAlertsSubscribePutStarvation(t *testing.T) {
 
 func TestAlertsPut(t *testing.T) {
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := NewAlerts(context.Background(), marker, 30*time.Minute, noopCallback{}, log.NewNopLogger())
+	alerts, err := NewAlerts(context.Background(), marker, 30*time.Minute, 0, noopCallback{}, log.NewNopLogger(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -166,7 +167,7 @@ func TestAlertsSubscribe(t *testing.T) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	alerts, err := NewAlerts(ctx, marker, 30*time.Minute, noopCallback{}, log.NewNopLogger())
+	alerts, err := NewAlerts(ctx, marker, 30*time.Minute, 0, noopCallback{}, log.NewNopLogger(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -243,7 +244,7 @@ func TestAlertsSubscribe(t *testing.T) {
 
 func TestAlertsGetPending(t *testing.T) {
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := NewAlerts(context.Background(), marker, 30*time.Minute, noopCallback{}, log.NewNopLogger())
+	alerts, err := NewAlerts(context.Background(), marker, 30*time.Minute, 0, noopCallback{}, log.NewNopLogger(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -284,9 +285,65 @@ func TestAlertsGetPending(t *testing.T) {
 	}
 }
 
+func TestAlertsConcurrentReads(t *testing.T) {
+	const numAlerts = 1000
+
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := NewAlerts(context.Background(), marker, 30*time.Minute, 0, noopCallback{}, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	newAlert := func(i int) *types.Alert {
+		return &types.Alert{
+			Alert: model.Alert{
+				Labels:   model.LabelSet{"alertname": model.LabelValue(strconv.Itoa(i))},
+				StartsAt: t0,
+				EndsAt:   t1,
+			},
+			UpdatedAt: t0,
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numAlerts; i++ {
+			if err := alerts.Put(newAlert(i)); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			it := alerts.GetPending()
+			for range it.Next() {
+			}
+			it.Close()
+		}
+	}()
+	wg.Wait()
+
+	require.Equal(t, numAlerts, alerts.Count())
+
+	var n int
+	it := alerts.GetPending()
+	for range it.Next() {
+		n++
+	}
+	require.Equal(t, numAlerts, n)
+
+	// The alerts are spread across the shards.
+	for _, s := range alerts.shards {
+		require.NotZero(t, s.Len())
+	}
+}
+
 func TestAlertsGC(t *testing.T) {
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := NewAlerts(context.Background(), marker, 200*time.Millisecond, noopCallback{}, log.NewNopLogger())
+	alerts, err := NewAlerts(context.Background(), marker, 200*time.Millisecond, 0, noopCallback{}, log.NewNopLogger(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -319,11 +376,42 @@ func TestAlertsGC(t *testing.T) {
 	}
 }
 
+func TestAlertsGCRetention(t *testing.T) {
+	marker := types.NewMarker(prometheus.NewRegistry())
+	alerts, err := NewAlerts(context.Background(), marker, 30*time.Minute, time.Hour, noopCallback{}, log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	recent := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "recent"},
+			StartsAt: time.Now().Add(-2 * time.Hour),
+			EndsAt:   time.Now().Add(-time.Minute),
+		},
+	}
+	old := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "old"},
+			StartsAt: time.Now().Add(-3 * time.Hour),
+			EndsAt:   time.Now().Add(-2 * time.Hour),
+		},
+	}
+	require.NoError(t, alerts.Put(recent, old))
+
+	alerts.gc()
+
+	_, err = alerts.Get(recent.Fingerprint())
+	require.NoError(t, err)
+	_, err = alerts.Get(old.Fingerprint())
+	require.Equal(t, store.ErrNotFound, err)
+	require.Equal(t, 1.0, testutil.ToFloat64(alerts.gcRemoved))
+}
+
 func TestAlertsStoreCallback(t *testing.T) {
 	cb := &limitCountCallback{limit: 3}
 
 	marker := types.NewMarker(prometheus.NewRegistry())
-	alerts, err := NewAlerts(context.Background(), marker, 200*time.Millisecond, cb, log.NewNopLogger())
+	alerts, err := NewAlerts(context.Background(), marker, 200*time.Millisecond, 0, cb, log.NewNopLogger(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -85,4 +85,7 @@ type Alerts interface {
 	Get(model.Fingerprint) (*types.Alert, error)
 	// Put adds the given set of alerts to the set.
 	Put(...*types.Alert) error
+	// Count returns the number of alerts in the set, including resolved
+	// alerts which have not been garbage collected yet.
+	Count() int
 }
@@ -0,0 +1,195 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	commoncfg "github.com/prometheus/common/config"
+	"gopkg.in/yaml.v2"
+)
+
+// The headers of requests forwarding alerts which authenticate them: the
+// time at which they were signed, and their signature with the secret shared
+// by the cluster members.
+const (
+	TimestampHeader = "X-Alertmanager-Forwarded-Timestamp"
+	SignatureHeader = "X-Alertmanager-Forwarded-Signature"
+)
+
+// maxSignatureAge is how long the signature of a request forwarding alerts
+// is valid, which bounds the clock skew between the members and how long a
+// captured request can be replayed.
+const maxSignatureAge = 5 * time.Minute
+
+// Config configures how the members of a cluster forward alerts to each other.
+type Config struct {
+	// The secret shared by the members signing the requests forwarding
+	// alerts, which authenticates them to the member receiving them.
+	Secret     commoncfg.Secret `yaml:"secret,omitempty"`
+	SecretFile string           `yaml:"secret_file,omitempty"`
+	// The HTTP client settings of the requests forwarding alerts, e.g. the
+	// TLS settings and the credentials accepted by the web servers of the
+	// members.
+	HTTPConfig commoncfg.HTTPClientConfig `yaml:"http_config,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = Config{HTTPConfig: commoncfg.DefaultHTTPClientConfig}
+	type plain Config
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Secret == "" && c.SecretFile == "" {
+		return errors.New("one of secret and secret_file must be configured")
+	}
+	if c.Secret != "" && c.SecretFile != "" {
+		return errors.New("at most one of secret and secret_file must be configured")
+	}
+	return c.HTTPConfig.Validate()
+}
+
+// LoadConfig reads the configuration of the forwarding of alerts from the
+// file. Relative paths are resolved against the directory of the file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	if c.SecretFile != "" && !filepath.IsAbs(c.SecretFile) {
+		c.SecretFile = filepath.Join(dir, c.SecretFile)
+	}
+	c.HTTPConfig.SetDirectory(dir)
+	return c, nil
+}
+
+// Signer authenticates the requests forwarding alerts between the members of
+// a cluster by signing them with their shared secret.
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewSigner returns a Signer using the secret of the configuration.
+func NewSigner(c *Config) (*Signer, error) {
+	secret := []byte(c.Secret)
+	if c.SecretFile != "" {
+		b, err := ioutil.ReadFile(c.SecretFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "read secret file")
+		}
+		secret = bytes.TrimSpace(b)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("empty secret")
+	}
+	return &Signer{secret: secret, now: time.Now}, nil
+}
+
+// Sign adds the timestamp and the signature of the request with the given
+// body to its headers.
+func (s *Signer) Sign(req *http.Request, body []byte) {
+	ts := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set(TimestampHeader, ts)
+	req.Header.Set(SignatureHeader, s.signature(req.Header, ts, body))
+}
+
+// signature returns the signature of the forwarding headers and the body of
+// a request signed at the given timestamp.
+func (s *Signer) signature(h http.Header, ts string, body []byte) string {
+	sum := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join([]string{
+		ts,
+		h.Get(SourceAddressHeader),
+		h.Get(SourceIdentityHeader),
+		hex.EncodeToString(sum[:]),
+	}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify tells whether the request with the given body was signed with the
+// secret recently.
+func (s *Signer) verify(req *http.Request, body []byte) bool {
+	ts := req.Header.Get(TimestampHeader)
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := s.now().Sub(time.Unix(sec, 0)); age > maxSignatureAge || age < -maxSignatureAge {
+		return false
+	}
+	sig, err := hex.DecodeString(req.Header.Get(SignatureHeader))
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(s.signature(req.Header, ts, body))
+	return hmac.Equal(sig, expected)
+}
+
+type forwardedKey struct{}
+
+// Forwarded returns whether the request of the context forwards alerts from
+// another cluster member, which was authenticated by Signer.Handler. The
+// forwarding headers of other requests must be ignored.
+func Forwarded(ctx context.Context) bool {
+	v, _ := ctx.Value(forwardedKey{}).(bool)
+	return v
+}
+
+// Handler authenticates the requests carrying the forwarding headers before
+// passing them to h. Authenticated requests are marked as forwarded in their
+// context, the forwarding headers of the others are removed. A nil Signer
+// authenticates no request.
+func (s *Signer) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get(Header) == "" {
+			h.ServeHTTP(w, req)
+			return
+		}
+		if s != nil {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				http.Error(w, "failed to read request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			if s.verify(req, body) {
+				h.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), forwardedKey{}, true)))
+				return
+			}
+		}
+		for _, k := range []string{Header, SourceAddressHeader, SourceIdentityHeader, TimestampHeader, SignatureHeader} {
+			req.Header.Del(k)
+		}
+		h.ServeHTTP(w, req)
+	})
+}
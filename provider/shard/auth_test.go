@@ -0,0 +1,159 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "forward.yml")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "secret"), []byte("s3cr3t\n"), 0o600))
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+secret_file: secret
+http_config:
+  tls_config:
+    ca_file: ca.pem
+`), 0o600))
+
+	c, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "secret"), c.SecretFile)
+	require.Equal(t, filepath.Join(dir, "ca.pem"), c.HTTPConfig.TLSConfig.CAFile)
+	require.True(t, c.HTTPConfig.FollowRedirects)
+
+	s, err := NewSigner(c)
+	require.NoError(t, err)
+	require.Equal(t, []byte("s3cr3t"), s.secret)
+
+	for _, in := range []string{
+		"http_config: {}",
+		"secret: a\nsecret_file: b",
+		"secret: a\nunknown: b",
+	} {
+		require.NoError(t, ioutil.WriteFile(path, []byte(in), 0o600))
+		_, err := LoadConfig(path)
+		require.Error(t, err, in)
+	}
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.yml"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestSignerHandler(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	s := &Signer{secret: []byte("s3cr3t"), now: func() time.Time { return now }}
+	other := &Signer{secret: []byte("other"), now: s.now}
+
+	newRequest := func(signer *Signer, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/alerts", strings.NewReader(body))
+		req.Header.Set(Header, "true")
+		req.Header.Set(SourceAddressHeader, "10.0.0.1")
+		req.Header.Set(SourceIdentityHeader, "prometheus")
+		if signer != nil {
+			signer.Sign(req, []byte(body))
+		}
+		return req
+	}
+
+	for _, tc := range []struct {
+		name      string
+		signer    *Signer
+		req       *http.Request
+		forwarded bool
+	}{
+		{
+			name:      "signed",
+			signer:    s,
+			req:       newRequest(s, "[]"),
+			forwarded: true,
+		},
+		{
+			name:   "unsigned",
+			signer: s,
+			req:    newRequest(nil, "[]"),
+		},
+		{
+			name:   "signed with another secret",
+			signer: s,
+			req:    newRequest(other, "[]"),
+		},
+		{
+			name:   "tampered body",
+			signer: s,
+			req: func() *http.Request {
+				req := newRequest(s, "[]")
+				req.Body = ioutil.NopCloser(strings.NewReader(`[{"labels":{}}]`))
+				return req
+			}(),
+		},
+		{
+			name:   "tampered source",
+			signer: s,
+			req: func() *http.Request {
+				req := newRequest(s, "[]")
+				req.Header.Set(SourceIdentityHeader, "admin")
+				return req
+			}(),
+		},
+		{
+			name:   "expired",
+			signer: s,
+			req: func() *http.Request {
+				old := &Signer{secret: s.secret, now: func() time.Time { return now.Add(-maxSignatureAge - time.Second) }}
+				return newRequest(old, "[]")
+			}(),
+		},
+		{
+			name: "no signer",
+			req:  newRequest(s, "[]"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				forwarded bool
+				header    http.Header
+				body      []byte
+			)
+			h := tc.signer.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				forwarded = Forwarded(r.Context())
+				header = r.Header
+				body, _ = ioutil.ReadAll(r.Body)
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), tc.req)
+
+			require.Equal(t, tc.forwarded, forwarded)
+			require.NotEmpty(t, body)
+			if tc.forwarded {
+				require.Equal(t, "prometheus", header.Get(SourceIdentityHeader))
+				return
+			}
+			// The forwarding headers of requests which aren't
+			// authenticated are removed.
+			for _, k := range []string{Header, SourceAddressHeader, SourceIdentityHeader, TimestampHeader, SignatureHeader} {
+				require.Empty(t, header.Get(k), k)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shard divides the alerts between the members of a cluster. Every
+// member owns a disjoint range of alert fingerprints and alerts received by
+// another member are forwarded to their owner, so that no member has to hold
+// every active alert.
+package shard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/go-openapi/strfmt"
+	"github.com/prometheus/client_golang/prometheus"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/storage"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Header marks requests forwarding alerts to their owner. Alerts received
+// with it are stored without being forwarded again, provided the request is
+// authenticated by a Signer.
+const Header = "X-Alertmanager-Forwarded"
+
+// The headers of requests forwarding alerts which hold the address and the
+// identity of the client which sent them.
+const (
+	SourceAddressHeader  = "X-Alertmanager-Source-Address"
+	SourceIdentityHeader = "X-Alertmanager-Source-Identity"
+)
+
+// forwardKey groups the alerts forwarded in one request: they have the same
+// owner and were sent by the same client.
+type forwardKey struct {
+	url      string
+	address  string
+	identity string
+}
+
+// Ring determines the owners of alerts, e.g. *cluster.Peer.
+type Ring interface {
+	// Owner returns whether the local member owns the given fingerprint
+	// and otherwise the metadata of the owner, which is the URL of its API.
+	Owner(fp uint64) (self bool, meta []byte)
+}
+
+type metrics struct {
+	forwarded       prometheus.Counter
+	forwardFailures prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		forwarded: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_alerts_forwarded_total",
+			Help: "Total number of alerts forwarded to the cluster member owning them.",
+		}),
+		forwardFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_alerts_forward_failures_total",
+			Help: "Total number of alerts which couldn't be forwarded and were stored locally instead.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(m.forwarded, m.forwardFailures)
+	}
+	return m
+}
+
+// Alerts stores the alerts owned by the local member in the wrapped
+// provider and forwards the others to their owners.
+type Alerts struct {
+	storage.Alerts
+
+	ring    Ring
+	client  *http.Client
+	signer  *Signer
+	logger  log.Logger
+	metrics *metrics
+}
+
+// forwardTimeout is the timeout of the requests forwarding alerts.
+const forwardTimeout = 10 * time.Second
+
+// NewAlerts returns a provider sharding alerts by the given ring. Alerts are
+// forwarded with the HTTP client settings of the configuration, in requests
+// signed by the signer.
+func NewAlerts(a storage.Alerts, ring Ring, c *Config, s *Signer, l log.Logger, r prometheus.Registerer) (*Alerts, error) {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	client, err := commoncfg.NewClientFromConfig(c.HTTPConfig, "shard")
+	if err != nil {
+		return nil, err
+	}
+	client.Timeout = forwardTimeout
+	return &Alerts{
+		Alerts:  a,
+		ring:    ring,
+		client:  client,
+		signer:  s,
+		logger:  l,
+		metrics: newMetrics(r),
+	}, nil
+}
+
+// Put forwards the alerts owned by other members to them and stores the
+// remaining ones. Alerts which can't be forwarded are stored locally, so that
+// they aren't lost.
+func (a *Alerts) Put(alerts ...*types.Alert) error {
+	var (
+		local  []*types.Alert
+		remote = map[forwardKey][]*types.Alert{}
+	)
+	for _, alert := range alerts {
+		self, meta := a.ring.Owner(uint64(alert.Fingerprint()))
+		// Owners which haven't announced their URL yet can't be forwarded to.
+		if self || len(meta) == 0 {
+			local = append(local, alert)
+			continue
+		}
+		k := forwardKey{url: string(meta), address: alert.Source.Address, identity: alert.Source.Identity}
+		remote[k] = append(remote[k], alert)
+	}
+	for k, as := range remote {
+		if err := a.forward(k, as); err != nil {
+			a.metrics.forwardFailures.Add(float64(len(as)))
+			level.Warn(a.logger).Log("msg", "Failed to forward alerts to their owner", "url", k.url, "alerts", len(as), "err", err)
+			local = append(local, as...)
+			continue
+		}
+		a.metrics.forwarded.Add(float64(len(as)))
+	}
+	return a.Alerts.Put(local...)
+}
+
+// PutLocal stores alerts forwarded by other members without forwarding them
+// again.
+func (a *Alerts) PutLocal(alerts ...*types.Alert) error {
+	return a.Alerts.Put(alerts...)
+}
+
+func (a *Alerts) forward(k forwardKey, alerts []*types.Alert) error {
+	b, err := json.Marshal(postableAlerts(alerts))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, strings.TrimSuffix(k.url, "/")+"/api/v2/alerts", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(Header, "true")
+	if k.address != "" {
+		req.Header.Set(SourceAddressHeader, k.address)
+	}
+	if k.identity != "" {
+		req.Header.Set(SourceIdentityHeader, k.identity)
+	}
+	a.signer.Sign(req, b)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func postableAlerts(alerts []*types.Alert) models.PostableAlerts {
+	res := make(models.PostableAlerts, 0, len(alerts))
+	for _, a := range alerts {
+		pa := &models.PostableAlert{
+			Annotations: models.LabelSet{},
+			StartsAt:    strfmt.DateTime(a.StartsAt),
+			Alert: models.Alert{
+				Labels:       models.LabelSet{},
+				GeneratorURL: strfmt.URI(a.GeneratorURL),
+			},
+			Source: a.Source.Name,
+		}
+		// Alerts without an end time are resolved by their owner after the
+		// resolve timeout.
+		if !a.Timeout {
+			pa.EndsAt = strfmt.DateTime(a.EndsAt)
+		}
+		for k, v := range a.Labels {
+			pa.Labels[string(k)] = string(v)
+		}
+		for k, v := range a.Annotations {
+			pa.Annotations[string(k)] = string(v)
+		}
+		res = append(res, pa)
+	}
+	return res
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// ringFunc adapts a function to the Ring interface.
+type ringFunc func(fp uint64) (bool, []byte)
+
+func (f ringFunc) Owner(fp uint64) (bool, []byte) { return f(fp) }
+
+func newAlert(name string) *types.Alert {
+	now := time.Now()
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": model.LabelValue(name)},
+			Annotations: model.LabelSet{"summary": "test"},
+			StartsAt:    now,
+			EndsAt:      now.Add(time.Hour),
+		},
+		UpdatedAt: now,
+	}
+}
+
+func newConfig(t *testing.T) *Config {
+	c := &Config{}
+	require.NoError(t, yaml.Unmarshal([]byte("secret: s3cr3t"), c))
+	return c
+}
+
+func newLocal(t *testing.T) *mem.Alerts {
+	a, err := mem.NewAlerts(context.Background(), types.NewMarker(prometheus.NewRegistry()), time.Hour, 0, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	t.Cleanup(a.Close)
+	return a
+}
+
+func TestPutForwardsToOwner(t *testing.T) {
+	var (
+		forwarded     models.PostableAlerts
+		header        http.Header
+		authenticated bool
+		authorization string
+	)
+	conf := newConfig(t)
+	conf.HTTPConfig.BearerToken = "peer-token"
+	signer, err := NewSigner(conf)
+	require.NoError(t, err)
+	srv := httptest.NewServer(signer.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v2/alerts", r.URL.Path)
+		header = r.Header
+		authenticated = Forwarded(r.Context())
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&forwarded))
+	})))
+	defer srv.Close()
+
+	mine, theirs := newAlert("mine"), newAlert("theirs")
+	theirs.Source = types.Source{Name: "staging", Address: "10.0.0.1", Identity: "prometheus"}
+	ring := ringFunc(func(fp uint64) (bool, []byte) {
+		if fp == uint64(theirs.Fingerprint()) {
+			return false, []byte(srv.URL + "/")
+		}
+		return true, nil
+	})
+	local := newLocal(t)
+	a, err := NewAlerts(local, ring, conf, signer, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, a.Put(mine, theirs))
+
+	require.True(t, authenticated)
+	require.Equal(t, "Bearer peer-token", authorization)
+	require.Equal(t, "true", header.Get(Header))
+	require.Equal(t, "10.0.0.1", header.Get(SourceAddressHeader))
+	require.Equal(t, "prometheus", header.Get(SourceIdentityHeader))
+	require.Len(t, forwarded, 1)
+	require.Equal(t, "theirs", forwarded[0].Labels["alertname"])
+	require.Equal(t, "staging", forwarded[0].Source)
+	require.Equal(t, "test", forwarded[0].Annotations["summary"])
+	require.Equal(t, 1, local.Count())
+	_, err = local.Get(mine.Fingerprint())
+	require.NoError(t, err)
+
+	// Forwarded alerts are stored locally regardless of their owner.
+	require.NoError(t, a.PutLocal(newAlert("theirs")))
+	require.Equal(t, 2, local.Count())
+}
+
+func TestPutStoresLocallyOnFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	for _, meta := range []string{srv.URL, ""} {
+		ring := ringFunc(func(uint64) (bool, []byte) { return false, []byte(meta) })
+		local := newLocal(t)
+		conf := newConfig(t)
+		signer, err := NewSigner(conf)
+		require.NoError(t, err)
+		a, err := NewAlerts(local, ring, conf, signer, nil, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, a.Put(newAlert("a"), newAlert("b")))
+		require.Equal(t, 2, local.Count(), meta)
+	}
+}
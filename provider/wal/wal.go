@@ -0,0 +1,287 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wal records ingested alerts in a write-ahead log before they are
+// stored, so that alerts received shortly before a crash are replayed on the
+// next start and still notified.
+//
+// The log is a file of records, each holding the alerts of one Put. Every
+// record is synced to disk before the alerts are stored. The log is
+// periodically replaced by a checkpoint holding the current alerts, which
+// keeps it from growing indefinitely.
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/alertmanager/storage"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// A record is made of an 8 byte header, holding the length and the CRC32 of
+// its data, followed by the data.
+const headerSize = 8
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+type metrics struct {
+	writeDuration prometheus.Summary
+	writeFailures prometheus.Counter
+	checkpoints   prometheus.Counter
+	replayed      prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		writeDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Name:       "alertmanager_alerts_wal_write_duration_seconds",
+			Help:       "Duration of writes of alerts to the write-ahead log, including syncing them to disk.",
+			Objectives: map[float64]float64{},
+		}),
+		writeFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_alerts_wal_write_failures_total",
+			Help: "Total number of failed writes of alerts to the write-ahead log.",
+		}),
+		checkpoints: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_alerts_wal_checkpoints_total",
+			Help: "Total number of checkpoints replacing the write-ahead log.",
+		}),
+		replayed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_alerts_wal_replayed_total",
+			Help: "Total number of alerts replayed from the write-ahead log on start.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(m.writeDuration, m.writeFailures, m.checkpoints, m.replayed)
+	}
+	return m
+}
+
+// Alerts records alerts in the write-ahead log before storing them in the
+// wrapped provider.
+type Alerts struct {
+	storage.Alerts
+
+	path    string
+	logger  log.Logger
+	metrics *metrics
+
+	mtx sync.Mutex
+	f   *os.File
+}
+
+// Open replays the write-ahead log at the given path into the provider and
+// returns a provider recording alerts in the log. The log is created if it
+// doesn't exist.
+func Open(path string, a storage.Alerts, l log.Logger, r prometheus.Registerer) (*Alerts, error) {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	w := &Alerts{
+		Alerts:  a,
+		path:    path,
+		logger:  l,
+		metrics: newMetrics(r),
+	}
+	alerts, err := w.replay()
+	if err != nil {
+		return nil, err
+	}
+	if err := a.Put(alerts...); err != nil {
+		return nil, err
+	}
+	w.metrics.replayed.Add(float64(len(alerts)))
+	if len(alerts) > 0 {
+		level.Info(l).Log("msg", "Replayed alerts from write-ahead log", "alerts", len(alerts))
+	}
+
+	// Start from a checkpoint of the replayed alerts, which also drops a
+	// torn record at the end of the log.
+	if err := w.Checkpoint(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// replay reads the alerts of all records in the log, in order. A record
+// which is incomplete or corrupted, e.g. after a crash while it was written,
+// ends the log.
+func (w *Alerts) replay() ([]*types.Alert, error) {
+	f, err := os.Open(w.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		res []*types.Alert
+		r   = bufio.NewReader(f)
+		hdr = make([]byte, headerSize)
+	)
+	for {
+		if _, err := io.ReadFull(r, hdr); err != nil {
+			if err != io.EOF {
+				level.Warn(w.logger).Log("msg", "Ignoring incomplete record at the end of the write-ahead log", "err", err)
+			}
+			return res, nil
+		}
+		data := make([]byte, binary.BigEndian.Uint32(hdr[:4]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			level.Warn(w.logger).Log("msg", "Ignoring incomplete record at the end of the write-ahead log", "err", err)
+			return res, nil
+		}
+		if crc32.Checksum(data, castagnoli) != binary.BigEndian.Uint32(hdr[4:]) {
+			level.Warn(w.logger).Log("msg", "Ignoring corrupted record in the write-ahead log and all following ones")
+			return res, nil
+		}
+		var alerts []*types.Alert
+		if err := json.Unmarshal(data, &alerts); err != nil {
+			return nil, errors.Wrap(err, "decode write-ahead log record")
+		}
+		res = append(res, alerts...)
+	}
+}
+
+// writeRecord writes the alerts as a record.
+func writeRecord(wr io.Writer, alerts []*types.Alert) error {
+	data, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+	rec := make([]byte, headerSize+len(data))
+	binary.BigEndian.PutUint32(rec[:4], uint32(len(data)))
+	binary.BigEndian.PutUint32(rec[4:headerSize], crc32.Checksum(data, castagnoli))
+	copy(rec[headerSize:], data)
+	_, err = wr.Write(rec)
+	return err
+}
+
+// Put records the alerts in the log and stores them once they are synced to
+// disk.
+func (w *Alerts) Put(alerts ...*types.Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	// The lock is held until the alerts are stored, so that a checkpoint
+	// either holds them or is taken before they are recorded.
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if err := w.write(alerts); err != nil {
+		w.metrics.writeFailures.Inc()
+		return errors.Wrap(err, "write-ahead log")
+	}
+	return w.Alerts.Put(alerts...)
+}
+
+func (w *Alerts) write(alerts []*types.Alert) error {
+	start := time.Now()
+	defer func() { w.metrics.writeDuration.Observe(time.Since(start).Seconds()) }()
+
+	if w.f == nil {
+		return errors.New("write-ahead log is closed")
+	}
+	if err := writeRecord(w.f, alerts); err != nil {
+		return err
+	}
+	return w.f.Sync()
+}
+
+// Checkpoint replaces the log by a single record of the current alerts.
+func (w *Alerts) Checkpoint() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	// Alerts put while the checkpoint is taken wait for the lock and are
+	// appended to the new log.
+	var alerts []*types.Alert
+	it := w.Alerts.GetPending()
+	for a := range it.Next() {
+		alerts = append(alerts, a)
+	}
+	it.Close()
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	tmp := w.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	if len(alerts) > 0 {
+		if err := writeRecord(f, alerts); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		f.Close()
+		return err
+	}
+	if w.f != nil {
+		w.f.Close()
+	}
+	// The file stays open for appending records after the checkpoint.
+	w.f = f
+	w.metrics.checkpoints.Inc()
+	return nil
+}
+
+// Maintenance checkpoints the log at the given interval until stopc is
+// closed.
+func (w *Alerts) Maintenance(interval time.Duration, stopc <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			return
+		case <-t.C:
+			if err := w.Checkpoint(); err != nil {
+				level.Error(w.logger).Log("msg", "Checkpointing write-ahead log failed", "err", err)
+			}
+		}
+	}
+}
+
+// Close closes the log and the wrapped provider.
+func (w *Alerts) Close() {
+	w.mtx.Lock()
+	if w.f != nil {
+		w.f.Close()
+		w.f = nil
+	}
+	w.mtx.Unlock()
+	w.Alerts.Close()
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func newAlert(name string) *types.Alert {
+	now := time.Now()
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": model.LabelValue(name)},
+			StartsAt: now,
+			EndsAt:   now.Add(time.Hour),
+		},
+		UpdatedAt: now,
+	}
+}
+
+func newMem(t *testing.T) *mem.Alerts {
+	a, err := mem.NewAlerts(context.Background(), types.NewMarker(prometheus.NewRegistry()), time.Hour, 0, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	return a
+}
+
+func tempPath(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "alerts.wal")
+}
+
+func TestReplay(t *testing.T) {
+	path := tempPath(t)
+
+	w, err := Open(path, newMem(t), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, w.Put(newAlert("a")))
+	require.NoError(t, w.Put(newAlert("b"), newAlert("c")))
+	// The log isn't checkpointed on close, as after a crash.
+	w.Close()
+
+	restored := newMem(t)
+	w, err = Open(path, restored, nil, nil)
+	require.NoError(t, err)
+	defer w.Close()
+	require.Equal(t, 3, restored.Count())
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := restored.Get(newAlert(name).Fingerprint())
+		require.NoError(t, err)
+	}
+}
+
+func TestReplayTornRecord(t *testing.T) {
+	path := tempPath(t)
+
+	w, err := Open(path, newMem(t), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, w.Put(newAlert("a")))
+	require.NoError(t, w.Put(newAlert("b")))
+	w.Close()
+
+	// Cut off the end of the last record, as if the process crashed while
+	// writing it.
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, fi.Size()-3))
+
+	restored := newMem(t)
+	w, err = Open(path, restored, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, restored.Count())
+
+	// The log remains usable after the torn record.
+	require.NoError(t, w.Put(newAlert("c")))
+	w.Close()
+	restored = newMem(t)
+	w, err = Open(path, restored, nil, nil)
+	require.NoError(t, err)
+	defer w.Close()
+	require.Equal(t, 2, restored.Count())
+}
+
+func TestCheckpoint(t *testing.T) {
+	path := tempPath(t)
+
+	w, err := Open(path, newMem(t), nil, nil)
+	require.NoError(t, err)
+	a := newAlert("a")
+	for i := 0; i < 10; i++ {
+		require.NoError(t, w.Put(a))
+	}
+	before, err := os.Stat(path)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Checkpoint())
+	after, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Less(t, after.Size(), before.Size())
+	w.Close()
+
+	restored := newMem(t)
+	w, err = Open(path, restored, nil, nil)
+	require.NoError(t, err)
+	defer w.Close()
+	require.Equal(t, 1, restored.Count())
+}
+
+func TestPutAfterClose(t *testing.T) {
+	w, err := Open(tempPath(t), newMem(t), nil, nil)
+	require.NoError(t, err)
+	w.Close()
+	require.Error(t, w.Put(newAlert("a")))
+}
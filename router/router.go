@@ -0,0 +1,218 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package router builds the routing of alerts of a configuration: its
+// routing tree, its receivers, the notification pipeline and the dispatcher
+// sending the alerts through them. It is shared by the Alertmanager binary
+// and the embeddable package, so that both honour the same configuration.
+package router
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/inhibit"
+	"github.com/prometheus/alertmanager/lifecycle"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/notifiers"
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// Options holds the components outliving the configurations, which the
+// routers of the successive configurations share.
+type Options struct {
+	Alerts   provider.Alerts
+	Marker   types.Marker
+	Silences *silence.Silences
+	// ExternalURL is the URL of the Alertmanager rendered by the templates.
+	ExternalURL *url.URL
+
+	// DryRun logs the notifications instead of sending them.
+	DryRun bool
+	// Transport wraps the round tripper of the HTTP clients of the
+	// integrations.
+	Transport notify.TransportWrapper
+	// Validate, if set, validates the receivers before anything else is
+	// built.
+	Validate func(map[string][]notify.Integration) error
+
+	PipelineBuilder   *notify.PipelineBuilder
+	DispatcherMetrics *dispatch.DispatcherMetrics
+
+	// The components of the notification pipeline, see
+	// notify.PipelineComponents. The quiet hours, route dedup and budgets
+	// are updated with the configuration.
+	Wait            func() time.Duration
+	IsLeader        func() bool
+	QuietHours      *notify.QuietHours
+	RouteDedup      *notify.RouteDedup
+	Budgets         *notify.Budgets
+	Events          lifecycle.Recorder
+	Attempts        notify.AttemptRecorder
+	NotificationLog notify.NotificationLog
+	DeadLetters     notify.DeadLetterQueue
+	Spool           notify.Spool
+	Peer            notify.Peer
+
+	// The options of the dispatcher, see dispatch.DispatcherOptions.
+	Timeout          func(time.Duration) time.Duration
+	Acknowledgements dispatch.Acknowledgements
+	Shards           int
+
+	Logger log.Logger
+}
+
+// Router routes the alerts according to a configuration.
+type Router struct {
+	Route *dispatch.Route
+	// The integrations of the receivers used by the routes, and their
+	// number.
+	Receivers    map[string][]notify.Integration
+	Integrations int
+
+	Inhibitor  *inhibit.Inhibitor
+	Silencer   *silence.Silencer
+	Dispatcher *dispatch.Dispatcher
+}
+
+// Build returns the router of the configuration. Nothing is changed if the
+// configuration can't be applied, so that the current router keeps running.
+func Build(conf *config.Config, o Options) (*Router, error) {
+	logger := o.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	tmpl, err := template.FromGlobs(conf.Templates...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse templates")
+	}
+	tmpl.ExternalURL = o.ExternalURL
+
+	// Build the routing tree and record which receivers are used.
+	r := &Router{Route: dispatch.NewRoute(conf.Route, nil)}
+	activeReceivers := make(map[string]struct{})
+	r.Route.Walk(func(route *dispatch.Route) {
+		activeReceivers[route.RouteOpts.Receiver] = struct{}{}
+		for _, e := range route.RouteOpts.Escalation {
+			activeReceivers[e.Receiver] = struct{}{}
+		}
+	})
+
+	middlewares, err := notify.LookupMiddlewares(conf.Global.NotificationMiddlewares)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the map of receiver to integrations.
+	r.Receivers = make(map[string][]notify.Integration, len(activeReceivers))
+	for _, rcv := range conf.Receivers {
+		if _, found := activeReceivers[rcv.Name]; !found {
+			// No need to build a receiver if no route is using it.
+			level.Info(logger).Log("msg", "skipping creation of receiver not referenced by any route", "receiver", rcv.Name)
+			continue
+		}
+		integrations, err := notifiers.Build(rcv, tmpl, notifiers.Options{
+			TLSPolicy:   conf.Global.TLSPolicy,
+			Middlewares: middlewares,
+			DryRun:      o.DryRun,
+			Logger:      logger,
+			Transport:   o.Transport,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// rcv.Name is guaranteed to be unique across all receivers.
+		r.Receivers[rcv.Name] = integrations
+		r.Integrations += len(integrations)
+	}
+	if o.Validate != nil {
+		if err := o.Validate(r.Receivers); err != nil {
+			return nil, err
+		}
+	}
+
+	// Build the map of time interval names to mute time definitions.
+	muteTimes := make(map[string][]timeinterval.TimeInterval, len(conf.MuteTimeIntervals))
+	for _, ti := range conf.MuteTimeIntervals {
+		muteTimes[ti.Name] = ti.TimeIntervals
+	}
+
+	marker := o.Marker
+	if o.Events != nil {
+		marker = lifecycle.NewMarker(o.Marker, o.Alerts, o.Events)
+	}
+	r.Inhibitor = inhibit.NewInhibitor(o.Alerts, conf.InhibitRules, marker, logger)
+	r.Silencer = silence.NewSilencer(o.Silences, marker, logger)
+
+	if o.QuietHours != nil {
+		o.QuietHours.Update(conf)
+	}
+	if o.RouteDedup != nil {
+		o.RouteDedup.Update(conf)
+	}
+	if o.Budgets != nil {
+		o.Budgets.Update(conf)
+	}
+
+	pipeline := o.PipelineBuilder.New(r.Receivers, notify.PipelineComponents{
+		Wait:            o.Wait,
+		IsLeader:        o.IsLeader,
+		Inhibitor:       r.Inhibitor,
+		Silencer:        r.Silencer,
+		MuteTimes:       muteTimes,
+		Suppressor:      notify.NewSuppressStage(conf.SuppressionRules, muteTimes, conf.Global.SeverityLabel, conf.Global.SeverityMapping),
+		QuietHours:      o.QuietHours,
+		RouteDedup:      o.RouteDedup,
+		Budgets:         o.Budgets,
+		Scrubber:        notify.NewScrubStage(conf.Scrubbing),
+		Events:          o.Events,
+		Attempts:        o.Attempts,
+		NotificationLog: o.NotificationLog,
+		DeadLetters:     o.DeadLetters,
+		Spool:           o.Spool,
+		Peer:            o.Peer,
+	})
+	r.Dispatcher = dispatch.NewDispatcher(o.Alerts, r.Route, pipeline, o.Marker, dispatch.DispatcherOptions{
+		Timeout:          o.Timeout,
+		Acknowledgements: o.Acknowledgements,
+		Events:           o.Events,
+		Shards:           o.Shards,
+	}, logger, o.DispatcherMetrics)
+	return r, nil
+}
+
+// Run starts routing the alerts.
+func (r *Router) Run() {
+	go r.Dispatcher.Run()
+	go r.Inhibitor.Run()
+}
+
+// Stop stops routing the alerts. It is a no-op for a nil Router.
+func (r *Router) Stop() {
+	if r == nil {
+		return
+	}
+	r.Inhibitor.Stop()
+	r.Dispatcher.Stop()
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestBuild(t *testing.T) {
+	conf, err := config.Load(`
+route:
+  receiver: team-X
+  routes:
+  - receiver: team-Y
+    matchers: [severity="page"]
+receivers:
+- name: team-X
+  webhook_configs:
+  - url: http://example.invalid/x
+- name: team-Y
+  webhook_configs:
+  - url: http://example.invalid/y1
+  - url: http://example.invalid/y2
+- name: unused
+  webhook_configs:
+  - url: http://example.invalid/unused
+`)
+	require.NoError(t, err)
+
+	reg := prometheus.NewRegistry()
+	marker := types.NewMarker(reg)
+	alerts, err := mem.NewAlerts(context.Background(), marker, time.Hour, 0, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+	silences, err := silence.New(silence.Options{})
+	require.NoError(t, err)
+
+	opts := Options{
+		Alerts:            alerts,
+		Marker:            marker,
+		Silences:          silences,
+		PipelineBuilder:   notify.NewPipelineBuilder(reg),
+		DispatcherMetrics: dispatch.NewDispatcherMetrics(false, reg),
+		RouteDedup:        notify.NewRouteDedup(nil),
+	}
+	r, err := Build(conf, opts)
+	require.NoError(t, err)
+	// The receivers used by no route aren't built.
+	require.Len(t, r.Receivers, 2)
+	require.Len(t, r.Receivers["team-Y"], 2)
+	require.Equal(t, 3, r.Integrations)
+	r.Run()
+	r.Stop()
+
+	opts.Validate = func(receivers map[string][]notify.Integration) error {
+		require.Len(t, receivers, 2)
+		return errors.New("invalid receivers")
+	}
+	_, err = Build(conf, opts)
+	require.EqualError(t, err, "invalid receivers")
+
+	conf.Templates = []string{"/nonexistent/["}
+	_, err = Build(conf, opts)
+	require.Error(t, err)
+
+	// Stopping a nil router is a no-op.
+	var nilRouter *Router
+	nilRouter.Stop()
+}
@@ -35,12 +35,12 @@ done
 GOGOPROTO_ROOT="$(go list -mod=readonly -f '{{ .Dir }}' -m github.com/gogo/protobuf)"
 GOGOPROTO_PATH="${GOGOPROTO_ROOT}:${GOGOPROTO_ROOT}/protobuf"
 
-DIRS="nflog/nflogpb silence/silencepb cluster/clusterpb"
+DIRS="nflog/nflogpb silence/silencepb cluster/clusterpb api/rpc/alertmanagerpb"
 
 echo "generating files"
 for dir in ${DIRS}; do
     pushd ${dir}
-        protoc --gogofast_out=:. -I=. \
+        protoc --gogofast_out=plugins=grpc:. -I=. \
             -I="${GOGOPROTO_PATH}" \
             *.proto
 
@@ -0,0 +1,525 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit implements a garbage-collected and snapshottable log of the
+// changes made to silences. Every creation, modification and expiry of a
+// silence is recorded along with the user who made it and the changed fields.
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+// EventType is the kind of change made to a silence.
+type EventType string
+
+// Possible values for EventType.
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventExpired EventType = "expired"
+	// A recurring silence moved to its next occurrence.
+	EventRescheduled EventType = "rescheduled"
+)
+
+// Change is the modification of a single field of a silence.
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// Event is a change made to a silence.
+type Event struct {
+	// A unique identifier of the event. Events of the same change recorded
+	// by different cluster members have the same ID.
+	ID        string    `json:"id"`
+	SilenceID string    `json:"silenceID"`
+	Type      EventType `json:"type"`
+	// The user who made the change, if known.
+	User    string    `json:"user,omitempty"`
+	Time    time.Time `json:"time"`
+	Changes []Change  `json:"changes,omitempty"`
+}
+
+// Query selects events from the log. Empty fields match all events.
+type Query struct {
+	SilenceID string
+	User      string
+	Since     time.Time
+	Until     time.Time
+}
+
+func (q Query) matches(e *Event) bool {
+	if q.SilenceID != "" && e.SilenceID != q.SilenceID {
+		return false
+	}
+	if q.User != "" && e.User != q.User {
+		return false
+	}
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && e.Time.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Log stores silence events.
+type Log struct {
+	logger    log.Logger
+	metrics   *metrics
+	now       func() time.Time
+	retention time.Duration
+
+	mtx    sync.RWMutex
+	events map[string]*Event
+}
+
+// MaintenanceFunc represents the function to run as part of the periodic
+// maintenance for the log. It returns the size of the snapshot taken or an
+// error if it failed.
+type MaintenanceFunc func() (int64, error)
+
+type metrics struct {
+	events           prometheus.GaugeFunc
+	gcDuration       prometheus.Summary
+	snapshotDuration prometheus.Summary
+	snapshotSize     prometheus.Gauge
+}
+
+func newMetrics(r prometheus.Registerer, l *Log) *metrics {
+	m := &metrics{}
+
+	m.events = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "alertmanager_silence_audit_events",
+		Help: "Number of events currently held in the silence audit log.",
+	}, func() float64 {
+		return float64(l.Len())
+	})
+	m.gcDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "alertmanager_silence_audit_gc_duration_seconds",
+		Help:       "Duration of the last silence audit log garbage collection cycle.",
+		Objectives: map[float64]float64{},
+	})
+	m.snapshotDuration = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "alertmanager_silence_audit_snapshot_duration_seconds",
+		Help:       "Duration of the last silence audit log snapshot.",
+		Objectives: map[float64]float64{},
+	})
+	m.snapshotSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "alertmanager_silence_audit_snapshot_size_bytes",
+		Help: "Size of the last silence audit log snapshot in bytes.",
+	})
+
+	if r != nil {
+		r.MustRegister(
+			m.events,
+			m.gcDuration,
+			m.snapshotDuration,
+			m.snapshotSize,
+		)
+	}
+	return m
+}
+
+// Options exposes configuration options for creating a new Log object.
+type Options struct {
+	// A snapshot file or reader from which the initial state is loaded.
+	// None or only one of them must be set.
+	SnapshotFile   string
+	SnapshotReader io.Reader
+
+	// Retention time for events. Events are garbage collected once they
+	// are older than the given duration.
+	Retention time.Duration
+
+	// A logger used by background processing.
+	Logger  log.Logger
+	Metrics prometheus.Registerer
+}
+
+func (o *Options) validate() error {
+	if o.SnapshotFile != "" && o.SnapshotReader != nil {
+		return fmt.Errorf("only one of SnapshotFile and SnapshotReader must be set")
+	}
+	return nil
+}
+
+// New returns a new Log object with the given configuration.
+func New(o Options) (*Log, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if o.SnapshotFile != "" {
+		if r, err := os.Open(o.SnapshotFile); err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		} else {
+			defer r.Close()
+			o.SnapshotReader = r
+		}
+	}
+	l := &Log{
+		logger:    log.NewNopLogger(),
+		now:       utcNow,
+		retention: o.Retention,
+		events:    map[string]*Event{},
+	}
+	l.metrics = newMetrics(o.Metrics, l)
+
+	if o.Logger != nil {
+		l.logger = o.Logger
+	}
+	if o.SnapshotReader != nil {
+		if err := l.loadSnapshot(o.SnapshotReader); err != nil {
+			return l, err
+		}
+	}
+	return l, nil
+}
+
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+// Record records the change from the previous to the current version of a
+// silence. The previous version is nil for new silences. It can be used as
+// the change callback of the silences.
+func (l *Log) Record(prev, cur *pb.Silence) {
+	e := NewEvent(prev, cur)
+	if e == nil {
+		return
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.events[e.ID] = e
+}
+
+// NewEvent returns the event describing the change from the previous to the
+// current version of a silence. It returns nil if no field changed.
+func NewEvent(prev, cur *pb.Silence) *Event {
+	e := &Event{
+		ID:        fmt.Sprintf("%s-%d", cur.Id, cur.UpdatedAt.UnixNano()),
+		SilenceID: cur.Id,
+		Time:      cur.UpdatedAt,
+	}
+	if prev == nil {
+		e.Type = EventCreated
+		e.User = cur.CreatedBy
+		e.Changes = diff(&pb.Silence{}, cur)
+		return e
+	}
+
+	e.Changes = diff(prev, cur)
+	switch {
+	case len(e.Changes) == 0:
+		return nil
+	case prev.EndsAt.After(cur.UpdatedAt) && !cur.EndsAt.After(cur.UpdatedAt):
+		e.Type = EventExpired
+		e.User = cur.ExpiredBy
+	case cur.Schedule != nil && onlyTimesChanged(e.Changes):
+		e.Type = EventRescheduled
+	default:
+		e.Type = EventUpdated
+		e.User = cur.CreatedBy
+	}
+	return e
+}
+
+func onlyTimesChanged(changes []Change) bool {
+	for _, c := range changes {
+		if c.Field != "startsAt" && c.Field != "endsAt" {
+			return false
+		}
+	}
+	return true
+}
+
+// diff returns the changed fields between two versions of a silence.
+func diff(a, b *pb.Silence) []Change {
+	var changes []Change
+	add := func(field, o, n string) {
+		if o != n {
+			changes = append(changes, Change{Field: field, Old: o, New: n})
+		}
+	}
+	add("matchers", formatMatchers(a.Matchers), formatMatchers(b.Matchers))
+	add("startsAt", formatTime(a.StartsAt), formatTime(b.StartsAt))
+	add("endsAt", formatTime(a.EndsAt), formatTime(b.EndsAt))
+	add("createdBy", a.CreatedBy, b.CreatedBy)
+	add("comment", a.Comment, b.Comment)
+	add("notifyReceiver", a.NotifyReceiver, b.NotifyReceiver)
+	add("notifyBefore", formatDuration(a.NotifyBefore), formatDuration(b.NotifyBefore))
+	add("schedule", formatSchedule(a.Schedule), formatSchedule(b.Schedule))
+	return changes
+}
+
+var matchOps = map[pb.Matcher_Type]string{
+	pb.Matcher_EQUAL:      "=",
+	pb.Matcher_NOT_EQUAL:  "!=",
+	pb.Matcher_REGEXP:     "=~",
+	pb.Matcher_NOT_REGEXP: "!~",
+}
+
+func formatMatchers(ms []*pb.Matcher) string {
+	if len(ms) == 0 {
+		return ""
+	}
+	s := make([]string, 0, len(ms))
+	for _, m := range ms {
+		s = append(s, fmt.Sprintf("%s%s%q", m.Name, matchOps[m.Type], m.Pattern))
+	}
+	return "{" + strings.Join(s, ", ") + "}"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func formatSchedule(s *pb.Schedule) string {
+	if s == nil {
+		return ""
+	}
+	res := fmt.Sprintf("%s for %s", s.Cron, s.Duration)
+	if !s.Until.IsZero() {
+		res += " until " + formatTime(s.Until)
+	}
+	return res
+}
+
+// Query returns the events matching the query sorted by time, oldest first.
+func (l *Log) Query(q Query) []*Event {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	var res []*Event
+	for _, e := range l.events {
+		if !q.matches(e) {
+			continue
+		}
+		c := *e
+		res = append(res, &c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Time.Equal(res[j].Time) {
+			return res[i].ID < res[j].ID
+		}
+		return res[i].Time.Before(res[j].Time)
+	})
+	return res
+}
+
+// Len returns the number of events in the log.
+func (l *Log) Len() int {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	return len(l.events)
+}
+
+// GC removes events that are older than the configured retention time.
+func (l *Log) GC() (int, error) {
+	start := time.Now()
+	defer func() { l.metrics.gcDuration.Observe(time.Since(start).Seconds()) }()
+
+	if l.retention <= 0 {
+		return 0, nil
+	}
+	now := l.now()
+	var n int
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	for id, e := range l.events {
+		if !e.Time.Add(l.retention).After(now) {
+			delete(l.events, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Maintenance garbage collects the log at the given interval. If the snapshot
+// file is set, a snapshot is written to it afterwards.
+// Terminates on receiving from stopc.
+// If not nil, the last argument is an override for what to do as part of the maintenance - for advanced usage.
+func (l *Log) Maintenance(interval time.Duration, snapf string, stopc <-chan struct{}, override MaintenanceFunc) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	doMaintenance := func() (int64, error) {
+		var size int64
+
+		if _, err := l.GC(); err != nil {
+			return size, err
+		}
+		if snapf == "" {
+			return size, nil
+		}
+		f, err := openReplace(snapf)
+		if err != nil {
+			return size, err
+		}
+		if size, err = l.Snapshot(f); err != nil {
+			return size, err
+		}
+		return size, f.Close()
+	}
+
+	if override != nil {
+		doMaintenance = override
+	}
+
+	runMaintenance := func(do MaintenanceFunc) error {
+		start := l.now()
+		level.Debug(l.logger).Log("msg", "Running maintenance")
+		size, err := do()
+		level.Debug(l.logger).Log("msg", "Maintenance done", "duration", l.now().Sub(start), "size", size)
+		l.metrics.snapshotSize.Set(float64(size))
+		return err
+	}
+
+Loop:
+	for {
+		select {
+		case <-stopc:
+			break Loop
+		case <-t.C:
+			if err := runMaintenance(doMaintenance); err != nil {
+				level.Info(l.logger).Log("msg", "Running maintenance failed", "err", err)
+			}
+		}
+	}
+	// No need for final maintenance if we don't want to snapshot.
+	if snapf == "" {
+		return
+	}
+	if err := runMaintenance(doMaintenance); err != nil {
+		level.Info(l.logger).Log("msg", "Creating shutdown snapshot failed", "err", err)
+	}
+}
+
+// loadSnapshot loads a snapshot generated by Snapshot() into the state.
+func (l *Log) loadSnapshot(r io.Reader) error {
+	events := map[string]*Event{}
+
+	dec := json.NewDecoder(r)
+	for {
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		if e.ID == "" {
+			return errors.New("invalid silence event: missing ID")
+		}
+		events[e.ID] = &e
+	}
+
+	l.mtx.Lock()
+	l.events = events
+	l.mtx.Unlock()
+
+	return nil
+}
+
+// Snapshot writes the full internal state into the writer and returns the number of bytes
+// written.
+func (l *Log) Snapshot(w io.Writer) (int64, error) {
+	start := time.Now()
+	defer func() { l.metrics.snapshotDuration.Observe(time.Since(start).Seconds()) }()
+
+	cw := &countingWriter{w: w}
+	enc := json.NewEncoder(cw)
+	for _, e := range l.Query(Query{}) {
+		if err := enc.Encode(e); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type replaceFile struct {
+	*os.File
+	filename string
+}
+
+func (f *replaceFile) Close() error {
+	if err := f.File.Sync(); err != nil {
+		return err
+	}
+	if err := f.File.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.File.Name(), f.filename)
+}
+
+// openReplace opens a new temporary file that is moved to filename on closing.
+func openReplace(filename string) (*replaceFile, error) {
+	tmpFilename := fmt.Sprintf("%s.%x", filename, uint64(rand.Int63()))
+
+	f, err := os.Create(tmpFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &replaceFile{
+		File:     f,
+		filename: filename,
+	}
+	return rf, nil
+}
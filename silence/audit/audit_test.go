@@ -0,0 +1,133 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/silence"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+func TestRecordSilenceChanges(t *testing.T) {
+	l, err := New(Options{Retention: time.Hour})
+	require.NoError(t, err)
+	s, err := silence.New(silence.Options{Retention: time.Hour, OnChange: l.Record})
+	require.NoError(t, err)
+
+	now := time.Now()
+	id, err := s.Set(&pb.Silence{
+		Matchers:  []*pb.Matcher{{Name: "job", Pattern: "api"}},
+		StartsAt:  now.Add(time.Hour),
+		EndsAt:    now.Add(2 * time.Hour),
+		CreatedBy: "alice",
+		Comment:   "maintenance",
+	})
+	require.NoError(t, err)
+
+	sil, err := s.QueryOne(silence.QIDs(id))
+	require.NoError(t, err)
+	sil.Comment = "longer maintenance"
+	sil.EndsAt = now.Add(3 * time.Hour)
+	sil.CreatedBy = "bob"
+	_, err = s.Set(sil)
+	require.NoError(t, err)
+
+	require.NoError(t, s.ExpireBy(id, "carol"))
+
+	events := l.Query(Query{SilenceID: id})
+	require.Len(t, events, 3)
+
+	require.Equal(t, EventCreated, events[0].Type)
+	require.Equal(t, "alice", events[0].User)
+	require.Contains(t, events[0].Changes, Change{Field: "matchers", New: `{job="api"}`})
+	require.Contains(t, events[0].Changes, Change{Field: "comment", New: "maintenance"})
+
+	require.Equal(t, EventUpdated, events[1].Type)
+	require.Equal(t, "bob", events[1].User)
+	require.Equal(t, []Change{
+		{Field: "endsAt", Old: now.Add(2 * time.Hour).UTC().Format(time.RFC3339), New: now.Add(3 * time.Hour).UTC().Format(time.RFC3339)},
+		{Field: "createdBy", Old: "alice", New: "bob"},
+		{Field: "comment", Old: "maintenance", New: "longer maintenance"},
+	}, events[1].Changes)
+
+	require.Equal(t, EventExpired, events[2].Type)
+	require.Equal(t, "carol", events[2].User)
+
+	require.Len(t, l.Query(Query{User: "bob"}), 1)
+	require.Len(t, l.Query(Query{Since: events[1].Time}), 2)
+	require.Len(t, l.Query(Query{Until: events[0].Time}), 1)
+	require.Empty(t, l.Query(Query{SilenceID: "other"}))
+}
+
+func TestNewEvent(t *testing.T) {
+	now := time.Now().UTC()
+	sil := &pb.Silence{
+		Id:        "id",
+		Matchers:  []*pb.Matcher{{Name: "job", Pattern: "api"}},
+		StartsAt:  now.Add(-time.Hour),
+		EndsAt:    now.Add(time.Hour),
+		UpdatedAt: now.Add(-time.Hour),
+		Schedule:  &pb.Schedule{Cron: "@daily", Duration: 2 * time.Hour},
+	}
+
+	// Only the update timestamp changed.
+	cur := *sil
+	cur.UpdatedAt = now
+	require.Nil(t, NewEvent(sil, &cur))
+
+	// A recurring silence moved to its next occurrence.
+	cur.StartsAt = now.Add(22 * time.Hour)
+	cur.EndsAt = now.Add(24 * time.Hour)
+	e := NewEvent(sil, &cur)
+	require.Equal(t, EventRescheduled, e.Type)
+	require.Equal(t, "", e.User)
+	require.Equal(t, fmt.Sprintf("id-%d", now.UnixNano()), e.ID)
+	require.Len(t, e.Changes, 2)
+}
+
+func TestSnapshotAndGC(t *testing.T) {
+	l, err := New(Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	l.now = func() time.Time { return now }
+	for i, ts := range []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Minute)} {
+		l.Record(nil, &pb.Silence{
+			Id:        string(rune('a' + i)),
+			Matchers:  []*pb.Matcher{{Name: "job", Pattern: "api"}},
+			UpdatedAt: ts,
+		})
+	}
+
+	var buf bytes.Buffer
+	_, err = l.Snapshot(&buf)
+	require.NoError(t, err)
+
+	l2, err := New(Options{SnapshotReader: &buf})
+	require.NoError(t, err)
+	require.Equal(t, l.Query(Query{}), l2.Query(Query{}))
+
+	n, err := l.GC()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	events := l.Query(Query{})
+	require.Len(t, events, 1)
+	require.Equal(t, "b", events[0].SilenceID)
+}
@@ -0,0 +1,218 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package expiry notifies receivers about silences that are about to expire.
+// Silences opt in by naming a receiver and a lead time; the notification is
+// sent once when the silence enters that window.
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/silence"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// AlertName is the name of the alerts sent for expiring silences.
+const AlertName = "SilenceExpiring"
+
+type metrics struct {
+	notificationsTotal       prometheus.Counter
+	notificationsFailedTotal prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		notificationsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_silence_expiry_notifications_total",
+			Help: "The total number of notifications sent about expiring silences.",
+		}),
+		notificationsFailedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_silence_expiry_notifications_failed_total",
+			Help: "The total number of failed notifications about expiring silences.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(m.notificationsTotal, m.notificationsFailedTotal)
+	}
+	return m
+}
+
+// Notifier periodically checks the active silences and notifies the receiver
+// of those entering their notification window.
+type Notifier struct {
+	silences  *silence.Silences
+	receivers func(name string) ([]notify.Integration, bool)
+	isLeader  func() bool
+	logger    log.Logger
+	metrics   *metrics
+	now       func() time.Time
+
+	mtx sync.Mutex
+	// The end time of the silences already notified about. A silence
+	// that is extended afterwards is notified about again.
+	notified map[string]time.Time
+}
+
+// New returns a new Notifier. The receivers function looks up the
+// integrations of a receiver in the current configuration. If isLeader is not
+// nil, notifications are only sent while it returns true, so that only one
+// member of a cluster sends them.
+func New(
+	s *silence.Silences,
+	receivers func(name string) ([]notify.Integration, bool),
+	isLeader func() bool,
+	l log.Logger,
+	r prometheus.Registerer,
+) *Notifier {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	return &Notifier{
+		silences:  s,
+		receivers: receivers,
+		isLeader:  isLeader,
+		logger:    l,
+		metrics:   newMetrics(r),
+		now:       time.Now,
+		notified:  map[string]time.Time{},
+	}
+}
+
+// Run checks the silences at the given interval until stopc is closed.
+func (n *Notifier) Run(interval time.Duration, stopc <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			return
+		case <-t.C:
+			n.Check(context.Background())
+		}
+	}
+}
+
+// Check sends the notifications for all active silences which entered their
+// notification window since the last check.
+func (n *Notifier) Check(ctx context.Context) {
+	sils, _, err := n.silences.Query(silence.QState(types.SilenceStateActive))
+	if err != nil {
+		level.Error(n.logger).Log("msg", "Querying silences failed", "err", err)
+		return
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	now := n.now()
+	active := make(map[string]struct{}, len(sils))
+	for _, s := range sils {
+		if s.NotifyReceiver == "" {
+			continue
+		}
+		active[s.Id] = struct{}{}
+		if now.Before(s.EndsAt.Add(-s.NotifyBefore)) {
+			continue
+		}
+		if endsAt, ok := n.notified[s.Id]; ok && endsAt.Equal(s.EndsAt) {
+			continue
+		}
+		if n.isLeader != nil && !n.isLeader() {
+			// Other members record the silence as well, so that they
+			// don't notify about it again after a leader change.
+			n.notified[s.Id] = s.EndsAt
+			continue
+		}
+		if err := n.notify(ctx, s, now); err != nil {
+			n.metrics.notificationsFailedTotal.Inc()
+			level.Error(n.logger).Log("msg", "Notifying about expiring silence failed", "id", s.Id, "receiver", s.NotifyReceiver, "err", err)
+			continue
+		}
+		n.metrics.notificationsTotal.Inc()
+		n.notified[s.Id] = s.EndsAt
+	}
+	// Forget about silences that expired or were deleted.
+	for id := range n.notified {
+		if _, ok := active[id]; !ok {
+			delete(n.notified, id)
+		}
+	}
+}
+
+func (n *Notifier) notify(ctx context.Context, s *pb.Silence, now time.Time) error {
+	integrations, ok := n.receivers(s.NotifyReceiver)
+	if !ok {
+		return fmt.Errorf("receiver %q not found in current configuration", s.NotifyReceiver)
+	}
+
+	alert := Alert(s, now)
+	ctx = notify.WithReceiverName(ctx, s.NotifyReceiver)
+	ctx = notify.WithGroupKey(ctx, "silence-expiry/"+s.Id)
+	ctx = notify.WithGroupLabels(ctx, alert.Labels)
+	ctx = notify.WithNow(ctx, now)
+	ctx = notify.WithFiringAlerts(ctx, []uint64{uint64(alert.Fingerprint())})
+
+	var lastErr error
+	for _, i := range integrations {
+		if _, err := i.Notify(ctx, alert); err != nil {
+			level.Warn(n.logger).Log("msg", "Notify for expiring silence failed", "id", s.Id, "integration", i.String(), "err", err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+var matchOps = map[pb.Matcher_Type]string{
+	pb.Matcher_EQUAL:      "=",
+	pb.Matcher_NOT_EQUAL:  "!=",
+	pb.Matcher_REGEXP:     "=~",
+	pb.Matcher_NOT_REGEXP: "!~",
+}
+
+// Alert returns the alert describing the expiry of the given silence.
+func Alert(s *pb.Silence, now time.Time) *types.Alert {
+	ms := make([]string, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		ms = append(ms, fmt.Sprintf("%s%s%q", m.Name, matchOps[m.Type], m.Pattern))
+	}
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{
+				model.AlertNameLabel: AlertName,
+				"silence_id":         model.LabelValue(s.Id),
+				"created_by":         model.LabelValue(s.CreatedBy),
+			},
+			Annotations: model.LabelSet{
+				"summary":  model.LabelValue(fmt.Sprintf("Silence %s expires at %s", s.Id, s.EndsAt.UTC().Format(time.RFC3339))),
+				"comment":  model.LabelValue(s.Comment),
+				"matchers": model.LabelValue("{" + strings.Join(ms, ", ") + "}"),
+			},
+			StartsAt: now,
+			EndsAt:   s.EndsAt,
+		},
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/silence"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+)
+
+type sendResolved bool
+
+func (s sendResolved) SendResolved() bool { return bool(s) }
+
+type recordingNotifier struct {
+	alerts    []*types.Alert
+	receivers []string
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	name, _ := notify.ReceiverName(ctx)
+	r.receivers = append(r.receivers, name)
+	r.alerts = append(r.alerts, alerts...)
+	return false, nil
+}
+
+func TestNotifierCheck(t *testing.T) {
+	silences, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	now := time.Now()
+	newSilence := func(receiver string, endsAt time.Time, before time.Duration) string {
+		id, err := silences.Set(&pb.Silence{
+			Matchers:       []*pb.Matcher{{Name: "job", Pattern: "api"}},
+			StartsAt:       now.Add(-time.Minute),
+			EndsAt:         endsAt,
+			CreatedBy:      "alice",
+			Comment:        "maintenance",
+			NotifyReceiver: receiver,
+			NotifyBefore:   before,
+		})
+		require.NoError(t, err)
+		return id
+	}
+	expiring := newSilence("team", now.Add(5*time.Minute), 10*time.Minute)
+	newSilence("team", now.Add(time.Hour), 10*time.Minute)
+	newSilence("", now.Add(time.Minute), 0)
+
+	rec := &recordingNotifier{}
+	leader := true
+	n := New(silences, func(name string) ([]notify.Integration, bool) {
+		if name != "team" {
+			return nil, false
+		}
+		return []notify.Integration{notify.NewIntegration(rec, sendResolved(false), "webhook", 0)}, true
+	}, func() bool { return leader }, nil, prometheus.NewRegistry())
+	n.now = func() time.Time { return now }
+
+	n.Check(context.Background())
+	require.Len(t, rec.alerts, 1)
+	require.Equal(t, []string{"team"}, rec.receivers)
+	a := rec.alerts[0]
+	require.Equal(t, model.LabelValue(AlertName), a.Labels[model.AlertNameLabel])
+	require.Equal(t, model.LabelValue(expiring), a.Labels["silence_id"])
+	require.Equal(t, model.LabelValue("alice"), a.Labels["created_by"])
+	require.Equal(t, model.LabelValue(`{job="api"}`), a.Annotations["matchers"])
+
+	// The silence is only notified about once.
+	n.Check(context.Background())
+	require.Len(t, rec.alerts, 1)
+
+	// Extending the silence notifies again when the new window is reached.
+	sil, err := silences.QueryOne(silence.QIDs(expiring))
+	require.NoError(t, err)
+	sil.EndsAt = now.Add(8 * time.Minute)
+	expiring, err = silences.Set(sil)
+	require.NoError(t, err)
+	n.Check(context.Background())
+	require.Len(t, rec.alerts, 2)
+
+	// Only the leader notifies.
+	leader = false
+	newSilence("team", now.Add(time.Minute), time.Minute)
+	n.Check(context.Background())
+	require.Len(t, rec.alerts, 2)
+	leader = true
+	n.Check(context.Background())
+	require.Len(t, rec.alerts, 2)
+
+	// Expired silences are forgotten.
+	require.NoError(t, silences.Expire(expiring))
+	n.Check(context.Background())
+	_, ok := n.notified[expiring]
+	require.False(t, ok)
+}
+
+func TestNotifierUnknownReceiver(t *testing.T) {
+	silences, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	now := time.Now()
+	_, err = silences.Set(&pb.Silence{
+		Matchers:       []*pb.Matcher{{Name: "job", Pattern: "api"}},
+		StartsAt:       now.Add(-time.Minute),
+		EndsAt:         now.Add(time.Minute),
+		NotifyReceiver: "gone",
+		NotifyBefore:   time.Hour,
+	})
+	require.NoError(t, err)
+
+	n := New(silences, func(string) ([]notify.Integration, bool) { return nil, false }, nil, nil, nil)
+	n.Check(context.Background())
+	require.Empty(t, n.notified)
+}
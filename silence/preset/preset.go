@@ -0,0 +1,85 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preset instantiates silences from the silence presets of the
+// configuration.
+package preset
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+var matchTypes = map[labels.MatchType]pb.Matcher_Type{
+	labels.MatchEqual:     pb.Matcher_EQUAL,
+	labels.MatchNotEqual:  pb.Matcher_NOT_EQUAL,
+	labels.MatchRegexp:    pb.Matcher_REGEXP,
+	labels.MatchNotRegexp: pb.Matcher_NOT_REGEXP,
+}
+
+// Instantiate returns a new silence created from the preset, starting at the
+// given time. The templates of the preset are executed with the parameters;
+// a parameter used by them but not given is an error. If the duration is
+// zero, the duration of the preset is used.
+func Instantiate(p *config.SilencePreset, params map[string]string, createdBy string, d time.Duration, now time.Time) (*pb.Silence, error) {
+	if d == 0 {
+		d = time.Duration(p.Duration)
+	}
+	sil := &pb.Silence{
+		StartsAt:  now,
+		EndsAt:    now.Add(d),
+		CreatedBy: createdBy,
+	}
+
+	for _, text := range p.Matchers {
+		s, err := execute(text, params)
+		if err != nil {
+			return nil, err
+		}
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %q: %v", s, err)
+		}
+		sil.Matchers = append(sil.Matchers, &pb.Matcher{
+			Type:    matchTypes[m.Type],
+			Name:    m.Name,
+			Pattern: m.Value,
+		})
+	}
+
+	comment, err := execute(p.Comment, params)
+	if err != nil {
+		return nil, err
+	}
+	sil.Comment = comment
+
+	return sil, nil
+}
+
+func execute(text string, params map[string]string) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
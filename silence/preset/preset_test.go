@@ -0,0 +1,58 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+func TestInstantiate(t *testing.T) {
+	p := &config.SilencePreset{
+		Name:     "node-maintenance",
+		Matchers: []string{`instance="{{ .instance }}"`, `severity!~"critical|page"`},
+		Duration: model.Duration(2 * time.Hour),
+		Comment:  "Maintenance of {{ .instance }}",
+	}
+	now := time.Now()
+
+	sil, err := Instantiate(p, map[string]string{"instance": "node1"}, "alice", 0, now)
+	require.NoError(t, err)
+	require.Equal(t, &pb.Silence{
+		Matchers: []*pb.Matcher{
+			{Type: pb.Matcher_EQUAL, Name: "instance", Pattern: "node1"},
+			{Type: pb.Matcher_NOT_REGEXP, Name: "severity", Pattern: "critical|page"},
+		},
+		StartsAt:  now,
+		EndsAt:    now.Add(2 * time.Hour),
+		CreatedBy: "alice",
+		Comment:   "Maintenance of node1",
+	}, sil)
+
+	sil, err = Instantiate(p, map[string]string{"instance": "node1"}, "alice", time.Hour, now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Hour), sil.EndsAt)
+
+	_, err = Instantiate(p, nil, "alice", 0, now)
+	require.Error(t, err)
+
+	_, err = Instantiate(p, map[string]string{"instance": `"`}, "alice", 0, now)
+	require.Error(t, err)
+}
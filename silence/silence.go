@@ -33,6 +33,7 @@ import (
 	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	"github.com/pkg/errors"
 	"github.com/prometheus/alertmanager/cluster"
+	"github.com/prometheus/alertmanager/pkg/cron"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	pb "github.com/prometheus/alertmanager/silence/silencepb"
 	"github.com/prometheus/alertmanager/types"
@@ -198,6 +199,7 @@ type Silences struct {
 	st        state
 	version   int // Increments whenever silences are added.
 	broadcast func([]byte)
+	onChange  func(prev, cur *pb.Silence)
 	mc        matcherCache
 }
 
@@ -303,6 +305,12 @@ type Options struct {
 	// garbage collected after the given duration after they ended.
 	Retention time.Duration
 
+	// If set, it is called with the previous and the new version of a
+	// silence whenever one is created or changed, locally or by a peer. The
+	// previous version is nil for new silences. It must not call back into
+	// the Silences.
+	OnChange func(prev, cur *pb.Silence)
+
 	// A logger used by background processing.
 	Logger  log.Logger
 	Metrics prometheus.Registerer
@@ -335,6 +343,7 @@ func New(o Options) (*Silences, error) {
 		retention: o.Retention,
 		now:       utcNow,
 		broadcast: func([]byte) {},
+		onChange:  o.OnChange,
 		st:        state{},
 	}
 	s.metrics = newMetrics(o.Metrics, s)
@@ -497,9 +506,65 @@ func validateSilence(s *pb.Silence) error {
 	if s.UpdatedAt.IsZero() {
 		return errors.New("invalid zero update timestamp")
 	}
+	if s.NotifyBefore < 0 {
+		return errors.New("notification lead time must not be negative")
+	}
+	if s.NotifyReceiver != "" && s.NotifyBefore == 0 {
+		return errors.New("notification lead time required for notify receiver")
+	}
+	if s.Schedule != nil {
+		if _, err := cron.Parse(s.Schedule.Cron); err != nil {
+			return errors.Wrap(err, "invalid schedule")
+		}
+		if s.Schedule.Duration <= 0 {
+			return errors.New("schedule duration must be positive")
+		}
+	}
 	return nil
 }
 
+// Occurrence is a time range during which a silence is active.
+type Occurrence struct {
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// nextOccurrence returns the first occurrence of the schedule which ends
+// after the given time. It returns false if there is none.
+func nextOccurrence(sched *pb.Schedule, after time.Time) (Occurrence, bool, error) {
+	c, err := cron.Parse(sched.Cron)
+	if err != nil {
+		return Occurrence{}, false, err
+	}
+	start := c.Next(after.Add(-sched.Duration))
+	if start.IsZero() || (!sched.Until.IsZero() && start.After(sched.Until)) {
+		return Occurrence{}, false, nil
+	}
+	return Occurrence{StartsAt: start, EndsAt: start.Add(sched.Duration)}, true, nil
+}
+
+// Occurrences returns up to n occurrences of the silence which have not
+// ended at the given time. A silence without a schedule has at most one.
+func Occurrences(sil *pb.Silence, now time.Time, n int) ([]Occurrence, error) {
+	var res []Occurrence
+	if n <= 0 || getState(sil, now) == types.SilenceStateExpired {
+		return res, nil
+	}
+	res = append(res, Occurrence{StartsAt: sil.StartsAt, EndsAt: sil.EndsAt})
+	if sil.Schedule == nil {
+		return res, nil
+	}
+	for len(res) < n {
+		// The next occurrence starts after the previous one.
+		o, ok, err := nextOccurrence(sil.Schedule, res[len(res)-1].StartsAt.Add(sil.Schedule.Duration))
+		if err != nil || !ok {
+			return res, err
+		}
+		res = append(res, o)
+	}
+	return res, nil
+}
+
 // cloneSilence returns a shallow copy of a silence.
 func cloneSilence(sil *pb.Silence) *pb.Silence {
 	s := *sil
@@ -530,8 +595,10 @@ func (s *Silences) setSilence(sil *pb.Silence, now time.Time) error {
 		return err
 	}
 
+	prev := s.st[sil.Id]
 	if s.st.merge(msil, now) {
 		s.version++
+		s.changed(prev, sil)
 	}
 	s.broadcast(b)
 
@@ -550,13 +617,25 @@ func (s *Silences) Set(sil *pb.Silence) (string, error) {
 	if sil.Id != "" && !ok {
 		return "", ErrNotFound
 	}
+	if sil.Schedule != nil {
+		// The time range of a recurring silence is its current or next
+		// occurrence.
+		o, found, err := nextOccurrence(sil.Schedule, now)
+		if err != nil {
+			return "", errors.Wrap(err, "silence invalid: invalid schedule")
+		}
+		if !found {
+			return "", errors.New("silence invalid: schedule has no upcoming occurrences")
+		}
+		sil.StartsAt, sil.EndsAt = o.StartsAt, o.EndsAt
+	}
 	if ok {
 		if canUpdate(prev, sil, now) {
 			return sil.Id, s.setSilence(sil, now)
 		}
 		if getState(prev, s.now()) != types.SilenceStateExpired {
 			// We cannot update the silence, expire the old one.
-			if err := s.expire(prev.Id); err != nil {
+			if err := s.expire(prev.Id, sil.CreatedBy); err != nil {
 				return "", errors.Wrap(err, "expire previous silence")
 			}
 		}
@@ -602,21 +681,78 @@ func canUpdate(a, b *pb.Silence, now time.Time) bool {
 	return true
 }
 
+// Reschedule moves recurring silences to their next occurrence at the given
+// interval, once their current one ended. Terminates on receiving from stopc.
+func (s *Silences) Reschedule(interval time.Duration, stopc <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			return
+		case <-t.C:
+			if _, err := s.reschedule(); err != nil {
+				level.Error(s.logger).Log("msg", "Rescheduling silences failed", "err", err)
+			}
+		}
+	}
+}
+
+// reschedule moves all recurring silences whose occurrence ended to their
+// next occurrence. It returns the number of rescheduled silences.
+func (s *Silences) reschedule() (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	var n int
+	for _, msil := range s.st {
+		sil := msil.Silence
+		if sil.Schedule == nil || !now.After(sil.EndsAt) {
+			continue
+		}
+		o, ok, err := nextOccurrence(sil.Schedule, now)
+		if err != nil {
+			return n, errors.Wrapf(err, "silence %s", sil.Id)
+		}
+		if !ok {
+			continue
+		}
+		sil = cloneSilence(sil)
+		sil.StartsAt, sil.EndsAt = o.StartsAt, o.EndsAt
+		if err := s.setSilence(sil, now); err != nil {
+			return n, errors.Wrapf(err, "silence %s", sil.Id)
+		}
+		n++
+	}
+	return n, nil
+}
+
 // Expire the silence with the given ID immediately.
 func (s *Silences) Expire(id string) error {
+	return s.ExpireBy(id, "")
+}
+
+// ExpireBy expires the silence with the given ID immediately and records the
+// user who expired it.
+func (s *Silences) ExpireBy(id, user string) error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
-	return s.expire(id)
+	return s.expire(id, user)
 }
 
 // Expire the silence with the given ID immediately.
-func (s *Silences) expire(id string) error {
+func (s *Silences) expire(id, user string) error {
 	sil, ok := s.getSilence(id)
 	if !ok {
 		return ErrNotFound
 	}
 	sil = cloneSilence(sil)
 	now := s.now()
+	// An expired silence does not recur anymore.
+	sil.Schedule = nil
+	sil.ExpiredBy = user
 
 	switch getState(sil, now) {
 	case types.SilenceStateExpired:
@@ -848,8 +984,10 @@ func (s *Silences) Merge(b []byte) error {
 	now := s.now()
 
 	for _, e := range st {
+		prev := s.st[e.Silence.Id]
 		if merged := s.st.merge(e, now); merged {
 			s.version++
+			s.changed(prev, e.Silence)
 			if !cluster.OversizedMessage(b) {
 				// If this is the first we've seen the message and it's
 				// not oversized, gossip it to other nodes. We don't
@@ -864,6 +1002,18 @@ func (s *Silences) Merge(b []byte) error {
 	return nil
 }
 
+// changed passes a merged change of a silence to the change callback.
+func (s *Silences) changed(prev *pb.MeshSilence, cur *pb.Silence) {
+	if s.onChange == nil {
+		return
+	}
+	if prev == nil {
+		s.onChange(nil, cur)
+		return
+	}
+	s.onChange(prev.Silence, cur)
+}
+
 // SetBroadcast sets the provided function as the one creating data to be
 // broadcast.
 func (s *Silences) SetBroadcast(f func([]byte)) {
@@ -1213,6 +1213,46 @@ func TestValidateSilence(t *testing.T) {
 			},
 			err: "invalid zero update timestamp",
 		},
+		{
+			s: &pb.Silence{
+				Id: "some_id",
+				Matchers: []*pb.Matcher{
+					&pb.Matcher{Name: "a", Pattern: "b"},
+				},
+				StartsAt:       validTimestamp,
+				EndsAt:         validTimestamp,
+				UpdatedAt:      validTimestamp,
+				NotifyReceiver: "team",
+				NotifyBefore:   time.Hour,
+			},
+			err: "",
+		},
+		{
+			s: &pb.Silence{
+				Id: "some_id",
+				Matchers: []*pb.Matcher{
+					&pb.Matcher{Name: "a", Pattern: "b"},
+				},
+				StartsAt:     validTimestamp,
+				EndsAt:       validTimestamp,
+				UpdatedAt:    validTimestamp,
+				NotifyBefore: -time.Hour,
+			},
+			err: "notification lead time must not be negative",
+		},
+		{
+			s: &pb.Silence{
+				Id: "some_id",
+				Matchers: []*pb.Matcher{
+					&pb.Matcher{Name: "a", Pattern: "b"},
+				},
+				StartsAt:       validTimestamp,
+				EndsAt:         validTimestamp,
+				UpdatedAt:      validTimestamp,
+				NotifyReceiver: "team",
+			},
+			err: "notification lead time required for notify receiver",
+		},
 	}
 	for _, c := range cases {
 		checkErr(t, c.err, validateSilence(c.s))
@@ -1407,3 +1447,102 @@ func Benchmark1000SilencesQuery(b *testing.B) {
 func Benchmark10000SilencesQuery(b *testing.B) {
 	benchmarkSilencesQuery(b, 10000)
 }
+
+func TestSilenceSchedule(t *testing.T) {
+	s, err := New(Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	// Tuesday.
+	now := time.Date(2021, 8, 10, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	// Every Saturday from 22:00 to 02:00.
+	id, err := s.Set(&pb.Silence{
+		Matchers: []*pb.Matcher{{Name: "a", Pattern: "b"}},
+		Schedule: &pb.Schedule{Cron: "0 22 * * sat", Duration: 4 * time.Hour},
+	})
+	require.NoError(t, err)
+
+	sil, err := s.QueryOne(QIDs(id))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 8, 14, 22, 0, 0, 0, time.UTC), sil.StartsAt)
+	require.Equal(t, time.Date(2021, 8, 15, 2, 0, 0, 0, time.UTC), sil.EndsAt)
+	require.Equal(t, types.SilenceStatePending, getState(sil, now))
+
+	occ, err := Occurrences(sil, now, 3)
+	require.NoError(t, err)
+	require.Equal(t, []Occurrence{
+		{time.Date(2021, 8, 14, 22, 0, 0, 0, time.UTC), time.Date(2021, 8, 15, 2, 0, 0, 0, time.UTC)},
+		{time.Date(2021, 8, 21, 22, 0, 0, 0, time.UTC), time.Date(2021, 8, 22, 2, 0, 0, 0, time.UTC)},
+		{time.Date(2021, 8, 28, 22, 0, 0, 0, time.UTC), time.Date(2021, 8, 29, 2, 0, 0, 0, time.UTC)},
+	}, occ)
+
+	// Nothing to do while the occurrence has not ended.
+	now = time.Date(2021, 8, 15, 1, 0, 0, 0, time.UTC)
+	n, err := s.reschedule()
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	// Once it ended, the silence moves to the next occurrence.
+	now = time.Date(2021, 8, 15, 3, 0, 0, 0, time.UTC)
+	n, err = s.reschedule()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	sil, err = s.QueryOne(QIDs(id))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 8, 21, 22, 0, 0, 0, time.UTC), sil.StartsAt)
+	require.Equal(t, time.Date(2021, 8, 22, 2, 0, 0, 0, time.UTC), sil.EndsAt)
+
+	// An occurrence in progress is materialized when the silence is set.
+	now = time.Date(2021, 8, 22, 1, 0, 0, 0, time.UTC)
+	sil.Schedule.Until = time.Date(2021, 8, 25, 0, 0, 0, 0, time.UTC)
+	_, err = s.Set(sil)
+	require.NoError(t, err)
+	sil, err = s.QueryOne(QIDs(id))
+	require.NoError(t, err)
+	require.Equal(t, types.SilenceStateActive, getState(sil, now))
+
+	// No occurrence starts after the end of the schedule.
+	occ, err = Occurrences(sil, now, 3)
+	require.NoError(t, err)
+	require.Len(t, occ, 1)
+	now = time.Date(2021, 8, 22, 3, 0, 0, 0, time.UTC)
+	n, err = s.reschedule()
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	sil, err = s.QueryOne(QIDs(id))
+	require.NoError(t, err)
+	require.Equal(t, types.SilenceStateExpired, getState(sil, now))
+
+	_, err = s.Set(&pb.Silence{
+		Matchers: []*pb.Matcher{{Name: "a", Pattern: "b"}},
+		Schedule: &pb.Schedule{Cron: "0 22 * * sat", Duration: time.Hour, Until: now},
+	})
+	require.EqualError(t, err, "silence invalid: schedule has no upcoming occurrences")
+}
+
+func TestSilenceExpireSchedule(t *testing.T) {
+	s, err := New(Options{Retention: time.Hour})
+	require.NoError(t, err)
+
+	now := time.Date(2021, 8, 10, 10, 30, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	id, err := s.Set(&pb.Silence{
+		Matchers: []*pb.Matcher{{Name: "a", Pattern: "b"}},
+		Schedule: &pb.Schedule{Cron: "@hourly", Duration: time.Hour},
+	})
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	require.NoError(t, s.Expire(id))
+
+	// An expired silence is not rescheduled.
+	now = now.Add(2 * time.Hour)
+	n, err := s.reschedule()
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	sil, err := s.QueryOne(QIDs(id))
+	require.NoError(t, err)
+	require.Nil(t, sil.Schedule)
+	require.Equal(t, types.SilenceStateExpired, getState(sil, now))
+}
@@ -166,8 +166,17 @@ type Silence struct {
 	// DEPRECATED: A set of comments made on the silence.
 	Comments []*Comment `protobuf:"bytes,7,rep,name=comments,proto3" json:"comments,omitempty"`
 	// Comment for the silence.
-	CreatedBy            string   `protobuf:"bytes,8,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
-	Comment              string   `protobuf:"bytes,9,opt,name=comment,proto3" json:"comment,omitempty"`
+	CreatedBy string `protobuf:"bytes,8,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Comment   string `protobuf:"bytes,9,opt,name=comment,proto3" json:"comment,omitempty"`
+	// The receiver notified the given duration before the silence expires.
+	// No notification is sent if the receiver is empty.
+	NotifyReceiver string        `protobuf:"bytes,10,opt,name=notify_receiver,json=notifyReceiver,proto3" json:"notify_receiver,omitempty"`
+	NotifyBefore   time.Duration `protobuf:"bytes,11,opt,name=notify_before,json=notifyBefore,proto3,stdduration" json:"notify_before"`
+	// If set, the silence recurs according to the schedule. The time range
+	// above is the current or next occurrence.
+	Schedule *Schedule `protobuf:"bytes,12,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	// The user who expired the silence, if known.
+	ExpiredBy            string   `protobuf:"bytes,13,opt,name=expired_by,json=expiredBy,proto3" json:"expired_by,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -206,6 +215,52 @@ func (m *Silence) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Silence proto.InternalMessageInfo
 
+// Schedule specifies the occurrences of a recurring silence.
+type Schedule struct {
+	// A cron expression for the start times of the occurrences.
+	Cron string `protobuf:"bytes,1,opt,name=cron,proto3" json:"cron,omitempty"`
+	// The duration of each occurrence.
+	Duration time.Duration `protobuf:"bytes,2,opt,name=duration,proto3,stdduration" json:"duration"`
+	// No occurrence starts after this time if it is set.
+	Until                time.Time `protobuf:"bytes,3,opt,name=until,proto3,stdtime" json:"until"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Schedule) Reset()         { *m = Schedule{} }
+func (m *Schedule) String() string { return proto.CompactTextString(m) }
+func (*Schedule) ProtoMessage()    {}
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7fc56058cf68dbd8, []int{3}
+}
+func (m *Schedule) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Schedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Schedule.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Schedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schedule.Merge(m, src)
+}
+func (m *Schedule) XXX_Size() int {
+	return m.Size()
+}
+func (m *Schedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schedule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schedule proto.InternalMessageInfo
+
 // MeshSilence wraps a regular silence with an expiration timestamp
 // after which the silence may be garbage collected.
 type MeshSilence struct {
@@ -220,7 +275,7 @@ func (m *MeshSilence) Reset()         { *m = MeshSilence{} }
 func (m *MeshSilence) String() string { return proto.CompactTextString(m) }
 func (*MeshSilence) ProtoMessage()    {}
 func (*MeshSilence) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7fc56058cf68dbd8, []int{3}
+	return fileDescriptor_7fc56058cf68dbd8, []int{4}
 }
 func (m *MeshSilence) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -254,42 +309,52 @@ func init() {
 	proto.RegisterType((*Matcher)(nil), "silencepb.Matcher")
 	proto.RegisterType((*Comment)(nil), "silencepb.Comment")
 	proto.RegisterType((*Silence)(nil), "silencepb.Silence")
+	proto.RegisterType((*Schedule)(nil), "silencepb.Schedule")
 	proto.RegisterType((*MeshSilence)(nil), "silencepb.MeshSilence")
 }
 
 func init() { proto.RegisterFile("silence.proto", fileDescriptor_7fc56058cf68dbd8) }
 
 var fileDescriptor_7fc56058cf68dbd8 = []byte{
-	// 461 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0xcd, 0x8e, 0xd2, 0x50,
-	0x14, 0xc7, 0xb9, 0x85, 0xa1, 0xdc, 0x43, 0x86, 0x90, 0x13, 0xa3, 0x0d, 0x89, 0x40, 0xba, 0x22,
-	0xd1, 0x94, 0x04, 0xb7, 0xba, 0x28, 0x13, 0xe2, 0xc6, 0xf1, 0xa3, 0x62, 0xe2, 0x6e, 0x52, 0xe8,
-	0x11, 0x9a, 0x4c, 0x3f, 0xd2, 0x1e, 0x12, 0x59, 0xe9, 0x23, 0xf8, 0x0c, 0x3e, 0x0d, 0x4b, 0x9f,
-	0xc0, 0x0f, 0xde, 0xc2, 0x9d, 0xe9, 0xed, 0x2d, 0xce, 0x84, 0x55, 0x77, 0xf7, 0xdc, 0xf3, 0xff,
-	0x9f, 0x8f, 0xdf, 0x81, 0xcb, 0x3c, 0xbc, 0xa5, 0x78, 0x4d, 0x4e, 0x9a, 0x25, 0x9c, 0xa0, 0xd4,
-	0x61, 0xba, 0x1a, 0x8c, 0x36, 0x49, 0xb2, 0xb9, 0xa5, 0xa9, 0x4a, 0xac, 0x76, 0x9f, 0xa6, 0x1c,
-	0x46, 0x94, 0xb3, 0x1f, 0xa5, 0xa5, 0x76, 0xf0, 0x60, 0x93, 0x6c, 0x12, 0xf5, 0x9c, 0x16, 0xaf,
-	0xf2, 0xd7, 0xfe, 0x2e, 0xc0, 0xbc, 0xf6, 0x79, 0xbd, 0xa5, 0x0c, 0x9f, 0x40, 0x8b, 0xf7, 0x29,
-	0x59, 0x62, 0x2c, 0x26, 0xbd, 0xd9, 0x23, 0xe7, 0x54, 0xdc, 0xd1, 0x0a, 0x67, 0xb9, 0x4f, 0xc9,
-	0x53, 0x22, 0x44, 0x68, 0xc5, 0x7e, 0x44, 0x96, 0x31, 0x16, 0x13, 0xe9, 0xa9, 0x37, 0x5a, 0x60,
-	0xa6, 0x3e, 0x33, 0x65, 0xb1, 0xd5, 0x54, 0xdf, 0x55, 0x68, 0x3f, 0x87, 0x56, 0xe1, 0x45, 0x09,
-	0x17, 0x8b, 0x77, 0x1f, 0xdc, 0x57, 0xfd, 0x06, 0x02, 0xb4, 0xbd, 0xc5, 0xcb, 0xc5, 0xc7, 0xb7,
-	0x7d, 0x81, 0x97, 0x20, 0x5f, 0xbf, 0x59, 0xde, 0x94, 0x29, 0x03, 0x7b, 0x00, 0x45, 0xa8, 0xd3,
-	0x4d, 0xfb, 0x0b, 0x98, 0x57, 0x49, 0x14, 0x51, 0xcc, 0xf8, 0x10, 0xda, 0xfe, 0x8e, 0xb7, 0x49,
-	0xa6, 0xa6, 0x94, 0x9e, 0x8e, 0x8a, 0xd6, 0xeb, 0x52, 0xa2, 0x27, 0xaa, 0x42, 0x9c, 0x83, 0x3c,
-	0xa1, 0x50, 0x63, 0x75, 0x67, 0x03, 0xa7, 0x84, 0xe5, 0x54, 0xb0, 0x9c, 0x65, 0xa5, 0x98, 0x77,
-	0x0e, 0x3f, 0x47, 0x8d, 0x6f, 0xbf, 0x46, 0xc2, 0xfb, 0x6f, 0xb3, 0xff, 0x1a, 0x60, 0xbe, 0x2f,
-	0x69, 0x60, 0x0f, 0x8c, 0x30, 0xd0, 0xdd, 0x8d, 0x30, 0x40, 0x07, 0x3a, 0x51, 0x89, 0x27, 0xb7,
-	0x8c, 0x71, 0x73, 0xd2, 0x9d, 0xe1, 0x39, 0x39, 0xef, 0xa4, 0x41, 0x17, 0x64, 0xce, 0x7e, 0xc6,
-	0xf9, 0x8d, 0xcf, 0xb5, 0xe6, 0xe9, 0x94, 0x36, 0x97, 0xf1, 0x05, 0x98, 0x14, 0x07, 0xaa, 0x40,
-	0xab, 0x46, 0x81, 0x76, 0x61, 0x72, 0x19, 0xaf, 0x00, 0x76, 0x69, 0xe0, 0x33, 0x05, 0x45, 0x85,
-	0x8b, 0x3a, 0x48, 0xb4, 0xcf, 0xe5, 0x62, 0x6d, 0x4d, 0x38, 0xb7, 0xcc, 0xb3, 0xb5, 0xf5, 0xb9,
-	0xbc, 0x93, 0x06, 0x1f, 0x03, 0xac, 0x33, 0x52, 0x4d, 0x57, 0x7b, 0xab, 0xa3, 0xf0, 0x49, 0xfd,
-	0x33, 0xdf, 0xdf, 0xbd, 0x9f, 0xbc, 0x77, 0x3f, 0xfb, 0xab, 0x80, 0xee, 0x35, 0xe5, 0xdb, 0x8a,
-	0xff, 0x53, 0x30, 0x75, 0x1f, 0x75, 0x84, 0xfb, 0x7d, 0xb5, 0xc8, 0xab, 0x24, 0xc5, 0xae, 0xf4,
-	0x39, 0x0d, 0x33, 0x52, 0xb4, 0x8c, 0x3a, 0xbb, 0x6a, 0x9f, 0xcb, 0xf3, 0xfe, 0xe1, 0xcf, 0xb0,
-	0x71, 0x38, 0x0e, 0xc5, 0x8f, 0xe3, 0x50, 0xfc, 0x3e, 0x0e, 0xc5, 0xaa, 0xad, 0xac, 0xcf, 0xfe,
-	0x05, 0x00, 0x00, 0xff, 0xff, 0xad, 0x09, 0x3a, 0xe9, 0x90, 0x03, 0x00, 0x00,
+	// 601 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xcd, 0x8e, 0xd3, 0x3c,
+	0x14, 0x9d, 0x64, 0x3a, 0x4d, 0x72, 0x3b, 0xed, 0x57, 0xf9, 0x43, 0x10, 0x2a, 0xd1, 0x56, 0xd9,
+	0x50, 0x09, 0x94, 0x4a, 0x65, 0x87, 0x40, 0x28, 0x19, 0x2a, 0x58, 0x30, 0xfc, 0x64, 0x8a, 0xc4,
+	0xae, 0x4a, 0x13, 0xb7, 0x8d, 0xd4, 0xc4, 0x91, 0xe3, 0x20, 0xb2, 0x82, 0x47, 0x60, 0x89, 0xc4,
+	0x8e, 0xe7, 0xe0, 0x01, 0xba, 0xe4, 0x09, 0xf8, 0xe9, 0x93, 0xa0, 0xd8, 0x4e, 0x98, 0xa1, 0x1b,
+	0xba, 0xf3, 0xbd, 0xf7, 0x1c, 0xfb, 0x9e, 0x73, 0xaf, 0xa1, 0x9d, 0x45, 0x1b, 0x9c, 0x04, 0xd8,
+	0x4e, 0x29, 0x61, 0x04, 0x19, 0x32, 0x4c, 0x17, 0xbd, 0xc1, 0x8a, 0x90, 0xd5, 0x06, 0x8f, 0x79,
+	0x61, 0x91, 0x2f, 0xc7, 0x2c, 0x8a, 0x71, 0xc6, 0xfc, 0x38, 0x15, 0xd8, 0x5e, 0xff, 0x6f, 0x40,
+	0x98, 0x53, 0x9f, 0x45, 0x24, 0x91, 0xf5, 0x6b, 0x2b, 0xb2, 0x22, 0xfc, 0x38, 0x2e, 0x4f, 0x22,
+	0x6b, 0x7d, 0x51, 0x40, 0x3b, 0xf7, 0x59, 0xb0, 0xc6, 0x14, 0xdd, 0x81, 0x06, 0x2b, 0x52, 0x6c,
+	0x2a, 0x43, 0x65, 0xd4, 0x99, 0xdc, 0xb0, 0xeb, 0xc7, 0x6d, 0x89, 0xb0, 0x67, 0x45, 0x8a, 0x3d,
+	0x0e, 0x42, 0x08, 0x1a, 0x89, 0x1f, 0x63, 0x53, 0x1d, 0x2a, 0x23, 0xc3, 0xe3, 0x67, 0x64, 0x82,
+	0x96, 0xfa, 0x8c, 0x61, 0x9a, 0x98, 0xc7, 0x3c, 0x5d, 0x85, 0xd6, 0x03, 0x68, 0x94, 0x5c, 0x64,
+	0xc0, 0xc9, 0xf4, 0xd5, 0x6b, 0xe7, 0x59, 0xf7, 0x08, 0x01, 0x34, 0xbd, 0xe9, 0x93, 0xe9, 0x9b,
+	0x97, 0x5d, 0x05, 0xb5, 0xc1, 0x78, 0xfe, 0x62, 0x36, 0x17, 0x25, 0x15, 0x75, 0x00, 0xca, 0x50,
+	0x96, 0x8f, 0xad, 0xf7, 0xa0, 0x9d, 0x91, 0x38, 0xc6, 0x09, 0x43, 0xd7, 0xa1, 0xe9, 0xe7, 0x6c,
+	0x4d, 0x28, 0xef, 0xd2, 0xf0, 0x64, 0x54, 0x3e, 0x1d, 0x08, 0x88, 0xec, 0xa8, 0x0a, 0x91, 0x0b,
+	0x46, 0x6d, 0x15, 0x6f, 0xab, 0x35, 0xe9, 0xd9, 0xc2, 0x2b, 0xbb, 0xf2, 0xca, 0x9e, 0x55, 0x08,
+	0x57, 0xdf, 0x7e, 0x1f, 0x1c, 0x7d, 0xfc, 0x31, 0x50, 0xbc, 0x3f, 0x34, 0xeb, 0x6b, 0x03, 0xb4,
+	0x0b, 0xe1, 0x06, 0xea, 0x80, 0x1a, 0x85, 0xf2, 0x75, 0x35, 0x0a, 0x91, 0x0d, 0x7a, 0x2c, 0xec,
+	0xc9, 0x4c, 0x75, 0x78, 0x3c, 0x6a, 0x4d, 0xd0, 0xbe, 0x73, 0x5e, 0x8d, 0x41, 0x0e, 0x18, 0x19,
+	0xf3, 0x29, 0xcb, 0xe6, 0x3e, 0x3b, 0xa8, 0x1f, 0x5d, 0xd0, 0x1c, 0x86, 0x1e, 0x82, 0x86, 0x93,
+	0x90, 0x5f, 0xd0, 0x38, 0xe0, 0x82, 0x66, 0x49, 0x72, 0x18, 0x3a, 0x03, 0xc8, 0xd3, 0xd0, 0x67,
+	0x38, 0x2c, 0x6f, 0x38, 0x39, 0xc4, 0x12, 0xc9, 0x73, 0x58, 0x29, 0x5b, 0x3a, 0x9c, 0x99, 0xda,
+	0x9e, 0x6c, 0x39, 0x2e, 0xaf, 0xc6, 0xa0, 0x5b, 0x00, 0x01, 0xc5, 0xfc, 0xd1, 0x45, 0x61, 0xea,
+	0xdc, 0x3e, 0x43, 0x66, 0xdc, 0xe2, 0xf2, 0xfc, 0x8c, 0xab, 0xf3, 0xbb, 0x0d, 0xff, 0x25, 0x84,
+	0x45, 0xcb, 0x62, 0x4e, 0x71, 0x80, 0xa3, 0xb7, 0x98, 0x9a, 0xc0, 0x11, 0x1d, 0x91, 0xf6, 0x64,
+	0x16, 0x3d, 0x85, 0xb6, 0x04, 0x2e, 0xf0, 0x92, 0x50, 0x6c, 0xb6, 0xb8, 0xb2, 0x9b, 0x7b, 0xca,
+	0x1e, 0xcb, 0x8f, 0x21, 0x84, 0x7d, 0x2a, 0x85, 0x9d, 0x0a, 0xa6, 0xcb, 0x89, 0x68, 0x0c, 0x7a,
+	0x16, 0xac, 0x71, 0x98, 0x6f, 0xb0, 0x79, 0xca, 0x2f, 0xf9, 0xff, 0x92, 0xb6, 0x0b, 0x59, 0xf2,
+	0x6a, 0x50, 0x29, 0x0e, 0xbf, 0x4b, 0x23, 0x2a, 0xc4, 0xb5, 0x85, 0x38, 0x99, 0x71, 0x0b, 0xeb,
+	0xb3, 0x02, 0x7a, 0xc5, 0x2a, 0x3f, 0x4e, 0x40, 0x49, 0x22, 0x37, 0x88, 0x9f, 0xd1, 0x23, 0xd0,
+	0xab, 0xdf, 0xca, 0xd7, 0xf7, 0x1f, 0xbb, 0xae, 0x49, 0xe8, 0x3e, 0x9c, 0xe4, 0x09, 0x8b, 0x36,
+	0x07, 0x2d, 0x94, 0xa0, 0x58, 0x1f, 0x14, 0x68, 0x9d, 0xe3, 0x6c, 0x5d, 0x2d, 0xf8, 0x5d, 0xd0,
+	0xa4, 0x58, 0xde, 0xe3, 0xd5, 0xc1, 0x4a, 0x90, 0x57, 0x41, 0xca, 0x65, 0x12, 0x42, 0xf9, 0x3a,
+	0xaa, 0x87, 0x2c, 0x93, 0xe4, 0x39, 0xcc, 0xed, 0x6e, 0x7f, 0xf5, 0x8f, 0xb6, 0xbb, 0xbe, 0xf2,
+	0x6d, 0xd7, 0x57, 0x7e, 0xee, 0xfa, 0xca, 0xa2, 0xc9, 0xa9, 0xf7, 0x7e, 0x07, 0x00, 0x00, 0xff,
+	0xff, 0xdf, 0x6d, 0x47, 0xe9, 0x11, 0x05, 0x00, 0x00,
 }
 
 func (m *Matcher) Marshal() (dAtA []byte, err error) {
@@ -411,6 +476,40 @@ func (m *Silence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.ExpiredBy) > 0 {
+		i -= len(m.ExpiredBy)
+		copy(dAtA[i:], m.ExpiredBy)
+		i = encodeVarintSilence(dAtA, i, uint64(len(m.ExpiredBy)))
+		i--
+		dAtA[i] = 0x6a
+	}
+	if m.Schedule != nil {
+		{
+			size, err := m.Schedule.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSilence(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	n3, err3 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.NotifyBefore, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.NotifyBefore):])
+	if err3 != nil {
+		return 0, err3
+	}
+	i -= n3
+	i = encodeVarintSilence(dAtA, i, uint64(n3))
+	i--
+	dAtA[i] = 0x5a
+	if len(m.NotifyReceiver) > 0 {
+		i -= len(m.NotifyReceiver)
+		copy(dAtA[i:], m.NotifyReceiver)
+		i = encodeVarintSilence(dAtA, i, uint64(len(m.NotifyReceiver)))
+		i--
+		dAtA[i] = 0x52
+	}
 	if len(m.Comment) > 0 {
 		i -= len(m.Comment)
 		copy(dAtA[i:], m.Comment)
@@ -439,28 +538,28 @@ func (m *Silence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			dAtA[i] = 0x3a
 		}
 	}
-	n2, err2 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.UpdatedAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.UpdatedAt):])
-	if err2 != nil {
-		return 0, err2
+	n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.UpdatedAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.UpdatedAt):])
+	if err4 != nil {
+		return 0, err4
 	}
-	i -= n2
-	i = encodeVarintSilence(dAtA, i, uint64(n2))
+	i -= n4
+	i = encodeVarintSilence(dAtA, i, uint64(n4))
 	i--
 	dAtA[i] = 0x2a
-	n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndsAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.EndsAt):])
-	if err3 != nil {
-		return 0, err3
+	n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndsAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.EndsAt):])
+	if err5 != nil {
+		return 0, err5
 	}
-	i -= n3
-	i = encodeVarintSilence(dAtA, i, uint64(n3))
+	i -= n5
+	i = encodeVarintSilence(dAtA, i, uint64(n5))
 	i--
 	dAtA[i] = 0x22
-	n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartsAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartsAt):])
-	if err4 != nil {
-		return 0, err4
+	n6, err6 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartsAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartsAt):])
+	if err6 != nil {
+		return 0, err6
 	}
-	i -= n4
-	i = encodeVarintSilence(dAtA, i, uint64(n4))
+	i -= n6
+	i = encodeVarintSilence(dAtA, i, uint64(n6))
 	i--
 	dAtA[i] = 0x1a
 	if len(m.Matchers) > 0 {
@@ -487,6 +586,56 @@ func (m *Silence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *Schedule) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Schedule) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Schedule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Until, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Until):])
+	if err7 != nil {
+		return 0, err7
+	}
+	i -= n7
+	i = encodeVarintSilence(dAtA, i, uint64(n7))
+	i--
+	dAtA[i] = 0x1a
+	n8, err8 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Duration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.Duration):])
+	if err8 != nil {
+		return 0, err8
+	}
+	i -= n8
+	i = encodeVarintSilence(dAtA, i, uint64(n8))
+	i--
+	dAtA[i] = 0x12
+	if len(m.Cron) > 0 {
+		i -= len(m.Cron)
+		copy(dAtA[i:], m.Cron)
+		i = encodeVarintSilence(dAtA, i, uint64(len(m.Cron)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *MeshSilence) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -511,12 +660,12 @@ func (m *MeshSilence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ExpiresAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ExpiresAt):])
-	if err5 != nil {
-		return 0, err5
+	n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ExpiresAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ExpiresAt):])
+	if err9 != nil {
+		return 0, err9
 	}
-	i -= n5
-	i = encodeVarintSilence(dAtA, i, uint64(n5))
+	i -= n9
+	i = encodeVarintSilence(dAtA, i, uint64(n9))
 	i--
 	dAtA[i] = 0x12
 	if m.Silence != nil {
@@ -626,6 +775,40 @@ func (m *Silence) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovSilence(uint64(l))
 	}
+	l = len(m.NotifyReceiver)
+	if l > 0 {
+		n += 1 + l + sovSilence(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.NotifyBefore)
+	n += 1 + l + sovSilence(uint64(l))
+	if m.Schedule != nil {
+		l = m.Schedule.Size()
+		n += 1 + l + sovSilence(uint64(l))
+	}
+	l = len(m.ExpiredBy)
+	if l > 0 {
+		n += 1 + l + sovSilence(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Schedule) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Cron)
+	if l > 0 {
+		n += 1 + l + sovSilence(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Duration)
+	n += 1 + l + sovSilence(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Until)
+	n += 1 + l + sovSilence(uint64(l))
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1230,6 +1413,288 @@ func (m *Silence) Unmarshal(dAtA []byte) error {
 			}
 			m.Comment = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotifyReceiver", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSilence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSilence
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSilence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NotifyReceiver = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotifyBefore", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSilence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSilence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSilence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.NotifyBefore, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Schedule", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSilence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSilence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSilence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Schedule == nil {
+				m.Schedule = &Schedule{}
+			}
+			if err := m.Schedule.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiredBy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSilence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSilence
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSilence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExpiredBy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSilence(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSilence
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Schedule) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSilence
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Schedule: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Schedule: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cron", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSilence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSilence
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSilence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cron = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Duration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSilence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSilence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSilence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Duration, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Until", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSilence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSilence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSilence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Until, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSilence(dAtA[iNdEx:])
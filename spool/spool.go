@@ -0,0 +1,222 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spool implements a directory-backed queue of outbound notifications.
+// A notification is written to the spool before it is sent and removed once it
+// was either delivered or given up on, so that notifications which were in
+// flight during a crash can be sent on the next start.
+package spool
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/types"
+)
+
+const fileSuffix = ".json"
+
+// Entry holds a notification that is about to be sent to an integration.
+type Entry struct {
+	// A unique identifier of the entry. Entries are ordered by their ID.
+	ID string `json:"id"`
+	// The name of the receiver and the integration the notification is
+	// destined for.
+	Receiver    string `json:"receiver"`
+	Integration string `json:"integration"`
+	Idx         uint32 `json:"idx"`
+	// The aggregation group the notification belongs to.
+	GroupKey    string         `json:"groupKey"`
+	GroupLabels model.LabelSet `json:"groupLabels"`
+	// The alerts of the notification and the hashes of the firing and
+	// resolved ones, as recorded in the notification log.
+	Alerts         []*types.Alert `json:"alerts"`
+	FiringAlerts   []uint64       `json:"firingAlerts"`
+	ResolvedAlerts []uint64       `json:"resolvedAlerts"`
+	// The time the notification was spooled.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Spool is a directory-backed queue of outbound notifications.
+type Spool struct {
+	dir     string
+	logger  log.Logger
+	metrics *metrics
+	now     func() time.Time
+
+	mtx sync.Mutex
+	seq uint64
+}
+
+type metrics struct {
+	entries       prometheus.Gauge
+	writeFailures prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		entries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alertmanager_spool_entries",
+			Help: "Number of outbound notifications currently held in the spool.",
+		}),
+		writeFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_spool_write_failures_total",
+			Help: "Total number of outbound notifications that could not be written to the spool.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(m.entries, m.writeFailures)
+	}
+	return m
+}
+
+// New returns a new Spool storing its entries in the given directory, which is
+// created if it doesn't exist.
+func New(dir string, l log.Logger, r prometheus.Registerer) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, err
+	}
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	s := &Spool{
+		dir:     dir,
+		logger:  l,
+		metrics: newMetrics(r),
+		now:     time.Now,
+	}
+	names, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.entries.Set(float64(len(names)))
+	return s, nil
+}
+
+// Put writes the entry to the spool and returns its ID. The ID and creation
+// time of the passed entry are ignored.
+func (s *Spool) Put(e *Entry) (string, error) {
+	c := *e
+
+	s.mtx.Lock()
+	now := s.now()
+	s.seq++
+	// Zero-padding keeps the lexical order of the IDs in line with the order
+	// in which the entries were added.
+	c.ID = fmt.Sprintf("%020d-%010d", now.UnixNano(), s.seq)
+	s.mtx.Unlock()
+	c.CreatedAt = now.UTC()
+
+	b, err := json.Marshal(&c)
+	if err != nil {
+		s.metrics.writeFailures.Inc()
+		return "", err
+	}
+	if err := writeFileSync(s.path(c.ID), b); err != nil {
+		s.metrics.writeFailures.Inc()
+		return "", err
+	}
+	s.metrics.entries.Inc()
+	return c.ID, nil
+}
+
+// Remove deletes the entry with the given ID from the spool.
+func (s *Spool) Remove(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		return err
+	}
+	s.metrics.entries.Dec()
+	return nil
+}
+
+// List returns all spooled entries in the order they were added. Files that
+// cannot be read are logged and skipped.
+func (s *Spool) List() ([]*Entry, error) {
+	names, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*Entry, 0, len(names))
+	for _, name := range names {
+		b, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			level.Warn(s.logger).Log("msg", "Failed to read spooled notification", "file", name, "err", err)
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			level.Warn(s.logger).Log("msg", "Failed to decode spooled notification", "file", name, "err", err)
+			continue
+		}
+		e.ID = strings.TrimSuffix(name, fileSuffix)
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
+
+func (s *Spool) names() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), fileSuffix) {
+			continue
+		}
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Spool) path(id string) string {
+	return filepath.Join(s.dir, id+fileSuffix)
+}
+
+// writeFileSync writes the data to a temporary file, syncs it and moves it
+// to filename so that a crash never leaves a partially written entry behind.
+func writeFileSync(filename string, b []byte) error {
+	tmp := filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
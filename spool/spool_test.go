@@ -0,0 +1,78 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/types"
+)
+
+func TestSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	var ids []string
+	for _, r := range []string{"team-X", "team-Y", "team-Z"} {
+		id, err := s.Put(&Entry{
+			Receiver:     r,
+			Integration:  "webhook",
+			GroupKey:     "1",
+			GroupLabels:  model.LabelSet{"alertname": "test"},
+			Alerts:       []*types.Alert{{Alert: model.Alert{Labels: model.LabelSet{"alertname": "test"}}}},
+			FiringAlerts: []uint64{1},
+		})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	// Entries added at the same time keep their order.
+	entries, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for i, e := range entries {
+		require.Equal(t, ids[i], e.ID)
+		require.Equal(t, now, e.CreatedAt)
+	}
+	require.Equal(t, "team-X", entries[0].Receiver)
+	require.Equal(t, []uint64{1}, entries[0].FiringAlerts)
+
+	require.NoError(t, s.Remove(ids[1]))
+	require.Error(t, s.Remove(ids[1]))
+
+	// A new spool on the same directory picks up the remaining entries and
+	// ignores unrelated and partially written files.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "foo.json.tmp"), []byte("{"), 0666))
+	s, err = New(dir, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+	entries, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, ids[0], entries[0].ID)
+	require.Equal(t, ids[2], entries[1].ID)
+}
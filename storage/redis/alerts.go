@@ -0,0 +1,411 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/store"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const (
+	// alertChannelLength is the minimum buffer of the channels of
+	// subscribers.
+	alertChannelLength = 200
+	// scanCount is the number of alerts requested per HSCAN, so that
+	// listing the alerts neither blocks the server nor loads the whole hash
+	// at once.
+	scanCount = 500
+)
+
+// Alerts is a provider of the alerts held by the server. Alerts put by any
+// replica are passed to the subscribers of all replicas.
+type Alerts struct {
+	b *Backend
+
+	mtx       sync.Mutex
+	listeners map[int]*listeningAlerts
+	next      int
+}
+
+// listeningAlerts queues the alerts of a subscriber, which are forwarded to
+// its channel by a goroutine, so that a slow subscriber doesn't hold up the
+// others nor the subscription.
+type listeningAlerts struct {
+	alerts chan *types.Alert
+	done   chan struct{}
+
+	mtx   sync.Mutex
+	queue []*types.Alert
+	wake  chan struct{}
+}
+
+func newListeningAlerts(done chan struct{}) *listeningAlerts {
+	return &listeningAlerts{
+		done: done,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// start forwards the queued alerts to the channel, which may already hold
+// alerts.
+func (l *listeningAlerts) start(ch chan *types.Alert) {
+	l.alerts = ch
+	go l.forward()
+}
+
+// push queues an alert without blocking.
+func (l *listeningAlerts) push(alert *types.Alert) {
+	l.mtx.Lock()
+	l.queue = append(l.queue, alert)
+	l.mtx.Unlock()
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// forward passes the queued alerts to the channel until the subscriber is
+// done, and then closes the channel.
+func (l *listeningAlerts) forward() {
+	defer close(l.alerts)
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-l.wake:
+		}
+		l.mtx.Lock()
+		queue := l.queue
+		l.queue = nil
+		l.mtx.Unlock()
+
+		for _, alert := range queue {
+			select {
+			case l.alerts <- alert:
+			case <-l.done:
+				return
+			}
+		}
+	}
+}
+
+func newAlerts(b *Backend) *Alerts {
+	return &Alerts{
+		b:         b,
+		listeners: map[int]*listeningAlerts{},
+	}
+}
+
+// Close implements storage.Alerts. The alerts are released when closing the
+// backend.
+func (a *Alerts) Close() {}
+
+// Subscribe implements provider.Alerts.
+func (a *Alerts) Subscribe() provider.AlertIterator {
+	// The listener is registered before listing, so that the alerts
+	// published meanwhile are queued after the listed ones and none of them
+	// is missed, without holding the lock while listing. They may be passed
+	// twice, which subscribers handle like any other update.
+	var (
+		done = make(chan struct{})
+		l    = newListeningAlerts(done)
+	)
+	a.mtx.Lock()
+	a.listeners[a.next] = l
+	a.next++
+	a.mtx.Unlock()
+
+	alerts, err := a.list()
+	ch := make(chan *types.Alert, max(len(alerts), alertChannelLength))
+	for _, alert := range alerts {
+		ch <- alert
+	}
+	l.start(ch)
+
+	return provider.NewAlertIterator(ch, done, err)
+}
+
+// GetPending implements provider.Alerts.
+func (a *Alerts) GetPending() provider.AlertIterator {
+	var (
+		ch   = make(chan *types.Alert, alertChannelLength)
+		done = make(chan struct{})
+	)
+
+	go func() {
+		defer close(ch)
+
+		// The alerts are decoded page by page as they are consumed.
+		err := a.scan(func(buf []byte) error {
+			alert, err := decodeAlert(buf)
+			if err != nil {
+				return err
+			}
+			select {
+			case ch <- alert:
+				return nil
+			case <-done:
+				return errDone
+			}
+		})
+		if err != nil && err != errDone {
+			level.Error(a.b.logger).Log("msg", "Failed to list pending alerts", "err", err)
+		}
+	}()
+
+	return provider.NewAlertIterator(ch, done, nil)
+}
+
+// errDone stops a scan whose consumer is gone.
+var errDone = errors.New("done")
+
+// Get implements provider.Alerts.
+func (a *Alerts) Get(fp model.Fingerprint) (*types.Alert, error) {
+	v, err := a.b.do("HGET", a.b.alertsKey(), fp.String())
+	if err != nil {
+		return nil, err
+	}
+	buf, ok := v.([]byte)
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return decodeAlert(buf)
+}
+
+// setUnchanged sets a field of a hash and publishes its value only if its
+// current value, or the empty string if it is unset, is unchanged, so that
+// an alert updated by another replica in the meantime isn't overwritten.
+const setUnchanged = `if (redis.call("HGET", KEYS[1], ARGV[1]) or "") ~= ARGV[2] then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+redis.call("PUBLISH", KEYS[1], ARGV[3])
+return 1`
+
+// Put implements provider.Alerts. Alerts are merged with the stored ones like
+// the in-memory provider does. The merge is retried if another replica
+// updated the alert meanwhile.
+func (a *Alerts) Put(alerts ...*types.Alert) error {
+	for _, alert := range alerts {
+		if err := a.put(alert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Alerts) put(alert *types.Alert) error {
+	fp := alert.Fingerprint().String()
+	for {
+		v, err := a.b.do("HGET", a.b.alertsKey(), fp)
+		if err != nil {
+			return err
+		}
+		prev, _ := v.([]byte)
+
+		merged := alert
+		if prev != nil {
+			old, err := decodeAlert(prev)
+			if err != nil {
+				return err
+			}
+			// Merge alerts if there is an overlap in activity range.
+			if (alert.EndsAt.After(old.StartsAt) && alert.EndsAt.Before(old.EndsAt)) ||
+				(alert.StartsAt.After(old.StartsAt) && alert.StartsAt.Before(old.EndsAt)) {
+				merged = old.Merge(alert)
+			}
+		}
+		buf, err := json.Marshal(merged)
+		if err != nil {
+			return err
+		}
+
+		// Subscribers, including the ones of this replica, are notified
+		// through the channel.
+		v, err = a.b.do("EVAL", setUnchanged, "1", a.b.alertsKey(), fp, string(prev), string(buf))
+		if err != nil {
+			return err
+		}
+		if n, _ := v.(int64); n == 1 {
+			return nil
+		}
+	}
+}
+
+// Count implements provider.Alerts.
+func (a *Alerts) Count() int {
+	v, err := a.b.do("HLEN", a.b.alertsKey())
+	if err != nil {
+		level.Warn(a.b.logger).Log("msg", "Failed to count alerts", "err", err)
+		return 0
+	}
+	n, _ := v.(int64)
+	return int(n)
+}
+
+// list returns all alerts held by the server.
+func (a *Alerts) list() ([]*types.Alert, error) {
+	var (
+		alerts []*types.Alert
+		seen   = map[model.Fingerprint]struct{}{}
+	)
+	err := a.scan(func(buf []byte) error {
+		alert, err := decodeAlert(buf)
+		if err != nil {
+			return err
+		}
+		// A scan may return an alert more than once.
+		fp := alert.Fingerprint()
+		if _, ok := seen[fp]; !ok {
+			seen[fp] = struct{}{}
+			alerts = append(alerts, alert)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// scan calls fn with the encoded alerts held by the server, requesting them
+// page by page, until fn returns an error.
+func (a *Alerts) scan(fn func([]byte) error) error {
+	cursor := "0"
+	for {
+		v, err := a.b.do("HSCAN", a.b.alertsKey(), cursor, "COUNT", fmt.Sprint(scanCount))
+		if err != nil {
+			return err
+		}
+		// The reply holds the next cursor and the fields alternating with
+		// their values.
+		arr, ok := v.([]interface{})
+		if !ok || len(arr) != 2 {
+			return fmt.Errorf("unexpected reply %v", v)
+		}
+		next, _ := arr[0].([]byte)
+		page, _ := arr[1].([]interface{})
+		for i := 1; i < len(page); i += 2 {
+			buf, _ := page[i].([]byte)
+			if err := fn(buf); err != nil {
+				return err
+			}
+		}
+		if cursor = string(next); cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+func decodeAlert(buf []byte) (*types.Alert, error) {
+	var alert types.Alert
+	if err := json.Unmarshal(buf, &alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// notify passes an alert published by any replica to the subscribers. It
+// doesn't block, the alert being queued for every subscriber.
+func (a *Alerts) notify(alert *types.Alert) {
+	a.mtx.Lock()
+	listeners := make([]*listeningAlerts, 0, len(a.listeners))
+	for _, l := range a.listeners {
+		listeners = append(listeners, l)
+	}
+	a.mtx.Unlock()
+
+	for _, l := range listeners {
+		l.push(alert)
+	}
+}
+
+// runGC periodically removes resolved alerts and closed listeners.
+func (a *Alerts) runGC(stopc <-chan struct{}, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			return
+		case <-t.C:
+			if err := a.gc(); err != nil {
+				level.Warn(a.b.logger).Log("msg", "Alert garbage collection failed", "err", err)
+			}
+		}
+	}
+}
+
+// deleteUnchanged deletes a field of a hash only if its value is unchanged,
+// so that alerts updated by another replica in the meantime are kept.
+const deleteUnchanged = `if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	return redis.call("HDEL", KEYS[1], ARGV[1])
+end
+return 0`
+
+// gc removes the alerts resolved for longer than the retention. Every
+// replica runs it, so that the markers of all replicas are cleaned up.
+func (a *Alerts) gc() error {
+	ts := time.Now().Add(-a.b.opts.Retention)
+	err := a.scan(func(buf []byte) error {
+		alert, err := decodeAlert(buf)
+		if err != nil {
+			return err
+		}
+		if !alert.ResolvedAt(ts) {
+			return nil
+		}
+		fp := alert.Fingerprint()
+		if _, err := a.b.do("EVAL", deleteUnchanged, "1", a.b.alertsKey(), fp.String(), string(buf)); err != nil {
+			return err
+		}
+		a.b.opts.Marker.Delete(fp)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The channels of closed listeners are closed by their forwarders.
+	a.mtx.Lock()
+	for i, l := range a.listeners {
+		select {
+		case <-l.done:
+			delete(a.listeners, i)
+		default:
+		}
+	}
+	a.mtx.Unlock()
+	return nil
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
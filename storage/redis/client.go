@@ -0,0 +1,231 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxIdleConns is the number of connections kept open for reuse.
+const maxIdleConns = 8
+
+// replyError is an error reply of the server. The connection remains usable
+// after receiving it.
+type replyError string
+
+func (e replyError) Error() string { return string(e) }
+
+// client is a minimal client of the Redis serialization protocol (RESP2).
+// Replies are decoded into nil, string (status replies), int64, []byte
+// (bulk strings) and []interface{} (arrays).
+type client struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+
+	mtx  sync.Mutex
+	idle []*conn
+}
+
+// newClient returns a client for a URL of the form
+// redis://[:password@]host[:port][/db].
+func newClient(rawURL string, timeout time.Duration) (*client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("unsupported scheme %q, must be redis", u.Scheme)
+	}
+	c := &client{
+		addr:    u.Host,
+		timeout: timeout,
+	}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if c.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid database %q", db)
+		}
+	}
+	return c, nil
+}
+
+// do runs the command on a pooled connection and returns its reply.
+func (c *client) do(args ...string) (interface{}, error) {
+	c.mtx.Lock()
+	var cn *conn
+	if n := len(c.idle); n > 0 {
+		cn, c.idle = c.idle[n-1], c.idle[:n-1]
+	}
+	c.mtx.Unlock()
+
+	if cn == nil {
+		var err error
+		if cn, err = c.dial(); err != nil {
+			return nil, err
+		}
+	}
+
+	v, err := cn.do(c.timeout, args...)
+	if _, ok := err.(replyError); err != nil && !ok {
+		cn.close()
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	if len(c.idle) < maxIdleConns {
+		c.idle = append(c.idle, cn)
+		cn = nil
+	}
+	c.mtx.Unlock()
+	if cn != nil {
+		cn.close()
+	}
+	return v, err
+}
+
+// dial opens a new connection, authenticates and selects the database.
+func (c *client) dial() (*conn, error) {
+	nc, err := net.DialTimeout("tcp", c.addr, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{c: nc, r: bufio.NewReader(nc)}
+	if c.password != "" {
+		if _, err := cn.do(c.timeout, "AUTH", c.password); err != nil {
+			cn.close()
+			return nil, err
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.do(c.timeout, "SELECT", strconv.Itoa(c.db)); err != nil {
+			cn.close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+// close closes all idle connections.
+func (c *client) close() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, cn := range c.idle {
+		cn.close()
+	}
+	c.idle = nil
+}
+
+type conn struct {
+	c net.Conn
+	r *bufio.Reader
+}
+
+func (cn *conn) close() {
+	cn.c.Close()
+}
+
+// do sends a command and reads its reply. A zero timeout waits forever.
+func (cn *conn) do(timeout time.Duration, args ...string) (interface{}, error) {
+	if err := cn.send(timeout, args...); err != nil {
+		return nil, err
+	}
+	return cn.receive(timeout)
+}
+
+func (cn *conn) send(timeout time.Duration, args ...string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if err := cn.c.SetDeadline(deadline(timeout)); err != nil {
+		return err
+	}
+	_, err := cn.c.Write(buf.Bytes())
+	return err
+}
+
+func (cn *conn) receive(timeout time.Duration) (interface{}, error) {
+	if err := cn.c.SetDeadline(deadline(timeout)); err != nil {
+		return nil, err
+	}
+	return readReply(cn.r)
+}
+
+func deadline(timeout time.Duration) time.Time {
+	if timeout == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(timeout)
+}
+
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("malformed reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, replyError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			if arr[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+	return nil, fmt.Errorf("malformed reply %q", line)
+}
@@ -0,0 +1,365 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redis implements a storage backend sharing the state of
+// Alertmanager replicas through Redis.
+//
+// Alerts are kept in a hash keyed by their fingerprint. State registered by
+// the replicas, e.g. silences, is written to a key per state. Updates of
+// alerts and state are published on channels which every replica subscribes
+// to, so that all replicas observe the same alerts and state.
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/alertmanager/cluster"
+	"github.com/prometheus/alertmanager/storage"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// DefaultPrefix is the default prefix of the keys and channels used.
+const DefaultPrefix = "alertmanager:"
+
+// Options configures the backend.
+type Options struct {
+	// The URL of the Redis server, e.g. redis://:password@localhost:6379/0.
+	URL string
+	// The prefix of all keys and channels. Defaults to DefaultPrefix.
+	Prefix string
+	// The timeout of requests to the server.
+	Timeout time.Duration
+	// The interval at which resolved alerts are removed.
+	GCInterval time.Duration
+	// How long resolved alerts are kept before they are removed. By
+	// default, they are removed by the next GC.
+	Retention time.Duration
+	// The interval at which the registered state is written to the server.
+	// Between writes, state is only shared through broadcasts.
+	SyncInterval time.Duration
+	// The marker of the alert statuses, whose entries are deleted for
+	// removed alerts.
+	Marker types.Marker
+
+	Logger  log.Logger
+	Metrics prometheus.Registerer
+}
+
+func (o *Options) validate() error {
+	if o.URL == "" {
+		return errors.New("URL must be set")
+	}
+	if o.GCInterval <= 0 {
+		return errors.New("GC interval must be greater than zero")
+	}
+	if o.SyncInterval <= 0 {
+		return errors.New("sync interval must be greater than zero")
+	}
+	if o.Marker == nil {
+		return errors.New("marker must be set")
+	}
+	return nil
+}
+
+type metrics struct {
+	requestFailures prometheus.Counter
+	messages        *prometheus.CounterVec
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		requestFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_redis_request_failures_total",
+			Help: "Total number of failed requests to the Redis server.",
+		}),
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alertmanager_redis_messages_received_total",
+			Help: "Total number of messages received on subscribed Redis channels.",
+		}, []string{"channel"}),
+	}
+	if r != nil {
+		r.MustRegister(m.requestFailures, m.messages)
+	}
+	return m
+}
+
+// Backend is a storage backend keeping the shared state in Redis.
+type Backend struct {
+	client  *client
+	opts    Options
+	logger  log.Logger
+	metrics *metrics
+	alerts  *Alerts
+
+	mtx    sync.RWMutex
+	states map[string]cluster.State
+
+	stopc chan struct{}
+	wg    sync.WaitGroup
+}
+
+var _ storage.Backend = &Backend{}
+
+// New returns a new backend. It fails if the server cannot be reached.
+func New(o Options) (*Backend, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if o.Prefix == "" {
+		o.Prefix = DefaultPrefix
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 5 * time.Second
+	}
+	if o.Logger == nil {
+		o.Logger = log.NewNopLogger()
+	}
+	c, err := newClient(o.URL, o.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.do("PING"); err != nil {
+		return nil, err
+	}
+
+	b := &Backend{
+		client:  c,
+		opts:    o,
+		logger:  o.Logger,
+		metrics: newMetrics(o.Metrics),
+		states:  map[string]cluster.State{},
+		stopc:   make(chan struct{}),
+	}
+	b.alerts = newAlerts(b)
+
+	// Subscribe before anything is read, so that no update is missed.
+	sub, err := b.subscribe()
+	if err != nil {
+		return nil, err
+	}
+	b.wg.Add(3)
+	go func() {
+		defer b.wg.Done()
+		b.receive(sub)
+	}()
+	go func() {
+		defer b.wg.Done()
+		b.alerts.runGC(b.stopc, o.GCInterval)
+	}()
+	go func() {
+		defer b.wg.Done()
+		b.runSync()
+	}()
+	return b, nil
+}
+
+// Alerts implements storage.Backend.
+func (b *Backend) Alerts() storage.Alerts {
+	return b.alerts
+}
+
+func (b *Backend) alertsKey() string { return b.opts.Prefix + "alerts" }
+
+func (b *Backend) stateKey(key string) string { return b.opts.Prefix + "state:" + key }
+
+// do runs a command and counts failed requests.
+func (b *Backend) do(args ...string) (interface{}, error) {
+	v, err := b.client.do(args...)
+	if err != nil {
+		b.metrics.requestFailures.Inc()
+	}
+	return v, err
+}
+
+// AddState implements storage.Backend.
+func (b *Backend) AddState(key string, s cluster.State) (cluster.ClusterChannel, error) {
+	v, err := b.do("GET", b.stateKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if buf, ok := v.([]byte); ok {
+		if err := s.Merge(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	b.mtx.Lock()
+	b.states[key] = s
+	b.mtx.Unlock()
+
+	return &channel{b: b, key: key}, nil
+}
+
+// channel broadcasts state changes by publishing them.
+type channel struct {
+	b   *Backend
+	key string
+}
+
+// Broadcast implements cluster.ClusterChannel.
+func (c *channel) Broadcast(msg []byte) {
+	if _, err := c.b.do("PUBLISH", c.b.stateKey(c.key), string(msg)); err != nil {
+		level.Warn(c.b.logger).Log("msg", "Failed to publish state", "key", c.key, "err", err)
+	}
+}
+
+// runSync periodically writes the registered state to the server, so that
+// replicas starting later are initialized with it.
+func (b *Backend) runSync() {
+	t := time.NewTicker(b.opts.SyncInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-b.stopc:
+			return
+		case <-t.C:
+			b.sync()
+		}
+	}
+}
+
+func (b *Backend) sync() {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	for key, s := range b.states {
+		buf, err := s.MarshalBinary()
+		if err != nil {
+			level.Warn(b.logger).Log("msg", "Failed to marshal state", "key", key, "err", err)
+			continue
+		}
+		if _, err := b.do("SET", b.stateKey(key), string(buf)); err != nil {
+			level.Warn(b.logger).Log("msg", "Failed to write state", "key", key, "err", err)
+		}
+	}
+}
+
+// subscribe opens a connection subscribed to all channels of the backend.
+func (b *Backend) subscribe() (*conn, error) {
+	cn, err := b.client.dial()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cn.do(b.opts.Timeout, "PSUBSCRIBE", b.opts.Prefix+"*"); err != nil {
+		cn.close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+// receive handles the messages published on the subscribed channels,
+// resubscribing after connection failures until the backend is closed.
+func (b *Backend) receive(cn *conn) {
+	go func() {
+		<-b.stopc
+		cn.close()
+	}()
+
+	for {
+		v, err := cn.receive(0)
+		if err != nil {
+			select {
+			case <-b.stopc:
+				return
+			default:
+			}
+			level.Warn(b.logger).Log("msg", "Subscription failed, resubscribing", "err", err)
+			cn.close()
+			if cn = b.resubscribe(); cn == nil {
+				return
+			}
+			go func(cn *conn) {
+				<-b.stopc
+				cn.close()
+			}(cn)
+			continue
+		}
+		// Messages are of the form [pmessage, pattern, channel, payload].
+		msg, ok := v.([]interface{})
+		if !ok || len(msg) != 4 {
+			continue
+		}
+		ch, _ := msg[2].([]byte)
+		payload, _ := msg[3].([]byte)
+		b.handle(string(ch), payload)
+	}
+}
+
+// resubscribe retries subscribing until it succeeds or the backend is
+// closed, in which case it returns nil.
+func (b *Backend) resubscribe() *conn {
+	for {
+		select {
+		case <-b.stopc:
+			return nil
+		case <-time.After(time.Second):
+		}
+		cn, err := b.subscribe()
+		if err == nil {
+			return cn
+		}
+		level.Warn(b.logger).Log("msg", "Resubscribing failed", "err", err)
+	}
+}
+
+func (b *Backend) handle(ch string, payload []byte) {
+	if ch == b.alertsKey() {
+		b.metrics.messages.WithLabelValues("alerts").Inc()
+		var a types.Alert
+		if err := json.Unmarshal(payload, &a); err != nil {
+			level.Warn(b.logger).Log("msg", "Failed to decode alert", "err", err)
+			return
+		}
+		b.alerts.notify(&a)
+		return
+	}
+
+	key := strings.TrimPrefix(ch, b.stateKey(""))
+	if key == ch {
+		return
+	}
+	b.mtx.RLock()
+	s, ok := b.states[key]
+	b.mtx.RUnlock()
+	if !ok {
+		return
+	}
+	b.metrics.messages.WithLabelValues(key).Inc()
+	if err := s.Merge(payload); err != nil {
+		level.Warn(b.logger).Log("msg", "Failed to merge state", "key", key, "err", err)
+	}
+}
+
+// Close implements storage.Backend.
+func (b *Backend) Close() error {
+	select {
+	case <-b.stopc:
+		return nil
+	default:
+	}
+	close(b.stopc)
+	b.wg.Wait()
+
+	b.sync()
+	b.client.close()
+	return nil
+}
@@ -0,0 +1,485 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/store"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// fakeServer implements the subset of Redis used by the backend.
+type fakeServer struct {
+	l net.Listener
+
+	mtx         sync.Mutex
+	strings     map[string]string
+	hashes      map[string]map[string]string
+	subscribers map[net.Conn]string
+	// beforeSet, if set, is called with the hash before an alert is set.
+	beforeSet func(map[string]string)
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeServer{
+		l:           l,
+		strings:     map[string]string{},
+		hashes:      map[string]map[string]string{},
+		subscribers: map[net.Conn]string{},
+	}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+func (s *fakeServer) url() string { return "redis://" + s.l.Addr().String() }
+
+func (s *fakeServer) serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	for {
+		v, err := readReply(r)
+		if err != nil {
+			s.mtx.Lock()
+			delete(s.subscribers, c)
+			s.mtx.Unlock()
+			return
+		}
+		var args []string
+		for _, a := range v.([]interface{}) {
+			args = append(args, string(a.([]byte)))
+		}
+		s.mtx.Lock()
+		reply := s.exec(c, args)
+		s.mtx.Unlock()
+		if _, err := c.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func bulk(s string) string { return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s) }
+
+func array(elems ...string) string {
+	return fmt.Sprintf("*%d\r\n%s", len(elems), strings.Join(elems, ""))
+}
+
+func (s *fakeServer) hash(key string) map[string]string {
+	if s.hashes[key] == nil {
+		s.hashes[key] = map[string]string{}
+	}
+	return s.hashes[key]
+}
+
+func (s *fakeServer) exec(c net.Conn, args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		v, ok := s.strings[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(v)
+	case "SET":
+		s.strings[args[1]] = args[2]
+		return "+OK\r\n"
+	case "HGET":
+		v, ok := s.hash(args[1])[args[2]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(v)
+	case "HSET":
+		s.hash(args[1])[args[2]] = args[3]
+		return ":1\r\n"
+	case "HLEN":
+		return fmt.Sprintf(":%d\r\n", len(s.hash(args[1])))
+	case "HGETALL":
+		var fields []string
+		for f := range s.hash(args[1]) {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		var elems []string
+		for _, f := range fields {
+			elems = append(elems, bulk(f), bulk(s.hash(args[1])[f]))
+		}
+		return array(elems...)
+	case "HSCAN":
+		// The cursor is the index of the next field in sorted order.
+		h := s.hash(args[1])
+		var fields []string
+		for f := range h {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		start, _ := strconv.Atoi(args[2])
+		count, _ := strconv.Atoi(args[4])
+		end, next := start+count, strconv.Itoa(start+count)
+		if end >= len(fields) {
+			end, next = len(fields), "0"
+		}
+		if start > end {
+			start = end
+		}
+		var elems []string
+		for _, f := range fields[start:end] {
+			elems = append(elems, bulk(f), bulk(h[f]))
+		}
+		return array(bulk(next), array(elems...))
+	case "EVAL":
+		// Only the scripts of the backend are supported.
+		h := s.hash(args[3])
+		switch args[1] {
+		case deleteUnchanged:
+			if h[args[4]] != args[5] {
+				return ":0\r\n"
+			}
+			delete(h, args[4])
+		case setUnchanged:
+			if s.beforeSet != nil {
+				s.beforeSet(h)
+			}
+			if h[args[4]] != args[5] {
+				return ":0\r\n"
+			}
+			h[args[4]] = args[6]
+			s.publish(args[3], args[6])
+		default:
+			return "-ERR unknown script\r\n"
+		}
+		return ":1\r\n"
+	case "PSUBSCRIBE":
+		s.subscribers[c] = strings.TrimSuffix(args[1], "*")
+		return array(bulk("psubscribe"), bulk(args[1]), ":1\r\n")
+	case "PUBLISH":
+		return fmt.Sprintf(":%d\r\n", s.publish(args[1], args[2]))
+	}
+	return "-ERR unknown command\r\n"
+}
+
+func (s *fakeServer) publish(ch, msg string) int {
+	for sc, prefix := range s.subscribers {
+		if strings.HasPrefix(ch, prefix) {
+			sc.Write([]byte(array(bulk("pmessage"), bulk(prefix+"*"), bulk(ch), bulk(msg))))
+		}
+	}
+	return len(s.subscribers)
+}
+
+func newTestBackend(t *testing.T, url string) *Backend {
+	t.Helper()
+
+	b, err := New(Options{
+		URL:          url,
+		GCInterval:   time.Hour,
+		SyncInterval: time.Hour,
+		Marker:       types.NewMarker(prometheus.NewRegistry()),
+		Metrics:      prometheus.NewRegistry(),
+	})
+	require.NoError(t, err)
+	return b
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := newClient("redis://:secret@example.com/2", time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "example.com:6379", c.addr)
+	require.Equal(t, "secret", c.password)
+	require.Equal(t, 2, c.db)
+
+	_, err = newClient("http://example.com", time.Second)
+	require.EqualError(t, err, `unsupported scheme "http", must be redis`)
+}
+
+func TestAlertsSharedBetweenReplicas(t *testing.T) {
+	srv := newFakeServer(t)
+	b1 := newTestBackend(t, srv.url())
+	defer b1.Close()
+	b2 := newTestBackend(t, srv.url())
+	defer b2.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	firing := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "firing"},
+			StartsAt: now,
+			EndsAt:   now.Add(time.Hour),
+		},
+		UpdatedAt: now,
+	}
+	resolved := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "resolved"},
+			StartsAt: now.Add(-time.Hour),
+			EndsAt:   now.Add(-time.Minute),
+		},
+		UpdatedAt: now,
+	}
+
+	it := b2.Alerts().Subscribe()
+	defer it.Close()
+
+	require.NoError(t, b1.Alerts().Put(firing, resolved))
+
+	// The subscriber of the other replica receives the alerts.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var received []*types.Alert
+	for len(received) < 2 {
+		select {
+		case a := <-it.Next():
+			received = append(received, a)
+		case <-ctx.Done():
+			t.Fatal("alerts were not received in time")
+		}
+	}
+	require.ElementsMatch(t, []*types.Alert{firing, resolved}, received)
+
+	got, err := b2.Alerts().Get(firing.Fingerprint())
+	require.NoError(t, err)
+	require.Equal(t, firing, got)
+	require.Equal(t, 2, b2.Alerts().Count())
+
+	require.NoError(t, b2.alerts.gc())
+	_, err = b1.Alerts().Get(resolved.Fingerprint())
+	require.Equal(t, store.ErrNotFound, err)
+	require.Equal(t, 1, b1.Alerts().Count())
+}
+
+func TestAlertsGCRetention(t *testing.T) {
+	srv := newFakeServer(t)
+	b := newTestBackend(t, srv.url())
+	defer b.Close()
+	b.opts.Retention = time.Hour
+
+	now := time.Now().UTC().Truncate(time.Second)
+	resolved := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "resolved"},
+			StartsAt: now.Add(-time.Hour),
+			EndsAt:   now.Add(-time.Minute),
+		},
+		UpdatedAt: now,
+	}
+	require.NoError(t, b.Alerts().Put(resolved))
+
+	// The alert is kept until it has been resolved for the retention.
+	require.NoError(t, b.alerts.gc())
+	require.Equal(t, 1, b.Alerts().Count())
+
+	b.opts.Retention = 0
+	require.NoError(t, b.alerts.gc())
+	require.Equal(t, 0, b.Alerts().Count())
+}
+
+func TestAlertsPutRetriesConcurrentUpdates(t *testing.T) {
+	srv := newFakeServer(t)
+	b := newTestBackend(t, srv.url())
+	defer b.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	stored := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "test"},
+			StartsAt: now.Add(-time.Hour),
+			EndsAt:   now.Add(time.Hour),
+		},
+		UpdatedAt: now.Add(-2 * time.Minute),
+	}
+	require.NoError(t, b.Alerts().Put(stored))
+
+	// Another replica updates the alert between the read and the write of
+	// the first attempt.
+	concurrent := *stored
+	concurrent.StartsAt = now.Add(-2 * time.Hour)
+	concurrent.UpdatedAt = now.Add(-time.Minute)
+	buf, err := json.Marshal(&concurrent)
+	require.NoError(t, err)
+	var sets int
+	srv.mtx.Lock()
+	srv.beforeSet = func(h map[string]string) {
+		if sets++; sets == 1 {
+			h[stored.Fingerprint().String()] = string(buf)
+		}
+	}
+	srv.mtx.Unlock()
+
+	update := *stored
+	update.StartsAt = now.Add(-30 * time.Minute)
+	update.EndsAt = now.Add(30 * time.Minute)
+	update.UpdatedAt = now
+	require.NoError(t, b.Alerts().Put(&update))
+
+	// The update is merged with the alert of the other replica.
+	require.Equal(t, 2, sets)
+	got, err := b.Alerts().Get(stored.Fingerprint())
+	require.NoError(t, err)
+	require.Equal(t, concurrent.StartsAt, got.StartsAt)
+	require.Equal(t, stored.EndsAt, got.EndsAt)
+}
+
+func TestAlertsSlowSubscriber(t *testing.T) {
+	srv := newFakeServer(t)
+	b := newTestBackend(t, srv.url())
+	defer b.Close()
+
+	// The first subscriber reads no alert.
+	slow := b.Alerts().Subscribe()
+	it := b.Alerts().Subscribe()
+	defer it.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	n := 2*alertChannelLength + scanCount
+	alerts := make([]*types.Alert, 0, n)
+	for i := 0; i < n; i++ {
+		alerts = append(alerts, &types.Alert{
+			Alert: model.Alert{
+				Labels:   model.LabelSet{"alertname": model.LabelValue(fmt.Sprint(i))},
+				StartsAt: now,
+				EndsAt:   now.Add(time.Hour),
+			},
+			UpdatedAt: now,
+		})
+	}
+	require.NoError(t, b.Alerts().Put(alerts...))
+
+	// The other subscriber receives all alerts nevertheless.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for received := 0; received < n; received++ {
+		select {
+		case <-it.Next():
+		case <-ctx.Done():
+			t.Fatalf("only %d alerts were received in time", received)
+		}
+	}
+
+	// All alerts are listed, across several pages.
+	listed, err := b.alerts.list()
+	require.NoError(t, err)
+	require.Len(t, listed, n)
+
+	// Closed subscribers are removed by the GC and their channel is closed.
+	slow.Close()
+	require.NoError(t, b.alerts.gc())
+	b.alerts.mtx.Lock()
+	require.Len(t, b.alerts.listeners, 1)
+	b.alerts.mtx.Unlock()
+	require.Eventually(t, func() bool {
+		for {
+			select {
+			case _, ok := <-slow.Next():
+				if !ok {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// testState is a cluster.State holding a set of strings.
+type testState struct {
+	mtx sync.Mutex
+	set map[string]struct{}
+}
+
+func newTestState() *testState { return &testState{set: map[string]struct{}{}} }
+
+func (s *testState) MarshalBinary() ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var elems []string
+	for e := range s.set {
+		elems = append(elems, e)
+	}
+	sort.Strings(elems)
+	return []byte(strings.Join(elems, ",")), nil
+}
+
+func (s *testState) Merge(b []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, e := range strings.Split(string(b), ",") {
+		if e != "" {
+			s.set[e] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (s *testState) has(e string) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	_, ok := s.set[e]
+	return ok
+}
+
+func TestStateSharedBetweenReplicas(t *testing.T) {
+	srv := newFakeServer(t)
+	b1 := newTestBackend(t, srv.url())
+	b2 := newTestBackend(t, srv.url())
+	defer b2.Close()
+
+	s1, s2 := newTestState(), newTestState()
+	c1, err := b1.AddState("sil", s1)
+	require.NoError(t, err)
+	_, err = b2.AddState("sil", s2)
+	require.NoError(t, err)
+
+	// Broadcasts are merged by the other replica.
+	require.NoError(t, s1.Merge([]byte("a")))
+	c1.Broadcast([]byte("a"))
+	require.Eventually(t, func() bool { return s2.has("a") }, 5*time.Second, 10*time.Millisecond)
+
+	// The state is written on close and initializes replicas added later.
+	require.NoError(t, s1.Merge([]byte("b")))
+	require.NoError(t, b1.Close())
+
+	b3 := newTestBackend(t, srv.url())
+	defer b3.Close()
+	s3 := newTestState()
+	_, err = b3.AddState("sil", s3)
+	require.NoError(t, err)
+	require.True(t, s3.has("a"))
+	require.True(t, s3.has("b"))
+}
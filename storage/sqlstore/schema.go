@@ -0,0 +1,167 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// dialect holds the differences between the supported databases.
+type dialect struct {
+	name string
+	// The column type of binary data.
+	blob string
+	// Whether placeholders are numbered, i.e. $1, rather than ?.
+	numbered bool
+}
+
+var (
+	sqlite   = dialect{name: "sqlite", blob: "BLOB"}
+	postgres = dialect{name: "postgres", blob: "BYTEA", numbered: true}
+)
+
+// dialectFor returns the dialect of the database accessed by the named
+// driver.
+func dialectFor(driver string) (dialect, error) {
+	switch driver {
+	case "sqlite", "sqlite3":
+		return sqlite, nil
+	case "postgres", "pgx":
+		return postgres, nil
+	}
+	return dialect{}, errors.Errorf("unsupported SQL driver %q", driver)
+}
+
+// rebind replaces the ? placeholders of the query with the ones of the
+// dialect.
+func (d dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var (
+		b strings.Builder
+		n int
+	)
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// migrations are the statements creating the schema, in order. A migration
+// is applied once and recorded in the schema_migrations table. Migrations
+// must never be changed once released; new ones are appended instead.
+var migrations = []func(d dialect) []string{
+	// 1: current silences and notification log entries, and their history.
+	func(d dialect) []string {
+		return []string{
+			fmt.Sprintf(`CREATE TABLE silences (
+	id VARCHAR(64) PRIMARY KEY,
+	matchers TEXT NOT NULL,
+	starts_at TIMESTAMP NOT NULL,
+	ends_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	created_by TEXT NOT NULL,
+	comment TEXT NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	data %s NOT NULL
+)`, d.blob),
+			`CREATE TABLE silence_history (
+	id VARCHAR(64) NOT NULL,
+	matchers TEXT NOT NULL,
+	starts_at TIMESTAMP NOT NULL,
+	ends_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	created_by TEXT NOT NULL,
+	comment TEXT NOT NULL
+)`,
+			`CREATE INDEX silence_history_id ON silence_history (id, updated_at)`,
+			fmt.Sprintf(`CREATE TABLE nflog (
+	state_key TEXT PRIMARY KEY,
+	group_key TEXT NOT NULL,
+	receiver TEXT NOT NULL,
+	integration TEXT NOT NULL,
+	idx INTEGER NOT NULL,
+	notified_at TIMESTAMP NOT NULL,
+	firing_alerts INTEGER NOT NULL,
+	resolved_alerts INTEGER NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	data %s NOT NULL
+)`, d.blob),
+			`CREATE TABLE nflog_history (
+	group_key TEXT NOT NULL,
+	receiver TEXT NOT NULL,
+	integration TEXT NOT NULL,
+	idx INTEGER NOT NULL,
+	notified_at TIMESTAMP NOT NULL,
+	firing_alerts INTEGER NOT NULL,
+	resolved_alerts INTEGER NOT NULL
+)`,
+			`CREATE INDEX nflog_history_notified_at ON nflog_history (notified_at)`,
+		}
+	},
+}
+
+// migrate applies the migrations which haven't been applied to the database
+// yet and returns the number of applied migrations.
+func migrate(db *sql.DB, d dialect) (int, error) {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	applied_at TIMESTAMP NOT NULL
+)`); err != nil {
+		return 0, errors.Wrap(err, "create migrations table")
+	}
+	var version int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, errors.Wrap(err, "read schema version")
+	}
+	if version > len(migrations) {
+		return 0, errors.Errorf("schema version %d is newer than the supported version %d", version, len(migrations))
+	}
+
+	applied := 0
+	for v := version + 1; v <= len(migrations); v++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return applied, err
+		}
+		for _, stmt := range migrations[v-1](d) {
+			if _, err := tx.Exec(stmt); err != nil {
+				tx.Rollback()
+				return applied, errors.Wrapf(err, "apply migration %d", v)
+			}
+		}
+		if _, err := tx.Exec(d.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`), v, time.Now().UTC()); err != nil {
+			tx.Rollback()
+			return applied, errors.Wrapf(err, "record migration %d", v)
+		}
+		if err := tx.Commit(); err != nil {
+			return applied, errors.Wrapf(err, "commit migration %d", v)
+		}
+		applied++
+	}
+	return applied, nil
+}
@@ -0,0 +1,335 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlstore records silences and the notification log in a SQL
+// database, which keeps a queryable, append-only history of both.
+//
+// The current silences and notification log entries are restored from the
+// database on start. Every change made by the replica is written to the
+// tables holding the current state and appended to their history tables,
+// which are never pruned.
+//
+// A pure Go SQLite driver is registered as sqlite. PostgreSQL databases can
+// be used by binaries built with a database/sql driver registered as postgres
+// or pgx. The schema is created and migrated on start.
+package sqlstore
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/proto"
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/alertmanager/cluster"
+	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+
+	// Register the SQLite driver.
+	_ "modernc.org/sqlite"
+)
+
+// Keys of the state recorded by the store, as registered with cluster peers.
+const (
+	KeySilences = "sil"
+	KeyNflog    = "nfl"
+)
+
+// Options configures the store.
+type Options struct {
+	// The name of the registered database/sql driver.
+	Driver string
+	// The data source name passed to the driver.
+	DSN string
+
+	Logger  log.Logger
+	Metrics prometheus.Registerer
+}
+
+type metrics struct {
+	writeDuration prometheus.Summary
+	writeFailures *prometheus.CounterVec
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		writeDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Name:       "alertmanager_sql_write_duration_seconds",
+			Help:       "Duration of writes of state changes to the SQL database.",
+			Objectives: map[float64]float64{},
+		}),
+		writeFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alertmanager_sql_write_failures_total",
+			Help: "Total number of failed writes of state changes to the SQL database.",
+		}, []string{"key"}),
+	}
+	if r != nil {
+		r.MustRegister(m.writeDuration, m.writeFailures)
+	}
+	return m
+}
+
+// Store records silences and the notification log in a SQL database.
+type Store struct {
+	db      *sql.DB
+	dialect dialect
+	logger  log.Logger
+	metrics *metrics
+	now     func() time.Time
+}
+
+// Open connects to the database and migrates its schema.
+func Open(o Options) (*Store, error) {
+	d, err := dialectFor(o.Driver)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(o.Driver, o.DSN)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite doesn't support concurrent writes.
+	if d.name == sqlite.name {
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "connect to database")
+	}
+	if o.Logger == nil {
+		o.Logger = log.NewNopLogger()
+	}
+	n, err := migrate(db, d)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if n > 0 {
+		level.Info(o.Logger).Log("msg", "Migrated database schema", "migrations", n)
+	}
+	return &Store{
+		db:      db,
+		dialect: d,
+		logger:  o.Logger,
+		metrics: newMetrics(o.Metrics),
+		now:     func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// table describes how the state registered under a key is recorded.
+type table struct {
+	name string
+	// record writes the changes held by a broadcast message.
+	record func(s *Store, tx *sql.Tx, msg []byte) error
+}
+
+var tables = map[string]table{
+	KeySilences: {name: "silences", record: (*Store).recordSilences},
+	KeyNflog:    {name: "nflog", record: (*Store).recordNflog},
+}
+
+// AddState restores the state registered under the given key, which must be
+// KeySilences or KeyNflog, from the database. The returned channel writes
+// the broadcast changes of the state to the database.
+func (s *Store) AddState(key string, st cluster.State) (cluster.ClusterChannel, error) {
+	t, ok := tables[key]
+	if !ok {
+		return nil, errors.Errorf("unsupported state %q", key)
+	}
+	now := s.now()
+	if _, err := s.db.Exec(s.dialect.rebind(fmt.Sprintf(`DELETE FROM %s WHERE expires_at <= ?`, t.name)), now); err != nil {
+		return nil, errors.Wrapf(err, "delete expired %s", t.name)
+	}
+	rows, err := s.db.Query(s.dialect.rebind(fmt.Sprintf(`SELECT data FROM %s WHERE expires_at > ?`, t.name)), now)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %s", t.name)
+	}
+	defer rows.Close()
+
+	// The rows hold single messages which are merged as one state.
+	var buf bytes.Buffer
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, errors.Wrapf(err, "read %s", t.name)
+		}
+		buf.Write(data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "read %s", t.name)
+	}
+	if buf.Len() > 0 {
+		if err := st.Merge(buf.Bytes()); err != nil {
+			return nil, errors.Wrapf(err, "restore %s", t.name)
+		}
+	}
+	return &channel{s: s, key: key, t: t}, nil
+}
+
+// channel writes broadcast state changes to the database.
+type channel struct {
+	s   *Store
+	key string
+	t   table
+}
+
+// Broadcast implements cluster.ClusterChannel.
+func (c *channel) Broadcast(msg []byte) {
+	if err := c.s.write(c.t, msg); err != nil {
+		c.s.metrics.writeFailures.WithLabelValues(c.key).Inc()
+		level.Error(c.s.logger).Log("msg", "Failed to write state change", "key", c.key, "err", err)
+	}
+}
+
+func (s *Store) write(t table, msg []byte) error {
+	start := time.Now()
+	defer func() { s.metrics.writeDuration.Observe(time.Since(start).Seconds()) }()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := t.record(s, tx, msg); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// readMessages decodes every length-delimited message of msg into m and calls
+// f with its encoding.
+func readMessages(msg []byte, m proto.Message, f func(data []byte) error) error {
+	r := bytes.NewReader(msg)
+	for r.Len() > 0 {
+		start := len(msg) - r.Len()
+		m.Reset()
+		if _, err := pbutil.ReadDelimited(r, m); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := f(msg[start : len(msg)-r.Len()]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) recordSilences(tx *sql.Tx, msg []byte) error {
+	var m silencepb.MeshSilence
+	return readMessages(msg, &m, func(data []byte) error {
+		sil := m.Silence
+		if sil == nil {
+			return errors.New("missing silence")
+		}
+		matchers := matchersString(sil.Matchers)
+		if _, err := tx.Exec(s.dialect.rebind(`INSERT INTO silences
+	(id, matchers, starts_at, ends_at, updated_at, created_by, comment, expires_at, data)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	matchers = excluded.matchers,
+	starts_at = excluded.starts_at,
+	ends_at = excluded.ends_at,
+	updated_at = excluded.updated_at,
+	created_by = excluded.created_by,
+	comment = excluded.comment,
+	expires_at = excluded.expires_at,
+	data = excluded.data
+WHERE silences.updated_at <= excluded.updated_at`),
+			sil.Id, matchers, sil.StartsAt.UTC(), sil.EndsAt.UTC(), sil.UpdatedAt.UTC(),
+			sil.CreatedBy, sil.Comment, m.ExpiresAt.UTC(), data,
+		); err != nil {
+			return errors.Wrap(err, "write silence")
+		}
+		if _, err := tx.Exec(s.dialect.rebind(`INSERT INTO silence_history
+	(id, matchers, starts_at, ends_at, updated_at, created_by, comment)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`),
+			sil.Id, matchers, sil.StartsAt.UTC(), sil.EndsAt.UTC(), sil.UpdatedAt.UTC(),
+			sil.CreatedBy, sil.Comment,
+		); err != nil {
+			return errors.Wrap(err, "write silence history")
+		}
+		return nil
+	})
+}
+
+func (s *Store) recordNflog(tx *sql.Tx, msg []byte) error {
+	var m nflogpb.MeshEntry
+	return readMessages(msg, &m, func(data []byte) error {
+		e := m.Entry
+		if e == nil || e.Receiver == nil {
+			return errors.New("missing notification log entry")
+		}
+		r := e.Receiver
+		key := fmt.Sprintf("%s:%s/%s/%d", e.GroupKey, r.GroupName, r.Integration, r.Idx)
+		if _, err := tx.Exec(s.dialect.rebind(`INSERT INTO nflog
+	(state_key, group_key, receiver, integration, idx, notified_at, firing_alerts, resolved_alerts, expires_at, data)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (state_key) DO UPDATE SET
+	notified_at = excluded.notified_at,
+	firing_alerts = excluded.firing_alerts,
+	resolved_alerts = excluded.resolved_alerts,
+	expires_at = excluded.expires_at,
+	data = excluded.data
+WHERE nflog.notified_at <= excluded.notified_at`),
+			key, string(e.GroupKey), r.GroupName, r.Integration, int64(r.Idx), e.Timestamp.UTC(),
+			len(e.FiringAlerts), len(e.ResolvedAlerts), m.ExpiresAt.UTC(), data,
+		); err != nil {
+			return errors.Wrap(err, "write notification log entry")
+		}
+		if _, err := tx.Exec(s.dialect.rebind(`INSERT INTO nflog_history
+	(group_key, receiver, integration, idx, notified_at, firing_alerts, resolved_alerts)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`),
+			string(e.GroupKey), r.GroupName, r.Integration, int64(r.Idx), e.Timestamp.UTC(),
+			len(e.FiringAlerts), len(e.ResolvedAlerts),
+		); err != nil {
+			return errors.Wrap(err, "write notification log history")
+		}
+		return nil
+	})
+}
+
+// matchersString formats the matchers of a silence like label matchers in
+// the API, e.g. {job="api",severity=~"warning|critical"}.
+func matchersString(ms []*silencepb.Matcher) string {
+	lms := make(labels.Matchers, 0, len(ms))
+	for _, m := range ms {
+		var t labels.MatchType
+		switch m.Type {
+		case silencepb.Matcher_EQUAL:
+			t = labels.MatchEqual
+		case silencepb.Matcher_NOT_EQUAL:
+			t = labels.MatchNotEqual
+		case silencepb.Matcher_REGEXP:
+			t = labels.MatchRegexp
+		case silencepb.Matcher_NOT_REGEXP:
+			t = labels.MatchNotRegexp
+		}
+		lms = append(lms, &labels.Matcher{Type: t, Name: m.Name, Value: m.Pattern})
+	}
+	return lms.String()
+}
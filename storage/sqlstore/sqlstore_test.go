@@ -0,0 +1,312 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/nflog"
+	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+)
+
+// fakeDB is a database/sql driver recording the executed statements. It
+// keeps the schema version and the data column of the silences table, which
+// is enough to exercise migrations and restoring silences.
+type fakeDB struct {
+	mtx      sync.Mutex
+	version  int64
+	stmts    []string
+	silences map[string][]byte
+}
+
+var (
+	fakeDBsMtx sync.Mutex
+	fakeDBs    = map[string]*fakeDB{}
+)
+
+func init() {
+	sql.Register("sqlstoretest", fakeDriver{})
+}
+
+func openFake(t *testing.T) (*sql.DB, *fakeDB) {
+	f := &fakeDB{silences: map[string][]byte{}}
+	fakeDBsMtx.Lock()
+	fakeDBs[t.Name()] = f
+	fakeDBsMtx.Unlock()
+
+	db, err := sql.Open("sqlstoretest", t.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, f
+}
+
+func (f *fakeDB) statements(prefix string) []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var res []string
+	for _, s := range f.stmts {
+		if strings.HasPrefix(s, prefix) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsMtx.Lock()
+	defer fakeDBsMtx.Unlock()
+	return &fakeConn{db: fakeDBs[name]}, nil
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mtx.Lock()
+	defer s.db.mtx.Unlock()
+	s.db.stmts = append(s.db.stmts, s.query)
+	switch {
+	case strings.HasPrefix(s.query, "INSERT INTO schema_migrations"):
+		s.db.version = args[0].(int64)
+	case strings.HasPrefix(s.query, "INSERT INTO silences"):
+		s.db.silences[args[0].(string)] = args[len(args)-1].([]byte)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mtx.Lock()
+	defer s.db.mtx.Unlock()
+	s.db.stmts = append(s.db.stmts, s.query)
+	switch {
+	case strings.HasPrefix(s.query, "SELECT COALESCE(MAX(version), 0)"):
+		return &fakeRows{cols: []string{"version"}, rows: [][]driver.Value{{s.db.version}}}, nil
+	case strings.HasPrefix(s.query, "SELECT data FROM silences"):
+		rows := &fakeRows{cols: []string{"data"}}
+		for _, data := range s.db.silences {
+			rows.rows = append(rows.rows, []driver.Value{data})
+		}
+		return rows, nil
+	}
+	return &fakeRows{cols: []string{"data"}}, nil
+}
+
+type fakeRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestDialectFor(t *testing.T) {
+	for driver, name := range map[string]string{
+		"sqlite":   "sqlite",
+		"sqlite3":  "sqlite",
+		"postgres": "postgres",
+		"pgx":      "postgres",
+	} {
+		d, err := dialectFor(driver)
+		require.NoError(t, err)
+		require.Equal(t, name, d.name)
+	}
+	_, err := dialectFor("mysql")
+	require.Error(t, err)
+}
+
+func TestRebind(t *testing.T) {
+	q := `INSERT INTO t (a, b) VALUES (?, ?)`
+	require.Equal(t, q, sqlite.rebind(q))
+	require.Equal(t, `INSERT INTO t (a, b) VALUES ($1, $2)`, postgres.rebind(q))
+}
+
+func TestMigrate(t *testing.T) {
+	db, f := openFake(t)
+
+	n, err := migrate(db, postgres)
+	require.NoError(t, err)
+	require.Equal(t, len(migrations), n)
+	require.Equal(t, int64(len(migrations)), f.version)
+	require.Len(t, f.statements("CREATE TABLE silences"), 1)
+	require.Contains(t, f.statements("CREATE TABLE silences")[0], "BYTEA")
+
+	// Migrations are only applied once.
+	n, err = migrate(db, postgres)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	require.Len(t, f.statements("CREATE TABLE silences"), 1)
+
+	// A schema newer than the supported one is refused.
+	f.version = int64(len(migrations) + 1)
+	_, err = migrate(db, postgres)
+	require.Error(t, err)
+}
+
+func TestAddStateUnsupported(t *testing.T) {
+	db, _ := openFake(t)
+	s := &Store{db: db, dialect: sqlite, logger: log.NewNopLogger(), metrics: newMetrics(nil), now: time.Now}
+
+	_, err := s.AddState("ack", nil)
+	require.Error(t, err)
+}
+
+func TestSilencesRecordedAndRestored(t *testing.T) {
+	db, f := openFake(t)
+	s := &Store{db: db, dialect: sqlite, logger: log.NewNopLogger(), metrics: newMetrics(nil), now: time.Now}
+
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+	c, err := s.AddState(KeySilences, sils)
+	require.NoError(t, err)
+	sils.SetBroadcast(c.Broadcast)
+
+	now := time.Now()
+	id, err := sils.Set(&silencepb.Silence{
+		Matchers:  []*silencepb.Matcher{{Type: silencepb.Matcher_REGEXP, Name: "job", Pattern: "api|db"}},
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		CreatedBy: "me",
+		Comment:   "maintenance",
+	})
+	require.NoError(t, err)
+	require.Len(t, f.statements("INSERT INTO silences"), 1)
+	require.Len(t, f.statements("INSERT INTO silence_history"), 1)
+
+	// A new instance is initialized with the recorded silence.
+	restored, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+	_, err = s.AddState(KeySilences, restored)
+	require.NoError(t, err)
+
+	res, _, err := restored.Query(silence.QIDs(id))
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, "maintenance", res[0].Comment)
+}
+
+func TestSQLite(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "history.db")
+	s, err := Open(Options{Driver: "sqlite", DSN: dsn})
+	require.NoError(t, err)
+
+	sils, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+	c, err := s.AddState(KeySilences, sils)
+	require.NoError(t, err)
+	sils.SetBroadcast(c.Broadcast)
+
+	nfl, err := nflog.New(nflog.WithRetention(time.Hour))
+	require.NoError(t, err)
+	c, err = s.AddState(KeyNflog, nfl)
+	require.NoError(t, err)
+	nfl.SetBroadcast(c.Broadcast)
+
+	now := time.Now()
+	sil := &silencepb.Silence{
+		Matchers:  []*silencepb.Matcher{{Type: silencepb.Matcher_EQUAL, Name: "job", Pattern: "api"}},
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		CreatedBy: "me",
+		Comment:   "maintenance",
+	}
+	id, err := sils.Set(sil)
+	require.NoError(t, err)
+	sil.Id = id
+	sil.Comment = "extended maintenance"
+	sil.EndsAt = now.Add(2 * time.Hour)
+	_, err = sils.Set(sil)
+	require.NoError(t, err)
+
+	recv := &nflogpb.Receiver{GroupName: "team", Integration: "webhook", Idx: 0}
+	require.NoError(t, nfl.Log(recv, "{}:{job=\"api\"}", []uint64{1, 2}, nil))
+
+	// Every change is appended to the history.
+	var n int
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM silence_history WHERE id = ?`, id).Scan(&n))
+	require.Equal(t, 2, n)
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM nflog_history WHERE receiver = 'team' AND firing_alerts = 2`).Scan(&n))
+	require.Equal(t, 1, n)
+	require.NoError(t, s.Close())
+
+	// The database is migrated once and restores the current state.
+	s, err = Open(Options{Driver: "sqlite", DSN: dsn})
+	require.NoError(t, err)
+	defer s.Close()
+
+	restored, err := silence.New(silence.Options{Retention: time.Hour})
+	require.NoError(t, err)
+	_, err = s.AddState(KeySilences, restored)
+	require.NoError(t, err)
+	res, _, err := restored.Query(silence.QIDs(id))
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, "extended maintenance", res[0].Comment)
+
+	restoredLog, err := nflog.New(nflog.WithRetention(time.Hour))
+	require.NoError(t, err)
+	_, err = s.AddState(KeyNflog, restoredLog)
+	require.NoError(t, err)
+	entries, err := restoredLog.Query(nflog.QReceiver(recv), nflog.QGroupKey("{}:{job=\"api\"}"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, []uint64{1, 2}, entries[0].FiringAlerts)
+}
+
+func TestMatchersString(t *testing.T) {
+	require.Equal(t, `{job="api",env!~"dev|test"}`, matchersString([]*silencepb.Matcher{
+		{Type: silencepb.Matcher_EQUAL, Name: "job", Pattern: "api"},
+		{Type: silencepb.Matcher_NOT_REGEXP, Name: "env", Pattern: "dev|test"},
+	}))
+}
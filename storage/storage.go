@@ -0,0 +1,42 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage defines backends holding the state shared by the replicas
+// of the Alertmanager. Replicas using the same backend share their alerts,
+// silences, notification log and acknowledgements without gossiping.
+package storage
+
+import (
+	"github.com/prometheus/alertmanager/cluster"
+	"github.com/prometheus/alertmanager/provider"
+)
+
+// Alerts is a provider of the alerts shared by all replicas.
+type Alerts interface {
+	provider.Alerts
+	// Close releases the resources of the provider.
+	Close()
+}
+
+// Backend holds the state shared by the replicas of the Alertmanager.
+type Backend interface {
+	// Alerts returns the provider of the shared alerts.
+	Alerts() Alerts
+	// AddState registers state under the given key, like a cluster peer
+	// does. The state is initialized from the backend and changes broadcast
+	// by any replica are merged into it.
+	AddState(key string, s cluster.State) (cluster.ClusterChannel, error)
+	// Close writes the registered state to the backend and releases its
+	// resources.
+	Close() error
+}
@@ -30,12 +30,14 @@ var (
 
 // Alerts provides lock-coordinated to an in-memory map of alerts, keyed by
 // their fingerprint. Resolved alerts are removed from the map based on
-// gcInterval. An optional callback can be set which receives a slice of all
-// resolved alerts that have been removed.
+// gcInterval, once they have been resolved for longer than the retention.
+// An optional callback can be set which receives a slice of all resolved
+// alerts that have been removed.
 type Alerts struct {
 	sync.Mutex
-	c  map[model.Fingerprint]*types.Alert
-	cb func([]*types.Alert)
+	c         map[model.Fingerprint]*types.Alert
+	cb        func([]*types.Alert)
+	retention time.Duration
 }
 
 // NewAlerts returns a new Alerts struct.
@@ -56,6 +58,15 @@ func (a *Alerts) SetGCCallback(cb func([]*types.Alert)) {
 	a.cb = cb
 }
 
+// SetRetention sets how long resolved alerts are kept before they are
+// garbage collected. By default, they are removed by the next GC.
+func (a *Alerts) SetRetention(d time.Duration) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.retention = d
+}
+
 // Run starts the GC loop. The interval must be greater than zero; if not, the function will panic.
 func (a *Alerts) Run(ctx context.Context, interval time.Duration) {
 	t := time.NewTicker(interval)
@@ -65,18 +76,22 @@ func (a *Alerts) Run(ctx context.Context, interval time.Duration) {
 		case <-ctx.Done():
 			return
 		case <-t.C:
-			a.gc()
+			a.GC()
 		}
 	}
 }
 
-func (a *Alerts) gc() {
+// GC removes resolved alerts from memory and passes them to the GC callback.
+func (a *Alerts) GC() {
 	a.Lock()
 	defer a.Unlock()
 
-	var resolved []*types.Alert
+	var (
+		resolved []*types.Alert
+		ts       = time.Now().Add(-a.retention)
+	)
 	for fp, alert := range a.c {
-		if alert.Resolved() {
+		if alert.ResolvedAt(ts) {
 			delete(a.c, fp)
 			resolved = append(resolved, alert)
 		}
@@ -128,6 +143,14 @@ func (a *Alerts) List() []*types.Alert {
 	return alerts
 }
 
+// Len returns the number of alerts currently held in memory.
+func (a *Alerts) Len() int {
+	a.Lock()
+	defer a.Unlock()
+
+	return len(a.c)
+}
+
 // Empty returns true if the store is empty.
 func (a *Alerts) Empty() bool {
 	a.Lock()
@@ -110,3 +110,34 @@ func TestGC(t *testing.T) {
 	}
 	require.Equal(t, len(resolved), n)
 }
+
+func TestGCRetention(t *testing.T) {
+	now := time.Now()
+	newAlert := func(key string, end time.Duration) *types.Alert {
+		return &types.Alert{
+			Alert: model.Alert{
+				Labels:   model.LabelSet{model.LabelName(key): "b"},
+				StartsAt: now.Add(-time.Hour),
+				EndsAt:   now.Add(end),
+			},
+		}
+	}
+	recent := newAlert("a", -time.Minute)
+	old := newAlert("b", -20*time.Minute)
+
+	s := NewAlerts()
+	s.SetRetention(10 * time.Minute)
+	var removed []*types.Alert
+	s.SetGCCallback(func(a []*types.Alert) {
+		removed = append(removed, a...)
+	})
+	require.NoError(t, s.Set(recent))
+	require.NoError(t, s.Set(old))
+
+	s.GC()
+
+	require.Equal(t, []*types.Alert{old}, removed)
+	_, err := s.Get(recent.Fingerprint())
+	require.NoError(t, err)
+	require.Equal(t, 1, s.Len())
+}
@@ -237,7 +237,84 @@ type Data struct {
 	CommonLabels      KV `json:"commonLabels"`
 	CommonAnnotations KV `json:"commonAnnotations"`
 
+	// GroupKey identifies the alert group and GroupID is its hash. See
+	// notify.Key for their format.
+	GroupKey string `json:"groupKey,omitempty"`
+	GroupID  string `json:"groupID,omitempty"`
+
 	ExternalURL string `json:"externalURL"`
+	// AckURL links to a page to acknowledge the alert group.
+	AckURL string `json:"ackURL,omitempty"`
+
+	// Part and Parts are set if the alerts of the group were split into
+	// several messages. Part starts at 1.
+	Part  int `json:"part,omitempty"`
+	Parts int `json:"parts,omitempty"`
+
+	// Digest is true if the alerts are notified in a scheduled digest.
+	Digest bool `json:"digest,omitempty"`
+
+	// Owner is the owner of the route of the alerts, if any.
+	Owner *Owner `json:"owner,omitempty"`
+
+	location   *time.Location
+	timeFormat string
+}
+
+// DefaultTimeFormat is the layout of the times formatted by FormatTime if the
+// receiver has no time format.
+const DefaultTimeFormat = "2006-01-02 15:04:05 MST"
+
+// Localize converts the start and end times of the alerts to the location,
+// and sets the location and layout used by FormatTime. The layout defaults
+// to DefaultTimeFormat if empty.
+func (d *Data) Localize(loc *time.Location, layout string) {
+	if loc != nil {
+		for i := range d.Alerts {
+			d.Alerts[i].StartsAt = d.Alerts[i].StartsAt.In(loc)
+			d.Alerts[i].EndsAt = d.Alerts[i].EndsAt.In(loc)
+		}
+	}
+	d.location, d.timeFormat = loc, layout
+}
+
+// FormatTime formats the time in the location and with the layout of the
+// receiver, e.g. '{{ $.FormatTime .StartsAt }}'.
+func (d *Data) FormatTime(t time.Time) string {
+	if d.location != nil {
+		t = t.In(d.location)
+	}
+	if d.timeFormat == "" {
+		return t.Format(DefaultTimeFormat)
+	}
+	return t.Format(d.timeFormat)
+}
+
+// Owner is the owner of the route of the notified alerts.
+type Owner struct {
+	Name string `json:"name,omitempty"`
+	// OnCall are the people on call in the schedule of the owner when the
+	// notification was sent. It is empty if the schedule couldn't be
+	// resolved.
+	OnCall []OnCall `json:"onCall,omitempty"`
+}
+
+// OnCall is a person on call.
+type OnCall struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// AckURL returns the URL of the page to acknowledge the alert group with the
+// given key.
+func (t *Template) AckURL(groupKey string) string {
+	if t.ExternalURL == nil {
+		return ""
+	}
+	u := *t.ExternalURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/ack"
+	u.RawQuery = url.Values{"groupKey": {groupKey}}.Encode()
+	return u.String()
 }
 
 // Alert holds one alert for notification templates.
@@ -276,6 +276,61 @@ func TestData(t *testing.T) {
 	}
 }
 
+func TestAckURL(t *testing.T) {
+	u, err := url.Parse("http://example.com/alertmanager/")
+	require.NoError(t, err)
+	tmpl := &Template{ExternalURL: u}
+
+	require.Equal(t,
+		"http://example.com/alertmanager/ack?groupKey=%7B%7D%3A%7Balertname%3D%22test%22%7D",
+		tmpl.AckURL(`{}:{alertname="test"}`),
+	)
+	require.Equal(t, "", (&Template{}).AckURL("{}:{}"))
+}
+
+func TestDigestExampleTemplate(t *testing.T) {
+	tmpl, err := FromGlobs("../doc/examples/digest.tmpl")
+	require.NoError(t, err)
+	tmpl.ExternalURL, _ = url.Parse("http://am.example.com")
+
+	startsAt := time.Date(2021, 8, 24, 9, 0, 0, 0, time.UTC)
+	data := tmpl.Data("team-X", model.LabelSet{"team": "X"},
+		&types.Alert{Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "DiskFull", "team": "X"},
+			Annotations: model.LabelSet{"summary": "Disk is almost full"},
+			StartsAt:    startsAt,
+		}},
+		&types.Alert{Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "HighLatency", "team": "X"},
+			StartsAt: startsAt,
+			EndsAt:   startsAt.Add(time.Hour),
+		}},
+	)
+	data.Digest = true
+
+	subject, err := tmpl.ExecuteTextString(`{{ template "digest.subject" . }}`, data)
+	require.NoError(t, err)
+	require.Equal(t, "[Digest] 1 firing, 1 resolved alerts for X", subject)
+
+	text, err := tmpl.ExecuteTextString(`{{ template "digest.text" . }}`, data)
+	require.NoError(t, err)
+	require.Equal(t, `Firing:
+- DiskFull: Disk is almost full (since 2021-08-24 09:00 UTC)
+  alertname=DiskFull team=X
+
+Resolved since the previous digest:
+- HighLatency (since 2021-08-24 09:00 UTC)
+  alertname=HighLatency team=X
+
+Sent by http://am.example.com
+`, text)
+
+	html, err := tmpl.ExecuteHTMLString(`{{ template "digest.html" . }}`, data)
+	require.NoError(t, err)
+	require.Contains(t, html, "<td>DiskFull</td><td>Disk is almost full</td>")
+	require.Contains(t, html, "<h2>Resolved since the previous digest</h2>")
+}
+
 func TestTemplateExpansion(t *testing.T) {
 	tmpl, err := FromGlobs()
 	require.NoError(t, err)
@@ -0,0 +1,349 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+const (
+	// maxBatchSize is the number of spans exported at once.
+	maxBatchSize = 512
+	// queueSize is the number of ended spans waiting to be exported, beyond
+	// which spans are dropped.
+	queueSize = 2048
+	// exportInterval is the longest time spans wait before being exported.
+	exportInterval = 5 * time.Second
+
+	scopeName = "github.com/prometheus/alertmanager"
+)
+
+// exporter sends batches of spans to the traces endpoint of an OpenTelemetry
+// protocol receiver, encoded as JSON.
+type exporter struct {
+	client  *http.Client
+	url     string
+	timeout time.Duration
+	logger  log.Logger
+
+	spans   chan *Span
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func newExporter(client *http.Client, endpoint string, timeout time.Duration, l log.Logger) *exporter {
+	e := &exporter{
+		client:  client,
+		url:     strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		timeout: timeout,
+		logger:  l,
+		spans:   make(chan *Span, queueSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+func (e *exporter) enqueue(s *Span) {
+	select {
+	case <-e.done:
+		// The spans ending after the exporter was replaced are dropped.
+		return
+	default:
+	}
+	select {
+	case e.spans <- s:
+	default:
+		level.Debug(e.logger).Log("msg", "Export queue full, dropping span", "span", s.name)
+	}
+}
+
+func (e *exporter) run() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, maxBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.export(batch); err != nil {
+			level.Warn(e.logger).Log("msg", "Failed to export spans", "spans", len(batch), "err", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case s := <-e.spans:
+			batch = append(batch, s)
+			if len(batch) == maxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.done:
+			// Export what was queued before stopping.
+			for {
+				select {
+				case s := <-e.spans:
+					batch = append(batch, s)
+					if len(batch) == maxBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// stop exports the queued spans and stops the exporter.
+func (e *exporter) stop() {
+	close(e.done)
+	<-e.stopped
+}
+
+func (e *exporter) export(spans []*Span) error {
+	b, err := json.Marshal(encodeSpans(spans))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+	return nil
+}
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanData struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              SpanKind   `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// The status codes of spans.
+const (
+	statusUnset = 0
+	statusError = 2
+)
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func encodeSpans(spans []*Span) exportRequest {
+	data := make([]spanData, 0, len(spans))
+	for _, s := range spans {
+		s.mtx.Lock()
+		d := spanData{
+			TraceID:           hex.EncodeToString(s.sc.traceID[:]),
+			SpanID:            hex.EncodeToString(s.sc.spanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Status:            status{Code: statusUnset},
+		}
+		if s.parentID != [8]byte{} {
+			d.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		for _, a := range s.attrs {
+			d.Attributes = append(d.Attributes, keyValue{Key: a.key, Value: encodeValue(a.value)})
+		}
+		if s.err != nil {
+			d.Status = status{Code: statusError, Message: s.err.Error()}
+		}
+		s.mtx.Unlock()
+		data = append(data, d)
+	}
+
+	service := "alertmanager"
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: []keyValue{{Key: "service.name", Value: anyValue{StringValue: &service}}},
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: scopeName},
+				Spans: data,
+			}},
+		}},
+	}
+}
+
+func encodeValue(v interface{}) anyValue {
+	var i int64
+	switch v := v.(type) {
+	case string:
+		return anyValue{StringValue: &v}
+	case bool:
+		return anyValue{BoolValue: &v}
+	case float64:
+		return anyValue{DoubleValue: &v}
+	case float32:
+		f := float64(v)
+		return anyValue{DoubleValue: &f}
+	case int:
+		i = int64(v)
+	case int32:
+		i = int64(v)
+	case int64:
+		i = v
+	case uint32:
+		i = int64(v)
+	default:
+		s := fmt.Sprint(v)
+		return anyValue{StringValue: &s}
+	}
+	s := strconv.FormatInt(i, 10)
+	return anyValue{IntValue: &s}
+}
+
+// Manager starts and stops the export of spans according to the
+// configuration.
+type Manager struct {
+	mtx    sync.Mutex
+	conf   *config.TracingConfig
+	exp    *exporter
+	logger log.Logger
+}
+
+// NewManager returns a manager which doesn't export spans until a
+// configuration enabling tracing is applied.
+func NewManager(l log.Logger) *Manager {
+	return &Manager{logger: l}
+}
+
+// Update applies the tracing configuration. The exporter is replaced only
+// if the configuration changed, the spans queued by the previous one being
+// exported first.
+func (m *Manager) Update(c *config.Config) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if reflect.DeepEqual(c.Tracing, m.conf) {
+		return nil
+	}
+
+	var exp *exporter
+	if c.Tracing != nil {
+		client, err := commoncfg.NewClientFromConfig(*c.Tracing.HTTPConfig, "tracing")
+		if err != nil {
+			return err
+		}
+		exp = newExporter(client, c.Tracing.Endpoint.String(), time.Duration(c.Tracing.Timeout), m.logger)
+		setExporter(exp, c.Tracing.SamplingFraction)
+	} else {
+		setExporter(nil, 0)
+	}
+
+	if m.exp != nil {
+		m.exp.stop()
+	}
+	m.conf, m.exp = c.Tracing, exp
+	return nil
+}
+
+// Stop exports the queued spans and disables tracing.
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	setExporter(nil, 0)
+	if m.exp != nil {
+		m.exp.stop()
+	}
+	m.conf, m.exp = nil, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// TestEncodeSpansSchema checks that the exported spans are valid OTLP/JSON by
+// decoding them into the messages of the OpenTelemetry protocol.
+func TestEncodeSpansSchema(t *testing.T) {
+	e := &exporter{spans: make(chan *Span, 10), done: make(chan struct{})}
+	setExporter(e, 1)
+	defer setExporter(nil, 0)
+
+	ctx, parent := Start(context.Background(), "parent", SpanKindServer, "ok", true)
+	_, child := Start(ctx, "child", SpanKindClient, "count", 2, "ratio", 0.5, "name", "foo", "labels", []string{"a"})
+	child.RecordError(errors.New("failed"))
+	child.End()
+	parent.End()
+
+	b, err := json.Marshal(encodeSpans([]*Span{parent, child}))
+	require.NoError(t, err)
+
+	// Unlike the canonical JSON encoding of protocol buffers, OTLP/JSON
+	// encodes the trace and span IDs in hex rather than in base64.
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &doc))
+	for _, rs := range doc["resourceSpans"].([]interface{}) {
+		for _, ss := range rs.(map[string]interface{})["scopeSpans"].([]interface{}) {
+			for _, s := range ss.(map[string]interface{})["spans"].([]interface{}) {
+				s := s.(map[string]interface{})
+				for _, k := range []string{"traceId", "spanId", "parentSpanId"} {
+					if id, ok := s[k].(string); ok {
+						raw, err := hex.DecodeString(id)
+						require.NoError(t, err)
+						s[k] = base64.StdEncoding.EncodeToString(raw)
+					}
+				}
+			}
+		}
+	}
+	b, err = json.Marshal(doc)
+	require.NoError(t, err)
+
+	// Unknown fields and values of the wrong type are rejected.
+	var data tracepb.TracesData
+	require.NoError(t, protojson.Unmarshal(b, &data))
+
+	require.Len(t, data.ResourceSpans, 1)
+	attrs := data.ResourceSpans[0].Resource.Attributes
+	require.Equal(t, "service.name", attrs[0].Key)
+	require.Equal(t, "alertmanager", attrs[0].Value.GetStringValue())
+	require.Len(t, data.ResourceSpans[0].ScopeSpans, 1)
+	require.Equal(t, scopeName, data.ResourceSpans[0].ScopeSpans[0].Scope.Name)
+
+	spans := data.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+	p, c := spans[0], spans[1]
+	require.Equal(t, parent.sc.traceID[:], p.TraceId)
+	require.Equal(t, parent.sc.spanID[:], p.SpanId)
+	require.Empty(t, p.ParentSpanId)
+	require.Equal(t, tracepb.Span_SPAN_KIND_SERVER, p.Kind)
+	require.Equal(t, uint64(parent.start.UnixNano()), p.StartTimeUnixNano)
+	require.Equal(t, uint64(parent.end.UnixNano()), p.EndTimeUnixNano)
+	require.True(t, p.Attributes[0].Value.GetBoolValue())
+	require.Equal(t, tracepb.Status_STATUS_CODE_UNSET, p.Status.Code)
+
+	require.Equal(t, parent.sc.traceID[:], c.TraceId)
+	require.Equal(t, parent.sc.spanID[:], c.ParentSpanId)
+	require.Equal(t, tracepb.Span_SPAN_KIND_CLIENT, c.Kind)
+	require.Equal(t, int64(2), c.Attributes[0].Value.GetIntValue())
+	require.Equal(t, 0.5, c.Attributes[1].Value.GetDoubleValue())
+	require.Equal(t, "foo", c.Attributes[2].Value.GetStringValue())
+	require.Equal(t, "[a]", c.Attributes[3].Value.GetStringValue())
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, c.Status.Code)
+	require.Equal(t, "failed", c.Status.Message)
+}
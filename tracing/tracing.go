@@ -0,0 +1,252 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing records spans of the handling of alerts and exports them
+// with the OpenTelemetry protocol. Trace contexts are propagated with the W3C
+// traceparent header.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpanKind describes the relationship of a span with its parent and
+// children.
+type SpanKind int
+
+// The kinds of span, numbered as in the OpenTelemetry protocol.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+const traceparentHeader = "traceparent"
+
+// spanContext identifies a span within a trace.
+type spanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+	sampled bool
+}
+
+func (sc spanContext) valid() bool {
+	return sc.traceID != [16]byte{} && sc.spanID != [8]byte{}
+}
+
+type spanContextKey struct{}
+
+func fromContext(ctx context.Context) (spanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(spanContext)
+	return sc, ok && sc.valid()
+}
+
+// WithSpanOf returns a copy of ctx whose spans are children of the span of
+// from, or root spans if from has none.
+func WithSpanOf(ctx, from context.Context) context.Context {
+	sc, _ := fromContext(from)
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// Span is an operation of a trace. A nil span is valid and records nothing,
+// which is what Start returns for traces which aren't sampled.
+type Span struct {
+	mtx sync.Mutex
+
+	sc       spanContext
+	parentID [8]byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+	end      time.Time
+	attrs    []attribute
+	err      error
+	ended    bool
+	exporter *exporter
+}
+
+type attribute struct {
+	key   string
+	value interface{}
+}
+
+var (
+	mtx              sync.RWMutex
+	globalExporter   *exporter
+	samplingFraction float64
+)
+
+// setExporter changes the exporter of the spans which are started
+// afterwards. A nil exporter disables tracing.
+func setExporter(e *exporter, fraction float64) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	globalExporter, samplingFraction = e, fraction
+}
+
+func currentExporter() (*exporter, float64) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	return globalExporter, samplingFraction
+}
+
+// Start starts a span as a child of the span of the context, if any, and
+// returns a context holding the new span. The key-value pairs are set as
+// attributes of the span.
+func Start(ctx context.Context, name string, kind SpanKind, kv ...interface{}) (context.Context, *Span) {
+	e, fraction := currentExporter()
+	if e == nil {
+		return ctx, nil
+	}
+
+	parent, hasParent := fromContext(ctx)
+	sc := spanContext{spanID: newSpanID()}
+	if hasParent {
+		sc.traceID, sc.sampled = parent.traceID, parent.sampled
+	} else {
+		sc.traceID = newTraceID()
+		// The trace ID is random, its lower bits decide the sampling.
+		sc.sampled = float64(binary.BigEndian.Uint64(sc.traceID[8:])>>11)/(1<<53) < fraction
+	}
+	ctx = context.WithValue(ctx, spanContextKey{}, sc)
+	if !sc.sampled {
+		return ctx, nil
+	}
+
+	s := &Span{
+		sc:       sc,
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+		exporter: e,
+	}
+	if hasParent {
+		s.parentID = parent.spanID
+	}
+	s.SetAttributes(kv...)
+	return ctx, s
+}
+
+// SetAttributes sets the key-value pairs as attributes of the span. Values
+// other than strings, booleans, integers and floats are formatted as
+// strings.
+func (s *Span) SetAttributes(kv ...interface{}) {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		s.attrs = append(s.attrs, attribute{key: fmt.Sprint(kv[i]), value: kv[i+1]})
+	}
+}
+
+// RecordError marks the span as failed with the error. Nil errors are
+// ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.err = err
+}
+
+// End ends the span and queues it for export. Only the first call has an
+// effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	if s.ended {
+		s.mtx.Unlock()
+		return
+	}
+	s.ended, s.end = true, time.Now()
+	s.mtx.Unlock()
+
+	s.exporter.enqueue(s)
+}
+
+// Inject sets the traceparent header identifying the span of the context, if
+// any.
+func Inject(ctx context.Context, h http.Header) {
+	sc, ok := fromContext(ctx)
+	if !ok {
+		return
+	}
+	var flags byte
+	if sc.sampled {
+		flags = 1
+	}
+	h.Set(traceparentHeader, fmt.Sprintf("00-%x-%x-%02x", sc.traceID, sc.spanID, flags))
+}
+
+// Extract returns a context whose spans are children of the span identified
+// by the traceparent header. The context is returned unchanged if the header
+// is missing or invalid.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	parts := strings.Split(strings.TrimSpace(h.Get(traceparentHeader)), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return ctx
+	}
+	// Version 00 has exactly four fields, later versions may add more.
+	if parts[0] == "00" && len(parts) != 4 {
+		return ctx
+	}
+
+	var (
+		sc    spanContext
+		flags [1]byte
+	)
+	if !decodeHex(sc.traceID[:], parts[1]) || !decodeHex(sc.spanID[:], parts[2]) || !decodeHex(flags[:], parts[3]) {
+		return ctx
+	}
+	if !sc.valid() {
+		return ctx
+	}
+	sc.sampled = flags[0]&1 == 1
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != 2*len(dst) || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+func newTraceID() (id [16]byte) {
+	for id == [16]byte{} {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() (id [8]byte) {
+	for id == [8]byte{} {
+		rand.Read(id[:])
+	}
+	return id
+}
@@ -0,0 +1,160 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+func TestStartWithoutExporter(t *testing.T) {
+	ctx, span := Start(context.Background(), "test", SpanKindInternal)
+	require.Nil(t, span)
+	// A nil span records nothing.
+	span.SetAttributes("key", "value")
+	span.RecordError(errors.New("error"))
+	span.End()
+
+	h := http.Header{}
+	Inject(ctx, h)
+	require.Empty(t, h.Get(traceparentHeader))
+}
+
+func TestPropagation(t *testing.T) {
+	e := &exporter{spans: make(chan *Span, 10), done: make(chan struct{})}
+	setExporter(e, 1)
+	defer setExporter(nil, 0)
+
+	h := http.Header{}
+	h.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, span := Start(Extract(context.Background(), h), "server", SpanKindServer)
+	require.NotNil(t, span)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", encodeSpans([]*Span{span}).ResourceSpans[0].ScopeSpans[0].Spans[0].TraceID)
+	require.Equal(t, "00f067aa0ba902b7", encodeSpans([]*Span{span}).ResourceSpans[0].ScopeSpans[0].Spans[0].ParentSpanID)
+
+	_, child := Start(ctx, "child", SpanKindInternal)
+	require.Equal(t, span.sc.traceID, child.sc.traceID)
+	require.Equal(t, span.sc.spanID, child.parentID)
+
+	out := http.Header{}
+	Inject(ctx, out)
+	require.Regexp(t, "^00-4bf92f3577b34da6a3ce929d0e0e4736-[0-9a-f]{16}-01$", out.Get(traceparentHeader))
+
+	// Traces which the caller didn't sample aren't recorded but are still
+	// propagated.
+	h.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	ctx, span = Start(Extract(context.Background(), h), "server", SpanKindServer)
+	require.Nil(t, span)
+	Inject(ctx, out)
+	require.Regexp(t, "-00$", out.Get(traceparentHeader))
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		h.Set(traceparentHeader, invalid)
+		ctx := Extract(context.Background(), h)
+		_, ok := fromContext(ctx)
+		require.False(t, ok, invalid)
+	}
+}
+
+func TestWithSpanOf(t *testing.T) {
+	e := &exporter{spans: make(chan *Span, 10), done: make(chan struct{})}
+	setExporter(e, 1)
+	defer setExporter(nil, 0)
+
+	ctx, parent := Start(context.Background(), "parent", SpanKindInternal)
+	sctx, _ := Start(ctx, "child", SpanKindInternal)
+	_, sibling := Start(WithSpanOf(sctx, ctx), "sibling", SpanKindInternal)
+	require.Equal(t, parent.sc.spanID, sibling.parentID)
+
+	_, root := Start(WithSpanOf(sctx, context.Background()), "root", SpanKindInternal)
+	require.Equal(t, [8]byte{}, root.parentID)
+	require.NotEqual(t, parent.sc.traceID, root.sc.traceID)
+}
+
+func TestSampling(t *testing.T) {
+	e := &exporter{spans: make(chan *Span, 10), done: make(chan struct{})}
+	setExporter(e, 0)
+	defer setExporter(nil, 0)
+
+	ctx, span := Start(context.Background(), "root", SpanKindInternal)
+	require.Nil(t, span)
+	// The children of unsampled spans aren't sampled either.
+	setExporter(e, 1)
+	_, span = Start(ctx, "child", SpanKindInternal)
+	require.Nil(t, span)
+}
+
+func TestManager(t *testing.T) {
+	received := make(chan exportRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/otlp/v1/traces", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var req exportRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		received <- req
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL + "/otlp")
+	require.NoError(t, err)
+	m := NewManager(log.NewNopLogger())
+	require.NoError(t, m.Update(&config.Config{
+		Tracing: &config.TracingConfig{
+			Endpoint:         &config.URL{URL: u},
+			SamplingFraction: 1,
+			Timeout:          model.Duration(time.Second),
+			HTTPConfig:       &commoncfg.HTTPClientConfig{},
+		},
+	}))
+
+	_, span := Start(context.Background(), "test", SpanKindInternal, "count", 2, "ok", true, "name", "foo")
+	span.RecordError(errors.New("failed"))
+	span.End()
+	// Stopping exports the queued spans.
+	m.Stop()
+
+	req := <-received
+	require.Equal(t, "service.name", req.ResourceSpans[0].Resource.Attributes[0].Key)
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	require.Equal(t, "test", spans[0].Name)
+	require.Equal(t, SpanKindInternal, spans[0].Kind)
+	require.Equal(t, status{Code: statusError, Message: "failed"}, spans[0].Status)
+	require.Equal(t, "2", *spans[0].Attributes[0].Value.IntValue)
+	require.True(t, *spans[0].Attributes[1].Value.BoolValue)
+	require.Equal(t, "foo", *spans[0].Attributes[2].Value.StringValue)
+
+	// Tracing is disabled once stopped.
+	_, span = Start(context.Background(), "test", SpanKindInternal)
+	require.Nil(t, span)
+}
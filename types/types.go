@@ -14,9 +14,11 @@
 package types
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
@@ -297,6 +299,80 @@ type Alert struct {
 	// The authoritative timestamp.
 	UpdatedAt time.Time
 	Timeout   bool
+	// Source is the client which sent the alert last.
+	Source Source
+}
+
+// Source identifies the client which sent an alert.
+type Source struct {
+	// Name is set by the client.
+	Name string `json:"name,omitempty"`
+	// Address is the IP address of the client.
+	Address string `json:"address,omitempty"`
+	// Identity is the authenticated identity of the client, if any.
+	Identity string `json:"identity,omitempty"`
+}
+
+// The pseudo-labels holding the source of an alert, which routes can match.
+const (
+	SourceLabel         = "__source__"
+	SourceAddressLabel  = "__source_address__"
+	SourceIdentityLabel = "__source_identity__"
+)
+
+// RoutingLabels returns the labels of the alert along with the pseudo-labels
+// of its source, which routes match.
+func (a *Alert) RoutingLabels() model.LabelSet {
+	if a.Source == (Source{}) {
+		return a.Labels
+	}
+	lset := make(model.LabelSet, len(a.Labels)+3)
+	for ln, lv := range a.Labels {
+		lset[ln] = lv
+	}
+	for ln, v := range map[model.LabelName]string{
+		SourceLabel:         a.Source.Name,
+		SourceAddressLabel:  a.Source.Address,
+		SourceIdentityLabel: a.Source.Identity,
+	} {
+		if v != "" {
+			lset[ln] = model.LabelValue(v)
+		}
+	}
+	return lset
+}
+
+// ValidateUTF8 validates the alert like Validate, but accepts any non-empty
+// UTF-8 string as the name of a label or an annotation.
+func (a *Alert) ValidateUTF8() error {
+	if a.StartsAt.IsZero() {
+		return fmt.Errorf("start time missing")
+	}
+	if !a.EndsAt.IsZero() && a.EndsAt.Before(a.StartsAt) {
+		return fmt.Errorf("start time must be before end time")
+	}
+	if err := validateUTF8(a.Labels); err != nil {
+		return fmt.Errorf("invalid label set: %s", err)
+	}
+	if len(a.Labels) == 0 {
+		return fmt.Errorf("at least one label pair required")
+	}
+	if err := validateUTF8(a.Annotations); err != nil {
+		return fmt.Errorf("invalid annotations: %s", err)
+	}
+	return nil
+}
+
+func validateUTF8(ls model.LabelSet) error {
+	for ln, lv := range ls {
+		if len(ln) == 0 || !utf8.ValidString(string(ln)) {
+			return fmt.Errorf("invalid name %q", ln)
+		}
+		if !lv.IsValid() {
+			return fmt.Errorf("invalid value %q", lv)
+		}
+	}
+	return nil
 }
 
 // AlertSlice is a sortable slice of Alerts.
@@ -0,0 +1,110 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/route"
+
+	"github.com/prometheus/alertmanager/ack"
+)
+
+const defaultAckDuration = "1h"
+
+var ackTmpl = template.Must(template.New("ack").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Acknowledge alert group</title></head>
+<body>
+<h1>Acknowledge alert group</h1>
+<p><code>{{ .GroupKey }}</code></p>
+{{ with .Ack }}
+<p>Acknowledged by <b>{{ .AckedBy }}</b> at {{ .StartsAt.Format "2006-01-02 15:04:05 MST" }} until {{ .EndsAt.Format "2006-01-02 15:04:05 MST" }}.{{ with .Comment }} Comment: {{ . }}{{ end }}</p>
+{{ end }}
+{{ with .Error }}<p style="color: red">{{ . }}</p>{{ end }}
+<form method="post">
+<input type="hidden" name="groupKey" value="{{ .GroupKey }}">
+<p><label>Name <input type="text" name="ackedBy" required></label></p>
+<p><label>Duration <input type="text" name="duration" value="{{ .Duration }}" required></label></p>
+<p><label>Comment <input type="text" name="comment"></label></p>
+<p><input type="submit" value="Acknowledge"></p>
+</form>
+</body>
+</html>
+`))
+
+type ackPage struct {
+	GroupKey string
+	Duration string
+	Ack      *ack.Ack
+	Error    string
+}
+
+// RegisterAck registers handlers serving a form to acknowledge the alert group
+// passed in the groupKey query parameter. Notification templates link to it.
+func RegisterAck(r *route.Router, acks *ack.Acks, logger log.Logger) {
+	r.Get("/ack", func(w http.ResponseWriter, req *http.Request) {
+		disableCaching(w)
+
+		page := ackPage{
+			GroupKey: req.URL.Query().Get("groupKey"),
+			Duration: defaultAckDuration,
+		}
+		if a, ok := acks.Get(page.GroupKey); ok {
+			page.Ack = a
+		}
+		renderAckPage(w, http.StatusOK, page, logger)
+	})
+
+	r.Post("/ack", func(w http.ResponseWriter, req *http.Request) {
+		disableCaching(w)
+
+		page := ackPage{
+			GroupKey: req.FormValue("groupKey"),
+			Duration: req.FormValue("duration"),
+		}
+		d, err := model.ParseDuration(page.Duration)
+		if err != nil {
+			page.Error = err.Error()
+			renderAckPage(w, http.StatusBadRequest, page, logger)
+			return
+		}
+		a, err := acks.Set(&ack.Ack{
+			GroupKey: page.GroupKey,
+			AckedBy:  req.FormValue("ackedBy"),
+			Comment:  req.FormValue("comment"),
+			EndsAt:   time.Now().Add(time.Duration(d)),
+		})
+		if err != nil {
+			page.Error = err.Error()
+			renderAckPage(w, http.StatusBadRequest, page, logger)
+			return
+		}
+		page.Ack = a
+		renderAckPage(w, http.StatusOK, page, logger)
+	})
+}
+
+func renderAckPage(w http.ResponseWriter, code int, page ackPage, logger log.Logger) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := ackTmpl.Execute(w, page); err != nil {
+		level.Error(logger).Log("msg", "Failed to render acknowledgement page", "err", err)
+	}
+}
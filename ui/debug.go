@@ -0,0 +1,114 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+
+	"github.com/prometheus/common/route"
+	"golang.org/x/net/trace"
+)
+
+func init() {
+	// The access to the traces is restricted by the authorization of the
+	// web server rather than to local clients.
+	trace.AuthRequest = func(*http.Request) (bool, bool) { return true, true }
+}
+
+// RegisterDebug registers the profiling and debugging endpoints under
+// /debug: pprof, the recent requests traced by TraceRequests and a dump of
+// the stacks of all goroutines.
+func RegisterDebug(r *route.Router) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/requests", trace.Traces)
+	mux.HandleFunc("/debug/goroutines", goroutines)
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		req.URL.Path = "/debug" + route.Param(req.Context(), "subpath")
+		mux.ServeHTTP(w, req)
+	}
+	r.Get("/debug/*subpath", handler)
+	r.Post("/debug/*subpath", handler)
+}
+
+// goroutines writes the stacks of all goroutines.
+func goroutines(w http.ResponseWriter, _ *http.Request) {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(buf)
+}
+
+// TraceRequests returns a handler tracing the requests to h, which are then
+// listed at /debug/requests.
+func TraceRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tr := trace.New("http", req.Method+" "+req.URL.Path)
+		defer tr.Finish()
+		tr.LazyPrintf("remote address %s", req.RemoteAddr)
+
+		rec := &statusRecorder{ResponseWriter: w}
+		h.ServeHTTP(rec, req)
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		tr.LazyPrintf("status %d", rec.status)
+		if rec.status >= http.StatusInternalServerError {
+			tr.SetError()
+		}
+	})
+}
+
+// statusRecorder records the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, which streaming responses, such as those of
+// the gRPC API, require.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/prometheus/alertmanager/pkg/srv"
+)
+
+// healthCheckTimeout bounds the duration of all the checks of a probe.
+const healthCheckTimeout = 5 * time.Second
+
+// HealthCheck checks a dependency of the Alertmanager.
+type HealthCheck struct {
+	Name string
+	// Check returns an error if the dependency is unavailable.
+	Check func(ctx context.Context) error
+}
+
+// ClusterCheck fails until the instance synchronized its state with its
+// peers. It always succeeds if ready is nil, i.e. clustering is disabled.
+func ClusterCheck(ready func() bool) HealthCheck {
+	return HealthCheck{
+		Name: "cluster",
+		Check: func(context.Context) error {
+			if ready != nil && !ready() {
+				return errors.New("waiting for the initial state from cluster peers")
+			}
+			return nil
+		},
+	}
+}
+
+// StorageCheck fails if no file can be written to the directory.
+func StorageCheck(dir string) HealthCheck {
+	return HealthCheck{
+		Name: "storage",
+		Check: func(context.Context) error {
+			f, err := ioutil.TempFile(dir, ".health")
+			if err != nil {
+				return err
+			}
+			defer os.Remove(f.Name())
+
+			if _, err := f.WriteString("ok"); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Sync(); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		},
+	}
+}
+
+// SMTPCheck fails if a connection can't be opened to one of the SMTP
+// smarthosts, given as host:port or as the name of an SRV record. A
+// connection to one of the targets of an SRV record is enough.
+func SMTPCheck(smarthosts func() []string) HealthCheck {
+	return HealthCheck{
+		Name: "smtp",
+		Check: func(ctx context.Context) error {
+			var errs []string
+			for _, addr := range smarthosts() {
+				if err := dialAny(ctx, addr); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+			if len(errs) > 0 {
+				return errors.New(strings.Join(errs, "; "))
+			}
+			return nil
+		},
+	}
+}
+
+// dialAny opens a connection to the address, or to the first reachable target
+// of the SRV record it names.
+func dialAny(ctx context.Context, addr string) error {
+	addrs := []string{addr}
+	if srv.IsSRV(addr) {
+		var err error
+		if addrs, err = srv.Lookup(ctx, net.DefaultResolver, addr); err != nil {
+			return err
+		}
+	}
+	var (
+		d   net.Dialer
+		err error
+	)
+	for _, a := range addrs {
+		var conn net.Conn
+		if conn, err = d.DialContext(ctx, "tcp", a); err == nil {
+			conn.Close()
+			return nil
+		}
+	}
+	return err
+}
+
+// healthHandler runs the checks concurrently. The response lists the result
+// of every check if one of them failed or if the verbose query parameter is
+// set, and is "OK" otherwise.
+func healthHandler(failure string, checks []HealthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), healthCheckTimeout)
+		defer cancel()
+
+		var (
+			wg   sync.WaitGroup
+			errs = make([]error, len(checks))
+		)
+		for i, c := range checks {
+			wg.Add(1)
+			go func(i int, c HealthCheck) {
+				defer wg.Done()
+				errs[i] = c.Check(ctx)
+			}(i, c)
+		}
+		wg.Wait()
+
+		var (
+			b      strings.Builder
+			failed bool
+		)
+		for i, c := range checks {
+			if errs[i] != nil {
+				failed = true
+				fmt.Fprintf(&b, "[-]%s failed: %v\n", c.Name, errs[i])
+				continue
+			}
+			fmt.Fprintf(&b, "[+]%s ok\n", c.Name)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if failed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "%s%s", b.String(), failure)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, verbose := req.URL.Query()["verbose"]; verbose {
+			fmt.Fprint(w, b.String())
+		}
+		fmt.Fprintf(w, "OK")
+	}
+}
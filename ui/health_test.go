@@ -0,0 +1,82 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler(t *testing.T) {
+	var synced bool
+	h := healthHandler("Not ready", []HealthCheck{
+		ClusterCheck(func() bool { return synced }),
+		{Name: "smtp", Check: func(context.Context) error { return nil }},
+	})
+
+	serve := func(target string) (int, string) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		return w.Code, w.Body.String()
+	}
+
+	code, body := serve("/-/ready")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "[-]cluster failed: waiting for the initial state from cluster peers\n[+]smtp ok\nNot ready", body)
+
+	synced = true
+	code, body = serve("/-/ready")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "OK", body)
+
+	code, body = serve("/-/ready?verbose")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "[+]cluster ok\n[+]smtp ok\nOK", body)
+
+	// Clustering is disabled.
+	require.NoError(t, ClusterCheck(nil).Check(context.Background()))
+}
+
+func TestStorageCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "health")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, StorageCheck(dir).Check(context.Background()))
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	err = StorageCheck(filepath.Join(dir, "missing")).Check(context.Background())
+	require.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestSMTPCheck(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.Listener.Addr().String()
+
+	c := SMTPCheck(func() []string { return []string{addr} })
+	require.NoError(t, c.Check(context.Background()))
+
+	srv.Close()
+	require.Error(t, c.Check(context.Background()))
+}
@@ -16,7 +16,6 @@ package ui
 import (
 	"fmt"
 	"net/http"
-	_ "net/http/pprof" // Comment this line to disable pprof endpoint.
 	"path"
 
 	"github.com/go-kit/log"
@@ -26,8 +25,9 @@ import (
 	"github.com/prometheus/alertmanager/asset"
 )
 
-// Register registers handlers to serve files for the web interface.
-func Register(r *route.Router, reloadCh chan<- chan error, logger log.Logger) {
+// Register registers handlers to serve files for the web interface. The health
+// and readiness endpoints fail if one of their checks fails.
+func Register(r *route.Router, reloadCh chan<- chan error, healthChecks, readyChecks []HealthCheck, logger log.Logger) {
 	r.Get("/metrics", promhttp.Handler().ServeHTTP)
 
 	r.Get("/", func(w http.ResponseWriter, req *http.Request) {
@@ -72,17 +72,8 @@ func Register(r *route.Router, reloadCh chan<- chan error, logger log.Logger) {
 		}
 	}))
 
-	r.Get("/-/healthy", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "OK")
-	}))
-	r.Get("/-/ready", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "OK")
-	}))
-
-	r.Get("/debug/*subpath", http.DefaultServeMux.ServeHTTP)
-	r.Post("/debug/*subpath", http.DefaultServeMux.ServeHTTP)
+	r.Get("/-/healthy", healthHandler("Not healthy", healthChecks))
+	r.Get("/-/ready", healthHandler("Not ready", readyChecks))
 }
 
 func disableCaching(w http.ResponseWriter) {
@@ -0,0 +1,251 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watchdog implements a dead man's switch: it notifies a receiver if
+// no alert matching the matchers of a watchdog, such as the always-firing
+// Watchdog alert of Prometheus, was received within its interval, which
+// means that the alerts don't reach the Alertmanager anymore.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/provider"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// AlertName is the name of the alerts sent when the alerts of a watchdog are
+// missing.
+const AlertName = "WatchdogMissing"
+
+type metrics struct {
+	notificationsTotal       prometheus.Counter
+	notificationsFailedTotal prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		notificationsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_watchdog_notifications_total",
+			Help: "The total number of notifications sent about missing watchdog alerts.",
+		}),
+		notificationsFailedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "alertmanager_watchdog_notifications_failed_total",
+			Help: "The total number of failed notifications about missing watchdog alerts.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(m.notificationsTotal, m.notificationsFailedTotal)
+	}
+	return m
+}
+
+type watchdog struct {
+	conf *config.Watchdog
+	// lastSeen is when a matching alert was last received, or when the
+	// watchdog was configured if none was.
+	lastSeen time.Time
+	// missingSince is set while the alerts are missing.
+	missingSince time.Time
+}
+
+// Watchdogs periodically checks the alerts received by the watchdogs of the
+// configuration.
+type Watchdogs struct {
+	alerts    provider.Alerts
+	receivers func(name string) ([]notify.Integration, bool)
+	isLeader  func() bool
+	logger    log.Logger
+	metrics   *metrics
+	now       func() time.Time
+
+	mtx       sync.Mutex
+	watchdogs map[string]*watchdog
+}
+
+// New returns a new Watchdogs. The receivers function looks up the
+// integrations of a receiver in the current configuration. If isLeader is not
+// nil, notifications are only sent while it returns true, so that only one
+// member of a cluster sends them.
+func New(
+	alerts provider.Alerts,
+	receivers func(name string) ([]notify.Integration, bool),
+	isLeader func() bool,
+	l log.Logger,
+	r prometheus.Registerer,
+) *Watchdogs {
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	return &Watchdogs{
+		alerts:    alerts,
+		receivers: receivers,
+		isLeader:  isLeader,
+		logger:    l,
+		metrics:   newMetrics(r),
+		now:       time.Now,
+		watchdogs: map[string]*watchdog{},
+	}
+}
+
+// Update applies the watchdogs of the configuration. Watchdogs keep their
+// state across updates as long as their name doesn't change, and new ones
+// wait for their interval before considering the alerts missing.
+func (w *Watchdogs) Update(c *config.Config) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	now := w.now()
+	watchdogs := make(map[string]*watchdog, len(c.Watchdogs))
+	for _, conf := range c.Watchdogs {
+		wd, ok := w.watchdogs[conf.Name]
+		if !ok {
+			wd = &watchdog{lastSeen: now}
+		}
+		wd.conf = conf
+		watchdogs[conf.Name] = wd
+	}
+	w.watchdogs = watchdogs
+}
+
+// Run checks the watchdogs at the given interval until stopc is closed.
+func (w *Watchdogs) Run(interval time.Duration, stopc <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stopc:
+			return
+		case <-t.C:
+			w.Check(context.Background())
+		}
+	}
+}
+
+// Check notifies the receivers of the watchdogs whose alerts went missing,
+// and, once they are received again, of their resolution.
+func (w *Watchdogs) Check(ctx context.Context) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if len(w.watchdogs) == 0 {
+		return
+	}
+
+	it := w.alerts.GetPending()
+	for a := range it.Next() {
+		for _, wd := range w.watchdogs {
+			if a.UpdatedAt.After(wd.lastSeen) && labels.Matchers(wd.conf.Matchers).Matches(a.Labels) {
+				wd.lastSeen = a.UpdatedAt
+			}
+		}
+	}
+	it.Close()
+	if err := it.Err(); err != nil {
+		level.Error(w.logger).Log("msg", "Listing alerts failed", "err", err)
+		return
+	}
+
+	now := w.now()
+	for name, wd := range w.watchdogs {
+		missing := now.Sub(wd.lastSeen) > time.Duration(wd.conf.Interval)
+		if missing == !wd.missingSince.IsZero() {
+			continue
+		}
+
+		missingSince := wd.missingSince
+		if missing {
+			missingSince = wd.lastSeen.Add(time.Duration(wd.conf.Interval))
+		}
+		if w.isLeader == nil || w.isLeader() {
+			// Other members record the state as well, so that they don't
+			// notify again after a leader change.
+			if err := w.notify(ctx, wd, missingSince, missing, now); err != nil {
+				w.metrics.notificationsFailedTotal.Inc()
+				level.Error(w.logger).Log("msg", "Notifying about watchdog failed", "watchdog", name, "receiver", wd.conf.Receiver, "err", err)
+				continue
+			}
+			w.metrics.notificationsTotal.Inc()
+		}
+		if missing {
+			wd.missingSince = missingSince
+		} else {
+			wd.missingSince = time.Time{}
+		}
+	}
+}
+
+func (w *Watchdogs) notify(ctx context.Context, wd *watchdog, missingSince time.Time, missing bool, now time.Time) error {
+	integrations, ok := w.receivers(wd.conf.Receiver)
+	if !ok {
+		return fmt.Errorf("receiver %q not found in current configuration", wd.conf.Receiver)
+	}
+
+	alert := Alert(wd.conf, missingSince, now)
+	if missing {
+		ctx = notify.WithFiringAlerts(ctx, []uint64{uint64(alert.Fingerprint())})
+	} else {
+		alert.EndsAt = now
+		ctx = notify.WithResolvedAlerts(ctx, []uint64{uint64(alert.Fingerprint())})
+	}
+	ctx = notify.WithReceiverName(ctx, wd.conf.Receiver)
+	ctx = notify.WithGroupKey(ctx, "watchdog/"+wd.conf.Name)
+	ctx = notify.WithGroupLabels(ctx, alert.Labels)
+	ctx = notify.WithNow(ctx, now)
+
+	var lastErr error
+	for _, i := range integrations {
+		if !missing && !i.SendResolved() {
+			continue
+		}
+		if _, err := i.Notify(ctx, alert); err != nil {
+			level.Warn(w.logger).Log("msg", "Notify for watchdog failed", "watchdog", wd.conf.Name, "integration", i.String(), "err", err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Alert returns the firing alert describing the missing alerts of a
+// watchdog.
+func Alert(conf *config.Watchdog, missingSince, now time.Time) *types.Alert {
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{
+				model.AlertNameLabel: AlertName,
+				"watchdog":           model.LabelValue(conf.Name),
+			},
+			Annotations: model.LabelSet{
+				"summary": model.LabelValue(fmt.Sprintf(
+					"No alert matching %s received within %s",
+					labels.Matchers(conf.Matchers).String(), conf.Interval,
+				)),
+				"description": "The alerts may not reach the Alertmanager anymore.",
+			},
+			StartsAt: missingSince,
+		},
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/provider/mem"
+	"github.com/prometheus/alertmanager/types"
+)
+
+type sendResolved bool
+
+func (s sendResolved) SendResolved() bool { return bool(s) }
+
+type recordingNotifier struct {
+	alerts    []*types.Alert
+	receivers []string
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	name, _ := notify.ReceiverName(ctx)
+	r.receivers = append(r.receivers, name)
+	r.alerts = append(r.alerts, alerts...)
+	return false, nil
+}
+
+func TestWatchdogsCheck(t *testing.T) {
+	alerts, err := mem.NewAlerts(context.Background(), types.NewMarker(prometheus.NewRegistry()), time.Hour, 0, nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+	defer alerts.Close()
+
+	m, err := labels.NewMatcher(labels.MatchEqual, "alertname", "Watchdog")
+	require.NoError(t, err)
+	conf := &config.Config{
+		Watchdogs: []*config.Watchdog{{
+			Name:     "prometheus",
+			Matchers: config.Matchers{m},
+			Interval: model.Duration(5 * time.Minute),
+			Receiver: "oncall",
+		}},
+	}
+
+	rec := &recordingNotifier{}
+	leader := true
+	w := New(alerts, func(name string) ([]notify.Integration, bool) {
+		if name != "oncall" {
+			return nil, false
+		}
+		return []notify.Integration{notify.NewIntegration(rec, sendResolved(true), "webhook", 0)}, true
+	}, func() bool { return leader }, nil, prometheus.NewRegistry())
+
+	start := time.Now()
+	now := start
+	w.now = func() time.Time { return now }
+	w.Update(conf)
+
+	put := func(name string) {
+		require.NoError(t, alerts.Put(&types.Alert{
+			Alert: model.Alert{
+				Labels:   model.LabelSet{"alertname": model.LabelValue(name)},
+				StartsAt: now,
+				EndsAt:   now.Add(time.Hour),
+			},
+			UpdatedAt: now,
+		}))
+	}
+
+	// Nothing is sent within the interval following the configuration.
+	now = start.Add(4 * time.Minute)
+	w.Check(context.Background())
+	require.Empty(t, rec.alerts)
+
+	// Other alerts don't count.
+	put("HighLatency")
+	now = start.Add(6 * time.Minute)
+	w.Check(context.Background())
+	require.Len(t, rec.alerts, 1)
+	a := rec.alerts[0]
+	require.Equal(t, "oncall", rec.receivers[0])
+	require.Equal(t, model.LabelSet{"alertname": AlertName, "watchdog": "prometheus"}, a.Labels)
+	require.Equal(t, `No alert matching {alertname="Watchdog"} received within 5m`, string(a.Annotations["summary"]))
+	require.Equal(t, start.Add(5*time.Minute), a.StartsAt)
+	require.False(t, a.Resolved())
+
+	// The notification is sent once.
+	now = start.Add(7 * time.Minute)
+	w.Check(context.Background())
+	require.Len(t, rec.alerts, 1)
+
+	// It is resolved once the watchdog alert is received again, and the
+	// configuration can be reloaded without losing the state.
+	put("Watchdog")
+	w.Update(conf)
+	w.Check(context.Background())
+	require.Len(t, rec.alerts, 2)
+	require.True(t, rec.alerts[1].ResolvedAt(now))
+	require.Equal(t, a.Fingerprint(), rec.alerts[1].Fingerprint())
+	require.Equal(t, a.StartsAt, rec.alerts[1].StartsAt)
+
+	// Only the leader notifies, but the others track the state.
+	leader = false
+	now = now.Add(6 * time.Minute)
+	w.Check(context.Background())
+	leader = true
+	w.Check(context.Background())
+	require.Len(t, rec.alerts, 2)
+
+	// Watchdogs without configuration are forgotten.
+	w.Update(&config.Config{})
+	require.Empty(t, w.watchdogs)
+}